@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// DefaultAlertAnomalyWindow is the sliding window over which alert rates are counted
+	DefaultAlertAnomalyWindow = 30 * time.Minute
+	// DefaultAlertAnomalyThreshold is the number of alerts of one type per
+	// window above which the type is considered spiking
+	DefaultAlertAnomalyThreshold = 20
+	// DefaultAlertAnomalyCooldown is how long a spiking type stays in digest mode
+	DefaultAlertAnomalyCooldown = 1 * time.Hour
+)
+
+// AlertAnomalyConfig configures meta-monitoring of the bot's own alert volume
+type AlertAnomalyConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Window    time.Duration `yaml:"window"`    // default 30m
+	Threshold int           `yaml:"threshold"` // alerts per window, default 20
+	Cooldown  time.Duration `yaml:"cooldown"`  // digest mode duration, default 1h
+	// Thresholds overrides the threshold per alert type, e.g. validator_inactivity: 40
+	Thresholds map[string]int `yaml:"thresholds"`
+}
+
+// ValidateAlertAnomaly checks the alert_anomaly settings
+func ValidateAlertAnomaly(cfg AlertAnomalyConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Window < 0 || cfg.Cooldown < 0 {
+		return fmt.Errorf("alert_anomaly window and cooldown cannot be negative")
+	}
+	if cfg.Threshold < 0 {
+		return fmt.Errorf("alert_anomaly.threshold cannot be negative")
+	}
+	for alertType, threshold := range cfg.Thresholds {
+		if alertType == "" {
+			return fmt.Errorf("alert_anomaly.thresholds contains an empty alert type")
+		}
+		if threshold <= 0 {
+			return fmt.Errorf("alert_anomaly.thresholds: threshold for %s must be positive", alertType)
+		}
+	}
+	return nil
+}
+
+// alertRate is the recent alert volume of one alert type
+type alertRate struct {
+	times       []time.Time // alerts within the window, oldest first
+	digestUntil time.Time   // digest mode ends at this time
+	digested    []*Alert    // alerts held back during digest mode, bounded by MaxDigestAlerts
+	dropped     int64       // digested alerts that did not fit the buffer
+}
+
+// AlertAnomalyDetector tracks the alert rate per alert type. When a type
+// spikes, one summary is sent, the type's alerts are batched into a digest
+// for the cooldown period and the episode is recorded in the error journal.
+// It is not safe for concurrent use; TelegramAlert guards it with its lock.
+type AlertAnomalyDetector struct {
+	window     time.Duration
+	threshold  int
+	thresholds map[string]int
+	cooldown   time.Duration
+	journal    *ErrorJournal
+
+	rates    map[string]*alertRate
+	episodes int64
+	digests  int64
+}
+
+// NewAlertAnomalyDetector creates a detector recording episodes in journal;
+// zero config values use the defaults
+func NewAlertAnomalyDetector(cfg AlertAnomalyConfig, journal *ErrorJournal) *AlertAnomalyDetector {
+	d := &AlertAnomalyDetector{
+		window:     cfg.Window,
+		threshold:  cfg.Threshold,
+		thresholds: cfg.Thresholds,
+		cooldown:   cfg.Cooldown,
+		journal:    journal,
+		rates:      make(map[string]*alertRate),
+	}
+	if d.window <= 0 {
+		d.window = DefaultAlertAnomalyWindow
+	}
+	if d.threshold <= 0 {
+		d.threshold = DefaultAlertAnomalyThreshold
+	}
+	if d.cooldown <= 0 {
+		d.cooldown = DefaultAlertAnomalyCooldown
+	}
+	return d
+}
+
+// alertTypeKey names the type of an alert for rate tracking, e.g.
+// "Validator Inactivity" becomes validator_inactivity
+func alertTypeKey(alert *Alert) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(alert.Title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			underscore = false
+			continue
+		}
+		underscore = true
+	}
+	if b.Len() == 0 {
+		return "untitled"
+	}
+	return b.String()
+}
+
+// thresholdFor returns the spike threshold of an alert type
+func (d *AlertAnomalyDetector) thresholdFor(alertType string) int {
+	if threshold, ok := d.thresholds[alertType]; ok {
+		return threshold
+	}
+	return d.threshold
+}
+
+// Observe counts an alert and reports whether it should be held back for the
+// type's digest. spike is the summary to send when this alert starts a spike.
+// Critical alerts are counted but always delivered, as during quiet hours.
+func (d *AlertAnomalyDetector) Observe(alert *Alert, now time.Time) (spike *Alert, digest bool) {
+	alertType := alertTypeKey(alert)
+	rate, ok := d.rates[alertType]
+	if !ok {
+		rate = &alertRate{}
+		d.rates[alertType] = rate
+	}
+
+	cutoff := now.Add(-d.window)
+	kept := rate.times[:0]
+	for _, t := range rate.times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	rate.times = append(kept, now)
+
+	digesting := now.Before(rate.digestUntil)
+	if !digesting && len(rate.times) > d.thresholdFor(alertType) {
+		spike = d.startEpisode(alertType, rate, now)
+		digesting = true
+	}
+
+	if !digesting || alert.Type == AlertTypeCritical {
+		return spike, false
+	}
+
+	if len(rate.digested) >= MaxDigestAlerts {
+		rate.digested = rate.digested[1:]
+		rate.dropped++
+	}
+	rate.digested = append(rate.digested, alert)
+	return spike, true
+}
+
+// startEpisode switches an alert type to digest mode and builds its summary
+func (d *AlertAnomalyDetector) startEpisode(alertType string, rate *alertRate, now time.Time) *Alert {
+	count := len(rate.times)
+	rate.digestUntil = now.Add(d.cooldown)
+	rate.times = rate.times[:0]
+	d.episodes++
+
+	summary := fmt.Sprintf("%s alerts spiked: %d in %s", alertType, count, shortDuration(d.window))
+	if d.journal != nil {
+		err := d.journal.Record(JournalEntry{
+			Timestamp: now,
+			Severity:  AlertTypeWarning.String(),
+			Source:    "alert_anomaly",
+			Summary:   summary,
+			Details: map[string]interface{}{
+				"alert_type":   alertType,
+				"count":        count,
+				"window":       d.window.String(),
+				"digest_until": rate.digestUntil.Format(time.RFC3339),
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to record alert spike in the error journal: %v", err)
+		}
+	}
+
+	return &Alert{
+		ID:        fmt.Sprintf("anomaly-%s-%d", alertType, now.UnixNano()),
+		Type:      AlertTypeWarning,
+		Priority:  AlertPriorityHigh,
+		Title:     "Alert Spike",
+		Message:   fmt.Sprintf("%s\nFurther %s alerts are batched into one digest until %s.", summary, alertType, rate.digestUntil.UTC().Format("15:04 UTC")),
+		Timestamp: now,
+		Metadata: map[string]interface{}{
+			"alert_type": alertType,
+			"count":      count,
+			"threshold":  d.thresholdFor(alertType),
+		},
+	}
+}
+
+// DueDigests returns one digest per alert type whose cooldown ended with
+// alerts held back, sorted by type
+func (d *AlertAnomalyDetector) DueDigests(now time.Time) []*Alert {
+	var alertTypes []string
+	for alertType, rate := range d.rates {
+		if len(rate.digested) > 0 && !now.Before(rate.digestUntil) {
+			alertTypes = append(alertTypes, alertType)
+		}
+	}
+	sort.Strings(alertTypes)
+
+	digests := make([]*Alert, 0, len(alertTypes))
+	for _, alertType := range alertTypes {
+		rate := d.rates[alertType]
+		lines := make([]string, 0, len(rate.digested)+1)
+		for _, held := range rate.digested {
+			lines = append(lines, fmt.Sprintf("%s %s %s", held.Timestamp.Format("15:04"), held.Type.Emoji(), strings.SplitN(held.Message, "\n", 2)[0]))
+		}
+		if rate.dropped > 0 {
+			lines = append(lines, fmt.Sprintf("... and %d older alerts", rate.dropped))
+		}
+
+		digests = append(digests, &Alert{
+			ID:        fmt.Sprintf("anomaly-digest-%s-%d", alertType, now.UnixNano()),
+			Type:      AlertTypeInfo,
+			Priority:  AlertPriorityLow,
+			Title:     fmt.Sprintf("Alert Digest: %s", alertType),
+			Message:   strings.Join(lines, "\n"),
+			Timestamp: now,
+			Metadata: map[string]interface{}{
+				"alert_type": alertType,
+				"alerts":     len(rate.digested),
+				"dropped":    rate.dropped,
+			},
+		})
+		rate.digested = nil
+		rate.dropped = 0
+		d.digests++
+	}
+	return digests
+}
+
+// GetStatus returns the episode counters and the types in digest mode
+func (d *AlertAnomalyDetector) GetStatus(now time.Time) map[string]interface{} {
+	digesting := make(map[string]string)
+	for alertType, rate := range d.rates {
+		if now.Before(rate.digestUntil) {
+			digesting[alertType] = rate.digestUntil.Format(time.RFC3339)
+		}
+	}
+
+	return map[string]interface{}{
+		"window":    d.window.String(),
+		"threshold": d.threshold,
+		"cooldown":  d.cooldown.String(),
+		"episodes":  d.episodes,
+		"digests":   d.digests,
+		"digesting": digesting,
+		"tracked":   len(d.rates),
+	}
+}
+
+// shortDuration formats whole minutes and hours without zero units, e.g. 30m or 1h
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAlertSpikeSendsOneSummaryAndDigests(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		sent = append(sent, msg.Text)
+		mu.Unlock()
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	journal := NewErrorJournal(t.TempDir())
+	ta := &TelegramAlert{
+		config:         &BotConfig{},
+		client:         server.Client(),
+		apiURL:         server.URL,
+		chatID:         "-100100",
+		maxRetries:     1,
+		maxMessageSize: MessageSizeLimit,
+		alertCounts:    make(map[AlertType]int64),
+		running:        true,
+		anomaly:        NewAlertAnomalyDetector(AlertAnomalyConfig{Threshold: 5, Cooldown: time.Hour}, journal),
+	}
+	sentCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(sent)
+	}
+
+	for i := 0; i < 42; i++ {
+		ta.handleAlert(newValidatorAlert("node", "gxrvaloper1node", "validator is inactive", 3, time.Now()))
+	}
+
+	// Five alerts, then the spike summary; the rest is held back
+	if got := sentCount(); got != 6 {
+		t.Fatalf("%d messages sent during the burst, want 6", got)
+	}
+	if summary := sent[5]; !strings.Contains(summary, "validator_inactivity alerts spiked: 6 in 30m") {
+		t.Fatalf("spike summary = %q", summary)
+	}
+
+	// Other types and critical alerts are still delivered
+	ta.handleAlert(newHalvingAlert(2, "cycle started", "", time.Now()))
+	ta.handleAlert(newValidatorAlert("node", "gxrvaloper1node", "jailed", 11, time.Now()))
+	if got := sentCount(); got != 8 {
+		t.Fatalf("%d messages sent, want 8", got)
+	}
+
+	// Nothing is flushed before the cooldown ends
+	ta.flushAnomalyDigests(time.Now())
+	if got := sentCount(); got != 8 {
+		t.Fatalf("digest sent during the cooldown")
+	}
+
+	ta.flushAnomalyDigests(time.Now().Add(2 * time.Hour))
+	ta.flushAnomalyDigests(time.Now().Add(3 * time.Hour))
+	if got := sentCount(); got != 9 {
+		t.Fatalf("%d messages sent, want a single digest", got)
+	}
+	if digest := sent[8]; !strings.Contains(digest, "Alert Digest: validator_inactivity") || !strings.Contains(digest, "• alerts: 37") {
+		t.Fatalf("digest = %q", digest)
+	}
+
+	status := ta.GetStatistics()["alert_anomaly"].(map[string]interface{})
+	if status["episodes"] != int64(1) || status["digests"] != int64(1) {
+		t.Fatalf("anomaly status = %v", status)
+	}
+
+	entries := journal.Entries()
+	if len(entries) != 1 || entries[0].Source != "alert_anomaly" || entries[0].Details["alert_type"] != "validator_inactivity" {
+		t.Fatalf("journal = %+v", entries)
+	}
+}
+
+func TestAlertAnomalySlidingWindowAndThresholds(t *testing.T) {
+	d := NewAlertAnomalyDetector(AlertAnomalyConfig{
+		Window:     10 * time.Minute,
+		Threshold:  3,
+		Cooldown:   30 * time.Minute,
+		Thresholds: map[string]int{"bot_status_rebalancer": 1},
+	}, nil)
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	alert := &Alert{Title: "Validator Inactivity", Type: AlertTypeWarning}
+
+	// Four alerts spread wider than the window never spike
+	for i := 0; i < 8; i++ {
+		if spike, digest := d.Observe(alert, start.Add(time.Duration(i)*4*time.Minute)); spike != nil || digest {
+			t.Fatalf("alert %d treated as a spike", i)
+		}
+	}
+
+	// A per-type threshold applies to its type only
+	bot := &Alert{Title: "Bot Status: rebalancer", Type: AlertTypeError}
+	d.Observe(bot, start)
+	spike, digest := d.Observe(bot, start.Add(time.Second))
+	if spike == nil || !digest || spike.Metadata["alert_type"] != "bot_status_rebalancer" {
+		t.Fatalf("spike = %+v, digest = %v", spike, digest)
+	}
+
+	// The type leaves digest mode after the cooldown and is flushed once
+	if _, digest := d.Observe(bot, start.Add(29*time.Minute)); !digest {
+		t.Fatal("alert delivered during the cooldown")
+	}
+	if digests := d.DueDigests(start.Add(31 * time.Minute)); len(digests) != 1 || digests[0].Metadata["alerts"] != 2 {
+		t.Fatalf("digests = %+v", digests)
+	}
+	if spike, digest := d.Observe(bot, start.Add(40*time.Minute)); spike != nil || digest {
+		t.Fatal("cooldown did not end")
+	}
+
+	// Alerts held back still count, so a spike lasting past the cooldown starts a new episode
+	if spike, _ := d.Observe(bot, start.Add(41*time.Minute)); spike == nil || d.episodes != 2 {
+		t.Fatalf("continued spike not reported, %d episodes", d.episodes)
+	}
+}
+
+func TestErrorJournalSurvivesRestart(t *testing.T) {
+	dataDir := t.TempDir()
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	journal := NewErrorJournal(dataDir)
+	for _, summary := range []string{"first", "second"} {
+		if err := journal.Record(JournalEntry{Timestamp: at, Severity: "WARNING", Source: "test", Summary: summary}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded := NewErrorJournal(dataDir).Entries()
+	if len(reloaded) != 2 || reloaded[1].Summary != "second" || !reloaded[0].Timestamp.Equal(at) {
+		t.Fatalf("reloaded journal = %+v", reloaded)
+	}
+}
+
+func TestAlertTypeKey(t *testing.T) {
+	testCases := map[string]string{
+		"Validator Inactivity":     "validator_inactivity",
+		"Bot Status: rebalancer":   "bot_status_rebalancer",
+		"  Slow Alert Delivery!  ": "slow_alert_delivery",
+		"🚨":                        "untitled",
+	}
+	for title, want := range testCases {
+		if got := alertTypeKey(&Alert{Title: title}); got != want {
+			t.Errorf("alertTypeKey(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxLatencySamples bounds the delivery latency window used for percentiles
+	MaxLatencySamples = 500
+	// DefaultAlertLatencyThreshold is the P95 delivery latency that raises an alert
+	DefaultAlertLatencyThreshold = 5 * time.Minute
+	// MinLatencySamplesForAlert avoids alerting on a handful of slow deliveries
+	MinLatencySamplesForAlert = 20
+	// DeliveryAlertCooldown is the minimum time between two latency alerts
+	DeliveryAlertCooldown = 1 * time.Hour
+)
+
+// TelegramFailure classifies why a Telegram API call failed
+type TelegramFailure string
+
+const (
+	FailureNone        TelegramFailure = ""
+	FailureRateLimited TelegramFailure = "rate_limited" // 429
+	FailureParse       TelegramFailure = "parse_error"  // 400, usually broken Markdown
+	FailureBlocked     TelegramFailure = "blocked"      // 403, bot kicked or blocked
+	FailureTimeout     TelegramFailure = "timeout"      // network timeout
+	FailureNetwork     TelegramFailure = "network"      // other transport errors
+	FailureOther       TelegramFailure = "other"
+)
+
+// Unreachable reports whether the failure means Telegram could not be
+// reached at all, rather than that it refused the message
+func (f TelegramFailure) Unreachable() bool {
+	return f == FailureTimeout || f == FailureNetwork
+}
+
+// classifyTelegramError maps a Telegram API error code to a failure class
+func classifyTelegramError(code int) TelegramFailure {
+	switch code {
+	case http.StatusTooManyRequests:
+		return FailureRateLimited
+	case http.StatusBadRequest:
+		return FailureParse
+	case http.StatusForbidden:
+		return FailureBlocked
+	default:
+		return FailureOther
+	}
+}
+
+// classifyTransportError maps an HTTP client error to a failure class
+func classifyTransportError(err error) TelegramFailure {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return FailureTimeout
+	}
+	return FailureNetwork
+}
+
+// deliveryStats tracks per-alert delivery latency and Telegram failures. The
+// zero value is ready to use.
+type deliveryStats struct {
+	latencies        []time.Duration
+	firstAttemptLag  []time.Duration
+	failures         map[TelegramFailure]int64
+	blockedChats     map[string]bool
+	lastLatencyAlert time.Time
+}
+
+// recordDelivery stores the enqueue-to-delivery and enqueue-to-first-attempt
+// latencies of a delivered alert
+func (d *deliveryStats) recordDelivery(alert *Alert) {
+	if alert.EnqueuedAt.IsZero() || alert.DeliveredAt.IsZero() {
+		return
+	}
+
+	d.latencies = appendBounded(d.latencies, alert.DeliveredAt.Sub(alert.EnqueuedAt))
+	if !alert.FirstAttemptAt.IsZero() {
+		d.firstAttemptLag = appendBounded(d.firstAttemptLag, alert.FirstAttemptAt.Sub(alert.EnqueuedAt))
+	}
+}
+
+// recordFailure counts a failed attempt. It returns true the first time a chat
+// answers 403, so the caller can raise an alert once.
+func (d *deliveryStats) recordFailure(chatID string, failure TelegramFailure) bool {
+	if d.failures == nil {
+		d.failures = make(map[TelegramFailure]int64)
+		d.blockedChats = make(map[string]bool)
+	}
+
+	d.failures[failure]++
+	if failure != FailureBlocked || d.blockedChats[chatID] {
+		return false
+	}
+	d.blockedChats[chatID] = true
+	return true
+}
+
+// latencyPercentile returns the p-th percentile (0-100) of recent delivery latencies
+func (d *deliveryStats) latencyPercentile(p float64) time.Duration {
+	return percentile(d.latencies, p)
+}
+
+// latencyAlertDue reports whether P95 exceeds threshold and the cooldown has passed
+func (d *deliveryStats) latencyAlertDue(threshold time.Duration, now time.Time) bool {
+	if len(d.latencies) < MinLatencySamplesForAlert || d.latencyPercentile(95) <= threshold {
+		return false
+	}
+	if !d.lastLatencyAlert.IsZero() && now.Sub(d.lastLatencyAlert) < DeliveryAlertCooldown {
+		return false
+	}
+	d.lastLatencyAlert = now
+	return true
+}
+
+// failureCounts returns the failure breakdown keyed by class
+func (d *deliveryStats) failureCounts() map[string]int64 {
+	counts := make(map[string]int64, len(d.failures))
+	for failure, count := range d.failures {
+		counts[string(failure)] = count
+	}
+	return counts
+}
+
+// summary renders latency and failures on one line for digests
+func (d *deliveryStats) summary() string {
+	if len(d.latencies) == 0 && len(d.failures) == 0 {
+		return ""
+	}
+
+	line := fmt.Sprintf("Delivery: p50 %s, p95 %s",
+		d.latencyPercentile(50).Round(time.Second), d.latencyPercentile(95).Round(time.Second))
+
+	if len(d.failures) > 0 {
+		classes := make([]string, 0, len(d.failures))
+		for failure, count := range d.failures {
+			classes = append(classes, fmt.Sprintf("%s×%d", failure, count))
+		}
+		sort.Strings(classes)
+		line += ", failures: " + strings.Join(classes, " ")
+	}
+
+	return line
+}
+
+// appendBounded appends v and keeps at most MaxLatencySamples values
+func appendBounded(values []time.Duration, v time.Duration) []time.Duration {
+	values = append(values, v)
+	if len(values) > MaxLatencySamples {
+		values = values[len(values)-MaxLatencySamples:]
+	}
+	return values
+}
+
+// percentile returns the nearest-rank p-th percentile of values
+func percentile(values []time.Duration, p float64) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// AlertFixture is the input of `gxr-bot alerts preview`. Each alert type reads
+// the fields it needs; the rest are ignored.
+type AlertFixture struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// validator_inactivity
+	ValidatorName    string `json:"validator_name"`
+	ValidatorAddress string `json:"validator_address"`
+	InactiveDays     int    `json:"inactive_days"`
+
+	// rebalancer_state
+	State string  `json:"state"`
+	Price float64 `json:"price"`
+
+	// bot_status
+	BotType string `json:"bot_type"`
+	Status  string `json:"status"`
+
+	// halving_event
+	Cycle   uint64 `json:"cycle"`
+	Event   string `json:"event"`
+	Details string `json:"details"`
+
+	// emergency
+	Title    string                 `json:"title"`
+	Metadata map[string]interface{} `json:"metadata"`
+
+	// Alert body for every type
+	Message string `json:"message"`
+}
+
+// alertPreviewBuilders build the alert of each previewable type exactly as
+// the corresponding Send* method does
+var alertPreviewBuilders = map[string]func(f AlertFixture) *Alert{
+	"validator_inactivity": func(f AlertFixture) *Alert {
+		return newValidatorAlert(f.ValidatorName, f.ValidatorAddress, f.Message, f.InactiveDays, f.Timestamp)
+	},
+	"rebalancer_state": func(f AlertFixture) *Alert {
+		return newRebalancerAlert(f.State, f.Message, f.Price, f.Timestamp)
+	},
+	"bot_status": func(f AlertFixture) *Alert {
+		return newBotAlert(f.BotType, f.Status, f.Message, f.Timestamp)
+	},
+	"halving_event": func(f AlertFixture) *Alert {
+		return newHalvingAlert(f.Cycle, f.Event, f.Details, f.Timestamp)
+	},
+	"emergency": func(f AlertFixture) *Alert {
+		return newEmergencyAlert(f.Title, f.Message, f.Metadata, f.Timestamp)
+	},
+}
+
+// alertPreviewTypes returns the previewable alert types in a stable order
+func alertPreviewTypes() []string {
+	types := make([]string, 0, len(alertPreviewBuilders))
+	for alertType := range alertPreviewBuilders {
+		types = append(types, alertType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// RenderAlertPreview renders an alert type from fixture data into the message
+// Telegram would receive, using maxMessageSize as the Telegram size limit
+func RenderAlertPreview(alertType string, fixture []byte, maxMessageSize int) (string, error) {
+	build, ok := alertPreviewBuilders[alertType]
+	if !ok {
+		return "", fmt.Errorf("unknown alert type %q (%s)", alertType, strings.Join(alertPreviewTypes(), "|"))
+	}
+
+	var f AlertFixture
+	if err := json.Unmarshal(fixture, &f); err != nil {
+		return "", fmt.Errorf("failed to parse fixture: %w", err)
+	}
+	if f.Timestamp.IsZero() {
+		f.Timestamp = time.Now()
+	}
+
+	ta := &TelegramAlert{maxMessageSize: maxMessageSize}
+	return ta.formatAlert(build(f)), nil
+}
+
+// createAlertsCmd creates the alerts command
+func createAlertsCmd() *cobra.Command {
+	alertsCmd := &cobra.Command{
+		Use:   "alerts",
+		Short: "Inspect alert formatting",
+	}
+
+	alertsCmd.AddCommand(createAlertsPreviewCmd())
+
+	return alertsCmd
+}
+
+// createAlertsPreviewCmd creates the alerts preview command
+func createAlertsPreviewCmd() *cobra.Command {
+	var (
+		alertType      string
+		fixturePath    string
+		maxMessageSize int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Render an alert type from fixture data to stdout",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var fixture []byte
+			var err error
+			if fixturePath == "" || fixturePath == "-" {
+				fixture, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				fixture, err = os.ReadFile(fixturePath)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read fixture: %w", err)
+			}
+
+			message, err := RenderAlertPreview(alertType, fixture, maxMessageSize)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), message)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&alertType, "type", "", "Alert type ("+strings.Join(alertPreviewTypes(), "|")+")")
+	cmd.Flags().StringVar(&fixturePath, "fixture", "", "JSON fixture with the alert data (default stdin)")
+	cmd.Flags().IntVar(&maxMessageSize, "max-message-size", MessageSizeLimit, "Telegram message size limit to render with")
+	cmd.MarkFlagRequired("type")
+
+	return cmd
+}
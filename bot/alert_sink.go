@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// AlertSinkTelegram sends alerts to Telegram only (default)
+	AlertSinkTelegram = "telegram"
+	// AlertSinkFile appends alerts to a local file instead of sending them
+	AlertSinkFile = "file"
+	// AlertSinkBoth sends alerts to Telegram and appends them to the file
+	AlertSinkBoth = "both"
+	// DefaultAlertFileName is the alert file created in data_dir when alert_file is not set
+	DefaultAlertFileName = "alerts.log"
+)
+
+// ValidateAlertSink checks the alert_sink setting
+func ValidateAlertSink(sink string) error {
+	switch sink {
+	case "", AlertSinkTelegram, AlertSinkFile, AlertSinkBoth:
+		return nil
+	default:
+		return fmt.Errorf("unknown alert_sink %q (telegram|file|both)", sink)
+	}
+}
+
+// usesTelegram reports whether alerts are sent to Telegram
+func (c *BotConfig) usesTelegram() bool {
+	return c.TelegramEnabled && c.AlertSink != AlertSinkFile
+}
+
+// usesAlertFile reports whether alerts are written to the alert file
+func (c *BotConfig) usesAlertFile() bool {
+	return c.AlertSink == AlertSinkFile || c.AlertSink == AlertSinkBoth
+}
+
+// alertFilePath returns the configured alert file, defaulting to data_dir/alerts.log
+func (c *BotConfig) alertFilePath() string {
+	if c.AlertFile != "" {
+		return c.AlertFile
+	}
+	dataDir := c.DataDir
+	if dataDir == "" {
+		dataDir = DefaultDataDir
+	}
+	return filepath.Join(dataDir, DefaultAlertFileName)
+}
+
+// AlertFileRecord is one alert message as written to the alert file
+type AlertFileRecord struct {
+	Timestamp time.Time
+	ChatID    string
+	Message   string
+}
+
+// FileAlertSink appends fully formatted alert messages to a local file, one
+// record per chat the message would have been sent to. Each record is
+//
+//	--- <RFC3339 time> chat=<chat id> bytes=<n> ---
+//	<message, exactly n bytes>
+//
+// followed by a blank line, so messages are stored byte for byte.
+type FileAlertSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileAlertSink creates a sink appending to path, creating its directory
+func NewFileAlertSink(path string) (*FileAlertSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create alert file directory: %w", err)
+	}
+	return &FileAlertSink{path: path}, nil
+}
+
+// Path returns the file alerts are written to
+func (s *FileAlertSink) Path() string {
+	return s.path
+}
+
+// Write appends a message addressed to chatID
+func (s *FileAlertSink) Write(chatID, message string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open alert file: %w", err)
+	}
+	defer f.Close()
+
+	if chatID == "" {
+		chatID = "-"
+	}
+	record := fmt.Sprintf("--- %s chat=%s bytes=%d ---\n%s\n\n", now.UTC().Format(time.RFC3339), chatID, len(message), message)
+	if _, err := f.WriteString(record); err != nil {
+		return fmt.Errorf("failed to write alert file: %w", err)
+	}
+	return nil
+}
+
+// ReadAlertFile parses the records of an alert file
+func ReadAlertFile(path string) ([]AlertFileRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []AlertFileRecord
+	reader := bufio.NewReader(f)
+	for {
+		header, err := reader.ReadString('\n')
+		if err == io.EOF && header == "" {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("truncated alert file header: %w", err)
+		}
+
+		fields := strings.Fields(strings.TrimSuffix(strings.TrimPrefix(header, "--- "), " ---\n"))
+		if len(fields) != 3 || !strings.HasPrefix(fields[1], "chat=") || !strings.HasPrefix(fields[2], "bytes=") {
+			return nil, fmt.Errorf("malformed alert file header %q", header)
+		}
+		timestamp, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("malformed alert timestamp: %w", err)
+		}
+		size, err := strconv.Atoi(strings.TrimPrefix(fields[2], "bytes="))
+		if err != nil {
+			return nil, fmt.Errorf("malformed alert size: %w", err)
+		}
+
+		// The message plus its two trailing newlines
+		body := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return nil, fmt.Errorf("truncated alert message: %w", err)
+		}
+
+		records = append(records, AlertFileRecord{
+			Timestamp: timestamp,
+			ChatID:    strings.TrimPrefix(fields[1], "chat="),
+			Message:   string(body[:size]),
+		})
+	}
+}
+
+// AlertSinkResult is the outcome of sending a test alert to one sink destination
+type AlertSinkResult struct {
+	Sink   string
+	Target string
+	Err    error
+}
+
+// CheckAlertSinks sends a test alert through every configured sink: the alert
+// file, each Telegram chat (the global chat and every validator_chat_map
+// chat) and the Discord webhook. Messages are sent synchronously, bypassing
+// the queue, rate limiting and quiet hours.
+func CheckAlertSinks(config *BotConfig, now time.Time) []AlertSinkResult {
+	return newTelegramAlert(config).checkSinks(now)
+}
+
+func (ta *TelegramAlert) checkSinks(now time.Time) []AlertSinkResult {
+	var results []AlertSinkResult
+	alert := &Alert{
+		Type:      AlertTypeSuccess,
+		Title:     "Test Alert",
+		Message:   "Alert sink test from gxr-bot, this sink is working correctly",
+		Timestamp: now,
+	}
+	message := ta.formatAlert(alert)
+
+	if ta.config.usesAlertFile() {
+		result := AlertSinkResult{Sink: AlertSinkFile, Target: ta.config.alertFilePath()}
+		if result.Err = ta.configureFileSink(); result.Err == nil {
+			result.Err = ta.fileSink.Write(ta.config.TelegramChatID, message, now)
+		}
+		results = append(results, result)
+	}
+
+	if ta.config.usesTelegram() {
+		results = append(results, ta.checkTelegramChats(message)...)
+	}
+
+	if ta.config.DiscordEnabled {
+		// The webhook URL holds its token, only the host is shown
+		result := AlertSinkResult{Sink: NotifierDiscord, Target: endpointKey(ta.config.DiscordWebhookURL)}
+		discord, err := NewDiscordAlert(ta.config)
+		if err == nil {
+			err = discord.SendAlertWithType(alert.Type, alert.Title, alert.Message)
+		}
+		result.Err = err
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// checkTelegramChats sends message to every configured Telegram chat
+func (ta *TelegramAlert) checkTelegramChats(message string) []AlertSinkResult {
+	if err := ta.configureTelegram(); err != nil {
+		return []AlertSinkResult{{Sink: AlertSinkTelegram, Target: ta.config.TelegramChatID, Err: err}}
+	}
+	ta.running = true
+	ta.sendToTelegram = true
+	if err := ta.TestConnection(); err != nil {
+		return []AlertSinkResult{{Sink: AlertSinkTelegram, Target: ta.chatID, Err: err}}
+	}
+
+	var results []AlertSinkResult
+	for _, chatID := range ta.configuredChats() {
+		_, err := ta.postMessage(chatID, message)
+		results = append(results, AlertSinkResult{Sink: AlertSinkTelegram, Target: chatID, Err: err})
+	}
+	return results
+}
+
+// configuredChats returns the global chat followed by the distinct validator chats
+func (ta *TelegramAlert) configuredChats() []string {
+	chats := []string{ta.chatID}
+	seen := map[string]bool{ta.chatID: true}
+
+	var validatorChats []string
+	for _, chatID := range ta.config.ValidatorChatMap {
+		if !seen[chatID] {
+			seen[chatID] = true
+			validatorChats = append(validatorChats, chatID)
+		}
+	}
+	sort.Strings(validatorChats)
+
+	return append(chats, validatorChats...)
+}
+
+// createTestAlertsCmd creates the test alerts command
+func createTestAlertsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "alerts",
+		Short:        "Send a test alert through every configured alert sink",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, _ := cmd.Flags().GetString("config")
+			config, err := LoadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			results := CheckAlertSinks(config, time.Now())
+			if len(results) == 0 {
+				return fmt.Errorf("no alert sinks enabled, set telegram_enabled, discord_enabled or alert_sink")
+			}
+
+			return reportAlertSinkResults(cmd.OutOrStdout(), results)
+		},
+	}
+}
+
+// reportAlertSinkResults prints one line per sink destination and fails if any failed
+func reportAlertSinkResults(out io.Writer, results []AlertSinkResult) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SINK\tTARGET\tRESULT")
+
+	failed := 0
+	for _, result := range results {
+		status := "OK"
+		if result.Err != nil {
+			failed++
+			status = "FAILED: " + result.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.Sink, result.Target, status)
+	}
+	w.Flush()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d alert sinks failed", failed, len(results))
+	}
+	return nil
+}
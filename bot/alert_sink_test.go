@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type sentMessage struct {
+	chatID string
+	text   string
+}
+
+func TestFileSinkMatchesTelegramMessages(t *testing.T) {
+	var mu sync.Mutex
+	var sent []sentMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		sent = append(sent, sentMessage{msg.ChatID, msg.Text})
+		mu.Unlock()
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "alerts", "alerts.log")
+	sink, err := NewFileAlertSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ta := &TelegramAlert{
+		config:         &BotConfig{ValidatorChatMap: map[string]string{"gxrvaloper1customer": "-100200"}},
+		client:         server.Client(),
+		apiURL:         server.URL,
+		chatID:         "-100100",
+		maxRetries:     1,
+		maxMessageSize: 1024,
+		alertCounts:    make(map[AlertType]int64),
+		running:        true,
+		sendToTelegram: true,
+		fileSink:       sink,
+	}
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	metadata := map[string]interface{}{"height": 123456}
+	for i := 0; i < 20; i++ {
+		metadata["field_"+strings.Repeat("x", i)] = strings.Repeat("*value* ", 20)
+	}
+	ta.handleAlert(newValidatorAlert("gxr-node-1", "gxrvaloper1customer", "Missed uptime check", 8, now))
+	ta.handleAlert(newEmergencyAlert("Chain Halted", strings.Repeat("No new blocks _since_ 10m ✅\n", 100), metadata, now))
+
+	records, err := ReadAlertFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Routed and truncated messages are written exactly as sent
+	if len(sent) != 3 || len(records) != len(sent) {
+		t.Fatalf("sent %d messages, wrote %d records", len(sent), len(records))
+	}
+	for i, record := range records {
+		if record.ChatID != sent[i].chatID {
+			t.Errorf("record %d chat = %s, want %s", i, record.ChatID, sent[i].chatID)
+		}
+		if record.Message != sent[i].text {
+			t.Errorf("record %d differs from the Telegram message:\n--- file ---\n%q\n--- telegram ---\n%q", i, record.Message, sent[i].text)
+		}
+	}
+	if !strings.Contains(records[2].Message, strings.TrimSpace(TruncationMarker)) {
+		t.Error("oversized alert was not truncated")
+	}
+}
+
+func TestFileOnlySinkSkipsTelegram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.log")
+	ta := NewTelegramAlert(&BotConfig{AlertSink: AlertSinkFile, AlertFile: path, TelegramEnabled: true})
+	defer ta.Stop()
+
+	if !ta.IsRunning() || ta.sendToTelegram {
+		t.Fatalf("running = %v, telegram = %v, want a running file-only sink", ta.IsRunning(), ta.sendToTelegram)
+	}
+	if err := ta.TestConnection(); err != nil {
+		t.Fatalf("connection test should not contact Telegram: %v", err)
+	}
+
+	ta.handleAlert(newBotAlert("rebalancer", "error", "price feed unavailable", time.Now()))
+
+	records, err := ReadAlertFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].ChatID != "-" || !strings.Contains(records[0].Message, "*Bot Status: rebalancer*") {
+		t.Fatalf("records = %+v", records)
+	}
+	if stats := ta.GetStatistics(); stats["successful_alerts"] != int64(1) || stats["alert_file"] != path {
+		t.Fatalf("file sink delivery not counted: %v", stats)
+	}
+}
+
+func TestAlertsPreviewCommand(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "alert_validator_inactivity.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := createAlertsCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"preview", "--type", "validator_inactivity", "--fixture", filepath.Join("testdata", "alert_validator_inactivity.json")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The preview is the message the alert pipeline would deliver
+	var f AlertFixture
+	if err := json.Unmarshal(fixture, &f); err != nil {
+		t.Fatal(err)
+	}
+	ta := &TelegramAlert{maxMessageSize: MessageSizeLimit}
+	want := ta.formatAlert(newValidatorAlert(f.ValidatorName, f.ValidatorAddress, f.Message, f.InactiveDays, f.Timestamp))
+	if out.String() != want+"\n" {
+		t.Fatalf("preview:\n%s\nwant:\n%s", out.String(), want)
+	}
+	for _, line := range []string{"⚠️ *WARNING*", "*Validator Inactivity*", "📅 2025-06-01 12:00:00", "• inactive_days: 8"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("preview missing %q", line)
+		}
+	}
+
+	if _, err := RenderAlertPreview("price_spike", fixture, MessageSizeLimit); err == nil {
+		t.Fatal("unknown alert type accepted")
+	}
+}
+
+func TestCheckAlertSinksReportsEachDestination(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/getMe") {
+			w.Write([]byte(`{"ok":true}`))
+			return
+		}
+		var msg TelegramMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		sent = append(sent, msg.ChatID)
+		mu.Unlock()
+		if msg.ChatID == "-100300" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was kicked"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "alerts.log")
+	config := &BotConfig{
+		TelegramEnabled: true,
+		TelegramToken:   "123:abc",
+		TelegramChatID:  "-100100",
+		ValidatorChatMap: map[string]string{
+			"gxrvaloper1a": "-100300",
+			"gxrvaloper1b": "-100200",
+			"gxrvaloper1c": "-100100",
+		},
+		AlertSink: AlertSinkBoth,
+		AlertFile: path,
+	}
+
+	ta := newTelegramAlert(config)
+	ta.client = server.Client()
+	ta.apiURL = server.URL
+	results := ta.checkSinks(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	var got []string
+	for _, result := range results {
+		got = append(got, result.Sink+" "+result.Target)
+	}
+	want := []string{"file " + path, "telegram -100100", "telegram -100200", "telegram -100300"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("checked %v, want %v", got, want)
+	}
+	if results[0].Err != nil || results[1].Err != nil || results[2].Err != nil {
+		t.Fatalf("healthy sinks failed: %+v", results)
+	}
+	if results[3].Err == nil || !strings.Contains(results[3].Err.Error(), "bot was kicked") {
+		t.Fatalf("blocked chat error = %v", results[3].Err)
+	}
+	if records, err := ReadAlertFile(path); err != nil || len(records) != 1 {
+		t.Fatalf("file sink records = %v, %v", records, err)
+	}
+
+	var out bytes.Buffer
+	err := reportAlertSinkResults(&out, results)
+	if err == nil || err.Error() != "1 of 4 alert sinks failed" {
+		t.Fatalf("err = %v", err)
+	}
+	if !strings.Contains(out.String(), "FAILED: telegram API error: 403") || strings.Count(out.String(), "OK") != 3 {
+		t.Fatalf("report:\n%s", out.String())
+	}
+}
+
+func TestCheckAlertSinksReportsConfigurationErrors(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "not-a-dir")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	results := CheckAlertSinks(&BotConfig{
+		TelegramEnabled: true,
+		TelegramToken:   "no-colon",
+		TelegramChatID:  "-100100",
+		AlertSink:       AlertSinkBoth,
+		AlertFile:       filepath.Join(blocker, "alerts.log"),
+	}, time.Now())
+
+	if len(results) != 2 || results[0].Err == nil || results[1].Err == nil {
+		t.Fatalf("results = %+v, want both sinks failing", results)
+	}
+	if !strings.Contains(results[1].Err.Error(), "invalid bot token format") {
+		t.Fatalf("telegram error = %v", results[1].Err)
+	}
+
+	if results := CheckAlertSinks(&BotConfig{}, time.Now()); len(results) != 0 {
+		t.Fatalf("no sinks configured, got %+v", results)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// AlertSpillFile holds the alerts waiting for Telegram to be reachable again
+	AlertSpillFile = "alert_spill.json"
+	// DefaultAlertSpillMaxAge is how long a spilled alert is kept before it is dropped
+	DefaultAlertSpillMaxAge = 6 * time.Hour
+	// MaxSpilledAlerts bounds the spill buffer; the oldest alerts are dropped first
+	MaxSpilledAlerts = 2000
+	// AlertSpillCheckInterval is how often an idle alert processor looks at the spill buffer
+	AlertSpillCheckInterval = 5 * time.Second
+	// AlertOfflineProbeInterval is how often the oldest spilled alert is retried
+	// while Telegram is unreachable
+	AlertOfflineProbeInterval = 30 * time.Second
+)
+
+// AlertSpill buffers the alerts the queue cannot hold while Telegram is
+// unreachable, oldest first, and keeps them on disk, so they are delivered
+// once it answers again, also after a restart. Alerts older than the max age
+// are dropped. An empty dataDir keeps the buffer in memory.
+type AlertSpill struct {
+	path   string
+	maxAge time.Duration
+
+	// saveMu serializes writes of the file, mu guards the buffer
+	saveMu sync.Mutex
+
+	mu      sync.Mutex
+	alerts  []*Alert
+	changed bool
+
+	// Alerts moved in from a queue, alerts held after Telegram could not be
+	// reached, and alerts dropped past the max age or over the size limit
+	spilled int64
+	held    int64
+	expired int64
+	dropped int64
+}
+
+// NewAlertSpill creates a spill buffer persisted in dataDir and loads the
+// alerts left by a previous run
+func NewAlertSpill(dataDir string, maxAge time.Duration) *AlertSpill {
+	if maxAge <= 0 {
+		maxAge = DefaultAlertSpillMaxAge
+	}
+
+	s := &AlertSpill{maxAge: maxAge}
+	if dataDir == "" {
+		return s
+	}
+
+	s.path = filepath.Join(dataDir, AlertSpillFile)
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s.alerts); err != nil {
+		log.Printf("Ignoring unreadable alert spill %s: %v", s.path, err)
+		s.alerts = nil
+	}
+	if len(s.alerts) > 0 {
+		log.Printf("Loaded %d undelivered alerts from %s", len(s.alerts), s.path)
+	}
+	return s
+}
+
+// Push adds an alert moved out of a queue. It only touches memory, so
+// callers queueing alerts never wait for the disk.
+func (s *AlertSpill) Push(alert *Alert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.spilled++
+	s.insertLocked(alert)
+}
+
+// Hold adds an alert Telegram could not be reached for. It is marked held,
+// so it is not screened again when it is sent.
+func (s *AlertSpill) Hold(alert *Alert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !alert.Held {
+		alert.Held = true
+		s.held++
+	}
+	s.insertLocked(alert)
+}
+
+// insertLocked keeps the alerts ordered by when they were queued, whichever
+// dispatcher or caller adds them first. New alerts are usually the newest,
+// so the search starts at the end. Over the size limit the oldest alert is
+// dropped.
+func (s *AlertSpill) insertLocked(alert *Alert) {
+	i := len(s.alerts)
+	for i > 0 && s.alerts[i-1].EnqueuedAt.After(alert.EnqueuedAt) {
+		i--
+	}
+	s.alerts = append(s.alerts, nil)
+	copy(s.alerts[i+1:], s.alerts[i:])
+	s.alerts[i] = alert
+	s.changed = true
+
+	if len(s.alerts) > MaxSpilledAlerts {
+		s.alerts[0] = nil
+		s.alerts = s.alerts[1:]
+		s.dropped++
+	}
+}
+
+// Pop takes the oldest alert that is not past the max age at now, dropping
+// the expired ones before it. It returns nil when none is left.
+func (s *AlertSpill) Pop(now time.Time) *Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.alerts) > 0 {
+		alert := s.alerts[0]
+		s.alerts[0] = nil
+		s.alerts = s.alerts[1:]
+		s.changed = true
+		if now.Sub(alert.EnqueuedAt) <= s.maxAge {
+			return alert
+		}
+		s.expired++
+		log.Printf("Dropping alert spilled more than %s ago: %s", s.maxAge, alert.Title)
+	}
+	return nil
+}
+
+// Len returns the number of spilled alerts
+func (s *AlertSpill) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.alerts)
+}
+
+// Oldest returns when the oldest spilled alert was queued, zero if the
+// buffer is empty
+func (s *AlertSpill) Oldest() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.alerts) == 0 {
+		return time.Time{}
+	}
+	return s.alerts[0].EnqueuedAt
+}
+
+// Save writes the buffer to disk if it changed since the last save. Only
+// encoding holds the buffer lock, so Push does not wait for the disk.
+func (s *AlertSpill) Save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	if !s.changed {
+		s.mu.Unlock()
+		return nil
+	}
+	data, err := json.MarshalIndent(s.alerts, "", "  ")
+	s.changed = err != nil
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return s.retrySave(err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return s.retrySave(err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return s.retrySave(err)
+	}
+	return nil
+}
+
+// retrySave marks the buffer for the next Save after a failed write
+func (s *AlertSpill) retrySave(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changed = true
+	return err
+}
+
+// Status returns the spill buffer status for monitoring
+func (s *AlertSpill) Status() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := map[string]interface{}{
+		"pending": len(s.alerts),
+		"spilled": s.spilled,
+		"held":    s.held,
+		"expired": s.expired,
+		"dropped": s.dropped,
+		"max_age": s.maxAge.String(),
+	}
+	if len(s.alerts) > 0 {
+		status["oldest"] = s.alerts[0].EnqueuedAt.Format(time.RFC3339)
+	}
+	if s.path != "" {
+		status["file"] = s.path
+	}
+	return status
+}
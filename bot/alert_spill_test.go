@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueueAlertDropsOldestWhenFull(t *testing.T) {
+	ta := &TelegramAlert{alertQueue: make(chan *Alert, 3), running: true}
+
+	for i := 0; i < 5; i++ {
+		if err := ta.QueueAlert(&Alert{Title: fmt.Sprintf("Alert %d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var queued []string
+	for len(ta.alertQueue) > 0 {
+		queued = append(queued, (<-ta.alertQueue).Title)
+	}
+	if strings.Join(queued, ",") != "Alert 2,Alert 3,Alert 4" {
+		t.Fatalf("queued %v, want the 3 newest", queued)
+	}
+	if ta.evictedAlerts.Load() != 2 || ta.droppedAlerts.Load() != 2 {
+		t.Fatalf("evicted %d, dropped %d", ta.evictedAlerts.Load(), ta.droppedAlerts.Load())
+	}
+}
+
+func TestAlertSpillKeepsQueueOrder(t *testing.T) {
+	dataDir := t.TempDir()
+	spill := NewAlertSpill(dataDir, time.Hour)
+	now := time.Now()
+
+	spill.Push(&Alert{Title: "second", EnqueuedAt: now.Add(-20 * time.Minute)})
+	spill.Push(&Alert{Title: "third", EnqueuedAt: now.Add(-10 * time.Minute)})
+	spill.Push(&Alert{Title: "expired", EnqueuedAt: now.Add(-2 * time.Hour)})
+	spill.Hold(&Alert{Title: "first", EnqueuedAt: now.Add(-30 * time.Minute)})
+	if err := spill.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The buffer survives a restart
+	spill = NewAlertSpill(dataDir, time.Hour)
+	var popped []string
+	for alert := spill.Pop(now); alert != nil; alert = spill.Pop(now) {
+		popped = append(popped, alert.Title)
+		if alert.Held != (alert.Title == "first") {
+			t.Fatalf("%s held = %v", alert.Title, alert.Held)
+		}
+	}
+	if strings.Join(popped, ",") != "first,second,third" {
+		t.Fatalf("popped %v", popped)
+	}
+	if status := spill.Status(); status["expired"] != int64(1) || status["pending"] != 0 {
+		t.Fatalf("status %v", status)
+	}
+}
+
+func TestAlertQueueSurvivesTelegramOutage(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+
+		// Unreachable Telegram: requests hang until the client gives up
+		if down.Load() {
+			<-r.Context().Done()
+			return
+		}
+		mu.Lock()
+		received = append(received, msg.Text)
+		mu.Unlock()
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.Timeout = 100 * time.Millisecond
+
+	dataDir := t.TempDir()
+	ta := &TelegramAlert{
+		config:               &BotConfig{AlertLatencyThreshold: 3 * time.Hour},
+		client:               client,
+		apiURL:               server.URL,
+		chatID:               "-100100",
+		maxRetries:           1,
+		maxMessageSize:       MessageSizeLimit,
+		alertCounts:          make(map[AlertType]int64),
+		alertQueue:           make(chan *Alert, 10),
+		stopChan:             make(chan struct{}),
+		running:              true,
+		sendToTelegram:       true,
+		spillCheckInterval:   10 * time.Millisecond,
+		offlineProbeInterval: time.Second,
+	}
+	ta.SetAlertSpill(NewAlertSpill(dataDir, 3*time.Hour))
+	go ta.processAlerts()
+	defer ta.Stop()
+
+	// Two hours of alerts, one every 30 seconds
+	now := time.Now()
+	var alerts []*Alert
+	for i := 0; i < 240; i++ {
+		alerts = append(alerts, &Alert{
+			Type:       AlertTypeWarning,
+			Title:      fmt.Sprintf("Alert %03d", i),
+			Message:    "validator missed blocks",
+			Timestamp:  now,
+			EnqueuedAt: now.Add(-2*time.Hour + time.Duration(i)*30*time.Second),
+		})
+	}
+
+	latencies := make([]time.Duration, 0, len(alerts))
+	for _, alert := range alerts {
+		start := time.Now()
+		if err := ta.QueueAlert(alert); err != nil {
+			t.Fatal(err)
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	if p99 := latencies[len(latencies)*99/100]; p99 >= time.Millisecond {
+		t.Fatalf("QueueAlert p99 latency %s during the outage, max %s", p99, latencies[len(latencies)-1])
+	}
+
+	// Everything waits in the spill buffer, also on disk
+	waitFor(t, func() bool {
+		return NewAlertSpill(dataDir, 3*time.Hour).Len() == len(alerts)
+	})
+	stats := ta.GetStatistics()
+	if stats["queue_size"] != 0 || stats["spilled_alerts"] != len(alerts) || stats["telegram_reachable"] != false {
+		t.Fatalf("outage status %v", stats)
+	}
+	if age := stats["backlog_age_seconds"].(int64); age < int64((2 * time.Hour).Seconds()) {
+		t.Fatalf("backlog age %ds, want at least 2h", age)
+	}
+
+	// Once Telegram answers, the backlog goes out oldest first
+	down.Store(false)
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == len(alerts)
+	})
+	mu.Lock()
+	for i, text := range received {
+		if want := fmt.Sprintf("Alert %03d", i); !strings.Contains(text, want) {
+			t.Fatalf("message %d is not %s:\n%s", i, want, text)
+		}
+	}
+	mu.Unlock()
+
+	stats = ta.GetStatistics()
+	if stats["successful_alerts"] != int64(len(alerts)) || stats["failed_alerts"] != int64(0) ||
+		stats["spilled_alerts"] != 0 || stats["telegram_reachable"] != true {
+		t.Fatalf("recovered status %v", stats)
+	}
+}
+
+// waitFor polls cond until it holds or a few seconds passed
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits of the chain's bot directory entries
+const (
+	MaxBotContactLength     = 128
+	MaxBotEndpointURLLength = 256
+)
+
+// BotMetadataConfig is the entry the bot registers for its validator in the
+// chain's bot directory, next to the bot's version
+type BotMetadataConfig struct {
+	// Submit the entry on startup whenever it differs from the chain's
+	Enabled bool `yaml:"enabled"`
+	// How to reach the operator, e.g. an email address or Telegram handle
+	Contact string `yaml:"contact"`
+	// Public URL of the bot, e.g. its status server (http or https)
+	EndpointURL string `yaml:"endpoint_url"`
+}
+
+// BotMetadata is a validator's entry in the chain's bot directory
+type BotMetadata struct {
+	Version       string
+	Contact       string
+	EndpointURL   string
+	UpdatedHeight uint64
+}
+
+// ValidateBotMetadata checks the bot_metadata settings against the limits
+// the chain enforces, so a registration is not rejected after startup, and
+// that validator_mnemonic is set to sign it
+func ValidateBotMetadata(c BotMetadataConfig, validatorMnemonic string) error {
+	if c.Enabled && validatorMnemonic == "" {
+		return fmt.Errorf("bot_metadata.enabled needs validator_mnemonic to sign the registration")
+	}
+	if len(c.Contact) > MaxBotContactLength || !utf8.ValidString(c.Contact) {
+		return fmt.Errorf("bot_metadata.contact must be valid UTF-8 of at most %d bytes", MaxBotContactLength)
+	}
+	if c.EndpointURL == "" {
+		return nil
+	}
+	if len(c.EndpointURL) > MaxBotEndpointURLLength {
+		return fmt.Errorf("bot_metadata.endpoint_url must be at most %d bytes", MaxBotEndpointURLLength)
+	}
+	u, err := url.Parse(c.EndpointURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid bot_metadata.endpoint_url %q, expected an http or https URL", c.EndpointURL)
+	}
+	return nil
+}
+
+// registerBotMetadata submits the validator's bot directory entry when it is
+// missing or differs from the configured one. The chain rate-limits updates,
+// so an unchanged entry is never resubmitted; when the query fails the entry
+// is submitted anyway and a rejection only costs the transaction.
+func (bs *BotService) registerBotMetadata(ctx context.Context, now time.Time) bool {
+	c := bs.config.BotMetadata
+	if !c.Enabled || bs.config.ValidatorAddress == "" {
+		return false
+	}
+	if bs.broadcastQueue == nil {
+		err := fmt.Errorf("bot_metadata is enabled but no broadcaster signs transactions, check validator_mnemonic")
+		log.Printf("Skipping bot metadata registration: %v", err)
+		bs.recordError("bot_metadata", err.Error())
+		if bs.telegramAlert != nil {
+			bs.telegramAlert.SendBotAlert("Bot Metadata", "warning", err.Error())
+		}
+		return false
+	}
+
+	if !bs.isProtocolCompatible() {
+		log.Printf("Skipping bot metadata registration: bot protocol v%d not accepted by chain", BotProtocolVersion)
+		return false
+	}
+	if bs.isReadOnly() {
+		log.Printf("Skipping bot metadata registration: running read-only")
+		return false
+	}
+
+	if bs.chainQuerier != nil {
+		current, found, err := bs.chainQuerier.QueryBotMetadata(ctx, bs.config.ValidatorAddress)
+		if err != nil {
+			log.Printf("Bot metadata query failed, registering anyway: %v", err)
+		} else if found && current.Version == Version && current.Contact == c.Contact && current.EndpointURL == c.EndpointURL {
+			return false
+		}
+	}
+
+	tx := BotTx{Kind: TxKindBotMetadata, Validator: bs.config.ValidatorAddress, Version: Version, Contact: c.Contact, EndpointURL: c.EndpointURL}
+	if err := bs.broadcastQueue.Submit(ctx, tx, now); err != nil {
+		bs.recordError("bot_metadata", err.Error())
+		return false
+	}
+	log.Printf("Registered bot v%s in the chain's bot directory", Version)
+	return true
+}
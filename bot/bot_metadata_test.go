@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegisterBotMetadataOnStartup(t *testing.T) {
+	var mu sync.Mutex
+	registered := "null"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gxr/halving/v1beta1/heartbeat_compliance/gxrvaloper1ours" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, `{"validator_address":"gxrvaloper1ours","bot_metadata":%s}`, registered)
+	}))
+	defer server.Close()
+
+	var sent []BotTx
+	bs := &BotService{
+		config: &BotConfig{
+			ValidatorAddress: "gxrvaloper1ours",
+			BotMetadata:      BotMetadataConfig{Enabled: true, Contact: "ops@example.com", EndpointURL: "https://bot.example.com"},
+		},
+		protocolCompatible: true,
+		chainQuerier:       NewChainQuerierForAPI(server.URL),
+	}
+	ctx, now := context.Background(), time.Now()
+
+	// Without a broadcaster the entry cannot be registered, which is reported
+	if bs.registerBotMetadata(ctx, now) || len(bs.lastErrors) != 1 || bs.lastErrors[0].Component != "bot_metadata" {
+		t.Fatalf("registered without a broadcaster, errors %+v", bs.lastErrors)
+	}
+
+	bs.SetBroadcaster(broadcasterFunc(func(ctx context.Context, tx BotTx) error {
+		sent = append(sent, tx)
+		return nil
+	}))
+
+	// Not registered yet
+	if !bs.registerBotMetadata(ctx, now) || len(sent) != 1 {
+		t.Fatalf("missing entry not registered, sent %+v", sent)
+	}
+	tx := sent[0]
+	if tx.Kind != TxKindBotMetadata || tx.Validator != "gxrvaloper1ours" || tx.Version != Version ||
+		tx.Contact != "ops@example.com" || tx.EndpointURL != "https://bot.example.com" {
+		t.Fatalf("registered %+v", tx)
+	}
+
+	// An unchanged entry is not resubmitted into the chain's rate limit
+	mu.Lock()
+	registered = fmt.Sprintf(`{"version":%q,"contact":"ops@example.com","endpoint_url":"https://bot.example.com","updated_height":"120"}`, Version)
+	mu.Unlock()
+	if bs.registerBotMetadata(ctx, now) || len(sent) != 1 {
+		t.Fatalf("unchanged entry resubmitted, sent %+v", sent)
+	}
+	current, found, err := bs.chainQuerier.QueryBotMetadata(ctx, "gxrvaloper1ours")
+	if err != nil || !found || current.UpdatedHeight != 120 {
+		t.Fatalf("queried %+v, found %v: %v", current, found, err)
+	}
+
+	// A changed contact is
+	bs.config.BotMetadata.Contact = "@gxr_ops"
+	if !bs.registerBotMetadata(ctx, now) || len(sent) != 2 || sent[1].Contact != "@gxr_ops" {
+		t.Fatalf("changed entry not registered, sent %+v", sent)
+	}
+
+	// Nothing is sent read-only or when disabled
+	bs.readOnly = true
+	bs.config.BotMetadata.Contact = "ops@example.com"
+	bs.config.BotMetadata.EndpointURL = ""
+	if bs.registerBotMetadata(ctx, now) {
+		t.Fatal("registered while read-only")
+	}
+	bs.readOnly = false
+	bs.config.BotMetadata.Enabled = false
+	if bs.registerBotMetadata(ctx, now) || len(sent) != 2 {
+		t.Fatalf("registered while disabled, sent %+v", sent)
+	}
+}
+
+func TestValidateBotMetadata(t *testing.T) {
+	valid := BotMetadataConfig{Enabled: true, Contact: "ops@example.com", EndpointURL: "http://10.0.0.5:8080/status"}
+	if err := ValidateBotMetadata(valid, signingMnemonic); err != nil {
+		t.Fatal(err)
+	}
+	// Nothing could sign the registration
+	if err := ValidateBotMetadata(valid, ""); err == nil {
+		t.Fatal("accepted bot_metadata without validator_mnemonic")
+	}
+
+	for _, c := range []BotMetadataConfig{
+		{EndpointURL: "bot.example.com"},
+		{EndpointURL: "ftp://bot.example.com"},
+		{Contact: string(make([]byte, MaxBotContactLength+1))},
+		{Contact: "\xff"},
+	} {
+		if err := ValidateBotMetadata(c, signingMnemonic); err == nil {
+			t.Fatalf("accepted %+v", c)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// BroadcastQueueFile is the persisted retry queue in the data directory
+	BroadcastQueueFile = "broadcast_queue.json"
+	// BroadcastRetryInterval is how often the retry queue is processed
+	BroadcastRetryInterval = 15 * time.Second
+	// BroadcastRetryBaseDelay is the delay before the first retry, doubled on every failure
+	BroadcastRetryBaseDelay = 15 * time.Second
+	// BroadcastRetryMaxDelay caps the retry backoff
+	BroadcastRetryMaxDelay = 5 * time.Minute
+	// DefaultBroadcastMaxAge is how long a failed transaction is retried before it is dropped
+	DefaultBroadcastMaxAge = 1 * time.Hour
+	// MaxBroadcastRetriesPerTick rate-limits retries, so a recovering node is not flooded
+	MaxBroadcastRetriesPerTick = 5
+)
+
+// Kinds of bot transactions
+const (
+	TxKindHeartbeat      = "heartbeat"
+	TxKindSlashingReport = "slashing_report"
+	TxKindBotMetadata    = "bot_metadata"
+	TxKindIBCTimeout     = "ibc_timeout"
+)
+
+// BotTx is a bot transaction waiting to be broadcast
+type BotTx struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Validator string    `json:"validator"`
+	Memo      string    `json:"memo,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Bot directory entry of a bot_metadata transaction
+	Version     string `json:"version,omitempty"`
+	Contact     string `json:"contact,omitempty"`
+	EndpointURL string `json:"endpoint_url,omitempty"`
+
+	// Packet of an ibc_timeout transaction, as sent on this chain, with the
+	// relayer's proof that the counterparty end never received it at
+	// ProofHeight of the channel's client
+	SourcePort          string    `json:"source_port,omitempty"`
+	ChannelID           string    `json:"channel_id,omitempty"`
+	Sequence            uint64    `json:"sequence,omitempty"`
+	PacketData          []byte    `json:"packet_data,omitempty"`
+	TimeoutHeight       IBCHeight `json:"timeout_height,omitempty"`
+	TimeoutTimestamp    time.Time `json:"timeout_timestamp,omitempty"`
+	CounterpartyPort    string    `json:"counterparty_port,omitempty"`
+	CounterpartyChannel string    `json:"counterparty_channel,omitempty"`
+	ProofUnreceived     []byte    `json:"proof_unreceived,omitempty"`
+	ProofHeight         IBCHeight `json:"proof_height,omitempty"`
+
+	// Set with tx_authz: the broadcaster signs with the Grantee key, wraps the
+	// messages in MsgExec (execMessage) and sets FeeGranter on the fee
+	Granter    string `json:"granter,omitempty"`
+	Grantee    string `json:"grantee,omitempty"`
+	FeeGranter string `json:"fee_granter,omitempty"`
+
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// TxBroadcaster signs and broadcasts bot transactions
+type TxBroadcaster interface {
+	Broadcast(ctx context.Context, tx BotTx) error
+}
+
+// BroadcastQueue broadcasts bot transactions and retries failed ones with
+// backoff until they succeed or exceed the max age. The queue is kept on disk,
+// so a restart during a node outage does not lose a heartbeat.
+type BroadcastQueue struct {
+	broadcaster TxBroadcaster
+	alert       *TelegramAlert
+	authz       TxAuthzConfig
+	maxAge      time.Duration
+	path        string
+
+	// gate, when set, must pass for anything to be broadcast, so an instance
+	// that is not the active one never signs
+	gate func() error
+
+	// drain tracks the broadcasts in flight, so shutdown waits for them
+	drain *DrainTracker
+
+	// costs records the gas and fee of every transaction broadcast
+	costs *CostLedger
+
+	mu      sync.Mutex
+	pending []*BotTx
+	sent    int64
+	retried int64
+	dropped int64
+}
+
+// NewBroadcastQueue creates a queue persisted in dataDir and loads the
+// transactions left by a previous run. An empty dataDir keeps it in memory.
+func NewBroadcastQueue(broadcaster TxBroadcaster, alert *TelegramAlert, dataDir string, maxAge time.Duration) *BroadcastQueue {
+	if maxAge <= 0 {
+		maxAge = DefaultBroadcastMaxAge
+	}
+
+	q := &BroadcastQueue{broadcaster: broadcaster, alert: alert, maxAge: maxAge}
+	if dataDir == "" {
+		return q
+	}
+
+	q.path = filepath.Join(dataDir, BroadcastQueueFile)
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		return q
+	}
+	if err := json.Unmarshal(data, &q.pending); err != nil {
+		log.Printf("Ignoring unreadable broadcast queue %s: %v", q.path, err)
+		q.pending = nil
+	}
+	if len(q.pending) > 0 {
+		log.Printf("Loaded %d pending bot transactions from %s", len(q.pending), q.path)
+	}
+	return q
+}
+
+// Submit broadcasts tx now and queues it for retry if that fails, unless the
+// broadcaster does not support it. A queued heartbeat of the same validator
+// is replaced, the newest one is what counts.
+func (q *BroadcastQueue) Submit(ctx context.Context, tx BotTx, now time.Time) error {
+	if tx.CreatedAt.IsZero() {
+		tx.CreatedAt = now
+	}
+	if tx.ID == "" {
+		tx.ID = fmt.Sprintf("%s-%s-%d", tx.Kind, tx.Validator, tx.CreatedAt.UnixNano())
+	}
+	if q.authz.Enabled() && tx.Granter == "" {
+		tx.Granter, tx.Grantee = q.authz.Granter, q.authz.Grantee
+	}
+	if tx.FeeGranter == "" {
+		tx.FeeGranter = q.authz.FeeGranter
+	}
+
+	if q.gate != nil {
+		if err := q.gate(); err != nil {
+			return err
+		}
+	}
+
+	// Once shutdown began the transaction is only queued for the next start
+	tx.Attempts = 1
+	var result TxResult
+	op, err := q.drain.Begin(ctx, "broadcast", tx.ID, tx)
+	if err == nil {
+		defer op.Done()
+		result, err = broadcastWithResult(op.Context(), q.broadcaster, tx)
+	} else {
+		tx.Attempts = 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Only the latest heartbeat and directory entry of a validator are worth sending
+	if tx.Kind == TxKindHeartbeat || tx.Kind == TxKindBotMetadata {
+		q.removeLocked(func(p *BotTx) bool { return p.Kind == tx.Kind && p.Validator == tx.Validator })
+	}
+	if err == nil {
+		q.sent++
+		q.costs.RecordTx(costCategory(tx.Kind), result, now)
+		return q.saveLocked()
+	}
+	if errors.Is(err, ErrUnsupportedTx) {
+		log.Printf("Not broadcasting %s: %v", tx.ID, err)
+		return err
+	}
+
+	tx.LastError = err.Error()
+	tx.NextAttempt = now.Add(retryDelay(tx.Attempts))
+	q.pending = append(q.pending, &tx)
+	log.Printf("Broadcast of %s failed, retrying from %s: %v", tx.ID, tx.NextAttempt.Format(time.RFC3339), err)
+
+	if saveErr := q.saveLocked(); saveErr != nil {
+		log.Printf("Failed to persist broadcast queue: %v", saveErr)
+	}
+	return err
+}
+
+// Run processes the queue every BroadcastRetryInterval until ctx is done
+func (q *BroadcastQueue) Run(ctx context.Context) {
+	ticker := NewJitteredTicker(BroadcastRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			q.Process(ctx, now)
+		}
+	}
+}
+
+// Process drops transactions older than the max age, alerting for each, and
+// retries up to MaxBroadcastRetriesPerTick of the due ones. Nothing is
+// retried while the gate is closed.
+func (q *BroadcastQueue) Process(ctx context.Context, now time.Time) {
+	if q.gate != nil && q.gate() != nil {
+		return
+	}
+
+	q.mu.Lock()
+	var expired, due []*BotTx
+	for _, tx := range q.pending {
+		switch {
+		case now.Sub(tx.CreatedAt) > q.maxAge:
+			expired = append(expired, tx)
+		case !tx.NextAttempt.After(now) && len(due) < MaxBroadcastRetriesPerTick:
+			due = append(due, tx)
+		}
+	}
+	q.removeLocked(func(p *BotTx) bool { return containsTx(expired, p) })
+	q.dropped += int64(len(expired))
+	q.mu.Unlock()
+
+	for _, tx := range expired {
+		q.alertDropped(*tx)
+	}
+
+	// Broadcast without the lock, a slow node must not block Submit. The
+	// retries end with the queue persisted, before shutdown may exit.
+	var finished []*BotTx
+	for _, tx := range due {
+		attempt := *tx
+		attempt.Attempts++
+		op, err := q.drain.Begin(ctx, "broadcast", tx.ID, attempt)
+		if err != nil {
+			break
+		}
+		defer op.Done()
+		result, err := broadcastWithResult(op.Context(), q.broadcaster, attempt)
+
+		q.mu.Lock()
+		q.retried++
+		tx.Attempts = attempt.Attempts
+		if err == nil {
+			finished = append(finished, tx)
+			q.sent++
+			q.costs.RecordTx(costCategory(tx.Kind), result, now)
+			log.Printf("Broadcast of %s succeeded after %d attempts", tx.ID, tx.Attempts)
+		} else if errors.Is(err, ErrUnsupportedTx) {
+			finished = append(finished, tx)
+			log.Printf("Dropping %s: %v", tx.ID, err)
+		} else {
+			tx.LastError = err.Error()
+			tx.NextAttempt = now.Add(retryDelay(tx.Attempts))
+		}
+		q.mu.Unlock()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.removeLocked(func(p *BotTx) bool { return containsTx(finished, p) })
+	if len(expired) > 0 || len(due) > 0 {
+		if err := q.saveLocked(); err != nil {
+			log.Printf("Failed to persist broadcast queue: %v", err)
+		}
+	}
+}
+
+// Resume queues the transactions whose broadcast the last shutdown
+// interrupted. Whether they reached the node is unknown, so they are
+// broadcast again: a repeated heartbeat or report is preferable to a lost one.
+func (q *BroadcastQueue) Resume(intents []Intent, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	resumed := 0
+	for _, intent := range intents {
+		var tx BotTx
+		if err := intent.Decode(&tx); err != nil {
+			log.Printf("Ignoring interrupted broadcast %q: %v", intent.Description, err)
+			continue
+		}
+		if containsTxID(q.pending, tx.ID) {
+			continue
+		}
+		tx.NextAttempt = now
+		tx.LastError = "interrupted by shutdown"
+		q.pending = append(q.pending, &tx)
+		resumed++
+	}
+	if resumed == 0 {
+		return
+	}
+	log.Printf("Resuming %d bot transactions interrupted by the last shutdown", resumed)
+	if err := q.saveLocked(); err != nil {
+		log.Printf("Failed to persist broadcast queue: %v", err)
+	}
+}
+
+// alertDropped reports a transaction that will not be retried anymore
+func (q *BroadcastQueue) alertDropped(tx BotTx) {
+	log.Printf("Dropping %s after %d attempts over %s: %s", tx.ID, tx.Attempts, q.maxAge, tx.LastError)
+	if q.alert == nil {
+		return
+	}
+
+	message := fmt.Sprintf("Validator: %s\nTransaction: %s\nAttempts: %d\nFirst attempt: %s\nLast error: %s",
+		tx.Validator, tx.Kind, tx.Attempts, tx.CreatedAt.Format("2006-01-02 15:04:05"), tx.LastError)
+	if tx.Kind == TxKindHeartbeat {
+		message += "\n⚠️ The chain may count this period as missing bot heartbeats"
+	}
+	if err := q.alert.SendAlertWithType(AlertTypeError, "Bot Transaction Dropped", message); err != nil {
+		log.Printf("Failed to send dropped transaction alert: %v", err)
+	}
+}
+
+// retryDelay is the exponential backoff after the given number of attempts
+func retryDelay(attempts int) time.Duration {
+	delay := BroadcastRetryBaseDelay
+	for i := 1; i < attempts && delay < BroadcastRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > BroadcastRetryMaxDelay {
+		delay = BroadcastRetryMaxDelay
+	}
+	return delay
+}
+
+func containsTx(txs []*BotTx, tx *BotTx) bool {
+	for _, t := range txs {
+		if t == tx {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTxID(txs []*BotTx, id string) bool {
+	for _, t := range txs {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeLocked removes the pending transactions matching drop
+func (q *BroadcastQueue) removeLocked(drop func(*BotTx) bool) {
+	kept := q.pending[:0]
+	for _, tx := range q.pending {
+		if !drop(tx) {
+			kept = append(kept, tx)
+		}
+	}
+	for i := len(kept); i < len(q.pending); i++ {
+		q.pending[i] = nil
+	}
+	q.pending = kept
+}
+
+// saveLocked writes the pending transactions to disk
+func (q *BroadcastQueue) saveLocked() error {
+	if q.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(q.pending, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.path)
+}
+
+// Pending returns a copy of the queued transactions
+func (q *BroadcastQueue) Pending() []BotTx {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := make([]BotTx, len(q.pending))
+	for i, tx := range q.pending {
+		pending[i] = *tx
+	}
+	return pending
+}
+
+// GetStatus returns the queue counters
+func (q *BroadcastQueue) GetStatus() map[string]interface{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return map[string]interface{}{
+		"pending": len(q.pending),
+		"sent":    q.sent,
+		"retried": q.retried,
+		"dropped": q.dropped,
+		"max_age": q.maxAge.String(),
+	}
+}
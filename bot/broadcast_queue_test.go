@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBroadcaster fails every broadcast while the node is down
+type fakeBroadcaster struct {
+	mu    sync.Mutex
+	down  bool
+	calls []BotTx
+	sent  []BotTx
+}
+
+func (f *fakeBroadcaster) Broadcast(_ context.Context, tx BotTx) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, tx)
+	if f.down {
+		return errors.New("connection refused")
+	}
+	f.sent = append(f.sent, tx)
+	return nil
+}
+
+func (f *fakeBroadcaster) setDown(down bool) {
+	f.mu.Lock()
+	f.down = down
+	f.mu.Unlock()
+}
+
+func TestBroadcastQueueRetriesUntilNodeRecovers(t *testing.T) {
+	dataDir := t.TempDir()
+	node := &fakeBroadcaster{down: true}
+	queue := NewBroadcastQueue(node, nil, dataDir, time.Hour)
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	report := BotTx{Kind: TxKindSlashingReport, Validator: "gxrvaloper1late", Reason: "Mandatory bot not running"}
+	if err := queue.Submit(ctx, report, start); err == nil {
+		t.Fatal("broadcast to a down node succeeded")
+	}
+	pending := queue.Pending()
+	if len(pending) != 1 || pending[0].LastError != "connection refused" || !pending[0].NextAttempt.Equal(start.Add(BroadcastRetryBaseDelay)) {
+		t.Fatalf("pending = %+v", pending)
+	}
+
+	// Nothing is retried before the backoff elapsed
+	queue.Process(ctx, start.Add(BroadcastRetryBaseDelay-time.Second))
+	if len(node.calls) != 1 {
+		t.Fatalf("%d broadcasts, want 1", len(node.calls))
+	}
+
+	// A failed retry doubles the backoff
+	queue.Process(ctx, start.Add(BroadcastRetryBaseDelay))
+	pending = queue.Pending()
+	if len(node.calls) != 2 || pending[0].Attempts != 2 || !pending[0].NextAttempt.Equal(start.Add(3*BroadcastRetryBaseDelay)) {
+		t.Fatalf("after a failed retry: %d broadcasts, pending %+v", len(node.calls), pending)
+	}
+
+	// The queue survives a restart of the bot
+	queue = NewBroadcastQueue(node, nil, dataDir, time.Hour)
+	if pending := queue.Pending(); len(pending) != 1 || pending[0].ID != node.calls[0].ID || pending[0].Attempts != 2 {
+		t.Fatalf("reloaded queue = %+v", pending)
+	}
+
+	node.setDown(false)
+	queue.Process(ctx, start.Add(3*BroadcastRetryBaseDelay))
+	if len(node.sent) != 1 || node.sent[0].ID != node.calls[0].ID || node.sent[0].Attempts != 3 {
+		t.Fatalf("sent = %+v", node.sent)
+	}
+	if len(queue.Pending()) != 0 {
+		t.Fatalf("pending after recovery = %+v", queue.Pending())
+	}
+
+	data, err := os.ReadFile(filepath.Join(dataDir, BroadcastQueueFile))
+	if err != nil || strings.TrimSpace(string(data)) != "[]" {
+		t.Fatalf("persisted queue = %q (%v)", data, err)
+	}
+	if status := queue.GetStatus(); status["sent"] != int64(1) || status["dropped"] != int64(0) {
+		t.Fatalf("status = %v", status)
+	}
+}
+
+func TestBroadcastQueueKeepsOnlyLatestHeartbeat(t *testing.T) {
+	node := &fakeBroadcaster{down: true}
+	queue := NewBroadcastQueue(node, nil, "", time.Hour)
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		queue.Submit(ctx, BotTx{Kind: TxKindHeartbeat, Validator: "gxrvaloper1ours", Memo: at.Format(time.RFC3339)}, at)
+	}
+	queue.Submit(ctx, BotTx{Kind: TxKindHeartbeat, Validator: "gxrvaloper1other"}, start)
+
+	pending := queue.Pending()
+	if len(pending) != 2 || pending[0].Validator != "gxrvaloper1ours" || pending[0].Memo != start.Add(2*time.Minute).Format(time.RFC3339) {
+		t.Fatalf("pending = %+v", pending)
+	}
+
+	// A successful heartbeat supersedes the queued one
+	node.setDown(false)
+	queue.Submit(ctx, BotTx{Kind: TxKindHeartbeat, Validator: "gxrvaloper1ours"}, start.Add(3*time.Minute))
+	if pending := queue.Pending(); len(pending) != 1 || pending[0].Validator != "gxrvaloper1other" {
+		t.Fatalf("pending = %+v", pending)
+	}
+}
+
+func TestBroadcastQueueRateLimitsRetries(t *testing.T) {
+	node := &fakeBroadcaster{down: true}
+	queue := NewBroadcastQueue(node, nil, "", time.Hour)
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2*MaxBroadcastRetriesPerTick; i++ {
+		queue.Submit(ctx, BotTx{Kind: TxKindSlashingReport, Validator: "gxrvaloper1late", ID: string(rune('a' + i))}, start)
+	}
+
+	node.setDown(false)
+	queue.Process(ctx, start.Add(BroadcastRetryBaseDelay))
+	if len(node.sent) != MaxBroadcastRetriesPerTick || len(queue.Pending()) != MaxBroadcastRetriesPerTick {
+		t.Fatalf("sent %d, pending %d", len(node.sent), len(queue.Pending()))
+	}
+	queue.Process(ctx, start.Add(BroadcastRetryBaseDelay+BroadcastRetryInterval))
+	if len(queue.Pending()) != 0 {
+		t.Fatalf("pending = %+v", queue.Pending())
+	}
+}
+
+func TestBroadcastQueueDropsExpiredAndAlerts(t *testing.T) {
+	alert := &TelegramAlert{running: true, alertQueue: make(chan *Alert, 10)}
+	node := &fakeBroadcaster{down: true}
+	queue := NewBroadcastQueue(node, alert, "", 10*time.Minute)
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	queue.Submit(ctx, BotTx{Kind: TxKindHeartbeat, Validator: "gxrvaloper1ours"}, start)
+	for at := start; at.Before(start.Add(10 * time.Minute)); at = at.Add(BroadcastRetryInterval) {
+		queue.Process(ctx, at)
+	}
+	if len(queue.Pending()) != 1 || len(alert.alertQueue) != 0 {
+		t.Fatal("transaction dropped before its max age")
+	}
+
+	queue.Process(ctx, start.Add(10*time.Minute+time.Second))
+	if len(queue.Pending()) != 0 || queue.GetStatus()["dropped"] != int64(1) {
+		t.Fatalf("expired transaction kept: %+v", queue.Pending())
+	}
+
+	select {
+	case dropped := <-alert.alertQueue:
+		if dropped.Type != AlertTypeError || !strings.Contains(dropped.Message, "connection refused") ||
+			!strings.Contains(dropped.Message, "missing bot heartbeats") {
+			t.Fatalf("drop alert = %+v", dropped)
+		}
+	default:
+		t.Fatal("no alert for the dropped transaction")
+	}
+}
+
+func TestHeartbeatQueuedWhenBroadcastFails(t *testing.T) {
+	node := &fakeBroadcaster{down: true}
+	bs := &BotService{
+		config:             &BotConfig{ValidatorAddress: "gxrvaloper1ours"},
+		protocolCompatible: true,
+		validatorMonitor:   &ValidatorMonitor{validators: make(map[string]*ValidatorStatus), botHeartbeats: make(map[string]time.Time)},
+	}
+	bs.SetBroadcaster(node)
+
+	if !bs.emitHeartbeat(context.Background()) {
+		t.Fatal("heartbeat not emitted")
+	}
+	pending := bs.broadcastQueue.Pending()
+	if len(pending) != 1 || pending[0].Kind != TxKindHeartbeat || pending[0].Memo != bs.lastHeartbeatMemo {
+		t.Fatalf("pending = %+v", pending)
+	}
+	if bs.validatorMonitor.broadcastQueue != bs.broadcastQueue {
+		t.Fatal("validator monitor does not broadcast slashing reports")
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ChainIDCheckInterval is how often the node's chain-id is re-checked, so a
+// node swapped behind the RPC URL is caught after the bot reconnects to it
+const ChainIDCheckInterval = 1 * time.Minute
+
+// ChainIDMismatchError reports a node serving another network than configured
+type ChainIDMismatchError struct {
+	Expected string
+	Node     string
+}
+
+func (e *ChainIDMismatchError) Error() string {
+	return fmt.Sprintf("node reports chain-id %q but the bot is configured for %q", e.Node, e.Expected)
+}
+
+// CheckChainID compares the chain-id reported by the node with the configured one
+func CheckChainID(expected, node string) error {
+	if node != expected {
+		return &ChainIDMismatchError{Expected: expected, Node: node}
+	}
+	return nil
+}
+
+// rpcURL turns the configured RPC address into an HTTP URL; tcp:// is what
+// CometBFT uses in its own config for the same listener
+func rpcURL(address string) string {
+	if strings.HasPrefix(address, "tcp://") {
+		return "http://" + strings.TrimPrefix(address, "tcp://")
+	}
+	return address
+}
+
+// QueryNodeChainID queries the chain-id (node_info.network) from the
+// CometBFT RPC /status of the node
+func (cq *ChainQuerier) QueryNodeChainID(ctx context.Context) (string, error) {
+	var resp struct {
+		Result struct {
+			NodeInfo struct {
+				Network string `json:"network"`
+			} `json:"node_info"`
+		} `json:"result"`
+	}
+
+	if err := cq.getJSON(ctx, "/status", &resp); err != nil {
+		return "", err
+	}
+	if resp.Result.NodeInfo.Network == "" {
+		return "", fmt.Errorf("node status has no chain-id")
+	}
+	return resp.Result.NodeInfo.Network, nil
+}
+
+// CheckChainID verifies the node serves the configured chain. On a mismatch
+// the bot alerts and runs read-only, unless the check is overridden for a
+// deliberate migration. Query failures leave the previous result in place.
+func (bs *BotService) CheckChainID(ctx context.Context) {
+	if bs.rpcQuerier == nil {
+		return
+	}
+
+	node, err := bs.rpcQuerier.QueryNodeChainID(ctx)
+
+	bs.mu.Lock()
+	if err != nil {
+		bs.chainIDError = err.Error()
+		bs.mu.Unlock()
+		log.Printf("Chain-id check skipped: %v", err)
+		return
+	}
+	mismatch := CheckChainID(bs.config.ChainID, node)
+	wasMismatched := bs.chainIDMismatch
+	bs.nodeChainID = node
+	bs.chainIDError = ""
+	bs.chainIDMismatch = mismatch != nil
+	override := bs.overrideChainIDCheck
+	bs.mu.Unlock()
+
+	if mismatch == nil {
+		if wasMismatched {
+			log.Printf("Node chain-id %s matches the configuration again; restart the bot to leave read-only mode", node)
+		}
+		return
+	}
+	if wasMismatched {
+		return
+	}
+
+	if override {
+		log.Printf("Chain-id mismatch ignored (--override-chain-id-check): %v", mismatch)
+		if bs.telegramAlert != nil {
+			bs.telegramAlert.SendBotAlert("Chain ID", "warning", mismatch.Error()+" - check overridden")
+		}
+		return
+	}
+
+	log.Printf("Chain-id mismatch: %v", mismatch)
+	bs.enterReadOnly("the node serves another chain")
+	if bs.telegramAlert != nil {
+		bs.telegramAlert.SendEmergencyAlert("Chain ID Mismatch", mismatch.Error(), map[string]interface{}{
+			"configured_chain_id": bs.config.ChainID,
+			"node_chain_id":       node,
+			"chain_rpc":           bs.config.ChainRPC,
+		})
+	}
+}
+
+// watchChainID re-checks the node's chain-id until ctx is done
+func (bs *BotService) watchChainID(ctx context.Context) {
+	ticker := NewJitteredTicker(ChainIDCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			bs.CheckChainID(ctx)
+		}
+	}
+}
+
+// chainIDStatus describes the last chain-id check for the status endpoint;
+// the caller holds bs.mu
+func (bs *BotService) chainIDStatus() map[string]interface{} {
+	return map[string]interface{}{
+		"configured": bs.config.ChainID,
+		"node":       bs.nodeChainID,
+		"mismatch":   bs.chainIDMismatch,
+		"override":   bs.overrideChainIDCheck,
+		"last_error": bs.chainIDError,
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newChainIDTestService returns a bot service whose node RPC reports the
+// chain-id stored in network
+func newChainIDTestService(t *testing.T, network *atomic.Value) *BotService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/status" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":{"node_info":{"network":%q}}}`, network.Load().(string))
+	}))
+	t.Cleanup(server.Close)
+
+	return &BotService{
+		config:             &BotConfig{ChainID: "gxr-1", ValidatorAddress: "gxrvaloper1test"},
+		healthStatus:       make(map[string]bool),
+		healthTracker:      NewHealthTracker(0, 0),
+		protocolCompatible: true,
+		rpcQuerier:         NewChainQuerierForAPI(rpcURL("tcp://" + server.Listener.Addr().String())),
+		validatorMonitor:   &ValidatorMonitor{validators: make(map[string]*ValidatorStatus), botHeartbeats: make(map[string]time.Time)},
+	}
+}
+
+func TestChainIDMatch(t *testing.T) {
+	var network atomic.Value
+	network.Store("gxr-1")
+	bs := newChainIDTestService(t, &network)
+
+	bs.CheckChainID(context.Background())
+	if bs.isReadOnly() || !bs.emitHeartbeat(context.Background()) {
+		t.Fatal("bot on the configured chain must keep broadcasting")
+	}
+	if status := bs.chainIDStatus(); status["node"] != "gxr-1" || status["mismatch"] != false {
+		t.Fatalf("status = %v", status)
+	}
+}
+
+func TestChainIDMismatchSwitchesToReadOnly(t *testing.T) {
+	var network atomic.Value
+	network.Store("gxr-testnet-2")
+	bs := newChainIDTestService(t, &network)
+
+	if err := CheckChainID("gxr-1", "gxr-testnet-2"); err == nil || err.Error() != `node reports chain-id "gxr-testnet-2" but the bot is configured for "gxr-1"` {
+		t.Fatalf("mismatch error = %v", err)
+	}
+
+	bs.CheckChainID(context.Background())
+	if !bs.isReadOnly() || bs.emitHeartbeat(context.Background()) {
+		t.Fatal("bot on another chain must stop broadcasting")
+	}
+	status := bs.GetStatus()["chain_id"].(map[string]interface{})
+	if status["configured"] != "gxr-1" || status["node"] != "gxr-testnet-2" || status["mismatch"] != true {
+		t.Fatalf("status = %v", status)
+	}
+	if !bs.healthSnapshot(time.Now()).ChainIDMismatch {
+		t.Fatal("health snapshot does not report the mismatch")
+	}
+
+	// A reconnect to the right node clears the mismatch, the bot stays
+	// read-only until restarted
+	network.Store("gxr-1")
+	bs.CheckChainID(context.Background())
+	if bs.chainIDStatus()["mismatch"] != false || !bs.isReadOnly() {
+		t.Fatalf("after the node came back: mismatch %v, read-only %v", bs.chainIDStatus()["mismatch"], bs.isReadOnly())
+	}
+}
+
+func TestChainIDMismatchOverride(t *testing.T) {
+	var network atomic.Value
+	network.Store("gxr-2")
+	bs := newChainIDTestService(t, &network)
+	bs.overrideChainIDCheck = true
+
+	bs.CheckChainID(context.Background())
+	if bs.isReadOnly() || !bs.emitHeartbeat(context.Background()) {
+		t.Fatal("--override-chain-id-check must keep the bot broadcasting")
+	}
+	if status := bs.chainIDStatus(); status["mismatch"] != true || status["override"] != true {
+		t.Fatalf("status = %v", status)
+	}
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ChainPhaseFile stores the last chain phase seen, so a restart does not
+	// alert on a transition again
+	ChainPhaseFile = "chain_phase.json"
+	// ChainPhaseCheckInterval is how often the halving schedule is queried
+	ChainPhaseCheckInterval = time.Hour
+	// ChainPhaseCountdownWindow is how long before the end of a distribution
+	// window the daily summary counts down to it
+	ChainPhaseCountdownWindow = 30 * 24 * time.Hour
+
+	// HalvingCycleDuration is the length of a halving cycle (5 years)
+	HalvingCycleDuration = 5 * 365 * 24 * time.Hour
+	// DistributionYear is one year of a distribution window
+	DistributionYear = 365 * 24 * time.Hour
+	// DistributionWindow is the length of a cycle's distribution window (730 days)
+	DistributionWindow = 2 * DistributionYear
+	// SupplyFloor is the total supply below which the chain starts no new
+	// halving cycle (1,000 GXR)
+	SupplyFloor = 1000 * 100_000_000
+)
+
+// ChainPhase is the economic phase of the chain in its halving schedule
+type ChainPhase string
+
+const (
+	// ChainPhaseAccumulating is a cycle whose distribution has not started
+	ChainPhaseAccumulating ChainPhase = "accumulating"
+	// ChainPhaseDistributingYear1 is the first year of a distribution window
+	ChainPhaseDistributingYear1 ChainPhase = "distributing_year_1"
+	// ChainPhaseDistributingYear2 is the second year of a distribution window
+	ChainPhaseDistributingYear2 ChainPhase = "distributing_year_2"
+	// ChainPhasePaused is the pause between a distribution window and the next cycle
+	ChainPhasePaused ChainPhase = "paused"
+	// ChainPhaseHalted is a supply below SupplyFloor outside a distribution
+	// window: no further cycle starts
+	ChainPhaseHalted ChainPhase = "halted"
+)
+
+// Label returns the phase for display
+func (p ChainPhase) Label() string {
+	switch p {
+	case ChainPhaseAccumulating:
+		return "accumulating"
+	case ChainPhaseDistributingYear1:
+		return "distributing (year 1)"
+	case ChainPhaseDistributingYear2:
+		return "distributing (year 2)"
+	case ChainPhasePaused:
+		return "paused"
+	case ChainPhaseHalted:
+		return "halted"
+	default:
+		return "unknown"
+	}
+}
+
+// Distributing reports whether halving rewards are paid in the phase
+func (p ChainPhase) Distributing() bool {
+	return p == ChainPhaseDistributingYear1 || p == ChainPhaseDistributingYear2
+}
+
+// PhaseInfo is the chain phase computed from the halving schedule. Since and
+// Until bound the phase; both are zero for a halted chain, which has no end.
+type PhaseInfo struct {
+	Phase      ChainPhase `json:"phase"`
+	Cycle      uint64     `json:"cycle"`
+	Since      time.Time  `json:"since,omitempty"`
+	Until      time.Time  `json:"until,omitempty"`
+	WindowEnd  time.Time  `json:"window_end,omitempty"` // end of the distribution window, when distributing
+	FinalCycle bool       `json:"final_cycle"`          // supply is below SupplyFloor, no cycle follows
+	Supply     uint64     `json:"supply"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Countdown returns the time left in the distribution window when it ends
+// within ChainPhaseCountdownWindow of now
+func (p PhaseInfo) Countdown(now time.Time) (time.Duration, bool) {
+	if !p.Phase.Distributing() || p.WindowEnd.IsZero() {
+		return 0, false
+	}
+	left := p.WindowEnd.Sub(now)
+	if left <= 0 || left > ChainPhaseCountdownWindow {
+		return 0, false
+	}
+	return left, true
+}
+
+// Describe renders the phase in one line, e.g. "distributing (year 2) of
+// cycle 2 until 2027-01-15"
+func (p PhaseInfo) Describe() string {
+	if p.Phase == "" {
+		return "unknown"
+	}
+	if p.Phase == ChainPhaseHalted {
+		return fmt.Sprintf("halted after cycle %d: supply below %d ugen", p.Cycle, uint64(SupplyFloor))
+	}
+
+	description := fmt.Sprintf("%s of cycle %d", p.Phase.Label(), p.Cycle)
+	if !p.Until.IsZero() {
+		description += " until " + p.Until.UTC().Format("2006-01-02")
+	}
+	if p.FinalCycle {
+		description += ", final cycle"
+	}
+	return description
+}
+
+// computeChainPhase places now in the halving schedule. The chain ends a
+// distribution window and advances cycles in its next block, so a window
+// past its 730 days counts as paused already. A supply below the floor stops
+// the cycles but not a window in progress: it is distributed to its end.
+func computeChainPhase(schedule HalvingSchedule, supply uint64, now time.Time) PhaseInfo {
+	info := PhaseInfo{
+		Cycle:      schedule.Cycle,
+		FinalCycle: supply < SupplyFloor,
+		Supply:     supply,
+		UpdatedAt:  now,
+	}
+	nextCycle := time.Time{}
+	if !schedule.CycleStart.IsZero() {
+		nextCycle = schedule.CycleStart.Add(HalvingCycleDuration)
+	}
+
+	if schedule.DistributionActive && !schedule.DistributionStart.IsZero() {
+		start := schedule.DistributionStart
+		elapsed := now.Sub(start)
+		switch {
+		case elapsed < DistributionYear:
+			info.Phase = ChainPhaseDistributingYear1
+			info.Since, info.Until = start, start.Add(DistributionYear)
+			info.WindowEnd = start.Add(DistributionWindow)
+			return info
+		case elapsed < DistributionWindow:
+			info.Phase = ChainPhaseDistributingYear2
+			info.Since, info.Until = start.Add(DistributionYear), start.Add(DistributionWindow)
+			info.WindowEnd = info.Until
+			return info
+		}
+		info.Phase = ChainPhasePaused
+		info.Since, info.Until = start.Add(DistributionWindow), nextCycle
+	} else if schedule.DistributionStart.IsZero() {
+		info.Phase = ChainPhaseAccumulating
+		info.Since, info.Until = schedule.CycleStart, nextCycle
+	} else {
+		info.Phase = ChainPhasePaused
+		info.Since, info.Until = schedule.PauseStart, nextCycle
+		if info.Since.IsZero() {
+			info.Since = schedule.DistributionStart.Add(DistributionWindow)
+		}
+	}
+
+	if info.FinalCycle {
+		info.Phase = ChainPhaseHalted
+		info.Since, info.Until = time.Time{}, time.Time{}
+	}
+	return info
+}
+
+// ChainPhaseTracker follows the chain phase through the halving schedule and
+// alerts once on every phase transition
+type ChainPhaseTracker struct {
+	chain         *ChainQuerier
+	telegramAlert *TelegramAlert
+	path          string
+
+	mu        sync.RWMutex
+	current   PhaseInfo
+	lastError string
+}
+
+// NewChainPhaseTracker creates the tracker, restoring the last phase seen
+// from dataDir; an empty dataDir keeps it in memory only
+func NewChainPhaseTracker(config *BotConfig, chain *ChainQuerier, telegramAlert *TelegramAlert) *ChainPhaseTracker {
+	t := &ChainPhaseTracker{
+		chain:         chain,
+		telegramAlert: telegramAlert,
+	}
+
+	if config.DataDir != "" {
+		t.path = filepath.Join(config.DataDir, ChainPhaseFile)
+		if data, err := os.ReadFile(t.path); err == nil {
+			if err := json.Unmarshal(data, &t.current); err != nil {
+				log.Printf("Ignoring unreadable chain phase %s: %v", t.path, err)
+				t.current = PhaseInfo{}
+			}
+		}
+	}
+
+	return t
+}
+
+// Start checks the chain phase every ChainPhaseCheckInterval until ctx is done
+func (t *ChainPhaseTracker) Start(ctx context.Context) error {
+	log.Printf("Starting chain phase tracker")
+
+	t.Check(ctx)
+
+	ticker := NewJitteredTicker(ChainPhaseCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.Check(ctx)
+		}
+	}
+}
+
+// Check queries the halving schedule and total supply and records the phase
+// at the chain's latest block time, which the schedule is kept in
+func (t *ChainPhaseTracker) Check(ctx context.Context) {
+	info, err := t.query(ctx)
+	if err != nil {
+		log.Printf("Failed to query chain phase: %v", err)
+		t.mu.Lock()
+		t.lastError = err.Error()
+		t.mu.Unlock()
+		return
+	}
+
+	if alert := t.Record(info); alert != nil && t.telegramAlert != nil {
+		if err := t.telegramAlert.QueueAlert(alert); err != nil {
+			log.Printf("Failed to queue chain phase alert: %v", err)
+		}
+	}
+}
+
+// query computes the phase from the chain
+func (t *ChainPhaseTracker) query(ctx context.Context) (PhaseInfo, error) {
+	schedule, err := t.chain.QueryHalvingSchedule(ctx)
+	if err != nil {
+		return PhaseInfo{}, err
+	}
+	supply, err := t.chain.QuerySupplyOf(ctx, EscrowDenom)
+	if err != nil {
+		return PhaseInfo{}, err
+	}
+	blockTime, err := t.chain.QueryLatestBlockTime(ctx)
+	if err != nil {
+		return PhaseInfo{}, err
+	}
+	return computeChainPhase(schedule, supply, blockTime), nil
+}
+
+// Record stores the phase and returns the alert of a transition. The first
+// phase seen is only stored: there is no transition to report.
+func (t *ChainPhaseTracker) Record(info PhaseInfo) *Alert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous := t.current
+	t.current = info
+	t.lastError = ""
+	t.saveLocked()
+
+	if previous.Phase == "" || previous.Phase == info.Phase {
+		return nil
+	}
+
+	log.Printf("Chain phase changed from %s to %s", previous.Phase, info.Phase)
+	event := fmt.Sprintf("chain phase changed from %s to %s", previous.Phase.Label(), info.Phase.Label())
+	return newHalvingAlert(info.Cycle, event, info.Describe(), info.UpdatedAt)
+}
+
+// Current returns the last phase recorded, with an empty Phase before the
+// first check
+func (t *ChainPhaseTracker) Current() PhaseInfo {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.current
+}
+
+// SummaryLines returns the chain phase lines of the daily summary, with a
+// countdown during the final days of a distribution window
+func (t *ChainPhaseTracker) SummaryLines(now time.Time) []string {
+	info := t.Current()
+	if info.Phase == "" {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("Chain phase: %s", info.Describe())}
+	if left, ok := info.Countdown(now); ok {
+		days := int(left.Hours() / 24)
+		lines = append(lines, fmt.Sprintf("⏳ Distribution window ends in %d day(s), on %s", days, info.WindowEnd.UTC().Format("2006-01-02 15:04 UTC")))
+	}
+	return lines
+}
+
+// GetStatus returns the chain phase for the status endpoint
+func (t *ChainPhaseTracker) GetStatus() map[string]interface{} {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	status := map[string]interface{}{
+		"phase":       string(t.current.Phase),
+		"label":       t.current.Phase.Label(),
+		"cycle":       t.current.Cycle,
+		"final_cycle": t.current.FinalCycle,
+		"supply":      t.current.Supply,
+	}
+	for key, value := range map[string]time.Time{
+		"since":      t.current.Since,
+		"until":      t.current.Until,
+		"window_end": t.current.WindowEnd,
+		"updated_at": t.current.UpdatedAt,
+	} {
+		if !value.IsZero() {
+			status[key] = value.UTC().Format(time.RFC3339)
+		}
+	}
+	if t.lastError != "" {
+		status["last_error"] = t.lastError
+	}
+	return status
+}
+
+// saveLocked persists the current phase; callers hold t.mu
+func (t *ChainPhaseTracker) saveLocked() {
+	if t.path == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(t.current, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode chain phase: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		log.Printf("Failed to save chain phase: %v", err)
+		return
+	}
+
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("Failed to save chain phase: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		os.Remove(tmp)
+		log.Printf("Failed to save chain phase: %v", err)
+	}
+}
+
+// statusReply answers the /status command with the chain phase
+func (ta *TelegramAlert) statusReply(now time.Time) string {
+	ta.mu.RLock()
+	tracker := ta.chainPhase
+	ta.mu.RUnlock()
+
+	lines := []string{"📊 *Status*", fmt.Sprintf("Bot version: %s", Version)}
+	if tracker == nil || tracker.Current().Phase == "" {
+		return strings.Join(append(lines, "Chain phase: unknown"), "\n")
+	}
+	return strings.Join(append(lines, tracker.SummaryLines(now)...), "\n")
+}
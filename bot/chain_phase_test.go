@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeChainPhaseBoundaries(t *testing.T) {
+	cycleStart := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	windowStart := cycleStart.Add(HalvingCycleDuration)
+	supply := uint64(SupplyFloor) * 1000
+
+	accumulating := HalvingSchedule{Cycle: 1, CycleStart: cycleStart}
+	distributing := HalvingSchedule{Cycle: 2, CycleStart: windowStart, DistributionActive: true, DistributionStart: windowStart}
+	paused := HalvingSchedule{Cycle: 2, CycleStart: windowStart, DistributionStart: windowStart, PauseStart: windowStart.Add(DistributionWindow + time.Minute)}
+
+	testCases := []struct {
+		name     string
+		schedule HalvingSchedule
+		supply   uint64
+		now      time.Time
+		phase    ChainPhase
+		until    time.Time
+	}{
+		{"first cycle", accumulating, supply, cycleStart, ChainPhaseAccumulating, windowStart},
+		{"window start", distributing, supply, windowStart, ChainPhaseDistributingYear1, windowStart.Add(DistributionYear)},
+		{"last second of year 1", distributing, supply, windowStart.Add(DistributionYear - time.Second), ChainPhaseDistributingYear1, windowStart.Add(DistributionYear)},
+		{"year 2", distributing, supply, windowStart.Add(DistributionYear), ChainPhaseDistributingYear2, windowStart.Add(DistributionWindow)},
+		{"last second of the window", distributing, supply, windowStart.Add(DistributionWindow - time.Second), ChainPhaseDistributingYear2, windowStart.Add(DistributionWindow)},
+		// The chain ends the window in its next block
+		{"window over, not ended yet", distributing, supply, windowStart.Add(DistributionWindow), ChainPhasePaused, windowStart.Add(HalvingCycleDuration)},
+		{"pause", paused, supply, windowStart.Add(3 * DistributionYear), ChainPhasePaused, windowStart.Add(HalvingCycleDuration)},
+		// Below the floor no cycle follows; a window in progress still pays out
+		{"halted in the pause", paused, SupplyFloor - 1, windowStart.Add(3 * DistributionYear), ChainPhaseHalted, time.Time{}},
+		{"halted while accumulating", accumulating, 0, cycleStart, ChainPhaseHalted, time.Time{}},
+		{"final window", distributing, SupplyFloor - 1, windowStart.Add(DistributionYear), ChainPhaseDistributingYear2, windowStart.Add(DistributionWindow)},
+		{"at the floor", paused, SupplyFloor, windowStart.Add(3 * DistributionYear), ChainPhasePaused, windowStart.Add(HalvingCycleDuration)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info := computeChainPhase(tc.schedule, tc.supply, tc.now)
+			if info.Phase != tc.phase || !info.Until.Equal(tc.until) {
+				t.Fatalf("phase %s until %v, want %s until %v", info.Phase, info.Until, tc.phase, tc.until)
+			}
+			if info.FinalCycle != (tc.supply < SupplyFloor) {
+				t.Fatalf("final cycle = %v", info.FinalCycle)
+			}
+		})
+	}
+
+	halted := computeChainPhase(paused, 0, windowStart.Add(3*DistributionYear))
+	if got := halted.Describe(); got != "halted after cycle 2: supply below 100000000000 ugen" {
+		t.Fatalf("halted description = %q", got)
+	}
+	if got := computeChainPhase(distributing, SupplyFloor-1, windowStart).Describe(); got != "distributing (year 1) of cycle 2 until 2031-01-14, final cycle" {
+		t.Fatalf("final window description = %q", got)
+	}
+}
+
+func TestChainPhaseCountdown(t *testing.T) {
+	start := time.Date(2030, 1, 14, 0, 0, 0, 0, time.UTC)
+	schedule := HalvingSchedule{Cycle: 2, CycleStart: start, DistributionActive: true, DistributionStart: start}
+	end := start.Add(DistributionWindow)
+
+	for _, now := range []time.Time{start.Add(DistributionYear - 10*24*time.Hour), end.Add(-ChainPhaseCountdownWindow - time.Second)} {
+		if _, ok := computeChainPhase(schedule, SupplyFloor, now).Countdown(now); ok {
+			t.Fatalf("countdown at %v", now)
+		}
+	}
+
+	now := end.Add(-ChainPhaseCountdownWindow)
+	if left, ok := computeChainPhase(schedule, SupplyFloor, now).Countdown(now); !ok || left != ChainPhaseCountdownWindow {
+		t.Fatalf("countdown at the start of the final 30 days = %v, %v", left, ok)
+	}
+
+	tracker := NewChainPhaseTracker(&BotConfig{}, nil, nil)
+	now = end.Add(-36 * time.Hour)
+	tracker.Record(computeChainPhase(schedule, SupplyFloor, now))
+	vm := newForecastTestMonitor()
+	vm.chainPhase = tracker
+	summary := vm.dailySummary(now)
+	if !strings.Contains(summary, "Chain phase: distributing (year 2) of cycle 2 until 2032-01-14") ||
+		!strings.Contains(summary, "Distribution window ends in 1 day(s), on 2032-01-14 00:00 UTC") {
+		t.Fatalf("summary:\n%s", summary)
+	}
+}
+
+func TestChainPhaseTrackerAlertsOnceOnTransition(t *testing.T) {
+	config := &BotConfig{DataDir: t.TempDir()}
+	start := time.Date(2030, 1, 14, 0, 0, 0, 0, time.UTC)
+	schedule := HalvingSchedule{Cycle: 2, CycleStart: start, DistributionActive: true, DistributionStart: start}
+
+	tracker := NewChainPhaseTracker(config, nil, nil)
+	if alert := tracker.Record(computeChainPhase(schedule, SupplyFloor, start)); alert != nil {
+		t.Fatalf("first phase alerted: %s", alert.Message)
+	}
+	if alert := tracker.Record(computeChainPhase(schedule, SupplyFloor, start.Add(time.Hour))); alert != nil {
+		t.Fatalf("same phase alerted: %s", alert.Message)
+	}
+
+	alert := tracker.Record(computeChainPhase(schedule, SupplyFloor, start.Add(DistributionYear)))
+	if alert == nil || alert.Title != "Halving Event" ||
+		alert.Message != "Cycle 2: chain phase changed from distributing (year 1) to distributing (year 2)" {
+		t.Fatalf("transition alert = %+v", alert)
+	}
+
+	// The phase survives a restart, so the transition is not reported again
+	restored := NewChainPhaseTracker(config, nil, nil)
+	if phase := restored.Current().Phase; phase != ChainPhaseDistributingYear2 {
+		t.Fatalf("restored phase = %s", phase)
+	}
+	if alert := restored.Record(computeChainPhase(schedule, SupplyFloor, start.Add(DistributionYear+time.Hour))); alert != nil {
+		t.Fatalf("restart alerted: %s", alert.Message)
+	}
+
+	status := restored.GetStatus()
+	if status["phase"] != "distributing_year_2" || status["window_end"] != "2032-01-14T00:00:00Z" {
+		t.Fatalf("status = %v", status)
+	}
+
+	// /status reports the phase in the alert chats
+	ta := newOnCallTestAlert(t, &telegramRecorder{})
+	if reply, ok := ta.commandReply("-100100", "/status", start); !ok || !strings.Contains(reply, "Chain phase: unknown") {
+		t.Fatalf("/status reply without a tracker = %q", reply)
+	}
+	ta.SetChainPhaseTracker(restored)
+	reply, ok := ta.commandReply("-100100", "/status", start.Add(DistributionYear+time.Hour))
+	if !ok || !strings.Contains(reply, "Chain phase: distributing (year 2) of cycle 2 until 2032-01-14") {
+		t.Fatalf("/status reply = %q", reply)
+	}
+}
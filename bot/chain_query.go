@@ -0,0 +1,706 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// DefaultChainAPI is the default REST (LCD) endpoint of the node
+	DefaultChainAPI = "http://localhost:1317"
+	// ChainQueryTimeout bounds a single REST query
+	ChainQueryTimeout = 10 * time.Second
+)
+
+// ChainQuerier performs read-only queries against the chain REST endpoint
+type ChainQuerier struct {
+	baseURL string
+	client  *http.Client
+	limiter *OutboundLimiter
+	header  http.Header // sent with every request, e.g. an API key
+}
+
+// NewChainQuerier creates a new chain querier from the bot configuration
+func NewChainQuerier(config *BotConfig) *ChainQuerier {
+	baseURL := config.ChainAPI
+	if baseURL == "" {
+		baseURL = DefaultChainAPI
+	}
+
+	return NewChainQuerierForAPI(baseURL)
+}
+
+// NewChainQuerierForAPI creates a chain querier for a REST endpoint, such as
+// the one of an IBC counterparty chain
+func NewChainQuerierForAPI(baseURL string) *ChainQuerier {
+	return &ChainQuerier{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: ChainQueryTimeout},
+		limiter: sharedOutbound.Load(),
+	}
+}
+
+// withHeader sets a header sent with every request of the querier
+func (cq *ChainQuerier) withHeader(name, value string) *ChainQuerier {
+	if cq.header == nil {
+		cq.header = make(http.Header)
+	}
+	cq.header.Set(name, value)
+	return cq
+}
+
+// getJSON fetches path from the REST endpoint and decodes the JSON body into out
+func (cq *ChainQuerier) getJSON(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", cq.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range cq.header {
+		req.Header[name] = values
+	}
+
+	if err := cq.limiter.Wait(ctx, cq.baseURL); err != nil {
+		return fmt.Errorf("failed to query %s: %w", path, err)
+	}
+
+	resp, err := cq.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	cq.limiter.Observe(cq.baseURL, resp, body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("query %s failed with status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// QueryBotProtocolVersion queries the bot protocol versions advertised by x/halving
+func (cq *ChainQuerier) QueryBotProtocolVersion(ctx context.Context) (ProtocolInfo, error) {
+	var resp struct {
+		BotProtocolVersion  jsonUint64 `json:"bot_protocol_version"`
+		MinSupportedVersion jsonUint64 `json:"min_supported_version"`
+	}
+
+	if err := cq.getJSON(ctx, "/gxr/halving/v1beta1/bot_protocol_version", &resp); err != nil {
+		return ProtocolInfo{}, err
+	}
+
+	return ProtocolInfo{
+		ChainVersion:        uint64(resp.BotProtocolVersion),
+		MinSupportedVersion: uint64(resp.MinSupportedVersion),
+	}, nil
+}
+
+// QueryBalance queries the balance of an account in denom
+func (cq *ChainQuerier) QueryBalance(ctx context.Context, address, denom string) (uint64, error) {
+	var resp struct {
+		Balance struct {
+			Amount jsonUint64 `json:"amount"`
+		} `json:"balance"`
+	}
+
+	path := fmt.Sprintf("/cosmos/bank/v1beta1/balances/%s/by_denom?denom=%s", address, url.QueryEscape(denom))
+	if err := cq.getJSON(ctx, path, &resp); err != nil {
+		return 0, err
+	}
+	return uint64(resp.Balance.Amount), nil
+}
+
+// QuerySupplyOf queries the total supply of denom
+func (cq *ChainQuerier) QuerySupplyOf(ctx context.Context, denom string) (uint64, error) {
+	var resp struct {
+		Amount struct {
+			Amount jsonUint64 `json:"amount"`
+		} `json:"amount"`
+	}
+
+	if err := cq.getJSON(ctx, "/cosmos/bank/v1beta1/supply/by_denom?denom="+url.QueryEscape(denom), &resp); err != nil {
+		return 0, err
+	}
+	return uint64(resp.Amount.Amount), nil
+}
+
+// HalvingSchedule is the chain's position in the halving schedule: the
+// current cycle and its distribution window. Times are zero when unset.
+type HalvingSchedule struct {
+	Cycle              uint64
+	CycleStart         time.Time
+	DistributionActive bool
+	DistributionStart  time.Time
+	PauseStart         time.Time
+}
+
+// QueryHalvingSchedule queries the current halving cycle and distribution window
+func (cq *ChainQuerier) QueryHalvingSchedule(ctx context.Context) (HalvingSchedule, error) {
+	var resp struct {
+		HalvingInfo struct {
+			CurrentCycle       jsonUint64 `json:"current_cycle"`
+			CycleStartTime     jsonUint64 `json:"cycle_start_time"`
+			DistributionActive bool       `json:"distribution_active"`
+			DistributionStart  jsonUint64 `json:"distribution_start"`
+			PauseStart         jsonUint64 `json:"pause_start"`
+		} `json:"halving_info"`
+	}
+
+	if err := cq.getJSON(ctx, "/gxr/halving/v1beta1/halving_info", &resp); err != nil {
+		return HalvingSchedule{}, err
+	}
+
+	info := resp.HalvingInfo
+	unix := func(seconds jsonUint64) time.Time {
+		if seconds == 0 {
+			return time.Time{}
+		}
+		return time.Unix(int64(seconds), 0).UTC()
+	}
+	return HalvingSchedule{
+		Cycle:              uint64(info.CurrentCycle),
+		CycleStart:         unix(info.CycleStartTime),
+		DistributionActive: info.DistributionActive,
+		DistributionStart:  unix(info.DistributionStart),
+		PauseStart:         unix(info.PauseStart),
+	}, nil
+}
+
+// PendingDexAllocation is the DEX share the chain kept in the dex_distribution
+// account for the bot to distribute. Amounts are in ugen.
+type PendingDexAllocation struct {
+	Cycle          uint64
+	Amount         int64
+	TotalAllocated int64
+	Months         uint64
+	LastUpdated    time.Time
+}
+
+// QueryPendingDexAllocation queries the DEX share awaiting bot distribution in the current cycle
+func (cq *ChainQuerier) QueryPendingDexAllocation(ctx context.Context) (PendingDexAllocation, error) {
+	type coin struct {
+		Denom  string     `json:"denom"`
+		Amount jsonUint64 `json:"amount"`
+	}
+	var resp struct {
+		PendingDexAllocation struct {
+			Cycle          jsonUint64 `json:"cycle"`
+			Amount         coin       `json:"amount"`
+			TotalAllocated coin       `json:"total_allocated"`
+			Months         jsonUint64 `json:"months"`
+			LastUpdated    jsonUint64 `json:"last_updated"`
+		} `json:"pending_dex_allocation"`
+	}
+
+	if err := cq.getJSON(ctx, "/gxr/halving/v1beta1/pending_dex_allocation", &resp); err != nil {
+		return PendingDexAllocation{}, err
+	}
+
+	pending := resp.PendingDexAllocation
+	if pending.Amount.Denom != "" && pending.Amount.Denom != "ugen" {
+		return PendingDexAllocation{}, fmt.Errorf("unexpected pending DEX allocation denom %s", pending.Amount.Denom)
+	}
+
+	result := PendingDexAllocation{
+		Cycle:          uint64(pending.Cycle),
+		Amount:         int64(pending.Amount.Amount),
+		TotalAllocated: int64(pending.TotalAllocated.Amount),
+		Months:         uint64(pending.Months),
+	}
+	if pending.LastUpdated > 0 {
+		result.LastUpdated = time.Unix(int64(pending.LastUpdated), 0)
+	}
+	return result, nil
+}
+
+// DexDistributionBalance is what the chain's dex_distribution account holds
+// for the bot and how much of it is pending. Amounts are in ugen.
+type DexDistributionBalance struct {
+	Address string
+	Balance int64
+	Pending int64
+}
+
+// QueryDexDistributionBalance queries the balance of the dex_distribution account
+func (cq *ChainQuerier) QueryDexDistributionBalance(ctx context.Context) (DexDistributionBalance, error) {
+	type coin struct {
+		Denom  string     `json:"denom"`
+		Amount jsonUint64 `json:"amount"`
+	}
+	var resp struct {
+		Address string `json:"address"`
+		Balance coin   `json:"balance"`
+		Pending coin   `json:"pending"`
+	}
+
+	if err := cq.getJSON(ctx, "/gxr/halving/v1beta1/dex_distribution_balance", &resp); err != nil {
+		return DexDistributionBalance{}, err
+	}
+
+	for _, c := range []coin{resp.Balance, resp.Pending} {
+		if c.Denom != "" && c.Denom != "ugen" {
+			return DexDistributionBalance{}, fmt.Errorf("unexpected DEX distribution denom %s", c.Denom)
+		}
+	}
+	return DexDistributionBalance{
+		Address: resp.Address,
+		Balance: int64(resp.Balance.Amount),
+		Pending: int64(resp.Pending.Amount),
+	}, nil
+}
+
+// ValidatorUptimeInfo is the chain's uptime record of a validator for the
+// current uptime month, as used for the 10-day reward threshold
+type ValidatorUptimeInfo struct {
+	Month                 uint64
+	InactiveDays          uint64
+	DeclaredInactiveDays  uint64
+	EffectiveInactiveDays float64
+	LastCheck             time.Time
+	Declarations          []DowntimeWindow
+}
+
+// DowntimeWindow is a planned downtime window declared on chain
+type DowntimeWindow struct {
+	Start  time.Time
+	End    time.Time
+	Days   uint64
+	Reason string
+}
+
+// QueryValidatorUptime queries the halving module's uptime record of a validator
+func (cq *ChainQuerier) QueryValidatorUptime(ctx context.Context, validatorAddress string) (ValidatorUptimeInfo, error) {
+	var resp struct {
+		Uptime struct {
+			CurrentMonth         jsonUint64 `json:"current_month"`
+			InactiveDays         jsonUint64 `json:"inactive_days"`
+			LastCheck            jsonUint64 `json:"last_check"`
+			DeclaredInactiveDays jsonUint64 `json:"declared_inactive_days"`
+		} `json:"uptime"`
+		EffectiveInactiveDays string `json:"effective_inactive_days"`
+		Declarations          []struct {
+			Start  jsonUint64 `json:"start"`
+			End    jsonUint64 `json:"end"`
+			Days   jsonUint64 `json:"days"`
+			Reason string     `json:"reason"`
+		} `json:"declarations"`
+	}
+
+	if err := cq.getJSON(ctx, "/gxr/halving/v1beta1/validator_uptime/"+validatorAddress, &resp); err != nil {
+		return ValidatorUptimeInfo{}, err
+	}
+
+	info := ValidatorUptimeInfo{
+		Month:                uint64(resp.Uptime.CurrentMonth),
+		InactiveDays:         uint64(resp.Uptime.InactiveDays),
+		DeclaredInactiveDays: uint64(resp.Uptime.DeclaredInactiveDays),
+	}
+	if resp.Uptime.LastCheck > 0 {
+		info.LastCheck = time.Unix(int64(resp.Uptime.LastCheck), 0)
+	}
+
+	info.EffectiveInactiveDays = float64(info.InactiveDays)
+	if resp.EffectiveInactiveDays != "" {
+		effective, err := strconv.ParseFloat(resp.EffectiveInactiveDays, 64)
+		if err != nil {
+			return ValidatorUptimeInfo{}, fmt.Errorf("invalid effective_inactive_days %q: %w", resp.EffectiveInactiveDays, err)
+		}
+		info.EffectiveInactiveDays = effective
+	}
+
+	for _, declaration := range resp.Declarations {
+		info.Declarations = append(info.Declarations, DowntimeWindow{
+			Start:  time.Unix(int64(declaration.Start), 0),
+			End:    time.Unix(int64(declaration.End), 0),
+			Days:   uint64(declaration.Days),
+			Reason: declaration.Reason,
+		})
+	}
+	return info, nil
+}
+
+// QueryDeclaredDowntimeWeight queries the weight at which declared downtime
+// days count toward the inactivity threshold. Chains without downtime
+// declarations report no weight, which counts every day fully.
+func (cq *ChainQuerier) QueryDeclaredDowntimeWeight(ctx context.Context) (float64, error) {
+	var resp struct {
+		Params struct {
+			DeclaredDowntimeWeight string `json:"declared_downtime_weight"`
+		} `json:"params"`
+	}
+
+	if err := cq.getJSON(ctx, "/gxr/halving/v1beta1/params", &resp); err != nil {
+		return 0, err
+	}
+	if resp.Params.DeclaredDowntimeWeight == "" {
+		return 1, nil
+	}
+
+	weight, err := strconv.ParseFloat(resp.Params.DeclaredDowntimeWeight, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid declared_downtime_weight %q: %w", resp.Params.DeclaredDowntimeWeight, err)
+	}
+	return weight, nil
+}
+
+// QueryBotMetadata queries a validator's entry in the halving module's bot
+// directory; found is false while the validator has not registered one
+func (cq *ChainQuerier) QueryBotMetadata(ctx context.Context, validatorAddress string) (metadata BotMetadata, found bool, err error) {
+	var resp struct {
+		BotMetadata *struct {
+			Version       string     `json:"version"`
+			Contact       string     `json:"contact"`
+			EndpointURL   string     `json:"endpoint_url"`
+			UpdatedHeight jsonUint64 `json:"updated_height"`
+		} `json:"bot_metadata"`
+	}
+
+	if err := cq.getJSON(ctx, "/gxr/halving/v1beta1/heartbeat_compliance/"+validatorAddress, &resp); err != nil {
+		return BotMetadata{}, false, err
+	}
+	if resp.BotMetadata == nil {
+		return BotMetadata{}, false, nil
+	}
+	return BotMetadata{
+		Version:       resp.BotMetadata.Version,
+		Contact:       resp.BotMetadata.Contact,
+		EndpointURL:   resp.BotMetadata.EndpointURL,
+		UpdatedHeight: uint64(resp.BotMetadata.UpdatedHeight),
+	}, true, nil
+}
+
+// QueryLatestBlockTime queries the time of the latest block, which the chain
+// uses for its month accounting
+func (cq *ChainQuerier) QueryLatestBlockTime(ctx context.Context) (time.Time, error) {
+	var resp struct {
+		Block struct {
+			Header struct {
+				Height jsonUint64 `json:"height"`
+				Time   time.Time  `json:"time"`
+			} `json:"header"`
+		} `json:"block"`
+	}
+
+	if err := cq.getJSON(ctx, "/cosmos/base/tendermint/v1beta1/blocks/latest", &resp); err != nil {
+		return time.Time{}, err
+	}
+
+	header := resp.Block.Header
+	if header.Time.IsZero() {
+		return time.Time{}, fmt.Errorf("latest block %d has no time", uint64(header.Height))
+	}
+	return header.Time.UTC(), nil
+}
+
+// VotingProposal is a governance proposal in its voting period
+type VotingProposal struct {
+	ID        uint64
+	VotingEnd time.Time
+}
+
+// QueryVotingProposals queries the governance proposals in their voting period
+func (cq *ChainQuerier) QueryVotingProposals(ctx context.Context) ([]VotingProposal, error) {
+	var proposals []VotingProposal
+	nextKey := ""
+
+	for {
+		var resp struct {
+			Proposals []struct {
+				ID            jsonUint64 `json:"id"`
+				VotingEndTime time.Time  `json:"voting_end_time"`
+			} `json:"proposals"`
+			Pagination struct {
+				NextKey string `json:"next_key"`
+			} `json:"pagination"`
+		}
+
+		path := "/cosmos/gov/v1/proposals?proposal_status=PROPOSAL_STATUS_VOTING_PERIOD"
+		if nextKey != "" {
+			path += "&pagination.key=" + url.QueryEscape(nextKey)
+		}
+		if err := cq.getJSON(ctx, path, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, proposal := range resp.Proposals {
+			proposals = append(proposals, VotingProposal{ID: uint64(proposal.ID), VotingEnd: proposal.VotingEndTime.UTC()})
+		}
+		if resp.Pagination.NextKey == "" {
+			return proposals, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
+// QueryProposalVoters queries the accounts that voted on a proposal. Votes
+// are pruned once the proposal is tallied, so this only sees the votes of
+// proposals still in their voting period.
+func (cq *ChainQuerier) QueryProposalVoters(ctx context.Context, proposalID uint64) ([]string, error) {
+	var voters []string
+	nextKey := ""
+
+	for {
+		var resp struct {
+			Votes []struct {
+				Voter string `json:"voter"`
+			} `json:"votes"`
+			Pagination struct {
+				NextKey string `json:"next_key"`
+			} `json:"pagination"`
+		}
+
+		path := fmt.Sprintf("/cosmos/gov/v1/proposals/%d/votes", proposalID)
+		if nextKey != "" {
+			path += "?pagination.key=" + url.QueryEscape(nextKey)
+		}
+		if err := cq.getJSON(ctx, path, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, vote := range resp.Votes {
+			voters = append(voters, vote.Voter)
+		}
+		if resp.Pagination.NextKey == "" {
+			return voters, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
+// IBCHeight is the height of an IBC client, its revision number and the
+// block height in that revision
+type IBCHeight struct {
+	RevisionNumber uint64 `json:"revision_number"`
+	RevisionHeight uint64 `json:"revision_height"`
+}
+
+// QueryChannelClientHeight queries the latest height of the light client
+// behind a channel, the newest counterparty height proofs can be verified at
+func (cq *ChainQuerier) QueryChannelClientHeight(ctx context.Context, portID, channelID string) (IBCHeight, error) {
+	var resp struct {
+		IdentifiedClientState struct {
+			ClientID    string `json:"client_id"`
+			ClientState struct {
+				LatestHeight struct {
+					RevisionNumber jsonUint64 `json:"revision_number"`
+					RevisionHeight jsonUint64 `json:"revision_height"`
+				} `json:"latest_height"`
+			} `json:"client_state"`
+		} `json:"identified_client_state"`
+	}
+
+	path := fmt.Sprintf("/ibc/core/channel/v1/channels/%s/ports/%s/client_state", channelID, portID)
+	if err := cq.getJSON(ctx, path, &resp); err != nil {
+		return IBCHeight{}, err
+	}
+
+	latest := resp.IdentifiedClientState.ClientState.LatestHeight
+	if latest.RevisionHeight == 0 {
+		return IBCHeight{}, fmt.Errorf("client %q of %s/%s has no latest height", resp.IdentifiedClientState.ClientID, portID, channelID)
+	}
+	return IBCHeight{RevisionNumber: uint64(latest.RevisionNumber), RevisionHeight: uint64(latest.RevisionHeight)}, nil
+}
+
+// QueryPacketReceiptAbsence queries the proof that the receipt of a packet is
+// absent from the IBC store at height, encoded as an ICS-23 merkle proof.
+// The proof verifies against the app hash of the block after height. It
+// fails if the packet was received.
+func (cq *ChainQuerier) QueryPacketReceiptAbsence(ctx context.Context, portID, channelID string, sequence, height uint64) ([]byte, error) {
+	var resp struct {
+		Value    []byte `json:"value"`
+		ProofOps struct {
+			Ops []struct {
+				Data []byte `json:"data"`
+			} `json:"ops"`
+		} `json:"proof_ops"`
+	}
+
+	key := fmt.Sprintf("receipts/ports/%s/channels/%s/sequences/%d", portID, channelID, sequence)
+	query := url.Values{}
+	query.Set("path", "store/ibc/key")
+	query.Set("data", base64.StdEncoding.EncodeToString([]byte(key)))
+	query.Set("height", strconv.FormatUint(height, 10))
+	query.Set("prove", "true")
+	if err := cq.getJSON(ctx, "/cosmos/base/tendermint/v1beta1/abci_query?"+query.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Value) > 0 {
+		return nil, fmt.Errorf("packet %s/%s/%d was received", portID, channelID, sequence)
+	}
+	if len(resp.ProofOps.Ops) == 0 {
+		return nil, fmt.Errorf("no proof of the receipt of %s/%s/%d at height %d", portID, channelID, sequence, height)
+	}
+
+	// A MerkleProof is the list of commitment proofs of the proof ops
+	var proof []byte
+	for _, op := range resp.ProofOps.Ops {
+		proof = protowire.AppendTag(proof, 1, protowire.BytesType)
+		proof = protowire.AppendBytes(proof, op.Data)
+	}
+	return proof, nil
+}
+
+// PacketCommitment is the commitment of a packet sent on this chain, stored
+// until the packet is acknowledged or timed out
+type PacketCommitment struct {
+	Sequence uint64
+	Hash     []byte
+}
+
+// QueryPacketCommitments queries the commitments of the packets sent on a
+// channel end that are still outstanding
+func (cq *ChainQuerier) QueryPacketCommitments(ctx context.Context, portID, channelID string) ([]PacketCommitment, error) {
+	var commitments []PacketCommitment
+	nextKey := ""
+
+	for {
+		var resp struct {
+			Commitments []struct {
+				Sequence jsonUint64 `json:"sequence"`
+				Data     []byte     `json:"data"`
+			} `json:"commitments"`
+			Pagination struct {
+				NextKey string `json:"next_key"`
+			} `json:"pagination"`
+		}
+
+		path := fmt.Sprintf("/ibc/core/channel/v1/channels/%s/ports/%s/packet_commitments", channelID, portID)
+		if nextKey != "" {
+			path += "?pagination.key=" + url.QueryEscape(nextKey)
+		}
+		if err := cq.getJSON(ctx, path, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, commitment := range resp.Commitments {
+			commitments = append(commitments, PacketCommitment{Sequence: uint64(commitment.Sequence), Hash: commitment.Data})
+		}
+		if resp.Pagination.NextKey == "" {
+			return commitments, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
+// QuerySentPacket rebuilds a packet sent on a channel end from the
+// send_packet event of the transaction that sent it
+func (cq *ChainQuerier) QuerySentPacket(ctx context.Context, portID, channelID string, sequence uint64) (IBCPacket, error) {
+	params := url.Values{}
+	params["events"] = []string{
+		fmt.Sprintf("send_packet.packet_src_port='%s'", portID),
+		fmt.Sprintf("send_packet.packet_src_channel='%s'", channelID),
+		fmt.Sprintf("send_packet.packet_sequence='%d'", sequence),
+	}
+
+	var resp struct {
+		TxResponses []struct {
+			Events []struct {
+				Type       string `json:"type"`
+				Attributes []struct {
+					Key   string `json:"key"`
+					Value string `json:"value"`
+				} `json:"attributes"`
+			} `json:"events"`
+		} `json:"tx_responses"`
+	}
+	if err := cq.getJSON(ctx, "/cosmos/tx/v1beta1/txs?"+params.Encode(), &resp); err != nil {
+		return IBCPacket{}, err
+	}
+
+	for _, tx := range resp.TxResponses {
+		for _, event := range tx.Events {
+			if event.Type != "send_packet" {
+				continue
+			}
+			attributes := make(map[string]string)
+			for _, attribute := range event.Attributes {
+				attributes[attribute.Key] = attribute.Value
+			}
+			if attributes["packet_src_port"] != portID || attributes["packet_src_channel"] != channelID ||
+				attributes["packet_sequence"] != strconv.FormatUint(sequence, 10) {
+				continue
+			}
+			return sentPacket(attributes)
+		}
+	}
+	return IBCPacket{}, fmt.Errorf("no send_packet event of %s/%s/%d", portID, channelID, sequence)
+}
+
+// sentPacket decodes the packet of a send_packet event
+func sentPacket(attributes map[string]string) (IBCPacket, error) {
+	packet := IBCPacket{
+		SourcePort:          attributes["packet_src_port"],
+		ChannelID:           attributes["packet_src_channel"],
+		CounterpartyPort:    attributes["packet_dst_port"],
+		CounterpartyChannel: attributes["packet_dst_channel"],
+		Data:                []byte(attributes["packet_data"]),
+	}
+	var err error
+	if packet.Sequence, err = strconv.ParseUint(attributes["packet_sequence"], 10, 64); err != nil {
+		return IBCPacket{}, fmt.Errorf("invalid packet_sequence: %w", err)
+	}
+	if data, ok := attributes["packet_data_hex"]; ok {
+		if packet.Data, err = hex.DecodeString(data); err != nil {
+			return IBCPacket{}, fmt.Errorf("invalid packet_data_hex: %w", err)
+		}
+	}
+
+	// The timeout height is "<revision number>-<revision height>"
+	if height := attributes["packet_timeout_height"]; height != "" {
+		number, revisionHeight, ok := strings.Cut(height, "-")
+		var numberErr, heightErr error
+		packet.TimeoutHeight.RevisionNumber, numberErr = strconv.ParseUint(number, 10, 64)
+		packet.TimeoutHeight.RevisionHeight, heightErr = strconv.ParseUint(revisionHeight, 10, 64)
+		if !ok || numberErr != nil || heightErr != nil {
+			return IBCPacket{}, fmt.Errorf("invalid packet_timeout_height %q", height)
+		}
+	}
+	if timestamp := attributes["packet_timeout_timestamp"]; timestamp != "" && timestamp != "0" {
+		nanos, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return IBCPacket{}, fmt.Errorf("invalid packet_timeout_timestamp: %w", err)
+		}
+		packet.TimeoutTimestamp = time.Unix(0, nanos).UTC()
+	}
+	return packet, nil
+}
+
+// jsonUint64 decodes uint64 values that the gateway may encode as strings
+type jsonUint64 uint64
+
+// UnmarshalJSON accepts both quoted and unquoted integers
+func (u *jsonUint64) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "" || value == "null" {
+		*u = 0
+		return nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s: %w", string(data), err)
+	}
+
+	*u = jsonUint64(parsed)
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// CurrentConfigVersion is the bot.yaml schema version this binary writes and understands
+	CurrentConfigVersion = 2
+	// legacyConfigVersion is assumed for config files without config_version
+	legacyConfigVersion = 1
+)
+
+// configMigration upgrades a raw config from version-1 to version. It
+// returns a description of every change it made.
+type configMigration struct {
+	version int
+	migrate func(cfg *rawConfig) []string
+}
+
+// configMigrations are applied in order to configs older than their version
+var configMigrations = []configMigration{
+	{version: 2, migrate: migrateConfigV2},
+}
+
+// migrateConfigV2 handles configs written before config_version existed
+func migrateConfigV2(cfg *rawConfig) []string {
+	var changes []string
+
+	// The documented example config set a token without telegram_enabled,
+	// which left alerts silently disabled
+	if _, ok := cfg.get("telegram_enabled"); !ok {
+		if token, ok := cfg.get("telegram_token"); ok && token != "" {
+			cfg.set("telegram_enabled", true)
+			changes = append(changes, "set telegram_enabled: true because telegram_token is configured")
+		}
+	}
+
+	// emergency_mode was never read by the bot
+	if cfg.delete("emergency_mode") {
+		changes = append(changes, "removed unsupported emergency_mode")
+	}
+
+	return changes
+}
+
+// rawConfig is a parsed config file that keeps its key order, so a migrated
+// config can be written back close to the original layout
+type rawConfig struct {
+	items yaml.MapSlice
+}
+
+func (c *rawConfig) index(key string) int {
+	for i, item := range c.items {
+		if k, ok := item.Key.(string); ok && k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *rawConfig) get(key string) (interface{}, bool) {
+	if i := c.index(key); i >= 0 {
+		return c.items[i].Value, true
+	}
+	return nil, false
+}
+
+func (c *rawConfig) set(key string, value interface{}) {
+	if i := c.index(key); i >= 0 {
+		c.items[i].Value = value
+		return
+	}
+	c.items = append(c.items, yaml.MapItem{Key: key, Value: value})
+}
+
+func (c *rawConfig) delete(key string) bool {
+	i := c.index(key)
+	if i < 0 {
+		return false
+	}
+	c.items = append(c.items[:i], c.items[i+1:]...)
+	return true
+}
+
+// ConfigMigration is the outcome of migrating a config file
+type ConfigMigration struct {
+	FromVersion int
+	ToVersion   int
+	Changes     []string
+	Data        []byte // the config at ToVersion
+}
+
+// Migrated reports whether the config was older than the current version
+func (m ConfigMigration) Migrated() bool {
+	return m.FromVersion < m.ToVersion
+}
+
+// MigrateConfig upgrades raw bot.yaml contents to CurrentConfigVersion. It
+// rejects configs written by a newer binary.
+func MigrateConfig(data []byte) (ConfigMigration, error) {
+	cfg := &rawConfig{}
+	if err := yaml.Unmarshal(data, &cfg.items); err != nil {
+		return ConfigMigration{}, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	version := legacyConfigVersion
+	if value, ok := cfg.get("config_version"); ok {
+		v, ok := value.(int)
+		if !ok || v < legacyConfigVersion {
+			return ConfigMigration{}, fmt.Errorf("invalid config_version %v", value)
+		}
+		version = v
+	}
+	if version > CurrentConfigVersion {
+		return ConfigMigration{}, fmt.Errorf("config_version %d is newer than this binary supports (%d), upgrade gxr-bot",
+			version, CurrentConfigVersion)
+	}
+
+	migration := ConfigMigration{FromVersion: version, ToVersion: CurrentConfigVersion, Data: data}
+	if !migration.Migrated() {
+		return migration, nil
+	}
+
+	for _, step := range configMigrations {
+		if step.version <= version {
+			continue
+		}
+		for _, change := range step.migrate(cfg) {
+			migration.Changes = append(migration.Changes, fmt.Sprintf("v%d: %s", step.version, change))
+		}
+	}
+
+	if cfg.index("config_version") < 0 {
+		cfg.items = append(yaml.MapSlice{{Key: "config_version"}}, cfg.items...)
+	}
+	cfg.set("config_version", CurrentConfigVersion)
+	migrated, err := yaml.Marshal(cfg.items)
+	if err != nil {
+		return ConfigMigration{}, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	migration.Data = migrated
+	return migration, nil
+}
+
+// writeMigratedConfig replaces the config file with its migrated contents,
+// keeping the original next to it since comments are not preserved
+func writeMigratedConfig(configPath string, original []byte, migration ConfigMigration) error {
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return err
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", configPath, migration.FromVersion)
+	if err := os.WriteFile(backupPath, original, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to back up config: %w", err)
+	}
+	if err := os.WriteFile(configPath, migration.Data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write migrated config: %w", err)
+	}
+
+	log.Printf("Migrated config written to %s, previous version saved as %s", configPath, backupPath)
+	return nil
+}
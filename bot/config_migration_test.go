@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const legacyConfig = `chain_rpc: "tcp://localhost:26657"
+chain_grpc: "localhost:9090"
+chain_id: "gxr-1"
+validator_address: "gxrvaloper1ours"
+telegram_token: "123:abc"
+telegram_chat_id: "-100"
+emergency_mode: false
+price_oracle:
+  source: "mock"
+`
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bot.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMigrateLegacyConfig(t *testing.T) {
+	migration, err := MigrateConfig([]byte(legacyConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !migration.Migrated() || migration.FromVersion != 1 || migration.ToVersion != CurrentConfigVersion {
+		t.Fatalf("migrated %d -> %d", migration.FromVersion, migration.ToVersion)
+	}
+	if len(migration.Changes) != 2 {
+		t.Fatalf("changes = %v, want telegram_enabled and emergency_mode", migration.Changes)
+	}
+
+	data := string(migration.Data)
+	if !strings.HasPrefix(data, "config_version: 2\n") {
+		t.Fatalf("config_version not written first:\n%s", data)
+	}
+	if strings.Contains(data, "emergency_mode") || !strings.Contains(data, "telegram_enabled: true") {
+		t.Fatalf("migrated config:\n%s", data)
+	}
+
+	// A current config is left untouched
+	again, err := MigrateConfig(migration.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Migrated() || string(again.Data) != data {
+		t.Fatalf("current config was migrated again: %v", again.Changes)
+	}
+}
+
+func TestMigrateConfigKeepsExplicitSettings(t *testing.T) {
+	migration, err := MigrateConfig([]byte("telegram_enabled: false\ntelegram_token: \"123:abc\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migration.Changes) != 0 || !strings.Contains(string(migration.Data), "telegram_enabled: false") {
+		t.Fatalf("explicit telegram_enabled overridden: %v\n%s", migration.Changes, migration.Data)
+	}
+}
+
+func TestMigrateConfigRejectsUnsupportedVersions(t *testing.T) {
+	for _, contents := range []string{"config_version: 3\n", "config_version: 0\n", "config_version: two\n"} {
+		if _, err := MigrateConfig([]byte(contents)); err == nil {
+			t.Fatalf("%q accepted", contents)
+		}
+	}
+
+	_, err := LoadConfig(writeConfig(t, "config_version: 99\n"+legacyConfig))
+	if err == nil || !strings.Contains(err.Error(), "newer than this binary supports") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestLoadConfigMigratesInMemory(t *testing.T) {
+	path := writeConfig(t, legacyConfig)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ConfigVersion != CurrentConfigVersion || !config.TelegramEnabled {
+		t.Fatalf("version = %d, telegram_enabled = %v", config.ConfigVersion, config.TelegramEnabled)
+	}
+
+	// Without --migrate the file is not rewritten
+	if data, _ := os.ReadFile(path); string(data) != legacyConfig {
+		t.Fatalf("config file was rewritten:\n%s", data)
+	}
+}
+
+func TestLoadConfigWritesMigratedConfig(t *testing.T) {
+	path := writeConfig(t, legacyConfig)
+
+	if _, err := LoadConfigWithMigration(path, true); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "config_version: 2\n") {
+		t.Fatalf("migrated config not written:\n%s", data)
+	}
+	backup, err := os.ReadFile(path + ".v1.bak")
+	if err != nil || string(backup) != legacyConfig {
+		t.Fatalf("backup = %q, %v", backup, err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Fatalf("migrated config mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CostLedgerFile is the persisted cost ledger in the data directory
+	CostLedgerFile = "cost_ledger.json"
+	// CostSampleInterval is how often Telegram and RPC usage is sampled and
+	// the ledger saved
+	CostSampleInterval = 5 * time.Minute
+	// CostLedgerDays is how many days of daily totals are kept, monthly
+	// totals are kept for good
+	CostLedgerDays = 62
+
+	costDayLayout = "2006-01-02"
+)
+
+// Cost categories of the transactions the bot pays for
+const (
+	CostHeartbeat    = "heartbeat"
+	CostRefill       = "refill"
+	CostDistribution = "distribution"
+	CostIBCRelay     = "ibc_relay"
+	CostOther        = "other"
+)
+
+// costCategories orders the categories in reports
+var costCategories = []string{CostHeartbeat, CostRefill, CostDistribution, CostIBCRelay, CostOther}
+
+// costCategory returns the cost category of a bot transaction kind
+func costCategory(kind string) string {
+	switch kind {
+	case TxKindHeartbeat:
+		return CostHeartbeat
+	case TxKindIBCTimeout:
+		return CostIBCRelay
+	default:
+		return CostOther
+	}
+}
+
+// TxResult is what a broadcast transaction cost, the fee in ugen. Simulated
+// operations report an empty result and are only counted.
+type TxResult struct {
+	TxHash    string
+	GasWanted int64
+	GasUsed   int64
+	Fee       int64
+}
+
+// TxResultBroadcaster is a TxBroadcaster that also reports the gas and fee
+// of the transactions it broadcasts
+type TxResultBroadcaster interface {
+	TxBroadcaster
+	BroadcastWithResult(ctx context.Context, tx BotTx) (TxResult, error)
+}
+
+// broadcastWithResult broadcasts tx, with its result if the broadcaster
+// reports one
+func broadcastWithResult(ctx context.Context, broadcaster TxBroadcaster, tx BotTx) (TxResult, error) {
+	if b, ok := broadcaster.(TxResultBroadcaster); ok {
+		return b.BroadcastWithResult(ctx, tx)
+	}
+	return TxResult{}, broadcaster.Broadcast(ctx, tx)
+}
+
+// TxCost totals the transactions of a category, the fee in ugen
+type TxCost struct {
+	Count   int64 `json:"count"`
+	GasUsed int64 `json:"gas_used"`
+	Fee     int64 `json:"fee"`
+}
+
+// CostTotals is what running the bot cost during a day or month
+type CostTotals struct {
+	Txs              map[string]TxCost `json:"txs"`
+	TelegramRequests int64             `json:"telegram_requests"`
+	RPCQueries       int64             `json:"rpc_queries"`
+}
+
+// Fee returns the fees of all transactions in ugen
+func (t CostTotals) Fee() int64 {
+	var fee int64
+	for _, cost := range t.Txs {
+		fee += cost.Fee
+	}
+	return fee
+}
+
+func (t *CostTotals) addTx(category string, result TxResult) {
+	if t.Txs == nil {
+		t.Txs = make(map[string]TxCost)
+	}
+	cost := t.Txs[category]
+	cost.Count++
+	cost.GasUsed += result.GasUsed
+	cost.Fee += result.Fee
+	t.Txs[category] = cost
+}
+
+// costLedgerFile is the persisted form of a CostLedger
+type costLedgerFile struct {
+	Days   map[string]*CostTotals `json:"days"`
+	Months map[string]*CostTotals `json:"months"`
+}
+
+// CostLedger aggregates what running the bot costs validators per day and
+// month: the gas and fees of its transactions by category, its Telegram API
+// requests and its chain queries. It is kept in data_dir, an empty data_dir
+// keeps it in memory. A nil ledger records nothing.
+type CostLedger struct {
+	mu     sync.Mutex
+	path   string
+	ledger costLedgerFile
+
+	// Usage counters at the last sample, both restart at 0 with the bot
+	telegramSeen int64
+	rpcSeen      uint64
+}
+
+// NewCostLedger creates a ledger persisted in dataDir and loads the totals
+// recorded by previous runs
+func NewCostLedger(dataDir string) *CostLedger {
+	l := &CostLedger{ledger: costLedgerFile{
+		Days:   make(map[string]*CostTotals),
+		Months: make(map[string]*CostTotals),
+	}}
+	if dataDir == "" {
+		return l
+	}
+
+	l.path = filepath.Join(dataDir, CostLedgerFile)
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return l
+	}
+	var loaded costLedgerFile
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		log.Printf("Ignoring unreadable cost ledger %s: %v", l.path, err)
+		return l
+	}
+	for day, totals := range loaded.Days {
+		l.ledger.Days[day] = totals
+	}
+	for month, totals := range loaded.Months {
+		l.ledger.Months[month] = totals
+	}
+	return l
+}
+
+// RecordTx records a transaction of category sent at at
+func (l *CostLedger) RecordTx(category string, result TxResult, at time.Time) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, totals := range l.totalsLocked(at) {
+		totals.addTx(category, result)
+	}
+}
+
+// RecordUsage records the Telegram API requests and chain queries made since
+// the last sample. Both are the bot's running totals.
+func (l *CostLedger) RecordUsage(telegramRequests int64, rpcQueries uint64, at time.Time) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	telegram := telegramRequests - l.telegramSeen
+	rpc := int64(rpcQueries - l.rpcSeen)
+	l.telegramSeen, l.rpcSeen = telegramRequests, rpcQueries
+	if telegram <= 0 && rpc <= 0 {
+		return
+	}
+	for _, totals := range l.totalsLocked(at) {
+		totals.TelegramRequests += max(telegram, 0)
+		totals.RPCQueries += max(rpc, 0)
+	}
+}
+
+// totalsLocked returns the day and month totals at falls in
+func (l *CostLedger) totalsLocked(at time.Time) []*CostTotals {
+	at = at.UTC()
+	day, month := at.Format(costDayLayout), at.Format(ReportMonthLayout)
+	if l.ledger.Days[day] == nil {
+		l.ledger.Days[day] = &CostTotals{}
+	}
+	if l.ledger.Months[month] == nil {
+		l.ledger.Months[month] = &CostTotals{}
+	}
+	return []*CostTotals{l.ledger.Days[day], l.ledger.Months[month]}
+}
+
+// Month returns the totals of a month in YYYY-MM form
+func (l *CostLedger) Month(month string) (CostTotals, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	totals, ok := l.ledger.Months[month]
+	if !ok {
+		return CostTotals{}, false
+	}
+	return copyCostTotals(totals), true
+}
+
+// Days returns the daily totals of a month in YYYY-MM form, by day
+func (l *CostLedger) Days(month string) map[string]CostTotals {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	days := make(map[string]CostTotals)
+	for day, totals := range l.ledger.Days {
+		if strings.HasPrefix(day, month+"-") {
+			days[day] = copyCostTotals(totals)
+		}
+	}
+	return days
+}
+
+// Save persists the ledger, dropping the daily totals older than
+// CostLedgerDays
+func (l *CostLedger) Save(now time.Time) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	oldest := now.UTC().AddDate(0, 0, -CostLedgerDays).Format(costDayLayout)
+	for day := range l.ledger.Days {
+		if day < oldest {
+			delete(l.ledger.Days, day)
+		}
+	}
+	if l.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(l.ledger, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
+
+// ReportCosts returns the cost section of the monthly report of month, nil
+// if nothing was recorded in it
+func (l *CostLedger) ReportCosts(month string) *ReportCosts {
+	totals, ok := l.Month(month)
+	if !ok {
+		return nil
+	}
+
+	costs := &ReportCosts{
+		TelegramRequests: uint64(totals.TelegramRequests),
+		RPCQueries:       uint64(totals.RPCQueries),
+	}
+	// Categories of a newer bot version follow the known ones
+	var unknown []string
+	for category := range totals.Txs {
+		if !containsString(costCategories, category) {
+			unknown = append(unknown, category)
+		}
+	}
+	sort.Strings(unknown)
+	for _, category := range append(append([]string(nil), costCategories...), unknown...) {
+		cost, ok := totals.Txs[category]
+		if !ok {
+			continue
+		}
+		costs.Categories = append(costs.Categories, ReportCost{
+			Category:     category,
+			Transactions: uint64(cost.Count),
+			GasUsed:      uint64(cost.GasUsed),
+			Fee:          uint64(cost.Fee),
+		})
+	}
+	return costs
+}
+
+func copyCostTotals(totals *CostTotals) CostTotals {
+	c := *totals
+	c.Txs = make(map[string]TxCost, len(totals.Txs))
+	for category, cost := range totals.Txs {
+		c.Txs[category] = cost
+	}
+	return c
+}
+
+// reportCostsFor returns the bot operating costs of month recorded in the
+// configured data_dir
+func reportCostsFor(config *BotConfig, month string) *ReportCosts {
+	return NewCostLedger(config.DataDir).ReportCosts(month)
+}
+
+// recordCosts samples the Telegram and RPC usage into the cost ledger and
+// saves it every CostSampleInterval until ctx is done
+func (bs *BotService) recordCosts(ctx context.Context) {
+	ticker := NewJitteredTicker(CostSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			bs.sampleCosts(now)
+		}
+	}
+}
+
+// sampleCosts records the usage since the last sample and saves the ledger
+func (bs *BotService) sampleCosts(now time.Time) {
+	// The rebalancer and validator monitor send through alerters of their own
+	telegram := bs.telegramAlert.APIRequests()
+	if bs.rebalancer != nil {
+		telegram += bs.rebalancer.telegramAlert.APIRequests()
+	}
+	if bs.validatorMonitor != nil {
+		telegram += bs.validatorMonitor.telegramAlert.APIRequests()
+	}
+	bs.costLedger.RecordUsage(telegram, bs.outbound.Requests(), now)
+	if err := bs.costLedger.Save(now); err != nil {
+		log.Printf("Failed to persist cost ledger: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resultBroadcaster reports a fixed gas and fee for every transaction
+type resultBroadcaster struct {
+	results map[string]TxResult
+}
+
+func (b resultBroadcaster) Broadcast(ctx context.Context, tx BotTx) error {
+	_, err := b.BroadcastWithResult(ctx, tx)
+	return err
+}
+
+func (b resultBroadcaster) BroadcastWithResult(_ context.Context, tx BotTx) (TxResult, error) {
+	return b.results[tx.Kind], nil
+}
+
+func TestCostLedgerAggregatesTxResults(t *testing.T) {
+	dataDir := t.TempDir()
+	ledger := NewCostLedger(dataDir)
+	day1 := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	queue := NewBroadcastQueue(resultBroadcaster{results: map[string]TxResult{
+		TxKindHeartbeat:      {GasUsed: 80_000, Fee: 2_000},
+		TxKindSlashingReport: {GasUsed: 150_000, Fee: 4_000},
+	}}, nil, "", time.Hour)
+	queue.costs = ledger
+
+	for _, tx := range []BotTx{
+		{Kind: TxKindHeartbeat, Validator: "gxrvaloper1ours"},
+		{Kind: TxKindHeartbeat, Validator: "gxrvaloper1ours"},
+		{Kind: TxKindSlashingReport, Validator: "gxrvaloper1other"},
+	} {
+		if err := queue.Submit(context.Background(), tx, day1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ledger.RecordTx(CostRefill, TxResult{GasUsed: 120_000, Fee: 3_000}, day2)
+	ledger.RecordTx(CostDistribution, TxResult{GasUsed: 400_000, Fee: 10_000}, day2)
+
+	// Usage is sampled from running totals
+	ledger.RecordUsage(10, 100, day1)
+	ledger.RecordUsage(15, 160, day2)
+
+	days := ledger.Days("2025-03")
+	if got := days["2025-03-01"]; got.Txs[CostHeartbeat] != (TxCost{Count: 2, GasUsed: 160_000, Fee: 4_000}) ||
+		got.Txs[CostOther].Fee != 4_000 || got.TelegramRequests != 10 || got.RPCQueries != 100 || got.Fee() != 8_000 {
+		t.Fatalf("day 1 = %+v", got)
+	}
+	if got := days["2025-03-02"]; got.Txs[CostRefill].Fee != 3_000 || got.TelegramRequests != 5 || got.RPCQueries != 60 || got.Fee() != 13_000 {
+		t.Fatalf("day 2 = %+v", got)
+	}
+
+	// The ledger survives a restart
+	if err := ledger.Save(day2); err != nil {
+		t.Fatal(err)
+	}
+	month, ok := NewCostLedger(dataDir).Month("2025-03")
+	if !ok || month.Fee() != 21_000 || month.Txs[CostHeartbeat].Count != 2 || month.TelegramRequests != 15 || month.RPCQueries != 160 {
+		t.Fatalf("month = %+v", month)
+	}
+
+	// The monthly report lists the categories in order, next to the rewards
+	report := sampleMonthlyReport()
+	report.Costs = NewCostLedger(dataDir).ReportCosts("2025-03")
+	var categories []string
+	for _, cost := range report.Costs.Categories {
+		categories = append(categories, cost.Category)
+	}
+	if strings.Join(categories, ",") != "heartbeat,refill,distribution,other" || report.Costs.TotalFee() != 21_000 {
+		t.Fatalf("report costs = %+v", report.Costs)
+	}
+	if report.NetRewards() != int64(report.Rewards.Total())-21_000 {
+		t.Fatalf("net rewards = %d", report.NetRewards())
+	}
+	markdown, err := RenderReport(report, "markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(markdown), "## Bot Operating Cost") || !strings.Contains(string(markdown), "| heartbeat | 2 | 160000 | 4000 ugen |") {
+		t.Fatalf("markdown report:\n%s", markdown)
+	}
+}
+
+func TestCostLedgerPrunesOldDays(t *testing.T) {
+	ledger := NewCostLedger("")
+	old := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ledger.RecordTx(CostHeartbeat, TxResult{Fee: 1_000}, old)
+
+	if err := ledger.Save(old.AddDate(0, 0, CostLedgerDays+1)); err != nil {
+		t.Fatal(err)
+	}
+	if days := ledger.Days("2025-01"); len(days) != 0 {
+		t.Fatalf("old days kept: %v", days)
+	}
+	if month, ok := ledger.Month("2025-01"); !ok || month.Fee() != 1_000 {
+		t.Fatalf("month = %+v", month)
+	}
+}
+
+func TestStatusServerCosts(t *testing.T) {
+	s := newTestStatusServer(StatusServerConfig{})
+	handler := s.Handler()
+
+	if got := statusRequest(t, handler, http.MethodGet, "/costs", ""); got != http.StatusServiceUnavailable {
+		t.Fatalf("costs without a ledger = %d", got)
+	}
+
+	s.bs.costLedger = NewCostLedger("")
+	s.bs.costLedger.RecordTx(CostIBCRelay, TxResult{GasUsed: 90_000, Fee: 2_500}, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC))
+	if got := statusRequest(t, handler, http.MethodGet, "/costs?month=March", ""); got != http.StatusBadRequest {
+		t.Fatalf("invalid month = %d", got)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/costs?month=2025-03", nil))
+	var body struct {
+		TotalFee int64                 `json:"total_fee"`
+		Days     map[string]CostTotals `json:"days"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("costs = %d %s", rec.Code, rec.Body)
+	}
+	if body.TotalFee != 2_500 || body.Days["2025-03-05"].Txs[CostIBCRelay].Count != 1 {
+		t.Fatalf("costs = %+v", body)
+	}
+}
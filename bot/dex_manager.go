@@ -2,23 +2,86 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"sync"
 	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// DEXManager handles DEX pool management and auto refill
+// DefaultPoolBalanceCheckInterval is how often the pool balances are checked
+// against the minimum balance without pool_balance_check_interval
+const DefaultPoolBalanceCheckInterval = 15 * time.Minute
+
+// PoolMinBalanceGXR is the balance below which a pool is refilled right away
+const PoolMinBalanceGXR = 1000
+
+// DEXManager handles DEX pool management and auto refill. The pool loop,
+// the rebalancer's swaps and the status server use it concurrently.
 type DEXManager struct {
 	config *BotConfig
 	
+	// mu guards the pools and the counters below it. It is not held across
+	// transfers, swaps or chain queries; those get a copy of the pool.
+	mu sync.RWMutex
+	
 	// DEX state
 	pools        map[string]*DEXPool
 	refillCount  int64
 	totalRefill  string
+	swapCount    int64
+	
+	// simulatedSwapCount counts the swaps that were not broadcast, apart
+	// from the executed ones in swapCount
+	simulatedSwapCount int64
+	
+	// DEX share the chain allocated for the bot. The chain amount only drops once
+	// a distribution is recorded on chain, so what this run already sent is
+	// tracked per cycle and subtracted.
+	chain         *ChainQuerier
+	pending       PendingDexAllocation
+	account       DexDistributionBalance
+	distributed   map[uint64]int64
+	totalRefilled int64
 	
 	// Pool monitoring
-	minBalanceThreshold string
-	refillInterval      time.Duration
+	minBalanceThreshold  string
+	refillInterval       time.Duration
+	balanceCheckInterval time.Duration
+	
+	// bankQuery reads the pool balances; without it pools are only refilled
+	// every refillInterval
+	bankQuery banktypes.QueryClient
+	
+	// alerts receives the low pool balance warnings, nil sends none
+	alerts *TelegramAlert
+	
+	// transfer sends a refill to a pool
+	transfer func(ctx context.Context, pool *DEXPool, amount int64) error
+	
+	// swap places a rebalance swap on a pool; a result marked Simulated was
+	// not broadcast
+	swap func(ctx context.Context, pool *DEXPool, amountGXR float64, direction SwapDirection) (SwapResult, error)
+	
+	// Refills in flight are drained on shutdown
+	drain *DrainTracker
+	
+	// costs records every refill; transfer reports no fee, so refills are
+	// only counted
+	costs *CostLedger
+}
+
+// DEXRefillIntent is a pool refill, recorded if shutdown interrupts it
+type DEXRefillIntent struct {
+	Pool    string `json:"pool"`
+	Address string `json:"address"`
+	Cycle   uint64 `json:"cycle"`
+	Amount  int64  `json:"amount"`
 }
 
 // DEXPool represents a DEX liquidity pool
@@ -29,6 +92,11 @@ type DEXPool struct {
 	Active     bool
 	LastRefill time.Time
 	RefillCount int64
+	SwapCount   int64
+	
+	// LowBalance is set while the pool holds less than the minimum balance,
+	// so each drop below it is alerted once
+	LowBalance bool
 	
 	// Pool health metrics
 	Volume24h   string
@@ -38,18 +106,32 @@ type DEXPool struct {
 
 // NewDEXManager creates a new DEX manager instance
 func NewDEXManager(config *BotConfig) *DEXManager {
-	return &DEXManager{
-		config:              config,
-		pools:               make(map[string]*DEXPool),
-		minBalanceThreshold: "1000ugen", // 1000 GXR minimum balance
-		refillInterval:      6 * time.Hour,
+	balanceCheckInterval := config.PoolBalanceCheckInterval
+	if balanceCheckInterval <= 0 {
+		balanceCheckInterval = DefaultPoolBalanceCheckInterval
 	}
+	
+	dm := &DEXManager{
+		config:               config,
+		pools:                make(map[string]*DEXPool),
+		chain:                NewChainQuerier(config),
+		distributed:          make(map[uint64]int64),
+		minBalanceThreshold:  fmt.Sprintf("%dugen", PoolMinBalanceGXR*UgenPerGXR),
+		refillInterval:       6 * time.Hour,
+		balanceCheckInterval: balanceCheckInterval,
+	}
+	dm.transfer = dm.simulateRefill
+	dm.swap = dm.simulateSwap
+	return dm
 }
 
 // Initialize initializes the DEX manager
 func (dm *DEXManager) Initialize() error {
 	log.Println("Initializing DEX Manager...")
 	
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	
 	// Initialize default DEX pools
 	dm.pools["GXR/TON"] = &DEXPool{
 		Name:       "GXR/TON",
@@ -106,9 +188,18 @@ func (dm *DEXManager) validatePools() error {
 func (dm *DEXManager) Start(ctx context.Context) error {
 	log.Println("Starting DEX Manager service...")
 	
-	ticker := time.NewTicker(dm.config.CheckInterval)
+	ticker := NewJitteredTicker(dm.config.CheckInterval)
 	defer ticker.Stop()
 	
+	// Pool balances are only checked with a bank query client; the
+	// time-based refills of managePools run either way
+	var balanceCheck <-chan time.Time
+	if dm.bankQuery != nil {
+		balanceTicker := NewJitteredTicker(dm.balanceCheckInterval)
+		defer balanceTicker.Stop()
+		balanceCheck = balanceTicker.C
+	}
+	
 	for {
 		select {
 		case <-ctx.Done():
@@ -116,18 +207,32 @@ func (dm *DEXManager) Start(ctx context.Context) error {
 			return nil
 			
 		case <-ticker.C:
-			if err := dm.managePools(); err != nil {
+			if err := dm.managePools(ctx); err != nil {
 				log.Printf("DEX Manager error: %v", err)
 			}
+			
+		case <-balanceCheck:
+			if err := dm.checkPoolBalances(ctx); err != nil {
+				log.Printf("DEX pool balance check error: %v", err)
+			}
 		}
 	}
 }
 
 // managePools manages all DEX pools
-func (dm *DEXManager) managePools() error {
+func (dm *DEXManager) managePools(ctx context.Context) error {
 	log.Println("Managing DEX pools...")
 	
-	for name, pool := range dm.pools {
+	dm.mu.Lock()
+	names := make([]string, 0, len(dm.pools))
+	for name := range dm.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	
+	var due []*DEXPool
+	for _, name := range names {
+		pool := dm.pools[name]
 		if !pool.Active {
 			log.Printf("Skipping inactive pool: %s", name)
 			continue
@@ -140,10 +245,7 @@ func (dm *DEXManager) managePools() error {
 		
 		// Check if pool needs refill
 		if dm.needsRefill(pool) {
-			if err := dm.refillPool(pool); err != nil {
-				log.Printf("Error refilling pool %s: %v", name, err)
-				continue
-			}
+			due = append(due, pool)
 		}
 		
 		// Check pool health
@@ -151,11 +253,192 @@ func (dm *DEXManager) managePools() error {
 			log.Printf("Pool health issue for %s: %v", name, err)
 		}
 	}
+	dm.mu.Unlock()
+	
+	return dm.refillPools(ctx, due)
+}
+
+// checkPoolBalances compares the balances of the active pools with the
+// minimum balance. A pool below it is refilled right away instead of at its
+// next refillInterval, and alerted once until its balance recovers.
+func (dm *DEXManager) checkPoolBalances(ctx context.Context) error {
+	threshold, err := dm.minBalance()
+	if err != nil {
+		return err
+	}
+	
+	var low []*DEXPool
+	for _, name := range dm.ActivePools() {
+		pool, ok := dm.pool(name)
+		if !ok {
+			continue
+		}
+		balance, err := dm.QueryPoolBalance(ctx, pool.Address)
+		if err != nil {
+			log.Printf("Error checking the balance of pool %s: %v", name, err)
+			continue
+		}
+		
+		dm.mu.Lock()
+		pool.Balance = balance.String()
+		wasLow := pool.LowBalance
+		pool.LowBalance = balance.IsLT(threshold)
+		snapshot := *pool
+		dm.mu.Unlock()
+		
+		if !snapshot.LowBalance {
+			continue
+		}
+		if !wasLow {
+			dm.alertLowBalance(&snapshot, balance, threshold)
+		}
+		low = append(low, pool)
+	}
+	
+	return dm.refillPools(ctx, low)
+}
+
+// alertLowBalance warns that a pool dropped below the minimum balance
+func (dm *DEXManager) alertLowBalance(pool *DEXPool, balance, threshold sdk.Coin) {
+	log.Printf("Pool %s holds %s, below the minimum of %s, refilling now", pool.Name, balance, threshold)
+	if dm.alerts == nil {
+		return
+	}
+	
+	message := fmt.Sprintf("Pool %s (%s) holds %s, below the minimum of %s. It is refilled now instead of at its next %s refill.",
+		pool.Name, pool.Address, balance, threshold, dm.refillInterval)
+	if err := dm.alerts.SendAlertWithType(AlertTypeWarning, "DEX Pool Low Liquidity", message); err != nil {
+		log.Printf("Failed to send low balance alert for pool %s: %v", pool.Name, err)
+	}
+}
+
+// QueryPoolBalance queries the balance a pool holds in the denom of the
+// minimum balance through the bank module
+func (dm *DEXManager) QueryPoolBalance(ctx context.Context, poolAddress string) (sdk.Coin, error) {
+	if dm.bankQuery == nil {
+		return sdk.Coin{}, errors.New("bank query client not configured")
+	}
+	threshold, err := dm.minBalance()
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	
+	resp, err := dm.bankQuery.Balance(ctx, &banktypes.QueryBalanceRequest{Address: poolAddress, Denom: threshold.Denom})
+	if err != nil {
+		return sdk.Coin{}, fmt.Errorf("failed to query the balance of %s: %w", poolAddress, err)
+	}
+	if resp.Balance == nil {
+		return sdk.NewInt64Coin(threshold.Denom, 0), nil
+	}
+	return *resp.Balance, nil
+}
+
+// minBalance returns the minimum pool balance as a coin
+func (dm *DEXManager) minBalance() (sdk.Coin, error) {
+	threshold, err := sdk.ParseCoinNormalized(dm.minBalanceThreshold)
+	if err != nil {
+		return sdk.Coin{}, fmt.Errorf("invalid min balance threshold %q: %w", dm.minBalanceThreshold, err)
+	}
+	return threshold, nil
+}
+
+// pool returns the managed pool named name
+func (dm *DEXManager) pool(name string) (*DEXPool, bool) {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	pool, ok := dm.pools[name]
+	return pool, ok
+}
+
+// refillPools refills the due pools with the DEX share the chain allocated
+// to the bot
+func (dm *DEXManager) refillPools(ctx context.Context, due []*DEXPool) error {
+	if len(due) == 0 {
+		return nil
+	}
+	
+	// Refill exactly what the chain allocated to the bot, split across due pools
+	available, err := dm.distributableAmount(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query the DEX share available for refills: %w", err)
+	}
+	dm.mu.RLock()
+	cycle := dm.pending.Cycle
+	dm.mu.RUnlock()
+	if available == 0 {
+		log.Printf("No pending DEX allocation for cycle %d, skipping refills", cycle)
+		return nil
+	}
+	
+	// Once shutdown began the remaining refills are left to the next start,
+	// the allocation stays pending on chain
+	amounts := allocateRefills(available, due)
+	for _, pool := range due {
+		refill := DEXRefillIntent{Pool: pool.Name, Address: pool.Address, Cycle: cycle, Amount: amounts[pool.Name]}
+		op, err := dm.drain.Begin(ctx, "refill", fmt.Sprintf("refill %s with %dugen", pool.Name, refill.Amount), refill)
+		if err != nil {
+			log.Printf("Shutting down, leaving the refill of %s to the next start", pool.Name)
+			break
+		}
+		err = dm.refillPool(op.Context(), pool, cycle, refill.Amount)
+		op.Done()
+		if err != nil {
+			log.Printf("Error refilling pool %s: %v", pool.Name, err)
+		}
+	}
 	
 	return nil
 }
 
-// updatePoolMetrics updates pool metrics
+// distributableAmount returns the chain's pending DEX allocation for the
+// current cycle minus what this bot already distributed from it, at most what
+// the dex_distribution account holds
+func (dm *DEXManager) distributableAmount(ctx context.Context) (int64, error) {
+	pending, err := dm.chain.QueryPendingDexAllocation(ctx)
+	if err != nil {
+		return 0, err
+	}
+	account, err := dm.chain.QueryDexDistributionBalance(ctx)
+	if err != nil {
+		return 0, err
+	}
+	
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.pending = pending
+	dm.account = account
+	
+	available := pending.Amount - dm.distributed[pending.Cycle]
+	if available > account.Balance {
+		log.Printf("DEX distribution account %s holds %dugen of %dugen pending, refilling only what it holds",
+			account.Address, account.Balance, available)
+		available = account.Balance
+	}
+	if available < 0 {
+		available = 0
+	}
+	return available, nil
+}
+
+// allocateRefills splits amount equally across pools; the last pool takes the
+// rounding remainder so the whole amount is distributed
+func allocateRefills(amount int64, pools []*DEXPool) map[string]int64 {
+	amounts := make(map[string]int64, len(pools))
+	if len(pools) == 0 {
+		return amounts
+	}
+	
+	share := amount / int64(len(pools))
+	for i, pool := range pools {
+		amounts[pool.Name] = share
+		if i == len(pools)-1 {
+			amounts[pool.Name] = amount - share*int64(len(pools)-1)
+		}
+	}
+	return amounts
+}
+
+// updatePoolMetrics updates pool metrics; dm.mu is held
 func (dm *DEXManager) updatePoolMetrics(pool *DEXPool) error {
 	// In a real implementation, this would:
 	// 1. Query the DEX API for current pool state
@@ -165,15 +448,17 @@ func (dm *DEXManager) updatePoolMetrics(pool *DEXPool) error {
 	// For now, we'll simulate the updates
 	pool.LastUpdate = time.Now()
 	
-	// Simulate balance changes
-	if pool.RefillCount > 0 {
+	// Simulate balance changes, unless checkPoolBalances reads the real ones
+	if dm.bankQuery == nil && pool.RefillCount > 0 {
 		pool.Balance = fmt.Sprintf("%dugen", 50000+(pool.RefillCount*5000))
 	}
 	
 	return nil
 }
 
-// needsRefill checks if a pool needs refilling
+// needsRefill checks if a pool is due its time-based refill. Pools below the
+// minimum balance are refilled sooner by checkPoolBalances; this is the
+// fallback when their balance cannot be read.
 func (dm *DEXManager) needsRefill(pool *DEXPool) bool {
 	// Check time-based refill (every 6 hours)
 	if time.Since(pool.LastRefill) < dm.refillInterval {
@@ -181,42 +466,61 @@ func (dm *DEXManager) needsRefill(pool *DEXPool) bool {
 	}
 	
 	// In a real implementation, this would also check:
-	// 1. Actual pool balance vs minimum threshold
-	// 2. Pool utilization metrics
-	// 3. Fee accumulation levels
+	// 1. Pool utilization metrics
+	// 2. Fee accumulation levels
 	
 	return true
 }
 
-// refillPool refills a DEX pool from fee collector
-func (dm *DEXManager) refillPool(pool *DEXPool) error {
-	log.Printf("Auto refilling DEX pool: %s", pool.Name)
-	
-	// Simulate refill process
-	if err := dm.simulateRefill(pool); err != nil {
-		return fmt.Errorf("refill simulation failed: %w", err)
+// refillPool refills a DEX pool with amount ugen of the DEX allocation of cycle
+func (dm *DEXManager) refillPool(ctx context.Context, pool *DEXPool, cycle uint64, amount int64) (err error) {
+	log.Printf("Auto refilling DEX pool: %s (%dugen)", pool.Name, amount)
+	
+	ctx, span := startSpan(ctx, "dex.refill",
+		tracePool.String(pool.Name),
+		attribute.String("dex.pool_address", pool.Address),
+		traceCycle.Int64(int64(cycle)),
+		traceAmount.Int64(amount))
+	defer func() { endSpan(span, err) }()
+	
+	dm.mu.RLock()
+	snapshot := *pool
+	dm.mu.RUnlock()
+	if err := dm.transfer(ctx, &snapshot, amount); err != nil {
+		return fmt.Errorf("refill failed: %w", err)
 	}
 	
+	dm.costs.RecordTx(CostRefill, TxResult{}, time.Now())
+	
+	dm.mu.Lock()
 	pool.LastRefill = time.Now()
 	pool.RefillCount++
+	refills := pool.RefillCount
 	dm.refillCount++
+	dm.distributed[cycle] += amount
 	
 	// Update total refill amount
-	dm.totalRefill = fmt.Sprintf("%dugen", dm.refillCount*5000)
+	dm.totalRefilled += amount
+	dm.totalRefill = fmt.Sprintf("%dugen", dm.totalRefilled)
+	dm.mu.Unlock()
 	
-	log.Printf("Pool %s refilled successfully (refill #%d)", pool.Name, pool.RefillCount)
+	log.Printf("Pool %s refilled successfully (refill #%d)", pool.Name, refills)
 	return nil
 }
 
 // simulateRefill simulates the refill process
-func (dm *DEXManager) simulateRefill(pool *DEXPool) error {
+func (dm *DEXManager) simulateRefill(ctx context.Context, pool *DEXPool, amount int64) error {
 	// Simulate checking fee collector balance
 	log.Printf("Checking fee collector balance for %s...", pool.Name)
 	time.Sleep(500 * time.Millisecond)
 	
 	// Simulate transferring funds
-	log.Printf("Transferring refill funds to %s...", pool.Address)
-	time.Sleep(1 * time.Second)
+	log.Printf("Transferring %dugen refill funds to %s...", amount, pool.Address)
+	select {
+	case <-time.After(1 * time.Second):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 	
 	// Simulate occasional failures
 	if pool.RefillCount > 0 && pool.RefillCount%15 == 0 {
@@ -226,7 +530,101 @@ func (dm *DEXManager) simulateRefill(pool *DEXPool) error {
 	return nil
 }
 
-// checkPoolHealth checks pool health metrics
+// ActivePools returns the names of the active pools, sorted
+func (dm *DEXManager) ActivePools() []string {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	
+	var names []string
+	for name, pool := range dm.pools {
+		if pool.Active {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExecuteSwap places a rebalance swap of amountGXR on an active pool. A
+// simulated swap is counted apart and has no tx hash.
+func (dm *DEXManager) ExecuteSwap(ctx context.Context, poolName string, amountGXR float64, direction SwapDirection) (SwapResult, error) {
+	dm.mu.RLock()
+	pool, exists := dm.pools[poolName]
+	var snapshot DEXPool
+	if exists {
+		snapshot = *pool
+	}
+	dm.mu.RUnlock()
+	if !exists {
+		return SwapResult{}, fmt.Errorf("pool %s not found", poolName)
+	}
+	if !snapshot.Active {
+		return SwapResult{}, fmt.Errorf("pool %s is not active", poolName)
+	}
+	if amountGXR <= 0 {
+		return SwapResult{}, fmt.Errorf("invalid swap amount %.2f GXR", amountGXR)
+	}
+	
+	result, err := dm.swap(ctx, &snapshot, amountGXR, direction)
+	if err != nil {
+		return SwapResult{}, fmt.Errorf("swap on %s failed: %w", poolName, err)
+	}
+	
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if result.Simulated {
+		dm.simulatedSwapCount++
+		log.Printf("Simulated swap on %s: %s %.2f GXR, nothing was broadcast", poolName, direction, amountGXR)
+		return result, nil
+	}
+	pool.SwapCount++
+	dm.swapCount++
+	log.Printf("Swap on %s: %s %.2f GXR (tx %s)", poolName, direction, amountGXR, result.TxHash)
+	return result, nil
+}
+
+// simulateSwap stands in for a DEX pool swap until one is wired up. It
+// broadcasts nothing, so its result is marked simulated and has no tx hash.
+func (dm *DEXManager) simulateSwap(ctx context.Context, pool *DEXPool, amountGXR float64, direction SwapDirection) (SwapResult, error) {
+	if err := ctx.Err(); err != nil {
+		return SwapResult{}, err
+	}
+	return SwapResult{Venue: pool.Name, AmountIn: amountGXR, ExecutedAt: time.Now(), Simulated: true}, nil
+}
+
+// Resume reconciles the refills the last shutdown interrupted. Whether their
+// transfer landed is unknown, so the amount is counted as distributed: the
+// allocation is never sent twice, and the returned refills are left to the
+// operator to verify.
+func (dm *DEXManager) Resume(intents []Intent) []DEXRefillIntent {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	
+	var refills []DEXRefillIntent
+	for _, intent := range intents {
+		var refill DEXRefillIntent
+		if err := intent.Decode(&refill); err != nil {
+			log.Printf("Ignoring interrupted DEX refill %q: %v", intent.Description, err)
+			continue
+		}
+		
+		dm.distributed[refill.Cycle] += refill.Amount
+		if pool, ok := dm.pools[refill.Pool]; ok {
+			pool.LastRefill = intent.StartedAt
+		}
+		log.Printf("Interrupted refill of %s with %dugen (cycle %d) counted as distributed, verify the transfer to %s",
+			refill.Pool, refill.Amount, refill.Cycle, refill.Address)
+		refills = append(refills, refill)
+	}
+	return refills
+}
+
+// Stop stops the DEX manager; the refill in flight is drained by the shutdown
+func (dm *DEXManager) Stop() {
+	log.Println("DEX Manager stopped")
+}
+
+// checkPoolHealth checks pool health metrics; dm.mu is held
 func (dm *DEXManager) checkPoolHealth(pool *DEXPool) error {
 	// Check if pool data is stale
 	if time.Since(pool.LastUpdate) > (30 * time.Minute) {
@@ -247,6 +645,9 @@ func (dm *DEXManager) AddPool(name string, address string) error {
 		return fmt.Errorf("name and address are required")
 	}
 	
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	
 	if _, exists := dm.pools[name]; exists {
 		return fmt.Errorf("pool %s already exists", name)
 	}
@@ -268,6 +669,9 @@ func (dm *DEXManager) AddPool(name string, address string) error {
 
 // RemovePool removes a pool from management
 func (dm *DEXManager) RemovePool(name string) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	
 	if _, exists := dm.pools[name]; !exists {
 		return fmt.Errorf("pool %s not found", name)
 	}
@@ -279,6 +683,9 @@ func (dm *DEXManager) RemovePool(name string) error {
 
 // ActivatePool activates a pool
 func (dm *DEXManager) ActivatePool(name string) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	
 	pool, exists := dm.pools[name]
 	if !exists {
 		return fmt.Errorf("pool %s not found", name)
@@ -291,6 +698,9 @@ func (dm *DEXManager) ActivatePool(name string) error {
 
 // DeactivatePool deactivates a pool
 func (dm *DEXManager) DeactivatePool(name string) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	
 	pool, exists := dm.pools[name]
 	if !exists {
 		return fmt.Errorf("pool %s not found", name)
@@ -303,6 +713,9 @@ func (dm *DEXManager) DeactivatePool(name string) error {
 
 // GetPoolStatus returns the status of a specific pool
 func (dm *DEXManager) GetPoolStatus(name string) (map[string]interface{}, error) {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	
 	pool, exists := dm.pools[name]
 	if !exists {
 		return nil, fmt.Errorf("pool %s not found", name)
@@ -315,6 +728,7 @@ func (dm *DEXManager) GetPoolStatus(name string) (map[string]interface{}, error)
 		"active":       pool.Active,
 		"last_refill":  pool.LastRefill,
 		"refill_count": pool.RefillCount,
+		"low_balance":  pool.LowBalance,
 		"volume_24h":   pool.Volume24h,
 		"apr":          pool.APR,
 		"last_update":  pool.LastUpdate,
@@ -323,6 +737,9 @@ func (dm *DEXManager) GetPoolStatus(name string) (map[string]interface{}, error)
 
 // GetStatus returns the current DEX manager status
 func (dm *DEXManager) GetStatus() map[string]interface{} {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	
 	poolStatus := make(map[string]interface{})
 	activePools := 0
 	
@@ -337,6 +754,8 @@ func (dm *DEXManager) GetStatus() map[string]interface{} {
 			"balance":      pool.Balance,
 			"last_refill":  pool.LastRefill,
 			"refill_count": pool.RefillCount,
+			"swap_count":   pool.SwapCount,
+			"low_balance":  pool.LowBalance,
 			"volume_24h":   pool.Volume24h,
 			"apr":          pool.APR,
 			"last_update":  pool.LastUpdate,
@@ -344,12 +763,19 @@ func (dm *DEXManager) GetStatus() map[string]interface{} {
 	}
 	
 	return map[string]interface{}{
-		"pools":              poolStatus,
-		"total_pools":        len(dm.pools),
-		"active_pools":       activePools,
-		"refill_count":       dm.refillCount,
-		"total_refill":       dm.totalRefill,
-		"refill_interval":    dm.refillInterval,
-		"min_balance_threshold": dm.minBalanceThreshold,
+		"pools":                       poolStatus,
+		"total_pools":                 len(dm.pools),
+		"active_pools":                activePools,
+		"refill_count":                dm.refillCount,
+		"swap_count":                  dm.swapCount,
+		"simulated_swap_count":        dm.simulatedSwapCount,
+		"total_refill":                dm.totalRefill,
+		"refill_interval":             dm.refillInterval,
+		"min_balance_threshold":       dm.minBalanceThreshold,
+		"pool_balance_check_interval": dm.balanceCheckInterval,
+		"pending_dex_cycle":           dm.pending.Cycle,
+		"pending_dex_allocation":      fmt.Sprintf("%dugen", dm.pending.Amount),
+		"distributed_in_cycle":        fmt.Sprintf("%dugen", dm.distributed[dm.pending.Cycle]),
+		"dex_distribution_balance":    fmt.Sprintf("%dugen", dm.account.Balance),
 	}
 }
\ No newline at end of file
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+)
+
+func TestDEXManagerDistributesPendingAllocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gxr/halving/v1beta1/pending_dex_allocation":
+			w.Write([]byte(`{"pending_dex_allocation":{"cycle":"1","amount":{"denom":"ugen","amount":"1000001"},` +
+				`"total_allocated":{"denom":"ugen","amount":"1500000"},"months":"3","last_updated":"1735689600"}}`))
+		case "/gxr/halving/v1beta1/dex_distribution_balance":
+			w.Write([]byte(`{"address":"gxr1dexdistribution","balance":{"denom":"ugen","amount":"1000001"},` +
+				`"pending":{"denom":"ugen","amount":"1000001"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dm := NewDEXManager(&BotConfig{ChainAPI: server.URL})
+
+	pending, err := dm.chain.QueryPendingDexAllocation(context.Background())
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if pending.Cycle != 1 || pending.Amount != 1_000_001 || pending.TotalAllocated != 1_500_000 || pending.Months != 3 {
+		t.Fatalf("unexpected pending allocation: %+v", pending)
+	}
+
+	available, err := dm.distributableAmount(context.Background())
+	if err != nil {
+		t.Fatalf("distributable amount: %v", err)
+	}
+	if available != 1_000_001 {
+		t.Fatalf("expected the full pending amount, got %d", available)
+	}
+
+	// The whole amount is split, the last pool taking the remainder
+	pools := []*DEXPool{{Name: "GXR/POLYGON"}, {Name: "GXR/TON"}}
+	amounts := allocateRefills(available, pools)
+	if amounts["GXR/POLYGON"] != 500_000 || amounts["GXR/TON"] != 500_001 {
+		t.Fatalf("unexpected split: %v", amounts)
+	}
+
+	// What this run already sent is not distributed again
+	dm.distributed[1] = 1_000_001
+	available, err = dm.distributableAmount(context.Background())
+	if err != nil {
+		t.Fatalf("distributable amount: %v", err)
+	}
+	if available != 0 {
+		t.Fatalf("expected nothing left to distribute, got %d", available)
+	}
+}
+
+func TestDEXManagerRefillsAtMostTheAccountBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gxr/halving/v1beta1/pending_dex_allocation":
+			w.Write([]byte(`{"pending_dex_allocation":{"cycle":"2","amount":{"denom":"ugen","amount":"30000"}}}`))
+		case "/gxr/halving/v1beta1/dex_distribution_balance":
+			w.Write([]byte(`{"address":"gxr1dexdistribution","balance":{"denom":"ugen","amount":"10000"},` +
+				`"pending":{"denom":"ugen","amount":"30000"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dm := NewDEXManager(&BotConfig{ChainAPI: server.URL})
+
+	available, err := dm.distributableAmount(context.Background())
+	if err != nil {
+		t.Fatalf("distributable amount: %v", err)
+	}
+	if available != 10_000 {
+		t.Fatalf("expected the account balance, got %d", available)
+	}
+	if dm.account.Address != "gxr1dexdistribution" || dm.account.Pending != 30_000 {
+		t.Fatalf("unexpected account: %+v", dm.account)
+	}
+}
+
+// fakeBankQuery answers Balance from balances, keyed by address
+type fakeBankQuery struct {
+	banktypes.QueryClient
+	balances map[string]int64
+}
+
+func (f *fakeBankQuery) Balance(_ context.Context, req *banktypes.QueryBalanceRequest, _ ...grpc.CallOption) (*banktypes.QueryBalanceResponse, error) {
+	balance := sdk.NewInt64Coin(req.Denom, f.balances[req.Address])
+	return &banktypes.QueryBalanceResponse{Balance: &balance}, nil
+}
+
+func TestDEXManagerRefillsLowPoolsRightAway(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gxr/halving/v1beta1/pending_dex_allocation":
+			w.Write([]byte(`{"pending_dex_allocation":{"cycle":"3","amount":{"denom":"ugen","amount":"20000"}}}`))
+		case "/gxr/halving/v1beta1/dex_distribution_balance":
+			w.Write([]byte(`{"address":"gxr1dexdistribution","balance":{"denom":"ugen","amount":"20000"},` +
+				`"pending":{"denom":"ugen","amount":"20000"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ta := newTelegramAlert(&BotConfig{})
+	ta.running = true
+	bank := &fakeBankQuery{balances: map[string]int64{"gxr1dexpool1polygon": 5000 * UgenPerGXR, "gxr1dexpool1ton": 400 * UgenPerGXR}}
+
+	dm := NewDEXManager(&BotConfig{ChainAPI: server.URL})
+	dm.bankQuery = bank
+	dm.alerts = ta
+	if dm.balanceCheckInterval != DefaultPoolBalanceCheckInterval {
+		t.Fatalf("balance check interval = %s, want %s", dm.balanceCheckInterval, DefaultPoolBalanceCheckInterval)
+	}
+	// Both pools were refilled just now, so neither is due its time-based refill
+	dm.pools["GXR/POLYGON"] = &DEXPool{Name: "GXR/POLYGON", Address: "gxr1dexpool1polygon", Active: true, LastRefill: time.Now()}
+	dm.pools["GXR/TON"] = &DEXPool{Name: "GXR/TON", Address: "gxr1dexpool1ton", Active: true, LastRefill: time.Now()}
+	transfers := make(map[string]int64)
+	dm.transfer = func(ctx context.Context, pool *DEXPool, amount int64) error {
+		transfers[pool.Name] += amount
+		return nil
+	}
+
+	if err := dm.checkPoolBalances(context.Background()); err != nil {
+		t.Fatalf("check pool balances: %v", err)
+	}
+	if len(transfers) != 1 || transfers["GXR/TON"] != 20_000 {
+		t.Fatalf("expected only GXR/TON refilled with the pending allocation, got %v", transfers)
+	}
+	if pool := dm.pools["GXR/POLYGON"]; pool.Balance != "500000000000ugen" || pool.LowBalance {
+		t.Fatalf("unexpected healthy pool: %+v", pool)
+	}
+	if pool := dm.pools["GXR/TON"]; pool.Balance != "40000000000ugen" || !pool.LowBalance || pool.RefillCount != 1 {
+		t.Fatalf("unexpected low pool: %+v", pool)
+	}
+	if len(ta.alertQueue) != 1 {
+		t.Fatalf("expected one alert, got %d", len(ta.alertQueue))
+	}
+	alert := <-ta.alertQueue
+	if alert.Type != AlertTypeWarning || !strings.Contains(alert.Message, "GXR/TON") || !strings.Contains(alert.Message, "100000000000ugen") {
+		t.Fatalf("unexpected alert: %+v", alert)
+	}
+
+	// A pool still below the minimum is not alerted again
+	if err := dm.checkPoolBalances(context.Background()); err != nil {
+		t.Fatalf("check pool balances: %v", err)
+	}
+	if len(ta.alertQueue) != 0 {
+		t.Fatalf("expected no alert while the pool stays low, got %d", len(ta.alertQueue))
+	}
+
+	// Once it recovered, the next drop is alerted again
+	bank.balances["gxr1dexpool1ton"] = 2000 * UgenPerGXR
+	if err := dm.checkPoolBalances(context.Background()); err != nil {
+		t.Fatalf("check pool balances: %v", err)
+	}
+	if dm.pools["GXR/TON"].LowBalance || len(ta.alertQueue) != 0 {
+		t.Fatalf("expected the recovered pool cleared without an alert")
+	}
+	bank.balances["gxr1dexpool1ton"] = 100 * UgenPerGXR
+	if err := dm.checkPoolBalances(context.Background()); err != nil {
+		t.Fatalf("check pool balances: %v", err)
+	}
+	if len(ta.alertQueue) != 1 {
+		t.Fatalf("expected the new drop alerted, got %d alerts", len(ta.alertQueue))
+	}
+}
+
+func TestDEXManagerMinBalanceIsThousandGXR(t *testing.T) {
+	bank := &fakeBankQuery{balances: map[string]int64{"gxr1dexpool1ton": 1000 * UgenPerGXR}}
+	// The chain is unreachable, so the low pool is only marked, not refilled
+	dm := NewDEXManager(&BotConfig{ChainAPI: "http://127.0.0.1:1"})
+	dm.bankQuery = bank
+	dm.pools["GXR/TON"] = &DEXPool{Name: "GXR/TON", Address: "gxr1dexpool1ton", Active: true, LastRefill: time.Now()}
+
+	threshold, err := dm.minBalance()
+	if err != nil || threshold.String() != "100000000000ugen" {
+		t.Fatalf("min balance = %s: %v", threshold, err)
+	}
+
+	// Exactly 1000 GXR is enough, one ugen less is low
+	dm.checkPoolBalances(context.Background())
+	if dm.pools["GXR/TON"].LowBalance {
+		t.Fatal("pool holding 1000 GXR marked low")
+	}
+	bank.balances["gxr1dexpool1ton"]--
+	dm.checkPoolBalances(context.Background())
+	if !dm.pools["GXR/TON"].LowBalance {
+		t.Fatal("pool holding less than 1000 GXR not marked low")
+	}
+}
+
+func TestDEXManagerQueryPoolBalanceWithoutBankQuery(t *testing.T) {
+	dm := NewDEXManager(&BotConfig{PoolBalanceCheckInterval: 5 * time.Minute})
+	if dm.balanceCheckInterval != 5*time.Minute {
+		t.Fatalf("balance check interval = %s, want 5m", dm.balanceCheckInterval)
+	}
+	if _, err := dm.QueryPoolBalance(context.Background(), "gxr1dexpool1ton"); err == nil {
+		t.Fatal("expected an error without a bank query client")
+	}
+}
+
+func TestDEXManagerConcurrentAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gxr/halving/v1beta1/pending_dex_allocation":
+			w.Write([]byte(`{"pending_dex_allocation":{"cycle":"1","amount":{"denom":"ugen","amount":"1000000"}}}`))
+		case "/gxr/halving/v1beta1/dex_distribution_balance":
+			w.Write([]byte(`{"address":"gxr1dexdistribution","balance":{"denom":"ugen","amount":"1000000"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dm := NewDEXManager(&BotConfig{ChainAPI: server.URL})
+	dm.bankQuery = &fakeBankQuery{balances: map[string]int64{}}
+	dm.transfer = func(context.Context, *DEXPool, int64) error { return nil }
+	for _, name := range []string{"GXR/TON", "GXR/POLYGON"} {
+		dm.pools[name] = &DEXPool{Name: name, Address: "gxr1" + name, Active: true, APR: 10, LastUpdate: time.Now()}
+	}
+
+	// The pool loop, the balance check, the rebalancer's swaps and the
+	// status server run at the same time; run with -race
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for _, work := range []func(i int){
+		func(int) { dm.managePools(ctx) },
+		func(int) { dm.checkPoolBalances(ctx) },
+		func(int) { dm.ExecuteSwap(ctx, "GXR/TON", 10, SwapSellGXR) },
+		func(int) { dm.GetStatus(); dm.GetPoolStatus("GXR/POLYGON") },
+		func(i int) {
+			if i%2 == 0 {
+				dm.DeactivatePool("GXR/POLYGON")
+			} else {
+				dm.ActivatePool("GXR/POLYGON")
+			}
+		},
+	} {
+		wg.Add(1)
+		go func(work func(int)) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				work(i)
+			}
+		}(work)
+	}
+	wg.Wait()
+
+	status := dm.GetStatus()
+	if status["simulated_swap_count"] != int64(20) || status["swap_count"] != int64(0) {
+		t.Fatalf("swaps %v, simulated %v", status["swap_count"], status["simulated_swap_count"])
+	}
+}
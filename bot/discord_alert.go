@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DiscordContentLimit is the maximum length of a webhook message's content
+	DiscordContentLimit = 2000
+	// DiscordEmbedDescriptionLimit is the maximum length of an embed description
+	DiscordEmbedDescriptionLimit = 4096
+	// DiscordEmbedTitleLimit is the maximum length of an embed title
+	DiscordEmbedTitleLimit = 256
+	// DiscordRequestTimeout bounds a single webhook request
+	DiscordRequestTimeout = 15 * time.Second
+)
+
+// discordColors are the embed colors of the alert types
+var discordColors = map[AlertType]int{
+	AlertTypeInfo:     0x3498db,
+	AlertTypeWarning:  0xf1c40f,
+	AlertTypeError:    0xe74c3c,
+	AlertTypeCritical: 0x992d22,
+	AlertTypeSuccess:  0x2ecc71,
+}
+
+// DiscordMessage is the body of a Discord webhook execution
+type DiscordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []DiscordEmbed `json:"embeds,omitempty"`
+}
+
+// DiscordEmbed is a rich embed of a Discord message
+type DiscordEmbed struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Color       int    `json:"color,omitempty"`
+	Timestamp   string `json:"timestamp,omitempty"`
+}
+
+// DiscordAlert sends alerts to a Discord channel through a webhook. Each
+// alert is a single request; retries and disabling a failing webhook are
+// left to the MultiNotifier it is registered with.
+type DiscordAlert struct {
+	webhookURL string
+	client     *http.Client
+	limiter    *OutboundLimiter
+	now        func() time.Time
+
+	mu      sync.RWMutex
+	running bool
+}
+
+// ValidateDiscordWebhookURL checks the discord_webhook_url setting
+func ValidateDiscordWebhookURL(webhookURL string) error {
+	if webhookURL == "" {
+		return fmt.Errorf("discord_webhook_url is required when discord is enabled")
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid discord_webhook_url, expected https://discord.com/api/webhooks/<id>/<token>")
+	}
+	return nil
+}
+
+// NewDiscordAlert creates a Discord alert channel for the configured webhook
+func NewDiscordAlert(config *BotConfig) (*DiscordAlert, error) {
+	if err := ValidateDiscordWebhookURL(config.DiscordWebhookURL); err != nil {
+		return nil, err
+	}
+	return &DiscordAlert{
+		webhookURL: config.DiscordWebhookURL,
+		client:     &http.Client{Timeout: DiscordRequestTimeout},
+		limiter:    sharedOutbound.Load(),
+		now:        time.Now,
+		running:    true,
+	}, nil
+}
+
+// SendAlert sends a plain message
+func (da *DiscordAlert) SendAlert(message string) error {
+	return da.post(DiscordMessage{Content: trimMiddle(message, DiscordContentLimit)})
+}
+
+// SendAlertWithType sends an alert as an embed colored by its type
+func (da *DiscordAlert) SendAlertWithType(alertType AlertType, title, message string) error {
+	heading := fmt.Sprintf("%s %s", alertType.Emoji(), alertType.String())
+	if title != "" {
+		heading += ": " + title
+	}
+	return da.post(DiscordMessage{Embeds: []DiscordEmbed{{
+		Title:       cutAtRune(heading, DiscordEmbedTitleLimit),
+		Description: trimMiddle(message, DiscordEmbedDescriptionLimit),
+		Color:       discordColors[alertType],
+		Timestamp:   da.now().UTC().Format(time.RFC3339),
+	}}})
+}
+
+// post executes the webhook with msg
+func (da *DiscordAlert) post(msg DiscordMessage) error {
+	if !da.IsRunning() {
+		return fmt.Errorf("discord alert channel is not running")
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode discord message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DiscordRequestTimeout)
+	defer cancel()
+	if err := da.limiter.Wait(ctx, da.webhookURL); err != nil {
+		return fmt.Errorf("discord webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, da.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create discord request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := da.client.Do(req)
+	if err != nil {
+		// The webhook token is part of the URL, keep it out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("discord webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	da.limiter.Observe(da.webhookURL, resp, respBody)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("discord webhook failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// Stop stops sending alerts
+func (da *DiscordAlert) Stop() {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	da.running = false
+}
+
+// IsRunning returns whether the channel sends alerts
+func (da *DiscordAlert) IsRunning() bool {
+	da.mu.RLock()
+	defer da.mu.RUnlock()
+	return da.running
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// ErrorJournalFile is the append-only error journal in the data directory
+	ErrorJournalFile = "error_journal.jsonl"
+	// MaxErrorJournalEntries is the number of recent entries kept in memory
+	MaxErrorJournalEntries = 500
+)
+
+// JournalEntry is one notable error or incident recorded by the bot
+type JournalEntry struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Severity  string                 `json:"severity"`
+	Source    string                 `json:"source"`
+	Summary   string                 `json:"summary"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// ErrorJournal records incidents as JSON lines so they survive restarts and
+// can be reviewed later, e.g. for the monthly report
+type ErrorJournal struct {
+	path string
+
+	mu      sync.Mutex
+	entries []JournalEntry
+}
+
+// NewErrorJournal opens the journal in dataDir and loads its most recent
+// entries. An empty dataDir keeps the journal in memory.
+func NewErrorJournal(dataDir string) *ErrorJournal {
+	j := &ErrorJournal{}
+	if dataDir == "" {
+		return j
+	}
+
+	j.path = filepath.Join(dataDir, ErrorJournalFile)
+	file, err := os.Open(j.path)
+	if err != nil {
+		return j
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Printf("Skipping unreadable error journal line in %s: %v", j.path, err)
+			continue
+		}
+		j.append(entry)
+	}
+	return j
+}
+
+// Record adds an entry to the journal and appends it to the journal file
+func (j *ErrorJournal) Record(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.append(entry)
+	if j.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// append keeps at most MaxErrorJournalEntries in memory
+func (j *ErrorJournal) append(entry JournalEntry) {
+	j.entries = append(j.entries, entry)
+	if len(j.entries) > MaxErrorJournalEntries {
+		j.entries = j.entries[len(j.entries)-MaxErrorJournalEntries:]
+	}
+}
+
+// Entries returns a copy of the recent entries, oldest first
+func (j *ErrorJournal) Entries() []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := make([]JournalEntry, len(j.entries))
+	copy(entries, j.entries)
+	return entries
+}
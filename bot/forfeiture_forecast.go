@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+const (
+	// ForfeitureForecastInterval is how often our validator's chain uptime is checked
+	ForfeitureForecastInterval = 1 * time.Hour
+	// DailySummaryInterval is how often the daily validator summary is sent
+	DailySummaryInterval = 24 * time.Hour
+)
+
+// ForfeitureAlertLevels are the fractions of the inactivity threshold at
+// which a forecast alert is sent, once per level and month
+var ForfeitureAlertLevels = []float64{0.5, 0.8}
+
+// ForfeitureForecast projects our validator's inactive days for the current
+// uptime month. Day counts are effective days: declared downtime is weighted.
+type ForfeitureForecast struct {
+	Month         uint64
+	InactiveDays  float64 // effective inactive days so far
+	ProjectedDays float64 // at the current rate plus the declared windows still ahead
+	UpcomingDays  float64 // declared downtime days that have not happened yet
+	MarginDays    float64 // inactive days left before the threshold is crossed
+	Threshold     float64
+	UpdatedAt     time.Time
+}
+
+// WillForfeit reports whether the projection crosses the threshold
+func (f ForfeitureForecast) WillForfeit() bool {
+	return f.ProjectedDays > f.Threshold
+}
+
+// IsZero reports whether nothing counts against the threshold this month
+func (f ForfeitureForecast) IsZero() bool {
+	return f.InactiveDays == 0 && f.ProjectedDays == 0
+}
+
+// uptimeMonth returns the chain's uptime month index of t (30-day months since epoch)
+func uptimeMonth(t time.Time) uint64 {
+	return uint64(t.Unix() / int64(MonthlyResetInterval.Seconds()))
+}
+
+// computeForfeitureForecast projects the month's inactive days from the chain
+// uptime record. Undeclared inactivity is extrapolated at its rate so far;
+// declared windows still ahead are added at the declared downtime weight
+// instead, since planned maintenance is not a trend. A record from a previous
+// month counts as zero: the chain resets it on the next uptime check.
+func computeForfeitureForecast(uptime ValidatorUptimeInfo, weight float64, now time.Time) ForfeitureForecast {
+	month := uptimeMonth(now)
+	monthStart := time.Unix(int64(month)*int64(MonthlyResetInterval.Seconds()), 0)
+	elapsedDays := now.Sub(monthStart).Hours() / 24
+	remainingDays := MonthlyResetInterval.Hours()/24 - elapsedDays
+
+	forecast := ForfeitureForecast{
+		Month:     month,
+		Threshold: ValidatorInactivityThreshold,
+		UpdatedAt: now,
+	}
+
+	if uptime.Month == month {
+		forecast.InactiveDays = uptime.EffectiveInactiveDays
+		// At least one day elapsed, so a single early miss is not extrapolated 30-fold
+		rate := float64(uptime.InactiveDays) / math.Max(elapsedDays, 1)
+		forecast.ProjectedDays = forecast.InactiveDays + rate*remainingDays
+	}
+
+	for _, window := range uptime.Declarations {
+		if uptimeMonth(window.Start) != month {
+			continue
+		}
+		start := window.Start
+		if start.Before(now) {
+			start = now
+		}
+		if window.End.After(start) {
+			forecast.UpcomingDays += window.End.Sub(start).Hours() / 24
+		}
+	}
+	forecast.ProjectedDays += weight * forecast.UpcomingDays
+
+	forecast.MarginDays = math.Max(0, forecast.Threshold-forecast.InactiveDays)
+	return forecast
+}
+
+// forfeitureForecastRoutine periodically refreshes the forecast and sends the daily summary
+func (vm *ValidatorMonitor) forfeitureForecastRoutine(ctx context.Context) {
+	ticker := NewJitteredTicker(ForfeitureForecastInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := vm.refreshForfeitureForecast(ctx); err != nil {
+				log.Printf("Failed to refresh forfeiture forecast: %v", err)
+			}
+			if now.Sub(vm.lastDailySummary) >= DailySummaryInterval {
+				vm.sendDailySummary(now)
+			}
+		}
+	}
+}
+
+// refreshForfeitureForecast queries our validator's chain uptime and evaluates
+// the forecast at the chain's latest block time, which decides the uptime month
+func (vm *ValidatorMonitor) refreshForfeitureForecast(ctx context.Context) error {
+	if vm.chain == nil || vm.config.ValidatorAddress == "" {
+		return nil
+	}
+
+	uptime, err := vm.chain.QueryValidatorUptime(ctx, vm.config.ValidatorAddress)
+	if err != nil {
+		return err
+	}
+	weight, err := vm.chain.QueryDeclaredDowntimeWeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	blockTime, err := vm.chain.QueryLatestBlockTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	vm.evaluateForfeitureForecast(computeForfeitureForecast(uptime, weight, blockTime))
+	return nil
+}
+
+// evaluateForfeitureForecast stores the forecast and sends an alert when the
+// inactive days reach a new alert level. It returns the level alerted, or 0.
+func (vm *ValidatorMonitor) evaluateForfeitureForecast(forecast ForfeitureForecast) float64 {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+
+	if forecast.Month != vm.forecast.Month {
+		vm.forecastAlertLevel = 0
+	}
+	vm.forecast = forecast
+
+	reached := 0.0
+	for _, level := range ForfeitureAlertLevels {
+		if level > vm.forecastAlertLevel && forecast.InactiveDays >= level*forecast.Threshold {
+			reached = level
+		}
+	}
+	if reached == 0 {
+		return 0
+	}
+	vm.forecastAlertLevel = reached
+
+	message := fmt.Sprintf("📉 Reward Forfeiture Forecast\n\nValidator: %s\nInactive days: %.1f/%d (%.0f%% of threshold)\nDays of margin remaining: %.1f\nProjected this month: %.1f days\nMonth: %d",
+		vm.config.ValidatorAddress,
+		forecast.InactiveDays, ValidatorInactivityThreshold, reached*100,
+		forecast.MarginDays,
+		forecast.ProjectedDays,
+		forecast.Month)
+	if forecast.UpcomingDays > 0 {
+		message += fmt.Sprintf("\nDeclared downtime ahead: %.1f days", forecast.UpcomingDays)
+	}
+	if forecast.WillForfeit() {
+		message += "\n⚠️ At the current rate the monthly reward will be forfeited"
+	}
+
+	log.Printf("Forfeiture forecast reached %.0f%% of the threshold - %.1f inactive days, %.1f days of margin",
+		reached*100, forecast.InactiveDays, forecast.MarginDays)
+
+	// Not rate limited like sendAlert: each level is reported at most once a month
+	if vm.telegramAlert != nil {
+		if err := vm.telegramAlert.SendValidatorAlertWithType(AlertTypeWarning, vm.config.ValidatorAddress, "Reward Forfeiture Forecast", message); err != nil {
+			log.Printf("Failed to send forfeiture forecast alert: %v", err)
+		}
+	}
+	return reached
+}
+
+// dailySummary renders the daily summary of our validator at now
+func (vm *ValidatorMonitor) dailySummary(now time.Time) string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	lines := []string{"📅 Daily Validator Summary", ""}
+	lines = append(lines, fmt.Sprintf("Validator: %s", vm.config.ValidatorAddress))
+	if status, ok := vm.validators[vm.config.ValidatorAddress]; ok {
+		lines = append(lines,
+			fmt.Sprintf("Bonded: %v", status.Status == stakingtypes.Bonded),
+			fmt.Sprintf("Jailed: %v", status.Jailed),
+			fmt.Sprintf("Missed blocks: %d", status.MissedBlocks),
+			fmt.Sprintf("Bot running: %v", status.BotRunning),
+		)
+	} else {
+		lines = append(lines, "Status: not in the bonded set")
+	}
+	lines = append(lines, fmt.Sprintf("Active validators: %d/%d", vm.activeValidators, vm.totalValidators))
+
+	if forecast := vm.forecast; !forecast.IsZero() {
+		lines = append(lines,
+			"",
+			fmt.Sprintf("Inactive days: %.1f/%d, %.1f days of margin", forecast.InactiveDays, ValidatorInactivityThreshold, forecast.MarginDays),
+			fmt.Sprintf("Projected this month: %.1f days", forecast.ProjectedDays),
+		)
+	}
+
+	if vm.chainPhase != nil {
+		if phaseLines := vm.chainPhase.SummaryLines(now); len(phaseLines) > 0 {
+			lines = append(append(lines, ""), phaseLines...)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// sendDailySummary sends the daily summary of our validator
+func (vm *ValidatorMonitor) sendDailySummary(now time.Time) {
+	summary := vm.dailySummary(now)
+
+	vm.mu.Lock()
+	vm.lastDailySummary = now
+	vm.mu.Unlock()
+
+	if vm.telegramAlert == nil {
+		return
+	}
+	if err := vm.telegramAlert.SendAlert(summary); err != nil {
+		log.Printf("Failed to send daily summary: %v", err)
+	}
+}
+
+// forecastStatus returns the forfeiture forecast for GetStatus
+func (vm *ValidatorMonitor) forecastStatus() map[string]interface{} {
+	if vm.forecast.UpdatedAt.IsZero() {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"month":          vm.forecast.Month,
+		"inactive_days":  vm.forecast.InactiveDays,
+		"projected_days": vm.forecast.ProjectedDays,
+		"upcoming_days":  vm.forecast.UpcomingDays,
+		"margin_days":    vm.forecast.MarginDays,
+		"will_forfeit":   vm.forecast.WillForfeit(),
+		"alert_level":    vm.forecastAlertLevel,
+		"updated_at":     vm.forecast.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+)
+
+func newForecastTestMonitor() *ValidatorMonitor {
+	vm := NewValidatorMonitor(&BotConfig{ValidatorAddress: "gxrvaloper1ours"}, client.Context{}, nil)
+	vm.telegramAlert = nil
+	return vm
+}
+
+// monthStartAt returns the start of the uptime month containing t
+func monthStartAt(t time.Time) time.Time {
+	return time.Unix(int64(uptimeMonth(t))*int64(MonthlyResetInterval.Seconds()), 0)
+}
+
+// simulateMonth accumulates inactive days at rate per elapsed day and returns
+// the month day on which each alert level fired
+func simulateMonth(vm *ValidatorMonitor, start time.Time, rate float64) map[float64]int {
+	fired := make(map[float64]int)
+	month := uptimeMonth(start)
+	for day := 1; day < 30; day++ {
+		now := start.Add(time.Duration(day) * 24 * time.Hour)
+		inactive := uint64(math.Floor(rate * float64(day)))
+		uptime := ValidatorUptimeInfo{Month: month, InactiveDays: inactive, EffectiveInactiveDays: float64(inactive)}
+		if level := vm.evaluateForfeitureForecast(computeForfeitureForecast(uptime, 0.5, now)); level > 0 {
+			fired[level] = day
+		}
+	}
+	return fired
+}
+
+func TestForfeitureForecastAlertTiming(t *testing.T) {
+	start := monthStartAt(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name     string
+		rate     float64
+		expFired map[float64]int
+	}{
+		{"healthy", 0, map[float64]int{}},
+		{"slow", 0.25, map[float64]int{0.5: 20}},
+		{"moderate", 0.5, map[float64]int{0.5: 10, 0.8: 16}},
+		{"fully offline", 1, map[float64]int{0.5: 5, 0.8: 8}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fired := simulateMonth(newForecastTestMonitor(), start, tc.rate)
+			if len(fired) != len(tc.expFired) {
+				t.Fatalf("fired %v, want %v", fired, tc.expFired)
+			}
+			for level, day := range tc.expFired {
+				if fired[level] != day {
+					t.Fatalf("%.0f%% alert fired on day %d, want day %d", level*100, fired[level], day)
+				}
+			}
+		})
+	}
+}
+
+func TestForfeitureForecastSkipsToHighestLevel(t *testing.T) {
+	vm := newForecastTestMonitor()
+	now := monthStartAt(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)).Add(12 * 24 * time.Hour)
+	uptime := ValidatorUptimeInfo{Month: uptimeMonth(now), InactiveDays: 9, EffectiveInactiveDays: 9}
+
+	// A jump straight past 80% sends one alert, not one per level
+	if level := vm.evaluateForfeitureForecast(computeForfeitureForecast(uptime, 0.5, now)); level != 0.8 {
+		t.Fatalf("alerted level %.1f, want 0.8", level)
+	}
+	if level := vm.evaluateForfeitureForecast(computeForfeitureForecast(uptime, 0.5, now.Add(time.Hour))); level != 0 {
+		t.Fatalf("level %.1f alerted twice", level)
+	}
+	if vm.forecast.MarginDays != 1 || !vm.forecast.WillForfeit() {
+		t.Fatalf("margin = %.1f, will forfeit = %v, want 1 and true", vm.forecast.MarginDays, vm.forecast.WillForfeit())
+	}
+}
+
+func TestForfeitureForecastResetsAtMonthBoundary(t *testing.T) {
+	vm := newForecastTestMonitor()
+	first := monthStartAt(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	if fired := simulateMonth(vm, first, 1); len(fired) != 2 {
+		t.Fatalf("first month fired %v", fired)
+	}
+
+	// Until the chain's next uptime check the record still holds last month
+	next := first.Add(MonthlyResetInterval)
+	stale := ValidatorUptimeInfo{Month: uptimeMonth(first), InactiveDays: 29, EffectiveInactiveDays: 29}
+	if level := vm.evaluateForfeitureForecast(computeForfeitureForecast(stale, 0.5, next.Add(time.Hour))); level != 0 {
+		t.Fatalf("stale record alerted level %.1f", level)
+	}
+	if !vm.forecast.IsZero() {
+		t.Fatalf("forecast carried over: %+v", vm.forecast)
+	}
+
+	// The levels alert again in the new month
+	if fired := simulateMonth(vm, next, 1); fired[0.5] != 5 || fired[0.8] != 8 {
+		t.Fatalf("new month fired %v", fired)
+	}
+}
+
+func TestForfeitureForecastDeclaredDowntime(t *testing.T) {
+	start := monthStartAt(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	now := start.Add(10 * 24 * time.Hour)
+	month := uptimeMonth(now)
+
+	// 4 undeclared and 2 declared days at half weight, 3 more declared days ahead
+	uptime := ValidatorUptimeInfo{
+		Month:                 month,
+		InactiveDays:          4,
+		DeclaredInactiveDays:  2,
+		EffectiveInactiveDays: 5,
+		Declarations: []DowntimeWindow{
+			{Start: start.Add(2 * 24 * time.Hour), End: start.Add(4 * 24 * time.Hour), Days: 2},
+			{Start: now.Add(24 * time.Hour), End: now.Add(4 * 24 * time.Hour), Days: 3},
+		},
+	}
+	forecast := computeForfeitureForecast(uptime, 0.5, now)
+
+	if forecast.UpcomingDays != 3 {
+		t.Fatalf("upcoming declared days = %.2f, want 3", forecast.UpcomingDays)
+	}
+	// 5 so far + 0.4/day undeclared for 20 days + 3 declared days at 0.5
+	if want := 5 + 0.4*20 + 1.5; math.Abs(forecast.ProjectedDays-want) > 1e-9 {
+		t.Fatalf("projected = %.2f, want %.2f", forecast.ProjectedDays, want)
+	}
+	if forecast.MarginDays != 5 {
+		t.Fatalf("margin = %.2f, want 5", forecast.MarginDays)
+	}
+
+	vm := newForecastTestMonitor()
+	if level := vm.evaluateForfeitureForecast(forecast); level != 0.5 {
+		t.Fatalf("weighted days alerted level %.1f, want 0.5", level)
+	}
+}
+
+func TestDailySummaryIncludesNonzeroForecast(t *testing.T) {
+	vm := newForecastTestMonitor()
+	now := monthStartAt(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)).Add(10 * 24 * time.Hour)
+
+	vm.evaluateForfeitureForecast(computeForfeitureForecast(ValidatorUptimeInfo{Month: uptimeMonth(now)}, 0.5, now))
+	if summary := vm.dailySummary(now); strings.Contains(summary, "Projected") {
+		t.Fatalf("zero forecast in summary:\n%s", summary)
+	}
+
+	vm.evaluateForfeitureForecast(computeForfeitureForecast(ValidatorUptimeInfo{Month: uptimeMonth(now), InactiveDays: 2, EffectiveInactiveDays: 2}, 0.5, now))
+	summary := vm.dailySummary(now)
+	if !strings.Contains(summary, "Inactive days: 2.0/10, 8.0 days of margin") || !strings.Contains(summary, "Projected this month: 6.0 days") {
+		t.Fatalf("forecast missing from summary:\n%s", summary)
+	}
+}
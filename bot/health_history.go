@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// HealthHistorySize is the number of health state changes kept per component
+	HealthHistorySize = 50
+	// DefaultHealthFlapThreshold is the number of state changes within the
+	// flap window above which a component is considered flapping
+	DefaultHealthFlapThreshold = 4
+	// DefaultHealthFlapWindow is the window in which state changes are counted
+	DefaultHealthFlapWindow = 10 * time.Minute
+)
+
+// HealthTransition records a component entering a health state
+type HealthTransition struct {
+	Timestamp time.Time
+	Healthy   bool
+}
+
+// componentHealth is the health history of one component
+type componentHealth struct {
+	history   []HealthTransition // state changes, oldest first, bounded by HealthHistorySize
+	trimmed   bool               // history no longer starts with the first observation
+	flapping  bool
+	flapCount int64 // flapping episodes detected
+	lastFlap  time.Time
+}
+
+// HealthTracker keeps a bounded health history per component and detects
+// components that change health state too often. It is not safe for
+// concurrent use; BotService guards it with its own lock.
+type HealthTracker struct {
+	threshold  int
+	window     time.Duration
+	components map[string]*componentHealth
+}
+
+// NewHealthTracker creates a tracker flagging components with more than
+// threshold state changes within window; zero values use the defaults
+func NewHealthTracker(threshold int, window time.Duration) *HealthTracker {
+	if threshold <= 0 {
+		threshold = DefaultHealthFlapThreshold
+	}
+	if window <= 0 {
+		window = DefaultHealthFlapWindow
+	}
+
+	return &HealthTracker{
+		threshold:  threshold,
+		window:     window,
+		components: make(map[string]*componentHealth),
+	}
+}
+
+// Record records a health check result and reports whether the component
+// just started flapping. A flapping episode ends once the changes within the
+// window drop back to the threshold.
+func (h *HealthTracker) Record(component string, healthy bool, now time.Time) bool {
+	state, ok := h.components[component]
+	if !ok {
+		state = &componentHealth{}
+		h.components[component] = state
+	}
+
+	if n := len(state.history); n == 0 || state.history[n-1].Healthy != healthy {
+		state.history = append(state.history, HealthTransition{Timestamp: now, Healthy: healthy})
+		if len(state.history) > HealthHistorySize {
+			state.history = state.history[len(state.history)-HealthHistorySize:]
+			state.trimmed = true
+		}
+	}
+
+	flapping := h.changesInWindow(state, now) > h.threshold
+	started := flapping && !state.flapping
+	if started {
+		state.flapCount++
+		state.lastFlap = now
+	}
+	state.flapping = flapping
+	return started
+}
+
+// changesInWindow counts the state changes within the flap window. The first
+// observation of a component is not a change.
+func (h *HealthTracker) changesInWindow(state *componentHealth, now time.Time) int {
+	first := 1
+	if state.trimmed {
+		first = 0
+	}
+
+	changes := 0
+	for i := len(state.history) - 1; i >= first; i-- {
+		if now.Sub(state.history[i].Timestamp) > h.window {
+			break
+		}
+		changes++
+	}
+	return changes
+}
+
+// History returns a copy of a component's recorded state changes
+func (h *HealthTracker) History(component string) []HealthTransition {
+	state, ok := h.components[component]
+	if !ok {
+		return nil
+	}
+	return append([]HealthTransition(nil), state.history...)
+}
+
+// FlapStatus returns the flap counters of all components for GetStatus
+func (h *HealthTracker) FlapStatus(now time.Time) map[string]interface{} {
+	status := make(map[string]interface{}, len(h.components))
+	for component, state := range h.components {
+		entry := map[string]interface{}{
+			"flapping":          state.flapping,
+			"flap_count":        state.flapCount,
+			"changes_in_window": h.changesInWindow(state, now),
+			"history_size":      len(state.history),
+		}
+		if !state.lastFlap.IsZero() {
+			entry["last_flap"] = state.lastFlap.Format(time.RFC3339)
+		}
+		status[component] = entry
+	}
+	return status
+}
+
+// flapMessage describes a flapping component for alerts
+func (h *HealthTracker) flapMessage(component string, now time.Time) string {
+	state := h.components[component]
+	return fmt.Sprintf("Component %s changed health state %d times in the last %s (threshold %d), currently %s. This indicates instability worth investigating.",
+		component, h.changesInWindow(state, now), h.window, h.threshold, healthLabel(state.history[len(state.history)-1].Healthy))
+}
+
+func healthLabel(healthy bool) string {
+	if healthy {
+		return "healthy"
+	}
+	return "unhealthy"
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthTrackerDetectsFlapping(t *testing.T) {
+	h := NewHealthTracker(3, 5*time.Minute)
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// A steady component never flaps
+	for i := 0; i < 20; i++ {
+		if h.Record("rebalancer", true, start.Add(time.Duration(i)*30*time.Second)) {
+			t.Fatal("steady component reported as flapping")
+		}
+	}
+
+	// Alternating every check: the fourth change within 5 minutes starts an episode
+	started := -1
+	for i := 0; i < 10; i++ {
+		now := start.Add(time.Duration(i) * 30 * time.Second)
+		if h.Record("ibc_relayer", i%2 == 0, now) {
+			if started >= 0 {
+				t.Fatalf("flap alert repeated at check %d", i)
+			}
+			started = i
+		}
+	}
+	if started != 4 {
+		t.Fatalf("flapping detected at check %d, want 4", started)
+	}
+
+	// Once stable for a full window the episode ends, and a new one is counted again
+	quiet := start.Add(20 * time.Minute)
+	if h.Record("ibc_relayer", true, quiet) {
+		t.Fatal("stable component reported as flapping")
+	}
+	status := h.FlapStatus(quiet)["ibc_relayer"].(map[string]interface{})
+	if status["flapping"] != false || status["flap_count"] != int64(1) {
+		t.Fatalf("after recovery status = %v", status)
+	}
+
+	for i := 1; i <= 4; i++ {
+		h.Record("ibc_relayer", i%2 == 1, quiet.Add(time.Duration(i)*time.Minute))
+	}
+	status = h.FlapStatus(quiet.Add(4 * time.Minute))["ibc_relayer"].(map[string]interface{})
+	if status["flapping"] != true || status["flap_count"] != int64(2) {
+		t.Fatalf("second episode status = %v", status)
+	}
+}
+
+func TestHealthTrackerHistoryIsBounded(t *testing.T) {
+	h := NewHealthTracker(0, 0)
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3*HealthHistorySize; i++ {
+		h.Record("dex_manager", i%2 == 0, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	history := h.History("dex_manager")
+	if len(history) != HealthHistorySize {
+		t.Fatalf("history holds %d entries, want %d", len(history), HealthHistorySize)
+	}
+	latest := history[len(history)-1]
+	if latest.Healthy || !latest.Timestamp.Equal(start.Add(time.Duration(3*HealthHistorySize-1)*time.Hour)) {
+		t.Fatalf("latest transition = %+v", latest)
+	}
+
+	// Repeated identical results are not state changes
+	h.Record("dex_manager", false, start.Add(1000*time.Hour))
+	if history := h.History("dex_manager"); !history[len(history)-1].Timestamp.Equal(latest.Timestamp) {
+		t.Fatal("unchanged health state was recorded as a transition")
+	}
+}
+
+func TestBotServiceReportsHealthFlaps(t *testing.T) {
+	bs := &BotService{
+		config:        &BotConfig{},
+		healthStatus:  make(map[string]bool),
+		healthTracker: NewHealthTracker(2, time.Hour),
+	}
+
+	for i := 0; i < 4; i++ {
+		bs.healthStatus["telegram_alert"] = i%2 == 0
+		bs.performHealthCheck()
+	}
+
+	flaps := bs.GetStatus()["health_flaps"].(map[string]interface{})
+	entry, ok := flaps["telegram_alert"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("health_flaps = %v", flaps)
+	}
+	if entry["flapping"] != true || entry["flap_count"] != int64(1) || entry["changes_in_window"] != 3 {
+		t.Fatalf("telegram_alert flaps = %v", entry)
+	}
+}
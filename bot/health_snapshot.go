@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// HealthSnapshotFile is the health snapshot name inside the data directory
+	HealthSnapshotFile = "health_snapshot.json"
+	// HealthSnapshotEnv overrides the snapshot path; the launcher sets it to a
+	// file in its run directory so it can find the snapshot without the bot config
+	HealthSnapshotEnv = "GXR_BOT_HEALTH_SNAPSHOT"
+)
+
+// HealthSnapshot is the "last known good" state written after every health
+// check. The launcher reads it for its status output and restart policy
+// without depending on the status server being up.
+type HealthSnapshot struct {
+	Version         string               `json:"version"`
+	PID             int                  `json:"pid"`
+	InstanceID      string               `json:"instance_id"`
+	WrittenAt       time.Time            `json:"written_at"`
+	IntervalSeconds int64                `json:"interval_seconds"`
+	Healthy         bool                 `json:"healthy"`
+	UnhealthySince  *time.Time           `json:"unhealthy_since,omitempty"`
+	Health          map[string]bool      `json:"health"`
+	LastHeartbeats  map[string]time.Time `json:"last_heartbeats,omitempty"`
+	RebalancerState string               `json:"rebalancer_state,omitempty"`
+	ReadOnly        bool                 `json:"read_only"`
+	ChainIDMismatch bool                 `json:"chain_id_mismatch,omitempty"`
+}
+
+// Stale reports whether the snapshot is older than two health check
+// intervals, i.e. the bot missed at least one write
+func (s HealthSnapshot) Stale(now time.Time) bool {
+	interval := time.Duration(s.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = HealthCheckInterval
+	}
+	return now.Sub(s.WrittenAt) > 2*interval
+}
+
+// healthSnapshotPath returns where the snapshot is written: HealthSnapshotEnv
+// when set, otherwise data_dir/health_snapshot.json
+func healthSnapshotPath(dataDir string) string {
+	if path := os.Getenv(HealthSnapshotEnv); path != "" {
+		return path
+	}
+	if dataDir == "" {
+		dataDir = DefaultDataDir
+	}
+	return filepath.Join(dataDir, HealthSnapshotFile)
+}
+
+// WriteHealthSnapshot atomically replaces the snapshot at path, so a reader
+// sees either the previous or the new snapshot, never a partial one
+func WriteHealthSnapshot(path string, snapshot HealthSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadHealthSnapshot reads a snapshot written by WriteHealthSnapshot
+func ReadHealthSnapshot(path string) (HealthSnapshot, error) {
+	var snapshot HealthSnapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, err
+	}
+	err = json.Unmarshal(data, &snapshot)
+	return snapshot, err
+}
+
+// healthSnapshot captures the state of the last health check
+func (bs *BotService) healthSnapshot(now time.Time) HealthSnapshot {
+	var heartbeats map[string]time.Time
+	if bs.validatorMonitor != nil {
+		heartbeats = bs.validatorMonitor.BotHeartbeats()
+	}
+	rebalancerState := ""
+	if bs.rebalancer != nil {
+		rebalancerState, _ = bs.rebalancer.GetStatus()["state"].(string)
+	}
+
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	health := make(map[string]bool, len(bs.healthStatus))
+	healthy := true
+	for component, ok := range bs.healthStatus {
+		health[component] = ok
+		healthy = healthy && ok
+	}
+
+	snapshot := HealthSnapshot{
+		Version:         Version,
+		PID:             os.Getpid(),
+		InstanceID:      bs.instanceID,
+		WrittenAt:       now.UTC(),
+		IntervalSeconds: int64(HealthCheckInterval / time.Second),
+		Healthy:         healthy,
+		Health:          health,
+		LastHeartbeats:  heartbeats,
+		RebalancerState: rebalancerState,
+		ReadOnly:        bs.readOnly,
+		ChainIDMismatch: bs.chainIDMismatch,
+	}
+	if !bs.unhealthySince.IsZero() {
+		since := bs.unhealthySince.UTC()
+		snapshot.UnhealthySince = &since
+	}
+	return snapshot
+}
+
+// writeHealthSnapshot writes the snapshot of the last health check
+func (bs *BotService) writeHealthSnapshot(now time.Time) error {
+	return WriteHealthSnapshot(healthSnapshotPath(bs.config.DataDir), bs.healthSnapshot(now))
+}
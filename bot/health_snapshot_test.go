@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHealthSnapshotAtomicWriteAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", HealthSnapshotFile)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Minute)
+
+	snapshot := HealthSnapshot{
+		Version:         Version,
+		PID:             42,
+		InstanceID:      "abc",
+		WrittenAt:       now,
+		IntervalSeconds: 30,
+		Healthy:         false,
+		UnhealthySince:  &since,
+		Health:          map[string]bool{"rebalancer": true, "ibc_relayer": false},
+		LastHeartbeats:  map[string]time.Time{"gxrvaloper1test": now.Add(-20 * time.Second)},
+		RebalancerState: "active",
+	}
+	if err := WriteHealthSnapshot(path, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	// The temporary file is renamed over the snapshot, nothing is left behind
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temporary snapshot left behind: %v", err)
+	}
+
+	read, err := ReadHealthSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.PID != 42 || read.Healthy || !read.UnhealthySince.Equal(since) || read.Health["ibc_relayer"] {
+		t.Fatalf("snapshot round trip = %+v", read)
+	}
+	if !read.LastHeartbeats["gxrvaloper1test"].Equal(now.Add(-20 * time.Second)) {
+		t.Fatalf("last heartbeats = %v", read.LastHeartbeats)
+	}
+
+	// A rewrite replaces the whole snapshot
+	snapshot.Healthy, snapshot.UnhealthySince = true, nil
+	if err := WriteHealthSnapshot(path, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if read, err = ReadHealthSnapshot(path); err != nil || !read.Healthy || read.UnhealthySince != nil {
+		t.Fatalf("rewritten snapshot = %+v, %v", read, err)
+	}
+}
+
+func TestHealthSnapshotStale(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := HealthSnapshot{WrittenAt: now, IntervalSeconds: 30}
+
+	if snapshot.Stale(now.Add(60 * time.Second)) {
+		t.Fatal("snapshot within two intervals reported stale")
+	}
+	if !snapshot.Stale(now.Add(61 * time.Second)) {
+		t.Fatal("snapshot older than two intervals not reported stale")
+	}
+
+	// Without an interval the bot's health check interval applies
+	snapshot.IntervalSeconds = 0
+	if !snapshot.Stale(now.Add(2*HealthCheckInterval + time.Second)) {
+		t.Fatal("snapshot without interval not reported stale")
+	}
+}
+
+func TestBotServiceWritesHealthSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot_health.json")
+	t.Setenv(HealthSnapshotEnv, path)
+
+	bs := &BotService{
+		config:        &BotConfig{DataDir: t.TempDir()},
+		healthStatus:  map[string]bool{"rebalancer": true, "dex_manager": true},
+		healthTracker: NewHealthTracker(0, 0),
+		instanceID:    "abc",
+	}
+
+	bs.performHealthCheck()
+	if err := bs.writeHealthSnapshot(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := ReadHealthSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !snapshot.Healthy || snapshot.UnhealthySince != nil || snapshot.PID != os.Getpid() || snapshot.InstanceID != "abc" {
+		t.Fatalf("healthy snapshot = %+v", snapshot)
+	}
+
+	// A failing component starts the unhealthy run, which persists across checks
+	bs.healthStatus["dex_manager"] = false
+	bs.performHealthCheck()
+	first := bs.lastHealthCheck
+	bs.performHealthCheck()
+	if err := bs.writeHealthSnapshot(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot, err = ReadHealthSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Healthy || snapshot.UnhealthySince == nil || !snapshot.UnhealthySince.Equal(first.UTC()) {
+		t.Fatalf("unhealthy snapshot = %+v", snapshot)
+	}
+	if snapshot.Health["dex_manager"] || !snapshot.Health["rebalancer"] {
+		t.Fatalf("health = %v", snapshot.Health)
+	}
+
+	// Recovery clears it
+	bs.healthStatus["dex_manager"] = true
+	bs.performHealthCheck()
+	if snapshot := bs.healthSnapshot(time.Now()); !snapshot.Healthy || snapshot.UnhealthySince != nil {
+		t.Fatalf("recovered snapshot = %+v", snapshot)
+	}
+}
+
+func TestHealthSnapshotPathDefaultsToDataDir(t *testing.T) {
+	t.Setenv(HealthSnapshotEnv, "")
+	if got := healthSnapshotPath("/var/lib/gxr-bot"); got != filepath.Join("/var/lib/gxr-bot", HealthSnapshotFile) {
+		t.Fatalf("snapshot path = %s", got)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// HeartbeatStoreFile holds the last bot heartbeat of every validator
+	HeartbeatStoreFile = "heartbeats.db"
+	// HeartbeatStoreRetention is how long a heartbeat is kept once its
+	// validator stopped sending them
+	HeartbeatStoreRetention = 7 * 24 * time.Hour
+	// heartbeatStoreOpenTimeout bounds waiting for the file lock held by
+	// another bot using the same data_dir
+	heartbeatStoreOpenTimeout = 5 * time.Second
+)
+
+var heartbeatBucket = []byte("heartbeats")
+
+// HeartbeatStore persists the last bot heartbeat of each validator in a
+// BoltDB file, so a restarted bot does not take validators whose heartbeat
+// it saw before the restart for non-compliant
+type HeartbeatStore struct {
+	db *bolt.DB
+}
+
+// OpenHeartbeatStore opens or creates the heartbeat store at path
+func OpenHeartbeatStore(path string) (*HeartbeatStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: heartbeatStoreOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open heartbeat store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(heartbeatBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize heartbeat store %s: %w", path, err)
+	}
+	return &HeartbeatStore{db: db}, nil
+}
+
+// Set records t as the last heartbeat of the validator addr
+func (s *HeartbeatStore) Set(addr string, t time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(heartbeatBucket).Put([]byte(addr), encodeHeartbeatTime(t))
+	})
+}
+
+// Get returns the last recorded heartbeat of the validator addr
+func (s *HeartbeatStore) Get(addr string) (time.Time, bool) {
+	var t time.Time
+	var found bool
+	s.db.View(func(tx *bolt.Tx) error {
+		t, found = decodeHeartbeatTime(tx.Bucket(heartbeatBucket).Get([]byte(addr)))
+		return nil
+	})
+	return t, found
+}
+
+// All returns the recorded heartbeat of every validator
+func (s *HeartbeatStore) All() (map[string]time.Time, error) {
+	heartbeats := make(map[string]time.Time)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(heartbeatBucket).ForEach(func(k, v []byte) error {
+			if t, ok := decodeHeartbeatTime(v); ok {
+				heartbeats[string(k)] = t
+			}
+			return nil
+		})
+	})
+	return heartbeats, err
+}
+
+// Prune removes the heartbeats older than olderThan and returns how many
+// were removed
+func (s *HeartbeatStore) Prune(olderThan time.Time) (int, error) {
+	pruned := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(heartbeatBucket)
+		var stale [][]byte
+		bucket.ForEach(func(k, v []byte) error {
+			if t, ok := decodeHeartbeatTime(v); !ok || t.Before(olderThan) {
+				stale = append(stale, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		for _, k := range stale {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		pruned = len(stale)
+		return nil
+	})
+	return pruned, err
+}
+
+// Close closes the store file
+func (s *HeartbeatStore) Close() error {
+	return s.db.Close()
+}
+
+func encodeHeartbeatTime(t time.Time) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(t.UnixNano()))
+	return b
+}
+
+// decodeHeartbeatTime decodes a stored heartbeat; unreadable values are
+// reported as missing
+func decodeHeartbeatTime(b []byte) (time.Time, bool) {
+	if len(b) != 8 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(b))), true
+}
+
+// openHeartbeatStore opens the heartbeat store of dataDir. Without a data
+// directory, or when the file cannot be opened, heartbeats are only kept in
+// memory.
+func openHeartbeatStore(dataDir string) *HeartbeatStore {
+	if dataDir == "" {
+		return nil
+	}
+	store, err := OpenHeartbeatStore(filepath.Join(dataDir, HeartbeatStoreFile))
+	if err != nil {
+		log.Printf("Bot heartbeats kept in memory only: %v", err)
+		return nil
+	}
+	return store
+}
+
+// reconcileHeartbeats drops the persisted heartbeats older than
+// HeartbeatStoreRetention and loads the rest into the in-memory map,
+// keeping any newer heartbeat already registered
+func (vm *ValidatorMonitor) reconcileHeartbeats(now time.Time) {
+	if vm.heartbeatStore == nil {
+		return
+	}
+	if pruned, err := vm.heartbeatStore.Prune(now.Add(-HeartbeatStoreRetention)); err != nil {
+		log.Printf("Failed to prune heartbeat store: %v", err)
+	} else if pruned > 0 {
+		log.Printf("Pruned %d bot heartbeats older than %s", pruned, HeartbeatStoreRetention)
+	}
+
+	stored, err := vm.heartbeatStore.All()
+	if err != nil {
+		log.Printf("Failed to load persisted bot heartbeats: %v", err)
+		return
+	}
+
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	for addr, t := range stored {
+		if t.After(vm.botHeartbeats[addr]) {
+			vm.botHeartbeats[addr] = t
+		}
+	}
+	if len(stored) > 0 {
+		log.Printf("Restored %d persisted bot heartbeats", len(stored))
+	}
+}
+
+// lastBotHeartbeat returns the last heartbeat of the validator addr, read
+// from the store when it is not in memory. Callers hold vm.mu.
+func (vm *ValidatorMonitor) lastBotHeartbeat(addr string) (time.Time, bool) {
+	if t, ok := vm.botHeartbeats[addr]; ok {
+		return t, true
+	}
+	if vm.heartbeatStore == nil {
+		return time.Time{}, false
+	}
+	return vm.heartbeatStore.Get(addr)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+)
+
+func TestHeartbeatStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HeartbeatStoreFile)
+	store, err := OpenHeartbeatStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1700000000, 123)
+	if err := store.Set("gxrvaloper1ours", now); err != nil {
+		t.Fatal(err)
+	}
+	store.Set("gxrvaloper1gone", now.Add(-8*24*time.Hour))
+	if got, ok := store.Get("gxrvaloper1ours"); !ok || !got.Equal(now) {
+		t.Fatalf("got %s, %v", got, ok)
+	}
+	if _, ok := store.Get("gxrvaloper1unknown"); ok {
+		t.Fatal("unknown validator has a heartbeat")
+	}
+	store.Close()
+
+	// Heartbeats survive reopening, stale ones are pruned
+	store, err = OpenHeartbeatStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if pruned, err := store.Prune(now.Add(-HeartbeatStoreRetention)); err != nil || pruned != 1 {
+		t.Fatalf("pruned %d: %v", pruned, err)
+	}
+	all, err := store.All()
+	if err != nil || len(all) != 1 || !all["gxrvaloper1ours"].Equal(now) {
+		t.Fatalf("stored %v: %v", all, err)
+	}
+}
+
+func TestBotHeartbeatsSurviveRestart(t *testing.T) {
+	dataDir := t.TempDir()
+	newMonitor := func() *ValidatorMonitor {
+		vm := NewValidatorMonitor(&BotConfig{DataDir: dataDir}, client.Context{}, nil)
+		vm.telegramAlert = nil
+		vm.validators["gxrvaloper1ours"] = &ValidatorStatus{OperatorAddress: "gxrvaloper1ours"}
+		return vm
+	}
+
+	vm := newMonitor()
+	vm.RegisterBotHeartbeat("gxrvaloper1ours", Version)
+	vm.Stop()
+
+	// The restarted bot has not seen a heartbeat yet, but the one persisted
+	// before the restart still counts
+	restarted := newMonitor()
+	t.Cleanup(restarted.Stop)
+	restarted.mu.Lock()
+	running := restarted.isValidatorBotRunning(restarted.validators["gxrvaloper1ours"])
+	restarted.mu.Unlock()
+	if !running {
+		t.Fatal("bot taken for stopped before reconciliation")
+	}
+
+	restarted.reconcileHeartbeats(time.Now())
+	if _, ok := restarted.BotHeartbeats()["gxrvaloper1ours"]; !ok {
+		t.Fatal("persisted heartbeat not loaded into memory")
+	}
+	restarted.checkBotHeartbeats(context.Background())
+	if !restarted.validators["gxrvaloper1ours"].BotRunning {
+		t.Fatal("validator with a persisted heartbeat reported without bot")
+	}
+}
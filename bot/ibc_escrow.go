@@ -0,0 +1,572 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+const (
+	// IBCEscrowFile stores the escrow balance history in the data directory
+	IBCEscrowFile = "ibc_escrow.json"
+	// IBCTransferPort is the ICS-20 port whose channels escrow ugen
+	IBCTransferPort = "transfer"
+	// IBCTransferVersion is the ICS-20 version the escrow address is derived from
+	IBCTransferVersion = "ics20-1"
+	// AccountAddressPrefix is the chain's account address prefix, used
+	// unless validator_address has another one
+	AccountAddressPrefix = "gxr"
+	// EscrowDenom is the denom locked in the escrow accounts
+	EscrowDenom = "ugen"
+	// DefaultEscrowCheckInterval is how often the escrow balances are queried
+	DefaultEscrowCheckInterval = 10 * time.Minute
+	// DefaultEscrowChangePercent is the balance change within EscrowChangeWindow that alerts
+	DefaultEscrowChangePercent = 10.0
+	// DefaultEscrowDivergencePercent is the escrow and voucher supply difference that alerts
+	DefaultEscrowDivergencePercent = 1.0
+	// EscrowChangeWindow is the window balance changes are measured over
+	EscrowChangeWindow = time.Hour
+	// EscrowHistoryRetention covers a report month; samples older than
+	// twice EscrowChangeWindow are thinned to one per hour
+	EscrowHistoryRetention = 35 * 24 * time.Hour
+)
+
+// IBCEscrowConfig configures the monitoring of the ICS-20 escrow balances of
+// the channels in ibc_channels
+type IBCEscrowConfig struct {
+	Enabled           bool          `yaml:"enabled"`
+	Interval          time.Duration `yaml:"interval"`           // default 10m
+	ChangePercent     float64       `yaml:"change_percent"`     // default 10, per hour
+	DivergencePercent float64       `yaml:"divergence_percent"` // default 1
+	// Counterparties maps our channel ID to the chain holding its vouchers
+	Counterparties map[string]EscrowCounterparty `yaml:"counterparties"`
+}
+
+// EscrowCounterparty is the chain a channel sends ugen to, queried for the
+// supply of the ugen voucher
+type EscrowCounterparty struct {
+	API       string `yaml:"api"`        // REST (LCD) endpoint, e.g. https://lcd.osmosis.zone
+	ChannelID string `yaml:"channel_id"` // counterparty end of the channel
+	Denom     string `yaml:"denom"`      // voucher denom, default derived from channel_id
+}
+
+// ValidateIBCEscrow checks the IBC escrow monitoring settings
+func ValidateIBCEscrow(cfg IBCEscrowConfig, channels []string) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Interval < 0 || cfg.ChangePercent < 0 || cfg.DivergencePercent < 0 {
+		return fmt.Errorf("ibc_escrow interval and percentages cannot be negative")
+	}
+	for channel, counterparty := range cfg.Counterparties {
+		if !containsString(channels, channel) {
+			return fmt.Errorf("ibc_escrow counterparty %s is not in ibc_channels", channel)
+		}
+		if counterparty.API == "" {
+			return fmt.Errorf("ibc_escrow counterparty %s needs an api", channel)
+		}
+		if counterparty.ChannelID == "" && counterparty.Denom == "" {
+			return fmt.Errorf("ibc_escrow counterparty %s needs a channel_id or denom", channel)
+		}
+	}
+	return nil
+}
+
+// EscrowAddress derives the ICS-20 escrow account address of a channel as
+// ibc-go does, encoded with the account prefix
+func EscrowAddress(prefix, portID, channelID string) (string, error) {
+	preImage := append([]byte(IBCTransferVersion), 0)
+	preImage = append(preImage, portID+"/"+channelID...)
+	hash := sha256.Sum256(preImage)
+	return bech32.ConvertAndEncode(prefix, hash[:20])
+}
+
+// VoucherDenom returns the denom ugen sent over channelID of portID has on
+// the receiving chain, ibc/ followed by the hash of its trace
+func VoucherDenom(portID, channelID, baseDenom string) string {
+	hash := sha256.Sum256([]byte(portID + "/" + channelID + "/" + baseDenom))
+	return "ibc/" + strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+// EscrowSample is one query of a channel's escrow balance, in ugen
+type EscrowSample struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Balance       uint64    `json:"balance"`
+	VoucherSupply *uint64   `json:"voucher_supply,omitempty"`
+}
+
+// EscrowChannel is the escrow account of one channel and its balance history
+type EscrowChannel struct {
+	Channel      string         `json:"channel"`
+	Address      string         `json:"address"`
+	VoucherDenom string         `json:"voucher_denom,omitempty"`
+	History      []EscrowSample `json:"history"`
+	LastError    string         `json:"last_error,omitempty"`
+
+	// Anomalies alerted, cleared when the balance is back within the limits
+	ChangeAlerted     bool `json:"change_alerted"`
+	DivergenceAlerted bool `json:"divergence_alerted"`
+}
+
+// Latest returns the most recent sample
+func (c *EscrowChannel) Latest() (EscrowSample, bool) {
+	if len(c.History) == 0 {
+		return EscrowSample{}, false
+	}
+	return c.History[len(c.History)-1], true
+}
+
+// EscrowMonitor periodically queries the escrow balances of the IBC channels
+// and alerts on large swings and on divergence from the counterparty's
+// voucher supply
+type EscrowMonitor struct {
+	interval          time.Duration
+	changePercent     float64
+	divergencePercent float64
+	chain             *ChainQuerier
+	counterparties    map[string]*ChainQuerier
+	telegramAlert     *TelegramAlert
+	path              string
+
+	mu        sync.RWMutex
+	channels  map[string]*EscrowChannel
+	lastCheck time.Time
+}
+
+// NewEscrowMonitor creates the escrow monitor of config.IBCChannels. The
+// history is restored from dataDir; an empty dataDir keeps it in memory only.
+func NewEscrowMonitor(config *BotConfig, chain *ChainQuerier, telegramAlert *TelegramAlert) (*EscrowMonitor, error) {
+	cfg := config.IBCEscrow
+	m := &EscrowMonitor{
+		interval:          cfg.Interval,
+		changePercent:     cfg.ChangePercent,
+		divergencePercent: cfg.DivergencePercent,
+		chain:             chain,
+		counterparties:    make(map[string]*ChainQuerier),
+		telegramAlert:     telegramAlert,
+		channels:          make(map[string]*EscrowChannel),
+	}
+	if m.interval <= 0 {
+		m.interval = DefaultEscrowCheckInterval
+	}
+	if m.changePercent <= 0 {
+		m.changePercent = DefaultEscrowChangePercent
+	}
+	if m.divergencePercent <= 0 {
+		m.divergencePercent = DefaultEscrowDivergencePercent
+	}
+
+	stored := make(map[string]*EscrowChannel)
+	if config.DataDir != "" {
+		m.path = filepath.Join(config.DataDir, IBCEscrowFile)
+		if data, err := os.ReadFile(m.path); err == nil {
+			if err := json.Unmarshal(data, &stored); err != nil {
+				log.Printf("Ignoring unreadable IBC escrow history %s: %v", m.path, err)
+				stored = make(map[string]*EscrowChannel)
+			}
+		}
+	}
+
+	prefix := AccountAddressPrefix
+	if hrp, _, err := bech32.DecodeAndConvert(config.ValidatorAddress); err == nil {
+		prefix = strings.TrimSuffix(hrp, "valoper")
+	}
+	for _, channelID := range config.IBCChannels {
+		address, err := EscrowAddress(prefix, IBCTransferPort, channelID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive escrow address of %s: %w", channelID, err)
+		}
+
+		channel := &EscrowChannel{Channel: channelID, Address: address}
+		if previous, ok := stored[channelID]; ok && previous.Address == address {
+			channel = previous
+		}
+		if counterparty, ok := cfg.Counterparties[channelID]; ok {
+			channel.VoucherDenom = counterparty.Denom
+			if channel.VoucherDenom == "" {
+				channel.VoucherDenom = VoucherDenom(IBCTransferPort, counterparty.ChannelID, EscrowDenom)
+			}
+			m.counterparties[channelID] = NewChainQuerierForAPI(counterparty.API)
+		}
+		m.channels[channelID] = channel
+	}
+
+	return m, nil
+}
+
+// Start queries the escrow balances every interval until ctx is done
+func (m *EscrowMonitor) Start(ctx context.Context) error {
+	log.Printf("Starting IBC escrow monitor for %d channel(s)", len(m.channels))
+
+	m.Check(ctx, time.Now())
+
+	ticker := NewJitteredTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			m.Check(ctx, now)
+		}
+	}
+}
+
+// Check queries the escrow balance of every channel and the voucher supply
+// of the channels with a counterparty, records them and sends the alerts
+func (m *EscrowMonitor) Check(ctx context.Context, now time.Time) {
+	for _, channelID := range m.channelIDs() {
+		m.mu.RLock()
+		channel := m.channels[channelID]
+		address, denom := channel.Address, channel.VoucherDenom
+		m.mu.RUnlock()
+
+		sample, err := m.query(ctx, channelID, address, denom, now)
+		if err != nil {
+			log.Printf("Failed to query IBC escrow of %s: %v", channelID, err)
+			m.mu.Lock()
+			channel.LastError = err.Error()
+			m.mu.Unlock()
+			continue
+		}
+
+		for _, alert := range m.Record(channelID, sample) {
+			log.Printf("IBC escrow anomaly on %s: %s", channelID, alert.Message)
+			if m.telegramAlert != nil {
+				if err := m.telegramAlert.QueueAlert(alert); err != nil {
+					log.Printf("Failed to queue IBC escrow alert: %v", err)
+				}
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.lastCheck = now
+	m.saveLocked()
+	m.mu.Unlock()
+}
+
+// query fetches the escrow balance of a channel and the voucher supply when
+// the channel has a counterparty
+func (m *EscrowMonitor) query(ctx context.Context, channelID, address, denom string, now time.Time) (EscrowSample, error) {
+	balance, err := m.chain.QueryBalance(ctx, address, EscrowDenom)
+	if err != nil {
+		return EscrowSample{}, err
+	}
+	sample := EscrowSample{Timestamp: now, Balance: balance}
+
+	if counterparty, ok := m.counterparties[channelID]; ok {
+		supply, err := counterparty.QuerySupplyOf(ctx, denom)
+		if err != nil {
+			return EscrowSample{}, fmt.Errorf("counterparty voucher supply: %w", err)
+		}
+		sample.VoucherSupply = &supply
+	}
+	return sample, nil
+}
+
+// Record adds a sample to the history of a channel and returns the alerts
+// for anomalies it starts: a balance change over the configured percentage
+// within EscrowChangeWindow, or a voucher supply diverging from the escrow
+// balance by more than the configured percentage
+func (m *EscrowMonitor) Record(channelID string, sample EscrowSample) []*Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	channel, ok := m.channels[channelID]
+	if !ok {
+		return nil
+	}
+	channel.LastError = ""
+	channel.History = pruneEscrowHistory(append(channel.History, sample), sample.Timestamp)
+
+	var alerts []*Alert
+
+	reference, change, ok := escrowChange(channel.History, sample.Timestamp)
+	switch {
+	case ok && change > m.changePercent:
+		if !channel.ChangeAlerted {
+			channel.ChangeAlerted = true
+			alerts = append(alerts, newIBCEscrowAlert(channel, "IBC Escrow Balance Swing",
+				fmt.Sprintf("Escrow balance of %s changed %.2f%% since %s: %d → %d ugen (limit %.2f%%)",
+					channelID, change, reference.Timestamp.UTC().Format("15:04 UTC"), reference.Balance, sample.Balance, m.changePercent),
+				AlertTypeWarning, sample))
+		}
+	case ok:
+		channel.ChangeAlerted = false
+	}
+
+	if sample.VoucherSupply != nil {
+		divergence := escrowDivergence(sample.Balance, *sample.VoucherSupply)
+		if divergence > m.divergencePercent {
+			if !channel.DivergenceAlerted {
+				channel.DivergenceAlerted = true
+				alertType := AlertTypeWarning
+				if *sample.VoucherSupply > sample.Balance {
+					// More vouchers than escrowed ugen backing them
+					alertType = AlertTypeCritical
+				}
+				alerts = append(alerts, newIBCEscrowAlert(channel, "IBC Escrow Divergence",
+					fmt.Sprintf("Escrow balance of %s is %d ugen but the counterparty reports %d %s, %.2f%% apart (limit %.2f%%)",
+						channelID, sample.Balance, *sample.VoucherSupply, channel.VoucherDenom, divergence, m.divergencePercent),
+					alertType, sample))
+			}
+		} else {
+			channel.DivergenceAlerted = false
+		}
+	}
+
+	return alerts
+}
+
+// escrowChange returns the balance change of the latest sample in percent of
+// the balance EscrowChangeWindow before it: the last sample at or before the
+// start of the window, or the first one inside it. It is not defined until a
+// sample older than the latest one exists, nor from a zero balance.
+func escrowChange(history []EscrowSample, now time.Time) (EscrowSample, float64, bool) {
+	if len(history) < 2 {
+		return EscrowSample{}, 0, false
+	}
+	latest := history[len(history)-1]
+	start := now.Add(-EscrowChangeWindow)
+
+	reference := history[0]
+	for _, sample := range history[:len(history)-1] {
+		if sample.Timestamp.After(start) {
+			break
+		}
+		reference = sample
+	}
+	if reference.Timestamp.Before(start.Add(-EscrowChangeWindow)) || reference.Balance == 0 {
+		return EscrowSample{}, 0, false
+	}
+
+	diff := float64(latest.Balance) - float64(reference.Balance)
+	if diff < 0 {
+		diff = -diff
+	}
+	return reference, diff / float64(reference.Balance) * 100, true
+}
+
+// escrowDivergence returns how far the voucher supply is from the escrow
+// balance, in percent of the balance
+func escrowDivergence(balance, supply uint64) float64 {
+	if balance == supply {
+		return 0
+	}
+	if balance == 0 {
+		return 100
+	}
+	diff := float64(balance) - float64(supply)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff / float64(balance) * 100
+}
+
+// pruneEscrowHistory drops the samples older than EscrowHistoryRetention and
+// keeps one sample per hour of those older than twice EscrowChangeWindow
+func pruneEscrowHistory(history []EscrowSample, now time.Time) []EscrowSample {
+	kept := history[:0]
+	var lastHour time.Time
+	for _, sample := range history {
+		age := now.Sub(sample.Timestamp)
+		if age > EscrowHistoryRetention {
+			continue
+		}
+		if age > 2*EscrowChangeWindow {
+			hour := sample.Timestamp.Truncate(time.Hour)
+			if hour.Equal(lastHour) {
+				continue
+			}
+			lastHour = hour
+		}
+		kept = append(kept, sample)
+	}
+	return kept
+}
+
+// newIBCEscrowAlert reports an escrow anomaly of a channel
+func newIBCEscrowAlert(channel *EscrowChannel, title, message string, alertType AlertType, sample EscrowSample) *Alert {
+	metadata := map[string]interface{}{
+		"channel":        channel.Channel,
+		"escrow_address": channel.Address,
+		"balance":        fmt.Sprintf("%d ugen", sample.Balance),
+	}
+	if sample.VoucherSupply != nil {
+		metadata["voucher_supply"] = fmt.Sprintf("%d %s", *sample.VoucherSupply, channel.VoucherDenom)
+	}
+
+	return &Alert{
+		ID:        fmt.Sprintf("ibc-escrow-%s-%d", channel.Channel, sample.Timestamp.UnixNano()),
+		Type:      alertType,
+		Priority:  AlertPriorityHigh,
+		Title:     title,
+		Message:   message,
+		Timestamp: sample.Timestamp,
+		Metadata:  metadata,
+	}
+}
+
+// channelIDs returns the monitored channels in order
+func (m *EscrowMonitor) channelIDs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]string, 0, len(m.channels))
+	for id := range m.channels {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Channel returns a copy of the escrow state of a channel
+func (m *EscrowMonitor) Channel(channelID string) (EscrowChannel, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	channel, ok := m.channels[channelID]
+	if !ok {
+		return EscrowChannel{}, false
+	}
+	copied := *channel
+	copied.History = append([]EscrowSample(nil), channel.History...)
+	return copied, true
+}
+
+// GetStatus returns the latest escrow balance of each channel
+func (m *EscrowMonitor) GetStatus() map[string]interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	channels := make(map[string]interface{})
+	for id, channel := range m.channels {
+		entry := map[string]interface{}{
+			"address":            channel.Address,
+			"change_alerted":     channel.ChangeAlerted,
+			"divergence_alerted": channel.DivergenceAlerted,
+		}
+		if latest, ok := channel.Latest(); ok {
+			entry["balance"] = latest.Balance
+			entry["updated"] = latest.Timestamp.Format(time.RFC3339)
+			if latest.VoucherSupply != nil {
+				entry["voucher_denom"] = channel.VoucherDenom
+				entry["voucher_supply"] = *latest.VoucherSupply
+				entry["divergence_percent"] = escrowDivergence(latest.Balance, *latest.VoucherSupply)
+			}
+		}
+		if _, change, ok := escrowChange(channel.History, m.lastCheck); ok {
+			entry["change_percent_1h"] = change
+		}
+		if channel.LastError != "" {
+			entry["last_error"] = channel.LastError
+		}
+		channels[id] = entry
+	}
+
+	return map[string]interface{}{
+		"interval":           m.interval.String(),
+		"change_percent":     m.changePercent,
+		"divergence_percent": m.divergencePercent,
+		"last_check":         m.lastCheck.Format(time.RFC3339),
+		"channels":           channels,
+	}
+}
+
+// Metrics returns the escrow gauges by metric name, labelled by channel
+func (m *EscrowMonitor) Metrics() map[string]float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	metrics := make(map[string]float64)
+	for id, channel := range m.channels {
+		latest, ok := channel.Latest()
+		if !ok {
+			continue
+		}
+		metrics[fmt.Sprintf(`gxr_ibc_escrow_balance_ugen{channel="%s"}`, id)] = float64(latest.Balance)
+		if latest.VoucherSupply != nil {
+			metrics[fmt.Sprintf(`gxr_ibc_voucher_supply{channel="%s"}`, id)] = float64(*latest.VoucherSupply)
+			metrics[fmt.Sprintf(`gxr_ibc_escrow_divergence_percent{channel="%s"}`, id)] = escrowDivergence(latest.Balance, *latest.VoucherSupply)
+		}
+	}
+	return metrics
+}
+
+// ReportEscrow summarizes the escrow balance of each channel over the month
+// starting at monthStart, from the samples recorded in it
+func (m *EscrowMonitor) ReportEscrow(monthStart time.Time) []ReportEscrow {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	var report []ReportEscrow
+	for _, channel := range m.channels {
+		var summary *ReportEscrow
+		for _, sample := range channel.History {
+			if sample.Timestamp.Before(monthStart) || !sample.Timestamp.Before(monthEnd) {
+				continue
+			}
+			if summary == nil {
+				summary = &ReportEscrow{Channel: channel.Channel, Address: channel.Address, Start: sample.Balance, Min: sample.Balance}
+			}
+			summary.End = sample.Balance
+			if sample.Balance < summary.Min {
+				summary.Min = sample.Balance
+			}
+			if sample.Balance > summary.Max {
+				summary.Max = sample.Balance
+			}
+			if sample.VoucherSupply != nil {
+				if divergence := escrowDivergence(sample.Balance, *sample.VoucherSupply); divergence > summary.MaxDivergence {
+					summary.MaxDivergence = divergence
+				}
+			}
+		}
+		if summary != nil {
+			report = append(report, *summary)
+		}
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Channel < report[j].Channel })
+	return report
+}
+
+// saveLocked atomically writes the escrow history. m.mu must be held.
+func (m *EscrowMonitor) saveLocked() {
+	if m.path == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(m.channels, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode IBC escrow history: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		log.Printf("Failed to save IBC escrow history: %v", err)
+		return
+	}
+
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("Failed to save IBC escrow history: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, m.path); err != nil {
+		os.Remove(tmp)
+		log.Printf("Failed to save IBC escrow history: %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// escrowServer serves the bank balance and supply queries of a chain, with
+// the amount returned set by the test
+func escrowServer(t *testing.T, amount *atomic.Uint64) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/cosmos/bank/v1beta1/balances/"):
+			fmt.Fprintf(w, `{"balance":{"denom":%q,"amount":"%d"}}`, r.URL.Query().Get("denom"), amount.Load())
+		case r.URL.Path == "/cosmos/bank/v1beta1/supply/by_denom":
+			fmt.Fprintf(w, `{"amount":{"denom":%q,"amount":"%d"}}`, r.URL.Query().Get("denom"), amount.Load())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVoucherDenomMatchesIBCTrace(t *testing.T) {
+	// ATOM received by Osmosis over its channel-0
+	if got := VoucherDenom("transfer", "channel-0", "uatom"); got != "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2" {
+		t.Fatalf("voucher denom = %s", got)
+	}
+
+	address, err := EscrowAddress("gxr", IBCTransferPort, "channel-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := EscrowAddress("gxr", IBCTransferPort, "channel-1")
+	if !strings.HasPrefix(address, "gxr1") || address == other {
+		t.Fatalf("escrow addresses %s and %s", address, other)
+	}
+}
+
+func TestEscrowMonitorAlertsOnLargeSwing(t *testing.T) {
+	config := &BotConfig{
+		ValidatorAddress: "gxrvaloper1ours",
+		IBCChannels:      []string{"channel-0"},
+		IBCEscrow:        IBCEscrowConfig{Enabled: true, ChangePercent: 20},
+	}
+	monitor, err := NewEscrowMonitor(config, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	record := func(offset time.Duration, balance uint64) []*Alert {
+		return monitor.Record("channel-0", EscrowSample{Timestamp: start.Add(offset), Balance: balance})
+	}
+
+	// Steady outflows of 5% per sample add up to less than 20% in an hour
+	balance := uint64(1_000_000_000)
+	for i := 0; i <= 6; i++ {
+		if alerts := record(time.Duration(i)*10*time.Minute, balance); len(alerts) != 0 {
+			t.Fatalf("alert at sample %d: %s", i, alerts[0].Message)
+		}
+		if i%2 == 0 {
+			balance = balance * 95 / 100
+		}
+	}
+
+	// A drop of a third within the hour alerts once
+	alerts := record(70*time.Minute, 600_000_000)
+	if len(alerts) != 1 || alerts[0].Title != "IBC Escrow Balance Swing" || alerts[0].Type != AlertTypeWarning {
+		t.Fatalf("alerts after the swing = %v", alerts)
+	}
+	if !strings.Contains(alerts[0].Message, "→ 600000000 ugen") {
+		t.Fatalf("swing message = %s", alerts[0].Message)
+	}
+	if alerts := record(80*time.Minute, 590_000_000); len(alerts) != 0 {
+		t.Fatalf("swing alerted twice")
+	}
+
+	// Once the hour before is at the new level the swing is over, and the
+	// next one alerts again
+	for offset := 90 * time.Minute; offset <= 3*time.Hour; offset += 10 * time.Minute {
+		if alerts := record(offset, 590_000_000); len(alerts) != 0 {
+			t.Fatalf("alert at %v while steady", offset)
+		}
+	}
+	if channel, _ := monitor.Channel("channel-0"); channel.ChangeAlerted {
+		t.Fatalf("swing not cleared")
+	}
+	if alerts := record(3*time.Hour+10*time.Minute, 900_000_000); len(alerts) != 1 {
+		t.Fatalf("alerts after an inflow = %v", alerts)
+	}
+
+	// History older than two hours is thinned to one sample per hour
+	channel, _ := monitor.Channel("channel-0")
+	if len(channel.History) != 15 {
+		t.Fatalf("history has %d samples", len(channel.History))
+	}
+}
+
+func TestEscrowMonitorAlertsOnDivergence(t *testing.T) {
+	var escrowed, vouchers atomic.Uint64
+	escrowed.Store(5_000_000_000)
+	vouchers.Store(5_000_000_000)
+	chain := escrowServer(t, &escrowed)
+	counterparty := escrowServer(t, &vouchers)
+
+	config := &BotConfig{
+		ValidatorAddress: "gxrvaloper1ours",
+		DataDir:          t.TempDir(),
+		IBCChannels:      []string{"channel-0", "channel-1"},
+		IBCEscrow: IBCEscrowConfig{
+			Enabled: true,
+			Counterparties: map[string]EscrowCounterparty{
+				"channel-0": {API: counterparty.URL, ChannelID: "channel-2093"},
+			},
+		},
+	}
+	if err := ValidateIBCEscrow(config.IBCEscrow, config.IBCChannels); err != nil {
+		t.Fatal(err)
+	}
+	monitor, err := NewEscrowMonitor(config, NewChainQuerierForAPI(chain.URL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+
+	monitor.Check(ctx, now)
+	channel, _ := monitor.Channel("channel-0")
+	if channel.DivergenceAlerted || channel.VoucherDenom != VoucherDenom("transfer", "channel-2093", "ugen") {
+		t.Fatalf("channel after a matching check = %+v", channel)
+	}
+
+	// Vouchers minted on the counterparty without ugen escrowed here
+	vouchers.Store(5_200_000_000)
+	alerts := monitor.Record("channel-0", EscrowSample{Timestamp: now.Add(10 * time.Minute), Balance: escrowed.Load(), VoucherSupply: ptrUint64(vouchers.Load())})
+	if len(alerts) != 1 || alerts[0].Title != "IBC Escrow Divergence" || alerts[0].Type != AlertTypeCritical {
+		t.Fatalf("alerts after the divergence = %v", alerts)
+	}
+	if !strings.Contains(alerts[0].Message, "4.00% apart") {
+		t.Fatalf("divergence message = %s", alerts[0].Message)
+	}
+
+	// The monitor keeps it alerted through its own checks until the supply
+	// is back within the limit
+	monitor.Check(ctx, now.Add(20*time.Minute))
+	if channel, _ := monitor.Channel("channel-0"); !channel.DivergenceAlerted {
+		t.Fatalf("divergence cleared while diverging")
+	}
+	vouchers.Store(5_010_000_000)
+	monitor.Check(ctx, now.Add(30*time.Minute))
+
+	status := monitor.GetStatus()["channels"].(map[string]interface{})
+	entry := status["channel-0"].(map[string]interface{})
+	if entry["divergence_alerted"] != false || entry["voucher_supply"] != uint64(5_010_000_000) || entry["balance"] != uint64(5_000_000_000) {
+		t.Fatalf("status = %v", entry)
+	}
+	if _, ok := status["channel-1"].(map[string]interface{})["voucher_supply"]; ok {
+		t.Fatalf("voucher supply reported for a channel without counterparty")
+	}
+	if metrics := monitor.Metrics(); metrics[`gxr_ibc_escrow_balance_ugen{channel="channel-1"}`] != 5_000_000_000 {
+		t.Fatalf("metrics = %v", metrics)
+	}
+
+	// The history survives a restart and feeds the monthly report
+	restored, err := NewEscrowMonitor(config, NewChainQuerierForAPI(chain.URL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := restored.ReportEscrow(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	if len(report) != 2 || report[0].Channel != "channel-0" || report[0].Max != 5_000_000_000 || report[0].MaxDivergence < 3.99 {
+		t.Fatalf("report = %+v", report)
+	}
+	if report := restored.ReportEscrow(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)); len(report) != 0 {
+		t.Fatalf("report of a month without samples = %+v", report)
+	}
+}
+
+func ptrUint64(v uint64) *uint64 {
+	return &v
+}
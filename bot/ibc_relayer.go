@@ -1,12 +1,69 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// DefaultIBCPacketTimeout is how long after it was queued a simulated packet
+// whose recv was not relayed is dropped
+const DefaultIBCPacketTimeout = 10 * time.Minute
+
+// IBCTimeoutMaxRetries is how often the timeout of a packet is retried
+// before it waits for the next scan of the packet commitments
+const IBCTimeoutMaxRetries = 3
+
+// errNoBroadcaster is returned for timeouts while no transaction broadcaster is set
+var errNoBroadcaster = errors.New("no transaction broadcaster configured")
+
+// IBCCounterparty is the other end of one of ibc_channels, queried for the
+// proofs of the packets it never received
+type IBCCounterparty struct {
+	API       string `yaml:"api"`        // REST (LCD) endpoint of the counterparty chain
+	ChannelID string `yaml:"channel_id"` // counterparty end of the channel
+	PortID    string `yaml:"port_id"`    // default transfer
+}
+
+// port returns the counterparty port of the channel
+func (c IBCCounterparty) port() string {
+	if c.PortID == "" {
+		return IBCTransferPort
+	}
+	return c.PortID
+}
+
+// ValidateIBCCounterparties checks the counterparty ends of the IBC channels
+func ValidateIBCCounterparties(counterparties map[string]IBCCounterparty, channels []string) error {
+	for channel, counterparty := range counterparties {
+		if !containsString(channels, channel) {
+			return fmt.Errorf("ibc_counterparties: %s is not in ibc_channels", channel)
+		}
+		if counterparty.API == "" || counterparty.ChannelID == "" {
+			return fmt.Errorf("ibc_counterparties: %s needs an api and a channel_id", channel)
+		}
+	}
+	return nil
+}
+
+// TimeoutProof is the proof that the counterparty end of a channel never
+// received a packet
+type TimeoutProof struct {
+	CounterpartyPort    string
+	CounterpartyChannel string
+	Proof               []byte
+	Height              IBCHeight
+}
+
 // IBCRelayer handles IBC relaying operations
 type IBCRelayer struct {
 	config *BotConfig
@@ -14,14 +71,45 @@ type IBCRelayer struct {
 	// IBC state
 	lastRelayTime time.Time
 	relayCount    int64
+	relayFailures int64
 	
 	// Channel management
 	channels      map[string]*IBCChannel
 	packetQueue   []IBCPacket
+	timeoutQueue  []IBCPacket
+	queueMu       sync.Mutex // guards packetQueue and timeoutQueue, which Stop records
+	timeoutCount  int64
 	
 	// Connection health
 	connectionHealth map[string]bool
 	lastHealthCheck  time.Time
+	
+	// relayStep submits the recv or ack of a packet
+	relayStep func(ctx context.Context, packet IBCPacket, step string) error
+	
+	// blockTime returns the latest block time packets time out against;
+	// without it no packet times out
+	blockTime func(ctx context.Context) (time.Time, error)
+	
+	// sentPackets caches the packets rebuilt from the packet commitments of
+	// this chain while they are outstanding, by channel and sequence
+	sentPackets map[string]IBCPacket
+	
+	// timeoutStep submits the timeout of a packet, by default as a bot
+	// transaction through broadcaster with the proof of proveUnreceived
+	timeoutStep     func(ctx context.Context, packet IBCPacket) error
+	proveUnreceived func(ctx context.Context, packet IBCPacket) (TimeoutProof, error)
+	broadcaster     TxBroadcaster
+	broadcastGate   func() error
+	
+	// chain is queried for the client heights proofs are verified at
+	chain *ChainQuerier
+	
+	// Relays in flight are drained on shutdown
+	drain *DrainTracker
+	
+	// costs records every relay and timeout transaction
+	costs *CostLedger
 }
 
 // IBCChannel represents an IBC channel
@@ -42,16 +130,35 @@ type IBCPacket struct {
 	Timestamp   time.Time
 	Retries     int
 	MaxRetries  int
+	
+	// RecvRelayed is set once the recv reached the counterparty; only the
+	// ack is left to relay
+	RecvRelayed bool
+	
+	// A packet sent on this chain, as in its send_packet event, with the
+	// commitment stored for it. The simulated packets of the relay loop have
+	// no commitment and are never timed out on chain.
+	SourcePort          string
+	CounterpartyPort    string
+	CounterpartyChannel string
+	TimeoutHeight       IBCHeight
+	TimeoutTimestamp    time.Time
+	Commitment          []byte
 }
 
 // NewIBCRelayer creates a new IBC relayer instance
 func NewIBCRelayer(config *BotConfig) *IBCRelayer {
-	return &IBCRelayer{
+	r := &IBCRelayer{
 		config:           config,
 		channels:         make(map[string]*IBCChannel),
 		packetQueue:      make([]IBCPacket, 0),
 		connectionHealth: make(map[string]bool),
+		sentPackets:      make(map[string]IBCPacket),
 	}
+	r.relayStep = r.simulateRelayStep
+	r.timeoutStep = r.submitTimeout
+	r.proveUnreceived = r.queryTimeoutProof
+	return r
 }
 
 // Initialize initializes the IBC relayer
@@ -133,13 +240,16 @@ func (r *IBCRelayer) Start(ctx context.Context) error {
 	log.Println("Starting IBC Relayer service...")
 	
 	// Start packet relaying
-	ticker := time.NewTicker(r.config.CheckInterval)
+	ticker := NewJitteredTicker(r.config.CheckInterval)
 	defer ticker.Stop()
 	
 	// Start health check ticker
-	healthTicker := time.NewTicker(30 * time.Second)
+	healthTicker := NewJitteredTicker(30 * time.Second)
 	defer healthTicker.Stop()
 	
+	// Timeouts are submitted on the same interval, independently of relays
+	go r.processTimeoutQueue(ctx)
+	
 	for {
 		select {
 		case <-ctx.Done():
@@ -147,7 +257,7 @@ func (r *IBCRelayer) Start(ctx context.Context) error {
 			return nil
 			
 		case <-ticker.C:
-			if err := r.relayPackets(); err != nil {
+			if err := r.relayPackets(ctx); err != nil {
 				log.Printf("IBC Relayer error: %v", err)
 			}
 			
@@ -160,7 +270,7 @@ func (r *IBCRelayer) Start(ctx context.Context) error {
 }
 
 // relayPackets handles packet relaying
-func (r *IBCRelayer) relayPackets() error {
+func (r *IBCRelayer) relayPackets(ctx context.Context) error {
 	log.Println("Checking for packets to relay...")
 	
 	// Query for new packets on all channels
@@ -180,7 +290,7 @@ func (r *IBCRelayer) relayPackets() error {
 	}
 	
 	// Process queued packets
-	if err := r.processPacketQueue(); err != nil {
+	if err := r.processPacketQueue(ctx); err != nil {
 		log.Printf("Error processing packet queue: %v", err)
 	}
 	
@@ -192,10 +302,13 @@ func (r *IBCRelayer) relayPackets() error {
 func (r *IBCRelayer) queryAndRelayPackets(channelID string) error {
 	channel := r.channels[channelID]
 	
-	// Simulate packet detection
-	if r.shouldCreatePacket(channel) {
+	// Simulate packet detection; once shutdown began packets are left for
+	// the next start to detect
+	if r.shouldCreatePacket(channel) && !r.drain.Closed() {
 		packet := r.createTestPacket(channelID)
+		r.queueMu.Lock()
 		r.packetQueue = append(r.packetQueue, packet)
+		r.queueMu.Unlock()
 		
 		log.Printf("Queued packet for channel %s (sequence %d)", channelID, packet.Sequence)
 		channel.PacketCount++
@@ -211,7 +324,8 @@ func (r *IBCRelayer) shouldCreatePacket(channel *IBCChannel) bool {
 	return time.Since(channel.LastPacket) > (5 * time.Minute)
 }
 
-// createTestPacket creates a test packet for demonstration
+// createTestPacket creates a test packet for demonstration. It is never
+// sent on chain, so it has no commitment to time out.
 func (r *IBCRelayer) createTestPacket(channelID string) IBCPacket {
 	channel := r.channels[channelID]
 	
@@ -225,60 +339,518 @@ func (r *IBCRelayer) createTestPacket(channelID string) IBCPacket {
 	}
 }
 
-// processPacketQueue processes the packet queue
-func (r *IBCRelayer) processPacketQueue() error {
-	if len(r.packetQueue) == 0 {
+// processPacketQueue processes the packet queue as one operation, whose
+// intent is the packets not relayed yet. Once shutdown began no further
+// packet is started and the pending ones are recorded for the next start.
+func (r *IBCRelayer) processPacketQueue(ctx context.Context) error {
+	r.queueMu.Lock()
+	queue := r.packetQueue
+	r.packetQueue = nil
+	r.queueMu.Unlock()
+	
+	queue = r.expirePackets(ctx, queue)
+	if len(queue) == 0 {
 		return nil
 	}
 	
-	log.Printf("Processing %d packets in queue", len(r.packetQueue))
+	op, err := r.drain.Begin(ctx, "relay_packets", fmt.Sprintf("%d queued packets", len(queue)), queue)
+	if err != nil {
+		r.recordPackets(queue)
+		return nil
+	}
+	defer op.Done()
+	
+	log.Printf("Processing %d packets in queue", len(queue))
 	
 	var remainingPackets []IBCPacket
+	progress := func(i int) {
+		pending := append(append([]IBCPacket(nil), remainingPackets...), queue[i:]...)
+		op.Update(fmt.Sprintf("relaying %s, %d packets pending", queue[i].describe(), len(pending)), pending)
+	}
 	
-	for _, packet := range r.packetQueue {
-		if err := r.relayPacket(packet); err != nil {
+	for i := range queue {
+		if r.drain.Closed() {
+			remainingPackets = append(remainingPackets, queue[i:]...)
+			break
+		}
+		progress(i)
+		
+		packet := &queue[i]
+		if err := r.relayPacket(op.Context(), packet, func() { progress(i) }); err != nil {
 			log.Printf("Failed to relay packet (channel %s, seq %d): %v", 
 				packet.ChannelID, packet.Sequence, err)
 			
-			// Retry logic
-			if packet.Retries < packet.MaxRetries {
-				packet.Retries++
-				remainingPackets = append(remainingPackets, packet)
-			} else {
-				log.Printf("Dropping packet after %d retries", packet.MaxRetries)
-			}
+			r.relayFailures++
+			remainingPackets = packet.retry(remainingPackets)
 		} else {
 			log.Printf("Successfully relayed packet (channel %s, seq %d)", 
 				packet.ChannelID, packet.Sequence)
 			r.relayCount++
 		}
 	}
+	op.Update(fmt.Sprintf("%d packets pending", len(remainingPackets)), remainingPackets)
+	
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
 	
-	r.packetQueue = remainingPackets
+	if r.drain.Closed() {
+		// Past the drain deadline the pending packets were already recorded
+		// as interrupted
+		if op.Context().Err() == nil {
+			r.recordPackets(remainingPackets)
+		}
+		return nil
+	}
+	r.packetQueue = append(remainingPackets, r.packetQueue...)
 	return nil
 }
 
-// relayPacket relays a single packet
-func (r *IBCRelayer) relayPacket(packet IBCPacket) error {
-	// Simulate packet relaying process
+// relayPacket relays the recv of a packet to the counterparty and its ack
+// back. progress is called once the recv was relayed, so that a shutdown
+// between both steps resumes with the ack. Each packet is a trace with a
+// span per step.
+func (r *IBCRelayer) relayPacket(ctx context.Context, packet *IBCPacket, progress func()) (err error) {
 	log.Printf("Relaying packet on channel %s...", packet.ChannelID)
 	
+	ctx, span := startSpan(ctx, "ibc.relay_packet",
+		traceChannel.String(packet.ChannelID),
+		traceSequence.Int64(int64(packet.Sequence)),
+		attribute.String("ibc.counterparty", r.getCounterparty(packet.ChannelID)),
+		attribute.Int("ibc.retries", packet.Retries))
+	defer func() { endSpan(span, err) }()
+	
 	// Check if channel is healthy
 	if !r.connectionHealth[packet.ChannelID] {
 		return fmt.Errorf("channel %s is unhealthy", packet.ChannelID)
 	}
 	
+	if !packet.RecvRelayed {
+		if err := r.tracedRelayStep(ctx, *packet, "recv"); err != nil {
+			return fmt.Errorf("recv: %w", err)
+		}
+		packet.RecvRelayed = true
+		progress()
+	}
+	
+	if err := r.tracedRelayStep(ctx, *packet, "ack"); err != nil {
+		return fmt.Errorf("ack: %w", err)
+	}
+	return nil
+}
+
+// tracedRelayStep submits a relay step in a span of its own. Each step is a
+// transaction; relayStep reports no fee, so relays are only counted.
+func (r *IBCRelayer) tracedRelayStep(ctx context.Context, packet IBCPacket, step string) error {
+	return traceStep(ctx, "ibc."+step, func(ctx context.Context, _ trace.Span) error {
+		if err := r.relayStep(ctx, packet, step); err != nil {
+			return err
+		}
+		r.costs.RecordTx(CostIBCRelay, TxResult{}, time.Now())
+		return nil
+	})
+}
+
+// simulateRelayStep simulates submitting a relay step
+func (r *IBCRelayer) simulateRelayStep(ctx context.Context, packet IBCPacket, step string) error {
 	// Simulate network delay
-	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-time.After(50 * time.Millisecond):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 	
 	// Simulate occasional failures
-	if r.relayCount > 0 && r.relayCount%10 == 0 {
+	if step == "recv" && r.relayCount > 0 && r.relayCount%10 == 0 {
 		return fmt.Errorf("simulated relay failure")
 	}
 	
 	return nil
 }
 
+// retry appends the packet to pending unless it used up its retries
+func (p *IBCPacket) retry(pending []IBCPacket) []IBCPacket {
+	if p.Retries >= p.MaxRetries {
+		log.Printf("Dropping packet %s after %d retries", p.describe(), p.MaxRetries)
+		return pending
+	}
+	p.Retries++
+	return append(pending, *p)
+}
+
+// packetTimeout returns how long after it was queued a simulated packet
+// whose recv was not relayed is dropped
+func (r *IBCRelayer) packetTimeout() time.Duration {
+	if r.config.IBCPacketTimeout > 0 {
+		return r.config.IBCPacketTimeout
+	}
+	return DefaultIBCPacketTimeout
+}
+
+// timeoutAt returns the time after which a simulated packet is dropped
+func (r *IBCRelayer) timeoutAt(packet IBCPacket) time.Time {
+	return packet.Timestamp.Add(r.packetTimeout())
+}
+
+// expirePackets drops the packets of queue whose recv was not relayed
+// within ibc_packet_timeout by the latest block time and returns the rest.
+// These are simulated packets, which were never sent on chain and so are
+// not timed out there; queueTimedOutPackets finds the packets to time out
+// from the commitments on chain. A packet whose recv was relayed cannot
+// time out any more, only its ack is left.
+func (r *IBCRelayer) expirePackets(ctx context.Context, queue []IBCPacket) []IBCPacket {
+	if r.blockTime == nil || len(queue) == 0 {
+		return queue
+	}
+	
+	now, err := r.blockTime(ctx)
+	if err != nil {
+		log.Printf("Not checking IBC packet timeouts, latest block time unavailable: %v", err)
+		return queue
+	}
+	
+	var live []IBCPacket
+	expired := 0
+	for _, packet := range queue {
+		if !packet.RecvRelayed && now.After(r.timeoutAt(packet)) {
+			expired++
+			continue
+		}
+		live = append(live, packet)
+	}
+	
+	if expired > 0 {
+		log.Printf("Dropped %d simulated IBC packets not relayed within %s", expired, r.packetTimeout())
+	}
+	return live
+}
+
+// queueTimedOutPackets queues the timeouts of the packets sent on the
+// channels in ibc_counterparties that timed out: their commitment is still
+// stored on this chain and the channel's client passed their timeout height
+// or the latest block their timeout timestamp. Each packet is rebuilt from
+// its send_packet event and must match its commitment.
+func (r *IBCRelayer) queueTimedOutPackets(ctx context.Context) {
+	if r.chain == nil || r.blockTime == nil || len(r.config.IBCCounterparties) == 0 {
+		return
+	}
+	now, err := r.blockTime(ctx)
+	if err != nil {
+		log.Printf("Not checking IBC packet timeouts, latest block time unavailable: %v", err)
+		return
+	}
+	
+	for channelID := range r.config.IBCCounterparties {
+		if err := r.queueChannelTimeouts(ctx, channelID, now); err != nil {
+			log.Printf("Not checking IBC packet timeouts of %s: %v", channelID, err)
+		}
+	}
+}
+
+// queueChannelTimeouts queues the timeouts of the packets sent on a channel
+// that timed out by now
+func (r *IBCRelayer) queueChannelTimeouts(ctx context.Context, channelID string, now time.Time) error {
+	commitments, err := r.chain.QueryPacketCommitments(ctx, IBCTransferPort, channelID)
+	if err != nil {
+		return err
+	}
+	
+	// Packets acknowledged or timed out since the last scan are forgotten
+	outstanding := make(map[string]bool)
+	for _, commitment := range commitments {
+		outstanding[fmt.Sprintf("%s/%d", channelID, commitment.Sequence)] = true
+	}
+	for key, packet := range r.sentPackets {
+		if packet.ChannelID == channelID && !outstanding[key] {
+			delete(r.sentPackets, key)
+		}
+	}
+	if len(commitments) == 0 {
+		return nil
+	}
+	
+	clientHeight, err := r.chain.QueryChannelClientHeight(ctx, IBCTransferPort, channelID)
+	if err != nil {
+		return err
+	}
+	
+	r.queueMu.Lock()
+	queued := make(map[string]bool)
+	for _, packet := range r.timeoutQueue {
+		queued[packet.describe()] = true
+	}
+	r.queueMu.Unlock()
+	
+	var expired []IBCPacket
+	for _, commitment := range commitments {
+		key := fmt.Sprintf("%s/%d", channelID, commitment.Sequence)
+		if queued[key] {
+			continue
+		}
+		packet, ok := r.sentPackets[key]
+		if !ok {
+			packet, err = r.chain.QuerySentPacket(ctx, IBCTransferPort, channelID, commitment.Sequence)
+			if err != nil {
+				log.Printf("Not timing out IBC packet %s: %v", key, err)
+				continue
+			}
+			if !bytes.Equal(packetCommitment(packet), commitment.Hash) {
+				log.Printf("Not timing out IBC packet %s: its send_packet event does not match its commitment", key)
+				continue
+			}
+			packet.Commitment = commitment.Hash
+			packet.MaxRetries = IBCTimeoutMaxRetries
+			r.sentPackets[key] = packet
+		}
+		if packet.timedOut(clientHeight, now) {
+			expired = append(expired, packet)
+		}
+	}
+	
+	if len(expired) > 0 {
+		r.queueMu.Lock()
+		r.timeoutQueue = append(r.timeoutQueue, expired...)
+		r.queueMu.Unlock()
+		log.Printf("Queued the timeouts of %d IBC packets sent on %s", len(expired), channelID)
+	}
+	return nil
+}
+
+// timedOut reports whether a packet sent on this chain timed out: the
+// counterparty height known to the channel's client reached its timeout
+// height, or the block time its timeout timestamp. The chain checks the
+// timestamp against the counterparty's block time at the proof height, so
+// a timeout sent before the client caught up is rejected and retried.
+func (p IBCPacket) timedOut(clientHeight IBCHeight, now time.Time) bool {
+	timeout := p.TimeoutHeight
+	if timeout.RevisionHeight > 0 && (clientHeight.RevisionNumber > timeout.RevisionNumber ||
+		clientHeight.RevisionNumber == timeout.RevisionNumber && clientHeight.RevisionHeight >= timeout.RevisionHeight) {
+		return true
+	}
+	return !p.TimeoutTimestamp.IsZero() && !now.Before(p.TimeoutTimestamp)
+}
+
+// packetCommitment computes the commitment ibc-go stores for a sent packet:
+// the hash of its timeout timestamp, timeout height and data hash
+func packetCommitment(packet IBCPacket) []byte {
+	var timestamp uint64
+	if !packet.TimeoutTimestamp.IsZero() {
+		timestamp = uint64(packet.TimeoutTimestamp.UnixNano())
+	}
+	dataHash := sha256.Sum256(packet.Data)
+	
+	buf := binary.BigEndian.AppendUint64(nil, timestamp)
+	buf = binary.BigEndian.AppendUint64(buf, packet.TimeoutHeight.RevisionNumber)
+	buf = binary.BigEndian.AppendUint64(buf, packet.TimeoutHeight.RevisionHeight)
+	hash := sha256.Sum256(append(buf, dataHash[:]...))
+	return hash[:]
+}
+
+// processTimeoutQueue queues the timeouts of the packets sent on chain and
+// submits the timeout queue on the interval packets are relayed on, until
+// ctx is done
+func (r *IBCRelayer) processTimeoutQueue(ctx context.Context) {
+	ticker := NewJitteredTicker(r.config.CheckInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.queueTimedOutPackets(ctx)
+			r.submitTimeouts(ctx)
+		}
+	}
+}
+
+// submitTimeouts submits the timeout of every packet in the timeout queue
+// as one operation, retrying failed ones like relays. Once shutdown began
+// the pending timeouts are recorded with the queued packets; on the next
+// start they time out again.
+func (r *IBCRelayer) submitTimeouts(ctx context.Context) {
+	r.queueMu.Lock()
+	queue := r.timeoutQueue
+	r.timeoutQueue = nil
+	r.queueMu.Unlock()
+	
+	if len(queue) == 0 {
+		return
+	}
+	
+	op, err := r.drain.Begin(ctx, "relay_packets", fmt.Sprintf("%d timed out packets", len(queue)), queue)
+	if err != nil {
+		r.recordPackets(queue)
+		return
+	}
+	defer op.Done()
+	
+	var remaining []IBCPacket
+	for i := range queue {
+		if r.drain.Closed() {
+			remaining = append(remaining, queue[i:]...)
+			break
+		}
+		pending := append(append([]IBCPacket(nil), remaining...), queue[i:]...)
+		op.Update(fmt.Sprintf("timing out %s, %d packets pending", queue[i].describe(), len(pending)), pending)
+		
+		packet := &queue[i]
+		err := traceStep(op.Context(), "ibc.timeout", func(ctx context.Context, _ trace.Span) error {
+			return r.timeoutStep(ctx, *packet)
+		})
+		if err != nil {
+			log.Printf("Failed to submit timeout of packet %s: %v", packet.describe(), err)
+			remaining = packet.retry(remaining)
+			continue
+		}
+		log.Printf("Submitted timeout of packet %s", packet.describe())
+		r.queueMu.Lock()
+		r.timeoutCount++
+		r.queueMu.Unlock()
+	}
+	op.Update(fmt.Sprintf("%d timed out packets pending", len(remaining)), remaining)
+	
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	
+	if r.drain.Closed() {
+		if op.Context().Err() == nil {
+			r.recordPackets(remaining)
+		}
+		return
+	}
+	r.timeoutQueue = append(remaining, r.timeoutQueue...)
+}
+
+// submitTimeout signs and broadcasts the MsgTimeout of a packet whose recv
+// never reached the counterparty, which refunds it on this chain. Only
+// packets rebuilt from their commitment on chain are timed out.
+func (r *IBCRelayer) submitTimeout(ctx context.Context, packet IBCPacket) error {
+	if len(packet.Commitment) == 0 {
+		return fmt.Errorf("packet %s has no commitment on chain", packet.describe())
+	}
+	if r.broadcaster == nil {
+		return errNoBroadcaster
+	}
+	if r.broadcastGate != nil {
+		if err := r.broadcastGate(); err != nil {
+			return err
+		}
+	}
+	proof, err := r.proveUnreceived(ctx, packet)
+	if err != nil {
+		return fmt.Errorf("failed to prove packet %s unreceived: %w", packet.describe(), err)
+	}
+	result, err := broadcastWithResult(ctx, r.broadcaster, BotTx{
+		Kind:                TxKindIBCTimeout,
+		Validator:           r.config.ValidatorAddress,
+		CreatedAt:           time.Now(),
+		SourcePort:          packet.SourcePort,
+		ChannelID:           packet.ChannelID,
+		Sequence:            packet.Sequence,
+		PacketData:          packet.Data,
+		TimeoutHeight:       packet.TimeoutHeight,
+		TimeoutTimestamp:    packet.TimeoutTimestamp,
+		CounterpartyPort:    proof.CounterpartyPort,
+		CounterpartyChannel: proof.CounterpartyChannel,
+		ProofUnreceived:     proof.Proof,
+		ProofHeight:         proof.Height,
+	})
+	if err != nil {
+		return err
+	}
+	r.costs.RecordTx(CostIBCRelay, result, time.Now())
+	return nil
+}
+
+// queryTimeoutProof proves on the counterparty of the packet's channel that
+// its receipt is absent at the latest height of the channel's client. That
+// height must be past the packet's timeout, which the client's updates by
+// any relayer reach. The counterparty end is the packet's destination.
+func (r *IBCRelayer) queryTimeoutProof(ctx context.Context, packet IBCPacket) (TimeoutProof, error) {
+	counterparty, ok := r.config.IBCCounterparties[packet.ChannelID]
+	if !ok {
+		return TimeoutProof{}, fmt.Errorf("no ibc_counterparties entry for %s", packet.ChannelID)
+	}
+	if r.chain == nil {
+		return TimeoutProof{}, fmt.Errorf("no chain querier configured")
+	}
+	if packet.CounterpartyChannel != counterparty.ChannelID || packet.CounterpartyPort != counterparty.port() {
+		return TimeoutProof{}, fmt.Errorf("packet %s was sent to %s/%s, not to the configured counterparty %s/%s",
+			packet.describe(), packet.CounterpartyPort, packet.CounterpartyChannel, counterparty.port(), counterparty.ChannelID)
+	}
+	
+	height, err := r.chain.QueryChannelClientHeight(ctx, IBCTransferPort, packet.ChannelID)
+	if err != nil {
+		return TimeoutProof{}, err
+	}
+	
+	// The state at a height is committed by the app hash of the next block
+	proof, err := NewChainQuerierForAPI(counterparty.API).QueryPacketReceiptAbsence(ctx, packet.CounterpartyPort, packet.CounterpartyChannel, packet.Sequence, height.RevisionHeight-1)
+	if err != nil {
+		return TimeoutProof{}, err
+	}
+	return TimeoutProof{
+		CounterpartyPort:    packet.CounterpartyPort,
+		CounterpartyChannel: packet.CounterpartyChannel,
+		Proof:               proof,
+		Height:              height,
+	}, nil
+}
+
+// describe names a packet in logs and intents
+func (p IBCPacket) describe() string {
+	return fmt.Sprintf("%s/%d", p.ChannelID, p.Sequence)
+}
+
+// recordPackets keeps packets that will not be relayed before the bot stops
+func (r *IBCRelayer) recordPackets(packets []IBCPacket) {
+	if len(packets) == 0 || r.drain == nil {
+		return
+	}
+	r.drain.Record("relay_packets", fmt.Sprintf("%d queued packets", len(packets)), packets)
+	log.Printf("Recorded %d unrelayed IBC packets for the next start", len(packets))
+}
+
+// Resume queues the packets the last shutdown left unrelayed. A packet whose
+// recv was relayed continues with the ack; relaying a recv twice is
+// harmless, the counterparty rejects it as redundant. Packets with a
+// commitment on chain go back to the timeout queue.
+func (r *IBCRelayer) Resume(intents []Intent) {
+	resumed := 0
+	for _, intent := range intents {
+		var packets []IBCPacket
+		if err := intent.Decode(&packets); err != nil {
+			log.Printf("Ignoring interrupted IBC relay %q: %v", intent.Description, err)
+			continue
+		}
+		r.queueMu.Lock()
+		for _, packet := range packets {
+			if len(packet.Commitment) > 0 {
+				r.timeoutQueue = append(r.timeoutQueue, packet)
+			} else {
+				r.packetQueue = append(r.packetQueue, packet)
+			}
+		}
+		r.queueMu.Unlock()
+		resumed += len(packets)
+	}
+	if resumed > 0 {
+		log.Printf("Resuming %d IBC packets left by the last shutdown", resumed)
+	}
+}
+
+// Stop records the queued packets for the next start; the relay in flight
+// is drained by the shutdown
+func (r *IBCRelayer) Stop() {
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	
+	r.recordPackets(append(r.packetQueue, r.timeoutQueue...))
+	r.packetQueue = nil
+	r.timeoutQueue = nil
+	log.Println("IBC Relayer stopped")
+}
+
 // checkConnectionHealth checks the health of all IBC connections
 func (r *IBCRelayer) checkConnectionHealth() error {
 	log.Println("Checking IBC connection health...")
@@ -393,7 +965,31 @@ func (r *IBCRelayer) GetStatus() map[string]interface{} {
 		"healthy_channels":   healthyChannels,
 		"last_relay_time":    r.lastRelayTime,
 		"relay_count":        r.relayCount,
-		"queued_packets":     len(r.packetQueue),
+		"relay_failures":     r.relayFailures,
+		"queued_packets":     r.queuedPackets(),
+		"timed_out_packets":  r.timedOutPackets(),
+		"timeout_count":      r.submittedTimeouts(),
 		"last_health_check":  r.lastHealthCheck,
 	}
-}
\ No newline at end of file
+}
+
+// queuedPackets returns the number of packets waiting to be relayed
+func (r *IBCRelayer) queuedPackets() int {
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	return len(r.packetQueue)
+}
+
+// timedOutPackets returns the number of packets waiting for their timeout
+func (r *IBCRelayer) timedOutPackets() int {
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	return len(r.timeoutQueue)
+}
+
+// submittedTimeouts returns the number of packet timeouts submitted
+func (r *IBCRelayer) submittedTimeouts() int64 {
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	return r.timeoutCount
+}
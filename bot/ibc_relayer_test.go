@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	coretypes "github.com/cometbft/cometbft/v2/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/v2/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// packetChain serves the packet commitments of channel-0, whose
+// counterparty end is channel-7, and the send_packet events of its packets
+type packetChain struct {
+	mu          sync.Mutex
+	blockTime   time.Time
+	commitments map[uint64][]byte
+	sent        map[uint64]IBCPacket
+	searches    int
+}
+
+func newPacketChain(blockTime time.Time) *packetChain {
+	return &packetChain{blockTime: blockTime, commitments: make(map[uint64][]byte), sent: make(map[uint64]IBCPacket)}
+}
+
+// send commits packet on channel-0 and records its send_packet event
+func (c *packetChain) send(packet IBCPacket) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	packet.SourcePort, packet.ChannelID = "transfer", "channel-0"
+	packet.CounterpartyPort, packet.CounterpartyChannel = "transfer", "channel-7"
+	c.commitments[packet.Sequence] = packetCommitment(packet)
+	c.sent[packet.Sequence] = packet
+}
+
+// settle deletes the commitment of an acknowledged or timed out packet
+func (c *packetChain) settle(sequence uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.commitments, sequence)
+}
+
+func (c *packetChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/ibc/core/channel/v1/channels/channel-0/ports/transfer/packet_commitments":
+		type commitment struct {
+			Sequence string `json:"sequence"`
+			Data     []byte `json:"data"`
+		}
+		var resp struct {
+			Commitments []commitment `json:"commitments"`
+		}
+		for sequence := uint64(1); sequence <= 10; sequence++ {
+			if hash, ok := c.commitments[sequence]; ok {
+				resp.Commitments = append(resp.Commitments, commitment{strconv.FormatUint(sequence, 10), hash})
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	case "/ibc/core/channel/v1/channels/channel-0/ports/transfer/client_state":
+		w.Write([]byte(`{"identified_client_state":{"client_id":"07-tendermint-0","client_state":{"latest_height":{"revision_number":"1","revision_height":"4200"}}}}`))
+	case "/cosmos/base/tendermint/v1beta1/blocks/latest":
+		fmt.Fprintf(w, `{"block":{"header":{"height":"10","time":%q}}}`, c.blockTime.Format(time.RFC3339Nano))
+	case "/cosmos/tx/v1beta1/txs":
+		c.searches++
+		var sequence uint64
+		for _, event := range r.URL.Query()["events"] {
+			fmt.Sscanf(event, "send_packet.packet_sequence='%d'", &sequence)
+		}
+		packet, ok := c.sent[sequence]
+		if !ok {
+			w.Write([]byte(`{"tx_responses":[]}`))
+			return
+		}
+		var timestamp int64
+		if !packet.TimeoutTimestamp.IsZero() {
+			timestamp = packet.TimeoutTimestamp.UnixNano()
+		}
+		type attribute struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}
+		attributes := []attribute{
+			{"packet_data", string(packet.Data)},
+			{"packet_data_hex", hex.EncodeToString(packet.Data)},
+			{"packet_timeout_height", fmt.Sprintf("%d-%d", packet.TimeoutHeight.RevisionNumber, packet.TimeoutHeight.RevisionHeight)},
+			{"packet_timeout_timestamp", strconv.FormatInt(timestamp, 10)},
+			{"packet_sequence", strconv.FormatUint(packet.Sequence, 10)},
+			{"packet_src_port", packet.SourcePort},
+			{"packet_src_channel", packet.ChannelID},
+			{"packet_dst_port", packet.CounterpartyPort},
+			{"packet_dst_channel", packet.CounterpartyChannel},
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"tx_responses": []interface{}{map[string]interface{}{
+				"events": []interface{}{map[string]interface{}{"type": "send_packet", "attributes": attributes}},
+			}},
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestIBCRelayerDropsExpiredSimulatedPackets(t *testing.T) {
+	sent := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	blockTime := sent.Add(DefaultIBCPacketTimeout + time.Second)
+
+	relayer := NewIBCRelayer(&BotConfig{ValidatorAddress: "gxrvaloper1ours"})
+	relayer.connectionHealth["channel-0"] = true
+	relayer.blockTime = func(context.Context) (time.Time, error) { return blockTime, nil }
+	relayer.relayStep = func(context.Context, IBCPacket, string) error { return nil }
+	var broadcast []BotTx
+	relayer.broadcaster = broadcasterFunc(func(_ context.Context, tx BotTx) error {
+		broadcast = append(broadcast, tx)
+		return nil
+	})
+	relayer.packetQueue = []IBCPacket{
+		{ChannelID: "channel-0", Sequence: 1, Timestamp: sent, MaxRetries: 3, Retries: 2},
+		{ChannelID: "channel-0", Sequence: 2, Timestamp: sent, MaxRetries: 3, RecvRelayed: true},
+		{ChannelID: "channel-0", Sequence: 3, Timestamp: blockTime, MaxRetries: 3},
+	}
+
+	// The simulated packet whose recv was never relayed is dropped, not
+	// timed out on chain; the others are relayed
+	relayer.processPacketQueue(context.Background())
+	relayer.submitTimeouts(context.Background())
+	if len(relayer.timeoutQueue) != 0 || len(relayer.packetQueue) != 0 || relayer.relayCount != 2 || len(broadcast) != 0 {
+		t.Fatalf("timeout queue = %+v, packet queue = %+v, relayed %d, broadcast %+v",
+			relayer.timeoutQueue, relayer.packetQueue, relayer.relayCount, broadcast)
+	}
+	if err := relayer.submitTimeout(context.Background(), IBCPacket{ChannelID: "channel-0", Sequence: 1}); err == nil || len(broadcast) != 0 {
+		t.Fatalf("timeout of a packet without commitment: %v", err)
+	}
+}
+
+func TestIBCRelayerTimesOutCommittedPackets(t *testing.T) {
+	blockTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	chain := newPacketChain(blockTime)
+	server := httptest.NewServer(chain)
+	defer server.Close()
+
+	// The client of channel-0 is at 1-4200
+	chain.send(IBCPacket{Sequence: 1, Data: []byte("by height"), TimeoutHeight: IBCHeight{1, 4100}})
+	chain.send(IBCPacket{Sequence: 2, Data: []byte("by timestamp"), TimeoutTimestamp: blockTime.Add(-time.Second)})
+	chain.send(IBCPacket{Sequence: 3, Data: []byte("live"), TimeoutHeight: IBCHeight{1, 5000}, TimeoutTimestamp: blockTime.Add(time.Hour)})
+	chain.send(IBCPacket{Sequence: 4, Data: []byte("forged"), TimeoutHeight: IBCHeight{1, 4100}})
+	chain.sent[4] = IBCPacket{Sequence: 4, Data: []byte("other"), TimeoutHeight: IBCHeight{1, 4100}, SourcePort: "transfer", ChannelID: "channel-0"}
+
+	relayer := NewIBCRelayer(&BotConfig{
+		ValidatorAddress:  "gxrvaloper1ours",
+		IBCCounterparties: map[string]IBCCounterparty{"channel-0": {API: "http://127.0.0.1:1", ChannelID: "channel-7"}},
+	})
+	relayer.chain = NewChainQuerierForAPI(server.URL)
+	relayer.blockTime = relayer.chain.QueryLatestBlockTime
+
+	// Only the packets past their timeout whose event matches the commitment
+	// are queued, once; matching events are searched once, the forged one
+	// on every scan
+	relayer.queueTimedOutPackets(context.Background())
+	relayer.queueTimedOutPackets(context.Background())
+	if len(relayer.timeoutQueue) != 2 || relayer.timeoutQueue[0].describe() != "channel-0/1" || relayer.timeoutQueue[1].describe() != "channel-0/2" {
+		t.Fatalf("timeout queue = %+v", relayer.timeoutQueue)
+	}
+	if chain.searches != 5 {
+		t.Fatalf("searched %d send_packet events, want 5", chain.searches)
+	}
+
+	var broadcast []BotTx
+	relayer.proveUnreceived = func(_ context.Context, packet IBCPacket) (TimeoutProof, error) {
+		return TimeoutProof{CounterpartyPort: packet.CounterpartyPort, CounterpartyChannel: packet.CounterpartyChannel, Proof: []byte("proof"), Height: IBCHeight{1, 4200}}, nil
+	}
+	relayer.broadcaster = broadcasterFunc(func(_ context.Context, tx BotTx) error {
+		broadcast = append(broadcast, tx)
+		return nil
+	})
+	relayer.submitTimeouts(context.Background())
+	if len(relayer.timeoutQueue) != 0 || relayer.submittedTimeouts() != 2 || len(broadcast) != 2 {
+		t.Fatalf("timeout queue = %+v, submitted %d, broadcast %+v", relayer.timeoutQueue, relayer.submittedTimeouts(), broadcast)
+	}
+	byHeight, byTimestamp := broadcast[0], broadcast[1]
+	if byHeight.Kind != TxKindIBCTimeout || byHeight.Validator != "gxrvaloper1ours" || byHeight.SourcePort != "transfer" ||
+		byHeight.ChannelID != "channel-0" || byHeight.Sequence != 1 || string(byHeight.PacketData) != "by height" ||
+		byHeight.TimeoutHeight != (IBCHeight{1, 4100}) || !byHeight.TimeoutTimestamp.IsZero() ||
+		byHeight.CounterpartyChannel != "channel-7" || string(byHeight.ProofUnreceived) != "proof" || byHeight.ProofHeight.RevisionHeight != 4200 {
+		t.Fatalf("timeout tx = %+v", byHeight)
+	}
+	if byTimestamp.TimeoutHeight != (IBCHeight{}) || !byTimestamp.TimeoutTimestamp.Equal(blockTime.Add(-time.Second)) {
+		t.Fatalf("timeout tx = %+v", byTimestamp)
+	}
+
+	// Once timed out on chain the packets are forgotten
+	chain.settle(1)
+	chain.settle(2)
+	relayer.queueTimedOutPackets(context.Background())
+	if len(relayer.timeoutQueue) != 0 || len(relayer.sentPackets) != 1 {
+		t.Fatalf("timeout queue = %+v, cached %+v", relayer.timeoutQueue, relayer.sentPackets)
+	}
+}
+
+func TestIBCRelayerDropsTimeoutAfterRetries(t *testing.T) {
+	relayer := NewIBCRelayer(&BotConfig{IBCPacketTimeout: time.Minute})
+	relayer.timeoutStep = func(context.Context, IBCPacket) error { return errors.New("node down") }
+	relayer.timeoutQueue = []IBCPacket{{ChannelID: "channel-1", Sequence: 4, MaxRetries: 2}}
+
+	for i := 0; i < 3; i++ {
+		relayer.submitTimeouts(context.Background())
+	}
+	if len(relayer.timeoutQueue) != 0 || relayer.submittedTimeouts() != 0 {
+		t.Fatalf("timeout queue = %+v", relayer.timeoutQueue)
+	}
+}
+
+func TestIBCRelayerKeepsPacketsWithoutBlockTime(t *testing.T) {
+	relayer := NewIBCRelayer(&BotConfig{})
+	relayer.blockTime = func(context.Context) (time.Time, error) { return time.Time{}, errors.New("rpc down") }
+	queue := []IBCPacket{{ChannelID: "channel-0", Sequence: 1, MaxRetries: 3}}
+
+	if live := relayer.expirePackets(context.Background(), queue); len(live) != 1 || len(relayer.timeoutQueue) != 0 {
+		t.Fatalf("live = %+v, timed out = %+v", live, relayer.timeoutQueue)
+	}
+}
+
+// syncNode records the transactions broadcast by the components of a
+// running bot service
+type syncNode struct {
+	client.CometRPC
+	mu  sync.Mutex
+	txs [][]byte
+}
+
+func (n *syncNode) BroadcastTxSync(_ context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTx, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.txs = append(n.txs, tx)
+	return &coretypes.ResultBroadcastTx{Hash: []byte{0xab, 0xcd}}, nil
+}
+
+func (n *syncNode) broadcast(typeURL string) [][]byte {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var txs [][]byte
+	for _, tx := range n.txs {
+		var raw txtypes.TxRaw
+		var body txtypes.TxBody
+		if raw.Unmarshal(tx) == nil && body.Unmarshal(raw.BodyBytes) == nil && len(body.Messages) == 1 && body.Messages[0].TypeUrl == typeURL {
+			txs = append(txs, body.Messages[0].Value)
+		}
+	}
+	return txs
+}
+
+func TestIBCTimeoutBroadcastFromStart(t *testing.T) {
+	counterparty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		key, _ := base64.StdEncoding.DecodeString(query.Get("data"))
+		if r.URL.Path != "/cosmos/base/tendermint/v1beta1/abci_query" || query.Get("path") != "store/ibc/key" ||
+			string(key) != "receipts/ports/transfer/channels/channel-7/sequences/3" || query.Get("height") != "4199" || query.Get("prove") != "true" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"value":null,"proof_ops":{"ops":[{"type":"ics23:iavl","data":%q}]},"height":"4199"}`, base64.StdEncoding.EncodeToString([]byte("nonexist")))
+	}))
+	defer counterparty.Close()
+	packets := newPacketChain(time.Now())
+	packets.send(IBCPacket{Sequence: 3, Data: []byte("data"), TimeoutHeight: IBCHeight{1, 4100}})
+	chain := httptest.NewServer(packets)
+	defer chain.Close()
+
+	clientCtx, err := newClientContext(&BotConfig{ChainID: "gxr-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(writeConfig(t, fmt.Sprintf(`chain_id: "gxr-1"
+chain_rpc: "tcp://127.0.0.1:1"
+chain_grpc: "127.0.0.1:1"
+chain_api: %q
+validator_address: %q
+validator_mnemonic: %q
+data_dir: %q
+monitoring_enabled: false
+price_oracle:
+  source: "mock"
+health_check_enabled: false
+ibc_enabled: true
+ibc_channels: ["channel-0"]
+ibc_counterparties:
+  channel-0:
+    api: %q
+    channel_id: "channel-7"
+`, chain.URL, testValidator(t, clientCtx), signingMnemonic, t.TempDir(), counterparty.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetOutboundLimiter(nil) })
+
+	bs, err := NewBotService(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := &syncNode{}
+	bs.clientCtx = bs.clientCtx.WithClient(node).WithAccountRetriever(&fakeAccounts{sequence: 1})
+
+	// Start returns once its components stop, here right away; the relayer
+	// then times out with the broadcaster Start installed
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := bs.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer bs.Stop()
+
+	bs.ibcRelayer.queueTimedOutPackets(context.Background())
+	bs.ibcRelayer.submitTimeouts(context.Background())
+
+	timeouts := node.broadcast(MsgTimeoutTypeURL)
+	if bs.ibcRelayer.submittedTimeouts() != 1 || len(timeouts) != 1 {
+		t.Fatalf("submitted %d timeouts, broadcast %d", bs.ibcRelayer.submittedTimeouts(), len(timeouts))
+	}
+	msg := protoFields(t, timeouts[0])
+	packet, height := protoFields(t, msg[1][0]), protoFields(t, msg[3][0])
+	if string(packet[3][0]) != "channel-0" || string(packet[5][0]) != "channel-7" || string(packet[6][0]) != "data" ||
+		packet[8] != nil || string(height[2][0]) != string(protowire.AppendVarint(nil, 4200)) {
+		t.Fatalf("packet %q at height %q", packet, height)
+	}
+	if timeoutHeight := protoFields(t, packet[7][0]); string(timeoutHeight[2][0]) != string(protowire.AppendVarint(nil, 4100)) {
+		t.Fatalf("packet timeout height = %q", timeoutHeight)
+	}
+	operator, _ := validatorAccountAddress(config.ValidatorAddress)
+	if proof := protoFields(t, msg[2][0]); string(proof[1][0]) != "nonexist" || string(msg[5][0]) != operator {
+		t.Fatalf("MsgTimeout fields = %q", msg)
+	}
+}
@@ -0,0 +1,645 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// IncidentsFile stores the recent incidents in the data directory
+	IncidentsFile = "incidents.json"
+	// DefaultIncidentWindow is how long after its last alert an incident
+	// still takes in related alerts
+	DefaultIncidentWindow = 15 * time.Minute
+	// DefaultIncidentIdleTimeout closes incidents whose components never
+	// reported a recovery
+	DefaultIncidentIdleTimeout = 6 * time.Hour
+	// MaxStoredIncidents is the number of incidents kept, oldest are dropped first
+	MaxStoredIncidents = 50
+	// MaxIncidentUpdates is the number of updates kept per incident
+	MaxIncidentUpdates = 100
+	// MaxListedClosedIncidents is the number of closed incidents /incidents lists
+	MaxListedClosedIncidents = 5
+)
+
+// Bot components alerts are correlated by
+const (
+	ComponentChainRPC          = "chain_rpc"
+	ComponentPriceFeed         = "price_feed"
+	ComponentValidatorMonitor  = "validator_monitor"
+	ComponentRebalancer        = "rebalancer"
+	ComponentRewardDistributor = "reward_distributor"
+	ComponentIBCRelayer        = "ibc_relayer"
+	ComponentDEXManager        = "dex_manager"
+)
+
+// componentDependencies maps each component to the components it needs to
+// work. When the chain RPC is down the price feed goes stale, the validator
+// monitor turns unhealthy and the rebalancer errors, so their alerts share
+// the root cause chain_rpc.
+var componentDependencies = map[string][]string{
+	ComponentChainRPC:          nil,
+	ComponentPriceFeed:         {ComponentChainRPC},
+	ComponentValidatorMonitor:  {ComponentChainRPC},
+	ComponentRewardDistributor: {ComponentChainRPC},
+	ComponentIBCRelayer:        {ComponentChainRPC},
+	ComponentDEXManager:        {ComponentChainRPC, ComponentPriceFeed},
+	ComponentRebalancer:        {ComponentPriceFeed},
+}
+
+// Incident resolutions
+const (
+	IncidentRecovered = "recovered"
+	IncidentIdle      = "idle"
+)
+
+// IncidentConfig configures the correlation of alerts into incidents
+type IncidentConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	Window      time.Duration `yaml:"window"`       // default 15m
+	IdleTimeout time.Duration `yaml:"idle_timeout"` // default 6h
+}
+
+// ValidateIncidents checks the incidents settings
+func ValidateIncidents(cfg IncidentConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Window < 0 || cfg.IdleTimeout < 0 {
+		return fmt.Errorf("incidents window and idle_timeout cannot be negative")
+	}
+	return nil
+}
+
+// IncidentUpdate is one alert of an incident
+type IncidentUpdate struct {
+	Number    int       `json:"number"`
+	Timestamp time.Time `json:"timestamp"`
+	Component string    `json:"component"`
+	Severity  string    `json:"severity"`
+	Title     string    `json:"title"`
+	Summary   string    `json:"summary"` // first line of the alert message
+}
+
+// Incident groups the alerts of components sharing a root cause
+type Incident struct {
+	ID         string    `json:"id"`
+	RootCause  string    `json:"root_cause"`
+	OpenedAt   time.Time `json:"opened_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	ClosedAt   time.Time `json:"closed_at"`
+	Resolution string    `json:"resolution,omitempty"`
+
+	// Components that alerted, and those of them not recovered yet
+	Components []string `json:"components"`
+	Down       []string `json:"down"`
+
+	// Alerts counts every alert; Updates keeps the last MaxIncidentUpdates
+	Alerts  int              `json:"alerts"`
+	Updates []IncidentUpdate `json:"updates"`
+}
+
+// Open reports whether the incident is still open
+func (inc Incident) Open() bool {
+	return inc.ClosedAt.IsZero()
+}
+
+// clone returns a copy that does not share slices with inc
+func (inc *Incident) clone() Incident {
+	c := *inc
+	c.Components = append([]string(nil), inc.Components...)
+	c.Down = append([]string(nil), inc.Down...)
+	c.Updates = append([]IncidentUpdate(nil), inc.Updates...)
+	return c
+}
+
+// IncidentTracker correlates alerts into incidents: alerts of components
+// sharing a root cause in componentDependencies within the window of each
+// other join one incident, which closes once every component that alerted
+// has recovered. It is shared by the alert dispatchers of all components and
+// safe for concurrent use.
+type IncidentTracker struct {
+	window      time.Duration
+	idleTimeout time.Duration
+	path        string
+
+	mu        sync.Mutex
+	incidents []*Incident // oldest first
+}
+
+// NewIncidentTracker loads the stored incidents from dataDir; a missing or
+// broken file starts empty. An empty dataDir keeps incidents in memory only.
+// Zero config values use the defaults.
+func NewIncidentTracker(cfg IncidentConfig, dataDir string) *IncidentTracker {
+	t := &IncidentTracker{
+		window:      cfg.Window,
+		idleTimeout: cfg.IdleTimeout,
+	}
+	if t.window <= 0 {
+		t.window = DefaultIncidentWindow
+	}
+	if t.idleTimeout <= 0 {
+		t.idleTimeout = DefaultIncidentIdleTimeout
+	}
+	if dataDir == "" {
+		return t
+	}
+
+	t.path = filepath.Join(dataDir, IncidentsFile)
+	incidents, err := LoadIncidents(dataDir)
+	if err != nil {
+		log.Printf("Ignoring unreadable incidents file %s: %v", t.path, err)
+		return t
+	}
+	for i := range incidents {
+		t.incidents = append(t.incidents, &incidents[i])
+	}
+	return t
+}
+
+// LoadIncidents reads the incidents stored in dataDir, oldest first
+func LoadIncidents(dataDir string) ([]Incident, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, IncidentsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var incidents []Incident
+	if err := json.Unmarshal(data, &incidents); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+// isIncidentComponent reports whether component is in the dependency graph
+func isIncidentComponent(component string) bool {
+	_, ok := componentDependencies[component]
+	return ok
+}
+
+// rootCauses returns the components without dependencies that component
+// transitively depends on, or the component itself if it has none
+func rootCauses(component string) []string {
+	if !isIncidentComponent(component) {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var roots []string
+	var walk func(c string)
+	walk = func(c string) {
+		if seen[c] {
+			return
+		}
+		seen[c] = true
+		if len(componentDependencies[c]) == 0 {
+			roots = append(roots, c)
+			return
+		}
+		for _, dependency := range componentDependencies[c] {
+			walk(dependency)
+		}
+	}
+	walk(component)
+
+	sort.Strings(roots)
+	return roots
+}
+
+// dependents returns the components whose root causes include root
+func dependents(root string) []string {
+	var components []string
+	for component := range componentDependencies {
+		if component != root && containsString(rootCauses(component), root) {
+			components = append(components, component)
+		}
+	}
+	sort.Strings(components)
+	return components
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// removeString returns list without s
+func removeString(list []string, s string) []string {
+	kept := list[:0]
+	for _, item := range list {
+		if item != s {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// addString returns list with s added, keeping it sorted
+func addString(list []string, s string) []string {
+	if containsString(list, s) {
+		return list
+	}
+	list = append(list, s)
+	sort.Strings(list)
+	return list
+}
+
+// incidentAlert reports whether an alert reports a failure that can open or
+// join an incident
+func incidentAlert(alert *Alert) bool {
+	switch alert.Type {
+	case AlertTypeWarning, AlertTypeError, AlertTypeCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// Observe adds a failure alert of component to the open incident sharing its
+// root cause within the window, or opens a new incident. It returns a copy
+// of the incident and the update the alert became; ok is false for alerts
+// that are not failures or come from a component outside the dependency graph.
+func (t *IncidentTracker) Observe(alert *Alert, component string, now time.Time) (incident Incident, update IncidentUpdate, ok bool) {
+	roots := rootCauses(component)
+	if len(roots) == 0 || !incidentAlert(alert) {
+		return Incident{}, IncidentUpdate{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current := t.openIncidentLocked(roots, now)
+	if current == nil {
+		current = &Incident{
+			ID:        t.newIDLocked(now),
+			RootCause: roots[0],
+			OpenedAt:  now,
+		}
+		t.incidents = append(t.incidents, current)
+		if len(t.incidents) > MaxStoredIncidents {
+			t.incidents = t.incidents[len(t.incidents)-MaxStoredIncidents:]
+		}
+	}
+
+	current.Alerts++
+	current.UpdatedAt = now
+	current.Components = addString(current.Components, component)
+	current.Down = addString(current.Down, component)
+
+	update = IncidentUpdate{
+		Number:    current.Alerts,
+		Timestamp: now,
+		Component: component,
+		Severity:  alert.Type.String(),
+		Title:     alert.Title,
+		Summary:   firstLine(alert.Message),
+	}
+	current.Updates = append(current.Updates, update)
+	if len(current.Updates) > MaxIncidentUpdates {
+		current.Updates = current.Updates[len(current.Updates)-MaxIncidentUpdates:]
+	}
+
+	t.saveLocked()
+	return current.clone(), update, true
+}
+
+// openIncidentLocked returns the newest open incident with one of roots as
+// root cause that was updated within the window. t.mu must be held.
+func (t *IncidentTracker) openIncidentLocked(roots []string, now time.Time) *Incident {
+	for i := len(t.incidents) - 1; i >= 0; i-- {
+		inc := t.incidents[i]
+		if inc.Open() && containsString(roots, inc.RootCause) && now.Sub(inc.UpdatedAt) <= t.window {
+			return inc
+		}
+	}
+	return nil
+}
+
+// newIDLocked returns an unused incident id for an incident opened at now.
+// t.mu must be held.
+func (t *IncidentTracker) newIDLocked(now time.Time) string {
+	base := "INC-" + now.UTC().Format("20060102-150405")
+	id := base
+	for n := 2; t.findLocked(id) != nil; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
+
+// findLocked returns the incident with id, if stored. t.mu must be held.
+func (t *IncidentTracker) findLocked(id string) *Incident {
+	for _, inc := range t.incidents {
+		if inc.ID == id {
+			return inc
+		}
+	}
+	return nil
+}
+
+// RecordHealth records whether component is healthy. A healthy component
+// counts as recovered in the open incidents it alerted in; one that turns
+// unhealthy again is waited for again. Returns copies of the incidents that
+// closed because all of their components recovered.
+func (t *IncidentTracker) RecordHealth(component string, healthy bool, now time.Time) []Incident {
+	if !isIncidentComponent(component) {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var closed []Incident
+	changed := false
+	for _, inc := range t.incidents {
+		if !inc.Open() || !containsString(inc.Components, component) {
+			continue
+		}
+
+		down := containsString(inc.Down, component)
+		switch {
+		case healthy && down:
+			inc.Down = removeString(inc.Down, component)
+			changed = true
+		case !healthy && !down:
+			inc.Down = addString(inc.Down, component)
+			changed = true
+		}
+
+		if len(inc.Down) == 0 {
+			inc.ClosedAt = now
+			inc.Resolution = IncidentRecovered
+			closed = append(closed, inc.clone())
+		}
+	}
+
+	if changed {
+		t.saveLocked()
+	}
+	return closed
+}
+
+// CloseIdle closes the open incidents without alerts for the idle timeout,
+// whose components never reported a recovery, and returns copies of them
+func (t *IncidentTracker) CloseIdle(now time.Time) []Incident {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var closed []Incident
+	for _, inc := range t.incidents {
+		if inc.Open() && now.Sub(inc.UpdatedAt) >= t.idleTimeout {
+			inc.ClosedAt = now
+			inc.Resolution = IncidentIdle
+			closed = append(closed, inc.clone())
+		}
+	}
+
+	if len(closed) > 0 {
+		t.saveLocked()
+	}
+	return closed
+}
+
+// Incidents returns copies of the stored incidents, oldest first
+func (t *IncidentTracker) Incidents() []Incident {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	incidents := make([]Incident, 0, len(t.incidents))
+	for _, inc := range t.incidents {
+		incidents = append(incidents, inc.clone())
+	}
+	return incidents
+}
+
+// Status returns the incident counters and the ids of the open incidents
+func (t *IncidentTracker) Status() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	open := make([]string, 0)
+	for _, inc := range t.incidents {
+		if inc.Open() {
+			open = append(open, inc.ID)
+		}
+	}
+
+	return map[string]interface{}{
+		"window":       t.window.String(),
+		"idle_timeout": t.idleTimeout.String(),
+		"open":         open,
+		"stored":       len(t.incidents),
+	}
+}
+
+// saveLocked atomically writes the incidents file. t.mu must be held.
+func (t *IncidentTracker) saveLocked() {
+	if t.path == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(t.incidents, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode incidents: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		log.Printf("Failed to save incidents: %v", err)
+		return
+	}
+
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("Failed to save incidents: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		os.Remove(tmp)
+		log.Printf("Failed to save incidents: %v", err)
+	}
+}
+
+// firstLine returns the first non-empty line of s
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// codeList formats components as Markdown code spans, e.g. `chain_rpc`, `rebalancer`
+func codeList(components []string) string {
+	quoted := make([]string, len(components))
+	for i, component := range components {
+		quoted[i] = "`" + component + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// incidentOpenedSummary is prepended to the alert that opens an incident
+func incidentOpenedSummary(inc Incident, window time.Duration) string {
+	summary := fmt.Sprintf("🔗 Incident `%s` opened. Likely root cause: `%s`.", inc.ID, inc.RootCause)
+	if affected := dependents(inc.RootCause); len(affected) > 0 {
+		summary += fmt.Sprintf("\nAlerts from %s within %s are added to it as updates.", codeList(affected), shortDuration(window))
+	}
+	return summary
+}
+
+// formatIncidentUpdate formats an alert joining an open incident as one line
+func formatIncidentUpdate(alert *Alert) string {
+	line := fmt.Sprintf("🔗 `%s` #%d %s `%s` %s", alert.Incident, alert.IncidentUpdate,
+		alert.Type.Emoji(), alert.Component, alert.Timestamp.Format("15:04:05"))
+	if alert.Title != "" {
+		line += fmt.Sprintf("\n*%s*", alert.Title)
+	}
+	if summary := firstLine(alert.Message); summary != "" {
+		if len(summary) > MaxMetadataLineLength {
+			summary = balanceMarkdown(cutAtRune(summary, MaxMetadataLineLength-len("…"))) + "…"
+		}
+		line += "\n" + summary
+	}
+	return line
+}
+
+// newIncidentClosedAlert builds the message sent when an incident closes
+func newIncidentClosedAlert(inc Incident, now time.Time) *Alert {
+	alert := &Alert{
+		ID:        fmt.Sprintf("incident-closed-%s-%d", inc.ID, now.UnixNano()),
+		Type:      AlertTypeSuccess,
+		Priority:  AlertPriorityMedium,
+		Title:     "Incident Resolved",
+		Message:   fmt.Sprintf("🔗 Incident `%s` resolved after %s: %s recovered.", inc.ID, inc.ClosedAt.Sub(inc.OpenedAt).Round(time.Second), codeList(inc.Components)),
+		Timestamp: now,
+		Incident:  inc.ID,
+		Metadata: map[string]interface{}{
+			"incident":   inc.ID,
+			"alerts":     inc.Alerts,
+			"root_cause": inc.RootCause,
+		},
+	}
+	if inc.Resolution == IncidentIdle {
+		alert.Type = AlertTypeInfo
+		alert.Title = "Incident Closed"
+		alert.Message = fmt.Sprintf("🔗 Incident `%s` closed after %s without alerts; no recovery reported by %s.",
+			inc.ID, inc.ClosedAt.Sub(inc.UpdatedAt).Round(time.Minute), codeList(inc.Down))
+	}
+	return alert
+}
+
+// describeIncident summarizes an incident on one line
+func describeIncident(inc Incident, now time.Time) string {
+	if inc.Open() {
+		return fmt.Sprintf("%s root %s, %d alerts, open for %s, waiting for %s",
+			inc.ID, inc.RootCause, inc.Alerts, now.Sub(inc.OpenedAt).Round(time.Second), strings.Join(inc.Down, ", "))
+	}
+	return fmt.Sprintf("%s root %s, %d alerts, %s after %s",
+		inc.ID, inc.RootCause, inc.Alerts, inc.Resolution, inc.ClosedAt.Sub(inc.OpenedAt).Round(time.Second))
+}
+
+// incidentsReply answers the /incidents command with the open incidents and
+// the most recently closed ones
+func (ta *TelegramAlert) incidentsReply(now time.Time) string {
+	tracker := ta.incidents.Load()
+	if tracker == nil {
+		return "Incident tracking is not enabled"
+	}
+
+	var open, closed []string
+	incidents := tracker.Incidents()
+	for i := len(incidents) - 1; i >= 0; i-- {
+		line := "• `" + describeIncident(incidents[i], now) + "`"
+		if incidents[i].Open() {
+			open = append(open, line)
+		} else if len(closed) < MaxListedClosedIncidents {
+			closed = append(closed, line)
+		}
+	}
+
+	lines := []string{"🔗 *Incidents*"}
+	if len(open) == 0 {
+		lines = append(lines, "No open incidents")
+	} else {
+		lines = append(lines, fmt.Sprintf("Open (%d):", len(open)))
+		lines = append(lines, open...)
+	}
+	if len(closed) > 0 {
+		lines = append(lines, "", "Recently closed:")
+		lines = append(lines, closed...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// createIncidentsCmd creates the incidents command
+func createIncidentsCmd() *cobra.Command {
+	var openOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "incidents [id]",
+		Short: "List the stored alert incidents, or show the updates of one",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, _ := cmd.Flags().GetString("config")
+			config, err := LoadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			incidents, err := LoadIncidents(config.DataDir)
+			if err != nil {
+				return fmt.Errorf("failed to read incidents: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			now := time.Now()
+			if len(args) == 1 {
+				for _, inc := range incidents {
+					if inc.ID != args[0] {
+						continue
+					}
+					fmt.Fprintln(out, describeIncident(inc, now))
+					for _, update := range inc.Updates {
+						fmt.Fprintf(out, "  #%d %s %-8s %-18s %s: %s\n", update.Number,
+							update.Timestamp.Format("2006-01-02 15:04:05"), update.Severity, update.Component, update.Title, update.Summary)
+					}
+					return nil
+				}
+				return fmt.Errorf("incident %s not found in %s", args[0], config.DataDir)
+			}
+
+			listed := 0
+			for i := len(incidents) - 1; i >= 0; i-- {
+				if openOnly && !incidents[i].Open() {
+					continue
+				}
+				fmt.Fprintln(out, describeIncident(incidents[i], now))
+				listed++
+			}
+			if listed == 0 {
+				fmt.Fprintln(out, "No incidents")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&openOnly, "open", false, "Only list open incidents")
+
+	return cmd
+}
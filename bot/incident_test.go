@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newIncidentTestAlert(t *testing.T, recorder *telegramRecorder, tracker *IncidentTracker, component string) *TelegramAlert {
+	ta := newOnCallTestAlert(t, recorder)
+	ta.onCall = nil
+	ta.alertQueue = make(chan *Alert, AlertQueueSize)
+	ta.SetIncidentTracker(tracker, component)
+	return ta
+}
+
+func TestIncidentCascadeIsOneIncident(t *testing.T) {
+	dataDir := t.TempDir()
+	tracker := NewIncidentTracker(IncidentConfig{Enabled: true}, dataDir)
+	recorder := &telegramRecorder{}
+	ta := newIncidentTestAlert(t, recorder, tracker, "")
+	rebalancerAlerts := newIncidentTestAlert(t, recorder, tracker, ComponentRebalancer)
+
+	// The chain RPC goes down and every component depending on it fails,
+	// alerting through the dispatcher it owns
+	now := time.Now()
+	ta.handleAlert(&Alert{Type: AlertTypeCritical, Component: ComponentChainRPC, Title: "Chain RPC Down", Message: "connection refused", Timestamp: now})
+	ta.handleAlert(&Alert{Type: AlertTypeWarning, Component: ComponentPriceFeed, Title: "Price Stale", Message: "price is 12m old", Timestamp: now})
+	ta.handleAlert(newBotAlert(ComponentValidatorMonitor, "flapping", "changed health 4 times in 10m", now))
+	rebalancerAlerts.handleAlert(&Alert{Type: AlertTypeError, Title: "Alert", Message: "🔄 Rebalancer State Change\n\nState: error", Timestamp: now})
+	ta.handleAlert(newBotAlert(ComponentRewardDistributor, "error", "allocation query failed", now))
+
+	incidents := tracker.Incidents()
+	if len(incidents) != 1 {
+		t.Fatalf("%d incidents, want 1: %+v", len(incidents), incidents)
+	}
+	inc := incidents[0]
+	if inc.RootCause != ComponentChainRPC || inc.Alerts != 5 || len(inc.Updates) != 5 || !inc.Open() {
+		t.Fatalf("incident = %+v", inc)
+	}
+	if got := strings.Join(inc.Down, ","); got != "chain_rpc,price_feed,rebalancer,reward_distributor,validator_monitor" {
+		t.Fatalf("components down = %s", got)
+	}
+
+	// One full alert opening the incident, then compact updates referring to it
+	messages := recorder.take()
+	if len(messages) != 5 {
+		t.Fatalf("%d messages sent, want 5", len(messages))
+	}
+	opening := messages[0].Text
+	if !strings.Contains(opening, "Incident `"+inc.ID+"` opened. Likely root cause: `chain_rpc`") ||
+		!strings.Contains(opening, "connection refused") || !strings.Contains(opening, "• incident: "+inc.ID) {
+		t.Fatalf("opening alert = %q", opening)
+	}
+	for i, msg := range messages[1:] {
+		if !strings.HasPrefix(msg.Text, "🔗 `"+inc.ID+"` #") || strings.Contains(msg.Text, "*Details:*") {
+			t.Fatalf("update %d = %q", i+2, msg.Text)
+		}
+	}
+	if !strings.Contains(messages[3].Text, "#4 ❌ `rebalancer`") || !strings.Contains(messages[3].Text, "🔄 Rebalancer State Change") {
+		t.Fatalf("rebalancer update = %q", messages[3].Text)
+	}
+
+	// Alerts of unrelated components and non-failures are not correlated
+	ta.handleAlert(newHalvingAlert(2, "cycle started", "", now))
+	ta.handleAlert(&Alert{Type: AlertTypeError, Title: "Bot Transaction Dropped", Timestamp: now})
+	if messages := recorder.take(); len(messages) != 2 || strings.Contains(messages[0].Text+messages[1].Text, inc.ID) {
+		t.Fatalf("unrelated alerts = %+v", messages)
+	}
+
+	// /incidents lists the open incident
+	reply, ok := ta.commandReply("-100100", "/incidents", now.Add(time.Minute))
+	if !ok || !strings.Contains(reply, "Open (1):") || !strings.Contains(reply, inc.ID+" root chain_rpc, 5 alerts") {
+		t.Fatalf("/incidents reply = %q", reply)
+	}
+
+	// The incident closes once all involved components recovered; one that
+	// fails again is waited for again
+	recovered := now.Add(10 * time.Minute)
+	for _, component := range []string{ComponentChainRPC, ComponentPriceFeed, ComponentValidatorMonitor, ComponentRewardDistributor} {
+		ta.RecordComponentHealth(component, true, recovered)
+	}
+	ta.RecordComponentHealth(ComponentPriceFeed, false, recovered)
+	ta.RecordComponentHealth(ComponentRebalancer, true, recovered)
+	ta.RecordComponentHealth(ComponentDEXManager, true, recovered)
+	if len(ta.alertQueue) != 0 {
+		t.Fatalf("incident closed while the price feed is down")
+	}
+
+	ta.RecordComponentHealth(ComponentPriceFeed, true, recovered)
+	if len(ta.alertQueue) != 1 {
+		t.Fatalf("%d alerts queued, want the resolution", len(ta.alertQueue))
+	}
+	ta.handleAlert(<-ta.alertQueue)
+	messages = recorder.take()
+	if len(messages) != 1 || !strings.Contains(messages[0].Text, "Incident `"+inc.ID+"` resolved after 10m0s") {
+		t.Fatalf("resolution = %+v", messages)
+	}
+
+	// Incidents survive a restart and are listed by gxr-bot incidents
+	reloaded := NewIncidentTracker(IncidentConfig{}, dataDir).Incidents()
+	if len(reloaded) != 1 || reloaded[0].Open() || reloaded[0].Resolution != IncidentRecovered || len(reloaded[0].Updates) != 5 {
+		t.Fatalf("reloaded incidents = %+v", reloaded)
+	}
+
+	configPath := writeConfig(t, legacyConfig+"data_dir: "+dataDir+"\n")
+	var out bytes.Buffer
+	cmd := CreateRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"incidents", inc.ID, "--config", configPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 6 || !strings.Contains(lines[0], "recovered after 10m0s") {
+		t.Fatalf("gxr-bot incidents %s = %q", inc.ID, out.String())
+	}
+}
+
+func TestIncidentWindowAndIdleTimeout(t *testing.T) {
+	tracker := NewIncidentTracker(IncidentConfig{Window: 5 * time.Minute, IdleTimeout: time.Hour}, "")
+	start := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	failure := &Alert{Type: AlertTypeError, Title: "Failure"}
+
+	first, _, ok := tracker.Observe(failure, ComponentRebalancer, start)
+	if !ok || first.ID != "INC-20261017-080000" || first.RootCause != ComponentChainRPC {
+		t.Fatalf("opened %+v", first)
+	}
+
+	// Within the window of the last alert related alerts keep joining
+	tracker.Observe(failure, ComponentDEXManager, start.Add(4*time.Minute))
+	joined, update, _ := tracker.Observe(failure, ComponentIBCRelayer, start.Add(8*time.Minute))
+	if joined.ID != first.ID || update.Number != 3 {
+		t.Fatalf("joined %s as update %d", joined.ID, update.Number)
+	}
+
+	// Past the window a new incident opens; unknown components and
+	// informational alerts are not tracked
+	later, _, _ := tracker.Observe(failure, ComponentChainRPC, start.Add(14*time.Minute))
+	if later.ID == first.ID {
+		t.Fatalf("alert after the window joined %s", first.ID)
+	}
+	if _, _, ok := tracker.Observe(failure, "telegram_alert", start); ok {
+		t.Fatalf("unknown component tracked")
+	}
+	if _, _, ok := tracker.Observe(&Alert{Type: AlertTypeInfo}, ComponentChainRPC, start); ok {
+		t.Fatalf("info alert tracked")
+	}
+
+	// Incidents without a reported recovery close after the idle timeout
+	closed := tracker.CloseIdle(start.Add(68 * time.Minute))
+	if len(closed) != 1 || closed[0].ID != first.ID || closed[0].Resolution != IncidentIdle {
+		t.Fatalf("closed %+v", closed)
+	}
+	alert := newIncidentClosedAlert(closed[0], start.Add(68*time.Minute))
+	if alert.Title != "Incident Closed" || !strings.Contains(alert.Message, "no recovery reported by `dex_manager`, `ibc_relayer`, `rebalancer`") {
+		t.Fatalf("idle close alert = %+v", alert)
+	}
+	if status := tracker.Status(); len(status["open"].([]string)) != 1 || status["stored"] != 2 {
+		t.Fatalf("status = %v", status)
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// InstanceLeaseTTL is how long a lease stays valid without renewal
+	InstanceLeaseTTL = 2 * time.Minute
+	// InstanceLeaseRenewInterval is how often the holder renews its lease
+	InstanceLeaseRenewInterval = 30 * time.Second
+
+	// leaseGuardWait is how long a bot waits for another one to finish
+	// updating a lease
+	leaseGuardWait = 5 * time.Second
+	// leaseGuardStale is the age after which the guard of a bot that died
+	// while updating a lease is removed
+	leaseGuardStale = 30 * time.Second
+)
+
+// InstanceLeaseInfo is the content of a validator lease file
+type InstanceLeaseInfo struct {
+	Validator  string    `json:"validator"`
+	InstanceID string    `json:"instance_id"`
+	Role       string    `json:"role,omitempty"`
+	PID        int       `json:"pid"`
+	Hostname   string    `json:"hostname"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// InstanceLease is a held lease on a validator. Unlike the instance lockfile,
+// which guards one data directory by PID, the lease is keyed by validator
+// address and expires unless renewed, so bots with different data
+// directories, or on hosts sharing the lease directory, exclude each other.
+type InstanceLease struct {
+	path string
+	info InstanceLeaseInfo
+	ttl  time.Duration
+}
+
+// leasePath returns the lease file of a validator in dir
+func leasePath(dir, validator string) string {
+	return filepath.Join(dir, "lease-"+strings.ToLower(validator)+".json")
+}
+
+// AcquireInstanceLease takes the validator's lease in dir. An expired lease,
+// or one already held by this instance, is taken over; a live lease of
+// another instance yields a DuplicateInstanceError.
+func AcquireInstanceLease(dir, validator, instanceID string, ttl time.Duration, now time.Time) (*InstanceLease, error) {
+	return AcquireInstanceLeaseAs(dir, validator, instanceID, "", ttl, now)
+}
+
+// AcquireInstanceLeaseAs is AcquireInstanceLease for an instance of a
+// failover pair. A primary also takes over the live lease of a promoted
+// standby, which steps down when its renewal fails.
+func AcquireInstanceLeaseAs(dir, validator, instanceID, role string, ttl time.Duration, now time.Time) (*InstanceLease, error) {
+	if dir == "" {
+		dir = DefaultDataDir
+	}
+	if ttl <= 0 {
+		ttl = InstanceLeaseTTL
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lease directory: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	lease := &InstanceLease{
+		path: leasePath(dir, validator),
+		ttl:  ttl,
+		info: InstanceLeaseInfo{
+			Validator:  validator,
+			InstanceID: instanceID,
+			Role:       role,
+			PID:        os.Getpid(),
+			Hostname:   hostname,
+			AcquiredAt: now.UTC(),
+		},
+	}
+
+	unlock, err := lockLease(lease.path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	existing, err := readInstanceLease(lease.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Taking over unreadable lease %s: %v", lease.path, err)
+	}
+	reclaim := role == RolePrimary && existing.Role == RoleStandby
+	if err == nil && existing.InstanceID != instanceID && now.Before(existing.ExpiresAt) && !reclaim {
+		return nil, leaseConflict(lease.path, existing, now)
+	}
+
+	if err := lease.write(now); err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// Renew extends the lease. It fails with a DuplicateInstanceError when
+// another instance took the lease over after it expired.
+func (l *InstanceLease) Renew(now time.Time) error {
+	unlock, err := lockLease(l.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	holder, err := readInstanceLease(l.path)
+	if err == nil && holder.InstanceID != l.info.InstanceID {
+		return leaseConflict(l.path, holder, now)
+	}
+	return l.write(now)
+}
+
+// Release removes the lease file if it is still held by this instance
+func (l *InstanceLease) Release() error {
+	unlock, err := lockLease(l.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	holder, err := readInstanceLease(l.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if holder.InstanceID != l.info.InstanceID {
+		return nil
+	}
+	return os.Remove(l.path)
+}
+
+// write atomically replaces the lease file with a lease expiring ttl after now
+func (l *InstanceLease) write(now time.Time) error {
+	l.info.RenewedAt = now.UTC()
+	l.info.ExpiresAt = now.Add(l.ttl).UTC()
+
+	data, err := json.Marshal(l.info)
+	if err != nil {
+		return fmt.Errorf("failed to encode lease: %w", err)
+	}
+
+	tmp := fmt.Sprintf("%s.%s.tmp", l.path, l.info.InstanceID)
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write lease: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write lease: %w", err)
+	}
+	return nil
+}
+
+// lockLease serializes the check and update of the lease at path across bots
+// by exclusively creating a guard file next to it, as AcquireInstanceLock
+// does for the lockfile. The returned function removes the guard.
+func lockLease(path string) (func(), error) {
+	guard := path + ".lock"
+	deadline := time.Now().Add(leaseGuardWait)
+	for {
+		file, err := os.OpenFile(guard, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(guard) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock lease: %w", err)
+		}
+
+		if info, err := os.Stat(guard); err == nil && time.Since(info.ModTime()) > leaseGuardStale {
+			log.Printf("Removing stale lease guard %s", guard)
+			os.Remove(guard)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock lease: %s held for over %s", guard, leaseGuardWait)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readInstanceLease reads a lease file
+func readInstanceLease(path string) (InstanceLeaseInfo, error) {
+	var info InstanceLeaseInfo
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+
+	if err := json.Unmarshal(data, &info); err != nil {
+		return info, fmt.Errorf("invalid lease: %w", err)
+	}
+
+	return info, nil
+}
+
+// leaseConflict describes a lease held by another instance
+func leaseConflict(path string, holder InstanceLeaseInfo, now time.Time) error {
+	return &DuplicateInstanceError{
+		Source: "lease",
+		Detail: fmt.Sprintf("%s is held by instance %s (PID %d on %s), renewed %s ago",
+			path, holder.InstanceID, holder.PID, holder.Hostname,
+			now.Sub(holder.RenewedAt).Round(time.Second)),
+	}
+}
+
+// renewInstanceLease renews the lease until ctx is done. If another instance
+// has taken it over, the bot drops to read-only and alerts the operator.
+func (bs *BotService) renewInstanceLease(ctx context.Context) {
+	ticker := time.NewTicker(InstanceLeaseRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if bs.checkInstanceLease(time.Now()) {
+				return
+			}
+		}
+	}
+}
+
+// checkInstanceLease renews the lease once and reports whether it was lost
+func (bs *BotService) checkInstanceLease(now time.Time) bool {
+	bs.mu.RLock()
+	lease := bs.instanceLease
+	bs.mu.RUnlock()
+	if lease == nil {
+		// Released by a standby stepping down
+		return true
+	}
+	err := lease.Renew(now)
+	if err == nil {
+		return false
+	}
+
+	var duplicate *DuplicateInstanceError
+	if !errors.As(err, &duplicate) {
+		// A transient write failure; the lease is retried on the next tick
+		log.Printf("Failed to renew instance lease: %v", err)
+		bs.recordError("instance_lease", err.Error())
+		return false
+	}
+
+	log.Printf("Instance lease lost: %v", err)
+	if bs.config.IsStandby() {
+		bs.demote("the primary took over the validator lease")
+		return true
+	}
+	bs.enterReadOnly("another instance holds the validator lease")
+	if bs.telegramAlert != nil {
+		bs.telegramAlert.SendEmergencyAlert("Bot Instance Lease Lost", err.Error(), map[string]interface{}{
+			MetadataValidatorAddress: bs.config.ValidatorAddress,
+			"instance_id":            bs.instanceID,
+		})
+	}
+	return true
+}
+
+// enterReadOnly stops this instance from sending transactions while
+// monitoring continues: no heartbeats, no slashing reports and no
+// price-driven rebalancing. reason completes the log line.
+func (bs *BotService) enterReadOnly(reason string) {
+	bs.mu.Lock()
+	bs.readOnly = true
+	bs.mu.Unlock()
+
+	if bs.validatorMonitor != nil {
+		bs.validatorMonitor.mu.Lock()
+		bs.validatorMonitor.broadcastQueue = nil
+		bs.validatorMonitor.mu.Unlock()
+	}
+	if bs.rebalancer != nil {
+		if err := bs.rebalancer.PausePriceMonitoring(); err != nil {
+			log.Printf("Rebalancer not paused: %v", err)
+		}
+	}
+	log.Printf("Continuing in read-only mode: %s", reason)
+}
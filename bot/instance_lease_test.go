@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const leaseValidator = "gxrvaloper1leasetest"
+
+func TestInstanceLeaseContended(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Unix(1700000000, 0)
+
+	first, err := AcquireInstanceLease(dir, leaseValidator, "first", time.Minute, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second instance is refused while the lease is live
+	_, err = AcquireInstanceLease(dir, leaseValidator, "second", time.Minute, now.Add(30*time.Second))
+	var duplicate *DuplicateInstanceError
+	if !errors.As(err, &duplicate) || duplicate.Source != "lease" {
+		t.Fatalf("expected lease conflict, got %v", err)
+	}
+
+	// Renewal keeps it live past the original expiry
+	if err := first.Renew(now.Add(50 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AcquireInstanceLease(dir, leaseValidator, "second", time.Minute, now.Add(90*time.Second)); !errors.As(err, &duplicate) {
+		t.Fatalf("renewed lease was taken over: %v", err)
+	}
+
+	// Other validators have their own lease
+	if _, err := AcquireInstanceLease(dir, "gxrvaloper1other", "second", time.Minute, now); err != nil {
+		t.Fatalf("lease of another validator: %v", err)
+	}
+
+	// Once the holder stops renewing, the lease expires and is taken over
+	second, err := AcquireInstanceLease(dir, leaseValidator, "second", time.Minute, now.Add(111*time.Second))
+	if err != nil {
+		t.Fatalf("expired lease not taken over: %v", err)
+	}
+	if err := first.Renew(now.Add(120 * time.Second)); !errors.As(err, &duplicate) {
+		t.Fatalf("renewing a lost lease = %v, want lease conflict", err)
+	}
+
+	// Releasing a lost lease leaves the new holder's lease alone
+	if err := first.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if holder, err := readInstanceLease(leasePath(dir, leaseValidator)); err != nil || holder.InstanceID != "second" {
+		t.Fatalf("lease holder = %+v, %v", holder, err)
+	}
+	if err := second.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(leasePath(dir, leaseValidator)); !os.IsNotExist(err) {
+		t.Fatal("lease should be removed on release")
+	}
+}
+
+func TestInstanceLeaseAcquireRace(t *testing.T) {
+	now := time.Now()
+	for round := 0; round < 50; round++ {
+		dir := t.TempDir()
+
+		// Two bots start at once; exactly one of them may take the lease
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		errs := make([]error, 2)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				_, errs[i] = AcquireInstanceLease(dir, leaseValidator, []string{"first", "second"}[i], time.Minute, now)
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		var duplicate *DuplicateInstanceError
+		acquired := 0
+		for _, err := range errs {
+			if err == nil {
+				acquired++
+			} else if !errors.As(err, &duplicate) {
+				t.Fatalf("round %d: %v", round, err)
+			}
+		}
+		if acquired != 1 {
+			t.Fatalf("round %d: %d instances acquired the lease", round, acquired)
+		}
+		if _, err := os.Stat(leasePath(dir, leaseValidator) + ".lock"); !os.IsNotExist(err) {
+			t.Fatalf("round %d: lease guard left behind", round)
+		}
+	}
+}
+
+func TestInstanceLeaseWaitsForGuard(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	path := leasePath(dir, leaseValidator)
+
+	// Another bot is between reading the free lease and writing its own
+	unlock, err := lockLease(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan error, 1)
+	go func() {
+		_, err := AcquireInstanceLease(dir, leaseValidator, "second", time.Minute, now)
+		acquired <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	first := &InstanceLease{path: path, ttl: time.Minute, info: InstanceLeaseInfo{Validator: leaseValidator, InstanceID: "first"}}
+	if err := first.write(now); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+
+	var duplicate *DuplicateInstanceError
+	if err := <-acquired; !errors.As(err, &duplicate) {
+		t.Fatalf("lease taken while another bot was writing it: %v", err)
+	}
+	if holder, err := readInstanceLease(path); err != nil || holder.InstanceID != "first" {
+		t.Fatalf("lease holder = %+v, %v", holder, err)
+	}
+}
+
+func TestInstanceLeaseStaleGuard(t *testing.T) {
+	dir := t.TempDir()
+	guard := filepath.Join(dir, "lease-"+leaseValidator+".json.lock")
+	if err := os.WriteFile(guard, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-2 * leaseGuardStale)
+	if err := os.Chtimes(guard, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	// The guard of a bot that died mid-update does not block the lease
+	if _, err := AcquireInstanceLease(dir, leaseValidator, "first", time.Minute, time.Now()); err != nil {
+		t.Fatalf("stale guard blocked the lease: %v", err)
+	}
+}
+
+func TestAcquireInstanceContendedLease(t *testing.T) {
+	leaseDir := t.TempDir()
+	newService := func() *BotService {
+		return &BotService{
+			config: &BotConfig{
+				ValidatorAddress: leaseValidator,
+				DataDir:          t.TempDir(),
+				InstanceLeaseDir: leaseDir,
+			},
+			healthStatus:       make(map[string]bool),
+			protocolCompatible: true,
+			instanceID:         NewInstanceID(),
+			validatorMonitor:   &ValidatorMonitor{validators: make(map[string]*ValidatorStatus), botHeartbeats: make(map[string]time.Time)},
+		}
+	}
+
+	// Different data directories, so only the shared lease can tell them apart
+	holder := newService()
+	if err := holder.AcquireInstance(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	defer holder.releaseInstance()
+
+	bs := newService()
+	err := bs.AcquireInstance(context.Background(), false)
+	var duplicate *DuplicateInstanceError
+	if !errors.As(err, &duplicate) || duplicate.Source != "lease" {
+		t.Fatalf("expected refusal on the held lease, got %v", err)
+	}
+	if bs.instanceLock != nil || bs.instanceLease != nil {
+		t.Fatal("refused instance must release its lock and lease")
+	}
+
+	bs = newService()
+	if err := bs.AcquireInstance(context.Background(), true); err != nil {
+		t.Fatalf("--allow-duplicate should keep monitoring running: %v", err)
+	}
+	if !bs.isReadOnly() || bs.instanceLease != nil {
+		t.Fatal("duplicate instance must run read-only without the lease")
+	}
+	bs.releaseInstance()
+
+	if lease, err := readInstanceLease(leasePath(leaseDir, leaseValidator)); err != nil || lease.InstanceID != holder.instanceID {
+		t.Fatalf("holder lost its lease: %+v, %v", lease, err)
+	}
+}
+
+func TestLostLeaseSwitchesToReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	queue := NewBroadcastQueue(nil, nil, "", 0)
+
+	lease, err := AcquireInstanceLease(dir, leaseValidator, "self", time.Minute, now.Add(-2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs := &BotService{
+		config:             &BotConfig{ValidatorAddress: leaseValidator},
+		healthStatus:       make(map[string]bool),
+		protocolCompatible: true,
+		instanceID:         "self",
+		instanceLease:      lease,
+		validatorMonitor:   &ValidatorMonitor{validators: make(map[string]*ValidatorStatus), botHeartbeats: make(map[string]time.Time), broadcastQueue: queue},
+	}
+
+	// Renewal succeeds while nobody else holds the lease
+	if bs.checkInstanceLease(now.Add(-90 * time.Second)) {
+		t.Fatal("renewable lease reported lost")
+	}
+
+	// Another instance takes the lease over after it expired
+	if _, err := AcquireInstanceLease(dir, leaseValidator, "other", time.Minute, now); err != nil {
+		t.Fatal(err)
+	}
+	if !bs.checkInstanceLease(now) {
+		t.Fatal("lease taken over by another instance not reported lost")
+	}
+	if !bs.isReadOnly() || bs.validatorMonitor.broadcastQueue != nil {
+		t.Fatal("instance without the lease must stop broadcasting")
+	}
+	if bs.emitHeartbeat(context.Background()) {
+		t.Fatal("instance without the lease must not send heartbeats")
+	}
+}
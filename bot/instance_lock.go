@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+const (
+	// DefaultDataDir is the default persistence directory of the bot
+	DefaultDataDir = "./data"
+	// InstanceLockFile is the lockfile name inside the data directory
+	InstanceLockFile = "gxr-bot.lock"
+	// HeartbeatMemoPrefix marks heartbeat transactions and carries the instance id
+	HeartbeatMemoPrefix = "gxr-bot-heartbeat"
+	// HeartbeatSearchLimit is the number of recent validator transactions inspected
+	HeartbeatSearchLimit = 20
+)
+
+// InstanceLockInfo is the content of the instance lockfile
+type InstanceLockInfo struct {
+	PID        int       `json:"pid"`
+	StartTime  time.Time `json:"start_time"`
+	InstanceID string    `json:"instance_id"`
+}
+
+// InstanceLock is a held instance lockfile
+type InstanceLock struct {
+	path string
+	info InstanceLockInfo
+
+	// predecessor is the instance id of a stale lock taken over at startup
+	predecessor string
+}
+
+// DuplicateInstanceError reports another live bot instance for the same validator
+type DuplicateInstanceError struct {
+	Source string
+	Detail string
+}
+
+func (e *DuplicateInstanceError) Error() string {
+	return fmt.Sprintf("duplicate bot instance detected (%s): %s", e.Source, e.Detail)
+}
+
+// NewInstanceID returns a random identifier for this bot process
+func NewInstanceID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// AcquireInstanceLock creates the lockfile in dataDir. A lockfile left behind
+// by a process that is no longer running is taken over; a lockfile held by a
+// live process yields a DuplicateInstanceError.
+func AcquireInstanceLock(dataDir, instanceID string) (*InstanceLock, error) {
+	if dataDir == "" {
+		dataDir = DefaultDataDir
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	lock := &InstanceLock{
+		path: filepath.Join(dataDir, InstanceLockFile),
+		info: InstanceLockInfo{
+			PID:        os.Getpid(),
+			StartTime:  time.Now().UTC(),
+			InstanceID: instanceID,
+		},
+	}
+
+	data, err := json.Marshal(lock.info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+
+	// Two attempts: the second follows removal of a stale lockfile
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(lock.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, writeErr := file.Write(data)
+			closeErr := file.Close()
+			if writeErr != nil || closeErr != nil {
+				os.Remove(lock.path)
+				return nil, fmt.Errorf("failed to write lockfile: %v", errors.Join(writeErr, closeErr))
+			}
+			return lock, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lockfile: %w", err)
+		}
+
+		existing, readErr := readInstanceLock(lock.path)
+		if readErr == nil && existing.PID != os.Getpid() && processAlive(existing.PID) {
+			return nil, &DuplicateInstanceError{
+				Source: "lockfile",
+				Detail: fmt.Sprintf("%s is held by PID %d (instance %s) since %s",
+					lock.path, existing.PID, existing.InstanceID, existing.StartTime.Format(time.RFC3339)),
+			}
+		}
+
+		// Stale or unreadable lockfile: remember its owner and take over
+		if readErr == nil {
+			lock.predecessor = existing.InstanceID
+		}
+		if err := os.Remove(lock.path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale lockfile: %w", err)
+		}
+	}
+
+	return nil, &DuplicateInstanceError{Source: "lockfile", Detail: "lockfile was re-created by another process during startup"}
+}
+
+// readInstanceLock reads a lockfile
+func readInstanceLock(path string) (InstanceLockInfo, error) {
+	var info InstanceLockInfo
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+
+	if err := json.Unmarshal(data, &info); err != nil {
+		return info, fmt.Errorf("invalid lockfile: %w", err)
+	}
+
+	return info, nil
+}
+
+// Predecessor returns the instance id of the stale lock that was taken over, if any
+func (l *InstanceLock) Predecessor() string {
+	return l.predecessor
+}
+
+// Release removes the lockfile if it is still owned by this instance
+func (l *InstanceLock) Release() error {
+	existing, err := readInstanceLock(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if existing.InstanceID != l.info.InstanceID {
+		return nil
+	}
+
+	return os.Remove(l.path)
+}
+
+// HeartbeatMemo builds the memo embedded in heartbeat transactions
+func HeartbeatMemo(instanceID string, t time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", HeartbeatMemoPrefix, instanceID, t.Unix())
+}
+
+// StandbyHeartbeatMemo builds the heartbeat memo of a promoted standby. The
+// role suffix lets a returning primary take over instead of refusing to start.
+func StandbyHeartbeatMemo(instanceID string, t time.Time) string {
+	return HeartbeatMemo(instanceID, t) + ":" + RoleStandby
+}
+
+// ParseHeartbeatMemo extracts the instance id and time from a heartbeat memo
+func ParseHeartbeatMemo(memo string) (string, time.Time, bool) {
+	parts := strings.Split(memo, ":")
+	if len(parts) == 4 && parts[3] == RoleStandby {
+		parts = parts[:3]
+	}
+	if len(parts) != 3 || parts[0] != HeartbeatMemoPrefix || parts[1] == "" {
+		return "", time.Time{}, false
+	}
+
+	unix, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	return parts[1], time.Unix(unix, 0), true
+}
+
+// isStandbyHeartbeat reports whether memo is a heartbeat of a promoted standby
+func isStandbyHeartbeat(memo string) bool {
+	_, _, ok := ParseHeartbeatMemo(memo)
+	return ok && strings.HasSuffix(memo, ":"+RoleStandby)
+}
+
+// DetectHeartbeatConflict checks whether the latest heartbeat on chain was sent
+// by another instance that still looks alive. Heartbeats from this instance or
+// from the predecessor whose stale lock was taken over are not conflicts, nor
+// are those of a promoted standby, which steps down once the primary is back.
+func DetectHeartbeatConflict(memo, instanceID, predecessor string, now time.Time) error {
+	senderID, sentAt, ok := ParseHeartbeatMemo(memo)
+	if !ok || senderID == instanceID || (predecessor != "" && senderID == predecessor) || isStandbyHeartbeat(memo) {
+		return nil
+	}
+
+	age := now.Sub(sentAt)
+	if age >= BotHeartbeatTimeout {
+		return nil
+	}
+
+	return &DuplicateInstanceError{
+		Source: "chain",
+		Detail: fmt.Sprintf("instance %s sent a heartbeat %s ago", senderID, age.Round(time.Second)),
+	}
+}
+
+// validatorAccountAddress converts a validator operator address to its account address
+func validatorAccountAddress(operatorAddress string) (string, error) {
+	hrp, data, err := bech32.DecodeAndConvert(operatorAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid validator address: %w", err)
+	}
+
+	return bech32.ConvertAndEncode(strings.TrimSuffix(hrp, "valoper"), data)
+}
+
+// QueryLatestHeartbeatMemo returns the memo of the most recent heartbeat
+// transaction sent by account, the operator account or the authz bot key,
+// or "" if none is found
+func (cq *ChainQuerier) QueryLatestHeartbeatMemo(ctx context.Context, account string) (string, error) {
+	memos, err := cq.QueryHeartbeatMemos(ctx, account)
+	if err != nil || len(memos) == 0 {
+		return "", err
+	}
+	return memos[0], nil
+}
+
+// QueryHeartbeatMemos returns the heartbeat memos among the last
+// HeartbeatSearchLimit transactions sent by account, newest first
+func (cq *ChainQuerier) QueryHeartbeatMemos(ctx context.Context, account string) ([]string, error) {
+	params := url.Values{}
+	params.Set("events", fmt.Sprintf("message.sender='%s'", account))
+	params.Set("order_by", "ORDER_BY_DESC")
+	params.Set("pagination.limit", strconv.Itoa(HeartbeatSearchLimit))
+
+	var resp struct {
+		Txs []struct {
+			Body struct {
+				Memo string `json:"memo"`
+			} `json:"body"`
+		} `json:"txs"`
+	}
+
+	if err := cq.getJSON(ctx, "/cosmos/tx/v1beta1/txs?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	var memos []string
+	for _, tx := range resp.Txs {
+		if strings.HasPrefix(tx.Body.Memo, HeartbeatMemoPrefix+":") {
+			memos = append(memos, tx.Body.Memo)
+		}
+	}
+
+	return memos, nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+func writeLockfile(t *testing.T, dir string, info InstanceLockInfo) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, InstanceLockFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// deadPID returns the PID of a process that has already exited
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run helper process: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestAcquireInstanceLockTakesOverStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	writeLockfile(t, dir, InstanceLockInfo{PID: deadPID(t), StartTime: time.Now().Add(-time.Hour), InstanceID: "old"})
+
+	lock, err := AcquireInstanceLock(dir, "new")
+	if err != nil {
+		t.Fatalf("stale lockfile should be taken over: %v", err)
+	}
+	if lock.Predecessor() != "old" {
+		t.Fatalf("predecessor = %q, want old", lock.Predecessor())
+	}
+
+	info, err := readInstanceLock(filepath.Join(dir, InstanceLockFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.PID != os.Getpid() || info.InstanceID != "new" {
+		t.Fatalf("lockfile not rewritten: %+v", info)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, InstanceLockFile)); !os.IsNotExist(err) {
+		t.Fatal("lockfile should be removed on release")
+	}
+}
+
+func TestAcquireInstanceLockDetectsLiveInstance(t *testing.T) {
+	dir := t.TempDir()
+	writeLockfile(t, dir, InstanceLockInfo{PID: os.Getppid(), StartTime: time.Now(), InstanceID: "other"})
+
+	_, err := AcquireInstanceLock(dir, "new")
+	var duplicate *DuplicateInstanceError
+	if !errors.As(err, &duplicate) || duplicate.Source != "lockfile" {
+		t.Fatalf("expected lockfile conflict, got %v", err)
+	}
+
+	// The live instance's lockfile must be left alone
+	info, err := readInstanceLock(filepath.Join(dir, InstanceLockFile))
+	if err != nil || info.InstanceID != "other" {
+		t.Fatalf("live lockfile was modified: %+v, %v", info, err)
+	}
+}
+
+func TestDetectHeartbeatConflict(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	fresh := now.Add(-time.Minute)
+	old := now.Add(-BotHeartbeatTimeout - time.Minute)
+
+	testCases := []struct {
+		name     string
+		memo     string
+		conflict bool
+	}{
+		{"no heartbeat", "", false},
+		{"unrelated memo", "delegate", false},
+		{"own heartbeat", HeartbeatMemo("self", fresh), false},
+		{"predecessor heartbeat", HeartbeatMemo("previous", fresh), false},
+		{"expired foreign heartbeat", HeartbeatMemo("other", old), false},
+		{"fresh foreign heartbeat", HeartbeatMemo("other", fresh), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := DetectHeartbeatConflict(tc.memo, "self", "previous", now)
+			if tc.conflict != (err != nil) {
+				t.Fatalf("conflict = %v, want %v", err, tc.conflict)
+			}
+		})
+	}
+}
+
+func TestAcquireInstanceRefusesOnChainConflict(t *testing.T) {
+	addr := make([]byte, 20)
+	valoper, err := bech32.ConvertAndEncode("gxrvaloper", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, _ := bech32.ConvertAndEncode("gxr", addr)
+
+	memo := HeartbeatMemo("other", time.Now().Add(-time.Minute))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cosmos/tx/v1beta1/txs" || r.URL.Query().Get("events") != "message.sender='"+account+"'" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"txs": []map[string]interface{}{
+				{"body": map[string]interface{}{"memo": "unrelated"}},
+				{"body": map[string]interface{}{"memo": memo}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	newService := func() *BotService {
+		config := &BotConfig{ChainAPI: server.URL, ValidatorAddress: valoper, DataDir: t.TempDir()}
+		return &BotService{
+			config:             config,
+			healthStatus:       make(map[string]bool),
+			protocolCompatible: true,
+			instanceID:         NewInstanceID(),
+			chainQuerier:       NewChainQuerier(config),
+			validatorMonitor:   &ValidatorMonitor{validators: make(map[string]*ValidatorStatus), botHeartbeats: make(map[string]time.Time)},
+		}
+	}
+
+	bs := newService()
+	if err := bs.AcquireInstance(context.Background(), false); err == nil {
+		t.Fatal("expected refusal when another instance is heartbeating")
+	}
+	if _, err := os.Stat(filepath.Join(bs.config.DataDir, InstanceLockFile)); !os.IsNotExist(err) {
+		t.Fatal("lockfile must be released after refusing to start")
+	}
+
+	bs = newService()
+	if err := bs.AcquireInstance(context.Background(), true); err != nil {
+		t.Fatalf("--allow-duplicate should keep monitoring running: %v", err)
+	}
+	if !bs.isReadOnly() {
+		t.Fatal("duplicate instance must run read-only")
+	}
+	if bs.emitHeartbeat(context.Background()) {
+		t.Fatal("read-only instance must not send heartbeats")
+	}
+}
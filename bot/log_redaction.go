@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// RedactedPlaceholder replaces secret values in log output
+	RedactedPlaceholder = "[REDACTED]"
+	// MinRedactedSecretLength is the shortest value masked; shorter values
+	// would mask ordinary words and numbers in every log line
+	MinRedactedSecretLength = 6
+
+	// Defaults of log file rotation
+	DefaultLogMaxSizeMB  = 50
+	DefaultLogMaxAge     = 7 * 24 * time.Hour
+	DefaultLogMaxBackups = 5
+)
+
+// LogRotationConfig bounds the log file. The file is rotated when it would
+// grow past max_size_mb or was started more than max_age ago; max_backups
+// rotated files are kept.
+type LogRotationConfig struct {
+	MaxSizeMB  int           `yaml:"max_size_mb"` // default 50
+	MaxAge     time.Duration `yaml:"max_age"`     // default 168h
+	MaxBackups int           `yaml:"max_backups"` // default 5
+}
+
+// ValidateLogRotation checks the log rotation settings
+func ValidateLogRotation(cfg LogRotationConfig) error {
+	if cfg.MaxSizeMB < 0 || cfg.MaxAge < 0 || cfg.MaxBackups < 0 {
+		return fmt.Errorf("log_rotation settings cannot be negative")
+	}
+	return nil
+}
+
+// SensitiveValues returns the configured values of the config fields tagged
+// sensitive:"true", including those of nested sections
+func SensitiveValues(config *BotConfig) []string {
+	var values []string
+	collectSensitiveValues(reflect.ValueOf(config).Elem(), &values)
+	return values
+}
+
+func collectSensitiveValues(v reflect.Value, values *[]string) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get("sensitive") == "true" && v.Field(i).Kind() == reflect.String {
+				if value := v.Field(i).String(); value != "" {
+					*values = append(*values, value)
+				}
+				continue
+			}
+			collectSensitiveValues(v.Field(i), values)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectSensitiveValues(v.Index(i), values)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			collectSensitiveValues(v.MapIndex(key), values)
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			collectSensitiveValues(v.Elem(), values)
+		}
+	}
+}
+
+// Redactor masks secret values in text
+type Redactor struct {
+	mu       sync.RWMutex
+	secrets  []string
+	replacer *strings.Replacer
+}
+
+// NewRedactor creates a redactor masking the given secrets
+func NewRedactor(secrets ...string) *Redactor {
+	r := &Redactor{}
+	r.Add(secrets...)
+	return r
+}
+
+// Add masks further secrets. Values shorter than MinRedactedSecretLength are
+// ignored. A mnemonic is masked as a whole and with its words joined by any
+// single whitespace, as it may have been reformatted.
+func (r *Redactor) Add(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, secret := range secrets {
+		secret = strings.TrimSpace(secret)
+		if len(secret) < MinRedactedSecretLength {
+			continue
+		}
+		r.secrets = append(r.secrets, secret)
+		if words := strings.Fields(secret); len(words) > 1 {
+			r.secrets = append(r.secrets, strings.Join(words, " "))
+		}
+	}
+
+	// Longer secrets first, so one containing another is masked whole
+	sort.Slice(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+	pairs := make([]string, 0, 2*len(r.secrets))
+	for _, secret := range r.secrets {
+		pairs = append(pairs, secret, RedactedPlaceholder)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// Redact returns text with every secret replaced by RedactedPlaceholder
+func (r *Redactor) Redact(text string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.replacer == nil {
+		return text
+	}
+	return r.replacer.Replace(text)
+}
+
+// RedactingWriter redacts everything written before passing it on. The log
+// package writes each entry with a single Write, so a secret is never split
+// across writes.
+type RedactingWriter struct {
+	redactor *Redactor
+	out      io.Writer
+}
+
+// NewRedactingWriter creates a writer redacting into out
+func NewRedactingWriter(redactor *Redactor, out io.Writer) *RedactingWriter {
+	return &RedactingWriter{redactor: redactor, out: out}
+}
+
+// Write implements io.Writer; it reports len(p) written on success
+func (w *RedactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, w.redactor.Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// RotatingFile is a log file rotated by size and age. Rotated files are
+// renamed to <file>.<timestamp> next to it.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	now        func() time.Time
+
+	file    *os.File
+	size    int64
+	started time.Time
+}
+
+// OpenRotatingFile opens path for appending with the given rotation limits
+func OpenRotatingFile(path string, cfg LogRotationConfig) (*RotatingFile, error) {
+	if cfg.MaxSizeMB == 0 {
+		cfg.MaxSizeMB = DefaultLogMaxSizeMB
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = DefaultLogMaxAge
+	}
+	if cfg.MaxBackups == 0 {
+		cfg.MaxBackups = DefaultLogMaxBackups
+	}
+
+	f := &RotatingFile{
+		path:       path,
+		maxSize:    int64(cfg.MaxSizeMB) * 1024 * 1024,
+		maxAge:     cfg.MaxAge,
+		maxBackups: cfg.MaxBackups,
+		now:        time.Now,
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the log file, continuing an existing one. Its modification
+// time stands in for when it was started, which is not recorded.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.started = f.now()
+	if f.size > 0 {
+		f.started = info.ModTime()
+	}
+	return nil
+}
+
+// Write implements io.Writer, rotating first when p would exceed the size
+// limit or the file is older than the age limit
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, fmt.Errorf("log file %s is closed", f.path)
+	}
+	if f.size > 0 && (f.size+int64(len(p)) > f.maxSize || f.now().Sub(f.started) >= f.maxAge) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate renames the current file to a timestamped backup, prunes the
+// oldest backups and starts a new file
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	f.file = nil
+
+	backup := f.path + "." + f.now().UTC().Format("20060102-150405.000")
+	if err := os.Rename(f.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	f.pruneBackups()
+
+	return f.open()
+}
+
+// pruneBackups removes all but the newest maxBackups rotated files
+func (f *RotatingFile) pruneBackups() {
+	backups, err := f.Backups()
+	if err != nil || len(backups) <= f.maxBackups {
+		return
+	}
+	for _, backup := range backups[:len(backups)-f.maxBackups] {
+		if err := os.Remove(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to remove old log file %s: %v\n", backup, err)
+		}
+	}
+}
+
+// Backups returns the rotated files, oldest first
+func (f *RotatingFile) Backups() ([]string, error) {
+	backups, err := filepath.Glob(f.path + ".[0-9]*")
+	if err != nil {
+		return nil, err
+	}
+	// The timestamp suffix sorts chronologically
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// Close closes the log file
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// SetupLogging sends the log output through a redactor masking the
+// config's sensitive values, to stderr and, when log_file is set, to the
+// rotated log file. The log file is returned for closing, nil without one.
+func SetupLogging(config *BotConfig) (*RotatingFile, error) {
+	redactor := NewRedactor(SensitiveValues(config)...)
+
+	var out io.Writer = os.Stderr
+	var file *RotatingFile
+	if config.LogFile != "" {
+		var err error
+		file, err = OpenRotatingFile(expandHome(config.LogFile), config.LogRotation)
+		if err != nil {
+			return nil, err
+		}
+		out = io.MultiWriter(os.Stderr, file)
+	}
+
+	log.SetOutput(NewRedactingWriter(redactor, out))
+	return file, nil
+}
+
+// ScrubLog redacts the secrets from the log at path, line by line, writing
+// to output or replacing the file when output is empty. Returns the number
+// of lines changed.
+func ScrubLog(path, output string, redactor *Redactor) (int, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open log: %w", err)
+	}
+	defer in.Close()
+
+	target := output
+	if target == "" {
+		target = path
+	}
+	tmp := target + ".scrub.tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create scrubbed log: %w", err)
+	}
+
+	changed := 0
+	reader := bufio.NewReader(in)
+	writer := bufio.NewWriter(out)
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" {
+			scrubbed := redactor.Redact(line)
+			if scrubbed != line {
+				changed++
+			}
+			if _, err := writer.WriteString(scrubbed); err != nil {
+				out.Close()
+				os.Remove(tmp)
+				return 0, fmt.Errorf("failed to write scrubbed log: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			out.Close()
+			os.Remove(tmp)
+			return 0, fmt.Errorf("failed to read log: %w", readErr)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return 0, fmt.Errorf("failed to write scrubbed log: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("failed to write scrubbed log: %w", err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("failed to replace log: %w", err)
+	}
+	return changed, nil
+}
+
+// createLogsCmd creates the logs command
+func createLogsCmd() *cobra.Command {
+	logsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Work with bot log files",
+	}
+
+	logsCmd.AddCommand(createLogsScrubCmd())
+
+	return logsCmd
+}
+
+// createLogsScrubCmd creates the logs scrub command
+func createLogsScrubCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "scrub <file>",
+		Short: "Redact the configured secrets from an existing log before sharing it",
+		Long: `Replaces every configured secret (telegram token, mnemonic, admin token and
+other sensitive settings) in the log with ` + RedactedPlaceholder + `. The file is
+rewritten in place unless --output is given. Secrets that are no longer in the
+config are not known and cannot be redacted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, _ := cmd.Flags().GetString("config")
+			config, err := LoadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			changed, err := ScrubLog(args[0], output, NewRedactor(SensitiveValues(config)...))
+			if err != nil {
+				return err
+			}
+
+			target := output
+			if target == "" {
+				target = args[0]
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Redacted %d line(s), scrubbed log written to %s\n", changed, target)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&output, "output", "", "Write the scrubbed log here instead of replacing the file")
+
+	return cmd
+}
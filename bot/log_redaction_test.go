@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMnemonic = "abandon ability able about above absent absorb abstract absurd abuse access accident"
+
+func TestLogRedactsSecretsInFormattedErrors(t *testing.T) {
+	config := &BotConfig{
+		TelegramToken:     "7100200300:AAHsecret-token-value",
+		ValidatorMnemonic: testMnemonic,
+		StatusServer:      StatusServerConfig{AdminToken: "admin-bearer-token"},
+		Reports:           ReportConfig{WebhookURL: "https://hooks.example/T000/B000/webhook-secret"},
+		TelegramChatID:    "-100200",
+		LogFile:           filepath.Join(t.TempDir(), "logs", "bot.log"),
+	}
+	if got := len(SensitiveValues(config)); got != 4 {
+		t.Fatalf("%d sensitive values, want 4", got)
+	}
+
+	logFile, err := SetupLogging(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		log.SetOutput(os.Stderr)
+		logFile.Close()
+	})
+
+	// The HTTP client puts the token-bearing URL into its errors
+	sendErr := fmt.Errorf("Post %q: dial tcp: i/o timeout",
+		"https://api.telegram.org/bot"+config.TelegramToken+"/sendMessage")
+	log.Printf("Failed to send alert to chat %s: %v", config.TelegramChatID, sendErr)
+	log.Printf("Signing with mnemonic %q", strings.ReplaceAll(testMnemonic, " ", "  "))
+	log.Printf("Admin request rejected, token %s, report webhook %s", config.StatusServer.AdminToken, config.Reports.WebhookURL)
+
+	data, err := os.ReadFile(config.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := string(data)
+	for _, secret := range append(SensitiveValues(config), "abandon ability") {
+		if strings.Contains(output, secret) {
+			t.Fatalf("log output contains %q:\n%s", secret, output)
+		}
+	}
+	if !strings.Contains(output, "https://api.telegram.org/bot"+RedactedPlaceholder+"/sendMessage") {
+		t.Fatalf("token not masked in place:\n%s", output)
+	}
+	// Values that are not secrets are kept
+	if !strings.Contains(output, "chat -100200") {
+		t.Fatalf("chat ID was masked:\n%s", output)
+	}
+}
+
+func TestRedactorIgnoresShortValues(t *testing.T) {
+	redactor := NewRedactor("", "abc", "longer-secret")
+	if got := redactor.Redact("abc longer-secret"); got != "abc "+RedactedPlaceholder {
+		t.Fatalf("redacted %q", got)
+	}
+}
+
+func TestRotatingFileRotatesBySizeAndAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	file, err := OpenRotatingFile(path, LogRotationConfig{MaxAge: time.Hour, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	file.now = func() time.Time { return now }
+	file.started = now
+	file.maxSize = 100
+
+	line := strings.Repeat("x", 39) + "\n"
+	for i := 0; i < 2; i++ {
+		now = now.Add(time.Second)
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if backups, _ := file.Backups(); len(backups) != 0 {
+		t.Fatalf("rotated below the size limit: %v", backups)
+	}
+
+	// A third line would exceed 100 bytes
+	now = now.Add(time.Second)
+	file.Write([]byte(line))
+	if backups, _ := file.Backups(); len(backups) != 1 {
+		t.Fatalf("backups after exceeding the size = %v", backups)
+	}
+
+	// A file older than max_age is rotated even when small
+	now = now.Add(time.Hour)
+	file.Write([]byte(line))
+	now = now.Add(time.Hour)
+	file.Write([]byte(line))
+
+	// Only max_backups rotated files are kept
+	backups, err := file.Backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0] != path+"."+now.Add(-time.Hour).Format("20060102-150405.000") ||
+		backups[1] != path+"."+now.Format("20060102-150405.000") {
+		t.Fatalf("backups = %v", backups)
+	}
+	if data, _ := os.ReadFile(path); string(data) != line {
+		t.Fatalf("current log = %q", data)
+	}
+}
+
+func TestLogsScrubCommand(t *testing.T) {
+	dir := t.TempDir()
+	configPath := writeConfig(t, legacyConfig+"validator_mnemonic: \""+testMnemonic+"\"\n")
+	logPath := filepath.Join(dir, "bot.log")
+	contents := "2026/10/17 08:00:00 Configuration loaded\n" +
+		"2026/10/17 08:00:01 Failed to send alert: Post \"https://api.telegram.org/bot123:abc/sendMessage\": EOF\n" +
+		"2026/10/17 08:00:02 Recovered key from " + testMnemonic + "\n"
+	if err := os.WriteFile(logPath, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := CreateRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"logs", "scrub", logPath, "--config", configPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Redacted 2 line(s)") {
+		t.Fatalf("output = %q", out.String())
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrubbed := string(data)
+	if strings.Contains(scrubbed, "123:abc") || strings.Contains(scrubbed, "abandon") ||
+		strings.Count(scrubbed, RedactedPlaceholder) != 2 || !strings.HasPrefix(scrubbed, "2026/10/17 08:00:00 Configuration loaded\n") {
+		t.Fatalf("scrubbed log = %q", scrubbed)
+	}
+	if _, err := os.Stat(logPath + ".scrub.tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temporary file left behind")
+	}
+}
@@ -2,16 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"sort"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
@@ -39,42 +41,177 @@ const (
 
 // BotConfig represents the enhanced bot configuration
 type BotConfig struct {
+	// Schema version of this file, see CurrentConfigVersion
+	ConfigVersion int `yaml:"config_version"`
+	
 	// Chain connection settings
 	ChainRPC     string `yaml:"chain_rpc"`
 	ChainGRPC    string `yaml:"chain_grpc"`
 	ChainID      string `yaml:"chain_id"`
+	ChainAPI     string `yaml:"chain_api"`
 	
 	// Validator settings
 	ValidatorAddress string `yaml:"validator_address"`
 	ValidatorName    string `yaml:"validator_name"`
-	ValidatorMnemonic string `yaml:"validator_mnemonic"`
+	ValidatorMnemonic string `yaml:"validator_mnemonic" sensitive:"true"`
 	
 	// Bot settings
 	LogLevel     string        `yaml:"log_level"`
+	// Log file written besides stderr, rotated by size and age. Configured
+	// secrets are masked in all log output.
+	LogFile      string            `yaml:"log_file"`
+	LogRotation  LogRotationConfig `yaml:"log_rotation"`
 	CheckInterval time.Duration `yaml:"check_interval"`
+	DataDir      string        `yaml:"data_dir"`
+	// Directory of the per-validator instance lease (default data_dir). Point
+	// bots that might run for the same validator at a shared directory.
+	InstanceLeaseDir string `yaml:"instance_lease_dir"`
 	
 	// Rebalancing settings
 	SwapCooldown  time.Duration `yaml:"swap_cooldown"`
 	PriceLimit    string        `yaml:"price_limit"`
 	MaxSwapDaily  string        `yaml:"max_swap_daily"`
+	// USD price of the emergency stop, above price_limit (default price_limit),
+	// length of monitor-only mode (default 24h) and interval between
+	// rebalances (default 1h, at least 10m)
+	EmergencyThreshold  string        `yaml:"emergency_threshold"`
+	MonitorOnlyDuration time.Duration `yaml:"monitor_only_duration"`
+	RebalanceInterval   time.Duration `yaml:"rebalance_interval"`
+	// Simulate rebalances instead of swapping, also switchable at runtime
+	// through the status server admin endpoints
+	RebalancerDryRun bool `yaml:"rebalancer_dry_run"`
+	// Number of price samples the rebalancer keeps for /prices and its
+	// average and volatility (default 60, an hour of samples)
+	PriceHistorySize int `yaml:"price_history_size"`
 	
 	// IBC settings
 	IBCEnabled   bool     `yaml:"ibc_enabled"`
 	IBCChannels  []string `yaml:"ibc_channels"`
+	// How long after it was queued by block time a simulated packet whose
+	// recv was not relayed is dropped (default 10m)
+	IBCPacketTimeout time.Duration `yaml:"ibc_packet_timeout"`
+	// Counterparty ends of ibc_channels, which prove the packets they never
+	// received for MsgTimeout; packets sent on the other channels are not
+	// timed out
+	IBCCounterparties map[string]IBCCounterparty `yaml:"ibc_counterparties"`
+	
+	// Escrow balance monitoring of the IBC channels
+	IBCEscrow IBCEscrowConfig `yaml:"ibc_escrow"`
 	
 	// DEX settings
 	DEXEnabled bool     `yaml:"dex_enabled"`
 	DEXPools   []string `yaml:"dex_pools"`
+	// How often the pool balances are checked; a pool below the minimum
+	// balance is refilled right away (default 15m)
+	PoolBalanceCheckInterval time.Duration `yaml:"pool_balance_check_interval"`
 	
 	// Telegram settings
 	TelegramEnabled bool   `yaml:"telegram_enabled"`
-	TelegramToken   string `yaml:"telegram_token"`
+	TelegramToken   string `yaml:"telegram_token" sensitive:"true"`
 	TelegramChatID  string `yaml:"telegram_chat_id"`
+	ValidatorChatMap map[string]string `yaml:"validator_chat_map"`
+	TelegramMaxMessageSize int `yaml:"telegram_max_message_size"`
+	// P95 alert delivery latency above which an alert is raised (default 5m)
+	AlertLatencyThreshold time.Duration `yaml:"alert_latency_threshold"`
+	
+	// Discord settings: alerts are also posted to the webhook's channel
+	DiscordEnabled    bool   `yaml:"discord_enabled"`
+	DiscordWebhookURL string `yaml:"discord_webhook_url" sensitive:"true"`
+	// How long a channel that failed more than 3 times in a row is skipped
+	// before it is tried again (default 15m)
+	NotifierCooldown time.Duration `yaml:"notifier_cooldown"`
+	
+	// Where alerts go: telegram (default), file or both. The file receives
+	// the exact messages Telegram would be sent (default data_dir/alerts.log)
+	AlertSink string `yaml:"alert_sink"`
+	AlertFile string `yaml:"alert_file"`
+	
+	// Rebalancer threshold smoothing (spot|ema1h|ema24h)
+	ThresholdSource string `yaml:"threshold_source"`
+	
+	// Where the rebalancer reads the GXR price (simulated|coingecko|twap|rest)
+	PriceOracle PriceOracleConfig `yaml:"price_oracle"`
+	// Oracles the rebalancer takes the median price of instead of
+	// price_oracle's; max_failures and the denom still come from price_oracle
+	PriceSources []PriceSourceConfig `yaml:"price_sources"`
+	
+	// DEX venue the rebalancer swaps on
+	SwapVenue SwapVenueConfig `yaml:"swap_venue"`
+	
+	// Quiet hours for non-critical alerts
+	QuietHours QuietHoursConfig `yaml:"quiet_hours"`
+	
+	// Alert spike detection: one summary, then a digest per spiking alert type
+	AlertAnomaly AlertAnomalyConfig `yaml:"alert_anomaly"`
+	
+	// Weekly on-call rotation for critical alerts
+	OnCallSchedule OnCallConfig `yaml:"oncall_schedule"`
+	
+	// Grouping of related component failure alerts into incidents
+	Incidents IncidentConfig `yaml:"incidents"`
+	
+	// Monthly validator reports
+	Reports ReportConfig `yaml:"reports"`
+	
+	// Local HTTP status server and its admin endpoints
+	StatusServer StatusServerConfig `yaml:"status_server"`
+	
+	// Request budgets, backoff and loop jitter for the chain endpoints,
+	// stricter for shared public RPC providers
+	Outbound OutboundConfig `yaml:"outbound"`
+	
+	// Weights of the monthly composite validator score
+	Scores ScoreConfig `yaml:"scores"`
+	
+	// Candidate decision paths run in shadow mode next to the active logic
+	Shadow ShadowConfig `yaml:"shadow"`
+	
+	// OTLP/HTTP collector the traces of distributions, IBC relays, DEX
+	// refills and alert deliveries are exported to; empty disables tracing
+	OtelEndpoint string `yaml:"otel_endpoint"`
+	
+	// How long failed heartbeat and slashing report broadcasts are retried (default 1h)
+	BroadcastMaxAge time.Duration `yaml:"broadcast_max_age"`
+	// Gas limit and fee in ugen of the bot's transactions (defaults 200000 and 5000)
+	TxGasLimit uint64 `yaml:"tx_gas_limit"`
+	TxFee      int64  `yaml:"tx_fee"`
+	
+	// How long alerts wait in the spill buffer while Telegram is unreachable (default 6h)
+	AlertSpillMaxAge time.Duration `yaml:"alert_spill_max_age"`
+	
+	// How long shutdown waits for each component's in-flight work
+	Shutdown ShutdownConfig `yaml:"shutdown"`
+	
+	// Sign bot transactions with a hot key acting through authz and feegrant
+	TxAuthz TxAuthzConfig `yaml:"tx_authz"`
+	
+	// Contact and endpoint registered with the bot version in the chain's
+	// bot directory
+	BotMetadata BotMetadataConfig `yaml:"bot_metadata"`
+	
+	// Failover between two bot hosts (primary|standby, default primary). A
+	// standby monitors without broadcasting and takes over once the primary
+	// missed StandbyPromoteAfter heartbeat intervals (default 3).
+	Role                string `yaml:"role"`
+	StandbyPromoteAfter int    `yaml:"standby_promote_after"`
+	
+	// Daily check for newer bot releases on GitHub. Only alerts, never installs.
+	UpdateCheck          bool   `yaml:"update_check"`
+	UpdateCheckRepo      string `yaml:"update_check_repo"`       // owner/name, default Crocodile-ark/Genpro
+	UpdateCheckProxy     string `yaml:"update_check_proxy"`      // e.g. http://proxy:3128, default HTTPS_PROXY
+	UpdateCheckPublicKey string `yaml:"update_check_public_key"` // base64 ed25519 key signing checksums.txt
 	
 	// Enhanced monitoring
 	MonitoringEnabled     bool `yaml:"monitoring_enabled"`
 	HealthCheckEnabled    bool `yaml:"health_check_enabled"`
 	MetricsEnabled        bool `yaml:"metrics_enabled"`
+	// Port of the Prometheus /metrics endpoint served with metrics_enabled
+	// (default 9464)
+	MetricsPort int `yaml:"metrics_port"`
+	// Alert when a component changes health state more than this many times
+	// within the flap window (defaults 4 and 10m)
+	HealthFlapThreshold int           `yaml:"health_flap_threshold"`
+	HealthFlapWindow    time.Duration `yaml:"health_flap_window"`
 	
 	// Advanced settings
 	RetryAttempts     int           `yaml:"retry_attempts"`
@@ -94,9 +231,49 @@ type BotService struct {
 	rebalancer       *Rebalancer
 	validatorMonitor *ValidatorMonitor
 	ibcRelayer       *IBCRelayer
+	escrowMonitor    *EscrowMonitor
+	chainPhase       *ChainPhaseTracker
 	dexManager       *DEXManager
 	rewardDistributor *RewardDistributor
 	telegramAlert    *TelegramAlert
+	chainQuerier     *ChainQuerier
+	rpcQuerier       *ChainQuerier
+	statusServer     *StatusServer
+	metricsServer    *MetricsServer
+	updateChecker    *UpdateChecker
+	broadcastQueue   *BroadcastQueue
+	queryCache       *QueryCache
+	costLedger       *CostLedger
+	outbound         *OutboundLimiter
+	incidents        *IncidentTracker
+	shadow           *ShadowRunner
+	
+	// Bot protocol handshake
+	protocolCompatible bool
+	protocolInfo       ProtocolInfo
+	protocolError      string
+	
+	// Chain-id of the node, checked against the configuration
+	nodeChainID          string
+	chainIDMismatch      bool
+	chainIDError         string
+	overrideChainIDCheck bool
+	
+	// Instance identity and duplicate protection; the lock, lease and
+	// readOnly are guarded by mu
+	instanceID        string
+	instanceLock      *InstanceLock
+	instanceLease     *InstanceLease
+	readOnly          bool
+	lastHeartbeatMemo string
+	
+	// Warm standby: standingBy while the primary is alive
+	standingBy           bool
+	watchingSince        time.Time
+	promotedAt           time.Time
+	lastPrimaryHeartbeat time.Time
+	activeCancel         context.CancelFunc
+	roleTransitions      int
 	
 	// State management
 	running          bool
@@ -107,11 +284,17 @@ type BotService struct {
 	
 	// Health monitoring
 	healthStatus     map[string]bool
+	healthTracker    *HealthTracker
+	unhealthySince   time.Time // start of the current run of failed health checks
 	lastErrors       []ErrorRecord
 	
-	// Shutdown handling
-	shutdownChan     chan struct{}
-	shutdownComplete chan struct{}
+	// Service manager notifications, nil unless run under systemd
+	notifier *SystemdNotifier
+	
+	// Shutdown handling: phase one closes shutdownChan and stops new work,
+	// phase two drains the work in flight
+	shutdownChan chan struct{}
+	shutdown     *Shutdown
 }
 
 // ErrorRecord represents an error record
@@ -126,9 +309,13 @@ func NewBotService(config *BotConfig) (*BotService, error) {
 	bs := &BotService{
 		config:           config,
 		healthStatus:     make(map[string]bool),
+		healthTracker:    NewHealthTracker(config.HealthFlapThreshold, config.HealthFlapWindow),
 		lastErrors:       make([]ErrorRecord, 0),
 		shutdownChan:     make(chan struct{}),
-		shutdownComplete: make(chan struct{}),
+		shutdown:         NewShutdown(config.Shutdown, config.DataDir),
+		protocolCompatible: true,
+		instanceID:       NewInstanceID(),
+		notifier:         NewSystemdNotifier(),
 	}
 	
 	// Initialize components
@@ -144,7 +331,7 @@ func (bs *BotService) initializeComponents() error {
 	log.Printf("Initializing bot components...")
 	
 	// Initialize telegram alert first
-	if bs.config.TelegramEnabled {
+	if bs.config.usesTelegram() || bs.config.usesAlertFile() || bs.config.DiscordEnabled {
 		bs.telegramAlert = NewTelegramAlert(bs.config)
 		if err := bs.telegramAlert.TestConnection(); err != nil {
 			log.Printf("Warning: Telegram connection failed: %v", err)
@@ -158,30 +345,106 @@ func (bs *BotService) initializeComponents() error {
 		return fmt.Errorf("failed to initialize chain client: %w", err)
 	}
 	
-	// Initialize rebalancer
-	bs.rebalancer = NewRebalancer(bs.config)
+	// Chain query results outlive brief RPC outages and restarts
+	bs.queryCache = NewQueryCache(bs.config.DataDir, nil)
+	
+	// What the bot's transactions, alerts and queries cost the validator
+	bs.costLedger = NewCostLedger(bs.config.DataDir)
+	
+	// Candidate decision paths observed in shadow mode
+	bs.shadow = NewShadowRunner(bs.config.Shadow, time.Now())
+	
+	// Initialize DEX manager if enabled
+	if bs.config.DEXEnabled {
+		bs.dexManager = NewDEXManager(bs.config)
+		bs.dexManager.costs = bs.costLedger
+		bs.dexManager.bankQuery = banktypes.NewQueryClient(bs.clientCtx)
+		bs.dexManager.alerts = bs.telegramAlert
+		bs.healthStatus["dex_manager"] = true
+	}
+	
+	// Initialize rebalancer, swapping on the DEX manager's pools
+	var swaps SwapExecutor
+	if bs.dexManager != nil {
+		swaps = bs.dexManager
+	}
+	rebalancer, err := NewRebalancer(bs.config, swaps)
+	if err != nil {
+		return fmt.Errorf("failed to initialize rebalancer: %w", err)
+	}
+	bs.rebalancer = rebalancer
+	bs.rebalancer.queryCache = bs.queryCache
+	bs.rebalancer.shadow = bs.shadow
 	bs.healthStatus["rebalancer"] = true
 	
 	// Initialize validator monitor
 	bs.validatorMonitor = NewValidatorMonitor(bs.config, bs.clientCtx, bs.cdc)
+	bs.validatorMonitor.queryCache = bs.queryCache
 	bs.healthStatus["validator_monitor"] = true
 	
 	// Initialize IBC relayer if enabled
 	if bs.config.IBCEnabled {
 		bs.ibcRelayer = NewIBCRelayer(bs.config)
+		bs.ibcRelayer.blockTime = bs.chainQuerier.QueryLatestBlockTime
+		bs.ibcRelayer.chain = bs.chainQuerier
+		bs.ibcRelayer.costs = bs.costLedger
 		bs.healthStatus["ibc_relayer"] = true
 	}
 	
-	// Initialize DEX manager if enabled
-	if bs.config.DEXEnabled {
-		bs.dexManager = NewDEXManager(bs.config)
-		bs.healthStatus["dex_manager"] = true
+	// Initialize the IBC escrow monitor if enabled
+	if bs.config.IBCEscrow.Enabled {
+		monitor, err := NewEscrowMonitor(bs.config, bs.chainQuerier, bs.telegramAlert)
+		if err != nil {
+			return fmt.Errorf("failed to initialize IBC escrow monitor: %w", err)
+		}
+		bs.escrowMonitor = monitor
+	}
+	
+	// Track the chain phase in the halving schedule for status and alerts
+	bs.chainPhase = NewChainPhaseTracker(bs.config, bs.chainQuerier, bs.telegramAlert)
+	bs.validatorMonitor.chainPhase = bs.chainPhase
+	if bs.telegramAlert != nil {
+		bs.telegramAlert.SetChainPhaseTracker(bs.chainPhase)
 	}
 	
 	// Initialize reward distributor
 	bs.rewardDistributor = NewRewardDistributor(bs.config)
+	bs.rewardDistributor.queryCache = bs.queryCache
+	bs.rewardDistributor.costs = bs.costLedger
+	bs.rewardDistributor.shadow = bs.shadow
 	bs.healthStatus["reward_distributor"] = true
 	
+	// Alerts of all components are correlated into shared incidents
+	if bs.config.Incidents.Enabled && bs.telegramAlert != nil {
+		bs.incidents = NewIncidentTracker(bs.config.Incidents, bs.config.DataDir)
+		bs.telegramAlert.SetIncidentTracker(bs.incidents, "")
+		bs.rebalancer.telegramAlert.SetIncidentTracker(bs.incidents, ComponentRebalancer)
+		bs.validatorMonitor.telegramAlert.SetIncidentTracker(bs.incidents, ComponentValidatorMonitor)
+	}
+	
+	// Alerts the queues cannot hold while Telegram is unreachable wait in a
+	// spill buffer in the data directory, shared by the dispatchers
+	if bs.telegramAlert != nil {
+		spill := NewAlertSpill(bs.config.DataDir, bs.config.AlertSpillMaxAge)
+		for _, ta := range []*TelegramAlert{bs.telegramAlert, bs.rebalancer.telegramAlert, bs.validatorMonitor.telegramAlert} {
+			if ta != nil {
+				ta.SetAlertSpill(spill)
+			}
+		}
+	}
+	
+	// Initialize the release update checker if enabled
+	if bs.config.UpdateCheck {
+		checker, err := NewUpdateChecker(bs.config, bs.telegramAlert)
+		if err != nil {
+			return fmt.Errorf("failed to initialize update checker: %w", err)
+		}
+		bs.updateChecker = checker
+	}
+	
+	// Track in-flight work for shutdown and pick up what the last one left
+	bs.attachDrainTrackers()
+	
 	log.Printf("All components initialized successfully")
 	return nil
 }
@@ -193,14 +456,204 @@ func (bs *BotService) initializeChainClient() error {
 	log.Printf("Chain RPC: %s", bs.config.ChainRPC)
 	log.Printf("Chain gRPC: %s", bs.config.ChainGRPC)
 	
-	// In a real implementation, this would create proper Cosmos SDK client
-	// For now, we'll simulate the initialization
-	time.Sleep(1 * time.Second)
+	// Every chain querier created from here on shares the request budgets
+	bs.outbound = NewOutboundLimiter(bs.config.Outbound)
+	SetOutboundLimiter(bs.outbound)
+	
+	bs.chainQuerier = NewChainQuerier(bs.config)
+	if bs.config.ChainRPC != "" {
+		bs.rpcQuerier = NewChainQuerierForAPI(rpcURL(bs.config.ChainRPC))
+	}
+	
+	clientCtx, err := newClientContext(bs.config)
+	if err != nil {
+		return err
+	}
+	bs.clientCtx, bs.cdc = clientCtx, clientCtx.Codec
 	
 	log.Printf("Chain client initialized successfully")
 	return nil
 }
 
+// AcquireInstance takes the instance lockfile and checks the chain for heartbeats
+// from another live instance of this validator's bot. On conflict the bot refuses
+// to start unless allowDuplicate is set, in which case it runs read-only: no
+// transaction-broadcasting components and no heartbeats.
+func (bs *BotService) AcquireInstance(ctx context.Context, allowDuplicate bool) error {
+	var conflict error
+	
+	lock, err := AcquireInstanceLock(bs.config.DataDir, bs.instanceID)
+	if err != nil {
+		var duplicate *DuplicateInstanceError
+		if !errors.As(err, &duplicate) {
+			return err
+		}
+		conflict = err
+	} else {
+		bs.mu.Lock()
+		bs.instanceLock = lock
+		bs.mu.Unlock()
+		if lock.Predecessor() != "" {
+			log.Printf("Took over stale instance lock from instance %s", lock.Predecessor())
+		}
+	}
+	
+	// A standby takes the lease and claims the validator only when promoted
+	if conflict == nil && bs.config.IsStandby() {
+		bs.mu.Lock()
+		bs.standingBy = true
+		bs.watchingSince = time.Now()
+		bs.mu.Unlock()
+		log.Printf("Bot instance %s standing by for the primary", bs.instanceID)
+		return nil
+	}
+	
+	if conflict == nil && bs.config.ValidatorAddress != "" {
+		leaseDir := bs.config.InstanceLeaseDir
+		if leaseDir == "" {
+			leaseDir = bs.config.DataDir
+		}
+		lease, err := AcquireInstanceLeaseAs(leaseDir, bs.config.ValidatorAddress, bs.instanceID, bs.config.role(), InstanceLeaseTTL, time.Now())
+		if err != nil {
+			var duplicate *DuplicateInstanceError
+			if !errors.As(err, &duplicate) {
+				bs.releaseInstance()
+				return err
+			}
+			conflict = err
+		} else {
+			bs.mu.Lock()
+			bs.instanceLease = lease
+			bs.mu.Unlock()
+		}
+	}
+	
+	if conflict == nil && bs.chainQuerier != nil && bs.config.ValidatorAddress != "" {
+		sender, err := bs.config.TxAuthz.Sender(bs.config.ValidatorAddress)
+		var memo string
+		if err == nil {
+			memo, err = bs.chainQuerier.QueryLatestHeartbeatMemo(ctx, sender)
+		}
+		if err != nil {
+			log.Printf("Heartbeat conflict check skipped: %v", err)
+		} else {
+			predecessor := ""
+			bs.mu.RLock()
+			if bs.instanceLock != nil {
+				predecessor = bs.instanceLock.Predecessor()
+			}
+			bs.mu.RUnlock()
+			conflict = DetectHeartbeatConflict(memo, bs.instanceID, predecessor, time.Now())
+			if standby, _, ok := ParseHeartbeatMemo(memo); ok && isStandbyHeartbeat(memo) && bs.telegramAlert != nil {
+				bs.telegramAlert.SendBotAlert("Primary", "reclaimed", fmt.Sprintf("Primary instance %s takes over from standby instance %s", bs.instanceID, standby))
+			}
+		}
+	}
+	
+	if conflict == nil {
+		log.Printf("Bot instance %s acquired", bs.instanceID)
+		return nil
+	}
+	
+	log.Printf("Duplicate bot instance: %v", conflict)
+	if bs.telegramAlert != nil {
+		bs.telegramAlert.SendEmergencyAlert("Duplicate Bot Instance", conflict.Error(), map[string]interface{}{
+			MetadataValidatorAddress: bs.config.ValidatorAddress,
+			"instance_id":            bs.instanceID,
+			"allow_duplicate":        allowDuplicate,
+		})
+	}
+	
+	if !allowDuplicate {
+		bs.releaseInstance()
+		return conflict
+	}
+	
+	// A read-only instance must not hold the lease the other instance renews
+	bs.mu.Lock()
+	lease := bs.instanceLease
+	bs.instanceLease = nil
+	bs.readOnly = true
+	bs.mu.Unlock()
+	if lease != nil {
+		lease.Release()
+	}
+	
+	log.Printf("Continuing in read-only mode (--allow-duplicate): transaction-broadcasting components disabled")
+	return nil
+}
+
+// releaseInstance releases the instance lockfile and lease
+func (bs *BotService) releaseInstance() {
+	bs.mu.Lock()
+	lease, lock := bs.instanceLease, bs.instanceLock
+	bs.instanceLease, bs.instanceLock = nil, nil
+	bs.mu.Unlock()
+	
+	if lease != nil {
+		if err := lease.Release(); err != nil {
+			log.Printf("Failed to release instance lease: %v", err)
+		}
+	}
+	if lock != nil {
+		if err := lock.Release(); err != nil {
+			log.Printf("Failed to release instance lock: %v", err)
+		}
+	}
+}
+
+// SetBroadcaster sends heartbeats, slashing reports and IBC packet timeouts
+// to the chain through broadcaster. Failed heartbeats and reports are retried
+// from a queue persisted in data_dir, timeouts by the IBC relayer. It must be
+// called before Start, which otherwise installs the ChainBroadcaster.
+func (bs *BotService) SetBroadcaster(broadcaster TxBroadcaster) {
+	bs.broadcastQueue = NewBroadcastQueue(broadcaster, bs.telegramAlert, bs.config.DataDir, bs.config.BroadcastMaxAge)
+	bs.broadcastQueue.authz = bs.config.TxAuthz
+	bs.broadcastQueue.gate = bs.broadcastGate
+	bs.broadcastQueue.drain = bs.shutdown.Tracker(ComponentBroadcast)
+	bs.broadcastQueue.costs = bs.costLedger
+	bs.broadcastQueue.Resume(bs.shutdown.Interrupted(ComponentBroadcast), time.Now())
+	if bs.validatorMonitor != nil {
+		bs.validatorMonitor.broadcastQueue = bs.broadcastQueue
+	}
+	if bs.ibcRelayer != nil {
+		bs.ibcRelayer.broadcaster = broadcaster
+		bs.ibcRelayer.broadcastGate = bs.broadcastGate
+	}
+}
+
+// installBroadcaster broadcasts bot transactions signed with the key of
+// validator_mnemonic, unless a broadcaster was set already. Without a key the
+// bot sends no transactions and the chain counts no heartbeats.
+func (bs *BotService) installBroadcaster() {
+	if bs.broadcastQueue != nil {
+		return
+	}
+	if bs.config.ValidatorMnemonic == "" {
+		log.Printf("Warning: no validator_mnemonic configured, heartbeats and bot metadata are not broadcast")
+		return
+	}
+	
+	broadcaster, err := NewChainBroadcaster(bs.clientCtx, bs.config)
+	if err != nil {
+		log.Printf("Warning: bot transactions are not broadcast: %v", err)
+		bs.recordError("broadcast", err.Error())
+		if bs.telegramAlert != nil {
+			bs.telegramAlert.SendBotAlert("Broadcaster", "error", err.Error())
+		}
+		return
+	}
+	bs.SetBroadcaster(broadcaster)
+}
+
+// isReadOnly reports whether the bot runs without broadcasting transactions,
+// also while it stands by for the primary
+func (bs *BotService) isReadOnly() bool {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	return bs.readOnly || bs.standingBy
+}
+
 // Start starts the bot service
 func (bs *BotService) Start(ctx context.Context) error {
 	bs.mu.Lock()
@@ -215,6 +668,13 @@ func (bs *BotService) Start(ctx context.Context) error {
 		bs.telegramAlert.SendBotAlert("GXR Bot", "started", "Bot service started successfully")
 	}
 	
+	// A node of another network puts the bot in read-only mode before any
+	// broadcasting component starts
+	bs.CheckChainID(ctx)
+	
+	// Sign bot transactions before any component needs to broadcast
+	bs.installBroadcaster()
+	
 	// Start all components
 	if err := bs.startComponents(ctx); err != nil {
 		return fmt.Errorf("failed to start components: %w", err)
@@ -225,9 +685,71 @@ func (bs *BotService) Start(ctx context.Context) error {
 		go bs.healthMonitor(ctx)
 	}
 	
+	// Serve status and admin endpoints
+	if bs.config.StatusServer.ListenAddr != "" {
+		bs.statusServer = NewStatusServer(bs, bs.config.StatusServer)
+		if err := bs.statusServer.Start(); err != nil {
+			return fmt.Errorf("failed to start status server: %w", err)
+		}
+	}
+	
+	// Serve the component statuses to Prometheus
+	if bs.config.MetricsEnabled {
+		bs.metricsServer = NewMetricsServer(bs, bs.config.MetricsPort)
+		if err := bs.metricsServer.Start(); err != nil {
+			return fmt.Errorf("failed to start metrics server: %w", err)
+		}
+	}
+	
+	// Keep checking the node's chain-id across reconnects
+	if bs.rpcQuerier != nil {
+		go bs.watchChainID(ctx)
+	}
+	
+	// Keep the validator lease while running
+	bs.mu.RLock()
+	leaseHeld := bs.instanceLease != nil
+	bs.mu.RUnlock()
+	if leaseHeld {
+		go bs.renewInstanceLease(ctx)
+	}
+	
+	// A standby takes over when the primary's heartbeats stop
+	if bs.config.IsStandby() {
+		go bs.watchPrimary(ctx)
+	}
+	
+	// Retry failed heartbeat and slashing report broadcasts
+	if bs.broadcastQueue != nil {
+		go bs.broadcastQueue.Run(ctx)
+	}
+	
+	// Account Telegram and RPC usage in the cost ledger
+	go bs.recordCosts(ctx)
+	
+	// Summarize the week, such as the shadow mode comparison
+	if bs.telegramAlert != nil {
+		go bs.sendWeeklyDigests(ctx)
+	}
+	
+	// Check for newer releases in the background, never delaying startup
+	if bs.updateChecker != nil {
+		go bs.updateChecker.Run(ctx)
+	}
+	
+	// Verify the chain accepts our bot protocol before sending heartbeats
+	bs.checkProtocolCompatibility(ctx)
+	
+	// Keep the validator's bot directory entry current
+	bs.registerBotMetadata(ctx, time.Now())
+	
 	// Start heartbeat for validator monitoring
 	go bs.sendHeartbeat(ctx)
 	
+	// Tell systemd the bot is up and keep its watchdog fed
+	bs.notifySystemd(true)
+	go bs.notifier.RunWatchdog(ctx, bs.watchdogAlive)
+	
 	log.Printf("Bot service started successfully - All components running")
 	return nil
 }
@@ -236,15 +758,18 @@ func (bs *BotService) Start(ctx context.Context) error {
 func (bs *BotService) startComponents(ctx context.Context) error {
 	var wg sync.WaitGroup
 	errors := make(chan error, 10)
+	readOnly := bs.isReadOnly()
 	
 	// Start rebalancer
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := bs.rebalancer.Start(ctx); err != nil {
-			errors <- fmt.Errorf("rebalancer failed: %w", err)
-		}
-	}()
+	if !readOnly {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := bs.rebalancer.Start(ctx); err != nil {
+				errors <- fmt.Errorf("rebalancer failed: %w", err)
+			}
+		}()
+	}
 	
 	// Start validator monitor
 	wg.Add(1)
@@ -256,7 +781,7 @@ func (bs *BotService) startComponents(ctx context.Context) error {
 	}()
 	
 	// Start IBC relayer if enabled
-	if bs.ibcRelayer != nil {
+	if bs.ibcRelayer != nil && !readOnly {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -266,26 +791,48 @@ func (bs *BotService) startComponents(ctx context.Context) error {
 		}()
 	}
 	
-	// Start DEX manager if enabled
-	if bs.dexManager != nil {
+	// Start the IBC escrow monitor if enabled; it only queries
+	if bs.escrowMonitor != nil {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if err := bs.dexManager.Start(ctx); err != nil {
-				errors <- fmt.Errorf("DEX manager failed: %w", err)
+			if err := bs.escrowMonitor.Start(ctx); err != nil {
+				errors <- fmt.Errorf("IBC escrow monitor failed: %w", err)
 			}
 		}()
 	}
 	
-	// Start reward distributor
+	// Start the chain phase tracker; it only queries
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := bs.rewardDistributor.Start(ctx); err != nil {
-			errors <- fmt.Errorf("reward distributor failed: %w", err)
+		if err := bs.chainPhase.Start(ctx); err != nil {
+			errors <- fmt.Errorf("chain phase tracker failed: %w", err)
 		}
 	}()
 	
+	// Start DEX manager if enabled
+	if bs.dexManager != nil && !readOnly {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := bs.dexManager.Start(ctx); err != nil {
+				errors <- fmt.Errorf("DEX manager failed: %w", err)
+			}
+		}()
+	}
+	
+	// Start reward distributor
+	if !readOnly {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := bs.rewardDistributor.Start(ctx); err != nil {
+				errors <- fmt.Errorf("reward distributor failed: %w", err)
+			}
+		}()
+	}
+	
 	// Check for startup errors
 	go func() {
 		wg.Wait()
@@ -304,17 +851,26 @@ func (bs *BotService) startComponents(ctx context.Context) error {
 	return nil
 }
 
-// healthMonitor monitors the health of all components
+// healthMonitor monitors the health of all components and writes the
+// health snapshot after every check
 func (bs *BotService) healthMonitor(ctx context.Context) {
-	ticker := time.NewTicker(HealthCheckInterval)
+	ticker := NewJitteredTicker(HealthCheckInterval)
 	defer ticker.Stop()
 	
+	if err := bs.writeHealthSnapshot(time.Now()); err != nil {
+		log.Printf("Failed to write health snapshot: %v", err)
+	}
+	
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			bs.performHealthCheck()
+			if err := bs.writeHealthSnapshot(time.Now()); err != nil {
+				log.Printf("Failed to write health snapshot: %v", err)
+			}
+			bs.notifySystemd(false)
 		}
 	}
 }
@@ -361,13 +917,37 @@ func (bs *BotService) performHealthCheck() {
 		bs.healthStatus["telegram_alert"] = bs.telegramAlert.IsRunning()
 	}
 	
-	// Count unhealthy components
+	// Count unhealthy components and track state changes
 	unhealthyCount := 0
 	for component, healthy := range bs.healthStatus {
 		if !healthy {
 			unhealthyCount++
 			log.Printf("Health check failed for component: %s", component)
 		}
+		if bs.telegramAlert != nil {
+			bs.telegramAlert.RecordComponentHealth(component, healthy, bs.lastHealthCheck)
+		}
+		if bs.healthTracker.Record(component, healthy, bs.lastHealthCheck) {
+			message := bs.healthTracker.flapMessage(component, bs.lastHealthCheck)
+			log.Printf("Component %s is flapping: %s", component, message)
+			if bs.telegramAlert != nil {
+				bs.telegramAlert.SendBotAlert(component, "flapping", message)
+			}
+		}
+	}
+	
+	// The chain RPC and price feed are not components of their own; the query
+	// cache tells whether their last queries failed
+	if bs.queryCache != nil && bs.telegramAlert != nil {
+		rpcHealthy := !bs.queryCache.Failing(QueryCacheValidators) && !bs.queryCache.Failing(QueryCachePendingDexAllocation)
+		bs.telegramAlert.RecordComponentHealth(ComponentChainRPC, rpcHealthy, bs.lastHealthCheck)
+		bs.telegramAlert.RecordComponentHealth(ComponentPriceFeed, !bs.queryCache.Failing(QueryCachePrice), bs.lastHealthCheck)
+	}
+	
+	if unhealthyCount == 0 {
+		bs.unhealthySince = time.Time{}
+	} else if bs.unhealthySince.IsZero() {
+		bs.unhealthySince = bs.lastHealthCheck
 	}
 	
 	// Send alert if too many components are unhealthy
@@ -378,23 +958,83 @@ func (bs *BotService) performHealthCheck() {
 	}
 }
 
+// unhealthyComponents returns the components that failed the last health check
+func (bs *BotService) unhealthyComponents() []string {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	
+	unhealthy := make([]string, 0)
+	for component, healthy := range bs.healthStatus {
+		if !healthy {
+			unhealthy = append(unhealthy, component)
+		}
+	}
+	sort.Strings(unhealthy)
+	return unhealthy
+}
+
 // sendHeartbeat sends periodic heartbeat to validator monitor
 func (bs *BotService) sendHeartbeat(ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Minute)
+	ticker := NewJitteredTicker(HeartbeatInterval)
 	defer ticker.Stop()
 	
+	protocolTicker := NewJitteredTicker(ProtocolCheckInterval)
+	defer protocolTicker.Stop()
+	
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-protocolTicker.C:
+			bs.checkProtocolCompatibility(ctx)
 		case <-ticker.C:
-			if bs.validatorMonitor != nil && bs.config.ValidatorAddress != "" {
-				bs.validatorMonitor.RegisterBotHeartbeat(bs.config.ValidatorAddress, Version)
-			}
+			bs.emitHeartbeat(ctx)
 		}
 	}
 }
 
+// emitHeartbeat registers a heartbeat unless the bot protocol is incompatible.
+// With a broadcaster the heartbeat is also sent to the chain, failed
+// broadcasts are retried by the broadcast queue.
+func (bs *BotService) emitHeartbeat(ctx context.Context) bool {
+	return bs.emitHeartbeatAt(ctx, time.Now())
+}
+
+// emitHeartbeatAt is emitHeartbeat for a heartbeat sent at now
+func (bs *BotService) emitHeartbeatAt(ctx context.Context, now time.Time) bool {
+	if !bs.isProtocolCompatible() {
+		log.Printf("Skipping heartbeat: bot protocol v%d not accepted by chain", BotProtocolVersion)
+		return false
+	}
+	
+	if bs.isReadOnly() {
+		log.Printf("Skipping heartbeat: running read-only")
+		return false
+	}
+	
+	if bs.validatorMonitor == nil || bs.config.ValidatorAddress == "" {
+		return false
+	}
+	
+	memo := HeartbeatMemo(bs.instanceID, now)
+	if bs.config.IsStandby() {
+		memo = StandbyHeartbeatMemo(bs.instanceID, now)
+	}
+	bs.mu.Lock()
+	bs.lastHeartbeatMemo = memo
+	bs.mu.Unlock()
+	
+	bs.validatorMonitor.RegisterBotHeartbeat(bs.config.ValidatorAddress, Version)
+	
+	if bs.broadcastQueue != nil {
+		tx := BotTx{Kind: TxKindHeartbeat, Validator: bs.config.ValidatorAddress, Memo: memo}
+		if err := bs.broadcastQueue.Submit(ctx, tx, now); err != nil {
+			bs.recordError("heartbeat", err.Error())
+		}
+	}
+	return true
+}
+
 // recordError records an error in the bot service
 func (bs *BotService) recordError(component, errorMsg string) {
 	bs.errorCount++
@@ -413,6 +1053,30 @@ func (bs *BotService) recordError(component, errorMsg string) {
 	}
 }
 
+// PausePriceMonitoring pauses the rebalancer's price-driven state changes
+func (bs *BotService) PausePriceMonitoring() error {
+	if bs.rebalancer == nil {
+		return fmt.Errorf("rebalancer is not running")
+	}
+	return bs.rebalancer.PausePriceMonitoring()
+}
+
+// ResumePriceMonitoring resumes the rebalancer's price monitoring
+func (bs *BotService) ResumePriceMonitoring() error {
+	if bs.rebalancer == nil {
+		return fmt.Errorf("rebalancer is not running")
+	}
+	return bs.rebalancer.ResumePriceMonitoring()
+}
+
+// SetRebalancerDryRun switches the rebalancer's dry-run mode
+func (bs *BotService) SetRebalancerDryRun(enabled bool) error {
+	if bs.rebalancer == nil {
+		return fmt.Errorf("rebalancer is not running")
+	}
+	return bs.rebalancer.SetDryRun(enabled)
+}
+
 // GetStatus returns the current status of the bot service
 func (bs *BotService) GetStatus() map[string]interface{} {
 	bs.mu.RLock()
@@ -427,17 +1091,42 @@ func (bs *BotService) GetStatus() map[string]interface{} {
 		"error_count":       bs.errorCount,
 		"success_count":     bs.successCount,
 		"health_status":     bs.healthStatus,
+		"health_flaps":      bs.healthTracker.FlapStatus(time.Now()),
+		"protocol": map[string]interface{}{
+			"bot_version":        BotProtocolVersion,
+			"chain_version":      bs.protocolInfo.ChainVersion,
+			"chain_min_version":  bs.protocolInfo.MinSupportedVersion,
+			"compatible":         bs.protocolCompatible,
+			"error":              bs.protocolError,
+		},
+		"instance": map[string]interface{}{
+			"id":                  bs.instanceID,
+			"read_only":           bs.readOnly,
+			"lease_held":          bs.instanceLease != nil,
+			"last_heartbeat_memo": bs.lastHeartbeatMemo,
+		},
+		"failover": bs.failoverStatus(),
 		"config": map[string]interface{}{
 			"chain_id":           bs.config.ChainID,
+			"chain_api":          bs.config.ChainAPI,
 			"validator_address":  bs.config.ValidatorAddress,
 			"validator_name":     bs.config.ValidatorName,
 			"telegram_enabled":   bs.config.TelegramEnabled,
+			"discord_enabled":    bs.config.DiscordEnabled,
 			"ibc_enabled":        bs.config.IBCEnabled,
 			"dex_enabled":        bs.config.DEXEnabled,
 			"monitoring_enabled": bs.config.MonitoringEnabled,
+			"tracing_enabled":    bs.config.OtelEndpoint != "",
+			"bot_metadata":       bs.config.BotMetadata.Enabled,
 		},
 	}
 	
+	status["chain_id"] = bs.chainIDStatus()
+	
+	if bs.chainPhase != nil {
+		status["chain_phase"] = bs.chainPhase.GetStatus()
+	}
+	
 	// Add component statuses
 	componentStatuses := make(map[string]interface{})
 	
@@ -453,6 +1142,10 @@ func (bs *BotService) GetStatus() map[string]interface{} {
 		componentStatuses["ibc_relayer"] = bs.ibcRelayer.GetStatus()
 	}
 	
+	if bs.escrowMonitor != nil {
+		componentStatuses["ibc_escrow"] = bs.escrowMonitor.GetStatus()
+	}
+	
 	if bs.dexManager != nil {
 		componentStatuses["dex_manager"] = bs.dexManager.GetStatus()
 	}
@@ -463,14 +1156,62 @@ func (bs *BotService) GetStatus() map[string]interface{} {
 	
 	if bs.telegramAlert != nil {
 		componentStatuses["telegram_alert"] = bs.telegramAlert.GetStatistics()
+		if onCall := bs.telegramAlert.OnCallStatus(time.Now()); onCall != nil {
+			status["on_call"] = onCall
+		}
 	}
 	
 	status["components"] = componentStatuses
 	
+	if bs.queryCache != nil {
+		status["query_cache"] = bs.queryCache.Status()
+	}
+	
+	if bs.outbound != nil {
+		status["outbound"] = bs.outbound.Status()
+	}
+	
+	if bs.incidents != nil {
+		status["incidents"] = bs.incidents.Status()
+	}
+	
+	if bs.statusServer != nil {
+		status["status_server"] = bs.statusServer.GetStatus()
+	}
+	
+	if bs.metricsServer != nil {
+		status["metrics_server"] = bs.metricsServer.GetStatus()
+	}
+	
+	if bs.updateChecker != nil {
+		status["update_check"] = bs.updateChecker.GetStatus()
+	}
+	
+	if bs.broadcastQueue != nil {
+		status["broadcast_queue"] = bs.broadcastQueue.GetStatus()
+	}
+	
+	if bs.config.MetricsEnabled {
+		metrics := make(map[string]float64)
+		if bs.escrowMonitor != nil {
+			for name, value := range bs.escrowMonitor.Metrics() {
+				metrics[name] = value
+			}
+		}
+		if bs.outbound != nil {
+			for name, value := range bs.outbound.Metrics() {
+				metrics[name] = value
+			}
+		}
+		status["metrics"] = metrics
+	}
+	
 	return status
 }
 
-// Stop gracefully stops the bot service
+// Stop gracefully stops the bot service in two phases: the components stop
+// taking new work, then their work in flight is drained up to per-component
+// deadlines, and what cannot finish is recorded for the next start
 func (bs *BotService) Stop() error {
 	bs.mu.Lock()
 	if !bs.running {
@@ -481,10 +1222,22 @@ func (bs *BotService) Stop() error {
 	bs.mu.Unlock()
 	
 	log.Printf("Stopping bot service...")
+	if err := bs.notifier.Notify("STOPPING=1"); err != nil {
+		log.Printf("Failed to notify systemd: %v", err)
+	}
 	
-	// Signal shutdown
+	// Phase one: stop taking new work
+	bs.BeginShutdown()
 	close(bs.shutdownChan)
 	
+	if bs.statusServer != nil {
+		bs.statusServer.Stop()
+	}
+	
+	if bs.metricsServer != nil {
+		bs.metricsServer.Stop()
+	}
+	
 	// Stop all components
 	if bs.rebalancer != nil {
 		bs.rebalancer.Stop()
@@ -506,18 +1259,33 @@ func (bs *BotService) Stop() error {
 		bs.rewardDistributor.Stop()
 	}
 	
+	// Phase two: drain the work in flight, then the alerts, including the
+	// ones raised while draining
+	results := bs.shutdown.Drain(drainedComponents...)
+	
 	// Send shutdown notification
 	if bs.telegramAlert != nil {
 		bs.telegramAlert.SendBotAlert("GXR Bot", "stopped", "Bot service stopped")
+	}
+	results = append(results, bs.shutdown.Drain(ComponentAlerts)...)
+	if bs.telegramAlert != nil {
 		bs.telegramAlert.Stop()
 	}
 	
-	// Wait for graceful shutdown or timeout
-	select {
-	case <-bs.shutdownComplete:
+	// Keep the usage since the last sample
+	bs.sampleCosts(time.Now())
+	
+	// Release the instance lock and lease
+	bs.releaseInstance()
+	
+	interrupted := 0
+	for _, result := range results {
+		interrupted += result.Interrupted
+	}
+	if interrupted > 0 {
+		log.Printf("Bot service stopped, %d operations recorded as interrupted for the next start", interrupted)
+	} else {
 		log.Printf("Bot service stopped gracefully")
-	case <-time.After(ShutdownTimeout):
-		log.Printf("Bot service shutdown timeout")
 	}
 	
 	return nil
@@ -525,9 +1293,16 @@ func (bs *BotService) Stop() error {
 
 // LoadConfig loads the bot configuration
 func LoadConfig(configPath string) (*BotConfig, error) {
+	return LoadConfigWithMigration(configPath, false)
+}
+
+// LoadConfigWithMigration loads the configuration, upgrading an older
+// config_version in memory. With writeBack the upgraded file is saved.
+func LoadConfigWithMigration(configPath string, writeBack bool) (*BotConfig, error) {
 	if configPath == "" {
 		configPath = DefaultConfigPath
 	}
+	configPath = expandHome(configPath)
 	
 	// Set default values
 	config := &BotConfig{
@@ -541,6 +1316,8 @@ func LoadConfig(configPath string) (*BotConfig, error) {
 		MaxConcurrentOps: 10,
 		HealthCheckEnabled: true,
 		MonitoringEnabled: true,
+		DataDir:       DefaultDataDir,
+		ConfigVersion: CurrentConfigVersion,
 	}
 	
 	// Try to load from file
@@ -550,7 +1327,25 @@ func LoadConfig(configPath string) (*BotConfig, error) {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
 		
-		if err := yaml.Unmarshal(data, config); err != nil {
+		migration, err := MigrateConfig(data)
+		if err != nil {
+			return nil, err
+		}
+		if migration.Migrated() {
+			log.Printf("Config %s is at version %d, migrating to %d", configPath, migration.FromVersion, migration.ToVersion)
+			for _, change := range migration.Changes {
+				log.Printf("Config migration: %s", change)
+			}
+			if writeBack {
+				if err := writeMigratedConfig(configPath, data, migration); err != nil {
+					return nil, err
+				}
+			} else {
+				log.Printf("Run with --migrate to write the upgraded config back")
+			}
+		}
+		
+		if err := yaml.Unmarshal(migration.Data, config); err != nil {
 			return nil, fmt.Errorf("failed to parse config file: %w", err)
 		}
 		
@@ -559,6 +1354,8 @@ func LoadConfig(configPath string) (*BotConfig, error) {
 		log.Printf("Config file not found, using defaults: %s", configPath)
 	}
 	
+	config.DataDir = expandHome(config.DataDir)
+	
 	// Validate configuration
 	if err := ValidateConfig(config); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -585,7 +1382,11 @@ func ValidateConfig(config *BotConfig) error {
 		return fmt.Errorf("validator_address is required")
 	}
 	
-	if config.TelegramEnabled {
+	if err := ValidateAlertSink(config.AlertSink); err != nil {
+		return err
+	}
+	
+	if config.usesTelegram() {
 		if config.TelegramToken == "" {
 			return fmt.Errorf("telegram_token is required when telegram is enabled")
 		}
@@ -593,11 +1394,154 @@ func ValidateConfig(config *BotConfig) error {
 			return fmt.Errorf("telegram_chat_id is required when telegram is enabled")
 		}
 	}
+	if config.DiscordEnabled {
+		if err := ValidateDiscordWebhookURL(config.DiscordWebhookURL); err != nil {
+			return err
+		}
+	}
+	if config.NotifierCooldown < 0 {
+		return fmt.Errorf("notifier_cooldown must not be negative")
+	}
+	
+	if _, err := ParseThresholdSource(config.ThresholdSource); err != nil {
+		return err
+	}
+	if _, err := ParseRebalancerThresholds(config); err != nil {
+		return err
+	}
+	if err := ValidatePriceHistorySize(config.PriceHistorySize); err != nil {
+		return err
+	}
+	
+	if err := ValidateSwapVenueConfig(config.SwapVenue); err != nil {
+		return err
+	}
+	if err := ValidatePriceOracleConfig(config.PriceOracle, config.PriceSources); err != nil {
+		return err
+	}
+	if err := ValidatePriceSources(config.PriceSources); err != nil {
+		return err
+	}
+	
+	if err := ValidateUpdateCheck(config); err != nil {
+		return err
+	}
+	
+	if err := ValidateAlertAnomaly(config.AlertAnomaly); err != nil {
+		return err
+	}
+	
+	if err := ValidateIncidents(config.Incidents); err != nil {
+		return err
+	}
+	
+	if err := ValidateLogRotation(config.LogRotation); err != nil {
+		return err
+	}
+	
+	if err := ValidateIBCEscrow(config.IBCEscrow, config.IBCChannels); err != nil {
+		return err
+	}
+	
+	if err := ValidateOutbound(config.Outbound); err != nil {
+		return err
+	}
+	
+	if err := ValidateScores(config.Scores); err != nil {
+		return err
+	}
+	if err := ValidateShadow(config.Shadow); err != nil {
+		return err
+	}
+	
+	if err := ValidateShutdown(config.Shutdown); err != nil {
+		return err
+	}
+	
+	if err := ValidateOtelEndpoint(config.OtelEndpoint); err != nil {
+		return err
+	}
+	
+	if err := ValidateBotMetadata(config.BotMetadata, config.ValidatorMnemonic); err != nil {
+		return err
+	}
+	
+	for validator, chatID := range config.ValidatorChatMap {
+		if validator == "" {
+			return fmt.Errorf("validator_chat_map contains an empty validator address")
+		}
+		if !isValidChatID(chatID) {
+			return fmt.Errorf("validator_chat_map: invalid chat ID %q for %s", chatID, validator)
+		}
+	}
+	
+	if config.TelegramMaxMessageSize != 0 &&
+		(config.TelegramMaxMessageSize < MinMessageSizeLimit || config.TelegramMaxMessageSize > MessageSizeLimit) {
+		return fmt.Errorf("telegram_max_message_size must be between %d and %d", MinMessageSizeLimit, MessageSizeLimit)
+	}
+	
+	if config.HealthFlapThreshold < 0 || config.HealthFlapWindow < 0 {
+		return fmt.Errorf("health_flap_threshold and health_flap_window cannot be negative")
+	}
+	
+	if config.BroadcastMaxAge < 0 {
+		return fmt.Errorf("broadcast_max_age cannot be negative")
+	}
+	
+	if config.TxFee < 0 {
+		return fmt.Errorf("tx_fee cannot be negative")
+	}
+	
+	if config.AlertSpillMaxAge < 0 {
+		return fmt.Errorf("alert_spill_max_age cannot be negative")
+	}
+	
+	if err := ValidateTxAuthz(config.TxAuthz, config.ValidatorAddress); err != nil {
+		return err
+	}
+	
+	if err := ValidateRole(config.Role, config.StandbyPromoteAfter); err != nil {
+		return err
+	}
+	
+	if config.AlertLatencyThreshold < 0 {
+		return fmt.Errorf("alert_latency_threshold cannot be negative")
+	}
+	
+	if config.QuietHours.Enabled {
+		if _, err := ParseQuietHours(config.QuietHours); err != nil {
+			return err
+		}
+	}
+	
+	if err := ValidateStatusServer(config.StatusServer); err != nil {
+		return err
+	}
+	if config.MetricsPort < 0 || config.MetricsPort > 65535 {
+		return fmt.Errorf("metrics_port must be between 1 and 65535, got %d", config.MetricsPort)
+	}
+	
+	if config.OnCallSchedule.Enabled {
+		if _, err := ParseOnCallSchedule(config.OnCallSchedule); err != nil {
+			return err
+		}
+	}
+	
+	if config.IBCPacketTimeout < 0 {
+		return fmt.Errorf("ibc_packet_timeout cannot be negative")
+	}
+	if err := ValidateIBCCounterparties(config.IBCCounterparties, config.IBCChannels); err != nil {
+		return err
+	}
 	
 	if config.CheckInterval < 1*time.Minute {
 		return fmt.Errorf("check_interval must be at least 1 minute")
 	}
 	
+	if config.PoolBalanceCheckInterval < 0 {
+		return fmt.Errorf("pool_balance_check_interval cannot be negative")
+	}
+	
 	if config.SwapCooldown < 1*time.Hour {
 		return fmt.Errorf("swap_cooldown must be at least 1 hour")
 	}
@@ -616,46 +1560,85 @@ func ValidateConfig(config *BotConfig) error {
 // CreateRootCmd creates the root command
 func CreateRootCmd() *cobra.Command {
 	var configPath string
+	var allowDuplicate bool
+	var migrate bool
+	var overrideChainID bool
 	
 	rootCmd := &cobra.Command{
 		Use:   "gxr-bot",
 		Short: "GXR Blockchain Bot Service",
 		Long:  "Enhanced GXR blockchain bot with validator monitoring, rebalancing, and alert systems",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runBot(configPath)
+			return runBot(configPath, allowDuplicate, migrate, overrideChainID)
 		},
 	}
 	
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", DefaultConfigPath, "Path to configuration file")
+	rootCmd.Flags().BoolVar(&allowDuplicate, "allow-duplicate", false, "Keep read-only monitoring running when another instance is detected")
+	rootCmd.Flags().BoolVar(&migrate, "migrate", false, "Write the config file back when its config_version is outdated")
+	rootCmd.Flags().BoolVar(&overrideChainID, "override-chain-id-check", false, "Keep broadcasting when the node reports another chain-id than chain_id, e.g. during a migration")
 	
 	// Add subcommands
 	rootCmd.AddCommand(createStatusCmd())
 	rootCmd.AddCommand(createTestCmd())
 	rootCmd.AddCommand(createVersionCmd())
+	rootCmd.AddCommand(createReportCmd())
+	rootCmd.AddCommand(createAlertsCmd())
+	rootCmd.AddCommand(createIncidentsCmd())
+	rootCmd.AddCommand(createLogsCmd())
+	rootCmd.AddCommand(createSystemdCmd())
 	
 	return rootCmd
 }
 
 // runBot runs the main bot service
-func runBot(configPath string) error {
+func runBot(configPath string, allowDuplicate, migrate, overrideChainID bool) error {
 	// Load configuration
-	config, err := LoadConfig(configPath)
+	config, err := LoadConfigWithMigration(configPath, migrate)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 	
+	// Mask secrets in everything logged from here on
+	logFile, err := SetupLogging(config)
+	if err != nil {
+		return fmt.Errorf("failed to set up logging: %w", err)
+	}
+	if logFile != nil {
+		defer logFile.Close()
+	}
+	
+	// Export traces when otel_endpoint is set, flushing them on exit
+	shutdownTracing, err := SetupTracing(context.Background(), config)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), TracingShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+	
 	// Create bot service
 	botService, err := NewBotService(config)
 	if err != nil {
 		return fmt.Errorf("failed to create bot service: %w", err)
 	}
+	botService.overrideChainIDCheck = overrideChainID
 	
 	// Setup signal handling
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	
+	// Refuse to run two broadcasting instances for the same validator
+	if err := botService.AcquireInstance(ctx, allowDuplicate); err != nil {
+		return fmt.Errorf("failed to acquire bot instance: %w", err)
+	}
+	
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, shutdownSignals...)
 	
 	// Start bot service
 	go func() {
@@ -669,7 +1652,8 @@ func runBot(configPath string) error {
 	<-sigChan
 	log.Printf("Received shutdown signal")
 	
-	// Graceful shutdown
+	// Graceful shutdown: no new work once the loops see the cancellation
+	botService.BeginShutdown()
 	cancel()
 	return botService.Stop()
 }
@@ -689,7 +1673,7 @@ func createStatusCmd() *cobra.Command {
 
 // createTestCmd creates the test command
 func createTestCmd() *cobra.Command {
-	return &cobra.Command{
+	testCmd := &cobra.Command{
 		Use:   "test",
 		Short: "Test bot configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -702,6 +1686,10 @@ func createTestCmd() *cobra.Command {
 			return nil
 		},
 	}
+	
+	testCmd.AddCommand(createTestAlertsCmd())
+	
+	return testCmd
 }
 
 // createVersionCmd creates the version command
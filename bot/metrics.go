@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// DefaultMetricsPort is the port of the metrics server when metrics_port is unset
+	DefaultMetricsPort = 9464
+	// MetricsServerShutdownTimeout bounds the graceful shutdown of the metrics server
+	MetricsServerShutdownTimeout = 5 * time.Second
+)
+
+var (
+	rebalancerStateDesc = prometheus.NewDesc("gxr_rebalancer_state",
+		"1 for the current rebalancer state, 0 for the others", []string{"state"}, nil)
+	priceDesc = prometheus.NewDesc("gxr_price_usd",
+		"Latest GXR price seen by the rebalancer", nil, nil)
+	validatorsDesc = prometheus.NewDesc("gxr_validators",
+		"Monitored validators by status", []string{"status"}, nil)
+	heartbeatAgeDesc = prometheus.NewDesc("gxr_bot_heartbeat_age_seconds",
+		"Seconds since the last bot heartbeat of a validator", []string{"validator"}, nil)
+	ibcRelaysDesc = prometheus.NewDesc("gxr_ibc_relays_total",
+		"IBC packets relayed, by result", []string{"result"}, nil)
+	dexRefillsDesc = prometheus.NewDesc("gxr_dex_pool_refills_total",
+		"Refills sent to a DEX pool", []string{"pool"}, nil)
+	alertQueueDesc = prometheus.NewDesc("gxr_alert_queue_depth",
+		"Alerts and bot transactions waiting to be sent, by queue", []string{"queue"}, nil)
+)
+
+// rebalancerStates are the states exported by gxr_rebalancer_state
+var rebalancerStates = []RebalanceState{StateActive, StateMonitorOnly, StateEmergencyStop, StateError, StateStopped}
+
+// MetricsServer serves the bot status as Prometheus metrics on /metrics. The
+// metrics are read from the component statuses on every scrape, so they
+// always match /status.
+type MetricsServer struct {
+	bs       *BotService
+	addr     string
+	registry *prometheus.Registry
+	server   *http.Server
+
+	// now is the time heartbeat ages are measured against
+	now func() time.Time
+}
+
+// NewMetricsServer creates the metrics server of a bot service listening on
+// port, DefaultMetricsPort if port is 0
+func NewMetricsServer(bs *BotService, port int) *MetricsServer {
+	if port == 0 {
+		port = DefaultMetricsPort
+	}
+
+	m := &MetricsServer{
+		bs:       bs,
+		addr:     fmt.Sprintf(":%d", port),
+		registry: prometheus.NewRegistry(),
+		now:      time.Now,
+	}
+	m.registry.MustRegister(statusCollector{m})
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	m.server = &http.Server{
+		Addr:              m.addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return m
+}
+
+// Handler returns the /metrics handler
+func (m *MetricsServer) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{ErrorLog: log.Default()})
+}
+
+// Start listens and serves in the background
+func (m *MetricsServer) Start() error {
+	listener, err := net.Listen("tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", m.addr, err)
+	}
+
+	go func() {
+		if err := m.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Metrics server stopped: %v", err)
+		}
+	}()
+
+	log.Printf("Metrics server listening on %s", listener.Addr())
+	return nil
+}
+
+// Stop shuts the server down, waiting for scrapes in flight
+func (m *MetricsServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), MetricsServerShutdownTimeout)
+	defer cancel()
+
+	if err := m.server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to shut down metrics server: %v", err)
+	}
+}
+
+// GetStatus returns the metrics server status
+func (m *MetricsServer) GetStatus() map[string]interface{} {
+	return map[string]interface{}{
+		"listen_addr": m.addr,
+	}
+}
+
+// statusCollector turns the bot status into metrics. It describes no metrics
+// up front since their labels depend on the validators, pools and channels
+// seen at scrape time.
+type statusCollector struct {
+	m *MetricsServer
+}
+
+func (c statusCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c statusCollector) Collect(ch chan<- prometheus.Metric) {
+	bs := c.m.bs
+	status := bs.GetStatus()
+	components, _ := status["components"].(map[string]interface{})
+
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+	}
+	counter := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, labels...)
+	}
+
+	if rebalancer, ok := components["rebalancer"].(map[string]interface{}); ok {
+		current, _ := rebalancer["state"].(string)
+		for _, state := range rebalancerStates {
+			value := 0.0
+			if state.String() == current {
+				value = 1
+			}
+			gauge(rebalancerStateDesc, value, state.String())
+		}
+		if price, ok := statusNumber(rebalancer, "current_price"); ok {
+			gauge(priceDesc, price)
+		}
+	}
+
+	if monitor, ok := components["validator_monitor"].(map[string]interface{}); ok {
+		if active, ok := statusNumber(monitor, "active_validators"); ok {
+			gauge(validatorsDesc, active, "active")
+		}
+		if inactive, ok := statusNumber(monitor, "inactive_validators"); ok {
+			gauge(validatorsDesc, inactive, "inactive")
+		}
+	}
+	if bs.validatorMonitor != nil {
+		now := c.m.now()
+		for validator, heartbeat := range bs.validatorMonitor.BotHeartbeats() {
+			gauge(heartbeatAgeDesc, now.Sub(heartbeat).Seconds(), validator)
+		}
+	}
+
+	if relayer, ok := components["ibc_relayer"].(map[string]interface{}); ok {
+		if relayed, ok := statusNumber(relayer, "relay_count"); ok {
+			counter(ibcRelaysDesc, relayed, "success")
+		}
+		if failed, ok := statusNumber(relayer, "relay_failures"); ok {
+			counter(ibcRelaysDesc, failed, "failure")
+		}
+	}
+
+	if dex, ok := components["dex_manager"].(map[string]interface{}); ok {
+		pools, _ := dex["pools"].(map[string]interface{})
+		for name, pool := range pools {
+			if pool, ok := pool.(map[string]interface{}); ok {
+				if refills, ok := statusNumber(pool, "refill_count"); ok {
+					counter(dexRefillsDesc, refills, name)
+				}
+			}
+		}
+	}
+
+	if alerts, ok := components["telegram_alert"].(map[string]interface{}); ok {
+		for queue, key := range map[string]string{
+			"telegram": "queue_size",
+			"digest":   "pending_digest_count",
+			"spill":    "spilled_alerts",
+		} {
+			if depth, ok := statusNumber(alerts, key); ok {
+				gauge(alertQueueDesc, depth, queue)
+			}
+		}
+	}
+	if broadcasts, ok := status["broadcast_queue"].(map[string]interface{}); ok {
+		if depth, ok := statusNumber(broadcasts, "pending"); ok {
+			gauge(alertQueueDesc, depth, "broadcast")
+		}
+	}
+
+	// The metrics /status already reports, such as the escrow balances and
+	// the outbound request counters
+	reported, _ := status["metrics"].(map[string]float64)
+	names := make([]string, 0, len(reported))
+	for name := range reported {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		metric, labels, err := parseMetricName(name)
+		if err != nil {
+			log.Printf("Skipping status metric %s: %v", name, err)
+			continue
+		}
+		desc := prometheus.NewDesc(metric, "Reported in the metrics of the bot status", nil, labels)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.UntypedValue, reported[name])
+	}
+}
+
+// statusNumber returns a numeric value of a status map
+func statusNumber(status map[string]interface{}, key string) (float64, bool) {
+	switch value := status[key].(type) {
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case float64:
+		return value, true
+	default:
+		return 0, false
+	}
+}
+
+// parseMetricName splits a status metric name like
+// gxr_outbound_requests_total{endpoint="rpc"} into its name and labels
+func parseMetricName(name string) (string, prometheus.Labels, error) {
+	open := strings.IndexByte(name, '{')
+	if open < 0 {
+		return name, nil, nil
+	}
+	if !strings.HasSuffix(name, "}") {
+		return "", nil, fmt.Errorf("unterminated labels")
+	}
+
+	labels := prometheus.Labels{}
+	for _, pair := range strings.Split(name[open+1:len(name)-1], ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+			return "", nil, fmt.Errorf("malformed label %q", pair)
+		}
+		labels[key] = value[1 : len(value)-1]
+	}
+	return name[:open], labels, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+)
+
+func TestMetricsServerExportsComponentStatus(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	rebalancer := newTestRebalancer(t, &BotConfig{}, nil)
+	rebalancer.state = StateMonitorOnly
+	rebalancer.currentPrice = 4.25
+
+	monitor := NewValidatorMonitor(&BotConfig{}, client.Context{}, nil)
+	monitor.activeValidators = 3
+	monitor.totalInactiveValidators = 1
+	monitor.botHeartbeats["gxrvaloper1ours"] = now.Add(-90 * time.Second)
+
+	relayer := NewIBCRelayer(&BotConfig{})
+	relayer.relayCount = 7
+	relayer.relayFailures = 2
+
+	dex := NewDEXManager(&BotConfig{})
+	dex.pools["GXR/TON"] = &DEXPool{Name: "GXR/TON", Address: "gxr1dexpool1ton", Active: true, RefillCount: 4}
+
+	bs := &BotService{
+		config:           &BotConfig{MetricsEnabled: true},
+		healthStatus:     map[string]bool{},
+		healthTracker:    NewHealthTracker(0, 0),
+		rebalancer:       rebalancer,
+		validatorMonitor: monitor,
+		ibcRelayer:       relayer,
+		dexManager:       dex,
+		broadcastQueue:   NewBroadcastQueue(nil, nil, "", time.Hour),
+		outbound:         NewOutboundLimiter(OutboundConfig{}),
+	}
+	bs.outbound.Wait(context.Background(), "https://rpc.example")
+	metrics := NewMetricsServer(bs, 0)
+	metrics.now = func() time.Time { return now }
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, want := range []string{
+		`gxr_rebalancer_state{state="active"} 0`,
+		`gxr_rebalancer_state{state="monitor_only"} 1`,
+		`gxr_price_usd 4.25`,
+		`gxr_validators{status="active"} 3`,
+		`gxr_validators{status="inactive"} 1`,
+		`gxr_bot_heartbeat_age_seconds{validator="gxrvaloper1ours"} 90`,
+		`gxr_ibc_relays_total{result="success"} 7`,
+		`gxr_ibc_relays_total{result="failure"} 2`,
+		`gxr_dex_pool_refills_total{pool="GXR/TON"} 4`,
+		`gxr_alert_queue_depth{queue="broadcast"} 0`,
+		`gxr_outbound_requests_total{endpoint="https://rpc.example"} 1`,
+	} {
+		if !strings.Contains(string(body), want+"\n") {
+			t.Errorf("metrics lack %s:\n%s", want, body)
+		}
+	}
+}
+
+func TestParseMetricName(t *testing.T) {
+	name, labels, err := parseMetricName(`gxr_ibc_escrow_balance_ugen{channel="channel-0"}`)
+	if err != nil || name != "gxr_ibc_escrow_balance_ugen" || labels["channel"] != "channel-0" {
+		t.Fatalf("parsed %s %v, %v", name, labels, err)
+	}
+	if _, _, err := parseMetricName(`gxr_outbound_requests_total{endpoint=rpc}`); err == nil {
+		t.Fatal("unquoted label value accepted")
+	}
+}
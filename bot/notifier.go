@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// NotifierDiscord is the name of the Discord channel
+	NotifierDiscord = "discord"
+	// DefaultNotifierCooldown is how long a failing notification channel
+	// stays disabled before it is tried again
+	DefaultNotifierCooldown = 15 * time.Minute
+)
+
+// Notifier is a channel alerts can be sent to
+type Notifier interface {
+	SendAlert(message string) error
+	SendAlertWithType(alertType AlertType, title, message string) error
+	Stop()
+	IsRunning() bool
+}
+
+var (
+	_ Notifier = (*TelegramAlert)(nil)
+	_ Notifier = (*DiscordAlert)(nil)
+	_ Notifier = (*MultiNotifier)(nil)
+)
+
+// notifierChannel is a channel of a MultiNotifier with its delivery record
+type notifierChannel struct {
+	name                string
+	notifier            Notifier
+	sent                int64
+	failures            int64
+	consecutiveFailures int
+	disabledUntil       time.Time
+	lastError           string
+}
+
+// NotifierChannelStatus is the delivery record of a notification channel
+type NotifierChannelStatus struct {
+	Name                string `json:"name"`
+	Running             bool   `json:"running"`
+	Enabled             bool   `json:"enabled"`
+	Sent                int64  `json:"sent"`
+	Failures            int64  `json:"failures"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	DisabledUntil       string `json:"disabled_until,omitempty"`
+	LastError           string `json:"last_error,omitempty"`
+}
+
+// MultiNotifier sends every alert to all of its channels concurrently. A
+// channel failing more than RetryAttempts times in a row is disabled for the
+// cooldown and then tried again; until it succeeds once more a single
+// failure disables it again.
+type MultiNotifier struct {
+	mu          sync.Mutex
+	channels    []*notifierChannel
+	maxFailures int
+	cooldown    time.Duration
+	now         func() time.Time
+}
+
+// NewMultiNotifier creates a notifier without channels. A zero cooldown
+// means DefaultNotifierCooldown.
+func NewMultiNotifier(cooldown time.Duration) *MultiNotifier {
+	if cooldown <= 0 {
+		cooldown = DefaultNotifierCooldown
+	}
+	return &MultiNotifier{maxFailures: RetryAttempts, cooldown: cooldown, now: time.Now}
+}
+
+// Register adds a channel under name
+func (m *MultiNotifier) Register(name string, notifier Notifier) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.channels = append(m.channels, &notifierChannel{name: name, notifier: notifier})
+}
+
+// Len returns the number of registered channels
+func (m *MultiNotifier) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.channels)
+}
+
+// SendAlert sends a plain message to every enabled channel
+func (m *MultiNotifier) SendAlert(message string) error {
+	return m.broadcast(func(n Notifier) error { return n.SendAlert(message) })
+}
+
+// SendAlertWithType sends a typed alert to every enabled channel
+func (m *MultiNotifier) SendAlertWithType(alertType AlertType, title, message string) error {
+	return m.broadcast(func(n Notifier) error { return n.SendAlertWithType(alertType, title, message) })
+}
+
+// broadcast runs send for every enabled, running channel concurrently and
+// returns the failures joined, each prefixed with its channel
+func (m *MultiNotifier) broadcast(send func(Notifier) error) error {
+	m.mu.Lock()
+	now := m.now()
+	var active []*notifierChannel
+	for _, ch := range m.channels {
+		if now.Before(ch.disabledUntil) || !ch.notifier.IsRunning() {
+			continue
+		}
+		active = append(active, ch)
+	}
+	m.mu.Unlock()
+
+	if len(active) == 0 {
+		return fmt.Errorf("no notification channel available")
+	}
+
+	errs := make([]error, len(active))
+	var wg sync.WaitGroup
+	for i, ch := range active {
+		wg.Add(1)
+		go func(i int, n Notifier) {
+			defer wg.Done()
+			errs[i] = send(n)
+		}(i, ch.notifier)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now = m.now()
+	var failed []error
+	for i, ch := range active {
+		if errs[i] == nil {
+			ch.sent++
+			ch.consecutiveFailures = 0
+			ch.lastError = ""
+			continue
+		}
+
+		ch.failures++
+		ch.consecutiveFailures++
+		ch.lastError = errs[i].Error()
+		failed = append(failed, fmt.Errorf("%s: %w", ch.name, errs[i]))
+		if ch.consecutiveFailures > m.maxFailures {
+			ch.disabledUntil = now.Add(m.cooldown)
+			log.Printf("Notification channel %s disabled for %s after %d failures in a row: %v",
+				ch.name, m.cooldown, ch.consecutiveFailures, errs[i])
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// Stop stops every channel
+func (m *MultiNotifier) Stop() {
+	m.mu.Lock()
+	channels := append([]*notifierChannel(nil), m.channels...)
+	m.mu.Unlock()
+
+	for _, ch := range channels {
+		ch.notifier.Stop()
+	}
+}
+
+// IsRunning reports whether any channel is running
+func (m *MultiNotifier) IsRunning() bool {
+	m.mu.Lock()
+	channels := append([]*notifierChannel(nil), m.channels...)
+	m.mu.Unlock()
+
+	for _, ch := range channels {
+		if ch.notifier.IsRunning() {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns the delivery record of every channel
+func (m *MultiNotifier) Status() []NotifierChannelStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	statuses := make([]NotifierChannelStatus, len(m.channels))
+	for i, ch := range m.channels {
+		statuses[i] = NotifierChannelStatus{
+			Name:                ch.name,
+			Running:             ch.notifier.IsRunning(),
+			Enabled:             !now.Before(ch.disabledUntil),
+			Sent:                ch.sent,
+			Failures:            ch.failures,
+			ConsecutiveFailures: ch.consecutiveFailures,
+			LastError:           ch.lastError,
+		}
+		if now.Before(ch.disabledUntil) {
+			statuses[i].DisabledUntil = ch.disabledUntil.Format(time.RFC3339)
+		}
+	}
+	return statuses
+}
+
+// channelMessage returns an alert's message followed by its metadata, for
+// channels that show the title separately
+func channelMessage(alert *Alert) string {
+	keys := make([]string, 0, len(alert.Metadata))
+	for key := range alert.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := []string{alert.Message}
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("• %s: %v", key, alert.Metadata[key]))
+	}
+	return strings.Join(lines, "\n")
+}
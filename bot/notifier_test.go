@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNotifier records the alerts sent to it and fails while err is set
+type fakeNotifier struct {
+	mu      sync.Mutex
+	titles  []string
+	err     error
+	stopped bool
+}
+
+func (f *fakeNotifier) SendAlert(message string) error {
+	return f.SendAlertWithType(AlertTypeInfo, "", message)
+}
+
+func (f *fakeNotifier) SendAlertWithType(alertType AlertType, title, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.titles = append(f.titles, title)
+	return nil
+}
+
+func (f *fakeNotifier) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = true
+}
+
+func (f *fakeNotifier) IsRunning() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.stopped
+}
+
+func (f *fakeNotifier) sent() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.titles)
+}
+
+func TestMultiNotifierDisablesFailingChannel(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	m := NewMultiNotifier(10 * time.Minute)
+	m.now = func() time.Time { return clock }
+
+	telegram, discord := &fakeNotifier{}, &fakeNotifier{err: errors.New("webhook deleted")}
+	m.Register("telegram", telegram)
+	m.Register(NotifierDiscord, discord)
+
+	// Failures are reported per channel, the other channel still receives
+	// the alert
+	for i := 0; i < RetryAttempts; i++ {
+		err := m.SendAlertWithType(AlertTypeWarning, "Jailed", "validator jailed")
+		if err == nil || !strings.Contains(err.Error(), "discord: webhook deleted") {
+			t.Fatalf("send %d returned %v", i+1, err)
+		}
+		if m.Status()[1].DisabledUntil != "" {
+			t.Fatalf("discord disabled after %d failures", i+1)
+		}
+	}
+	m.SendAlertWithType(AlertTypeWarning, "Jailed", "validator jailed")
+	status := m.Status()[1]
+	if status.Enabled || status.ConsecutiveFailures != RetryAttempts+1 {
+		t.Fatalf("discord status after %d failures: %+v", RetryAttempts+1, status)
+	}
+
+	// A disabled channel is skipped until the cooldown ends
+	discord.err = nil
+	if err := m.SendAlert("skipped by discord"); err != nil {
+		t.Fatal(err)
+	}
+	if telegram.sent() != RetryAttempts+2 || discord.sent() != 0 {
+		t.Fatalf("sent %d to telegram, %d to discord", telegram.sent(), discord.sent())
+	}
+
+	// Then one failure disables it again, one success re-enables it
+	clock = clock.Add(10 * time.Minute)
+	discord.err = errors.New("still down")
+	m.SendAlert("retry")
+	if m.Status()[1].Enabled {
+		t.Fatal("discord enabled after failing its retry")
+	}
+	clock = clock.Add(10 * time.Minute)
+	discord.err = nil
+	if err := m.SendAlert("recovered"); err != nil {
+		t.Fatal(err)
+	}
+	if status := m.Status()[1]; !status.Enabled || status.ConsecutiveFailures != 0 || discord.sent() != 1 {
+		t.Fatalf("discord status after recovering: %+v", status)
+	}
+
+	m.Stop()
+	if m.IsRunning() {
+		t.Fatal("running after stop")
+	}
+	if err := m.SendAlert("after stop"); err == nil {
+		t.Fatal("sent with every channel stopped")
+	}
+}
+
+func TestDiscordOnlyAlerts(t *testing.T) {
+	var mu sync.Mutex
+	var messages []DiscordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/webhooks/1/token" {
+			http.Error(w, "unknown webhook", http.StatusNotFound)
+			return
+		}
+		var msg DiscordMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		messages = append(messages, msg)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config := &BotConfig{DiscordEnabled: true, DiscordWebhookURL: server.URL + "/api/webhooks/1/token"}
+	if err := ValidateDiscordWebhookURL(config.DiscordWebhookURL); err != nil {
+		t.Fatal(err)
+	}
+	ta := newTelegramAlert(config)
+	if err := ta.validateConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	ta.handleAlert(&Alert{Type: AlertTypeCritical, Title: "Validator Jailed", Message: "missed blocks", Metadata: map[string]interface{}{"validator": "gxrvaloper1ours"}})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(messages) != 1 || len(messages[0].Embeds) != 1 {
+		t.Fatalf("discord received %+v", messages)
+	}
+	embed := messages[0].Embeds[0]
+	if embed.Title != "🚨 CRITICAL: Validator Jailed" || embed.Description != "missed blocks\n• validator: gxrvaloper1ours" || embed.Color != discordColors[AlertTypeCritical] {
+		t.Fatalf("embed = %+v", embed)
+	}
+	if ta.successfulAlerts != 1 {
+		t.Fatalf("%d successful alerts, want 1", ta.successfulAlerts)
+	}
+
+	// Errors do not leak the webhook token
+	bad := &DiscordAlert{webhookURL: "http://127.0.0.1:1/api/webhooks/1/secret-token", client: &http.Client{Timeout: time.Second}, now: time.Now, running: true}
+	if err := bad.SendAlert("unreachable"); err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Fatalf("error = %v", err)
+	}
+}
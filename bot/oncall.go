@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// OnCallConfig defines a weekly on-call rotation. Critical alerts mention the
+// current on-call member and are also sent to their chat.
+type OnCallConfig struct {
+	Enabled  bool           `yaml:"enabled"`
+	Timezone string         `yaml:"timezone"` // IANA name, e.g. "Europe/Berlin"
+	Handover string         `yaml:"handover"` // first shift start in local time, "2006-01-02 15:04"
+	Rotation []OnCallMember `yaml:"rotation"`
+}
+
+// OnCallMember is one operator in the rotation
+type OnCallMember struct {
+	Name   string `yaml:"name"`
+	UserID int64  `yaml:"user_id"` // Telegram user mentioned in critical alerts
+	ChatID string `yaml:"chat_id"` // chat that also receives critical alerts
+}
+
+// Mention returns the member as a Telegram Markdown mention, or the plain
+// name when no user ID is configured
+func (m OnCallMember) Mention() string {
+	if m.UserID == 0 {
+		return m.Name
+	}
+	return fmt.Sprintf("[%s](tg://user?id=%d)", m.Name, m.UserID)
+}
+
+// OnCallSchedule is the parsed form of OnCallConfig. Shifts start every week
+// on the weekday and at the wall-clock time of the first handover, so
+// handovers stay at the same local time across DST changes.
+type OnCallSchedule struct {
+	members  []OnCallMember
+	first    time.Time
+	location *time.Location
+}
+
+// OnCallShift is one member's shift
+type OnCallShift struct {
+	Member OnCallMember
+	Next   OnCallMember
+	Start  time.Time
+	End    time.Time
+}
+
+// ParseOnCallSchedule parses and validates an on-call configuration
+func ParseOnCallSchedule(cfg OnCallConfig) (*OnCallSchedule, error) {
+	if len(cfg.Rotation) == 0 {
+		return nil, fmt.Errorf("oncall_schedule.rotation must not be empty")
+	}
+
+	for i, member := range cfg.Rotation {
+		if member.Name == "" {
+			return nil, fmt.Errorf("oncall_schedule.rotation[%d] has no name", i)
+		}
+		if member.UserID == 0 && member.ChatID == "" {
+			return nil, fmt.Errorf("oncall_schedule.rotation[%d] (%s) needs a user_id or chat_id", i, member.Name)
+		}
+		if member.ChatID != "" && !isValidChatID(member.ChatID) {
+			return nil, fmt.Errorf("oncall_schedule.rotation[%d] (%s) has an invalid chat_id %q", i, member.Name, member.ChatID)
+		}
+	}
+
+	location := time.UTC
+	if cfg.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oncall_schedule.timezone: %w", err)
+		}
+	}
+
+	first, err := time.ParseInLocation("2006-01-02 15:04", strings.TrimSpace(cfg.Handover), location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid oncall_schedule.handover, expected \"YYYY-MM-DD HH:MM\": %w", err)
+	}
+
+	return &OnCallSchedule{
+		members:  append([]OnCallMember(nil), cfg.Rotation...),
+		first:    first,
+		location: location,
+	}, nil
+}
+
+// handover returns the start of shift n, counted from the first handover.
+// Shifts before the first handover have negative numbers.
+func (s *OnCallSchedule) handover(n int) time.Time {
+	return time.Date(s.first.Year(), s.first.Month(), s.first.Day()+7*n,
+		s.first.Hour(), s.first.Minute(), 0, 0, s.location)
+}
+
+// member returns the member on call during shift n
+func (s *OnCallSchedule) member(n int) OnCallMember {
+	i := n % len(s.members)
+	if i < 0 {
+		i += len(s.members)
+	}
+	return s.members[i]
+}
+
+// ShiftAt returns the shift running at t
+func (s *OnCallSchedule) ShiftAt(t time.Time) OnCallShift {
+	// Estimate the shift from calendar days, then correct for the time of day
+	local := t.In(s.location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	firstDay := time.Date(s.first.Year(), s.first.Month(), s.first.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(day.Sub(firstDay) / (24 * time.Hour))
+
+	n := days / 7
+	if days < 0 && days%7 != 0 {
+		n--
+	}
+	if s.handover(n).After(t) {
+		n--
+	} else if !s.handover(n + 1).After(t) {
+		n++
+	}
+
+	return OnCallShift{
+		Member: s.member(n),
+		Next:   s.member(n + 1),
+		Start:  s.handover(n),
+		End:    s.handover(n + 1),
+	}
+}
+
+// Describe renders a shift for status output and Telegram replies
+func (s *OnCallSchedule) Describe(shift OnCallShift) string {
+	return fmt.Sprintf("%s until %s, then %s",
+		shift.Member.Name, shift.End.In(s.location).Format("Mon 2006-01-02 15:04 MST"), shift.Next.Name)
+}
+
+// Status returns the shift running at now for status output
+func (s *OnCallSchedule) Status(now time.Time) map[string]interface{} {
+	shift := s.ShiftAt(now)
+	return map[string]interface{}{
+		"name":     shift.Member.Name,
+		"user_id":  shift.Member.UserID,
+		"chat_id":  shift.Member.ChatID,
+		"since":    shift.Start.Format(time.RFC3339),
+		"until":    shift.End.Format(time.RFC3339),
+		"next":     shift.Next.Name,
+		"rotation": len(s.members),
+		"timezone": s.location.String(),
+	}
+}
+
+// newOnCallHandoverAlert announces the start of a shift
+func newOnCallHandoverAlert(previous OnCallMember, shift OnCallShift, description string, now time.Time) *Alert {
+	return &Alert{
+		ID:        fmt.Sprintf("oncall-%d", shift.Start.Unix()),
+		Type:      AlertTypeInfo,
+		Priority:  AlertPriorityLow,
+		Title:     "On-Call Handover",
+		Message:   fmt.Sprintf("%s hands over to %s.\n%s", previous.Name, shift.Member.Mention(), description),
+		Timestamp: now,
+		Metadata: map[string]interface{}{
+			"oncall":   shift.Member.Name,
+			"previous": previous.Name,
+		},
+	}
+}
+
+// routeToOnCall mentions the on-call member in a critical alert and adds
+// their chat to its routes
+func (ta *TelegramAlert) routeToOnCall(alert *Alert, now time.Time) {
+	if ta.onCall == nil || alert.Type != AlertTypeCritical {
+		return
+	}
+
+	member := ta.onCall.ShiftAt(now).Member
+	if alert.Metadata == nil {
+		alert.Metadata = make(map[string]interface{})
+	}
+	alert.Metadata["oncall"] = member.Mention()
+	if member.ChatID != "" {
+		alert.Routes = append(alert.Routes, member.ChatID)
+	}
+	ta.onCallAlerts++
+}
+
+// checkOnCallHandover sends an informational alert when a new shift has started
+func (ta *TelegramAlert) checkOnCallHandover(now time.Time) {
+	if alert := ta.onCallHandover(now); alert != nil {
+		ta.handleAlert(alert)
+	}
+}
+
+// onCallHandover records the shift running at now and returns the handover
+// alert if it differs from the last recorded one
+func (ta *TelegramAlert) onCallHandover(now time.Time) *Alert {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+
+	if ta.onCall == nil {
+		return nil
+	}
+
+	shift := ta.onCall.ShiftAt(now)
+	if shift.Start.Equal(ta.onCallShiftStart) {
+		return nil
+	}
+
+	previous, first := ta.onCallMember, ta.onCallShiftStart.IsZero()
+	ta.onCallShiftStart = shift.Start
+	ta.onCallMember = shift.Member
+	if first {
+		return nil
+	}
+
+	ta.onCallHandovers++
+	log.Printf("On-call handover: %s -> %s", previous.Name, shift.Member.Name)
+	return newOnCallHandoverAlert(previous, shift, ta.onCall.Describe(shift), now)
+}
+
+// OnCallStatus returns the current on-call shift, or nil without a schedule
+func (ta *TelegramAlert) OnCallStatus(now time.Time) map[string]interface{} {
+	if ta.onCall == nil {
+		return nil
+	}
+	return ta.onCall.Status(now)
+}
+
+// onCallReply answers the /oncall command
+func (ta *TelegramAlert) onCallReply(now time.Time) string {
+	if ta.onCall == nil {
+		return "No on-call schedule is configured"
+	}
+	return fmt.Sprintf("👤 *On call*\n%s", ta.onCall.Describe(ta.onCall.ShiftAt(now)))
+}
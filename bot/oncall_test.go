@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testRotation = []OnCallMember{
+	{Name: "ayu", UserID: 1001, ChatID: "2001"},
+	{Name: "budi", UserID: 1002},
+	{Name: "citra", ChatID: "2003"},
+}
+
+func mustOnCallSchedule(t *testing.T, timezone, handover string) *OnCallSchedule {
+	t.Helper()
+	schedule, err := ParseOnCallSchedule(OnCallConfig{
+		Enabled:  true,
+		Timezone: timezone,
+		Handover: handover,
+		Rotation: testRotation,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schedule
+}
+
+func TestOnCallScheduleRotationEdges(t *testing.T) {
+	s := mustOnCallSchedule(t, "", "2025-03-03 09:00")
+	first := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		at   time.Time
+		want string
+	}{
+		{first, "ayu"},
+		{first.Add(7*24*time.Hour - time.Second), "ayu"},
+		{first.Add(7 * 24 * time.Hour), "budi"},
+		{first.Add(14 * 24 * time.Hour), "citra"},
+		{first.Add(21 * 24 * time.Hour), "ayu"},
+		// Before the first handover the rotation runs backwards
+		{first.Add(-time.Second), "citra"},
+		{first.Add(-7*24*time.Hour - time.Second), "budi"},
+	}
+	for _, tc := range testCases {
+		if got := s.ShiftAt(tc.at).Member.Name; got != tc.want {
+			t.Errorf("on call at %s = %s, want %s", tc.at, got, tc.want)
+		}
+	}
+
+	shift := s.ShiftAt(first.Add(time.Hour))
+	if !shift.Start.Equal(first) || !shift.End.Equal(first.Add(7*24*time.Hour)) || shift.Next.Name != "budi" {
+		t.Fatalf("shift = %+v", shift)
+	}
+}
+
+func TestOnCallScheduleAcrossDST(t *testing.T) {
+	testCases := []struct {
+		name     string
+		timezone string
+		handover string
+	}{
+		{"berlin spring forward", "Europe/Berlin", "2025-03-24 09:00"},
+		{"berlin fall back", "Europe/Berlin", "2025-10-20 09:00"},
+		{"new york handover inside the skipped hour", "America/New_York", "2025-03-02 02:30"},
+		{"new york handover inside the repeated hour", "America/New_York", "2025-10-26 01:30"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := mustOnCallSchedule(t, tc.timezone, tc.handover)
+
+			// Shifts tile time without gaps or overlaps and only change at handovers
+			start := s.first.Add(-3 * 24 * time.Hour)
+			previous := s.ShiftAt(start)
+			for at := start; at.Before(s.first.Add(5 * 7 * 24 * time.Hour)); at = at.Add(15 * time.Minute) {
+				shift := s.ShiftAt(at)
+				if at.Before(shift.Start) || !at.Before(shift.End) {
+					t.Fatalf("shift %s-%s does not contain %s", shift.Start, shift.End, at)
+				}
+				if length := shift.End.Sub(shift.Start); length < 167*time.Hour || length > 169*time.Hour {
+					t.Fatalf("shift from %s lasts %s", shift.Start, length)
+				}
+				if !shift.Start.Equal(previous.Start) && !shift.Start.Equal(previous.End) {
+					t.Fatalf("shift at %s starts %s, previous ended %s", at, shift.Start, previous.End)
+				}
+				previous = shift
+			}
+		})
+	}
+
+	// Handovers keep their local wall-clock time when DST starts
+	s := mustOnCallSchedule(t, "Europe/Berlin", "2025-03-24 09:00")
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	handover := time.Date(2025, 3, 31, 9, 0, 0, 0, berlin)
+	if got := s.ShiftAt(handover.Add(-time.Minute)).Member.Name; got != "ayu" {
+		t.Fatalf("before the handover on call = %s, want ayu", got)
+	}
+	shift := s.ShiftAt(handover)
+	if shift.Member.Name != "budi" || !shift.Start.Equal(handover) || handover.UTC().Hour() != 7 {
+		t.Fatalf("after DST the handover is %s (%s), on call %s", shift.Start, handover.UTC(), shift.Member.Name)
+	}
+}
+
+func TestParseOnCallScheduleRejectsInvalidConfig(t *testing.T) {
+	valid := OnCallConfig{Enabled: true, Timezone: "Asia/Jakarta", Handover: "2025-01-06 08:00", Rotation: testRotation}
+	if _, err := ParseOnCallSchedule(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := map[string]func(c *OnCallConfig){
+		"empty rotation":   func(c *OnCallConfig) { c.Rotation = nil },
+		"unknown timezone": func(c *OnCallConfig) { c.Timezone = "Mars/Olympus" },
+		"bad handover":     func(c *OnCallConfig) { c.Handover = "monday 08:00" },
+		"no target":        func(c *OnCallConfig) { c.Rotation = []OnCallMember{{Name: "dewi"}} },
+		"bad chat":         func(c *OnCallConfig) { c.Rotation = []OnCallMember{{Name: "dewi", ChatID: "dewi"}} },
+	}
+	for name, mutate := range invalid {
+		cfg := valid
+		mutate(&cfg)
+		if _, err := ParseOnCallSchedule(cfg); err == nil {
+			t.Errorf("%s: config accepted", name)
+		}
+	}
+}
+
+// telegramRecorder is a fake Telegram API recording sent messages
+type telegramRecorder struct {
+	mu       sync.Mutex
+	messages []TelegramMessage
+}
+
+func (r *telegramRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var msg TelegramMessage
+	json.NewDecoder(req.Body).Decode(&msg)
+	r.mu.Lock()
+	r.messages = append(r.messages, msg)
+	r.mu.Unlock()
+	w.Write([]byte(`{"ok":true}`))
+}
+
+func (r *telegramRecorder) take() []TelegramMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	messages := r.messages
+	r.messages = nil
+	return messages
+}
+
+func newOnCallTestAlert(t *testing.T, recorder *telegramRecorder) *TelegramAlert {
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+
+	return &TelegramAlert{
+		config:         &BotConfig{},
+		client:         server.Client(),
+		apiURL:         server.URL,
+		chatID:         "-100100",
+		maxRetries:     1,
+		maxMessageSize: MessageSizeLimit,
+		alertCounts:    make(map[AlertType]int64),
+		running:        true,
+		sendToTelegram: true,
+		onCall:         mustOnCallSchedule(t, "", "2025-03-03 09:00"),
+	}
+}
+
+func TestCriticalAlertsRoutedToOnCall(t *testing.T) {
+	recorder := &telegramRecorder{}
+	ta := newOnCallTestAlert(t, recorder)
+
+	// Warnings only go to the normal routes
+	ta.handleAlert(&Alert{Type: AlertTypeWarning, Title: "Slow RPC"})
+	if messages := recorder.take(); len(messages) != 1 || strings.Contains(messages[0].Text, "oncall") {
+		t.Fatalf("warning delivered as %+v", messages)
+	}
+
+	// Alerts are routed by the wall clock, so ayu is always on call here
+	onCall, err := ParseOnCallSchedule(OnCallConfig{Handover: "2025-03-03 09:00", Rotation: testRotation[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ta.onCall = onCall
+
+	ta.handleAlert(&Alert{Type: AlertTypeCritical, Title: "Validator jailed", Timestamp: time.Now()})
+	messages := recorder.take()
+	if len(messages) != 2 || messages[0].ChatID != "-100100" || messages[1].ChatID != "2001" {
+		t.Fatalf("critical alert delivered as %+v", messages)
+	}
+	for _, msg := range messages {
+		if !strings.Contains(msg.Text, "[ayu](tg://user?id=1001)") {
+			t.Fatalf("critical alert does not mention ayu: %q", msg.Text)
+		}
+	}
+	if ta.onCallAlerts != 1 {
+		t.Fatalf("on-call alerts = %d", ta.onCallAlerts)
+	}
+}
+
+func TestOnCallHandoverAnnounced(t *testing.T) {
+	recorder := &telegramRecorder{}
+	ta := newOnCallTestAlert(t, recorder)
+	handover := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	// The first check records the running shift without announcing it
+	ta.checkOnCallHandover(handover.Add(-time.Hour))
+	ta.checkOnCallHandover(handover.Add(-time.Minute))
+	if messages := recorder.take(); len(messages) != 0 {
+		t.Fatalf("handover announced before the rotation: %+v", messages)
+	}
+
+	ta.checkOnCallHandover(handover)
+	ta.checkOnCallHandover(handover.Add(time.Minute))
+	messages := recorder.take()
+	if len(messages) != 1 {
+		t.Fatalf("got %d handover messages, want 1", len(messages))
+	}
+	if !strings.Contains(messages[0].Text, "ayu hands over to [budi](tg://user?id=1002)") {
+		t.Fatalf("handover message = %q", messages[0].Text)
+	}
+	if ta.onCallHandovers != 1 {
+		t.Fatalf("on-call handovers = %d", ta.onCallHandovers)
+	}
+}
+
+func TestOnCallCommand(t *testing.T) {
+	recorder := &telegramRecorder{}
+	ta := newOnCallTestAlert(t, recorder)
+	now := time.Date(2025, 3, 12, 12, 0, 0, 0, time.UTC)
+
+	update := func(chatID int64, text string) telegramUpdate {
+		var u telegramUpdate
+		raw := fmt.Sprintf(`{"update_id":1,"message":{"chat":{"id":%d},"text":%q}}`, chatID, text)
+		if err := json.Unmarshal([]byte(raw), &u); err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+
+	// Other chats and other commands are ignored
+	ta.handleUpdate(update(-999, "/oncall"), now)
+	ta.handleUpdate(update(-100100, "/start"), now)
+	if messages := recorder.take(); len(messages) != 0 {
+		t.Fatalf("unexpected replies: %+v", messages)
+	}
+
+	// Group commands may be addressed to the bot, on-call chats are alert chats too
+	ta.handleUpdate(update(-100100, "/oncall@gxrbot"), now)
+	ta.handleUpdate(update(2003, "/oncall"), now)
+	messages := recorder.take()
+	if len(messages) != 2 || messages[0].ChatID != "-100100" || messages[1].ChatID != "2003" {
+		t.Fatalf("replies = %+v", messages)
+	}
+	if !strings.Contains(messages[0].Text, "budi until Mon 2025-03-17 09:00 UTC, then citra") {
+		t.Fatalf("/oncall reply = %q", messages[0].Text)
+	}
+}
+
+func TestStatusReportsOnCall(t *testing.T) {
+	ta := newOnCallTestAlert(t, &telegramRecorder{})
+	bs := &BotService{
+		config:        &BotConfig{},
+		healthStatus:  make(map[string]bool),
+		healthTracker: NewHealthTracker(0, 0),
+		telegramAlert: ta,
+	}
+
+	onCall, ok := bs.GetStatus()["on_call"].(map[string]interface{})
+	if !ok || onCall["name"] != ta.onCall.ShiftAt(time.Now()).Member.Name {
+		t.Fatalf("status on_call = %v", onCall)
+	}
+}
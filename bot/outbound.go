@@ -0,0 +1,453 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Outbound request budgets. Public endpoints are shared with everyone else
+// and get the stricter defaults.
+const (
+	DefaultOutboundRequestsPerMinute = 120
+	DefaultOutboundBurst             = 20
+	DefaultPublicRequestsPerMinute   = 30
+	DefaultPublicBurst               = 5
+
+	// OutboundBackoffBase is the first backoff after a rate limited response;
+	// it doubles on every consecutive one up to the max backoff
+	OutboundBackoffBase       = 15 * time.Second
+	DefaultOutboundMaxBackoff = 10 * time.Minute
+
+	// DefaultTickerJitter spreads each ticker interval by up to ±10%
+	DefaultTickerJitter = 0.1
+	MaxTickerJitter     = 0.5
+)
+
+// grpcCodeResourceExhausted is the gRPC status code the REST gateway reports
+// when the node or a proxy in front of it sheds load
+const grpcCodeResourceExhausted = 8
+
+// ErrOutboundBackoff is returned for requests to an endpoint that recently
+// answered with a rate limit, until its backoff has passed
+var ErrOutboundBackoff = errors.New("endpoint is rate limiting, backing off")
+
+// OutboundConfig sets the request budgets of the chain endpoints the bot
+// queries. Endpoints not listed get the default budget.
+type OutboundConfig struct {
+	RequestsPerMinute       int           `yaml:"requests_per_minute"`        // default 120
+	Burst                   int           `yaml:"burst"`                      // default 20
+	PublicRequestsPerMinute int           `yaml:"public_requests_per_minute"` // default 30, for endpoints marked public
+	PublicBurst             int           `yaml:"public_burst"`               // default 5
+	MaxBackoff              time.Duration `yaml:"max_backoff"`                // default 10m
+	// Jitter spreads every loop interval by up to this fraction, so that the
+	// bot's loops do not query in aligned bursts (default 0.1)
+	Jitter    float64            `yaml:"jitter"`
+	Endpoints []OutboundEndpoint `yaml:"endpoints"`
+}
+
+// OutboundEndpoint overrides the budget of one endpoint, matched on its
+// scheme and host
+type OutboundEndpoint struct {
+	URL string `yaml:"url"`
+	// Public marks a shared provider endpoint, which gets the public budget
+	Public            bool `yaml:"public"`
+	RequestsPerMinute int  `yaml:"requests_per_minute"`
+	Burst             int  `yaml:"burst"`
+}
+
+// ValidateOutbound checks the outbound settings
+func ValidateOutbound(cfg OutboundConfig) error {
+	if cfg.RequestsPerMinute < 0 || cfg.Burst < 0 || cfg.PublicRequestsPerMinute < 0 || cfg.PublicBurst < 0 {
+		return fmt.Errorf("outbound budgets cannot be negative")
+	}
+	if cfg.MaxBackoff < 0 {
+		return fmt.Errorf("outbound.max_backoff cannot be negative")
+	}
+	if cfg.Jitter < 0 || cfg.Jitter > MaxTickerJitter {
+		return fmt.Errorf("outbound.jitter must be between 0 and %.1f", MaxTickerJitter)
+	}
+
+	seen := make(map[string]bool)
+	for _, endpoint := range cfg.Endpoints {
+		key := endpointKey(endpoint.URL)
+		if key == "" {
+			return fmt.Errorf("outbound.endpoints: invalid url %q", endpoint.URL)
+		}
+		if seen[key] {
+			return fmt.Errorf("outbound.endpoints: %s listed twice", key)
+		}
+		seen[key] = true
+		if endpoint.RequestsPerMinute < 0 || endpoint.Burst < 0 {
+			return fmt.Errorf("outbound.endpoints: budget of %s cannot be negative", key)
+		}
+	}
+	return nil
+}
+
+// endpointKey returns scheme://host of rawURL, "" when it has no host
+func endpointKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+// endpointBudget is the token bucket and the counters of one endpoint
+type endpointBudget struct {
+	public bool
+	perMin int
+	burst  int
+
+	tokens   float64
+	refillAt time.Time
+
+	backoffUntil time.Time
+	consecutive  int
+
+	requests    uint64
+	throttled   uint64
+	rateLimited uint64
+}
+
+// OutboundLimiter paces the bot's requests to each endpoint with a token
+// bucket, and backs off from endpoints answering with HTTP 429 or gRPC
+// ResourceExhausted. One limiter is shared by all chain queriers.
+type OutboundLimiter struct {
+	mu        sync.Mutex
+	cfg       OutboundConfig
+	endpoints map[string]*endpointBudget
+
+	now func() time.Time
+}
+
+// NewOutboundLimiter creates a limiter with the budgets of cfg
+func NewOutboundLimiter(cfg OutboundConfig) *OutboundLimiter {
+	if cfg.RequestsPerMinute == 0 {
+		cfg.RequestsPerMinute = DefaultOutboundRequestsPerMinute
+	}
+	if cfg.Burst == 0 {
+		cfg.Burst = DefaultOutboundBurst
+	}
+	if cfg.PublicRequestsPerMinute == 0 {
+		cfg.PublicRequestsPerMinute = DefaultPublicRequestsPerMinute
+	}
+	if cfg.PublicBurst == 0 {
+		cfg.PublicBurst = DefaultPublicBurst
+	}
+	if cfg.MaxBackoff == 0 {
+		cfg.MaxBackoff = DefaultOutboundMaxBackoff
+	}
+	if cfg.Jitter == 0 {
+		cfg.Jitter = DefaultTickerJitter
+	}
+
+	return &OutboundLimiter{
+		cfg:       cfg,
+		endpoints: make(map[string]*endpointBudget),
+		now:       time.Now,
+	}
+}
+
+// sharedOutbound is the limiter of the running bot, nil until the bot
+// configured it; queriers created before then are not paced
+var sharedOutbound atomic.Pointer[OutboundLimiter]
+
+// SetOutboundLimiter installs the limiter used by chain queriers created
+// from now on and by jittered tickers
+func SetOutboundLimiter(l *OutboundLimiter) {
+	sharedOutbound.Store(l)
+}
+
+// budget returns the bucket of key, creating it full. Callers hold l.mu.
+func (l *OutboundLimiter) budget(key string, now time.Time) *endpointBudget {
+	if b, ok := l.endpoints[key]; ok {
+		return b
+	}
+
+	b := &endpointBudget{perMin: l.cfg.RequestsPerMinute, burst: l.cfg.Burst}
+	for _, endpoint := range l.cfg.Endpoints {
+		if endpointKey(endpoint.URL) != key {
+			continue
+		}
+		b.public = endpoint.Public
+		if endpoint.Public {
+			b.perMin, b.burst = l.cfg.PublicRequestsPerMinute, l.cfg.PublicBurst
+		}
+		if endpoint.RequestsPerMinute > 0 {
+			b.perMin = endpoint.RequestsPerMinute
+		}
+		if endpoint.Burst > 0 {
+			b.burst = endpoint.Burst
+		}
+	}
+	b.tokens = float64(b.burst)
+	b.refillAt = now
+	l.endpoints[key] = b
+	return b
+}
+
+// reserve takes a token for a request to key at now. It returns how long the
+// request has to wait for it, or ErrOutboundBackoff while backing off.
+func (l *OutboundLimiter) reserve(key string, now time.Time) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.budget(key, now)
+	if now.Before(b.backoffUntil) {
+		b.throttled++
+		return 0, fmt.Errorf("%w until %s", ErrOutboundBackoff, b.backoffUntil.Format(time.RFC3339))
+	}
+
+	if elapsed := now.Sub(b.refillAt); elapsed > 0 {
+		b.tokens += float64(elapsed) * float64(b.perMin) / float64(time.Minute)
+		if b.tokens > float64(b.burst) {
+			b.tokens = float64(b.burst)
+		}
+		b.refillAt = now
+	}
+
+	// Tokens may go negative: they are reserved by requests already waiting
+	b.tokens--
+	b.requests++
+	if b.tokens >= 0 {
+		return 0, nil
+	}
+	b.throttled++
+	return time.Duration(-b.tokens * float64(time.Minute) / float64(b.perMin)), nil
+}
+
+// Wait blocks until a request to rawURL is within its endpoint's budget. It
+// fails while the endpoint is backing off or when ctx is done first.
+func (l *OutboundLimiter) Wait(ctx context.Context, rawURL string) error {
+	if l == nil {
+		return nil
+	}
+
+	wait, err := l.reserve(endpointKey(rawURL), l.now())
+	if err != nil || wait <= 0 {
+		return err
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe records the response of a request to rawURL. A rate limited
+// response starts or extends the endpoint's backoff, any other one ends the
+// streak of consecutive rate limits.
+func (l *OutboundLimiter) Observe(rawURL string, resp *http.Response, body []byte) {
+	if l == nil || resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.budget(endpointKey(rawURL), now)
+	if !isRateLimited(resp.StatusCode, body) {
+		b.consecutive = 0
+		return
+	}
+
+	b.rateLimited++
+	b.consecutive++
+	backoff := retryAfter(resp.Header.Get("Retry-After"), now)
+	if backoff <= 0 {
+		backoff = OutboundBackoffBase << (b.consecutive - 1)
+		if b.consecutive > 16 {
+			backoff = l.cfg.MaxBackoff
+		}
+	}
+	if backoff > l.cfg.MaxBackoff {
+		backoff = l.cfg.MaxBackoff
+	}
+	if until := now.Add(backoff); until.After(b.backoffUntil) {
+		b.backoffUntil = until
+	}
+}
+
+// isRateLimited reports whether a response is an HTTP 429 or a gateway error
+// carrying gRPC ResourceExhausted
+func isRateLimited(statusCode int, body []byte) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if statusCode == http.StatusOK {
+		return false
+	}
+
+	var grpcErr struct {
+		Code int `json:"code"`
+	}
+	return json.Unmarshal(body, &grpcErr) == nil && grpcErr.Code == grpcCodeResourceExhausted
+}
+
+// retryAfter parses a Retry-After header in seconds or as an HTTP date
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// Jitter returns the fraction tickers spread their intervals by
+func (l *OutboundLimiter) Jitter() float64 {
+	if l == nil {
+		return DefaultTickerJitter
+	}
+	return l.cfg.Jitter
+}
+
+// Status returns the budget and counters of each endpoint queried so far
+func (l *OutboundLimiter) Status() map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	endpoints := make(map[string]interface{}, len(l.endpoints))
+	var requests, throttled, rateLimited uint64
+	for key, b := range l.endpoints {
+		entry := map[string]interface{}{
+			"public":              b.public,
+			"requests_per_minute": b.perMin,
+			"burst":               b.burst,
+			"requests":            b.requests,
+			"throttled":           b.throttled,
+			"rate_limited":        b.rateLimited,
+		}
+		if now.Before(b.backoffUntil) {
+			entry["backoff_until"] = b.backoffUntil
+		}
+		endpoints[key] = entry
+		requests += b.requests
+		throttled += b.throttled
+		rateLimited += b.rateLimited
+	}
+
+	return map[string]interface{}{
+		"requests":     requests,
+		"throttled":    throttled,
+		"rate_limited": rateLimited,
+		"jitter":       l.cfg.Jitter,
+		"endpoints":    endpoints,
+	}
+}
+
+// Requests returns the requests made to all endpoints since the bot started
+func (l *OutboundLimiter) Requests() uint64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var requests uint64
+	for _, b := range l.endpoints {
+		requests += b.requests
+	}
+	return requests
+}
+
+// Metrics returns the outbound request counters per endpoint
+func (l *OutboundLimiter) Metrics() map[string]float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	metrics := make(map[string]float64)
+	keys := make([]string, 0, len(l.endpoints))
+	for key := range l.endpoints {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b := l.endpoints[key]
+		metrics[fmt.Sprintf(`gxr_outbound_requests_total{endpoint="%s"}`, key)] = float64(b.requests)
+		metrics[fmt.Sprintf(`gxr_outbound_throttled_total{endpoint="%s"}`, key)] = float64(b.throttled)
+		metrics[fmt.Sprintf(`gxr_outbound_rate_limited_total{endpoint="%s"}`, key)] = float64(b.rateLimited)
+		backoff := 0.0
+		if now.Before(b.backoffUntil) {
+			backoff = b.backoffUntil.Sub(now).Seconds()
+		}
+		metrics[fmt.Sprintf(`gxr_outbound_backoff_seconds{endpoint="%s"}`, key)] = backoff
+	}
+	return metrics
+}
+
+// jitterInterval spreads interval uniformly by up to ±fraction. rnd returns
+// values in [0, 1), like rand.Float64.
+func jitterInterval(interval time.Duration, fraction float64, rnd func() float64) time.Duration {
+	if fraction <= 0 || interval <= 0 {
+		return interval
+	}
+	return time.Duration(float64(interval) * (1 + fraction*(2*rnd()-1)))
+}
+
+// JitteredTicker delivers ticks like time.Ticker, but draws every interval
+// anew with jitter, so that loops started together drift apart
+type JitteredTicker struct {
+	C <-chan time.Time
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewJitteredTicker ticks about every interval, spread by the jitter of the
+// bot's outbound config
+func NewJitteredTicker(interval time.Duration) *JitteredTicker {
+	return newJitteredTicker(interval, sharedOutbound.Load().Jitter(), rand.Float64)
+}
+
+func newJitteredTicker(interval time.Duration, fraction float64, rnd func() float64) *JitteredTicker {
+	c := make(chan time.Time, 1)
+	t := &JitteredTicker{C: c, stop: make(chan struct{})}
+
+	go func() {
+		timer := time.NewTimer(jitterInterval(interval, fraction, rnd))
+		defer timer.Stop()
+		for {
+			select {
+			case <-t.stop:
+				return
+			case now := <-timer.C:
+				// Like time.Ticker, drop ticks a slow receiver did not take
+				select {
+				case c <- now:
+				default:
+				}
+				timer.Reset(jitterInterval(interval, fraction, rnd))
+			}
+		}
+	}()
+	return t
+}
+
+// Stop turns off the ticker. It does not close C.
+func (t *JitteredTicker) Stop() {
+	t.once.Do(func() { close(t.stop) })
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJitterIntervalDistribution(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1)).Float64
+	const samples = 20000
+	interval := time.Minute
+
+	var buckets [10]int
+	var sum float64
+	for i := 0; i < samples; i++ {
+		d := jitterInterval(interval, 0.1, rnd)
+		if d < 54*time.Second || d > 66*time.Second {
+			t.Fatalf("jittered interval %s outside ±10%% of %s", d, interval)
+		}
+		sum += float64(d)
+		buckets[int((d-54*time.Second)*10/(12*time.Second))%10]++
+	}
+
+	// Uniform over the range: centered on the interval, every tenth about as likely
+	if mean := time.Duration(sum / samples); math.Abs(float64(mean-interval)) > float64(time.Second/4) {
+		t.Fatalf("mean jittered interval = %s, want about %s", mean, interval)
+	}
+	for i, n := range buckets {
+		if n < samples/10*85/100 || n > samples/10*115/100 {
+			t.Fatalf("bucket %d holds %d of %d samples, want about %d", i, n, samples, samples/10)
+		}
+	}
+
+	if d := jitterInterval(interval, 0, rnd); d != interval {
+		t.Fatalf("interval without jitter = %s", d)
+	}
+}
+
+func TestJitteredTickersDriftApart(t *testing.T) {
+	// Two loops started together tick at different times
+	a := newJitteredTicker(20*time.Millisecond, 0.5, func() float64 { return 0 })
+	defer a.Stop()
+	b := newJitteredTicker(20*time.Millisecond, 0.5, func() float64 { return 0.999 })
+	defer b.Stop()
+
+	var first, second time.Time
+	select {
+	case first = <-a.C:
+	case <-time.After(time.Second):
+		t.Fatal("no tick from the first ticker")
+	}
+	select {
+	case second = <-b.C:
+	case <-time.After(time.Second):
+		t.Fatal("no tick from the second ticker")
+	}
+	if second.Sub(first) < 10*time.Millisecond {
+		t.Fatalf("ticks %s apart, want the intervals of 10ms and 30ms", second.Sub(first))
+	}
+
+	// A tick already due may still be delivered, none after it
+	a.Stop()
+	time.Sleep(30 * time.Millisecond)
+	select {
+	case <-a.C:
+	default:
+	}
+	select {
+	case tick := <-a.C:
+		t.Fatalf("tick at %s after Stop", tick)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// newLimitedQuerier serves handler and queries it through a limiter on a
+// settable clock
+func newLimitedQuerier(t *testing.T, cfg OutboundConfig, handler http.HandlerFunc) (*ChainQuerier, *OutboundLimiter, *time.Time) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	limiter := NewOutboundLimiter(cfg)
+	limiter.now = func() time.Time { return now }
+
+	cq := NewChainQuerierForAPI(srv.URL)
+	cq.limiter = limiter
+	return cq, limiter, &now
+}
+
+func TestChainQuerierBacksOffOn429(t *testing.T) {
+	var hits, limited int32
+	atomic.StoreInt32(&limited, 1)
+	cq, limiter, now := newLimitedQuerier(t, OutboundConfig{}, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if atomic.LoadInt32(&limited) == 1 {
+			w.Header().Set("Retry-After", "120")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	ctx := context.Background()
+	var out map[string]interface{}
+
+	if err := cq.getJSON(ctx, "/status", &out); err == nil {
+		t.Fatal("rate limited query succeeded")
+	}
+
+	// The endpoint is left alone for the Retry-After period
+	atomic.StoreInt32(&limited, 0)
+	*now = now.Add(119 * time.Second)
+	if err := cq.getJSON(ctx, "/status", &out); !errors.Is(err, ErrOutboundBackoff) {
+		t.Fatalf("query during backoff: %v", err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Fatalf("endpoint hit %d times during backoff", n)
+	}
+
+	*now = now.Add(2 * time.Second)
+	if err := cq.getJSON(ctx, "/status", &out); err != nil {
+		t.Fatalf("query after backoff: %v", err)
+	}
+
+	status := limiter.Status()
+	if status["rate_limited"] != uint64(1) || status["throttled"] != uint64(1) || status["requests"] != uint64(2) {
+		t.Fatalf("outbound status = %v", status)
+	}
+	key := endpointKey(cq.baseURL)
+	metrics := limiter.Metrics()
+	if metrics[`gxr_outbound_rate_limited_total{endpoint="`+key+`"}`] != 1 ||
+		metrics[`gxr_outbound_throttled_total{endpoint="`+key+`"}`] != 1 {
+		t.Fatalf("outbound metrics = %v", metrics)
+	}
+}
+
+func TestResourceExhaustedBackoffDoubles(t *testing.T) {
+	var exhausted int32 = 1
+	cq, limiter, now := newLimitedQuerier(t, OutboundConfig{MaxBackoff: 45 * time.Second}, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&exhausted) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code":8,"message":"rate limit exceeded","details":[]}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	ctx := context.Background()
+	var out map[string]interface{}
+	key := endpointKey(cq.baseURL)
+
+	// gRPC ResourceExhausted through the gateway backs off 15s, 30s, then
+	// the max backoff
+	for _, want := range []time.Duration{15 * time.Second, 30 * time.Second, 45 * time.Second} {
+		if err := cq.getJSON(ctx, "/status", &out); err == nil || errors.Is(err, ErrOutboundBackoff) {
+			t.Fatalf("exhausted query: %v", err)
+		}
+		if got := limiter.endpoints[key].backoffUntil.Sub(*now); got != want {
+			t.Fatalf("backoff = %s, want %s", got, want)
+		}
+		*now = now.Add(want)
+	}
+
+	// A good response ends the streak
+	atomic.StoreInt32(&exhausted, 0)
+	if err := cq.getJSON(ctx, "/status", &out); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&exhausted, 1)
+	cq.getJSON(ctx, "/status", &out)
+	if got := limiter.endpoints[key].backoffUntil.Sub(*now); got != OutboundBackoffBase {
+		t.Fatalf("backoff after recovery = %s", got)
+	}
+
+	// Other errors do not back off
+	if isRateLimited(http.StatusInternalServerError, []byte(`{"code":13,"message":"internal"}`)) {
+		t.Fatal("internal error treated as rate limit")
+	}
+}
+
+func TestPublicEndpointBudget(t *testing.T) {
+	limiter := NewOutboundLimiter(OutboundConfig{Endpoints: []OutboundEndpoint{
+		{URL: "https://rpc.public.example:443", Public: true},
+	}})
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	// The public endpoint bursts 5 requests, then one every 2s (30/min)
+	for i := 0; i < DefaultPublicBurst; i++ {
+		if wait, err := limiter.reserve("https://rpc.public.example:443", now); err != nil || wait != 0 {
+			t.Fatalf("request %d waits %s: %v", i, wait, err)
+		}
+	}
+	if wait, _ := limiter.reserve("https://rpc.public.example:443", now); wait != 2*time.Second {
+		t.Fatalf("request past the public burst waits %s", wait)
+	}
+	if wait, _ := limiter.reserve("https://rpc.public.example:443", now); wait != 4*time.Second {
+		t.Fatalf("second request past the public burst waits %s", wait)
+	}
+
+	// Other endpoints keep the default budget
+	for i := 0; i < DefaultOutboundBurst; i++ {
+		if wait, _ := limiter.reserve("http://localhost:1317", now); wait != 0 {
+			t.Fatalf("own node request %d waits %s", i, wait)
+		}
+	}
+
+	status := limiter.Status()["endpoints"].(map[string]interface{})
+	public := status["https://rpc.public.example:443"].(map[string]interface{})
+	if public["public"] != true || public["throttled"] != uint64(2) || public["requests_per_minute"] != DefaultPublicRequestsPerMinute {
+		t.Fatalf("public endpoint status = %v", public)
+	}
+}
+
+func TestValidateOutbound(t *testing.T) {
+	for _, cfg := range []OutboundConfig{
+		{Jitter: 0.6},
+		{RequestsPerMinute: -1},
+		{Endpoints: []OutboundEndpoint{{URL: "not a url"}}},
+		{Endpoints: []OutboundEndpoint{{URL: "https://a.example"}, {URL: "https://A.example/rpc"}}},
+	} {
+		if err := ValidateOutbound(cfg); err == nil {
+			t.Fatalf("config %+v accepted", cfg)
+		}
+	}
+	if err := ValidateOutbound(OutboundConfig{Jitter: 0.2, Endpoints: []OutboundEndpoint{{URL: "https://rpc.example", Public: true}}}); err != nil {
+		t.Fatal(err)
+	}
+}
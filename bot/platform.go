@@ -0,0 +1,25 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// expandHome resolves a leading "~" in a configured path to the user's home
+// directory. Both separators are accepted after it on Windows.
+func expandHome(path string) string {
+	if path != "~" && (len(path) < 2 || path[0] != '~' || !os.IsPathSeparator(path[1])) {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Printf("Cannot expand %s, home directory unknown: %v", path, err)
+		return path
+	}
+	if path == "~" {
+		return home
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,46 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestExpandHomeUnix(t *testing.T) {
+	t.Setenv("HOME", "/home/validator")
+
+	for path, want := range map[string]string{
+		"~":                 "/home/validator",
+		"~/.gxr-bot/data":   "/home/validator/.gxr-bot/data",
+		"~/config/bot.yaml": "/home/validator/config/bot.yaml",
+		"~other/data":       "~other/data",
+		"./data":            "./data",
+		"/var/lib/~/data":   "/var/lib/~/data",
+	} {
+		if got := expandHome(path); got != want {
+			t.Errorf("expandHome(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestLoadConfigExpandsDataDirUnix(t *testing.T) {
+	t.Setenv("HOME", "/home/validator")
+
+	config, err := LoadConfig(writeConfig(t, legacyConfig+"data_dir: \"~/.gxr-bot\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.DataDir != "/home/validator/.gxr-bot" {
+		t.Fatalf("data_dir = %q", config.DataDir)
+	}
+}
+
+func TestProcessAliveUnix(t *testing.T) {
+	if !processAlive(os.Getpid()) {
+		t.Fatal("own process reported dead")
+	}
+	if processAlive(0) || processAlive(-1) {
+		t.Fatal("invalid PID reported alive")
+	}
+}
@@ -0,0 +1,46 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestExpandHomeWindows(t *testing.T) {
+	t.Setenv("USERPROFILE", `C:\Users\validator`)
+
+	for path, want := range map[string]string{
+		"~":                   `C:\Users\validator`,
+		`~\.gxr-bot\data`:     `C:\Users\validator\.gxr-bot\data`,
+		"~/config/bot.yaml":   `C:\Users\validator\config\bot.yaml`,
+		`~other\data`:         `~other\data`,
+		"./data":              "./data",
+		`D:\validator\~\data`: `D:\validator\~\data`,
+	} {
+		if got := expandHome(path); got != want {
+			t.Errorf("expandHome(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestLoadConfigExpandsDataDirWindows(t *testing.T) {
+	t.Setenv("USERPROFILE", `C:\Users\validator`)
+
+	config, err := LoadConfig(writeConfig(t, legacyConfig+"data_dir: '~\\.gxr-bot'\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.DataDir != `C:\Users\validator\.gxr-bot` {
+		t.Fatalf("data_dir = %q", config.DataDir)
+	}
+}
+
+func TestProcessAliveWindows(t *testing.T) {
+	if !processAlive(os.Getpid()) {
+		t.Fatal("own process reported dead")
+	}
+	if processAlive(0) || processAlive(-1) {
+		t.Fatal("invalid PID reported alive")
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// EMAShortPeriod is the period of the short exponential moving average
+	EMAShortPeriod = 1 * time.Hour
+	// EMALongPeriod is the period of the long exponential moving average
+	EMALongPeriod = 24 * time.Hour
+	// TrendTolerance is the relative EMA spread below which the trend is flat (0.5%)
+	TrendTolerance = 0.005
+)
+
+// ThresholdSource selects the price the rebalancer compares against its thresholds
+type ThresholdSource string
+
+const (
+	ThresholdSourceSpot   ThresholdSource = "spot"
+	ThresholdSourceEMA1h  ThresholdSource = "ema1h"
+	ThresholdSourceEMA24h ThresholdSource = "ema24h"
+)
+
+// ParseThresholdSource parses the threshold_source config value; empty means spot
+func ParseThresholdSource(value string) (ThresholdSource, error) {
+	switch ThresholdSource(value) {
+	case "", ThresholdSourceSpot:
+		return ThresholdSourceSpot, nil
+	case ThresholdSourceEMA1h, ThresholdSourceEMA24h:
+		return ThresholdSource(value), nil
+	default:
+		return "", fmt.Errorf("invalid threshold_source %q (use spot, ema1h or ema24h)", value)
+	}
+}
+
+// PriceTrend is the direction of the short EMA relative to the long EMA
+type PriceTrend string
+
+const (
+	TrendUnknown PriceTrend = "unknown"
+	TrendRising  PriceTrend = "rising"
+	TrendFalling PriceTrend = "falling"
+	TrendFlat    PriceTrend = "flat"
+)
+
+// PriceEMA is a time-weighted exponential moving average. Samples may arrive
+// at irregular intervals; each one is weighted by the time since the previous.
+type PriceEMA struct {
+	period    time.Duration
+	value     float64
+	firstTime time.Time
+	lastTime  time.Time
+	samples   int
+}
+
+// NewPriceEMA creates an EMA over the given period
+func NewPriceEMA(period time.Duration) *PriceEMA {
+	return &PriceEMA{period: period}
+}
+
+// Update adds a price observed at t. Samples older than the last one are ignored.
+func (e *PriceEMA) Update(price float64, t time.Time) {
+	if e.samples == 0 {
+		e.value = price
+		e.firstTime = t
+		e.lastTime = t
+		e.samples = 1
+		return
+	}
+
+	if !t.After(e.lastTime) {
+		return
+	}
+
+	alpha := 1 - math.Exp(-float64(t.Sub(e.lastTime))/float64(e.period))
+	e.value += alpha * (price - e.value)
+	e.lastTime = t
+	e.samples++
+}
+
+// Value returns the current average
+func (e *PriceEMA) Value() float64 {
+	return e.value
+}
+
+// Ready reports whether the samples span at least one full period
+func (e *PriceEMA) Ready() bool {
+	return e.samples > 0 && e.lastTime.Sub(e.firstTime) >= e.period
+}
+
+// PriceSignals is a snapshot of spot price, moving averages and trend
+type PriceSignals struct {
+	Spot        float64    `json:"spot"`
+	EMA1h       float64    `json:"ema_1h"`
+	EMA24h      float64    `json:"ema_24h"`
+	EMA1hReady  bool       `json:"ema_1h_ready"`
+	EMA24hReady bool       `json:"ema_24h_ready"`
+	Trend       PriceTrend `json:"trend"`
+}
+
+// NewPriceSignals builds a snapshot from the spot price and both averages
+func NewPriceSignals(spot float64, short, long *PriceEMA) PriceSignals {
+	signals := PriceSignals{
+		Spot:        spot,
+		EMA1h:       short.Value(),
+		EMA24h:      long.Value(),
+		EMA1hReady:  short.Ready(),
+		EMA24hReady: long.Ready(),
+		Trend:       TrendUnknown,
+	}
+
+	// The trend needs the short average warmed up and some long history
+	if signals.EMA1hReady && signals.EMA24h > 0 {
+		spread := (signals.EMA1h - signals.EMA24h) / signals.EMA24h
+		switch {
+		case spread > TrendTolerance:
+			signals.Trend = TrendRising
+		case spread < -TrendTolerance:
+			signals.Trend = TrendFalling
+		default:
+			signals.Trend = TrendFlat
+		}
+	}
+
+	return signals
+}
+
+// ThresholdPrice returns the price to compare against thresholds for source.
+// An EMA that is still warming up falls back to spot; the second result is the
+// source actually used.
+func (s PriceSignals) ThresholdPrice(source ThresholdSource) (float64, ThresholdSource) {
+	switch source {
+	case ThresholdSourceEMA1h:
+		if s.EMA1hReady {
+			return s.EMA1h, source
+		}
+	case ThresholdSourceEMA24h:
+		if s.EMA24hReady {
+			return s.EMA24h, source
+		}
+	}
+	return s.Spot, ThresholdSourceSpot
+}
+
+// Summary renders the signals for alerts
+func (s PriceSignals) Summary() string {
+	return fmt.Sprintf("Trend: %s (EMA1h $%.4f, EMA24h $%.4f)", s.Trend, s.EMA1h, s.EMA24h)
+}
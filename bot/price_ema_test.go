@@ -0,0 +1,140 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPriceEMAKnownSeries(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// 1h EMA sampled every 30 minutes: alpha = 1 - e^-0.5
+	ema := NewPriceEMA(time.Hour)
+	for i, price := range []float64{1, 2, 3} {
+		ema.Update(price, start.Add(time.Duration(i)*30*time.Minute))
+	}
+	if !approxEqual(ema.Value(), 2.025589899115924) {
+		t.Fatalf("EMA = %.12f, want 2.025589899116", ema.Value())
+	}
+
+	// A step from 2 to 5 converges as 5 - 3e^(-t/period), independent of the
+	// sampling interval
+	want := 5 - 3/math.E
+	coarse := NewPriceEMA(time.Hour)
+	coarse.Update(2, start)
+	coarse.Update(5, start.Add(time.Hour))
+
+	fine := NewPriceEMA(time.Hour)
+	fine.Update(2, start)
+	for minute := 1; minute <= 60; minute++ {
+		fine.Update(5, start.Add(time.Duration(minute)*time.Minute))
+	}
+
+	if !approxEqual(coarse.Value(), want) || !approxEqual(fine.Value(), want) {
+		t.Fatalf("step response coarse=%.12f fine=%.12f, want %.12f", coarse.Value(), fine.Value(), want)
+	}
+
+	// Out-of-order samples are ignored
+	fine.Update(100, start.Add(30*time.Minute))
+	if !approxEqual(fine.Value(), want) {
+		t.Fatal("stale sample must not change the average")
+	}
+}
+
+func TestPriceEMAWarmUp(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	ema := NewPriceEMA(time.Hour)
+
+	if ema.Ready() {
+		t.Fatal("empty EMA must not be ready")
+	}
+
+	ema.Update(3, start)
+	ema.Update(3, start.Add(59*time.Minute))
+	if ema.Ready() {
+		t.Fatal("EMA must not be ready before one full period")
+	}
+
+	ema.Update(3, start.Add(time.Hour))
+	if !ema.Ready() || !approxEqual(ema.Value(), 3) {
+		t.Fatalf("constant series: ready=%v value=%f", ema.Ready(), ema.Value())
+	}
+}
+
+func TestPriceSignalsTrendAndThresholdPrice(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	short, long := NewPriceEMA(EMAShortPeriod), NewPriceEMA(EMALongPeriod)
+	short.Update(4, start)
+	long.Update(4, start)
+
+	signals := NewPriceSignals(4.5, short, long)
+	if signals.Trend != TrendUnknown {
+		t.Fatalf("trend before warm-up = %s", signals.Trend)
+	}
+	if price, used := signals.ThresholdPrice(ThresholdSourceEMA1h); price != 4.5 || used != ThresholdSourceSpot {
+		t.Fatalf("warming EMA must fall back to spot, got %f from %s", price, used)
+	}
+
+	short.Update(4.4, start.Add(2*time.Hour))
+	long.Update(4.4, start.Add(2*time.Hour))
+	signals = NewPriceSignals(4.4, short, long)
+	if signals.Trend != TrendRising {
+		t.Fatalf("short EMA above long EMA should be rising, got %s", signals.Trend)
+	}
+	if price, used := signals.ThresholdPrice(ThresholdSourceEMA1h); price != short.Value() || used != ThresholdSourceEMA1h {
+		t.Fatalf("warmed EMA should be used, got %f from %s", price, used)
+	}
+	if _, used := signals.ThresholdPrice(ThresholdSourceEMA24h); used != ThresholdSourceSpot {
+		t.Fatal("24h EMA is still warming up after 2 hours")
+	}
+}
+
+func TestRebalancerThresholdSource(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// 2 hours at $3.00, then a one-minute spike to $6.00
+	feed := func(r *Rebalancer) {
+		for minute := 0; minute <= 120; minute++ {
+			r.recordPrice(3.0, start.Add(time.Duration(minute)*time.Minute))
+		}
+		r.recordPrice(6.0, start.Add(121*time.Minute))
+	}
+
+	spot := newTestRebalancer(t, &BotConfig{ThresholdSource: "spot"}, nil)
+	feed(spot)
+	if spot.state == StateActive {
+		t.Fatal("spot threshold should react to the spike")
+	}
+
+	smoothed := newTestRebalancer(t, &BotConfig{ThresholdSource: "ema1h"}, nil)
+	feed(smoothed)
+	if smoothed.state != StateActive {
+		t.Fatalf("ema1h threshold should ride out the spike, state %s", smoothed.state)
+	}
+	if !approxEqual(smoothed.priceSignals().EMA1h, 3.0495856385351474) {
+		t.Fatalf("EMA1h = %.12f", smoothed.priceSignals().EMA1h)
+	}
+
+	// The 24h EMA is not warm after 2 hours: fall back to spot with a notice
+	warming := newTestRebalancer(t, &BotConfig{ThresholdSource: "ema24h"}, nil)
+	feed(warming)
+	if warming.state == StateActive || !warming.warmupNoticeLogged {
+		t.Fatalf("warming ema24h should fall back to spot (state %s, notice %v)", warming.state, warming.warmupNoticeLogged)
+	}
+}
+
+func TestParseThresholdSource(t *testing.T) {
+	for _, value := range []string{"", "spot", "ema1h", "ema24h"} {
+		if _, err := ParseThresholdSource(value); err != nil {
+			t.Errorf("%q: unexpected error %v", value, err)
+		}
+	}
+	if _, err := ParseThresholdSource("ema7d"); err == nil {
+		t.Error("expected error for unknown source")
+	}
+}
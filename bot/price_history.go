@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// DefaultPriceHistorySize is the number of price samples kept without
+	// price_history_size, an hour of PriceUpdateInterval samples
+	DefaultPriceHistorySize = 60
+	// MaxPriceHistorySize bounds price_history_size to a day of samples
+	MaxPriceHistorySize = 1440
+)
+
+// PriceSample is a price observation of the rebalancer
+type PriceSample struct {
+	Price float64   `json:"price"`
+	At    time.Time `json:"at"`
+}
+
+// PriceHistory is a fixed-size ring buffer of the latest price samples.
+// It is not safe for concurrent use; the rebalancer guards it with its mutex
+// and hands out copies through Samples.
+type PriceHistory struct {
+	samples []PriceSample
+	start   int
+	count   int
+}
+
+// NewPriceHistory creates a history keeping the last size samples
+// (DefaultPriceHistorySize if size is not positive)
+func NewPriceHistory(size int) *PriceHistory {
+	if size <= 0 {
+		size = DefaultPriceHistorySize
+	}
+	return &PriceHistory{samples: make([]PriceSample, size)}
+}
+
+// ValidatePriceHistorySize checks the price_history_size config value; 0
+// means the default
+func ValidatePriceHistorySize(size int) error {
+	if size < 0 || size > MaxPriceHistorySize {
+		return fmt.Errorf("price_history_size must be between 0 and %d, got %d", MaxPriceHistorySize, size)
+	}
+	return nil
+}
+
+// Add records a sample, dropping the oldest once the history is full
+func (h *PriceHistory) Add(sample PriceSample) {
+	if h.count < len(h.samples) {
+		h.samples[(h.start+h.count)%len(h.samples)] = sample
+		h.count++
+		return
+	}
+	h.samples[h.start] = sample
+	h.start = (h.start + 1) % len(h.samples)
+}
+
+// Len returns the number of samples held
+func (h *PriceHistory) Len() int {
+	return h.count
+}
+
+// Cap returns the number of samples the history keeps
+func (h *PriceHistory) Cap() int {
+	return len(h.samples)
+}
+
+// Samples returns a copy of the samples, oldest first
+func (h *PriceHistory) Samples() []PriceSample {
+	samples := make([]PriceSample, h.count)
+	for i := range samples {
+		samples[i] = h.samples[(h.start+i)%len(h.samples)]
+	}
+	return samples
+}
+
+// Statistics returns the average and the standard deviation of the sampled
+// prices, zero without samples
+func (h *PriceHistory) Statistics() (average, volatility float64) {
+	if h.count == 0 {
+		return 0, 0
+	}
+
+	sum := 0.0
+	for i := 0; i < h.count; i++ {
+		sum += h.samples[(h.start+i)%len(h.samples)].Price
+	}
+	average = sum / float64(h.count)
+
+	varianceSum := 0.0
+	for i := 0; i < h.count; i++ {
+		diff := h.samples[(h.start+i)%len(h.samples)].Price - average
+		varianceSum += diff * diff
+	}
+	return average, math.Sqrt(varianceSum / float64(h.count))
+}
+
+// SamplesSince returns the samples taken at or after since, oldest first
+func SamplesSince(samples []PriceSample, since time.Time) []PriceSample {
+	for i, sample := range samples {
+		if !sample.At.Before(since) {
+			return samples[i:]
+		}
+	}
+	return []PriceSample{}
+}
@@ -0,0 +1,605 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// PriceOracleMock derives the price from a sine wave, for test networks
+	// and tests. It is the only source of simulated prices.
+	PriceOracleMock = "mock"
+	// PriceOracleCoinGecko reads the CoinGecko simple price REST API
+	PriceOracleCoinGecko = "coingecko"
+	// PriceOracleTWAP reads the arithmetic TWAP of an AMM pool over gRPC
+	PriceOracleTWAP = "twap"
+	// PriceOracleREST reads the price from a field of any JSON REST endpoint
+	PriceOracleREST = "rest"
+
+	// DefaultPriceDenom is the denom the rebalancer prices
+	DefaultPriceDenom = "ugen"
+	// DefaultCoinGeckoEndpoint is the public CoinGecko API
+	DefaultCoinGeckoEndpoint = "https://api.coingecko.com/api/v3"
+	// DefaultPriceVsCurrency is the currency CoinGecko quotes in
+	DefaultPriceVsCurrency = "usd"
+	// DefaultCoinGeckoAPIKeyHeader carries a CoinGecko Pro API key
+	DefaultCoinGeckoAPIKeyHeader = "x-cg-pro-api-key"
+	// DefaultRESTAPIKeyHeader carries the API key of a rest oracle
+	DefaultRESTAPIKeyHeader = "X-API-Key"
+	// DefaultTWAPWindow is how far back the TWAP starts
+	DefaultTWAPWindow = 30 * time.Minute
+	// DefaultPriceMaxFailures is how many consecutive failed price updates the
+	// rebalancer tolerates before falling back to the last known good price
+	DefaultPriceMaxFailures = 5
+	// DefaultPriceBackoffMax caps the wait between requests of a failing oracle
+	DefaultPriceBackoffMax = 10 * time.Minute
+
+	// TWAPQueryMethod is the Osmosis x/twap query the TWAP oracle calls
+	TWAPQueryMethod = "/osmosis.twap.v1beta1.Query/ArithmeticTwapToNow"
+	// twapDecPrecision is the precision of the sdk.Dec the TWAP is encoded as
+	twapDecPrecision = 18
+)
+
+// ErrPriceOracleBackoff is returned, wrapping the last failure, while a
+// failing oracle waits before its next request
+var ErrPriceOracleBackoff = errors.New("price oracle backing off")
+
+// PriceOracle returns the USD price of one whole token of denom
+type PriceOracle interface {
+	GetPrice(ctx context.Context, denom string) (float64, error)
+}
+
+// PriceOracleFunc adapts a function to PriceOracle
+type PriceOracleFunc func(ctx context.Context, denom string) (float64, error)
+
+func (f PriceOracleFunc) GetPrice(ctx context.Context, denom string) (float64, error) {
+	return f(ctx, denom)
+}
+
+// PriceSource returns the USD price of GXR, where the rebalancer reads it
+type PriceSource interface {
+	FetchGXRPrice(ctx context.Context) (float64, error)
+}
+
+// PriceSourceFunc adapts a function to PriceSource
+type PriceSourceFunc func(ctx context.Context) (float64, error)
+
+func (f PriceSourceFunc) FetchGXRPrice(ctx context.Context) (float64, error) {
+	return f(ctx)
+}
+
+// oraclePriceSource reads the GXR price from an oracle pricing denom
+type oraclePriceSource struct {
+	oracle PriceOracle
+	denom  string
+}
+
+func (s oraclePriceSource) FetchGXRPrice(ctx context.Context) (float64, error) {
+	return s.oracle.GetPrice(ctx, s.denom)
+}
+
+// PriceRetry is how often an oracle retries a failed request before the
+// price update fails, from retry_attempts and retry_delay
+type PriceRetry struct {
+	Attempts int
+	Delay    time.Duration
+}
+
+// PriceOracleConfig selects and configures where the rebalancer reads the
+// GXR price
+type PriceOracleConfig struct {
+	Source string `yaml:"source"` // mock|coingecko|twap|rest, required
+	Denom  string `yaml:"denom"`  // denom priced, default ugen
+	// coingecko: REST base URL (default the public API); twap: gRPC address
+	// of the AMM chain (default chain_grpc); rest: URL answering with the price
+	Endpoint   string `yaml:"endpoint"`
+	TokenID    string `yaml:"token_id"`    // coingecko: token ID of the denom
+	VsCurrency string `yaml:"vs_currency"` // coingecko: default usd
+
+	// coingecko, rest: API key sent in api_key_header (default
+	// x-cg-pro-api-key for coingecko, X-API-Key for rest)
+	APIKey       string `yaml:"api_key"`
+	APIKeyHeader string `yaml:"api_key_header"`
+	// rest: dot-separated path of the price in the JSON response, e.g.
+	// "data.0.price"; the price may be a number or a numeric string
+	PricePath string `yaml:"price_path"`
+
+	PoolID        uint64        `yaml:"pool_id"`        // twap: AMM pool
+	BaseAsset     string        `yaml:"base_asset"`     // twap: denom of GXR on the AMM chain, default denom
+	QuoteAsset    string        `yaml:"quote_asset"`    // twap: USD stablecoin denom
+	BaseDecimals  int           `yaml:"base_decimals"`  // twap: default 8
+	QuoteDecimals int           `yaml:"quote_decimals"` // twap: default 6
+	Window        time.Duration `yaml:"twap_window"`    // twap: default 30m
+
+	// Consecutive failed price updates before the rebalancer falls back to
+	// the last known good price and stops trading (default 5)
+	MaxFailures int `yaml:"max_failures"`
+	// Cap of the exponential backoff between requests of a failing oracle,
+	// which starts at the price update interval (default 10m)
+	BackoffMax time.Duration `yaml:"backoff_max"`
+}
+
+// ValidatePriceOracleConfig validates the price_oracle configuration. Its
+// source is not required with price_sources, whose median replaces it.
+func ValidatePriceOracleConfig(cfg PriceOracleConfig, sources []PriceSourceConfig) error {
+	if cfg.Source == "" && len(sources) > 0 {
+		return validatePriceOracleLimits(cfg, "price_oracle")
+	}
+	return validatePriceOracleConfig(cfg, "price_oracle")
+}
+
+// validatePriceOracleConfig validates an oracle configured under field
+func validatePriceOracleConfig(cfg PriceOracleConfig, field string) error {
+	switch cfg.Source {
+	case "":
+		return fmt.Errorf("%s.source is required (use mock, coingecko, twap or rest)", field)
+	case PriceOracleMock:
+	case PriceOracleCoinGecko:
+		if cfg.TokenID == "" {
+			return fmt.Errorf("%s.token_id is required for the %s oracle", field, cfg.Source)
+		}
+	case PriceOracleTWAP:
+		if cfg.PoolID == 0 {
+			return fmt.Errorf("%s.pool_id is required for the %s oracle", field, cfg.Source)
+		}
+		if cfg.QuoteAsset == "" {
+			return fmt.Errorf("%s.quote_asset is required for the %s oracle", field, cfg.Source)
+		}
+	case PriceOracleREST:
+		if cfg.Endpoint == "" {
+			return fmt.Errorf("%s.endpoint is required for the %s oracle", field, cfg.Source)
+		}
+		if cfg.PricePath == "" {
+			return fmt.Errorf("%s.price_path is required for the %s oracle", field, cfg.Source)
+		}
+	default:
+		return fmt.Errorf("invalid %s.source %q (use mock, coingecko, twap or rest)", field, cfg.Source)
+	}
+	return validatePriceOracleLimits(cfg, field)
+}
+
+// validatePriceOracleLimits validates the numeric settings of an oracle
+// configured under field
+func validatePriceOracleLimits(cfg PriceOracleConfig, field string) error {
+	if cfg.BaseDecimals < 0 || cfg.BaseDecimals > 18 || cfg.QuoteDecimals < 0 || cfg.QuoteDecimals > 18 {
+		return fmt.Errorf("%s decimals must be between 0 and 18", field)
+	}
+	if cfg.Window < 0 || cfg.MaxFailures < 0 || cfg.BackoffMax < 0 {
+		return fmt.Errorf("%s.twap_window, max_failures and backoff_max must not be negative", field)
+	}
+	return nil
+}
+
+// denom returns the configured denom or the default
+func (cfg PriceOracleConfig) denom() string {
+	if cfg.Denom == "" {
+		return DefaultPriceDenom
+	}
+	return cfg.Denom
+}
+
+// maxFailures returns the configured failure threshold or the default
+func (cfg PriceOracleConfig) maxFailures() int {
+	if cfg.MaxFailures == 0 {
+		return DefaultPriceMaxFailures
+	}
+	return cfg.MaxFailures
+}
+
+// NewPriceOracle creates the oracle selected by cfg. chainGRPC is the
+// default address of the TWAP oracle, retry how its failed requests are
+// retried.
+func NewPriceOracle(cfg PriceOracleConfig, chainGRPC string, retry PriceRetry) (PriceOracle, error) {
+	if err := validatePriceOracleConfig(cfg, "price_oracle"); err != nil {
+		return nil, err
+	}
+	backoff := newOracleBackoff(PriceUpdateInterval, cfg.BackoffMax)
+	backoff.retry = retry
+
+	switch cfg.Source {
+	case PriceOracleCoinGecko:
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultCoinGeckoEndpoint
+		}
+		vsCurrency := cfg.VsCurrency
+		if vsCurrency == "" {
+			vsCurrency = DefaultPriceVsCurrency
+		}
+		return &CoinGeckoOracle{
+			denom:      cfg.denom(),
+			tokenID:    cfg.TokenID,
+			vsCurrency: vsCurrency,
+			api:        cfg.withAPIKey(NewChainQuerierForAPI(endpoint), DefaultCoinGeckoAPIKeyHeader),
+			backoff:    backoff,
+		}, nil
+	case PriceOracleTWAP:
+		return newTWAPOracle(cfg, chainGRPC, backoff)
+	case PriceOracleREST:
+		return &RESTOracle{
+			denom:   cfg.denom(),
+			path:    strings.Split(cfg.PricePath, "."),
+			api:     cfg.withAPIKey(NewChainQuerierForAPI(cfg.Endpoint), DefaultRESTAPIKeyHeader),
+			backoff: backoff,
+		}, nil
+	case PriceOracleMock:
+		return PriceOracleFunc(simulatedPrice), nil
+	default:
+		return nil, fmt.Errorf("invalid price oracle source %q", cfg.Source)
+	}
+}
+
+// withAPIKey sets the configured API key on api, in api_key_header or
+// defaultHeader
+func (cfg PriceOracleConfig) withAPIKey(api *ChainQuerier, defaultHeader string) *ChainQuerier {
+	if cfg.APIKey == "" {
+		return api
+	}
+	header := cfg.APIKeyHeader
+	if header == "" {
+		header = defaultHeader
+	}
+	return api.withHeader(header, cfg.APIKey)
+}
+
+// simulatedPrice derives a price below the threshold from a sine wave, with
+// occasional spikes
+func simulatedPrice(ctx context.Context, denom string) (float64, error) {
+	basePrice := 3.0
+	variation := 0.1 * (2.0*math.Sin(float64(time.Now().Unix())/3600) + 1.0)
+	newPrice := basePrice + variation
+
+	// Add some randomness
+	if time.Now().UnixNano()%7 == 0 {
+		newPrice += 0.5 * (float64(time.Now().UnixNano()%100) / 100.0)
+	}
+
+	return newPrice, nil
+}
+
+// oracleBackoff spaces out the requests of a failing oracle exponentially,
+// from base up to max, and resets on the first success. A request failing
+// all its retries counts as one failure.
+type oracleBackoff struct {
+	mu          sync.Mutex
+	base        time.Duration
+	max         time.Duration
+	retry       PriceRetry
+	failures    int
+	nextAttempt time.Time
+	lastErr     error
+	now         func() time.Time
+}
+
+func newOracleBackoff(base, max time.Duration) *oracleBackoff {
+	if max == 0 {
+		max = DefaultPriceBackoffMax
+	}
+	if max < base {
+		max = base
+	}
+	return &oracleBackoff{base: base, max: max, now: time.Now}
+}
+
+// do runs fetch, retrying it, unless the oracle is backing off, in which
+// case the last failure is returned wrapped in ErrPriceOracleBackoff
+func (b *oracleBackoff) do(ctx context.Context, fetch func() (float64, error)) (float64, error) {
+	b.mu.Lock()
+	if now := b.now(); now.Before(b.nextAttempt) {
+		wait, lastErr := b.nextAttempt.Sub(now), b.lastErr
+		b.mu.Unlock()
+		return 0, fmt.Errorf("%w for %s: %v", ErrPriceOracleBackoff, wait.Round(time.Second), lastErr)
+	}
+	b.mu.Unlock()
+
+	price, err := b.fetchWithRetry(ctx, fetch)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err != nil {
+		b.failures++
+		b.lastErr = err
+		b.nextAttempt = b.now().Add(b.delay())
+		return 0, err
+	}
+	b.failures = 0
+	b.lastErr = nil
+	b.nextAttempt = time.Time{}
+	return price, nil
+}
+
+// fetchWithRetry runs fetch up to retry.Attempts times, retry.Delay apart,
+// until it returns a valid price
+func (b *oracleBackoff) fetchWithRetry(ctx context.Context, fetch func() (float64, error)) (float64, error) {
+	attempts := max(b.retry.Attempts, 1)
+	for attempt := 1; ; attempt++ {
+		price, err := fetch()
+		if err == nil && (price <= 0 || math.IsNaN(price) || math.IsInf(price, 0)) {
+			err = fmt.Errorf("invalid price %v", price)
+		}
+		if err == nil || attempt >= attempts {
+			if err != nil && attempts > 1 {
+				err = fmt.Errorf("%w (after %d attempts)", err, attempts)
+			}
+			return price, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("%w (retry of %v cancelled)", ctx.Err(), err)
+		case <-time.After(b.retry.Delay):
+		}
+	}
+}
+
+// delay returns the wait after the current run of failures. Callers must
+// hold b.mu.
+func (b *oracleBackoff) delay() time.Duration {
+	delay := b.base
+	for i := 1; i < b.failures && delay < b.max; i++ {
+		delay *= 2
+	}
+	if delay > b.max {
+		delay = b.max
+	}
+	return delay
+}
+
+// CoinGeckoOracle reads prices from a CoinGecko-compatible
+// GET {endpoint}/simple/price?ids={token_id}&vs_currencies={vs_currency}
+type CoinGeckoOracle struct {
+	denom      string
+	tokenID    string
+	vsCurrency string
+	api        *ChainQuerier
+	backoff    *oracleBackoff
+}
+
+func (o *CoinGeckoOracle) GetPrice(ctx context.Context, denom string) (float64, error) {
+	if denom != o.denom {
+		return 0, fmt.Errorf("coingecko oracle prices %s, not %s", o.denom, denom)
+	}
+	return o.backoff.do(ctx, func() (float64, error) {
+		query := url.Values{}
+		query.Set("ids", o.tokenID)
+		query.Set("vs_currencies", o.vsCurrency)
+
+		var resp map[string]map[string]float64
+		if err := o.api.getJSON(ctx, "/simple/price?"+query.Encode(), &resp); err != nil {
+			return 0, err
+		}
+		price, ok := resp[o.tokenID][o.vsCurrency]
+		if !ok {
+			return 0, fmt.Errorf("coingecko has no %s price for %s", o.vsCurrency, o.tokenID)
+		}
+		return price, nil
+	})
+}
+
+// RESTOracle reads prices from a field of the JSON response of a GET on its
+// endpoint, for price APIs without a dedicated oracle
+type RESTOracle struct {
+	denom   string
+	path    []string
+	api     *ChainQuerier
+	backoff *oracleBackoff
+}
+
+func (o *RESTOracle) GetPrice(ctx context.Context, denom string) (float64, error) {
+	if denom != o.denom {
+		return 0, fmt.Errorf("rest oracle prices %s, not %s", o.denom, denom)
+	}
+	return o.backoff.do(ctx, func() (float64, error) {
+		var resp interface{}
+		if err := o.api.getJSON(ctx, "", &resp); err != nil {
+			return 0, err
+		}
+		return jsonPathPrice(resp, o.path)
+	})
+}
+
+// jsonPathPrice returns the price at path in a decoded JSON value. Array
+// elements are addressed by index.
+func jsonPathPrice(value interface{}, path []string) (float64, error) {
+	for i, key := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return 0, fmt.Errorf("price_path %s not found in response", strings.Join(path[:i+1], "."))
+			}
+			value = next
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(v) {
+				return 0, fmt.Errorf("price_path %s not found in response", strings.Join(path[:i+1], "."))
+			}
+			value = v[index]
+		default:
+			return 0, fmt.Errorf("price_path %s not found in response", strings.Join(path[:i+1], "."))
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid price %q at %s", v, strings.Join(path, "."))
+		}
+		return price, nil
+	default:
+		return 0, fmt.Errorf("price_path %s is not a number", strings.Join(path, "."))
+	}
+}
+
+// TWAPOracle reads the arithmetic TWAP of an AMM pool with the Osmosis x/twap
+// gRPC query, from the configured window ago until now
+type TWAPOracle struct {
+	cfg     PriceOracleConfig
+	conn    *grpc.ClientConn
+	backoff *oracleBackoff
+	now     func() time.Time
+	// invoke sends an encoded request and returns the encoded response;
+	// replaced in tests
+	invoke func(ctx context.Context, method string, req []byte) ([]byte, error)
+}
+
+func newTWAPOracle(cfg PriceOracleConfig, chainGRPC string, backoff *oracleBackoff) (*TWAPOracle, error) {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = chainGRPC
+	}
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("price_oracle.endpoint or chain_grpc is required for the %s oracle", PriceOracleTWAP)
+	}
+	if cfg.BaseAsset == "" {
+		cfg.BaseAsset = cfg.denom()
+	}
+	if cfg.BaseDecimals == 0 {
+		cfg.BaseDecimals = DefaultSwapTokenInDecimals
+	}
+	if cfg.QuoteDecimals == 0 {
+		cfg.QuoteDecimals = DefaultSwapTokenOutDecimals
+	}
+	if cfg.Window == 0 {
+		cfg.Window = DefaultTWAPWindow
+	}
+
+	// The connection is established lazily, on the first query
+	conn, err := grpc.NewClient(cfg.Endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create twap oracle client for %s: %w", cfg.Endpoint, err)
+	}
+
+	o := &TWAPOracle{cfg: cfg, conn: conn, backoff: backoff, now: time.Now}
+	o.invoke = o.invokeGRPC
+	return o, nil
+}
+
+func (o *TWAPOracle) GetPrice(ctx context.Context, denom string) (float64, error) {
+	if denom != o.cfg.denom() {
+		return 0, fmt.Errorf("twap oracle prices %s, not %s", o.cfg.denom(), denom)
+	}
+	return o.backoff.do(ctx, func() (float64, error) {
+		req := encodeTWAPRequest(o.cfg.PoolID, o.cfg.BaseAsset, o.cfg.QuoteAsset, o.now().Add(-o.cfg.Window))
+		resp, err := o.invoke(ctx, TWAPQueryMethod, req)
+		if err != nil {
+			return 0, fmt.Errorf("twap query for pool %d failed: %w", o.cfg.PoolID, err)
+		}
+		twap, err := decodeTWAPResponse(resp)
+		if err != nil {
+			return 0, err
+		}
+		// The TWAP is in quote base units per base unit
+		return twap * math.Pow10(o.cfg.BaseDecimals-o.cfg.QuoteDecimals), nil
+	})
+}
+
+// Close closes the gRPC connection
+func (o *TWAPOracle) Close() error {
+	return o.conn.Close()
+}
+
+func (o *TWAPOracle) invokeGRPC(ctx context.Context, method string, req []byte) ([]byte, error) {
+	var resp rawProto
+	if err := o.conn.Invoke(ctx, method, rawProto(req), &resp, grpc.ForceCodec(rawProtoCodec{})); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// rawProto is an already encoded protobuf message. The bot does not link
+// the Osmosis protobuf types, so the two TWAP messages are encoded by hand.
+type rawProto []byte
+
+// rawProtoCodec passes rawProto messages through unchanged
+type rawProtoCodec struct{}
+
+func (rawProtoCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(rawProto)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return msg, nil
+}
+
+func (rawProtoCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(*rawProto)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+// Name is the content subtype the node expects for protobuf messages
+func (rawProtoCodec) Name() string { return "proto" }
+
+// encodeTWAPRequest encodes an ArithmeticTwapToNowRequest
+func encodeTWAPRequest(poolID uint64, baseAsset, quoteAsset string, startTime time.Time) []byte {
+	var ts []byte
+	ts = protowire.AppendTag(ts, 1, protowire.VarintType)
+	ts = protowire.AppendVarint(ts, uint64(startTime.Unix()))
+	if nanos := startTime.Nanosecond(); nanos != 0 {
+		ts = protowire.AppendTag(ts, 2, protowire.VarintType)
+		ts = protowire.AppendVarint(ts, uint64(nanos))
+	}
+
+	var req []byte
+	req = protowire.AppendTag(req, 1, protowire.VarintType)
+	req = protowire.AppendVarint(req, poolID)
+	req = protowire.AppendTag(req, 2, protowire.BytesType)
+	req = protowire.AppendString(req, baseAsset)
+	req = protowire.AppendTag(req, 3, protowire.BytesType)
+	req = protowire.AppendString(req, quoteAsset)
+	req = protowire.AppendTag(req, 4, protowire.BytesType)
+	req = protowire.AppendBytes(req, ts)
+	return req
+}
+
+// decodeTWAPResponse decodes the arithmetic_twap of an
+// ArithmeticTwapToNowResponse, an sdk.Dec encoded as its integer value
+// scaled by 10^18
+func decodeTWAPResponse(resp []byte) (float64, error) {
+	var twap string
+	for len(resp) > 0 {
+		num, typ, n := protowire.ConsumeTag(resp)
+		if n < 0 {
+			return 0, fmt.Errorf("malformed twap response: %w", protowire.ParseError(n))
+		}
+		resp = resp[n:]
+		if num == 1 && typ == protowire.BytesType {
+			value, n := protowire.ConsumeString(resp)
+			if n < 0 {
+				return 0, fmt.Errorf("malformed twap response: %w", protowire.ParseError(n))
+			}
+			twap, resp = value, resp[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, resp)
+		if n < 0 {
+			return 0, fmt.Errorf("malformed twap response: %w", protowire.ParseError(n))
+		}
+		resp = resp[n:]
+	}
+
+	scaled, ok := new(big.Int).SetString(twap, 10)
+	if !ok {
+		return 0, fmt.Errorf("invalid twap %q", twap)
+	}
+	price, _ := new(big.Rat).SetFrac(scaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(twapDecPrecision), nil)).Float64()
+	return price, nil
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestCoinGeckoOracle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/simple/price" || r.URL.Query().Get("ids") != "genesis-xr" || r.URL.Query().Get("vs_currencies") != "usd" {
+			http.Error(w, "unexpected query "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"genesis-xr":{"usd":3.25}}`))
+	}))
+	defer server.Close()
+
+	oracle, err := NewPriceOracle(PriceOracleConfig{Source: PriceOracleCoinGecko, Endpoint: server.URL, TokenID: "genesis-xr"}, "", PriceRetry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, err := oracle.GetPrice(context.Background(), DefaultPriceDenom)
+	if err != nil || price != 3.25 {
+		t.Fatalf("price = %v, %v, want 3.25", price, err)
+	}
+	if _, err := oracle.GetPrice(context.Background(), "uatom"); err == nil {
+		t.Fatal("oracle priced a denom it is not configured for")
+	}
+}
+
+func TestOracleBackoff(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	b := newOracleBackoff(time.Minute, 5*time.Minute)
+	b.now = func() time.Time { return clock }
+
+	calls := 0
+	down := errors.New("endpoint down")
+	fetch := func() (float64, error) {
+		calls++
+		return 0, down
+	}
+
+	// Each failure doubles the wait, up to the cap, and calls made while
+	// waiting do not reach the endpoint
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute} {
+		if _, err := b.do(context.Background(), fetch); !errors.Is(err, down) {
+			t.Fatalf("failure %d returned %v", i+1, err)
+		}
+		if calls != i+1 {
+			t.Fatalf("failure %d made %d calls", i+1, calls)
+		}
+		clock = clock.Add(want - time.Second)
+		if _, err := b.do(context.Background(), fetch); !errors.Is(err, ErrPriceOracleBackoff) || calls != i+1 {
+			t.Fatalf("call %s into a %s backoff returned %v after %d calls", want-time.Second, want, err, calls)
+		}
+		clock = clock.Add(time.Second)
+	}
+
+	// A success resets the backoff
+	price, err := b.do(context.Background(), func() (float64, error) { return 3.1, nil })
+	if err != nil || price != 3.1 {
+		t.Fatalf("price = %v, %v", price, err)
+	}
+	b.do(context.Background(), fetch)
+	clock = clock.Add(time.Minute)
+	if _, err := b.do(context.Background(), fetch); errors.Is(err, ErrPriceOracleBackoff) {
+		t.Fatal("backoff was not reset by the success")
+	}
+
+	// Nonsensical prices count as failures
+	clock = clock.Add(time.Hour)
+	if _, err := b.do(context.Background(), func() (float64, error) { return 0, nil }); err == nil {
+		t.Fatal("zero price accepted")
+	}
+}
+
+func TestOracleBackoffRetries(t *testing.T) {
+	b := newOracleBackoff(time.Minute, 5*time.Minute)
+	b.retry = PriceRetry{Attempts: 3, Delay: time.Millisecond}
+
+	// A request answering within its retries is no failure
+	calls := 0
+	down := errors.New("endpoint down")
+	price, err := b.do(context.Background(), func() (float64, error) {
+		calls++
+		if calls < 3 {
+			return 0, down
+		}
+		return 3.3, nil
+	})
+	if err != nil || price != 3.3 || calls != 3 || b.failures != 0 {
+		t.Fatalf("price = %v, %v after %d calls, %d failures", price, err, calls, b.failures)
+	}
+
+	// One failing all its retries counts once and starts the backoff
+	calls = 0
+	if _, err := b.do(context.Background(), func() (float64, error) {
+		calls++
+		return 0, down
+	}); !errors.Is(err, down) || calls != 3 || b.failures != 1 {
+		t.Fatalf("err = %v after %d calls, %d failures", err, calls, b.failures)
+	}
+	if _, err := b.do(context.Background(), func() (float64, error) { return 3.3, nil }); !errors.Is(err, ErrPriceOracleBackoff) {
+		t.Fatalf("retried request did not back off: %v", err)
+	}
+}
+
+func TestMockPriceOracle(t *testing.T) {
+	if err := ValidatePriceOracleConfig(PriceOracleConfig{Source: PriceOracleMock}, nil); err != nil {
+		t.Fatal(err)
+	}
+	r := newTestRebalancer(t, &BotConfig{PriceOracle: PriceOracleConfig{Source: PriceOracleMock}}, nil)
+	r.telegramAlert = nil
+	if price, err := r.priceSource.FetchGXRPrice(context.Background()); err != nil || price <= 0 {
+		t.Fatalf("mock price = %v, %v", price, err)
+	}
+
+	// Prices are never simulated without asking for the mock
+	for _, source := range []string{"", "simulated", "chainlink"} {
+		if r, err := NewRebalancer(&BotConfig{PriceOracle: PriceOracleConfig{Source: source}}, nil); err == nil {
+			t.Fatalf("source %q: rebalancer created with oracle %T", source, r.oracle)
+		}
+	}
+	if _, err := NewRebalancer(&BotConfig{PriceSources: []PriceSourceConfig{{Name: "sim"}}}, nil); err == nil {
+		t.Fatal("rebalancer created with a price source without source")
+	}
+}
+
+// newTestTWAPOracle returns a TWAP oracle for $3.25 per GXR against a 6
+// decimal stablecoin, answering with invoke
+func newTestTWAPOracle(t *testing.T, endpoint string) *TWAPOracle {
+	t.Helper()
+	oracle, err := NewPriceOracle(PriceOracleConfig{
+		Source:     PriceOracleTWAP,
+		Endpoint:   endpoint,
+		PoolID:     7,
+		BaseAsset:  "ibc/GXR",
+		QuoteAsset: "uusdc",
+	}, "localhost:9090", PriceRetry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { oracle.(*TWAPOracle).Close() })
+	return oracle.(*TWAPOracle)
+}
+
+// twapResponse encodes an ArithmeticTwapToNowResponse of $3.25 per GXR:
+// 0.0325 uusdc per ugen, scaled by 10^18
+func twapResponse() []byte {
+	resp := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendString(resp, "32500000000000000")
+}
+
+func TestTWAPOracle(t *testing.T) {
+	oracle := newTestTWAPOracle(t, "")
+	start := time.Unix(1700000000, 0)
+	oracle.now = func() time.Time { return start.Add(DefaultTWAPWindow) }
+
+	oracle.invoke = func(_ context.Context, method string, req []byte) ([]byte, error) {
+		if method != TWAPQueryMethod {
+			t.Fatalf("called %s", method)
+		}
+		want := encodeTWAPRequest(7, "ibc/GXR", "uusdc", start)
+		if string(req) != string(want) {
+			t.Fatalf("request = %x, want %x", req, want)
+		}
+		return twapResponse(), nil
+	}
+
+	price, err := oracle.GetPrice(context.Background(), DefaultPriceDenom)
+	if err != nil || math.Abs(price-3.25) > 1e-9 {
+		t.Fatalf("price = %v, %v, want 3.25", price, err)
+	}
+	if oracle.cfg.Endpoint != "localhost:9090" {
+		t.Fatalf("endpoint = %s, want chain_grpc", oracle.cfg.Endpoint)
+	}
+
+	if _, err := decodeTWAPResponse(protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "1.5")); err == nil {
+		t.Fatal("decimal point accepted in an encoded sdk.Dec")
+	}
+}
+
+func TestTWAPOracleOverGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var method string
+	server := grpc.NewServer(grpc.ForceServerCodec(rawProtoCodec{}), grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		method, _ = grpc.MethodFromServerStream(stream)
+		var req rawProto
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+		return stream.SendMsg(rawProto(twapResponse()))
+	}))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	oracle := newTestTWAPOracle(t, listener.Addr().String())
+	price, err := oracle.GetPrice(context.Background(), DefaultPriceDenom)
+	if err != nil || math.Abs(price-3.25) > 1e-9 {
+		t.Fatalf("price = %v, %v, want 3.25", price, err)
+	}
+	if method != TWAPQueryMethod {
+		t.Fatalf("served %s", method)
+	}
+}
+
+func TestRebalancerFallsBackToLastGoodPrice(t *testing.T) {
+	r := newTestRebalancer(t, &BotConfig{PriceOracle: PriceOracleConfig{MaxFailures: 2}}, nil)
+	r.telegramAlert = nil
+
+	var down bool
+	r.priceSource = PriceSourceFunc(func(context.Context) (float64, error) {
+		if down {
+			return 0, errRPCUnreachable
+		}
+		return 3.4, nil
+	})
+	ctx := context.Background()
+
+	r.refreshPrice(ctx)
+	down = true
+	r.currentPrice = 9.9 // stands in for anything but the last good price
+
+	// Failures up to the threshold keep the state
+	r.refreshPrice(ctx)
+	r.refreshPrice(ctx)
+	if r.state != StateActive || r.priceFallback {
+		t.Fatalf("state %s after 2 failures, fallback %v", r.state, r.priceFallback)
+	}
+
+	r.refreshPrice(ctx)
+	if r.state != StateError || !r.priceFallback || r.currentPrice != 3.4 {
+		t.Fatalf("state %s, fallback %v, price %v after 3 failures", r.state, r.priceFallback, r.currentPrice)
+	}
+
+	// No auto-recovery while the oracle is down
+	r.stateChangeTime = time.Now().Add(-2 * time.Hour)
+	r.handleErrorState(ctx)
+	if r.state != StateError {
+		t.Fatal("recovered on a stale price")
+	}
+
+	down = false
+	r.refreshPrice(ctx)
+	if r.priceFallback {
+		t.Fatal("fallback kept after the oracle recovered")
+	}
+	r.handleErrorState(ctx)
+	if r.state != StateActive {
+		t.Fatalf("state %s once the oracle recovered", r.state)
+	}
+}
+
+func TestRebalancerSkipsRebalanceWithoutPrice(t *testing.T) {
+	venue := &mockSwapVenue{price: 3.0}
+	r := newVenueTestRebalancer(t, venue)
+	r.nextRebalanceTime = time.Now()
+
+	if err := r.processRebalanceCheck(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(venue.executed) != 0 {
+		t.Fatal("rebalanced on the placeholder price")
+	}
+}
+
+func TestValidatePriceOracleConfig(t *testing.T) {
+	for _, cfg := range []PriceOracleConfig{
+		{Source: PriceOracleMock},
+		{Source: PriceOracleCoinGecko, TokenID: "genesis-xr"},
+		{Source: PriceOracleTWAP, PoolID: 7, QuoteAsset: "uusdc"},
+	} {
+		if err := ValidatePriceOracleConfig(cfg, nil); err != nil {
+			t.Errorf("%+v: %v", cfg, err)
+		}
+	}
+	for _, cfg := range []PriceOracleConfig{
+		{},
+		{Source: "simulated"},
+		{Source: "chainlink"},
+		{Source: PriceOracleCoinGecko},
+		{Source: PriceOracleTWAP, QuoteAsset: "uusdc"},
+		{Source: PriceOracleTWAP, PoolID: 7},
+		{MaxFailures: -1},
+		{QuoteDecimals: 19},
+	} {
+		if err := ValidatePriceOracleConfig(cfg, nil); err == nil {
+			t.Errorf("%+v accepted", cfg)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PriceSourceConfig is one oracle of the price_sources the rebalancer takes
+// the median of
+type PriceSourceConfig struct {
+	Name              string `yaml:"name"` // shown in the status, default the source
+	PriceOracleConfig `yaml:",inline"`
+}
+
+// name returns the configured name or the source
+func (cfg PriceSourceConfig) name() string {
+	if cfg.Name != "" {
+		return cfg.Name
+	}
+	return cfg.Source
+}
+
+// ValidatePriceSources validates the price_sources configuration
+func ValidatePriceSources(sources []PriceSourceConfig) error {
+	names := make(map[string]bool, len(sources))
+	for i, source := range sources {
+		if err := validatePriceOracleConfig(source.PriceOracleConfig, fmt.Sprintf("price_sources[%d]", i)); err != nil {
+			return err
+		}
+		if names[source.name()] {
+			return fmt.Errorf("duplicate price source name %q, set price_sources[%d].name", source.name(), i)
+		}
+		names[source.name()] = true
+	}
+	return nil
+}
+
+// PriceSourceStatus is the outcome of a price source's last update
+type PriceSourceStatus struct {
+	Name          string    `json:"name"`
+	Source        string    `json:"source"`
+	Healthy       bool      `json:"healthy"`
+	LastPrice     float64   `json:"last_price"`
+	LastFetchedAt time.Time `json:"last_fetched_at"`
+	LastError     string    `json:"last_error,omitempty"`
+}
+
+// priceSource is an oracle of a MedianPriceOracle with its last outcome
+type priceSource struct {
+	oracle PriceOracle
+	status PriceSourceStatus
+}
+
+// MedianPriceOracle queries several oracles and returns the median of the
+// prices they answered with. It fails only when every oracle fails, so a
+// single unreachable or backing-off source does not stop the rebalancer.
+type MedianPriceOracle struct {
+	mu      sync.Mutex
+	sources []*priceSource
+	now     func() time.Time
+}
+
+// NewMedianPriceOracle creates the oracles of sources. chainGRPC is the
+// default address of TWAP oracles, retry how each retries failed requests.
+func NewMedianPriceOracle(sources []PriceSourceConfig, chainGRPC string, retry PriceRetry) (*MedianPriceOracle, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no price sources configured")
+	}
+	if err := ValidatePriceSources(sources); err != nil {
+		return nil, err
+	}
+
+	m := &MedianPriceOracle{now: time.Now}
+	for _, cfg := range sources {
+		oracle, err := NewPriceOracle(cfg.PriceOracleConfig, chainGRPC, retry)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("price source %s: %w", cfg.name(), err)
+		}
+		m.addSource(cfg.name(), cfg.Source, oracle)
+	}
+	return m, nil
+}
+
+// addSource adds an oracle under name
+func (m *MedianPriceOracle) addSource(name, source string, oracle PriceOracle) {
+	m.sources = append(m.sources, &priceSource{oracle: oracle, status: PriceSourceStatus{Name: name, Source: source}})
+}
+
+func (m *MedianPriceOracle) GetPrice(ctx context.Context, denom string) (float64, error) {
+	prices := make([]float64, len(m.sources))
+	errs := make([]error, len(m.sources))
+	var wg sync.WaitGroup
+	for i, source := range m.sources {
+		wg.Add(1)
+		go func(i int, oracle PriceOracle) {
+			defer wg.Done()
+			prices[i], errs[i] = oracle.GetPrice(ctx, denom)
+		}(i, source.oracle)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	var answered []float64
+	var failures []string
+	for i, source := range m.sources {
+		if errs[i] != nil {
+			source.status.Healthy = false
+			source.status.LastError = errs[i].Error()
+			failures = append(failures, fmt.Sprintf("%s: %v", source.status.Name, errs[i]))
+			continue
+		}
+		source.status.Healthy = true
+		source.status.LastPrice = prices[i]
+		source.status.LastFetchedAt = now
+		source.status.LastError = ""
+		answered = append(answered, prices[i])
+	}
+
+	if len(answered) == 0 {
+		return 0, fmt.Errorf("all %d price sources failed: %s", len(m.sources), strings.Join(failures, "; "))
+	}
+	return median(answered), nil
+}
+
+// SourceStatus returns the outcome of each source's last update
+func (m *MedianPriceOracle) SourceStatus() []PriceSourceStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]PriceSourceStatus, len(m.sources))
+	for i, source := range m.sources {
+		statuses[i] = source.status
+	}
+	return statuses
+}
+
+// Close closes the sources holding connections
+func (m *MedianPriceOracle) Close() error {
+	var firstErr error
+	for _, source := range m.sources {
+		if closer, ok := source.oracle.(io.Closer); ok {
+			if err := closer.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// median returns the median of values, the mean of the middle two for an
+// even count
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRESTOracle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":[{"symbol":"GXR","price":"3.30"}]}`))
+	}))
+	defer server.Close()
+
+	oracle, err := NewPriceOracle(PriceOracleConfig{Source: PriceOracleREST, Endpoint: server.URL + "/v1/ticker?symbol=GXR", PricePath: "data.0.price", APIKey: "secret"}, "", PriceRetry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, err := oracle.GetPrice(context.Background(), DefaultPriceDenom)
+	if err != nil || price != 3.3 {
+		t.Fatalf("price = %v, %v, want 3.3", price, err)
+	}
+
+	for _, path := range []string{"data.1.price", "data.0.symbol", "data.price"} {
+		var resp interface{} = map[string]interface{}{"data": []interface{}{map[string]interface{}{"symbol": "GXR"}}}
+		if _, err := jsonPathPrice(resp, strings.Split(path, ".")); err == nil {
+			t.Errorf("%s resolved to a price", path)
+		}
+	}
+}
+
+func TestCoinGeckoOracleSendsAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-cg-demo-api-key") != "demo" {
+			http.Error(w, "missing key", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"genesis-xr":{"usd":3.1}}`))
+	}))
+	defer server.Close()
+
+	oracle, err := NewPriceOracle(PriceOracleConfig{Source: PriceOracleCoinGecko, Endpoint: server.URL, TokenID: "genesis-xr", APIKey: "demo", APIKeyHeader: "x-cg-demo-api-key"}, "", PriceRetry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price, err := oracle.GetPrice(context.Background(), DefaultPriceDenom); err != nil || price != 3.1 {
+		t.Fatalf("price = %v, %v, want 3.1", price, err)
+	}
+}
+
+func TestMedianPriceOracle(t *testing.T) {
+	down := errors.New("endpoint down")
+	prices := map[string]float64{"coingecko": 3.2, "dex": 3.0, "cex": 3.6}
+	failing := map[string]bool{}
+
+	m := &MedianPriceOracle{now: time.Now}
+	for _, name := range []string{"coingecko", "dex", "cex"} {
+		name := name
+		m.addSource(name, PriceOracleREST, PriceOracleFunc(func(context.Context, string) (float64, error) {
+			if failing[name] {
+				return 0, down
+			}
+			return prices[name], nil
+		}))
+	}
+	ctx := context.Background()
+
+	if price, err := m.GetPrice(ctx, DefaultPriceDenom); err != nil || price != 3.2 {
+		t.Fatalf("median of three = %v, %v, want 3.2", price, err)
+	}
+
+	// A failing source is left out and reported unhealthy with its last price
+	failing["cex"] = true
+	if price, err := m.GetPrice(ctx, DefaultPriceDenom); err != nil || price != 3.1 {
+		t.Fatalf("median of two = %v, %v, want 3.1", price, err)
+	}
+	status := m.SourceStatus()
+	if !status[0].Healthy || status[2].Healthy || status[2].LastPrice != 3.6 || status[2].LastError != down.Error() {
+		t.Fatalf("status = %+v", status)
+	}
+
+	failing["coingecko"], failing["dex"] = true, true
+	if _, err := m.GetPrice(ctx, DefaultPriceDenom); err == nil {
+		t.Fatal("price returned with every source down")
+	}
+}
+
+func TestRebalancerErrorsWhenAllPriceSourcesFail(t *testing.T) {
+	r := newTestRebalancer(t, &BotConfig{
+		PriceOracle:  PriceOracleConfig{MaxFailures: 1},
+		PriceSources: []PriceSourceConfig{{Name: "gecko", PriceOracleConfig: PriceOracleConfig{Source: PriceOracleCoinGecko, TokenID: "genesis-xr"}}, {Name: "sim", PriceOracleConfig: PriceOracleConfig{Source: PriceOracleMock}}},
+	}, nil)
+	r.telegramAlert = nil
+
+	m, ok := r.oracle.(*MedianPriceOracle)
+	if !ok {
+		t.Fatalf("oracle is %T, want the median of price_sources", r.oracle)
+	}
+	down := true
+	m.sources = nil
+	for _, name := range []string{"gecko", "sim"} {
+		m.addSource(name, "", PriceOracleFunc(func(context.Context, string) (float64, error) {
+			if down {
+				return 0, errRPCUnreachable
+			}
+			return 3.4, nil
+		}))
+	}
+	ctx := context.Background()
+
+	r.refreshPrice(ctx)
+	if r.state != StateActive {
+		t.Fatalf("state %s after the first failure", r.state)
+	}
+	r.refreshPrice(ctx)
+	if r.state != StateError {
+		t.Fatalf("state %s after every source failed twice", r.state)
+	}
+
+	down = false
+	r.refreshPrice(ctx)
+	sources, ok := r.GetStatus()["price_sources"].([]PriceSourceStatus)
+	if !ok || len(sources) != 2 || !sources[0].Healthy || sources[1].LastPrice != 3.4 {
+		t.Fatalf("price_sources status = %+v", r.GetStatus()["price_sources"])
+	}
+}
+
+func TestValidatePriceSources(t *testing.T) {
+	valid := []PriceSourceConfig{
+		{PriceOracleConfig: PriceOracleConfig{Source: PriceOracleCoinGecko, TokenID: "genesis-xr"}},
+		{PriceOracleConfig: PriceOracleConfig{Source: PriceOracleREST, Endpoint: "https://api.example.com/gxr", PricePath: "price"}},
+		{Name: "second-gecko", PriceOracleConfig: PriceOracleConfig{Source: PriceOracleCoinGecko, TokenID: "genesis-xr"}},
+	}
+	if err := ValidatePriceSources(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sources := range [][]PriceSourceConfig{
+		{{PriceOracleConfig: PriceOracleConfig{Source: PriceOracleREST, PricePath: "price"}}},
+		{{PriceOracleConfig: PriceOracleConfig{Source: PriceOracleREST, Endpoint: "https://api.example.com/gxr"}}},
+		{valid[0], valid[0]},
+	} {
+		if err := ValidatePriceSources(sources); err == nil {
+			t.Errorf("%+v accepted", sources)
+		}
+	}
+}
+
+func TestPriceSourcesReplacePriceOracleSource(t *testing.T) {
+	const base = `chain_rpc: "tcp://localhost:26657"
+chain_grpc: "localhost:9090"
+chain_id: "gxr-1"
+validator_address: "gxrvaloper1ours"
+price_sources:
+  - source: "coingecko"
+    token_id: "genesis-xr"
+`
+	if _, err := LoadConfig(writeConfig(t, base)); err != nil {
+		t.Fatalf("price_sources without price_oracle.source: %v", err)
+	}
+	if _, err := LoadConfig(writeConfig(t, base+"price_oracle:\n  max_failures: -1\n")); err == nil {
+		t.Fatal("negative price_oracle.max_failures accepted with price_sources")
+	}
+	if _, err := LoadConfig(writeConfig(t, base+"price_oracle:\n  source: \"chainlink\"\n")); err == nil {
+		t.Fatal("unknown price_oracle.source accepted with price_sources")
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// BotProtocolVersion is the mandatory-bot protocol version spoken by this binary
+	BotProtocolVersion uint64 = 1
+	// ProtocolCheckInterval is how often the chain's expected protocol is re-checked
+	ProtocolCheckInterval = 1 * time.Hour
+)
+
+// ProtocolInfo is the bot protocol information advertised by the chain
+type ProtocolInfo struct {
+	ChainVersion        uint64
+	MinSupportedVersion uint64
+}
+
+// CheckProtocolCompatibility verifies that this bot speaks a protocol version
+// the chain accepts, i.e. MinSupportedVersion <= BotProtocolVersion <= ChainVersion
+func CheckProtocolCompatibility(info ProtocolInfo) error {
+	if BotProtocolVersion < info.MinSupportedVersion {
+		return fmt.Errorf("bot protocol v%d is no longer supported (chain minimum v%d, expected v%d) - upgrade the bot",
+			BotProtocolVersion, info.MinSupportedVersion, info.ChainVersion)
+	}
+
+	if BotProtocolVersion > info.ChainVersion {
+		return fmt.Errorf("bot protocol v%d is newer than the chain's v%d - the chain has not been upgraded yet",
+			BotProtocolVersion, info.ChainVersion)
+	}
+
+	return nil
+}
+
+// checkProtocolCompatibility performs the compatibility handshake with the chain.
+// Query failures leave the previous result in place so a flaky node never blocks startup.
+func (bs *BotService) checkProtocolCompatibility(ctx context.Context) {
+	if bs.chainQuerier == nil {
+		return
+	}
+
+	info, err := bs.chainQuerier.QueryBotProtocolVersion(ctx)
+	if err != nil {
+		log.Printf("Bot protocol check skipped: %v", err)
+		return
+	}
+
+	compatErr := CheckProtocolCompatibility(info)
+
+	bs.mu.Lock()
+	wasCompatible := bs.protocolCompatible
+	bs.protocolInfo = info
+	bs.protocolCompatible = compatErr == nil
+	if compatErr != nil {
+		bs.protocolError = compatErr.Error()
+	} else {
+		bs.protocolError = ""
+	}
+	bs.mu.Unlock()
+
+	if compatErr != nil {
+		log.Printf("Bot protocol incompatible, heartbeats suspended: %v", compatErr)
+		if wasCompatible && bs.telegramAlert != nil {
+			bs.telegramAlert.SendEmergencyAlert("Bot Protocol Mismatch", compatErr.Error(), map[string]interface{}{
+				"bot_protocol":       BotProtocolVersion,
+				"chain_protocol":     info.ChainVersion,
+				"chain_min_protocol": info.MinSupportedVersion,
+			})
+		}
+		return
+	}
+
+	if !wasCompatible {
+		log.Printf("Bot protocol v%d compatible with chain (expected v%d, minimum v%d)",
+			BotProtocolVersion, info.ChainVersion, info.MinSupportedVersion)
+		if bs.telegramAlert != nil {
+			bs.telegramAlert.SendBotAlert("GXR Bot", "running", "Bot protocol compatible again, heartbeats resumed")
+		}
+	}
+}
+
+// isProtocolCompatible reports whether heartbeats may be sent
+func (bs *BotService) isProtocolCompatible() bool {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	return bs.protocolCompatible
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckProtocolCompatibility(t *testing.T) {
+	testCases := []struct {
+		name    string
+		info    ProtocolInfo
+		wantErr bool
+	}{
+		{"exact match", ProtocolInfo{ChainVersion: BotProtocolVersion, MinSupportedVersion: BotProtocolVersion}, false},
+		{"chain ahead but still supported", ProtocolInfo{ChainVersion: BotProtocolVersion + 1, MinSupportedVersion: BotProtocolVersion}, false},
+		{"bot below minimum", ProtocolInfo{ChainVersion: BotProtocolVersion + 2, MinSupportedVersion: BotProtocolVersion + 1}, true},
+		{"bot ahead of chain", ProtocolInfo{ChainVersion: BotProtocolVersion - 1, MinSupportedVersion: BotProtocolVersion - 1}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckProtocolCompatibility(tc.info)
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected error for %+v", tc.info)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestHeartbeatGatedOnProtocolVersion(t *testing.T) {
+	var response atomic.Value
+	response.Store(`{"bot_protocol_version":"1","min_supported_version":"1"}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gxr/halving/v1beta1/bot_protocol_version" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(response.Load().(string)))
+	}))
+	defer server.Close()
+
+	config := &BotConfig{ChainAPI: server.URL, ValidatorAddress: "gxrvaloper1test"}
+	bs := &BotService{
+		config:             config,
+		healthStatus:       make(map[string]bool),
+		protocolCompatible: true,
+		chainQuerier:       NewChainQuerier(config),
+		validatorMonitor:   &ValidatorMonitor{validators: make(map[string]*ValidatorStatus), botHeartbeats: make(map[string]time.Time)},
+	}
+
+	bs.checkProtocolCompatibility(context.Background())
+	if !bs.emitHeartbeat(context.Background()) {
+		t.Fatal("heartbeat should be sent when protocol versions match")
+	}
+
+	// Chain requires a newer protocol than this binary speaks
+	response.Store(`{"bot_protocol_version":"3","min_supported_version":"2"}`)
+	bs.checkProtocolCompatibility(context.Background())
+	if bs.emitHeartbeat(context.Background()) {
+		t.Fatal("heartbeat must be refused when the bot protocol is below the chain minimum")
+	}
+
+	// Query failures keep the last known result
+	server.Close()
+	bs.checkProtocolCompatibility(context.Background())
+	if bs.isProtocolCompatible() {
+		t.Fatal("query failure must not re-enable heartbeats")
+	}
+}
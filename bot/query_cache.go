@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QueryCacheFile stores the last good chain query results in DataDir
+const QueryCacheFile = "query_cache.json"
+
+// Cached query datasets
+const (
+	QueryCacheValidators           = "validators"
+	QueryCachePrice                = "price"
+	QueryCachePendingDexAllocation = "pending_dex_allocation"
+)
+
+// QueryCachePolicy sets how long a dataset's cached result is good for. TTL
+// is how long a result counts as fresh; status reports an older one as
+// stale. When a query fails, a cached result up to MaxStale old is served
+// instead, flagged as cached; past MaxStale the failure propagates.
+type QueryCachePolicy struct {
+	TTL      time.Duration
+	MaxStale time.Duration
+}
+
+// DefaultQueryCachePolicies covers a few missed query cycles of each dataset,
+// so that brief RPC outages are bridged but a real one still surfaces
+var DefaultQueryCachePolicies = map[string]QueryCachePolicy{
+	QueryCacheValidators:           {TTL: ValidatorCheckInterval, MaxStale: 15 * time.Minute},
+	QueryCachePrice:                {TTL: PriceUpdateInterval, MaxStale: 10 * time.Minute},
+	QueryCachePendingDexAllocation: {TTL: RewardQueryInterval, MaxStale: 10 * time.Minute},
+}
+
+// queryCacheEntry is the last good result of a dataset
+type queryCacheEntry struct {
+	Data      json.RawMessage `json:"data"`
+	FetchedAt time.Time       `json:"fetched_at"`
+
+	// Not persisted: the current run's view of the dataset
+	servingCachedSince time.Time
+	lastError          string
+}
+
+// CachedResult describes where a CachedQuery result came from
+type CachedResult struct {
+	// Cached is set when the query failed and the cached result was served
+	Cached    bool
+	FetchedAt time.Time
+	Age       time.Duration
+}
+
+// QueryCache keeps the last good result of each chain query dataset, in
+// memory and in DataDir, so that components ride out brief RPC outages,
+// including across a restart, on slightly stale data
+type QueryCache struct {
+	mu       sync.Mutex
+	path     string
+	policies map[string]QueryCachePolicy
+	entries  map[string]*queryCacheEntry
+
+	// now is the clock, replaced in tests
+	now func() time.Time
+}
+
+// NewQueryCache loads the query cache from dataDir; a missing or broken file
+// starts an empty cache. An empty dataDir keeps the cache in memory only.
+// Datasets without a policy in policies use DefaultQueryCachePolicies.
+func NewQueryCache(dataDir string, policies map[string]QueryCachePolicy) *QueryCache {
+	qc := &QueryCache{
+		policies: make(map[string]QueryCachePolicy, len(DefaultQueryCachePolicies)),
+		entries:  make(map[string]*queryCacheEntry),
+		now:      time.Now,
+	}
+	for dataset, policy := range DefaultQueryCachePolicies {
+		qc.policies[dataset] = policy
+	}
+	for dataset, policy := range policies {
+		qc.policies[dataset] = policy
+	}
+
+	if dataDir == "" {
+		return qc
+	}
+
+	qc.path = filepath.Join(dataDir, QueryCacheFile)
+	data, err := os.ReadFile(qc.path)
+	if err != nil {
+		return qc
+	}
+	if err := json.Unmarshal(data, &qc.entries); err != nil {
+		log.Printf("Ignoring unreadable query cache %s: %v", qc.path, err)
+		qc.entries = make(map[string]*queryCacheEntry)
+	}
+	return qc
+}
+
+// CachedQuery runs fetch for a dataset and caches its result. If fetch fails
+// and the cached result is within the dataset's MaxStale, that result is
+// returned without error and flagged Cached. A nil cache only runs fetch.
+func CachedQuery[T any](qc *QueryCache, dataset string, fetch func() (T, error)) (T, CachedResult, error) {
+	if qc == nil {
+		value, err := fetch()
+		return value, CachedResult{FetchedAt: time.Now()}, err
+	}
+
+	value, err := fetch()
+	now := qc.now()
+	if err == nil {
+		qc.store(dataset, value, now)
+		return value, CachedResult{FetchedAt: now}, nil
+	}
+
+	var cached T
+	entry, ok := qc.failed(dataset, err, now)
+	if !ok {
+		return cached, CachedResult{}, err
+	}
+	age := now.Sub(entry.FetchedAt)
+	if maxStale := qc.policy(dataset).MaxStale; age > maxStale {
+		return cached, CachedResult{}, fmt.Errorf("%w (cached %s is %s old, limit %s)",
+			err, dataset, age.Round(time.Second), maxStale)
+	}
+	if uerr := json.Unmarshal(entry.Data, &cached); uerr != nil {
+		return cached, CachedResult{}, fmt.Errorf("%w (unreadable cached %s: %v)", err, dataset, uerr)
+	}
+
+	log.Printf("Query for %s failed, serving result from %s ago: %v", dataset, age.Round(time.Second), err)
+	return cached, CachedResult{Cached: true, FetchedAt: entry.FetchedAt, Age: age}, nil
+}
+
+// policy returns the policy of a dataset; unknown datasets are never served from cache
+func (qc *QueryCache) policy(dataset string) QueryCachePolicy {
+	return qc.policies[dataset]
+}
+
+// store records a fresh result and persists the cache
+func (qc *QueryCache) store(dataset string, value interface{}, now time.Time) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Not caching %s: %v", dataset, err)
+		return
+	}
+
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	qc.entries[dataset] = &queryCacheEntry{Data: data, FetchedAt: now}
+	if err := qc.saveLocked(); err != nil {
+		log.Printf("Failed to save query cache: %v", err)
+	}
+}
+
+// failed records a failed query and returns a copy of the cached entry, if any
+func (qc *QueryCache) failed(dataset string, err error, now time.Time) (queryCacheEntry, bool) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	entry, ok := qc.entries[dataset]
+	if !ok {
+		return queryCacheEntry{}, false
+	}
+	entry.lastError = err.Error()
+	if entry.servingCachedSince.IsZero() {
+		entry.servingCachedSince = now
+	}
+	return *entry, true
+}
+
+// Failing reports whether the last query of a dataset failed. A dataset
+// that never returned a result is not failing as far as the cache knows.
+func (qc *QueryCache) Failing(dataset string) bool {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	entry, ok := qc.entries[dataset]
+	return ok && !entry.servingCachedSince.IsZero()
+}
+
+// saveLocked atomically writes the cache file. qc.mu must be held.
+func (qc *QueryCache) saveLocked() error {
+	if qc.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(qc.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(qc.path), 0755); err != nil {
+		return err
+	}
+
+	tmp := qc.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, qc.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Status reports the age and staleness of every cached dataset
+func (qc *QueryCache) Status() map[string]interface{} {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	now := qc.now()
+	datasets := make([]string, 0, len(qc.entries))
+	for dataset := range qc.entries {
+		datasets = append(datasets, dataset)
+	}
+	sort.Strings(datasets)
+
+	status := make(map[string]interface{}, len(datasets))
+	for _, dataset := range datasets {
+		entry := qc.entries[dataset]
+		policy := qc.policy(dataset)
+		age := now.Sub(entry.FetchedAt)
+
+		dataStatus := map[string]interface{}{
+			"fetched_at":        entry.FetchedAt.Format(time.RFC3339),
+			"age_seconds":       int64(age.Seconds()),
+			"stale":             age > policy.TTL,
+			"expired":           age > policy.MaxStale,
+			"max_stale_seconds": int64(policy.MaxStale.Seconds()),
+		}
+		if !entry.servingCachedSince.IsZero() {
+			dataStatus["serving_cached_since"] = entry.servingCachedSince.Format(time.RFC3339)
+			dataStatus["last_error"] = entry.lastError
+		}
+		status[dataset] = dataStatus
+	}
+	return status
+}
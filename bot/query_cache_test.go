@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"google.golang.org/grpc"
+)
+
+var errRPCUnreachable = errors.New("connection refused")
+
+// unreachableStakingQuery fails every validator query, as during an RPC outage
+type unreachableStakingQuery struct {
+	stakingtypes.QueryClient
+}
+
+func (unreachableStakingQuery) Validators(context.Context, *stakingtypes.QueryValidatorsRequest, ...grpc.CallOption) (*stakingtypes.QueryValidatorsResponse, error) {
+	return nil, errRPCUnreachable
+}
+
+// queryCacheFixture runs the cached components against a chain that can be
+// taken down, on the cache's clock
+type queryCacheFixture struct {
+	ctx     context.Context
+	clock   time.Time
+	cache   *QueryCache
+	down    atomic.Bool
+	staking *fakeStakingQuery
+	monitor *ValidatorMonitor
+	reb     *Rebalancer
+	rd      *RewardDistributor
+}
+
+func newQueryCacheFixture(t *testing.T, dataDir string) *queryCacheFixture {
+	t.Helper()
+
+	f := &queryCacheFixture{ctx: context.Background(), clock: time.Unix(1700000000, 0)}
+	f.cache = NewQueryCache(dataDir, nil)
+	f.cache.now = func() time.Time { return f.clock }
+
+	f.monitor, f.staking, _ = newBatchTestMonitor(t, dataDir, 3)
+	f.monitor.queryCache = f.cache
+
+	f.reb = newTestRebalancer(t, &BotConfig{}, nil)
+	f.reb.telegramAlert = nil
+	f.reb.queryCache = f.cache
+	f.reb.priceSource = PriceSourceFunc(func(context.Context) (float64, error) {
+		if f.down.Load() {
+			return 0, errRPCUnreachable
+		}
+		return 3.2, nil
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.down.Load() {
+			http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"pending_dex_allocation":{"cycle":"1","amount":{"denom":"ugen","amount":"5000"},"months":"2"}}`))
+	}))
+	t.Cleanup(server.Close)
+	f.rd = NewRewardDistributor(&BotConfig{ChainAPI: server.URL})
+	f.rd.queryCache = f.cache
+
+	return f
+}
+
+// setDown takes the chain down or brings it back
+func (f *queryCacheFixture) setDown(down bool) {
+	f.down.Store(down)
+	if down {
+		f.monitor.stakingQuery = unreachableStakingQuery{}
+	} else {
+		f.monitor.stakingQuery = f.staking
+	}
+}
+
+// cycle advances the clock by a minute and runs every component's query once
+func (f *queryCacheFixture) cycle() (validatorErr, rewardErr error) {
+	f.clock = f.clock.Add(time.Minute)
+	f.reb.refreshPrice(f.ctx)
+	return f.monitor.checkAllValidators(f.ctx), f.rd.refreshPendingAllocation(f.ctx)
+}
+
+func TestQueryCacheBridgesShortOutage(t *testing.T) {
+	f := newQueryCacheFixture(t, t.TempDir())
+	if verr, rerr := f.cycle(); verr != nil || rerr != nil {
+		t.Fatal(verr, rerr)
+	}
+
+	statuses := make(map[string]ValidatorStatus)
+	for addr, status := range f.monitor.validators {
+		statuses[addr] = *status
+	}
+	history := f.reb.priceHistory.Len()
+
+	// Two minutes without the chain: every component keeps its state
+	f.setDown(true)
+	for i := 0; i < 2; i++ {
+		if verr, rerr := f.cycle(); verr != nil || rerr != nil {
+			t.Fatalf("minute %d of the outage failed: %v, %v", i+1, verr, rerr)
+		}
+	}
+
+	if f.reb.state != StateActive || f.reb.priceUpdateErrors != 0 || f.reb.priceHistory.Len() != history {
+		t.Fatalf("rebalancer changed during the outage: state %s, %d errors, %d prices", f.reb.state, f.reb.priceUpdateErrors, f.reb.priceHistory.Len())
+	}
+	if !f.reb.GetStatus()["price_from_cache"].(bool) {
+		t.Fatal("cached price not flagged in status")
+	}
+
+	if !f.monitor.lastQueryStats.FromCache || f.monitor.lastQueryStats.DataAge != 2*time.Minute {
+		t.Fatalf("validator cycle stats = %+v, want served from a 2m old cache", f.monitor.lastQueryStats)
+	}
+	if f.monitor.totalValidators != len(statuses) {
+		t.Fatalf("tracking %d validators, want %d", f.monitor.totalValidators, len(statuses))
+	}
+	for addr, status := range f.monitor.validators {
+		if !reflect.DeepEqual(*status, statuses[addr]) {
+			t.Fatalf("validator %s changed on cached data: %+v", addr, *status)
+		}
+	}
+
+	rdStatus := f.rd.GetStatus()
+	if !rdStatus["connected"].(bool) || !rdStatus["pending_dex_from_cache"].(bool) || rdStatus["pending_dex_amount"] != int64(5000) {
+		t.Fatalf("reward distributor status during the outage = %v", rdStatus)
+	}
+
+	validators := f.cache.Status()[QueryCacheValidators].(map[string]interface{})
+	if validators["age_seconds"] != int64(120) || validators["last_error"] != errRPCUnreachable.Error() {
+		t.Fatalf("cache status = %v", validators)
+	}
+	if _, ok := validators["serving_cached_since"]; !ok {
+		t.Fatal("cache status does not show the cached dataset being served")
+	}
+
+	// Recovery clears the flags
+	f.setDown(false)
+	if verr, rerr := f.cycle(); verr != nil || rerr != nil {
+		t.Fatal(verr, rerr)
+	}
+	if f.reb.priceFromCache || f.monitor.lastQueryStats.FromCache || f.rd.pendingDexFromCache {
+		t.Fatal("fresh results still flagged as cached")
+	}
+	if _, ok := f.cache.Status()[QueryCacheValidators].(map[string]interface{})["serving_cached_since"]; ok {
+		t.Fatal("recovered dataset still reported as served from cache")
+	}
+}
+
+func TestQueryCacheLongOutageFails(t *testing.T) {
+	f := newQueryCacheFixture(t, t.TempDir())
+	if verr, rerr := f.cycle(); verr != nil || rerr != nil {
+		t.Fatal(verr, rerr)
+	}
+
+	f.setDown(true)
+	var verr, rerr error
+	for minute := 1; minute <= 30; minute++ {
+		verr, rerr = f.cycle()
+
+		// The price and allocation are served for 10 minutes
+		if minute <= 10 && (rerr != nil || f.reb.priceUpdateErrors != 0) {
+			t.Fatalf("minute %d: cached results not served: %v, %d price errors", minute, rerr, f.reb.priceUpdateErrors)
+		}
+		if minute == 11 && (rerr == nil || f.rd.connected()) {
+			t.Fatalf("minute %d: reward distributor still connected on an expired cache", minute)
+		}
+		// The bonded set for 15
+		if minute <= 15 && verr != nil {
+			t.Fatalf("minute %d: cached validators not served: %v", minute, verr)
+		}
+		if minute == 16 && verr == nil {
+			t.Fatal("validator check succeeded on an expired cache")
+		}
+	}
+
+	if !errors.Is(verr, errRPCUnreachable) || rerr == nil {
+		t.Fatalf("outage errors not propagated: %v, %v", verr, rerr)
+	}
+	if f.reb.state != StateError {
+		t.Fatalf("rebalancer state = %s after 30 minutes without prices, want error", f.reb.state)
+	}
+	if f.rd.GetStatus()["connected"].(bool) {
+		t.Fatal("reward distributor reports connected after 30 minutes without the chain")
+	}
+	if !f.cache.Status()[QueryCachePrice].(map[string]interface{})["expired"].(bool) {
+		t.Fatal("expired price not reported in cache status")
+	}
+}
+
+func TestQueryCacheSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	f := newQueryCacheFixture(t, dir)
+	if verr, rerr := f.cycle(); verr != nil || rerr != nil {
+		t.Fatal(verr, rerr)
+	}
+	fetchedAt := f.clock
+
+	// The bot restarts during an outage and picks up the persisted results
+	f.monitor.Stop()
+	restarted := newQueryCacheFixture(t, dir)
+	restarted.clock = fetchedAt
+	restarted.setDown(true)
+	if verr, rerr := restarted.cycle(); verr != nil || rerr != nil {
+		t.Fatalf("restart during an outage: %v, %v", verr, rerr)
+	}
+
+	if restarted.monitor.totalValidators != 3 {
+		t.Fatalf("restarted monitor tracks %d validators from the cache, want 3", restarted.monitor.totalValidators)
+	}
+	if restarted.reb.currentPrice != 3.2 || !restarted.reb.lastPriceUpdate.Equal(fetchedAt) {
+		t.Fatalf("restarted rebalancer price = %v at %s", restarted.reb.currentPrice, restarted.reb.lastPriceUpdate)
+	}
+	if restarted.rd.pendingDex.Amount != 5000 || !restarted.rd.pendingDexFromCache {
+		t.Fatalf("restarted reward distributor allocation = %+v", restarted.rd.pendingDex)
+	}
+
+	// A cached result is not recorded twice
+	history := restarted.reb.priceHistory.Len()
+	restarted.cycle()
+	if restarted.reb.priceHistory.Len() != history {
+		t.Fatal("cached price recorded again")
+	}
+}
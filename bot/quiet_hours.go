@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuietHoursConfig defines a daily window during which non-critical alerts are batched
+type QuietHoursConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Start    string `yaml:"start"`    // "HH:MM", e.g. "22:00"
+	End      string `yaml:"end"`      // "HH:MM", e.g. "07:00"
+	Timezone string `yaml:"timezone"` // IANA name, e.g. "Asia/Jakarta"
+}
+
+// QuietHours is the parsed form of QuietHoursConfig
+type QuietHours struct {
+	startMinute int
+	endMinute   int
+	location    *time.Location
+}
+
+// ParseQuietHours parses and validates a quiet hours configuration
+func ParseQuietHours(cfg QuietHoursConfig) (*QuietHours, error) {
+	start, err := parseClockMinutes(cfg.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet_hours.start: %w", err)
+	}
+
+	end, err := parseClockMinutes(cfg.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet_hours.end: %w", err)
+	}
+
+	if start == end {
+		return nil, fmt.Errorf("quiet_hours start and end must differ")
+	}
+
+	location := time.UTC
+	if cfg.Timezone != "" {
+		location, err = time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet_hours.timezone: %w", err)
+		}
+	}
+
+	return &QuietHours{
+		startMinute: start,
+		endMinute:   end,
+		location:    location,
+	}, nil
+}
+
+// parseClockMinutes parses "HH:MM" into minutes since midnight
+func parseClockMinutes(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid hour in %q", value)
+	}
+
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid minute in %q", value)
+	}
+
+	return hour*60 + minute, nil
+}
+
+// Contains reports whether t falls inside the quiet window. Windows that
+// cross midnight (e.g. 22:00-07:00) are supported.
+func (qh *QuietHours) Contains(t time.Time) bool {
+	local := t.In(qh.location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if qh.startMinute < qh.endMinute {
+		return minute >= qh.startMinute && minute < qh.endMinute
+	}
+
+	return minute >= qh.startMinute || minute < qh.endMinute
+}
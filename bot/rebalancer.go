@@ -3,26 +3,24 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
-	"math"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
 const (
-	// RebalanceInterval is exactly 1 hour
-	RebalanceInterval = 1 * time.Hour
-	// PriceThreshold is exactly $5.00 USD
-	PriceThreshold = 5.0
-	// MonitorOnlyDuration is exactly 24 hours
-	MonitorOnlyDuration = 24 * time.Hour
+	// DefaultRebalanceInterval is the interval between rebalances without rebalance_interval
+	DefaultRebalanceInterval = 1 * time.Hour
+	// MinRebalanceInterval is the shortest rebalance_interval accepted
+	MinRebalanceInterval = 10 * time.Minute
+	// DefaultMonitorOnlyDuration is how long monitor-only mode lasts without monitor_only_duration
+	DefaultMonitorOnlyDuration = 24 * time.Hour
 	// PriceUpdateInterval is 1 minute
 	PriceUpdateInterval = 1 * time.Minute
-	// MaxPriceHistory keeps last 60 price points
-	MaxPriceHistory = 60
-	// EmergencyStopThreshold is 500% above baseline
-	EmergencyStopThreshold = 5.0
 )
 
 // RebalanceState represents the current state of the rebalancer
@@ -33,6 +31,9 @@ const (
 	StateMonitorOnly
 	StateEmergencyStop
 	StateError
+	// StateStopped is a clean shutdown; it is never persisted, so a restart
+	// resumes the state the rebalancer stopped in
+	StateStopped
 )
 
 func (s RebalanceState) String() string {
@@ -45,6 +46,8 @@ func (s RebalanceState) String() string {
 		return "emergency_stop"
 	case StateError:
 		return "error"
+	case StateStopped:
+		return "stopped"
 	default:
 		return "unknown"
 	}
@@ -55,16 +58,43 @@ type Rebalancer struct {
 	config *BotConfig
 	mu     sync.RWMutex
 	
+	// Price limits and timings from the config
+	thresholds          RebalancerThresholds
+	
 	// State management
 	state               RebalanceState
 	stateChangeTime     time.Time
 	stateChangeReason   string
+	stopped             bool // Stop ran; later calls do nothing
 	
 	// Price monitoring
 	currentPrice        float64
-	priceHistory        []float64
+	priceHistory        *PriceHistory
 	lastPriceUpdate     time.Time
 	priceUpdateErrors   int
+	oracle              PriceOracle
+	priceSource         PriceSource // reads the GXR price from oracle
+	priceDenom          string
+	maxPriceFailures    int
+	queryCache          *QueryCache // serves the last price through brief outages
+	shadow              *ShadowRunner // candidate threshold logic in shadow mode
+	priceFromCache      bool
+	
+	// Last price received from the oracle, which the rebalancer falls back
+	// to once the oracle fails more than maxPriceFailures times in a row
+	lastGoodPrice       float64
+	lastGoodPriceAt     time.Time
+	priceFallback       bool
+	
+	// Price monitoring pause (e.g. during a known oracle outage)
+	priceMonitoringPaused   bool
+	priceMonitoringPausedAt time.Time
+	
+	// Smoothed price signals
+	ema1h               *PriceEMA
+	ema24h              *PriceEMA
+	thresholdSource     ThresholdSource
+	warmupNoticeLogged  bool
 	
 	// Rebalancing state
 	lastRebalance       time.Time
@@ -72,6 +102,34 @@ type Rebalancer struct {
 	nextRebalanceTime   time.Time
 	totalRebalanceVolume float64
 	
+	// Dry run: rebalances are simulated and reported instead of swapped, and
+	// counted apart from the executed ones
+	dryRun              bool
+	simulatedCount      int64
+	simulatedVolume     float64
+	lastSimulated       time.Time
+	
+	// Swap execution and slippage tracking
+	swapVenue           SwapVenue
+	maxSlippage         float64
+	lastQuote           SwapQuote
+	lastSwap            SwapResult
+	lastSlippage        float64
+	totalSlippage       float64
+	worstSlippage       float64
+	swapCount           int64
+	slippageBreaches    int64
+	
+	// DEX pools of dex_pools the rebalance volume is split across, and the
+	// swaps of the last rebalance on them
+	swaps               SwapExecutor
+	
+	// Where the state machine and counters are persisted, empty without a
+	// data_dir
+	statePath           string
+	lastPoolSwaps       []PoolSwap
+	poolSwapFailures    int64
+	
 	// Monitor-only mode state
 	monitorOnlyStart    time.Time
 	monitorOnlyReason   string
@@ -92,25 +150,91 @@ type Rebalancer struct {
 	priceVolatility     float64
 }
 
-// NewRebalancer creates a new enhanced rebalancer instance
-func NewRebalancer(config *BotConfig) *Rebalancer {
-	return &Rebalancer{
+// PoolSwap is the outcome of a rebalance swap on one DEX pool
+type PoolSwap struct {
+	Pool      string    `json:"pool"`
+	AmountGXR float64   `json:"amount_gxr"`
+	TxHash    string    `json:"tx_hash,omitempty"`
+	Simulated bool      `json:"simulated,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// NewRebalancer creates a new enhanced rebalancer instance. With dex_pools
+// configured rebalances are swapped on those pools through swaps, which may
+// be nil to always use the swap venue. It fails when the price oracle cannot
+// be created; prices are only simulated with price_oracle.source mock.
+func NewRebalancer(config *BotConfig, swaps SwapExecutor) (*Rebalancer, error) {
+	thresholdSource, err := ParseThresholdSource(config.ThresholdSource)
+	if err != nil {
+		log.Printf("%v, using spot price", err)
+	}
+	thresholds, err := ParseRebalancerThresholds(config)
+	if err != nil {
+		log.Printf("%v, using the default rebalancer thresholds", err)
+		thresholds = DefaultRebalancerThresholds()
+	}
+	
+	r := &Rebalancer{
 		config:              config,
+		thresholds:          thresholds,
 		state:               StateActive,
 		stateChangeTime:     time.Now(),
 		stateChangeReason:   "initialization",
 		currentPrice:        3.0, // Default price below threshold
-		priceHistory:        make([]float64, 0, MaxPriceHistory),
+		priceHistory:        NewPriceHistory(config.PriceHistorySize),
+		ema1h:               NewPriceEMA(EMAShortPeriod),
+		ema24h:              NewPriceEMA(EMALongPeriod),
+		thresholdSource:     thresholdSource,
 		lastRebalance:       time.Now(),
-		nextRebalanceTime:   time.Now().Add(RebalanceInterval),
+		nextRebalanceTime:   time.Now().Add(thresholds.RebalanceInterval),
 		lastDailyReset:      time.Now(),
+		dryRun:              config.RebalancerDryRun,
 		telegramAlert:       NewTelegramAlert(config),
+		maxSlippage:         config.SwapVenue.maxSlippage(),
+		swaps:               swaps,
+		priceDenom:          config.PriceOracle.denom(),
+		maxPriceFailures:    config.PriceOracle.maxFailures(),
 	}
+	
+	var oracle PriceOracle
+	retry := PriceRetry{Attempts: config.RetryAttempts, Delay: config.RetryDelay}
+	if len(config.PriceSources) > 0 {
+		oracle, err = NewMedianPriceOracle(config.PriceSources, config.ChainGRPC, retry)
+	} else {
+		oracle, err = NewPriceOracle(config.PriceOracle, config.ChainGRPC, retry)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the price oracle: %w", err)
+	}
+	r.oracle = oracle
+	r.priceSource = oraclePriceSource{oracle: oracle, denom: r.priceDenom}
+	
+	// The simulated venue reads the price while performRebalance holds the lock
+	venue, err := NewSwapVenue(config.SwapVenue, func() float64 { return r.currentPrice })
+	if err != nil {
+		log.Printf("%v, using simulated swap venue", err)
+		venue, _ = NewSwapVenue(SwapVenueConfig{}, func() float64 { return r.currentPrice })
+	}
+	r.swapVenue = venue
+	
+	// A restart continues the state, timers and counters of the last run
+	if config.DataDir != "" {
+		r.statePath = filepath.Join(config.DataDir, RebalancerStateFile)
+		if err := r.LoadState(); err != nil {
+			log.Printf("Failed to restore rebalancer state, starting active: %v", err)
+		}
+	}
+	
+	return r, nil
 }
 
 // Start starts the enhanced rebalancer with proper state management
 func (r *Rebalancer) Start(ctx context.Context) error {
-	log.Printf("Starting enhanced rebalancer with 1-hour intervals")
+	log.Printf("Starting enhanced rebalancer with %s intervals", r.thresholds.RebalanceInterval)
+	if r.IsDryRun() {
+		log.Printf("Rebalancer dry run enabled, rebalances are simulated without swapping")
+	}
 	
 	// Send startup notification
 	if err := r.sendStateChangeAlert("Rebalancer started", StateActive); err != nil {
@@ -127,14 +251,16 @@ func (r *Rebalancer) Start(ctx context.Context) error {
 	go r.dailyResetRoutine(ctx)
 	
 	// Main rebalancing loop
-	ticker := time.NewTicker(RebalanceInterval)
+	ticker := NewJitteredTicker(r.thresholds.RebalanceInterval)
 	defer ticker.Stop()
 	
 	for {
 		select {
 		case <-ctx.Done():
 			log.Printf("Rebalancer stopping due to context cancellation")
-			r.sendStateChangeAlert("Rebalancer stopped", StateError)
+			r.mu.Lock()
+			r.enterStoppedState("Rebalancer stopped: context cancelled")
+			r.mu.Unlock()
 			return ctx.Err()
 		case <-ticker.C:
 			if err := r.processRebalanceCheck(ctx); err != nil {
@@ -147,7 +273,7 @@ func (r *Rebalancer) Start(ctx context.Context) error {
 
 // monitorPrices continuously monitors GXR price with enhanced tracking
 func (r *Rebalancer) monitorPrices(ctx context.Context) {
-	ticker := time.NewTicker(PriceUpdateInterval)
+	ticker := NewJitteredTicker(PriceUpdateInterval)
 	defer ticker.Stop()
 	
 	for {
@@ -155,100 +281,272 @@ func (r *Rebalancer) monitorPrices(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if err := r.updatePrice(ctx); err != nil {
-				log.Printf("Error updating price: %v", err)
-				r.priceUpdateErrors++
-				if r.priceUpdateErrors >= 5 {
-					r.handlePriceError("Too many price update failures")
-				}
-			} else {
-				r.priceUpdateErrors = 0
-			}
+			r.refreshPrice(ctx)
 		}
 	}
 }
 
-// updatePrice updates the current GXR price and checks thresholds
+// refreshPrice runs one price update, moving to the error state after
+// repeated failures
+func (r *Rebalancer) refreshPrice(ctx context.Context) {
+	err := r.updatePrice(ctx)
+	
+	r.mu.Lock()
+	if err != nil {
+		r.priceUpdateErrors++
+	} else {
+		r.priceUpdateErrors = 0
+	}
+	failures := r.priceUpdateErrors
+	r.mu.Unlock()
+	
+	if err != nil {
+		log.Printf("Error updating price: %v", err)
+		if failures > r.maxPriceFailures {
+			r.handlePriceError(r.fallBackToLastGoodPrice(failures))
+		}
+	}
+}
+
+// fallBackToLastGoodPrice reverts the current price to the last one received
+// from the oracle and returns the reason for the price error. Until the
+// oracle recovers the rebalancer stays in the error state.
+func (r *Rebalancer) fallBackToLastGoodPrice(failures int) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	r.priceFallback = true
+	if r.lastGoodPriceAt.IsZero() {
+		return fmt.Sprintf("%d price update failures in a row, no price received yet", failures)
+	}
+	
+	r.currentPrice = r.lastGoodPrice
+	return fmt.Sprintf("%d price update failures in a row, using last known good price $%.4f from %s",
+		failures, r.lastGoodPrice, r.lastGoodPriceAt.Format(time.RFC3339))
+}
+
+// updatePrice updates the current GXR price and checks thresholds. While the
+// price source is unreachable the cached price is served, until it is too
+// old and the failure is returned.
 func (r *Rebalancer) updatePrice(ctx context.Context) error {
+	r.mu.RLock()
+	paused := r.priceMonitoringPaused
+	r.mu.RUnlock()
+	
+	// Keep the last known price while paused so no threshold transitions fire
+	if paused {
+		return nil
+	}
+	
+	newPrice, cached, err := CachedQuery(r.queryCache, QueryCachePrice, func() (float64, error) {
+		return r.priceSource.FetchGXRPrice(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
-	// Simulate price fetching with realistic variation
-	// In production, this would fetch from actual price sources
-	basePrice := 3.0
-	variation := 0.1 * (2.0*math.Sin(float64(time.Now().Unix())/3600) + 1.0)
-	newPrice := basePrice + variation
+	if r.priceMonitoringPaused {
+		return nil
+	}
 	
-	// Add some randomness
-	if time.Now().UnixNano()%7 == 0 {
-		newPrice += 0.5 * (float64(time.Now().UnixNano()%100) / 100.0)
+	r.priceFromCache = cached.Cached
+	// A cached price was recorded when it was fetched; only one fetched
+	// before a restart is new to this run
+	if cached.Cached && !cached.FetchedAt.After(r.lastPriceUpdate) {
+		return nil
 	}
+	r.recordPrice(newPrice, cached.FetchedAt)
 	
+	return nil
+}
+
+// recordPrice stores a new price observation and checks thresholds against the
+// configured threshold source. Callers must hold r.mu.
+func (r *Rebalancer) recordPrice(newPrice float64, now time.Time) {
 	r.currentPrice = newPrice
-	r.lastPriceUpdate = time.Now()
+	r.lastPriceUpdate = now
+	r.lastGoodPrice = newPrice
+	r.lastGoodPriceAt = now
+	r.priceFallback = false
 	
 	// Update price history
-	r.priceHistory = append(r.priceHistory, newPrice)
-	if len(r.priceHistory) > MaxPriceHistory {
-		r.priceHistory = r.priceHistory[1:]
-	}
+	r.priceHistory.Add(PriceSample{Price: newPrice, At: now})
+	
+	// Update moving averages
+	r.ema1h.Update(newPrice, now)
+	r.ema24h.Update(newPrice, now)
 	
 	// Calculate statistics
 	r.calculatePriceStatistics()
 	
+	thresholdPrice := r.thresholdPrice()
+	
+	// The 1h EMA candidate only records the transition it would make
+	r.shadow.Run(ShadowRebalancerEMA1h, r.thresholdDecision(thresholdPrice), func() string {
+		price, _ := r.priceSignals().ThresholdPrice(ThresholdSourceEMA1h)
+		return r.thresholdDecision(price)
+	}, now)
+	
 	// Check for price threshold breach
-	if newPrice >= PriceThreshold && r.state == StateActive {
-		r.enterMonitorOnlyMode(fmt.Sprintf("Price threshold breach: $%.2f >= $%.2f", newPrice, PriceThreshold))
+	if thresholdPrice >= r.thresholds.PriceLimit && r.state == StateActive {
+		r.enterMonitorOnlyMode(fmt.Sprintf("Price threshold breach: $%.2f >= $%.2f", thresholdPrice, r.thresholds.PriceLimit))
 	}
 	
 	// Check for emergency conditions
-	if newPrice >= EmergencyStopThreshold && r.state != StateEmergencyStop {
-		r.enterEmergencyStop(fmt.Sprintf("Emergency price threshold: $%.2f", newPrice))
+	if thresholdPrice >= r.thresholds.EmergencyThreshold && r.state != StateEmergencyStop {
+		r.enterEmergencyStop(fmt.Sprintf("Emergency price threshold: $%.2f", thresholdPrice))
+	}
+}
+
+// thresholdDecision returns the state a threshold price moves the rebalancer
+// to, ShadowDecisionNone if it stays in its state
+func (r *Rebalancer) thresholdDecision(price float64) string {
+	switch {
+	case price >= r.thresholds.EmergencyThreshold && r.state != StateEmergencyStop:
+		return StateEmergencyStop.String()
+	case price >= r.thresholds.PriceLimit && r.state == StateActive:
+		return StateMonitorOnly.String()
+	default:
+		return ShadowDecisionNone
+	}
+}
+
+// PausePriceMonitoring stops price updates and the threshold-driven state
+// transitions they trigger, keeping the last known price
+func (r *Rebalancer) PausePriceMonitoring() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if r.priceMonitoringPaused {
+		return fmt.Errorf("price monitoring already paused since %s", r.priceMonitoringPausedAt.Format(time.RFC3339))
 	}
 	
+	r.priceMonitoringPaused = true
+	r.priceMonitoringPausedAt = time.Now()
+	r.priceUpdateErrors = 0
+	
+	log.Printf("Price monitoring paused at $%.2f (state %s)", r.currentPrice, r.state)
+	r.sendPriceMonitoringAlert(fmt.Sprintf("⏸️ Price Monitoring Paused\n\nLast known price: $%.2f\nState: %s\nThreshold transitions are suspended until resumed.",
+		r.currentPrice, r.state))
 	return nil
 }
 
-// calculatePriceStatistics calculates average price and volatility
-func (r *Rebalancer) calculatePriceStatistics() {
-	if len(r.priceHistory) == 0 {
-		return
+// ResumePriceMonitoring restarts price updates after PausePriceMonitoring
+func (r *Rebalancer) ResumePriceMonitoring() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if !r.priceMonitoringPaused {
+		return fmt.Errorf("price monitoring is not paused")
 	}
 	
-	// Calculate average
-	sum := 0.0
-	for _, price := range r.priceHistory {
-		sum += price
+	pausedFor := time.Since(r.priceMonitoringPausedAt).Round(time.Second)
+	r.priceMonitoringPaused = false
+	r.priceMonitoringPausedAt = time.Time{}
+	
+	log.Printf("Price monitoring resumed after %s", pausedFor)
+	r.sendPriceMonitoringAlert(fmt.Sprintf("▶️ Price Monitoring Resumed\n\nPaused for: %s\nLast known price: $%.2f\nState: %s",
+		pausedFor, r.currentPrice, r.state))
+	return nil
+}
+
+// IsPriceMonitoringPaused reports whether price monitoring is paused
+func (r *Rebalancer) IsPriceMonitoringPaused() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.priceMonitoringPaused
+}
+
+// sendPriceMonitoringAlert sends a pause/resume alert. Unlike state change
+// alerts it is not rate limited, so a quick pause/resume is always reported.
+func (r *Rebalancer) sendPriceMonitoringAlert(message string) {
+	if r.telegramAlert == nil {
+		return
+	}
+	if err := r.telegramAlert.SendAlert(message); err != nil {
+		log.Printf("Failed to send price monitoring alert: %v", err)
+	}
+}
+
+// priceSignals returns the current spot price, moving averages and trend
+func (r *Rebalancer) priceSignals() PriceSignals {
+	return NewPriceSignals(r.currentPrice, r.ema1h, r.ema24h)
+}
+
+// thresholdPrice returns the price compared against thresholds. While the
+// configured EMA is still warming up the spot price is used instead.
+func (r *Rebalancer) thresholdPrice() float64 {
+	price, used := r.priceSignals().ThresholdPrice(r.thresholdSource)
+	if used != r.thresholdSource && !r.warmupNoticeLogged {
+		log.Printf("Threshold source %s is warming up, falling back to spot price", r.thresholdSource)
+		r.warmupNoticeLogged = true
 	}
-	r.averagePrice = sum / float64(len(r.priceHistory))
 	
-	// Calculate volatility (standard deviation)
-	varianceSum := 0.0
-	for _, price := range r.priceHistory {
-		diff := price - r.averagePrice
-		varianceSum += diff * diff
+	return price
+}
+
+// PriceSignals returns the current spot price, moving averages and trend
+func (r *Rebalancer) PriceSignals() PriceSignals {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.priceSignals()
+}
+
+// calculatePriceStatistics calculates average price and volatility
+func (r *Rebalancer) calculatePriceStatistics() {
+	if r.priceHistory.Len() == 0 {
+		return
 	}
-	r.priceVolatility = math.Sqrt(varianceSum / float64(len(r.priceHistory)))
+	r.averagePrice, r.priceVolatility = r.priceHistory.Statistics()
+}
+
+// GetPriceHistory returns a copy of the sampled prices, oldest first
+func (r *Rebalancer) GetPriceHistory() []PriceSample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.priceHistory.Samples()
 }
 
-// processRebalanceCheck processes the hourly rebalance check
+// PriceHistoryCapacity returns the number of samples the price history keeps
+func (r *Rebalancer) PriceHistoryCapacity() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.priceHistory.Cap()
+}
+
+// PriceStatistics returns the average and volatility of the price history
+func (r *Rebalancer) PriceStatistics() (average, volatility float64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.averagePrice, r.priceVolatility
+}
+
+// processRebalanceCheck processes the periodic rebalance check
 func (r *Rebalancer) processRebalanceCheck(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
 	now := time.Now()
 	
-	// Check if it's time to rebalance (exactly 1 hour)
+	// Check if it's time to rebalance
 	if now.Before(r.nextRebalanceTime) {
 		return nil // Not time yet
 	}
 	
 	// Update next rebalance time
-	r.nextRebalanceTime = now.Add(RebalanceInterval)
+	r.nextRebalanceTime = now.Add(r.thresholds.RebalanceInterval)
 	
 	// Check current state
 	switch r.state {
 	case StateActive:
+		// The initial price is a placeholder until the oracle answers
+		if r.lastGoodPriceAt.IsZero() {
+			log.Printf("No price received yet, skipping rebalance")
+			return nil
+		}
 		return r.performRebalance(ctx)
 	case StateMonitorOnly:
 		return r.handleMonitorOnlyMode(ctx)
@@ -256,6 +554,8 @@ func (r *Rebalancer) processRebalanceCheck(ctx context.Context) error {
 		return r.handleEmergencyStop(ctx)
 	case StateError:
 		return r.handleErrorState(ctx)
+	case StateStopped:
+		return nil
 	default:
 		return fmt.Errorf("unknown rebalancer state: %v", r.state)
 	}
@@ -263,28 +563,36 @@ func (r *Rebalancer) processRebalanceCheck(ctx context.Context) error {
 
 // performRebalance performs the actual rebalancing when in active state
 func (r *Rebalancer) performRebalance(ctx context.Context) error {
-	log.Printf("Performing hourly rebalance - Price: $%.2f", r.currentPrice)
+	log.Printf("Performing rebalance - Price: $%.2f", r.currentPrice)
 	
 	// Check if we're still in acceptable price range
-	if r.currentPrice >= PriceThreshold {
-		return r.enterMonitorOnlyMode(fmt.Sprintf("Price threshold reached during rebalance: $%.2f", r.currentPrice))
+	if price := r.thresholdPrice(); price >= r.thresholds.PriceLimit {
+		return r.enterMonitorOnlyMode(fmt.Sprintf("Price threshold reached during rebalance: $%.2f", price))
 	}
 	
 	// Perform rebalancing logic
 	rebalanceVolume := r.calculateRebalanceVolume()
 	
 	// Execute rebalance
-	if err := r.executeRebalance(ctx, rebalanceVolume); err != nil {
+	result, err := r.executeRebalance(ctx, rebalanceVolume)
+	if err != nil {
 		return fmt.Errorf("rebalance execution failed: %w", err)
 	}
 	
-	// Update statistics
+	// Simulated rebalances leave the executed statistics alone
+	if r.dryRun || result.Simulated {
+		return nil
+	}
+	
+	// Update statistics; swaps failing on some pools leave part of the
+	// volume unexecuted
 	r.lastRebalance = time.Now()
 	r.rebalanceCount++
 	r.dailyRebalanceCount++
-	r.totalRebalanceVolume += rebalanceVolume
+	r.totalRebalanceVolume += result.AmountIn
+	r.persistState()
 	
-	log.Printf("Rebalance completed - Volume: %.2f GXR, Total: %d", rebalanceVolume, r.rebalanceCount)
+	log.Printf("Rebalance completed - Volume: %.2f GXR, Total: %d", result.AmountIn, r.rebalanceCount)
 	
 	return nil
 }
@@ -298,36 +606,220 @@ func (r *Rebalancer) calculateRebalanceVolume() float64 {
 	return baseVolume * volatilityMultiplier
 }
 
-// executeRebalance executes the actual rebalancing operation
-func (r *Rebalancer) executeRebalance(ctx context.Context, volume float64) error {
-	// Simulate rebalancing - in production this would interact with DEX
-	log.Printf("Executing rebalance of %.2f GXR", volume)
+// executeRebalance quotes and executes the rebalance swap on the configured
+// venue and records the slippage of the executed price against the quote.
+// In dry-run mode the rebalance is only simulated.
+func (r *Rebalancer) executeRebalance(ctx context.Context, volume float64) (SwapResult, error) {
+	if r.dryRun {
+		r.simulateRebalance(ctx, volume)
+		return SwapResult{}, nil
+	}
+	if pools := r.swapPools(); len(pools) > 0 {
+		return r.executePoolSwaps(ctx, volume, pools)
+	}
+	if r.swaps != nil && len(r.config.DEXPools) > 0 {
+		return SwapResult{}, fmt.Errorf("none of the dex_pools %s is active", strings.Join(r.config.DEXPools, ", "))
+	}
+	
+	log.Printf("Executing rebalance of %.2f GXR on %s", volume, r.swapVenue.Name())
+	
+	quote, err := r.swapVenue.Quote(ctx, volume)
+	if err != nil {
+		return SwapResult{}, fmt.Errorf("quote on %s failed: %w", r.swapVenue.Name(), err)
+	}
+	
+	result, err := r.swapVenue.ExecuteSwap(ctx, quote)
+	if err != nil {
+		return SwapResult{}, fmt.Errorf("swap on %s failed: %w", r.swapVenue.Name(), err)
+	}
+	
+	r.recordSwap(quote, result)
+	return result, nil
+}
+
+// swapPools returns the active pools of dex_pools, in configured order
+func (r *Rebalancer) swapPools() []string {
+	if r.swaps == nil || len(r.config.DEXPools) == 0 {
+		return nil
+	}
+	
+	active := r.swaps.ActivePools()
+	var pools []string
+	for _, pool := range r.config.DEXPools {
+		if containsString(active, pool) {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
+}
+
+// executePoolSwaps splits volume evenly across pools and sells it on each.
+// A failing pool does not stop the swaps on the others; the rebalance fails
+// only when every pool failed. The result holds the volume executed. Swaps
+// that were only simulated are not executed volume: when no pool swapped for
+// real the rebalance is counted as simulated, like one in dry-run mode.
+func (r *Rebalancer) executePoolSwaps(ctx context.Context, volume float64, pools []string) (SwapResult, error) {
+	share := volume / float64(len(pools))
+	log.Printf("Executing rebalance of %.2f GXR on %d DEX pools (%.2f GXR each)", volume, len(pools), share)
+	
+	swaps := make([]PoolSwap, 0, len(pools))
+	var executed, simulated float64
+	var failures []string
+	for _, pool := range pools {
+		swap := PoolSwap{Pool: pool, AmountGXR: share}
+		result, err := r.swaps.ExecuteSwap(ctx, pool, share, SwapSellGXR)
+		swap.At = time.Now()
+		switch {
+		case err != nil:
+			swap.Error = err.Error()
+			failures = append(failures, fmt.Sprintf("%s: %v", pool, err))
+			r.poolSwapFailures++
+		case result.Simulated:
+			swap.Simulated = true
+			simulated += share
+		default:
+			swap.TxHash = result.TxHash
+			executed += share
+		}
+		swaps = append(swaps, swap)
+	}
+	r.lastPoolSwaps = swaps
+	
+	if executed == 0 && simulated > 0 {
+		r.simulatedCount++
+		r.simulatedVolume += simulated
+		r.lastSimulated = time.Now()
+		log.Printf("DEX pool swaps are simulated, %.2f GXR was not swapped - Simulated: %d", simulated, r.simulatedCount)
+		return SwapResult{Venue: "dex_pools", ExecutedAt: time.Now(), Simulated: true}, nil
+	}
+	if executed == 0 {
+		return SwapResult{}, fmt.Errorf("swaps failed on all %d pools: %s", len(pools), strings.Join(failures, "; "))
+	}
+	if len(failures) > 0 {
+		log.Printf("Rebalance swapped %.2f of %.2f GXR, failed on %s", executed, volume, strings.Join(failures, "; "))
+	}
+	return SwapResult{Venue: "dex_pools", AmountIn: executed, ExecutedAt: time.Now()}, nil
+}
+
+// simulateRebalance records and reports the rebalance a dry run would have
+// executed. The quote only adds the expected price, so a failing quote does
+// not stop the simulation.
+func (r *Rebalancer) simulateRebalance(ctx context.Context, volume float64) {
+	expected := "unavailable"
+	if quote, err := r.swapVenue.Quote(ctx, volume); err != nil {
+		log.Printf("Dry run quote on %s failed: %v", r.swapVenue.Name(), err)
+	} else {
+		expected = fmt.Sprintf("$%.4f", quote.Price)
+	}
 	
-	// Simulate processing time
-	time.Sleep(100 * time.Millisecond)
+	r.simulatedCount++
+	r.simulatedVolume += volume
+	r.lastSimulated = time.Now()
 	
-	// Simulate potential errors
-	if time.Now().UnixNano()%100 == 0 {
-		return fmt.Errorf("simulated rebalance error")
+	log.Printf("Dry run: would rebalance %.2f GXR on %s at %s - Simulated: %d, %.2f GXR total",
+		volume, r.swapVenue.Name(), expected, r.simulatedCount, r.simulatedVolume)
+	r.sendDryRunAlert(fmt.Sprintf("🧪 Simulated Rebalance\n\nNo swap was executed (dry run).\nVolume: %.2f GXR\nVenue: %s\nExpected price: %s\nCurrent price: $%.2f\nSimulated rebalances: %d",
+		volume, r.swapVenue.Name(), expected, r.currentPrice, r.simulatedCount))
+}
+
+// SetDryRun switches dry-run mode at runtime. Switching to the current mode
+// is an error, like pausing twice.
+func (r *Rebalancer) SetDryRun(enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if r.dryRun == enabled {
+		if enabled {
+			return fmt.Errorf("rebalancer dry run already enabled")
+		}
+		return fmt.Errorf("rebalancer dry run is not enabled")
 	}
+	r.dryRun = enabled
 	
+	if enabled {
+		log.Printf("Rebalancer dry run enabled, rebalances are simulated without swapping")
+		r.sendDryRunAlert("🧪 Rebalancer Dry Run Enabled\n\nRebalances are simulated and reported, no swaps are executed.")
+	} else {
+		log.Printf("Rebalancer dry run disabled after %d simulated rebalances", r.simulatedCount)
+		r.sendDryRunAlert(fmt.Sprintf("▶️ Rebalancer Dry Run Disabled\n\nSwaps are executed again.\nSimulated rebalances: %d", r.simulatedCount))
+	}
 	return nil
 }
 
+// IsDryRun reports whether rebalances are only simulated
+func (r *Rebalancer) IsDryRun() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.dryRun
+}
+
+// sendDryRunAlert sends an informational dry-run alert
+func (r *Rebalancer) sendDryRunAlert(message string) {
+	if r.telegramAlert == nil {
+		return
+	}
+	if err := r.telegramAlert.SendAlertWithType(AlertTypeInfo, "Rebalancer Dry Run", message); err != nil {
+		log.Printf("Failed to send dry run alert: %v", err)
+	}
+}
+
+// recordSwap updates the slippage statistics with an executed swap and
+// alerts when the slippage exceeds the configured maximum
+func (r *Rebalancer) recordSwap(quote SwapQuote, result SwapResult) {
+	slippage := quote.Slippage(result)
+	
+	r.lastQuote = quote
+	r.lastSwap = result
+	r.lastSlippage = slippage
+	r.totalSlippage += slippage
+	r.swapCount++
+	if r.swapCount == 1 || slippage > r.worstSlippage {
+		r.worstSlippage = slippage
+	}
+	
+	log.Printf("Swap executed on %s - Expected: $%.4f, Executed: $%.4f, Slippage: %.2f%%",
+		result.Venue, quote.Price, result.Price, slippage*100)
+	
+	if slippage > r.maxSlippage {
+		r.slippageBreaches++
+		r.sendSlippageAlert(fmt.Sprintf("⚠️ Rebalance Slippage\n\nVenue: %s\nExpected price: $%.4f\nExecuted price: $%.4f\nSlippage: %.2f%% (max %.2f%%)",
+			result.Venue, quote.Price, result.Price, slippage*100, r.maxSlippage*100))
+	}
+}
+
+// sendSlippageAlert reports a swap that exceeded the slippage limit
+func (r *Rebalancer) sendSlippageAlert(message string) {
+	if r.telegramAlert == nil {
+		return
+	}
+	if err := r.telegramAlert.SendAlert(message); err != nil {
+		log.Printf("Failed to send slippage alert: %v", err)
+	}
+}
+
+// averageSlippage returns the mean slippage of all executed swaps
+func (r *Rebalancer) averageSlippage() float64 {
+	if r.swapCount == 0 {
+		return 0
+	}
+	return r.totalSlippage / float64(r.swapCount)
+}
+
 // handleMonitorOnlyMode handles the bot when in monitor-only mode
 func (r *Rebalancer) handleMonitorOnlyMode(ctx context.Context) error {
 	elapsed := time.Since(r.monitorOnlyStart)
 	
 	log.Printf("Monitor-only mode - Elapsed: %v, Price: $%.2f", elapsed, r.currentPrice)
 	
-	// Check if 24 hours have passed
-	if elapsed >= MonitorOnlyDuration {
+	// Check if the monitor-only period has passed
+	if elapsed >= r.thresholds.MonitorOnlyDuration {
 		// Check if price is back below threshold
-		if r.currentPrice < PriceThreshold {
-			return r.exitMonitorOnlyMode("24-hour period elapsed and price below threshold")
+		if r.thresholdPrice() < r.thresholds.PriceLimit {
+			return r.exitMonitorOnlyMode(fmt.Sprintf("%s period elapsed and price below threshold", r.thresholds.MonitorOnlyDuration))
 		} else {
 			// Extend monitor-only period
 			r.monitorOnlyStart = time.Now()
+			r.persistState()
 			r.sendStateChangeAlert(fmt.Sprintf("Monitor-only mode extended - Price: $%.2f", r.currentPrice), StateMonitorOnly)
 		}
 	}
@@ -340,7 +832,7 @@ func (r *Rebalancer) handleEmergencyStop(ctx context.Context) error {
 	log.Printf("Emergency stop active - Price: $%.2f", r.currentPrice)
 	
 	// Check if conditions have normalized
-	if r.currentPrice < PriceThreshold {
+	if r.thresholdPrice() < r.thresholds.PriceLimit {
 		return r.exitEmergencyStop("Price returned to normal levels")
 	}
 	
@@ -351,6 +843,11 @@ func (r *Rebalancer) handleEmergencyStop(ctx context.Context) error {
 func (r *Rebalancer) handleErrorState(ctx context.Context) error {
 	log.Printf("Error state active - attempting recovery")
 	
+	// Stale prices are no basis for trading
+	if r.priceFallback {
+		return nil
+	}
+	
 	// Simple recovery logic - reset to active after 1 hour
 	if time.Since(r.stateChangeTime) >= time.Hour {
 		return r.recoverFromError("Auto-recovery after 1 hour")
@@ -370,6 +867,8 @@ func (r *Rebalancer) enterMonitorOnlyMode(reason string) error {
 	r.monitorOnlyReason = reason
 	r.priceBreachTime = time.Now()
 	
+	r.persistState()
+	
 	log.Printf("Entering monitor-only mode: %s", reason)
 	return r.sendStateChangeAlert(reason, StateMonitorOnly)
 }
@@ -380,6 +879,8 @@ func (r *Rebalancer) exitMonitorOnlyMode(reason string) error {
 	r.stateChangeTime = time.Now()
 	r.stateChangeReason = reason
 	
+	r.persistState()
+	
 	log.Printf("Exiting monitor-only mode: %s", reason)
 	return r.sendStateChangeAlert(reason, StateActive)
 }
@@ -392,6 +893,8 @@ func (r *Rebalancer) enterEmergencyStop(reason string) error {
 	r.emergencyReason = reason
 	r.emergencyStartTime = time.Now()
 	
+	r.persistState()
+	
 	log.Printf("EMERGENCY STOP: %s", reason)
 	return r.sendStateChangeAlert(fmt.Sprintf("EMERGENCY: %s", reason), StateEmergencyStop)
 }
@@ -402,6 +905,8 @@ func (r *Rebalancer) exitEmergencyStop(reason string) error {
 	r.stateChangeTime = time.Now()
 	r.stateChangeReason = reason
 	
+	r.persistState()
+	
 	log.Printf("Exiting emergency stop: %s", reason)
 	return r.sendStateChangeAlert(fmt.Sprintf("Recovery: %s", reason), StateActive)
 }
@@ -412,6 +917,8 @@ func (r *Rebalancer) recoverFromError(reason string) error {
 	r.stateChangeTime = time.Now()
 	r.stateChangeReason = reason
 	
+	r.persistState()
+	
 	log.Printf("Recovering from error: %s", reason)
 	return r.sendStateChangeAlert(fmt.Sprintf("Recovery: %s", reason), StateActive)
 }
@@ -425,6 +932,8 @@ func (r *Rebalancer) handleError(err error) {
 	r.stateChangeTime = time.Now()
 	r.stateChangeReason = err.Error()
 	
+	r.persistState()
+	
 	log.Printf("Rebalancer error: %v", err)
 	r.sendStateChangeAlert(fmt.Sprintf("Error: %v", err), StateError)
 }
@@ -438,29 +947,57 @@ func (r *Rebalancer) handlePriceError(reason string) {
 	r.stateChangeTime = time.Now()
 	r.stateChangeReason = reason
 	
+	r.persistState()
+	
 	log.Printf("Price error: %s", reason)
 	r.sendStateChangeAlert(fmt.Sprintf("Price Error: %s", reason), StateError)
 }
 
+// enterStoppedState moves to StateStopped on a clean shutdown, alerting only
+// the first time. The state before stopping is persisted first. Callers must
+// hold r.mu.
+func (r *Rebalancer) enterStoppedState(reason string) {
+	if r.state == StateStopped {
+		return
+	}
+	r.persistState()
+	
+	r.state = StateStopped
+	r.stateChangeTime = time.Now()
+	r.stateChangeReason = reason
+	
+	log.Printf("%s", reason)
+	r.sendStateChangeAlert(reason, StateStopped)
+}
+
 // sendStateChangeAlert sends telegram alert for state changes
 func (r *Rebalancer) sendStateChangeAlert(message string, newState RebalanceState) error {
 	if r.telegramAlert == nil {
 		return nil
 	}
 	
-	// Rate limiting - don't send alerts too frequently
-	if time.Since(r.lastAlertTime) < 5*time.Minute {
+	// Rate limiting - don't send alerts too frequently. The stop alert is
+	// sent once, so a shutdown soon after a state change is still reported.
+	if newState != StateStopped && time.Since(r.lastAlertTime) < 5*time.Minute {
 		return nil
 	}
 	
-	fullMessage := fmt.Sprintf("🔄 Rebalancer State Change\n\nState: %s\nReason: %s\nPrice: $%.2f\nTime: %s",
+	fullMessage := fmt.Sprintf("🔄 Rebalancer State Change\n\nState: %s\nReason: %s\nPrice: $%.2f\n%s\nThreshold source: %s\nTime: %s",
 		newState.String(),
 		message,
 		r.currentPrice,
+		r.priceSignals().Summary(),
+		r.thresholdSource,
 		time.Now().Format("2006-01-02 15:04:05"),
 	)
 	
-	if err := r.telegramAlert.SendAlert(fullMessage); err != nil {
+	// Error and emergency states are failures, which incident correlation groups
+	alertType := AlertTypeInfo
+	if newState == StateError || newState == StateEmergencyStop {
+		alertType = AlertTypeError
+	}
+	
+	if err := r.telegramAlert.SendAlertWithType(alertType, "Alert", fullMessage); err != nil {
 		log.Printf("Failed to send state change alert: %v", err)
 		return err
 	}
@@ -471,7 +1008,7 @@ func (r *Rebalancer) sendStateChangeAlert(message string, newState RebalanceStat
 
 // dailyResetRoutine resets daily counters
 func (r *Rebalancer) dailyResetRoutine(ctx context.Context) {
-	ticker := time.NewTicker(24 * time.Hour)
+	ticker := NewJitteredTicker(24 * time.Hour)
 	defer ticker.Stop()
 	
 	for {
@@ -483,6 +1020,7 @@ func (r *Rebalancer) dailyResetRoutine(ctx context.Context) {
 			log.Printf("Daily reset - Rebalances today: %d", r.dailyRebalanceCount)
 			r.dailyRebalanceCount = 0
 			r.lastDailyReset = time.Now()
+			r.persistState()
 			r.mu.Unlock()
 		}
 	}
@@ -493,25 +1031,56 @@ func (r *Rebalancer) GetStatus() map[string]interface{} {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	
-	return map[string]interface{}{
-		"state":                 r.state.String(),
-		"state_change_time":     r.stateChangeTime.Format(time.RFC3339),
-		"state_change_reason":   r.stateChangeReason,
-		"current_price":         r.currentPrice,
-		"last_price_update":     r.lastPriceUpdate.Format(time.RFC3339),
-		"price_history_count":   len(r.priceHistory),
-		"average_price":         r.averagePrice,
-		"price_volatility":      r.priceVolatility,
-		"last_rebalance":        r.lastRebalance.Format(time.RFC3339),
-		"next_rebalance":        r.nextRebalanceTime.Format(time.RFC3339),
-		"rebalance_count":       r.rebalanceCount,
-		"daily_rebalance_count": r.dailyRebalanceCount,
-		"total_volume":          r.totalRebalanceVolume,
-		"monitor_only_start":    r.monitorOnlyStart.Format(time.RFC3339),
-		"monitor_only_reason":   r.monitorOnlyReason,
-		"emergency_reason":      r.emergencyReason,
-		"emergency_start":       r.emergencyStartTime.Format(time.RFC3339),
+	status := map[string]interface{}{
+		"state":                     r.state.String(),
+		"state_change_time":         r.stateChangeTime.Format(time.RFC3339),
+		"state_change_reason":       r.stateChangeReason,
+		"current_price":             r.currentPrice,
+		"last_price_update":         r.lastPriceUpdate.Format(time.RFC3339),
+		"price_from_cache":          r.priceFromCache,
+		"price_fallback":            r.priceFallback,
+		"last_good_price_at":        r.lastGoodPriceAt.Format(time.RFC3339),
+		"price_history_count":       r.priceHistory.Len(),
+		"price_history_size":        r.priceHistory.Cap(),
+		"average_price":             r.averagePrice,
+		"price_volatility":          r.priceVolatility,
+		"price_signals":             r.priceSignals(),
+		"threshold_source":          string(r.thresholdSource),
+		"price_limit":               r.thresholds.PriceLimit,
+		"emergency_threshold":       r.thresholds.EmergencyThreshold,
+		"monitor_only_duration":     r.thresholds.MonitorOnlyDuration.String(),
+		"rebalance_interval":        r.thresholds.RebalanceInterval.String(),
+		"last_rebalance":            r.lastRebalance.Format(time.RFC3339),
+		"next_rebalance":            r.nextRebalanceTime.Format(time.RFC3339),
+		"rebalance_count":           r.rebalanceCount,
+		"daily_rebalance_count":     r.dailyRebalanceCount,
+		"total_volume":              r.totalRebalanceVolume,
+		"dry_run":                   r.dryRun,
+		"simulated_rebalance_count": r.simulatedCount,
+		"simulated_volume":          r.simulatedVolume,
+		"last_simulated_rebalance":  r.lastSimulated.Format(time.RFC3339),
+		"monitor_only_start":        r.monitorOnlyStart.Format(time.RFC3339),
+		"monitor_only_reason":       r.monitorOnlyReason,
+		"emergency_reason":          r.emergencyReason,
+		"emergency_start":           r.emergencyStartTime.Format(time.RFC3339),
+		"price_monitor_paused":      r.priceMonitoringPaused,
+		"price_paused_since":        r.priceMonitoringPausedAt.Format(time.RFC3339),
+		"swap_venue":                r.swapVenue.Name(),
+		"swap_count":                r.swapCount,
+		"last_expected_price":       r.lastQuote.Price,
+		"last_executed_price":       r.lastSwap.Price,
+		"last_slippage":             r.lastSlippage,
+		"average_slippage":          r.averageSlippage(),
+		"worst_slippage":            r.worstSlippage,
+		"slippage_breaches":         r.slippageBreaches,
+		"dex_pools":                 r.config.DEXPools,
+		"last_pool_swaps":           r.lastPoolSwaps,
+		"pool_swap_failures":        r.poolSwapFailures,
+	}
+	if sources, ok := r.oracle.(*MedianPriceOracle); ok {
+		status["price_sources"] = sources.SourceStatus()
 	}
+	return status
 }
 
 // Stop gracefully stops the rebalancer
@@ -519,8 +1088,19 @@ func (r *Rebalancer) Stop() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
-	log.Printf("Stopping rebalancer - Final stats: %d rebalances, $%.2f total volume", 
-		r.rebalanceCount, r.totalRebalanceVolume)
+	if r.stopped {
+		return
+	}
+	r.stopped = true
+	
+	log.Printf("Stopping rebalancer - Final stats: %d rebalances, $%.2f total volume, %d simulated", 
+		r.rebalanceCount, r.totalRebalanceVolume, r.simulatedCount)
 	
-	r.sendStateChangeAlert("Rebalancer stopped", StateError)
+	r.enterStoppedState("Rebalancer stopped")
+	
+	if closer, ok := r.oracle.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("Failed to close price oracle: %v", err)
+		}
+	}
 }
\ No newline at end of file
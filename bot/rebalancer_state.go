@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RebalancerStateFile is the persisted rebalancer state in the data directory
+const RebalancerStateFile = "rebalancer_state.json"
+
+// rebalancerSnapshot is the rebalancer state that outlives a restart: the
+// state machine with its timers, and the rebalance counters the cooldowns
+// and daily limit are based on
+type rebalancerSnapshot struct {
+	State               string    `json:"state"`
+	StateChangeTime     time.Time `json:"state_change_time"`
+	StateChangeReason   string    `json:"state_change_reason"`
+	MonitorOnlyStart    time.Time `json:"monitor_only_start"`
+	MonitorOnlyReason   string    `json:"monitor_only_reason"`
+	PriceBreachTime     time.Time `json:"price_breach_time"`
+	EmergencyReason     string    `json:"emergency_reason"`
+	EmergencyStartTime  time.Time `json:"emergency_start_time"`
+	LastRebalance       time.Time `json:"last_rebalance"`
+	NextRebalanceTime   time.Time `json:"next_rebalance_time"`
+	RebalanceCount      int64     `json:"rebalance_count"`
+	DailyRebalanceCount int       `json:"daily_rebalance_count"`
+	LastDailyReset      time.Time `json:"last_daily_reset"`
+	TotalVolume         float64   `json:"total_volume"`
+	SavedAt             time.Time `json:"saved_at"`
+}
+
+// ParseRebalanceState parses the name of a rebalancer state
+func ParseRebalanceState(name string) (RebalanceState, error) {
+	for _, state := range rebalancerStates {
+		if state.String() == name {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown rebalancer state %q", name)
+}
+
+// SaveState persists the rebalancer state to data_dir. Without a data_dir
+// the state is kept in memory only.
+func (r *Rebalancer) SaveState() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saveStateLocked()
+}
+
+// saveStateLocked atomically writes the state file. Callers must hold r.mu.
+func (r *Rebalancer) saveStateLocked() error {
+	// The file keeps the state the rebalancer stopped in
+	if r.statePath == "" || r.state == StateStopped {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(rebalancerSnapshot{
+		State:               r.state.String(),
+		StateChangeTime:     r.stateChangeTime,
+		StateChangeReason:   r.stateChangeReason,
+		MonitorOnlyStart:    r.monitorOnlyStart,
+		MonitorOnlyReason:   r.monitorOnlyReason,
+		PriceBreachTime:     r.priceBreachTime,
+		EmergencyReason:     r.emergencyReason,
+		EmergencyStartTime:  r.emergencyStartTime,
+		LastRebalance:       r.lastRebalance,
+		NextRebalanceTime:   r.nextRebalanceTime,
+		RebalanceCount:      r.rebalanceCount,
+		DailyRebalanceCount: r.dailyRebalanceCount,
+		LastDailyReset:      r.lastDailyReset,
+		TotalVolume:         r.totalRebalanceVolume,
+		SavedAt:             time.Now(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.statePath), 0o755); err != nil {
+		return err
+	}
+
+	tmp := r.statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.statePath)
+}
+
+// LoadState restores the state persisted by a previous run. The monitor-only
+// and emergency timers keep running from when they started, so a restart
+// does not cut them short. A missing state file is not an error.
+func (r *Rebalancer) LoadState() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.statePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(r.statePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snapshot rebalancerSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("invalid rebalancer state %s: %w", r.statePath, err)
+	}
+	state, err := ParseRebalanceState(snapshot.State)
+	if err != nil {
+		return err
+	}
+
+	r.state = state
+	r.stateChangeTime = snapshot.StateChangeTime
+	r.stateChangeReason = snapshot.StateChangeReason
+	r.monitorOnlyStart = snapshot.MonitorOnlyStart
+	r.monitorOnlyReason = snapshot.MonitorOnlyReason
+	r.priceBreachTime = snapshot.PriceBreachTime
+	r.emergencyReason = snapshot.EmergencyReason
+	r.emergencyStartTime = snapshot.EmergencyStartTime
+	r.lastRebalance = snapshot.LastRebalance
+	r.nextRebalanceTime = snapshot.NextRebalanceTime
+	r.rebalanceCount = snapshot.RebalanceCount
+	r.dailyRebalanceCount = snapshot.DailyRebalanceCount
+	r.lastDailyReset = snapshot.LastDailyReset
+	r.totalRebalanceVolume = snapshot.TotalVolume
+
+	// The daily counter of a day that ended while the bot was down
+	if time.Since(r.lastDailyReset) >= 24*time.Hour {
+		r.dailyRebalanceCount = 0
+		r.lastDailyReset = time.Now()
+	}
+
+	log.Printf("Restored rebalancer state %s (since %s, saved %s)",
+		r.state, r.stateChangeTime.Format(time.RFC3339), snapshot.SavedAt.Format(time.RFC3339))
+	return nil
+}
+
+// persistState saves the state after a transition or rebalance, logging
+// failures. Callers must hold r.mu.
+func (r *Rebalancer) persistState() {
+	if err := r.saveStateLocked(); err != nil {
+		log.Printf("Failed to persist rebalancer state: %v", err)
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestRebalancer creates a rebalancer of config, on the mock price oracle
+// unless config selects one
+func newTestRebalancer(t testing.TB, config *BotConfig, swaps SwapExecutor) *Rebalancer {
+	t.Helper()
+	if config.PriceOracle.Source == "" && len(config.PriceSources) == 0 {
+		config.PriceOracle.Source = PriceOracleMock
+	}
+	r, err := NewRebalancer(config, swaps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRebalancerPausePriceMonitoring(t *testing.T) {
+	r := newTestRebalancer(t, &BotConfig{}, nil)
+	r.telegramAlert = nil
+	ctx := context.Background()
+
+	if err := r.ResumePriceMonitoring(); err == nil {
+		t.Fatal("resume without pause should fail")
+	}
+
+	r.recordPrice(3.0, time.Now())
+	lastUpdate := r.lastPriceUpdate
+	if err := r.PausePriceMonitoring(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.PausePriceMonitoring(); err == nil {
+		t.Fatal("second pause should fail")
+	}
+
+	// Updates while paused keep the last known price and the state
+	for i := 0; i < 3; i++ {
+		if err := r.updatePrice(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.currentPrice != 3.0 || !r.lastPriceUpdate.Equal(lastUpdate) || r.priceHistory.Len() != 1 {
+		t.Fatalf("paused monitor recorded a price: $%.2f, %d history entries", r.currentPrice, r.priceHistory.Len())
+	}
+	if r.state != StateActive {
+		t.Fatalf("state changed while paused: %s", r.state)
+	}
+	if status := r.GetStatus(); status["price_monitor_paused"] != true {
+		t.Fatalf("status does not report the pause: %v", status["price_monitor_paused"])
+	}
+
+	if err := r.ResumePriceMonitoring(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.updatePrice(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if r.priceHistory.Len() != 2 || r.IsPriceMonitoringPaused() {
+		t.Fatalf("price updates did not resume: %d history entries", r.priceHistory.Len())
+	}
+}
+
+func TestRebalancerThresholdsFromConfig(t *testing.T) {
+	// Configs without the settings keep the former constants
+	for _, config := range []*BotConfig{{}, {PriceLimit: DefaultPriceLimit}} {
+		got, err := ParseRebalancerThresholds(config)
+		want := RebalancerThresholds{PriceLimit: 5.0, EmergencyThreshold: 5.0, MonitorOnlyDuration: 24 * time.Hour, RebalanceInterval: time.Hour}
+		if err != nil || got != want {
+			t.Fatalf("thresholds of %+v = %+v, %v", config, got, err)
+		}
+	}
+
+	for _, config := range []*BotConfig{
+		{PriceLimit: "free"},
+		{PriceLimit: "-1"},
+		{PriceLimit: "8", EmergencyThreshold: "8"},
+		{EmergencyThreshold: "4"},
+		{RebalanceInterval: 5 * time.Minute},
+		{MonitorOnlyDuration: -time.Hour},
+	} {
+		if _, err := ParseRebalancerThresholds(config); err == nil {
+			t.Fatalf("accepted %+v", config)
+		}
+	}
+
+	r := newTestRebalancer(t, &BotConfig{PriceLimit: "8", EmergencyThreshold: "12", MonitorOnlyDuration: 2 * time.Hour, RebalanceInterval: 10 * time.Minute}, nil)
+	r.telegramAlert = nil
+	if next := time.Until(r.nextRebalanceTime); next > 10*time.Minute {
+		t.Fatalf("next rebalance in %s", next)
+	}
+
+	r.recordPrice(6, time.Now())
+	if r.state != StateActive {
+		t.Fatalf("state %s below the configured limit", r.state)
+	}
+	r.recordPrice(9, time.Now())
+	if r.state != StateMonitorOnly {
+		t.Fatalf("state %s above the configured limit", r.state)
+	}
+
+	// Monitor-only ends after the configured duration once the price is back
+	r.recordPrice(7, time.Now())
+	r.monitorOnlyStart = time.Now().Add(-2 * time.Hour)
+	r.handleMonitorOnlyMode(context.Background())
+	if r.state != StateActive {
+		t.Fatalf("state %s after monitor_only_duration", r.state)
+	}
+
+	r.recordPrice(12.5, time.Now())
+	if r.state != StateEmergencyStop {
+		t.Fatalf("state %s above the emergency threshold", r.state)
+	}
+}
+
+func TestRebalancerDryRun(t *testing.T) {
+	r := newTestRebalancer(t, &BotConfig{RebalancerDryRun: true}, nil)
+	r.telegramAlert = nil
+	ctx := context.Background()
+	r.recordPrice(3.0, time.Now())
+
+	rebalance := func() {
+		t.Helper()
+		r.nextRebalanceTime = time.Now().Add(-time.Second)
+		if err := r.processRebalanceCheck(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Simulated rebalances are counted apart and swap nothing
+	rebalance()
+	rebalance()
+	status := r.GetStatus()
+	if status["dry_run"] != true || status["simulated_rebalance_count"] != int64(2) || status["simulated_volume"].(float64) <= 0 {
+		t.Fatalf("dry run status %v", status)
+	}
+	if status["rebalance_count"] != int64(0) || status["swap_count"] != int64(0) || status["total_volume"] != 0.0 {
+		t.Fatalf("dry run executed a swap: %v", status)
+	}
+
+	if err := r.SetDryRun(true); err == nil {
+		t.Fatal("enabling the dry run twice should fail")
+	}
+	if err := r.SetDryRun(false); err != nil {
+		t.Fatal(err)
+	}
+	rebalance()
+	status = r.GetStatus()
+	if status["rebalance_count"] != int64(1) || status["swap_count"] != int64(1) || status["simulated_rebalance_count"] != int64(2) {
+		t.Fatalf("status after disabling the dry run %v", status)
+	}
+}
+
+func TestRebalancerStateSurvivesRestart(t *testing.T) {
+	dataDir := t.TempDir()
+	r := newTestRebalancer(t, &BotConfig{DataDir: dataDir}, nil)
+	r.telegramAlert = nil
+
+	r.mu.Lock()
+	r.recordPrice(6.0, time.Now())
+	r.enterMonitorOnlyMode("Price threshold reached: $6.00")
+	r.dailyRebalanceCount = 3
+	r.mu.Unlock()
+	// 20 of the 24 monitor-only hours passed before the crash
+	started := time.Now().Add(-20 * time.Hour).Truncate(time.Second)
+	r.monitorOnlyStart = started
+	if err := r.SaveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := newTestRebalancer(t, &BotConfig{DataDir: dataDir}, nil)
+	restarted.telegramAlert = nil
+	if restarted.state != StateMonitorOnly || !restarted.monitorOnlyStart.Equal(started) || restarted.dailyRebalanceCount != 3 {
+		t.Fatalf("restored %s since %s with %d rebalances today", restarted.state, restarted.monitorOnlyStart, restarted.dailyRebalanceCount)
+	}
+	if remaining := restarted.thresholds.MonitorOnlyDuration - time.Since(restarted.monitorOnlyStart); remaining > 4*time.Hour || remaining < 4*time.Hour-time.Minute {
+		t.Fatalf("remaining monitor-only duration = %s, want 4h", remaining)
+	}
+
+	// A price back below the limit does not end the remaining 4 hours
+	restarted.recordPrice(3.0, time.Now())
+	if err := restarted.handleMonitorOnlyMode(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.state != StateMonitorOnly {
+		t.Fatalf("monitor-only mode ended %s early", time.Since(started))
+	}
+
+	// Once they passed the transition back to active is persisted too
+	restarted.monitorOnlyStart = started.Add(-5 * time.Hour)
+	if err := restarted.handleMonitorOnlyMode(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if again := newTestRebalancer(t, &BotConfig{DataDir: dataDir}, nil); again.state != StateActive {
+		t.Fatalf("restored %s after monitor-only mode ended", again.state)
+	}
+}
+
+func TestRebalancerPriceHistory(t *testing.T) {
+	r := newTestRebalancer(t, &BotConfig{PriceHistorySize: 3}, nil)
+	r.telegramAlert = nil
+
+	start := time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)
+	for i, price := range []float64{1, 2, 3, 4, 5} {
+		r.recordPrice(price, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	history := r.GetPriceHistory()
+	if len(history) != 3 || r.PriceHistoryCapacity() != 3 {
+		t.Fatalf("history = %+v, capacity %d", history, r.PriceHistoryCapacity())
+	}
+	for i, sample := range history {
+		if sample.Price != float64(i+3) || !sample.At.Equal(start.Add(time.Duration(i+2)*time.Minute)) {
+			t.Fatalf("sample %d = %+v", i, sample)
+		}
+	}
+	if average, _ := r.PriceStatistics(); average != 4 {
+		t.Fatalf("average = %v", average)
+	}
+
+	// The returned samples are a copy
+	history[0].Price = 100
+	if r.GetPriceHistory()[0].Price != 3 {
+		t.Fatal("GetPriceHistory shares the ring buffer")
+	}
+
+	if recent := SamplesSince(history, start.Add(4*time.Minute)); len(recent) != 1 || recent[0].Price != 5 {
+		t.Fatalf("samples since the last minute = %+v", recent)
+	}
+	if err := ValidatePriceHistorySize(MaxPriceHistorySize + 1); err == nil {
+		t.Fatal("oversized price_history_size accepted")
+	}
+}
+
+func TestRebalancerStop(t *testing.T) {
+	testCases := []struct {
+		name   string
+		run    func(r *Rebalancer)
+		state  RebalanceState
+		reason string
+		alerts []string
+	}{
+		{
+			name: "graceful stop",
+			run: func(r *Rebalancer) {
+				r.Stop()
+				r.Stop()
+			},
+			state:  StateStopped,
+			reason: "Rebalancer stopped",
+			alerts: []string{"State: stopped\nReason: Rebalancer stopped\n"},
+		},
+		{
+			// BotService cancels the context and stops the rebalancer
+			name: "context cancelled",
+			run: func(r *Rebalancer) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				if err := r.Start(ctx); err != context.Canceled {
+					t.Fatalf("Start returned %v", err)
+				}
+				r.Stop()
+			},
+			state:  StateStopped,
+			reason: "Rebalancer stopped: context cancelled",
+			alerts: []string{
+				"State: active\nReason: Rebalancer started\n",
+				"State: stopped\nReason: Rebalancer stopped: context cancelled\n",
+			},
+		},
+		{
+			name: "error",
+			run: func(r *Rebalancer) {
+				r.handleError(errors.New("swap failed"))
+			},
+			state:  StateError,
+			reason: "swap failed",
+			alerts: []string{"State: error\nReason: Error: swap failed\n"},
+		},
+		{
+			name: "stop after an error",
+			run: func(r *Rebalancer) {
+				r.handleError(errors.New("swap failed"))
+				r.Stop()
+			},
+			state:  StateStopped,
+			reason: "Rebalancer stopped",
+			alerts: []string{
+				"State: error\nReason: Error: swap failed\n",
+				"State: stopped\nReason: Rebalancer stopped\n",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestRebalancer(t, &BotConfig{}, nil)
+			r.telegramAlert = newTelegramAlert(&BotConfig{})
+			r.telegramAlert.running = true
+
+			tc.run(r)
+
+			status := r.GetStatus()
+			if r.state != tc.state || status["state"] != tc.state.String() || status["state_change_reason"] != tc.reason {
+				t.Fatalf("state %s (%v), reason %q", r.state, status["state"], status["state_change_reason"])
+			}
+
+			var alerts []*Alert
+			for len(r.telegramAlert.alertQueue) > 0 {
+				alerts = append(alerts, <-r.telegramAlert.alertQueue)
+			}
+			if len(alerts) != len(tc.alerts) {
+				t.Fatalf("sent %d alerts, want %d", len(alerts), len(tc.alerts))
+			}
+			for i, want := range tc.alerts {
+				if !strings.Contains(alerts[i].Message, want) {
+					t.Errorf("alert %d = %q, want %q", i, alerts[i].Message, want)
+				}
+				wantType := AlertTypeInfo
+				if strings.Contains(want, "State: error") {
+					wantType = AlertTypeError
+				}
+				if alerts[i].Type != wantType {
+					t.Errorf("alert %d type = %s, want %s", i, alerts[i].Type, wantType)
+				}
+			}
+		})
+	}
+}
+
+func TestRebalancerStopKeepsPersistedState(t *testing.T) {
+	dataDir := t.TempDir()
+	r := newTestRebalancer(t, &BotConfig{DataDir: dataDir}, nil)
+	r.telegramAlert = nil
+
+	r.mu.Lock()
+	r.enterMonitorOnlyMode("Price threshold reached: $6.00")
+	r.mu.Unlock()
+	r.Stop()
+	if err := r.SaveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restart resumes the state the rebalancer stopped in
+	restarted := newTestRebalancer(t, &BotConfig{DataDir: dataDir}, nil)
+	restarted.telegramAlert = nil
+	if restarted.state != StateMonitorOnly {
+		t.Fatalf("restored %s after a stop in monitor-only mode", restarted.state)
+	}
+}
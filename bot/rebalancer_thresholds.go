@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// RebalancerThresholds are the price limits and timings of the rebalancer
+type RebalancerThresholds struct {
+	// Price in USD from which the rebalancer only monitors
+	PriceLimit float64
+	// Price in USD from which the rebalancer stops until the price is back
+	// below PriceLimit
+	EmergencyThreshold float64
+	// How long monitor-only mode lasts before the price is checked again
+	MonitorOnlyDuration time.Duration
+	// Interval between rebalances
+	RebalanceInterval time.Duration
+}
+
+// DefaultRebalancerThresholds are the thresholds of a config without
+// rebalancer settings. The emergency stop triggers at the price limit.
+func DefaultRebalancerThresholds() RebalancerThresholds {
+	limit, _ := strconv.ParseFloat(DefaultPriceLimit, 64)
+	return RebalancerThresholds{
+		PriceLimit:          limit,
+		EmergencyThreshold:  limit,
+		MonitorOnlyDuration: DefaultMonitorOnlyDuration,
+		RebalanceInterval:   DefaultRebalanceInterval,
+	}
+}
+
+// ParseRebalancerThresholds reads price_limit, emergency_threshold,
+// monitor_only_duration and rebalance_interval, using the defaults for
+// absent ones
+func ParseRebalancerThresholds(config *BotConfig) (RebalancerThresholds, error) {
+	t := DefaultRebalancerThresholds()
+
+	if config.PriceLimit != "" {
+		limit, err := parsePositivePrice("price_limit", config.PriceLimit)
+		if err != nil {
+			return t, err
+		}
+		t.PriceLimit = limit
+	}
+	t.EmergencyThreshold = t.PriceLimit
+
+	if config.EmergencyThreshold != "" {
+		emergency, err := parsePositivePrice("emergency_threshold", config.EmergencyThreshold)
+		if err != nil {
+			return t, err
+		}
+		if emergency <= t.PriceLimit {
+			return t, fmt.Errorf("emergency_threshold $%g must be above price_limit $%g", emergency, t.PriceLimit)
+		}
+		t.EmergencyThreshold = emergency
+	}
+
+	if config.MonitorOnlyDuration < 0 {
+		return t, fmt.Errorf("monitor_only_duration must not be negative")
+	}
+	if config.MonitorOnlyDuration > 0 {
+		t.MonitorOnlyDuration = config.MonitorOnlyDuration
+	}
+
+	if config.RebalanceInterval != 0 {
+		if config.RebalanceInterval < MinRebalanceInterval {
+			return t, fmt.Errorf("rebalance_interval must be at least %s", MinRebalanceInterval)
+		}
+		t.RebalanceInterval = config.RebalanceInterval
+	}
+	return t, nil
+}
+
+// parsePositivePrice parses a USD price setting
+func parsePositivePrice(name, value string) (float64, error) {
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || !(price > 0) || math.IsInf(price, 1) {
+		return 0, fmt.Errorf("invalid %s %q, expected a USD price such as \"5.0\"", name, value)
+	}
+	return price, nil
+}
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultReportDataDir is where monthly report snapshots are persisted
+	DefaultReportDataDir = "./data/reports"
+	// ReportMonthLayout is the month identifier format, e.g. 2025-03
+	ReportMonthLayout = "2006-01"
+	// ReportWebhookTimeout bounds a single webhook push
+	ReportWebhookTimeout = 30 * time.Second
+	// UgenPerGXR is the number of ugen in a GXR
+	UgenPerGXR = 100_000_000
+)
+
+// ReportConfig configures monthly validator reports
+type ReportConfig struct {
+	DataDir    string `yaml:"data_dir"`
+	OutputDir  string `yaml:"output_dir"`
+	WebhookURL string `yaml:"webhook_url" sensitive:"true"`
+}
+
+// MonthlyReport is the persisted per-month validator health snapshot
+type MonthlyReport struct {
+	Month            string           `json:"month"`
+	ValidatorName    string           `json:"validator_name"`
+	ValidatorAddress string           `json:"validator_address"`
+	GeneratedAt      time.Time        `json:"generated_at"`
+	ChainPhase       *PhaseInfo       `json:"chain_phase,omitempty"`
+	UptimePercent    float64          `json:"uptime_percent"`
+	MissedBlocks     uint64           `json:"missed_blocks"`
+	BotCompliance    float64          `json:"bot_compliance_percent"`
+	Rewards          ReportRewards    `json:"rewards"`
+	Forfeited        uint64           `json:"forfeited"`
+	Costs            *ReportCosts     `json:"costs,omitempty"`
+	Price            *PriceSignals    `json:"price,omitempty"`
+	Escrow           []ReportEscrow   `json:"escrow,omitempty"`
+	Incidents        []ReportIncident `json:"incidents"`
+	Scores           *ReportScores    `json:"scores,omitempty"`
+}
+
+// ReportRewards breaks down rewards received during the month, in ugen
+type ReportRewards struct {
+	Halving    uint64 `json:"halving"`
+	Fees       uint64 `json:"fees"`
+	Commission uint64 `json:"commission"`
+}
+
+// Total returns the sum of all reward sources
+func (r ReportRewards) Total() uint64 {
+	return r.Halving + r.Fees + r.Commission
+}
+
+// NetRewards returns the rewards of the month minus the fees the bot paid, in ugen
+func (r MonthlyReport) NetRewards() int64 {
+	net := int64(r.Rewards.Total())
+	if r.Costs != nil {
+		net -= int64(r.Costs.TotalFee())
+	}
+	return net
+}
+
+// ReportCosts is what running the bot cost during the month, fees in ugen
+type ReportCosts struct {
+	Categories       []ReportCost `json:"categories"`
+	TelegramRequests uint64       `json:"telegram_requests"`
+	RPCQueries       uint64       `json:"rpc_queries"`
+}
+
+// ReportCost is the transactions the bot paid for in one cost category
+type ReportCost struct {
+	Category     string `json:"category"`
+	Transactions uint64 `json:"transactions"`
+	GasUsed      uint64 `json:"gas_used"`
+	Fee          uint64 `json:"fee"`
+}
+
+// TotalFee returns the fees of all categories
+func (c ReportCosts) TotalFee() uint64 {
+	var fee uint64
+	for _, cost := range c.Categories {
+		fee += cost.Fee
+	}
+	return fee
+}
+
+// ReportEscrow is the IBC escrow balance of a channel over the month, in ugen
+type ReportEscrow struct {
+	Channel       string  `json:"channel"`
+	Address       string  `json:"address"`
+	Start         uint64  `json:"start"`
+	End           uint64  `json:"end"`
+	Min           uint64  `json:"min"`
+	Max           uint64  `json:"max"`
+	MaxDivergence float64 `json:"max_divergence_percent"`
+}
+
+// ReportIncident is a notable event taken from the error journal or alert history
+type ReportIncident struct {
+	Timestamp time.Time `json:"timestamp"`
+	Severity  string    `json:"severity"`
+	Summary   string    `json:"summary"`
+}
+
+// reportFuncs are shared by the markdown and HTML templates
+var reportFuncs = map[string]interface{}{
+	"percent":  func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+	"usd":      func(v float64) string { return fmt.Sprintf("$%.4f", v) },
+	"ugen":     func(v uint64) string { return fmt.Sprintf("%d ugen", v) },
+	"date":     func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+	"score":    func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"movement": RankMovement,
+	"label":    func(s ValidatorScore) string { return validatorLabel(s.OperatorAddress, s.Moniker) },
+	"phase": func(p *PhaseInfo) string {
+		if p == nil {
+			return "unknown"
+		}
+		return p.Describe()
+	},
+}
+
+const markdownReportTemplate = `# Validator Report — {{.ValidatorName}} ({{.Month}})
+
+Validator: ` + "`{{.ValidatorAddress}}`" + `
+Generated: {{date .GeneratedAt}}
+Chain phase: {{phase .ChainPhase}}
+
+## Health
+
+| Metric | Value |
+|---|---|
+| Uptime | {{percent .UptimePercent}} |
+| Missed blocks | {{.MissedBlocks}} |
+| Bot compliance | {{percent .BotCompliance}} |
+
+## Rewards
+
+| Source | Amount |
+|---|---|
+| Halving | {{ugen .Rewards.Halving}} |
+| Fees | {{ugen .Rewards.Fees}} |
+| Commission | {{ugen .Rewards.Commission}} |
+| **Total** | **{{ugen .Rewards.Total}}** |
+
+Forfeited: {{ugen .Forfeited}}
+{{with .Costs}}
+## Bot Operating Cost
+
+| Category | Transactions | Gas used | Fees |
+|---|---|---|---|
+{{range .Categories}}| {{.Category}} | {{.Transactions}} | {{.GasUsed}} | {{ugen .Fee}} |
+{{end}}| **Total** | | | **{{ugen .TotalFee}}** |
+
+Telegram API requests: {{.TelegramRequests}}
+RPC queries: {{.RPCQueries}}
+Net rewards after bot fees: {{$.NetRewards}} ugen
+{{end}}{{with .Price}}
+## Price
+
+| Signal | Value |
+|---|---|
+| Spot | {{usd .Spot}} |
+| EMA 1h | {{usd .EMA1h}} |
+| EMA 24h | {{usd .EMA24h}} |
+| Trend | {{.Trend}} |
+{{end}}{{if .Escrow}}
+## IBC Escrow
+
+| Channel | Start | End | Min | Max | Max divergence |
+|---|---|---|---|---|---|
+{{range .Escrow}}| {{.Channel}} | {{ugen .Start}} | {{ugen .End}} | {{ugen .Min}} | {{ugen .Max}} | {{percent .MaxDivergence}} |
+{{end}}{{end}}{{with .Scores}}
+## Validator Scores (month {{.Month}})
+
+| Rank | Movement | Validator | Score |
+|---|---|---|---|
+{{range .Validators}}| {{.Rank}} | {{movement .}} | {{label .}} | {{score .Score}} |
+{{end}}{{end}}
+## Incidents
+{{if .Incidents}}
+{{range .Incidents}}- {{date .Timestamp}} [{{.Severity}}] {{.Summary}}
+{{end}}{{else}}
+No notable incidents this month.
+{{end}}{{with .Scores}}
+---
+
+_{{.Formula}}_
+{{end}}`
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Validator Report — {{.ValidatorName}} ({{.Month}})</title>
+<style>
+body { font-family: sans-serif; max-width: 720px; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 6px 10px; text-align: left; }
+.total { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Validator Report — {{.ValidatorName}} ({{.Month}})</h1>
+<p>Validator: <code>{{.ValidatorAddress}}</code><br>Generated: {{date .GeneratedAt}}<br>Chain phase: {{phase .ChainPhase}}</p>
+<h2>Health</h2>
+<table>
+<tr><th>Metric</th><th>Value</th></tr>
+<tr><td>Uptime</td><td>{{percent .UptimePercent}}</td></tr>
+<tr><td>Missed blocks</td><td>{{.MissedBlocks}}</td></tr>
+<tr><td>Bot compliance</td><td>{{percent .BotCompliance}}</td></tr>
+</table>
+<h2>Rewards</h2>
+<table>
+<tr><th>Source</th><th>Amount</th></tr>
+<tr><td>Halving</td><td>{{ugen .Rewards.Halving}}</td></tr>
+<tr><td>Fees</td><td>{{ugen .Rewards.Fees}}</td></tr>
+<tr><td>Commission</td><td>{{ugen .Rewards.Commission}}</td></tr>
+<tr class="total"><td>Total</td><td>{{ugen .Rewards.Total}}</td></tr>
+</table>
+<p>Forfeited: {{ugen .Forfeited}}</p>
+{{with .Costs}}<h2>Bot Operating Cost</h2>
+<table>
+<tr><th>Category</th><th>Transactions</th><th>Gas used</th><th>Fees</th></tr>
+{{range .Categories}}<tr><td>{{.Category}}</td><td>{{.Transactions}}</td><td>{{.GasUsed}}</td><td>{{ugen .Fee}}</td></tr>
+{{end}}<tr class="total"><td>Total</td><td></td><td></td><td>{{ugen .TotalFee}}</td></tr>
+</table>
+<p>Telegram API requests: {{.TelegramRequests}}<br>RPC queries: {{.RPCQueries}}<br>Net rewards after bot fees: {{$.NetRewards}} ugen</p>
+{{end}}{{with .Price}}<h2>Price</h2>
+<table>
+<tr><th>Signal</th><th>Value</th></tr>
+<tr><td>Spot</td><td>{{usd .Spot}}</td></tr>
+<tr><td>EMA 1h</td><td>{{usd .EMA1h}}</td></tr>
+<tr><td>EMA 24h</td><td>{{usd .EMA24h}}</td></tr>
+<tr><td>Trend</td><td>{{.Trend}}</td></tr>
+</table>
+{{end}}{{if .Escrow}}<h2>IBC Escrow</h2>
+<table>
+<tr><th>Channel</th><th>Start</th><th>End</th><th>Min</th><th>Max</th><th>Max divergence</th></tr>
+{{range .Escrow}}<tr><td>{{.Channel}}</td><td>{{ugen .Start}}</td><td>{{ugen .End}}</td><td>{{ugen .Min}}</td><td>{{ugen .Max}}</td><td>{{percent .MaxDivergence}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Scores}}<h2>Validator Scores (month {{.Month}})</h2>
+<table>
+<tr><th>Rank</th><th>Movement</th><th>Validator</th><th>Score</th></tr>
+{{range .Validators}}<tr><td>{{.Rank}}</td><td>{{movement .}}</td><td>{{label .}}</td><td>{{score .Score}}</td></tr>
+{{end}}</table>
+{{end}}<h2>Incidents</h2>
+{{if .Incidents}}<ul>
+{{range .Incidents}}<li>{{date .Timestamp}} [{{.Severity}}] {{.Summary}}</li>
+{{end}}</ul>{{else}}<p>No notable incidents this month.</p>{{end}}
+{{with .Scores}}<footer><hr><p><small>{{.Formula}}</small></p></footer>
+{{end}}</body>
+</html>
+`
+
+var (
+	markdownReport = texttemplate.Must(texttemplate.New("markdown").Funcs(reportFuncs).Parse(markdownReportTemplate))
+	htmlReport     = htmltemplate.Must(htmltemplate.New("html").Funcs(reportFuncs).Parse(htmlReportTemplate))
+)
+
+// ParseReportMonth validates a month identifier in YYYY-MM form
+func ParseReportMonth(month string) (time.Time, error) {
+	parsed, err := time.Parse(ReportMonthLayout, month)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q, expected YYYY-MM", month)
+	}
+	return parsed, nil
+}
+
+// previousReportMonth returns the month before the one now falls in. It steps
+// back from the first of the month, since AddDate normalizes e.g. March 31
+// minus a month to March 3.
+func previousReportMonth(now time.Time) string {
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return firstOfMonth.AddDate(0, -1, 0).Format(ReportMonthLayout)
+}
+
+// reportSnapshotPath returns the snapshot file for a month
+func reportSnapshotPath(dataDir, month string) string {
+	if dataDir == "" {
+		dataDir = DefaultReportDataDir
+	}
+	return filepath.Join(dataDir, month+".json")
+}
+
+// SaveMonthlyReport persists a monthly snapshot, replacing any previous one atomically
+func SaveMonthlyReport(dataDir string, report *MonthlyReport) error {
+	if _, err := ParseReportMonth(report.Month); err != nil {
+		return err
+	}
+
+	path := reportSnapshotPath(dataDir, report.Month)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// LoadMonthlyReport reads a persisted monthly snapshot without contacting the chain
+func LoadMonthlyReport(dataDir, month string) (*MonthlyReport, error) {
+	if _, err := ParseReportMonth(month); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(reportSnapshotPath(dataDir, month))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no report data recorded for %s", month)
+		}
+		return nil, fmt.Errorf("failed to read report data: %w", err)
+	}
+
+	var report MonthlyReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report data: %w", err)
+	}
+
+	return &report, nil
+}
+
+// RenderReport renders a monthly report as markdown or html
+func RenderReport(report *MonthlyReport, format string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch format {
+	case "markdown":
+		if err := markdownReport.Execute(&buf, report); err != nil {
+			return nil, fmt.Errorf("failed to render markdown report: %w", err)
+		}
+	case "html":
+		if err := htmlReport.Execute(&buf, report); err != nil {
+			return nil, fmt.Errorf("failed to render html report: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported report format %q (use markdown or html)", format)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// reportFileName returns the output file name for a rendered report
+func reportFileName(month, format string) string {
+	ext := "md"
+	if format == "html" {
+		ext = "html"
+	}
+	return fmt.Sprintf("validator-report-%s.%s", month, ext)
+}
+
+// pushReport posts a rendered report to a webhook
+func pushReport(ctx context.Context, webhookURL, format string, content []byte) error {
+	contentType := "text/markdown; charset=utf-8"
+	if format == "html" {
+		contentType = "text/html; charset=utf-8"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ReportWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// createReportCmd creates the report command
+func createReportCmd() *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Validator transparency reports",
+	}
+
+	reportCmd.AddCommand(createReportPublishCmd())
+
+	return reportCmd
+}
+
+// createReportPublishCmd creates the report publish command
+func createReportPublishCmd() *cobra.Command {
+	var (
+		format    string
+		month     string
+		outputDir string
+		webhook   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Render a monthly validator health report from persisted data",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, _ := cmd.Flags().GetString("config")
+			config, err := LoadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			if month == "" {
+				month = previousReportMonth(time.Now())
+			}
+			if outputDir == "" {
+				outputDir = config.Reports.OutputDir
+			}
+			if webhook == "" {
+				webhook = config.Reports.WebhookURL
+			}
+
+			report, err := LoadMonthlyReport(config.Reports.DataDir, month)
+			if err != nil {
+				return err
+			}
+			if report.Scores == nil {
+				report.Scores = reportScoresFor(config, month)
+			}
+			if report.Costs == nil {
+				report.Costs = reportCostsFor(config, month)
+			}
+
+			content, err := RenderReport(report, strings.ToLower(format))
+			if err != nil {
+				return err
+			}
+
+			if outputDir == "" && webhook == "" {
+				_, err := cmd.OutOrStdout().Write(content)
+				return err
+			}
+
+			if outputDir != "" {
+				if err := os.MkdirAll(outputDir, 0755); err != nil {
+					return fmt.Errorf("failed to create output directory: %w", err)
+				}
+				path := filepath.Join(outputDir, reportFileName(month, strings.ToLower(format)))
+				if err := os.WriteFile(path, content, 0644); err != nil {
+					return fmt.Errorf("failed to write report: %w", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Report written to %s\n", path)
+			}
+
+			if webhook != "" {
+				if err := pushReport(cmd.Context(), webhook, strings.ToLower(format), content); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Report pushed to webhook\n")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "markdown", "Output format (markdown|html)")
+	cmd.Flags().StringVar(&month, "month", "", "Report month as YYYY-MM (default: previous month)")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Directory to write the report to")
+	cmd.Flags().StringVar(&webhook, "webhook", "", "Webhook URL to push the report to")
+
+	return cmd
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func sampleMonthlyReport() *MonthlyReport {
+	return &MonthlyReport{
+		Month:            "2025-03",
+		ValidatorName:    "Crocodile <Node>",
+		ValidatorAddress: "gxrvaloper1abcdefg",
+		GeneratedAt:      time.Date(2025, 4, 1, 0, 5, 0, 0, time.UTC),
+		ChainPhase: &PhaseInfo{
+			Phase: ChainPhaseAccumulating,
+			Cycle: 1,
+			Since: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2030, 1, 14, 0, 0, 0, 0, time.UTC),
+		},
+		UptimePercent: 99.87,
+		MissedBlocks:  112,
+		BotCompliance: 100,
+		Rewards: ReportRewards{
+			Halving:    2500000,
+			Fees:       180000,
+			Commission: 95000,
+		},
+		Forfeited: 0,
+		Price: &PriceSignals{
+			Spot:        3.1842,
+			EMA1h:       3.1511,
+			EMA24h:      3.0275,
+			EMA1hReady:  true,
+			EMA24hReady: true,
+			Trend:       TrendRising,
+		},
+		Escrow: []ReportEscrow{
+			{Channel: "channel-0", Address: "gxr1escrow", Start: 1250000000, End: 1310000000, Min: 1190000000, Max: 1320000000, MaxDivergence: 0.12},
+		},
+		Incidents: []ReportIncident{
+			{Timestamp: time.Date(2025, 3, 12, 4, 30, 0, 0, time.UTC), Severity: "warning", Summary: "Bot heartbeat delayed 7 minutes"},
+			{Timestamp: time.Date(2025, 3, 20, 18, 2, 0, 0, time.UTC), Severity: "critical", Summary: "RPC node unreachable for 3 minutes"},
+		},
+	}
+}
+
+func TestRenderReportGolden(t *testing.T) {
+	dataDir := t.TempDir()
+	if err := SaveMonthlyReport(dataDir, sampleMonthlyReport()); err != nil {
+		t.Fatalf("failed to save report: %v", err)
+	}
+
+	report, err := LoadMonthlyReport(dataDir, "2025-03")
+	if err != nil {
+		t.Fatalf("failed to load report: %v", err)
+	}
+
+	for _, format := range []string{"markdown", "html"} {
+		t.Run(format, func(t *testing.T) {
+			got, err := RenderReport(report, format)
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+
+			golden := filepath.Join("testdata", "report_"+format+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+
+			if string(got) != string(want) {
+				t.Errorf("%s report does not match %s\n--- got ---\n%s", format, golden, got)
+			}
+		})
+	}
+}
+
+func TestLoadMonthlyReportErrors(t *testing.T) {
+	dataDir := t.TempDir()
+
+	if _, err := LoadMonthlyReport(dataDir, "March"); err == nil {
+		t.Fatal("expected error for malformed month")
+	}
+
+	if _, err := LoadMonthlyReport(dataDir, "2025-03"); err == nil {
+		t.Fatal("expected error when no data is recorded")
+	}
+
+	if _, err := RenderReport(sampleMonthlyReport(), "pdf"); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+}
+
+func TestPreviousReportMonth(t *testing.T) {
+	cases := map[time.Time]string{
+		time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC): "2025-02",
+		time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC):  "2025-04",
+		time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC):  "2024-12",
+		time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC):   "2025-06",
+	}
+	for now, want := range cases {
+		if got := previousReportMonth(now); got != want {
+			t.Errorf("previousReportMonth(%s) = %s, want %s", now.Format("2006-01-02"), got, want)
+		}
+	}
+}
+
+func TestMonthlyResetSavesReport(t *testing.T) {
+	dataDir := t.TempDir()
+	reportDir := filepath.Join(dataDir, "reports")
+	config := &BotConfig{DataDir: dataDir, ValidatorAddress: "gxrvaloper1ours", Reports: ReportConfig{DataDir: reportDir}}
+
+	journal := NewErrorJournal(dataDir)
+	if err := journal.Record(JournalEntry{Timestamp: time.Now(), Severity: "critical", Source: "rpc", Summary: "RPC node unreachable"}); err != nil {
+		t.Fatal(err)
+	}
+
+	vm := NewValidatorMonitor(config, client.Context{}, nil)
+	vm.telegramAlert = nil
+	vm.currentMonth = uptimeMonth(time.Now())
+	vm.validators["gxrvaloper1ours"] = &ValidatorStatus{
+		OperatorAddress:  "gxrvaloper1ours",
+		Moniker:          "ours",
+		UptimePercent:    99.5,
+		MissedBlocks:     12,
+		BotChecks:        10,
+		BotChecksRunning: 9,
+		ForfeitedRewards: 1.5,
+	}
+	vm.performMonthlyReset(context.Background(), vm.currentMonth+1)
+
+	month := time.Now().Format(ReportMonthLayout)
+	report, err := LoadMonthlyReport(reportDir, month)
+	if err != nil {
+		t.Fatalf("report of %s not saved: %v", month, err)
+	}
+	if report.ValidatorName != "ours" || report.UptimePercent != 99.5 || report.MissedBlocks != 12 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.BotCompliance != 90 || report.Forfeited != 150_000_000 {
+		t.Fatalf("compliance %.2f, forfeited %d", report.BotCompliance, report.Forfeited)
+	}
+	if len(report.Incidents) != 1 || report.Incidents[0].Summary != "RPC node unreachable" {
+		t.Fatalf("incidents = %+v", report.Incidents)
+	}
+}
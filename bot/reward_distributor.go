@@ -2,29 +2,63 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// RewardQueryInterval is how often the pending DEX allocation is queried
+const RewardQueryInterval = 1 * time.Minute
+
+// DistributionConfirmDelay is how long the simulated distribution waits for
+// its transaction to be confirmed
+const DistributionConfirmDelay = 2 * time.Second
+
 // RewardDistributor handles automatic reward distribution
 type RewardDistributor struct {
 	config *BotConfig
+	mu     sync.Mutex
 	
 	// Chain client would be here in real implementation
 	chainClient interface{}
+	chain       *ChainQuerier
+	queryCache  *QueryCache // serves the last allocation through brief outages
+	
+	// How long a distribution waits for confirmation
+	confirmDelay time.Duration
+	
+	// Pending DEX allocation, as last queried
+	pendingDex          PendingDexAllocation
+	pendingDexFetchedAt time.Time
+	pendingDexFromCache bool
+	lastQueryError      string
 	
 	// Distribution state
 	lastDistribution  time.Time
 	distributionCount int64
 	totalDistributed  string
 	isConnected       bool
+	
+	// costs records the gas and fee of every distribution
+	costs *CostLedger
+	
+	// shadow runs the candidate distribution triggers
+	shadow *ShadowRunner
 }
 
 // NewRewardDistributor creates a new reward distributor instance
 func NewRewardDistributor(config *BotConfig) *RewardDistributor {
 	return &RewardDistributor{
-		config: config,
+		config:       config,
+		chain:        NewChainQuerier(config),
+		confirmDelay: DistributionConfirmDelay,
 	}
 }
 
@@ -37,9 +71,11 @@ func (rd *RewardDistributor) Initialize() error {
 		return fmt.Errorf("failed to initialize chain client: %w", err)
 	}
 	
+	rd.mu.Lock()
 	rd.lastDistribution = time.Now()
 	rd.totalDistributed = "0ugen"
 	rd.isConnected = true
+	rd.mu.Unlock()
 	
 	log.Println("Reward Distributor initialized successfully")
 	return nil
@@ -69,37 +105,81 @@ func (rd *RewardDistributor) Start(ctx context.Context) error {
 	log.Println("Starting Reward Distributor service...")
 	
 	// Check connection status
-	if !rd.isConnected {
+	if !rd.connected() {
 		return fmt.Errorf("reward distributor not connected to chain")
 	}
 	
 	// Check every hour for monthly distributions
-	ticker := time.NewTicker(1 * time.Hour)
+	ticker := NewJitteredTicker(1 * time.Hour)
 	defer ticker.Stop()
 	
+	queryTicker := NewJitteredTicker(RewardQueryInterval)
+	defer queryTicker.Stop()
+	
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("Reward Distributor stopping...")
 			return nil
 			
+		case <-queryTicker.C:
+			if err := rd.refreshPendingAllocation(ctx); err != nil {
+				log.Printf("Reward Distributor query error: %v", err)
+			}
+			
 		case <-ticker.C:
-			if err := rd.checkAndDistribute(); err != nil {
+			if err := rd.checkAndDistribute(ctx); err != nil {
 				log.Printf("Reward Distributor error: %v", err)
 			}
 		}
 	}
 }
 
+// refreshPendingAllocation queries the DEX share awaiting distribution. While
+// the chain is briefly unreachable the cached allocation is kept; once it is
+// too old the distributor reports itself disconnected.
+func (rd *RewardDistributor) refreshPendingAllocation(ctx context.Context) error {
+	allocation, cached, err := CachedQuery(rd.queryCache, QueryCachePendingDexAllocation, func() (PendingDexAllocation, error) {
+		return rd.chain.QueryPendingDexAllocation(ctx)
+	})
+	
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	
+	if err != nil {
+		rd.isConnected = false
+		rd.lastQueryError = err.Error()
+		return fmt.Errorf("failed to query pending DEX allocation: %w", err)
+	}
+	
+	rd.isConnected = true
+	rd.pendingDex = allocation
+	rd.pendingDexFetchedAt = cached.FetchedAt
+	rd.pendingDexFromCache = cached.Cached
+	if !cached.Cached {
+		rd.lastQueryError = ""
+	}
+	return nil
+}
+
+// connected reports whether the distributor considers the chain reachable
+func (rd *RewardDistributor) connected() bool {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	return rd.isConnected
+}
+
 // checkAndDistribute checks if it's time to distribute rewards and does so
-func (rd *RewardDistributor) checkAndDistribute() error {
+func (rd *RewardDistributor) checkAndDistribute(ctx context.Context) error {
 	// Check if it's time for monthly distribution
 	now := time.Now()
-	if rd.shouldDistribute(now) {
+	due := rd.shouldDistribute(now)
+	rd.shadowTrigger(due, now)
+	if due {
 		log.Println("Time for monthly reward distribution")
 		
 		// Distribute halving rewards
-		if err := rd.distributeHalvingRewards(); err != nil {
+		if err := rd.distributeHalvingRewards(ctx); err != nil {
 			return fmt.Errorf("failed to distribute halving rewards: %w", err)
 		}
 		
@@ -118,20 +198,103 @@ func (rd *RewardDistributor) shouldDistribute(now time.Time) bool {
 	return now.Sub(rd.lastDistribution) >= (30 * 24 * time.Hour)
 }
 
-// distributeHalvingRewards distributes rewards from the halving fund
-func (rd *RewardDistributor) distributeHalvingRewards() error {
+// shadowTrigger records what the candidate distribution triggers would have
+// decided next to due
+func (rd *RewardDistributor) shadowTrigger(due bool, now time.Time) {
+	rd.shadow.Run(ShadowDistributionCalendarMonth, distributionDecision(due), func() string {
+		return distributionDecision(rd.calendarMonthDue(now))
+	}, now)
+}
+
+// calendarMonthDue is the calendar month candidate of shouldDistribute: the
+// first check of a month without a distribution distributes
+func (rd *RewardDistributor) calendarMonthDue(now time.Time) bool {
+	lastYear, lastMonth, _ := rd.lastDistribution.Date()
+	year, month, _ := now.Date()
+	return year != lastYear || month != lastMonth
+}
+
+// distributionDecision names a distribution trigger's decision for shadow mode
+func distributionDecision(due bool) string {
+	if due {
+		return "distribute"
+	}
+	return ShadowDecisionNone
+}
+
+// DistributionTx is the transaction distributing a cycle's halving rewards
+type DistributionTx struct {
+	Cycle  uint64
+	Amount int64 // ugen
+	Memo   string
+	Signed []byte
+	Hash   string
+	Result TxResult // gas and fee, set by the broadcast
+}
+
+// distributeHalvingRewards distributes rewards from the halving fund. Each
+// step is a span of the distribution's trace, so a slow distribution shows
+// which step took the time.
+func (rd *RewardDistributor) distributeHalvingRewards(ctx context.Context) (err error) {
 	log.Println("Distributing halving rewards...")
 	
-	// In a real implementation, this would:
-	// 1. Create a transaction to call the halving module's distribute function
-	// 2. Sign and broadcast the transaction
-	// 3. Wait for confirmation
+	ctx, span := startSpan(ctx, "distribution", traceChainID.String(rd.config.ChainID))
+	defer func() { endSpan(span, err) }()
+	
+	// The allocation only annotates the distribution, the module distributes
+	// what it holds; a failed query falls back to the last one
+	var allocation PendingDexAllocation
+	traceStep(ctx, "distribution.query", func(ctx context.Context, step trace.Span) error {
+		if err := rd.refreshPendingAllocation(ctx); err != nil {
+			step.RecordError(err)
+			log.Printf("Distributing with the last queried DEX allocation: %v", err)
+		}
+		rd.mu.Lock()
+		allocation = rd.pendingDex
+		rd.mu.Unlock()
+		step.SetAttributes(traceCycle.Int64(int64(allocation.Cycle)), attribute.Int64("gxr.pending_dex_ugen", allocation.Amount))
+		return nil
+	})
+	
+	var tx DistributionTx
+	traceStep(ctx, "distribution.build", func(ctx context.Context, step trace.Span) error {
+		tx = rd.buildDistributionTx(allocation)
+		step.SetAttributes(traceAmount.Int64(tx.Amount))
+		return nil
+	})
+	span.SetAttributes(traceCycle.Int64(int64(tx.Cycle)), traceAmount.Int64(tx.Amount))
 	
-	// For now, we'll simulate the process
-	if err := rd.simulateDistribution(); err != nil {
-		return fmt.Errorf("distribution simulation failed: %w", err)
+	traceStep(ctx, "distribution.sign", func(ctx context.Context, step trace.Span) error {
+		rd.signDistributionTx(&tx)
+		return nil
+	})
+	
+	if err := traceStep(ctx, "distribution.broadcast", func(ctx context.Context, step trace.Span) error {
+		if err := rd.broadcastDistributionTx(&tx); err != nil {
+			return err
+		}
+		step.SetAttributes(traceTxHash.String(tx.Hash))
+		return nil
+	}); err != nil {
+		return fmt.Errorf("distribution broadcast failed: %w", err)
+	}
+	span.SetAttributes(traceTxHash.String(tx.Hash))
+	
+	if err := traceStep(ctx, "distribution.confirm", func(ctx context.Context, step trace.Span) error {
+		step.SetAttributes(traceTxHash.String(tx.Hash))
+		return rd.confirmDistributionTx(ctx, tx)
+	}); err != nil {
+		return fmt.Errorf("distribution %s not confirmed: %w", tx.Hash, err)
 	}
+	rd.costs.RecordTx(CostDistribution, tx.Result, time.Now())
+	
+	traceStep(ctx, "distribution.reconcile", func(ctx context.Context, step trace.Span) error {
+		total := rd.reconcileDistribution(tx)
+		step.SetAttributes(attribute.String("gxr.total_distributed", total))
+		return nil
+	})
 	
+	log.Printf("Distribution confirmed (tx %s)", tx.Hash)
 	log.Println("- 70% distributed to active validators")
 	log.Println("- 20% distributed to PoS pool (delegators)")
 	log.Println("- 10% distributed to DEX pools")
@@ -139,24 +302,58 @@ func (rd *RewardDistributor) distributeHalvingRewards() error {
 	return nil
 }
 
-// simulateDistribution simulates the distribution process
-func (rd *RewardDistributor) simulateDistribution() error {
-	// Simulate transaction creation delay
-	time.Sleep(2 * time.Second)
-	
+// buildDistributionTx creates the distribution transaction of allocation's
+// cycle. In a real implementation this would call the halving module's
+// distribute function; the amount is simulated.
+func (rd *RewardDistributor) buildDistributionTx(allocation PendingDexAllocation) DistributionTx {
+	return DistributionTx{
+		Cycle:  allocation.Cycle,
+		Amount: (rd.distributionCount + 1) * 70833,
+		Memo:   fmt.Sprintf("gxr-bot halving distribution %d", rd.distributionCount+1),
+	}
+}
+
+// signDistributionTx simulates signing tx with the bot's key
+func (rd *RewardDistributor) signDistributionTx(tx *DistributionTx) {
+	tx.Signed = []byte(fmt.Sprintf("%s|%s|%d|%d", rd.config.ChainID, tx.Memo, tx.Cycle, tx.Amount))
+}
+
+// broadcastDistributionTx simulates broadcasting tx and sets its hash
+func (rd *RewardDistributor) broadcastDistributionTx(tx *DistributionTx) error {
 	// Simulate potential failures
 	if rd.distributionCount > 0 && rd.distributionCount%10 == 0 {
 		return fmt.Errorf("simulated network error")
 	}
 	
-	// Update total distributed amount (this would come from the actual transaction)
-	rd.totalDistributed = fmt.Sprintf("%dugen", (rd.distributionCount+1)*70833)
-	
+	sum := sha256.Sum256(tx.Signed)
+	tx.Hash = strings.ToUpper(hex.EncodeToString(sum[:]))
 	return nil
 }
 
+// confirmDistributionTx simulates waiting for tx to be included in a block
+func (rd *RewardDistributor) confirmDistributionTx(ctx context.Context, tx DistributionTx) error {
+	select {
+	case <-time.After(rd.confirmDelay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reconcileDistribution records the confirmed tx and returns the total
+// distributed (this would come from the actual transaction's events)
+func (rd *RewardDistributor) reconcileDistribution(tx DistributionTx) string {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	rd.totalDistributed = fmt.Sprintf("%dugen", tx.Amount)
+	return rd.totalDistributed
+}
+
 // GetStatus returns the current reward distributor status
 func (rd *RewardDistributor) GetStatus() map[string]interface{} {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	
 	nextDistribution := rd.lastDistribution.Add(30 * 24 * time.Hour)
 	timeUntilNext := nextDistribution.Sub(time.Now())
 	
@@ -170,18 +367,23 @@ func (rd *RewardDistributor) GetStatus() map[string]interface{} {
 		"chain_id":           rd.config.ChainID,
 		"chain_rpc":          rd.config.ChainRPC,
 		"chain_grpc":         rd.config.ChainGRPC,
+		"pending_dex_amount": rd.pendingDex.Amount,
+		"pending_dex_cycle":  rd.pendingDex.Cycle,
+		"pending_dex_fetched_at": rd.pendingDexFetchedAt.Format(time.RFC3339),
+		"pending_dex_from_cache": rd.pendingDexFromCache,
+		"last_query_error":   rd.lastQueryError,
 	}
 }
 
 // ForceDistribution forces a manual distribution (for testing/emergency)
 func (rd *RewardDistributor) ForceDistribution() error {
-	if !rd.isConnected {
+	if !rd.connected() {
 		return fmt.Errorf("not connected to chain")
 	}
 	
 	log.Println("Forcing manual reward distribution...")
 	
-	if err := rd.distributeHalvingRewards(); err != nil {
+	if err := rd.distributeHalvingRewards(context.Background()); err != nil {
 		return fmt.Errorf("forced distribution failed: %w", err)
 	}
 	
@@ -196,13 +398,17 @@ func (rd *RewardDistributor) ForceDistribution() error {
 func (rd *RewardDistributor) Reconnect() error {
 	log.Println("Attempting to reconnect to chain...")
 	
+	rd.mu.Lock()
 	rd.isConnected = false
+	rd.mu.Unlock()
 	
 	if err := rd.initializeChainClient(); err != nil {
 		return fmt.Errorf("reconnection failed: %w", err)
 	}
 	
+	rd.mu.Lock()
 	rd.isConnected = true
+	rd.mu.Unlock()
 	log.Println("Reconnection successful")
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// ShadowRebalancerEMA1h checks the rebalancer thresholds against the 1h
+	// EMA instead of the threshold_source price
+	ShadowRebalancerEMA1h = "rebalancer_ema1h_threshold"
+	// ShadowDistributionCalendarMonth distributes at the first check of a
+	// calendar month instead of 30 days after the last distribution
+	ShadowDistributionCalendarMonth = "distribution_calendar_month"
+
+	// MaxShadowDivergences is the number of recent divergences kept per candidate
+	MaxShadowDivergences = 20
+
+	// ShadowDecisionNone is the decision of a path that would not act
+	ShadowDecisionNone = "none"
+)
+
+// shadowCandidates are the candidate decision paths, with the component
+// running each next to its active logic
+var shadowCandidates = map[string]string{
+	ShadowRebalancerEMA1h:           ComponentRebalancer,
+	ShadowDistributionCalendarMonth: ComponentRewardDistributor,
+}
+
+// ShadowConfig selects the candidate decision paths that run in shadow mode:
+// next to the active logic, only logging and recording what they would have
+// done
+type ShadowConfig struct {
+	Candidates []string `yaml:"candidates"`
+}
+
+// ValidateShadow checks the shadow settings
+func ValidateShadow(cfg ShadowConfig) error {
+	seen := make(map[string]bool, len(cfg.Candidates))
+	for _, name := range cfg.Candidates {
+		if _, ok := shadowCandidates[name]; !ok {
+			return fmt.Errorf("shadow.candidates: unknown candidate %q", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("shadow.candidates: %s is listed twice", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// ShadowDivergence is a decision where the candidate disagreed with the active logic
+type ShadowDivergence struct {
+	At        time.Time `json:"at"`
+	Active    string    `json:"active"`
+	Candidate string    `json:"candidate"`
+}
+
+// ShadowCandidateReport compares a candidate with the active logic since the
+// report window started
+type ShadowCandidateReport struct {
+	Name        string `json:"name"`
+	Component   string `json:"component"`
+	Runs        int64  `json:"runs"`
+	Divergences int64  `json:"divergences"`
+	// Failures are candidate runs that panicked; the active logic is unaffected
+	Failures int64 `json:"failures"`
+	// Decisions counts what the candidate would have done
+	Decisions map[string]int64 `json:"decisions"`
+	// Recent are the last MaxShadowDivergences divergences, oldest first
+	Recent []ShadowDivergence `json:"recent_divergences"`
+}
+
+// ShadowReport is the comparison of all enabled candidates
+type ShadowReport struct {
+	Since      time.Time               `json:"since"`
+	Candidates []ShadowCandidateReport `json:"candidates"`
+}
+
+// ShadowRunner runs the enabled candidate decision paths and records how
+// often they diverge from the active logic. A nil runner runs nothing.
+type ShadowRunner struct {
+	mu         sync.Mutex
+	since      time.Time
+	candidates map[string]*ShadowCandidateReport
+}
+
+// NewShadowRunner creates a runner of the configured candidates, nil when
+// none is enabled
+func NewShadowRunner(cfg ShadowConfig, now time.Time) *ShadowRunner {
+	if len(cfg.Candidates) == 0 {
+		return nil
+	}
+	s := &ShadowRunner{since: now, candidates: make(map[string]*ShadowCandidateReport)}
+	for _, name := range cfg.Candidates {
+		s.candidates[name] = newShadowCandidateReport(name)
+		log.Printf("Shadow mode: candidate %s runs next to the %s logic", name, shadowCandidates[name])
+	}
+	return s
+}
+
+func newShadowCandidateReport(name string) *ShadowCandidateReport {
+	return &ShadowCandidateReport{
+		Name:      name,
+		Component: shadowCandidates[name],
+		Decisions: make(map[string]int64),
+	}
+}
+
+// Enabled reports whether a candidate runs
+func (s *ShadowRunner) Enabled(name string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.candidates[name]
+	return ok
+}
+
+// Run runs candidate if it is enabled and records its decision against the
+// active one. The candidate only decides; it must not act.
+func (s *ShadowRunner) Run(name, active string, candidate func() string, now time.Time) {
+	if !s.Enabled(name) {
+		return
+	}
+	decision, err := runShadowCandidate(candidate)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := s.candidates[name]
+	report.Runs++
+	if err != nil {
+		report.Failures++
+		log.Printf("Shadow candidate %s failed: %v", name, err)
+		return
+	}
+	report.Decisions[decision]++
+	if decision == active {
+		return
+	}
+
+	report.Divergences++
+	report.Recent = append(report.Recent, ShadowDivergence{At: now, Active: active, Candidate: decision})
+	if len(report.Recent) > MaxShadowDivergences {
+		report.Recent = report.Recent[1:]
+	}
+	log.Printf("Shadow candidate %s diverged: active %s, candidate would %s", name, active, decision)
+}
+
+// runShadowCandidate calls a candidate, turning a panic into an error
+func runShadowCandidate(candidate func() string) (decision string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return candidate(), nil
+}
+
+// Report returns a copy of the comparison, candidates ordered by name
+func (s *ShadowRunner) Report() ShadowReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := ShadowReport{Since: s.since, Candidates: make([]ShadowCandidateReport, 0, len(s.candidates))}
+	for _, candidate := range s.candidates {
+		c := *candidate
+		c.Decisions = make(map[string]int64, len(candidate.Decisions))
+		for decision, count := range candidate.Decisions {
+			c.Decisions[decision] = count
+		}
+		c.Recent = append([]ShadowDivergence(nil), candidate.Recent...)
+		report.Candidates = append(report.Candidates, c)
+	}
+	sort.Slice(report.Candidates, func(i, j int) bool {
+		return report.Candidates[i].Name < report.Candidates[j].Name
+	})
+	return report
+}
+
+// Reset starts a new report window
+func (s *ShadowRunner) Reset(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.since = now
+	for name := range s.candidates {
+		s.candidates[name] = newShadowCandidateReport(name)
+	}
+}
+
+// DigestSection renders the report as a section of the weekly digest
+func (r ShadowReport) DigestSection() string {
+	section := "🔬 Shadow Mode"
+	for _, c := range r.Candidates {
+		section += fmt.Sprintf("\n%s (%s): %d of %d decisions diverged", c.Name, c.Component, c.Divergences, c.Runs)
+		if c.Failures > 0 {
+			section += fmt.Sprintf(", %d failed", c.Failures)
+		}
+	}
+	return section
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShadowRebalancerEMA1hThreshold(t *testing.T) {
+	start := time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)
+	shadow := NewShadowRunner(ShadowConfig{Candidates: []string{ShadowRebalancerEMA1h}}, start)
+	r := newTestRebalancer(t, &BotConfig{PriceLimit: "5", EmergencyThreshold: "10"}, nil)
+	r.telegramAlert = nil
+	r.shadow = shadow
+
+	// An hour at $3 warms up the 1h EMA, then a one-minute spike to $6 moves
+	// the spot price above the limit while the average stays below it
+	prices := make([]float64, 0, 72)
+	for i := 0; i < 70; i++ {
+		prices = append(prices, 3)
+	}
+	prices = append(prices, 6, 3)
+	for i, price := range prices {
+		r.recordPrice(price, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	if r.state != StateMonitorOnly {
+		t.Fatalf("active logic did not act on the spike: state %s", r.state)
+	}
+	report := shadow.Report()
+	if len(report.Candidates) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	got := report.Candidates[0]
+	if got.Component != ComponentRebalancer || got.Runs != 72 || got.Divergences != 1 || got.Decisions[ShadowDecisionNone] != 72 {
+		t.Fatalf("candidate report = %+v", got)
+	}
+	want := ShadowDivergence{At: start.Add(70 * time.Minute), Active: "monitor_only", Candidate: ShadowDecisionNone}
+	if len(got.Recent) != 1 || got.Recent[0] != want {
+		t.Fatalf("divergences = %+v, want %+v", got.Recent, want)
+	}
+}
+
+func TestShadowDistributionCalendarMonth(t *testing.T) {
+	shadow := NewShadowRunner(ShadowConfig{Candidates: []string{ShadowDistributionCalendarMonth}}, time.Now())
+	rd := NewRewardDistributor(&BotConfig{})
+	rd.shadow = shadow
+	rd.lastDistribution = time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		now     time.Time
+		diverge bool
+	}{
+		{time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), false}, // both wait
+		{time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), true},   // new month, 7 days
+		{time.Date(2025, 2, 24, 0, 0, 0, 0, time.UTC), false}, // both distribute
+	}
+	var divergences int64
+	for _, tc := range testCases {
+		rd.shadowTrigger(rd.shouldDistribute(tc.now), tc.now)
+		if tc.diverge {
+			divergences++
+		}
+		if got := shadow.Report().Candidates[0].Divergences; got != divergences {
+			t.Fatalf("%s: %d divergences, want %d", tc.now.Format(time.DateOnly), got, divergences)
+		}
+	}
+	if recent := shadow.Report().Candidates[0].Recent; recent[0].Active != ShadowDecisionNone || recent[0].Candidate != "distribute" {
+		t.Fatalf("divergence = %+v", recent[0])
+	}
+}
+
+func TestShadowRunner(t *testing.T) {
+	var nilRunner *ShadowRunner
+	nilRunner.Run(ShadowRebalancerEMA1h, "none", func() string {
+		t.Fatal("nil runner ran a candidate")
+		return ""
+	}, time.Now())
+	if NewShadowRunner(ShadowConfig{}, time.Now()) != nil {
+		t.Fatal("runner created without candidates")
+	}
+
+	start := time.Now()
+	s := NewShadowRunner(ShadowConfig{Candidates: []string{ShadowRebalancerEMA1h}}, start)
+	s.Run(ShadowDistributionCalendarMonth, "none", func() string {
+		t.Fatal("disabled candidate ran")
+		return ""
+	}, start)
+	s.Run(ShadowRebalancerEMA1h, "none", func() string { panic("boom") }, start)
+	for i := 0; i < MaxShadowDivergences+5; i++ {
+		s.Run(ShadowRebalancerEMA1h, "none", func() string { return "monitor_only" }, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	got := s.Report().Candidates[0]
+	if got.Runs != MaxShadowDivergences+6 || got.Failures != 1 || got.Divergences != MaxShadowDivergences+5 {
+		t.Fatalf("report = %+v", got)
+	}
+	if len(got.Recent) != MaxShadowDivergences || !got.Recent[0].At.Equal(start.Add(5*time.Minute)) {
+		t.Fatalf("recent divergences = %+v", got.Recent)
+	}
+	if section := s.Report().DigestSection(); !strings.Contains(section, "rebalancer_ema1h_threshold (rebalancer): 25 of 26 decisions diverged, 1 failed") {
+		t.Fatalf("digest section = %q", section)
+	}
+
+	s.Reset(start.Add(time.Hour))
+	if report := s.Report(); !report.Since.Equal(start.Add(time.Hour)) || report.Candidates[0].Runs != 0 {
+		t.Fatalf("report after reset = %+v", report)
+	}
+
+	if err := ValidateShadow(ShadowConfig{Candidates: []string{"rebalancer_magic"}}); err == nil {
+		t.Fatal("unknown candidate accepted")
+	}
+	if err := ValidateShadow(ShadowConfig{Candidates: []string{ShadowRebalancerEMA1h, ShadowRebalancerEMA1h}}); err == nil {
+		t.Fatal("duplicate candidate accepted")
+	}
+}
+
+func TestStatusServerShadow(t *testing.T) {
+	s := newTestStatusServer(StatusServerConfig{})
+	handler := s.Handler()
+
+	if got := statusRequest(t, handler, http.MethodGet, "/shadow", ""); got != http.StatusServiceUnavailable {
+		t.Fatalf("shadow without candidates = %d", got)
+	}
+	if s.bs.weeklyDigest(time.Now()) != nil {
+		t.Fatal("weekly digest without sections")
+	}
+
+	s.bs.shadow = NewShadowRunner(ShadowConfig{Candidates: []string{ShadowDistributionCalendarMonth}}, time.Now())
+	s.bs.shadow.Run(ShadowDistributionCalendarMonth, ShadowDecisionNone, func() string { return "distribute" }, time.Now())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/shadow", nil))
+	var report ShadowReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("shadow = %d %s", rec.Code, rec.Body)
+	}
+	if len(report.Candidates) != 1 || report.Candidates[0].Divergences != 1 {
+		t.Fatalf("shadow = %+v", report)
+	}
+
+	digest := s.bs.weeklyDigest(time.Now())
+	if digest == nil || !strings.Contains(digest.Message, "distribution_calendar_month (reward_distributor): 1 of 1 decisions diverged") {
+		t.Fatalf("weekly digest = %+v", digest)
+	}
+}
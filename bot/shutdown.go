@@ -0,0 +1,587 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// InterruptedIntentsFile keeps the work a shutdown could not finish in the data directory
+	InterruptedIntentsFile = "interrupted_intents.json"
+	// DefaultDrainDeadline is how long shutdown waits for a component's in-flight work
+	DefaultDrainDeadline = 10 * time.Second
+	// DrainProgressInterval is how often a draining component logs what it still waits for
+	DrainProgressInterval = 2 * time.Second
+)
+
+// Drained components besides the ones alerts are correlated by
+const (
+	ComponentBroadcast = "broadcast"
+	ComponentAlerts    = "alerts"
+)
+
+// ErrShuttingDown is returned for work offered after shutdown began
+var ErrShuttingDown = errors.New("bot is shutting down")
+
+// ShutdownConfig bounds how long shutdown drains each component
+type ShutdownConfig struct {
+	// Drain deadline per component: broadcast, alerts, ibc_relayer and
+	// dex_manager (default 10s each). The alerts are drained last, after the
+	// other components, so both stages share the shutdown timeout.
+	DrainDeadlines map[string]time.Duration `yaml:"drain_deadlines"`
+}
+
+// Deadline returns the drain deadline of a component
+func (c ShutdownConfig) Deadline(component string) time.Duration {
+	if d := c.DrainDeadlines[component]; d > 0 {
+		return d
+	}
+	return DefaultDrainDeadline
+}
+
+// ValidateShutdown checks the drain deadlines
+func ValidateShutdown(cfg ShutdownConfig) error {
+	longest := DefaultDrainDeadline
+	for component, deadline := range cfg.DrainDeadlines {
+		if deadline < 0 {
+			return fmt.Errorf("shutdown.drain_deadlines.%s cannot be negative", component)
+		}
+		if component != ComponentAlerts && deadline > longest {
+			longest = deadline
+		}
+	}
+	if total := longest + cfg.Deadline(ComponentAlerts); total > ShutdownTimeout {
+		return fmt.Errorf("shutdown drain deadlines take up to %s, more than the %s shutdown timeout", total, ShutdownTimeout)
+	}
+	return nil
+}
+
+// Intent is work that was in flight or queued when the bot stopped. The
+// owning component resumes or reconciles it on the next start.
+type Intent struct {
+	Component     string          `json:"component"`
+	Kind          string          `json:"kind"`
+	Description   string          `json:"description"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	StartedAt     time.Time       `json:"started_at"`
+	InterruptedAt time.Time       `json:"interrupted_at"`
+}
+
+// Decode unmarshals the payload of the intent into v
+func (i Intent) Decode(v interface{}) error {
+	if len(i.Payload) == 0 {
+		return fmt.Errorf("%s intent has no payload", i.Kind)
+	}
+	return json.Unmarshal(i.Payload, v)
+}
+
+// Operation is a unit of in-flight work drained on shutdown
+type Operation struct {
+	id      uint64
+	tracker *DrainTracker
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	mu     sync.Mutex
+	intent Intent
+}
+
+// Context is the context of the operation. It is not cancelled when the bot
+// stops its loops, only when the component's drain deadline passes.
+func (op *Operation) Context() context.Context {
+	if op == nil {
+		return context.Background()
+	}
+	return op.ctx
+}
+
+// Update replaces what is recorded if the operation is interrupted, as it
+// progresses through its steps
+func (op *Operation) Update(description string, payload interface{}) {
+	if op == nil || op.tracker == nil {
+		return
+	}
+	raw := marshalPayload(payload)
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.intent.Description = description
+	op.intent.Payload = raw
+}
+
+// Done ends the operation
+func (op *Operation) Done() {
+	if op == nil || op.tracker == nil {
+		return
+	}
+	op.cancel()
+	op.tracker.done(op)
+}
+
+// snapshot returns the intent recorded if the operation is interrupted
+func (op *Operation) snapshot(now time.Time) Intent {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	intent := op.intent
+	intent.InterruptedAt = now
+	return intent
+}
+
+// DrainTracker tracks the in-flight work of one component. A nil tracker
+// accepts all work and tracks nothing.
+type DrainTracker struct {
+	component string
+	journal   *IntentJournal
+	ctx       context.Context // cancelled when the drain deadline passes
+	cancel    context.CancelFunc
+
+	mu       sync.Mutex
+	closed   bool
+	nextID   uint64
+	inFlight map[uint64]*Operation
+	changed  chan struct{} // closed and replaced whenever an operation ends
+}
+
+func newDrainTracker(component string, journal *IntentJournal) *DrainTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &DrainTracker{
+		component: component,
+		journal:   journal,
+		ctx:       ctx,
+		cancel:    cancel,
+		inFlight:  make(map[uint64]*Operation),
+		changed:   make(chan struct{}),
+	}
+}
+
+// Begin starts an operation, or returns ErrShuttingDown once the component
+// stopped taking new work. payload is what is recorded if the operation is
+// still running at the drain deadline. The operation's context carries the
+// values of parent but not its cancellation.
+func (t *DrainTracker) Begin(parent context.Context, kind, description string, payload interface{}) (*Operation, error) {
+	if t == nil {
+		return &Operation{ctx: parent}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	stop := context.AfterFunc(t.ctx, cancel)
+	op := &Operation{
+		tracker: t,
+		ctx:     ctx,
+		cancel:  func() { stop(); cancel() },
+		intent: Intent{
+			Component:   t.component,
+			Kind:        kind,
+			Description: description,
+			Payload:     marshalPayload(payload),
+			StartedAt:   time.Now(),
+		},
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		op.cancel()
+		return nil, ErrShuttingDown
+	}
+	t.nextID++
+	op.id = t.nextID
+	t.inFlight[op.id] = op
+	return op, nil
+}
+
+// Closed reports whether the component stopped taking new work
+func (t *DrainTracker) Closed() bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// Record keeps queued work that will not be started before the bot stops,
+// so that the component picks it up on the next start
+func (t *DrainTracker) Record(kind, description string, payload interface{}) {
+	if t == nil {
+		return
+	}
+	t.journal.Record(Intent{
+		Component:     t.component,
+		Kind:          kind,
+		Description:   description,
+		Payload:       marshalPayload(payload),
+		InterruptedAt: time.Now(),
+	})
+}
+
+// close stops taking new work
+func (t *DrainTracker) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+}
+
+func (t *DrainTracker) done(op *Operation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.inFlight[op.id]; !ok {
+		return
+	}
+	delete(t.inFlight, op.id)
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// pending returns the number of operations in flight, their descriptions and
+// a channel closed when one of them ends
+func (t *DrainTracker) pending() (int, []string, <-chan struct{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	descriptions := make([]string, 0, len(t.inFlight))
+	for _, op := range t.inFlight {
+		op.mu.Lock()
+		descriptions = append(descriptions, op.intent.Description)
+		op.mu.Unlock()
+	}
+	sort.Strings(descriptions)
+	return len(t.inFlight), descriptions, t.changed
+}
+
+// drain waits until the in-flight work completed or the deadline passed,
+// logging its progress. Work still running at the deadline is recorded as
+// interrupted and its context cancelled.
+func (t *DrainTracker) drain(deadline time.Duration) DrainResult {
+	start := time.Now()
+	result := DrainResult{Component: t.component, Deadline: deadline}
+
+	count, descriptions, changed := t.pending()
+	if count == 0 {
+		t.cancel()
+		return result
+	}
+	log.Printf("Draining %s: waiting up to %s for %d in-flight operations (%s)",
+		t.component, deadline, count, strings.Join(descriptions, ", "))
+
+	timeout := time.NewTimer(deadline)
+	defer timeout.Stop()
+	progress := time.NewTicker(DrainProgressInterval)
+	defer progress.Stop()
+
+	for count > 0 {
+		select {
+		case <-changed:
+			left, rest, next := t.pending()
+			result.Completed += count - left
+			count, descriptions, changed = left, rest, next
+			if count > 0 {
+				log.Printf("Draining %s: %d completed, %d still in flight", t.component, result.Completed, count)
+			}
+		case <-progress.C:
+			log.Printf("Draining %s: still waiting after %s for %s",
+				t.component, time.Since(start).Round(time.Millisecond), strings.Join(descriptions, ", "))
+		case <-timeout.C:
+			result.Interrupted = t.interrupt()
+			count = 0
+		}
+	}
+	t.cancel()
+
+	result.Elapsed = time.Since(start)
+	if result.Interrupted > 0 {
+		log.Printf("Drain deadline of %s passed for %s: %d operations completed, %d recorded as interrupted",
+			deadline, t.component, result.Completed, result.Interrupted)
+	} else {
+		log.Printf("Drained %s in %s: %d operations completed",
+			t.component, result.Elapsed.Round(time.Millisecond), result.Completed)
+	}
+	return result
+}
+
+// interrupt records the operations still in flight as interrupted intents
+func (t *DrainTracker) interrupt() int {
+	t.mu.Lock()
+	ops := make([]*Operation, 0, len(t.inFlight))
+	for _, op := range t.inFlight {
+		ops = append(ops, op)
+	}
+	t.inFlight = make(map[uint64]*Operation)
+	t.mu.Unlock()
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].id < ops[j].id })
+	now := time.Now()
+	intents := make([]Intent, len(ops))
+	for i, op := range ops {
+		intents[i] = op.snapshot(now)
+		log.Printf("Interrupted %s %s: %s", t.component, intents[i].Kind, intents[i].Description)
+	}
+	t.journal.Record(intents...)
+	return len(ops)
+}
+
+// DrainResult is the outcome of draining one component
+type DrainResult struct {
+	Component   string
+	Deadline    time.Duration
+	Completed   int
+	Interrupted int
+	Elapsed     time.Duration
+}
+
+// Shutdown coordinates the two phases of stopping the bot: first the
+// components stop taking new work, then their in-flight work is drained,
+// each up to its own deadline. Work that cannot finish in time is journaled
+// as interrupted intents instead of being lost.
+type Shutdown struct {
+	config  ShutdownConfig
+	journal *IntentJournal
+
+	mu       sync.Mutex
+	trackers map[string]*DrainTracker
+}
+
+// NewShutdown creates the coordinator and loads the intents interrupted by
+// the previous run from dataDir
+func NewShutdown(config ShutdownConfig, dataDir string) *Shutdown {
+	return &Shutdown{
+		config:   config,
+		journal:  NewIntentJournal(dataDir),
+		trackers: make(map[string]*DrainTracker),
+	}
+}
+
+// Tracker returns the drain tracker of a component, nil without a
+// coordinator
+func (s *Shutdown) Tracker(component string) *DrainTracker {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tracker, ok := s.trackers[component]
+	if !ok {
+		tracker = newDrainTracker(component, s.journal)
+		s.trackers[component] = tracker
+	}
+	return tracker
+}
+
+// Close is phase one for the given components: they stop taking new work
+func (s *Shutdown) Close(components ...string) {
+	if s == nil {
+		return
+	}
+	for _, component := range components {
+		s.Tracker(component).close()
+	}
+}
+
+// Drain is phase two for the given components: their in-flight work is
+// drained concurrently, each up to its deadline. Components not closed yet
+// are closed first.
+func (s *Shutdown) Drain(components ...string) []DrainResult {
+	if s == nil {
+		return nil
+	}
+	s.Close(components...)
+
+	results := make([]DrainResult, len(components))
+	var wg sync.WaitGroup
+	for i, component := range components {
+		wg.Add(1)
+		go func(i int, tracker *DrainTracker) {
+			defer wg.Done()
+			results[i] = tracker.drain(s.config.Deadline(tracker.component))
+		}(i, s.Tracker(component))
+	}
+	wg.Wait()
+	return results
+}
+
+// Interrupted takes the intents a component left when the bot last stopped
+func (s *Shutdown) Interrupted(component string) []Intent {
+	if s == nil {
+		return nil
+	}
+	return s.journal.Take(component)
+}
+
+// PendingIntents returns the interrupted intents not taken yet
+func (s *Shutdown) PendingIntents() []Intent {
+	if s == nil {
+		return nil
+	}
+	return s.journal.Intents()
+}
+
+// drainedComponents are drained first in phase two; the alerts follow, so
+// that what the components report while draining is still sent
+var drainedComponents = []string{ComponentBroadcast, ComponentIBCRelayer, ComponentDEXManager}
+
+// BeginShutdown is phase one of stopping the bot: the components stop taking
+// new work. It precedes the cancellation of the loops, so that no loop
+// starts work that shutdown would not wait for.
+func (bs *BotService) BeginShutdown() {
+	bs.shutdown.Close(drainedComponents...)
+}
+
+// attachDrainTrackers hands the components their drain trackers and resumes
+// the work the last shutdown interrupted
+func (bs *BotService) attachDrainTrackers() {
+	alerts := bs.shutdown.Tracker(ComponentAlerts)
+	for _, ta := range []*TelegramAlert{bs.telegramAlert, bs.rebalancer.telegramAlert, bs.validatorMonitor.telegramAlert} {
+		if ta != nil {
+			ta.drain = alerts
+		}
+	}
+	if bs.telegramAlert != nil && bs.telegramAlert.IsRunning() {
+		bs.telegramAlert.Resume(bs.shutdown.Interrupted(ComponentAlerts))
+	}
+
+	if bs.ibcRelayer != nil {
+		bs.ibcRelayer.drain = bs.shutdown.Tracker(ComponentIBCRelayer)
+		bs.ibcRelayer.Resume(bs.shutdown.Interrupted(ComponentIBCRelayer))
+	}
+
+	if bs.dexManager != nil {
+		bs.dexManager.drain = bs.shutdown.Tracker(ComponentDEXManager)
+		refills := bs.dexManager.Resume(bs.shutdown.Interrupted(ComponentDEXManager))
+		if len(refills) > 0 && bs.telegramAlert != nil {
+			lines := make([]string, len(refills))
+			for i, refill := range refills {
+				lines[i] = fmt.Sprintf("%s: %dugen to %s (cycle %d)", refill.Pool, refill.Amount, refill.Address, refill.Cycle)
+			}
+			message := "Shutdown interrupted these refills. They are counted as distributed and not retried, verify that the transfers landed:\n" +
+				strings.Join(lines, "\n")
+			bs.telegramAlert.SendAlertWithType(AlertTypeWarning, "Interrupted DEX Refills", message)
+		}
+	}
+
+	// Intents of components disabled in this run stay for a later start
+	if pending := bs.shutdown.PendingIntents(); len(pending) > 0 {
+		log.Printf("Keeping %d interrupted intents of disabled components in %s", len(pending), InterruptedIntentsFile)
+	}
+}
+
+// IntentJournal persists interrupted intents across restarts. An empty
+// dataDir keeps them in memory.
+type IntentJournal struct {
+	path string
+
+	mu      sync.Mutex
+	intents []Intent
+}
+
+// NewIntentJournal opens the journal in dataDir
+func NewIntentJournal(dataDir string) *IntentJournal {
+	j := &IntentJournal{}
+	if dataDir == "" {
+		return j
+	}
+
+	j.path = filepath.Join(dataDir, InterruptedIntentsFile)
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		return j
+	}
+	if err := json.Unmarshal(data, &j.intents); err != nil {
+		log.Printf("Ignoring unreadable interrupted intents %s: %v", j.path, err)
+		j.intents = nil
+	}
+	if len(j.intents) > 0 {
+		log.Printf("Loaded %d interrupted intents from %s", len(j.intents), j.path)
+	}
+	return j
+}
+
+// Record adds intents and persists the journal
+func (j *IntentJournal) Record(intents ...Intent) {
+	if len(intents) == 0 {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.intents = append(j.intents, intents...)
+	if err := j.saveLocked(); err != nil {
+		log.Printf("Failed to persist interrupted intents: %v", err)
+	}
+}
+
+// Take removes and returns the intents of a component
+func (j *IntentJournal) Take(component string) []Intent {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var taken []Intent
+	kept := j.intents[:0]
+	for _, intent := range j.intents {
+		if intent.Component == component {
+			taken = append(taken, intent)
+		} else {
+			kept = append(kept, intent)
+		}
+	}
+	j.intents = kept
+	if len(taken) > 0 {
+		if err := j.saveLocked(); err != nil {
+			log.Printf("Failed to persist interrupted intents: %v", err)
+		}
+	}
+	return taken
+}
+
+// Intents returns a copy of the journaled intents
+func (j *IntentJournal) Intents() []Intent {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]Intent(nil), j.intents...)
+}
+
+// saveLocked writes the journal to disk, removing it once empty
+func (j *IntentJournal) saveLocked() error {
+	if j.path == "" {
+		return nil
+	}
+	if len(j.intents) == 0 {
+		if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(j.intents, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
+
+// marshalPayload encodes an intent payload, logging what cannot be encoded
+func marshalPayload(payload interface{}) json.RawMessage {
+	if payload == nil {
+		return nil
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to encode intent payload: %v", err)
+		return nil
+	}
+	return raw
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingBroadcaster holds every broadcast until released or its context ends
+type blockingBroadcaster struct {
+	started chan BotTx
+	release chan struct{}
+
+	mu   sync.Mutex
+	sent []BotTx
+}
+
+func newBlockingBroadcaster() *blockingBroadcaster {
+	return &blockingBroadcaster{started: make(chan BotTx, 10), release: make(chan struct{})}
+}
+
+func (b *blockingBroadcaster) Broadcast(ctx context.Context, tx BotTx) error {
+	b.started <- tx
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+// drainResult returns the result of draining one component
+func drainResult(t *testing.T, results []DrainResult, component string) DrainResult {
+	t.Helper()
+	for _, result := range results {
+		if result.Component == component {
+			return result
+		}
+	}
+	t.Fatalf("no drain result for %s in %+v", component, results)
+	return DrainResult{}
+}
+
+func TestShutdownDrainsInFlightBroadcast(t *testing.T) {
+	shutdown := NewShutdown(ShutdownConfig{}, t.TempDir())
+	node := newBlockingBroadcaster()
+	queue := NewBroadcastQueue(node, nil, t.TempDir(), time.Hour)
+	queue.drain = shutdown.Tracker(ComponentBroadcast)
+
+	// The loops' context is cancelled at shutdown, the broadcast goes on
+	ctx, cancel := context.WithCancel(context.Background())
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- queue.Submit(ctx, BotTx{Kind: TxKindSlashingReport, Validator: "gxrvaloper1late"}, time.Now())
+	}()
+	<-node.started
+
+	shutdown.Close(ComponentBroadcast)
+	cancel()
+	if err := queue.Submit(ctx, BotTx{Kind: TxKindHeartbeat, Validator: "gxrvaloper1late"}, time.Now()); !errors.Is(err, ErrShuttingDown) {
+		t.Fatalf("submit during shutdown = %v, want ErrShuttingDown", err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(node.release)
+	}()
+	result := drainResult(t, shutdown.Drain(ComponentBroadcast), ComponentBroadcast)
+	if result.Completed != 1 || result.Interrupted != 0 {
+		t.Fatalf("drain result = %+v", result)
+	}
+	if err := <-submitted; err != nil {
+		t.Fatalf("in-flight broadcast failed: %v", err)
+	}
+	if len(node.sent) != 1 || node.sent[0].Kind != TxKindSlashingReport {
+		t.Fatalf("sent = %+v", node.sent)
+	}
+
+	// The heartbeat offered after shutdown began is kept, not broadcast
+	pending := queue.Pending()
+	if len(pending) != 1 || pending[0].Kind != TxKindHeartbeat || pending[0].Attempts != 0 {
+		t.Fatalf("pending = %+v", pending)
+	}
+	if len(shutdown.PendingIntents()) != 0 {
+		t.Fatalf("completed work recorded as interrupted: %+v", shutdown.PendingIntents())
+	}
+}
+
+func TestShutdownRecordsInterruptedBroadcast(t *testing.T) {
+	dataDir := t.TempDir()
+	shutdown := NewShutdown(ShutdownConfig{DrainDeadlines: map[string]time.Duration{ComponentBroadcast: 50 * time.Millisecond}}, dataDir)
+	node := newBlockingBroadcaster()
+	queue := NewBroadcastQueue(node, nil, "", time.Hour)
+	queue.drain = shutdown.Tracker(ComponentBroadcast)
+
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- queue.Submit(context.Background(), BotTx{ID: "report-1", Kind: TxKindSlashingReport, Validator: "gxrvaloper1late"}, time.Now())
+	}()
+	<-node.started
+
+	result := drainResult(t, shutdown.Drain(ComponentBroadcast), ComponentBroadcast)
+	if result.Completed != 0 || result.Interrupted != 1 {
+		t.Fatalf("drain result = %+v", result)
+	}
+	// The broadcast sees its context cancelled at the deadline
+	if err := <-submitted; !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted broadcast returned %v", err)
+	}
+
+	// The next start resumes the transaction from the journal
+	next := NewShutdown(ShutdownConfig{}, dataDir)
+	intents := next.Interrupted(ComponentBroadcast)
+	if len(intents) != 1 || intents[0].Kind != "broadcast" || intents[0].Description != "report-1" {
+		t.Fatalf("intents = %+v", intents)
+	}
+	resumed := NewBroadcastQueue(&fakeBroadcaster{}, nil, "", time.Hour)
+	now := time.Now()
+	resumed.Resume(intents, now)
+	pending := resumed.Pending()
+	if len(pending) != 1 || pending[0].ID != "report-1" || pending[0].Kind != TxKindSlashingReport || !pending[0].NextAttempt.Equal(now) {
+		t.Fatalf("resumed = %+v", pending)
+	}
+
+	// Taken intents are gone from the journal
+	if intents := NewShutdown(ShutdownConfig{}, dataDir).Interrupted(ComponentBroadcast); len(intents) != 0 {
+		t.Fatalf("intents taken twice: %+v", intents)
+	}
+}
+
+func TestShutdownDrainsAlertBeingRetried(t *testing.T) {
+	var mu sync.Mutex
+	down := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if down {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"ok":false,"error_code":502,"description":"Bad Gateway"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	shutdown := NewShutdown(ShutdownConfig{}, t.TempDir())
+	ta := &TelegramAlert{
+		config:         &BotConfig{},
+		client:         server.Client(),
+		apiURL:         server.URL,
+		chatID:         "-100100",
+		maxRetries:     5,
+		retryDelay:     20 * time.Millisecond,
+		maxMessageSize: MessageSizeLimit,
+		alertCounts:    make(map[AlertType]int64),
+		alertQueue:     make(chan *Alert, 10),
+		stopChan:       make(chan struct{}),
+		running:        true,
+		sendToTelegram: true,
+		drain:          shutdown.Tracker(ComponentAlerts),
+	}
+	go ta.processAlerts()
+
+	if err := ta.SendBotAlert("GXR Bot", "stopped", "Bot service stopped"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Telegram recovers while the alert is retried during the drain
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		mu.Lock()
+		down = false
+		mu.Unlock()
+	}()
+	result := drainResult(t, shutdown.Drain(ComponentAlerts), ComponentAlerts)
+	if result.Completed != 1 || result.Interrupted != 0 {
+		t.Fatalf("drain result = %+v", result)
+	}
+	if err := ta.SendAlert("late"); !errors.Is(err, ErrShuttingDown) {
+		t.Fatalf("alert after the drain = %v, want ErrShuttingDown", err)
+	}
+	ta.Stop()
+	if stats := ta.GetStatistics(); stats["successful_alerts"].(int64) != 1 {
+		t.Fatalf("alert not delivered: %v", stats)
+	}
+}
+
+func TestShutdownRecordsUndeliveredAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"ok":false,"error_code":502,"description":"Bad Gateway"}`))
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	shutdown := NewShutdown(ShutdownConfig{DrainDeadlines: map[string]time.Duration{ComponentAlerts: 100 * time.Millisecond}}, dataDir)
+	ta := &TelegramAlert{
+		config:         &BotConfig{},
+		client:         server.Client(),
+		apiURL:         server.URL,
+		chatID:         "-100100",
+		maxRetries:     3,
+		retryDelay:     time.Hour,
+		maxMessageSize: MessageSizeLimit,
+		alertCounts:    make(map[AlertType]int64),
+		alertQueue:     make(chan *Alert, 10),
+		stopChan:       make(chan struct{}),
+		running:        true,
+		sendToTelegram: true,
+		drain:          shutdown.Tracker(ComponentAlerts),
+	}
+	go ta.processAlerts()
+
+	// One alert waits for its retry, the other behind it in the queue
+	ta.SendBotAlert("GXR Bot", "error", "first")
+	ta.SendBotAlert("GXR Bot", "stopped", "second")
+
+	result := drainResult(t, shutdown.Drain(ComponentAlerts), ComponentAlerts)
+	ta.Stop()
+	if result.Interrupted != 2 {
+		t.Fatalf("drain result = %+v", result)
+	}
+
+	// Both are queued again on the next start
+	next := NewShutdown(ShutdownConfig{}, dataDir)
+	resumed := &TelegramAlert{alertQueue: make(chan *Alert, 10), running: true}
+	resumed.Resume(next.Interrupted(ComponentAlerts))
+	if len(resumed.alertQueue) != 2 {
+		t.Fatalf("%d alerts resumed, want 2", len(resumed.alertQueue))
+	}
+	for _, want := range []string{"first", "second"} {
+		if alert := <-resumed.alertQueue; !strings.Contains(alert.Message, want) {
+			t.Fatalf("resumed alert = %+v, want %q", alert, want)
+		}
+	}
+}
+
+func TestShutdownResumesIBCPacketAfterRecv(t *testing.T) {
+	dataDir := t.TempDir()
+	shutdown := NewShutdown(ShutdownConfig{DrainDeadlines: map[string]time.Duration{ComponentIBCRelayer: 50 * time.Millisecond}}, dataDir)
+
+	// The recv goes through, the ack hangs until the drain deadline
+	acking := make(chan struct{})
+	relayer := NewIBCRelayer(&BotConfig{})
+	relayer.connectionHealth["channel-0"] = true
+	relayer.drain = shutdown.Tracker(ComponentIBCRelayer)
+	relayer.relayStep = func(ctx context.Context, packet IBCPacket, step string) error {
+		if step == "recv" {
+			return nil
+		}
+		close(acking)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	relayer.packetQueue = []IBCPacket{
+		{ChannelID: "channel-0", Sequence: 7, MaxRetries: 3},
+		{ChannelID: "channel-0", Sequence: 8, MaxRetries: 3},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	processed := make(chan struct{})
+	go func() {
+		relayer.processPacketQueue(ctx)
+		close(processed)
+	}()
+	<-acking
+
+	shutdown.Close(ComponentIBCRelayer)
+	cancel()
+	relayer.Stop()
+	result := drainResult(t, shutdown.Drain(ComponentIBCRelayer), ComponentIBCRelayer)
+	<-processed
+	if result.Interrupted != 1 {
+		t.Fatalf("drain result = %+v", result)
+	}
+
+	// Both packets are resumed once, the first with only its ack left
+	next := NewShutdown(ShutdownConfig{}, dataDir)
+	resumed := NewIBCRelayer(&BotConfig{})
+	resumed.connectionHealth["channel-0"] = true
+	resumed.Resume(next.Interrupted(ComponentIBCRelayer))
+	if len(resumed.packetQueue) != 2 || !resumed.packetQueue[0].RecvRelayed || resumed.packetQueue[1].RecvRelayed {
+		t.Fatalf("resumed packets = %+v", resumed.packetQueue)
+	}
+
+	var steps []string
+	resumed.relayStep = func(_ context.Context, packet IBCPacket, step string) error {
+		steps = append(steps, packet.describe()+" "+step)
+		return nil
+	}
+	resumed.processPacketQueue(context.Background())
+	if strings.Join(steps, ", ") != "channel-0/7 ack, channel-0/8 recv, channel-0/8 ack" {
+		t.Fatalf("relayed %v", steps)
+	}
+}
+
+func TestShutdownRecordsQueuedIBCPackets(t *testing.T) {
+	dataDir := t.TempDir()
+	shutdown := NewShutdown(ShutdownConfig{}, dataDir)
+	relayer := NewIBCRelayer(&BotConfig{})
+	relayer.drain = shutdown.Tracker(ComponentIBCRelayer)
+	relayer.packetQueue = []IBCPacket{{ChannelID: "channel-1", Sequence: 3, MaxRetries: 3}}
+
+	shutdown.Close(ComponentIBCRelayer)
+	relayer.Stop()
+	if result := drainResult(t, shutdown.Drain(ComponentIBCRelayer), ComponentIBCRelayer); result.Completed != 0 || result.Interrupted != 0 {
+		t.Fatalf("drain result = %+v", result)
+	}
+
+	// Nothing is relayed after shutdown began
+	relayer.processPacketQueue(context.Background())
+
+	resumed := NewIBCRelayer(&BotConfig{})
+	resumed.Resume(NewShutdown(ShutdownConfig{}, dataDir).Interrupted(ComponentIBCRelayer))
+	if len(resumed.packetQueue) != 1 || resumed.packetQueue[0].describe() != "channel-1/3" {
+		t.Fatalf("resumed packets = %+v", resumed.packetQueue)
+	}
+}
+
+func TestShutdownReconcilesInterruptedRefill(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gxr/halving/v1beta1/dex_distribution_balance" {
+			w.Write([]byte(`{"address":"gxr1dexdistribution","balance":{"denom":"ugen","amount":"1000"},` +
+				`"pending":{"denom":"ugen","amount":"1000"}}`))
+			return
+		}
+		w.Write([]byte(`{"pending_dex_allocation":{"cycle":"2","amount":{"denom":"ugen","amount":"1000"},` +
+			`"total_allocated":{"denom":"ugen","amount":"3000"},"months":"3","last_updated":"1735689600"}}`))
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	shutdown := NewShutdown(ShutdownConfig{DrainDeadlines: map[string]time.Duration{ComponentDEXManager: 50 * time.Millisecond}}, dataDir)
+
+	// The first pool's transfer hangs, the second pool is never started
+	transferring := make(chan struct{})
+	dm := NewDEXManager(&BotConfig{ChainAPI: server.URL})
+	dm.drain = shutdown.Tracker(ComponentDEXManager)
+	dm.pools["GXR/POLYGON"] = &DEXPool{Name: "GXR/POLYGON", Address: "gxr1dexpool1polygon", Active: true}
+	dm.pools["GXR/TON"] = &DEXPool{Name: "GXR/TON", Address: "gxr1dexpool1ton", Active: true}
+	var transfers []string
+	dm.transfer = func(ctx context.Context, pool *DEXPool, amount int64) error {
+		transfers = append(transfers, pool.Name)
+		close(transferring)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	managed := make(chan struct{})
+	go func() {
+		dm.managePools(ctx)
+		close(managed)
+	}()
+	<-transferring
+
+	shutdown.Close(ComponentDEXManager)
+	cancel()
+	result := drainResult(t, shutdown.Drain(ComponentDEXManager), ComponentDEXManager)
+	<-managed
+	if result.Interrupted != 1 || len(transfers) != 1 {
+		t.Fatalf("drain result = %+v, transfers %v", result, transfers)
+	}
+
+	// The next start counts the refill as distributed instead of sending it again
+	next := NewDEXManager(&BotConfig{ChainAPI: server.URL})
+	refills := next.Resume(NewShutdown(ShutdownConfig{}, dataDir).Interrupted(ComponentDEXManager))
+	if len(refills) != 1 || refills[0].Pool != "GXR/POLYGON" || refills[0].Amount != 500 || refills[0].Cycle != 2 {
+		t.Fatalf("refills = %+v", refills)
+	}
+	available, err := next.distributableAmount(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if available != 500 {
+		t.Fatalf("available = %d, want the 500ugen not yet sent", available)
+	}
+}
+
+func TestValidateShutdown(t *testing.T) {
+	if err := ValidateShutdown(ShutdownConfig{}); err != nil {
+		t.Fatalf("defaults rejected: %v", err)
+	}
+	if err := ValidateShutdown(ShutdownConfig{DrainDeadlines: map[string]time.Duration{ComponentIBCRelayer: -time.Second}}); err == nil {
+		t.Fatal("negative deadline accepted")
+	}
+	// The alerts are drained after the other components
+	long := ShutdownConfig{DrainDeadlines: map[string]time.Duration{ComponentDEXManager: 20 * time.Second, ComponentAlerts: 15 * time.Second}}
+	if err := ValidateShutdown(long); err == nil {
+		t.Fatal("deadlines beyond the shutdown timeout accepted")
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Roles of the bots of a failover pair
+const (
+	RolePrimary = "primary"
+	RoleStandby = "standby"
+)
+
+const (
+	// HeartbeatInterval is how often the active instance sends a heartbeat
+	HeartbeatInterval = 1 * time.Minute
+	// DefaultStandbyPromoteAfter is the number of heartbeat intervals the
+	// primary may miss before the standby takes over
+	DefaultStandbyPromoteAfter = 3
+)
+
+// ErrNotActive is returned for broadcasts of an instance that must not sign:
+// an idle standby or a bot running read-only
+var ErrNotActive = errors.New("bot instance is not active, transaction not broadcast")
+
+// ValidateRole checks the failover settings
+func ValidateRole(role string, promoteAfter int) error {
+	switch role {
+	case "", RolePrimary, RoleStandby:
+	default:
+		return fmt.Errorf("role must be %s or %s, got %q", RolePrimary, RoleStandby, role)
+	}
+	if promoteAfter < 0 {
+		return fmt.Errorf("standby_promote_after cannot be negative")
+	}
+	return nil
+}
+
+// IsStandby reports whether the bot is configured as the standby of a failover pair
+func (c *BotConfig) IsStandby() bool {
+	return c.Role == RoleStandby
+}
+
+// role returns the configured role, primary unless set to standby
+func (c *BotConfig) role() string {
+	if c.IsStandby() {
+		return RoleStandby
+	}
+	return RolePrimary
+}
+
+// standbyPromoteAfter returns how long the primary's heartbeats may be missing
+// before the standby takes over
+func (c *BotConfig) standbyPromoteAfter() time.Duration {
+	missed := c.StandbyPromoteAfter
+	if missed == 0 {
+		missed = DefaultStandbyPromoteAfter
+	}
+	return time.Duration(missed) * HeartbeatInterval
+}
+
+// latestPeerHeartbeat returns the newest heartbeat in memos, newest first,
+// that was not sent by instanceID
+func latestPeerHeartbeat(memos []string, instanceID string) (string, time.Time, bool) {
+	for _, memo := range memos {
+		peer, sentAt, ok := ParseHeartbeatMemo(memo)
+		if ok && peer != instanceID {
+			return peer, sentAt, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// watchPrimary checks the primary's heartbeats every HeartbeatInterval until
+// ctx is done, promoting and demoting this standby
+func (bs *BotService) watchPrimary(ctx context.Context) {
+	ticker := NewJitteredTicker(HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			bs.checkStandby(ctx, time.Now())
+		}
+	}
+}
+
+// checkStandby looks up the latest heartbeats of the validator's bot. An idle
+// standby takes over when the primary missed StandbyPromoteAfter intervals;
+// a promoted standby steps down as soon as the primary sends one again.
+func (bs *BotService) checkStandby(ctx context.Context, now time.Time) {
+	if bs.chainQuerier == nil || bs.config.ValidatorAddress == "" {
+		return
+	}
+
+	sender, err := bs.config.TxAuthz.Sender(bs.config.ValidatorAddress)
+	var memos []string
+	if err == nil {
+		memos, err = bs.chainQuerier.QueryHeartbeatMemos(ctx, sender)
+	}
+	if err != nil {
+		// Without the chain neither instance can broadcast; keep the role
+		log.Printf("Standby check skipped: %v", err)
+		bs.recordError("standby", err.Error())
+		return
+	}
+	peer, sentAt, ok := latestPeerHeartbeat(memos, bs.instanceID)
+
+	bs.mu.Lock()
+	active, promotedAt, watchingSince := !bs.standingBy, bs.promotedAt, bs.watchingSince
+	if ok {
+		bs.lastPrimaryHeartbeat = sentAt
+	}
+	bs.mu.Unlock()
+
+	if active {
+		if ok && sentAt.After(promotedAt) {
+			bs.demote(fmt.Sprintf("instance %s is sending heartbeats again", peer))
+		}
+		return
+	}
+
+	// A primary that never sent a heartbeat is missing since the standby started
+	last := watchingSince
+	if ok && sentAt.After(last) {
+		last = sentAt
+	}
+	if missing := now.Sub(last); missing > bs.config.standbyPromoteAfter() {
+		bs.promote(ctx, now, fmt.Sprintf("no primary heartbeat for %s", missing.Round(time.Second)))
+	}
+}
+
+// promote makes this standby the active instance: it takes the validator
+// lease and starts the transaction-broadcasting components, its heartbeats
+// then claim the validator. A live lease of the primary blocks promotion.
+func (bs *BotService) promote(ctx context.Context, now time.Time, reason string) bool {
+	bs.mu.RLock()
+	readOnly := bs.readOnly
+	bs.mu.RUnlock()
+	if readOnly {
+		log.Printf("Standby not promoted, running read-only: %s", reason)
+		return false
+	}
+
+	if bs.config.ValidatorAddress != "" {
+		leaseDir := bs.config.InstanceLeaseDir
+		if leaseDir == "" {
+			leaseDir = bs.config.DataDir
+		}
+		lease, err := AcquireInstanceLeaseAs(leaseDir, bs.config.ValidatorAddress, bs.instanceID, RoleStandby, InstanceLeaseTTL, now)
+		if err != nil {
+			log.Printf("Standby not promoted (%s): %v", reason, err)
+			return false
+		}
+		bs.mu.Lock()
+		bs.instanceLease = lease
+		bs.mu.Unlock()
+	}
+
+	activeCtx, cancel := context.WithCancel(ctx)
+	bs.mu.Lock()
+	leaseHeld := bs.instanceLease != nil
+	bs.standingBy = false
+	bs.promotedAt = now
+	bs.activeCancel = cancel
+	bs.roleTransitions++
+	bs.mu.Unlock()
+
+	if bs.validatorMonitor != nil {
+		bs.validatorMonitor.mu.Lock()
+		bs.validatorMonitor.broadcastQueue = bs.broadcastQueue
+		bs.validatorMonitor.mu.Unlock()
+	}
+	if leaseHeld {
+		go bs.renewInstanceLease(activeCtx)
+	}
+	bs.startActiveComponents(activeCtx)
+
+	log.Printf("Standby promoted to active: %s", reason)
+	if bs.telegramAlert != nil {
+		bs.telegramAlert.SendEmergencyAlert("Standby Bot Promoted", reason, map[string]interface{}{
+			MetadataValidatorAddress: bs.config.ValidatorAddress,
+			"instance_id":            bs.instanceID,
+		})
+	}
+	return true
+}
+
+// demote returns a promoted standby to standing by: broadcasting stops at
+// once, the active components are cancelled and the lease is released
+func (bs *BotService) demote(reason string) {
+	bs.mu.Lock()
+	if bs.standingBy || !bs.config.IsStandby() {
+		bs.mu.Unlock()
+		return
+	}
+	bs.standingBy = true
+	bs.roleTransitions++
+	cancel := bs.activeCancel
+	bs.activeCancel = nil
+	lease := bs.instanceLease
+	bs.instanceLease = nil
+	bs.mu.Unlock()
+
+	if bs.validatorMonitor != nil {
+		bs.validatorMonitor.mu.Lock()
+		bs.validatorMonitor.broadcastQueue = nil
+		bs.validatorMonitor.mu.Unlock()
+	}
+	if cancel != nil {
+		cancel()
+	}
+	if lease != nil {
+		if err := lease.Release(); err != nil {
+			log.Printf("Failed to release instance lease: %v", err)
+		}
+	}
+
+	log.Printf("Standby stepped down: %s", reason)
+	if bs.telegramAlert != nil {
+		bs.telegramAlert.SendBotAlert("Standby", "demoted", fmt.Sprintf("Standby instance %s stepped down: %s", bs.instanceID, reason))
+	}
+}
+
+// startActiveComponents runs the transaction-broadcasting components of a
+// promoted standby until ctx is cancelled on demotion
+func (bs *BotService) startActiveComponents(ctx context.Context) {
+	run := func(name string, start func(context.Context) error) {
+		go func() {
+			if err := start(ctx); err != nil {
+				log.Printf("%s failed: %v", name, err)
+			}
+		}()
+	}
+
+	if bs.rebalancer != nil {
+		run("Rebalancer", bs.rebalancer.Start)
+	}
+	if bs.ibcRelayer != nil {
+		run("IBC relayer", bs.ibcRelayer.Start)
+	}
+	if bs.dexManager != nil {
+		run("DEX manager", bs.dexManager.Start)
+	}
+	if bs.rewardDistributor != nil {
+		run("Reward distributor", bs.rewardDistributor.Start)
+	}
+}
+
+// broadcastGate lets only the active instance sign transactions
+func (bs *BotService) broadcastGate() error {
+	if bs.isReadOnly() {
+		return ErrNotActive
+	}
+	return nil
+}
+
+// failoverStatus reports the role of this instance for the status output.
+// Callers hold bs.mu.
+func (bs *BotService) failoverStatus() map[string]interface{} {
+	status := map[string]interface{}{
+		"role":        bs.config.role(),
+		"active":      !bs.readOnly && !bs.standingBy,
+		"transitions": bs.roleTransitions,
+	}
+	if bs.config.IsStandby() {
+		status["promote_after"] = bs.config.standbyPromoteAfter().String()
+		if !bs.lastPrimaryHeartbeat.IsZero() {
+			status["last_primary_heartbeat"] = bs.lastPrimaryHeartbeat.Format(time.RFC3339)
+		}
+		if !bs.standingBy {
+			status["promoted_at"] = bs.promotedAt.Format(time.RFC3339)
+		}
+	}
+	return status
+}
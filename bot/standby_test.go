@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// failoverValidator is the validator both bots of a failover pair serve
+var failoverValidator, _ = bech32.ConvertAndEncode("gxrvaloper", append(make([]byte, 19), 7))
+
+// heartbeatChain records the heartbeats broadcast by the bots of a failover
+// pair and serves them from the tx search endpoint, newest first
+type heartbeatChain struct {
+	mu    sync.Mutex
+	memos []string
+	by    []string
+}
+
+func (c *heartbeatChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type tx struct {
+		Body struct {
+			Memo string `json:"memo"`
+		} `json:"body"`
+	}
+	var resp struct {
+		Txs []tx `json:"txs"`
+	}
+	for i := len(c.memos) - 1; i >= 0; i-- {
+		var t tx
+		t.Body.Memo = c.memos[i]
+		resp.Txs = append(resp.Txs, t)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// signer returns the broadcaster of one bot host
+func (c *heartbeatChain) signer(host string) TxBroadcaster {
+	return broadcasterFunc(func(_ context.Context, tx BotTx) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.memos = append(c.memos, tx.Memo)
+		c.by = append(c.by, host)
+		return nil
+	})
+}
+
+// since returns the hosts that broadcast after the first n transactions
+func (c *heartbeatChain) since(n int) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.by[n:]...)
+}
+
+func (c *heartbeatChain) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.by)
+}
+
+type broadcasterFunc func(ctx context.Context, tx BotTx) error
+
+func (f broadcasterFunc) Broadcast(ctx context.Context, tx BotTx) error {
+	return f(ctx, tx)
+}
+
+// newFailoverTestService returns one bot of a failover pair on host
+func newFailoverTestService(t *testing.T, chain *heartbeatChain, api, host, role, leaseDir string) *BotService {
+	t.Helper()
+	bs := &BotService{
+		config: &BotConfig{
+			ValidatorAddress: failoverValidator,
+			DataDir:          t.TempDir(),
+			InstanceLeaseDir: leaseDir,
+			Role:             role,
+		},
+		healthStatus:       make(map[string]bool),
+		healthTracker:      NewHealthTracker(0, 0),
+		protocolCompatible: true,
+		instanceID:         host + "-" + NewInstanceID(),
+		chainQuerier:       NewChainQuerierForAPI(api),
+		telegramAlert:      &TelegramAlert{config: &BotConfig{}, running: true, alertQueue: make(chan *Alert, 10)},
+		validatorMonitor:   &ValidatorMonitor{validators: make(map[string]*ValidatorStatus), botHeartbeats: make(map[string]time.Time)},
+	}
+	bs.SetBroadcaster(chain.signer(host))
+	if err := bs.AcquireInstance(context.Background(), false); err != nil {
+		t.Fatalf("%s: %v", host, err)
+	}
+	t.Cleanup(bs.releaseInstance)
+	return bs
+}
+
+// alertTitles drains the alerts queued by bs
+func alertTitles(bs *BotService) []string {
+	var titles []string
+	for {
+		select {
+		case alert := <-bs.telegramAlert.alertQueue:
+			titles = append(titles, alert.Title)
+		default:
+			return titles
+		}
+	}
+}
+
+func TestStandbyTakesOverAndStepsDown(t *testing.T) {
+	chain := &heartbeatChain{}
+	server := httptest.NewServer(chain)
+	t.Cleanup(server.Close)
+	leaseDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	primary := newFailoverTestService(t, chain, server.URL, "primary", RolePrimary, leaseDir)
+	standby := newFailoverTestService(t, chain, server.URL, "standby", RoleStandby, leaseDir)
+	// Heartbeat memos carry whole seconds
+	start := time.Now().Truncate(time.Second)
+	standby.watchingSince = start
+
+	// Every minute the primary, while up, sends its heartbeat; the standby
+	// checks the chain and sends one only while promoted
+	var returned *BotService
+	minute := func(m int, primaryUp bool) []string {
+		now := start.Add(time.Duration(m) * time.Minute)
+		before := chain.count()
+		if primaryUp {
+			primary.emitHeartbeatAt(ctx, now)
+		}
+		if returned != nil {
+			returned.emitHeartbeatAt(ctx, now)
+		}
+		standby.checkStandby(ctx, now)
+		standby.emitHeartbeatAt(ctx, now)
+		return chain.since(before)
+	}
+	expect := func(m int, broadcasters []string, host string) {
+		t.Helper()
+		if host == "" && len(broadcasters) != 0 || host != "" && (len(broadcasters) != 1 || broadcasters[0] != host) {
+			t.Fatalf("minute %d: broadcast by %v, want only %q", m, broadcasters, host)
+		}
+	}
+
+	for m := 1; m <= 3; m++ {
+		expect(m, minute(m, true), "primary")
+	}
+	if titles := alertTitles(standby); len(titles) != 0 {
+		t.Fatalf("standby alerted while the primary is up: %v", titles)
+	}
+
+	// The primary fails; the standby waits three missed intervals
+	for m := 4; m <= 6; m++ {
+		expect(m, minute(m, false), "")
+	}
+	expect(7, minute(7, false), "standby")
+	if titles := alertTitles(standby); len(titles) != 1 || titles[0] != "Standby Bot Promoted" {
+		t.Fatalf("promotion alerts = %v", titles)
+	}
+	if holder, err := readInstanceLease(leasePath(leaseDir, failoverValidator)); err != nil || holder.InstanceID != standby.instanceID {
+		t.Fatalf("promoted standby does not hold the lease: %+v, %v", holder, err)
+	}
+	for m := 8; m <= 9; m++ {
+		expect(m, minute(m, false), "standby")
+	}
+
+	// The primary host comes back: it takes over from the standby instead of
+	// refusing to start, and the standby steps down before its next heartbeat
+	returned = newFailoverTestService(t, chain, server.URL, "primary", RolePrimary, leaseDir)
+	if titles := alertTitles(returned); len(titles) != 1 || titles[0] != "Bot Status: Primary" {
+		t.Fatalf("reclaim alerts = %v", titles)
+	}
+	for m := 10; m <= 12; m++ {
+		expect(m, minute(m, false), "primary")
+	}
+	if titles := alertTitles(standby); len(titles) != 1 || titles[0] != "Bot Status: Standby" {
+		t.Fatalf("demotion alerts = %v", titles)
+	}
+	if holder, err := readInstanceLease(leasePath(leaseDir, failoverValidator)); err != nil || holder.InstanceID != returned.instanceID {
+		t.Fatalf("returned primary does not hold the lease: %+v, %v", holder, err)
+	}
+
+	status := standby.GetStatus()["failover"].(map[string]interface{})
+	if status["role"] != RoleStandby || status["active"] != false || status["transitions"] != 2 {
+		t.Fatalf("standby status = %v", status)
+	}
+}
+
+func TestStandbyNotPromotedWhilePrimaryHoldsLease(t *testing.T) {
+	chain := &heartbeatChain{}
+	server := httptest.NewServer(chain)
+	t.Cleanup(server.Close)
+	leaseDir := t.TempDir()
+
+	// The primary renews its lease but its heartbeats do not reach the chain
+	primary := newFailoverTestService(t, chain, server.URL, "primary", RolePrimary, leaseDir)
+	standby := newFailoverTestService(t, chain, server.URL, "standby", RoleStandby, leaseDir)
+	standby.watchingSince = time.Now().Add(-10 * time.Minute)
+
+	standby.checkStandby(context.Background(), time.Now())
+	if !standby.isReadOnly() || standby.emitHeartbeat(context.Background()) || chain.count() != 0 {
+		t.Fatal("standby promoted against the primary's live lease")
+	}
+	if primary.isReadOnly() {
+		t.Fatal("primary lost its role")
+	}
+
+	// Slashing reports and retries of a standby are refused as well
+	err := standby.broadcastQueue.Submit(context.Background(), BotTx{Kind: TxKindSlashingReport, Validator: failoverValidator}, time.Now())
+	if err != ErrNotActive || chain.count() != 0 {
+		t.Fatalf("standby broadcast: %v", err)
+	}
+}
+
+func TestStandbyDemotionWhileRenewingLease(t *testing.T) {
+	chain := &heartbeatChain{}
+	server := httptest.NewServer(chain)
+	t.Cleanup(server.Close)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	standby := newFailoverTestService(t, chain, server.URL, "standby", RoleStandby, t.TempDir())
+	if !standby.promote(ctx, time.Now(), "test") {
+		t.Fatal("standby not promoted")
+	}
+
+	// The renewal goroutine, a step-down and status reads race on the lease
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for !standby.checkInstanceLease(time.Now()) {
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		standby.demote("test")
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			standby.GetStatus()
+		}
+	}()
+	wg.Wait()
+
+	if status := standby.GetStatus()["instance"].(map[string]interface{}); status["lease_held"] != false {
+		t.Fatalf("demoted standby still holds the lease: %v", status)
+	}
+}
+
+func TestHeartbeatMemoRoles(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	memo := StandbyHeartbeatMemo("backup", now)
+	if id, sentAt, ok := ParseHeartbeatMemo(memo); !ok || id != "backup" || !sentAt.Equal(now) || !isStandbyHeartbeat(memo) {
+		t.Fatalf("standby memo %q parsed as %s, %v, %v", memo, id, sentAt, ok)
+	}
+	if isStandbyHeartbeat(HeartbeatMemo("main", now)) {
+		t.Fatal("primary heartbeat taken for a standby's")
+	}
+
+	// A returning primary is not refused by a promoted standby's heartbeat
+	if err := DetectHeartbeatConflict(memo, "main", "", now.Add(time.Minute)); err != nil {
+		t.Fatalf("standby heartbeat reported as conflict: %v", err)
+	}
+
+	if err := ValidateRole("secondary", 0); err == nil {
+		t.Fatal("unknown role accepted")
+	}
+	if err := ValidateRole(RoleStandby, -1); err == nil {
+		t.Fatal("negative standby_promote_after accepted")
+	}
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// MinAdminTokenLength is the shortest accepted status_server.admin_token
+	MinAdminTokenLength = 16
+	// StatusServerShutdownTimeout bounds the graceful shutdown of the status server
+	StatusServerShutdownTimeout = 5 * time.Second
+)
+
+// StatusServerConfig configures the local HTTP status server
+type StatusServerConfig struct {
+	ListenAddr string `yaml:"listen_addr"`                  // e.g. "127.0.0.1:8090", empty disables the server
+	AdminToken string `yaml:"admin_token" sensitive:"true"` // required as "Authorization: Bearer <token>" on protected routes
+	// AdminEndpoints enables the mutating /admin routes
+	AdminEndpoints bool `yaml:"admin_endpoints"`
+	// ProtectReadOnly requires the admin token on /status and /healthz too
+	ProtectReadOnly bool `yaml:"protect_read_only"`
+}
+
+// ValidateStatusServer checks the status_server settings. A token is required
+// whenever a route is protected.
+func ValidateStatusServer(cfg StatusServerConfig) error {
+	if cfg.ListenAddr == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(cfg.ListenAddr); err != nil {
+		return fmt.Errorf("invalid status_server.listen_addr: %w", err)
+	}
+
+	if cfg.AdminToken == "" {
+		if cfg.AdminEndpoints {
+			return fmt.Errorf("status_server.admin_token is required when admin_endpoints is enabled")
+		}
+		if cfg.ProtectReadOnly {
+			return fmt.Errorf("status_server.admin_token is required when protect_read_only is enabled")
+		}
+		return nil
+	}
+	if len(cfg.AdminToken) < MinAdminTokenLength {
+		return fmt.Errorf("status_server.admin_token must be at least %d characters", MinAdminTokenLength)
+	}
+
+	return nil
+}
+
+// StatusServer serves the bot status over HTTP. Read-only routes are open
+// unless protect_read_only is set; mutating /admin routes always require the
+// admin token.
+type StatusServer struct {
+	bs     *BotService
+	config StatusServerConfig
+	server *http.Server
+
+	unauthorized atomic.Int64
+}
+
+// NewStatusServer creates the status server for a bot service
+func NewStatusServer(bs *BotService, config StatusServerConfig) *StatusServer {
+	s := &StatusServer{bs: bs, config: config}
+	s.server = &http.Server{
+		Addr:              config.ListenAddr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Handler returns the routes of the status server
+func (s *StatusServer) Handler() http.Handler {
+	readOnly := func(h http.HandlerFunc) http.Handler {
+		if s.config.ProtectReadOnly {
+			return s.requireAdminToken(h)
+		}
+		return h
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/status", readOnly(s.handleStatus))
+	mux.Handle("/healthz", readOnly(s.handleHealthz))
+	mux.Handle("/monitor/export.csv", readOnly(s.handleMonitorExport))
+	mux.Handle("/scores", readOnly(s.handleScores))
+	mux.Handle("/costs", readOnly(s.handleCosts))
+	mux.Handle("/prices", readOnly(s.handlePrices))
+	mux.Handle("/shadow", readOnly(s.handleShadow))
+	mux.Handle("/validator-set/changes", readOnly(s.handleSetChanges))
+
+	if s.config.AdminEndpoints {
+		mux.Handle("/admin/pause", s.requireAdminToken(s.adminAction("price monitoring paused", s.bs.PausePriceMonitoring)))
+		mux.Handle("/admin/resume", s.requireAdminToken(s.adminAction("price monitoring resumed", s.bs.ResumePriceMonitoring)))
+		mux.Handle("/admin/dry-run/enable", s.requireAdminToken(s.adminAction("rebalancer dry run enabled", func() error {
+			return s.bs.SetRebalancerDryRun(true)
+		})))
+		mux.Handle("/admin/dry-run/disable", s.requireAdminToken(s.adminAction("rebalancer dry run disabled", func() error {
+			return s.bs.SetRebalancerDryRun(false)
+		})))
+	}
+
+	return mux
+}
+
+// requireAdminToken rejects requests without a matching bearer token with 401
+func (s *StatusServer) requireAdminToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if s.config.AdminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AdminToken)) != 1 {
+			s.unauthorized.Add(1)
+			log.Printf("Unauthorized status server request: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gxr-bot"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *StatusServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.bs.GetStatus())
+}
+
+func (s *StatusServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	unhealthy := s.bs.unhealthyComponents()
+	if len(unhealthy) > 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"healthy": false, "unhealthy": unhealthy})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"healthy": true})
+}
+
+// handleCosts serves the bot operating costs of the current month or of the
+// month given by ?month=YYYY-MM, with its daily totals
+func (s *StatusServer) handleCosts(w http.ResponseWriter, r *http.Request) {
+	if s.bs.costLedger == nil {
+		http.Error(w, "cost ledger is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	month := time.Now().UTC().Format(ReportMonthLayout)
+	if value := r.URL.Query().Get("month"); value != "" {
+		if _, err := ParseReportMonth(value); err != nil {
+			http.Error(w, "invalid month", http.StatusBadRequest)
+			return
+		}
+		month = value
+	}
+
+	totals, ok := s.bs.costLedger.Month(month)
+	if !ok {
+		http.Error(w, "no costs recorded", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"month":     month,
+		"totals":    totals,
+		"total_fee": totals.Fee(),
+		"days":      s.bs.costLedger.Days(month),
+	})
+}
+
+// handlePrices serves the rebalancer price history, limited to the last
+// ?window= (such as 1h) if given
+func (s *StatusServer) handlePrices(w http.ResponseWriter, r *http.Request) {
+	if s.bs.rebalancer == nil {
+		http.Error(w, "rebalancer is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	samples := s.bs.rebalancer.GetPriceHistory()
+	if value := r.URL.Query().Get("window"); value != "" {
+		window, err := time.ParseDuration(value)
+		if err != nil || window <= 0 {
+			http.Error(w, "invalid window", http.StatusBadRequest)
+			return
+		}
+		samples = SamplesSince(samples, time.Now().Add(-window))
+	}
+
+	average, volatility := s.bs.rebalancer.PriceStatistics()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"capacity":         s.bs.rebalancer.PriceHistoryCapacity(),
+		"average_price":    average,
+		"price_volatility": volatility,
+		"samples":          samples,
+	})
+}
+
+// handleShadow serves the comparison of the shadow mode candidates with the
+// active logic since the last weekly digest
+func (s *StatusServer) handleShadow(w http.ResponseWriter, r *http.Request) {
+	if s.bs.shadow == nil {
+		http.Error(w, "no shadow candidates enabled", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.bs.shadow.Report())
+}
+
+// handleSetChanges serves the recorded validator set changes, oldest first
+func (s *StatusServer) handleSetChanges(w http.ResponseWriter, r *http.Request) {
+	if s.bs.validatorMonitor == nil {
+		http.Error(w, "validator monitor is not running", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"changes": s.bs.validatorMonitor.GetSetChangeHistory(),
+	})
+}
+
+// monitorExportHeader is the header row of /monitor/export.csv
+var monitorExportHeader = []string{
+	"operator_address", "moniker", "status", "jailed", "inactive_days",
+	"uptime_percent", "bot_running", "reward_eligible", "forfeited_rewards_gxr",
+}
+
+// handleScores serves the monthly validator scores: the latest month, the
+// month given by ?month=, or one validator's score trend with ?validator=
+func (s *StatusServer) handleScores(w http.ResponseWriter, r *http.Request) {
+	if s.bs.validatorMonitor == nil || s.bs.validatorMonitor.GetScores() == nil {
+		http.Error(w, "validator monitor is not running", http.StatusServiceUnavailable)
+		return
+	}
+	history := s.bs.validatorMonitor.GetScores()
+	cfg := s.bs.config.Scores
+
+	if validator := r.URL.Query().Get("validator"); validator != "" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"validator": validator,
+			"trend":     history.Trend(validator),
+		})
+		return
+	}
+
+	var month uint64
+	if value := r.URL.Query().Get("month"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid month", http.StatusBadRequest)
+			return
+		}
+		month = parsed
+	}
+
+	scores, ok := history.Month(month)
+	if !ok {
+		http.Error(w, "no validator scores recorded", http.StatusNotFound)
+		return
+	}
+
+	movements := make(map[string]string, len(scores.Scores))
+	for _, score := range scores.Scores {
+		movements[score.OperatorAddress] = RankMovement(score)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"month":       scores.Month,
+		"computed_at": scores.ComputedAt,
+		"weights":     scores.Weights,
+		"formula":     ScoreFormula(scores.Weights, cfg.commissionSwing()),
+		"scores":      scores.Scores,
+		"movements":   movements,
+	})
+}
+
+// handleMonitorExport writes one CSV row per tracked validator, sorted by
+// operator address, for spreadsheets
+func (s *StatusServer) handleMonitorExport(w http.ResponseWriter, r *http.Request) {
+	if s.bs.validatorMonitor == nil {
+		http.Error(w, "validator monitor is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	statuses := s.bs.validatorMonitor.GetAllValidatorStatuses()
+	addresses := make([]string, 0, len(statuses))
+	for addr := range statuses {
+		addresses = append(addresses, addr)
+	}
+	sort.Strings(addresses)
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="validator-monitor.csv"`)
+
+	out := csv.NewWriter(w)
+	out.Write(monitorExportHeader)
+	for _, addr := range addresses {
+		status := statuses[addr]
+		out.Write([]string{
+			addr,
+			status.Moniker,
+			status.Status.String(),
+			strconv.FormatBool(status.Jailed),
+			strconv.FormatUint(status.InactiveDays, 10),
+			strconv.FormatFloat(status.MonthlyUptime, 'f', 2, 64),
+			strconv.FormatBool(status.BotRunning),
+			strconv.FormatBool(status.RewardEligible),
+			strconv.FormatFloat(status.ForfeitedRewards, 'f', -1, 64),
+		})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		log.Printf("Failed to write validator monitor export: %v", err)
+	}
+}
+
+// adminAction wraps a mutating operation as a POST-only route
+func (s *StatusServer) adminAction(done string, action func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := action(); err != nil {
+			writeJSON(w, http.StatusConflict, map[string]interface{}{"error": err.Error()})
+			return
+		}
+
+		log.Printf("Admin request from %s: %s", r.RemoteAddr, done)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": done})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+// Start listens on the configured address and serves in the background
+func (s *StatusServer) Start() error {
+	listener, err := net.Listen("tcp", s.config.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.ListenAddr, err)
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Status server stopped: %v", err)
+		}
+	}()
+
+	log.Printf("Status server listening on %s (admin endpoints: %v)", listener.Addr(), s.config.AdminEndpoints)
+	return nil
+}
+
+// Stop shuts the server down, waiting for in-flight requests
+func (s *StatusServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), StatusServerShutdownTimeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to shut down status server: %v", err)
+	}
+}
+
+// GetStatus returns the status server settings and counters
+func (s *StatusServer) GetStatus() map[string]interface{} {
+	return map[string]interface{}{
+		"listen_addr":           s.config.ListenAddr,
+		"admin_endpoints":       s.config.AdminEndpoints,
+		"protect_read_only":     s.config.ProtectReadOnly,
+		"unauthorized_requests": s.unauthorized.Load(),
+	}
+}
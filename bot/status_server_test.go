@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+const testAdminToken = "s3cret-admin-token"
+
+func newTestStatusServer(config StatusServerConfig) *StatusServer {
+	bs := &BotService{
+		config:        &BotConfig{},
+		healthStatus:  map[string]bool{"telegram_alert": true},
+		healthTracker: NewHealthTracker(0, 0),
+	}
+	return NewStatusServer(bs, config)
+}
+
+func statusRequest(t *testing.T, handler http.Handler, method, path, token string) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestStatusServerAdminToken(t *testing.T) {
+	s := newTestStatusServer(StatusServerConfig{AdminToken: testAdminToken, AdminEndpoints: true})
+	handler := s.Handler()
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"status is open", http.MethodGet, "/status", "", http.StatusOK},
+		{"healthz is open", http.MethodGet, "/healthz", "", http.StatusOK},
+		{"admin without token", http.MethodPost, "/admin/pause", "", http.StatusUnauthorized},
+		{"admin with wrong token", http.MethodPost, "/admin/pause", "s3cret-admin-tokeX", http.StatusUnauthorized},
+		{"admin with token prefix", http.MethodPost, "/admin/resume", testAdminToken[:8], http.StatusUnauthorized},
+		{"admin requires post", http.MethodGet, "/admin/pause", testAdminToken, http.StatusMethodNotAllowed},
+		// Authorized, but there is no rebalancer to pause
+		{"admin with token", http.MethodPost, "/admin/pause", testAdminToken, http.StatusConflict},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := statusRequest(t, handler, tc.method, tc.path, tc.token); got != tc.want {
+				t.Fatalf("%s %s = %d, want %d", tc.method, tc.path, got, tc.want)
+			}
+		})
+	}
+
+	if got := s.GetStatus()["unauthorized_requests"]; got != int64(3) {
+		t.Fatalf("unauthorized requests = %v, want 3", got)
+	}
+}
+
+func TestStatusServerProtectReadOnly(t *testing.T) {
+	handler := newTestStatusServer(StatusServerConfig{AdminToken: testAdminToken, ProtectReadOnly: true}).Handler()
+
+	for _, path := range []string{"/status", "/healthz"} {
+		if got := statusRequest(t, handler, http.MethodGet, path, ""); got != http.StatusUnauthorized {
+			t.Errorf("GET %s without token = %d, want 401", path, got)
+		}
+		if got := statusRequest(t, handler, http.MethodGet, path, testAdminToken); got != http.StatusOK {
+			t.Errorf("GET %s with token = %d, want 200", path, got)
+		}
+	}
+
+	// Admin endpoints are not registered unless enabled
+	if got := statusRequest(t, handler, http.MethodPost, "/admin/pause", testAdminToken); got != http.StatusNotFound {
+		t.Errorf("POST /admin/pause with admin endpoints disabled = %d, want 404", got)
+	}
+}
+
+func TestStatusServerHealthz(t *testing.T) {
+	s := newTestStatusServer(StatusServerConfig{})
+	s.bs.healthStatus["ibc_relayer"] = false
+
+	if got := statusRequest(t, s.Handler(), http.MethodGet, "/healthz", ""); got != http.StatusServiceUnavailable {
+		t.Fatalf("GET /healthz with an unhealthy component = %d, want 503", got)
+	}
+}
+
+func TestValidateStatusServer(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  StatusServerConfig
+		wantErr bool
+	}{
+		{"disabled", StatusServerConfig{AdminEndpoints: true}, false},
+		{"read-only without token", StatusServerConfig{ListenAddr: "127.0.0.1:8090"}, false},
+		{"admin endpoints without token", StatusServerConfig{ListenAddr: "127.0.0.1:8090", AdminEndpoints: true}, true},
+		{"protected read-only without token", StatusServerConfig{ListenAddr: "127.0.0.1:8090", ProtectReadOnly: true}, true},
+		{"short token", StatusServerConfig{ListenAddr: "127.0.0.1:8090", AdminEndpoints: true, AdminToken: "short"}, true},
+		{"bad address", StatusServerConfig{ListenAddr: "8090"}, true},
+		{"admin endpoints with token", StatusServerConfig{ListenAddr: ":8090", AdminEndpoints: true, AdminToken: testAdminToken}, false},
+	}
+	for _, tc := range testCases {
+		if err := ValidateStatusServer(tc.config); (err != nil) != tc.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestStatusServerMonitorExport(t *testing.T) {
+	s := newTestStatusServer(StatusServerConfig{})
+	if got := statusRequest(t, s.Handler(), http.MethodGet, "/monitor/export.csv", ""); got != http.StatusServiceUnavailable {
+		t.Fatalf("export without validator monitor = %d, want 503", got)
+	}
+
+	s.bs.validatorMonitor = &ValidatorMonitor{validators: map[string]*ValidatorStatus{
+		"gxrvaloper1b": {Moniker: "Node, \"B\"", Status: stakingtypes.Unbonded, Jailed: true, InactiveDays: 12, MonthlyUptime: 60, ForfeitedRewards: 12.5},
+		"gxrvaloper1a": {Moniker: "alpha", Status: stakingtypes.Bonded, MonthlyUptime: 99.871, BotRunning: true, RewardEligible: true},
+	}}
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/monitor/export.csv", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("export = %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		monitorExportHeader,
+		{"gxrvaloper1a", "alpha", "BOND_STATUS_BONDED", "false", "0", "99.87", "true", "true", "0"},
+		{"gxrvaloper1b", "Node, \"B\"", "BOND_STATUS_UNBONDED", "true", "12", "60.00", "false", "false", "12.5"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %q", rows)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+
+	// The export is read-only data and follows protect_read_only
+	s.config = StatusServerConfig{AdminToken: testAdminToken, ProtectReadOnly: true}
+	if got := statusRequest(t, s.Handler(), http.MethodGet, "/monitor/export.csv", ""); got != http.StatusUnauthorized {
+		t.Fatalf("protected export without token = %d, want 401", got)
+	}
+}
+
+func TestStatusServerSetChanges(t *testing.T) {
+	s := newTestStatusServer(StatusServerConfig{})
+	handler := s.Handler()
+
+	if got := statusRequest(t, handler, http.MethodGet, "/validator-set/changes", ""); got != http.StatusServiceUnavailable {
+		t.Fatalf("set changes without a validator monitor = %d", got)
+	}
+
+	s.bs.validatorMonitor = &ValidatorMonitor{setChangeHistory: []ValidatorSetDiff{
+		{Timestamp: time.Now(), Joined: []string{"alpha", "beta"}, Left: []string{"gamma"}, SetSize: 4},
+	}}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validator-set/changes", nil))
+	var body struct {
+		Changes []ValidatorSetDiff `json:"changes"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("set changes = %d %s", rec.Code, rec.Body)
+	}
+	if len(body.Changes) != 1 || len(body.Changes[0].Joined) != 2 || body.Changes[0].Left[0] != "gamma" || body.Changes[0].SetSize != 4 {
+		t.Fatalf("set changes = %+v", body.Changes)
+	}
+}
+
+func TestStatusServerPrices(t *testing.T) {
+	s := newTestStatusServer(StatusServerConfig{})
+	handler := s.Handler()
+
+	if got := statusRequest(t, handler, http.MethodGet, "/prices", ""); got != http.StatusServiceUnavailable {
+		t.Fatalf("prices without a rebalancer = %d", got)
+	}
+
+	s.bs.rebalancer = newTestRebalancer(t, &BotConfig{}, nil)
+	s.bs.rebalancer.telegramAlert = nil
+	s.bs.rebalancer.recordPrice(2.5, time.Now().Add(-2*time.Hour))
+	s.bs.rebalancer.recordPrice(3.5, time.Now().Add(-time.Minute))
+	if got := statusRequest(t, handler, http.MethodGet, "/prices?window=soon", ""); got != http.StatusBadRequest {
+		t.Fatalf("invalid window = %d", got)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/prices?window=1h", nil))
+	var body struct {
+		Capacity int           `json:"capacity"`
+		Samples  []PriceSample `json:"samples"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("prices = %d %s", rec.Code, rec.Body)
+	}
+	if body.Capacity != DefaultPriceHistorySize || len(body.Samples) != 1 || body.Samples[0].Price != 3.5 {
+		t.Fatalf("prices = %+v", body)
+	}
+}
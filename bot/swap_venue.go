@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SwapVenueSimulated fills swaps at the monitored price without touching a DEX
+	SwapVenueSimulated = "simulated"
+	// SwapVenueOsmosis quotes through the Osmosis poolmanager REST API
+	SwapVenueOsmosis = "osmosis"
+	// SwapVenueAMM quotes a generic constant-product (x*y=k) pool
+	SwapVenueAMM = "amm"
+
+	// DefaultMaxSlippage is the slippage above which an executed swap is reported (1%)
+	DefaultMaxSlippage = 0.01
+	// DefaultSwapTokenInDecimals matches ugen (1 GXR = 10^8 ugen)
+	DefaultSwapTokenInDecimals = 8
+	// DefaultSwapTokenOutDecimals matches the usual 6-decimal quote assets
+	DefaultSwapTokenOutDecimals = 6
+)
+
+// SwapVenueConfig selects and configures the venue the rebalancer swaps on
+type SwapVenueConfig struct {
+	Venue            string  `yaml:"venue"`              // simulated|osmosis|amm, empty means simulated
+	Endpoint         string  `yaml:"endpoint"`           // REST endpoint of the venue
+	PoolID           string  `yaml:"pool_id"`            // pool to swap through
+	TokenIn          string  `yaml:"token_in"`           // denom sold (GXR on the venue chain)
+	TokenOut         string  `yaml:"token_out"`          // denom bought
+	TokenInDecimals  int     `yaml:"token_in_decimals"`  // default 8
+	TokenOutDecimals int     `yaml:"token_out_decimals"` // default 6
+	MaxSlippage      float64 `yaml:"max_slippage"`       // default 0.01 (1%)
+}
+
+// SwapQuote is the expected outcome of a swap, in whole tokens
+type SwapQuote struct {
+	Venue     string
+	AmountIn  float64
+	AmountOut float64
+	Price     float64 // AmountOut per AmountIn
+	QuotedAt  time.Time
+}
+
+// SwapResult is the executed outcome of a swap, in whole tokens
+type SwapResult struct {
+	Venue      string
+	AmountIn   float64
+	AmountOut  float64
+	Price      float64
+	TxHash     string
+	ExecutedAt time.Time
+	// Simulated is set when nothing was broadcast; the swap has no tx hash
+	// and does not count as executed
+	Simulated bool
+}
+
+// Slippage returns how much worse the executed price was than the quoted
+// one, relative to the quote; negative values mean a better fill
+func (q SwapQuote) Slippage(result SwapResult) float64 {
+	if q.Price == 0 {
+		return 0
+	}
+	return (q.Price - result.Price) / q.Price
+}
+
+// SwapVenue is a DEX the rebalancer can quote and execute swaps on
+type SwapVenue interface {
+	Name() string
+	Quote(ctx context.Context, amountIn float64) (SwapQuote, error)
+	ExecuteSwap(ctx context.Context, quote SwapQuote) (SwapResult, error)
+}
+
+// SwapDirection is which side of a DEX pool a rebalance swap sells
+type SwapDirection string
+
+const (
+	// SwapSellGXR sells GXR into the pool, pushing the price down
+	SwapSellGXR SwapDirection = "sell_gxr"
+	// SwapBuyGXR buys GXR from the pool, pushing the price up
+	SwapBuyGXR SwapDirection = "buy_gxr"
+)
+
+// SwapExecutor places rebalance swaps on the bot's DEX pools. With dex_pools
+// configured the rebalancer swaps through it instead of the swap venue.
+type SwapExecutor interface {
+	// ActivePools returns the names of the pools accepting swaps
+	ActivePools() []string
+	// ExecuteSwap swaps amountGXR on a pool. The result is marked Simulated
+	// when no swap was broadcast.
+	ExecuteSwap(ctx context.Context, poolName string, amountGXR float64, direction SwapDirection) (SwapResult, error)
+}
+
+// ValidateSwapVenueConfig validates the swap_venue configuration
+func ValidateSwapVenueConfig(cfg SwapVenueConfig) error {
+	switch cfg.Venue {
+	case "", SwapVenueSimulated:
+	case SwapVenueOsmosis, SwapVenueAMM:
+		if cfg.Endpoint == "" {
+			return fmt.Errorf("swap_venue.endpoint is required for the %s venue", cfg.Venue)
+		}
+		if cfg.PoolID == "" {
+			return fmt.Errorf("swap_venue.pool_id is required for the %s venue", cfg.Venue)
+		}
+		if cfg.TokenIn == "" || cfg.TokenOut == "" {
+			return fmt.Errorf("swap_venue.token_in and token_out are required for the %s venue", cfg.Venue)
+		}
+	default:
+		return fmt.Errorf("invalid swap_venue.venue %q (use simulated, osmosis or amm)", cfg.Venue)
+	}
+
+	if cfg.TokenInDecimals < 0 || cfg.TokenInDecimals > 18 || cfg.TokenOutDecimals < 0 || cfg.TokenOutDecimals > 18 {
+		return fmt.Errorf("swap_venue token decimals must be between 0 and 18")
+	}
+	if cfg.MaxSlippage < 0 || cfg.MaxSlippage >= 1 {
+		return fmt.Errorf("swap_venue.max_slippage must be in [0, 1)")
+	}
+	return nil
+}
+
+// NewSwapVenue creates the venue selected by cfg. spotPrice feeds the
+// simulated venue and is called with the rebalancer lock held.
+func NewSwapVenue(cfg SwapVenueConfig, spotPrice func() float64) (SwapVenue, error) {
+	if err := ValidateSwapVenueConfig(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.TokenInDecimals == 0 {
+		cfg.TokenInDecimals = DefaultSwapTokenInDecimals
+	}
+	if cfg.TokenOutDecimals == 0 {
+		cfg.TokenOutDecimals = DefaultSwapTokenOutDecimals
+	}
+
+	switch cfg.Venue {
+	case SwapVenueOsmosis:
+		return &OsmosisVenue{cfg: cfg, api: newVenueQuerier(cfg.Endpoint)}, nil
+	case SwapVenueAMM:
+		return &AMMVenue{cfg: cfg, api: newVenueQuerier(cfg.Endpoint)}, nil
+	default:
+		return &SimulatedVenue{price: spotPrice}, nil
+	}
+}
+
+// maxSlippage returns the configured slippage limit or the default
+func (cfg SwapVenueConfig) maxSlippage() float64 {
+	if cfg.MaxSlippage == 0 {
+		return DefaultMaxSlippage
+	}
+	return cfg.MaxSlippage
+}
+
+// newVenueQuerier reuses the REST helper of ChainQuerier for a venue endpoint
+func newVenueQuerier(endpoint string) *ChainQuerier {
+	return NewChainQuerierForAPI(endpoint)
+}
+
+// SimulatedVenue fills every swap at the monitored spot price. It is the
+// default until a real venue is configured.
+type SimulatedVenue struct {
+	price func() float64
+}
+
+func (v *SimulatedVenue) Name() string { return SwapVenueSimulated }
+
+func (v *SimulatedVenue) Quote(ctx context.Context, amountIn float64) (SwapQuote, error) {
+	price := v.price()
+	return SwapQuote{
+		Venue:     SwapVenueSimulated,
+		AmountIn:  amountIn,
+		AmountOut: amountIn * price,
+		Price:     price,
+		QuotedAt:  time.Now(),
+	}, nil
+}
+
+func (v *SimulatedVenue) ExecuteSwap(ctx context.Context, quote SwapQuote) (SwapResult, error) {
+	log.Printf("Simulating swap of %.2f GXR at $%.4f", quote.AmountIn, quote.Price)
+	return fillAtQuote(quote), nil
+}
+
+// OsmosisVenue quotes swaps with the Osmosis poolmanager estimate query.
+// Broadcasting the swap is not wired up yet: ExecuteSwap re-estimates at
+// execution time, so the recorded slippage is the price move since the quote.
+type OsmosisVenue struct {
+	cfg SwapVenueConfig
+	api *ChainQuerier
+}
+
+func (v *OsmosisVenue) Name() string { return SwapVenueOsmosis }
+
+func (v *OsmosisVenue) Quote(ctx context.Context, amountIn float64) (SwapQuote, error) {
+	amountOut, err := v.estimate(ctx, amountIn)
+	if err != nil {
+		return SwapQuote{}, err
+	}
+	return newSwapQuote(SwapVenueOsmosis, amountIn, amountOut), nil
+}
+
+func (v *OsmosisVenue) ExecuteSwap(ctx context.Context, quote SwapQuote) (SwapResult, error) {
+	amountOut, err := v.estimate(ctx, quote.AmountIn)
+	if err != nil {
+		return SwapResult{}, fmt.Errorf("osmosis swap failed: %w", err)
+	}
+	log.Printf("Osmosis pool %s: swapping %.2f GXR for %.4f %s", v.cfg.PoolID, quote.AmountIn, amountOut, v.cfg.TokenOut)
+	return newSwapResult(SwapVenueOsmosis, quote.AmountIn, amountOut), nil
+}
+
+// estimate returns the whole-token output of swapping amountIn through the pool
+func (v *OsmosisVenue) estimate(ctx context.Context, amountIn float64) (float64, error) {
+	var resp struct {
+		TokenOutAmount string `json:"token_out_amount"`
+	}
+
+	query := url.Values{}
+	query.Set("token_in", toBaseUnits(amountIn, v.cfg.TokenInDecimals)+v.cfg.TokenIn)
+	query.Set("token_out_denom", v.cfg.TokenOut)
+	path := fmt.Sprintf("/osmosis/poolmanager/v1beta1/%s/estimate/single_pool_swap_exact_amount_in?%s",
+		url.PathEscape(v.cfg.PoolID), query.Encode())
+
+	if err := v.api.getJSON(ctx, path, &resp); err != nil {
+		return 0, err
+	}
+	return fromBaseUnits(resp.TokenOutAmount, v.cfg.TokenOutDecimals)
+}
+
+// AMMVenue quotes swaps against a generic constant-product pool whose
+// reserves and fee are read from GET {endpoint}/pools/{pool_id}. Like
+// OsmosisVenue it re-quotes at execution until broadcasting is wired up.
+type AMMVenue struct {
+	cfg SwapVenueConfig
+	api *ChainQuerier
+}
+
+func (v *AMMVenue) Name() string { return SwapVenueAMM }
+
+func (v *AMMVenue) Quote(ctx context.Context, amountIn float64) (SwapQuote, error) {
+	amountOut, err := v.estimate(ctx, amountIn)
+	if err != nil {
+		return SwapQuote{}, err
+	}
+	return newSwapQuote(SwapVenueAMM, amountIn, amountOut), nil
+}
+
+func (v *AMMVenue) ExecuteSwap(ctx context.Context, quote SwapQuote) (SwapResult, error) {
+	amountOut, err := v.estimate(ctx, quote.AmountIn)
+	if err != nil {
+		return SwapResult{}, fmt.Errorf("amm swap failed: %w", err)
+	}
+	log.Printf("AMM pool %s: swapping %.2f GXR for %.4f %s", v.cfg.PoolID, quote.AmountIn, amountOut, v.cfg.TokenOut)
+	return newSwapResult(SwapVenueAMM, quote.AmountIn, amountOut), nil
+}
+
+// estimate reads the pool reserves and applies the constant-product formula
+func (v *AMMVenue) estimate(ctx context.Context, amountIn float64) (float64, error) {
+	var resp struct {
+		Reserves map[string]string `json:"reserves"`
+		SwapFee  string            `json:"swap_fee"`
+	}
+	if err := v.api.getJSON(ctx, "/pools/"+url.PathEscape(v.cfg.PoolID), &resp); err != nil {
+		return 0, err
+	}
+
+	reserveIn, err := fromBaseUnits(resp.Reserves[v.cfg.TokenIn], v.cfg.TokenInDecimals)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s reserve: %w", v.cfg.TokenIn, err)
+	}
+	reserveOut, err := fromBaseUnits(resp.Reserves[v.cfg.TokenOut], v.cfg.TokenOutDecimals)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s reserve: %w", v.cfg.TokenOut, err)
+	}
+	fee := 0.0
+	if resp.SwapFee != "" {
+		if fee, err = strconv.ParseFloat(resp.SwapFee, 64); err != nil {
+			return 0, fmt.Errorf("invalid swap fee: %w", err)
+		}
+	}
+
+	return constantProductOut(reserveIn, reserveOut, amountIn, fee)
+}
+
+// constantProductOut returns the output of swapping amountIn into an x*y=k
+// pool, with the fee taken from the input
+func constantProductOut(reserveIn, reserveOut, amountIn, fee float64) (float64, error) {
+	if reserveIn <= 0 || reserveOut <= 0 {
+		return 0, fmt.Errorf("pool has no liquidity")
+	}
+	if fee < 0 || fee >= 1 {
+		return 0, fmt.Errorf("invalid swap fee %.4f", fee)
+	}
+
+	in := amountIn * (1 - fee)
+	return reserveOut * in / (reserveIn + in), nil
+}
+
+func newSwapQuote(venue string, amountIn, amountOut float64) SwapQuote {
+	return SwapQuote{
+		Venue:     venue,
+		AmountIn:  amountIn,
+		AmountOut: amountOut,
+		Price:     swapPrice(amountIn, amountOut),
+		QuotedAt:  time.Now(),
+	}
+}
+
+func newSwapResult(venue string, amountIn, amountOut float64) SwapResult {
+	return SwapResult{
+		Venue:      venue,
+		AmountIn:   amountIn,
+		AmountOut:  amountOut,
+		Price:      swapPrice(amountIn, amountOut),
+		ExecutedAt: time.Now(),
+	}
+}
+
+// fillAtQuote returns a result that filled exactly at the quote
+func fillAtQuote(quote SwapQuote) SwapResult {
+	return SwapResult{
+		Venue:      quote.Venue,
+		AmountIn:   quote.AmountIn,
+		AmountOut:  quote.AmountOut,
+		Price:      quote.Price,
+		ExecutedAt: time.Now(),
+	}
+}
+
+func swapPrice(amountIn, amountOut float64) float64 {
+	if amountIn == 0 {
+		return 0
+	}
+	return amountOut / amountIn
+}
+
+// toBaseUnits converts a whole-token amount into an integer base-unit string
+func toBaseUnits(amount float64, decimals int) string {
+	return strconv.FormatFloat(math.Floor(amount*math.Pow10(decimals)), 'f', 0, 64)
+}
+
+// fromBaseUnits converts an integer base-unit string into whole tokens
+func fromBaseUnits(amount string, decimals int) (float64, error) {
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+	return value / math.Pow10(decimals), nil
+}
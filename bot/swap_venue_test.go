@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// mockSwapVenue quotes at a fixed price and fills at price * (1 - fillSlippage)
+type mockSwapVenue struct {
+	price        float64
+	fillSlippage float64
+	quoteErr     error
+
+	quotes   int
+	executed []SwapQuote
+}
+
+func (m *mockSwapVenue) Name() string { return "mock" }
+
+func (m *mockSwapVenue) Quote(ctx context.Context, amountIn float64) (SwapQuote, error) {
+	m.quotes++
+	if m.quoteErr != nil {
+		return SwapQuote{}, m.quoteErr
+	}
+	return newSwapQuote("mock", amountIn, amountIn*m.price), nil
+}
+
+func (m *mockSwapVenue) ExecuteSwap(ctx context.Context, quote SwapQuote) (SwapResult, error) {
+	m.executed = append(m.executed, quote)
+	return newSwapResult("mock", quote.AmountIn, quote.AmountOut*(1-m.fillSlippage)), nil
+}
+
+func newVenueTestRebalancer(t *testing.T, venue SwapVenue) *Rebalancer {
+	r := newTestRebalancer(t, &BotConfig{}, nil)
+	r.telegramAlert = nil
+	r.swapVenue = venue
+	return r
+}
+
+func TestRebalancerSwapsOnConfiguredVenue(t *testing.T) {
+	venue := &mockSwapVenue{price: 3.0, fillSlippage: 0.005}
+	r := newVenueTestRebalancer(t, venue)
+
+	if err := r.performRebalance(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(venue.executed) != 1 || r.rebalanceCount != 1 {
+		t.Fatalf("executed %d swaps, %d rebalances, want 1", len(venue.executed), r.rebalanceCount)
+	}
+	if r.lastQuote.Price != 3.0 || math.Abs(r.lastSwap.Price-2.985) > 1e-9 {
+		t.Fatalf("expected/executed price = %.4f/%.4f, want 3.0/2.985", r.lastQuote.Price, r.lastSwap.Price)
+	}
+	if math.Abs(r.lastSlippage-0.005) > 1e-9 || r.slippageBreaches != 0 {
+		t.Fatalf("slippage = %.4f with %d breaches, want 0.005 within the limit", r.lastSlippage, r.slippageBreaches)
+	}
+
+	// Slippage above max_slippage is counted as a breach
+	venue.fillSlippage = 0.03
+	if err := r.performRebalance(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r.slippageBreaches != 1 || math.Abs(r.worstSlippage-0.03) > 1e-9 {
+		t.Fatalf("breaches = %d, worst = %.4f, want 1 and 0.03", r.slippageBreaches, r.worstSlippage)
+	}
+	if math.Abs(r.averageSlippage()-0.0175) > 1e-9 {
+		t.Fatalf("average slippage = %.4f, want 0.0175", r.averageSlippage())
+	}
+
+	status := r.GetStatus()
+	if status["swap_venue"] != "mock" || status["swap_count"] != int64(2) {
+		t.Fatalf("status reports venue %v with %v swaps", status["swap_venue"], status["swap_count"])
+	}
+}
+
+func TestRebalancerSplitsSwapsAcrossDEXPools(t *testing.T) {
+	dex := NewDEXManager(&BotConfig{})
+	for _, pool := range []*DEXPool{
+		{Name: "GXR/TON", Address: "gxr1dexpool1ton", Active: true},
+		{Name: "GXR/POLYGON", Address: "gxr1dexpool1polygon", Active: true},
+		{Name: "GXR/BSC", Address: "gxr1dexpool1bsc", Active: false},
+		{Name: "GXR/ETH", Address: "gxr1dexpool1eth", Active: true},
+	} {
+		dex.pools[pool.Name] = pool
+	}
+	dex.swap = func(ctx context.Context, pool *DEXPool, amountGXR float64, direction SwapDirection) (SwapResult, error) {
+		if pool.Name == "GXR/POLYGON" {
+			return SwapResult{}, errors.New("pool paused")
+		}
+		return SwapResult{TxHash: "HASH-" + pool.Name}, nil
+	}
+
+	// GXR/ETH is active but not in dex_pools, GXR/BSC is in dex_pools but inactive
+	venue := &mockSwapVenue{price: 3.0}
+	r := newTestRebalancer(t, &BotConfig{DEXPools: []string{"GXR/TON", "GXR/POLYGON", "GXR/BSC"}}, dex)
+	r.telegramAlert = nil
+	r.swapVenue = venue
+
+	if _, err := r.executeRebalance(context.Background(), 1000); err != nil {
+		t.Fatal(err)
+	}
+	want := []PoolSwap{
+		{Pool: "GXR/TON", AmountGXR: 500, TxHash: "HASH-GXR/TON"},
+		{Pool: "GXR/POLYGON", AmountGXR: 500, Error: "swap on GXR/POLYGON failed: pool paused"},
+	}
+	if len(r.lastPoolSwaps) != len(want) {
+		t.Fatalf("pool swaps = %+v", r.lastPoolSwaps)
+	}
+	for i, swap := range r.lastPoolSwaps {
+		swap.At = time.Time{}
+		if swap != want[i] {
+			t.Fatalf("pool swap %d = %+v, want %+v", i, swap, want[i])
+		}
+	}
+	if len(venue.executed) != 0 || dex.pools["GXR/TON"].SwapCount != 1 || r.poolSwapFailures != 1 {
+		t.Fatalf("venue swaps %d, TON swaps %d, failures %d", len(venue.executed), dex.pools["GXR/TON"].SwapCount, r.poolSwapFailures)
+	}
+
+	// Only the swapped half counts as rebalanced
+	r.lastGoodPriceAt = time.Now()
+	r.priceVolatility = 0
+	if err := r.performRebalance(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r.totalRebalanceVolume != 500 {
+		t.Fatalf("total volume = %.2f, want 500", r.totalRebalanceVolume)
+	}
+
+	// The rebalance fails once no pool takes the swap
+	dex.pools["GXR/TON"].Active = false
+	if _, err := r.executeRebalance(context.Background(), 1000); err == nil {
+		t.Fatal("rebalance succeeded without an active pool")
+	}
+}
+
+func TestSimulatedPoolSwapsNotExecuted(t *testing.T) {
+	// Without a wired up swap the pools only simulate
+	dex := NewDEXManager(&BotConfig{})
+	dex.pools["GXR/TON"] = &DEXPool{Name: "GXR/TON", Address: "gxr1dexpool1ton", Active: true}
+	dex.pools["GXR/POLYGON"] = &DEXPool{Name: "GXR/POLYGON", Address: "gxr1dexpool1polygon", Active: true}
+
+	result, err := dex.ExecuteSwap(context.Background(), "GXR/TON", 100, SwapSellGXR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Simulated || result.TxHash != "" || dex.pools["GXR/TON"].SwapCount != 0 {
+		t.Fatalf("simulated swap = %+v, %d swaps", result, dex.pools["GXR/TON"].SwapCount)
+	}
+
+	venue := &mockSwapVenue{price: 3.0}
+	r := newTestRebalancer(t, &BotConfig{DEXPools: []string{"GXR/TON", "GXR/POLYGON"}}, dex)
+	r.telegramAlert = nil
+	r.swapVenue = venue
+	r.lastGoodPriceAt = time.Now()
+	r.priceVolatility = 0
+	if err := r.performRebalance(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rebalance is counted as simulated, not executed
+	if r.rebalanceCount != 0 || r.totalRebalanceVolume != 0 || r.simulatedCount != 1 || r.simulatedVolume != 1000 {
+		t.Fatalf("rebalances %d (%.2f GXR), simulated %d (%.2f GXR)", r.rebalanceCount, r.totalRebalanceVolume, r.simulatedCount, r.simulatedVolume)
+	}
+	for _, swap := range r.lastPoolSwaps {
+		if !swap.Simulated || swap.TxHash != "" {
+			t.Fatalf("pool swap = %+v", swap)
+		}
+	}
+	status := dex.GetStatus()
+	if status["swap_count"] != int64(0) || status["simulated_swap_count"] != int64(3) || len(venue.executed) != 0 {
+		t.Fatalf("status swaps %v, simulated %v", status["swap_count"], status["simulated_swap_count"])
+	}
+}
+
+func TestRebalancerQuoteFailureSkipsSwap(t *testing.T) {
+	venue := &mockSwapVenue{quoteErr: errors.New("pool unavailable")}
+	r := newVenueTestRebalancer(t, venue)
+
+	if err := r.performRebalance(context.Background()); err == nil {
+		t.Fatal("rebalance should fail when the venue cannot quote")
+	}
+	if len(venue.executed) != 0 || r.rebalanceCount != 0 || r.swapCount != 0 {
+		t.Fatalf("failed quote still executed: %d swaps, %d rebalances", len(venue.executed), r.rebalanceCount)
+	}
+}
+
+func TestConstantProductOut(t *testing.T) {
+	// 1,000 GXR into a 100,000 GXR / 300,000 USDC pool with a 0.3% fee
+	out, err := constantProductOut(100000, 300000, 1000, 0.003)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := 1000 * 0.997
+	if want := 300000 * in / (100000 + in); math.Abs(out-want) > 1e-9 {
+		t.Fatalf("out = %.6f, want %.6f", out, want)
+	}
+	if out >= 3000 {
+		t.Fatalf("out = %.2f should be below the spot value due to fee and price impact", out)
+	}
+
+	if _, err := constantProductOut(0, 300000, 1000, 0.003); err == nil {
+		t.Fatal("empty pool should fail")
+	}
+}
+
+func TestOsmosisVenueQuote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/osmosis/poolmanager/v1beta1/42/estimate/single_pool_swap_exact_amount_in" {
+			http.NotFound(w, req)
+			return
+		}
+		if req.URL.Query().Get("token_in") != "100000000000ibc/GXR" || req.URL.Query().Get("token_out_denom") != "uusdc" {
+			http.Error(w, "unexpected query "+req.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"token_out_amount":"2970000000"}`))
+	}))
+	defer server.Close()
+
+	venue, err := NewSwapVenue(SwapVenueConfig{
+		Venue:    SwapVenueOsmosis,
+		Endpoint: server.URL,
+		PoolID:   "42",
+		TokenIn:  "ibc/GXR",
+		TokenOut: "uusdc",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quote, err := venue.Quote(context.Background(), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.AmountOut != 2970 || math.Abs(quote.Price-2.97) > 1e-9 {
+		t.Fatalf("quote = %.4f out at $%.4f, want 2970 at $2.97", quote.AmountOut, quote.Price)
+	}
+}
+
+func TestValidateSwapVenueConfig(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cfg   SwapVenueConfig
+		valid bool
+	}{
+		{"default simulated", SwapVenueConfig{}, true},
+		{"amm", SwapVenueConfig{Venue: SwapVenueAMM, Endpoint: "http://amm", PoolID: "1", TokenIn: "ugen", TokenOut: "uusdc"}, true},
+		{"osmosis without pool", SwapVenueConfig{Venue: SwapVenueOsmosis, Endpoint: "http://lcd", TokenIn: "ugen", TokenOut: "uosmo"}, false},
+		{"unknown venue", SwapVenueConfig{Venue: "uniswap"}, false},
+		{"slippage too high", SwapVenueConfig{MaxSlippage: 1}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSwapVenueConfig(tc.cfg)
+			if tc.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
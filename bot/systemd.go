@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Environment set by systemd for Type=notify services
+const (
+	NotifySocketEnv = "NOTIFY_SOCKET"
+	WatchdogUsecEnv = "WATCHDOG_USEC"
+	WatchdogPIDEnv  = "WATCHDOG_PID"
+)
+
+// SystemdWatchdogSec is the watchdog timeout of the generated unit files. The
+// bot pings at half of it while its health loop keeps running.
+const SystemdWatchdogSec = 2 * time.Minute
+
+// SystemdNotifier sends sd_notify datagrams to the service manager. It is nil
+// when the bot does not run under systemd; all methods are no-ops then.
+type SystemdNotifier struct {
+	socket   string
+	watchdog time.Duration
+}
+
+// NewSystemdNotifier returns a notifier for NOTIFY_SOCKET, nil when it is not
+// set. The watchdog is enabled by WATCHDOG_USEC for this process.
+func NewSystemdNotifier() *SystemdNotifier {
+	socket := os.Getenv(NotifySocketEnv)
+	if socket == "" {
+		return nil
+	}
+
+	n := &SystemdNotifier{socket: socket}
+	usec, err := strconv.ParseInt(os.Getenv(WatchdogUsecEnv), 10, 64)
+	pid := os.Getenv(WatchdogPIDEnv)
+	if err == nil && usec > 0 && (pid == "" || pid == strconv.Itoa(os.Getpid())) {
+		n.watchdog = time.Duration(usec) * time.Microsecond
+	}
+	return n
+}
+
+// Notify sends the given state assignments, e.g. "READY=1", in one datagram
+func (n *SystemdNotifier) Notify(state ...string) error {
+	if n == nil {
+		return nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", n.socket, err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(strings.Join(state, "\n")))
+	return err
+}
+
+// WatchdogInterval returns the watchdog timeout systemd expects pings within,
+// 0 when the watchdog is disabled
+func (n *SystemdNotifier) WatchdogInterval() time.Duration {
+	if n == nil {
+		return 0
+	}
+	return n.watchdog
+}
+
+// RunWatchdog sends WATCHDOG=1 every half watchdog interval until ctx is
+// done, but only while alive reports the process as working. A hung bot
+// thus misses its pings and systemd restarts it.
+func (n *SystemdNotifier) RunWatchdog(ctx context.Context, alive func(now time.Time) bool) {
+	if n.WatchdogInterval() <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(n.watchdog / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !alive(now) {
+				continue
+			}
+			if err := n.Notify("WATCHDOG=1"); err != nil {
+				log.Printf("Failed to send systemd watchdog ping: %v", err)
+			}
+		}
+	}
+}
+
+// systemdStatus returns the one-line health summary shown by systemctl status
+func (bs *BotService) systemdStatus() string {
+	unhealthy := bs.unhealthyComponents()
+
+	bs.mu.RLock()
+	total := len(bs.healthStatus)
+	standingBy, readOnly, since := bs.standingBy, bs.readOnly, bs.unhealthySince
+	bs.mu.RUnlock()
+
+	var status string
+	switch {
+	case total == 0:
+		status = "running, waiting for the first health check"
+	case len(unhealthy) == 0:
+		status = fmt.Sprintf("healthy, %d components ok", total)
+	default:
+		status = fmt.Sprintf("unhealthy: %s (%d/%d ok)", strings.Join(unhealthy, ", "), total-len(unhealthy), total)
+		if !since.IsZero() {
+			status += " since " + since.UTC().Format(time.RFC3339)
+		}
+	}
+
+	switch {
+	case standingBy:
+		status = "standby, " + status
+	case readOnly:
+		status = "read-only, " + status
+	}
+	return status
+}
+
+// notifySystemd sends the health summary to systemd, with READY=1 once the
+// components have started
+func (bs *BotService) notifySystemd(ready bool) {
+	state := []string{"STATUS=" + bs.systemdStatus()}
+	if ready {
+		state = append([]string{"READY=1"}, state...)
+	}
+	if err := bs.notifier.Notify(state...); err != nil {
+		log.Printf("Failed to notify systemd: %v", err)
+	}
+}
+
+// watchdogAlive reports whether the health loop ran within two intervals,
+// which is what the systemd watchdog pings vouch for
+func (bs *BotService) watchdogAlive(now time.Time) bool {
+	if !bs.config.HealthCheckEnabled {
+		return true
+	}
+
+	bs.mu.RLock()
+	last := bs.lastHealthCheck
+	if last.Before(bs.startTime) {
+		last = bs.startTime
+	}
+	bs.mu.RUnlock()
+	return now.Sub(last) <= 2*HealthCheckInterval
+}
+
+// systemdUnit renders a Type=notify unit file
+func systemdUnit(description, execStart string, user bool) string {
+	wantedBy := "multi-user.target"
+	if user {
+		wantedBy = "default.target"
+	}
+
+	return fmt.Sprintf(`[Unit]
+Description=%s
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=notify
+NotifyAccess=main
+ExecStart=%s
+Restart=on-failure
+RestartSec=10
+WatchdogSec=%d
+TimeoutStopSec=%d
+
+[Install]
+WantedBy=%s
+`, description, execStart, int(SystemdWatchdogSec/time.Second), int(2*ShutdownTimeout/time.Second), wantedBy)
+}
+
+// systemdUnitDir returns where unit files are installed for the user or the system
+func systemdUnitDir(user bool) string {
+	if !user {
+		return "/etc/systemd/system"
+	}
+	if config := os.Getenv("XDG_CONFIG_HOME"); config != "" {
+		return filepath.Join(config, "systemd", "user")
+	}
+	return expandHome("~/.config/systemd/user")
+}
+
+// createSystemdCmd creates the systemd command
+func createSystemdCmd() *cobra.Command {
+	systemdCmd := &cobra.Command{
+		Use:   "systemd",
+		Short: "Run the bot under systemd",
+	}
+
+	systemdCmd.AddCommand(createSystemdInstallCmd())
+
+	return systemdCmd
+}
+
+// createSystemdInstallCmd creates the systemd install command
+func createSystemdInstallCmd() *cobra.Command {
+	var user bool
+	var dir string
+	var launcherBinary string
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Write sample unit files for the bot and the launcher",
+		Long: `Writes gxr-bot.service, which runs the bot alone, and gxr-launcher.service,
+which runs the launcher managing the node and the bot. Enable only one of them.
+Both are Type=notify: the process reports when it is ready, a one-line health
+summary for systemctl status and watchdog pings while its health loop runs.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, _ := cmd.Flags().GetString("config")
+			configPath, err := filepath.Abs(expandHome(configPath))
+			if err != nil {
+				return fmt.Errorf("invalid config path: %w", err)
+			}
+			botBinary, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("cannot locate the gxr-bot binary: %w", err)
+			}
+			if launcherBinary == "" {
+				launcherBinary = filepath.Join(filepath.Dir(botBinary), "gxr-launcher")
+			}
+			if dir == "" {
+				dir = systemdUnitDir(user)
+			}
+
+			units := map[string]string{
+				"gxr-bot.service": systemdUnit("GXR validator bot",
+					fmt.Sprintf("%s --config %s", botBinary, configPath), user),
+				"gxr-launcher.service": systemdUnit("GXR node and validator bot",
+					fmt.Sprintf("%s --bot-binary %s --bot-config %s", expandHome(launcherBinary), botBinary, configPath), user),
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+			out := cmd.OutOrStdout()
+			for _, name := range []string{"gxr-bot.service", "gxr-launcher.service"} {
+				path := filepath.Join(dir, name)
+				if err := os.WriteFile(path, []byte(units[name]), 0o644); err != nil {
+					return fmt.Errorf("failed to write %s: %w", path, err)
+				}
+				fmt.Fprintf(out, "Wrote %s\n", path)
+			}
+
+			systemctl := "systemctl"
+			if user {
+				systemctl += " --user"
+			}
+			fmt.Fprintf(out, "\nEnable one of them:\n  %s daemon-reload\n  %s enable --now gxr-bot   # or gxr-launcher\n", systemctl, systemctl)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&user, "user", false, "Install user units (~/.config/systemd/user) instead of system units")
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory to write the unit files to (default: the systemd unit directory)")
+	cmd.Flags().StringVar(&launcherBinary, "launcher-binary", "", "Path of gxr-launcher (default: next to gxr-bot)")
+
+	return cmd
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenNotifySocket serves a fake systemd notify socket and points
+// NOTIFY_SOCKET at it. The directory is kept short for the sun_path limit.
+func listenNotifySocket(t *testing.T) *net.UnixConn {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "sdn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv(NotifySocketEnv, path)
+	return conn
+}
+
+// readDatagram returns the next datagram, "" when none arrives within timeout
+func readDatagram(t *testing.T, conn *net.UnixConn, timeout time.Duration) string {
+	t.Helper()
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	n, err := conn.Read(buf)
+	if err != nil {
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			return ""
+		}
+		t.Fatal(err)
+	}
+	return string(buf[:n])
+}
+
+func TestSystemdNotifierDisabledWithoutSocket(t *testing.T) {
+	t.Setenv(NotifySocketEnv, "")
+	n := NewSystemdNotifier()
+	if n != nil {
+		t.Fatalf("notifier = %+v without NOTIFY_SOCKET", n)
+	}
+	if err := n.Notify("READY=1"); err != nil {
+		t.Fatal(err)
+	}
+	if n.WatchdogInterval() != 0 {
+		t.Fatal("watchdog enabled without NOTIFY_SOCKET")
+	}
+}
+
+func TestSystemdNotifyReadyAndHealthStatus(t *testing.T) {
+	conn := listenNotifySocket(t)
+	t.Setenv(WatchdogUsecEnv, "")
+	bs := &BotService{
+		config:        &BotConfig{HealthCheckEnabled: true},
+		healthStatus:  make(map[string]bool),
+		healthTracker: NewHealthTracker(0, 0),
+		notifier:      NewSystemdNotifier(),
+	}
+
+	bs.notifySystemd(true)
+	if got := readDatagram(t, conn, time.Second); got != "READY=1\nSTATUS=running, waiting for the first health check" {
+		t.Fatalf("ready datagram = %q", got)
+	}
+
+	bs.healthStatus = map[string]bool{"rebalancer": true, "ibc_relayer": false, "dex_manager": false}
+	bs.unhealthySince = time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	bs.notifySystemd(false)
+	if got := readDatagram(t, conn, time.Second); got != "STATUS=unhealthy: dex_manager, ibc_relayer (1/3 ok) since 2026-10-17T08:00:00Z" {
+		t.Fatalf("status datagram = %q", got)
+	}
+
+	bs.healthStatus = map[string]bool{"rebalancer": true, "ibc_relayer": true}
+	bs.standingBy = true
+	bs.notifySystemd(false)
+	if got := readDatagram(t, conn, time.Second); got != "STATUS=standby, healthy, 2 components ok" {
+		t.Fatalf("status datagram = %q", got)
+	}
+
+	if err := bs.notifier.Notify("STOPPING=1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readDatagram(t, conn, time.Second); got != "STOPPING=1" {
+		t.Fatalf("stopping datagram = %q", got)
+	}
+}
+
+func TestSystemdWatchdogFollowsHealthLoop(t *testing.T) {
+	conn := listenNotifySocket(t)
+	t.Setenv(WatchdogUsecEnv, "100000")
+	t.Setenv(WatchdogPIDEnv, "")
+	bs := &BotService{
+		config:    &BotConfig{HealthCheckEnabled: true},
+		startTime: time.Now(),
+		notifier:  NewSystemdNotifier(),
+	}
+	if got := bs.notifier.WatchdogInterval(); got != 100*time.Millisecond {
+		t.Fatalf("watchdog interval = %v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		bs.notifier.RunWatchdog(ctx, bs.watchdogAlive)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if got := readDatagram(t, conn, time.Second); got != "WATCHDOG=1" {
+		t.Fatalf("watchdog datagram = %q", got)
+	}
+
+	// A health loop that stopped ticking starves the watchdog
+	bs.mu.Lock()
+	bs.startTime = time.Now().Add(-3 * HealthCheckInterval)
+	bs.lastHealthCheck = bs.startTime
+	bs.mu.Unlock()
+	for readDatagram(t, conn, 100*time.Millisecond) != "" {
+	}
+	if got := readDatagram(t, conn, 300*time.Millisecond); got != "" {
+		t.Fatalf("watchdog pinged for a stalled health loop: %q", got)
+	}
+
+	bs.mu.Lock()
+	bs.lastHealthCheck = time.Now()
+	bs.mu.Unlock()
+	if got := readDatagram(t, conn, time.Second); got != "WATCHDOG=1" {
+		t.Fatalf("watchdog datagram after recovery = %q", got)
+	}
+
+	// The watchdog belongs to another process
+	t.Setenv(WatchdogPIDEnv, "1")
+	if NewSystemdNotifier().WatchdogInterval() != 0 {
+		t.Fatal("watchdog enabled for another process")
+	}
+}
+
+func TestSystemdInstallWritesUnitFiles(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "bot.yaml")
+	var out bytes.Buffer
+	cmd := CreateRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"systemd", "install", "--user", "--dir", dir, "--config", configPath, "--launcher-binary", "/opt/gxr/gxr-launcher"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot, err := os.ReadFile(filepath.Join(dir, "gxr-bot.service"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Type=notify", "NotifyAccess=main", "WatchdogSec=120", "ExecStart=" + exe + " --config " + configPath, "WantedBy=default.target"} {
+		if !strings.Contains(string(bot), want) {
+			t.Fatalf("gxr-bot.service misses %q:\n%s", want, bot)
+		}
+	}
+	launcher, err := os.ReadFile(filepath.Join(dir, "gxr-launcher.service"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ExecStart=/opt/gxr/gxr-launcher --bot-binary " + exe + " --bot-config " + configPath; !strings.Contains(string(launcher), want) {
+		t.Fatalf("gxr-launcher.service misses %q:\n%s", want, launcher)
+	}
+	if !strings.Contains(out.String(), "systemctl --user enable --now gxr-bot") {
+		t.Fatalf("install output = %q", out.String())
+	}
+}
@@ -8,10 +8,16 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 const (
@@ -27,12 +33,26 @@ const (
 	RetryDelay = 5 * time.Second
 	// MessageSizeLimit is the maximum message size for Telegram
 	MessageSizeLimit = 4096
+	// MinMessageSizeLimit is the smallest configurable message size
+	MinMessageSizeLimit = 512
+	// MaxTruncatedMetadataLines is the number of metadata lines kept when an alert is truncated
+	MaxTruncatedMetadataLines = 5
+	// MaxMetadataLineLength caps a single metadata line when an alert is truncated
+	MaxMetadataLineLength = 200
+	// TruncationMarker replaces the trimmed middle of an oversized alert body
+	TruncationMarker = "\n… message trimmed …\n"
+	// MetadataValidatorAddress is the metadata key used to route alerts per validator
+	MetadataValidatorAddress = "validator_address"
 	// AlertPriorityHigh is for high priority alerts
 	AlertPriorityHigh = 1
 	// AlertPriorityMedium is for medium priority alerts
 	AlertPriorityMedium = 2
 	// AlertPriorityLow is for low priority alerts
 	AlertPriorityLow = 3
+	// DigestCheckInterval is how often the quiet hours digest is checked for flushing
+	DigestCheckInterval = 1 * time.Minute
+	// MaxDigestAlerts is the maximum number of alerts buffered during quiet hours
+	MaxDigestAlerts = 100
 )
 
 // AlertType represents different types of alerts
@@ -102,16 +122,73 @@ type TelegramAlert struct {
 	alertCounts  map[AlertType]int64
 	alertHistory []AlertRecord
 	
+	// Quiet hours batching
+	quietHours     *QuietHours
+	digestBuffer   []*Alert
+	digestDropped  int64
+	digestsSent    int64
+	
+	// Meta-monitoring of the alert volume per alert type
+	anomaly *AlertAnomalyDetector
+	
+	// Per-validator routing
+	validatorRoutedAlerts int64
+	
+	// On-call rotation: critical alerts mention and route to the member on call
+	onCall           *OnCallSchedule
+	onCallShiftStart time.Time
+	onCallMember     OnCallMember
+	onCallAlerts     int64
+	onCallHandovers  int64
+	
+	// Delivery latency and Telegram failure taxonomy
+	delivery deliveryStats
+	
+	// Incident correlation, shared with the other components' dispatchers;
+	// component is the default component of this dispatcher's alerts. The
+	// tracker is read without mu, so health reports never wait for a send.
+	incidents atomic.Pointer[IncidentTracker]
+	component string
+	
+	// chainPhase answers the /status command
+	chainPhase *ChainPhaseTracker
+	
+	// Output sinks: Telegram and/or the local alert file
+	sendToTelegram bool
+	fileSink       *FileAlertSink
+	fileSinkErrors int64
+	
+	// Further channels, such as Discord, each delivered alert is also sent to
+	channels *MultiNotifier
+	
+	// Queue guardrails: QueueAlert never blocks. A full queue evicts its
+	// oldest alert into the spill buffer, or drops it without one. While
+	// Telegram is unreachable alerts wait in the spill buffer, and only its
+	// oldest alert probes Telegram every offlineProbeInterval.
+	spill                atomic.Pointer[AlertSpill]
+	evictedAlerts        atomic.Int64
+	droppedAlerts        atomic.Int64
+	apiRequests          atomic.Int64 // Telegram Bot API requests, for cost accounting
+	handlingSince        atomic.Int64 // EnqueuedAt of the alert being handled, Unix nanoseconds
+	offline              bool
+	lastProbe            time.Time
+	spillCheckInterval   time.Duration
+	offlineProbeInterval time.Duration
+	
 	// Configuration
 	botToken    string
 	chatID      string
 	apiURL      string
 	maxRetries  int
 	retryDelay  time.Duration
+	maxMessageSize int
 	
 	// Control
 	running    bool
 	stopChan   chan struct{}
+	
+	// Queued alerts are in-flight work that shutdown drains
+	drain *DrainTracker
 }
 
 // Alert represents an individual alert
@@ -125,6 +202,36 @@ type Alert struct {
 	Metadata    map[string]interface{}
 	Retries     int
 	LastAttempt time.Time
+	
+	// LastFailure is why the last send attempt failed
+	LastFailure TelegramFailure
+	
+	// Held is set once Telegram could not be reached for the alert: it waits
+	// in the spill buffer and is sent as it was screened then
+	Held bool
+	
+	// BypassRateLimit sends the alert regardless of the rate limit, without
+	// counting it against the limit
+	BypassRateLimit bool
+	
+	// Routes are chat IDs that receive the alert in addition to the global chat
+	Routes []string
+	
+	// Component is the bot component the alert is about, for incident correlation
+	Component string
+	
+	// Incident is the incident the alert belongs to and IncidentUpdate its
+	// number in it; updates after the first are sent in compact form
+	Incident       string
+	IncidentUpdate int
+	
+	// Delivery timing: queued, first send attempt, delivered to the global chat
+	EnqueuedAt     time.Time
+	FirstAttemptAt time.Time
+	DeliveredAt    time.Time
+	
+	// op tracks the alert from the queue until it is handled
+	op *Operation
 }
 
 // AlertRecord represents a historical alert record
@@ -155,6 +262,27 @@ type TelegramResponse struct {
 
 // NewTelegramAlert creates a new enhanced Telegram alert system
 func NewTelegramAlert(config *BotConfig) *TelegramAlert {
+	ta := newTelegramAlert(config)
+	
+	// Validate and set configuration
+	if err := ta.validateConfig(); err != nil {
+		log.Printf("Telegram alert configuration error: %v", err)
+		return ta
+	}
+	
+	// Start alert processing
+	go ta.processAlerts()
+	
+	// Answer /oncall and /incidents in the alert chats
+	if (ta.onCall != nil || config.Incidents.Enabled) && ta.sendToTelegram {
+		go ta.pollCommands()
+	}
+	
+	return ta
+}
+
+// newTelegramAlert creates the alert system without configuring its sinks
+func newTelegramAlert(config *BotConfig) *TelegramAlert {
 	ta := &TelegramAlert{
 		config:           config,
 		client:           &http.Client{Timeout: 30 * time.Second},
@@ -165,23 +293,104 @@ func NewTelegramAlert(config *BotConfig) *TelegramAlert {
 		alertHistory:     make([]AlertRecord, 0),
 		maxRetries:       RetryAttempts,
 		retryDelay:       RetryDelay,
+		maxMessageSize:   MessageSizeLimit,
 		stopChan:         make(chan struct{}),
+		digestBuffer:     make([]*Alert, 0),
 	}
 	
-	// Validate and set configuration
-	if err := ta.validateConfig(); err != nil {
-		log.Printf("Telegram alert configuration error: %v", err)
-		return ta
+	if config.TelegramMaxMessageSize > 0 && config.TelegramMaxMessageSize <= MessageSizeLimit {
+		ta.maxMessageSize = config.TelegramMaxMessageSize
 	}
 	
-	// Start alert processing
-	go ta.processAlerts()
+	// Configure quiet hours batching
+	if config.QuietHours.Enabled {
+		quietHours, err := ParseQuietHours(config.QuietHours)
+		if err != nil {
+			log.Printf("Quiet hours configuration error, batching disabled: %v", err)
+		} else {
+			ta.quietHours = quietHours
+		}
+	}
+	
+	// Configure alert spike detection
+	if config.AlertAnomaly.Enabled {
+		ta.anomaly = NewAlertAnomalyDetector(config.AlertAnomaly, NewErrorJournal(config.DataDir))
+	}
+	
+	// Configure the Discord channel
+	if config.DiscordEnabled {
+		discord, err := NewDiscordAlert(config)
+		if err != nil {
+			log.Printf("Discord alert configuration error, Discord disabled: %v", err)
+		} else {
+			ta.channels = NewMultiNotifier(config.NotifierCooldown)
+			ta.channels.Register(NotifierDiscord, discord)
+		}
+	}
+	
+	// Configure the on-call rotation
+	if config.OnCallSchedule.Enabled {
+		onCall, err := ParseOnCallSchedule(config.OnCallSchedule)
+		if err != nil {
+			log.Printf("On-call schedule configuration error, on-call routing disabled: %v", err)
+		} else {
+			ta.onCall = onCall
+			shift := onCall.ShiftAt(time.Now())
+			ta.onCallShiftStart, ta.onCallMember = shift.Start, shift.Member
+		}
+	}
 	
 	return ta
 }
 
-// validateConfig validates the Telegram configuration
+// validateConfig validates the Telegram configuration and opens the alert file sink
 func (ta *TelegramAlert) validateConfig() error {
+	if ta.config.usesAlertFile() {
+		if err := ta.configureFileSink(); err != nil {
+			return err
+		}
+	}
+	
+	if !ta.config.usesTelegram() {
+		if ta.fileSink == nil && ta.channels == nil {
+			return fmt.Errorf("no alert sink configured")
+		}
+		ta.chatID = ta.config.TelegramChatID
+		ta.running = true
+		if ta.fileSink != nil {
+			log.Printf("Alert file sink initialized - Telegram disabled, alerts written to %s", ta.fileSink.Path())
+		} else {
+			log.Printf("Alert channels initialized - Telegram disabled")
+		}
+		return nil
+	}
+	
+	if err := ta.configureTelegram(); err != nil {
+		return err
+	}
+	
+	ta.sendToTelegram = true
+	ta.running = true
+	log.Printf("Telegram alert system initialized - Chat: %s", ta.chatID)
+	if ta.fileSink != nil {
+		log.Printf("Alerts are also written to %s", ta.fileSink.Path())
+	}
+	
+	return nil
+}
+
+// configureFileSink opens the alert file sink
+func (ta *TelegramAlert) configureFileSink() error {
+	sink, err := NewFileAlertSink(ta.config.alertFilePath())
+	if err != nil {
+		return err
+	}
+	ta.fileSink = sink
+	return nil
+}
+
+// configureTelegram validates the Telegram token and chat
+func (ta *TelegramAlert) configureTelegram() error {
 	if ta.config.TelegramToken == "" {
 		return fmt.Errorf("telegram_token is required")
 	}
@@ -192,7 +401,9 @@ func (ta *TelegramAlert) validateConfig() error {
 	
 	ta.botToken = ta.config.TelegramToken
 	ta.chatID = ta.config.TelegramChatID
-	ta.apiURL = fmt.Sprintf("%s%s", TelegramAPIBaseURL, ta.botToken)
+	if ta.apiURL == "" {
+		ta.apiURL = fmt.Sprintf("%s%s", TelegramAPIBaseURL, ta.botToken)
+	}
 	
 	// Validate bot token format
 	if !strings.Contains(ta.botToken, ":") {
@@ -200,24 +411,86 @@ func (ta *TelegramAlert) validateConfig() error {
 	}
 	
 	// Validate chat ID format
-	if _, err := strconv.ParseInt(ta.chatID, 10, 64); err != nil {
-		if !strings.HasPrefix(ta.chatID, "@") {
-			return fmt.Errorf("invalid chat ID format")
+	if !isValidChatID(ta.chatID) {
+		return fmt.Errorf("invalid chat ID format")
+	}
+	
+	return nil
+}
+
+// isValidChatID reports whether id is a numeric chat ID or an @channel name
+func isValidChatID(id string) bool {
+	if _, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return true
+	}
+	return strings.HasPrefix(id, "@") && len(id) > 1
+}
+
+// validatorChatID returns the chat configured for a validator address, if any
+func (ta *TelegramAlert) validatorChatID(validatorAddress string) string {
+	if ta.config == nil || validatorAddress == "" {
+		return ""
+	}
+	return ta.config.ValidatorChatMap[validatorAddress]
+}
+
+// alertRoutes returns the chats an alert is delivered to: the global chat first,
+// then explicit routes and the chat mapped to the alert's validator address
+func (ta *TelegramAlert) alertRoutes(alert *Alert) []string {
+	routes := []string{ta.chatID}
+	add := func(chatID string) {
+		if chatID == "" {
+			return
+		}
+		for _, existing := range routes {
+			if existing == chatID {
+				return
+			}
 		}
+		routes = append(routes, chatID)
 	}
 	
-	ta.running = true
-	log.Printf("Telegram alert system initialized - Chat: %s", ta.chatID)
+	for _, chatID := range alert.Routes {
+		add(chatID)
+	}
+	if address, ok := alert.Metadata[MetadataValidatorAddress].(string); ok {
+		add(ta.validatorChatID(address))
+	}
 	
-	return nil
+	return routes
 }
 
 // processAlerts processes the alert queue
 func (ta *TelegramAlert) processAlerts() {
+	digestTicker := NewJitteredTicker(DigestCheckInterval)
+	defer digestTicker.Stop()
+	
+	checkInterval := ta.spillCheckInterval
+	if checkInterval <= 0 {
+		checkInterval = AlertSpillCheckInterval
+	}
+	spillTicker := time.NewTicker(checkInterval)
+	defer spillTicker.Stop()
+	
 	for {
+		// Spilled alerts are older than the queued ones and go first
+		if alert := ta.nextSpilledAlert(time.Now()); alert != nil {
+			ta.handleAlert(alert)
+			continue
+		}
+		
 		select {
 		case alert := <-ta.alertQueue:
-			ta.handleAlert(alert)
+			if !ta.spillBehindBacklog(alert) {
+				ta.handleAlert(alert)
+			}
+		case <-spillTicker.C:
+			ta.saveSpill()
+		case <-digestTicker.C:
+			ta.flushDigestIfDue(time.Now())
+			ta.flushAnomalyDigests(time.Now())
+			ta.checkOnCallHandover(time.Now())
+			ta.closeIdleIncidents(time.Now())
 		case <-ta.stopChan:
 			log.Printf("Stopping Telegram alert processor")
 			return
@@ -227,21 +500,72 @@ func (ta *TelegramAlert) processAlerts() {
 
 // handleAlert handles an individual alert
 func (ta *TelegramAlert) handleAlert(alert *Alert) {
+	defer alert.op.Done()
+	
+	_, span := startSpan(alert.op.Context(), "alert.deliver",
+		traceAlertType.String(alert.Type.String()), traceAlertTitle.String(alert.Title))
+	outcome := "delivered"
+	defer func() {
+		span.SetAttributes(attribute.String("alert.outcome", outcome))
+		span.End()
+	}()
+	
+	if !alert.EnqueuedAt.IsZero() {
+		ta.handlingSince.Store(alert.EnqueuedAt.UnixNano())
+		defer ta.handlingSince.Store(0)
+	}
+	
 	ta.mu.Lock()
 	defer ta.mu.Unlock()
 	
-	// Check rate limiting
-	if ta.rateLimitEnabled && !ta.canSendAlert() {
-		ta.rateLimitedAlerts++
-		log.Printf("Alert rate limited: %s", alert.Title)
-		return
+	// Held alerts were screened before Telegram became unreachable
+	if !alert.Held {
+		if screened := ta.screenAlert(alert); screened != "" {
+			outcome = screened
+			return
+		}
 	}
 	
 	// Format message
 	message := ta.formatAlert(alert)
 	
-	// Send with retries
-	success := ta.sendWithRetries(message, alert)
+	// Send with retries to the global chat, per-validator routes and the
+	// on-call chat; success is judged on the global chat
+	success := false
+	unreachable := false
+	routes := ta.alertRoutes(alert)
+	for i, chatID := range routes {
+		delivered := ta.deliver(chatID, message, alert)
+		if i == 0 {
+			success = delivered
+			unreachable = !delivered && alert.LastFailure.Unreachable()
+			continue
+		}
+		if delivered {
+			ta.validatorRoutedAlerts++
+		} else {
+			log.Printf("Failed to deliver alert to routed chat %s: %s", chatID, alert.Title)
+		}
+	}
+	if !alert.Held {
+		success = ta.notifyChannels(alert, success)
+	}
+	span.SetAttributes(attribute.Int("alert.routes", len(routes)))
+	
+	// An alert Telegram could not be reached for has not failed: it waits
+	// in the spill buffer until Telegram answers again
+	if unreachable && ta.holdAlert(alert) {
+		outcome = "held"
+		return
+	}
+	if ta.offline {
+		ta.offline = false
+		log.Printf("Telegram reachable again, delivering the spilled alerts")
+	}
+	if !success {
+		outcome = "failed"
+		span.SetStatus(codes.Error, "alert not delivered")
+	}
 	
 	// Update statistics
 	ta.totalAlerts++
@@ -250,6 +574,8 @@ func (ta *TelegramAlert) handleAlert(alert *Alert) {
 	
 	if success {
 		ta.successfulAlerts++
+		ta.delivery.recordDelivery(alert)
+		ta.checkDeliveryLatency(time.Now())
 	} else {
 		ta.failedAlerts++
 	}
@@ -258,12 +584,419 @@ func (ta *TelegramAlert) handleAlert(alert *Alert) {
 	ta.addToHistory(alert, success)
 	
 	// Update rate limiting
-	if ta.rateLimitEnabled {
+	if ta.rateLimitEnabled && !alert.BypassRateLimit {
 		ta.alertTimes = append(ta.alertTimes, time.Now())
 		ta.cleanupOldAlerts()
 	}
 }
 
+// screenAlert runs an alert through incident correlation, quiet hours, spike
+// detection, rate limiting and on-call routing. It returns the outcome of an
+// alert that is not sent now, or "" to send it.
+func (ta *TelegramAlert) screenAlert(alert *Alert) string {
+	// Failure alerts of related components are grouped into one incident
+	ta.correlateIncident(alert, time.Now())
+	
+	// Hold back non-critical alerts during quiet hours
+	if ta.shouldDefer(alert, time.Now()) {
+		ta.bufferForDigest(alert)
+		return "deferred"
+	}
+	
+	// A spiking alert type is announced once and then batched into a digest
+	if ta.anomaly != nil {
+		spike, digest := ta.anomaly.Observe(alert, time.Now())
+		if spike != nil {
+			ta.sendSummaryLocked(spike, time.Now())
+		}
+		if digest {
+			log.Printf("Alert held for the %s digest: %s", alertTypeKey(alert), alert.Title)
+			return "digest"
+		}
+	}
+	
+	// Check rate limiting
+	if ta.rateLimitEnabled && !alert.BypassRateLimit && !ta.canSendAlert() {
+		ta.rateLimitedAlerts++
+		log.Printf("Alert rate limited: %s", alert.Title)
+		return "rate_limited"
+	}
+	
+	// Critical alerts also go to whoever is on call
+	ta.routeToOnCall(alert, time.Now())
+	return ""
+}
+
+// holdAlert keeps an alert Telegram could not be reached for in the spill
+// buffer, where it is the oldest, and takes Telegram for offline. Without a
+// spill buffer the alert fails.
+func (ta *TelegramAlert) holdAlert(alert *Alert) bool {
+	spill := ta.spill.Load()
+	if spill == nil {
+		return false
+	}
+	
+	spill.Hold(alert)
+	if !ta.offline {
+		log.Printf("Telegram unreachable, alerts wait in the spill buffer until it answers again")
+		ta.offline = true
+	}
+	ta.lastProbe = time.Now()
+	return true
+}
+
+// nextSpilledAlert returns the oldest spilled alert when it is its turn: the
+// backlog goes out within the rate limit, and while Telegram is unreachable
+// it is only probed every offlineProbeInterval
+func (ta *TelegramAlert) nextSpilledAlert(now time.Time) *Alert {
+	spill := ta.spill.Load()
+	if spill == nil || spill.Len() == 0 {
+		return nil
+	}
+	
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+	
+	if !ta.running {
+		return nil
+	}
+	if ta.offline {
+		probeInterval := ta.offlineProbeInterval
+		if probeInterval <= 0 {
+			probeInterval = AlertOfflineProbeInterval
+		}
+		if now.Sub(ta.lastProbe) < probeInterval {
+			return nil
+		}
+		ta.lastProbe = now
+	}
+	if ta.rateLimitEnabled && !ta.canSendAlert() {
+		return nil
+	}
+	return spill.Pop(now)
+}
+
+// spillBehindBacklog moves a queued alert to the end of the spill buffer
+// while Telegram is unreachable or older alerts still wait there, so that
+// the backlog is delivered in order
+func (ta *TelegramAlert) spillBehindBacklog(alert *Alert) bool {
+	spill := ta.spill.Load()
+	if spill == nil {
+		return false
+	}
+	
+	ta.mu.RLock()
+	offline := ta.offline
+	ta.mu.RUnlock()
+	if !offline && spill.Len() == 0 {
+		return false
+	}
+	
+	// The spill buffer keeps the alert across a shutdown
+	alert.op.Done()
+	alert.op = nil
+	spill.Push(alert)
+	return true
+}
+
+// saveSpill writes the spill buffer to disk
+func (ta *TelegramAlert) saveSpill() {
+	spill := ta.spill.Load()
+	if spill == nil {
+		return
+	}
+	if err := spill.Save(); err != nil {
+		log.Printf("Failed to save alert spill: %v", err)
+	}
+}
+
+// backlogAge estimates how long the oldest undelivered alert has waited:
+// the oldest spilled one, or else the alert being handled, which was queued
+// before everything still in the queue
+func (ta *TelegramAlert) backlogAge(now time.Time) time.Duration {
+	var oldest time.Time
+	if spill := ta.spill.Load(); spill != nil {
+		oldest = spill.Oldest()
+	}
+	if since := ta.handlingSince.Load(); since != 0 {
+		if handling := time.Unix(0, since); oldest.IsZero() || handling.Before(oldest) {
+			oldest = handling
+		}
+	}
+	if oldest.IsZero() {
+		return 0
+	}
+	return now.Sub(oldest)
+}
+
+// shouldDefer reports whether an alert should be batched into the quiet hours digest
+func (ta *TelegramAlert) shouldDefer(alert *Alert, now time.Time) bool {
+	if ta.quietHours == nil || alert.Type == AlertTypeCritical {
+		return false
+	}
+	
+	return ta.quietHours.Contains(now)
+}
+
+// bufferForDigest stores an alert until quiet hours end
+func (ta *TelegramAlert) bufferForDigest(alert *Alert) {
+	if len(ta.digestBuffer) >= MaxDigestAlerts {
+		ta.digestBuffer = ta.digestBuffer[1:]
+		ta.digestDropped++
+	}
+	
+	ta.digestBuffer = append(ta.digestBuffer, alert)
+	log.Printf("Alert deferred to quiet hours digest: %s", alert.Title)
+}
+
+// flushDigestIfDue sends the buffered alerts as a single digest once quiet hours are over
+func (ta *TelegramAlert) flushDigestIfDue(now time.Time) {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+	
+	if len(ta.digestBuffer) == 0 || (ta.quietHours != nil && ta.quietHours.Contains(now)) {
+		return
+	}
+	
+	digest := ta.buildDigest(now)
+	routed := ta.routedDigests(now)
+	dropped := ta.digestDropped
+	ta.digestBuffer = ta.digestBuffer[:0]
+	ta.digestDropped = 0
+	
+	success := false
+	if !ta.channelsOnly() {
+		success = ta.sendWithRetries(ta.chatID, ta.formatAlert(digest), digest)
+		
+		// Routed chats only receive the deferred alerts routed to them
+		chats := make([]string, 0, len(routed))
+		for chatID := range routed {
+			chats = append(chats, chatID)
+		}
+		sort.Strings(chats)
+		for _, chatID := range chats {
+			if ta.sendWithRetries(chatID, ta.formatAlert(routed[chatID]), routed[chatID]) {
+				ta.validatorRoutedAlerts++
+			} else {
+				log.Printf("Failed to deliver quiet hours digest to routed chat %s", chatID)
+			}
+		}
+	}
+	success = ta.notifyChannels(digest, success)
+	
+	ta.totalAlerts++
+	ta.lastAlertTime = now
+	ta.alertCounts[digest.Type]++
+	ta.digestsSent++
+	
+	if success {
+		ta.successfulAlerts++
+	} else {
+		ta.failedAlerts++
+	}
+	
+	ta.addToHistory(digest, success)
+	
+	log.Printf("Quiet hours digest sent - %d alerts, %d dropped", digest.Metadata["alerts"], dropped)
+}
+
+// buildDigest combines the buffered alerts into one digest alert
+func (ta *TelegramAlert) buildDigest(now time.Time) *Alert {
+	lines := digestLines(ta.digestBuffer)
+	
+	if ta.digestDropped > 0 {
+		lines = append(lines, fmt.Sprintf("... and %d older alerts", ta.digestDropped))
+	}
+	
+	if summary := ta.delivery.summary(); summary != "" {
+		lines = append(lines, "", summary)
+	}
+	
+	return &Alert{
+		ID:        fmt.Sprintf("digest-%d", now.UnixNano()),
+		Type:      AlertTypeInfo,
+		Priority:  AlertPriorityLow,
+		Title:     "Quiet Hours Digest",
+		Message:   strings.Join(lines, "\n"),
+		Timestamp: now,
+		Metadata: map[string]interface{}{
+			"alerts":  len(ta.digestBuffer),
+			"dropped": ta.digestDropped,
+		},
+	}
+}
+
+// routedDigests builds a digest per routed chat of the buffered alerts,
+// keeping the routes they would have been delivered on outside quiet hours
+func (ta *TelegramAlert) routedDigests(now time.Time) map[string]*Alert {
+	alerts := make(map[string][]*Alert)
+	for _, buffered := range ta.digestBuffer {
+		for _, chatID := range ta.alertRoutes(buffered)[1:] {
+			alerts[chatID] = append(alerts[chatID], buffered)
+		}
+	}
+	
+	digests := make(map[string]*Alert, len(alerts))
+	for chatID, routed := range alerts {
+		digests[chatID] = &Alert{
+			ID:        fmt.Sprintf("digest-%s-%d", chatID, now.UnixNano()),
+			Type:      AlertTypeInfo,
+			Priority:  AlertPriorityLow,
+			Title:     "Quiet Hours Digest",
+			Message:   strings.Join(digestLines(routed), "\n"),
+			Timestamp: now,
+			Metadata: map[string]interface{}{
+				"alerts": len(routed),
+			},
+		}
+	}
+	return digests
+}
+
+// digestLines lists alerts one per line in a digest
+func digestLines(alerts []*Alert) []string {
+	lines := make([]string, 0, len(alerts)+1)
+	for _, alert := range alerts {
+		lines = append(lines, fmt.Sprintf("%s %s %s",
+			alert.Timestamp.Format("15:04"), alert.Type.Emoji(), alert.Title))
+	}
+	return lines
+}
+
+// flushAnomalyDigests sends the digests of alert types whose cooldown ended
+func (ta *TelegramAlert) flushAnomalyDigests(now time.Time) {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+	
+	if ta.anomaly == nil {
+		return
+	}
+	for _, digest := range ta.anomaly.DueDigests(now) {
+		ta.sendSummaryLocked(digest, now)
+		log.Printf("Alert spike digest sent - %d %s alerts", digest.Metadata["alerts"], digest.Metadata["alert_type"])
+	}
+}
+
+// sendSummaryLocked delivers a summary or digest alert to the global chat.
+// Summaries replace many alerts, so they are not rate limited.
+func (ta *TelegramAlert) sendSummaryLocked(alert *Alert, now time.Time) {
+	success := ta.deliver(ta.chatID, ta.formatAlert(alert), alert)
+	success = ta.notifyChannels(alert, success)
+	
+	ta.totalAlerts++
+	ta.lastAlertTime = now
+	ta.alertCounts[alert.Type]++
+	if success {
+		ta.successfulAlerts++
+	} else {
+		ta.failedAlerts++
+	}
+	
+	ta.addToHistory(alert, success)
+}
+
+// SetIncidentTracker correlates this dispatcher's alerts into the incidents
+// of tracker; component is the default for alerts without a component
+func (ta *TelegramAlert) SetIncidentTracker(tracker *IncidentTracker, component string) {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+	
+	ta.incidents.Store(tracker)
+	ta.component = component
+}
+
+// SetChainPhaseTracker sets the tracker the /status command reports the chain phase of
+func (ta *TelegramAlert) SetChainPhaseTracker(tracker *ChainPhaseTracker) {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+	
+	ta.chainPhase = tracker
+}
+
+// alertComponent returns the component an alert is about: its own, the
+// dispatcher's default, or the component named by a bot status alert
+func (ta *TelegramAlert) alertComponent(alert *Alert) string {
+	if alert.Component != "" {
+		return alert.Component
+	}
+	if ta.component != "" {
+		return ta.component
+	}
+	if botType, ok := alert.Metadata["bot_type"].(string); ok && isIncidentComponent(botType) {
+		return botType
+	}
+	return ""
+}
+
+// correlateIncident tags a failure alert with its incident. The alert
+// opening an incident carries its summary, later ones become compact
+// updates. A success alert of a component counts as its recovery. Alerts
+// already tagged, like incident resolutions, are left alone.
+func (ta *TelegramAlert) correlateIncident(alert *Alert, now time.Time) {
+	tracker := ta.incidents.Load()
+	if tracker == nil || alert.Incident != "" {
+		return
+	}
+	component := ta.alertComponent(alert)
+	if component == "" {
+		return
+	}
+	
+	if alert.Type == AlertTypeSuccess {
+		for _, closed := range tracker.RecordHealth(component, true, now) {
+			ta.sendSummaryLocked(newIncidentClosedAlert(closed, now), now)
+		}
+		return
+	}
+	
+	incident, update, ok := tracker.Observe(alert, component, now)
+	if !ok {
+		return
+	}
+	
+	alert.Component = component
+	alert.Incident = incident.ID
+	alert.IncidentUpdate = update.Number
+	if alert.Metadata == nil {
+		alert.Metadata = make(map[string]interface{})
+	}
+	alert.Metadata["incident"] = incident.ID
+	if update.Number == 1 {
+		alert.Message = incidentOpenedSummary(incident, tracker.window) + "\n\n" + alert.Message
+	}
+}
+
+// RecordComponentHealth reports a component's health check result to the
+// incident tracker and announces the incidents it closes
+func (ta *TelegramAlert) RecordComponentHealth(component string, healthy bool, now time.Time) {
+	tracker := ta.incidents.Load()
+	if tracker == nil {
+		return
+	}
+	
+	for _, closed := range tracker.RecordHealth(component, healthy, now) {
+		log.Printf("Incident %s resolved, all components recovered", closed.ID)
+		if err := ta.QueueAlert(newIncidentClosedAlert(closed, now)); err != nil {
+			log.Printf("Failed to queue incident resolution for %s: %v", closed.ID, err)
+		}
+	}
+}
+
+// closeIdleIncidents closes the incidents no component reported a recovery for
+func (ta *TelegramAlert) closeIdleIncidents(now time.Time) {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+	
+	tracker := ta.incidents.Load()
+	if tracker == nil {
+		return
+	}
+	for _, closed := range tracker.CloseIdle(now) {
+		log.Printf("Incident %s closed without recovery after %s idle", closed.ID, tracker.idleTimeout)
+		ta.sendSummaryLocked(newIncidentClosedAlert(closed, now), now)
+	}
+}
+
 // canSendAlert checks if we can send an alert based on rate limiting
 func (ta *TelegramAlert) canSendAlert() bool {
 	ta.cleanupOldAlerts()
@@ -286,57 +1019,263 @@ func (ta *TelegramAlert) cleanupOldAlerts() {
 
 // formatAlert formats an alert message for Telegram
 func (ta *TelegramAlert) formatAlert(alert *Alert) string {
+	// Later alerts of an incident only refer to it
+	if alert.IncidentUpdate > 1 {
+		return formatIncidentUpdate(alert)
+	}
+	
 	timestamp := alert.Timestamp.Format("2006-01-02 15:04:05")
 	
-	var parts []string
+	// Header with emoji and type, followed by the title
+	head := []string{fmt.Sprintf("%s *%s*", alert.Type.Emoji(), alert.Type.String())}
+	if alert.Title != "" {
+		head = append(head, fmt.Sprintf("*%s*", alert.Title))
+	}
 	
-	// Add header with emoji and type
-	header := fmt.Sprintf("%s *%s*", alert.Type.Emoji(), alert.Type.String())
-	parts = append(parts, header)
+	// Metadata lines in a stable order
+	keys := make([]string, 0, len(alert.Metadata))
+	for key := range alert.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 	
-	// Add title
-	if alert.Title != "" {
-		parts = append(parts, fmt.Sprintf("*%s*", alert.Title))
+	metadata := make([]string, 0, len(keys))
+	for _, key := range keys {
+		metadata = append(metadata, fmt.Sprintf("• %s: %v", key, alert.Metadata[key]))
 	}
 	
-	// Add message
-	if alert.Message != "" {
-		parts = append(parts, alert.Message)
+	limit := ta.maxMessageSize
+	if limit <= 0 || limit > MessageSizeLimit {
+		limit = MessageSizeLimit
+	}
+	
+	message := assembleAlert(head, alert.Message, timestamp, metadata)
+	if len(message) <= limit {
+		return message
+	}
+	
+	return truncateAlert(head, alert.Message, timestamp, metadata, limit)
+}
+
+// assembleAlert joins the alert sections into the final message
+func assembleAlert(head []string, body, timestamp string, metadata []string) string {
+	parts := append([]string{}, head...)
+	
+	if body != "" {
+		parts = append(parts, body)
 	}
 	
-	// Add timestamp
 	parts = append(parts, fmt.Sprintf("📅 %s", timestamp))
 	
-	// Add metadata if present
-	if len(alert.Metadata) > 0 {
+	if len(metadata) > 0 {
 		parts = append(parts, "")
 		parts = append(parts, "*Details:*")
-		for key, value := range alert.Metadata {
-			parts = append(parts, fmt.Sprintf("• %s: %v", key, value))
+		parts = append(parts, metadata...)
+	}
+	
+	return strings.Join(parts, "\n")
+}
+
+// truncateAlert shrinks an oversized alert while keeping the header, title,
+// timestamp and the first metadata lines. The body is trimmed in the middle so
+// both its opening and its conclusion survive.
+func truncateAlert(head []string, body, timestamp string, metadata []string, limit int) string {
+	kept := make([]string, 0, MaxTruncatedMetadataLines+1)
+	for i, line := range metadata {
+		if i == MaxTruncatedMetadataLines {
+			kept = append(kept, fmt.Sprintf("• … %d more fields", len(metadata)-i))
+			break
+		}
+		if len(line) > MaxMetadataLineLength {
+			line = balanceMarkdown(cutAtRune(line, MaxMetadataLineLength-len("…"))) + "…"
 		}
+		kept = append(kept, line)
 	}
 	
-	message := strings.Join(parts, "\n")
+	frame := assembleAlert(head, "", timestamp, kept)
+	if body != "" {
+		budget := limit - len(frame) - len("\n")
+		if budget > len(TruncationMarker) {
+			body = trimMiddle(body, budget)
+		} else {
+			body = ""
+		}
+	}
 	
-	// Truncate if too long
-	if len(message) > MessageSizeLimit {
-		message = message[:MessageSizeLimit-3] + "..."
+	message := assembleAlert(head, body, timestamp, kept)
+	if len(message) > limit {
+		// Header and metadata alone exceed the limit; fall back to a hard cut
+		message = balanceMarkdown(cutAtRune(message, limit-len("…"))) + "…"
 	}
 	
 	return message
 }
 
-// sendWithRetries sends a message with retry logic
-func (ta *TelegramAlert) sendWithRetries(message string, alert *Alert) bool {
+// trimMiddle removes the middle of s so that it fits in max bytes
+func trimMiddle(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	
+	keep := max - len(TruncationMarker)
+	prefixLen := keep / 2
+	suffixLen := keep - prefixLen
+	
+	prefix := cutAtRune(s, prefixLen)
+	suffix := s[len(s)-suffixLen:]
+	for len(suffix) > 0 && !utf8.RuneStart(suffix[0]) {
+		suffix = suffix[1:]
+	}
+	
+	return balanceMarkdown(prefix) + TruncationMarker + balanceMarkdownSuffix(suffix)
+}
+
+// cutAtRune returns the longest prefix of s not exceeding n bytes that ends on a rune boundary
+func cutAtRune(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// balanceMarkdown drops dangling Markdown entity markers left behind by a cut,
+// so Telegram can still parse the message
+func balanceMarkdown(s string) string {
+	if strings.Count(s, "```")%2 != 0 {
+		idx := strings.LastIndex(s, "```")
+		s = s[:idx] + s[idx+3:]
+	}
+	
+	for _, marker := range []string{"`", "*", "_"} {
+		if strings.Count(s, marker)%2 != 0 {
+			idx := strings.LastIndex(s, marker)
+			s = s[:idx] + s[idx+1:]
+		}
+	}
+	
+	if open := strings.LastIndex(s, "["); open > strings.LastIndex(s, "]") {
+		s = s[:open] + s[open+1:]
+	}
+	
+	return s
+}
+
+// balanceMarkdownSuffix is balanceMarkdown for text whose beginning was cut off
+func balanceMarkdownSuffix(s string) string {
+	if strings.Count(s, "```")%2 != 0 {
+		idx := strings.Index(s, "```")
+		s = s[:idx] + s[idx+3:]
+	}
+	
+	for _, marker := range []string{"`", "*", "_"} {
+		if strings.Count(s, marker)%2 != 0 {
+			idx := strings.Index(s, marker)
+			s = s[:idx] + s[idx+1:]
+		}
+	}
+	
+	if closing := strings.Index(s, "]"); closing >= 0 && (strings.Index(s, "[") < 0 || closing < strings.Index(s, "[")) {
+		s = s[:closing] + s[closing+1:]
+	}
+	
+	return s
+}
+
+// deliver hands a formatted message for a chat to the configured sinks. The
+// file sink receives exactly the text Telegram would be sent; when it is the
+// only sink, writing the file counts as delivery.
+func (ta *TelegramAlert) deliver(chatID, message string, alert *Alert) bool {
+	if ta.channelsOnly() {
+		return false
+	}
+	
+	// A held alert was written to the file when it was first sent
+	if ta.fileSink != nil && !alert.Held {
+		now := time.Now()
+		if alert.FirstAttemptAt.IsZero() {
+			alert.FirstAttemptAt = now
+		}
+		err := ta.fileSink.Write(chatID, message, now)
+		if err != nil {
+			ta.fileSinkErrors++
+			log.Printf("Failed to write alert to file: %v", err)
+		}
+		if !ta.sendToTelegram {
+			if err != nil {
+				return false
+			}
+			if alert.DeliveredAt.IsZero() {
+				alert.DeliveredAt = now
+			}
+			return true
+		}
+	}
+	
+	return ta.sendWithRetries(chatID, message, alert)
+}
+
+// notifyChannels sends an alert to the further channels and returns whether
+// the alert counts as delivered: delivered, the outcome of Telegram or the
+// file, unless the channels are the only sink.
+func (ta *TelegramAlert) notifyChannels(alert *Alert, delivered bool) bool {
+	if ta.channels == nil {
+		return delivered
+	}
+	
+	err := ta.channels.SendAlertWithType(alert.Type, alert.Title, channelMessage(alert))
+	if err != nil {
+		log.Printf("Failed to send alert to channels: %s: %v", alert.Title, err)
+	}
+	if ta.channelsOnly() {
+		return err == nil
+	}
+	return delivered
+}
+
+// channelsOnly reports whether alerts go to the further channels only,
+// neither to Telegram nor to the alert file
+func (ta *TelegramAlert) channelsOnly() bool {
+	return ta.channels != nil && !ta.sendToTelegram && ta.fileSink == nil
+}
+
+// sendWithRetries sends a message to a chat with retry logic
+func (ta *TelegramAlert) sendWithRetries(chatID, message string, alert *Alert) bool {
+	alert.LastFailure = FailureNone
 	for attempt := 0; attempt < ta.maxRetries; attempt++ {
 		if attempt > 0 {
-			time.Sleep(ta.retryDelay)
+			// Retries continue during shutdown until the alerts' drain
+			// deadline, which records the alert for the next start
+			select {
+			case <-time.After(ta.retryDelay):
+			case <-alert.op.Context().Done():
+				log.Printf("Alert retries stopped by shutdown: %s", alert.Title)
+				return false
+			}
+		}
+		
+		if alert.FirstAttemptAt.IsZero() {
+			alert.FirstAttemptAt = time.Now()
 		}
 		
-		if ta.sendMessage(message) {
+		failure := ta.sendMessage(chatID, message)
+		if failure == FailureNone {
+			if alert.DeliveredAt.IsZero() {
+				alert.DeliveredAt = time.Now()
+			}
 			return true
 		}
 		
+		if ta.delivery.recordFailure(chatID, failure) {
+			ta.alertBotBlocked(chatID)
+		}
+		
+		alert.LastFailure = failure
 		alert.Retries++
 		alert.LastAttempt = time.Now()
 		
@@ -346,58 +1285,125 @@ func (ta *TelegramAlert) sendWithRetries(message string, alert *Alert) bool {
 	return false
 }
 
-// sendMessage sends a message to Telegram
-func (ta *TelegramAlert) sendMessage(message string) bool {
+// sendMessage sends a message to a Telegram chat and classifies any failure
+func (ta *TelegramAlert) sendMessage(chatID, message string) TelegramFailure {
+	failure, err := ta.postMessage(chatID, message)
+	if err != nil {
+		log.Printf("%v", err)
+	}
+	return failure
+}
+
+// postMessage sends a message to a Telegram chat, returning the failure class
+// and the underlying error
+func (ta *TelegramAlert) postMessage(chatID, message string) (TelegramFailure, error) {
 	if !ta.running {
-		return false
+		return FailureOther, fmt.Errorf("telegram alert system is not running")
 	}
 	
 	telegramMsg := TelegramMessage{
-		ChatID:    ta.chatID,
+		ChatID:    chatID,
 		Text:      message,
 		ParseMode: "Markdown",
 	}
 	
 	jsonData, err := json.Marshal(telegramMsg)
 	if err != nil {
-		log.Printf("Failed to marshal Telegram message: %v", err)
-		return false
+		return FailureOther, fmt.Errorf("failed to marshal Telegram message: %w", err)
 	}
 	
 	url := fmt.Sprintf("%s/sendMessage", ta.apiURL)
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		log.Printf("Failed to create Telegram request: %v", err)
-		return false
+		return FailureOther, fmt.Errorf("failed to create Telegram request: %w", err)
 	}
 	
 	req.Header.Set("Content-Type", "application/json")
 	
+	ta.apiRequests.Add(1)
 	resp, err := ta.client.Do(req)
 	if err != nil {
-		log.Printf("Failed to send Telegram message: %v", err)
-		return false
+		return classifyTransportError(err), fmt.Errorf("failed to send Telegram message: %w", err)
 	}
 	defer resp.Body.Close()
 	
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Failed to read Telegram response: %v", err)
-		return false
+		return classifyTransportError(err), fmt.Errorf("failed to read Telegram response: %w", err)
 	}
 	
 	var telegramResp TelegramResponse
 	if err := json.Unmarshal(body, &telegramResp); err != nil {
-		log.Printf("Failed to parse Telegram response: %v", err)
-		return false
+		return classifyTelegramError(resp.StatusCode), fmt.Errorf("failed to parse Telegram response (HTTP %d): %w", resp.StatusCode, err)
 	}
 	
 	if !telegramResp.OK {
-		log.Printf("Telegram API error: %d - %s", telegramResp.ErrorCode, telegramResp.Description)
-		return false
+		err := fmt.Errorf("telegram API error: %d - %s", telegramResp.ErrorCode, telegramResp.Description)
+		if telegramResp.ErrorCode == 0 {
+			return classifyTelegramError(resp.StatusCode), err
+		}
+		return classifyTelegramError(telegramResp.ErrorCode), err
 	}
 	
-	return true
+	return FailureNone, nil
+}
+
+// latencyThreshold returns the configured P95 delivery latency alert threshold
+func (ta *TelegramAlert) latencyThreshold() time.Duration {
+	if ta.config != nil && ta.config.AlertLatencyThreshold > 0 {
+		return ta.config.AlertLatencyThreshold
+	}
+	return DefaultAlertLatencyThreshold
+}
+
+// checkDeliveryLatency raises an alert when P95 delivery latency exceeds the threshold
+func (ta *TelegramAlert) checkDeliveryLatency(now time.Time) {
+	threshold := ta.latencyThreshold()
+	if !ta.delivery.latencyAlertDue(threshold, now) {
+		return
+	}
+	
+	p95 := ta.delivery.latencyPercentile(95)
+	log.Printf("Alert delivery is slow: P95 %s exceeds %s", p95, threshold)
+	ta.enqueueDeliveryAlert(&Alert{
+		ID:        fmt.Sprintf("delivery-latency-%d", now.UnixNano()),
+		Type:      AlertTypeWarning,
+		Priority:  AlertPriorityHigh,
+		Title:     "Slow Alert Delivery",
+		Message:   fmt.Sprintf("P95 alert delivery latency is %s, above the %s threshold", p95.Round(time.Second), threshold),
+		Timestamp: now,
+		Metadata: map[string]interface{}{
+			"p50": ta.delivery.latencyPercentile(50).Round(time.Second).String(),
+			"p95": p95.Round(time.Second).String(),
+		},
+	})
+}
+
+// alertBotBlocked raises an alert the first time a chat answers 403
+func (ta *TelegramAlert) alertBotBlocked(chatID string) {
+	log.Printf("Telegram returned 403 for chat %s: the bot was removed or blocked", chatID)
+	ta.enqueueDeliveryAlert(&Alert{
+		ID:        fmt.Sprintf("delivery-blocked-%d", time.Now().UnixNano()),
+		Type:      AlertTypeCritical,
+		Priority:  AlertPriorityHigh,
+		Title:     "Telegram Bot Blocked",
+		Message:   fmt.Sprintf("Telegram refused delivery to chat %s (403). The bot was removed from the chat or blocked.", chatID),
+		Timestamp: time.Now(),
+		Metadata: map[string]interface{}{
+			"chat_id": chatID,
+		},
+	})
+}
+
+// enqueueDeliveryAlert queues an alert about alert delivery itself. It is called
+// from the queue consumer, so it must never block.
+func (ta *TelegramAlert) enqueueDeliveryAlert(alert *Alert) {
+	alert.EnqueuedAt = time.Now()
+	select {
+	case ta.alertQueue <- alert:
+	default:
+		log.Printf("Alert queue full, dropping delivery alert: %s", alert.Title)
+	}
 }
 
 // addToHistory adds an alert to the history
@@ -442,119 +1448,222 @@ func (ta *TelegramAlert) SendAlertWithType(alertType AlertType, title, message s
 
 // SendRebalancerAlert sends a rebalancer state change alert
 func (ta *TelegramAlert) SendRebalancerAlert(state, reason string, price float64) error {
-	alert := &Alert{
-		ID:        fmt.Sprintf("rebalancer-%d", time.Now().UnixNano()),
+	return ta.QueueAlert(newRebalancerAlert(state, reason, price, time.Now()))
+}
+
+// newRebalancerAlert builds a rebalancer state change alert
+func newRebalancerAlert(state, reason string, price float64, now time.Time) *Alert {
+	return &Alert{
+		ID:        fmt.Sprintf("rebalancer-%d", now.UnixNano()),
 		Type:      AlertTypeWarning,
 		Priority:  AlertPriorityHigh,
 		Title:     "Rebalancer State Change",
 		Message:   reason,
-		Timestamp: time.Now(),
+		Timestamp: now,
 		Metadata: map[string]interface{}{
 			"state": state,
 			"price": fmt.Sprintf("$%.2f", price),
 		},
 	}
+}
+
+// SendValidatorAlert sends a validator-related alert to the global chat and the
+// chat configured for the validator in validator_chat_map
+func (ta *TelegramAlert) SendValidatorAlert(validatorName, validatorAddress, reason string, inactiveDays int) error {
+	alert := newValidatorAlert(validatorName, validatorAddress, reason, inactiveDays, time.Now())
+	if chatID := ta.validatorChatID(validatorAddress); chatID != "" {
+		alert.Routes = append(alert.Routes, chatID)
+	}
+	
+	return ta.QueueAlert(alert)
+}
+
+// SendValidatorAlertWithType sends an alert about a validator to the global
+// chat and the chat configured for the validator in validator_chat_map
+func (ta *TelegramAlert) SendValidatorAlertWithType(alertType AlertType, validatorAddress, title, message string) error {
+	alert := &Alert{
+		ID:        fmt.Sprintf("validator-%d", time.Now().UnixNano()),
+		Type:      alertType,
+		Priority:  AlertPriorityHigh,
+		Title:     title,
+		Message:   message,
+		Timestamp: time.Now(),
+		Metadata: map[string]interface{}{
+			MetadataValidatorAddress: validatorAddress,
+		},
+	}
+	if chatID := ta.validatorChatID(validatorAddress); chatID != "" {
+		alert.Routes = append(alert.Routes, chatID)
+	}
 	
 	return ta.QueueAlert(alert)
 }
 
-// SendValidatorAlert sends a validator-related alert
-func (ta *TelegramAlert) SendValidatorAlert(validatorName, reason string, inactiveDays int) error {
+// newValidatorAlert builds a validator inactivity alert
+func newValidatorAlert(validatorName, validatorAddress, reason string, inactiveDays int, now time.Time) *Alert {
 	alertType := AlertTypeWarning
 	if inactiveDays > 10 {
 		alertType = AlertTypeCritical
 	}
 	
-	alert := &Alert{
-		ID:        fmt.Sprintf("validator-%d", time.Now().UnixNano()),
+	return &Alert{
+		ID:        fmt.Sprintf("validator-%d", now.UnixNano()),
 		Type:      alertType,
 		Priority:  AlertPriorityHigh,
 		Title:     "Validator Inactivity",
 		Message:   reason,
-		Timestamp: time.Now(),
+		Timestamp: now,
 		Metadata: map[string]interface{}{
-			"validator":      validatorName,
-			"inactive_days":  inactiveDays,
-			"threshold":      10,
+			"validator":              validatorName,
+			"inactive_days":          inactiveDays,
+			"threshold":              10,
+			MetadataValidatorAddress: validatorAddress,
 		},
 	}
-	
-	return ta.QueueAlert(alert)
 }
 
 // SendBotAlert sends a bot-related alert
 func (ta *TelegramAlert) SendBotAlert(botType, status, reason string) error {
+	return ta.QueueAlert(newBotAlert(botType, status, reason, time.Now()))
+}
+
+// newBotAlert builds a bot status alert
+func newBotAlert(botType, status, reason string, now time.Time) *Alert {
 	alertType := AlertTypeWarning
 	if status == "error" || status == "stopped" {
 		alertType = AlertTypeError
 	}
 	
-	alert := &Alert{
-		ID:        fmt.Sprintf("bot-%d", time.Now().UnixNano()),
+	return &Alert{
+		ID:        fmt.Sprintf("bot-%d", now.UnixNano()),
 		Type:      alertType,
 		Priority:  AlertPriorityMedium,
 		Title:     fmt.Sprintf("Bot Status: %s", botType),
 		Message:   reason,
-		Timestamp: time.Now(),
+		Timestamp: now,
 		Metadata: map[string]interface{}{
 			"bot_type": botType,
 			"status":   status,
 		},
 	}
-	
-	return ta.QueueAlert(alert)
 }
 
 // SendHalvingAlert sends a halving-related alert
 func (ta *TelegramAlert) SendHalvingAlert(cycle uint64, event, details string) error {
-	alert := &Alert{
-		ID:        fmt.Sprintf("halving-%d", time.Now().UnixNano()),
+	return ta.QueueAlert(newHalvingAlert(cycle, event, details, time.Now()))
+}
+
+// newHalvingAlert builds a halving event alert
+func newHalvingAlert(cycle uint64, event, details string, now time.Time) *Alert {
+	return &Alert{
+		ID:        fmt.Sprintf("halving-%d", now.UnixNano()),
 		Type:      AlertTypeInfo,
 		Priority:  AlertPriorityMedium,
 		Title:     "Halving Event",
 		Message:   fmt.Sprintf("Cycle %d: %s", cycle, event),
-		Timestamp: time.Now(),
+		Timestamp: now,
 		Metadata: map[string]interface{}{
 			"cycle":   cycle,
 			"event":   event,
 			"details": details,
 		},
 	}
-	
-	return ta.QueueAlert(alert)
 }
 
 // SendEmergencyAlert sends a high-priority emergency alert
 func (ta *TelegramAlert) SendEmergencyAlert(title, message string, metadata map[string]interface{}) error {
-	alert := &Alert{
-		ID:        fmt.Sprintf("emergency-%d", time.Now().UnixNano()),
-		Type:      AlertTypeCritical,
-		Priority:  AlertPriorityHigh,
-		Title:     title,
-		Message:   message,
-		Timestamp: time.Now(),
-		Metadata:  metadata,
+	return ta.QueueAlert(newEmergencyAlert(title, message, metadata, time.Now()))
+}
+
+// newEmergencyAlert builds an emergency alert, which bypasses rate limiting
+func newEmergencyAlert(title, message string, metadata map[string]interface{}, now time.Time) *Alert {
+	return &Alert{
+		ID:              fmt.Sprintf("emergency-%d", now.UnixNano()),
+		Type:            AlertTypeCritical,
+		Priority:        AlertPriorityHigh,
+		Title:           title,
+		Message:         message,
+		Timestamp:       now,
+		Metadata:        metadata,
+		BypassRateLimit: true,
 	}
-	
-	// Emergency alerts bypass rate limiting
-	oldRateLimit := ta.rateLimitEnabled
-	ta.rateLimitEnabled = false
-	defer func() { ta.rateLimitEnabled = oldRateLimit }()
-	
-	return ta.QueueAlert(alert)
 }
 
-// QueueAlert adds an alert to the processing queue
+// QueueAlert adds an alert to the processing queue. It never blocks: when
+// the queue is full, its oldest alert moves to the spill buffer, or is
+// dropped without one, to make room.
 func (ta *TelegramAlert) QueueAlert(alert *Alert) error {
 	if !ta.running {
 		return fmt.Errorf("telegram alert system is not running")
 	}
 	
+	if alert.EnqueuedAt.IsZero() {
+		alert.EnqueuedAt = time.Now()
+	}
+	
+	op, err := ta.drain.Begin(context.Background(), "alert", alert.Title, alert)
+	if err != nil {
+		return err
+	}
+	alert.op = op
+	
 	select {
 	case ta.alertQueue <- alert:
 		return nil
-	case <-time.After(5 * time.Second):
-		return fmt.Errorf("alert queue is full")
+	default:
+	}
+	
+	select {
+	case oldest := <-ta.alertQueue:
+		ta.overflowAlert(oldest)
+	default:
+	}
+	select {
+	case ta.alertQueue <- alert:
+	default:
+		// Other callers took the freed slot first
+		ta.overflowAlert(alert)
+	}
+	return nil
+}
+
+// overflowAlert takes an alert evicted from the full queue into the spill
+// buffer, or drops it without one
+func (ta *TelegramAlert) overflowAlert(alert *Alert) {
+	ta.evictedAlerts.Add(1)
+	alert.op.Done()
+	alert.op = nil
+	
+	if spill := ta.spill.Load(); spill != nil {
+		spill.Push(alert)
+		return
+	}
+	ta.droppedAlerts.Add(1)
+	log.Printf("Alert queue full, dropping oldest alert: %s", alert.Title)
+}
+
+// SetAlertSpill sets the spill buffer overflowing alerts and alerts waiting
+// for Telegram are kept in; dispatchers may share one
+func (ta *TelegramAlert) SetAlertSpill(spill *AlertSpill) {
+	ta.spill.Store(spill)
+}
+
+// Resume queues the alerts the last shutdown could not deliver
+func (ta *TelegramAlert) Resume(intents []Intent) {
+	for _, intent := range intents {
+		var alert Alert
+		if err := intent.Decode(&alert); err != nil {
+			log.Printf("Ignoring interrupted alert %q: %v", intent.Description, err)
+			continue
+		}
+		alert.EnqueuedAt, alert.FirstAttemptAt, alert.DeliveredAt = time.Time{}, time.Time{}, time.Time{}
+		if err := ta.QueueAlert(&alert); err != nil {
+			log.Printf("Failed to resume interrupted alert %q: %v", alert.Title, err)
+			ta.drain.Record("alert", alert.Title, &alert)
+		}
+	}
+	if len(intents) > 0 {
+		log.Printf("Resumed %d alerts interrupted by the last shutdown", len(intents))
 	}
 }
 
@@ -567,6 +1676,14 @@ func (ta *TelegramAlert) EnableRateLimit(enabled bool) {
 	log.Printf("Telegram rate limiting %s", map[bool]string{true: "enabled", false: "disabled"}[enabled])
 }
 
+// APIRequests returns the Telegram Bot API requests made since the bot started
+func (ta *TelegramAlert) APIRequests() int64 {
+	if ta == nil {
+		return 0
+	}
+	return ta.apiRequests.Load()
+}
+
 // GetStatistics returns alert statistics
 func (ta *TelegramAlert) GetStatistics() map[string]interface{} {
 	ta.mu.RLock()
@@ -584,6 +1701,64 @@ func (ta *TelegramAlert) GetStatistics() map[string]interface{} {
 		"max_rate_per_minute":  MaxAlertsPerMinute,
 		"alert_history_size":   len(ta.alertHistory),
 		"running":              ta.running,
+		"quiet_hours_enabled":  ta.quietHours != nil,
+		"quiet_hours_active":   ta.quietHours != nil && ta.quietHours.Contains(time.Now()),
+		"pending_digest_count": len(ta.digestBuffer),
+		"digests_sent":         ta.digestsSent,
+	}
+	
+	// Alert spike detection
+	if ta.anomaly != nil {
+		stats["alert_anomaly"] = ta.anomaly.GetStatus(time.Now())
+	}
+	
+	// Per-validator routing
+	stats["validator_routed_alerts"] = ta.validatorRoutedAlerts
+	if ta.config != nil {
+		stats["validator_routes"] = len(ta.config.ValidatorChatMap)
+	}
+	
+	// On-call rotation
+	if ta.onCall != nil {
+		stats["on_call"] = ta.onCall.Status(time.Now())
+		stats["on_call_alerts"] = ta.onCallAlerts
+		stats["on_call_handovers"] = ta.onCallHandovers
+	}
+	
+	// Incident correlation
+	if tracker := ta.incidents.Load(); tracker != nil {
+		stats["incidents"] = tracker.Status()
+	}
+	
+	// Queue guardrails and the alerts waiting for Telegram
+	stats["queue_capacity"] = cap(ta.alertQueue)
+	stats["queue_evicted"] = ta.evictedAlerts.Load()
+	stats["queue_dropped"] = ta.droppedAlerts.Load()
+	stats["api_requests"] = ta.apiRequests.Load()
+	stats["telegram_reachable"] = !ta.offline
+	stats["backlog_age_seconds"] = int64(ta.backlogAge(time.Now()).Seconds())
+	spilled := 0
+	if spill := ta.spill.Load(); spill != nil {
+		spilled = spill.Len()
+		stats["alert_spill"] = spill.Status()
+	}
+	stats["spilled_alerts"] = spilled
+	
+	// Delivery latency and Telegram failure breakdown
+	stats["delivery_latency_p50_ms"] = ta.delivery.latencyPercentile(50).Milliseconds()
+	stats["delivery_latency_p95_ms"] = ta.delivery.latencyPercentile(95).Milliseconds()
+	stats["first_attempt_latency_p95_ms"] = percentile(ta.delivery.firstAttemptLag, 95).Milliseconds()
+	stats["delivery_failures"] = ta.delivery.failureCounts()
+	stats["blocked_chats"] = len(ta.delivery.blockedChats)
+	
+	// Output sinks
+	stats["telegram_sink"] = ta.sendToTelegram
+	if ta.fileSink != nil {
+		stats["alert_file"] = ta.fileSink.Path()
+		stats["alert_file_errors"] = ta.fileSinkErrors
+	}
+	if ta.channels != nil {
+		stats["channels"] = ta.channels.Status()
 	}
 	
 	// Add alert counts by type
@@ -613,8 +1788,12 @@ func (ta *TelegramAlert) TestConnection() error {
 	if !ta.running {
 		return fmt.Errorf("telegram alert system is not running")
 	}
+	if !ta.sendToTelegram {
+		return nil
+	}
 	
 	url := fmt.Sprintf("%s/getMe", ta.apiURL)
+	ta.apiRequests.Add(1)
 	resp, err := ta.client.Get(url)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Telegram: %w", err)
@@ -654,6 +1833,18 @@ func (ta *TelegramAlert) Stop() {
 	
 	ta.running = false
 	close(ta.stopChan)
+	if ta.channels != nil {
+		ta.channels.Stop()
+	}
+	
+	// Alerts held for the quiet hours digest are sent after the next start
+	for _, alert := range ta.digestBuffer {
+		ta.drain.Record("alert", alert.Title, alert)
+	}
+	ta.digestBuffer = nil
+	
+	// Spilled alerts are delivered after the next start
+	ta.saveSpill()
 	
 	log.Printf("Telegram alert system stopped - Final stats: %d total alerts, %d successful, %d failed", 
 		ta.totalAlerts, ta.successfulAlerts, ta.failedAlerts)
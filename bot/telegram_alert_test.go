@@ -0,0 +1,443 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestFormatAlertTruncatesOversizedMetadata(t *testing.T) {
+	ta := &TelegramAlert{maxMessageSize: 1024}
+
+	metadata := make(map[string]interface{})
+	for i := 0; i < 300; i++ {
+		metadata[fmt.Sprintf("key-%03d", i)] = strings.Repeat("v", 50)
+	}
+
+	body := "Start of *incident* report 🚨\n" + strings.Repeat("line with *bold* and `code` ✅\n", 200) + "Final *conclusion*"
+	alert := &Alert{
+		Type:      AlertTypeCritical,
+		Title:     "Validator Jailed",
+		Message:   body,
+		Timestamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Metadata:  metadata,
+	}
+
+	message := ta.formatAlert(alert)
+
+	if len(message) > 1024 {
+		t.Fatalf("message exceeds limit: %d bytes", len(message))
+	}
+	if !utf8.ValidString(message) {
+		t.Fatal("message is not valid UTF-8")
+	}
+
+	for _, want := range []string{
+		"🚨 *CRITICAL*",
+		"*Validator Jailed*",
+		"Start of *incident* report",
+		"Final *conclusion*",
+		"📅 2025-03-01 12:00:00",
+		"• key-000: ",
+		"• key-004: ",
+		"• … 295 more fields",
+		strings.TrimSpace(TruncationMarker),
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("truncated message missing %q:\n%s", want, message)
+		}
+	}
+
+	if strings.Contains(message, "key-005") {
+		t.Error("metadata beyond the kept lines should be dropped")
+	}
+
+	for _, marker := range []string{"*", "`"} {
+		if strings.Count(message, marker)%2 != 0 {
+			t.Errorf("unbalanced %q in truncated message", marker)
+		}
+	}
+}
+
+func TestFormatAlertKeepsShortMessages(t *testing.T) {
+	ta := &TelegramAlert{maxMessageSize: MessageSizeLimit}
+
+	alert := &Alert{
+		Type:      AlertTypeInfo,
+		Title:     "Heartbeat",
+		Message:   "All systems nominal",
+		Timestamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Metadata:  map[string]interface{}{"b": 2, "a": 1},
+	}
+
+	want := "ℹ️ *INFO*\n*Heartbeat*\nAll systems nominal\n📅 2025-03-01 12:00:00\n\n*Details:*\n• a: 1\n• b: 2"
+	if got := ta.formatAlert(alert); got != want {
+		t.Fatalf("unexpected message:\n%s", got)
+	}
+}
+
+func TestBalanceMarkdown(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"some *bold* text", "some *bold* text"},
+		{"cut in *bol", "cut in bol"},
+		{"open ```code", "open code"},
+		{"link [text", "link text"},
+		{"[ok](url) and _it", "[ok](url) and it"},
+	}
+
+	for _, tc := range testCases {
+		if got := balanceMarkdown(tc.in); got != tc.want {
+			t.Errorf("balanceMarkdown(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+
+	if got := balanceMarkdownSuffix("ld* and *more*"); got != "ld and *more*" {
+		t.Errorf("balanceMarkdownSuffix dropped the wrong marker: %q", got)
+	}
+}
+
+func TestValidatorAlertsRoutedToMappedChat(t *testing.T) {
+	var mu sync.Mutex
+	var chats []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		chats = append(chats, msg.ChatID)
+		mu.Unlock()
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	ta := &TelegramAlert{
+		config: &BotConfig{ValidatorChatMap: map[string]string{
+			"gxrvaloper1customer": "-100200",
+			"gxrvaloper1shared":   "-100100",
+		}},
+		client:         server.Client(),
+		apiURL:         server.URL,
+		chatID:         "-100100",
+		maxRetries:     1,
+		maxMessageSize: MessageSizeLimit,
+		alertCounts:    make(map[AlertType]int64),
+		running:        true,
+	}
+
+	testCases := []struct {
+		name  string
+		alert *Alert
+		want  []string
+	}{
+		{"no validator", &Alert{Title: "Bot", Metadata: map[string]interface{}{}}, []string{"-100100"}},
+		{"unmapped validator", &Alert{Title: "Jailed", Metadata: map[string]interface{}{MetadataValidatorAddress: "gxrvaloper1other"}}, []string{"-100100"}},
+		{"mapped validator", &Alert{Title: "Jailed", Metadata: map[string]interface{}{MetadataValidatorAddress: "gxrvaloper1customer"}}, []string{"-100100", "-100200"}},
+		{"mapped to global chat", &Alert{Title: "Jailed", Metadata: map[string]interface{}{MetadataValidatorAddress: "gxrvaloper1shared"}}, []string{"-100100"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			chats = nil
+			mu.Unlock()
+
+			ta.handleAlert(tc.alert)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if fmt.Sprint(chats) != fmt.Sprint(tc.want) {
+				t.Fatalf("delivered to %v, want %v", chats, tc.want)
+			}
+		})
+	}
+
+	// SendValidatorAlert includes the route and the address in the alert itself
+	ta.alertQueue = make(chan *Alert, 1)
+	if err := ta.SendValidatorAlert("customer", "gxrvaloper1customer", "inactive", 3); err != nil {
+		t.Fatal(err)
+	}
+	alert := <-ta.alertQueue
+	if fmt.Sprint(alert.Routes) != "[-100200]" || alert.Metadata[MetadataValidatorAddress] != "gxrvaloper1customer" {
+		t.Fatalf("validator alert missing route: %+v", alert)
+	}
+}
+
+func TestQuietHoursDigestKeepsValidatorRoutes(t *testing.T) {
+	var mu sync.Mutex
+	messages := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		messages[msg.ChatID] = msg.Text
+		mu.Unlock()
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	ta := &TelegramAlert{
+		config:         &BotConfig{ValidatorChatMap: map[string]string{"gxrvaloper1customer": "-100200"}},
+		client:         server.Client(),
+		apiURL:         server.URL,
+		chatID:         "-100100",
+		maxRetries:     1,
+		maxMessageSize: MessageSizeLimit,
+		alertCounts:    make(map[AlertType]int64),
+		running:        true,
+	}
+	now := time.Date(2025, 6, 1, 7, 0, 0, 0, time.UTC)
+	ta.digestBuffer = []*Alert{
+		{Title: "Bot Inactivity", Type: AlertTypeWarning, Timestamp: now.Add(-3 * time.Hour),
+			Metadata: map[string]interface{}{MetadataValidatorAddress: "gxrvaloper1customer"}},
+		{Title: "Rebalancer Paused", Type: AlertTypeWarning, Timestamp: now.Add(-2 * time.Hour), Metadata: map[string]interface{}{}},
+	}
+
+	ta.flushDigestIfDue(now)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(messages) != 2 {
+		t.Fatalf("digest delivered to %v", messages)
+	}
+	if global := messages["-100100"]; !strings.Contains(global, "Bot Inactivity") || !strings.Contains(global, "Rebalancer Paused") {
+		t.Fatalf("global digest = %q", global)
+	}
+	if routed := messages["-100200"]; !strings.Contains(routed, "Bot Inactivity") || strings.Contains(routed, "Rebalancer Paused") {
+		t.Fatalf("routed digest = %q", routed)
+	}
+	if ta.validatorRoutedAlerts != 1 || len(ta.digestBuffer) != 0 {
+		t.Fatalf("routed %d, %d alerts still buffered", ta.validatorRoutedAlerts, len(ta.digestBuffer))
+	}
+}
+
+func TestValidatorMonitorAlertsCarryTheValidatorRoute(t *testing.T) {
+	ta := newTelegramAlert(&BotConfig{ValidatorChatMap: map[string]string{"gxrvaloper1customer": "-100200"}})
+	ta.running = true
+	vm := &ValidatorMonitor{telegramAlert: ta}
+	status := &ValidatorStatus{OperatorAddress: "gxrvaloper1customer", Moniker: "customer", InactiveDays: 11}
+
+	sends := []func(){
+		func() { vm.markValidatorInactive(status) },
+		func() { vm.sendBotInactivityAlert(status) },
+	}
+	for _, send := range sends {
+		vm.lastAlertTime = time.Time{}
+		send()
+		if len(ta.alertQueue) != 1 {
+			t.Fatalf("expected one alert, got %d", len(ta.alertQueue))
+		}
+		alert := <-ta.alertQueue
+		if fmt.Sprint(alert.Routes) != "[-100200]" || alert.Metadata[MetadataValidatorAddress] != "gxrvaloper1customer" {
+			t.Fatalf("%s alert missing its route: %+v", alert.Title, alert)
+		}
+	}
+}
+
+func TestDeliveryFailureTaxonomy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+
+		switch msg.ChatID {
+		case "429":
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 3"}`))
+		case "400":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities"}`))
+		case "403":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was kicked from the group chat"}`))
+		case "timeout":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.Timeout = 50 * time.Millisecond
+
+	ta := &TelegramAlert{
+		config:         &BotConfig{},
+		client:         client,
+		apiURL:         server.URL,
+		maxRetries:     1,
+		maxMessageSize: MessageSizeLimit,
+		alertCounts:    make(map[AlertType]int64),
+		alertQueue:     make(chan *Alert, 10),
+		running:        true,
+	}
+
+	for _, chatID := range []string{"429", "400", "403", "403", "timeout", "ok"} {
+		ta.chatID = chatID
+		ta.handleAlert(&Alert{Title: "Jailed", Metadata: map[string]interface{}{}, EnqueuedAt: time.Now()})
+	}
+
+	stats := ta.GetStatistics()
+	failures := stats["delivery_failures"].(map[string]int64)
+	want := map[string]int64{"rate_limited": 1, "parse_error": 1, "blocked": 2, "timeout": 1}
+	if fmt.Sprint(failures) != fmt.Sprint(want) {
+		t.Fatalf("failure breakdown = %v, want %v", failures, want)
+	}
+	if stats["successful_alerts"].(int64) != 1 || stats["failed_alerts"].(int64) != 5 {
+		t.Fatalf("unexpected success/failure counts: %v", stats)
+	}
+
+	// The first 403 for a chat raises exactly one critical alert
+	if len(ta.alertQueue) != 1 {
+		t.Fatalf("expected one blocked-bot alert, got %d queued", len(ta.alertQueue))
+	}
+	if blocked := <-ta.alertQueue; blocked.Type != AlertTypeCritical || blocked.Metadata["chat_id"] != "403" {
+		t.Fatalf("unexpected blocked alert: %+v", blocked)
+	}
+	if stats["blocked_chats"].(int) != 1 {
+		t.Fatalf("blocked_chats = %v", stats["blocked_chats"])
+	}
+}
+
+func TestDeliveryLatencyPercentilesAndAlert(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	ta := &TelegramAlert{
+		config:         &BotConfig{AlertLatencyThreshold: 10 * time.Minute},
+		client:         server.Client(),
+		apiURL:         server.URL,
+		chatID:         "-100100",
+		maxRetries:     1,
+		maxMessageSize: MessageSizeLimit,
+		alertCounts:    make(map[AlertType]int64),
+		alertQueue:     make(chan *Alert, 10),
+		running:        true,
+	}
+
+	// 18 alerts delivered within a minute, then 2 that waited 40 minutes
+	for i := 0; i < MinLatencySamplesForAlert; i++ {
+		wait := time.Duration(i+1) * time.Second
+		if i >= MinLatencySamplesForAlert-2 {
+			wait = 40 * time.Minute
+		}
+		ta.handleAlert(&Alert{Title: "Slashing", Metadata: map[string]interface{}{}, EnqueuedAt: time.Now().Add(-wait)})
+	}
+
+	stats := ta.GetStatistics()
+	if p50 := stats["delivery_latency_p50_ms"].(int64); p50 < 10_000 || p50 > 11_000 {
+		t.Fatalf("p50 = %dms, want about 10s", p50)
+	}
+	if p95 := stats["delivery_latency_p95_ms"].(int64); p95 < (40 * time.Minute).Milliseconds() {
+		t.Fatalf("p95 = %dms, want about 40m", p95)
+	}
+
+	if len(ta.alertQueue) != 1 {
+		t.Fatalf("expected one latency alert, got %d queued", len(ta.alertQueue))
+	}
+	if alert := <-ta.alertQueue; alert.Title != "Slow Alert Delivery" {
+		t.Fatalf("unexpected alert %q", alert.Title)
+	}
+
+	// The cooldown suppresses a second alert
+	ta.handleAlert(&Alert{Title: "Slashing", Metadata: map[string]interface{}{}, EnqueuedAt: time.Now().Add(-time.Hour)})
+	if len(ta.alertQueue) != 0 {
+		t.Fatal("latency alert should respect the cooldown")
+	}
+
+	if summary := ta.delivery.summary(); !strings.HasPrefix(summary, "Delivery: p50 11s, p95 ") {
+		t.Fatalf("unexpected digest summary %q", summary)
+	}
+}
+
+func TestEmergencyAlertsBypassRateLimitConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	delivered := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		for _, title := range []string{"Chain Halted", "Heartbeat Late"} {
+			if strings.Contains(msg.Text, title) {
+				delivered[title]++
+			}
+		}
+		mu.Unlock()
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	ta := newTelegramAlert(&BotConfig{})
+	ta.client = server.Client()
+	ta.apiURL = server.URL
+	ta.chatID = "-100100"
+	ta.maxRetries = 1
+	ta.sendToTelegram = true
+	ta.running = true
+	go ta.processAlerts()
+	defer close(ta.stopChan)
+
+	testCases := []struct {
+		title string
+		send  func() error
+		want  int
+	}{
+		{"Chain Halted", func() error {
+			return ta.SendEmergencyAlert("Chain Halted", "no new blocks", map[string]interface{}{})
+		}, 50},
+		{"Heartbeat Late", func() error {
+			return ta.SendAlertWithType(AlertTypeWarning, "Heartbeat Late", "heartbeat overdue")
+		}, MaxAlertsPerMinute},
+	}
+
+	var wg sync.WaitGroup
+	for _, tc := range testCases {
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(send func() error) {
+				defer wg.Done()
+				if err := send(); err != nil {
+					t.Error(err)
+				}
+			}(tc.send)
+		}
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		ta.mu.RLock()
+		handled := ta.totalAlerts + ta.rateLimitedAlerts
+		ta.mu.RUnlock()
+		if handled == 100 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("handled %d of 100 alerts", handled)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, tc := range testCases {
+		if delivered[tc.title] != tc.want {
+			t.Errorf("%s: delivered %d, want %d", tc.title, delivered[tc.title], tc.want)
+		}
+	}
+	ta.mu.RLock()
+	defer ta.mu.RUnlock()
+	if !ta.rateLimitEnabled || ta.rateLimitedAlerts != int64(50-MaxAlertsPerMinute) {
+		t.Fatalf("rate limit enabled %v, %d alerts rate limited", ta.rateLimitEnabled, ta.rateLimitedAlerts)
+	}
+}
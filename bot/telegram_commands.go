@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CommandPollTimeout is the long-polling timeout of getUpdates, below the HTTP client timeout
+const CommandPollTimeout = 25 * time.Second
+
+// telegramUpdate is the part of a Telegram update the bot reads
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+// telegramUpdatesResponse is the getUpdates response
+type telegramUpdatesResponse struct {
+	OK          bool             `json:"ok"`
+	Result      []telegramUpdate `json:"result"`
+	ErrorCode   int              `json:"error_code,omitempty"`
+	Description string           `json:"description,omitempty"`
+}
+
+// pollCommands answers bot commands sent in the alert chats until the alert
+// system stops
+func (ta *TelegramAlert) pollCommands() {
+	var offset int64
+	for {
+		select {
+		case <-ta.stopChan:
+			return
+		default:
+		}
+
+		updates, err := ta.getUpdates(offset, CommandPollTimeout)
+		if err != nil {
+			log.Printf("Failed to poll Telegram commands: %v", err)
+			select {
+			case <-ta.stopChan:
+				return
+			case <-time.After(ta.retryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			ta.handleUpdate(update, time.Now())
+		}
+	}
+}
+
+// getUpdates long-polls Telegram for messages after offset
+func (ta *TelegramAlert) getUpdates(offset int64, timeout time.Duration) ([]telegramUpdate, error) {
+	query := url.Values{}
+	query.Set("offset", strconv.FormatInt(offset, 10))
+	query.Set("timeout", strconv.Itoa(int(timeout.Seconds())))
+	query.Set("allowed_updates", `["message"]`)
+
+	ta.apiRequests.Add(1)
+	resp, err := ta.client.Get(fmt.Sprintf("%s/getUpdates?%s", ta.apiURL, query.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Telegram updates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Telegram updates: %w", err)
+	}
+
+	var updatesResp telegramUpdatesResponse
+	if err := json.Unmarshal(body, &updatesResp); err != nil {
+		return nil, fmt.Errorf("failed to parse Telegram updates (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if !updatesResp.OK {
+		return nil, fmt.Errorf("telegram API error: %d - %s", updatesResp.ErrorCode, updatesResp.Description)
+	}
+
+	return updatesResp.Result, nil
+}
+
+// handleUpdate replies to a command message
+func (ta *TelegramAlert) handleUpdate(update telegramUpdate, now time.Time) {
+	if update.Message == nil {
+		return
+	}
+
+	chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+	reply, ok := ta.commandReply(chatID, update.Message.Text, now)
+	if !ok {
+		return
+	}
+
+	if _, err := ta.postMessage(chatID, reply); err != nil {
+		log.Printf("Failed to answer Telegram command in chat %s: %v", chatID, err)
+	}
+}
+
+// commandReply returns the reply to a command. Only the alert chats get
+// answers, other chats are ignored.
+func (ta *TelegramAlert) commandReply(chatID, text string, now time.Time) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !ta.isAlertChat(chatID) {
+		return "", false
+	}
+
+	// Commands in groups may be addressed as /command@botname
+	command := strings.SplitN(fields[0], "@", 2)[0]
+	switch command {
+	case "/oncall":
+		return ta.onCallReply(now), true
+	case "/incidents":
+		return ta.incidentsReply(now), true
+	case "/status":
+		return ta.statusReply(now), true
+	default:
+		return "", false
+	}
+}
+
+// isAlertChat reports whether alerts are sent to chatID
+func (ta *TelegramAlert) isAlertChat(chatID string) bool {
+	for _, configured := range ta.configuredChats() {
+		if configured == chatID {
+			return true
+		}
+	}
+	if ta.onCall != nil {
+		for _, member := range ta.onCall.members {
+			if member.ChatID == chatID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracingServiceName is the service.name of the bot's spans
+	TracingServiceName = "gxr-bot"
+	// OTLPTracesPath is where spans are sent when otel_endpoint has no path
+	OTLPTracesPath = "/v1/traces"
+	// TracingShutdownTimeout bounds flushing the buffered spans on exit
+	TracingShutdownTimeout = 5 * time.Second
+)
+
+// Attributes of the bot's spans
+const (
+	traceChainID    = attribute.Key("gxr.chain_id")
+	traceCycle      = attribute.Key("gxr.cycle")
+	traceAmount     = attribute.Key("gxr.amount_ugen")
+	traceTxHash     = attribute.Key("tx.hash")
+	traceChannel    = attribute.Key("ibc.channel")
+	traceSequence   = attribute.Key("ibc.sequence")
+	tracePool       = attribute.Key("dex.pool")
+	traceAlertType  = attribute.Key("alert.type")
+	traceAlertTitle = attribute.Key("alert.title")
+)
+
+// tracerRef holds the tracer of the configured provider
+type tracerRef struct {
+	trace.Tracer
+}
+
+// botTracer creates the bot's spans. It is nil while tracing is disabled,
+// so an operation then costs an atomic load and no span is allocated.
+var botTracer atomic.Pointer[tracerRef]
+
+// noopSpan is returned for every span while tracing is disabled
+var noopSpan = trace.SpanFromContext(context.Background())
+
+// ValidateOtelEndpoint checks the otel_endpoint setting; empty disables tracing
+func ValidateOtelEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid otel_endpoint %q, expected the collector's OTLP/HTTP URL such as http://localhost:4318", endpoint)
+	}
+	return nil
+}
+
+// otlpTracesURL returns the URL spans are posted to: endpoint itself when it
+// has a path, the collector's default traces path otherwise
+func otlpTracesURL(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil && strings.Trim(u.Path, "/") == "" {
+		u.Path = OTLPTracesPath
+		return u.String()
+	}
+	return endpoint
+}
+
+// SetupTracing exports the bot's spans to config.OtelEndpoint over OTLP/HTTP.
+// Without an endpoint tracing stays disabled. The returned function flushes
+// the spans still buffered and stops the exporter.
+func SetupTracing(ctx context.Context, config *BotConfig) (func(context.Context) error, error) {
+	if config.OtelEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if err := ValidateOtelEndpoint(config.OtelEndpoint); err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(otlpTracesURL(config.OtelEndpoint)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", TracingServiceName),
+			attribute.String("service.version", Version),
+			traceChainID.String(config.ChainID),
+		)),
+	)
+	useTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		useTracerProvider(nil)
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// useTracerProvider creates the bot's spans with provider; nil disables tracing
+func useTracerProvider(provider trace.TracerProvider) {
+	if provider == nil {
+		botTracer.Store(nil)
+		return
+	}
+	botTracer.Store(&tracerRef{provider.Tracer(TracingServiceName)})
+}
+
+// startSpan starts a span named name as a child of the span in ctx
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ref := botTracer.Load()
+	if ref == nil {
+		return ctx, noopSpan
+	}
+	return ref.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, marking it failed with err
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceStep runs step in a child span named name
+func traceStep(ctx context.Context, name string, step func(ctx context.Context, span trace.Span) error) error {
+	ctx, span := startSpan(ctx, name)
+	err := step(ctx, span)
+	endSpan(span, err)
+	return err
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans traces into an in-memory exporter until the test ends
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	useTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { useTracerProvider(nil) })
+	return exporter
+}
+
+// spanAttr returns the value of key on span
+func spanAttr(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestDistributionTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"pending_dex_allocation":{"cycle":"2","amount":{"denom":"ugen","amount":"1000"}}}`))
+	}))
+	defer server.Close()
+
+	exporter := recordSpans(t)
+	rd := NewRewardDistributor(&BotConfig{ChainID: "gxr-1", ChainAPI: server.URL})
+	rd.confirmDelay = 0
+
+	if err := rd.distributeHalvingRewards(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Steps end before their parent, in the order they ran
+	spans := exporter.GetSpans()
+	want := []string{"distribution.query", "distribution.build", "distribution.sign",
+		"distribution.broadcast", "distribution.confirm", "distribution.reconcile", "distribution"}
+	if len(spans) != len(want) {
+		t.Fatalf("recorded %d spans, want %d", len(spans), len(want))
+	}
+	root := spans[len(spans)-1]
+	for i, name := range want {
+		if spans[i].Name != name {
+			t.Fatalf("span %d is %s, want %s", i, spans[i].Name, name)
+		}
+		if i < len(want)-1 && (spans[i].Parent.SpanID() != root.SpanContext.SpanID() || spans[i].SpanContext.TraceID() != root.SpanContext.TraceID()) {
+			t.Fatalf("%s is not a step of the distribution", name)
+		}
+	}
+
+	hash := spanAttr(root, traceTxHash).AsString()
+	if len(hash) != 64 || spanAttr(spans[3], traceTxHash).AsString() != hash || spanAttr(spans[4], traceTxHash).AsString() != hash {
+		t.Fatalf("tx hash %q not on the broadcast and confirm spans", hash)
+	}
+	if spanAttr(root, traceAmount).AsInt64() != 70833 || spanAttr(root, traceCycle).AsInt64() != 2 || spanAttr(root, traceChainID).AsString() != "gxr-1" {
+		t.Fatalf("distribution attributes %v", root.Attributes)
+	}
+
+	// A failed broadcast ends the trace there, marked failed
+	exporter.Reset()
+	rd.distributionCount = 10
+	if err := rd.distributeHalvingRewards(context.Background()); err == nil {
+		t.Fatal("simulated broadcast failure not returned")
+	}
+	spans = exporter.GetSpans()
+	if len(spans) != 5 || spans[3].Name != "distribution.broadcast" || spans[3].Status.Code != codes.Error || spans[4].Status.Code != codes.Error {
+		t.Fatalf("failed distribution recorded %v", spans)
+	}
+}
+
+func TestRelayPacketTrace(t *testing.T) {
+	exporter := recordSpans(t)
+	r := NewIBCRelayer(&BotConfig{})
+	r.connectionHealth["channel-0"] = true
+	r.relayStep = func(context.Context, IBCPacket, string) error { return nil }
+
+	packet := &IBCPacket{ChannelID: "channel-0", Sequence: 7}
+	if err := r.relayPacket(context.Background(), packet, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 || spans[0].Name != "ibc.recv" || spans[1].Name != "ibc.ack" || spans[2].Name != "ibc.relay_packet" {
+		t.Fatalf("recorded %v", spans)
+	}
+	if spanAttr(spans[2], traceSequence).AsInt64() != 7 || spanAttr(spans[2], traceChannel).AsString() != "channel-0" {
+		t.Fatalf("packet attributes %v", spans[2].Attributes)
+	}
+}
+
+func TestTracingDisabled(t *testing.T) {
+	ctx := context.Background()
+	spanCtx, span := startSpan(ctx, "distribution")
+	if spanCtx != ctx || span.IsRecording() {
+		t.Fatal("span started with tracing disabled")
+	}
+
+	shutdown, err := SetupTracing(ctx, &BotConfig{})
+	if err != nil || botTracer.Load() != nil {
+		t.Fatalf("tracing set up without otel_endpoint: %v", err)
+	}
+	if err := shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateOtelEndpoint("localhost:4318"); err == nil {
+		t.Fatal("endpoint without scheme accepted")
+	}
+	if got := otlpTracesURL("http://collector:4318"); got != "http://collector:4318/v1/traces" {
+		t.Fatalf("traces URL = %s", got)
+	}
+}
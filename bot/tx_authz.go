@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// MsgExecTypeURL is the type URL of the authz message executing granted messages
+const MsgExecTypeURL = "/cosmos.authz.v1beta1.MsgExec"
+
+// TxAuthzConfig lets the bot sign with a hot key holding authz grants from
+// the operator instead of the operator key itself. The operator grants the
+// bot messages to Grantee and, with FeeGranter, pays its fees through a
+// feegrant allowance so the bot key needs no balance.
+type TxAuthzConfig struct {
+	// Account that granted the bot messages, normally the validator operator
+	// account. When set, bot messages are wrapped in MsgExec.
+	Granter string `yaml:"granter"`
+	// Account address of the bot key signing the transactions
+	Grantee string `yaml:"grantee"`
+	// Account paying the fees of bot transactions under a fee allowance
+	FeeGranter string `yaml:"fee_granter"`
+}
+
+// Enabled reports whether bot messages are executed through an authz grant
+func (c TxAuthzConfig) Enabled() bool {
+	return c.Granter != ""
+}
+
+// ValidateTxAuthz checks the authz settings. With a validator configured the
+// granter must be its operator account, the only signer of its messages.
+func ValidateTxAuthz(c TxAuthzConfig, validatorAddress string) error {
+	for name, address := range map[string]string{"granter": c.Granter, "grantee": c.Grantee, "fee_granter": c.FeeGranter} {
+		if address == "" {
+			continue
+		}
+		if _, _, err := bech32.DecodeAndConvert(address); err != nil {
+			return fmt.Errorf("tx_authz.%s: invalid address %q: %w", name, address, err)
+		}
+	}
+
+	if c.Granter == "" {
+		if c.Grantee != "" {
+			return fmt.Errorf("tx_authz.grantee requires tx_authz.granter")
+		}
+		return nil
+	}
+	if c.Grantee == "" {
+		return fmt.Errorf("tx_authz.granter requires tx_authz.grantee, the bot key's address")
+	}
+	if c.Grantee == c.Granter {
+		return fmt.Errorf("tx_authz.grantee must differ from the granter")
+	}
+
+	if validatorAddress != "" {
+		operator, err := validatorAccountAddress(validatorAddress)
+		if err != nil {
+			return err
+		}
+		if c.Granter != operator {
+			return fmt.Errorf("tx_authz.granter %s is not the operator account %s of validator_address", c.Granter, operator)
+		}
+	}
+	return nil
+}
+
+// Sender returns the account that signs bot transactions: the bot key with
+// authz, otherwise the operator account of validatorAddress
+func (c TxAuthzConfig) Sender(validatorAddress string) (string, error) {
+	if c.Enabled() {
+		return c.Grantee, nil
+	}
+	return validatorAccountAddress(validatorAddress)
+}
+
+// Wrap returns the messages to put in a bot transaction. msgs are proto JSON
+// messages with an "@type" field; with authz they are executed by a single
+// MsgExec of the grantee, otherwise they are returned as they are.
+func (c TxAuthzConfig) Wrap(msgs ...json.RawMessage) ([]json.RawMessage, error) {
+	if !c.Enabled() {
+		return msgs, nil
+	}
+
+	exec, err := json.Marshal(struct {
+		Type    string            `json:"@type"`
+		Grantee string            `json:"grantee"`
+		Msgs    []json.RawMessage `json:"msgs"`
+	}{MsgExecTypeURL, c.Grantee, msgs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap messages in MsgExec: %w", err)
+	}
+	return []json.RawMessage{exec}, nil
+}
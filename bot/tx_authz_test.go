@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+func TestValidateTxAuthz(t *testing.T) {
+	operatorBytes := make([]byte, 20)
+	botBytes := append(make([]byte, 19), 1)
+	valoper, _ := bech32.ConvertAndEncode("gxrvaloper", operatorBytes)
+	operator, _ := bech32.ConvertAndEncode("gxr", operatorBytes)
+	bot, _ := bech32.ConvertAndEncode("gxr", botBytes)
+
+	testCases := []struct {
+		name   string
+		config TxAuthzConfig
+		errMsg string
+	}{
+		{"disabled", TxAuthzConfig{}, ""},
+		{"fee grant only", TxAuthzConfig{FeeGranter: operator}, ""},
+		{"granted bot key", TxAuthzConfig{Granter: operator, Grantee: bot, FeeGranter: operator}, ""},
+		{"grantee without granter", TxAuthzConfig{Grantee: bot}, "requires tx_authz.granter"},
+		{"granter without grantee", TxAuthzConfig{Granter: operator}, "requires tx_authz.grantee"},
+		{"self grant", TxAuthzConfig{Granter: operator, Grantee: operator}, "must differ"},
+		{"granter is not the operator", TxAuthzConfig{Granter: bot, Grantee: operator}, "is not the operator account"},
+		{"invalid address", TxAuthzConfig{Granter: operator, Grantee: "gxr1notbech32"}, "tx_authz.grantee: invalid address"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTxAuthz(tc.config, valoper)
+			if tc.errMsg == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tc.errMsg)) {
+				t.Fatalf("error = %v, want %q", err, tc.errMsg)
+			}
+		})
+	}
+
+	// Heartbeats are looked up by the key that signs them
+	if sender, _ := (TxAuthzConfig{Granter: operator, Grantee: bot}).Sender(valoper); sender != bot {
+		t.Fatalf("sender with authz = %s", sender)
+	}
+	if sender, _ := (TxAuthzConfig{}).Sender(valoper); sender != operator {
+		t.Fatalf("sender without authz = %s", sender)
+	}
+}
+
+func TestTxAuthzWrapsMessagesInMsgExec(t *testing.T) {
+	declare := json.RawMessage(`{"@type":"/gxr.halving.v1beta1.MsgDeclareDowntime","validator_address":"gxrvaloper1ours"}`)
+
+	msgs, err := TxAuthzConfig{}.Wrap(declare)
+	if err != nil || len(msgs) != 1 || string(msgs[0]) != string(declare) {
+		t.Fatalf("messages without authz = %s, %v", msgs, err)
+	}
+
+	msgs, err = TxAuthzConfig{Granter: "gxr1operator", Grantee: "gxr1bot"}.Wrap(declare, declare)
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("messages with authz = %s, %v", msgs, err)
+	}
+	want := `{"@type":"/cosmos.authz.v1beta1.MsgExec","grantee":"gxr1bot","msgs":[` + string(declare) + `,` + string(declare) + `]}`
+	if string(msgs[0]) != want {
+		t.Fatalf("MsgExec = %s", msgs[0])
+	}
+}
+
+func TestBroadcastQueueSetsGrants(t *testing.T) {
+	node := &fakeBroadcaster{}
+	queue := NewBroadcastQueue(node, nil, "", time.Hour)
+	queue.authz = TxAuthzConfig{Granter: "gxr1operator", Grantee: "gxr1bot", FeeGranter: "gxr1operator"}
+
+	heartbeat := BotTx{Kind: TxKindHeartbeat, Validator: "gxrvaloper1ours", Memo: HeartbeatMemo("self", time.Now())}
+	if err := queue.Submit(context.Background(), heartbeat, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if sent := node.sent[0]; sent.Granter != "gxr1operator" || sent.Grantee != "gxr1bot" || sent.FeeGranter != "gxr1operator" {
+		t.Fatalf("broadcast %+v", sent)
+	}
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/std"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// DefaultTxGasLimit is the gas limit of bot transactions, enough for a
+	// halving message executed through MsgExec
+	DefaultTxGasLimit = 200000
+	// DefaultTxFee is the fee of a bot transaction in ugen
+	DefaultTxFee = 5000
+
+	// Type URLs of the chain messages bot transactions carry
+	MsgRegisterBotHeartbeatTypeURL = "/gxr.halving.v1beta1.MsgRegisterBotHeartbeat"
+	MsgSetBotMetadataTypeURL       = "/gxr.halving.v1beta1.MsgSetBotMetadata"
+	MsgTimeoutTypeURL              = "/ibc.core.channel.v1.MsgTimeout"
+
+	// botKeyName is the name of the signing key in the in-memory keyring
+	botKeyName = "gxrbot"
+	// botHDPath derives the signing key like the chain's CLI, coin type 118
+	botHDPath = "m/44'/118'/0'/0/0"
+	// codeWrongSequence is the SDK's ErrWrongSequence code
+	codeWrongSequence = 32
+)
+
+// ErrUnsupportedTx is returned for bot transactions the chain has no message
+// for. They fail on every attempt, so they are not retried.
+var ErrUnsupportedTx = errors.New("no chain message for this bot transaction")
+
+// ChainBroadcaster signs bot transactions with the key of validator_mnemonic,
+// the operator key or with tx_authz the bot key, and broadcasts them to the
+// node of the client context with BroadcastTxSync. The messages are encoded
+// here, the bot does not link the chain's modules.
+type ChainBroadcaster struct {
+	clientCtx client.Context
+	keyring   keyring.Keyring
+	sender    sdk.AccAddress
+	signer    string // sender, bech32 encoded
+	gasLimit  uint64
+	fee       int64
+
+	// mu serializes broadcasts: every transaction takes the next sequence,
+	// which the node only reports once the previous one is in a block
+	mu       sync.Mutex
+	sequence uint64
+}
+
+// newClientContext returns the client context of the chain node, querying
+// over chain_grpc and broadcasting over chain_rpc. Accounts and keys are the
+// only interfaces it decodes.
+func newClientContext(config *BotConfig) (client.Context, error) {
+	registry := codectypes.NewInterfaceRegistry()
+	std.RegisterInterfaces(registry)
+	authtypes.RegisterInterfaces(registry)
+	clientCtx := client.Context{}.
+		WithChainID(config.ChainID).
+		WithCodec(codec.NewProtoCodec(registry)).
+		WithInterfaceRegistry(registry).
+		WithAccountRetriever(authtypes.AccountRetriever{}).
+		WithBroadcastMode(flags.BroadcastSync)
+
+	if config.ChainRPC != "" {
+		node, err := client.NewClientFromNode(config.ChainRPC)
+		if err != nil {
+			return clientCtx, fmt.Errorf("invalid chain_rpc %q: %w", config.ChainRPC, err)
+		}
+		clientCtx = clientCtx.WithClient(node).WithNodeURI(config.ChainRPC)
+	}
+	if config.ChainGRPC != "" {
+		conn, err := grpc.NewClient(config.ChainGRPC, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return clientCtx, fmt.Errorf("invalid chain_grpc %q: %w", config.ChainGRPC, err)
+		}
+		clientCtx = clientCtx.WithGRPCClient(conn)
+	}
+	return clientCtx, nil
+}
+
+// NewChainBroadcaster imports validator_mnemonic into an in-memory keyring
+// and checks that it is the key of the account signing bot transactions
+func NewChainBroadcaster(clientCtx client.Context, config *BotConfig) (*ChainBroadcaster, error) {
+	if config.ValidatorMnemonic == "" {
+		return nil, fmt.Errorf("validator_mnemonic is required to sign bot transactions")
+	}
+	expected, err := config.TxAuthz.Sender(config.ValidatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	kr := keyring.NewInMemory(clientCtx.Codec)
+	record, err := kr.NewAccount(botKeyName, config.ValidatorMnemonic, "", botHDPath, hd.Secp256k1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validator_mnemonic: %w", err)
+	}
+	sender, err := record.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+	if _, data, err := bech32.DecodeAndConvert(expected); err != nil || !bytes.Equal(sender, data) {
+		return nil, fmt.Errorf("validator_mnemonic is not the key of %s, the account signing bot transactions", expected)
+	}
+
+	gasLimit, fee := config.TxGasLimit, config.TxFee
+	if gasLimit == 0 {
+		gasLimit = DefaultTxGasLimit
+	}
+	if fee == 0 {
+		fee = DefaultTxFee
+	}
+	return &ChainBroadcaster{
+		clientCtx: clientCtx,
+		keyring:   kr,
+		sender:    sender,
+		signer:    expected,
+		gasLimit:  gasLimit,
+		fee:       fee,
+	}, nil
+}
+
+// Broadcast implements TxBroadcaster
+func (b *ChainBroadcaster) Broadcast(ctx context.Context, tx BotTx) error {
+	_, err := b.BroadcastWithResult(ctx, tx)
+	return err
+}
+
+// BroadcastWithResult signs tx and broadcasts it, failing when the node
+// rejects it in CheckTx
+func (b *ChainBroadcaster) BroadcastWithResult(ctx context.Context, tx BotTx) (TxResult, error) {
+	msgs, err := botTxMessages(tx, b.signer)
+	if err != nil {
+		return TxResult{}, err
+	}
+	if tx.Granter != "" {
+		msgs = []*codectypes.Any{execMessage(tx.Grantee, msgs)}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	accountNumber, sequence, err := b.clientCtx.AccountRetriever.GetAccountNumberSequence(b.clientCtx, b.sender)
+	if err != nil {
+		return TxResult{}, fmt.Errorf("failed to query account %s: %w", b.sender, err)
+	}
+	if sequence < b.sequence {
+		sequence = b.sequence
+	}
+
+	txBytes, err := b.sign(tx, msgs, accountNumber, sequence)
+	if err != nil {
+		return TxResult{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return TxResult{}, err
+	}
+	res, err := b.clientCtx.BroadcastTxSync(txBytes)
+	if err != nil {
+		return TxResult{}, fmt.Errorf("failed to broadcast %s: %w", tx.Kind, err)
+	}
+	if res.Code != 0 {
+		if res.Code == codeWrongSequence {
+			b.sequence = 0
+		}
+		return TxResult{}, fmt.Errorf("%s transaction %s rejected with code %d: %s", tx.Kind, res.TxHash, res.Code, res.RawLog)
+	}
+
+	b.sequence = sequence + 1
+	return TxResult{TxHash: res.TxHash, GasWanted: int64(b.gasLimit), GasUsed: res.GasUsed, Fee: b.fee}, nil
+}
+
+// sign builds the transaction of msgs and signs it in SIGN_MODE_DIRECT
+func (b *ChainBroadcaster) sign(tx BotTx, msgs []*codectypes.Any, accountNumber, sequence uint64) ([]byte, error) {
+	record, err := b.keyring.Key(botKeyName)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := record.GetPubKey()
+	if err != nil {
+		return nil, err
+	}
+	pubKeyAny, err := codectypes.NewAnyWithValue(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &txtypes.TxBody{Messages: msgs, Memo: tx.Memo}
+	authInfo := &txtypes.AuthInfo{
+		SignerInfos: []*txtypes.SignerInfo{{
+			PublicKey: pubKeyAny,
+			ModeInfo: &txtypes.ModeInfo{Sum: &txtypes.ModeInfo_Single_{
+				Single: &txtypes.ModeInfo_Single{Mode: signingtypes.SignMode_SIGN_MODE_DIRECT},
+			}},
+			Sequence: sequence,
+		}},
+		Fee: &txtypes.Fee{Amount: sdk.NewCoins(sdk.NewInt64Coin("ugen", b.fee)), GasLimit: b.gasLimit, Granter: tx.FeeGranter},
+	}
+	bodyBytes, err := body.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	authInfoBytes, err := authInfo.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	signDoc := &txtypes.SignDoc{
+		BodyBytes:     bodyBytes,
+		AuthInfoBytes: authInfoBytes,
+		ChainId:       b.clientCtx.ChainID,
+		AccountNumber: accountNumber,
+	}
+	signBytes, err := signDoc.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	signature, _, err := b.keyring.Sign(botKeyName, signBytes, signingtypes.SignMode_SIGN_MODE_DIRECT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %s: %w", tx.Kind, err)
+	}
+
+	raw := &txtypes.TxRaw{BodyBytes: bodyBytes, AuthInfoBytes: authInfoBytes, Signatures: [][]byte{signature}}
+	return raw.Marshal()
+}
+
+// botTxMessages encodes the chain messages of a bot transaction signed by
+// signer. Slashing reports have no message, the chain judges bot compliance
+// from heartbeats itself.
+func botTxMessages(tx BotTx, signer string) ([]*codectypes.Any, error) {
+	switch tx.Kind {
+	case TxKindHeartbeat:
+		msg := protowire.AppendTag(nil, 1, protowire.BytesType)
+		msg = protowire.AppendString(msg, tx.Validator)
+		return []*codectypes.Any{{TypeUrl: MsgRegisterBotHeartbeatTypeURL, Value: msg}}, nil
+	case TxKindBotMetadata:
+		var msg []byte
+		for i, field := range []string{tx.Validator, tx.Version, tx.Contact, tx.EndpointURL} {
+			if field == "" {
+				continue
+			}
+			msg = protowire.AppendTag(msg, protowire.Number(i+1), protowire.BytesType)
+			msg = protowire.AppendString(msg, field)
+		}
+		return []*codectypes.Any{{TypeUrl: MsgSetBotMetadataTypeURL, Value: msg}}, nil
+	case TxKindIBCTimeout:
+		if len(tx.ProofUnreceived) == 0 {
+			return nil, fmt.Errorf("ibc_timeout of %s/%d has no proof of non-receipt", tx.ChannelID, tx.Sequence)
+		}
+		return []*codectypes.Any{{TypeUrl: MsgTimeoutTypeURL, Value: timeoutMessage(tx, signer)}}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTx, tx.Kind)
+	}
+}
+
+// timeoutMessage encodes the MsgTimeout of the packet of an ibc_timeout
+// transaction. The packet must be the one sent on chain, whose commitment
+// the chain checks; a zero timeout height or timestamp is left out, as
+// ibc-go does for a packet without that timeout.
+func timeoutMessage(tx BotTx, signer string) []byte {
+	sourcePort := tx.SourcePort
+	if sourcePort == "" {
+		sourcePort = IBCTransferPort
+	}
+
+	packet := protowire.AppendTag(nil, 1, protowire.VarintType)
+	packet = protowire.AppendVarint(packet, tx.Sequence)
+	for i, field := range []string{sourcePort, tx.ChannelID, tx.CounterpartyPort, tx.CounterpartyChannel} {
+		packet = protowire.AppendTag(packet, protowire.Number(i+2), protowire.BytesType)
+		packet = protowire.AppendString(packet, field)
+	}
+	packet = protowire.AppendTag(packet, 6, protowire.BytesType)
+	packet = protowire.AppendBytes(packet, tx.PacketData)
+	if tx.TimeoutHeight != (IBCHeight{}) {
+		packet = protowire.AppendTag(packet, 7, protowire.BytesType)
+		packet = protowire.AppendBytes(packet, heightMessage(tx.TimeoutHeight))
+	}
+	if !tx.TimeoutTimestamp.IsZero() {
+		packet = protowire.AppendTag(packet, 8, protowire.VarintType)
+		packet = protowire.AppendVarint(packet, uint64(tx.TimeoutTimestamp.UnixNano()))
+	}
+
+	msg := protowire.AppendTag(nil, 1, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, packet)
+	msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, tx.ProofUnreceived)
+	msg = protowire.AppendTag(msg, 3, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, heightMessage(tx.ProofHeight))
+	// Only checked on ordered channels, ICS-20 channels are unordered
+	msg = protowire.AppendTag(msg, 4, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, tx.Sequence)
+	msg = protowire.AppendTag(msg, 5, protowire.BytesType)
+	return protowire.AppendString(msg, signer)
+}
+
+// heightMessage encodes an IBC client height
+func heightMessage(height IBCHeight) []byte {
+	msg := protowire.AppendTag(nil, 1, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, height.RevisionNumber)
+	msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+	return protowire.AppendVarint(msg, height.RevisionHeight)
+}
+
+// execMessage wraps msgs in the MsgExec of grantee, the binary counterpart
+// of TxAuthzConfig.Wrap
+func execMessage(grantee string, msgs []*codectypes.Any) *codectypes.Any {
+	exec := protowire.AppendTag(nil, 1, protowire.BytesType)
+	exec = protowire.AppendString(exec, grantee)
+	for _, msg := range msgs {
+		packed := protowire.AppendTag(nil, 1, protowire.BytesType)
+		packed = protowire.AppendString(packed, msg.TypeUrl)
+		packed = protowire.AppendTag(packed, 2, protowire.BytesType)
+		packed = protowire.AppendBytes(packed, msg.Value)
+
+		exec = protowire.AppendTag(exec, 2, protowire.BytesType)
+		exec = protowire.AppendBytes(exec, packed)
+	}
+	return &codectypes.Any{TypeUrl: MsgExecTypeURL, Value: exec}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	coretypes "github.com/cometbft/cometbft/v2/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/v2/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const signingMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// fakeNode answers BroadcastTxSync with the queued codes and records the txs
+type fakeNode struct {
+	client.CometRPC
+	codes []uint32
+	txs   [][]byte
+}
+
+func (n *fakeNode) BroadcastTxSync(_ context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTx, error) {
+	n.txs = append(n.txs, tx)
+	var code uint32
+	if len(n.codes) > 0 {
+		code, n.codes = n.codes[0], n.codes[1:]
+	}
+	return &coretypes.ResultBroadcastTx{Code: code, Log: "account sequence mismatch", Hash: []byte{0xab, 0xcd}}, nil
+}
+
+// fakeAccounts reports the same account sequence until it is changed
+type fakeAccounts struct {
+	client.AccountRetriever
+	sequence uint64
+}
+
+func (a *fakeAccounts) GetAccountNumberSequence(client.Context, sdk.AccAddress) (uint64, uint64, error) {
+	return 12, a.sequence, nil
+}
+
+// testValidator returns the validator whose operator account has the key of signingMnemonic
+func testValidator(t *testing.T, clientCtx client.Context) string {
+	t.Helper()
+	record, err := keyring.NewInMemory(clientCtx.Codec).NewAccount("test", signingMnemonic, "", botHDPath, hd.Secp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := record.GetAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	valoper, err := bech32.ConvertAndEncode("gxrvaloper", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return valoper
+}
+
+// protoFields decodes the fields of a proto message, the length-delimited
+// ones to their content and varints to their encoding
+func protoFields(t *testing.T, b []byte) map[protowire.Number][][]byte {
+	t.Helper()
+	fields := make(map[protowire.Number][][]byte)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 || (typ != protowire.BytesType && typ != protowire.VarintType) {
+			t.Fatalf("unexpected field %d of type %d", num, typ)
+		}
+		b = b[n:]
+		var value []byte
+		if typ == protowire.BytesType {
+			value, n = protowire.ConsumeBytes(b)
+		} else {
+			_, n = protowire.ConsumeVarint(b)
+			value = b[:max(n, 0)]
+		}
+		if n < 0 {
+			t.Fatalf("truncated field %d", num)
+		}
+		fields[num] = append(fields[num], value)
+		b = b[n:]
+	}
+	return fields
+}
+
+func TestBotTxMessages(t *testing.T) {
+	msgs, err := botTxMessages(BotTx{Kind: TxKindHeartbeat, Validator: "gxrvaloper1ours", Memo: "gxrbot-heartbeat"}, "gxr1ours")
+	if err != nil || len(msgs) != 1 || msgs[0].TypeUrl != MsgRegisterBotHeartbeatTypeURL {
+		t.Fatalf("heartbeat messages = %+v: %v", msgs, err)
+	}
+	if fields := protoFields(t, msgs[0].Value); string(fields[1][0]) != "gxrvaloper1ours" || len(fields) != 1 {
+		t.Fatalf("heartbeat fields = %q", fields)
+	}
+
+	msgs, err = botTxMessages(BotTx{Kind: TxKindBotMetadata, Validator: "gxrvaloper1ours", Version: "2.0.0", Contact: "@gxr_ops"}, "gxr1ours")
+	if err != nil || len(msgs) != 1 || msgs[0].TypeUrl != MsgSetBotMetadataTypeURL {
+		t.Fatalf("metadata messages = %+v: %v", msgs, err)
+	}
+	fields := protoFields(t, msgs[0].Value)
+	if string(fields[1][0]) != "gxrvaloper1ours" || string(fields[2][0]) != "2.0.0" || string(fields[3][0]) != "@gxr_ops" || fields[4] != nil {
+		t.Fatalf("metadata fields = %q", fields)
+	}
+
+	// Executed by the bot key under the operator's grant
+	exec := execMessage("gxr1bot", msgs)
+	fields = protoFields(t, exec.Value)
+	if exec.TypeUrl != MsgExecTypeURL || string(fields[1][0]) != "gxr1bot" || len(fields[2]) != 1 {
+		t.Fatalf("MsgExec fields = %q", fields)
+	}
+	inner := protoFields(t, fields[2][0])
+	if string(inner[1][0]) != MsgSetBotMetadataTypeURL || string(inner[2][0]) != string(msgs[0].Value) {
+		t.Fatalf("executed message = %q", inner)
+	}
+
+	if _, err := botTxMessages(BotTx{Kind: TxKindSlashingReport}, "gxr1ours"); !errors.Is(err, ErrUnsupportedTx) {
+		t.Fatalf("slashing report err = %v, want ErrUnsupportedTx", err)
+	}
+}
+
+func TestTimeoutMessage(t *testing.T) {
+	timeout := BotTx{
+		Kind: TxKindIBCTimeout, ChannelID: "channel-0", Sequence: 9, PacketData: []byte(`{"amount":"5"}`),
+		TimeoutHeight: IBCHeight{RevisionNumber: 1, RevisionHeight: 4100}, TimeoutTimestamp: time.Unix(1700000000, 0),
+		CounterpartyPort: "transfer", CounterpartyChannel: "channel-7",
+		ProofUnreceived: []byte("proof"), ProofHeight: IBCHeight{RevisionNumber: 1, RevisionHeight: 4200},
+	}
+	msgs, err := botTxMessages(timeout, "gxr1bot")
+	if err != nil || len(msgs) != 1 || msgs[0].TypeUrl != MsgTimeoutTypeURL {
+		t.Fatalf("timeout messages = %+v: %v", msgs, err)
+	}
+
+	msg := protoFields(t, msgs[0].Value)
+	if string(msg[2][0]) != "proof" || string(msg[4][0]) != string(protowire.AppendVarint(nil, 9)) || string(msg[5][0]) != "gxr1bot" {
+		t.Fatalf("MsgTimeout fields = %q", msg)
+	}
+	height := protoFields(t, msg[3][0])
+	if string(height[1][0]) != string(protowire.AppendVarint(nil, 1)) || string(height[2][0]) != string(protowire.AppendVarint(nil, 4200)) {
+		t.Fatalf("proof height = %q", height)
+	}
+	packet := protoFields(t, msg[1][0])
+	if string(packet[2][0]) != "transfer" || string(packet[3][0]) != "channel-0" || string(packet[4][0]) != "transfer" ||
+		string(packet[5][0]) != "channel-7" || string(packet[6][0]) != `{"amount":"5"}` ||
+		string(packet[8][0]) != string(protowire.AppendVarint(nil, 1700000000000000000)) {
+		t.Fatalf("packet = %q", packet)
+	}
+	if timeoutHeight := protoFields(t, packet[7][0]); string(timeoutHeight[2][0]) != string(protowire.AppendVarint(nil, 4100)) {
+		t.Fatalf("timeout height = %q", timeoutHeight)
+	}
+
+	// A packet without a timeout timestamp is committed without one
+	noTimestamp := timeout
+	noTimestamp.TimeoutTimestamp = time.Time{}
+	msgs, _ = botTxMessages(noTimestamp, "gxr1bot")
+	if packet := protoFields(t, protoFields(t, msgs[0].Value)[1][0]); packet[8] != nil {
+		t.Fatalf("packet without timeout timestamp = %q", packet)
+	}
+
+	// Without a proof the chain would reject the timeout
+	timeout.ProofUnreceived = nil
+	if _, err := botTxMessages(timeout, "gxr1bot"); err == nil {
+		t.Fatal("timeout without a proof encoded")
+	}
+}
+
+func TestChainBroadcasterSignsAndBroadcasts(t *testing.T) {
+	clientCtx, err := newClientContext(&BotConfig{ChainID: "gxr-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, accounts := &fakeNode{}, &fakeAccounts{sequence: 7}
+	clientCtx = clientCtx.WithClient(node).WithAccountRetriever(accounts)
+	config := &BotConfig{ValidatorAddress: testValidator(t, clientCtx), ValidatorMnemonic: signingMnemonic}
+
+	broadcaster, err := NewChainBroadcaster(clientCtx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	heartbeat := BotTx{Kind: TxKindHeartbeat, Validator: config.ValidatorAddress, Memo: "gxrbot-heartbeat", FeeGranter: "gxr1payer"}
+
+	result, err := broadcaster.BroadcastWithResult(ctx, heartbeat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TxHash != "ABCD" || result.Fee != DefaultTxFee || result.GasWanted != DefaultTxGasLimit {
+		t.Fatalf("result = %+v", result)
+	}
+	body, authInfo := decodeTestTx(t, node.txs[0])
+	if body.Memo != "gxrbot-heartbeat" || len(body.Messages) != 1 || body.Messages[0].TypeUrl != MsgRegisterBotHeartbeatTypeURL {
+		t.Fatalf("body = %+v", body)
+	}
+	if authInfo.SignerInfos[0].Sequence != 7 || authInfo.Fee.Granter != "gxr1payer" || authInfo.Fee.GasLimit != DefaultTxGasLimit {
+		t.Fatalf("auth info = %+v", authInfo)
+	}
+
+	// The next transaction takes the next sequence before the node reports it
+	if err := broadcaster.Broadcast(ctx, heartbeat); err != nil {
+		t.Fatal(err)
+	}
+	if _, authInfo := decodeTestTx(t, node.txs[1]); authInfo.SignerInfos[0].Sequence != 8 {
+		t.Fatalf("second sequence = %d, want 8", authInfo.SignerInfos[0].Sequence)
+	}
+
+	// A sequence mismatch falls back to the node's sequence
+	node.codes = []uint32{codeWrongSequence}
+	if err := broadcaster.Broadcast(ctx, heartbeat); err == nil || !strings.Contains(err.Error(), "code 32") {
+		t.Fatalf("rejected broadcast err = %v", err)
+	}
+	accounts.sequence = 8
+	if err := broadcaster.Broadcast(ctx, heartbeat); err != nil {
+		t.Fatal(err)
+	}
+	if _, authInfo := decodeTestTx(t, node.txs[3]); authInfo.SignerInfos[0].Sequence != 8 {
+		t.Fatalf("sequence after mismatch = %d, want 8", authInfo.SignerInfos[0].Sequence)
+	}
+
+	// With authz the message is executed by the grantee
+	granted := heartbeat
+	granted.Granter, granted.Grantee = "gxr1operator", "gxr1bot"
+	if err := broadcaster.Broadcast(ctx, granted); err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := decodeTestTx(t, node.txs[4]); body.Messages[0].TypeUrl != MsgExecTypeURL {
+		t.Fatalf("authz body = %+v", body)
+	}
+
+	// Nothing reaches the node for transactions without a chain message
+	if err := broadcaster.Broadcast(ctx, BotTx{Kind: TxKindSlashingReport}); !errors.Is(err, ErrUnsupportedTx) || len(node.txs) != 5 {
+		t.Fatalf("slashing report err = %v, %d txs", err, len(node.txs))
+	}
+}
+
+func decodeTestTx(t *testing.T, txBytes []byte) (*txtypes.TxBody, *txtypes.AuthInfo) {
+	t.Helper()
+	var raw txtypes.TxRaw
+	var body txtypes.TxBody
+	var authInfo txtypes.AuthInfo
+	if err := raw.Unmarshal(txBytes); err != nil {
+		t.Fatal(err)
+	}
+	if err := body.Unmarshal(raw.BodyBytes); err != nil {
+		t.Fatal(err)
+	}
+	if err := authInfo.Unmarshal(raw.AuthInfoBytes); err != nil {
+		t.Fatal(err)
+	}
+	if len(raw.Signatures) != 1 || len(authInfo.SignerInfos) != 1 {
+		t.Fatalf("signatures = %d, signer infos = %d", len(raw.Signatures), len(authInfo.SignerInfos))
+	}
+	return &body, &authInfo
+}
+
+func TestInstallBroadcaster(t *testing.T) {
+	clientCtx, err := newClientContext(&BotConfig{ChainID: "gxr-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := testValidator(t, clientCtx)
+	newService := func(mnemonic string) *BotService {
+		return &BotService{
+			config:           &BotConfig{ValidatorAddress: validator, ValidatorMnemonic: mnemonic},
+			clientCtx:        clientCtx,
+			validatorMonitor: &ValidatorMonitor{},
+		}
+	}
+
+	// Without a key nothing is broadcast
+	bs := newService("")
+	bs.installBroadcaster()
+	if bs.broadcastQueue != nil {
+		t.Fatal("broadcaster installed without validator_mnemonic")
+	}
+
+	// The key of another account is refused
+	bs = newService("zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong")
+	bs.installBroadcaster()
+	if bs.broadcastQueue != nil || len(bs.lastErrors) != 1 || bs.lastErrors[0].Component != "broadcast" {
+		t.Fatalf("broadcaster installed with a foreign key, errors %+v", bs.lastErrors)
+	}
+
+	bs = newService(signingMnemonic)
+	bs.installBroadcaster()
+	if bs.broadcastQueue == nil || bs.validatorMonitor.broadcastQueue != bs.broadcastQueue {
+		t.Fatal("broadcaster not installed")
+	}
+	if _, ok := bs.broadcastQueue.broadcaster.(*ChainBroadcaster); !ok {
+		t.Fatalf("broadcaster = %T, want *ChainBroadcaster", bs.broadcastQueue.broadcaster)
+	}
+
+	// A broadcaster set before Start is kept
+	queue := bs.broadcastQueue
+	bs.installBroadcaster()
+	if bs.broadcastQueue != queue {
+		t.Fatal("broadcaster replaced")
+	}
+}
+
+func TestUnsupportedTxNotQueued(t *testing.T) {
+	queue := NewBroadcastQueue(broadcasterFunc(func(context.Context, BotTx) error {
+		return ErrUnsupportedTx
+	}), nil, "", time.Hour)
+
+	err := queue.Submit(context.Background(), BotTx{Kind: TxKindSlashingReport, Validator: "gxrvaloper1other"}, time.Now())
+	if !errors.Is(err, ErrUnsupportedTx) || len(queue.Pending()) != 0 {
+		t.Fatalf("err = %v, pending %+v", err, queue.Pending())
+	}
+}
@@ -0,0 +1,599 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultGitHubAPI is the GitHub REST API queried for releases
+	DefaultGitHubAPI = "https://api.github.com"
+	// DefaultUpdateRepo is the repository whose releases are checked
+	DefaultUpdateRepo = "Crocodile-ark/Genpro"
+	// UpdateCheckInterval is how often the releases are checked
+	UpdateCheckInterval = 24 * time.Hour
+	// UpdateCheckTimeout bounds each request of an update check
+	UpdateCheckTimeout = 30 * time.Second
+	// ChecksumsAsset lists the SHA-256 of every release artifact
+	ChecksumsAsset = "checksums.txt"
+	// ChecksumsSignatureAsset is the base64 ed25519 signature of ChecksumsAsset
+	ChecksumsSignatureAsset = "checksums.txt.sig"
+
+	// MaxChangelogLines and MaxChangelogChars bound the changelog excerpt in the alert
+	MaxChangelogLines = 12
+	MaxChangelogChars = 800
+	// maxReleaseMetadataSize bounds the downloaded checksums and signature
+	maxReleaseMetadataSize = 1 << 20
+)
+
+// Signature states of a release's checksums
+const (
+	SignatureVerified   = "verified"
+	SignatureNotChecked = "not checked" // no update_check_public_key configured
+	SignatureMissing    = "missing"
+	SignatureInvalid    = "invalid"
+)
+
+// SemVer is a parsed semantic version
+type SemVer struct {
+	Major, Minor, Patch uint64
+	Pre                 string // pre-release, e.g. "rc.1"
+}
+
+// ParseSemVer parses "v1.2.3", "1.2.3-rc.1" or "1.2.3+build"
+func ParseSemVer(s string) (SemVer, error) {
+	version := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i]
+	}
+
+	var v SemVer
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		v.Pre = version[i+1:]
+		version = version[:i]
+		if v.Pre == "" {
+			return SemVer{}, fmt.Errorf("invalid version %q: empty pre-release", s)
+		}
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return SemVer{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+	numbers := make([]uint64, 3)
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return SemVer{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		numbers[i] = n
+	}
+	v.Major, v.Minor, v.Patch = numbers[0], numbers[1], numbers[2]
+	return v, nil
+}
+
+// String formats the version without a "v" prefix
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than o.
+// Pre-releases are older than the release they lead up to.
+func (v SemVer) Compare(o SemVer) int {
+	for _, pair := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	}
+	return comparePreRelease(v.Pre, o.Pre)
+}
+
+// comparePreRelease compares dot-separated identifiers, numeric ones numerically
+func comparePreRelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// githubRelease is the part of a GitHub release the checker reads
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// assetURL returns the download URL of the named asset, or ""
+func (r githubRelease) assetURL(name string) string {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset.BrowserDownloadURL
+		}
+	}
+	return ""
+}
+
+// ReleaseVerification is the result of checking a release's checksum metadata
+type ReleaseVerification struct {
+	Artifact  string // the artifact for this platform
+	SHA256    string
+	Signature string // one of the Signature* states
+	Error     string // why the metadata could not be verified, empty if it was
+}
+
+// Verified reports whether the checksums are usable and not known to be forged
+func (v ReleaseVerification) Verified() bool {
+	return v.Error == "" && v.Signature != SignatureInvalid && v.Signature != SignatureMissing
+}
+
+// UpdateInfo describes a newer release
+type UpdateInfo struct {
+	Current      string
+	Latest       string
+	URL          string
+	PublishedAt  time.Time
+	Changelog    string
+	Verification ReleaseVerification
+}
+
+// UpdateChecker polls GitHub releases for newer bot versions. It only
+// alerts, installing is left to the operator.
+type UpdateChecker struct {
+	apiURL    string
+	repo      string
+	current   SemVer
+	publicKey ed25519.PublicKey
+	client    *http.Client
+	alert     *TelegramAlert
+
+	mu           sync.RWMutex
+	lastCheck    time.Time
+	lastError    string
+	latest       *UpdateInfo
+	lastAlerted  string
+	checksFailed int64
+}
+
+// NewUpdateChecker creates an update checker from the bot configuration.
+// The configuration is validated by ValidateUpdateCheck.
+func NewUpdateChecker(config *BotConfig, alert *TelegramAlert) (*UpdateChecker, error) {
+	current, err := ParseSemVer(Version)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := parseUpdatePublicKey(config.UpdateCheckPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if config.UpdateCheckProxy != "" {
+		proxyURL, err := url.Parse(config.UpdateCheckProxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid update_check_proxy: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	repo := config.UpdateCheckRepo
+	if repo == "" {
+		repo = DefaultUpdateRepo
+	}
+
+	return &UpdateChecker{
+		apiURL:    DefaultGitHubAPI,
+		repo:      repo,
+		current:   current,
+		publicKey: publicKey,
+		client: &http.Client{
+			Timeout:   UpdateCheckTimeout,
+			Transport: &http.Transport{Proxy: proxy},
+		},
+		alert: alert,
+	}, nil
+}
+
+// ValidateUpdateCheck checks the update_check settings
+func ValidateUpdateCheck(config *BotConfig) error {
+	if !config.UpdateCheck {
+		return nil
+	}
+	if config.UpdateCheckRepo != "" && strings.Count(config.UpdateCheckRepo, "/") != 1 {
+		return fmt.Errorf("update_check_repo must be owner/name, got %q", config.UpdateCheckRepo)
+	}
+	if config.UpdateCheckProxy != "" {
+		proxyURL, err := url.Parse(config.UpdateCheckProxy)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return fmt.Errorf("update_check_proxy must be a URL like http://proxy:3128, got %q", config.UpdateCheckProxy)
+		}
+	}
+	if _, err := parseUpdatePublicKey(config.UpdateCheckPublicKey); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseUpdatePublicKey decodes the base64 ed25519 release signing key
+func parseUpdatePublicKey(encoded string) (ed25519.PublicKey, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("update_check_public_key must be a base64 ed25519 public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Run checks for updates now and then every UpdateCheckInterval until ctx is
+// done. Failures are logged and retried on the next interval, so an offline
+// bot starts and runs normally.
+func (uc *UpdateChecker) Run(ctx context.Context) {
+	ticker := NewJitteredTicker(UpdateCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		uc.checkAndAlert(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAndAlert runs one check and alerts once per newer version
+func (uc *UpdateChecker) checkAndAlert(ctx context.Context) {
+	info, err := uc.Check(ctx)
+	if err != nil {
+		log.Printf("Update check failed: %v", err)
+		return
+	}
+	if info == nil {
+		return
+	}
+
+	uc.mu.Lock()
+	alerted := uc.lastAlerted == info.Latest
+	uc.lastAlerted = info.Latest
+	uc.mu.Unlock()
+	if alerted {
+		return
+	}
+
+	log.Printf("Bot update available: v%s -> v%s (checksums %s, signature %s)",
+		info.Current, info.Latest, verificationSummary(info.Verification), info.Verification.Signature)
+
+	if uc.alert == nil {
+		return
+	}
+	alertType, title := AlertTypeInfo, "Bot Update Available"
+	if !info.Verification.Verified() {
+		alertType, title = AlertTypeWarning, "Bot Update Failed Verification"
+	}
+	if err := uc.alert.SendAlertWithType(alertType, title, formatUpdateMessage(info)); err != nil {
+		log.Printf("Failed to send update alert: %v", err)
+	}
+}
+
+// Check queries the latest release. It returns nil without an error when the
+// running version is up to date.
+func (uc *UpdateChecker) Check(ctx context.Context) (*UpdateInfo, error) {
+	info, err := uc.check(ctx)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.lastCheck = time.Now()
+	if err != nil {
+		uc.lastError = err.Error()
+		uc.checksFailed++
+		return nil, err
+	}
+	uc.lastError = ""
+	uc.latest = info
+	return info, nil
+}
+
+func (uc *UpdateChecker) check(ctx context.Context) (*UpdateInfo, error) {
+	var release githubRelease
+	if err := uc.getJSON(ctx, fmt.Sprintf("%s/repos/%s/releases/latest", uc.apiURL, uc.repo), &release); err != nil {
+		return nil, err
+	}
+	if release.Draft || release.Prerelease {
+		return nil, nil
+	}
+
+	latest, err := ParseSemVer(release.TagName)
+	if err != nil {
+		return nil, fmt.Errorf("latest release tag: %w", err)
+	}
+	if latest.Compare(uc.current) <= 0 {
+		return nil, nil
+	}
+
+	return &UpdateInfo{
+		Current:      uc.current.String(),
+		Latest:       latest.String(),
+		URL:          release.HTMLURL,
+		PublishedAt:  release.PublishedAt,
+		Changelog:    changelogExcerpt(release.Body),
+		Verification: uc.verifyRelease(ctx, release, latest),
+	}, nil
+}
+
+// verifyRelease checks that the release publishes a well-formed checksum for
+// this platform's artifact and, with a public key configured, that the
+// checksums are signed by it
+func (uc *UpdateChecker) verifyRelease(ctx context.Context, release githubRelease, version SemVer) ReleaseVerification {
+	result := ReleaseVerification{Signature: SignatureNotChecked}
+
+	checksumsURL := release.assetURL(ChecksumsAsset)
+	if checksumsURL == "" {
+		result.Error = "release has no " + ChecksumsAsset
+		return result
+	}
+	checksums, err := uc.download(ctx, checksumsURL)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if uc.publicKey != nil {
+		result.Signature = uc.verifySignature(ctx, release, checksums)
+	}
+
+	artifact, sum, err := platformChecksum(checksums, version)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if release.assetURL(artifact) == "" {
+		result.Error = fmt.Sprintf("%s lists %s but the release has no such asset", ChecksumsAsset, artifact)
+		return result
+	}
+	result.Artifact, result.SHA256 = artifact, sum
+	return result
+}
+
+// verifySignature checks the ed25519 signature of the checksums file
+func (uc *UpdateChecker) verifySignature(ctx context.Context, release githubRelease, checksums []byte) string {
+	signatureURL := release.assetURL(ChecksumsSignatureAsset)
+	if signatureURL == "" {
+		return SignatureMissing
+	}
+	encoded, err := uc.download(ctx, signatureURL)
+	if err != nil {
+		return SignatureMissing
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil || !ed25519.Verify(uc.publicKey, checksums, signature) {
+		return SignatureInvalid
+	}
+	return SignatureVerified
+}
+
+// platformChecksum finds the artifact of this OS and architecture in a
+// "<sha256>  <file>" checksums file
+func platformChecksum(checksums []byte, version SemVer) (string, string, error) {
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return "", "", fmt.Errorf("malformed %s line %q", ChecksumsAsset, scanner.Text())
+		}
+		sum, file := fields[0], strings.TrimPrefix(fields[1], "*")
+		if !strings.Contains(file, platform) || !strings.Contains(file, version.String()) {
+			continue
+		}
+		if decoded, err := hex.DecodeString(sum); err != nil || len(decoded) != 32 {
+			return "", "", fmt.Errorf("invalid SHA-256 %q for %s", sum, file)
+		}
+		return file, strings.ToLower(sum), nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", err
+	}
+	return "", "", fmt.Errorf("%s has no %s artifact for v%s", ChecksumsAsset, platform, version)
+}
+
+// changelogExcerpt returns the start of the release notes
+func changelogExcerpt(body string) string {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n")), "\n")
+	truncated := false
+	if len(lines) > MaxChangelogLines {
+		lines, truncated = lines[:MaxChangelogLines], true
+	}
+
+	excerpt := strings.Join(lines, "\n")
+	if len(excerpt) > MaxChangelogChars {
+		excerpt, truncated = truncateUTF8(excerpt, MaxChangelogChars), true
+	}
+	if truncated {
+		excerpt += "\n…"
+	}
+	return excerpt
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a character
+func truncateUTF8(s string, n int) string {
+	for n > 0 && n < len(s) && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
+
+// verificationSummary describes the checksum state of a release
+func verificationSummary(v ReleaseVerification) string {
+	if v.Error != "" {
+		return "unverified: " + v.Error
+	}
+	return "ok"
+}
+
+// formatUpdateMessage renders the update alert
+func formatUpdateMessage(info *UpdateInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Running v%s, latest release is v%s", info.Current, info.Latest)
+	if !info.PublishedAt.IsZero() {
+		fmt.Fprintf(&b, " (published %s)", info.PublishedAt.Format("2006-01-02"))
+	}
+	b.WriteString("\n")
+
+	v := info.Verification
+	if v.Error != "" {
+		fmt.Fprintf(&b, "⚠️ Checksums could not be verified: %s\n", v.Error)
+	} else {
+		fmt.Fprintf(&b, "Artifact: %s\nSHA-256: %s\n", v.Artifact, v.SHA256)
+	}
+	switch v.Signature {
+	case SignatureVerified:
+		b.WriteString("Signature: verified\n")
+	case SignatureInvalid, SignatureMissing:
+		fmt.Fprintf(&b, "⚠️ Signature %s - do not install this release\n", v.Signature)
+	default:
+		b.WriteString("Signature: not checked (no update_check_public_key)\n")
+	}
+
+	if info.URL != "" {
+		fmt.Fprintf(&b, "Release: %s\n", info.URL)
+	}
+	if info.Changelog != "" {
+		fmt.Fprintf(&b, "\nChangelog:\n%s", info.Changelog)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// getJSON fetches a GitHub API URL into out
+func (uc *UpdateChecker) getJSON(ctx context.Context, rawURL string, out interface{}) error {
+	body, err := uc.get(ctx, rawURL, "application/vnd.github+json")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse release: %w", err)
+	}
+	return nil
+}
+
+// download fetches a release asset
+func (uc *UpdateChecker) download(ctx context.Context, rawURL string) ([]byte, error) {
+	return uc.get(ctx, rawURL, "application/octet-stream")
+}
+
+func (uc *UpdateChecker) get(ctx context.Context, rawURL, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "gxr-bot/"+Version)
+
+	resp, err := uc.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxReleaseMetadataSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", rawURL, resp.StatusCode)
+	}
+	return body, nil
+}
+
+// GetStatus returns the last check result
+func (uc *UpdateChecker) GetStatus() map[string]interface{} {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+
+	status := map[string]interface{}{
+		"repo":             uc.repo,
+		"current_version":  uc.current.String(),
+		"last_check":       uc.lastCheck.Format(time.RFC3339),
+		"last_error":       uc.lastError,
+		"failed_checks":    uc.checksFailed,
+		"update_available": uc.latest != nil,
+	}
+	if uc.latest != nil {
+		status["latest_version"] = uc.latest.Latest
+		status["release_url"] = uc.latest.URL
+		status["checksums"] = verificationSummary(uc.latest.Verification)
+		status["signature"] = uc.latest.Verification.Signature
+	}
+	return status
+}
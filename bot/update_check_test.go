@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const testSHA256 = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+// fakeReleases serves a GitHub latest release with its checksum assets
+type fakeReleases struct {
+	release    map[string]interface{}
+	rawRelease string // served instead of release when set
+	checksums  string
+	signature  string
+	requests   int64
+}
+
+func (f *fakeReleases) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt64(&f.requests, 1)
+	switch r.URL.Path {
+	case "/repos/gxr/bot/releases/latest":
+		if f.rawRelease != "" {
+			w.Write([]byte(f.rawRelease))
+			return
+		}
+		json.NewEncoder(w).Encode(f.release)
+	case "/download/" + ChecksumsAsset:
+		w.Write([]byte(f.checksums))
+	case "/download/" + ChecksumsSignatureAsset:
+		w.Write([]byte(f.signature))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// newFakeRelease publishes version with this platform's artifact, signing the
+// checksums with key when it is set
+func newFakeRelease(serverURL, version string, key ed25519.PrivateKey) *fakeReleases {
+	artifact := fmt.Sprintf("gxrbot_%s_%s_%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+	checksums := fmt.Sprintf("%s  %s\n%s  gxrbot_%s_plan9_mips.tar.gz\n", testSHA256, artifact, testSHA256, version)
+
+	assets := []map[string]string{
+		{"name": artifact, "browser_download_url": serverURL + "/download/" + artifact},
+		{"name": ChecksumsAsset, "browser_download_url": serverURL + "/download/" + ChecksumsAsset},
+	}
+	f := &fakeReleases{checksums: checksums}
+	if key != nil {
+		f.signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(checksums)))
+		assets = append(assets, map[string]string{
+			"name": ChecksumsSignatureAsset, "browser_download_url": serverURL + "/download/" + ChecksumsSignatureAsset,
+		})
+	}
+
+	f.release = map[string]interface{}{
+		"tag_name":     "v" + version,
+		"html_url":     "https://github.com/gxr/bot/releases/tag/v" + version,
+		"published_at": "2025-06-02T10:00:00Z",
+		"body":         "## Changes\r\n- Faster validator queries\r\n- Fix alert retries",
+		"assets":       assets,
+	}
+	return f
+}
+
+func newTestUpdateChecker(t *testing.T, publicKey ed25519.PublicKey) (*UpdateChecker, *httptest.Server, *fakeReleases) {
+	t.Helper()
+	releases := &fakeReleases{}
+	server := httptest.NewServer(releases)
+	t.Cleanup(server.Close)
+
+	config := &BotConfig{UpdateCheck: true, UpdateCheckRepo: "gxr/bot"}
+	if publicKey != nil {
+		config.UpdateCheckPublicKey = base64.StdEncoding.EncodeToString(publicKey)
+	}
+	if err := ValidateUpdateCheck(config); err != nil {
+		t.Fatal(err)
+	}
+	checker, err := NewUpdateChecker(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker.apiURL = server.URL
+	return checker, server, releases
+}
+
+func TestSemVerCompare(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"2.0.0", "v2.0.0", 0},
+		{"2.0.1", "2.0.0", 1},
+		{"2.1.0", "2.0.9", 1},
+		{"10.0.0", "9.9.9", 1},
+		{"2.1.0-rc.1", "2.1.0", -1},
+		{"2.1.0-rc.2", "2.1.0-rc.10", -1},
+		{"2.1.0-rc.1", "2.1.0-beta", 1},
+		{"2.1.0+build.5", "2.1.0", 0},
+	}
+	for _, tc := range testCases {
+		a, err := ParseSemVer(tc.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseSemVer(tc.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a.Compare(b); got != tc.want {
+			t.Errorf("%s vs %s = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+
+	for _, invalid := range []string{"", "latest", "2.0", "2.0.x", "2.0.0-"} {
+		if _, err := ParseSemVer(invalid); err == nil {
+			t.Errorf("%q parsed", invalid)
+		}
+	}
+}
+
+func TestUpdateCheckNewerRelease(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	checker, server, releases := newTestUpdateChecker(t, publicKey)
+	*releases = *newFakeRelease(server.URL, "2.1.0", privateKey)
+
+	info, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info == nil || info.Latest != "2.1.0" || info.Current != Version {
+		t.Fatalf("update = %+v", info)
+	}
+	v := info.Verification
+	if !v.Verified() || v.Signature != SignatureVerified || v.SHA256 != testSHA256 || !strings.Contains(v.Artifact, runtime.GOOS+"_"+runtime.GOARCH) {
+		t.Fatalf("verification = %+v", v)
+	}
+
+	message := formatUpdateMessage(info)
+	for _, want := range []string{"Running v2.0.0, latest release is v2.1.0", "Signature: verified", "- Fix alert retries", "releases/tag/v2.1.0"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message lacks %q:\n%s", want, message)
+		}
+	}
+	if status := checker.GetStatus(); status["update_available"] != true || status["latest_version"] != "2.1.0" {
+		t.Fatalf("status = %v", status)
+	}
+
+	// Each version is announced once
+	checker.checkAndAlert(context.Background())
+	checker.checkAndAlert(context.Background())
+	if checker.lastAlerted != "2.1.0" {
+		t.Fatalf("last alerted = %q", checker.lastAlerted)
+	}
+}
+
+func TestUpdateCheckEqualVersion(t *testing.T) {
+	checker, server, releases := newTestUpdateChecker(t, nil)
+	*releases = *newFakeRelease(server.URL, Version, nil)
+
+	info, err := checker.Check(context.Background())
+	if err != nil || info != nil {
+		t.Fatalf("same version reported as update %+v (%v)", info, err)
+	}
+	// Up to date releases are not downloaded
+	if releases.requests != 1 {
+		t.Fatalf("%d requests, want only the release query", releases.requests)
+	}
+	if status := checker.GetStatus(); status["update_available"] != false || status["last_error"] != "" {
+		t.Fatalf("status = %v", status)
+	}
+
+	releases.release["tag_name"] = "v3.0.0-rc.1"
+	releases.release["prerelease"] = true
+	if info, err := checker.Check(context.Background()); err != nil || info != nil {
+		t.Fatalf("pre-release reported as update %+v (%v)", info, err)
+	}
+}
+
+func TestUpdateCheckMalformedResponses(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	checker, server, releases := newTestUpdateChecker(t, publicKey)
+
+	// Unparseable responses are errors, never updates
+	for _, raw := range []string{`{"tag_name":`, `{"tag_name":"latest"}`, `[]`} {
+		*releases = fakeReleases{rawRelease: raw}
+		if info, err := checker.Check(context.Background()); err == nil {
+			t.Errorf("release %s accepted as %+v", raw, info)
+		}
+	}
+	if status := checker.GetStatus(); status["last_error"] == "" || status["failed_checks"] != int64(3) {
+		t.Fatalf("status = %v", status)
+	}
+
+	testCases := []struct {
+		name   string
+		mutate func(f *fakeReleases)
+		want   func(v ReleaseVerification) bool
+	}{
+		{"tampered checksums", func(f *fakeReleases) { f.checksums = strings.Replace(f.checksums, "9f86", "0000", 1) },
+			func(v ReleaseVerification) bool { return v.Signature == SignatureInvalid }},
+		{"garbage signature", func(f *fakeReleases) { f.signature = "not base64!" },
+			func(v ReleaseVerification) bool { return v.Signature == SignatureInvalid }},
+		{"bad checksum", func(f *fakeReleases) { f.checksums = strings.Replace(f.checksums, testSHA256, "xyz", 1) },
+			func(v ReleaseVerification) bool { return strings.Contains(v.Error, "invalid SHA-256") }},
+		{"no checksums", func(f *fakeReleases) { f.release["assets"] = []map[string]string{} },
+			func(v ReleaseVerification) bool { return strings.Contains(v.Error, "no checksums.txt") }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			*releases = *newFakeRelease(server.URL, "2.1.0", privateKey)
+			tc.mutate(releases)
+
+			info, err := checker.Check(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Verification.Verified() || !tc.want(info.Verification) {
+				t.Fatalf("verification = %+v", info.Verification)
+			}
+			if !strings.Contains(formatUpdateMessage(info), "⚠️") {
+				t.Fatalf("unverified release announced without a warning:\n%s", formatUpdateMessage(info))
+			}
+		})
+	}
+}
+
+func TestUpdateCheckUsesProxy(t *testing.T) {
+	var proxied int64
+	releases := &fakeReleases{}
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives absolute request URIs for the target host
+		if r.URL.Host != "releases.invalid" {
+			http.Error(w, "unexpected host "+r.URL.Host, http.StatusBadGateway)
+			return
+		}
+		atomic.AddInt64(&proxied, 1)
+		releases.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+	*releases = *newFakeRelease("http://releases.invalid", "2.0.1", nil)
+
+	checker, err := NewUpdateChecker(&BotConfig{UpdateCheckRepo: "gxr/bot", UpdateCheckProxy: proxy.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker.apiURL = "http://releases.invalid"
+
+	info, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info == nil || info.Latest != "2.0.1" || info.Verification.Signature != SignatureNotChecked || !info.Verification.Verified() {
+		t.Fatalf("update = %+v", info)
+	}
+	if proxied != 2 {
+		t.Fatalf("%d requests went through the proxy, want 2", proxied)
+	}
+}
+
+func TestValidateUpdateCheck(t *testing.T) {
+	invalid := []BotConfig{
+		{UpdateCheck: true, UpdateCheckRepo: "Genpro"},
+		{UpdateCheck: true, UpdateCheckProxy: "proxy:3128"},
+		{UpdateCheck: true, UpdateCheckPublicKey: base64.StdEncoding.EncodeToString([]byte("short"))},
+	}
+	for _, cfg := range invalid {
+		cfg := cfg
+		if err := ValidateUpdateCheck(&cfg); err == nil {
+			t.Errorf("accepted %+v", cfg)
+		}
+	}
+
+	// Disabled checks are not validated
+	if err := ValidateUpdateCheck(&BotConfig{UpdateCheckProxy: "proxy:3128"}); err != nil {
+		t.Fatal(err)
+	}
+}
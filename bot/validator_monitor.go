@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
-	"github.com/cosmos/cosmos-sdk/types/query"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 )
 
@@ -18,6 +20,8 @@ const (
 	ValidatorCheckInterval = 5 * time.Minute
 	// MonthlyResetInterval is 30 days
 	MonthlyResetInterval = 30 * 24 * time.Hour
+	// ChainMonthCheckInterval is how often the chain's block time is checked for a new month
+	ChainMonthCheckInterval = 5 * time.Minute
 	// ValidatorInactivityThreshold is 10 days per month
 	ValidatorInactivityThreshold = 10
 	// BotHeartbeatInterval is 1 minute
@@ -26,6 +30,8 @@ const (
 	BotHeartbeatTimeout = 5 * time.Minute
 	// SlashingGracePeriod is 10 minutes
 	SlashingGracePeriod = 10 * time.Minute
+	// MaxSetChangeHistory is the number of validator set changes kept
+	MaxSetChangeHistory = 50
 )
 
 // ValidatorStatus represents the status of a validator
@@ -38,6 +44,10 @@ type ValidatorStatus struct {
 	DelegatorShares string
 	Commission      string
 	
+	// Unbonding delegations, refreshed when the delegator shares change
+	UnbondingEntries int
+	UnbondingTokens  string
+	
 	// Uptime tracking
 	CurrentMonth     uint64
 	InactiveDays     uint64
@@ -60,6 +70,15 @@ type ValidatorStatus struct {
 	UptimePercent    float64
 	MonthlyUptime    float64
 	TotalMissedBlocks uint64
+	
+	// Validator score inputs of the month; BotChecks counts the checks the
+	// validator was in the bonded set at
+	BotChecks          uint64
+	BotChecksRunning   uint64
+	CommissionMovement float64
+	Jails              int
+	JailedUntil        time.Time
+	Tombstoned         bool
 }
 
 // ValidatorMonitor monitors validator performance and bot requirements
@@ -69,23 +88,46 @@ type ValidatorMonitor struct {
 	cdc           codec.Codec
 	mu            sync.RWMutex
 	
+	// Chain queries
+	stakingQuery   stakingtypes.QueryClient
+	slashingQuery  slashingtypes.QueryClient
+	consCache      *consensusAddressCache
+	lastQueryStats ValidatorQueryStats
+	queryCache     *QueryCache // serves the last bonded set through brief outages
+	chainPhase     *ChainPhaseTracker // adds the chain phase to the daily summary
+	chain          *ChainQuerier
+	broadcastQueue *BroadcastQueue // nil while slashing reports are not broadcast
+	
 	// Validator tracking
 	validators    map[string]*ValidatorStatus
 	totalValidators int
 	activeValidators int
 	
-	// Monthly tracking
+	// Monthly tracking, by the chain's block time
 	currentMonth  uint64
 	lastMonthReset time.Time
+	lastBlockTime  time.Time
+	monthChecks    uint64 // validator checks that reached the chain this month
 	
 	// Bot enforcement
 	botHeartbeats map[string]time.Time
+	heartbeatStore *HeartbeatStore // persists botHeartbeats, nil without data_dir
 	slashingQueue []string
 	
+	// Validator set change tracking
+	lastActiveSet    map[string]string // operator address -> moniker
+	setChangeHistory []ValidatorSetDiff
+	
 	// Statistics
 	totalInactiveValidators int
 	totalForfeitedRewards   float64
 	monthlyStats            map[uint64]*MonthlyStats
+	scores                  *ScoreHistory
+	
+	// Forfeiture forecast for our validator, from the chain uptime record
+	forecast           ForfeitureForecast
+	forecastAlertLevel float64
+	lastDailySummary   time.Time
 	
 	// Alert system
 	telegramAlert   *TelegramAlert
@@ -105,18 +147,31 @@ type MonthlyStats struct {
 	SlashedValidators int
 }
 
+// ValidatorSetDiff describes validators joining or leaving the bonded set between checks
+type ValidatorSetDiff struct {
+	Timestamp time.Time `json:"timestamp"`
+	Joined    []string  `json:"joined"`
+	Left      []string  `json:"left"`
+	SetSize   int       `json:"set_size"`
+}
+
 // NewValidatorMonitor creates a new validator monitor
 func NewValidatorMonitor(config *BotConfig, clientCtx client.Context, cdc codec.Codec) *ValidatorMonitor {
 	return &ValidatorMonitor{
 		config:        config,
 		clientCtx:     clientCtx,
 		cdc:           cdc,
+		stakingQuery:  stakingtypes.NewQueryClient(clientCtx),
+		slashingQuery: slashingtypes.NewQueryClient(clientCtx),
+		consCache:     loadConsensusAddressCache(config.DataDir),
+		chain:         NewChainQuerier(config),
 		validators:    make(map[string]*ValidatorStatus),
-		currentMonth:  getCurrentMonth(),
 		lastMonthReset: time.Now(),
 		botHeartbeats: make(map[string]time.Time),
+		heartbeatStore: openHeartbeatStore(config.DataDir),
 		slashingQueue: make([]string, 0),
 		monthlyStats:  make(map[uint64]*MonthlyStats),
+		scores:        LoadScoreHistory(config.DataDir),
 		telegramAlert: NewTelegramAlert(config),
 	}
 }
@@ -130,18 +185,28 @@ func (vm *ValidatorMonitor) Start(ctx context.Context) error {
 		log.Printf("Failed to send startup alert: %v", err)
 	}
 	
+	// Heartbeats seen before a restart still count toward BotHeartbeatTimeout
+	vm.reconcileHeartbeats(time.Now())
+	
+	// Align the month with the chain before the first checks
+	if err := vm.syncChainMonth(ctx); err != nil {
+		log.Printf("Failed to query chain block time, current month unknown until the next check: %v", err)
+	}
+	
 	// Start periodic checks
 	go vm.validatorCheckRoutine(ctx)
 	go vm.botMonitoringRoutine(ctx)
 	go vm.monthlyResetRoutine(ctx)
 	go vm.slashingRoutine(ctx)
+	go vm.forfeitureForecastRoutine(ctx)
+	go vm.governanceRoutine(ctx)
 	
 	return nil
 }
 
 // validatorCheckRoutine periodically checks validator status
 func (vm *ValidatorMonitor) validatorCheckRoutine(ctx context.Context) {
-	ticker := time.NewTicker(ValidatorCheckInterval)
+	ticker := NewJitteredTicker(ValidatorCheckInterval)
 	defer ticker.Stop()
 	
 	for {
@@ -158,7 +223,7 @@ func (vm *ValidatorMonitor) validatorCheckRoutine(ctx context.Context) {
 
 // botMonitoringRoutine monitors bot heartbeats
 func (vm *ValidatorMonitor) botMonitoringRoutine(ctx context.Context) {
-	ticker := time.NewTicker(BotHeartbeatInterval)
+	ticker := NewJitteredTicker(BotHeartbeatInterval)
 	defer ticker.Stop()
 	
 	for {
@@ -171,9 +236,9 @@ func (vm *ValidatorMonitor) botMonitoringRoutine(ctx context.Context) {
 	}
 }
 
-// monthlyResetRoutine resets monthly counters
+// monthlyResetRoutine resets monthly counters when the chain enters a new month
 func (vm *ValidatorMonitor) monthlyResetRoutine(ctx context.Context) {
-	ticker := time.NewTicker(MonthlyResetInterval)
+	ticker := NewJitteredTicker(ChainMonthCheckInterval)
 	defer ticker.Stop()
 	
 	for {
@@ -181,14 +246,57 @@ func (vm *ValidatorMonitor) monthlyResetRoutine(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			vm.performMonthlyReset(ctx)
+			if err := vm.syncChainMonth(ctx); err != nil {
+				log.Printf("Failed to check chain month: %v", err)
+			}
 		}
 	}
 }
 
+// syncChainMonth derives the current month from the chain's latest block
+// time, as x/halving does, so the bot and the chain agree on the month even
+// when block time drifts from the wall clock
+func (vm *ValidatorMonitor) syncChainMonth(ctx context.Context) error {
+	if vm.chain == nil {
+		return nil
+	}
+	
+	blockTime, err := vm.chain.QueryLatestBlockTime(ctx)
+	if err != nil {
+		return err
+	}
+	
+	vm.applyChainMonth(ctx, blockTime)
+	return nil
+}
+
+// applyChainMonth records the chain's block time and resets the monthly
+// counters if it is in a later month. The first block time only sets the month.
+func (vm *ValidatorMonitor) applyChainMonth(ctx context.Context, blockTime time.Time) {
+	month := uptimeMonth(blockTime)
+	
+	vm.mu.Lock()
+	first := vm.lastBlockTime.IsZero()
+	if blockTime.After(vm.lastBlockTime) {
+		vm.lastBlockTime = blockTime
+	}
+	if first {
+		vm.currentMonth = month
+		vm.mu.Unlock()
+		log.Printf("Current month %d from chain block time %s", month, blockTime.Format(time.RFC3339))
+		return
+	}
+	newMonth := month > vm.currentMonth
+	vm.mu.Unlock()
+	
+	if newMonth {
+		vm.performMonthlyReset(ctx, month)
+	}
+}
+
 // slashingRoutine handles validator slashing for bot non-compliance
 func (vm *ValidatorMonitor) slashingRoutine(ctx context.Context) {
-	ticker := time.NewTicker(SlashingGracePeriod)
+	ticker := NewJitteredTicker(SlashingGracePeriod)
 	defer ticker.Stop()
 	
 	for {
@@ -203,17 +311,33 @@ func (vm *ValidatorMonitor) slashingRoutine(ctx context.Context) {
 
 // checkAllValidators checks all bonded validators
 func (vm *ValidatorMonitor) checkAllValidators(ctx context.Context) error {
+	// Query without holding the lock so status readers are not blocked
+	vm.mu.RLock()
+	knownShares := make(map[string]string, len(vm.validators))
+	for addr, status := range vm.validators {
+		knownShares[addr] = status.DelegatorShares
+	}
+	vm.mu.RUnlock()
+	
+	snapshot, stats, err := vm.fetchValidatorSnapshot(ctx, knownShares)
+	
 	vm.mu.Lock()
 	defer vm.mu.Unlock()
 	
-	// Query all validators
-	validators, err := vm.queryValidators(ctx)
+	vm.lastQueryStats = stats
 	if err != nil {
 		return fmt.Errorf("failed to query validators: %w", err)
 	}
+	validators := snapshot.validators
+	
+	// Detect validators entering or leaving the bonded set
+	vm.trackValidatorSetChanges(validators)
 	
 	activeCount := 0
 	inactiveCount := 0
+	if !stats.FromCache {
+		vm.monthChecks++
+	}
 	
 	for _, validator := range validators {
 		status, exists := vm.validators[validator.OperatorAddress]
@@ -229,8 +353,18 @@ func (vm *ValidatorMonitor) checkAllValidators(ctx context.Context) error {
 			vm.validators[validator.OperatorAddress] = status
 		}
 		
-		// Update validator status
-		vm.updateValidatorStatus(status, validator)
+		// Update validator status; a cached bonded set is no new observation
+		// of validators already tracked, so their status is left as it was
+		if !exists || !stats.FromCache {
+			vm.updateValidatorStatus(status, validator)
+			if missed, ok := snapshot.missedBlocks[validator.OperatorAddress]; ok {
+				status.MissedBlocks = missed
+			}
+			if unbonding, ok := snapshot.unbonding[validator.OperatorAddress]; ok {
+				status.UnbondingEntries = unbonding.Entries
+				status.UnbondingTokens = unbonding.Tokens
+			}
+		}
 		
 		// Check inactivity
 		if vm.isValidatorInactive(status) {
@@ -243,36 +377,109 @@ func (vm *ValidatorMonitor) checkAllValidators(ctx context.Context) error {
 		}
 		
 		// Check bot requirement
-		if !vm.isValidatorBotRunning(status) {
+		botRunning := vm.isValidatorBotRunning(status)
+		if !stats.FromCache {
+			status.BotChecks++
+			if botRunning {
+				status.BotChecksRunning++
+			}
+		}
+		if !botRunning {
 			vm.queueForSlashing(status.OperatorAddress)
 		}
 	}
 	
+	// Uptime is the share of this month's checks a validator was bonded at
+	if !stats.FromCache {
+		vm.trackSlashing(snapshot.slashing)
+		for _, status := range vm.validators {
+			status.UptimePercent = float64(status.BotChecks) / float64(vm.monthChecks) * 100
+		}
+	}
+	
 	vm.totalValidators = len(validators)
 	vm.activeValidators = activeCount
 	vm.totalInactiveValidators = inactiveCount
 	
-	log.Printf("Validator check complete - Total: %d, Active: %d, Inactive: %d", 
-		vm.totalValidators, vm.activeValidators, vm.totalInactiveValidators)
+	log.Printf("Validator check complete - Total: %d, Active: %d, Inactive: %d, Queries: %d in %s", 
+		vm.totalValidators, vm.activeValidators, vm.totalInactiveValidators,
+		stats.Queries, stats.Duration.Round(time.Millisecond))
 	
 	return nil
 }
 
-// queryValidators queries all validators from the chain
-func (vm *ValidatorMonitor) queryValidators(ctx context.Context) ([]stakingtypes.Validator, error) {
-	queryClient := stakingtypes.NewQueryClient(vm.clientCtx)
+// trackValidatorSetChanges compares the bonded set with the previous check and
+// records and alerts on any churn. The first check only establishes the baseline.
+func (vm *ValidatorMonitor) trackValidatorSetChanges(validators []stakingtypes.Validator) {
+	currentSet := make(map[string]string, len(validators))
+	for _, validator := range validators {
+		currentSet[validator.OperatorAddress] = validator.Description.Moniker
+	}
 	
-	resp, err := queryClient.Validators(ctx, &stakingtypes.QueryValidatorsRequest{
-		Status: stakingtypes.BondStatusBonded,
-		Pagination: &query.PageRequest{
-			Limit: 1000,
-		},
-	})
-	if err != nil {
-		return nil, err
+	previousSet := vm.lastActiveSet
+	vm.lastActiveSet = currentSet
+	
+	if previousSet == nil {
+		return
+	}
+	
+	diff := diffValidatorSets(previousSet, currentSet)
+	if len(diff.Joined) == 0 && len(diff.Left) == 0 {
+		return
+	}
+	
+	vm.setChangeHistory = append(vm.setChangeHistory, diff)
+	if len(vm.setChangeHistory) > MaxSetChangeHistory {
+		vm.setChangeHistory = vm.setChangeHistory[1:]
+	}
+	
+	summary := fmt.Sprintf("%d validators joined, %d left the active set", len(diff.Joined), len(diff.Left))
+	log.Printf("Validator set changed - %s", summary)
+	
+	message := fmt.Sprintf("🔀 Validator Set Change\n\n%s\nActive set size: %d", summary, diff.SetSize)
+	if len(diff.Joined) > 0 {
+		message += fmt.Sprintf("\nJoined: %s", strings.Join(diff.Joined, ", "))
+	}
+	if len(diff.Left) > 0 {
+		message += fmt.Sprintf("\nLeft: %s", strings.Join(diff.Left, ", "))
+	}
+	
+	vm.sendAlert("Validator Set Change", message)
+}
+
+// diffValidatorSets returns the monikers of validators added to and removed from the set
+func diffValidatorSets(previous, current map[string]string) ValidatorSetDiff {
+	diff := ValidatorSetDiff{
+		Timestamp: time.Now(),
+		Joined:    make([]string, 0),
+		Left:      make([]string, 0),
+		SetSize:   len(current),
 	}
 	
-	return resp.Validators, nil
+	for addr, moniker := range current {
+		if _, existed := previous[addr]; !existed {
+			diff.Joined = append(diff.Joined, validatorLabel(addr, moniker))
+		}
+	}
+	
+	for addr, moniker := range previous {
+		if _, exists := current[addr]; !exists {
+			diff.Left = append(diff.Left, validatorLabel(addr, moniker))
+		}
+	}
+	
+	sort.Strings(diff.Joined)
+	sort.Strings(diff.Left)
+	
+	return diff
+}
+
+// validatorLabel returns the moniker, falling back to the operator address
+func validatorLabel(addr, moniker string) string {
+	if moniker == "" {
+		return addr
+	}
+	return moniker
 }
 
 // updateValidatorStatus updates a validator's status
@@ -281,6 +488,9 @@ func (vm *ValidatorMonitor) updateValidatorStatus(status *ValidatorStatus, valid
 	status.Jailed = validator.Jailed
 	status.Tokens = validator.Tokens.String()
 	status.DelegatorShares = validator.DelegatorShares.String()
+	if rate := validator.Commission.Rate.String(); status.Commission != "" && rate != status.Commission {
+		status.CommissionMovement += commissionChange(status.Commission, rate)
+	}
 	status.Commission = validator.Commission.Rate.String()
 	status.LastCheck = time.Now()
 	
@@ -330,12 +540,14 @@ func (vm *ValidatorMonitor) markValidatorInactive(status *ValidatorStatus) {
 	message := fmt.Sprintf("⚠️ Validator Inactivity Alert\n\nValidator: %s\nInactive Days: %d/%d\nStatus: Reward Forfeited\nMonth: %d", 
 		status.Moniker, status.InactiveDays, ValidatorInactivityThreshold, vm.currentMonth)
 	
-	vm.sendAlert("Validator Inactivity", message)
+	vm.deliverAlert(func() error {
+		return vm.telegramAlert.SendValidatorAlert(status.Moniker, status.OperatorAddress, message, int(status.InactiveDays))
+	})
 }
 
 // isValidatorBotRunning checks if validator's bot is running
 func (vm *ValidatorMonitor) isValidatorBotRunning(status *ValidatorStatus) bool {
-	lastHeartbeat, exists := vm.botHeartbeats[status.OperatorAddress]
+	lastHeartbeat, exists := vm.lastBotHeartbeat(status.OperatorAddress)
 	if !exists {
 		return false
 	}
@@ -366,7 +578,7 @@ func (vm *ValidatorMonitor) checkBotHeartbeats(ctx context.Context) {
 	inactiveValidators := 0
 	
 	for addr, status := range vm.validators {
-		lastHeartbeat, exists := vm.botHeartbeats[addr]
+		lastHeartbeat, exists := vm.lastBotHeartbeat(addr)
 		if !exists {
 			lastHeartbeat = now.Add(-time.Hour) // Assume old heartbeat
 		}
@@ -416,8 +628,8 @@ func (vm *ValidatorMonitor) processSlashingQueue(ctx context.Context) {
 
 // slashValidator executes slashing for a validator
 func (vm *ValidatorMonitor) slashValidator(ctx context.Context, operatorAddr string) error {
-	// In a real implementation, this would submit a slashing transaction
-	// For now, we'll just log and send alerts
+	// The slashing report is broadcast when the bot has a broadcaster,
+	// otherwise this only logs and sends alerts
 	
 	status, exists := vm.validators[operatorAddr]
 	if !exists {
@@ -427,20 +639,28 @@ func (vm *ValidatorMonitor) slashValidator(ctx context.Context, operatorAddr str
 	log.Printf("SLASHING: Validator %s (%s) for bot non-compliance", 
 		status.Moniker, operatorAddr)
 	
+	// A failed broadcast stays queued and is retried, so it is not an error here
+	if vm.broadcastQueue != nil {
+		report := BotTx{Kind: TxKindSlashingReport, Validator: operatorAddr, Reason: "Mandatory bot not running"}
+		if err := vm.broadcastQueue.Submit(ctx, report, time.Now()); err != nil {
+			log.Printf("Slashing report for %s not sent: %v", operatorAddr, err)
+		}
+	}
+	
 	// Send slashing alert
 	message := fmt.Sprintf("⚔️ Validator Slashed\n\nValidator: %s\nReason: Mandatory bot not running\nTime: %s", 
 		status.Moniker, time.Now().Format("2006-01-02 15:04:05"))
 	
-	return vm.sendAlert("Validator Slashed", message)
+	return vm.sendValidatorAlert(AlertTypeError, operatorAddr, "Validator Slashed", message)
 }
 
 // performMonthlyReset resets monthly counters
-func (vm *ValidatorMonitor) performMonthlyReset(ctx context.Context) {
+func (vm *ValidatorMonitor) performMonthlyReset(ctx context.Context, month uint64) {
 	vm.mu.Lock()
 	defer vm.mu.Unlock()
 	
 	oldMonth := vm.currentMonth
-	vm.currentMonth = getCurrentMonth()
+	vm.currentMonth = month
 	vm.lastMonthReset = time.Now()
 	
 	// Store monthly statistics
@@ -454,12 +674,22 @@ func (vm *ValidatorMonitor) performMonthlyReset(ctx context.Context) {
 		BotsRunning:        vm.countRunningBots(),
 	}
 	
+	// Score the month and snapshot its report before its counters are reset
+	vm.closeMonthScores(oldMonth)
+	vm.saveMonthlyReport(vm.lastMonthReset)
+	
 	// Reset all validator monthly counters
+	vm.monthChecks = 0
 	for _, status := range vm.validators {
 		status.CurrentMonth = vm.currentMonth
 		status.InactiveDays = 0
 		status.RewardEligible = true
 		status.MissedBlocks = 0
+		status.BotChecks = 0
+		status.BotChecksRunning = 0
+		status.UptimePercent = 0
+		status.CommissionMovement = 0
+		status.Jails = 0
 	}
 	
 	log.Printf("Monthly reset completed - Month %d -> %d", oldMonth, vm.currentMonth)
@@ -498,13 +728,32 @@ func (vm *ValidatorMonitor) RegisterBotHeartbeat(operatorAddr string, version st
 	vm.mu.Lock()
 	defer vm.mu.Unlock()
 	
-	vm.botHeartbeats[operatorAddr] = time.Now()
+	now := time.Now()
+	vm.botHeartbeats[operatorAddr] = now
+	if vm.heartbeatStore != nil {
+		if err := vm.heartbeatStore.Set(operatorAddr, now); err != nil {
+			log.Printf("Failed to persist bot heartbeat of %s: %v", operatorAddr, err)
+		}
+	}
 	
 	if status, exists := vm.validators[operatorAddr]; exists {
 		status.BotRunning = true
 		status.BotVersion = version
-		status.LastBotHeartbeat = time.Now()
+		status.LastBotHeartbeat = now
+	}
+}
+
+// BotHeartbeats returns the time of the last bot heartbeat of each validator
+func (vm *ValidatorMonitor) BotHeartbeats() map[string]time.Time {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	
+	result := make(map[string]time.Time, len(vm.botHeartbeats))
+	for addr, heartbeat := range vm.botHeartbeats {
+		result[addr] = heartbeat
 	}
+	
+	return result
 }
 
 // sendBotInactivityAlert sends an alert for bot inactivity
@@ -513,7 +762,51 @@ func (vm *ValidatorMonitor) sendBotInactivityAlert(status *ValidatorStatus) {
 		status.Moniker, 
 		status.LastBotHeartbeat.Format("2006-01-02 15:04:05"))
 	
-	vm.sendAlert("Bot Inactivity", message)
+	vm.sendValidatorAlert(AlertTypeWarning, status.OperatorAddress, "Bot Inactivity", message)
+}
+
+// saveMonthlyReport persists the report snapshot of our validator for the
+// month that just closed, under the calendar month it closed in, for
+// `report publish`. Incidents are the error journal entries of that month.
+func (vm *ValidatorMonitor) saveMonthlyReport(closedAt time.Time) {
+	status, exists := vm.validators[vm.config.ValidatorAddress]
+	if !exists {
+		return
+	}
+	
+	month := closedAt.Format(ReportMonthLayout)
+	report := &MonthlyReport{
+		Month:            month,
+		ValidatorName:    status.Moniker,
+		ValidatorAddress: status.OperatorAddress,
+		GeneratedAt:      closedAt,
+		UptimePercent:    status.UptimePercent,
+		MissedBlocks:     status.MissedBlocks,
+		Forfeited:        uint64(status.ForfeitedRewards * UgenPerGXR),
+		Incidents:        []ReportIncident{},
+	}
+	if status.BotChecks > 0 {
+		report.BotCompliance = float64(status.BotChecksRunning) / float64(status.BotChecks) * 100
+	}
+	if vm.chainPhase != nil {
+		if info := vm.chainPhase.Current(); info.Phase != "" {
+			report.ChainPhase = &info
+		}
+	}
+	
+	start, _ := ParseReportMonth(month)
+	for _, entry := range NewErrorJournal(vm.config.DataDir).Entries() {
+		if entry.Timestamp.Before(start) || entry.Timestamp.After(closedAt) {
+			continue
+		}
+		report.Incidents = append(report.Incidents, ReportIncident{Timestamp: entry.Timestamp, Severity: entry.Severity, Summary: entry.Summary})
+	}
+	
+	if err := SaveMonthlyReport(vm.config.Reports.DataDir, report); err != nil {
+		log.Printf("Failed to save the monthly report of %s: %v", month, err)
+		return
+	}
+	log.Printf("Monthly report of %s saved", month)
 }
 
 // sendMonthlyReport sends a monthly statistics report
@@ -531,12 +824,28 @@ func (vm *ValidatorMonitor) sendMonthlyReport(month uint64) {
 		stats.ForfeitedRewards,
 		stats.AverageUptime,
 		stats.BotsRunning)
+	message += vm.scoreReportLines(month)
 	
 	vm.sendAlert("Monthly Report", message)
 }
 
 // sendAlert sends a telegram alert
 func (vm *ValidatorMonitor) sendAlert(title, message string) error {
+	return vm.deliverAlert(func() error {
+		return vm.telegramAlert.SendAlert(fmt.Sprintf("%s\n\n%s", title, message))
+	})
+}
+
+// sendValidatorAlert sends an alert about a validator, also routed to the
+// chat validator_chat_map configures for it
+func (vm *ValidatorMonitor) sendValidatorAlert(alertType AlertType, validatorAddress, title, message string) error {
+	return vm.deliverAlert(func() error {
+		return vm.telegramAlert.SendValidatorAlertWithType(alertType, validatorAddress, title, message)
+	})
+}
+
+// deliverAlert queues an alert with send, at most one every 2 minutes
+func (vm *ValidatorMonitor) deliverAlert(send func() error) error {
 	if vm.telegramAlert == nil {
 		return nil
 	}
@@ -546,8 +855,7 @@ func (vm *ValidatorMonitor) sendAlert(title, message string) error {
 		return nil
 	}
 	
-	fullMessage := fmt.Sprintf("%s\n\n%s", title, message)
-	if err := vm.telegramAlert.SendAlert(fullMessage); err != nil {
+	if err := send(); err != nil {
 		log.Printf("Failed to send alert: %v", err)
 		return err
 	}
@@ -604,17 +912,47 @@ func (vm *ValidatorMonitor) GetStatus() map[string]interface{} {
 		"inactive_validators":      vm.totalInactiveValidators,
 		"current_month":           vm.currentMonth,
 		"last_month_reset":        vm.lastMonthReset.Format(time.RFC3339),
+		"last_block_time":         vm.lastBlockTime.Format(time.RFC3339),
 		"slashing_queue_size":     len(vm.slashingQueue),
 		"running_bots":            vm.countRunningBots(),
 		"total_forfeited_rewards": vm.totalForfeitedRewards,
 		"alerts_sent":             vm.alertsSent,
 		"average_uptime":          vm.calculateAverageUptime(),
+		"set_changes_recorded":    len(vm.setChangeHistory),
+		"latest_set_diff":         vm.latestSetDiff(),
+		"last_cycle_queries":      vm.lastQueryStats.Queries,
+		"last_cycle_query_ms":     vm.lastQueryStats.Duration.Milliseconds(),
+		"last_cycle_unbonding_refreshed": vm.lastQueryStats.UnbondingRefreshed,
+		"last_cycle_from_cache":   vm.lastQueryStats.FromCache,
+		"last_cycle_data_age_seconds": int64(vm.lastQueryStats.DataAge.Seconds()),
+		"forfeiture_forecast":     vm.forecastStatus(),
 	}
 }
 
-// getCurrentMonth returns current month identifier
-func getCurrentMonth() uint64 {
-	return uint64(time.Now().Unix() / int64(30*24*time.Hour.Seconds()))
+// latestSetDiff returns the most recent validator set change, if any
+func (vm *ValidatorMonitor) latestSetDiff() map[string]interface{} {
+	if len(vm.setChangeHistory) == 0 {
+		return nil
+	}
+	
+	diff := vm.setChangeHistory[len(vm.setChangeHistory)-1]
+	return map[string]interface{}{
+		"timestamp": diff.Timestamp.Format(time.RFC3339),
+		"joined":    diff.Joined,
+		"left":      diff.Left,
+		"set_size":  diff.SetSize,
+	}
+}
+
+// GetSetChangeHistory returns the recorded validator set changes
+func (vm *ValidatorMonitor) GetSetChangeHistory() []ValidatorSetDiff {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	
+	history := make([]ValidatorSetDiff, len(vm.setChangeHistory))
+	copy(history, vm.setChangeHistory)
+	
+	return history
 }
 
 // Stop gracefully stops the validator monitor
@@ -626,4 +964,11 @@ func (vm *ValidatorMonitor) Stop() {
 		vm.totalValidators, vm.alertsSent)
 	
 	vm.sendAlert("Monitor Stopped", "Validator monitor stopped")
+	
+	if vm.heartbeatStore != nil {
+		if err := vm.heartbeatStore.Close(); err != nil {
+			log.Printf("Failed to close heartbeat store: %v", err)
+		}
+		vm.heartbeatStore = nil
+	}
 }
\ No newline at end of file
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"google.golang.org/grpc"
+)
+
+// fakeStakingQuery serves a fixed validator set, counting calls and the
+// number of unbonding queries in flight
+type fakeStakingQuery struct {
+	stakingtypes.QueryClient
+	validators []stakingtypes.Validator
+
+	validatorCalls int64
+	unbondingCalls int64
+	inFlight       int64
+	maxInFlight    int64
+}
+
+func (f *fakeStakingQuery) Validators(_ context.Context, req *stakingtypes.QueryValidatorsRequest, _ ...grpc.CallOption) (*stakingtypes.QueryValidatorsResponse, error) {
+	atomic.AddInt64(&f.validatorCalls, 1)
+	page, next := paginate(len(f.validators), req.Pagination)
+	return &stakingtypes.QueryValidatorsResponse{
+		Validators: f.validators[page[0]:page[1]],
+		Pagination: next,
+	}, nil
+}
+
+func (f *fakeStakingQuery) ValidatorUnbondingDelegations(_ context.Context, req *stakingtypes.QueryValidatorUnbondingDelegationsRequest, _ ...grpc.CallOption) (*stakingtypes.QueryValidatorUnbondingDelegationsResponse, error) {
+	atomic.AddInt64(&f.unbondingCalls, 1)
+	current := atomic.AddInt64(&f.inFlight, 1)
+	defer atomic.AddInt64(&f.inFlight, -1)
+	for {
+		peak := atomic.LoadInt64(&f.maxInFlight)
+		if current <= peak || atomic.CompareAndSwapInt64(&f.maxInFlight, peak, current) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return &stakingtypes.QueryValidatorUnbondingDelegationsResponse{Pagination: &query.PageResponse{}}, nil
+}
+
+// fakeSlashingQuery serves signing infos for a fixed set of consensus addresses
+type fakeSlashingQuery struct {
+	slashingtypes.QueryClient
+	infos []slashingtypes.ValidatorSigningInfo
+	calls int64
+}
+
+func (f *fakeSlashingQuery) SigningInfos(_ context.Context, req *slashingtypes.QuerySigningInfosRequest, _ ...grpc.CallOption) (*slashingtypes.QuerySigningInfosResponse, error) {
+	atomic.AddInt64(&f.calls, 1)
+	page, next := paginate(len(f.infos), req.Pagination)
+	return &slashingtypes.QuerySigningInfosResponse{
+		Info:       f.infos[page[0]:page[1]],
+		Pagination: next,
+	}, nil
+}
+
+// paginate returns the [start, end) range for a page request, using the
+// decimal offset as the page key
+func paginate(total int, req *query.PageRequest) ([2]int, *query.PageResponse) {
+	start := 0
+	if req != nil && len(req.Key) > 0 {
+		fmt.Sscan(string(req.Key), &start)
+	}
+	end := total
+	if req != nil && req.Limit > 0 && start+int(req.Limit) < total {
+		end = start + int(req.Limit)
+	}
+
+	resp := &query.PageResponse{}
+	if end < total {
+		resp.NextKey = []byte(fmt.Sprint(end))
+	}
+	return [2]int{start, end}, resp
+}
+
+func newBatchTestMonitor(t *testing.T, dataDir string, count int) (*ValidatorMonitor, *fakeStakingQuery, *fakeSlashingQuery) {
+	t.Helper()
+
+	staking := &fakeStakingQuery{}
+	slashing := &fakeSlashingQuery{}
+	for i := 0; i < count; i++ {
+		pubKey := ed25519.GenPrivKey().PubKey()
+		operator, err := bech32.ConvertAndEncode("gxrvaloper", []byte(fmt.Sprintf("validator-operator-%02d", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		validator, err := stakingtypes.NewValidator(operator, pubKey, stakingtypes.Description{Moniker: fmt.Sprintf("val-%02d", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		staking.validators = append(staking.validators, validator)
+
+		consAddr, err := bech32.ConvertAndEncode("gxrvalcons", pubKey.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		slashing.infos = append(slashing.infos, slashingtypes.ValidatorSigningInfo{
+			Address:             consAddr,
+			MissedBlocksCounter: int64(i),
+		})
+	}
+
+	vm := NewValidatorMonitor(&BotConfig{DataDir: dataDir}, client.Context{}, nil)
+	t.Cleanup(vm.Stop)
+	vm.telegramAlert = nil
+	vm.stakingQuery = staking
+	vm.slashingQuery = slashing
+	return vm, staking, slashing
+}
+
+func TestValidatorMonitorQueryBudget(t *testing.T) {
+	const validatorCount = 85
+	// One paginated Validators and SigningInfos walk plus one unbonding
+	// query per validator on the first cycle
+	const firstCycleBudget = 2*(validatorCount/ValidatorQueryPageLimit+1) + validatorCount
+	// Unchanged validators only need the bulk queries
+	const steadyCycleBudget = 2 * (validatorCount/ValidatorQueryPageLimit + 1)
+
+	dataDir := t.TempDir()
+	vm, staking, slashing := newBatchTestMonitor(t, dataDir, validatorCount)
+	ctx := context.Background()
+
+	if err := vm.checkAllValidators(ctx); err != nil {
+		t.Fatal(err)
+	}
+	first := vm.lastQueryStats
+	if first.Queries > firstCycleBudget {
+		t.Fatalf("first cycle issued %d queries, budget %d", first.Queries, firstCycleBudget)
+	}
+	if staking.unbondingCalls != validatorCount {
+		t.Fatalf("unbonding queries = %d, want %d", staking.unbondingCalls, validatorCount)
+	}
+	if staking.maxInFlight > ValidatorQueryWorkers {
+		t.Fatalf("%d unbonding queries in flight, limit %d", staking.maxInFlight, ValidatorQueryWorkers)
+	}
+	if vm.totalValidators != validatorCount {
+		t.Fatalf("tracked %d validators, want %d", vm.totalValidators, validatorCount)
+	}
+
+	// Missed blocks come from the bulk signing-info query via the consensus address
+	for i, validator := range staking.validators {
+		status := vm.validators[validator.OperatorAddress]
+		if status.MissedBlocks != uint64(i) {
+			t.Fatalf("%s missed blocks = %d, want %d", validator.OperatorAddress, status.MissedBlocks, i)
+		}
+	}
+
+	if err := vm.checkAllValidators(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if vm.lastQueryStats.Queries > steadyCycleBudget {
+		t.Fatalf("steady cycle issued %d queries, budget %d", vm.lastQueryStats.Queries, steadyCycleBudget)
+	}
+	if staking.unbondingCalls != validatorCount {
+		t.Fatalf("unchanged validators were re-queried: %d unbonding queries", staking.unbondingCalls)
+	}
+
+	// A change in delegator shares refreshes only that validator
+	vm.validators[staking.validators[0].OperatorAddress].DelegatorShares = "stale"
+	if err := vm.checkAllValidators(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if vm.lastQueryStats.UnbondingRefreshed != 1 || staking.unbondingCalls != validatorCount+1 {
+		t.Fatalf("refreshed %d validators (%d unbonding queries), want 1", vm.lastQueryStats.UnbondingRefreshed, staking.unbondingCalls)
+	}
+
+	status := vm.GetStatus()
+	if status["last_cycle_queries"] != vm.lastQueryStats.Queries {
+		t.Fatalf("status reports %v queries, want %d", status["last_cycle_queries"], vm.lastQueryStats.Queries)
+	}
+	if slashing.calls == 0 {
+		t.Fatal("signing infos were not queried")
+	}
+}
+
+func TestConsensusAddressCachePersists(t *testing.T) {
+	dataDir := t.TempDir()
+	vm, staking, _ := newBatchTestMonitor(t, dataDir, 3)
+
+	mapping := vm.consensusAddresses(staking.validators)
+	if len(mapping) != 3 {
+		t.Fatalf("derived %d consensus addresses, want 3", len(mapping))
+	}
+
+	reloaded := loadConsensusAddressCache(dataDir)
+	for consAddr, operator := range mapping {
+		cached, ok := reloaded.get(operator)
+		if !ok || cached != consAddr {
+			t.Fatalf("cache for %s = %q, want %q", operator, cached, consAddr)
+		}
+	}
+
+	// Cached entries are used as is, without deriving them again
+	reloaded.set(staking.validators[0].OperatorAddress, "gxrvalcons1cached")
+	vm.consCache = reloaded
+	mapping = vm.consensusAddresses(staking.validators)
+	if mapping["gxrvalcons1cached"] != staking.validators[0].OperatorAddress {
+		t.Fatal("cached consensus address was not used")
+	}
+}
+
+// fakeBlockTime serves the latest block at a settable time
+type fakeBlockTime struct {
+	mu   sync.Mutex
+	time time.Time
+}
+
+func (f *fakeBlockTime) set(t time.Time) {
+	f.mu.Lock()
+	f.time = t
+	f.mu.Unlock()
+}
+
+func (f *fakeBlockTime) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/cosmos/base/tendermint/v1beta1/blocks/latest" {
+		http.NotFound(w, r)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fmt.Fprintf(w, `{"block":{"header":{"height":"1200","time":%q}}}`, f.time.Format(time.RFC3339Nano))
+}
+
+func TestCurrentMonthFollowsChainBlockTime(t *testing.T) {
+	// The chain lags the wall clock by several months, e.g. after a halt
+	blockTime := monthStartAt(time.Now()).Add(-4*MonthlyResetInterval + 2*time.Hour)
+	chain := &fakeBlockTime{time: blockTime}
+	server := httptest.NewServer(chain)
+	defer server.Close()
+
+	vm := NewValidatorMonitor(&BotConfig{ChainAPI: server.URL}, client.Context{}, nil)
+	vm.telegramAlert = nil
+	vm.validators["gxrvaloper1a"] = &ValidatorStatus{InactiveDays: 4}
+
+	ctx := context.Background()
+	if err := vm.syncChainMonth(ctx); err != nil {
+		t.Fatal(err)
+	}
+	month := uptimeMonth(blockTime)
+	if vm.currentMonth != month || month == uptimeMonth(time.Now()) {
+		t.Fatalf("current month = %d, want %d from the block time, not the wall clock %d", vm.currentMonth, month, uptimeMonth(time.Now()))
+	}
+	if len(vm.monthlyStats) != 0 {
+		t.Fatal("the first block time reset the month")
+	}
+
+	// Blocks within the same chain month do not reset the counters
+	chain.set(blockTime.Add(MonthlyResetInterval - 3*time.Hour))
+	if err := vm.syncChainMonth(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if vm.currentMonth != month || vm.validators["gxrvaloper1a"].InactiveDays != 4 {
+		t.Fatalf("month %d reset within the chain month", vm.currentMonth)
+	}
+
+	// The reset happens when the chain, not the wall clock, enters the next month
+	chain.set(blockTime.Add(MonthlyResetInterval))
+	if err := vm.syncChainMonth(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if vm.currentMonth != month+1 {
+		t.Fatalf("current month = %d, want %d", vm.currentMonth, month+1)
+	}
+	if _, ok := vm.monthlyStats[month]; !ok {
+		t.Fatalf("no statistics stored for month %d", month)
+	}
+	status := vm.validators["gxrvaloper1a"]
+	if status.CurrentMonth != month+1 || status.InactiveDays != 0 {
+		t.Fatalf("validator counters not reset: %+v", status)
+	}
+	if got := vm.GetStatus()["last_block_time"]; got != blockTime.Add(MonthlyResetInterval).Format(time.RFC3339) {
+		t.Fatalf("status last_block_time = %v", got)
+	}
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+const (
+	// ValidatorQueryPageLimit is the page size for the bulk validator and signing-info queries
+	ValidatorQueryPageLimit = 100
+	// ValidatorQueryWorkers bounds the per-validator queries running in parallel
+	ValidatorQueryWorkers = 8
+	// ConsensusAddressCacheFile stores the operator -> consensus address mapping in DataDir
+	ConsensusAddressCacheFile = "validator_consensus_cache.json"
+)
+
+// ValidatorQueryStats records the chain queries issued by one validator check cycle
+type ValidatorQueryStats struct {
+	Queries            int64
+	UnbondingRefreshed int
+	Duration           time.Duration
+	CompletedAt        time.Time
+	// FromCache is set when the validator query failed and the cached bonded
+	// set, DataAge old, was used instead
+	FromCache bool
+	DataAge   time.Duration
+}
+
+// validatorSnapshot is what one check cycle fetched from the chain
+type validatorSnapshot struct {
+	validators []stakingtypes.Validator
+	// missedBlocks is keyed by operator address; validators without a signing
+	// info or a known consensus address are absent
+	missedBlocks map[string]uint64
+	// unbonding only holds validators whose unbonding delegations were refreshed
+	unbonding map[string]unbondingSummary
+	// slashing is keyed by operator address and also covers tracked
+	// validators that left the bonded set
+	slashing map[string]slashingSummary
+}
+
+// slashingSummary is the jailing state of a validator's signing info
+type slashingSummary struct {
+	JailedUntil time.Time
+	Tombstoned  bool
+}
+
+// unbondingSummary aggregates a validator's unbonding delegations
+type unbondingSummary struct {
+	Entries int
+	Tokens  string
+}
+
+// fetchValidatorSnapshot fetches the bonded set with paginated bulk queries and
+// refreshes unbonding delegations through a bounded worker pool. Unbonding is
+// only re-queried for new validators and those whose delegator shares changed,
+// since any unbonding changes the shares. knownShares maps operator addresses
+// to the delegator shares seen in the previous cycle.
+func (vm *ValidatorMonitor) fetchValidatorSnapshot(ctx context.Context, knownShares map[string]string) (*validatorSnapshot, ValidatorQueryStats, error) {
+	start := time.Now()
+	var queries int64
+
+	validators, cached, err := CachedQuery(vm.queryCache, QueryCacheValidators, func() ([]stakingtypes.Validator, error) {
+		return vm.queryValidators(ctx, &queries)
+	})
+	if err != nil {
+		return nil, ValidatorQueryStats{Queries: queries}, err
+	}
+
+	snapshot := &validatorSnapshot{
+		validators:   validators,
+		missedBlocks: make(map[string]uint64),
+		unbonding:    make(map[string]unbondingSummary),
+		slashing:     make(map[string]slashingSummary),
+	}
+
+	// The chain is unreachable: skip the follow-up queries and keep the
+	// missed blocks and unbonding seen last
+	if cached.Cached {
+		return snapshot, ValidatorQueryStats{
+			Queries:     atomic.LoadInt64(&queries),
+			Duration:    time.Since(start),
+			CompletedAt: time.Now(),
+			FromCache:   true,
+			DataAge:     cached.Age,
+		}, nil
+	}
+
+	// Missed blocks are best effort: a failure must not stop the check cycle
+	if infos, err := vm.querySigningInfos(ctx, &queries); err != nil {
+		log.Printf("Failed to query signing infos: %v", err)
+	} else {
+		consToOperator := vm.consensusAddresses(validators)
+		for operator := range knownShares {
+			if consAddr, ok := vm.consCache.get(operator); ok {
+				if _, seen := consToOperator[consAddr]; !seen {
+					consToOperator[consAddr] = operator
+				}
+			}
+		}
+		for _, info := range infos {
+			operator, ok := consToOperator[info.Address]
+			if !ok {
+				continue
+			}
+			snapshot.missedBlocks[operator] = uint64(info.MissedBlocksCounter)
+			snapshot.slashing[operator] = slashingSummary{JailedUntil: info.JailedUntil.UTC(), Tombstoned: info.Tombstoned}
+		}
+	}
+
+	var refresh []string
+	for _, validator := range validators {
+		shares, known := knownShares[validator.OperatorAddress]
+		if !known || shares != validator.DelegatorShares.String() {
+			refresh = append(refresh, validator.OperatorAddress)
+		}
+	}
+	snapshot.unbonding = vm.queryUnbondingSummaries(ctx, refresh, &queries)
+
+	stats := ValidatorQueryStats{
+		Queries:            atomic.LoadInt64(&queries),
+		UnbondingRefreshed: len(refresh),
+		Duration:           time.Since(start),
+		CompletedAt:        time.Now(),
+	}
+	return snapshot, stats, nil
+}
+
+// queryValidators queries all bonded validators page by page
+func (vm *ValidatorMonitor) queryValidators(ctx context.Context, queries *int64) ([]stakingtypes.Validator, error) {
+	var validators []stakingtypes.Validator
+	var nextKey []byte
+
+	for {
+		atomic.AddInt64(queries, 1)
+		resp, err := vm.stakingQuery.Validators(ctx, &stakingtypes.QueryValidatorsRequest{
+			Status:     stakingtypes.BondStatusBonded,
+			Pagination: &query.PageRequest{Key: nextKey, Limit: ValidatorQueryPageLimit},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		validators = append(validators, resp.Validators...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			return validators, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
+// querySigningInfos queries the signing infos of all validators page by page
+func (vm *ValidatorMonitor) querySigningInfos(ctx context.Context, queries *int64) ([]slashingtypes.ValidatorSigningInfo, error) {
+	if vm.slashingQuery == nil {
+		return nil, errors.New("slashing query client not configured")
+	}
+
+	var infos []slashingtypes.ValidatorSigningInfo
+	var nextKey []byte
+
+	for {
+		atomic.AddInt64(queries, 1)
+		resp, err := vm.slashingQuery.SigningInfos(ctx, &slashingtypes.QuerySigningInfosRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: ValidatorQueryPageLimit},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, resp.Info...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			return infos, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
+// queryUnbondingSummaries queries the unbonding delegations of the given
+// validators with at most ValidatorQueryWorkers requests in flight
+func (vm *ValidatorMonitor) queryUnbondingSummaries(ctx context.Context, operators []string, queries *int64) map[string]unbondingSummary {
+	summaries := make(map[string]unbondingSummary, len(operators))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	work := make(chan string)
+	for i := 0; i < ValidatorQueryWorkers && i < len(operators); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for operator := range work {
+				summary, err := vm.queryUnbondingSummary(ctx, operator, queries)
+				if err != nil {
+					log.Printf("Failed to query unbonding delegations for %s: %v", operator, err)
+					continue
+				}
+				mu.Lock()
+				summaries[operator] = summary
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, operator := range operators {
+		select {
+		case work <- operator:
+		case <-ctx.Done():
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return summaries
+}
+
+// queryUnbondingSummary queries and aggregates one validator's unbonding delegations
+func (vm *ValidatorMonitor) queryUnbondingSummary(ctx context.Context, operator string, queries *int64) (unbondingSummary, error) {
+	entries := 0
+	tokens := new(big.Int)
+	var nextKey []byte
+
+	for {
+		atomic.AddInt64(queries, 1)
+		resp, err := vm.stakingQuery.ValidatorUnbondingDelegations(ctx, &stakingtypes.QueryValidatorUnbondingDelegationsRequest{
+			ValidatorAddr: operator,
+			Pagination:    &query.PageRequest{Key: nextKey, Limit: ValidatorQueryPageLimit},
+		})
+		if err != nil {
+			return unbondingSummary{}, err
+		}
+
+		for _, ubd := range resp.UnbondingResponses {
+			for _, entry := range ubd.Entries {
+				entries++
+				tokens.Add(tokens, entry.Balance.BigInt())
+			}
+		}
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			return unbondingSummary{Entries: entries, Tokens: tokens.String()}, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
+// consensusAddresses returns consensus address -> operator address for the
+// given validators, deriving and caching addresses that are not cached yet
+func (vm *ValidatorMonitor) consensusAddresses(validators []stakingtypes.Validator) map[string]string {
+	result := make(map[string]string, len(validators))
+	for _, validator := range validators {
+		consAddr, ok := vm.consCache.get(validator.OperatorAddress)
+		if !ok {
+			var err error
+			consAddr, err = deriveConsensusAddress(validator)
+			if err != nil {
+				log.Printf("Cannot derive consensus address for %s: %v", validator.OperatorAddress, err)
+				continue
+			}
+			vm.consCache.set(validator.OperatorAddress, consAddr)
+		}
+		result[consAddr] = validator.OperatorAddress
+	}
+
+	if err := vm.consCache.save(); err != nil {
+		log.Printf("Failed to save consensus address cache: %v", err)
+	}
+	return result
+}
+
+// deriveConsensusAddress returns the bech32 consensus address of a validator,
+// using the operator address prefix to pick the valcons prefix
+func deriveConsensusAddress(validator stakingtypes.Validator) (string, error) {
+	consAddr, err := validator.GetConsAddr()
+	if err != nil {
+		return "", err
+	}
+
+	hrp, _, err := bech32.DecodeAndConvert(validator.OperatorAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid operator address: %w", err)
+	}
+	return bech32.ConvertAndEncode(strings.TrimSuffix(hrp, "valoper")+"valcons", consAddr)
+}
+
+// consensusAddressCache maps operator addresses to consensus addresses. The
+// mapping does not change for a validator, so it is kept on disk across restarts.
+type consensusAddressCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]string
+	dirty   bool
+}
+
+// loadConsensusAddressCache reads the cache from dataDir; a missing or broken
+// file starts an empty cache. An empty dataDir keeps the cache in memory only.
+func loadConsensusAddressCache(dataDir string) *consensusAddressCache {
+	cache := &consensusAddressCache{entries: make(map[string]string)}
+	if dataDir == "" {
+		return cache
+	}
+
+	cache.path = filepath.Join(dataDir, ConsensusAddressCacheFile)
+	data, err := os.ReadFile(cache.path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		log.Printf("Ignoring unreadable consensus address cache %s: %v", cache.path, err)
+		cache.entries = make(map[string]string)
+	}
+	return cache
+}
+
+func (c *consensusAddressCache) get(operator string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	consAddr, ok := c.entries[operator]
+	return consAddr, ok
+}
+
+func (c *consensusAddressCache) set(operator, consAddr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[operator] = consAddr
+	c.dirty = true
+}
+
+// save writes the cache if it changed since the last save
+func (c *consensusAddressCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty || c.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+
+	c.dirty = false
+	return nil
+}
@@ -0,0 +1,645 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// ValidatorScoresFile stores the monthly validator scores in the data directory
+	ValidatorScoresFile = "validator_scores.json"
+	// MaxScoreHistoryMonths is the number of monthly score tables kept
+	MaxScoreHistoryMonths = 24
+	// ScoreSlashWindowMonths is how many months of jailings weigh on the slash history
+	ScoreSlashWindowMonths = 12
+	// DefaultCommissionSwing is the commission rate movement within a month
+	// that takes commission stability to zero (10 percentage points)
+	DefaultCommissionSwing = 0.10
+	// GovernanceCheckInterval is how often the votes on proposals in their
+	// voting period are sampled
+	GovernanceCheckInterval = 15 * time.Minute
+	// MaxReportedScores is the number of ranked validators in the monthly report
+	MaxReportedScores = 5
+)
+
+// ScoreWeights weigh the components of the validator score. They are
+// normalized by their sum, so only their ratios matter.
+type ScoreWeights struct {
+	Uptime              float64 `yaml:"uptime" json:"uptime"`
+	BotCompliance       float64 `yaml:"bot_compliance" json:"bot_compliance"`
+	CommissionStability float64 `yaml:"commission_stability" json:"commission_stability"`
+	Governance          float64 `yaml:"governance" json:"governance"`
+	SlashHistory        float64 `yaml:"slash_history" json:"slash_history"`
+}
+
+// DefaultScoreWeights favour uptime and bot compliance, what the halving
+// rewards depend on
+func DefaultScoreWeights() ScoreWeights {
+	return ScoreWeights{
+		Uptime:              0.35,
+		BotCompliance:       0.25,
+		CommissionStability: 0.15,
+		Governance:          0.15,
+		SlashHistory:        0.10,
+	}
+}
+
+// sum returns the total weight
+func (w ScoreWeights) sum() float64 {
+	return w.Uptime + w.BotCompliance + w.CommissionStability + w.Governance + w.SlashHistory
+}
+
+// ScoreConfig configures the monthly validator score
+type ScoreConfig struct {
+	Weights         ScoreWeights `yaml:"weights"`          // default 0.35/0.25/0.15/0.15/0.10
+	CommissionSwing float64      `yaml:"commission_swing"` // default 0.10
+}
+
+// weights returns the configured weights, the defaults when none are set
+func (c ScoreConfig) weights() ScoreWeights {
+	if c.Weights == (ScoreWeights{}) {
+		return DefaultScoreWeights()
+	}
+	return c.Weights
+}
+
+// commissionSwing returns the configured swing, the default when unset
+func (c ScoreConfig) commissionSwing() float64 {
+	if c.CommissionSwing <= 0 {
+		return DefaultCommissionSwing
+	}
+	return c.CommissionSwing
+}
+
+// ValidateScores checks the validator score settings
+func ValidateScores(cfg ScoreConfig) error {
+	w := cfg.Weights
+	for name, weight := range map[string]float64{
+		"uptime":               w.Uptime,
+		"bot_compliance":       w.BotCompliance,
+		"commission_stability": w.CommissionStability,
+		"governance":           w.Governance,
+		"slash_history":        w.SlashHistory,
+	} {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return fmt.Errorf("scores weight %s must be a non-negative number", name)
+		}
+	}
+	if cfg.CommissionSwing < 0 || cfg.CommissionSwing > 1 {
+		return fmt.Errorf("scores commission_swing must be between 0 and 1")
+	}
+	return nil
+}
+
+// ScoreInputs are a validator's measurements of a month
+type ScoreInputs struct {
+	OperatorAddress string `json:"operator_address"`
+	Moniker         string `json:"moniker"`
+	// Monthly uptime tracked by the monitor, in percent
+	UptimePercent float64 `json:"uptime_percent"`
+	// Validator checks of the month, and those that saw a live bot heartbeat
+	BotChecks        uint64 `json:"bot_checks"`
+	BotChecksRunning uint64 `json:"bot_checks_running"`
+	// Sum of the commission rate changes seen in the month
+	CommissionMovement float64 `json:"commission_movement"`
+	// Proposals whose voting ended in the month, and those the validator voted on
+	Proposals int `json:"proposals"`
+	Votes     int `json:"votes"`
+	// Jailings seen in the month
+	Jails      int  `json:"jails"`
+	Tombstoned bool `json:"tombstoned"`
+}
+
+// ScoreComponents are the normalized parts of a score, each from 0 to 1
+type ScoreComponents struct {
+	Uptime              float64 `json:"uptime"`
+	BotCompliance       float64 `json:"bot_compliance"`
+	CommissionStability float64 `json:"commission_stability"`
+	Governance          float64 `json:"governance"`
+	SlashHistory        float64 `json:"slash_history"`
+}
+
+// ValidatorScore is a validator's score and rank of a month
+type ValidatorScore struct {
+	OperatorAddress string  `json:"operator_address"`
+	Moniker         string  `json:"moniker"`
+	Score           float64 `json:"score"`
+	Rank            int     `json:"rank"`
+	// PreviousRank is the rank of the previous scored month, 0 if unranked
+	PreviousRank int             `json:"previous_rank,omitempty"`
+	Components   ScoreComponents `json:"components"`
+	Inputs       ScoreInputs     `json:"inputs"`
+}
+
+// MonthlyScores is the score table of an uptime month, best first
+type MonthlyScores struct {
+	Month      uint64           `json:"month"`
+	ComputedAt time.Time        `json:"computed_at"`
+	Weights    ScoreWeights     `json:"weights"`
+	Scores     []ValidatorScore `json:"scores"`
+}
+
+// ScorePoint is a validator's score of one month
+type ScorePoint struct {
+	Month uint64  `json:"month"`
+	Score float64 `json:"score"`
+	Rank  int     `json:"rank"`
+}
+
+// ComputeValidatorScores scores and ranks the validators of a month. history
+// holds the earlier months, oldest first; it provides the jailings of the
+// slash window and the previous ranks.
+func ComputeValidatorScores(month uint64, inputs []ScoreInputs, history []MonthlyScores, cfg ScoreConfig) []ValidatorScore {
+	weights := cfg.weights()
+	total := weights.sum()
+	swing := cfg.commissionSwing()
+
+	pastJails := make(map[string]int)
+	previousRanks := make(map[string]int)
+	for _, past := range history {
+		if past.Month >= month {
+			continue
+		}
+		if past.Month+ScoreSlashWindowMonths > month {
+			for _, score := range past.Scores {
+				pastJails[score.OperatorAddress] += score.Inputs.Jails
+			}
+		}
+		previousRanks = make(map[string]int, len(past.Scores))
+		for _, score := range past.Scores {
+			previousRanks[score.OperatorAddress] = score.Rank
+		}
+	}
+
+	scores := make([]ValidatorScore, 0, len(inputs))
+	for _, in := range inputs {
+		components := ScoreComponents{
+			Uptime:              clampUnit(in.UptimePercent / 100),
+			CommissionStability: clampUnit(1 - in.CommissionMovement/swing),
+			Governance:          1,
+			SlashHistory:        1 / float64(1+in.Jails+pastJails[in.OperatorAddress]),
+		}
+		if in.BotChecks > 0 {
+			components.BotCompliance = clampUnit(float64(in.BotChecksRunning) / float64(in.BotChecks))
+		}
+		if in.Proposals > 0 {
+			components.Governance = clampUnit(float64(in.Votes) / float64(in.Proposals))
+		}
+		if in.Tombstoned {
+			components.SlashHistory = 0
+		}
+
+		score := 0.0
+		if total > 0 {
+			score = 100 * (weights.Uptime*components.Uptime +
+				weights.BotCompliance*components.BotCompliance +
+				weights.CommissionStability*components.CommissionStability +
+				weights.Governance*components.Governance +
+				weights.SlashHistory*components.SlashHistory) / total
+		}
+
+		scores = append(scores, ValidatorScore{
+			OperatorAddress: in.OperatorAddress,
+			Moniker:         in.Moniker,
+			Score:           math.Round(score*100) / 100,
+			PreviousRank:    previousRanks[in.OperatorAddress],
+			Components:      components,
+			Inputs:          in,
+		})
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].OperatorAddress < scores[j].OperatorAddress
+	})
+	for i := range scores {
+		scores[i].Rank = i + 1
+	}
+	return scores
+}
+
+// clampUnit limits v to [0, 1]
+func clampUnit(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// RankMovement returns an arrow for the rank change since the previous month
+func RankMovement(score ValidatorScore) string {
+	switch {
+	case score.PreviousRank == 0:
+		return "new"
+	case score.PreviousRank > score.Rank:
+		return fmt.Sprintf("▲%d", score.PreviousRank-score.Rank)
+	case score.PreviousRank < score.Rank:
+		return fmt.Sprintf("▼%d", score.Rank-score.PreviousRank)
+	default:
+		return "="
+	}
+}
+
+// ScoreFormula documents how scores are computed with the given weights
+func ScoreFormula(weights ScoreWeights, commissionSwing float64) string {
+	return fmt.Sprintf("score = 100 × (%.2f·uptime + %.2f·bot compliance + %.2f·commission stability + %.2f·governance + %.2f·slash history) / %.2f. "+
+		"Uptime is the monthly uptime tracked by the monitor; bot compliance the share of validator checks that saw a live bot heartbeat; "+
+		"commission stability 1 − the commission rate changes of the month / %.0f%% (at least 0); "+
+		"governance the share of proposals whose voting ended in the month that the validator's account voted on (1 without proposals); "+
+		"slash history 1 / (1 + jailings seen in the last %d months), 0 once tombstoned. "+
+		"Validators in the bonded set at one check of the month at least are scored.",
+		weights.Uptime, weights.BotCompliance, weights.CommissionStability, weights.Governance, weights.SlashHistory, weights.sum(),
+		commissionSwing*100, ScoreSlashWindowMonths)
+}
+
+// TrackedProposal holds the tracked validators that voted on a proposal
+type TrackedProposal struct {
+	ID        uint64          `json:"id"`
+	VotingEnd time.Time       `json:"voting_end"`
+	Voters    map[string]bool `json:"voters"`
+}
+
+// ScoreHistory persists the monthly score tables and the votes on the
+// proposals of the months not scored yet
+type ScoreHistory struct {
+	mu   sync.Mutex
+	path string
+
+	months    []MonthlyScores
+	proposals map[uint64]*TrackedProposal
+}
+
+// scoreHistoryFile is the stored form of the score history
+type scoreHistoryFile struct {
+	Months    []MonthlyScores    `json:"months"`
+	Proposals []*TrackedProposal `json:"proposals"`
+}
+
+// LoadScoreHistory reads the score history of a data directory. Without a
+// data directory the history is kept in memory only.
+func LoadScoreHistory(dataDir string) *ScoreHistory {
+	h := &ScoreHistory{proposals: make(map[uint64]*TrackedProposal)}
+	if dataDir == "" {
+		return h
+	}
+
+	h.path = filepath.Join(dataDir, ValidatorScoresFile)
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		return h
+	}
+
+	var stored scoreHistoryFile
+	if err := json.Unmarshal(data, &stored); err != nil {
+		log.Printf("Ignoring unreadable validator scores %s: %v", h.path, err)
+		return h
+	}
+	h.months = stored.Months
+	for _, proposal := range stored.Proposals {
+		h.proposals[proposal.ID] = proposal
+	}
+	return h
+}
+
+// save writes the history atomically
+func (h *ScoreHistory) save() error {
+	if h.path == "" {
+		return nil
+	}
+
+	stored := scoreHistoryFile{Months: h.months, Proposals: make([]*TrackedProposal, 0, len(h.proposals))}
+	for _, proposal := range h.proposals {
+		stored.Proposals = append(stored.Proposals, proposal)
+	}
+	sort.Slice(stored.Proposals, func(i, j int) bool { return stored.Proposals[i].ID < stored.Proposals[j].ID })
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode validator scores: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	tmpPath := h.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write validator scores: %w", err)
+	}
+	return os.Rename(tmpPath, h.path)
+}
+
+// RecordVotes adds the tracked validator accounts that voted on a proposal
+func (h *ScoreHistory) RecordVotes(id uint64, votingEnd time.Time, voters []string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	proposal, ok := h.proposals[id]
+	if !ok {
+		proposal = &TrackedProposal{ID: id, Voters: make(map[string]bool)}
+		h.proposals[id] = proposal
+	}
+	proposal.VotingEnd = votingEnd.UTC()
+	for _, voter := range voters {
+		proposal.Voters[voter] = true
+	}
+	return h.save()
+}
+
+// Participation returns the number of tracked proposals whose voting ended in
+// month and how many of them each account voted on
+func (h *ScoreHistory) Participation(month uint64) (int, map[string]int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	proposals := 0
+	votes := make(map[string]int)
+	for _, proposal := range h.proposals {
+		if uptimeMonth(proposal.VotingEnd) != month {
+			continue
+		}
+		proposals++
+		for voter := range proposal.Voters {
+			votes[voter]++
+		}
+	}
+	return proposals, votes
+}
+
+// Record adds the score table of a month, replacing an earlier one of the
+// same month, and drops the proposals that ended before it
+func (h *ScoreHistory) Record(scores MonthlyScores) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	months := h.months[:0]
+	for _, past := range h.months {
+		if past.Month != scores.Month {
+			months = append(months, past)
+		}
+	}
+	months = append(months, scores)
+	sort.Slice(months, func(i, j int) bool { return months[i].Month < months[j].Month })
+	if len(months) > MaxScoreHistoryMonths {
+		months = months[len(months)-MaxScoreHistoryMonths:]
+	}
+	h.months = months
+
+	for id, proposal := range h.proposals {
+		if uptimeMonth(proposal.VotingEnd) <= scores.Month {
+			delete(h.proposals, id)
+		}
+	}
+	return h.save()
+}
+
+// Months returns the score tables, oldest first
+func (h *ScoreHistory) Months() []MonthlyScores {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	months := make([]MonthlyScores, len(h.months))
+	copy(months, h.months)
+	return months
+}
+
+// Month returns the score table of a month; month 0 selects the latest
+func (h *ScoreHistory) Month(month uint64) (MonthlyScores, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := len(h.months) - 1; i >= 0; i-- {
+		if month == 0 || h.months[i].Month == month {
+			return h.months[i], true
+		}
+	}
+	return MonthlyScores{}, false
+}
+
+// Trend returns a validator's score in every month it was scored, oldest first
+func (h *ScoreHistory) Trend(operatorAddress string) []ScorePoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	trend := make([]ScorePoint, 0, len(h.months))
+	for _, month := range h.months {
+		for _, score := range month.Scores {
+			if score.OperatorAddress == operatorAddress {
+				trend = append(trend, ScorePoint{Month: month.Month, Score: score.Score, Rank: score.Rank})
+				break
+			}
+		}
+	}
+	return trend
+}
+
+// reportedScores returns the top of a score table, followed by the scores of
+// the given validators ranked below it
+func reportedScores(scores MonthlyScores, limit int, include ...string) []ValidatorScore {
+	var reported []ValidatorScore
+	for i, score := range scores.Scores {
+		if i < limit {
+			reported = append(reported, score)
+			continue
+		}
+		for _, addr := range include {
+			if score.OperatorAddress == addr {
+				reported = append(reported, score)
+			}
+		}
+	}
+	return reported
+}
+
+// ReportScores is the validator score table included in a monthly report
+type ReportScores struct {
+	Month      uint64           `json:"month"`
+	Validators []ValidatorScore `json:"validators"`
+	Formula    string           `json:"formula"`
+}
+
+// newReportScores returns the report section of a score table: the top
+// validators and ours
+func newReportScores(scores MonthlyScores, cfg ScoreConfig, validatorAddress string) *ReportScores {
+	return &ReportScores{
+		Month:      scores.Month,
+		Validators: reportedScores(scores, MaxReportedScores, validatorAddress),
+		Formula:    ScoreFormula(scores.Weights, cfg.commissionSwing()),
+	}
+}
+
+// commissionChange returns the absolute difference of two commission rates
+func commissionChange(previous, current string) float64 {
+	from, err := strconv.ParseFloat(previous, 64)
+	if err != nil {
+		return 0
+	}
+	to, err := strconv.ParseFloat(current, 64)
+	if err != nil {
+		return 0
+	}
+	return math.Abs(to - from)
+}
+
+// trackSlashing counts the jailings of the tracked validators: a jailing
+// moves the jailed-until time of a signing info past the one seen before.
+// The first signing info seen of a validator only sets the baseline.
+func (vm *ValidatorMonitor) trackSlashing(slashing map[string]slashingSummary) {
+	for operator, summary := range slashing {
+		status, ok := vm.validators[operator]
+		if !ok {
+			continue
+		}
+		if !status.JailedUntil.IsZero() && summary.JailedUntil.After(status.JailedUntil) {
+			status.Jails++
+			log.Printf("Validator %s jailed until %s", operator, summary.JailedUntil.Format(time.RFC3339))
+		}
+		status.JailedUntil = summary.JailedUntil
+		status.Tombstoned = summary.Tombstoned
+	}
+}
+
+// closeMonthScores scores the validators that were in the bonded set during
+// month and records the score table. Called with vm.mu held, before the
+// monthly counters are reset.
+func (vm *ValidatorMonitor) closeMonthScores(month uint64) {
+	if vm.scores == nil || vm.monthChecks == 0 {
+		return
+	}
+
+	proposals, votes := vm.scores.Participation(month)
+	var inputs []ScoreInputs
+	for _, status := range vm.validators {
+		if status.BotChecks == 0 {
+			continue
+		}
+		account, _ := validatorAccountAddress(status.OperatorAddress)
+		inputs = append(inputs, ScoreInputs{
+			OperatorAddress:    status.OperatorAddress,
+			Moniker:            status.Moniker,
+			UptimePercent:      status.UptimePercent,
+			BotChecks:          status.BotChecks,
+			BotChecksRunning:   status.BotChecksRunning,
+			CommissionMovement: status.CommissionMovement,
+			Proposals:          proposals,
+			Votes:              votes[account],
+			Jails:              status.Jails,
+			Tombstoned:         status.Tombstoned,
+		})
+	}
+
+	cfg := vm.config.Scores
+	scores := MonthlyScores{
+		Month:      month,
+		ComputedAt: time.Now().UTC(),
+		Weights:    cfg.weights(),
+		Scores:     ComputeValidatorScores(month, inputs, vm.scores.Months(), cfg),
+	}
+	if err := vm.scores.Record(scores); err != nil {
+		log.Printf("Failed to save validator scores: %v", err)
+	}
+	log.Printf("Validator scores of month %d computed for %d validators", month, len(scores.Scores))
+}
+
+// scoreReportLines returns the ranking lines of the monthly Telegram report
+func (vm *ValidatorMonitor) scoreReportLines(month uint64) string {
+	if vm.scores == nil {
+		return ""
+	}
+	scores, ok := vm.scores.Month(month)
+	if !ok || scores.Month != month || len(scores.Scores) == 0 {
+		return ""
+	}
+
+	lines := "\n\n🏅 Validator Scores"
+	for _, score := range reportedScores(scores, MaxReportedScores, vm.config.ValidatorAddress) {
+		lines += fmt.Sprintf("\n%d. %s %.2f (%s)", score.Rank, validatorLabel(score.OperatorAddress, score.Moniker), score.Score, RankMovement(score))
+	}
+	return lines + "\n\n" + ScoreFormula(scores.Weights, vm.config.Scores.commissionSwing())
+}
+
+// governanceRoutine samples the votes on proposals in their voting period
+func (vm *ValidatorMonitor) governanceRoutine(ctx context.Context) {
+	ticker := NewJitteredTicker(GovernanceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := vm.checkGovernanceVotes(ctx); err != nil {
+				log.Printf("Failed to check governance votes: %v", err)
+			}
+		}
+	}
+}
+
+// checkGovernanceVotes records which tracked validators voted on the
+// proposals in their voting period
+func (vm *ValidatorMonitor) checkGovernanceVotes(ctx context.Context) error {
+	if vm.chain == nil || vm.scores == nil {
+		return nil
+	}
+
+	proposals, err := vm.chain.QueryVotingProposals(ctx)
+	if err != nil {
+		return err
+	}
+
+	vm.mu.RLock()
+	accounts := make(map[string]bool, len(vm.validators))
+	for operator := range vm.validators {
+		if account, err := validatorAccountAddress(operator); err == nil {
+			accounts[account] = true
+		}
+	}
+	vm.mu.RUnlock()
+
+	for _, proposal := range proposals {
+		voters, err := vm.chain.QueryProposalVoters(ctx, proposal.ID)
+		if err != nil {
+			return fmt.Errorf("proposal %d: %w", proposal.ID, err)
+		}
+
+		var tracked []string
+		for _, voter := range voters {
+			if accounts[voter] {
+				tracked = append(tracked, voter)
+			}
+		}
+		if err := vm.scores.RecordVotes(proposal.ID, proposal.VotingEnd, tracked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetScores returns the recorded validator score history
+func (vm *ValidatorMonitor) GetScores() *ScoreHistory {
+	return vm.scores
+}
+
+// reportScoresFor returns the scores of the last uptime month closed before
+// the end of a report month (YYYY-MM), nil if none were recorded
+func reportScoresFor(config *BotConfig, month string) *ReportScores {
+	start, err := ParseReportMonth(month)
+	if err != nil {
+		return nil
+	}
+	end := start.AddDate(0, 1, 0)
+
+	months := LoadScoreHistory(config.DataDir).Months()
+	for i := len(months) - 1; i >= 0; i-- {
+		if months[i].ComputedAt.Before(end) {
+			return newReportScores(months[i], config.Scores, config.ValidatorAddress)
+		}
+	}
+	return nil
+}
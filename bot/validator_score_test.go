@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// syntheticValidators are scored in the month 100 tests
+var syntheticValidators = []ScoreInputs{
+	// Perfect month
+	{OperatorAddress: "gxrvaloper1steady", Moniker: "steady", UptimePercent: 100, BotChecks: 100, BotChecksRunning: 100, Proposals: 2, Votes: 2},
+	// Fully up, but its bot was down half the time
+	{OperatorAddress: "gxrvaloper1flaky", Moniker: "flaky", UptimePercent: 100, BotChecks: 100, BotChecksRunning: 50, Proposals: 2, Votes: 2},
+	// Jailed once and out of the set for a while
+	{OperatorAddress: "gxrvaloper1jailed", Moniker: "jailed", UptimePercent: 90, BotChecks: 90, BotChecksRunning: 90, Proposals: 2, Votes: 2, Jails: 1},
+	// Hiked its commission by 5 points and skipped governance
+	{OperatorAddress: "gxrvaloper1hiker", Moniker: "hiker", UptimePercent: 100, BotChecks: 100, BotChecksRunning: 100, CommissionMovement: 0.05, Proposals: 2},
+	// Tombstoned for double signing
+	{OperatorAddress: "gxrvaloper1tomb", Moniker: "tomb", UptimePercent: 100, BotChecks: 100, BotChecksRunning: 100, Proposals: 2, Votes: 2, Tombstoned: true},
+}
+
+// ranking returns the monikers of scores in rank order
+func ranking(scores []ValidatorScore) []string {
+	monikers := make([]string, len(scores))
+	for i, score := range scores {
+		if score.Rank != i+1 {
+			panic(fmt.Sprintf("score %d has rank %d", i, score.Rank))
+		}
+		monikers[i] = score.Moniker
+	}
+	return monikers
+}
+
+func TestComputeValidatorScores(t *testing.T) {
+	scores := ComputeValidatorScores(100, syntheticValidators, nil, ScoreConfig{})
+
+	want := map[string]float64{
+		"steady": 100,
+		"flaky":  87.5, // 100 - 25×0.5
+		"jailed": 91.5, // 100 - 35×0.1 - 10×0.5
+		"hiker":  77.5, // 100 - 15×0.5 - 15
+		"tomb":   90,   // 100 - 10
+	}
+	for _, score := range scores {
+		if score.Score != want[score.Moniker] {
+			t.Errorf("%s scored %.2f, want %.2f (components %+v)", score.Moniker, score.Score, want[score.Moniker], score.Components)
+		}
+		if score.PreviousRank != 0 || RankMovement(score) != "new" {
+			t.Errorf("%s has a previous rank without history", score.Moniker)
+		}
+	}
+	if got := strings.Join(ranking(scores), ","); got != "steady,jailed,tomb,flaky,hiker" {
+		t.Fatalf("ranking = %s", got)
+	}
+}
+
+func TestScoreWeightsChangeRanking(t *testing.T) {
+	// Uptime and bot compliance only: the commission hiker ties with the
+	// perfect validators and the flaky bot drops to last
+	uptimeFirst := ScoreConfig{Weights: ScoreWeights{Uptime: 0.5, BotCompliance: 0.5}}
+	scores := ComputeValidatorScores(100, syntheticValidators, nil, uptimeFirst)
+	if got := strings.Join(ranking(scores), ","); got != "hiker,steady,tomb,jailed,flaky" {
+		t.Fatalf("uptime weighted ranking = %s", got)
+	}
+
+	// Governance and commission only: the commission hiker that skipped the
+	// votes drops to last, the flaky bot does not matter
+	delegatorFirst := ScoreConfig{Weights: ScoreWeights{CommissionStability: 1, Governance: 1}}
+	scores = ComputeValidatorScores(100, syntheticValidators, nil, delegatorFirst)
+	if got := strings.Join(ranking(scores), ","); got != "flaky,jailed,steady,tomb,hiker" {
+		t.Fatalf("governance weighted ranking = %s", got)
+	}
+	if scores[len(scores)-1].Score != 25 {
+		t.Fatalf("hiker scored %.2f, want 25", scores[len(scores)-1].Score)
+	}
+
+	// Only ratios matter
+	doubled := ScoreConfig{Weights: ScoreWeights{CommissionStability: 2, Governance: 2}}
+	for i, score := range ComputeValidatorScores(100, syntheticValidators, nil, doubled) {
+		if score.Score != scores[i].Score {
+			t.Fatalf("doubling the weights changed %s from %.2f to %.2f", score.Moniker, scores[i].Score, score.Score)
+		}
+	}
+
+	// A wider commission swing forgives the hike
+	swing := ScoreConfig{Weights: ScoreWeights{CommissionStability: 1}, CommissionSwing: 0.5}
+	for _, score := range ComputeValidatorScores(100, syntheticValidators, nil, swing) {
+		if score.Moniker == "hiker" && score.Score != 90 {
+			t.Fatalf("hiker scored %.2f with a 50%% swing, want 90", score.Score)
+		}
+	}
+}
+
+func TestScoreHistoryRankMovement(t *testing.T) {
+	dataDir := t.TempDir()
+	history := LoadScoreHistory(dataDir)
+
+	first := MonthlyScores{Month: 100, Weights: DefaultScoreWeights(), Scores: ComputeValidatorScores(100, syntheticValidators, nil, ScoreConfig{})}
+	if err := history.Record(first); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next month the flaky bot is fixed and the jailed validator is back up
+	next := make([]ScoreInputs, len(syntheticValidators))
+	for i, in := range syntheticValidators {
+		in.UptimePercent, in.BotChecksRunning, in.Jails = 100, in.BotChecks, 0
+		next[i] = in
+	}
+
+	// Reloaded from disk
+	history = LoadScoreHistory(dataDir)
+	scores := ComputeValidatorScores(101, next, history.Months(), ScoreConfig{})
+	if got := strings.Join(ranking(scores), ","); got != "flaky,steady,jailed,tomb,hiker" {
+		t.Fatalf("ranking = %s", got)
+	}
+
+	movements := make(map[string]string)
+	for _, score := range scores {
+		movements[score.Moniker] = RankMovement(score)
+	}
+	want := map[string]string{"flaky": "▲3", "steady": "▼1", "jailed": "▼1", "tomb": "▼1", "hiker": "="}
+	for moniker, movement := range want {
+		if movements[moniker] != movement {
+			t.Errorf("%s moved %s, want %s", moniker, movements[moniker], movement)
+		}
+	}
+
+	// The jailing of month 100 still weighs on month 101, not after the slash window
+	for _, score := range scores {
+		if score.Moniker == "jailed" && score.Components.SlashHistory != 0.5 {
+			t.Fatalf("jailed slash history = %.2f in the next month", score.Components.SlashHistory)
+		}
+	}
+	later := ComputeValidatorScores(100+ScoreSlashWindowMonths, next, history.Months(), ScoreConfig{})
+	for _, score := range later {
+		if score.Moniker == "jailed" && score.Components.SlashHistory != 1 {
+			t.Fatalf("jailed slash history = %.2f after the slash window", score.Components.SlashHistory)
+		}
+	}
+
+	if err := history.Record(MonthlyScores{Month: 101, Scores: scores}); err != nil {
+		t.Fatal(err)
+	}
+	trend := LoadScoreHistory(dataDir).Trend("gxrvaloper1flaky")
+	if len(trend) != 2 || trend[0] != (ScorePoint{Month: 100, Score: 87.5, Rank: 4}) || trend[1] != (ScorePoint{Month: 101, Score: 100, Rank: 1}) {
+		t.Fatalf("flaky trend = %+v", trend)
+	}
+
+	// Only the last MaxScoreHistoryMonths months are kept
+	for month := uint64(102); month < 102+MaxScoreHistoryMonths; month++ {
+		if err := history.Record(MonthlyScores{Month: month}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	months := LoadScoreHistory(dataDir).Months()
+	if len(months) != MaxScoreHistoryMonths || months[0].Month != 102 {
+		t.Fatalf("%d months kept, oldest %d", len(months), months[0].Month)
+	}
+}
+
+// fakeGovernance serves one proposal in its voting period and its votes
+type fakeGovernance struct {
+	proposalID uint64
+	votingEnd  time.Time
+	voters     []string
+}
+
+func (f *fakeGovernance) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/cosmos/gov/v1/proposals":
+		fmt.Fprintf(w, `{"proposals":[{"id":"%d","voting_end_time":%q}],"pagination":{"next_key":null}}`, f.proposalID, f.votingEnd.Format(time.RFC3339))
+	case fmt.Sprintf("/cosmos/gov/v1/proposals/%d/votes", f.proposalID):
+		votes := make([]map[string]string, len(f.voters))
+		for i, voter := range f.voters {
+			votes[i] = map[string]string{"voter": voter}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"votes": votes, "pagination": map[string]interface{}{"next_key": nil}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestMonthCloseScoresTrackedValidators(t *testing.T) {
+	operator := func(name string) string {
+		addr, err := bech32.ConvertAndEncode("gxrvaloper", []byte(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr
+	}
+	voter, absent, left := operator("voter-validator"), operator("absent-validator"), operator("left-validator")
+	voterAccount, err := validatorAccountAddress(voter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	month := uptimeMonth(time.Now())
+	gov := &fakeGovernance{proposalID: 7, votingEnd: monthStartAt(time.Now()).Add(time.Hour), voters: []string{voterAccount, "gxr1someoneelse"}}
+	server := httptest.NewServer(gov)
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	vm := NewValidatorMonitor(&BotConfig{ChainAPI: server.URL, DataDir: dataDir}, client.Context{}, nil)
+	vm.telegramAlert = nil
+	vm.currentMonth = month
+	vm.monthChecks = 10
+	vm.validators[voter] = &ValidatorStatus{OperatorAddress: voter, Moniker: "voter", BotChecks: 10, BotChecksRunning: 10, UptimePercent: 100, Commission: "0.05"}
+	vm.validators[absent] = &ValidatorStatus{OperatorAddress: absent, Moniker: "absent", BotChecks: 10, BotChecksRunning: 10, UptimePercent: 100}
+	// Not in the bonded set this month: not scored
+	vm.validators[left] = &ValidatorStatus{OperatorAddress: left, Moniker: "left"}
+
+	// A jailing moves the jailed-until time past the one seen before
+	baseline := time.Unix(0, 0).UTC()
+	vm.trackSlashing(map[string]slashingSummary{absent: {JailedUntil: baseline}})
+	vm.trackSlashing(map[string]slashingSummary{absent: {JailedUntil: baseline}})
+	vm.trackSlashing(map[string]slashingSummary{absent: {JailedUntil: time.Now().Add(10 * time.Minute)}})
+	if jails := vm.validators[absent].Jails; jails != 1 {
+		t.Fatalf("%d jailings counted, want 1", jails)
+	}
+
+	if err := vm.checkGovernanceVotes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	vm.performMonthlyReset(context.Background(), month+1)
+
+	scores, ok := LoadScoreHistory(dataDir).Month(month)
+	if !ok || len(scores.Scores) != 2 {
+		t.Fatalf("scores of month %d = %+v", month, scores)
+	}
+	top := scores.Scores[0]
+	if top.OperatorAddress != voter || top.Score != 100 || top.Inputs.Proposals != 1 || top.Inputs.Votes != 1 {
+		t.Fatalf("top score = %+v", top)
+	}
+	bottom := scores.Scores[1]
+	if bottom.Components.Governance != 0 || bottom.Components.SlashHistory != 0.5 {
+		t.Fatalf("absent validator components = %+v", bottom.Components)
+	}
+	if status := vm.validators[voter]; status.BotChecks != 0 || status.UptimePercent != 0 || vm.monthChecks != 0 {
+		t.Fatalf("score counters not reset: %+v", status)
+	}
+
+	// Served on /scores, latest month by default
+	s := newTestStatusServer(StatusServerConfig{})
+	s.bs.validatorMonitor = vm
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scores", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/scores returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Month     uint64            `json:"month"`
+		Formula   string            `json:"formula"`
+		Scores    []ValidatorScore  `json:"scores"`
+		Movements map[string]string `json:"movements"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Month != month || len(body.Scores) != 2 || body.Movements[voter] != "new" || !strings.Contains(body.Formula, "0.35·uptime") {
+		t.Fatalf("/scores = %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scores?validator="+voter, nil))
+	if !strings.Contains(rec.Body.String(), fmt.Sprintf(`"month":%d`, month)) {
+		t.Fatalf("/scores trend = %s", rec.Body.String())
+	}
+	if code := statusRequest(t, s.Handler(), http.MethodGet, "/scores?month=1", ""); code != http.StatusNotFound {
+		t.Fatalf("/scores of an unscored month returned %d", code)
+	}
+}
+
+func TestValidateScores(t *testing.T) {
+	for _, cfg := range []ScoreConfig{
+		{Weights: ScoreWeights{Uptime: -1}},
+		{CommissionSwing: -0.1},
+		{CommissionSwing: 2},
+	} {
+		if err := ValidateScores(cfg); err == nil {
+			t.Fatalf("config %+v accepted", cfg)
+		}
+	}
+	if err := ValidateScores(ScoreConfig{Weights: ScoreWeights{Governance: 3, Uptime: 1}, CommissionSwing: 0.2}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// WeeklyDigestInterval is how often the weekly digest is sent
+const WeeklyDigestInterval = 7 * 24 * time.Hour
+
+// sendWeeklyDigests sends the weekly digest every WeeklyDigestInterval until
+// ctx is done
+func (bs *BotService) sendWeeklyDigests(ctx context.Context) {
+	ticker := NewJitteredTicker(WeeklyDigestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			bs.sendWeeklyDigest(now)
+		}
+	}
+}
+
+// sendWeeklyDigest queues the weekly digest and starts the next week of the
+// sections it reported
+func (bs *BotService) sendWeeklyDigest(now time.Time) {
+	digest := bs.weeklyDigest(now)
+	if digest == nil {
+		return
+	}
+	if err := bs.telegramAlert.QueueAlert(digest); err != nil {
+		log.Printf("Failed to send weekly digest: %v", err)
+		return
+	}
+	if bs.shadow != nil {
+		bs.shadow.Reset(now)
+	}
+}
+
+// weeklyDigest builds the digest of the past week, nil when no section has
+// anything to report
+func (bs *BotService) weeklyDigest(now time.Time) *Alert {
+	var sections []string
+	if bs.shadow != nil {
+		sections = append(sections, bs.shadow.Report().DigestSection())
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+
+	return &Alert{
+		ID:        fmt.Sprintf("weekly-digest-%d", now.UnixNano()),
+		Type:      AlertTypeInfo,
+		Priority:  AlertPriorityLow,
+		Title:     "Weekly Digest",
+		Message:   strings.Join(sections, "\n\n"),
+		Timestamp: now,
+		Metadata: map[string]interface{}{
+			"sections": len(sections),
+		},
+	}
+}
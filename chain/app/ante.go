@@ -0,0 +1,185 @@
+package app
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/auth/ante"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	denylisttypes "github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+)
+
+// BondDenomKeeper returns the staking bond denom
+type BondDenomKeeper interface {
+	BondDenom(ctx sdk.Context) string
+}
+
+// DenylistKeeper looks up the addresses bank sends are rejected to
+type DenylistKeeper interface {
+	GetDeniedAddress(ctx sdk.Context, address string) (denylisttypes.DeniedAddress, bool)
+}
+
+// HandlerOptions are the SDK ante options plus what the GXR decorators need
+type HandlerOptions struct {
+	ante.HandlerOptions
+
+	StakingKeeper  BondDenomKeeper
+	DenylistKeeper DenylistKeeper
+}
+
+// NewAnteHandler runs the GXR checks before the SDK ante handler, so
+// malformed fees and staking messages fail early with a clear error
+func NewAnteHandler(options HandlerOptions) (sdk.AnteHandler, error) {
+	if options.StakingKeeper == nil {
+		return nil, errorsmod.Wrap(sdkerrors.ErrLogic, "staking keeper is required for ante builder")
+	}
+	if options.DenylistKeeper == nil {
+		return nil, errorsmod.Wrap(sdkerrors.ErrLogic, "denylist keeper is required for ante builder")
+	}
+
+	sdkHandler, err := ante.NewAnteHandler(options.HandlerOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	gxrHandler := sdk.ChainAnteDecorators(
+		NewFeeDenomDecorator(),
+		NewBondDenomDecorator(options.StakingKeeper),
+		NewDenylistDecorator(options.DenylistKeeper),
+	)
+
+	return func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, error) {
+		newCtx, err := gxrHandler(ctx, tx, simulate)
+		if err != nil {
+			return newCtx, err
+		}
+		return sdkHandler(newCtx, tx, simulate)
+	}, nil
+}
+
+// FeeDenomDecorator rejects fees listing the same denom more than once,
+// which the fee deduction would otherwise report as an unrelated error
+type FeeDenomDecorator struct{}
+
+// NewFeeDenomDecorator creates a FeeDenomDecorator
+func NewFeeDenomDecorator() FeeDenomDecorator {
+	return FeeDenomDecorator{}
+}
+
+// AnteHandle implements sdk.AnteDecorator
+func (FeeDenomDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	feeTx, ok := tx.(sdk.FeeTx)
+	if !ok {
+		return ctx, errorsmod.Wrap(sdkerrors.ErrTxDecode, "transaction must be a FeeTx")
+	}
+
+	seen := make(map[string]bool)
+	for _, coin := range feeTx.GetFee() {
+		if seen[coin.Denom] {
+			return ctx, errorsmod.Wrapf(sdkerrors.ErrInvalidCoins,
+				"duplicate fee denom %s, list each denom once in %s", coin.Denom, feeTx.GetFee())
+		}
+		seen[coin.Denom] = true
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+// BondDenomDecorator rejects staking messages whose amount is not in the
+// bond denom, instead of letting the staking module fail downstream
+type BondDenomDecorator struct {
+	stakingKeeper BondDenomKeeper
+}
+
+// NewBondDenomDecorator creates a BondDenomDecorator
+func NewBondDenomDecorator(stakingKeeper BondDenomKeeper) BondDenomDecorator {
+	return BondDenomDecorator{stakingKeeper: stakingKeeper}
+}
+
+// AnteHandle implements sdk.AnteDecorator
+func (d BondDenomDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	bondDenom := d.stakingKeeper.BondDenom(ctx)
+
+	for _, msg := range tx.GetMsgs() {
+		var amount sdk.Coin
+		switch msg := msg.(type) {
+		case *stakingtypes.MsgCreateValidator:
+			amount = msg.Value
+		case *stakingtypes.MsgDelegate:
+			amount = msg.Amount
+		case *stakingtypes.MsgUndelegate:
+			amount = msg.Amount
+		case *stakingtypes.MsgBeginRedelegate:
+			amount = msg.Amount
+		default:
+			continue
+		}
+
+		if amount.Denom != bondDenom {
+			return ctx, errorsmod.Wrapf(sdkerrors.ErrInvalidCoins,
+				"%s must be denominated in the bond denom %s, got %q", sdk.MsgTypeURL(msg), bondDenom, amount.Denom)
+		}
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+// DenylistDecorator rejects bank sends to denylisted addresses, such as
+// retired allocation addresses and known scam addresses, including sends
+// executed through an authz grant
+type DenylistDecorator struct {
+	denylistKeeper DenylistKeeper
+}
+
+// NewDenylistDecorator creates a DenylistDecorator
+func NewDenylistDecorator(denylistKeeper DenylistKeeper) DenylistDecorator {
+	return DenylistDecorator{denylistKeeper: denylistKeeper}
+}
+
+// AnteHandle implements sdk.AnteDecorator
+func (d DenylistDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	if err := d.checkMsgs(ctx, tx.GetMsgs()); err != nil {
+		return ctx, err
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+// checkMsgs checks the recipients of the bank sends in msgs and in the
+// messages of authz executions among them
+func (d DenylistDecorator) checkMsgs(ctx sdk.Context, msgs []sdk.Msg) error {
+	for _, msg := range msgs {
+		var recipients []string
+		switch msg := msg.(type) {
+		case *banktypes.MsgSend:
+			recipients = []string{msg.ToAddress}
+		case *banktypes.MsgMultiSend:
+			for _, output := range msg.Outputs {
+				recipients = append(recipients, output.Address)
+			}
+		case *authz.MsgExec:
+			inner, err := msg.GetMessages()
+			if err != nil {
+				return err
+			}
+			if err := d.checkMsgs(ctx, inner); err != nil {
+				return err
+			}
+			continue
+		default:
+			continue
+		}
+
+		for _, recipient := range recipients {
+			if denied, found := d.denylistKeeper.GetDeniedAddress(ctx, recipient); found {
+				return errorsmod.Wrapf(denylisttypes.ErrDeniedRecipient,
+					"%s to %s is rejected: %s", sdk.MsgTypeURL(msg), recipient, denied.Reason)
+			}
+		}
+	}
+
+	return nil
+}
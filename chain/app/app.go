@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	reflectionv1 "cosmossdk.io/api/cosmos/reflection/v1"
 	"github.com/cosmos/cosmos-sdk/baseapp"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -45,8 +46,12 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/evidence"
 	evidencekeeper "github.com/cosmos/cosmos-sdk/x/evidence/keeper"
 	evidencetypes "github.com/cosmos/cosmos-sdk/x/evidence/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	feegrantkeeper "github.com/cosmos/cosmos-sdk/x/feegrant/keeper"
+	feegrantmodule "github.com/cosmos/cosmos-sdk/x/feegrant/module"
 	"github.com/cosmos/cosmos-sdk/x/genutil"
 	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
 	paramsclient "github.com/cosmos/cosmos-sdk/x/params/client"
 	paramskeeper "github.com/cosmos/cosmos-sdk/x/params/keeper"
@@ -63,12 +68,19 @@ import (
 	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
 
 	// Custom GXR modules
+	gxrdocs "github.com/Crocodile-ark/gxrchaind/docs"
 	"github.com/Crocodile-ark/gxrchaind/x/halving"
 	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
 	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
 	"github.com/Crocodile-ark/gxrchaind/x/feerouter"
 	feerouterkeeper "github.com/Crocodile-ark/gxrchaind/x/feerouter/keeper"
 	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	"github.com/Crocodile-ark/gxrchaind/x/denylist"
+	denylistkeeper "github.com/Crocodile-ark/gxrchaind/x/denylist/keeper"
+	denylisttypes "github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop"
+	airdropkeeper "github.com/Crocodile-ark/gxrchaind/x/airdrop/keeper"
+	airdroptypes "github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
 
 	abci "github.com/cometbft/cometbft/abci/types"
 	"github.com/cometbft/cometbft/libs/log"
@@ -82,15 +94,13 @@ const (
 	Name                 = "gxrchaind"
 )
 
-// Module account permissions
-var maccPerms = map[string][]string{
+// Module account permissions. GXR accounts come from gxrModuleAccountPerms.
+var maccPerms = withGXRModuleAccounts(map[string][]string{
 	authtypes.FeeCollectorName:     nil,
 	distrtypes.ModuleName:          nil,
 	stakingtypes.BondedPoolName:    {authtypes.Burner, authtypes.Staking},
 	stakingtypes.NotBondedPoolName: {authtypes.Burner, authtypes.Staking},
-	halvingtypes.ModuleName:        {authtypes.Minter, authtypes.Burner},
-	feeroutertypes.ModuleName:      nil,
-}
+})
 
 var (
 	// DefaultNodeHome default home directories for the application daemon
@@ -108,9 +118,12 @@ var (
 		upgrade.AppModuleBasic{},
 		evidence.AppModuleBasic{},
 		authzmodule.AppModuleBasic{},
+		feegrantmodule.AppModuleBasic{},
 		vesting.AppModuleBasic{},
 		halving.AppModuleBasic{},
 		feerouter.AppModuleBasic{},
+		denylist.AppModuleBasic{},
+		airdrop.AppModuleBasic{},
 	)
 
 	// Verify app interface at compile time
@@ -153,11 +166,14 @@ type GXRApp struct {
 	UpgradeKeeper    upgradekeeper.Keeper
 	ParamsKeeper     paramskeeper.Keeper
 	AuthzKeeper      authzkeeper.Keeper
+	FeeGrantKeeper   feegrantkeeper.Keeper
 	EvidenceKeeper   evidencekeeper.Keeper
 	
 	// Custom GXR keepers
 	HalvingKeeper    halvingkeeper.Keeper
 	FeeRouterKeeper  feerouterkeeper.Keeper
+	DenylistKeeper   denylistkeeper.Keeper
+	AirdropKeeper    airdropkeeper.Keeper
 
 	// the module manager
 	mm *module.Manager
@@ -195,10 +211,11 @@ func New(
 		authtypes.StoreKey, banktypes.StoreKey, stakingtypes.StoreKey,
 		distrtypes.StoreKey, slashingtypes.StoreKey,
 		paramstypes.StoreKey, upgradetypes.StoreKey, evidencetypes.StoreKey,
-		authzkeeper.StoreKey,
-		halvingtypes.StoreKey, feeroutertypes.StoreKey,
+		authzkeeper.StoreKey, feegrant.StoreKey,
+		halvingtypes.StoreKey, feeroutertypes.StoreKey, denylisttypes.StoreKey,
+		airdroptypes.StoreKey,
 	)
-	tkeys := sdk.NewTransientStoreKeys(paramstypes.TStoreKey)
+	tkeys := sdk.NewTransientStoreKeys(paramstypes.TStoreKey, feeroutertypes.TStoreKey)
 	memKeys := sdk.NewMemoryStoreKeys()
 
 	app := &GXRApp{
@@ -249,6 +266,9 @@ func New(
 
 	app.AuthzKeeper = authzkeeper.NewKeeper(keys[authzkeeper.StoreKey], appCodec, app.BaseApp.MsgServiceRouter(), app.AccountKeeper)
 
+	// Lets the validator operator pay the fees of a restricted bot key
+	app.FeeGrantKeeper = feegrantkeeper.NewKeeper(appCodec, keys[feegrant.StoreKey], app.AccountKeeper)
+
 	evidenceKeeper := evidencekeeper.NewKeeper(
 		appCodec, keys[evidencetypes.StoreKey], &app.StakingKeeper, app.SlashingKeeper,
 	)
@@ -262,18 +282,55 @@ func New(
 		app.AccountKeeper,
 		app.BankKeeper,
 		&app.StakingKeeper,
+		// Like the feerouter, the distribution shares are changed by governance
+		authtypes.NewModuleAddress(govtypes.ModuleName).String(),
 	)
 
 	app.FeeRouterKeeper = feerouterkeeper.NewKeeper(
 		appCodec,
 		keys[feeroutertypes.StoreKey],
+		tkeys[feeroutertypes.TStoreKey],
 		app.GetSubspace(feeroutertypes.ModuleName),
 		app.AccountKeeper,
 		app.BankKeeper,
 		&app.StakingKeeper,
-		app.DistrKeeper,
+		// No gov module is wired yet, so LP pools and params can only change
+		// through governance once it is added
+		authtypes.NewModuleAddress(govtypes.ModuleName).String(),
+	)
+
+	app.DenylistKeeper = denylistkeeper.NewKeeper(
+		appCodec,
+		keys[denylisttypes.StoreKey],
+		// Like the feerouter, the denylist is updated by governance
+		authtypes.NewModuleAddress(govtypes.ModuleName).String(),
+	)
+
+	app.AirdropKeeper = airdropkeeper.NewKeeper(
+		appCodec,
+		keys[airdroptypes.StoreKey],
+		app.BankKeeper,
+		// Claim rounds are opened by governance, no key holds the allocation
+		authtypes.NewModuleAddress(govtypes.ModuleName).String(),
 	)
 
+	// The halving DEX share can be routed to the feerouter LP pools by param
+	app.HalvingKeeper.SetLPPoolDistributor(app.FeeRouterKeeper)
+	// DEX reserve withdrawals may only go to the registered LP pools
+	app.HalvingKeeper.SetLPPoolRegistry(app.FeeRouterKeeper)
+	// After the DEX window the DEX share may be redirected to the community pool
+	app.HalvingKeeper.SetCommunityPoolFunder(app.DistrKeeper)
+	// Reward estimates deduct the community tax from the delegator share
+	app.HalvingKeeper.SetCommunityTaxSource(app.DistrKeeper)
+	// Validators tombstoned for double signing receive no halving rewards
+	app.HalvingKeeper.SetSlashingKeeper(app.SlashingKeeper)
+
+	// Fail at startup rather than on the first payout if a keeper uses an
+	// unregistered module account
+	if err := ValidateModuleAccounts(app.AccountKeeper.GetModulePermissions(), keeperModuleAccounts()); err != nil {
+		panic(err)
+	}
+
 	/****  Module Options ****/
 
 	// NOTE: we may consider parsing `appOpts` inside module constructors. For the moment
@@ -297,11 +354,14 @@ func New(
 		params.NewAppModule(app.ParamsKeeper),
 		evidence.NewAppModule(app.EvidenceKeeper),
 		authzmodule.NewAppModule(appCodec, app.AuthzKeeper, app.AccountKeeper, app.BankKeeper, app.interfaceRegistry),
+		feegrantmodule.NewAppModule(appCodec, app.AccountKeeper, app.BankKeeper, app.FeeGrantKeeper, app.interfaceRegistry),
 		upgrade.NewAppModule(app.UpgradeKeeper),
 		
 		// Custom GXR modules
 		halving.NewAppModule(appCodec, app.HalvingKeeper, app.AccountKeeper, app.BankKeeper),
 		feerouter.NewAppModule(appCodec, app.FeeRouterKeeper, app.AccountKeeper, app.BankKeeper),
+		denylist.NewAppModule(appCodec, app.DenylistKeeper),
+		airdrop.NewAppModule(appCodec, app.AirdropKeeper),
 	)
 
 	// During begin block slashing happens after distr.BeginBlocker so that
@@ -316,7 +376,10 @@ func New(
 		evidencetypes.ModuleName, 
 		stakingtypes.ModuleName,
 		authzkeeper.ModuleName,
+		feegrant.ModuleName,
 		feeroutertypes.ModuleName,
+		denylisttypes.ModuleName,
+		airdroptypes.ModuleName,
 	)
 
 	app.mm.SetOrderEndBlockers(
@@ -324,6 +387,9 @@ func New(
 		feeroutertypes.ModuleName,
 		halvingtypes.ModuleName,
 		authzkeeper.ModuleName,
+		feegrant.ModuleName,
+		denylisttypes.ModuleName,
+		airdroptypes.ModuleName,
 	)
 
 	// NOTE: The genutils module must occur after staking so that pools are
@@ -339,7 +405,10 @@ func New(
 		upgradetypes.ModuleName, 
 		evidencetypes.ModuleName,
 		authzkeeper.ModuleName,
+		feegrant.ModuleName,
 		vestingtypes.ModuleName,
+		denylisttypes.ModuleName,
+		airdroptypes.ModuleName,
 		genutiltypes.ModuleName,
 		halvingtypes.ModuleName,
 		feeroutertypes.ModuleName,
@@ -348,8 +417,18 @@ func New(
 	// app.mm.RegisterInvariants(&app.CrisisKeeper) // Crisis module not used in GXR
 	app.configurator = module.NewConfigurator(app.appCodec, app.BaseApp.MsgServiceRouter(), app.BaseApp.GRPCQueryRouter())
 	app.mm.RegisterServices(app.configurator)
+	app.registerUpgradeHandlers()
+
+	// Serve the proto files of all registered services, including the GXR
+	// modules', to clients discovering the node through cosmos.reflection.v1
+	reflectionSvc, err := runtimeservices.NewReflectionService()
+	if err != nil {
+		panic(err)
+	}
+	reflectionv1.RegisterReflectionServiceServer(app.GRPCQueryRouter(), reflectionSvc)
 
-	// initialize stores
+	// initialize stores. Every module keeps its state in these KV stores, so
+	// state-sync snapshots need no extensions.
 	app.MountKVStores(keys)
 	app.MountTransientStores(tkeys)
 	app.MountMemoryStores(memKeys)
@@ -358,13 +437,17 @@ func New(
 	app.SetInitChainer(app.InitChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
 
-	anteHandler, err := ante.NewAnteHandler(
-		ante.HandlerOptions{
-			AccountKeeper:   app.AccountKeeper,
-			BankKeeper:      app.BankKeeper,
-			SignModeHandler: encodingConfig.TxConfig.SignModeHandler(),
-			FeegrantKeeper:  nil,
-			SigGasConsumer:  ante.DefaultSigVerificationGasConsumer,
+	anteHandler, err := NewAnteHandler(
+		HandlerOptions{
+			HandlerOptions: ante.HandlerOptions{
+				AccountKeeper:   app.AccountKeeper,
+				BankKeeper:      app.BankKeeper,
+				SignModeHandler: encodingConfig.TxConfig.SignModeHandler(),
+				FeegrantKeeper:  app.FeeGrantKeeper,
+				SigGasConsumer:  ante.DefaultSigVerificationGasConsumer,
+			},
+			StakingKeeper:  app.StakingKeeper,
+			DenylistKeeper: app.DenylistKeeper,
 		},
 	)
 	if err != nil {
@@ -372,6 +455,17 @@ func New(
 	}
 
 	app.SetAnteHandler(anteHandler)
+
+	postHandler, err := NewPostHandler(
+		PostHandlerOptions{
+			FeeRouterKeeper: app.FeeRouterKeeper,
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	app.SetPostHandler(postHandler)
 	app.SetEndBlocker(app.EndBlocker)
 
 	if loadLatest {
@@ -402,6 +496,10 @@ func (app *GXRApp) InitChainer(ctx sdk.Context, req abci.RequestInitChain) abci.
 	if err := json.Unmarshal(req.AppStateBytes, &genesisState); err != nil {
 		panic(err)
 	}
+	// Refuse to start a chain whose halving and feerouter genesis disagree
+	if err := ValidateRewardRouting(app.appCodec, genesisState); err != nil {
+		panic(err)
+	}
 	app.UpgradeKeeper.SetModuleVersionMap(ctx, app.mm.GetVersionMap())
 	return app.mm.InitGenesis(ctx, app.appCodec, genesisState)
 }
@@ -469,6 +567,13 @@ func (app *GXRApp) RegisterAPIRoutes(apiSvr *api.Server, apiConfig config.APICon
 	// Register legacy and grpc-gateway routes for all modules.
 	ModuleBasics.RegisterRESTRoutes(clientCtx, apiSvr.Router)
 	ModuleBasics.RegisterGRPCGatewayRoutes(clientCtx, apiSvr.GRPCGatewayRouter)
+
+	// Serve the GXR OpenAPI document and the swagger UI when api.swagger is
+	// enabled. The document goes first, the UI claims all of /swagger/.
+	gxrdocs.RegisterOpenAPIRoutes(apiSvr.Router, apiConfig.Swagger)
+	if err := server.RegisterSwaggerAPI(clientCtx, apiSvr.Router, apiConfig.Swagger); err != nil {
+		panic(err)
+	}
 }
 
 // RegisterTxService implements the Application.RegisterTxService method.
@@ -12,6 +12,7 @@ import (
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
+	airdroptypes "github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
 	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
 	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
 )
@@ -47,6 +48,10 @@ type GXRGenesisAllocation struct {
 	VestingType string
 	VestingEnd  int64
 	Description string
+
+	// ModuleName is set for module-owned allocations; the module account itself
+	// is created by the auth keeper on first use, so only a balance is added
+	ModuleName string
 }
 
 // CreateGXRGenesisAllocations creates the genesis allocations according to GXR specification
@@ -58,12 +63,14 @@ func CreateGXRGenesisAllocations(genesisTime time.Time) []GXRGenesisAllocation {
 		return sdk.NewCoin("ugen", sdk.NewInt(gxrAmount*UgenPerGXR))
 	}
 
-	// Airdrop & Farming - distributed via Telegram bot farming (no vesting)
+	// Airdrop & Farming - held by the airdrop module, paid out through merkle
+	// claim rounds opened by governance (no vesting)
 	allocations = append(allocations, GXRGenesisAllocation{
-		Address:     "gxr1airdrop0000000000000000000000000000000000", // Placeholder address
+		Address:     authtypes.NewModuleAddress(airdroptypes.ModuleName).String(),
 		Amount:      toUgen(AirdropFarmingGXR),
 		VestingType: "none",
-		Description: "Airdrop & Farming allocation via Telegram bot",
+		Description: "Airdrop & Farming allocation claimed through the airdrop module",
+		ModuleName:  airdroptypes.ModuleName,
 	})
 
 	// Developer Core - 5 year hard vesting, 10% unlock every 6 months
@@ -76,11 +83,13 @@ func CreateGXRGenesisAllocations(genesisTime time.Time) []GXRGenesisAllocation {
 	})
 
 	// Tim Inti (3 orang) - 3 year soft vesting
-	// Split: 3% / 2% / 2%
+	// Split: 3% / 2% / 2%, the last member takes any rounding remainder
+	timInti1 := int64(TimIntiGXR) * 3 / 7
+	timInti2 := int64(TimIntiGXR) * 2 / 7
 	timIntiAmounts := []int64{
-		int64(float64(TimIntiGXR) * 0.42857), // 3% / 7% = ~42.857%
-		int64(float64(TimIntiGXR) * 0.28571), // 2% / 7% = ~28.571%
-		int64(float64(TimIntiGXR) * 0.28571), // 2% / 7% = ~28.571%
+		timInti1,                                // 3% / 7%
+		timInti2,                                // 2% / 7%
+		int64(TimIntiGXR) - timInti1 - timInti2, // 2% / 7%
 	}
 	timIntiAddresses := []string{
 		"gxr1timinti1000000000000000000000000000000000", // Team member 1 (3%)
@@ -116,10 +125,11 @@ func CreateGXRGenesisAllocations(genesisTime time.Time) []GXRGenesisAllocation {
 
 	// Pool Staking (PoS) - delegator rewards
 	allocations = append(allocations, GXRGenesisAllocation{
-		Address:     "gxr1poolstaking00000000000000000000000000000", // Placeholder address
+		Address:     authtypes.NewModuleAddress(PoolStakingName).String(),
 		Amount:      toUgen(PoolStakingGXR),
 		VestingType: "none",
 		Description: "PoS Pool for delegator rewards",
+		ModuleName:  PoolStakingName,
 	})
 
 	// Halving Fund - managed by halving module
@@ -128,6 +138,7 @@ func CreateGXRGenesisAllocations(genesisTime time.Time) []GXRGenesisAllocation {
 		Amount:      toUgen(HalvingFundGXR),
 		VestingType: "none",
 		Description: "Halving Fund for 5-year cycle rewards",
+		ModuleName:  halvingtypes.ModuleName,
 	})
 
 	// Cadangan/Ekspansi - emergency and development fund
@@ -139,12 +150,18 @@ func CreateGXRGenesisAllocations(genesisTime time.Time) []GXRGenesisAllocation {
 	})
 
 	// Validator Awal (30 validators) - early validator bonus
-	// Split equally among 30 validators: 0.5% year 1, 0.5% year 2 (if active >20 days/month)
-	validatorAmount := ValidatorAwalGXR / 30 // Per validator
+	// Split equally among 30 validators: 0.5% year 1, 0.5% year 2 (if active >20 days/month).
+	// The split is done in ugen and the last validator takes the rounding remainder
+	validatorTotal := sdk.NewInt(int64(ValidatorAwalGXR) * UgenPerGXR)
+	validatorAmount := validatorTotal.QuoRaw(30) // Per validator
 	for i := 0; i < 30; i++ {
+		amount := validatorAmount
+		if i == 29 {
+			amount = validatorTotal.Sub(validatorAmount.MulRaw(29))
+		}
 		allocations = append(allocations, GXRGenesisAllocation{
 			Address:     fmt.Sprintf("gxr1validator%02d000000000000000000000000000", i+1),
-			Amount:      toUgen(validatorAmount),
+			Amount:      sdk.NewCoin("ugen", amount),
 			VestingType: "continuous",
 			VestingEnd:  genesisTime.Add(2 * 365 * 24 * time.Hour).Unix(), // 2 years
 			Description: fmt.Sprintf("Early validator %d bonus allocation", i+1),
@@ -156,9 +173,12 @@ func CreateGXRGenesisAllocations(genesisTime time.Time) []GXRGenesisAllocation {
 
 // SetupGXRGenesis configures the genesis state with GXR allocations
 func SetupGXRGenesis(cdc codec.JSONCodec, genesisState GenesisState, genesisTime time.Time) GenesisState {
-	// Get allocations
-	allocations := CreateGXRGenesisAllocations(genesisTime)
+	return SetupGXRGenesisWithAllocations(cdc, genesisState, genesisTime, CreateGXRGenesisAllocations(genesisTime))
+}
 
+// SetupGXRGenesisWithAllocations configures the genesis state with the given
+// allocations. It panics if they do not add up to the total supply.
+func SetupGXRGenesisWithAllocations(cdc codec.JSONCodec, genesisState GenesisState, genesisTime time.Time, allocations []GXRGenesisAllocation) GenesisState {
 	// Setup Auth genesis state
 	var authGenState authtypes.GenesisState
 	cdc.MustUnmarshalJSON(genesisState[authtypes.ModuleName], &authGenState)
@@ -172,6 +192,7 @@ func SetupGXRGenesis(cdc codec.JSONCodec, genesisState GenesisState, genesisTime
 	bankGenState.Supply = sdk.NewCoins()
 
 	// Add accounts and balances
+	var genAccounts authtypes.GenesisAccounts
 	for _, alloc := range allocations {
 		// Create account
 		addr, err := sdk.AccAddressFromBech32(alloc.Address)
@@ -181,7 +202,10 @@ func SetupGXRGenesis(cdc codec.JSONCodec, genesisState GenesisState, genesisTime
 		}
 
 		var account authtypes.GenesisAccount
-		if alloc.VestingType == "continuous" && alloc.VestingEnd > 0 {
+		if alloc.ModuleName != "" {
+			// Module accounts are created by their module
+			account = nil
+		} else if alloc.VestingType == "continuous" && alloc.VestingEnd > 0 {
 			// Create vesting account
 			baseAccount := authtypes.NewBaseAccount(addr, nil, 0, 0)
 			vestingAccount := authvesting.NewContinuousVestingAccount(
@@ -196,7 +220,9 @@ func SetupGXRGenesis(cdc codec.JSONCodec, genesisState GenesisState, genesisTime
 			account = authtypes.NewBaseAccount(addr, nil, 0, 0)
 		}
 
-		authGenState.Accounts = append(authGenState.Accounts, account)
+		if account != nil {
+			genAccounts = append(genAccounts, account)
+		}
 
 		// Add balance
 		balance := banktypes.Balance{
@@ -215,6 +241,12 @@ func SetupGXRGenesis(cdc codec.JSONCodec, genesisState GenesisState, genesisTime
 		panic(fmt.Sprintf("Total supply mismatch: expected %s, got %s", expectedSupply, bankGenState.Supply))
 	}
 
+	packedAccounts, err := authtypes.PackAccounts(genAccounts)
+	if err != nil {
+		panic(fmt.Sprintf("failed to pack genesis accounts: %s", err))
+	}
+	authGenState.Accounts = append(authGenState.Accounts, packedAccounts...)
+
 	// Setup Staking genesis to use ugen
 	var stakingGenState stakingtypes.GenesisState
 	cdc.MustUnmarshalJSON(genesisState[stakingtypes.ModuleName], &stakingGenState)
@@ -224,6 +256,8 @@ func SetupGXRGenesis(cdc codec.JSONCodec, genesisState GenesisState, genesisTime
 	// Setup Halving genesis
 	var halvingGenState halvingtypes.GenesisState
 	cdc.MustUnmarshalJSON(genesisState[halvingtypes.ModuleName], &halvingGenState)
+	// Cycle 1 is funded with its share of the halving reserve allocated above
+	halvingGenState.HalvingInfo = halvingtypes.DefaultHalvingInfo()
 	halvingGenState.HalvingInfo.CycleStartTime = genesisTime.Unix()
 
 	// Setup FeeRouter genesis
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/crypto"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	authvesting "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/stretchr/testify/require"
+
+	airdroptypes "github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// testAllocations returns the GXR allocations with the placeholder addresses
+// replaced by deterministic valid ones
+func testAllocations(genesisTime time.Time) []GXRGenesisAllocation {
+	allocations := CreateGXRGenesisAllocations(genesisTime)
+	for i := range allocations {
+		if allocations[i].ModuleName != "" {
+			continue
+		}
+		seed := fmt.Sprintf("gxr-genesis-%d", i)
+		allocations[i].Address = sdk.AccAddress(crypto.AddressHash([]byte(seed))).String()
+	}
+	return allocations
+}
+
+func TestGXRGenesisAllocationsSumToTotalSupply(t *testing.T) {
+	total := sdk.ZeroInt()
+	for _, alloc := range CreateGXRGenesisAllocations(time.Unix(0, 0)) {
+		require.Equal(t, "ugen", alloc.Amount.Denom)
+		total = total.Add(alloc.Amount.Amount)
+	}
+	require.Equal(t, TotalSupplyUgen, total)
+}
+
+func TestSetupGXRGenesis(t *testing.T) {
+	encoding := MakeTestEncodingConfig()
+	cdc := encoding.Codec
+	genesisTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	allocations := testAllocations(genesisTime)
+
+	genesis := SetupGXRGenesisWithAllocations(cdc, NewDefaultGenesisState(cdc), genesisTime, allocations)
+
+	// Bank: one balance per allocation, supply equals the fixed total
+	var bankGenesis banktypes.GenesisState
+	cdc.MustUnmarshalJSON(genesis[banktypes.ModuleName], &bankGenesis)
+	require.Equal(t, sdk.NewCoins(sdk.NewCoin("ugen", TotalSupplyUgen)), bankGenesis.Supply)
+	require.Len(t, bankGenesis.Balances, len(allocations))
+
+	// Auth: one account per allocation except module-owned ones
+	var authGenesis authtypes.GenesisState
+	cdc.MustUnmarshalJSON(genesis[authtypes.ModuleName], &authGenesis)
+	accounts, err := authtypes.UnpackAccounts(authGenesis.Accounts)
+	require.NoError(t, err)
+
+	byAddress := make(map[string]authtypes.GenesisAccount, len(accounts))
+	for _, account := range accounts {
+		byAddress[account.GetAddress().String()] = account
+	}
+
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName).String()
+	require.NotContains(t, byAddress, halvingAddr)
+	poolStakingAddr := authtypes.NewModuleAddress(PoolStakingName).String()
+	require.NotContains(t, byAddress, poolStakingAddr)
+	airdropAddr := authtypes.NewModuleAddress(airdroptypes.ModuleName).String()
+	require.NotContains(t, byAddress, airdropAddr)
+	require.Len(t, accounts, len(allocations)-3)
+
+	// Vesting accounts end when their allocation says so
+	vestingEnds := map[int64]int{}
+	for _, alloc := range allocations {
+		if alloc.ModuleName != "" {
+			continue
+		}
+		account, found := byAddress[alloc.Address]
+		require.True(t, found, alloc.Description)
+
+		if alloc.VestingType != "continuous" {
+			require.IsType(t, &authtypes.BaseAccount{}, account, alloc.Description)
+			continue
+		}
+
+		vesting, ok := account.(*authvesting.ContinuousVestingAccount)
+		require.True(t, ok, alloc.Description)
+		require.Equal(t, genesisTime.Unix(), vesting.StartTime, alloc.Description)
+		require.Equal(t, alloc.VestingEnd, vesting.EndTime, alloc.Description)
+		require.Equal(t, sdk.NewCoins(alloc.Amount), vesting.OriginalVesting, alloc.Description)
+		vestingEnds[vesting.EndTime]++
+	}
+
+	year := 365 * 24 * time.Hour
+	require.Equal(t, map[int64]int{
+		genesisTime.Add(5 * year).Unix(): 1,  // Developer Core
+		genesisTime.Add(3 * year).Unix(): 3,  // Tim Inti
+		genesisTime.Add(2 * year).Unix(): 30, // Validator Awal
+	}, vestingEnds)
+
+	// Staking: ugen bond denom with 85 validators
+	var stakingGenesis stakingtypes.GenesisState
+	cdc.MustUnmarshalJSON(genesis[stakingtypes.ModuleName], &stakingGenesis)
+	require.Equal(t, "ugen", stakingGenesis.Params.BondDenom)
+	require.Equal(t, uint32(85), stakingGenesis.Params.MaxValidators)
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	airdroptypes "github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+const (
+	// PoolStakingName is the module account holding the genesis PoS pool for
+	// delegator rewards
+	PoolStakingName = "pool_staking"
+)
+
+// gxrModuleAccountPerms is the single table of GXR module accounts and their
+// permissions. It is merged into maccPerms; add new GXR accounts here.
+var gxrModuleAccountPerms = map[string][]string{
+	halvingtypes.ModuleName:                 {authtypes.Minter, authtypes.Burner},
+	halvingtypes.DexDistributionAccountName: nil,
+	feeroutertypes.ModuleName:               nil,
+	PoolStakingName:                         nil,
+	airdroptypes.ModuleName:                 nil,
+}
+
+// keeperModuleAccounts returns the module accounts each custom keeper moves
+// coins out of, with the permissions it needs
+func keeperModuleAccounts() map[string]map[string][]string {
+	return map[string]map[string][]string{
+		halvingtypes.ModuleName:   halvingtypes.ModuleAccountPermissions,
+		feeroutertypes.ModuleName: feeroutertypes.ModuleAccountPermissions,
+		airdroptypes.ModuleName:   airdroptypes.ModuleAccountPermissions,
+	}
+}
+
+// withGXRModuleAccounts adds the GXR module accounts to the SDK module account
+// permissions. It panics if a GXR account shadows an SDK one.
+func withGXRModuleAccounts(perms map[string][]string) map[string][]string {
+	for name, gxrPerms := range gxrModuleAccountPerms {
+		if _, found := perms[name]; found {
+			panic(fmt.Sprintf("GXR module account %s is already registered", name))
+		}
+		perms[name] = gxrPerms
+	}
+	return perms
+}
+
+// ValidateModuleAccounts checks that every module account referenced by the
+// custom keepers is registered with at least the permissions it needs. A
+// missing account would otherwise only surface as a panic in
+// SendCoinsFromModuleToAccount, MintCoins or BurnCoins.
+func ValidateModuleAccounts(
+	registered map[string]authtypes.PermissionsForAddress,
+	required map[string]map[string][]string,
+) error {
+	keepers := make([]string, 0, len(required))
+	for keeper := range required {
+		keepers = append(keepers, keeper)
+	}
+	sort.Strings(keepers)
+
+	for _, keeper := range keepers {
+		names := make([]string, 0, len(required[keeper]))
+		for name := range required[keeper] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			perms, found := registered[name]
+			if !found {
+				return fmt.Errorf("%s keeper uses module account %s, which is not registered in maccPerms", keeper, name)
+			}
+			for _, perm := range required[keeper][name] {
+				if !perms.HasPermission(perm) {
+					return fmt.Errorf("%s keeper needs %s permission on module account %s", keeper, perm, name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
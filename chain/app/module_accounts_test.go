@@ -0,0 +1,67 @@
+package app
+
+import (
+	"testing"
+
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/stretchr/testify/require"
+
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// registeredPermissions builds the account keeper's permission view of perms
+func registeredPermissions(perms map[string][]string) map[string]authtypes.PermissionsForAddress {
+	registered := make(map[string]authtypes.PermissionsForAddress, len(perms))
+	for name, p := range perms {
+		registered[name] = authtypes.NewPermissionsForAddress(name, p)
+	}
+	return registered
+}
+
+func TestGXRModuleAccountsRegistered(t *testing.T) {
+	perms := GetMaccPerms()
+	for name, gxrPerms := range gxrModuleAccountPerms {
+		require.Contains(t, perms, name)
+		require.Equal(t, gxrPerms, perms[name], name)
+	}
+
+	require.NoError(t, ValidateModuleAccounts(registeredPermissions(perms), keeperModuleAccounts()))
+}
+
+func TestValidateModuleAccounts(t *testing.T) {
+	registered := registeredPermissions(GetMaccPerms())
+
+	testCases := []struct {
+		name     string
+		required map[string]map[string][]string
+		expErr   string
+	}{
+		{
+			"fee collector without permissions",
+			map[string]map[string][]string{feeroutertypes.ModuleName: {authtypes.FeeCollectorName: nil}},
+			"",
+		},
+		{
+			"unregistered module account",
+			map[string]map[string][]string{halvingtypes.ModuleName: {"dex_reserve": nil}},
+			"halving keeper uses module account dex_reserve, which is not registered",
+		},
+		{
+			"missing permission",
+			map[string]map[string][]string{feeroutertypes.ModuleName: {feeroutertypes.ModuleName: {authtypes.Burner}}},
+			"feerouter keeper needs burner permission on module account feerouter",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateModuleAccounts(registered, tc.required)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
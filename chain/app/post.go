@@ -0,0 +1,60 @@
+package app
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// FeeRouterKeeper splits the fees of a transaction by the feerouter shares
+type FeeRouterKeeper interface {
+	IsFarmingTransaction(ctx sdk.Context, tx sdk.Tx) bool
+	AddBlockFees(ctx sdk.Context, fees sdk.Coins, isFarmingTransaction bool)
+}
+
+// PostHandlerOptions are what the GXR post decorators need
+type PostHandlerOptions struct {
+	FeeRouterKeeper FeeRouterKeeper
+}
+
+// NewPostHandler runs the GXR post decorators after the messages of a
+// transaction succeeded
+func NewPostHandler(options PostHandlerOptions) (sdk.PostHandler, error) {
+	if options.FeeRouterKeeper == nil {
+		return nil, errorsmod.Wrap(sdkerrors.ErrLogic, "feerouter keeper is required for post handler builder")
+	}
+
+	return sdk.ChainPostDecorators(
+		NewFeeRouterDecorator(options.FeeRouterKeeper),
+	), nil
+}
+
+// FeeRouterDecorator adds the fees a delivered transaction paid into the fee
+// collector to the block's general or farming fees, which the feerouter
+// EndBlocker routes by the 40/30/30 or 30/25/25/20 split. Adding them costs
+// the transaction a transient store write per fee denom, charged to its gas
+// meter; the sends to every validator and LP pool are made once per block.
+// The fees of failed transactions stay in the fee collector for
+// x/distribution.
+type FeeRouterDecorator struct {
+	feeRouterKeeper FeeRouterKeeper
+}
+
+// NewFeeRouterDecorator creates a FeeRouterDecorator
+func NewFeeRouterDecorator(feeRouterKeeper FeeRouterKeeper) FeeRouterDecorator {
+	return FeeRouterDecorator{feeRouterKeeper: feeRouterKeeper}
+}
+
+// PostHandle implements sdk.PostDecorator
+func (d FeeRouterDecorator) PostHandle(ctx sdk.Context, tx sdk.Tx, simulate, success bool, next sdk.PostHandler) (sdk.Context, error) {
+	// CheckTx runs no messages; the fees are routed when the tx is delivered
+	feeTx, ok := tx.(sdk.FeeTx)
+	if !ok || !success || ctx.IsCheckTx() || feeTx.GetFee().IsZero() {
+		return next(ctx, tx, simulate, success)
+	}
+
+	isFarming := d.feeRouterKeeper.IsFarmingTransaction(ctx, tx)
+	d.feeRouterKeeper.AddBlockFees(ctx, feeTx.GetFee(), isFarming)
+
+	return next(ctx, tx, simulate, success)
+}
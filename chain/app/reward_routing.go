@@ -0,0 +1,82 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// ValidateRewardRouting cross-checks the halving and feerouter genesis states.
+// Each module validates its own genesis, but nothing else catches the two
+// reward-routing modules drifting apart:
+//
+//   - The feerouter LP pools are the chain's only DEX pool address set; the
+//     halving DEX share is paid to the same pools when dex_share_to_lp_pools
+//     is set, so at least one of them must be active. Pools must be listed
+//     once and must not be module accounts such as the fee collector.
+//   - Both modules split amounts held in the fee collector and halving module
+//     account with the same rounding convention, so dust ends up in the same
+//     place whichever module routed it.
+func ValidateRewardRouting(cdc codec.JSONCodec, genesisState GenesisState) error {
+	var halvingGenesis halvingtypes.GenesisState
+	if err := cdc.UnmarshalJSON(genesisState[halvingtypes.ModuleName], &halvingGenesis); err != nil {
+		return fmt.Errorf("failed to unmarshal %s genesis: %w", halvingtypes.ModuleName, err)
+	}
+
+	var feerouterGenesis feeroutertypes.GenesisState
+	if err := cdc.UnmarshalJSON(genesisState[feeroutertypes.ModuleName], &feerouterGenesis); err != nil {
+		return fmt.Errorf("failed to unmarshal %s genesis: %w", feeroutertypes.ModuleName, err)
+	}
+
+	return validateRewardRouting(halvingGenesis, feerouterGenesis, moduleAccountNames())
+}
+
+// validateRewardRouting checks the two genesis states against each other;
+// moduleAccounts maps module account addresses to their names
+func validateRewardRouting(halving halvingtypes.GenesisState, feerouter feeroutertypes.GenesisState, moduleAccounts map[string]string) error {
+	halvingParams, feerouterParams := halving.Params, feerouter.Params
+
+	if halvingParams.RoundingStrategy != feerouterParams.RoundingStrategy {
+		return fmt.Errorf("reward routing mismatch: halving rounds with %q but feerouter rounds with %q",
+			halvingParams.RoundingStrategy, feerouterParams.RoundingStrategy)
+	}
+	if halvingParams.RemainderToPos != feerouterParams.RemainderToPos {
+		return fmt.Errorf("reward routing mismatch: halving remainder_to_pos is %t but feerouter remainder_to_pos is %t",
+			halvingParams.RemainderToPos, feerouterParams.RemainderToPos)
+	}
+
+	seen := make(map[string]string, len(feerouter.LPPools))
+	active := 0
+	for _, pool := range feerouter.LPPools {
+		if name, found := seen[pool.Address]; found {
+			return fmt.Errorf("reward routing mismatch: LP pools %q and %q share the address %s", name, pool.Name, pool.Address)
+		}
+		seen[pool.Address] = pool.Name
+
+		if module, found := moduleAccounts[pool.Address]; found {
+			return fmt.Errorf("reward routing mismatch: LP pool %q is the %s module account", pool.Name, module)
+		}
+		if pool.Active {
+			active++
+		}
+	}
+
+	if halvingParams.DexShareToLPPools && halvingParams.DexShare.IsPositive() && active == 0 {
+		return fmt.Errorf("reward routing mismatch: halving pays its DEX share to the feerouter LP pools (dex_share_to_lp_pools), but no LP pool is active")
+	}
+
+	return nil
+}
+
+// moduleAccountNames maps the address of every registered module account to its name
+func moduleAccountNames() map[string]string {
+	accounts := make(map[string]string, len(maccPerms))
+	for name := range maccPerms {
+		accounts[authtypes.NewModuleAddress(name).String()] = name
+	}
+	return accounts
+}
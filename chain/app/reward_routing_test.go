@@ -0,0 +1,103 @@
+package app
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/internal/rounding"
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+func TestValidateRewardRouting(t *testing.T) {
+	pool := func(name string, active bool) feeroutertypes.LPPool {
+		return feeroutertypes.LPPool{Address: sdk.AccAddress([]byte("lp-pool-" + name)).String(), Name: name, Active: active}
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(h *halvingtypes.GenesisState, f *feeroutertypes.GenesisState)
+		expErr string
+	}{
+		{"defaults", func(h *halvingtypes.GenesisState, f *feeroutertypes.GenesisState) {}, ""},
+		{
+			"DEX share to active LP pools",
+			func(h *halvingtypes.GenesisState, f *feeroutertypes.GenesisState) {
+				h.Params.DexShareToLPPools = true
+				f.LPPools = []feeroutertypes.LPPool{pool("GXR/TON", true), pool("GXR/POLYGON", false)}
+			},
+			"",
+		},
+		{
+			"rounding strategy differs",
+			func(h *halvingtypes.GenesisState, f *feeroutertypes.GenesisState) {
+				f.Params.RoundingStrategy = rounding.Bankers
+			},
+			`halving rounds with "truncate" but feerouter rounds with "bankers"`,
+		},
+		{
+			"remainder destination differs",
+			func(h *halvingtypes.GenesisState, f *feeroutertypes.GenesisState) {
+				h.Params.RemainderToPos = false
+			},
+			"halving remainder_to_pos is false but feerouter remainder_to_pos is true",
+		},
+		{
+			"DEX share to LP pools without an active pool",
+			func(h *halvingtypes.GenesisState, f *feeroutertypes.GenesisState) {
+				h.Params.DexShareToLPPools = true
+				f.LPPools = []feeroutertypes.LPPool{pool("GXR/TON", false)}
+			},
+			"no LP pool is active",
+		},
+		{
+			"duplicate LP pool address",
+			func(h *halvingtypes.GenesisState, f *feeroutertypes.GenesisState) {
+				duplicate := pool("GXR/TON", true)
+				duplicate.Name = "GXR/TON v2"
+				f.LPPools = []feeroutertypes.LPPool{pool("GXR/TON", true), duplicate}
+			},
+			`LP pools "GXR/TON" and "GXR/TON v2" share the address`,
+		},
+		{
+			"LP pool at the fee collector",
+			func(h *halvingtypes.GenesisState, f *feeroutertypes.GenesisState) {
+				f.LPPools = []feeroutertypes.LPPool{{
+					Address: authtypes.NewModuleAddress(authtypes.FeeCollectorName).String(),
+					Name:    "GXR/TON",
+					Active:  true,
+				}}
+			},
+			`LP pool "GXR/TON" is the fee_collector module account`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			halving := *halvingtypes.DefaultGenesisState()
+			feerouter := *feeroutertypes.DefaultGenesisState()
+			tc.modify(&halving, &feerouter)
+
+			err := validateRewardRouting(halving, feerouter, moduleAccountNames())
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestValidateRewardRoutingFromAppGenesis(t *testing.T) {
+	cdc := MakeTestEncodingConfig().Codec
+	genesisState := NewDefaultGenesisState(cdc)
+	require.NoError(t, ValidateRewardRouting(cdc, genesisState))
+
+	feerouter := feeroutertypes.DefaultGenesisState()
+	feerouter.Params.RemainderToPos = false
+	genesisState[feeroutertypes.ModuleName] = cdc.MustMarshalJSON(feerouter)
+	require.ErrorContains(t, ValidateRewardRouting(cdc, genesisState), "remainder_to_pos")
+}
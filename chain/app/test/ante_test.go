@@ -0,0 +1,101 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/stretchr/testify/require"
+
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+)
+
+func TestAnteRejectsStakingInOtherDenoms(t *testing.T) {
+	chain := Setup(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 2, nil)
+	delegator, operator := chain.Accounts[0], chain.Accounts[1]
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 5_000))
+	wrongDenom := sdk.NewInt64Coin("uatom", 1_000_000)
+
+	createValidator, err := stakingtypes.NewMsgCreateValidator(
+		sdk.ValAddress(operator.Address),
+		ed25519.GenPrivKey().PubKey(),
+		wrongDenom,
+		stakingtypes.NewDescription("wrong-denom", "", "", "", ""),
+		stakingtypes.NewCommissionRates(sdk.NewDecWithPrec(1, 1), sdk.NewDecWithPrec(2, 1), sdk.NewDecWithPrec(1, 2)),
+		sdk.OneInt(),
+	)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		sender TestAccount
+		msg    sdk.Msg
+	}{
+		{"create validator", operator, createValidator},
+		{"delegate", delegator, stakingtypes.NewMsgDelegate(delegator.Address, chain.Validator, wrongDenom)},
+		{"undelegate", delegator, stakingtypes.NewMsgUndelegate(delegator.Address, chain.Validator, wrongDenom)},
+		{"redelegate", delegator, stakingtypes.NewMsgBeginRedelegate(
+			delegator.Address, chain.Validator, sdk.ValAddress(operator.Address), wrongDenom,
+		)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain.BeginBlock(DefaultBlockTime)
+			res := chain.SendTx(tc.sender, fee, tc.msg)
+			chain.EndBlock()
+
+			require.NotZero(t, res.Code, "%s in uatom accepted", tc.name)
+			require.Contains(t, res.Log, "must be denominated in the bond denom ugen")
+			require.Contains(t, res.Log, "uatom")
+		})
+	}
+
+	// Rejected in the ante handler, so no fee was charged and nothing moved
+	require.Equal(t, sdk.NewInt(DefaultAccountBalance), chain.Balance(delegator.Address))
+	require.Equal(t, sdk.NewInt(DefaultAccountBalance), chain.Balance(operator.Address))
+}
+
+func TestAnteRejectsDuplicateFeeDenoms(t *testing.T) {
+	chain := Setup(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 1, nil)
+	delegator := chain.Accounts[0]
+	amount := sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_000_000)
+
+	// sdk.NewCoins would merge the entries, so build the fee by hand
+	fee := sdk.Coins{
+		sdk.NewInt64Coin(halvingkeeper.MainDenom, 2_500),
+		sdk.NewInt64Coin(halvingkeeper.MainDenom, 2_500),
+	}
+
+	chain.BeginBlock(DefaultBlockTime)
+	res := chain.SendTx(delegator, fee, stakingtypes.NewMsgDelegate(delegator.Address, chain.Validator, amount))
+	chain.EndBlock()
+
+	require.NotZero(t, res.Code)
+	require.Contains(t, res.Log, "duplicate fee denom ugen")
+	require.Equal(t, sdk.NewInt(DefaultAccountBalance), chain.Balance(delegator.Address))
+}
+
+func TestAnteAcceptsBondDenomDelegation(t *testing.T) {
+	chain := Setup(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 1, nil)
+	delegator := chain.Accounts[0]
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 5_000))
+	amount := sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_000_000)
+
+	chain.BeginBlock(DefaultBlockTime)
+	res := chain.SendTx(delegator, fee, stakingtypes.NewMsgDelegate(delegator.Address, chain.Validator, amount))
+	chain.EndBlock()
+	require.Zero(t, res.Code, res.Log)
+
+	delegation, found := chain.App.StakingKeeper.GetDelegation(chain.Context(), delegator.Address, chain.Validator)
+	require.True(t, found)
+	require.False(t, delegation.Shares.IsZero())
+
+	chain.BeginBlock(DefaultBlockTime)
+	res = chain.SendTx(delegator, fee, stakingtypes.NewMsgUndelegate(delegator.Address, chain.Validator, amount))
+	chain.EndBlock()
+	require.Zero(t, res.Code, res.Log)
+	chain.AssertSupplyInvariant()
+}
@@ -0,0 +1,214 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+	"github.com/Crocodile-ark/gxrchaind/x/halving"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// halvingFund is the cycle fund held by the halving module account in these tests
+const halvingFund = 24_000 * 1e8
+
+// activeHalvingCycle starts genesis in an active distribution period
+func activeHalvingCycle(genesisTime time.Time) GenesisModifier {
+	return func(encoding app.EncodingConfig, genesis app.GenesisState) app.GenesisState {
+		halvingGenesis := halvingtypes.DefaultGenesisState()
+		halvingGenesis.HalvingInfo = halvingtypes.HalvingInfo{
+			CurrentCycle:       1,
+			CycleStartTime:     genesisTime.Unix(),
+			TotalSupply:        sdk.NewInt64Coin(halvingkeeper.MainDenom, 0),
+			HalvingFund:        sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund),
+			DistributionActive: true,
+			DistributionStart:  genesisTime.Unix(),
+			DistributedAmount:  sdk.NewInt64Coin(halvingkeeper.MainDenom, 0),
+		}
+		genesis[halvingtypes.ModuleName] = encoding.Codec.MustMarshalJSON(halvingGenesis)
+		return genesis
+	}
+}
+
+func TestBlockLifecycleWithCustomModules(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	chain := Setup(t, genesisTime, 2, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime))
+
+	sender, recipient := chain.Accounts[0], chain.Accounts[1]
+
+	// Block 1: no distribution mid-month, supply is sampled
+	chain.NextBlock(DefaultBlockTime)
+	require.Equal(t, int64(halvingFund), chain.ModuleBalance(halvingtypes.ModuleName).Int64())
+	require.Len(t, chain.App.HalvingKeeper.GetSupplySnapshots(chain.Context()), 1)
+	chain.AssertSupplyInvariant()
+
+	// Block 2: the fee of a transfer is routed out of the fee collector by
+	// the feerouter post handler but for the 30% PoS share
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 5_000))
+	distrBefore := chain.ModuleBalance(distrtypes.ModuleName)
+	chain.BeginBlock(DefaultBlockTime)
+	res := chain.SendTx(sender, fee, banktypes.NewMsgSend(
+		sender.Address, recipient.Address, sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_000)),
+	))
+	require.Zero(t, res.Code, res.Log)
+	chain.EndBlock()
+
+	require.Equal(t, int64(DefaultAccountBalance+1_000), chain.Balance(recipient.Address).Int64())
+	require.Equal(t, int64(1_500), chain.ModuleBalance(authtypes.FeeCollectorName).Int64())
+	require.Equal(t, distrBefore, chain.ModuleBalance(distrtypes.ModuleName))
+	chain.AssertSupplyInvariant()
+
+	// Block 3: distribution sweeps the PoS share
+	chain.NextBlock(DefaultBlockTime)
+	require.True(t, chain.ModuleBalance(authtypes.FeeCollectorName).IsZero())
+	require.Equal(t, distrBefore.AddRaw(1_500), chain.ModuleBalance(distrtypes.ModuleName))
+	chain.AssertSupplyInvariant()
+
+	// Jump to the first of the next month: the halving distribution runs
+	validatorAcc := sdk.AccAddress(chain.Validator)
+	validatorBefore := chain.Balance(validatorAcc)
+	supplyBefore := chain.App.BankKeeper.GetSupply(chain.Context(), halvingkeeper.MainDenom).Amount
+
+	nextMonth := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	chain.NextBlock(nextMonth.Sub(chain.Time))
+
+	monthly := sdk.NewInt(halvingFund).QuoRaw(24)
+	validatorShare := sdk.NewDecFromInt(monthly).Mul(sdk.MustNewDecFromStr("0.70")).TruncateInt()
+	delegatorShare := sdk.NewDecFromInt(monthly).Mul(sdk.MustNewDecFromStr("0.20")).TruncateInt()
+
+	require.True(t, chain.HasEvent(banktypes.EventTypeCoinBurn, banktypes.AttributeKeyBurner, halvingAddr.String()))
+	require.True(t, chain.HasEvent(banktypes.EventTypeCoinMint, banktypes.AttributeKeyMinter, halvingAddr.String()))
+	require.Equal(t, validatorBefore.Add(validatorShare), chain.Balance(validatorAcc))
+
+	// The delegator share went through the fee collector, the DEX share is
+	// held in the dex_distribution account
+	dexShare := monthly.Sub(validatorShare).Sub(delegatorShare)
+	require.Equal(t, sdk.NewInt(halvingFund).Sub(monthly), chain.ModuleBalance(halvingtypes.ModuleName))
+	require.Equal(t, dexShare, chain.ModuleBalance(halvingtypes.DexDistributionAccountName))
+
+	balance, err := chain.App.HalvingKeeper.DexDistributionBalance(sdk.WrapSDKContext(chain.Context()), &halvingtypes.QueryDexDistributionBalanceRequest{})
+	require.NoError(t, err)
+	require.Equal(t, authtypes.NewModuleAddress(halvingtypes.DexDistributionAccountName).String(), balance.Address)
+	require.Equal(t, dexShare, balance.Balance.Amount)
+	require.Equal(t, balance.Balance, balance.Pending)
+
+	// Burn and re-mint leave supply unchanged
+	require.Equal(t, supplyBefore, chain.App.BankKeeper.GetSupply(chain.Context(), halvingkeeper.MainDenom).Amount)
+
+	info, found := chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+	require.True(t, found)
+	require.Equal(t, monthly, info.DistributedAmount.Amount)
+	require.Equal(t, sdk.NewInt(halvingFund), info.DistributedAmount.Amount.Add(info.HalvingFund.Amount))
+	require.Equal(t, nextMonth.Unix(), info.LastMonthlyDistrib)
+	chain.AssertSupplyInvariant()
+
+	// A later block on the same day does not distribute again
+	chain.NextBlock(DefaultBlockTime)
+	require.Equal(t, validatorBefore.Add(validatorShare), chain.Balance(validatorAcc))
+	require.False(t, chain.HasEvent(banktypes.EventTypeCoinBurn, banktypes.AttributeKeyBurner, halvingAddr.String()))
+	chain.AssertSupplyInvariant()
+}
+
+func TestMonthlyDistributionRunsOncePerTrigger(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime))
+
+	monthsDistributed := func() uint64 {
+		info, found := chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+		require.True(t, found)
+		return info.MonthsDistributed
+	}
+
+	// Hourly blocks across the month boundary: the first distribution runs in
+	// the first block of the month and no later block of the day repeats it
+	firstDistribution := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	for chain.Time.Before(firstDistribution.Add(36 * time.Hour)) {
+		chain.NextBlock(time.Hour)
+		if chain.Time.Before(firstDistribution) {
+			require.Zero(t, monthsDistributed(), "distributed at %s", chain.Time)
+		}
+	}
+	require.Equal(t, uint64(1), monthsDistributed())
+
+	last, found := chain.App.HalvingKeeper.GetLastDistributionTime(chain.Context())
+	require.True(t, found)
+	require.Equal(t, firstDistribution.Unix(), last)
+
+	// The chain is down when the trigger passes: it distributes in its first
+	// block back
+	down := firstDistribution.Add(halvingkeeper.MonthlyDistributionTrigger + 2*24*time.Hour)
+	chain.NextBlock(down.Sub(chain.Time))
+	require.Equal(t, uint64(2), monthsDistributed())
+	chain.AssertSupplyInvariant()
+
+	// The next first of the month alone does not distribute, the trigger
+	// counts from the last distribution
+	chain.NextBlock(time.Date(2025, 3, 31, 18, 0, 0, 0, time.UTC).Sub(chain.Time))
+	for chain.Time.Before(time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)) {
+		chain.NextBlock(6 * time.Hour)
+	}
+	require.Equal(t, uint64(2), monthsDistributed())
+
+	chain.NextBlock(down.Add(halvingkeeper.MonthlyDistributionTrigger).Sub(chain.Time))
+	require.Equal(t, uint64(3), monthsDistributed())
+	chain.AssertSupplyInvariant()
+}
+
+func TestLastDistributionTimeKeptApartFromRecords(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingReserve)),
+	}}, firstHalvingCycle(genesisTime))
+	k := chain.App.HalvingKeeper
+
+	// The first distribution stores its time next to its record
+	firstDistribution := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	chain.NextBlock(firstDistribution.Sub(chain.Time))
+	last, found := k.GetLastDistributionTime(chain.Context())
+	require.True(t, found)
+	require.Equal(t, firstDistribution.Unix(), last)
+
+	// Exports and record queries only see the records
+	exported := halving.ExportGenesis(chain.Context(), k)
+	require.Len(t, exported.DistributionRecords, 1)
+	require.Equal(t, uint64(1), exported.DistributionRecords[0].DistributionMonth)
+
+	res, err := k.DistributionHistory(sdk.WrapSDKContext(chain.Context()), &halvingtypes.QueryDistributionHistoryRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.DistributionRecords, 1)
+	_, found = k.GetDistributionRecordByMonth(chain.Context(), 1, 1)
+	require.True(t, found)
+
+	// A new cycle counts its months from the records, past the stored time
+	info, found := k.GetHalvingInfo(chain.Context())
+	require.True(t, found)
+	cycleEnd := time.Unix(info.CycleStartTime, 0).Add(halvingkeeper.HalvingCycleDuration)
+	chain.NextBlock(cycleEnd.Sub(chain.Time))
+	chain.NextBlock(time.Hour)
+
+	info, found = k.GetHalvingInfo(chain.Context())
+	require.True(t, found)
+	require.Equal(t, uint64(2), info.CurrentCycle)
+	require.NotEmpty(t, halving.ExportGenesis(chain.Context(), k).DistributionRecords)
+	chain.AssertSupplyInvariant()
+}
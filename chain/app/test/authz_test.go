@@ -0,0 +1,77 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	"github.com/stretchr/testify/require"
+
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+func TestBotKeyActsThroughAuthzAndFeeGrant(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(t, genesisTime, 1, nil)
+	funder := chain.Accounts[0]
+	operator := sdk.AccAddress(chain.Validator)
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 5_000))
+	declareURL := sdk.MsgTypeURL(&halvingtypes.MsgDeclareDowntime{})
+
+	// The hot bot key holds no funds; the operator grants it the bot
+	// messages and pays its fees
+	priv := secp256k1.GenPrivKey()
+	botKey := TestAccount{PrivKey: priv, Address: sdk.AccAddress(priv.PubKey().Address())}
+
+	chain.BeginBlock(DefaultBlockTime)
+	res := chain.SendTx(funder, fee, banktypes.NewMsgSend(funder.Address, operator, sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_000_000))))
+	require.Zero(t, res.Code, res.Log)
+	ctx := chain.DeliverContext()
+	chain.App.AccountKeeper.SetAccount(ctx, chain.App.AccountKeeper.NewAccountWithAddress(ctx, botKey.Address))
+	expiration := genesisTime.Add(365 * 24 * time.Hour)
+	require.NoError(t, chain.App.AuthzKeeper.SaveGrant(ctx, botKey.Address, operator, authz.NewGenericAuthorization(declareURL), &expiration))
+	require.NoError(t, chain.App.FeeGrantKeeper.GrantAllowance(ctx, operator, botKey.Address, &feegrant.BasicAllowance{}))
+	chain.EndBlock()
+
+	declare := func(start time.Time) sdk.Msg {
+		exec := authz.NewMsgExec(botKey.Address, []sdk.Msg{
+			halvingtypes.NewMsgDeclareDowntime(chain.Validator, start, start.Add(time.Hour), "kernel upgrade"),
+		})
+		return &exec
+	}
+
+	// A granted declaration from the bot key, with the fee taken from the operator
+	operatorBefore := chain.Balance(operator)
+	start := chain.Time.Add(time.Hour)
+	chain.BeginBlock(DefaultBlockTime)
+	res = chain.SendTxWithFeeGranter(botKey, operator, fee, declare(start))
+	chain.EndBlock()
+	require.Zero(t, res.Code, res.Log)
+	require.True(t, chain.Balance(botKey.Address).IsZero())
+	// As the only bonded validator the operator gets the 40% validator share
+	// of the fee back
+	validatorShare := fee.AmountOf(halvingkeeper.MainDenom).MulRaw(40).QuoRaw(100)
+	require.Equal(t, operatorBefore.Sub(fee.AmountOf(halvingkeeper.MainDenom)).Add(validatorShare), chain.Balance(operator))
+	declarations := chain.App.HalvingKeeper.GetDowntimeDeclarations(chain.Context(), chain.Validator)
+	require.Len(t, declarations, 1)
+	require.Equal(t, start.Unix(), declarations[0].Start)
+
+	// Without the fee allowance the empty bot key cannot pay
+	chain.BeginBlock(DefaultBlockTime)
+	res = chain.SendTxWithFeeGranter(botKey, funder.Address, fee, declare(start.Add(2*time.Hour)))
+	chain.EndBlock()
+	require.Equal(t, feegrant.ErrNoAllowance.ABCICode(), res.Code, res.Log)
+
+	// Once the operator revokes the grant, the bot key is refused
+	chain.BeginBlock(DefaultBlockTime)
+	require.NoError(t, chain.App.AuthzKeeper.DeleteGrant(chain.DeliverContext(), botKey.Address, operator, declareURL))
+	res = chain.SendTxWithFeeGranter(botKey, operator, fee, declare(start.Add(2*time.Hour)))
+	chain.EndBlock()
+	require.Equal(t, authz.ErrNoAuthorizationFound.ABCICode(), res.Code, res.Log)
+	require.Len(t, chain.App.HalvingKeeper.GetDowntimeDeclarations(chain.Context(), chain.Validator), 1)
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/stretchr/testify/require"
+
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+func TestSetBotMetadata(t *testing.T) {
+	chain := Setup(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 1, nil)
+	msgServer := halvingkeeper.NewMsgServerImpl(chain.App.HalvingKeeper)
+
+	// Messages are delivered straight to the Msg service at a given height,
+	// without ValidateBasic
+	chain.BeginBlock(DefaultBlockTime)
+	ctx := chain.DeliverContext()
+	set := func(height int64, msg *halvingtypes.MsgSetBotMetadata) (*halvingtypes.MsgSetBotMetadataResponse, sdk.Events, error) {
+		msgCtx := ctx.WithBlockHeight(height).WithEventManager(sdk.NewEventManager())
+		res, err := msgServer.SetBotMetadata(sdk.WrapSDKContext(msgCtx), msg)
+		return res, msgCtx.EventManager().Events(), err
+	}
+
+	// Oversized or malformed fields are refused
+	for _, msg := range []*halvingtypes.MsgSetBotMetadata{
+		halvingtypes.NewMsgSetBotMetadata(chain.Validator, "", "ops@validator.example", ""),
+		halvingtypes.NewMsgSetBotMetadata(chain.Validator, strings.Repeat("v", halvingtypes.MaxBotVersionLength+1), "", ""),
+		halvingtypes.NewMsgSetBotMetadata(chain.Validator, "v1.4.0", strings.Repeat("c", halvingtypes.MaxBotContactLength+1), ""),
+		halvingtypes.NewMsgSetBotMetadata(chain.Validator, "v1.4.0", "", "https://bot.example/"+strings.Repeat("p", halvingtypes.MaxBotEndpointURLLength)),
+		halvingtypes.NewMsgSetBotMetadata(chain.Validator, "v1.4.0", "", "ftp://bot.example"),
+	} {
+		require.ErrorIs(t, msg.ValidateBasic(), halvingtypes.ErrInvalidBotMetadata)
+		_, _, err := set(chain.Height, msg)
+		require.ErrorIs(t, err, halvingtypes.ErrInvalidBotMetadata)
+	}
+	_, found := chain.App.HalvingKeeper.GetBotMetadata(ctx, chain.Validator)
+	require.False(t, found)
+
+	// Only validators have a directory entry
+	_, _, err := set(chain.Height, halvingtypes.NewMsgSetBotMetadata(sdk.ValAddress(chain.Accounts[0].Address), "v1.4.0", "", ""))
+	require.ErrorIs(t, err, halvingtypes.ErrValidatorNotFound)
+
+	registeredAt := chain.Height
+	res, events, err := set(registeredAt, halvingtypes.NewMsgSetBotMetadata(chain.Validator, "v1.4.0", "ops@validator.example", "https://bot.validator.example/status"))
+	require.NoError(t, err)
+	require.Equal(t, registeredAt+halvingtypes.BotMetadataUpdateIntervalBlocks, res.NextUpdateHeight)
+	require.Len(t, events, 1)
+	require.Equal(t, halvingtypes.EventTypeBotMetadataSet, events[0].Type)
+
+	// An update before the interval has passed is refused and changes nothing
+	_, _, err = set(res.NextUpdateHeight-1, halvingtypes.NewMsgSetBotMetadata(chain.Validator, "v1.5.0", "ops@validator.example", ""))
+	require.ErrorIs(t, err, halvingtypes.ErrBotMetadataRateLimited)
+	metadata, found := chain.App.HalvingKeeper.GetBotMetadata(ctx, chain.Validator)
+	require.True(t, found)
+	require.Equal(t, "v1.4.0", metadata.Version)
+	require.Equal(t, registeredAt, metadata.UpdatedHeight)
+
+	_, _, err = set(res.NextUpdateHeight, halvingtypes.NewMsgSetBotMetadata(chain.Validator, "v1.5.0", "ops@validator.example", ""))
+	require.NoError(t, err)
+
+	other := sdk.ValAddress([]byte("directory-validator-"))
+	chain.App.HalvingKeeper.SetBotMetadata(ctx, other, halvingtypes.BotMetadata{ValidatorAddress: other.String(), Version: "v1.3.2"})
+	chain.EndBlock()
+
+	// The entry is part of the validator's compliance and of the directory
+	compliance, err := chain.App.HalvingKeeper.HeartbeatCompliance(sdk.WrapSDKContext(chain.Context()),
+		&halvingtypes.QueryHeartbeatComplianceRequest{ValidatorAddress: chain.Validator.String()})
+	require.NoError(t, err)
+	require.NotNil(t, compliance.BotMetadata)
+	require.Equal(t, "v1.5.0", compliance.BotMetadata.Version)
+	require.Empty(t, compliance.BotMetadata.EndpointUrl)
+	require.Equal(t, res.NextUpdateHeight, compliance.BotMetadata.UpdatedHeight)
+
+	seen := map[string]string{}
+	var next []byte
+	for page := 0; page < 2; page++ {
+		directory, err := chain.App.HalvingKeeper.BotDirectory(sdk.WrapSDKContext(chain.Context()),
+			&halvingtypes.QueryBotDirectoryRequest{Pagination: &query.PageRequest{Key: next, Limit: 1, CountTotal: page == 0}})
+		require.NoError(t, err)
+		require.Len(t, directory.BotMetadata, 1)
+		if page == 0 {
+			require.Equal(t, uint64(2), directory.Pagination.Total)
+		}
+		seen[directory.BotMetadata[0].ValidatorAddress] = directory.BotMetadata[0].Version
+		next = directory.Pagination.NextKey
+	}
+	require.Nil(t, next)
+	require.Equal(t, map[string]string{chain.Validator.String(): "v1.5.0", other.String(): "v1.3.2"}, seen)
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// importedHalvingCycle is a distribution period started long before genesis
+// whose last paid month is paid, as after an export and import
+func importedHalvingCycle(distributionStart time.Time, paid uint64) GenesisModifier {
+	return func(encoding app.EncodingConfig, genesis app.GenesisState) app.GenesisState {
+		lastDistribution := distributionStart.Add(time.Duration(paid-1) * halvingkeeper.MonthDuration)
+
+		halvingGenesis := halvingtypes.DefaultGenesisState()
+		halvingGenesis.HalvingInfo = halvingtypes.HalvingInfo{
+			CurrentCycle:       1,
+			CycleStartTime:     distributionStart.Unix(),
+			TotalSupply:        sdk.NewInt64Coin(halvingkeeper.MainDenom, 0),
+			HalvingFund:        sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund),
+			DistributionActive: true,
+			DistributionStart:  distributionStart.Unix(),
+			LastMonthlyDistrib: lastDistribution.Unix(),
+			DistributedAmount:  sdk.NewInt64Coin(halvingkeeper.MainDenom, 0),
+			MonthsDistributed:  paid,
+		}
+		genesis[halvingtypes.ModuleName] = encoding.Codec.MustMarshalJSON(halvingGenesis)
+		return genesis
+	}
+}
+
+func TestImportedStateCatchesUpMissedMonths(t *testing.T) {
+	genesisTime := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	// Month 11 of the period is running and months 1-7 were paid: months
+	// 8, 9 and 10 were missed
+	distributionStart := genesisTime.Add(-10*halvingkeeper.MonthDuration - time.Hour)
+	chain := Setup(t, genesisTime, 2, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, importedHalvingCycle(distributionStart, 7))
+
+	// One missed month is paid per block
+	for month := uint64(8); month <= 10; month++ {
+		chain.NextBlock(DefaultBlockTime)
+
+		require.True(t, chain.HasEvent(halvingtypes.EventTypeCatchUpDistribution,
+			halvingtypes.AttributeKeyMonth, fmt.Sprintf("%d", month)), "month %d", month)
+		require.True(t, chain.HasEvent(halvingtypes.EventTypeCatchUpDistribution,
+			halvingtypes.AttributeKeyMonthsBehind, fmt.Sprintf("%d", 11-month)), "month %d", month)
+
+		info, found := chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+		require.True(t, found)
+		require.Equal(t, month, info.MonthsDistributed)
+		require.Equal(t, chain.Time.Unix(), info.LastMonthlyDistrib)
+		chain.AssertSupplyInvariant()
+	}
+
+	// The running month waits for the monthly distribution trigger
+	for i := 0; i < 3; i++ {
+		chain.NextBlock(DefaultBlockTime)
+		require.False(t, chain.HasEvent(banktypes.EventTypeCoinBurn, banktypes.AttributeKeyBurner, halvingAddr.String()))
+	}
+
+	records := chain.App.HalvingKeeper.GetAllDistributionRecords(chain.Context())
+	require.Len(t, records, 3)
+	for i, record := range records {
+		require.True(t, record.CatchUp)
+		require.Equal(t, uint64(8+i), record.DistributionMonth)
+	}
+
+	info, found := chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+	require.True(t, found)
+	require.Equal(t, uint64(10), info.MonthsDistributed)
+	require.Equal(t, sdk.NewInt(halvingFund), info.DistributedAmount.Amount.Add(info.HalvingFund.Amount))
+
+	// Once the trigger passed since the last catch-up the running month is
+	// paid as a regular distribution
+	nextDistribution := time.Unix(info.LastMonthlyDistrib, 0).Add(halvingkeeper.MonthlyDistributionTrigger)
+	chain.NextBlock(nextDistribution.Sub(chain.Time))
+	require.False(t, chain.HasEvent(halvingtypes.EventTypeCatchUpDistribution,
+		halvingtypes.AttributeKeyCycle, "1"))
+	require.True(t, chain.HasEvent(banktypes.EventTypeCoinBurn, banktypes.AttributeKeyBurner, halvingAddr.String()))
+
+	info, found = chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+	require.True(t, found)
+	require.Equal(t, uint64(11), info.MonthsDistributed)
+	chain.AssertSupplyInvariant()
+}
@@ -0,0 +1,329 @@
+// Package test runs the full GXR application in memory. It is the shared
+// harness for application-level tests: wiring, block lifecycle, upgrades and
+// module ordering.
+package test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	clienttx "github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+)
+
+const (
+	// ChainID is the chain id used by the in-memory test chain
+	ChainID = "gxr-test-1"
+	// DefaultBlockTime is the time between two blocks produced by NextBlock
+	DefaultBlockTime = 5 * time.Second
+	// DefaultAccountBalance is the ugen balance of every funded test account
+	DefaultAccountBalance = 1_000_000 * 1e8
+	// DefaultGas is the gas limit of transactions built by SendTx
+	DefaultGas = 200_000
+)
+
+// GenesisModifier adjusts the genesis state before the chain is initialised
+type GenesisModifier func(encoding app.EncodingConfig, genesis app.GenesisState) app.GenesisState
+
+// TestAccount is a funded genesis account with its signing key
+type TestAccount struct {
+	PrivKey cryptotypes.PrivKey
+	Address sdk.AccAddress
+}
+
+// TestChain drives a GXRApp through the ABCI block lifecycle
+type TestChain struct {
+	t testing.TB
+
+	App       *app.GXRApp
+	Encoding  app.EncodingConfig
+	Accounts  []TestAccount
+	Validator sdk.ValAddress
+
+	Height int64
+	Time   time.Time
+
+	// LastEvents holds the events emitted by the last block
+	LastEvents []abci.Event
+
+	// SignBlocks reports every bonded validator as a signer of the previous
+	// block, so x/distribution allocates collected fees to the validators
+	// rather than all to the community pool
+	SignBlocks bool
+}
+
+// Setup starts a single-validator chain at genesisTime with numAccounts funded
+// accounts. Extra module balances and genesis modifiers are applied before
+// InitChain.
+func Setup(t testing.TB, genesisTime time.Time, numAccounts int, moduleBalances []banktypes.Balance, modifiers ...GenesisModifier) *TestChain {
+	t.Helper()
+	return SetupWithBaseAppOptions(t, nil, genesisTime, numAccounts, moduleBalances, modifiers...)
+}
+
+// SetupWithBaseAppOptions is Setup with extra baseapp options, e.g. a
+// snapshot store
+func SetupWithBaseAppOptions(t testing.TB, baseAppOptions []func(*baseapp.BaseApp), genesisTime time.Time, numAccounts int, moduleBalances []banktypes.Balance, modifiers ...GenesisModifier) *TestChain {
+	t.Helper()
+	app.SetDefaultBondDenom()
+
+	encoding := app.MakeTestEncodingConfig()
+	gxrApp := NewApp(t, dbm.NewMemDB(), encoding, baseAppOptions...)
+
+	valSet, err := simtestutil.CreateRandomValidatorSet()
+	require.NoError(t, err)
+
+	accounts := make([]TestAccount, numAccounts)
+	genAccounts := make([]authtypes.GenesisAccount, numAccounts)
+	balances := make([]banktypes.Balance, 0, numAccounts+len(moduleBalances))
+	for i := range accounts {
+		priv := secp256k1.GenPrivKey()
+		addr := sdk.AccAddress(priv.PubKey().Address())
+		accounts[i] = TestAccount{PrivKey: priv, Address: addr}
+		genAccounts[i] = authtypes.NewBaseAccount(addr, priv.PubKey(), uint64(i), 0)
+		balances = append(balances, banktypes.Balance{
+			Address: addr.String(),
+			Coins:   sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, DefaultAccountBalance)),
+		})
+	}
+	balances = append(balances, moduleBalances...)
+
+	genesis, err := simtestutil.GenesisStateWithValSet(
+		encoding.Codec, app.NewDefaultGenesisState(encoding.Codec), valSet, genAccounts, balances...,
+	)
+	require.NoError(t, err)
+
+	for _, modify := range modifiers {
+		genesis = modify(encoding, genesis)
+	}
+
+	stateBytes, err := json.MarshalIndent(genesis, "", " ")
+	require.NoError(t, err)
+
+	gxrApp.InitChain(abci.RequestInitChain{
+		ChainId:         ChainID,
+		Time:            genesisTime,
+		Validators:      []abci.ValidatorUpdate{},
+		ConsensusParams: simtestutil.DefaultConsensusParams,
+		AppStateBytes:   stateBytes,
+	})
+
+	return &TestChain{
+		t:         t,
+		App:       gxrApp,
+		Encoding:  encoding,
+		Accounts:  accounts,
+		Validator: sdk.ValAddress(valSet.Validators[0].Address),
+		Height:    0,
+		Time:      genesisTime,
+	}
+}
+
+// NewApp creates a GXRApp over db without initialising the chain, as a node
+// does before InitChain or state sync
+func NewApp(t testing.TB, db dbm.DB, encoding app.EncodingConfig, baseAppOptions ...func(*baseapp.BaseApp)) *app.GXRApp {
+	t.Helper()
+
+	return app.New(
+		log.NewNopLogger(),
+		db,
+		nil,
+		true,
+		map[int64]bool{},
+		t.TempDir(),
+		5,
+		encoding,
+		simtestutil.EmptyAppOptions{},
+		baseAppOptions...,
+	)
+}
+
+// header returns the header of the block being built
+func (c *TestChain) header() tmproto.Header {
+	return tmproto.Header{ChainID: ChainID, Height: c.Height, Time: c.Time}
+}
+
+// BeginBlock starts the next block dt after the previous one
+func (c *TestChain) BeginBlock(dt time.Duration) {
+	c.Height++
+	c.Time = c.Time.Add(dt)
+
+	req := abci.RequestBeginBlock{Header: c.header()}
+	if c.SignBlocks {
+		req.LastCommitInfo = abci.CommitInfo{Votes: c.bondedVotes()}
+	}
+
+	res := c.App.BeginBlock(req)
+	c.LastEvents = append([]abci.Event{}, res.Events...)
+}
+
+// bondedVotes returns a signed vote of every bonded validator with its
+// voting power in the last committed state
+func (c *TestChain) bondedVotes() []abci.VoteInfo {
+	ctx := c.Context()
+	powerReduction := c.App.StakingKeeper.PowerReduction(ctx)
+
+	var votes []abci.VoteInfo
+	for _, validator := range c.App.StakingKeeper.GetBondedValidatorsByPower(ctx) {
+		consAddr, err := validator.GetConsAddr()
+		require.NoError(c.t, err)
+		votes = append(votes, abci.VoteInfo{
+			Validator:       abci.Validator{Address: consAddr, Power: validator.GetConsensusPower(powerReduction)},
+			SignedLastBlock: true,
+		})
+	}
+	return votes
+}
+
+// EndBlock ends and commits the current block
+func (c *TestChain) EndBlock() {
+	res := c.App.EndBlock(abci.RequestEndBlock{Height: c.Height})
+	c.LastEvents = append(c.LastEvents, res.Events...)
+	c.App.Commit()
+}
+
+// NextBlock produces an empty block dt after the previous one
+func (c *TestChain) NextBlock(dt time.Duration) {
+	c.BeginBlock(dt)
+	c.EndBlock()
+}
+
+// SendTx signs msgs with the sender's key, delivers them in the current block
+// and returns the result
+func (c *TestChain) SendTx(sender TestAccount, fee sdk.Coins, msgs ...sdk.Msg) abci.ResponseDeliverTx {
+	c.t.Helper()
+
+	acc := c.App.AccountKeeper.GetAccount(c.Context(), sender.Address)
+	require.NotNil(c.t, acc, "sender account not found")
+
+	tx, err := simtestutil.GenSignedMockTx(
+		rand.New(rand.NewSource(c.Height)),
+		c.Encoding.TxConfig,
+		msgs,
+		fee,
+		DefaultGas,
+		ChainID,
+		[]uint64{acc.GetAccountNumber()},
+		[]uint64{acc.GetSequence()},
+		sender.PrivKey,
+	)
+	require.NoError(c.t, err)
+
+	txBytes, err := c.Encoding.TxConfig.TxEncoder()(tx)
+	require.NoError(c.t, err)
+
+	res := c.App.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+	c.LastEvents = append(c.LastEvents, res.Events...)
+	return res
+}
+
+// SendTxWithFeeGranter is SendTx with the fees paid by feeGranter under a
+// fee allowance to the sender
+func (c *TestChain) SendTxWithFeeGranter(sender TestAccount, feeGranter sdk.AccAddress, fee sdk.Coins, msgs ...sdk.Msg) abci.ResponseDeliverTx {
+	c.t.Helper()
+
+	acc := c.App.AccountKeeper.GetAccount(c.Context(), sender.Address)
+	require.NotNil(c.t, acc, "sender account not found")
+
+	builder := c.Encoding.TxConfig.NewTxBuilder()
+	require.NoError(c.t, builder.SetMsgs(msgs...))
+	builder.SetFeeAmount(fee)
+	builder.SetGasLimit(DefaultGas)
+	builder.SetFeeGranter(feeGranter)
+
+	signMode := c.Encoding.TxConfig.SignModeHandler().DefaultMode()
+	signerData := authsigning.SignerData{
+		Address:       sender.Address.String(),
+		ChainID:       ChainID,
+		AccountNumber: acc.GetAccountNumber(),
+		Sequence:      acc.GetSequence(),
+		PubKey:        sender.PrivKey.PubKey(),
+	}
+	// The signer infos are part of the signed bytes, so they are set first
+	require.NoError(c.t, builder.SetSignatures(signing.SignatureV2{
+		PubKey:   sender.PrivKey.PubKey(),
+		Data:     &signing.SingleSignatureData{SignMode: signMode},
+		Sequence: acc.GetSequence(),
+	}))
+	sig, err := clienttx.SignWithPrivKey(signMode, signerData, builder, sender.PrivKey, c.Encoding.TxConfig, acc.GetSequence())
+	require.NoError(c.t, err)
+	require.NoError(c.t, builder.SetSignatures(sig))
+
+	txBytes, err := c.Encoding.TxConfig.TxEncoder()(builder.GetTx())
+	require.NoError(c.t, err)
+
+	res := c.App.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+	c.LastEvents = append(c.LastEvents, res.Events...)
+	return res
+}
+
+// Context returns a context over the last committed state
+func (c *TestChain) Context() sdk.Context {
+	return c.App.BaseApp.NewContext(true, c.header())
+}
+
+// DeliverContext returns a context over the block being built. Writes made
+// between BeginBlock and EndBlock are committed with the block.
+func (c *TestChain) DeliverContext() sdk.Context {
+	return c.App.BaseApp.NewContext(false, c.header())
+}
+
+// Balance returns the ugen balance of an address in the last committed state
+func (c *TestChain) Balance(addr sdk.AccAddress) sdk.Int {
+	return c.App.BankKeeper.GetBalance(c.Context(), addr, sdk.DefaultBondDenom).Amount
+}
+
+// ModuleBalance returns the ugen balance of a module account
+func (c *TestChain) ModuleBalance(moduleName string) sdk.Int {
+	return c.Balance(authtypes.NewModuleAddress(moduleName))
+}
+
+// HasEvent reports whether the last block emitted an event of the given type
+// with the given attribute value
+func (c *TestChain) HasEvent(eventType, key, value string) bool {
+	for _, event := range c.LastEvents {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == key && attr.Value == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AssertSupplyInvariant fails the test if the ugen total supply differs from
+// the sum of all account balances
+func (c *TestChain) AssertSupplyInvariant() {
+	c.t.Helper()
+	ctx := c.Context()
+
+	sum := sdk.ZeroInt()
+	c.App.BankKeeper.IterateAllBalances(ctx, func(_ sdk.AccAddress, coin sdk.Coin) bool {
+		if coin.Denom == sdk.DefaultBondDenom {
+			sum = sum.Add(coin.Amount)
+		}
+		return false
+	})
+
+	supply := c.App.BankKeeper.GetSupply(ctx, sdk.DefaultBondDenom)
+	require.True(c.t, supply.Amount.Equal(sum), "total supply %s != sum of balances %s", supply.Amount, sum)
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+	"github.com/Crocodile-ark/gxrchaind/x/denylist/keeper"
+	denylisttypes "github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+)
+
+// retiredAllocation stands in for an allocation address retired at genesis
+var retiredAllocation = sdk.AccAddress([]byte("retired-allocation-01"))
+
+// deniedAtGenesis denies sends to addresses in the denylist genesis
+func deniedAtGenesis(denied ...denylisttypes.DeniedAddress) GenesisModifier {
+	return func(encoding app.EncodingConfig, genesis app.GenesisState) app.GenesisState {
+		genesis[denylisttypes.ModuleName] = encoding.Codec.MustMarshalJSON(denylisttypes.NewGenesisState(denied))
+		return genesis
+	}
+}
+
+func TestAnteRejectsSendsToDeniedAddresses(t *testing.T) {
+	chain := Setup(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 2, nil, deniedAtGenesis(
+		denylisttypes.DeniedAddress{Address: retiredAllocation.String(), Reason: "retired presale allocation"},
+	))
+	sender, other := chain.Accounts[0], chain.Accounts[1]
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 5_000))
+	amount := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_000_000))
+
+	// The sender executes a send from the other account under an authz grant
+	exec := authz.NewMsgExec(sender.Address, []sdk.Msg{banktypes.NewMsgSend(other.Address, retiredAllocation, amount)})
+	testCases := []struct {
+		name string
+		msg  sdk.Msg
+	}{
+		{"send", banktypes.NewMsgSend(sender.Address, retiredAllocation, amount)},
+		{"multi-send", banktypes.NewMsgMultiSend(
+			[]banktypes.Input{banktypes.NewInput(sender.Address, amount.Add(amount...))},
+			[]banktypes.Output{banktypes.NewOutput(other.Address, amount), banktypes.NewOutput(retiredAllocation, amount)},
+		)},
+		{"authz exec", &exec},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain.BeginBlock(DefaultBlockTime)
+			res := chain.SendTx(sender, fee, tc.msg)
+			chain.EndBlock()
+
+			require.Equal(t, denylisttypes.ErrDeniedRecipient.ABCICode(), res.Code, res.Log)
+			require.Contains(t, res.Log, "/cosmos.bank.v1beta1.Msg")
+			require.Contains(t, res.Log, retiredAllocation.String()+" is rejected: retired presale allocation")
+		})
+	}
+
+	// Rejected in the ante handler, so no fee was charged and nothing moved
+	require.Equal(t, sdk.NewInt(DefaultAccountBalance), chain.Balance(sender.Address))
+	require.Equal(t, sdk.NewInt(DefaultAccountBalance), chain.Balance(other.Address))
+	require.True(t, chain.Balance(retiredAllocation).IsZero())
+
+	// Sends to other addresses go through
+	chain.BeginBlock(DefaultBlockTime)
+	res := chain.SendTx(sender, fee, banktypes.NewMsgSend(sender.Address, other.Address, amount))
+	chain.EndBlock()
+	require.Zero(t, res.Code, res.Log)
+	require.Equal(t, sdk.NewInt(DefaultAccountBalance+1_000_000), chain.Balance(other.Address))
+}
+
+func TestGovernanceUpdatesDenylist(t *testing.T) {
+	chain := Setup(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 2, nil)
+	sender, scam := chain.Accounts[0], chain.Accounts[1]
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 5_000))
+	send := banktypes.NewMsgSend(sender.Address, scam.Address, sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_000)))
+	gov := authtypes.NewModuleAddress(govtypes.ModuleName).String()
+	require.Equal(t, gov, chain.App.DenylistKeeper.GetAuthority())
+
+	update := func(authority string, add []denylisttypes.DeniedAddress, remove []string) error {
+		chain.BeginBlock(DefaultBlockTime)
+		defer chain.EndBlock()
+		msgServer := keeper.NewMsgServerImpl(chain.App.DenylistKeeper)
+		_, err := msgServer.UpdateDenylist(sdk.WrapSDKContext(chain.DeliverContext()), denylisttypes.NewMsgUpdateDenylist(authority, add, remove))
+		return err
+	}
+	sendCode := func() uint32 {
+		chain.BeginBlock(DefaultBlockTime)
+		defer chain.EndBlock()
+		return chain.SendTx(sender, fee, send).Code
+	}
+
+	require.Zero(t, sendCode())
+
+	// Only governance may change the denylist
+	denied := denylisttypes.DeniedAddress{Address: scam.Address.String(), Reason: "phishing airdrop"}
+	err := update(sender.Address.String(), []denylisttypes.DeniedAddress{denied}, nil)
+	require.ErrorIs(t, err, denylisttypes.ErrInvalidAuthority)
+	require.Zero(t, sendCode())
+
+	require.NoError(t, update(gov, []denylisttypes.DeniedAddress{denied}, nil))
+	require.Equal(t, denylisttypes.ErrDeniedRecipient.ABCICode(), sendCode())
+
+	res, err := chain.App.DenylistKeeper.DeniedAddresses(sdk.WrapSDKContext(chain.Context()), &denylisttypes.QueryDeniedAddressesRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []denylisttypes.DeniedAddress{denied}, res.DeniedAddresses)
+	single, err := chain.App.DenylistKeeper.DeniedAddress(sdk.WrapSDKContext(chain.Context()), &denylisttypes.QueryDeniedAddressRequest{Address: scam.Address.String()})
+	require.NoError(t, err)
+	require.True(t, single.Denied)
+	require.Equal(t, "phishing airdrop", single.DeniedAddress.Reason)
+
+	// Removing the address allows sends again; removing it twice fails
+	require.NoError(t, update(gov, nil, []string{scam.Address.String()}))
+	require.Zero(t, sendCode())
+	require.ErrorIs(t, update(gov, nil, []string{scam.Address.String()}), denylisttypes.ErrInvalidDeniedAddress)
+
+	res, err = chain.App.DenylistKeeper.DeniedAddresses(sdk.WrapSDKContext(chain.Context()), &denylisttypes.QueryDeniedAddressesRequest{})
+	require.NoError(t, err)
+	require.Empty(t, res.DeniedAddresses)
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// halvingParams changes the halving genesis params
+func halvingParams(modify func(*halvingtypes.Params)) GenesisModifier {
+	return func(encoding app.EncodingConfig, genesis app.GenesisState) app.GenesisState {
+		var halvingGenesis halvingtypes.GenesisState
+		encoding.Codec.MustUnmarshalJSON(genesis[halvingtypes.ModuleName], &halvingGenesis)
+		modify(&halvingGenesis.Params)
+		genesis[halvingtypes.ModuleName] = encoding.Codec.MustMarshalJSON(&halvingGenesis)
+		return genesis
+	}
+}
+
+func TestDexShareRedirectedAfterDexWindow(t *testing.T) {
+	genesisTime := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	// Month 14 of the period is running, 390 days after the distribution
+	// start: inside the two-year distribution period, past a one-year DEX window
+	distributionStart := genesisTime.Add(-13*halvingkeeper.MonthDuration - time.Hour)
+
+	testCases := []struct {
+		name       string
+		dexPeriod  time.Duration
+		redirected bool
+	}{
+		{"default window", halvingtypes.DefaultDexDistributionPeriod, false},
+		{"one-year window", 365 * 24 * time.Hour, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+				Address: halvingAddr.String(),
+				Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+			}}, importedHalvingCycle(distributionStart, 13), halvingParams(func(params *halvingtypes.Params) {
+				params.DexDistributionPeriod = tc.dexPeriod
+				params.PostDexShareDestination = halvingtypes.PostDexShareBurn
+			}))
+			k := chain.App.HalvingKeeper
+
+			// The first block pays month 14 through the BeginBlocker
+			chain.NextBlock(DefaultBlockTime)
+			ctx := chain.Context()
+			info, found := k.GetHalvingInfo(ctx)
+			require.True(t, found)
+			require.True(t, info.DistributionActive)
+			require.Equal(t, uint64(14), info.MonthsDistributed)
+
+			records := k.GetAllDistributionRecords(ctx)
+			require.Len(t, records, 1)
+			record := records[0]
+			require.Equal(t, uint64(14), record.DistributionMonth)
+			dexShare := sdk.NewDecFromInt(record.Amount.Amount).Mul(k.GetParams(ctx).DexShare).TruncateInt()
+			require.True(t, dexShare.IsPositive())
+
+			pendingDex := k.GetPendingDexAllocation(ctx, 1).Amount.Amount
+			// Every distribution burns the monthly amount first; only the
+			// redirect burns the DEX share on its own
+			dexShareBurned := chain.HasEvent(banktypes.EventTypeCoinBurn, sdk.AttributeKeyAmount,
+				sdk.NewCoins(sdk.NewCoin(halvingkeeper.MainDenom, dexShare)).String())
+			if !tc.redirected {
+				require.Equal(t, dexShare, pendingDex)
+				require.True(t, record.RedirectedDexAmount.IsZero())
+				require.Empty(t, record.DexRedirectDestination)
+				require.False(t, dexShareBurned)
+				return
+			}
+
+			// The DEX share went to post_dex_share_destination, not the DEX
+			require.True(t, pendingDex.IsZero())
+			require.Equal(t, dexShare, record.RedirectedDexAmount.Amount)
+			require.Equal(t, halvingtypes.PostDexShareBurn, record.DexRedirectDestination)
+			require.True(t, chain.HasEvent(halvingtypes.EventTypeDexShareRedirected,
+				halvingtypes.AttributeKeyDestination, halvingtypes.PostDexShareBurn))
+			require.True(t, dexShareBurned)
+			chain.AssertSupplyInvariant()
+		})
+	}
+}
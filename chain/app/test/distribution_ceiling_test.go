@@ -0,0 +1,78 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// maxMonthlyDistribution sets the absolute monthly distribution ceiling in
+// the halving genesis
+func maxMonthlyDistribution(ceiling int64) GenesisModifier {
+	return func(encoding app.EncodingConfig, genesis app.GenesisState) app.GenesisState {
+		var halvingGenesis halvingtypes.GenesisState
+		encoding.Codec.MustUnmarshalJSON(genesis[halvingtypes.ModuleName], &halvingGenesis)
+		halvingGenesis.Params.MaxMonthlyDistribution = sdk.NewInt(ceiling)
+		genesis[halvingtypes.ModuleName] = encoding.Codec.MustMarshalJSON(&halvingGenesis)
+		return genesis
+	}
+}
+
+func TestDistributionClampedAtCeiling(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+	monthly := sdk.NewInt(halvingFund).QuoRaw(24)
+	ceiling := monthly.MulRaw(6).QuoRaw(10)
+
+	chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime), maxMonthlyDistribution(ceiling.Int64()))
+
+	halvingInfo := func() halvingtypes.HalvingInfo {
+		info, found := chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+		require.True(t, found)
+		return info
+	}
+
+	// The first month pays the ceiling and reports what it held back
+	chain.NextBlock(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Sub(chain.Time))
+	heldBack := sdk.NewCoin(halvingkeeper.MainDenom, monthly.Sub(ceiling))
+	require.True(t, chain.HasEvent(halvingtypes.EventTypeDistributionClamped, halvingtypes.AttributeKeyClampedAmount, heldBack.String()))
+	require.True(t, chain.HasEvent(halvingtypes.EventTypeDistributionClamped, halvingtypes.AttributeKeyMonth, "1"))
+
+	info := halvingInfo()
+	require.Equal(t, uint64(1), info.MonthsDistributed)
+	require.Equal(t, ceiling, info.DistributedAmount.Amount)
+	require.Equal(t, heldBack, info.ClampedAmount)
+	require.Equal(t, sdk.NewInt(halvingFund), info.DistributedAmount.Amount.Add(info.HalvingFund.Amount))
+	chain.AssertSupplyInvariant()
+
+	// Governance removes the ceiling
+	chain.BeginBlock(DefaultBlockTime)
+	ctx := chain.DeliverContext()
+	params := chain.App.HalvingKeeper.GetParams(ctx)
+	params.MaxMonthlyDistribution = sdk.ZeroInt()
+	chain.App.HalvingKeeper.SetParams(ctx, params)
+	chain.EndBlock()
+
+	// The next month pays its amount and what the first one held back
+	nextDistribution := time.Unix(info.LastMonthlyDistrib, 0).Add(halvingkeeper.MonthlyDistributionTrigger)
+	chain.NextBlock(nextDistribution.Sub(chain.Time))
+	require.False(t, chain.HasEvent(halvingtypes.EventTypeDistributionClamped, halvingtypes.AttributeKeyMonth, "2"))
+
+	info = halvingInfo()
+	require.Equal(t, uint64(2), info.MonthsDistributed)
+	require.Equal(t, monthly.MulRaw(2), info.DistributedAmount.Amount)
+	require.True(t, info.Clamped().IsZero())
+	require.Equal(t, sdk.NewInt(halvingFund), info.DistributedAmount.Amount.Add(info.HalvingFund.Amount))
+	chain.AssertSupplyInvariant()
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// setValidatorInactiveDays produces a block dt after the previous one in
+// which the validator's uptime record for the running uptime month is set to
+// inactiveDays, as if it had just crossed (or recovered from) the threshold
+func setValidatorInactiveDays(chain *TestChain, dt time.Duration, inactiveDays uint64) {
+	chain.BeginBlock(dt)
+	ctx := chain.DeliverContext()
+	chain.App.HalvingKeeper.SetValidatorUptime(ctx, chain.Validator, halvingtypes.ValidatorUptime{
+		ValidatorAddress: chain.Validator.String(),
+		CurrentMonth:     uint64(chain.Time.Unix() / int64(halvingkeeper.MonthDuration.Seconds())),
+		InactiveDays:     inactiveDays,
+		LastCheck:        chain.Time.Unix(),
+	})
+	chain.EndBlock()
+}
+
+func TestValidatorRewardsFollowEligibilitySnapshot(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime))
+
+	validatorAcc := sdk.AccAddress(chain.Validator)
+	monthly := sdk.NewInt(halvingFund).QuoRaw(24)
+	validatorShare := sdk.NewDecFromInt(monthly).Mul(sdk.MustNewDecFromStr("0.70")).TruncateInt()
+
+	// Month 1 is due from genesis: the first block snapshots the validator as eligible
+	chain.NextBlock(DefaultBlockTime)
+	require.True(t, chain.HasEvent(halvingtypes.EventTypeEligibilitySnapshot, halvingtypes.AttributeKeyMonth, "1"))
+	snapshots := chain.App.HalvingKeeper.GetEligibilitySnapshots(chain.Context(), 1, 1)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, chain.Validator.String(), snapshots[0].ValidatorAddress)
+	require.True(t, snapshots[0].Eligible)
+
+	// The snapshot is taken once per month
+	chain.NextBlock(DefaultBlockTime)
+	require.False(t, chain.HasEvent(halvingtypes.EventTypeEligibilitySnapshot, halvingtypes.AttributeKeyMonth, "1"))
+
+	// The validator crosses the inactivity threshold in the block before the
+	// distribution: it is still paid, as it was eligible at the snapshot
+	distributionDay := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	setValidatorInactiveDays(chain, distributionDay.Add(-DefaultBlockTime).Sub(chain.Time), halvingkeeper.ValidatorInactiveThreshold+1)
+	validatorBefore := chain.Balance(validatorAcc)
+
+	chain.NextBlock(DefaultBlockTime)
+	require.True(t, chain.HasEvent(banktypes.EventTypeCoinBurn, banktypes.AttributeKeyBurner, halvingAddr.String()))
+	require.Equal(t, validatorBefore.Add(validatorShare), chain.Balance(validatorAcc))
+	chain.AssertSupplyInvariant()
+
+	// Month 2 becomes due while the validator is inactive, and it recovers
+	// right after the snapshot: it is not paid for month 2
+	monthTwo := genesisTime.Add(halvingkeeper.MonthDuration)
+	setValidatorInactiveDays(chain, monthTwo.Add(-DefaultBlockTime).Sub(chain.Time), halvingkeeper.ValidatorInactiveThreshold+1)
+	chain.NextBlock(DefaultBlockTime)
+	require.True(t, chain.HasEvent(halvingtypes.EventTypeEligibilitySnapshot, halvingtypes.AttributeKeyMonth, "2"))
+	snapshots = chain.App.HalvingKeeper.GetEligibilitySnapshots(chain.Context(), 1, 2)
+	require.Len(t, snapshots, 1)
+	require.False(t, snapshots[0].Eligible)
+	require.Equal(t, sdk.NewDec(halvingkeeper.ValidatorInactiveThreshold+1), snapshots[0].InactiveDays)
+
+	// The month 1 snapshot was used and is pruned
+	require.Empty(t, chain.App.HalvingKeeper.GetEligibilitySnapshots(chain.Context(), 1, 1))
+
+	setValidatorInactiveDays(chain, DefaultBlockTime, 0)
+	validatorBefore = chain.Balance(validatorAcc)
+	moduleBefore := chain.ModuleBalance(halvingtypes.ModuleName)
+
+	chain.NextBlock(distributionDay.Add(halvingkeeper.MonthlyDistributionTrigger).Sub(chain.Time))
+	require.True(t, chain.HasEvent(banktypes.EventTypeCoinBurn, banktypes.AttributeKeyBurner, halvingAddr.String()))
+	require.Equal(t, validatorBefore, chain.Balance(validatorAcc))
+
+	// The forfeited validator share stays with the module
+	delegatorShare := sdk.NewDecFromInt(monthly).Mul(sdk.MustNewDecFromStr("0.20")).TruncateInt()
+	dexShare := sdk.NewDecFromInt(monthly).Mul(sdk.MustNewDecFromStr("0.10")).TruncateInt()
+	require.Equal(t, moduleBefore.Sub(delegatorShare).Sub(dexShare), chain.ModuleBalance(halvingtypes.ModuleName))
+
+	info, found := chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+	require.True(t, found)
+	require.Equal(t, uint64(2), info.MonthsDistributed)
+	chain.AssertSupplyInvariant()
+}
+
+func TestEligibilitySnapshotDelay(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+	delay := 10 * 24 * time.Hour
+
+	withDelay := func(encoding app.EncodingConfig, genesis app.GenesisState) app.GenesisState {
+		var halvingGenesis halvingtypes.GenesisState
+		encoding.Codec.MustUnmarshalJSON(genesis[halvingtypes.ModuleName], &halvingGenesis)
+		halvingGenesis.Params.EligibilitySnapshotDelay = delay
+		genesis[halvingtypes.ModuleName] = encoding.Codec.MustMarshalJSON(&halvingGenesis)
+		return genesis
+	}
+
+	chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime), withDelay)
+
+	// No snapshot until the delay has passed since month 1 became due
+	chain.NextBlock(DefaultBlockTime)
+	require.False(t, chain.App.HalvingKeeper.EligibilitySnapshotDue(chain.Context()))
+	require.Empty(t, chain.App.HalvingKeeper.GetEligibilitySnapshots(chain.Context(), 1, 1))
+
+	chain.NextBlock(genesisTime.Add(delay).Sub(chain.Time))
+	require.True(t, chain.HasEvent(halvingtypes.EventTypeEligibilitySnapshot, halvingtypes.AttributeKeyMonth, "1"))
+	snapshots := chain.App.HalvingKeeper.GetEligibilitySnapshots(chain.Context(), 1, 1)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, genesisTime.Add(delay).Unix(), snapshots[0].TakenAt)
+
+	// The snapshot survives an export and import
+	exported := chain.App.HalvingKeeper.GetAllEligibilitySnapshots(chain.Context())
+	require.Equal(t, snapshots, exported)
+	require.NoError(t, (&halvingtypes.GenesisState{
+		Params:               chain.App.HalvingKeeper.GetParams(chain.Context()),
+		HalvingInfo:          halvingtypes.HalvingInfo{CurrentCycle: 1, CycleStartTime: genesisTime.Unix()},
+		EligibilitySnapshots: exported,
+	}).Validate())
+}
@@ -0,0 +1,201 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	"github.com/stretchr/testify/require"
+
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+)
+
+// feeBenchTxsPerBlock is how many routed transactions a benchmark block holds
+const feeBenchTxsPerBlock = 100
+
+// transientStores are not persisted, so their writes are not store writes
+var transientStores = map[string]bool{
+	paramstypes.TStoreKey:    true,
+	feeroutertypes.TStoreKey: true,
+}
+
+// writeCountingMultiStore counts the writes made through it, per store and
+// per store key
+type writeCountingMultiStore struct {
+	storetypes.MultiStore
+	stores map[string]int
+	keys   map[string]int
+}
+
+func newWriteCountingMultiStore(ms storetypes.MultiStore) *writeCountingMultiStore {
+	return &writeCountingMultiStore{MultiStore: ms, stores: make(map[string]int), keys: make(map[string]int)}
+}
+
+func (ms *writeCountingMultiStore) GetKVStore(key storetypes.StoreKey) storetypes.KVStore {
+	return &writeCountingKVStore{KVStore: ms.MultiStore.GetKVStore(key), name: key.Name(), ms: ms}
+}
+
+// persistentWrites returns the writes to stores that are committed
+func (ms *writeCountingMultiStore) persistentWrites() int {
+	writes := 0
+	for name, n := range ms.stores {
+		if !transientStores[name] {
+			writes += n
+		}
+	}
+	return writes
+}
+
+// keyWrites returns the writes to one key of a store
+func (ms *writeCountingMultiStore) keyWrites(store string, key []byte) int {
+	return ms.keys[store+"/"+string(key)]
+}
+
+func (ms *writeCountingMultiStore) count(store string, key []byte) {
+	ms.stores[store]++
+	ms.keys[store+"/"+string(key)]++
+}
+
+type writeCountingKVStore struct {
+	storetypes.KVStore
+	name string
+	ms   *writeCountingMultiStore
+}
+
+func (s *writeCountingKVStore) Set(key, value []byte) {
+	s.ms.count(s.name, key)
+	s.KVStore.Set(key, value)
+}
+
+func (s *writeCountingKVStore) Delete(key []byte) {
+	s.ms.count(s.name, key)
+	s.KVStore.Delete(key)
+}
+
+// registerBenchLPPools registers n active LP pools in the current block
+func registerBenchLPPools(chain *TestChain, n int) []sdk.AccAddress {
+	pools := make([]sdk.AccAddress, n)
+	for i := range pools {
+		pools[i] = sdk.AccAddress([]byte(fmt.Sprintf("bench-lp-pool-%06d", i)))
+		chain.App.FeeRouterKeeper.SetLPPool(chain.DeliverContext(), feeroutertypes.LPPool{
+			Address: pools[i].String(),
+			Name:    fmt.Sprintf("GXR/BENCH%d", i),
+			Active:  true,
+		})
+	}
+	return pools
+}
+
+// fundFeeCollector moves fees for txs transactions into the fee collector
+// of the current block, as the ante handler would
+func fundFeeCollector(tb testing.TB, chain *TestChain, fee sdk.Coins, txs int) {
+	total := sdk.NewCoins()
+	for i := 0; i < txs; i++ {
+		total = total.Add(fee...)
+	}
+	require.NoError(tb, chain.App.BankKeeper.SendCoinsFromAccountToModule(chain.DeliverContext(), chain.Accounts[0].Address, authtypes.FeeCollectorName, total))
+}
+
+// BenchmarkProcessTransactionFees routes farming transaction fees in blocks
+// of feeBenchTxsPerBlock transactions. Besides time it reports the committed
+// store writes per transaction, in all stores and in the feerouter store,
+// including the once-per-block fee stats write.
+func BenchmarkProcessTransactionFees(b *testing.B) {
+	for _, pools := range []int{0, 5, 50} {
+		b.Run(fmt.Sprintf("pools=%d", pools), func(b *testing.B) {
+			benchmarkProcessTransactionFees(b, pools)
+		})
+	}
+}
+
+func benchmarkProcessTransactionFees(b *testing.B, pools int) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(b, genesisTime, 1, nil)
+	feeRouter := chain.App.FeeRouterKeeper
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 10_001))
+
+	chain.BeginBlock(DefaultBlockTime)
+	registerBenchLPPools(chain, pools)
+	chain.EndBlock()
+
+	var writes, feeRouterWrites int
+	var counting *writeCountingMultiStore
+	var ctx sdk.Context
+	endBlock := func() {
+		feeRouter.FlushBlockFeeStats(ctx)
+		writes += counting.persistentWrites()
+		feeRouterWrites += counting.stores[feeroutertypes.StoreKey]
+		chain.EndBlock()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%feeBenchTxsPerBlock == 0 {
+			b.StopTimer()
+			if i > 0 {
+				endBlock()
+			}
+			chain.BeginBlock(DefaultBlockTime)
+			fundFeeCollector(b, chain, fee, feeBenchTxsPerBlock)
+			counting = newWriteCountingMultiStore(chain.DeliverContext().MultiStore())
+			ctx = chain.DeliverContext().WithMultiStore(counting)
+			b.StartTimer()
+		}
+
+		if err := feeRouter.ProcessTransactionFees(ctx, fee, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	endBlock()
+
+	b.ReportMetric(float64(writes)/float64(b.N), "writes/op")
+	b.ReportMetric(float64(feeRouterWrites)/float64(b.N), "feerouter-writes/op")
+}
+
+func TestFeeRoutingStoreWrites(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(t, genesisTime, 1, nil)
+	feeRouter := chain.App.FeeRouterKeeper
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 10_001))
+	const txs = 3
+
+	chain.BeginBlock(DefaultBlockTime)
+	pools := registerBenchLPPools(chain, 5)
+	chain.EndBlock()
+
+	chain.BeginBlock(DefaultBlockTime)
+	fundFeeCollector(t, chain, fee, txs)
+	counting := newWriteCountingMultiStore(chain.DeliverContext().MultiStore())
+	ctx := chain.DeliverContext().WithMultiStore(counting)
+
+	for i := 0; i < txs; i++ {
+		require.NoError(t, feeRouter.ProcessTransactionFees(ctx, fee, true))
+	}
+
+	// Transactions write each paid pool once, and no fee stats
+	require.Zero(t, counting.keyWrites(feeroutertypes.StoreKey, feeroutertypes.FeeStatsKey))
+	for _, pool := range pools {
+		key := append(append([]byte{}, feeroutertypes.LPPoolsKey...), []byte(pool.String())...)
+		require.Equal(t, txs, counting.keyWrites(feeroutertypes.StoreKey, key))
+	}
+
+	// Params and active pools are read from the param and pool stores at
+	// most once per block
+	require.LessOrEqual(t, counting.keyWrites(feeroutertypes.TStoreKey, feeroutertypes.BlockParamsKey), 1)
+	require.LessOrEqual(t, counting.keyWrites(feeroutertypes.TStoreKey, feeroutertypes.BlockActivePoolsKey), 1)
+
+	// The block's fee stats are written once at its end
+	feeRouter.FlushBlockFeeStats(ctx)
+	require.Equal(t, 1, counting.keyWrites(feeroutertypes.StoreKey, feeroutertypes.FeeStatsKey))
+	chain.EndBlock()
+
+	stats, found := feeRouter.GetFeeStats(chain.Context())
+	require.True(t, found)
+	require.Equal(t, "30003ugen", stats.TotalCollected.String())
+}
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/stretchr/testify/require"
+
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+)
+
+func TestPostHandlerRoutesTransactionFees(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(t, genesisTime, 3, nil)
+	chain.SignBlocks = true
+	feeRouter := chain.App.FeeRouterKeeper
+	sender, recipient, pool := chain.Accounts[0], chain.Accounts[1], chain.Accounts[2]
+	validatorAcc := sdk.AccAddress(chain.Validator)
+
+	chain.BeginBlock(DefaultBlockTime)
+	feeRouter.SetLPPool(chain.DeliverContext(), feeroutertypes.LPPool{
+		Address: pool.Address.String(),
+		Name:    "GXR/USDC",
+		Active:  true,
+	})
+	chain.EndBlock()
+
+	testCases := []struct {
+		name      string
+		to        sdk.AccAddress
+		isFarming bool
+		// validator, DEX, PoS and LP shares of a 10,000ugen fee
+		validator, dex, pos, lp int64
+	}{
+		{"general transaction", recipient.Address, false, 4_000, 3_000, 3_000, 0},
+		{"farming transaction", pool.Address, true, 3_000, 2_500, 2_000, 2_500},
+	}
+
+	expectedStats := feeroutertypes.DefaultFeeStats()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 10_000))
+			amount := sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_000)
+
+			validatorBefore := chain.Balance(validatorAcc)
+			dexBefore := chain.ModuleBalance(feeroutertypes.ModuleName)
+			distrBefore := chain.ModuleBalance(distrtypes.ModuleName)
+			communityBefore := chain.App.DistrKeeper.GetFeePool(chain.Context()).CommunityPool
+			outstandingBefore := chain.App.DistrKeeper.GetValidatorOutstandingRewards(chain.Context(), chain.Validator).Rewards
+			toBefore := chain.Balance(tc.to)
+
+			chain.BeginBlock(DefaultBlockTime)
+			res := chain.SendTx(sender, fee, banktypes.NewMsgSend(sender.Address, tc.to, sdk.NewCoins(amount)))
+			require.Zero(t, res.Code, res.Log)
+			chain.EndBlock()
+
+			require.Equal(t, validatorBefore.AddRaw(tc.validator), chain.Balance(validatorAcc))
+			require.Equal(t, dexBefore.AddRaw(tc.dex), chain.ModuleBalance(feeroutertypes.ModuleName))
+			require.Equal(t, toBefore.Add(amount.Amount).AddRaw(tc.lp), chain.Balance(tc.to))
+
+			// Only the PoS share is left in the fee collector
+			require.Equal(t, tc.pos, chain.ModuleBalance(authtypes.FeeCollectorName).Int64())
+			require.Equal(t, distrBefore, chain.ModuleBalance(distrtypes.ModuleName))
+			chain.AssertSupplyInvariant()
+
+			// The next block pays it to the validator's delegators, less the
+			// community tax
+			chain.NextBlock(DefaultBlockTime)
+			require.True(t, chain.ModuleBalance(authtypes.FeeCollectorName).IsZero())
+			require.Equal(t, distrBefore.AddRaw(tc.pos), chain.ModuleBalance(distrtypes.ModuleName))
+			pos := sdk.NewDecCoins(sdk.NewInt64DecCoin(halvingkeeper.MainDenom, tc.pos))
+			tax := chain.App.DistrKeeper.GetCommunityTax(chain.Context())
+			delegators := pos.MulDecTruncate(sdk.OneDec().Sub(tax))
+			outstanding := chain.App.DistrKeeper.GetValidatorOutstandingRewards(chain.Context(), chain.Validator).Rewards.Sub(outstandingBefore)
+			require.Equal(t, delegators.String(), outstanding.String())
+			community := chain.App.DistrKeeper.GetFeePool(chain.Context()).CommunityPool.Sub(communityBefore)
+			require.Equal(t, pos.Sub(delegators).String(), community.String())
+			chain.AssertSupplyInvariant()
+
+			expectedStats = expectedStats.Add(feeroutertypes.FeeStats{
+				TotalCollected:    fee,
+				TotalToValidators: sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, tc.validator)),
+				TotalToDex:        sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, tc.dex)),
+				TotalToPos:        sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, tc.pos)),
+				TotalToLPRewards:  sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, tc.lp)),
+			})
+			stats, found := feeRouter.GetFeeStats(chain.Context())
+			require.True(t, found)
+			require.Equal(t, expectedStats, stats)
+		})
+	}
+}
+
+func TestPostHandlerRoutesFeesOncePerBlock(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(t, genesisTime, 2, nil)
+	feeRouter := chain.App.FeeRouterKeeper
+	sender, recipient := chain.Accounts[0], chain.Accounts[1]
+	validatorAcc := sdk.AccAddress(chain.Validator)
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 10_000))
+	const txs = 3
+
+	validatorBefore := chain.Balance(validatorAcc)
+	totalBefore, _ := feeRouter.GetValidatorFeeTotal(chain.Context(), chain.Validator.String())
+
+	// Delivered transactions only add their fees to the block's
+	chain.BeginBlock(DefaultBlockTime)
+	for i := 0; i < txs; i++ {
+		res := chain.SendTx(sender, fee, banktypes.NewMsgSend(sender.Address, recipient.Address,
+			sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_000))))
+		require.Zero(t, res.Code, res.Log)
+	}
+	require.Equal(t, int64(txs*10_000), chain.ModuleBalance(authtypes.FeeCollectorName).Int64())
+	require.Equal(t, "30000ugen", feeRouter.GetBlockFees(chain.DeliverContext(), false).String())
+	require.True(t, feeRouter.GetBlockFees(chain.DeliverContext(), true).IsZero())
+	require.Equal(t, validatorBefore, chain.Balance(validatorAcc))
+	chain.EndBlock()
+
+	// The end of the block routes them in one payout per validator, leaving
+	// the PoS share for x/distribution
+	require.Equal(t, int64(txs*3_000), chain.ModuleBalance(authtypes.FeeCollectorName).Int64())
+	require.Equal(t, validatorBefore.AddRaw(txs*4_000), chain.Balance(validatorAcc))
+	total, found := feeRouter.GetValidatorFeeTotal(chain.Context(), chain.Validator.String())
+	require.True(t, found)
+	require.Equal(t, "12000ugen", total.Total.Sub(totalBefore.Total...).String())
+	require.True(t, feeRouter.GetBlockFees(chain.Context(), false).IsZero())
+	chain.AssertSupplyInvariant()
+
+	stats, found := feeRouter.GetFeeStats(chain.Context())
+	require.True(t, found)
+	require.Equal(t, "30000ugen", stats.TotalCollected.String())
+}
+
+func TestPostHandlerLeavesFeesOfFailedTransactions(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(t, genesisTime, 2, nil)
+	sender, recipient := chain.Accounts[0], chain.Accounts[1]
+	fee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 10_000))
+
+	// The send fails for lack of funds after the fee was deducted
+	chain.BeginBlock(DefaultBlockTime)
+	res := chain.SendTx(sender, fee, banktypes.NewMsgSend(sender.Address, recipient.Address,
+		sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 2*DefaultAccountBalance))))
+	require.NotZero(t, res.Code)
+	chain.EndBlock()
+
+	require.Equal(t, int64(10_000), chain.ModuleBalance(authtypes.FeeCollectorName).Int64())
+	require.True(t, chain.ModuleBalance(feeroutertypes.ModuleName).IsZero())
+	_, found := chain.App.FeeRouterKeeper.GetFeeStats(chain.Context())
+	require.False(t, found)
+}
+
+func TestProcessTransactionFeesCreditsEachBondedValidator(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(t, genesisTime, 3, nil)
+	feeRouter := chain.App.FeeRouterKeeper
+	sender := chain.Accounts[0]
+	txFee := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 5_000))
+
+	// Two more validators join the genesis one in the bonded set
+	chain.BeginBlock(DefaultBlockTime)
+	for i, operator := range chain.Accounts[1:] {
+		createValidator, err := stakingtypes.NewMsgCreateValidator(
+			sdk.ValAddress(operator.Address),
+			ed25519.GenPrivKey().PubKey(),
+			sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_000_000),
+			stakingtypes.NewDescription(fmt.Sprintf("validator-%d", i), "", "", "", ""),
+			stakingtypes.NewCommissionRates(sdk.NewDecWithPrec(1, 1), sdk.NewDecWithPrec(2, 1), sdk.NewDecWithPrec(1, 2)),
+			sdk.OneInt(),
+		)
+		require.NoError(t, err)
+		res := chain.SendTx(operator, txFee, createValidator)
+		require.Zero(t, res.Code, res.Log)
+	}
+	chain.EndBlock()
+
+	chain.BeginBlock(DefaultBlockTime)
+	ctx := chain.DeliverContext()
+	validators := chain.App.StakingKeeper.GetBondedValidatorsByPower(ctx)
+	require.Len(t, validators, 3)
+
+	before := make(map[string]sdk.Coins, len(validators))
+	for _, validator := range validators {
+		total, _ := feeRouter.GetValidatorFeeTotal(ctx, validator.OperatorAddress)
+		before[validator.OperatorAddress] = total.Total
+	}
+
+	// The 40% validator share of 9,000ugen is 1,200ugen per validator
+	fees := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 9_000))
+	require.NoError(t, chain.App.BankKeeper.SendCoinsFromAccountToModule(ctx, sender.Address, authtypes.FeeCollectorName, fees))
+	require.NoError(t, feeRouter.ProcessTransactionFees(ctx, fees, false))
+
+	share := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 1_200))
+	for _, validator := range validators {
+		total, found := feeRouter.GetValidatorFeeTotal(ctx, validator.OperatorAddress)
+		require.True(t, found, validator.OperatorAddress)
+		require.Equal(t, before[validator.OperatorAddress].Add(share...), total.Total, validator.OperatorAddress)
+		require.Equal(t, validator.OperatorAddress, total.ValidatorAddress)
+	}
+	chain.EndBlock()
+	chain.AssertSupplyInvariant()
+}
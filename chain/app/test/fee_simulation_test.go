@@ -0,0 +1,152 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/stretchr/testify/require"
+
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+)
+
+func TestSimulatedFeeSplitMatchesDistribution(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(t, genesisTime, 2, nil)
+	chain.BeginBlock(DefaultBlockTime)
+	ctx := chain.DeliverContext()
+
+	bank, feeRouter := chain.App.BankKeeper, chain.App.FeeRouterKeeper
+	validatorAddr := sdk.AccAddress(chain.Validator)
+	poolAddr := chain.Accounts[1].Address
+	feeCollectorAddr := authtypes.NewModuleAddress(authtypes.FeeCollectorName)
+	dexAddr := authtypes.NewModuleAddress(feeroutertypes.ModuleName)
+
+	feeRouter.SetLPPool(ctx, feeroutertypes.LPPool{
+		Address: poolAddr.String(),
+		Name:    "GXR/USDC",
+		Active:  true,
+	})
+
+	for _, isFarming := range []bool{false, true} {
+		fees := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 10_001))
+		require.NoError(t, bank.SendCoinsFromAccountToModule(ctx, chain.Accounts[0].Address, authtypes.FeeCollectorName, fees))
+
+		validatorBefore := bank.GetAllBalances(ctx, validatorAddr)
+		poolBefore := bank.GetAllBalances(ctx, poolAddr)
+		collectorBefore := bank.GetAllBalances(ctx, feeCollectorAddr)
+		dexBefore := bank.GetAllBalances(ctx, dexAddr)
+		communityBefore := chain.App.DistrKeeper.GetFeePool(ctx).CommunityPool
+
+		split := feeRouter.SimulateFeeSplit(ctx, fees, isFarming)
+		require.NoError(t, feeRouter.ProcessTransactionFees(ctx, fees, isFarming))
+
+		// With a single bonded validator and LP pool nothing is left as dust,
+		// so every share lands exactly where the simulation said it would
+		require.Equal(t, sdk.NewCoins(split.Validators...).String(),
+			bank.GetAllBalances(ctx, validatorAddr).Sub(validatorBefore...).String(), "farming=%t", isFarming)
+		require.Equal(t, sdk.NewCoins(split.LPRewards...).String(),
+			bank.GetAllBalances(ctx, poolAddr).Sub(poolBefore...).String(), "farming=%t", isFarming)
+		require.Equal(t, sdk.NewCoins(split.Dex...).String(),
+			bank.GetAllBalances(ctx, dexAddr).Sub(dexBefore...).String(), "farming=%t", isFarming)
+		require.Equal(t, collectorBefore.Sub(fees...).String(),
+			bank.GetAllBalances(ctx, feeCollectorAddr).String(), "farming=%t", isFarming)
+
+		community := chain.App.DistrKeeper.GetFeePool(ctx).CommunityPool.Sub(communityBefore)
+		require.Equal(t, sdk.NewDecCoinsFromCoins(split.Pos...).String(), community.String(), "farming=%t", isFarming)
+	}
+
+	// Fee stats are stored at the end of the block
+	_, found := feeRouter.GetFeeStats(ctx)
+	require.False(t, found)
+	chain.EndBlock()
+
+	stats, found := feeRouter.GetFeeStats(chain.Context())
+	require.True(t, found)
+	require.Equal(t, "20002ugen", stats.TotalCollected.String())
+	require.Equal(t, "7000ugen", stats.TotalToValidators.String())
+	require.Equal(t, "2500ugen", stats.TotalToLPRewards.String())
+}
+
+func TestFeeStatsAggregatedPerBlock(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(t, genesisTime, 2, nil)
+	feeRouter := chain.App.FeeRouterKeeper
+	poolAddr := chain.Accounts[1].Address
+
+	chain.BeginBlock(DefaultBlockTime)
+	feeRouter.SetLPPool(chain.DeliverContext(), feeroutertypes.LPPool{
+		Address: poolAddr.String(),
+		Name:    "GXR/USDC",
+		Active:  true,
+	})
+	chain.EndBlock()
+
+	// The stats a per-transaction write would have produced
+	expected := feeroutertypes.DefaultFeeStats()
+	poolRewards := sdk.NewCoins()
+
+	for block := 0; block < 3; block++ {
+		chain.BeginBlock(DefaultBlockTime)
+		ctx := chain.DeliverContext()
+
+		for tx := 0; tx < 4; tx++ {
+			fees := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, int64(997+block*131+tx*17)))
+			isFarming := tx%2 == 1
+			require.NoError(t, chain.App.BankKeeper.SendCoinsFromAccountToModule(ctx, chain.Accounts[0].Address, authtypes.FeeCollectorName, fees))
+
+			split := feeRouter.SimulateFeeSplit(ctx, fees, isFarming)
+			require.NoError(t, feeRouter.ProcessTransactionFees(ctx, fees, isFarming))
+
+			expected = expected.Add(feeroutertypes.FeeStats{
+				TotalCollected:    fees,
+				TotalToValidators: sdk.NewCoins(split.Validators...),
+				TotalToDex:        sdk.NewCoins(split.Dex...),
+				TotalToPos:        sdk.NewCoins(split.Pos...),
+				TotalToLPRewards:  sdk.NewCoins(split.LPRewards...),
+			})
+			poolRewards = poolRewards.Add(split.LPRewards...)
+		}
+
+		chain.EndBlock()
+
+		stats, found := feeRouter.GetFeeStats(chain.Context())
+		require.True(t, found)
+		require.Equal(t, expected, stats, "block %d", block)
+
+		pool, found := feeRouter.GetLPPool(chain.Context(), poolAddr.String())
+		require.True(t, found)
+		require.Equal(t, poolRewards, pool.TotalRewards, "block %d", block)
+	}
+}
+
+func TestLPPoolChangesReachCachedActivePools(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	chain := Setup(t, genesisTime, 3, nil)
+	feeRouter, bank := chain.App.FeeRouterKeeper, chain.App.BankKeeper
+	first, second := chain.Accounts[1].Address, chain.Accounts[2].Address
+
+	chain.BeginBlock(DefaultBlockTime)
+	ctx := chain.DeliverContext()
+	fees := sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 10_000))
+	require.NoError(t, bank.SendCoinsFromAccountToModule(ctx, chain.Accounts[0].Address, authtypes.FeeCollectorName, fees.Add(fees...)))
+
+	feeRouter.SetLPPool(ctx, feeroutertypes.LPPool{Address: first.String(), Name: "GXR/USDC", Active: true})
+	require.NoError(t, feeRouter.ProcessTransactionFees(ctx, fees, true))
+
+	// A pool registered after the active pools were cached shares the next
+	// transaction's LP rewards
+	feeRouter.SetLPPool(ctx, feeroutertypes.LPPool{Address: second.String(), Name: "GXR/TON", Active: true})
+	firstBefore, secondBefore := bank.GetBalance(ctx, first, halvingkeeper.MainDenom), bank.GetBalance(ctx, second, halvingkeeper.MainDenom)
+	require.NoError(t, feeRouter.ProcessTransactionFees(ctx, fees, true))
+	require.Equal(t, int64(1250), bank.GetBalance(ctx, first, halvingkeeper.MainDenom).Sub(firstBefore).Amount.Int64())
+	require.Equal(t, int64(1250), bank.GetBalance(ctx, second, halvingkeeper.MainDenom).Sub(secondBefore).Amount.Int64())
+	chain.EndBlock()
+
+	// Reward totals are kept up to date alongside the cache
+	pool, found := feeRouter.GetLPPool(chain.Context(), first.String())
+	require.True(t, found)
+	require.Equal(t, "3750ugen", pool.TotalRewards.String())
+}
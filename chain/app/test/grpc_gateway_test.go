@@ -0,0 +1,116 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server/api"
+	"github.com/cosmos/cosmos-sdk/server/config"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	denylisttypes "github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// newAPIServer registers the app's routes on an API server whose gateway
+// queries the node's gRPC server, wired as the node does on start
+func newAPIServer(t *testing.T, chain *TestChain) *api.Server {
+	t.Helper()
+	conn := dialNodeGRPC(t, chain, grpc.WithDefaultCallOptions(
+		grpc.ForceCodec(codec.NewProtoCodec(chain.Encoding.InterfaceRegistry).GRPCCodec()),
+	))
+	clientCtx := client.Context{}.
+		WithCodec(chain.Encoding.Codec).
+		WithInterfaceRegistry(chain.Encoding.InterfaceRegistry).
+		WithTxConfig(chain.Encoding.TxConfig).
+		WithChainID(ChainID).
+		WithGRPCClient(conn)
+
+	apiSrv := api.New(clientCtx, log.NewNopLogger(), nil)
+	chain.App.RegisterAPIRoutes(apiSrv, config.DefaultConfig().API)
+	// Start mounts the gateway behind the routes registered by the app
+	apiSrv.Router.PathPrefix("/").Handler(apiSrv.GRPCGatewayRouter)
+	return apiSrv
+}
+
+// getJSON sends a GET to the API server and returns the status and body
+func getJSON(apiSrv *api.Server, url string) (int, []byte) {
+	rec := httptest.NewRecorder()
+	apiSrv.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	return rec.Code, rec.Body.Bytes()
+}
+
+func TestGRPCGatewayServesCustomQueries(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+	denied := denylisttypes.DeniedAddress{Address: retiredAllocation.String(), Reason: "retired presale allocation"}
+
+	chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime), deniedAtGenesis(denied))
+	chain.SignBlocks = true
+	chain.NextBlock(DefaultBlockTime)
+
+	apiSrv := newAPIServer(t, chain)
+	cdc := chain.Encoding.Codec
+	ctx := chain.Context()
+
+	t.Run("halving params", func(t *testing.T) {
+		code, body := getJSON(apiSrv, "/gxr/halving/v1beta1/params")
+		require.Equal(t, http.StatusOK, code, string(body))
+		var res halvingtypes.QueryParamsResponse
+		require.NoError(t, cdc.UnmarshalJSON(body, &res))
+		expected := chain.App.HalvingKeeper.GetParams(ctx)
+		require.Equal(t, string(cdc.MustMarshalJSON(&expected)), string(cdc.MustMarshalJSON(&res.Params)))
+	})
+
+	t.Run("feerouter params", func(t *testing.T) {
+		code, body := getJSON(apiSrv, "/gxr/feerouter/v1beta1/params")
+		require.Equal(t, http.StatusOK, code, string(body))
+		var res feeroutertypes.QueryParamsResponse
+		require.NoError(t, cdc.UnmarshalJSON(body, &res))
+		expected := chain.App.FeeRouterKeeper.GetParams(ctx)
+		require.Equal(t, string(cdc.MustMarshalJSON(&expected)), string(cdc.MustMarshalJSON(&res.Params)))
+	})
+
+	t.Run("denied address path parameter", func(t *testing.T) {
+		code, body := getJSON(apiSrv, "/gxr/denylist/v1beta1/denied_addresses/"+retiredAllocation.String())
+		require.Equal(t, http.StatusOK, code, string(body))
+		var res denylisttypes.QueryDeniedAddressResponse
+		require.NoError(t, cdc.UnmarshalJSON(body, &res))
+		require.True(t, res.Denied)
+		require.Equal(t, denied.Reason, res.DeniedAddress.Reason)
+	})
+
+	t.Run("reward estimate query parameter", func(t *testing.T) {
+		delegation := sdk.NewInt(3_000_000)
+		expected, err := chain.App.HalvingKeeper.RewardEstimate(sdk.WrapSDKContext(ctx), &halvingtypes.QueryRewardEstimateRequest{
+			ValidatorAddress:     chain.Validator.String(),
+			AdditionalDelegation: delegation,
+		})
+		require.NoError(t, err)
+
+		code, body := getJSON(apiSrv, "/gxr/halving/v1beta1/reward_estimate/"+chain.Validator.String()+
+			"?additional_delegation="+delegation.String())
+		require.Equal(t, http.StatusOK, code, string(body))
+		var res halvingtypes.QueryRewardEstimateResponse
+		require.NoError(t, cdc.UnmarshalJSON(body, &res))
+		require.Equal(t, string(cdc.MustMarshalJSON(expected)), string(cdc.MustMarshalJSON(&res)))
+
+		code, body = getJSON(apiSrv, "/gxr/halving/v1beta1/reward_estimate/"+chain.Validator.String()+
+			"?additional_delegation=lots")
+		require.Equal(t, http.StatusBadRequest, code, string(body))
+	})
+}
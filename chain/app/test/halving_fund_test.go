@@ -0,0 +1,97 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// halvingReserve is the halving fund allocated to the module account at genesis
+const halvingReserve = 21_250_000 * 1e8
+
+// firstHalvingCycle starts cycle 1 at genesis with the default first-cycle fund
+func firstHalvingCycle(genesisTime time.Time) GenesisModifier {
+	return func(encoding app.EncodingConfig, genesis app.GenesisState) app.GenesisState {
+		halvingGenesis := halvingtypes.DefaultGenesisState()
+		halvingGenesis.HalvingInfo = halvingtypes.DefaultHalvingInfo()
+		halvingGenesis.HalvingInfo.CycleStartTime = genesisTime.Unix()
+		halvingGenesis.HalvingInfo.DistributionActive = true
+		halvingGenesis.HalvingInfo.DistributionStart = genesisTime.Unix()
+		genesis[halvingtypes.ModuleName] = encoding.Codec.MustMarshalJSON(halvingGenesis)
+		return genesis
+	}
+}
+
+func TestHalvingFundSeriesAcrossCycles(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	chain := Setup(t, genesisTime, 2, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingReserve)),
+	}}, firstHalvingCycle(genesisTime))
+
+	// The fund of each cycle in ugen, as in the tokenomics: 4,250,000 GXR
+	// for cycle 1, then 15% less every cycle
+	expected := []int64{
+		425_000_000_000_000,
+		361_250_000_000_000,
+		307_062_500_000_000,
+		261_003_125_000_000,
+		221_852_656_250_000,
+	}
+
+	var total int64
+	for _, fund := range expected {
+		total += fund
+	}
+	require.Less(t, total, int64(halvingReserve), "the reserve covers every cycle")
+
+	// A distribution in cycle 1 does not change the funds of later cycles
+	chain.NextBlock(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Sub(chain.Time))
+	info, found := chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+	require.True(t, found)
+	require.Equal(t, sdk.NewInt(expected[0]).QuoRaw(halvingtypes.DistributionMonths), info.DistributedAmount.Amount)
+
+	for i, fund := range expected {
+		info, found := chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+		require.True(t, found)
+		require.Equal(t, uint64(i+1), info.CurrentCycle)
+		require.Equal(t, fund, info.CycleFund().Amount.Int64(), "cycle %d", info.CurrentCycle)
+
+		// Cycle funds come from the reserve, no tokens are minted for them
+		chain.AssertSupplyInvariant()
+
+		if i+1 < len(expected) {
+			cycleEnd := time.Unix(info.CycleStartTime, 0).Add(halvingkeeper.HalvingCycleDuration)
+			chain.NextBlock(cycleEnd.Sub(chain.Time))
+		}
+	}
+}
+
+func TestHalvingFundCappedAtReserve(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	// The reserve holds less than cycle 2 would be funded with
+	reserve := int64(300_000_000_000_000)
+	chain := Setup(t, genesisTime, 2, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, reserve)),
+	}}, firstHalvingCycle(genesisTime))
+
+	chain.NextBlock(halvingkeeper.HalvingCycleDuration)
+
+	info, found := chain.App.HalvingKeeper.GetHalvingInfo(chain.Context())
+	require.True(t, found)
+	require.Equal(t, uint64(2), info.CurrentCycle)
+	require.Equal(t, reserve, info.HalvingFund.Amount.Int64())
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop"
+	airdroptypes "github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+	"github.com/Crocodile-ark/gxrchaind/x/denylist"
+	denylisttypes "github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter"
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	"github.com/Crocodile-ark/gxrchaind/x/halving"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// The custom modules implement the module interfaces the manager dispatches
+// on; a legacy method signature fails the build here
+var (
+	_ module.HasGenesis          = halving.AppModule{}
+	_ module.HasServices         = halving.AppModule{}
+	_ module.HasConsensusVersion = halving.AppModule{}
+	_ module.BeginBlockAppModule = halving.AppModule{}
+	_ module.EndBlockAppModule   = halving.AppModule{}
+
+	_ module.HasGenesis          = feerouter.AppModule{}
+	_ module.HasServices         = feerouter.AppModule{}
+	_ module.HasConsensusVersion = feerouter.AppModule{}
+	_ module.BeginBlockAppModule = feerouter.AppModule{}
+	_ module.EndBlockAppModule   = feerouter.AppModule{}
+
+	_ module.HasGenesis          = denylist.AppModule{}
+	_ module.HasServices         = denylist.AppModule{}
+	_ module.HasConsensusVersion = denylist.AppModule{}
+	_ module.BeginBlockAppModule = denylist.AppModule{}
+	_ module.EndBlockAppModule   = denylist.AppModule{}
+
+	_ module.HasGenesis          = airdrop.AppModule{}
+	_ module.HasServices         = airdrop.AppModule{}
+	_ module.HasConsensusVersion = airdrop.AppModule{}
+	_ module.BeginBlockAppModule = airdrop.AppModule{}
+	_ module.EndBlockAppModule   = airdrop.AppModule{}
+)
+
+func TestCustomModuleServicesRegistered(t *testing.T) {
+	chain := Setup(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 1, nil)
+
+	// Messages are routed by the Msg services from RegisterServices, there is
+	// no legacy router
+	for _, msg := range []sdk.Msg{
+		&halvingtypes.MsgDeclareDowntime{},
+		&halvingtypes.MsgWithdrawDexReserve{},
+		&halvingtypes.MsgRegisterBotHeartbeat{},
+		&halvingtypes.MsgSetBotMetadata{},
+		&halvingtypes.MsgUpdateHalvingParams{},
+		&feeroutertypes.MsgRegisterLPPool{},
+		&feeroutertypes.MsgUpdateParams{},
+		&denylisttypes.MsgUpdateDenylist{},
+		&airdroptypes.MsgSetClaimRoot{},
+		&airdroptypes.MsgClaimAirdrop{},
+	} {
+		require.NotNil(t, chain.App.MsgServiceRouter().Handler(msg), "no handler for %s", sdk.MsgTypeURL(msg))
+	}
+
+	for _, service := range []grpc.ServiceDesc{
+		halvingtypes.Query_ServiceDesc,
+		feeroutertypes.Query_ServiceDesc,
+		denylisttypes.Query_ServiceDesc,
+		airdroptypes.Query_ServiceDesc,
+	} {
+		for _, method := range service.Methods {
+			route := "/" + service.ServiceName + "/" + method.MethodName
+			require.NotNil(t, chain.App.GRPCQueryRouter().Route(route), "no query route %s", route)
+		}
+	}
+}
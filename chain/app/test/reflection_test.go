@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	reflectionv1 "cosmossdk.io/api/cosmos/reflection/v1"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/server/config"
+	servergrpc "github.com/cosmos/cosmos-sdk/server/grpc"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// dialNodeGRPC serves the app on the node's gRPC server, with its reflection
+// services, over an in-memory listener
+func dialNodeGRPC(t *testing.T, chain *TestChain, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	clientCtx := client.Context{}.
+		WithInterfaceRegistry(chain.Encoding.InterfaceRegistry).
+		WithTxConfig(chain.Encoding.TxConfig).
+		WithChainID(ChainID)
+	grpcSrv, err := servergrpc.NewGRPCServer(clientCtx, chain.App, config.DefaultConfig().GRPC)
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = grpcSrv.Serve(listener) }()
+	t.Cleanup(grpcSrv.Stop)
+
+	conn, err := grpc.Dial("bufnet", append([]grpc.DialOption{
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestReflectionListsCustomQueryServices(t *testing.T) {
+	chain := Setup(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 1, nil)
+	conn := dialNodeGRPC(t, chain)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	rc := grpcreflect.NewClientAuto(ctx, conn)
+	defer rc.Reset()
+	services, err := rc.ListServices()
+	require.NoError(t, err)
+
+	for _, service := range []grpc.ServiceDesc{
+		halvingtypes.Query_ServiceDesc,
+		feeroutertypes.Query_ServiceDesc,
+	} {
+		require.Contains(t, services, service.ServiceName)
+
+		// The service resolves with all of its methods from the registered descriptors
+		sd, err := rc.ResolveService(service.ServiceName)
+		require.NoError(t, err)
+		var methods []string
+		for _, method := range sd.GetMethods() {
+			methods = append(methods, method.GetName())
+		}
+		var want []string
+		for _, method := range service.Methods {
+			want = append(want, method.MethodName)
+		}
+		require.ElementsMatch(t, want, methods, "methods of %s", service.ServiceName)
+	}
+
+	// cosmos.reflection.v1 hands out the module's proto files as well
+	files, err := reflectionv1.NewReflectionServiceClient(conn).FileDescriptors(ctx, &reflectionv1.FileDescriptorsRequest{})
+	require.NoError(t, err)
+	names := make(map[string]bool)
+	for _, file := range files.Files {
+		names[file.GetName()] = true
+	}
+	require.True(t, names[halvingtypes.Query_ServiceDesc.Metadata.(string)], "halving query.proto not in reflection")
+	require.True(t, names[feeroutertypes.Query_ServiceDesc.Metadata.(string)], "feerouter query.proto not in reflection")
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// withCommission sets the commission rate of the genesis validators
+func withCommission(rate sdk.Dec) GenesisModifier {
+	return func(encoding app.EncodingConfig, genesis app.GenesisState) app.GenesisState {
+		var stakingGenesis stakingtypes.GenesisState
+		encoding.Codec.MustUnmarshalJSON(genesis[stakingtypes.ModuleName], &stakingGenesis)
+		for i := range stakingGenesis.Validators {
+			stakingGenesis.Validators[i].Commission = stakingtypes.NewCommission(rate, sdk.OneDec(), sdk.OneDec())
+		}
+		genesis[stakingtypes.ModuleName] = encoding.Codec.MustMarshalJSON(&stakingGenesis)
+		return genesis
+	}
+}
+
+func TestRewardEstimateMatchesDistribution(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+	commissionRate := sdk.MustNewDecFromStr("0.10")
+
+	chain := Setup(t, genesisTime, 2, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime), withCommission(commissionRate))
+	chain.SignBlocks = true
+
+	validatorAcc := sdk.AccAddress(chain.Validator)
+	delegator := chain.Accounts[1]
+	delegation := sdk.NewInt64Coin(halvingkeeper.MainDenom, 3_000_000)
+
+	// Month 1 is due from genesis and the validator is snapshotted as eligible
+	chain.NextBlock(DefaultBlockTime)
+
+	res, err := chain.App.HalvingKeeper.RewardEstimate(sdk.WrapSDKContext(chain.Context()), &halvingtypes.QueryRewardEstimateRequest{
+		ValidatorAddress:     chain.Validator.String(),
+		AdditionalDelegation: delegation.Amount,
+	})
+	require.NoError(t, err)
+	estimate := res.RewardEstimate
+
+	// The estimate is labeled as one and echoes its inputs and assumptions
+	require.True(t, estimate.Estimate)
+	require.NotEmpty(t, estimate.Assumptions)
+	require.Equal(t, uint64(1), estimate.DistributionMonth)
+	require.Equal(t, uint64(halvingtypes.DistributionMonths), estimate.MonthsRemaining)
+	require.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Unix(), estimate.DistributionTime)
+	require.Equal(t, sdk.NewInt(halvingFund).QuoRaw(24), estimate.MonthlyAmount.Amount)
+	require.True(t, estimate.DexWindowOpen)
+	require.True(t, estimate.ValidatorEligible)
+	require.Equal(t, uint64(1), estimate.EligibleValidators)
+	require.Equal(t, commissionRate, estimate.CommissionRate)
+	require.Equal(t, chain.App.DistrKeeper.GetCommunityTax(chain.Context()), estimate.CommunityTax)
+	require.Equal(t, delegation, estimate.AdditionalDelegation)
+	require.True(t, estimate.DelegationReward.IsPositive())
+
+	// Delegate the amount the estimate was made for
+	chain.BeginBlock(DefaultBlockTime)
+	txRes := chain.SendTx(delegator, sdk.NewCoins(), stakingtypes.NewMsgDelegate(delegator.Address, chain.Validator, delegation))
+	chain.EndBlock()
+	require.Zero(t, txRes.Code, txRes.Log)
+
+	validatorBefore := chain.Balance(validatorAcc)
+
+	// Nothing else changes until the distribution day
+	chain.NextBlock(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Sub(chain.Time))
+	require.True(t, chain.HasEvent(banktypes.EventTypeCoinBurn, banktypes.AttributeKeyBurner, halvingAddr.String()))
+
+	ctx, _ := chain.Context().CacheContext()
+	require.Equal(t, validatorBefore.Add(estimate.ValidatorReward.Amount), chain.Balance(validatorAcc))
+
+	commission := chain.App.DistrKeeper.GetValidatorAccumulatedCommission(ctx, chain.Validator).Commission
+	require.Equal(t, estimate.ValidatorCommission.Amount, commission.AmountOf(halvingkeeper.MainDenom).TruncateInt())
+
+	validator := chain.App.StakingKeeper.Validator(ctx, chain.Validator)
+	bonded := chain.App.StakingKeeper.Delegation(ctx, delegator.Address, chain.Validator)
+	endingPeriod := chain.App.DistrKeeper.IncrementValidatorPeriod(ctx, validator)
+	rewards := chain.App.DistrKeeper.CalculateDelegationRewards(ctx, validator, bonded, endingPeriod)
+	require.Equal(t, estimate.DelegationReward.Amount, rewards.AmountOf(halvingkeeper.MainDenom).TruncateInt())
+	chain.AssertSupplyInvariant()
+
+	// Once the validator is over the inactivity threshold it is estimated to
+	// forfeit the next month's validator reward
+	setValidatorInactiveDays(chain, DefaultBlockTime, halvingkeeper.ValidatorInactiveThreshold+1)
+	next, err := chain.App.HalvingKeeper.EstimateReward(chain.Context(), chain.Validator, sdk.ZeroInt())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), next.DistributionMonth)
+	require.False(t, next.ValidatorEligible)
+	require.True(t, next.ValidatorReward.IsZero())
+	require.True(t, next.DelegationReward.IsZero())
+	require.True(t, next.DelegatorsReward.IsPositive())
+}
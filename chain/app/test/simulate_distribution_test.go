@@ -0,0 +1,201 @@
+package test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// requireDistributionMatches runs the real distribution on ctx and requires
+// it to pay exactly what the simulation on the same context returned
+func requireDistributionMatches(t *testing.T, chain *TestChain, ctx sdk.Context, simulation halvingtypes.DistributionSimulation) {
+	t.Helper()
+	k := chain.App.HalvingKeeper
+	feeCollector := authtypes.NewModuleAddress(authtypes.FeeCollectorName)
+	balance := func(addr sdk.AccAddress) sdk.Int {
+		return chain.App.BankKeeper.GetBalance(ctx, addr, halvingkeeper.MainDenom).Amount
+	}
+
+	payouts := make(map[string]sdk.Int)
+	for _, payout := range simulation.ValidatorPayouts {
+		payouts[payout.AccountAddress] = payout.Amount.Amount
+	}
+	validatorAcc := sdk.AccAddress(chain.Validator)
+	validatorBefore := balance(validatorAcc)
+	feesBefore := balance(feeCollector)
+	dexBefore := k.GetPendingDexAllocation(ctx, simulation.Cycle).Amount
+
+	require.NoError(t, k.DistributeHalvingRewards(ctx))
+
+	expected, found := payouts[validatorAcc.String()]
+	if !found {
+		expected = sdk.ZeroInt()
+	}
+	require.Equal(t, validatorBefore.Add(expected), balance(validatorAcc))
+	require.Equal(t, feesBefore.Add(simulation.Delegators.Amount), balance(feeCollector))
+	require.Equal(t, dexBefore.Add(simulation.Dex), k.GetPendingDexAllocation(ctx, simulation.Cycle).Amount)
+
+	info, found := k.GetHalvingInfo(ctx)
+	require.True(t, found)
+	require.Equal(t, simulation.RemainingFund, info.HalvingFund)
+	require.Equal(t, simulation.DistributionMonth, info.MonthsDistributed)
+	record, found := k.GetDistributionRecord(ctx, ctx.BlockTime().Unix())
+	require.True(t, found)
+	require.Equal(t, simulation.MonthlyAmount, record.Amount)
+	require.Equal(t, simulation.CatchUp, record.CatchUp)
+}
+
+func TestSimulateDistributionMatchesDistribution(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime))
+	k := chain.App.HalvingKeeper
+
+	// Month 1 is due from genesis and its eligibility snapshot is taken
+	chain.NextBlock(DefaultBlockTime)
+	infoBefore, _ := k.GetHalvingInfo(chain.Context())
+
+	// Simulate and distribute in the block context of the distribution day
+	ctx, _ := chain.Context().CacheContext()
+	ctx = ctx.WithBlockTime(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)).WithBlockHeight(chain.Height + 1)
+
+	simulation := k.SimulateNextDistribution(ctx)
+	require.True(t, simulation.Due)
+	require.False(t, simulation.CatchUp)
+	require.True(t, simulation.EligibilitySnapshotted)
+	require.Equal(t, uint64(1), simulation.DistributionMonth)
+	require.Equal(t, sdk.NewInt(halvingFund).QuoRaw(24), simulation.MonthlyAmount.Amount)
+	require.Equal(t, simulation.MonthlyAmount, simulation.Validators.Add(simulation.Delegators).Add(simulation.Dex).Add(simulation.Redirected))
+	require.Len(t, simulation.ValidatorPayouts, 1)
+	require.Equal(t, chain.Validator.String(), simulation.ValidatorPayouts[0].ValidatorAddress)
+	require.Empty(t, simulation.Exclusions)
+
+	// The simulation wrote nothing and emitted nothing
+	require.Empty(t, ctx.EventManager().Events())
+	infoAfter, _ := k.GetHalvingInfo(ctx)
+	require.Equal(t, infoBefore, infoAfter)
+
+	requireDistributionMatches(t, chain, ctx, simulation)
+
+	// The next month is not due yet
+	next := k.SimulateNextDistribution(ctx.WithBlockTime(ctx.BlockTime().Add(24 * time.Hour)))
+	require.False(t, next.Due)
+	require.Equal(t, uint64(2), next.DistributionMonth)
+
+	// Month 2 becomes due while the validator is over the inactivity
+	// threshold. Its snapshot is not taken yet: the simulation evaluates
+	// eligibility on a discarded branch, the distribution takes the snapshot.
+	ctx = ctx.WithBlockTime(genesisTime.Add(halvingkeeper.MonthDuration))
+	k.SetValidatorUptime(ctx, chain.Validator, halvingtypes.ValidatorUptime{
+		ValidatorAddress: chain.Validator.String(),
+		CurrentMonth:     uint64(ctx.BlockTime().Unix() / int64(halvingkeeper.MonthDuration.Seconds())),
+		InactiveDays:     halvingkeeper.ValidatorInactiveThreshold + 1,
+		LastCheck:        ctx.BlockTime().Unix(),
+	})
+
+	simulation = k.SimulateNextDistribution(ctx)
+	require.True(t, simulation.Due)
+	require.False(t, simulation.EligibilitySnapshotted)
+	require.Empty(t, simulation.ValidatorPayouts)
+	require.Equal(t, []halvingtypes.DistributionExclusion{{
+		ValidatorAddress: chain.Validator.String(),
+		Reason:           halvingkeeper.ExclusionReasonInactive,
+		InactiveDays:     sdk.NewDec(halvingkeeper.ValidatorInactiveThreshold + 1),
+	}}, simulation.Exclusions)
+	require.Equal(t, simulation.Validators, simulation.ValidatorsForfeited)
+	require.Empty(t, k.GetEligibilitySnapshots(ctx, 1, 2))
+
+	requireDistributionMatches(t, chain, ctx, simulation)
+	require.Len(t, k.GetEligibilitySnapshots(ctx, 1, 2), 1)
+}
+
+func TestDistributionPaysParamShares(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime))
+	k := chain.App.HalvingKeeper
+	chain.NextBlock(DefaultBlockTime)
+
+	ctx, _ := chain.Context().CacheContext()
+	ctx = ctx.WithBlockTime(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)).WithBlockHeight(chain.Height + 1)
+
+	// Governance moves the shares away from the 70/10/20 default
+	params := k.GetParams(ctx)
+	params.ValidatorShare = sdk.MustNewDecFromStr("0.50")
+	params.DexShare = sdk.MustNewDecFromStr("0.15")
+	params.DelegatorShare = sdk.MustNewDecFromStr("0.35")
+	require.NoError(t, params.Validate())
+	k.SetParams(ctx, params)
+
+	feeCollector := authtypes.NewModuleAddress(authtypes.FeeCollectorName)
+	validatorAcc := sdk.AccAddress(chain.Validator)
+	balance := func(addr sdk.AccAddress) sdk.Int {
+		return chain.App.BankKeeper.GetBalance(ctx, addr, halvingkeeper.MainDenom).Amount
+	}
+	validatorBefore, feesBefore := balance(validatorAcc), balance(feeCollector)
+	dexBefore := k.GetPendingDexAllocation(ctx, 1).Amount.Amount
+
+	require.NoError(t, k.DistributeHalvingRewards(ctx))
+
+	monthly := sdk.NewInt(halvingFund).QuoRaw(24)
+	require.Equal(t, monthly.MulRaw(50).QuoRaw(100), balance(validatorAcc).Sub(validatorBefore))
+	require.Equal(t, monthly.MulRaw(35).QuoRaw(100), balance(feeCollector).Sub(feesBefore))
+	require.Equal(t, monthly.MulRaw(15).QuoRaw(100), k.GetPendingDexAllocation(ctx, 1).Amount.Amount.Sub(dexBefore))
+}
+
+func TestQuerySimulateDistributionConcurrent(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	chain := Setup(t, genesisTime, 1, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime))
+	chain.NextBlock(DefaultBlockTime)
+
+	// Queries run in parallel on their own branch of the committed state, as
+	// the node serves them
+	const queries = 8
+	results := make([]*halvingtypes.QuerySimulateDistributionResponse, queries)
+	errs := make([]error, queries)
+	var wg sync.WaitGroup
+	for i := 0; i < queries; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ms, err := chain.App.CommitMultiStore().CacheMultiStoreWithVersion(chain.Height)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			ctx := sdk.NewContext(ms, chain.header(), true, log.NewNopLogger())
+			results[i], errs[i] = chain.App.HalvingKeeper.SimulateDistribution(sdk.WrapSDKContext(ctx), &halvingtypes.QuerySimulateDistributionRequest{})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < queries; i++ {
+		require.NoError(t, errs[i])
+		require.Equal(t, results[0].Simulation.ValidatorPayouts, results[i].Simulation.ValidatorPayouts)
+		require.Equal(t, results[0].Simulation.Delegators, results[i].Simulation.Delegators)
+	}
+	require.True(t, results[0].Simulation.MonthlyAmount.IsPositive())
+	require.Len(t, results[0].Simulation.ValidatorPayouts, 1)
+}
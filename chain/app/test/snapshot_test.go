@@ -0,0 +1,186 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/snapshots"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/app"
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// snapshotOptions configures a snapshot store taking a snapshot every block,
+// as the node does from the state-sync flags
+func snapshotOptions(t *testing.T) []func(*baseapp.BaseApp) {
+	t.Helper()
+
+	store, err := snapshots.NewStore(dbm.NewMemDB(), t.TempDir())
+	require.NoError(t, err)
+
+	return []func(*baseapp.BaseApp){
+		baseapp.SetSnapshotStore(store),
+		baseapp.SetSnapshotInterval(1),
+		baseapp.SetSnapshotKeepRecent(2),
+	}
+}
+
+// waitForSnapshot waits for the snapshot of height, which is taken in the background after commit
+func waitForSnapshot(t *testing.T, gxrApp *app.GXRApp, height int64) *abci.Snapshot {
+	t.Helper()
+
+	var snapshot *abci.Snapshot
+	require.Eventually(t, func() bool {
+		for _, s := range gxrApp.ListSnapshots(abci.RequestListSnapshots{}).Snapshots {
+			if s.Height == uint64(height) {
+				snapshot = s
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second, 10*time.Millisecond, "no snapshot at height %d", height)
+
+	return snapshot
+}
+
+// storeContents returns every key/value pair of a committed module store
+func storeContents(t *testing.T, gxrApp *app.GXRApp, storeKey string) [][2][]byte {
+	t.Helper()
+
+	store := gxrApp.CommitMultiStore().GetCommitKVStore(gxrApp.GetKey(storeKey))
+	require.NotNil(t, store, storeKey)
+
+	iter := store.Iterator(nil, nil)
+	defer iter.Close()
+
+	var contents [][2][]byte
+	for ; iter.Valid(); iter.Next() {
+		contents = append(contents, [2][]byte{bytes.Clone(iter.Key()), bytes.Clone(iter.Value())})
+	}
+	return contents
+}
+
+func TestStateSyncRestoresCustomModuleStores(t *testing.T) {
+	genesisTime := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	halvingAddr := authtypes.NewModuleAddress(halvingtypes.ModuleName)
+
+	source := SetupWithBaseAppOptions(t, snapshotOptions(t), genesisTime, 2, []banktypes.Balance{{
+		Address: halvingAddr.String(),
+		Coins:   sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, halvingFund)),
+	}}, activeHalvingCycle(genesisTime))
+
+	// Build up state in both custom modules: a monthly distribution record,
+	// uptime and downtime records, LP pools and fee totals
+	source.NextBlock(DefaultBlockTime)
+	source.NextBlock(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC).Sub(source.Time))
+
+	source.BeginBlock(DefaultBlockTime)
+	ctx := source.DeliverContext()
+	month := uint64(source.Time.Unix() / int64(halvingkeeper.MonthDuration.Seconds()))
+	source.App.HalvingKeeper.SetValidatorUptime(ctx, source.Validator, halvingtypes.ValidatorUptime{
+		ValidatorAddress: source.Validator.String(),
+		CurrentMonth:     month,
+		InactiveDays:     2,
+		LastCheck:        source.Time.Unix(),
+	})
+	source.App.HalvingKeeper.SetDowntimeDeclaration(ctx, source.Validator, halvingtypes.DowntimeDeclaration{
+		ValidatorAddress: source.Validator.String(),
+		Start:            source.Time.Add(24 * time.Hour).Unix(),
+		End:              source.Time.Add(48 * time.Hour).Unix(),
+		Reason:           "datacenter migration",
+		Month:            month,
+		Days:             1,
+		DeclaredAt:       source.Time.Unix(),
+	})
+	source.App.FeeRouterKeeper.SetLPPool(ctx, feeroutertypes.LPPool{
+		Address:      source.Accounts[1].Address.String(),
+		Name:         "GXR/USDC",
+		Active:       true,
+		TotalRewards: sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 7_500)),
+	})
+	source.App.FeeRouterKeeper.AddValidatorFeeTotal(ctx, source.Validator.String(),
+		sdk.NewCoins(sdk.NewInt64Coin(halvingkeeper.MainDenom, 12_000)))
+	source.EndBlock()
+
+	require.NotEmpty(t, source.App.HalvingKeeper.GetAllDistributionRecords(source.Context()))
+	snapshot := waitForSnapshot(t, source.App, source.Height)
+
+	// Restore the snapshot into a fresh node, as state sync does
+	target := NewApp(t, dbm.NewMemDB(), source.Encoding, snapshotOptions(t)...)
+
+	offer := target.OfferSnapshot(abci.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  source.App.LastCommitID().Hash,
+	})
+	require.Equal(t, abci.ResponseOfferSnapshot_ACCEPT, offer.Result)
+
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		chunk := source.App.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunk:  i,
+		})
+		require.NotEmpty(t, chunk.Chunk)
+
+		applied := target.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: i, Chunk: chunk.Chunk})
+		require.Equal(t, abci.ResponseApplySnapshotChunk_ACCEPT, applied.Result, "chunk %d", i)
+	}
+
+	require.NoError(t, target.LoadLatestVersion())
+	require.Equal(t, source.App.LastCommitID(), target.LastCommitID())
+
+	// The custom module stores are restored byte for byte
+	for _, storeKey := range []string{halvingtypes.StoreKey, feeroutertypes.StoreKey} {
+		contents := storeContents(t, source.App, storeKey)
+		require.NotEmpty(t, contents, storeKey)
+		require.Equal(t, contents, storeContents(t, target, storeKey), storeKey)
+	}
+
+	// and the restored node answers queries like the archival one
+	header := source.header()
+	sourceCtx := sdk.WrapSDKContext(source.App.BaseApp.NewContext(true, header))
+	targetCtx := sdk.WrapSDKContext(target.BaseApp.NewContext(true, header))
+
+	sourceInfo, err := source.App.HalvingKeeper.HalvingInfo(sourceCtx, &halvingtypes.QueryHalvingInfoRequest{})
+	require.NoError(t, err)
+	targetInfo, err := target.HalvingKeeper.HalvingInfo(targetCtx, &halvingtypes.QueryHalvingInfoRequest{})
+	require.NoError(t, err)
+	require.Equal(t, sourceInfo, targetInfo)
+
+	sourceHistory, err := source.App.HalvingKeeper.DistributionHistory(sourceCtx, &halvingtypes.QueryDistributionHistoryRequest{})
+	require.NoError(t, err)
+	targetHistory, err := target.HalvingKeeper.DistributionHistory(targetCtx, &halvingtypes.QueryDistributionHistoryRequest{})
+	require.NoError(t, err)
+	require.Equal(t, sourceHistory, targetHistory)
+
+	uptimeReq := &halvingtypes.QueryValidatorUptimeRequest{ValidatorAddress: source.Validator.String()}
+	sourceUptime, err := source.App.HalvingKeeper.ValidatorUptime(sourceCtx, uptimeReq)
+	require.NoError(t, err)
+	targetUptime, err := target.HalvingKeeper.ValidatorUptime(targetCtx, uptimeReq)
+	require.NoError(t, err)
+	require.Equal(t, sourceUptime, targetUptime)
+
+	sourcePools, err := source.App.FeeRouterKeeper.LPPools(sourceCtx, &feeroutertypes.QueryLPPoolsRequest{})
+	require.NoError(t, err)
+	targetPools, err := target.FeeRouterKeeper.LPPools(targetCtx, &feeroutertypes.QueryLPPoolsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, sourcePools, targetPools)
+
+	// Params live in the params store and are restored with it
+	require.Equal(t,
+		source.App.HalvingKeeper.GetParams(source.App.BaseApp.NewContext(true, header)),
+		target.HalvingKeeper.GetParams(target.BaseApp.NewContext(true, header)))
+	require.Equal(t,
+		source.App.FeeRouterKeeper.GetParams(source.App.BaseApp.NewContext(true, header)),
+		target.FeeRouterKeeper.GetParams(target.BaseApp.NewContext(true, header)))
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+
+	feerouterkeeper "github.com/Crocodile-ark/gxrchaind/x/feerouter/keeper"
+	halvingkeeper "github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+)
+
+// ModuleParamsUpgrade moves the halving and feerouter params from their
+// x/params subspaces to the module stores. The subspaces keep a copy, kept in
+// sync by the keepers, until a later upgrade deletes it.
+const ModuleParamsUpgrade = "v2-module-params"
+
+// BotLivenessUpgrade adds the halving bot_heartbeat_timeout_blocks param and
+// starts tracking validator bot liveness from MsgRegisterBotHeartbeat
+const BotLivenessUpgrade = "v3-bot-liveness"
+
+// DistributionCeilingUpgrade adds the halving monthly distribution ceiling
+// params, a circuit breaker on what a single month may pay
+const DistributionCeilingUpgrade = "v4-distribution-ceiling"
+
+// DexDistributionAccountUpgrade moves the pending halving DEX share to the
+// dex_distribution module account
+const DexDistributionAccountUpgrade = "v5-dex-distribution-account"
+
+// WeightedValidatorRewardsUpgrade divides the halving validator share by
+// bonded tokens instead of equally; no state changes
+const WeightedValidatorRewardsUpgrade = "v6-weighted-validator-rewards"
+
+// LastDistributionTimeUpgrade moves the halving last distribution time off
+// the distribution record prefix, where it broke exports and record queries
+const LastDistributionTimeUpgrade = "v7-last-distribution-time"
+
+// DexDistributionPeriodUpgrade adds the halving dex_distribution_period param,
+// which lets governance close the DEX window before the distribution ends
+const DexDistributionPeriodUpgrade = "v8-dex-distribution-period"
+
+// registerUpgradeHandlers registers the handlers of the planned upgrades
+func (app *GXRApp) registerUpgradeHandlers() {
+	app.UpgradeKeeper.SetUpgradeHandler(ModuleParamsUpgrade, func(ctx sdk.Context, _ upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		versions, err := app.mm.RunMigrations(ctx, app.configurator, fromVM)
+		if err != nil {
+			return nil, err
+		}
+
+		// Fail the upgrade rather than run on params that differ from the ones governance set
+		for _, invariant := range []sdk.Invariant{
+			halvingkeeper.ParamsConsistencyInvariant(app.HalvingKeeper),
+			feerouterkeeper.ParamsConsistencyInvariant(app.FeeRouterKeeper),
+		} {
+			if msg, broken := invariant(ctx); broken {
+				return nil, fmt.Errorf("params migration: %s", msg)
+			}
+		}
+		return versions, nil
+	})
+
+	app.UpgradeKeeper.SetUpgradeHandler(BotLivenessUpgrade, func(ctx sdk.Context, _ upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		return app.mm.RunMigrations(ctx, app.configurator, fromVM)
+	})
+
+	app.UpgradeKeeper.SetUpgradeHandler(DistributionCeilingUpgrade, func(ctx sdk.Context, _ upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		return app.mm.RunMigrations(ctx, app.configurator, fromVM)
+	})
+
+	app.UpgradeKeeper.SetUpgradeHandler(DexDistributionAccountUpgrade, func(ctx sdk.Context, _ upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		versions, err := app.mm.RunMigrations(ctx, app.configurator, fromVM)
+		if err != nil {
+			return nil, err
+		}
+
+		// Fail the upgrade rather than leave pending DEX shares uncovered
+		if msg, broken := halvingkeeper.DexDistributionBalanceInvariant(app.HalvingKeeper)(ctx); broken {
+			return nil, fmt.Errorf("dex distribution account migration: %s", msg)
+		}
+		return versions, nil
+	})
+
+	app.UpgradeKeeper.SetUpgradeHandler(WeightedValidatorRewardsUpgrade, func(ctx sdk.Context, _ upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		return app.mm.RunMigrations(ctx, app.configurator, fromVM)
+	})
+
+	app.UpgradeKeeper.SetUpgradeHandler(LastDistributionTimeUpgrade, func(ctx sdk.Context, _ upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		return app.mm.RunMigrations(ctx, app.configurator, fromVM)
+	})
+
+	app.UpgradeKeeper.SetUpgradeHandler(DexDistributionPeriodUpgrade, func(ctx sdk.Context, _ upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		return app.mm.RunMigrations(ctx, app.configurator, fromVM)
+	})
+}
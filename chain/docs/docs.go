@@ -0,0 +1,33 @@
+// Package docs embeds the OpenAPI document of the REST routes of the GXR
+// modules. static/openapi.yml is generated from the proto query services by
+// `make proto-swagger-gen`; the modules it covers are listed in config.json.
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// OpenAPIPath is where the API server serves the GXR OpenAPI document
+const OpenAPIPath = "/swagger/gxr/openapi.yml"
+
+// OpenAPI is the generated OpenAPI document of the GXR modules
+//
+//go:embed static/openapi.yml
+var OpenAPI []byte
+
+// RegisterOpenAPIRoutes serves the GXR OpenAPI document when swagger is
+// enabled in the API config. It has to be registered before the Cosmos SDK
+// swagger UI, whose /swagger/ prefix would otherwise shadow it.
+func RegisterOpenAPIRoutes(rtr *mux.Router, swaggerEnabled bool) {
+	if !swaggerEnabled {
+		return
+	}
+
+	rtr.HandleFunc(OpenAPIPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(OpenAPI)
+	}).Methods(http.MethodGet)
+}
@@ -0,0 +1,64 @@
+package docs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Crocodile-ark/gxrchaind/docs"
+	feeroutertypes "github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+	halvingtypes "github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+type openAPIDocument struct {
+	Paths map[string]map[string]struct {
+		OperationID string `json:"operationId"`
+	} `json:"paths"`
+}
+
+func TestOpenAPICoversQueryServices(t *testing.T) {
+	var doc openAPIDocument
+	require.NoError(t, yaml.Unmarshal(docs.OpenAPI, &doc))
+
+	operations := make(map[string]bool)
+	for _, methods := range doc.Paths {
+		operations[methods["get"].OperationID] = true
+	}
+
+	// Every query method has a REST route; Params is prefixed with the module
+	for prefix, service := range map[string]grpc.ServiceDesc{
+		"Halving":   halvingtypes.Query_ServiceDesc,
+		"FeeRouter": feeroutertypes.Query_ServiceDesc,
+	} {
+		for _, method := range service.Methods {
+			operation := method.MethodName
+			if operation == "Params" {
+				operation = prefix + operation
+			}
+			require.True(t, operations[operation], "no REST route for %s/%s", service.ServiceName, method.MethodName)
+		}
+	}
+	require.Len(t, operations, len(halvingtypes.Query_ServiceDesc.Methods)+len(feeroutertypes.Query_ServiceDesc.Methods))
+}
+
+func TestRegisterOpenAPIRoutesFollowsSwaggerFlag(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		rtr := mux.NewRouter()
+		docs.RegisterOpenAPIRoutes(rtr, enabled)
+
+		rec := httptest.NewRecorder()
+		rtr.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, docs.OpenAPIPath, nil))
+		if !enabled {
+			require.Equal(t, http.StatusNotFound, rec.Code)
+			continue
+		}
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+		require.Equal(t, docs.OpenAPI, rec.Body.Bytes())
+	}
+}
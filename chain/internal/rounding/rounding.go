@@ -0,0 +1,77 @@
+// Package rounding converts decimal reward shares into integer amounts. It is
+// shared by the halving and feerouter modules so both round the same way.
+package rounding
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Strategy selects how a fractional amount is turned into an integer
+type Strategy = string
+
+const (
+	// Truncate always rounds down
+	Truncate Strategy = "truncate"
+	// Round rounds half away from zero
+	Round Strategy = "round"
+	// Bankers rounds half to even
+	Bankers Strategy = "bankers"
+)
+
+// DefaultStrategy rounds down; combined with the remainder going to the last
+// recipient every unit is still distributed
+const DefaultStrategy = Truncate
+
+// ValidateStrategy returns an error unless s is a known strategy
+func ValidateStrategy(s Strategy) error {
+	switch s {
+	case Truncate, Round, Bankers:
+		return nil
+	default:
+		return fmt.Errorf("invalid rounding strategy %q: must be one of %s, %s or %s", s, Truncate, Round, Bankers)
+	}
+}
+
+// ToInt rounds d to an integer with the given strategy. Unknown strategies
+// truncate.
+func ToInt(d sdk.Dec, s Strategy) sdk.Int {
+	switch s {
+	case Round:
+		half := sdk.NewDecWithPrec(5, 1)
+		if d.IsNegative() {
+			return d.Sub(half).Ceil().TruncateInt()
+		}
+		return d.Add(half).TruncateInt()
+	case Bankers:
+		return d.RoundInt()
+	default:
+		return d.TruncateInt()
+	}
+}
+
+// Split divides amount between recipients according to shares, in order.
+// Rounded amounts never exceed what is left, so the parts never add up to more
+// than amount. With remainderToLast the last recipient receives whatever the
+// others did not, so the parts add up to exactly amount.
+func Split(amount sdk.Int, shares []sdk.Dec, s Strategy, remainderToLast bool) []sdk.Int {
+	parts := make([]sdk.Int, len(shares))
+	left := amount
+
+	for i, share := range shares {
+		if remainderToLast && i == len(shares)-1 {
+			parts[i] = left
+			break
+		}
+
+		part := sdk.MinInt(ToInt(amount.ToDec().Mul(share), s), left)
+		if part.IsNegative() {
+			part = sdk.ZeroInt()
+		}
+		parts[i] = part
+		left = left.Sub(part)
+	}
+
+	return parts
+}
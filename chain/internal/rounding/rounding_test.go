@@ -0,0 +1,99 @@
+package rounding_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/internal/rounding"
+)
+
+func TestToInt(t *testing.T) {
+	testCases := []struct {
+		value    string
+		truncate int64
+		round    int64
+		bankers  int64
+	}{
+		{"0.0", 0, 0, 0},
+		{"0.4", 0, 0, 0},
+		{"0.5", 0, 1, 0},
+		{"1.5", 1, 2, 2},
+		{"2.5", 2, 3, 2},
+		{"2.500000000000000001", 2, 3, 3},
+		{"2.499999999999999999", 2, 2, 2},
+		{"2.6", 2, 3, 3},
+		{"-2.5", -2, -3, -2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			d := sdk.MustNewDecFromStr(tc.value)
+			require.Equal(t, tc.truncate, rounding.ToInt(d, rounding.Truncate).Int64(), "truncate")
+			require.Equal(t, tc.round, rounding.ToInt(d, rounding.Round).Int64(), "round")
+			require.Equal(t, tc.bankers, rounding.ToInt(d, rounding.Bankers).Int64(), "bankers")
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	thirds := []sdk.Dec{
+		sdk.MustNewDecFromStr("0.333333333333333333"),
+		sdk.MustNewDecFromStr("0.333333333333333333"),
+		sdk.MustNewDecFromStr("0.333333333333333334"),
+	}
+	halves := []sdk.Dec{sdk.MustNewDecFromStr("0.5"), sdk.MustNewDecFromStr("0.5")}
+	fees := []sdk.Dec{
+		sdk.MustNewDecFromStr("0.40"),
+		sdk.MustNewDecFromStr("0.30"),
+		sdk.MustNewDecFromStr("0.30"),
+	}
+
+	testCases := []struct {
+		name            string
+		amount          int64
+		shares          []sdk.Dec
+		strategy        rounding.Strategy
+		remainderToLast bool
+		exp             []int64
+	}{
+		{"truncate leaves dust", 10, thirds, rounding.Truncate, false, []int64{3, 3, 3}},
+		{"truncate remainder to last", 10, thirds, rounding.Truncate, true, []int64{3, 3, 4}},
+		{"round leaves dust", 11, thirds, rounding.Round, false, []int64{4, 4, 3}},
+		{"round remainder to last", 11, thirds, rounding.Round, true, []int64{4, 4, 3}},
+		{"round never exceeds amount", 1, halves, rounding.Round, false, []int64{1, 0}},
+		{"bankers half to even", 5, halves, rounding.Bankers, false, []int64{2, 2}},
+		{"bankers remainder to last", 5, halves, rounding.Bankers, true, []int64{2, 3}},
+		{"fee split of one unit", 1, fees, rounding.Truncate, true, []int64{0, 0, 1}},
+		{"fee split of odd amount", 7, fees, rounding.Round, true, []int64{3, 2, 2}},
+		{"fee split of odd amount bankers", 7, fees, rounding.Bankers, false, []int64{3, 2, 2}},
+		{"zero amount", 0, fees, rounding.Round, true, []int64{0, 0, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parts := rounding.Split(sdk.NewInt(tc.amount), tc.shares, tc.strategy, tc.remainderToLast)
+			require.Len(t, parts, len(tc.exp))
+
+			total := sdk.ZeroInt()
+			for i, part := range parts {
+				require.Equal(t, tc.exp[i], part.Int64(), "part %d", i)
+				total = total.Add(part)
+			}
+
+			require.True(t, total.LTE(sdk.NewInt(tc.amount)))
+			if tc.remainderToLast {
+				require.Equal(t, tc.amount, total.Int64())
+			}
+		})
+	}
+}
+
+func TestValidateStrategy(t *testing.T) {
+	for _, s := range []rounding.Strategy{rounding.Truncate, rounding.Round, rounding.Bankers} {
+		require.NoError(t, rounding.ValidateStrategy(s))
+	}
+	require.Error(t, rounding.ValidateStrategy(""))
+	require.Error(t, rounding.ValidateStrategy("ceil"))
+}
@@ -0,0 +1,26 @@
+#!/usr/bin/env bash
+
+# Generates docs/static/openapi.yml from the query services of the GXR
+# modules listed in docs/config.json. Run through `make proto-swagger-gen`.
+
+set -eo pipefail
+
+mkdir -p ./tmp-swagger-gen
+cd proto
+proto_dirs=$(find ./gxr -path -prune -o -name '*.proto' -print0 | xargs -0 -n1 dirname | sort | uniq)
+for dir in $proto_dirs; do
+  # generate swagger files (filter query files)
+  query_file=$(find "${dir}" -maxdepth 1 \( -name 'query.proto' -o -name 'service.proto' \))
+  if [[ ! -z "$query_file" ]]; then
+    buf generate --template buf.gen.swagger.yaml $query_file
+  fi
+done
+
+cd ..
+# combine swagger files
+# uses nodejs package `swagger-combine`.
+# all the individual swagger files need to be configured in `config.json` for merging
+swagger-combine ./docs/config.json -o ./docs/static/openapi.yml -f yaml --continueOnConflictingPaths true --includeDefinitions true
+
+# clean swagger files
+rm -rf ./tmp-swagger-gen
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+)
+
+// GetQueryCmd returns the cli query commands for this module
+func GetQueryCmd(queryRoute string) *cobra.Command {
+	// Group airdrop queries under a subcommand
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      fmt.Sprintf("Querying commands for the %s module", types.ModuleName),
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		CmdQueryClaimRounds(),
+		CmdQueryClaimRound(),
+		CmdQueryClaimStatus(),
+	)
+
+	return cmd
+}
+
+// CmdQueryClaimRounds implements the claim rounds query command.
+func CmdQueryClaimRounds() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim-rounds",
+		Args:  cobra.NoArgs,
+		Short: "Query the airdrop claim rounds",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.ClaimRounds(cmd.Context(), &types.QueryClaimRoundsRequest{
+				Pagination: pageReq,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "claim rounds")
+
+	return cmd
+}
+
+// CmdQueryClaimRound implements the single claim round query command.
+func CmdQueryClaimRound() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim-round [round-id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Query an airdrop claim round",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			roundID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid round id %q: %w", args[0], err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.ClaimRound(cmd.Context(), &types.QueryClaimRoundRequest{
+				RoundId: roundID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryClaimStatus implements the claim status query command.
+func CmdQueryClaimStatus() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim-status [round-id] [index]",
+		Args:  cobra.ExactArgs(2),
+		Short: "Query whether a claim of a round was paid and whether the round expired",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			roundID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid round id %q: %w", args[0], err)
+			}
+			index, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid index %q: %w", args[1], err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.ClaimStatus(cmd.Context(), &types.QueryClaimStatusRequest{
+				RoundId: roundID,
+				Index:   index,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
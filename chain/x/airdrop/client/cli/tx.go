@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+)
+
+// GetTxCmd returns the transaction commands for this module
+func GetTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      fmt.Sprintf("%s transactions subcommands", types.ModuleName),
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	// Claim rounds are only opened by governance through MsgSetClaimRoot
+	cmd.AddCommand(
+		CmdClaimAirdrop(),
+	)
+
+	return cmd
+}
+
+// CmdClaimAirdrop implements the airdrop claim transaction command.
+func CmdClaimAirdrop() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim [round-id] [index] [amount] [proof]",
+		Args:  cobra.RangeArgs(3, 4),
+		Short: "Claim your airdrop of a claim round",
+		Long: `Claims the airdrop of a claim round, signed by the address of the claim.
+The index, amount and proof are published with the claim tree of the round;
+the proof is the comma separated hex encoded sibling hashes, empty for a tree
+with a single claim. Each claim is paid once, until the round expires.
+
+Example:
+$ gxrchaind tx airdrop claim 1 42 150000000000ugen 3f1c...,a9e0... --from mykey`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			roundID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid round id %q: %w", args[0], err)
+			}
+			index, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid index %q: %w", args[1], err)
+			}
+			amount, err := sdk.ParseCoinNormalized(args[2])
+			if err != nil {
+				return fmt.Errorf("invalid amount %q: %w", args[2], err)
+			}
+			var proof []string
+			if len(args) == 4 && args[3] != "" {
+				proof = strings.Split(args[3], ",")
+			}
+
+			msg := types.NewMsgClaimAirdrop(clientCtx.GetFromAddress(), roundID, index, amount, proof)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+
+	return cmd
+}
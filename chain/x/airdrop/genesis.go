@@ -0,0 +1,29 @@
+package airdrop
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+)
+
+// InitGenesis initializes the airdrop module's state from a provided genesis state.
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState types.GenesisState) {
+	var lastRoundID uint64
+	for _, round := range genState.ClaimRounds {
+		k.SetClaimRound(ctx, round)
+		if round.Id > lastRoundID {
+			lastRoundID = round.Id
+		}
+	}
+	k.SetLastClaimRoundID(ctx, lastRoundID)
+
+	for _, word := range genState.Claimed {
+		k.SetClaimedWord(ctx, word)
+	}
+}
+
+// ExportGenesis returns the airdrop module's exported genesis.
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+	return types.NewGenesisState(k.GetAllClaimRounds(ctx), k.GetAllClaimedWords(ctx))
+}
@@ -0,0 +1,144 @@
+package keeper
+
+import (
+	"strconv"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+)
+
+// IsExpired reports whether a round no longer accepts claims at the block time
+func IsExpired(ctx sdk.Context, round types.ClaimRound) bool {
+	return round.Swept || ctx.BlockTime().Unix() >= round.ExpiryTime
+}
+
+// GetOutstandingClaims returns what the open claim rounds may still pay out
+func (k Keeper) GetOutstandingClaims(ctx sdk.Context) sdk.Coin {
+	outstanding := sdk.NewCoin(types.ClaimDenom, sdk.ZeroInt())
+	for _, round := range k.GetAllClaimRounds(ctx) {
+		outstanding = outstanding.Add(round.Unclaimed())
+	}
+	return outstanding
+}
+
+// OpenClaimRound opens a claim round paying up to total from the module
+// account until expiryTime. The module account must cover the total next to
+// what the open rounds may still pay out.
+func (k Keeper) OpenClaimRound(ctx sdk.Context, merkleRoot string, total sdk.Coin, expiryTime int64, reserve sdk.AccAddress) (types.ClaimRound, error) {
+	if expiryTime <= ctx.BlockTime().Unix() {
+		return types.ClaimRound{}, errorsmod.Wrapf(types.ErrInvalidClaimRound, "expiry %d is not after the block time %d", expiryTime, ctx.BlockTime().Unix())
+	}
+	if k.bankKeeper.BlockedAddr(reserve) {
+		return types.ClaimRound{}, errorsmod.Wrapf(sdkerrors.ErrUnauthorized, "reserve %s is not allowed to receive funds", reserve)
+	}
+
+	balance := k.bankKeeper.GetBalance(ctx, k.GetModuleAddress(), types.ClaimDenom)
+	required := k.GetOutstandingClaims(ctx).Add(total)
+	if balance.IsLT(required) {
+		return types.ClaimRound{}, errorsmod.Wrapf(types.ErrInsufficientAirdrop, "module account holds %s, open rounds and the new one need %s", balance, required)
+	}
+
+	round := types.ClaimRound{
+		Id:         k.GetLastClaimRoundID(ctx) + 1,
+		MerkleRoot: merkleRoot,
+		Total:      total,
+		Claimed:    sdk.NewCoin(total.Denom, sdk.ZeroInt()),
+		ExpiryTime: expiryTime,
+		Reserve:    reserve.String(),
+	}
+	k.SetClaimRound(ctx, round)
+	k.SetLastClaimRoundID(ctx, round.Id)
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeClaimRootSet,
+		sdk.NewAttribute(types.AttributeKeyRoundID, strconv.FormatUint(round.Id, 10)),
+		sdk.NewAttribute(types.AttributeKeyMerkleRoot, round.MerkleRoot),
+		sdk.NewAttribute(types.AttributeKeyTotal, round.Total.String()),
+		sdk.NewAttribute(types.AttributeKeyExpiryTime, strconv.FormatInt(round.ExpiryTime, 10)),
+	))
+
+	return round, nil
+}
+
+// Claim pays claim index of a round to claimant once the proof leads from
+// its leaf to the round root. Each claim is paid once.
+func (k Keeper) Claim(ctx sdk.Context, claimant sdk.AccAddress, roundID, index uint64, amount sdk.Coin, proof [][]byte) error {
+	round, found := k.GetClaimRound(ctx, roundID)
+	if !found {
+		return errorsmod.Wrapf(types.ErrClaimRoundNotFound, "round %d", roundID)
+	}
+	if IsExpired(ctx, round) {
+		return errorsmod.Wrapf(types.ErrClaimRoundExpired, "round %d expired at %d", roundID, round.ExpiryTime)
+	}
+	if k.IsClaimed(ctx, roundID, index) {
+		return errorsmod.Wrapf(types.ErrAlreadyClaimed, "claim %d of round %d", index, roundID)
+	}
+
+	root, err := types.ParseMerkleRoot(round.MerkleRoot)
+	if err != nil {
+		return errorsmod.Wrap(types.ErrInvalidClaimRound, err.Error())
+	}
+	if !types.VerifyClaimProof(root, types.ClaimLeaf(index, claimant, amount), proof) {
+		return errorsmod.Wrapf(types.ErrInvalidClaimProof, "claim %d of %s for %s is not in round %d", index, claimant, amount, roundID)
+	}
+
+	// A tree adding up to more than its total must not pay out other rounds' funds
+	if round.Unclaimed().IsLT(amount) {
+		return errorsmod.Wrapf(types.ErrInsufficientAirdrop, "round %d has %s left, claim is %s", roundID, round.Unclaimed(), amount)
+	}
+
+	if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, claimant, sdk.NewCoins(amount)); err != nil {
+		return err
+	}
+	k.setClaimed(ctx, roundID, index)
+	round.Claimed = round.Claimed.Add(amount)
+	round.ClaimCount++
+	k.SetClaimRound(ctx, round)
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeAirdropClaimed,
+		sdk.NewAttribute(types.AttributeKeyRoundID, strconv.FormatUint(roundID, 10)),
+		sdk.NewAttribute(types.AttributeKeyIndex, strconv.FormatUint(index, 10)),
+		sdk.NewAttribute(types.AttributeKeyClaimant, claimant.String()),
+		sdk.NewAttribute(types.AttributeKeyAmount, amount.String()),
+	))
+
+	return nil
+}
+
+// SweepExpiredRounds sends the unclaimed rest of the rounds that expired to
+// their reserve. A failed sweep is retried in the next block.
+func (k Keeper) SweepExpiredRounds(ctx sdk.Context) {
+	for _, round := range k.GetAllClaimRounds(ctx) {
+		if round.Swept || !IsExpired(ctx, round) {
+			continue
+		}
+
+		unclaimed := round.Unclaimed()
+		if unclaimed.IsPositive() {
+			reserve, err := sdk.AccAddressFromBech32(round.Reserve)
+			if err != nil {
+				k.Logger(ctx).Error("invalid claim round reserve", "round", round.Id, "reserve", round.Reserve, "error", err)
+				continue
+			}
+			if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, reserve, sdk.NewCoins(unclaimed)); err != nil {
+				k.Logger(ctx).Error("failed to sweep expired claim round", "round", round.Id, "error", err)
+				continue
+			}
+		}
+
+		round.Swept = true
+		k.SetClaimRound(ctx, round)
+
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeClaimRoundExpired,
+			sdk.NewAttribute(types.AttributeKeyRoundID, strconv.FormatUint(round.Id, 10)),
+			sdk.NewAttribute(types.AttributeKeyReserve, round.Reserve),
+			sdk.NewAttribute(types.AttributeKeyAmount, unclaimed.String()),
+		))
+		k.Logger(ctx).Info("claim round expired", "round", round.Id, "claims", round.ClaimCount, "swept", unclaimed)
+	}
+}
@@ -0,0 +1,79 @@
+package keeper
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+)
+
+var _ types.QueryServer = Keeper{}
+
+// ClaimRounds returns the claim rounds with pagination.
+func (k Keeper) ClaimRounds(goCtx context.Context, req *types.QueryClaimRoundsRequest) (*types.QueryClaimRoundsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	roundStore := prefix.NewStore(ctx.KVStore(k.storeKey), types.ClaimRoundsKey)
+
+	var rounds []types.ClaimRound
+	pageRes, err := query.Paginate(roundStore, req.Pagination, func(key []byte, value []byte) error {
+		var round types.ClaimRound
+		if err := k.cdc.Unmarshal(value, &round); err != nil {
+			return err
+		}
+		rounds = append(rounds, round)
+		return nil
+	})
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryClaimRoundsResponse{
+		ClaimRounds: rounds,
+		Pagination:  pageRes,
+	}, nil
+}
+
+// ClaimRound returns a claim round by id.
+func (k Keeper) ClaimRound(goCtx context.Context, req *types.QueryClaimRoundRequest) (*types.QueryClaimRoundResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	round, found := k.GetClaimRound(ctx, req.RoundId)
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "claim round %d not found", req.RoundId)
+	}
+
+	return &types.QueryClaimRoundResponse{ClaimRound: round}, nil
+}
+
+// ClaimStatus returns whether a claim of a round was paid and whether the
+// round still accepts claims.
+func (k Keeper) ClaimStatus(goCtx context.Context, req *types.QueryClaimStatusRequest) (*types.QueryClaimStatusResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	round, found := k.GetClaimRound(ctx, req.RoundId)
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "claim round %d not found", req.RoundId)
+	}
+
+	return &types.QueryClaimStatusResponse{
+		Claimed: k.IsClaimed(ctx, req.RoundId, req.Index),
+		Expired: IsExpired(ctx, round),
+	}, nil
+}
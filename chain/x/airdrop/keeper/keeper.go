@@ -0,0 +1,157 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	bankkeeper "github.com/cosmos/cosmos-sdk/x/bank/keeper"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+)
+
+type Keeper struct {
+	cdc      codec.BinaryCodec
+	storeKey storetypes.StoreKey
+
+	bankKeeper bankkeeper.Keeper
+
+	// authority may open claim rounds
+	authority string
+}
+
+func NewKeeper(
+	cdc codec.BinaryCodec,
+	storeKey storetypes.StoreKey,
+	bankKeeper bankkeeper.Keeper,
+	authority string,
+) Keeper {
+	return Keeper{
+		cdc:        cdc,
+		storeKey:   storeKey,
+		bankKeeper: bankKeeper,
+		authority:  authority,
+	}
+}
+
+// GetAuthority returns the address allowed to open claim rounds
+func (k Keeper) GetAuthority() string {
+	return k.authority
+}
+
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
+}
+
+// GetModuleAddress returns the address of the airdrop module account, which
+// holds the airdrop & farming allocation
+func (k Keeper) GetModuleAddress() sdk.AccAddress {
+	return authtypes.NewModuleAddress(types.ModuleName)
+}
+
+// GetClaimRound returns a claim round by id
+func (k Keeper) GetClaimRound(ctx sdk.Context, roundID uint64) (types.ClaimRound, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetClaimRoundKey(roundID))
+	if bz == nil {
+		return types.ClaimRound{}, false
+	}
+
+	var round types.ClaimRound
+	k.cdc.MustUnmarshal(bz, &round)
+	return round, true
+}
+
+// SetClaimRound stores a claim round
+func (k Keeper) SetClaimRound(ctx sdk.Context, round types.ClaimRound) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&round)
+	store.Set(types.GetClaimRoundKey(round.Id), bz)
+}
+
+// GetAllClaimRounds returns the claim rounds ordered by id
+func (k Keeper) GetAllClaimRounds(ctx sdk.Context) []types.ClaimRound {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.ClaimRoundsKey)
+	iterator := store.Iterator(nil, nil)
+	defer iterator.Close()
+
+	rounds := []types.ClaimRound{}
+	for ; iterator.Valid(); iterator.Next() {
+		var round types.ClaimRound
+		k.cdc.MustUnmarshal(iterator.Value(), &round)
+		rounds = append(rounds, round)
+	}
+	return rounds
+}
+
+// GetLastClaimRoundID returns the id of the last claim round opened, 0 if
+// none was
+func (k Keeper) GetLastClaimRoundID(ctx sdk.Context) uint64 {
+	bz := ctx.KVStore(k.storeKey).Get(types.LastClaimRoundIDKey)
+	if bz == nil {
+		return 0
+	}
+	return sdk.BigEndianToUint64(bz)
+}
+
+// SetLastClaimRoundID stores the id of the last claim round opened
+func (k Keeper) SetLastClaimRoundID(ctx sdk.Context, roundID uint64) {
+	ctx.KVStore(k.storeKey).Set(types.LastClaimRoundIDKey, sdk.Uint64ToBigEndian(roundID))
+}
+
+// IsClaimed reports whether claim index of a round was paid
+func (k Keeper) IsClaimed(ctx sdk.Context, roundID, index uint64) bool {
+	return k.getClaimedWord(ctx, roundID, index/64)&(1<<(index%64)) != 0
+}
+
+// setClaimed marks claim index of a round as paid
+func (k Keeper) setClaimed(ctx sdk.Context, roundID, index uint64) {
+	word := index / 64
+	k.SetClaimedWord(ctx, types.ClaimBitmapWord{
+		RoundId: roundID,
+		Word:    word,
+		Bits:    k.getClaimedWord(ctx, roundID, word) | 1<<(index%64),
+	})
+}
+
+func (k Keeper) getClaimedWord(ctx sdk.Context, roundID, word uint64) uint64 {
+	bz := ctx.KVStore(k.storeKey).Get(types.GetClaimedBitmapWordKey(roundID, word))
+	if bz == nil {
+		return 0
+	}
+	return sdk.BigEndianToUint64(bz)
+}
+
+// SetClaimedWord stores a word of a claimed bitmap
+func (k Keeper) SetClaimedWord(ctx sdk.Context, word types.ClaimBitmapWord) {
+	store := ctx.KVStore(k.storeKey)
+	key := types.GetClaimedBitmapWordKey(word.RoundId, word.Word)
+	if word.Bits == 0 {
+		store.Delete(key)
+		return
+	}
+	store.Set(key, sdk.Uint64ToBigEndian(word.Bits))
+}
+
+// GetAllClaimedWords returns the non-zero words of the claimed bitmaps,
+// ordered by round and word
+func (k Keeper) GetAllClaimedWords(ctx sdk.Context) []types.ClaimBitmapWord {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.ClaimedBitmapKey)
+	iterator := store.Iterator(nil, nil)
+	defer iterator.Close()
+
+	words := []types.ClaimBitmapWord{}
+	for ; iterator.Valid(); iterator.Next() {
+		key := iterator.Key()
+		words = append(words, types.ClaimBitmapWord{
+			RoundId: sdk.BigEndianToUint64(key[:8]),
+			Word:    sdk.BigEndianToUint64(key[8:16]),
+			Bits:    sdk.BigEndianToUint64(iterator.Value()),
+		})
+	}
+	return words
+}
@@ -0,0 +1,298 @@
+package keeper_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/store"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	bankkeeper "github.com/cosmos/cosmos-sdk/x/bank/keeper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop"
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+)
+
+// genesisTime is the block time used by keeper tests
+var genesisTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testAuthority may open claim rounds in keeper tests
+var testAuthority = sdk.AccAddress([]byte("airdrop-authority")).String()
+
+// testReserve receives the unclaimed rest of expired rounds
+var testReserve = sdk.AccAddress([]byte("airdrop-reserve"))
+
+// accountBankKeeper holds balances by address
+type accountBankKeeper struct {
+	bankkeeper.Keeper
+	balances map[string]sdk.Coins
+}
+
+func (b *accountBankKeeper) SendCoinsFromModuleToAccount(_ sdk.Context, module string, recipient sdk.AccAddress, amount sdk.Coins) error {
+	sender := authtypes.NewModuleAddress(module).String()
+	balance, negative := b.balances[sender].SafeSub(amount...)
+	if negative {
+		return sdkerrors.ErrInsufficientFunds
+	}
+	b.balances[sender] = balance
+	b.balances[recipient.String()] = b.balances[recipient.String()].Add(amount...)
+	return nil
+}
+
+func (b *accountBankKeeper) GetBalance(_ sdk.Context, address sdk.AccAddress, denom string) sdk.Coin {
+	return sdk.NewCoin(denom, b.balances[address.String()].AmountOf(denom))
+}
+
+func (b *accountBankKeeper) BlockedAddr(address sdk.AccAddress) bool {
+	return address.Equals(authtypes.NewModuleAddress(types.ModuleName))
+}
+
+func (b *accountBankKeeper) balance(address sdk.AccAddress) int64 {
+	return b.balances[address.String()].AmountOf(types.ClaimDenom).Int64()
+}
+
+// setupKeeper creates an airdrop keeper backed by an in-memory store, with
+// the module account holding allocation ugen
+func setupKeeper(t testing.TB, allocation int64) (keeper.Keeper, sdk.Context, *accountBankKeeper) {
+	storeKey := sdk.NewKVStoreKey(types.StoreKey)
+
+	db := dbm.NewMemDB()
+	stateStore := store.NewCommitMultiStore(db)
+	stateStore.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, db)
+	require.NoError(t, stateStore.LoadLatestVersion())
+
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	bank := &accountBankKeeper{balances: map[string]sdk.Coins{
+		authtypes.NewModuleAddress(types.ModuleName).String(): sdk.NewCoins(sdk.NewInt64Coin(types.ClaimDenom, allocation)),
+	}}
+	k := keeper.NewKeeper(cdc, storeKey, bank, testAuthority)
+
+	ctx := sdk.NewContext(stateStore, tmproto.Header{Time: genesisTime}, false, log.NewNopLogger())
+	return k, ctx, bank
+}
+
+// testClaim is a leaf of a test claim tree
+type testClaim struct {
+	claimant sdk.AccAddress
+	amount   sdk.Coin
+}
+
+// newTestClaims returns n claims of 1000, 2000, ... ugen and their tree
+func newTestClaims(n int) ([]testClaim, *types.ClaimTree) {
+	claims := make([]testClaim, n)
+	leaves := make([][]byte, n)
+	for i := range claims {
+		claims[i] = testClaim{
+			claimant: sdk.AccAddress(crypto.AddressHash([]byte(fmt.Sprintf("claimant-%d", i)))),
+			amount:   sdk.NewInt64Coin(types.ClaimDenom, int64(1000*(i+1))),
+		}
+		leaves[i] = types.ClaimLeaf(uint64(i), claims[i].claimant, claims[i].amount)
+	}
+	return claims, types.NewClaimTree(leaves)
+}
+
+// claimMsg returns the claim of index i with its proof
+func claimMsg(roundID uint64, i int, claim testClaim, tree *types.ClaimTree) *types.MsgClaimAirdrop {
+	var proof []string
+	for _, sibling := range tree.Proof(i) {
+		proof = append(proof, hex.EncodeToString(sibling))
+	}
+	return types.NewMsgClaimAirdrop(claim.claimant, roundID, uint64(i), claim.amount, proof)
+}
+
+// openRound opens a round of tree paying total, expiring after a day
+func openRound(t *testing.T, ctx sdk.Context, k keeper.Keeper, tree *types.ClaimTree, total int64) uint64 {
+	t.Helper()
+	res, err := keeper.NewMsgServerImpl(k).SetClaimRoot(sdk.WrapSDKContext(ctx), types.NewMsgSetClaimRoot(
+		testAuthority,
+		hex.EncodeToString(tree.Root()),
+		sdk.NewInt64Coin(types.ClaimDenom, total),
+		ctx.BlockTime().Add(24*time.Hour).Unix(),
+		testReserve.String(),
+	))
+	require.NoError(t, err)
+	return res.RoundId
+}
+
+func TestSetClaimRoot(t *testing.T) {
+	k, ctx, _ := setupKeeper(t, 10_000)
+	msgServer := keeper.NewMsgServerImpl(k)
+	goCtx := sdk.WrapSDKContext(ctx)
+	_, tree := newTestClaims(3)
+	root := hex.EncodeToString(tree.Root())
+	expiry := genesisTime.Add(time.Hour).Unix()
+
+	msg := types.NewMsgSetClaimRoot(testAuthority, root, sdk.NewInt64Coin(types.ClaimDenom, 6_000), expiry, testReserve.String())
+	other := *msg
+	other.Authority = testReserve.String()
+	_, err := msgServer.SetClaimRoot(goCtx, &other)
+	require.ErrorIs(t, err, types.ErrInvalidAuthority)
+
+	past := *msg
+	past.ExpiryTime = genesisTime.Unix()
+	_, err = msgServer.SetClaimRoot(goCtx, &past)
+	require.ErrorIs(t, err, types.ErrInvalidClaimRound)
+
+	blocked := *msg
+	blocked.Reserve = k.GetModuleAddress().String()
+	_, err = msgServer.SetClaimRoot(goCtx, &blocked)
+	require.ErrorIs(t, err, sdkerrors.ErrUnauthorized)
+
+	res, err := msgServer.SetClaimRoot(goCtx, msg)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.RoundId)
+
+	// The module account covers 4,000 next to the open round
+	msg.Total = sdk.NewInt64Coin(types.ClaimDenom, 4_001)
+	_, err = msgServer.SetClaimRoot(goCtx, msg)
+	require.ErrorIs(t, err, types.ErrInsufficientAirdrop)
+
+	msg.Total = sdk.NewInt64Coin(types.ClaimDenom, 4_000)
+	res, err = msgServer.SetClaimRoot(goCtx, msg)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), res.RoundId)
+	require.Equal(t, int64(10_000), k.GetOutstandingClaims(ctx).Amount.Int64())
+}
+
+func TestClaimAirdropPaysOnce(t *testing.T) {
+	k, ctx, bank := setupKeeper(t, 1_000_000)
+	msgServer := keeper.NewMsgServerImpl(k)
+	claims, tree := newTestClaims(5)
+	roundID := openRound(t, ctx, k, tree, 15_000)
+
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	goCtx := sdk.WrapSDKContext(ctx)
+	_, err := msgServer.ClaimAirdrop(goCtx, claimMsg(roundID, 2, claims[2], tree))
+	require.NoError(t, err)
+	require.Equal(t, int64(3000), bank.balance(claims[2].claimant))
+
+	events := ctx.EventManager().Events()
+	require.Len(t, events, 1)
+	require.Equal(t, types.EventTypeAirdropClaimed, events[0].Type)
+
+	// The claimed bitmap rejects the second claim
+	_, err = msgServer.ClaimAirdrop(goCtx, claimMsg(roundID, 2, claims[2], tree))
+	require.ErrorIs(t, err, types.ErrAlreadyClaimed)
+	require.Equal(t, int64(3000), bank.balance(claims[2].claimant))
+
+	status, err := k.ClaimStatus(goCtx, &types.QueryClaimStatusRequest{RoundId: roundID, Index: 2})
+	require.NoError(t, err)
+	require.True(t, status.Claimed)
+	require.False(t, status.Expired)
+	status, err = k.ClaimStatus(goCtx, &types.QueryClaimStatusRequest{RoundId: roundID, Index: 3})
+	require.NoError(t, err)
+	require.False(t, status.Claimed)
+
+	// Claims of another account, amount or index are rejected
+	stolen := claimMsg(roundID, 3, claims[3], tree)
+	stolen.Claimant = claims[0].claimant.String()
+	_, err = msgServer.ClaimAirdrop(goCtx, stolen)
+	require.ErrorIs(t, err, types.ErrInvalidClaimProof)
+
+	inflated := claimMsg(roundID, 3, claims[3], tree)
+	inflated.Amount = inflated.Amount.AddAmount(sdk.NewInt(1000))
+	_, err = msgServer.ClaimAirdrop(goCtx, inflated)
+	require.ErrorIs(t, err, types.ErrInvalidClaimProof)
+
+	_, err = msgServer.ClaimAirdrop(goCtx, claimMsg(roundID+1, 3, claims[3], tree))
+	require.ErrorIs(t, err, types.ErrClaimRoundNotFound)
+
+	round, found := k.GetClaimRound(ctx, roundID)
+	require.True(t, found)
+	require.Equal(t, uint64(1), round.ClaimCount)
+	require.Equal(t, int64(3000), round.Claimed.Amount.Int64())
+}
+
+func TestClaimRoundCannotExceedItsTotal(t *testing.T) {
+	k, ctx, _ := setupKeeper(t, 1_000_000)
+	msgServer := keeper.NewMsgServerImpl(k)
+	goCtx := sdk.WrapSDKContext(ctx)
+
+	// The tree adds up to 6,000 but the round was opened for 4,000
+	claims, tree := newTestClaims(3)
+	roundID := openRound(t, ctx, k, tree, 4_000)
+
+	_, err := msgServer.ClaimAirdrop(goCtx, claimMsg(roundID, 2, claims[2], tree))
+	require.NoError(t, err)
+	_, err = msgServer.ClaimAirdrop(goCtx, claimMsg(roundID, 1, claims[1], tree))
+	require.ErrorIs(t, err, types.ErrInsufficientAirdrop)
+	_, err = msgServer.ClaimAirdrop(goCtx, claimMsg(roundID, 0, claims[0], tree))
+	require.NoError(t, err)
+}
+
+func TestExpiredClaimRoundSweptToReserve(t *testing.T) {
+	k, ctx, bank := setupKeeper(t, 100_000)
+	msgServer := keeper.NewMsgServerImpl(k)
+	claims, tree := newTestClaims(4)
+	roundID := openRound(t, ctx, k, tree, 10_000)
+	module := airdrop.NewAppModule(nil, k)
+
+	_, err := msgServer.ClaimAirdrop(sdk.WrapSDKContext(ctx), claimMsg(roundID, 3, claims[3], tree))
+	require.NoError(t, err)
+
+	// Nothing is swept before the expiry
+	module.EndBlock(ctx.WithBlockTime(genesisTime.Add(24*time.Hour-time.Second)), abci.RequestEndBlock{})
+	require.Zero(t, bank.balance(testReserve))
+
+	expired := ctx.WithBlockTime(genesisTime.Add(24 * time.Hour)).WithEventManager(sdk.NewEventManager())
+	_, err = msgServer.ClaimAirdrop(sdk.WrapSDKContext(expired), claimMsg(roundID, 0, claims[0], tree))
+	require.ErrorIs(t, err, types.ErrClaimRoundExpired)
+
+	module.EndBlock(expired, abci.RequestEndBlock{})
+	require.Equal(t, int64(6_000), bank.balance(testReserve))
+	require.Equal(t, int64(90_000), bank.balance(k.GetModuleAddress()))
+	require.Equal(t, types.EventTypeClaimRoundExpired, expired.EventManager().Events()[0].Type)
+
+	round, found := k.GetClaimRound(ctx, roundID)
+	require.True(t, found)
+	require.True(t, round.Swept)
+	require.True(t, k.GetOutstandingClaims(ctx).IsZero())
+
+	// A swept round is swept once
+	module.EndBlock(expired.WithBlockTime(genesisTime.Add(48*time.Hour)), abci.RequestEndBlock{})
+	require.Equal(t, int64(6_000), bank.balance(testReserve))
+
+	status, err := k.ClaimStatus(sdk.WrapSDKContext(ctx), &types.QueryClaimStatusRequest{RoundId: roundID, Index: 0})
+	require.NoError(t, err)
+	require.True(t, status.Expired)
+	require.False(t, status.Claimed)
+}
+
+func TestGenesisRoundTrip(t *testing.T) {
+	k, ctx, _ := setupKeeper(t, 10_000_000)
+	msgServer := keeper.NewMsgServerImpl(k)
+	claims, tree := newTestClaims(70)
+	roundID := openRound(t, ctx, k, tree, 2_485_000)
+	for _, i := range []int{0, 5, 69} {
+		_, err := msgServer.ClaimAirdrop(sdk.WrapSDKContext(ctx), claimMsg(roundID, i, claims[i], tree))
+		require.NoError(t, err)
+	}
+
+	exported := airdrop.ExportGenesis(ctx, k)
+	require.NoError(t, exported.Validate())
+	require.Len(t, exported.Claimed, 2)
+
+	imported, importedCtx, _ := setupKeeper(t, 0)
+	airdrop.InitGenesis(importedCtx, imported, *exported)
+	require.Equal(t, exported, airdrop.ExportGenesis(importedCtx, imported))
+	require.Equal(t, roundID, imported.GetLastClaimRoundID(importedCtx))
+	require.True(t, imported.IsClaimed(importedCtx, roundID, 69))
+	require.False(t, imported.IsClaimed(importedCtx, roundID, 68))
+
+	// A claim count the bitmap disagrees with is rejected
+	exported.ClaimRounds[0].ClaimCount++
+	require.Error(t, exported.Validate())
+}
@@ -0,0 +1,59 @@
+package keeper
+
+import (
+	"context"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+)
+
+type msgServer struct {
+	Keeper
+}
+
+var _ types.MsgServer = msgServer{}
+
+// NewMsgServerImpl returns an implementation of the airdrop MsgServer interface
+func NewMsgServerImpl(keeper Keeper) types.MsgServer {
+	return &msgServer{Keeper: keeper}
+}
+
+// SetClaimRoot opens a claim round; only the authority may open rounds
+func (m msgServer) SetClaimRoot(goCtx context.Context, msg *types.MsgSetClaimRoot) (*types.MsgSetClaimRootResponse, error) {
+	if msg.Authority != m.authority {
+		return nil, errorsmod.Wrapf(types.ErrInvalidAuthority, "expected %s, got %s", m.authority, msg.Authority)
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	round, err := m.OpenClaimRound(ctx, msg.MerkleRoot, msg.Total, msg.ExpiryTime, sdk.MustAccAddressFromBech32(msg.Reserve))
+	if err != nil {
+		return nil, err
+	}
+
+	m.Logger(ctx).Info("claim round opened", "round", round.Id, "total", round.Total, "expiry", round.ExpiryTime)
+
+	return &types.MsgSetClaimRootResponse{RoundId: round.Id}, nil
+}
+
+// ClaimAirdrop pays a claim of a round to the claimant of its leaf
+func (m msgServer) ClaimAirdrop(goCtx context.Context, msg *types.MsgClaimAirdrop) (*types.MsgClaimAirdropResponse, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	proof, err := types.ParseClaimProof(msg.Proof)
+	if err != nil {
+		return nil, errorsmod.Wrap(types.ErrInvalidClaimProof, err.Error())
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	if err := m.Claim(ctx, sdk.MustAccAddressFromBech32(msg.Claimant), msg.RoundId, msg.Index, msg.Amount, proof); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgClaimAirdropResponse{}, nil
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	cdctypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/msgservice"
+)
+
+// RegisterLegacyAminoCodec registers the airdrop messages for amino JSON signing
+func RegisterLegacyAminoCodec(cdc *codec.LegacyAmino) {
+	cdc.RegisterConcrete(&MsgSetClaimRoot{}, "airdrop/MsgSetClaimRoot", nil)
+	cdc.RegisterConcrete(&MsgClaimAirdrop{}, "airdrop/MsgClaimAirdrop", nil)
+}
+
+// RegisterInterfaces registers the airdrop messages and Msg service
+func RegisterInterfaces(registry cdctypes.InterfaceRegistry) {
+	registry.RegisterImplementations((*sdk.Msg)(nil),
+		&MsgSetClaimRoot{},
+		&MsgClaimAirdrop{},
+	)
+
+	msgservice.RegisterMsgServiceDesc(registry, &Msg_ServiceDesc)
+}
+
+var (
+	amino = codec.NewLegacyAmino()
+
+	// ModuleCdc is the amino codec used for legacy sign bytes
+	ModuleCdc = codec.NewAminoCodec(amino)
+)
+
+func init() {
+	RegisterLegacyAminoCodec(amino)
+	amino.Seal()
+}
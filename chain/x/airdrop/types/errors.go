@@ -0,0 +1,16 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// Airdrop module errors
+var (
+	ErrInvalidAuthority    = errorsmod.Register(ModuleName, 2, "invalid authority")
+	ErrInvalidClaimRound   = errorsmod.Register(ModuleName, 3, "invalid claim round")
+	ErrClaimRoundNotFound  = errorsmod.Register(ModuleName, 4, "claim round not found")
+	ErrClaimRoundExpired   = errorsmod.Register(ModuleName, 5, "claim round expired")
+	ErrAlreadyClaimed      = errorsmod.Register(ModuleName, 6, "airdrop already claimed")
+	ErrInvalidClaimProof   = errorsmod.Register(ModuleName, 7, "invalid claim proof")
+	ErrInsufficientAirdrop = errorsmod.Register(ModuleName, 8, "insufficient airdrop allocation")
+)
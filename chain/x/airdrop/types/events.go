@@ -0,0 +1,17 @@
+package types
+
+// Airdrop module event types and attributes
+const (
+	EventTypeClaimRootSet      = "claim_root_set"
+	EventTypeAirdropClaimed    = "airdrop_claimed"
+	EventTypeClaimRoundExpired = "claim_round_expired"
+
+	AttributeKeyRoundID    = "round_id"
+	AttributeKeyMerkleRoot = "merkle_root"
+	AttributeKeyTotal      = "total"
+	AttributeKeyExpiryTime = "expiry_time"
+	AttributeKeyClaimant   = "claimant"
+	AttributeKeyIndex      = "index"
+	AttributeKeyAmount     = "amount"
+	AttributeKeyReserve    = "reserve"
+)
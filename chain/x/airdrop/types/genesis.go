@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"math/bits"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewGenesisState creates a new GenesisState object
+func NewGenesisState(rounds []ClaimRound, claimed []ClaimBitmapWord) *GenesisState {
+	return &GenesisState{ClaimRounds: rounds, Claimed: claimed}
+}
+
+// DefaultGenesisState returns a default genesis state without claim rounds.
+// Rounds are opened by governance once the claim tree is published.
+func DefaultGenesisState() *GenesisState {
+	return NewGenesisState([]ClaimRound{}, []ClaimBitmapWord{})
+}
+
+// Validate performs basic validation of the GenesisState
+func (gs GenesisState) Validate() error {
+	rounds := make(map[uint64]ClaimRound, len(gs.ClaimRounds))
+	for _, round := range gs.ClaimRounds {
+		if err := round.Validate(); err != nil {
+			return fmt.Errorf("claim round %d: %w", round.Id, err)
+		}
+		if _, found := rounds[round.Id]; found {
+			return fmt.Errorf("duplicate claim round %d", round.Id)
+		}
+		rounds[round.Id] = round
+	}
+
+	words := make(map[[2]uint64]bool, len(gs.Claimed))
+	claims := make(map[uint64]uint64, len(rounds))
+	for _, word := range gs.Claimed {
+		if _, found := rounds[word.RoundId]; !found {
+			return fmt.Errorf("claimed bitmap of unknown claim round %d", word.RoundId)
+		}
+		key := [2]uint64{word.RoundId, word.Word}
+		if words[key] {
+			return fmt.Errorf("duplicate claimed bitmap word %d of claim round %d", word.Word, word.RoundId)
+		}
+		words[key] = true
+		claims[word.RoundId] += uint64(bits.OnesCount64(word.Bits))
+	}
+	for id, round := range rounds {
+		if claims[id] != round.ClaimCount {
+			return fmt.Errorf("claim round %d counts %d claims, its bitmap %d", id, round.ClaimCount, claims[id])
+		}
+	}
+	return nil
+}
+
+// Validate checks the root, the addresses and that no more than the total
+// was claimed
+func (r ClaimRound) Validate() error {
+	if r.Id == 0 {
+		return fmt.Errorf("round id must be positive")
+	}
+	if _, err := ParseMerkleRoot(r.MerkleRoot); err != nil {
+		return err
+	}
+	if !r.Total.IsValid() || !r.Total.IsPositive() || r.Total.Denom != ClaimDenom {
+		return fmt.Errorf("total must be a positive amount of %s: %s", ClaimDenom, r.Total)
+	}
+	if !r.Claimed.IsValid() || r.Claimed.Denom != ClaimDenom || r.Total.IsLT(r.Claimed) {
+		return fmt.Errorf("claimed %s exceeds the total %s", r.Claimed, r.Total)
+	}
+	if _, err := sdk.AccAddressFromBech32(r.Reserve); err != nil {
+		return fmt.Errorf("invalid reserve address %q: %w", r.Reserve, err)
+	}
+	return nil
+}
+
+// Unclaimed returns what the claims of the round may still pay out
+func (r ClaimRound) Unclaimed() sdk.Coin {
+	if r.Swept {
+		return sdk.NewCoin(r.Total.Denom, sdk.ZeroInt())
+	}
+	return r.Total.Sub(r.Claimed)
+}
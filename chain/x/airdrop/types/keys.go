@@ -0,0 +1,56 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// ModuleName defines the module name
+	ModuleName = "airdrop"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+
+	// RouterKey is the message route for the airdrop
+	RouterKey = ModuleName
+
+	// QuerierRoute defines the module's query routing key
+	QuerierRoute = ModuleName
+
+	// ClaimDenom is the denomination airdrop claims are paid in
+	ClaimDenom = "ugen"
+)
+
+// ModuleAccountPermissions lists the module accounts the airdrop keeper moves
+// coins out of, with the permissions each one needs
+var ModuleAccountPermissions = map[string][]string{
+	ModuleName: nil,
+}
+
+// KVStore keys
+var (
+	// ClaimRoundsKey prefixes the claim rounds, keyed by big endian round id
+	ClaimRoundsKey = []byte{0x01}
+
+	// LastClaimRoundIDKey stores the id of the last claim round opened
+	LastClaimRoundIDKey = []byte{0x02}
+
+	// ClaimedBitmapKey prefixes the claimed bitmap words, keyed by big endian
+	// round id and word index
+	ClaimedBitmapKey = []byte{0x03}
+)
+
+// GetClaimRoundKey returns the store key of a claim round
+func GetClaimRoundKey(roundID uint64) []byte {
+	return append(append([]byte{}, ClaimRoundsKey...), sdk.Uint64ToBigEndian(roundID)...)
+}
+
+// GetClaimedBitmapRoundKey returns the prefix of the claimed bitmap of a round
+func GetClaimedBitmapRoundKey(roundID uint64) []byte {
+	return append(append([]byte{}, ClaimedBitmapKey...), sdk.Uint64ToBigEndian(roundID)...)
+}
+
+// GetClaimedBitmapWordKey returns the store key of a claimed bitmap word
+func GetClaimedBitmapWordKey(roundID, word uint64) []byte {
+	return append(GetClaimedBitmapRoundKey(roundID), sdk.Uint64ToBigEndian(word)...)
+}
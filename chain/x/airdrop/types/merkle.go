@@ -0,0 +1,137 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MaxProofLength bounds the proof of a claim, enough for 2^64 claims
+const MaxProofLength = 64
+
+// Domain separation of the claim tree hashes, so a leaf can never be passed
+// off as an inner node
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// ClaimLeaf returns the leaf hash of claim index paying amount to claimant:
+// sha256(0x00 || uint64be(index) || claimant || amount), with the amount as
+// its coin string, such as "1000ugen"
+func ClaimLeaf(index uint64, claimant sdk.AccAddress, amount sdk.Coin) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(sdk.Uint64ToBigEndian(index))
+	h.Write(claimant)
+	h.Write([]byte(amount.String()))
+	return h.Sum(nil)
+}
+
+// hashNode returns the parent of two nodes. The pair is sorted, so proofs
+// carry no left or right flags.
+func hashNode(a, b []byte) []byte {
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(a)
+	h.Write(b)
+	return h.Sum(nil)
+}
+
+// VerifyClaimProof reports whether proof leads from leaf to root
+func VerifyClaimProof(root, leaf []byte, proof [][]byte) bool {
+	if len(proof) > MaxProofLength {
+		return false
+	}
+	node := leaf
+	for _, sibling := range proof {
+		node = hashNode(node, sibling)
+	}
+	return bytes.Equal(node, root)
+}
+
+// ParseMerkleRoot decodes a hex encoded sha256 root
+func ParseMerkleRoot(root string) ([]byte, error) {
+	bz, err := hex.DecodeString(root)
+	if err != nil {
+		return nil, fmt.Errorf("merkle root is not hex: %w", err)
+	}
+	if len(bz) != sha256.Size {
+		return nil, fmt.Errorf("merkle root is %d bytes, want %d", len(bz), sha256.Size)
+	}
+	return bz, nil
+}
+
+// ParseClaimProof decodes the hex encoded sibling hashes of a proof
+func ParseClaimProof(proof []string) ([][]byte, error) {
+	if len(proof) > MaxProofLength {
+		return nil, fmt.Errorf("proof has %d hashes, max %d", len(proof), MaxProofLength)
+	}
+	hashes := make([][]byte, len(proof))
+	for i, sibling := range proof {
+		bz, err := hex.DecodeString(sibling)
+		if err != nil || len(bz) != sha256.Size {
+			return nil, fmt.Errorf("proof hash %d is not a hex encoded sha256 hash", i)
+		}
+		hashes[i] = bz
+	}
+	return hashes, nil
+}
+
+// ClaimTree is the merkle tree of the claims of a round. Governance proposals
+// publish its root; claimants are handed their leaf's proof.
+type ClaimTree struct {
+	// levels[0] are the leaves, the last level is the root
+	levels [][][]byte
+}
+
+// NewClaimTree builds the tree of the given leaves. A node without a sibling
+// is carried up to the next level unchanged.
+func NewClaimTree(leaves [][]byte) *ClaimTree {
+	if len(leaves) == 0 {
+		return &ClaimTree{}
+	}
+	levels := [][][]byte{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, hashNode(level[i], level[i+1]))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return &ClaimTree{levels: levels}
+}
+
+// Root returns the root of the tree, nil for a tree without leaves
+func (t *ClaimTree) Root() []byte {
+	if len(t.levels) == 0 {
+		return nil
+	}
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Proof returns the sibling hashes from leaf index to the root
+func (t *ClaimTree) Proof(index int) [][]byte {
+	if len(t.levels) == 0 {
+		return nil
+	}
+	var proof [][]byte
+	for _, level := range t.levels[:len(t.levels)-1] {
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		index /= 2
+	}
+	return proof
+}
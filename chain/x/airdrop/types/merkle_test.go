@@ -0,0 +1,62 @@
+package types_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/cometbft/cometbft/crypto"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/airdrop/types"
+)
+
+func claimant(i int) sdk.AccAddress {
+	return sdk.AccAddress(crypto.AddressHash([]byte(fmt.Sprintf("claimant-%d", i))))
+}
+
+func TestClaimProofsVerify(t *testing.T) {
+	// Odd sizes carry a node without sibling up a level
+	for _, size := range []int{1, 2, 3, 7, 64, 100} {
+		leaves := make([][]byte, size)
+		for i := range leaves {
+			leaves[i] = types.ClaimLeaf(uint64(i), claimant(i), sdk.NewInt64Coin(types.ClaimDenom, int64(1000*(i+1))))
+		}
+		tree := types.NewClaimTree(leaves)
+		root := tree.Root()
+
+		for i, leaf := range leaves {
+			require.True(t, types.VerifyClaimProof(root, leaf, tree.Proof(i)), "leaf %d of %d", i, size)
+		}
+
+		// Another claimant, amount or index does not verify with the proof
+		proof := tree.Proof(0)
+		amount := sdk.NewInt64Coin(types.ClaimDenom, 1000)
+		require.False(t, types.VerifyClaimProof(root, types.ClaimLeaf(0, claimant(1), amount), proof), "size %d", size)
+		require.False(t, types.VerifyClaimProof(root, types.ClaimLeaf(0, claimant(0), amount.AddAmount(sdk.OneInt())), proof), "size %d", size)
+		require.False(t, types.VerifyClaimProof(root, types.ClaimLeaf(1, claimant(0), amount), proof), "size %d", size)
+	}
+}
+
+func TestParseClaimProof(t *testing.T) {
+	hash := hex.EncodeToString(make([]byte, 32))
+
+	proof, err := types.ParseClaimProof([]string{hash, hash})
+	require.NoError(t, err)
+	require.Len(t, proof, 2)
+
+	for _, invalid := range [][]string{
+		{"zz"},
+		{hash[:62]},
+		make([]string, types.MaxProofLength+1),
+	} {
+		_, err := types.ParseClaimProof(invalid)
+		require.Error(t, err)
+	}
+
+	_, err = types.ParseMerkleRoot(hash)
+	require.NoError(t, err)
+	_, err = types.ParseMerkleRoot(hash + "00")
+	require.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	// TypeMsgSetClaimRoot is the type of MsgSetClaimRoot
+	TypeMsgSetClaimRoot = "set_claim_root"
+
+	// TypeMsgClaimAirdrop is the type of MsgClaimAirdrop
+	TypeMsgClaimAirdrop = "claim_airdrop"
+)
+
+var (
+	_ sdk.Msg = &MsgSetClaimRoot{}
+	_ sdk.Msg = &MsgClaimAirdrop{}
+)
+
+// NewMsgSetClaimRoot creates a new MsgSetClaimRoot
+func NewMsgSetClaimRoot(authority, merkleRoot string, total sdk.Coin, expiryTime int64, reserve string) *MsgSetClaimRoot {
+	return &MsgSetClaimRoot{
+		Authority:  authority,
+		MerkleRoot: merkleRoot,
+		Total:      total,
+		ExpiryTime: expiryTime,
+		Reserve:    reserve,
+	}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgSetClaimRoot) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgSetClaimRoot) Type() string { return TypeMsgSetClaimRoot }
+
+// GetSigners returns the module authority
+func (msg MsgSetClaimRoot) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.MustAccAddressFromBech32(msg.Authority)}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgSetClaimRoot) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic checks the addresses, the root and the total. Whether the
+// caller is the authority, the expiry is ahead and the module account covers
+// the total is checked by the msg server.
+func (msg MsgSetClaimRoot) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid authority address: %s", err)
+	}
+	if _, err := sdk.AccAddressFromBech32(msg.Reserve); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid reserve address: %s", err)
+	}
+	if _, err := ParseMerkleRoot(msg.MerkleRoot); err != nil {
+		return errorsmod.Wrap(ErrInvalidClaimRound, err.Error())
+	}
+	if !msg.Total.IsValid() || !msg.Total.IsPositive() || msg.Total.Denom != ClaimDenom {
+		return errorsmod.Wrapf(ErrInvalidClaimRound, "total must be a positive amount of %s: %s", ClaimDenom, msg.Total)
+	}
+	if msg.ExpiryTime <= 0 {
+		return errorsmod.Wrapf(ErrInvalidClaimRound, "invalid expiry time %d", msg.ExpiryTime)
+	}
+	return nil
+}
+
+// NewMsgClaimAirdrop creates a new MsgClaimAirdrop
+func NewMsgClaimAirdrop(claimant sdk.AccAddress, roundID, index uint64, amount sdk.Coin, proof []string) *MsgClaimAirdrop {
+	return &MsgClaimAirdrop{
+		Claimant: claimant.String(),
+		RoundId:  roundID,
+		Index:    index,
+		Amount:   amount,
+		Proof:    proof,
+	}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgClaimAirdrop) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgClaimAirdrop) Type() string { return TypeMsgClaimAirdrop }
+
+// GetSigners returns the claimant
+func (msg MsgClaimAirdrop) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.MustAccAddressFromBech32(msg.Claimant)}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgClaimAirdrop) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic checks the claimant, the amount and that the proof decodes.
+// The proof itself is verified against the round root by the msg server.
+func (msg MsgClaimAirdrop) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Claimant); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid claimant address: %s", err)
+	}
+	if !msg.Amount.IsValid() || !msg.Amount.IsPositive() || msg.Amount.Denom != ClaimDenom {
+		return errorsmod.Wrapf(ErrInvalidClaimProof, "amount must be a positive amount of %s: %s", ClaimDenom, msg.Amount)
+	}
+	if _, err := ParseClaimProof(msg.Proof); err != nil {
+		return errorsmod.Wrap(ErrInvalidClaimProof, err.Error())
+	}
+	return nil
+}
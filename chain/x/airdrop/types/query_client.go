@@ -0,0 +1,139 @@
+package types
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// QueryServer defines the gRPC querier service for the airdrop module.
+type QueryServer interface {
+	ClaimRounds(context.Context, *QueryClaimRoundsRequest) (*QueryClaimRoundsResponse, error)
+	ClaimRound(context.Context, *QueryClaimRoundRequest) (*QueryClaimRoundResponse, error)
+	ClaimStatus(context.Context, *QueryClaimStatusRequest) (*QueryClaimStatusResponse, error)
+}
+
+// QueryClient defines the gRPC querier client for the airdrop module.
+type QueryClient interface {
+	ClaimRounds(ctx context.Context, in *QueryClaimRoundsRequest, opts ...grpc.CallOption) (*QueryClaimRoundsResponse, error)
+	ClaimRound(ctx context.Context, in *QueryClaimRoundRequest, opts ...grpc.CallOption) (*QueryClaimRoundResponse, error)
+	ClaimStatus(ctx context.Context, in *QueryClaimStatusRequest, opts ...grpc.CallOption) (*QueryClaimStatusResponse, error)
+}
+
+type queryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewQueryClient creates a new QueryClient
+func NewQueryClient(cc grpc.ClientConnInterface) QueryClient {
+	return &queryClient{cc}
+}
+
+func (c *queryClient) ClaimRounds(ctx context.Context, in *QueryClaimRoundsRequest, opts ...grpc.CallOption) (*QueryClaimRoundsResponse, error) {
+	out := new(QueryClaimRoundsResponse)
+	err := c.cc.Invoke(ctx, "/gxr.airdrop.v1beta1.Query/ClaimRounds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) ClaimRound(ctx context.Context, in *QueryClaimRoundRequest, opts ...grpc.CallOption) (*QueryClaimRoundResponse, error) {
+	out := new(QueryClaimRoundResponse)
+	err := c.cc.Invoke(ctx, "/gxr.airdrop.v1beta1.Query/ClaimRound", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) ClaimStatus(ctx context.Context, in *QueryClaimStatusRequest, opts ...grpc.CallOption) (*QueryClaimStatusResponse, error) {
+	out := new(QueryClaimStatusResponse)
+	err := c.cc.Invoke(ctx, "/gxr.airdrop.v1beta1.Query/ClaimStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegisterQueryServer registers the airdrop query server
+func RegisterQueryServer(s grpc.ServiceRegistrar, srv QueryServer) {
+	s.RegisterService(&Query_ServiceDesc, srv)
+}
+
+// Query_ServiceDesc is the grpc service descriptor for Query service.
+var Query_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gxr.airdrop.v1beta1.Query",
+	HandlerType: (*QueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ClaimRounds",
+			Handler:    _Query_ClaimRounds_Handler,
+		},
+		{
+			MethodName: "ClaimRound",
+			Handler:    _Query_ClaimRound_Handler,
+		},
+		{
+			MethodName: "ClaimStatus",
+			Handler:    _Query_ClaimStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gxr/airdrop/v1beta1/query.proto",
+}
+
+// Handler functions (normally generated by protoc)
+func _Query_ClaimRounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryClaimRoundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).ClaimRounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.airdrop.v1beta1.Query/ClaimRounds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).ClaimRounds(ctx, req.(*QueryClaimRoundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_ClaimRound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryClaimRoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).ClaimRound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.airdrop.v1beta1.Query/ClaimRound",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).ClaimRound(ctx, req.(*QueryClaimRoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_ClaimStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryClaimStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).ClaimStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.airdrop.v1beta1.Query/ClaimStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).ClaimStatus(ctx, req.(*QueryClaimStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+
+	"github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+)
+
+// GetQueryCmd returns the cli query commands for this module
+func GetQueryCmd(queryRoute string) *cobra.Command {
+	// Group denylist queries under a subcommand
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      fmt.Sprintf("Querying commands for the %s module", types.ModuleName),
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		CmdQueryDeniedAddresses(),
+		CmdQueryDeniedAddress(),
+	)
+
+	return cmd
+}
+
+// CmdQueryDeniedAddresses implements the denylist query command.
+func CmdQueryDeniedAddresses() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "denied-addresses",
+		Args:  cobra.NoArgs,
+		Short: "Query the addresses bank sends are rejected to",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.DeniedAddresses(cmd.Context(), &types.QueryDeniedAddressesRequest{
+				Pagination: pageReq,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "denied addresses")
+
+	return cmd
+}
+
+// CmdQueryDeniedAddress implements the single address denylist query command.
+func CmdQueryDeniedAddress() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "denied-address [address]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Query whether bank sends to an address are rejected, and why",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.DeniedAddress(cmd.Context(), &types.QueryDeniedAddressRequest{
+				Address: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
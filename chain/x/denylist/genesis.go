@@ -0,0 +1,20 @@
+package denylist
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/denylist/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+)
+
+// InitGenesis initializes the denylist module's state from a provided genesis state.
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState types.GenesisState) {
+	for _, denied := range genState.DeniedAddresses {
+		k.SetDeniedAddress(ctx, denied)
+	}
+}
+
+// ExportGenesis returns the denylist module's exported genesis.
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+	return types.NewGenesisState(k.GetAllDeniedAddresses(ctx))
+}
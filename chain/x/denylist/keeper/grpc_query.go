@@ -0,0 +1,63 @@
+package keeper
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+
+	"github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+)
+
+var _ types.QueryServer = Keeper{}
+
+// DeniedAddresses returns the denylist with pagination.
+func (k Keeper) DeniedAddresses(goCtx context.Context, req *types.QueryDeniedAddressesRequest) (*types.QueryDeniedAddressesResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	deniedStore := prefix.NewStore(ctx.KVStore(k.storeKey), types.DeniedAddressesKey)
+
+	var denied []types.DeniedAddress
+	pageRes, err := query.Paginate(deniedStore, req.Pagination, func(key []byte, value []byte) error {
+		var entry types.DeniedAddress
+		if err := k.cdc.Unmarshal(value, &entry); err != nil {
+			return err
+		}
+		denied = append(denied, entry)
+		return nil
+	})
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryDeniedAddressesResponse{
+		DeniedAddresses: denied,
+		Pagination:      pageRes,
+	}, nil
+}
+
+// DeniedAddress returns whether sends to an address are rejected, and why.
+func (k Keeper) DeniedAddress(goCtx context.Context, req *types.QueryDeniedAddressRequest) (*types.QueryDeniedAddressResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if _, err := sdk.AccAddressFromBech32(req.Address); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address: %s", err)
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	denied, found := k.GetDeniedAddress(ctx, req.Address)
+
+	return &types.QueryDeniedAddressResponse{
+		Denied:        found,
+		DeniedAddress: denied,
+	}, nil
+}
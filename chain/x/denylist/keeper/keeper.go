@@ -0,0 +1,85 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+)
+
+type Keeper struct {
+	cdc      codec.BinaryCodec
+	storeKey storetypes.StoreKey
+
+	// authority may update the denylist
+	authority string
+}
+
+func NewKeeper(
+	cdc codec.BinaryCodec,
+	storeKey storetypes.StoreKey,
+	authority string,
+) Keeper {
+	return Keeper{
+		cdc:       cdc,
+		storeKey:  storeKey,
+		authority: authority,
+	}
+}
+
+// GetAuthority returns the address allowed to sign denylist Msgs
+func (k Keeper) GetAuthority() string {
+	return k.authority
+}
+
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
+}
+
+// GetDeniedAddress returns the denylist entry of address, if sends to it are
+// rejected
+func (k Keeper) GetDeniedAddress(ctx sdk.Context, address string) (types.DeniedAddress, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetDeniedAddressKey(address))
+	if bz == nil {
+		return types.DeniedAddress{}, false
+	}
+
+	var denied types.DeniedAddress
+	k.cdc.MustUnmarshal(bz, &denied)
+	return denied, true
+}
+
+// SetDeniedAddress denies sends to an address, replacing its reason when it
+// is already denied
+func (k Keeper) SetDeniedAddress(ctx sdk.Context, denied types.DeniedAddress) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&denied)
+	store.Set(types.GetDeniedAddressKey(denied.Address), bz)
+}
+
+// DeleteDeniedAddress allows sends to an address again
+func (k Keeper) DeleteDeniedAddress(ctx sdk.Context, address string) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetDeniedAddressKey(address))
+}
+
+// GetAllDeniedAddresses returns the denylist ordered by address
+func (k Keeper) GetAllDeniedAddresses(ctx sdk.Context) []types.DeniedAddress {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.DeniedAddressesKey)
+	iterator := store.Iterator(nil, nil)
+	defer iterator.Close()
+
+	denied := []types.DeniedAddress{}
+	for ; iterator.Valid(); iterator.Next() {
+		var entry types.DeniedAddress
+		k.cdc.MustUnmarshal(iterator.Value(), &entry)
+		denied = append(denied, entry)
+	}
+	return denied
+}
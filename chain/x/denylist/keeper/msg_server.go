@@ -0,0 +1,56 @@
+package keeper
+
+import (
+	"context"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/denylist/types"
+)
+
+type msgServer struct {
+	Keeper
+}
+
+var _ types.MsgServer = msgServer{}
+
+// NewMsgServerImpl returns an implementation of the denylist MsgServer interface
+func NewMsgServerImpl(keeper Keeper) types.MsgServer {
+	return &msgServer{Keeper: keeper}
+}
+
+// UpdateDenylist denies and allows addresses; only the authority may update
+// the denylist
+func (m msgServer) UpdateDenylist(goCtx context.Context, msg *types.MsgUpdateDenylist) (*types.MsgUpdateDenylistResponse, error) {
+	if msg.Authority != m.authority {
+		return nil, errorsmod.Wrapf(types.ErrInvalidAuthority, "expected %s, got %s", m.authority, msg.Authority)
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	for _, denied := range msg.Add {
+		m.SetDeniedAddress(ctx, denied)
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeAddressDenied,
+			sdk.NewAttribute(types.AttributeKeyAddress, denied.Address),
+			sdk.NewAttribute(types.AttributeKeyReason, denied.Reason),
+		))
+	}
+	for _, address := range msg.Remove {
+		if _, found := m.GetDeniedAddress(ctx, address); !found {
+			return nil, errorsmod.Wrapf(types.ErrInvalidDeniedAddress, "%s is not denied", address)
+		}
+		m.DeleteDeniedAddress(ctx, address)
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeAddressAllowed,
+			sdk.NewAttribute(types.AttributeKeyAddress, address),
+		))
+	}
+
+	m.Logger(ctx).Info("denylist updated", "added", len(msg.Add), "removed", len(msg.Remove))
+
+	return &types.MsgUpdateDenylistResponse{}, nil
+}
@@ -0,0 +1,12 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// Denylist module errors
+var (
+	ErrInvalidAuthority     = errorsmod.Register(ModuleName, 2, "invalid authority")
+	ErrInvalidDeniedAddress = errorsmod.Register(ModuleName, 3, "invalid denied address")
+	ErrDeniedRecipient      = errorsmod.Register(ModuleName, 4, "recipient address is denylisted")
+)
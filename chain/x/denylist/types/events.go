@@ -0,0 +1,10 @@
+package types
+
+// Denylist module event types and attributes
+const (
+	EventTypeAddressDenied  = "address_denied"
+	EventTypeAddressAllowed = "address_allowed"
+
+	AttributeKeyAddress = "address"
+	AttributeKeyReason  = "reason"
+)
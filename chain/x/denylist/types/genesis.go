@@ -0,0 +1,47 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MaxReasonLength bounds the reason stored with a denied address
+const MaxReasonLength = 256
+
+// NewGenesisState creates a new GenesisState object
+func NewGenesisState(deniedAddresses []DeniedAddress) *GenesisState {
+	return &GenesisState{DeniedAddresses: deniedAddresses}
+}
+
+// DefaultGenesisState returns a default genesis state, denying no address
+func DefaultGenesisState() *GenesisState {
+	return NewGenesisState([]DeniedAddress{})
+}
+
+// Validate performs basic validation of the GenesisState
+func (gs GenesisState) Validate() error {
+	seen := make(map[string]bool, len(gs.DeniedAddresses))
+	for i, denied := range gs.DeniedAddresses {
+		if err := denied.Validate(); err != nil {
+			return fmt.Errorf("denied address %d: %w", i, err)
+		}
+		if seen[denied.Address] {
+			return fmt.Errorf("duplicate denied address %s", denied.Address)
+		}
+		seen[denied.Address] = true
+	}
+	return nil
+}
+
+// Validate checks that the address is a bech32 account address and the
+// reason fits MaxReasonLength
+func (d DeniedAddress) Validate() error {
+	if _, err := sdk.AccAddressFromBech32(d.Address); err != nil {
+		return fmt.Errorf("invalid address %q: %w", d.Address, err)
+	}
+	if len(d.Reason) > MaxReasonLength {
+		return fmt.Errorf("reason for %s is %d bytes, max %d", d.Address, len(d.Reason), MaxReasonLength)
+	}
+	return nil
+}
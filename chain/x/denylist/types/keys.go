@@ -0,0 +1,26 @@
+package types
+
+const (
+	// ModuleName defines the module name
+	ModuleName = "denylist"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+
+	// RouterKey is the message route for the denylist
+	RouterKey = ModuleName
+
+	// QuerierRoute defines the module's query routing key
+	QuerierRoute = ModuleName
+)
+
+// KVStore keys
+var (
+	// DeniedAddressesKey prefixes the denied addresses, keyed by bech32 address
+	DeniedAddressesKey = []byte{0x01}
+)
+
+// GetDeniedAddressKey returns the store key of a denied address
+func GetDeniedAddressKey(address string) []byte {
+	return append(append([]byte{}, DeniedAddressesKey...), []byte(address)...)
+}
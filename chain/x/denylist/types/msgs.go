@@ -0,0 +1,70 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// TypeMsgUpdateDenylist is the type of MsgUpdateDenylist
+const TypeMsgUpdateDenylist = "update_denylist"
+
+var _ sdk.Msg = &MsgUpdateDenylist{}
+
+// NewMsgUpdateDenylist creates a new MsgUpdateDenylist
+func NewMsgUpdateDenylist(authority string, add []DeniedAddress, remove []string) *MsgUpdateDenylist {
+	return &MsgUpdateDenylist{
+		Authority: authority,
+		Add:       add,
+		Remove:    remove,
+	}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgUpdateDenylist) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgUpdateDenylist) Type() string { return TypeMsgUpdateDenylist }
+
+// GetSigners returns the module authority
+func (msg MsgUpdateDenylist) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.MustAccAddressFromBech32(msg.Authority)}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgUpdateDenylist) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic checks the authority and that the update changes something,
+// names each address once and only valid addresses. Whether the caller is the
+// authority is checked by the msg server.
+func (msg MsgUpdateDenylist) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid authority address: %s", err)
+	}
+	if len(msg.Add) == 0 && len(msg.Remove) == 0 {
+		return errorsmod.Wrap(ErrInvalidDeniedAddress, "update adds and removes no address")
+	}
+
+	seen := make(map[string]bool, len(msg.Add)+len(msg.Remove))
+	for _, denied := range msg.Add {
+		if err := denied.Validate(); err != nil {
+			return errorsmod.Wrap(ErrInvalidDeniedAddress, err.Error())
+		}
+		if seen[denied.Address] {
+			return errorsmod.Wrapf(ErrInvalidDeniedAddress, "%s is listed twice", denied.Address)
+		}
+		seen[denied.Address] = true
+	}
+	for _, address := range msg.Remove {
+		if _, err := sdk.AccAddressFromBech32(address); err != nil {
+			return errorsmod.Wrapf(ErrInvalidDeniedAddress, "invalid address %q: %s", address, err)
+		}
+		if seen[address] {
+			return errorsmod.Wrapf(ErrInvalidDeniedAddress, "%s is listed twice", address)
+		}
+		seen[address] = true
+	}
+	return nil
+}
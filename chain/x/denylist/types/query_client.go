@@ -0,0 +1,106 @@
+package types
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// QueryServer defines the gRPC querier service for the denylist module.
+type QueryServer interface {
+	DeniedAddresses(context.Context, *QueryDeniedAddressesRequest) (*QueryDeniedAddressesResponse, error)
+	DeniedAddress(context.Context, *QueryDeniedAddressRequest) (*QueryDeniedAddressResponse, error)
+}
+
+// QueryClient defines the gRPC querier client for the denylist module.
+type QueryClient interface {
+	DeniedAddresses(ctx context.Context, in *QueryDeniedAddressesRequest, opts ...grpc.CallOption) (*QueryDeniedAddressesResponse, error)
+	DeniedAddress(ctx context.Context, in *QueryDeniedAddressRequest, opts ...grpc.CallOption) (*QueryDeniedAddressResponse, error)
+}
+
+type queryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewQueryClient creates a new QueryClient
+func NewQueryClient(cc grpc.ClientConnInterface) QueryClient {
+	return &queryClient{cc}
+}
+
+func (c *queryClient) DeniedAddresses(ctx context.Context, in *QueryDeniedAddressesRequest, opts ...grpc.CallOption) (*QueryDeniedAddressesResponse, error) {
+	out := new(QueryDeniedAddressesResponse)
+	err := c.cc.Invoke(ctx, "/gxr.denylist.v1beta1.Query/DeniedAddresses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) DeniedAddress(ctx context.Context, in *QueryDeniedAddressRequest, opts ...grpc.CallOption) (*QueryDeniedAddressResponse, error) {
+	out := new(QueryDeniedAddressResponse)
+	err := c.cc.Invoke(ctx, "/gxr.denylist.v1beta1.Query/DeniedAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegisterQueryServer registers the denylist query server
+func RegisterQueryServer(s grpc.ServiceRegistrar, srv QueryServer) {
+	s.RegisterService(&Query_ServiceDesc, srv)
+}
+
+// Query_ServiceDesc is the grpc service descriptor for Query service.
+var Query_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gxr.denylist.v1beta1.Query",
+	HandlerType: (*QueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DeniedAddresses",
+			Handler:    _Query_DeniedAddresses_Handler,
+		},
+		{
+			MethodName: "DeniedAddress",
+			Handler:    _Query_DeniedAddress_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gxr/denylist/v1beta1/query.proto",
+}
+
+// Handler functions (normally generated by protoc)
+func _Query_DeniedAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryDeniedAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).DeniedAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.denylist.v1beta1.Query/DeniedAddresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).DeniedAddresses(ctx, req.(*QueryDeniedAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_DeniedAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryDeniedAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).DeniedAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.denylist.v1beta1.Query/DeniedAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).DeniedAddress(ctx, req.(*QueryDeniedAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
@@ -6,11 +6,13 @@ import (
 	"github.com/Crocodile-ark/gxrchaind/x/feerouter/keeper"
 )
 
-// EndBlocker processes accumulated fees at the end of each block
+// EndBlocker routes the fees of the block's transactions and writes their
+// statistics
 func EndBlocker(ctx sdk.Context, k keeper.Keeper) {
-	// Process any accumulated fees from the fee collector
-	// This would be called at the end of each block to distribute fees
-	// For now, this is a placeholder as fee processing happens in the ante handler
+	// The post handler only adds each transaction's fees to the block's;
+	// they are routed here, and their statistics stored, once per block
+	k.RouteBlockFees(ctx)
+	k.FlushBlockFeeStats(ctx)
 	
 	k.Logger(ctx).Debug("Fee router end blocker executed", "height", ctx.BlockHeight())
 }
\ No newline at end of file
@@ -7,6 +7,7 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
 )
@@ -26,6 +27,9 @@ func GetQueryCmd(queryRoute string) *cobra.Command {
 		CmdQueryParams(),
 		CmdQueryFeeStats(),
 		CmdQueryLPPools(),
+		CmdQueryValidatorFeeTotal(),
+		CmdQueryValidatorFeeTotals(),
+		CmdQuerySimulateFees(),
 	)
 
 	return cmd
@@ -121,4 +125,120 @@ func CmdQueryLPPools() *cobra.Command {
 	flags.AddPaginationFlagsToCmd(cmd, "LP pools")
 
 	return cmd
-}
\ No newline at end of file
+}
+
+// CmdQueryValidatorFeeTotal implements the single validator fee total query command.
+func CmdQueryValidatorFeeTotal() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validator-fee-total [validator-addr]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Query the lifetime fees routed to a validator",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.ValidatorFeeTotal(cmd.Context(), &types.QueryValidatorFeeTotalRequest{
+				ValidatorAddress: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryValidatorFeeTotals implements the validator fee totals query command.
+func CmdQueryValidatorFeeTotals() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validator-fee-totals",
+		Args:  cobra.NoArgs,
+		Short: "Query the lifetime fees routed to every validator",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.ValidatorFeeTotals(cmd.Context(), &types.QueryValidatorFeeTotalsRequest{
+				Pagination: pageReq,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "validator fee totals")
+
+	return cmd
+}
+
+// FlagFarming marks a simulated transaction as an LP farming transaction
+const FlagFarming = "farming"
+
+// CmdQuerySimulateFees implements the fee routing simulation query command.
+func CmdQuerySimulateFees() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate-fees [fee]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Preview how a transaction fee would be split between validators, DEX, PoS and LP rewards",
+		Long: `Preview how a transaction fee would be split under the live feerouter params.
+Nothing is distributed. Pass --farming for the farming split, which includes LP rewards.
+
+Example:
+$ gxrchaind query feerouter simulate-fees 5000ugen --farming`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			fees, err := sdk.ParseCoinsNormalized(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid fee %q: %w", args[0], err)
+			}
+
+			farming, err := cmd.Flags().GetBool(FlagFarming)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.SimulateFees(cmd.Context(), &types.QuerySimulateFeesRequest{
+				Fees:      fees,
+				IsFarming: farming,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().Bool(FlagFarming, false, "Simulate an LP farming transaction")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
@@ -1,6 +1,8 @@
 package feerouter
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/Crocodile-ark/gxrchaind/x/feerouter/keeper"
@@ -15,10 +17,19 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState types.GenesisState)
 	// Set fee stats
 	k.SetFeeStats(ctx, genState.FeeStats)
 
-	// Set LP pools
+	// Set LP pools; accounts are initialized first, so module and vesting
+	// accounts are known here
 	for _, pool := range genState.LPPools {
+		if err := k.ValidateLPPoolAddress(ctx, pool.Address); err != nil {
+			panic(fmt.Errorf("LP pool %q: %w", pool.Name, err))
+		}
 		k.SetLPPool(ctx, pool)
 	}
+
+	// Set per-validator routed-fee totals
+	for _, total := range genState.ValidatorFeeTotals {
+		k.SetValidatorFeeTotal(ctx, total)
+	}
 }
 
 // ExportGenesis returns the feerouter module's exported genesis.
@@ -32,5 +43,9 @@ func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
 
 	genesis.LPPools = k.GetAllLPPools(ctx)
 
+	if totals := k.GetAllValidatorFeeTotals(ctx); totals != nil {
+		genesis.ValidatorFeeTotals = totals
+	}
+
 	return genesis
 }
\ No newline at end of file
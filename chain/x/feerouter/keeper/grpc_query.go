@@ -0,0 +1,150 @@
+package keeper
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+var _ types.QueryServer = Keeper{}
+
+// Params returns the total set of feerouter parameters.
+func (k Keeper) Params(goCtx context.Context, req *types.QueryParamsRequest) (*types.QueryParamsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	params := k.GetParams(ctx)
+
+	return &types.QueryParamsResponse{Params: params}, nil
+}
+
+// FeeStats returns the fee collection and distribution statistics.
+func (k Keeper) FeeStats(goCtx context.Context, req *types.QueryFeeStatsRequest) (*types.QueryFeeStatsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	stats, found := k.GetFeeStats(ctx)
+	if !found {
+		stats = types.DefaultFeeStats()
+	}
+
+	return &types.QueryFeeStatsResponse{FeeStats: stats}, nil
+}
+
+// LPPools returns the registered LP pools with pagination.
+func (k Keeper) LPPools(goCtx context.Context, req *types.QueryLPPoolsRequest) (*types.QueryLPPoolsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	store := ctx.KVStore(k.storeKey)
+	poolStore := prefix.NewStore(store, types.LPPoolsKey)
+
+	var pools []types.LPPool
+	pageRes, err := query.Paginate(poolStore, req.Pagination, func(key []byte, value []byte) error {
+		var pool types.LPPool
+		if err := k.cdc.Unmarshal(value, &pool); err != nil {
+			return err
+		}
+		pools = append(pools, pool)
+		return nil
+	})
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryLPPoolsResponse{
+		LPPools:    pools,
+		Pagination: pageRes,
+	}, nil
+}
+
+// ValidatorFeeTotal returns the lifetime fees routed to a single validator.
+func (k Keeper) ValidatorFeeTotal(goCtx context.Context, req *types.QueryValidatorFeeTotalRequest) (*types.QueryValidatorFeeTotalResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	if _, err := sdk.ValAddressFromBech32(req.ValidatorAddress); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid validator address: %s", err)
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	total, found := k.GetValidatorFeeTotal(ctx, req.ValidatorAddress)
+	if !found {
+		total = types.ValidatorFeeTotal{
+			ValidatorAddress: req.ValidatorAddress,
+			Total:            sdk.NewCoins(),
+		}
+	}
+
+	return &types.QueryValidatorFeeTotalResponse{ValidatorFeeTotal: total}, nil
+}
+
+// ValidatorFeeTotals returns the lifetime fees routed to every validator with pagination.
+func (k Keeper) ValidatorFeeTotals(goCtx context.Context, req *types.QueryValidatorFeeTotalsRequest) (*types.QueryValidatorFeeTotalsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	store := ctx.KVStore(k.storeKey)
+	totalsStore := prefix.NewStore(store, types.ValidatorFeeTotalsKey)
+
+	var totals []types.ValidatorFeeTotal
+	pageRes, err := query.Paginate(totalsStore, req.Pagination, func(key []byte, value []byte) error {
+		var total types.ValidatorFeeTotal
+		if err := k.cdc.Unmarshal(value, &total); err != nil {
+			return err
+		}
+		totals = append(totals, total)
+		return nil
+	})
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryValidatorFeeTotalsResponse{
+		ValidatorFeeTotals: totals,
+		Pagination:         pageRes,
+	}, nil
+}
+
+// SimulateFees returns how the given transaction fees would be split under the
+// current params. Nothing is distributed and no state is written.
+func (k Keeper) SimulateFees(goCtx context.Context, req *types.QuerySimulateFeesRequest) (*types.QuerySimulateFeesResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	if req.Fees.Empty() {
+		return nil, status.Error(codes.InvalidArgument, "fees cannot be empty")
+	}
+	if err := req.Fees.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid fees: %s", err)
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	split := k.SimulateFeeSplit(ctx, req.Fees, req.IsFarming)
+
+	return &types.QuerySimulateFeesResponse{
+		ValidatorAmount: split.Validators,
+		DexAmount:       split.Dex,
+		PosAmount:       split.Pos,
+		LPRewardAmount:  split.LPRewards,
+	}, nil
+}
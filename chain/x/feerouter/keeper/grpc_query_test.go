@@ -0,0 +1,47 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+func TestQuerySimulateFees(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	wctx := sdk.WrapSDKContext(ctx)
+	fees := sdk.NewCoins(sdk.NewInt64Coin("ugen", 10_001), sdk.NewInt64Coin("uatom", 100))
+
+	_, err := k.SimulateFees(wctx, nil)
+	require.Error(t, err)
+	_, err = k.SimulateFees(wctx, &types.QuerySimulateFeesRequest{})
+	require.Error(t, err)
+	_, err = k.SimulateFees(wctx, &types.QuerySimulateFeesRequest{Fees: sdk.Coins{sdk.NewInt64Coin("ugen", 0)}})
+	require.Error(t, err)
+
+	// General transactions split 40/30/30 and the remainder goes to PoS
+	res, err := k.SimulateFees(wctx, &types.QuerySimulateFeesRequest{Fees: fees})
+	require.NoError(t, err)
+	require.Equal(t, "40uatom,4000ugen", res.ValidatorAmount.String())
+	require.Equal(t, "30uatom,3000ugen", res.DexAmount.String())
+	require.Equal(t, "30uatom,3001ugen", res.PosAmount.String())
+	require.True(t, res.LPRewardAmount.IsZero())
+
+	// Farming transactions split 30/25/25/20 with LP rewards
+	res, err = k.SimulateFees(wctx, &types.QuerySimulateFeesRequest{Fees: fees, IsFarming: true})
+	require.NoError(t, err)
+	require.Equal(t, "30uatom,3000ugen", res.ValidatorAmount.String())
+	require.Equal(t, "25uatom,2500ugen", res.DexAmount.String())
+	require.Equal(t, "25uatom,2500ugen", res.LPRewardAmount.String())
+	require.Equal(t, "20uatom,2001ugen", res.PosAmount.String())
+
+	// The whole fee is accounted for
+	total := res.ValidatorAmount.Add(res.DexAmount...).Add(res.PosAmount...).Add(res.LPRewardAmount...)
+	require.Equal(t, fees, total)
+
+	// Simulating writes nothing
+	_, found := k.GetFeeStats(ctx)
+	require.False(t, found)
+}
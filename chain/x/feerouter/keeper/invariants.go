@@ -0,0 +1,31 @@
+package keeper
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+// RegisterInvariants registers the feerouter invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "params-consistency", ParamsConsistencyInvariant(k))
+}
+
+// ParamsConsistencyInvariant checks that the params in the module store and
+// in the legacy x/params subspace agree while both exist
+func ParamsConsistencyInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		stored := ctx.KVStore(k.storeKey).Get(types.FeeRouterParamsKey)
+		if stored == nil || !k.hasLegacyParams(ctx) {
+			return sdk.FormatInvariant(types.ModuleName, "params-consistency", "single params source"), false
+		}
+
+		legacy := k.legacyParams(ctx)
+		broken := !bytes.Equal(stored, k.cdc.MustMarshal(&legacy))
+		return sdk.FormatInvariant(types.ModuleName, "params-consistency",
+			fmt.Sprintf("module store and legacy subspace params differ: %t", broken)), broken
+	}
+}
@@ -3,43 +3,59 @@ package keeper
 import (
 	"fmt"
 
+	errorsmod "cosmossdk.io/errors"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cosmos/cosmos-sdk/codec"
 	storetypes "github.com/cosmos/cosmos-sdk/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestexported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
 	bankkeeper "github.com/cosmos/cosmos-sdk/x/bank/keeper"
-	distrkeeper "github.com/cosmos/cosmos-sdk/x/distribution/keeper"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
 	stakingkeeper "github.com/cosmos/cosmos-sdk/x/staking/keeper"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
+	"github.com/Crocodile-ark/gxrchaind/internal/rounding"
 	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
 )
 
 type (
 	Keeper struct {
-		cdc        codec.BinaryCodec
-		storeKey   storetypes.StoreKey
-		paramstore paramtypes.Subspace
+		cdc          codec.BinaryCodec
+		storeKey     storetypes.StoreKey
+		transientKey storetypes.StoreKey // per-block caches of the fee routing path
+		paramstore   paramtypes.Subspace // legacy params, kept in sync until deleted
 
 		accountKeeper authkeeper.AccountKeeper
 		bankKeeper    bankkeeper.Keeper
 		stakingKeeper *stakingkeeper.Keeper
-		distrKeeper   distrkeeper.Keeper
+
+		// authority may register LP pools and update params
+		authority string
+	}
+
+	// FeeSplit is how the fees of one transaction are divided. LPRewards is
+	// only non-zero for farming transactions.
+	FeeSplit struct {
+		Validators sdk.Coins
+		Dex        sdk.Coins
+		Pos        sdk.Coins
+		LPRewards  sdk.Coins
 	}
 )
 
 func NewKeeper(
 	cdc codec.BinaryCodec,
 	storeKey storetypes.StoreKey,
+	transientKey storetypes.StoreKey,
 	ps paramtypes.Subspace,
 	accountKeeper authkeeper.AccountKeeper,
 	bankKeeper bankkeeper.Keeper,
 	stakingKeeper *stakingkeeper.Keeper,
-	distrKeeper distrkeeper.Keeper,
+	authority string,
 ) Keeper {
 	// set KeyTable if it has not already been set
 	if !ps.HasKeyTable() {
@@ -49,27 +65,22 @@ func NewKeeper(
 	return Keeper{
 		cdc:           cdc,
 		storeKey:      storeKey,
+		transientKey:  transientKey,
 		paramstore:    ps,
 		accountKeeper: accountKeeper,
 		bankKeeper:    bankKeeper,
 		stakingKeeper: stakingKeeper,
-		distrKeeper:   distrKeeper,
+		authority:     authority,
 	}
 }
 
-func (k Keeper) Logger(ctx sdk.Context) log.Logger {
-	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
-}
-
-// GetParams get all parameters as types.Params
-func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
-	k.paramstore.GetParamSet(ctx, &params)
-	return
+// GetAuthority returns the address allowed to sign feerouter Msgs
+func (k Keeper) GetAuthority() string {
+	return k.authority
 }
 
-// SetParams set the params
-func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
-	k.paramstore.SetParamSet(ctx, &params)
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
 }
 
 // GetFeeStats gets the fee collection statistics
@@ -106,14 +117,86 @@ func (k Keeper) GetLPPool(ctx sdk.Context, address string) (types.LPPool, bool)
 	return pool, true
 }
 
+// IsActiveLPPool reports whether address is a registered LP pool that is
+// active. The halving module only sends DEX reserve withdrawals to these.
+func (k Keeper) IsActiveLPPool(ctx sdk.Context, address string) bool {
+	pool, found := k.GetLPPool(ctx, address)
+	return found && pool.Active
+}
+
 // SetLPPool sets an LP pool
 func (k Keeper) SetLPPool(ctx sdk.Context, pool types.LPPool) {
+	k.setLPPool(ctx, pool)
+	ctx.TransientStore(k.transientKey).Delete(types.BlockActivePoolsKey)
+}
+
+// setLPPool stores an LP pool without touching the cached active pools
+func (k Keeper) setLPPool(ctx sdk.Context, pool types.LPPool) {
 	store := ctx.KVStore(k.storeKey)
 	key := append(types.LPPoolsKey, []byte(pool.Address)...)
 	bz := k.cdc.MustMarshal(&pool)
 	store.Set(key, bz)
 }
 
+// getActiveLPPools returns the active LP pools. They are read from the pool
+// store once per block and cached in the transient store without their
+// reward totals; SetLPPool drops the cache.
+func (k Keeper) getActiveLPPools(ctx sdk.Context) []types.LPPool {
+	tstore := ctx.TransientStore(k.transientKey)
+	var active types.ActiveLPPools
+	if bz := tstore.Get(types.BlockActivePoolsKey); bz != nil {
+		k.cdc.MustUnmarshal(bz, &active)
+		return active.Pools
+	}
+
+	for _, pool := range k.GetAllLPPools(ctx) {
+		if pool.Active {
+			pool.TotalRewards = nil
+			active.Pools = append(active.Pools, pool)
+		}
+	}
+	tstore.Set(types.BlockActivePoolsKey, k.cdc.MustMarshal(&active))
+	return active.Pools
+}
+
+// addLPPoolRewards adds to the reward total of an LP pool. Rewards do not
+// change which pools are active, so the cached active pools are kept.
+func (k Keeper) addLPPoolRewards(ctx sdk.Context, address string, rewards sdk.Coins) {
+	pool, found := k.GetLPPool(ctx, address)
+	if !found {
+		return
+	}
+
+	pool.TotalRewards = pool.TotalRewards.Add(rewards...)
+	k.setLPPool(ctx, pool)
+}
+
+// ValidateLPPoolAddress checks that address can receive LP rewards: it must
+// be a bech32 account address that is neither a module account, registered
+// or already in state, nor a vesting account. An address without an account
+// is fine, the first reward send creates it.
+func (k Keeper) ValidateLPPoolAddress(ctx sdk.Context, address string) error {
+	addr, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		return errorsmod.Wrapf(types.ErrInvalidLPPool, "invalid address %s: %s", address, err)
+	}
+
+	for name, perms := range k.accountKeeper.GetModulePermissions() {
+		if perms.GetAddress().Equals(addr) {
+			return errorsmod.Wrapf(types.ErrLPPoolModuleAccount, "%s is the %s module account", address, name)
+		}
+	}
+
+	switch acc := k.accountKeeper.GetAccount(ctx, addr).(type) {
+	case authtypes.ModuleAccountI:
+		return errorsmod.Wrapf(types.ErrLPPoolModuleAccount, "%s is the %s module account", address, acc.GetName())
+	case vestexported.VestingAccount:
+		return errorsmod.Wrapf(types.ErrLPPoolVestingAccount, "%s vests until %d", address, acc.GetEndTime())
+	}
+
+	return nil
+}
+
 // GetAllLPPools gets all LP pools
 func (k Keeper) GetAllLPPools(ctx sdk.Context) []types.LPPool {
 	store := ctx.KVStore(k.storeKey)
@@ -130,12 +213,64 @@ func (k Keeper) GetAllLPPools(ctx sdk.Context) []types.LPPool {
 	return pools
 }
 
-// ProcessTransactionFees processes transaction fees according to GXR specification
-func (k Keeper) ProcessTransactionFees(ctx sdk.Context, fees sdk.Coins, isFarmingTransaction bool) error {
-	if fees.IsZero() {
-		return nil
+// GetValidatorFeeTotal gets the lifetime routed-fee total of a validator
+func (k Keeper) GetValidatorFeeTotal(ctx sdk.Context, operatorAddress string) (types.ValidatorFeeTotal, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetValidatorFeeTotalKey(operatorAddress))
+	if bz == nil {
+		return types.ValidatorFeeTotal{}, false
+	}
+
+	var total types.ValidatorFeeTotal
+	k.cdc.MustUnmarshal(bz, &total)
+	return total, true
+}
+
+// SetValidatorFeeTotal sets the lifetime routed-fee total of a validator
+func (k Keeper) SetValidatorFeeTotal(ctx sdk.Context, total types.ValidatorFeeTotal) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&total)
+	store.Set(types.GetValidatorFeeTotalKey(total.ValidatorAddress), bz)
+}
+
+// AddValidatorFeeTotal adds routed fees to a validator's lifetime total. Amounts are
+// recorded as allocated to the validator, before any commission split.
+func (k Keeper) AddValidatorFeeTotal(ctx sdk.Context, operatorAddress string, amount sdk.Coins) {
+	if amount.IsZero() {
+		return
+	}
+
+	total, found := k.GetValidatorFeeTotal(ctx, operatorAddress)
+	if !found {
+		total = types.ValidatorFeeTotal{
+			ValidatorAddress: operatorAddress,
+			Total:            sdk.NewCoins(),
+		}
+	}
+
+	total.Total = total.Total.Add(amount...)
+	k.SetValidatorFeeTotal(ctx, total)
+}
+
+// GetAllValidatorFeeTotals gets the routed-fee totals of all validators
+func (k Keeper) GetAllValidatorFeeTotals(ctx sdk.Context) []types.ValidatorFeeTotal {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.ValidatorFeeTotalsKey)
+	defer iterator.Close()
+
+	var totals []types.ValidatorFeeTotal
+	for ; iterator.Valid(); iterator.Next() {
+		var total types.ValidatorFeeTotal
+		k.cdc.MustUnmarshal(iterator.Value(), &total)
+		totals = append(totals, total)
 	}
 
+	return totals
+}
+
+// SimulateFeeSplit returns how ProcessTransactionFees splits fees under the
+// current params, without touching state
+func (k Keeper) SimulateFeeSplit(ctx sdk.Context, fees sdk.Coins, isFarmingTransaction bool) FeeSplit {
 	params := k.GetParams(ctx)
 	var validatorShare, dexShare, posShare, lpRewardShare sdk.Dec
 
@@ -154,20 +289,96 @@ func (k Keeper) ProcessTransactionFees(ctx sdk.Context, fees sdk.Coins, isFarmin
 	}
 
 	// Calculate distribution amounts
-	validatorAmount := make(sdk.Coins, len(fees))
-	dexAmount := make(sdk.Coins, len(fees))
-	posAmount := make(sdk.Coins, len(fees))
-	lpRewardAmount := make(sdk.Coins, len(fees))
+	split := FeeSplit{
+		Validators: make(sdk.Coins, len(fees)),
+		Dex:        make(sdk.Coins, len(fees)),
+		Pos:        make(sdk.Coins, len(fees)),
+		LPRewards:  make(sdk.Coins, len(fees)),
+	}
 
+	// PoS comes last so it can take the rounding remainder
+	shares := []sdk.Dec{validatorShare, dexShare, lpRewardShare, posShare}
 	for i, fee := range fees {
-		validatorAmount[i] = sdk.NewCoin(fee.Denom, fee.Amount.ToDec().Mul(validatorShare).TruncateInt())
-		dexAmount[i] = sdk.NewCoin(fee.Denom, fee.Amount.ToDec().Mul(dexShare).TruncateInt())
-		posAmount[i] = sdk.NewCoin(fee.Denom, fee.Amount.ToDec().Mul(posShare).TruncateInt())
-		if isFarmingTransaction {
-			lpRewardAmount[i] = sdk.NewCoin(fee.Denom, fee.Amount.ToDec().Mul(lpRewardShare).TruncateInt())
+		parts := rounding.Split(fee.Amount, shares, params.RoundingStrategy, params.RemainderToPos)
+		split.Validators[i] = sdk.NewCoin(fee.Denom, parts[0])
+		split.Dex[i] = sdk.NewCoin(fee.Denom, parts[1])
+		split.LPRewards[i] = sdk.NewCoin(fee.Denom, parts[2])
+		split.Pos[i] = sdk.NewCoin(fee.Denom, parts[3])
+	}
+
+	return split
+}
+
+// AddBlockFees adds the fees a delivered transaction paid to the block's
+// general or farming fees in the transient store. It writes one entry per fee
+// denom, whatever the size of the validator set, so it runs on the
+// transaction's gas meter; RouteBlockFees pays the fees out at the end of the
+// block.
+func (k Keeper) AddBlockFees(ctx sdk.Context, fees sdk.Coins, isFarmingTransaction bool) {
+	tstore := ctx.TransientStore(k.transientKey)
+	for _, fee := range fees {
+		key := types.GetBlockFeesKey(isFarmingTransaction, fee.Denom)
+		total := fee
+		if bz := tstore.Get(key); bz != nil {
+			var pending sdk.Coin
+			k.cdc.MustUnmarshal(bz, &pending)
+			total = total.Add(pending)
+		}
+		tstore.Set(key, k.cdc.MustMarshal(&total))
+	}
+}
+
+// GetBlockFees returns the general or farming transaction fees added in the
+// current block and not routed yet
+func (k Keeper) GetBlockFees(ctx sdk.Context, isFarmingTransaction bool) sdk.Coins {
+	tstore := ctx.TransientStore(k.transientKey)
+	iterator := sdk.KVStorePrefixIterator(tstore, types.GetBlockFeesPrefix(isFarmingTransaction))
+	defer iterator.Close()
+
+	fees := sdk.NewCoins()
+	for ; iterator.Valid(); iterator.Next() {
+		var fee sdk.Coin
+		k.cdc.MustUnmarshal(iterator.Value(), &fee)
+		fees = fees.Add(fee)
+	}
+	return fees
+}
+
+// RouteBlockFees routes the fees of the block's delivered transactions out of
+// the fee collector with ProcessTransactionFees, once for the general and
+// once for the farming fees. EndBlocker calls it, so the sends and ledger
+// writes per validator and LP pool are made once per block rather than once
+// per transaction. If routing fails the fees stay in the fee collector, where
+// x/distribution allocates them at the next block.
+func (k Keeper) RouteBlockFees(ctx sdk.Context) {
+	for _, isFarming := range []bool{false, true} {
+		fees := k.GetBlockFees(ctx, isFarming)
+		if fees.IsZero() {
+			continue
+		}
+
+		cacheCtx, write := ctx.CacheContext()
+		if err := k.ProcessTransactionFees(cacheCtx, fees, isFarming); err != nil {
+			k.Logger(ctx).Error("Failed to route transaction fees, leaving them in the fee collector",
+				"fees", fees.String(),
+				"is_farming", isFarming,
+				"error", err,
+			)
+			continue
 		}
+		write()
+	}
+}
+
+// ProcessTransactionFees processes transaction fees according to GXR specification
+func (k Keeper) ProcessTransactionFees(ctx sdk.Context, fees sdk.Coins, isFarmingTransaction bool) error {
+	if fees.IsZero() {
+		return nil
 	}
 
+	split := k.SimulateFeeSplit(ctx, fees, isFarmingTransaction)
+	validatorAmount, dexAmount, posAmount, lpRewardAmount := split.Validators, split.Dex, split.Pos, split.LPRewards
+
 	// Distribute to validators
 	if err := k.distributeToValidators(ctx, validatorAmount); err != nil {
 		return fmt.Errorf("failed to distribute to validators: %w", err)
@@ -237,6 +448,8 @@ func (k Keeper) distributeToValidators(ctx sdk.Context, amount sdk.Coins) error
 				k.Logger(ctx).Error("Failed to send fee to validator", "validator", validator.OperatorAddress, "error", err)
 				continue
 			}
+
+			k.AddValidatorFeeTotal(ctx, validator.OperatorAddress, sdk.NewCoins(reward))
 		}
 	}
 
@@ -249,54 +462,60 @@ func (k Keeper) distributeToDEX(ctx sdk.Context, amount sdk.Coins) error {
 		return nil
 	}
 
-	// For now, keep in fee collector - will be handled by bot validator
-	// In production, this would be sent to specific DEX pool addresses
+	// Held in the feerouter module account for the bot's pool refills; left
+	// in the fee collector, x/distribution would sweep it at the next block
+	if err := k.bankKeeper.SendCoinsFromModuleToModule(ctx, authtypes.FeeCollectorName, types.ModuleName, amount); err != nil {
+		return err
+	}
+
 	k.Logger(ctx).Info("DEX fees allocated for auto refill", "amount", amount.String())
 	return nil
 }
 
-// distributeToPoS distributes fees to PoS pool (delegators)
+// distributeToPoS pays the PoS share to the delegators. It is left in the fee
+// collector, which x/distribution allocates at the next block to the bonded
+// validators by voting power, less the community tax and their commission,
+// as the halving delegator share is paid.
 func (k Keeper) distributeToPoS(ctx sdk.Context, amount sdk.Coins) error {
 	if amount.IsZero() {
 		return nil
 	}
 
-	// Add to distribution module fee pool for delegators
-	feePool := k.distrKeeper.GetFeePool(ctx)
-	feePool.CommunityPool = feePool.CommunityPool.Add(sdk.NewDecCoinsFromCoins(amount...)...)
-	k.distrKeeper.SetFeePool(ctx, feePool)
-
+	k.Logger(ctx).Info("PoS fees left in the fee collector for delegators", "amount", amount.String())
 	return nil
 }
 
 // distributeToLPRewards distributes fees to LP community rewards
 func (k Keeper) distributeToLPRewards(ctx sdk.Context, amount sdk.Coins) error {
+	k.DistributeToLPPools(ctx, authtypes.FeeCollectorName, amount)
+	return nil
+}
+
+// DistributeToLPPools distributes amount from the given module account equally
+// among active LP pools and returns the coins actually sent. Anything not sent
+// (no active pools, rounding dust, failed sends) stays in the module account.
+func (k Keeper) DistributeToLPPools(ctx sdk.Context, fromModule string, amount sdk.Coins) sdk.Coins {
+	distributed := sdk.NewCoins()
 	if amount.IsZero() {
-		return nil
+		return distributed
 	}
 
 	// Get active LP pools
-	pools := k.GetAllLPPools(ctx)
-	activePools := []types.LPPool{}
-	for _, pool := range pools {
-		if pool.Active {
-			activePools = append(activePools, pool)
-		}
-	}
-
+	activePools := k.getActiveLPPools(ctx)
 	if len(activePools) == 0 {
-		k.Logger(ctx).Info("No active LP pools found, keeping LP rewards in fee collector")
-		return nil
+		k.Logger(ctx).Info("No active LP pools found, keeping LP rewards in module account", "module", fromModule)
+		return distributed
 	}
 
 	// Distribute equally among active LP pools
+	rewards := make([]sdk.Coins, len(activePools))
 	for _, coin := range amount {
 		perPoolAmount := coin.Amount.QuoRaw(int64(len(activePools)))
 		if perPoolAmount.IsZero() {
 			continue
 		}
 
-		for _, pool := range activePools {
+		for i, pool := range activePools {
 			poolAddr, err := sdk.AccAddressFromBech32(pool.Address)
 			if err != nil {
 				k.Logger(ctx).Error("Invalid LP pool address", "address", pool.Address, "error", err)
@@ -304,41 +523,95 @@ func (k Keeper) distributeToLPRewards(ctx sdk.Context, amount sdk.Coins) error {
 			}
 
 			reward := sdk.NewCoin(coin.Denom, perPoolAmount)
-			if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, authtypes.FeeCollectorName, poolAddr, sdk.NewCoins(reward)); err != nil {
+			if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, fromModule, poolAddr, sdk.NewCoins(reward)); err != nil {
 				k.Logger(ctx).Error("Failed to send reward to LP pool", "pool", pool.Name, "error", err)
 				continue
 			}
 
-			// Update pool stats
-			pool.TotalRewards = pool.TotalRewards.Add(reward)
-			k.SetLPPool(ctx, pool)
+			rewards[i] = rewards[i].Add(reward)
+			distributed = distributed.Add(reward)
 		}
 	}
 
-	return nil
+	// Update pool stats, once per pool for all denoms
+	for i, pool := range activePools {
+		if !rewards[i].IsZero() {
+			k.addLPPoolRewards(ctx, pool.Address, rewards[i])
+		}
+	}
+
+	return distributed
 }
 
-// updateFeeStats updates the fee collection statistics
+// updateFeeStats adds a transaction's fees to the block's fee statistics in
+// the transient store; FlushBlockFeeStats adds them to the stored statistics
+// at the end of the block
 func (k Keeper) updateFeeStats(ctx sdk.Context, totalFees, validatorAmount, dexAmount, posAmount, lpRewardAmount sdk.Coins) {
+	tstore := ctx.TransientStore(k.transientKey)
+	stats := types.DefaultFeeStats()
+	if bz := tstore.Get(types.BlockFeeStatsKey); bz != nil {
+		k.cdc.MustUnmarshal(bz, &stats)
+	}
+
+	stats = stats.Add(types.FeeStats{
+		TotalCollected:    totalFees,
+		TotalToValidators: validatorAmount,
+		TotalToDex:        dexAmount,
+		TotalToPos:        posAmount,
+		TotalToLPRewards:  lpRewardAmount,
+	})
+
+	tstore.Set(types.BlockFeeStatsKey, k.cdc.MustMarshal(&stats))
+}
+
+// FlushBlockFeeStats adds the fee statistics of the block's transactions to
+// the stored statistics, so they are written once per block rather than once
+// per transaction. EndBlocker calls it; fees routed after the feerouter
+// EndBlocker would be dropped with the transient store.
+func (k Keeper) FlushBlockFeeStats(ctx sdk.Context) {
+	tstore := ctx.TransientStore(k.transientKey)
+	bz := tstore.Get(types.BlockFeeStatsKey)
+	if bz == nil {
+		return
+	}
+
+	var block types.FeeStats
+	k.cdc.MustUnmarshal(bz, &block)
+
 	stats, found := k.GetFeeStats(ctx)
 	if !found {
 		stats = types.DefaultFeeStats()
 	}
 
-	stats.TotalCollected = stats.TotalCollected.Add(totalFees...)
-	stats.TotalToValidators = stats.TotalToValidators.Add(validatorAmount...)
-	stats.TotalToDex = stats.TotalToDex.Add(dexAmount...)
-	stats.TotalToPos = stats.TotalToPos.Add(posAmount...)
-	stats.TotalToLPRewards = stats.TotalToLPRewards.Add(lpRewardAmount...)
-
-	k.SetFeeStats(ctx, stats)
+	k.SetFeeStats(ctx, stats.Add(block))
+	tstore.Delete(types.BlockFeeStatsKey)
 }
 
-// IsFarmingTransaction determines if a transaction is a farming transaction
-// This is a simplified implementation - in production this would check
-// specific transaction types or message types
+// IsFarmingTransaction reports whether tx is an LP farming transaction: a
+// bank send from or to an active LP pool
 func (k Keeper) IsFarmingTransaction(ctx sdk.Context, tx sdk.Tx) bool {
-	// For now, return false - this would be implemented based on
-	// specific criteria for identifying LP farming transactions
+	for _, msg := range tx.GetMsgs() {
+		var addresses []string
+		switch msg := msg.(type) {
+		case *banktypes.MsgSend:
+			addresses = []string{msg.FromAddress, msg.ToAddress}
+		case *banktypes.MsgMultiSend:
+			for _, input := range msg.Inputs {
+				addresses = append(addresses, input.Address)
+			}
+			for _, output := range msg.Outputs {
+				addresses = append(addresses, output.Address)
+			}
+		default:
+			continue
+		}
+
+		for _, address := range addresses {
+			if k.IsActiveLPPool(ctx, address) {
+				return true
+			}
+		}
+	}
+
 	return false
-}
\ No newline at end of file
+}
@@ -0,0 +1,227 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/store"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter"
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+// genesisTime is the block time used by keeper tests
+var genesisTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testModuleAccounts are the module accounts known to the test account keeper
+var testModuleAccounts = map[string][]string{
+	authtypes.FeeCollectorName: nil,
+	distrtypes.ModuleName:      nil,
+}
+
+// setupKeeper creates a feerouter keeper backed by an in-memory store. The
+// module store, params and accounts are available; bank, staking and
+// distribution are not wired.
+func setupKeeper(t testing.TB) (keeper.Keeper, sdk.Context) {
+	k, ctx, _ := setupKeeperWithAccounts(t)
+	return k, ctx
+}
+
+// setupKeeperWithAccounts is setupKeeper that also returns the account keeper
+func setupKeeperWithAccounts(t testing.TB) (keeper.Keeper, sdk.Context, authkeeper.AccountKeeper) {
+	storeKey := sdk.NewKVStoreKey(types.StoreKey)
+	transientKey := sdk.NewTransientStoreKey(types.TStoreKey)
+	authKey := sdk.NewKVStoreKey(authtypes.StoreKey)
+	paramsKey := sdk.NewKVStoreKey(paramstypes.StoreKey)
+	paramsTKey := sdk.NewTransientStoreKey(paramstypes.TStoreKey)
+
+	db := dbm.NewMemDB()
+	stateStore := store.NewCommitMultiStore(db)
+	stateStore.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(transientKey, storetypes.StoreTypeTransient, nil)
+	stateStore.MountStoreWithDB(authKey, storetypes.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(paramsKey, storetypes.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(paramsTKey, storetypes.StoreTypeTransient, nil)
+	require.NoError(t, stateStore.LoadLatestVersion())
+
+	registry := codectypes.NewInterfaceRegistry()
+	authtypes.RegisterInterfaces(registry)
+	vestingtypes.RegisterInterfaces(registry)
+	cdc := codec.NewProtoCodec(registry)
+	amino := codec.NewLegacyAmino()
+
+	authSubspace := paramstypes.NewSubspace(cdc, amino, paramsKey, paramsTKey, authtypes.ModuleName)
+	accountKeeper := authkeeper.NewAccountKeeper(cdc, authKey, authSubspace, authtypes.ProtoBaseAccount, testModuleAccounts, "gxr")
+
+	subspace := paramstypes.NewSubspace(cdc, amino, paramsKey, paramsTKey, types.ModuleName)
+	k := keeper.NewKeeper(cdc, storeKey, transientKey, subspace, accountKeeper, nil, nil, testAuthority)
+
+	ctx := sdk.NewContext(stateStore, tmproto.Header{Time: genesisTime}, false, log.NewNopLogger())
+	k.SetParams(ctx, types.DefaultParams())
+
+	return k, ctx, accountKeeper
+}
+
+// testAuthority is the address allowed to sign feerouter Msgs in tests
+var testAuthority = sdk.AccAddress([]byte("feerouter-authority")).String()
+
+// testValidators returns deterministic validator operator addresses
+func testValidators() []string {
+	return []string{
+		sdk.ValAddress([]byte("validator-address-01")).String(),
+		sdk.ValAddress([]byte("validator-address-02")).String(),
+		sdk.ValAddress([]byte("validator-address-03")).String(),
+	}
+}
+
+func TestValidatorFeeTotalsAcrossBlocks(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	vals := testValidators()
+
+	// Three blocks of routed fees; the third validator only joins in block 2
+	for height := int64(1); height <= 3; height++ {
+		ctx = ctx.WithBlockHeight(height).WithBlockTime(genesisTime.Add(time.Duration(height) * 5 * time.Second))
+
+		k.AddValidatorFeeTotal(ctx, vals[0], sdk.NewCoins(sdk.NewInt64Coin("ugen", 100)))
+		k.AddValidatorFeeTotal(ctx, vals[1], sdk.NewCoins(sdk.NewInt64Coin("ugen", 100), sdk.NewInt64Coin("uatom", 7)))
+		if height >= 2 {
+			k.AddValidatorFeeTotal(ctx, vals[2], sdk.NewCoins(sdk.NewInt64Coin("ugen", 50)))
+		}
+
+		// Zero amounts never create entries
+		k.AddValidatorFeeTotal(ctx, vals[2], sdk.NewCoins())
+	}
+
+	total, found := k.GetValidatorFeeTotal(ctx, vals[0])
+	require.True(t, found)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("ugen", 300)), total.Total)
+
+	total, found = k.GetValidatorFeeTotal(ctx, vals[1])
+	require.True(t, found)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("ugen", 300), sdk.NewInt64Coin("uatom", 21)), total.Total)
+
+	total, found = k.GetValidatorFeeTotal(ctx, vals[2])
+	require.True(t, found)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("ugen", 100)), total.Total)
+
+	_, found = k.GetValidatorFeeTotal(ctx, sdk.ValAddress([]byte("validator-address-99")).String())
+	require.False(t, found)
+
+	require.Len(t, k.GetAllValidatorFeeTotals(ctx), 3)
+}
+
+func TestQueryValidatorFeeTotals(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	wctx := sdk.WrapSDKContext(ctx)
+	vals := testValidators()
+
+	for _, val := range vals {
+		k.AddValidatorFeeTotal(ctx, val, sdk.NewCoins(sdk.NewInt64Coin("ugen", 10)))
+	}
+
+	_, err := k.ValidatorFeeTotals(wctx, nil)
+	require.Error(t, err)
+
+	// Page through the totals two at a time
+	res, err := k.ValidatorFeeTotals(wctx, &types.QueryValidatorFeeTotalsRequest{
+		Pagination: &query.PageRequest{Limit: 2, CountTotal: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.ValidatorFeeTotals, 2)
+	require.Equal(t, uint64(3), res.Pagination.Total)
+	require.NotNil(t, res.Pagination.NextKey)
+
+	res, err = k.ValidatorFeeTotals(wctx, &types.QueryValidatorFeeTotalsRequest{
+		Pagination: &query.PageRequest{Key: res.Pagination.NextKey, Limit: 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.ValidatorFeeTotals, 1)
+
+	single, err := k.ValidatorFeeTotal(wctx, &types.QueryValidatorFeeTotalRequest{ValidatorAddress: vals[1]})
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("ugen", 10)), single.ValidatorFeeTotal.Total)
+
+	// Validators that never received fees report an empty total
+	unknown := sdk.ValAddress([]byte("validator-address-99")).String()
+	single, err = k.ValidatorFeeTotal(wctx, &types.QueryValidatorFeeTotalRequest{ValidatorAddress: unknown})
+	require.NoError(t, err)
+	require.True(t, single.ValidatorFeeTotal.Total.IsZero())
+
+	_, err = k.ValidatorFeeTotal(wctx, &types.QueryValidatorFeeTotalRequest{ValidatorAddress: "not-an-address"})
+	require.Error(t, err)
+}
+
+func TestValidatorFeeTotalsGenesisRoundTrip(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	vals := testValidators()
+
+	k.AddValidatorFeeTotal(ctx, vals[0], sdk.NewCoins(sdk.NewInt64Coin("ugen", 1234)))
+	k.AddValidatorFeeTotal(ctx, vals[1], sdk.NewCoins(sdk.NewInt64Coin("ugen", 42)))
+
+	exported := feerouter.ExportGenesis(ctx, k)
+	require.NoError(t, exported.Validate())
+	require.Len(t, exported.ValidatorFeeTotals, 2)
+
+	k2, ctx2 := setupKeeper(t)
+	feerouter.InitGenesis(ctx2, k2, *exported)
+
+	require.Equal(t, exported.ValidatorFeeTotals, feerouter.ExportGenesis(ctx2, k2).ValidatorFeeTotals)
+}
+
+func TestGenesisRejectsInvalidLPPools(t *testing.T) {
+	pool := sdk.AccAddress([]byte("lp-pool-gxr-ton")).String()
+	valid := types.DefaultGenesisState()
+	valid.LPPools = []types.LPPool{{Address: pool, Name: "GXR/TON", Active: true}}
+	require.NoError(t, valid.Validate())
+
+	invalid := *valid
+	invalid.LPPools = []types.LPPool{{Address: "not-an-address", Name: "GXR/BAD", Active: true}}
+	require.Error(t, invalid.Validate())
+
+	invalid.LPPools = []types.LPPool{valid.LPPools[0], {Address: pool, Name: "GXR/TON v2", Active: true}}
+	require.ErrorContains(t, invalid.Validate(), "duplicate LP pool")
+
+	// A module account passes stateless validation but is refused at init
+	k, ctx := setupKeeper(t)
+	invalid.LPPools = []types.LPPool{{Address: authtypes.NewModuleAddress(distrtypes.ModuleName).String(), Name: "GXR/DISTR", Active: true}}
+	require.NoError(t, invalid.Validate())
+	require.Panics(t, func() { feerouter.InitGenesis(ctx, k, invalid) })
+	require.Empty(t, k.GetAllLPPools(ctx))
+
+	feerouter.InitGenesis(ctx, k, *valid)
+	require.Len(t, k.GetAllLPPools(ctx), 1)
+}
+
+func TestSetParamsRefreshesCachedParams(t *testing.T) {
+	k, ctx := setupKeeper(t)
+
+	// The first read caches the params for the block
+	params := k.GetParams(ctx)
+	require.Equal(t, types.DefaultParams(), params)
+
+	// An update in the same block is seen by later transactions
+	params.RemainderToPos = !params.RemainderToPos
+	params.RoundingStrategy = "bankers"
+	k.SetParams(ctx, params)
+	require.Equal(t, params, k.GetParams(ctx))
+
+	// Without routed fees the end of the block writes no stats
+	k.FlushBlockFeeStats(ctx)
+	_, found := k.GetFeeStats(ctx)
+	require.False(t, found)
+}
@@ -0,0 +1,35 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+// Migrator migrates the feerouter store between consensus versions
+type Migrator struct {
+	keeper Keeper
+}
+
+// NewMigrator returns a Migrator for the keeper
+func NewMigrator(keeper Keeper) Migrator {
+	return Migrator{keeper: keeper}
+}
+
+// Migrate1to2 copies the params from the legacy x/params subspace to the
+// module store, the defaults if the subspace was never initialized. The
+// legacy copy is kept and kept in sync until the following upgrade deletes
+// it. Params that do not validate fail the upgrade rather than the first
+// block after it.
+func (m Migrator) Migrate1to2(ctx sdk.Context) error {
+	params := m.keeper.legacyParams(ctx)
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("invalid legacy %s params: %w", types.ModuleName, err)
+	}
+
+	m.keeper.setStoreParams(ctx, params)
+	m.keeper.Logger(ctx).Info("Migrated params to the module store", "legacy_copy", m.keeper.hasLegacyParams(ctx))
+	return nil
+}
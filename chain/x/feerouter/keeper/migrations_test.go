@@ -0,0 +1,80 @@
+package keeper_test
+
+import (
+	"testing"
+
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/store"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+// setupParamsMigration returns a keeper whose params are in neither store
+// yet, and its legacy subspace
+func setupParamsMigration(t *testing.T) (keeper.Keeper, sdk.Context, paramstypes.Subspace) {
+	storeKey := sdk.NewKVStoreKey(types.StoreKey)
+	transientKey := sdk.NewTransientStoreKey(types.TStoreKey)
+	paramsKey := sdk.NewKVStoreKey(paramstypes.StoreKey)
+	paramsTKey := sdk.NewTransientStoreKey(paramstypes.TStoreKey)
+
+	db := dbm.NewMemDB()
+	stateStore := store.NewCommitMultiStore(db)
+	stateStore.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(transientKey, storetypes.StoreTypeTransient, nil)
+	stateStore.MountStoreWithDB(paramsKey, storetypes.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(paramsTKey, storetypes.StoreTypeTransient, nil)
+	require.NoError(t, stateStore.LoadLatestVersion())
+
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	subspace := paramstypes.NewSubspace(cdc, codec.NewLegacyAmino(), paramsKey, paramsTKey, types.ModuleName).
+		WithKeyTable(types.ParamKeyTable())
+	k := keeper.NewKeeper(cdc, storeKey, transientKey, subspace, authkeeper.AccountKeeper{}, nil, nil, testAuthority)
+
+	ctx := sdk.NewContext(stateStore, tmproto.Header{Time: genesisTime}, false, log.NewNopLogger())
+	return k, ctx, subspace
+}
+
+func TestParamsMigrationMigratedChain(t *testing.T) {
+	k, ctx, subspace := setupParamsMigration(t)
+
+	legacy := types.DefaultParams()
+	legacy.RemainderToPos = !legacy.RemainderToPos
+	subspace.SetParamSet(ctx, &legacy)
+
+	require.NoError(t, keeper.NewMigrator(k).Migrate1to2(ctx))
+	require.Equal(t, legacy, k.GetParams(ctx))
+	_, broken := keeper.ParamsConsistencyInvariant(k)(ctx)
+	require.False(t, broken)
+
+	// Updates reach both copies and are visible in the same block
+	updated := legacy
+	updated.RemainderToPos = !updated.RemainderToPos
+	k.SetParams(ctx, updated)
+	require.Equal(t, updated, k.GetParams(ctx))
+	var remainderToPos bool
+	subspace.Get(ctx, types.KeyRemainderToPos, &remainderToPos)
+	require.Equal(t, updated.RemainderToPos, remainderToPos)
+	_, broken = keeper.ParamsConsistencyInvariant(k)(ctx)
+	require.False(t, broken)
+}
+
+func TestParamsMigrationUninitializedSubspace(t *testing.T) {
+	k, ctx, subspace := setupParamsMigration(t)
+
+	// Reads before the migration fall back to the defaults instead of panicking
+	require.Equal(t, types.DefaultParams(), k.GetParams(ctx))
+
+	require.NoError(t, keeper.NewMigrator(k).Migrate1to2(ctx))
+	require.Equal(t, types.DefaultParams(), k.GetParams(ctx))
+	require.False(t, subspace.Has(ctx, types.KeyRemainderToPos))
+}
@@ -0,0 +1,66 @@
+package keeper
+
+import (
+	"context"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+type msgServer struct {
+	Keeper
+}
+
+var _ types.MsgServer = msgServer{}
+
+// NewMsgServerImpl returns an implementation of the feerouter MsgServer interface
+func NewMsgServerImpl(keeper Keeper) types.MsgServer {
+	return &msgServer{Keeper: keeper}
+}
+
+// RegisterLPPool adds an active LP pool; only the authority may register pools
+func (m msgServer) RegisterLPPool(goCtx context.Context, msg *types.MsgRegisterLPPool) (*types.MsgRegisterLPPoolResponse, error) {
+	if msg.Authority != m.authority {
+		return nil, errorsmod.Wrapf(types.ErrInvalidAuthority, "expected %s, got %s", m.authority, msg.Authority)
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	if _, found := m.GetLPPool(ctx, msg.Address); found {
+		return nil, errorsmod.Wrap(types.ErrLPPoolExists, msg.Address)
+	}
+	if err := m.ValidateLPPoolAddress(ctx, msg.Address); err != nil {
+		return nil, err
+	}
+
+	m.SetLPPool(ctx, types.LPPool{
+		Address: msg.Address,
+		Name:    msg.Name,
+		Active:  true,
+	})
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeLPPoolRegistered,
+		sdk.NewAttribute(types.AttributeKeyPoolName, msg.Name),
+		sdk.NewAttribute(types.AttributeKeyPoolAddress, msg.Address),
+	))
+
+	return &types.MsgRegisterLPPoolResponse{}, nil
+}
+
+// UpdateParams replaces the module params; only the authority may update them
+func (m msgServer) UpdateParams(goCtx context.Context, msg *types.MsgUpdateParams) (*types.MsgUpdateParamsResponse, error) {
+	if msg.Authority != m.authority {
+		return nil, errorsmod.Wrapf(types.ErrInvalidAuthority, "expected %s, got %s", m.authority, msg.Authority)
+	}
+	if err := msg.Params.Validate(); err != nil {
+		return nil, errorsmod.Wrap(types.ErrInvalidParams, err.Error())
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	m.SetParams(ctx, msg.Params)
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeParamsUpdated))
+
+	return &types.MsgUpdateParamsResponse{}, nil
+}
@@ -0,0 +1,96 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+func TestMsgServerRegisterLPPool(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	msgServer := keeper.NewMsgServerImpl(k)
+	pool := sdk.AccAddress([]byte("lp-pool-gxr-ton"))
+
+	// Only the authority may register pools
+	other := sdk.AccAddress([]byte("not-the-authority")).String()
+	_, err := msgServer.RegisterLPPool(sdk.WrapSDKContext(ctx), types.NewMsgRegisterLPPool(other, pool, "GXR/TON"))
+	require.ErrorIs(t, err, types.ErrInvalidAuthority)
+	require.Empty(t, k.GetAllLPPools(ctx))
+
+	_, err = msgServer.RegisterLPPool(sdk.WrapSDKContext(ctx), types.NewMsgRegisterLPPool(testAuthority, pool, "GXR/TON"))
+	require.NoError(t, err)
+	registered, found := k.GetLPPool(ctx, pool.String())
+	require.True(t, found)
+	require.Equal(t, "GXR/TON", registered.Name)
+	require.True(t, registered.Active)
+
+	_, err = msgServer.RegisterLPPool(sdk.WrapSDKContext(ctx), types.NewMsgRegisterLPPool(testAuthority, pool, "GXR/TON v2"))
+	require.ErrorIs(t, err, types.ErrLPPoolExists)
+}
+
+func TestMsgServerRegisterLPPoolRejectsUnsuitableAccounts(t *testing.T) {
+	k, ctx, accountKeeper := setupKeeperWithAccounts(t)
+	msgServer := keeper.NewMsgServerImpl(k)
+	wctx := sdk.WrapSDKContext(ctx)
+
+	register := func(address, name string) error {
+		_, err := msgServer.RegisterLPPool(wctx, &types.MsgRegisterLPPool{Authority: testAuthority, Address: address, Name: name})
+		return err
+	}
+
+	require.ErrorIs(t, register("not-an-address", "GXR/BAD"), types.ErrInvalidLPPool)
+
+	// Known module accounts are rejected before their account exists
+	feeCollector := authtypes.NewModuleAddress(authtypes.FeeCollectorName)
+	require.Nil(t, accountKeeper.GetAccount(ctx, feeCollector))
+	require.ErrorIs(t, register(feeCollector.String(), "GXR/FEES"), types.ErrLPPoolModuleAccount)
+
+	// As are module accounts only found in state
+	other := authtypes.NewEmptyModuleAccount("other-module")
+	accountKeeper.SetModuleAccount(ctx, other)
+	require.ErrorIs(t, register(other.GetAddress().String(), "GXR/OTHER"), types.ErrLPPoolModuleAccount)
+
+	// Vesting accounts would lock the rewards paid to them
+	vestingAddr := sdk.AccAddress([]byte("lp-pool-vesting"))
+	base := authtypes.NewBaseAccountWithAddress(vestingAddr)
+	vesting := vestingtypes.NewContinuousVestingAccount(base, sdk.NewCoins(sdk.NewInt64Coin("ugen", 1000)), ctx.BlockTime().Unix(), ctx.BlockTime().AddDate(1, 0, 0).Unix())
+	accountKeeper.SetAccount(ctx, vesting)
+	require.ErrorIs(t, register(vestingAddr.String(), "GXR/VEST"), types.ErrLPPoolVestingAccount)
+
+	require.Empty(t, k.GetAllLPPools(ctx))
+
+	// Plain accounts, existing or not, are accepted
+	existing := sdk.AccAddress([]byte("lp-pool-existing"))
+	accountKeeper.SetAccount(ctx, accountKeeper.NewAccountWithAddress(ctx, existing))
+	require.NoError(t, register(existing.String(), "GXR/USDT"))
+	require.NoError(t, register(sdk.AccAddress([]byte("lp-pool-new")).String(), "GXR/TON"))
+	require.Len(t, k.GetAllLPPools(ctx), 2)
+}
+
+func TestMsgServerUpdateParams(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	msgServer := keeper.NewMsgServerImpl(k)
+
+	params := types.DefaultParams()
+	params.GeneralDexShare = sdk.NewDecWithPrec(25, 2)
+	params.GeneralPosShare = sdk.NewDecWithPrec(35, 2)
+
+	other := sdk.AccAddress([]byte("not-the-authority")).String()
+	_, err := msgServer.UpdateParams(sdk.WrapSDKContext(ctx), types.NewMsgUpdateParams(other, params))
+	require.ErrorIs(t, err, types.ErrInvalidAuthority)
+	require.Equal(t, types.DefaultParams(), k.GetParams(ctx))
+
+	_, err = msgServer.UpdateParams(sdk.WrapSDKContext(ctx), types.NewMsgUpdateParams(testAuthority, params))
+	require.NoError(t, err)
+	require.Equal(t, params, k.GetParams(ctx))
+
+	params.GeneralPosShare = sdk.NewDecWithPrec(50, 2)
+	_, err = msgServer.UpdateParams(sdk.WrapSDKContext(ctx), types.NewMsgUpdateParams(testAuthority, params))
+	require.ErrorIs(t, err, types.ErrInvalidParams)
+}
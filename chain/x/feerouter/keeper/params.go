@@ -0,0 +1,68 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+// GetParams returns the module params. They are read from the module store
+// once per block and cached in the transient store, as every routed
+// transaction needs them. Until the migration to the module store has run,
+// they are read from the legacy x/params subspace and written through.
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	tstore := ctx.TransientStore(k.transientKey)
+	if bz := tstore.Get(types.BlockParamsKey); bz != nil {
+		k.cdc.MustUnmarshal(bz, &params)
+		return params
+	}
+
+	if bz := ctx.KVStore(k.storeKey).Get(types.FeeRouterParamsKey); bz != nil {
+		k.cdc.MustUnmarshal(bz, &params)
+	} else {
+		params = k.legacyParams(ctx)
+		k.setStoreParams(ctx, params)
+	}
+	tstore.Set(types.BlockParamsKey, k.cdc.MustMarshal(&params))
+	return params
+}
+
+// SetParams stores the params. While the legacy subspace still holds a copy,
+// it is updated too, so both sources agree until the copy is deleted.
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.setStoreParams(ctx, params)
+	if k.hasLegacyParams(ctx) {
+		k.paramstore.SetParamSet(ctx, &params)
+	}
+	ctx.TransientStore(k.transientKey).Delete(types.BlockParamsKey)
+}
+
+// setStoreParams writes the params to the module store
+func (k Keeper) setStoreParams(ctx sdk.Context, params types.Params) {
+	ctx.KVStore(k.storeKey).Set(types.FeeRouterParamsKey, k.cdc.MustMarshal(&params))
+}
+
+// hasLegacyParams reports whether the legacy subspace holds any param
+func (k Keeper) hasLegacyParams(ctx sdk.Context) bool {
+	if !k.paramstore.HasKeyTable() {
+		return false
+	}
+	var params types.Params
+	for _, pair := range params.ParamSetPairs() {
+		if k.paramstore.Has(ctx, pair.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyParams reads the params from the legacy subspace. Params the
+// subspace does not hold, all of them if it was never initialized, keep
+// their defaults.
+func (k Keeper) legacyParams(ctx sdk.Context) types.Params {
+	params := types.DefaultParams()
+	if k.hasLegacyParams(ctx) {
+		k.paramstore.GetParamSetIfExists(ctx, &params)
+	}
+	return params
+}
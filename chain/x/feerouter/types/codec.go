@@ -0,0 +1,41 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	cdctypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/msgservice"
+	authzcodec "github.com/cosmos/cosmos-sdk/x/authz/codec"
+)
+
+// RegisterLegacyAminoCodec registers the feerouter messages for amino JSON signing
+func RegisterLegacyAminoCodec(cdc *codec.LegacyAmino) {
+	cdc.RegisterConcrete(&MsgRegisterLPPool{}, "feerouter/RegisterLPPool", nil)
+	cdc.RegisterConcrete(&MsgUpdateParams{}, "feerouter/MsgUpdateParams", nil)
+}
+
+// RegisterInterfaces registers the feerouter messages and Msg service
+func RegisterInterfaces(registry cdctypes.InterfaceRegistry) {
+	registry.RegisterImplementations((*sdk.Msg)(nil),
+		&MsgRegisterLPPool{},
+		&MsgUpdateParams{},
+	)
+
+	msgservice.RegisterMsgServiceDesc(registry, &Msg_ServiceDesc)
+}
+
+var (
+	amino = codec.NewLegacyAmino()
+
+	// ModuleCdc is the amino codec used for legacy sign bytes
+	ModuleCdc = codec.NewAminoCodec(amino)
+)
+
+func init() {
+	RegisterLegacyAminoCodec(amino)
+	amino.Seal()
+
+	// A MsgExec carrying these messages, e.g. from a bot key acting for the
+	// operator, is signed with amino JSON by hardware wallets
+	RegisterLegacyAminoCodec(authzcodec.Amino)
+}
@@ -0,0 +1,119 @@
+package types_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/feerouter/types"
+)
+
+var testAuthority = sdk.AccAddress([]byte("feerouter-authority")).String()
+
+func TestMsgCodecRoundTrip(t *testing.T) {
+	params := types.DefaultParams()
+	params.GeneralDexShare = sdk.NewDecWithPrec(25, 2)
+	params.GeneralPosShare = sdk.NewDecWithPrec(35, 2)
+
+	testCases := []struct {
+		name     string
+		msg      sdk.Msg
+		decoded  sdk.Msg
+		typeURL  string
+		aminoKey string
+	}{
+		{
+			"register LP pool",
+			types.NewMsgRegisterLPPool(testAuthority, sdk.AccAddress([]byte("lp-pool-gxr-ton")), "GXR/TON"),
+			&types.MsgRegisterLPPool{},
+			"/gxr.feerouter.v1beta1.MsgRegisterLPPool",
+			"feerouter/RegisterLPPool",
+		},
+		{
+			"update params",
+			types.NewMsgUpdateParams(testAuthority, params),
+			&types.MsgUpdateParams{},
+			"/gxr.feerouter.v1beta1.MsgUpdateParams",
+			"feerouter/MsgUpdateParams",
+		},
+	}
+
+	registry := codectypes.NewInterfaceRegistry()
+	types.RegisterInterfaces(registry)
+	cdc := codec.NewProtoCodec(registry)
+	amino := codec.NewLegacyAmino()
+	types.RegisterLegacyAminoCodec(amino)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.msg.ValidateBasic())
+
+			// Proto binary
+			bz, err := cdc.Marshal(tc.msg)
+			require.NoError(t, err)
+			require.NoError(t, cdc.Unmarshal(bz, tc.decoded))
+			require.Equal(t, tc.msg, tc.decoded)
+
+			// Packed in an Any, as in a tx body
+			any, err := codectypes.NewAnyWithValue(tc.msg)
+			require.NoError(t, err)
+			require.Equal(t, tc.typeURL, any.TypeUrl)
+			var unpacked sdk.Msg
+			require.NoError(t, registry.UnpackAny(any, &unpacked))
+			require.Equal(t, tc.msg, unpacked)
+
+			// Amino JSON, used for ledger signing
+			jsonBz, err := amino.MarshalJSON(tc.msg)
+			require.NoError(t, err)
+			require.Contains(t, string(jsonBz), `"type":"`+tc.aminoKey+`"`)
+			fromJSON := reflect.New(reflect.TypeOf(tc.msg).Elem()).Interface()
+			require.NoError(t, amino.UnmarshalJSON(jsonBz, fromJSON))
+			require.Equal(t, tc.msg, fromJSON)
+
+			require.Equal(t, []sdk.AccAddress{sdk.MustAccAddressFromBech32(testAuthority)}, tc.msg.GetSigners())
+		})
+	}
+}
+
+func TestMsgRegisterLPPoolValidateBasic(t *testing.T) {
+	pool := sdk.AccAddress([]byte("lp-pool-gxr-ton")).String()
+	longName := make([]byte, types.MaxLPPoolNameLength+1)
+	for i := range longName {
+		longName[i] = 'a'
+	}
+
+	testCases := []struct {
+		name   string
+		msg    types.MsgRegisterLPPool
+		expErr bool
+	}{
+		{"valid", types.MsgRegisterLPPool{Authority: testAuthority, Address: pool, Name: "GXR/TON"}, false},
+		{"bad authority", types.MsgRegisterLPPool{Authority: "gxr1invalid", Address: pool, Name: "GXR/TON"}, true},
+		{"bad pool address", types.MsgRegisterLPPool{Authority: testAuthority, Address: "pool", Name: "GXR/TON"}, true},
+		{"empty name", types.MsgRegisterLPPool{Authority: testAuthority, Address: pool}, true},
+		{"long name", types.MsgRegisterLPPool{Authority: testAuthority, Address: pool, Name: string(longName)}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.msg.ValidateBasic()
+			if tc.expErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMsgUpdateParamsValidateBasic(t *testing.T) {
+	require.NoError(t, types.NewMsgUpdateParams(testAuthority, types.DefaultParams()).ValidateBasic())
+
+	params := types.DefaultParams()
+	params.GeneralDexShare = sdk.NewDecWithPrec(90, 2)
+	require.ErrorIs(t, types.NewMsgUpdateParams(testAuthority, params).ValidateBasic(), types.ErrInvalidParams)
+}
@@ -0,0 +1,15 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// Feerouter module errors
+var (
+	ErrInvalidAuthority     = errorsmod.Register(ModuleName, 2, "invalid authority")
+	ErrInvalidLPPool        = errorsmod.Register(ModuleName, 3, "invalid LP pool")
+	ErrLPPoolExists         = errorsmod.Register(ModuleName, 4, "LP pool already registered")
+	ErrInvalidParams        = errorsmod.Register(ModuleName, 5, "invalid params")
+	ErrLPPoolModuleAccount  = errorsmod.Register(ModuleName, 6, "LP pool address is a module account")
+	ErrLPPoolVestingAccount = errorsmod.Register(ModuleName, 7, "LP pool address is a vesting account")
+)
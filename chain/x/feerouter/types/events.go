@@ -0,0 +1,10 @@
+package types
+
+// Feerouter module event types and attributes
+const (
+	EventTypeLPPoolRegistered = "lp_pool_registered"
+	EventTypeParamsUpdated    = "params_updated"
+
+	AttributeKeyPoolName    = "pool_name"
+	AttributeKeyPoolAddress = "pool_address"
+)
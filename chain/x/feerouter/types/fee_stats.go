@@ -0,0 +1,12 @@
+package types
+
+// Add returns the sum of two fee stats
+func (s FeeStats) Add(other FeeStats) FeeStats {
+	return FeeStats{
+		TotalCollected:    s.TotalCollected.Add(other.TotalCollected...),
+		TotalToValidators: s.TotalToValidators.Add(other.TotalToValidators...),
+		TotalToDex:        s.TotalToDex.Add(other.TotalToDex...),
+		TotalToPos:        s.TotalToPos.Add(other.TotalToPos...),
+		TotalToLPRewards:  s.TotalToLPRewards.Add(other.TotalToLPRewards...),
+	}
+}
@@ -15,6 +15,8 @@ type Params struct {
 	FarmingDexShare       sdk.Dec `protobuf:"bytes,5,opt,name=farming_dex_share,json=farmingDexShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"farming_dex_share"`
 	FarmingLPRewardShare  sdk.Dec `protobuf:"bytes,6,opt,name=farming_lp_reward_share,json=farmingLpRewardShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"farming_lp_reward_share"`
 	FarmingPosShare       sdk.Dec `protobuf:"bytes,7,opt,name=farming_pos_share,json=farmingPosShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"farming_pos_share"`
+	RoundingStrategy      string  `protobuf:"bytes,8,opt,name=rounding_strategy,json=roundingStrategy,proto3" json:"rounding_strategy,omitempty"`
+	RemainderToPos        bool    `protobuf:"varint,9,opt,name=remainder_to_pos,json=remainderToPos,proto3" json:"remainder_to_pos,omitempty"`
 }
 
 // FeeStats tracks fee collection and distribution statistics
@@ -34,25 +36,39 @@ type LPPool struct {
 	TotalRewards sdk.Coins `protobuf:"bytes,4,rep,name=total_rewards,json=totalRewards,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"total_rewards"`
 }
 
+// ActiveLPPools is the per-block cache of the active LP pools kept in the
+// transient store. Reward totals are not cached.
+type ActiveLPPools struct {
+	Pools []LPPool `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools"`
+}
+
+// ValidatorFeeTotal tracks the cumulative fees routed to a single validator
+type ValidatorFeeTotal struct {
+	ValidatorAddress string    `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Total            sdk.Coins `protobuf:"bytes,2,rep,name=total,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"total"`
+}
+
 // GenesisState defines the feerouter module's genesis state.
 type GenesisState struct {
-	Params   Params   `protobuf:"bytes,1,opt,name=params,proto3" json:"params"`
-	FeeStats FeeStats `protobuf:"bytes,2,opt,name=fee_stats,json=feeStats,proto3" json:"fee_stats"`
-	LPPools  []LPPool `protobuf:"bytes,3,rep,name=lp_pools,json=lpPools,proto3" json:"lp_pools"`
+	Params             Params              `protobuf:"bytes,1,opt,name=params,proto3" json:"params"`
+	FeeStats           FeeStats            `protobuf:"bytes,2,opt,name=fee_stats,json=feeStats,proto3" json:"fee_stats"`
+	LPPools            []LPPool            `protobuf:"bytes,3,rep,name=lp_pools,json=lpPools,proto3" json:"lp_pools"`
+	ValidatorFeeTotals []ValidatorFeeTotal `protobuf:"bytes,4,rep,name=validator_fee_totals,json=validatorFeeTotals,proto3" json:"validator_fee_totals"`
 }
 
 // NewGenesisState creates a new GenesisState object
-func NewGenesisState(params Params, feeStats FeeStats, lpPools []LPPool) *GenesisState {
+func NewGenesisState(params Params, feeStats FeeStats, lpPools []LPPool, validatorFeeTotals []ValidatorFeeTotal) *GenesisState {
 	return &GenesisState{
-		Params:   params,
-		FeeStats: feeStats,
-		LPPools:  lpPools,
+		Params:             params,
+		FeeStats:           feeStats,
+		LPPools:            lpPools,
+		ValidatorFeeTotals: validatorFeeTotals,
 	}
 }
 
 // DefaultGenesisState returns a default genesis state
 func DefaultGenesisState() *GenesisState {
-	return NewGenesisState(DefaultParams(), DefaultFeeStats(), []LPPool{})
+	return NewGenesisState(DefaultParams(), DefaultFeeStats(), []LPPool{}, []ValidatorFeeTotal{})
 }
 
 // DefaultFeeStats returns default fee stats for genesis
@@ -73,14 +89,37 @@ func (gs GenesisState) Validate() error {
 	}
 
 	// Validate LP pools
+	seenPools := make(map[string]bool, len(gs.LPPools))
 	for i, pool := range gs.LPPools {
 		if pool.Address == "" {
 			return fmt.Errorf("LP pool %d has empty address", i)
 		}
+		if _, err := sdk.AccAddressFromBech32(pool.Address); err != nil {
+			return fmt.Errorf("LP pool %d has invalid address: %w", i, err)
+		}
+		if seenPools[pool.Address] {
+			return fmt.Errorf("duplicate LP pool %s", pool.Address)
+		}
+		seenPools[pool.Address] = true
 		if pool.Name == "" {
 			return fmt.Errorf("LP pool %d has empty name", i)
 		}
 	}
 
+	// Validate per-validator fee totals
+	seenValidators := make(map[string]bool, len(gs.ValidatorFeeTotals))
+	for i, total := range gs.ValidatorFeeTotals {
+		if _, err := sdk.ValAddressFromBech32(total.ValidatorAddress); err != nil {
+			return fmt.Errorf("validator fee total %d has invalid address: %w", i, err)
+		}
+		if seenValidators[total.ValidatorAddress] {
+			return fmt.Errorf("duplicate validator fee total for %s", total.ValidatorAddress)
+		}
+		seenValidators[total.ValidatorAddress] = true
+		if !total.Total.IsValid() {
+			return fmt.Errorf("validator fee total for %s has invalid coins: %s", total.ValidatorAddress, total.Total)
+		}
+	}
+
 	return nil
 }
\ No newline at end of file
@@ -1,5 +1,9 @@
 package types
 
+import (
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
 const (
 	// ModuleName defines the module name
 	ModuleName = "feerouter"
@@ -7,6 +11,9 @@ const (
 	// StoreKey defines the primary module store key
 	StoreKey = ModuleName
 
+	// TStoreKey defines the transient store key, holding per-block caches
+	TStoreKey = "transient_" + ModuleName
+
 	// RouterKey is the message route for fee router
 	RouterKey = ModuleName
 
@@ -14,9 +21,55 @@ const (
 	QuerierRoute = ModuleName
 )
 
+// ModuleAccountPermissions lists the module accounts the feerouter keeper moves
+// coins out of, with the permissions each one needs. LP pool payouts from other
+// modules' accounts are covered by those modules' own entries.
+var ModuleAccountPermissions = map[string][]string{
+	authtypes.FeeCollectorName: nil,
+}
+
 // KVStore keys
 var (
+	// FeeRouterParamsKey stores the module params, kept in the x/params
+	// subspace before consensus version 2
 	FeeRouterParamsKey = []byte{0x01}
 	FeeStatsKey        = []byte{0x02}
 	LPPoolsKey         = []byte{0x03}
-)
\ No newline at end of file
+
+	// ValidatorFeeTotalsKey prefixes the lifetime routed-fee totals per validator
+	ValidatorFeeTotalsKey = []byte{0x04}
+)
+
+// GetValidatorFeeTotalKey returns the store key for a validator's routed-fee total
+func GetValidatorFeeTotalKey(operatorAddress string) []byte {
+	return append(append([]byte{}, ValidatorFeeTotalsKey...), []byte(operatorAddress)...)
+}
+
+// Transient store keys, cleared after every block
+var (
+	// BlockParamsKey caches the params read from the module store
+	BlockParamsKey = []byte{0x01}
+	// BlockActivePoolsKey caches the active LP pools
+	BlockActivePoolsKey = []byte{0x02}
+	// BlockFeeStatsKey accumulates the fee stats of the block's transactions
+	BlockFeeStatsKey = []byte{0x03}
+	// BlockFeesKey prefixes the fees of the block's delivered transactions,
+	// by transaction type and denom, until they are routed at its end
+	BlockFeesKey = []byte{0x04}
+)
+
+// GetBlockFeesPrefix returns the transient store prefix of the block's
+// general or farming transaction fees
+func GetBlockFeesPrefix(isFarmingTransaction bool) []byte {
+	kind := byte(0)
+	if isFarmingTransaction {
+		kind = 1
+	}
+	return append(append([]byte{}, BlockFeesKey...), kind)
+}
+
+// GetBlockFeesKey returns the transient store key of the block's general or
+// farming transaction fees in denom
+func GetBlockFeesKey(isFarmingTransaction bool, denom string) []byte {
+	return append(GetBlockFeesPrefix(isFarmingTransaction), []byte(denom)...)
+}
@@ -0,0 +1,100 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	// TypeMsgRegisterLPPool is the type of MsgRegisterLPPool
+	TypeMsgRegisterLPPool = "register_lp_pool"
+	// TypeMsgUpdateParams is the type of MsgUpdateParams
+	TypeMsgUpdateParams = "update_params"
+
+	// MaxLPPoolNameLength bounds the name stored with an LP pool
+	MaxLPPoolNameLength = 64
+)
+
+var (
+	_ sdk.Msg = &MsgRegisterLPPool{}
+	_ sdk.Msg = &MsgUpdateParams{}
+)
+
+// NewMsgRegisterLPPool creates a new MsgRegisterLPPool
+func NewMsgRegisterLPPool(authority string, pool sdk.AccAddress, name string) *MsgRegisterLPPool {
+	return &MsgRegisterLPPool{
+		Authority: authority,
+		Address:   pool.String(),
+		Name:      name,
+	}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgRegisterLPPool) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgRegisterLPPool) Type() string { return TypeMsgRegisterLPPool }
+
+// GetSigners returns the module authority
+func (msg MsgRegisterLPPool) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.MustAccAddressFromBech32(msg.Authority)}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgRegisterLPPool) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic performs stateless checks. Whether the caller is the
+// authority and the pool is new is checked by the msg server.
+func (msg MsgRegisterLPPool) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid authority address: %s", err)
+	}
+	if _, err := sdk.AccAddressFromBech32(msg.Address); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid LP pool address: %s", err)
+	}
+	if msg.Name == "" {
+		return errorsmod.Wrap(ErrInvalidLPPool, "name cannot be empty")
+	}
+	if len(msg.Name) > MaxLPPoolNameLength {
+		return errorsmod.Wrapf(ErrInvalidLPPool, "name is %d bytes, max %d", len(msg.Name), MaxLPPoolNameLength)
+	}
+	return nil
+}
+
+// NewMsgUpdateParams creates a new MsgUpdateParams
+func NewMsgUpdateParams(authority string, params Params) *MsgUpdateParams {
+	return &MsgUpdateParams{
+		Authority: authority,
+		Params:    params,
+	}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgUpdateParams) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgUpdateParams) Type() string { return TypeMsgUpdateParams }
+
+// GetSigners returns the module authority
+func (msg MsgUpdateParams) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.MustAccAddressFromBech32(msg.Authority)}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgUpdateParams) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic checks the authority address and that the new params are valid
+func (msg MsgUpdateParams) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid authority address: %s", err)
+	}
+	if err := msg.Params.Validate(); err != nil {
+		return errorsmod.Wrap(ErrInvalidParams, err.Error())
+	}
+	return nil
+}
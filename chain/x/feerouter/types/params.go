@@ -6,6 +6,8 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
 	"gopkg.in/yaml.v2"
+
+	"github.com/Crocodile-ark/gxrchaind/internal/rounding"
 )
 
 // Parameter store keys
@@ -20,6 +22,10 @@ var (
 	KeyFarmingDexShare        = []byte("FarmingDexShare")
 	KeyFarmingLPRewardShare   = []byte("FarmingLPRewardShare")
 	KeyFarmingPosShare        = []byte("FarmingPosShare")
+
+	// Rounding of fee shares
+	KeyRoundingStrategy = []byte("RoundingStrategy")
+	KeyRemainderToPos   = []byte("RemainderToPos")
 )
 
 // Default parameter values for general transactions
@@ -37,6 +43,12 @@ const (
 	DefaultFarmingPosShare       = "0.20" // 20%
 )
 
+// Default rounding: truncate every share and give the remainder to PoS
+const (
+	DefaultRoundingStrategy = rounding.DefaultStrategy
+	DefaultRemainderToPos   = true
+)
+
 // DefaultParams returns a default set of parameters
 func DefaultParams() Params {
 	generalValidatorShare, _ := sdk.NewDecFromStr(DefaultGeneralValidatorShare)
@@ -56,6 +68,8 @@ func DefaultParams() Params {
 		FarmingDexShare:       farmingDexShare,
 		FarmingLPRewardShare:  farmingLPRewardShare,
 		FarmingPosShare:       farmingPosShare,
+		RoundingStrategy:      DefaultRoundingStrategy,
+		RemainderToPos:        DefaultRemainderToPos,
 	}
 }
 
@@ -101,6 +115,10 @@ func (p Params) Validate() error {
 		return fmt.Errorf("farming transaction shares must add up to 1.0, got %s", farmingTotal.String())
 	}
 
+	if err := validateRoundingStrategy(p.RoundingStrategy); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -121,6 +139,8 @@ func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
 		paramtypes.NewParamSetPair(KeyFarmingDexShare, &p.FarmingDexShare, validateShare),
 		paramtypes.NewParamSetPair(KeyFarmingLPRewardShare, &p.FarmingLPRewardShare, validateShare),
 		paramtypes.NewParamSetPair(KeyFarmingPosShare, &p.FarmingPosShare, validateShare),
+		paramtypes.NewParamSetPair(KeyRoundingStrategy, &p.RoundingStrategy, validateRoundingStrategy),
+		paramtypes.NewParamSetPair(KeyRemainderToPos, &p.RemainderToPos, validateRemainderToPos),
 	}
 }
 
@@ -139,4 +159,21 @@ func validateShare(i interface{}) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func validateRoundingStrategy(i interface{}) error {
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return rounding.ValidateStrategy(v)
+}
+
+func validateRemainderToPos(i interface{}) error {
+	if _, ok := i.(bool); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return nil
+}
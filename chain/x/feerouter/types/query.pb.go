@@ -1,6 +1,7 @@
 package types
 
 import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/query"
 )
 
@@ -29,4 +30,39 @@ type QueryLPPoolsRequest struct {
 type QueryLPPoolsResponse struct {
 	LPPools    []LPPool            `protobuf:"bytes,1,rep,name=lp_pools,json=lpPools,proto3" json:"lp_pools"`
 	Pagination *query.PageResponse `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
-}
\ No newline at end of file
+}
+
+// QueryValidatorFeeTotalRequest is the request type for the Query/ValidatorFeeTotal RPC method.
+type QueryValidatorFeeTotalRequest struct {
+	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+}
+
+// QueryValidatorFeeTotalResponse is the response type for the Query/ValidatorFeeTotal RPC method.
+type QueryValidatorFeeTotalResponse struct {
+	ValidatorFeeTotal ValidatorFeeTotal `protobuf:"bytes,1,opt,name=validator_fee_total,json=validatorFeeTotal,proto3" json:"validator_fee_total"`
+}
+
+// QueryValidatorFeeTotalsRequest is the request type for the Query/ValidatorFeeTotals RPC method.
+type QueryValidatorFeeTotalsRequest struct {
+	Pagination *query.PageRequest `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+// QueryValidatorFeeTotalsResponse is the response type for the Query/ValidatorFeeTotals RPC method.
+type QueryValidatorFeeTotalsResponse struct {
+	ValidatorFeeTotals []ValidatorFeeTotal `protobuf:"bytes,1,rep,name=validator_fee_totals,json=validatorFeeTotals,proto3" json:"validator_fee_totals"`
+	Pagination         *query.PageResponse `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+// QuerySimulateFeesRequest is the request type for the Query/SimulateFees RPC method.
+type QuerySimulateFeesRequest struct {
+	Fees      sdk.Coins `protobuf:"bytes,1,rep,name=fees,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"fees"`
+	IsFarming bool      `protobuf:"varint,2,opt,name=is_farming,json=isFarming,proto3" json:"is_farming,omitempty"`
+}
+
+// QuerySimulateFeesResponse is the response type for the Query/SimulateFees RPC method.
+type QuerySimulateFeesResponse struct {
+	ValidatorAmount sdk.Coins `protobuf:"bytes,1,rep,name=validator_amount,json=validatorAmount,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"validator_amount"`
+	DexAmount       sdk.Coins `protobuf:"bytes,2,rep,name=dex_amount,json=dexAmount,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"dex_amount"`
+	PosAmount       sdk.Coins `protobuf:"bytes,3,rep,name=pos_amount,json=posAmount,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"pos_amount"`
+	LPRewardAmount  sdk.Coins `protobuf:"bytes,4,rep,name=lp_reward_amount,json=lpRewardAmount,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"lp_reward_amount"`
+}
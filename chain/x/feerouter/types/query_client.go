@@ -0,0 +1,238 @@
+package types
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// QueryServer defines the gRPC querier service for the feerouter module.
+type QueryServer interface {
+	Params(context.Context, *QueryParamsRequest) (*QueryParamsResponse, error)
+	FeeStats(context.Context, *QueryFeeStatsRequest) (*QueryFeeStatsResponse, error)
+	LPPools(context.Context, *QueryLPPoolsRequest) (*QueryLPPoolsResponse, error)
+	ValidatorFeeTotal(context.Context, *QueryValidatorFeeTotalRequest) (*QueryValidatorFeeTotalResponse, error)
+	ValidatorFeeTotals(context.Context, *QueryValidatorFeeTotalsRequest) (*QueryValidatorFeeTotalsResponse, error)
+	SimulateFees(context.Context, *QuerySimulateFeesRequest) (*QuerySimulateFeesResponse, error)
+}
+
+// QueryClient defines the gRPC querier client for the feerouter module.
+type QueryClient interface {
+	Params(ctx context.Context, in *QueryParamsRequest, opts ...grpc.CallOption) (*QueryParamsResponse, error)
+	FeeStats(ctx context.Context, in *QueryFeeStatsRequest, opts ...grpc.CallOption) (*QueryFeeStatsResponse, error)
+	LPPools(ctx context.Context, in *QueryLPPoolsRequest, opts ...grpc.CallOption) (*QueryLPPoolsResponse, error)
+	ValidatorFeeTotal(ctx context.Context, in *QueryValidatorFeeTotalRequest, opts ...grpc.CallOption) (*QueryValidatorFeeTotalResponse, error)
+	ValidatorFeeTotals(ctx context.Context, in *QueryValidatorFeeTotalsRequest, opts ...grpc.CallOption) (*QueryValidatorFeeTotalsResponse, error)
+	SimulateFees(ctx context.Context, in *QuerySimulateFeesRequest, opts ...grpc.CallOption) (*QuerySimulateFeesResponse, error)
+}
+
+type queryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewQueryClient creates a new QueryClient
+func NewQueryClient(cc grpc.ClientConnInterface) QueryClient {
+	return &queryClient{cc}
+}
+
+func (c *queryClient) Params(ctx context.Context, in *QueryParamsRequest, opts ...grpc.CallOption) (*QueryParamsResponse, error) {
+	out := new(QueryParamsResponse)
+	err := c.cc.Invoke(ctx, "/gxr.feerouter.v1beta1.Query/Params", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) FeeStats(ctx context.Context, in *QueryFeeStatsRequest, opts ...grpc.CallOption) (*QueryFeeStatsResponse, error) {
+	out := new(QueryFeeStatsResponse)
+	err := c.cc.Invoke(ctx, "/gxr.feerouter.v1beta1.Query/FeeStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) LPPools(ctx context.Context, in *QueryLPPoolsRequest, opts ...grpc.CallOption) (*QueryLPPoolsResponse, error) {
+	out := new(QueryLPPoolsResponse)
+	err := c.cc.Invoke(ctx, "/gxr.feerouter.v1beta1.Query/LPPools", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) ValidatorFeeTotal(ctx context.Context, in *QueryValidatorFeeTotalRequest, opts ...grpc.CallOption) (*QueryValidatorFeeTotalResponse, error) {
+	out := new(QueryValidatorFeeTotalResponse)
+	err := c.cc.Invoke(ctx, "/gxr.feerouter.v1beta1.Query/ValidatorFeeTotal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) ValidatorFeeTotals(ctx context.Context, in *QueryValidatorFeeTotalsRequest, opts ...grpc.CallOption) (*QueryValidatorFeeTotalsResponse, error) {
+	out := new(QueryValidatorFeeTotalsResponse)
+	err := c.cc.Invoke(ctx, "/gxr.feerouter.v1beta1.Query/ValidatorFeeTotals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) SimulateFees(ctx context.Context, in *QuerySimulateFeesRequest, opts ...grpc.CallOption) (*QuerySimulateFeesResponse, error) {
+	out := new(QuerySimulateFeesResponse)
+	err := c.cc.Invoke(ctx, "/gxr.feerouter.v1beta1.Query/SimulateFees", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegisterQueryServer registers the feerouter query server
+func RegisterQueryServer(s grpc.ServiceRegistrar, srv QueryServer) {
+	s.RegisterService(&Query_ServiceDesc, srv)
+}
+
+// Query_ServiceDesc is the grpc service descriptor for Query service.
+var Query_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gxr.feerouter.v1beta1.Query",
+	HandlerType: (*QueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Params",
+			Handler:    _Query_Params_Handler,
+		},
+		{
+			MethodName: "FeeStats",
+			Handler:    _Query_FeeStats_Handler,
+		},
+		{
+			MethodName: "LPPools",
+			Handler:    _Query_LPPools_Handler,
+		},
+		{
+			MethodName: "ValidatorFeeTotal",
+			Handler:    _Query_ValidatorFeeTotal_Handler,
+		},
+		{
+			MethodName: "ValidatorFeeTotals",
+			Handler:    _Query_ValidatorFeeTotals_Handler,
+		},
+		{
+			MethodName: "SimulateFees",
+			Handler:    _Query_SimulateFees_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gxr/feerouter/v1beta1/query.proto",
+}
+
+// Handler functions (normally generated by protoc)
+func _Query_Params_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Params(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.feerouter.v1beta1.Query/Params",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Params(ctx, req.(*QueryParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_FeeStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryFeeStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).FeeStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.feerouter.v1beta1.Query/FeeStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).FeeStats(ctx, req.(*QueryFeeStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_LPPools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryLPPoolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).LPPools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.feerouter.v1beta1.Query/LPPools",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).LPPools(ctx, req.(*QueryLPPoolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_ValidatorFeeTotal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryValidatorFeeTotalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).ValidatorFeeTotal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.feerouter.v1beta1.Query/ValidatorFeeTotal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).ValidatorFeeTotal(ctx, req.(*QueryValidatorFeeTotalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_ValidatorFeeTotals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryValidatorFeeTotalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).ValidatorFeeTotals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.feerouter.v1beta1.Query/ValidatorFeeTotals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).ValidatorFeeTotals(ctx, req.(*QueryValidatorFeeTotalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_SimulateFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuerySimulateFeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).SimulateFees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.feerouter.v1beta1.Query/SimulateFees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).SimulateFees(ctx, req.(*QuerySimulateFeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
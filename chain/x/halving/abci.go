@@ -3,13 +3,24 @@ package halving
 import (
 	"time"
 
+	abci "github.com/cometbft/cometbft/abci/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
 )
 
 // BeginBlocker checks for halving cycle advancement and distribution status
-func BeginBlocker(ctx sdk.Context, k keeper.Keeper) {
+func BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock, k keeper.Keeper) {
+	// Claw back the rewards of validators reported for double signing. This
+	// runs before the evidence module tombstones them and before a
+	// distribution in this block, which then excludes them.
+	for _, misbehavior := range req.ByzantineValidators {
+		switch misbehavior.Type {
+		case abci.MisbehaviorType_DUPLICATE_VOTE, abci.MisbehaviorType_LIGHT_CLIENT_ATTACK:
+			k.HandleEquivocation(ctx, sdk.ConsAddress(misbehavior.Validator.Address), misbehavior.Height, misbehavior.Time)
+		}
+	}
+
 	// Check if we need to advance to next halving cycle (every 5 years)
 	if err := k.CheckAndAdvanceHalvingCycle(ctx); err != nil {
 		k.Logger(ctx).Error("Failed to check halving cycle advancement", "error", err)
@@ -20,27 +31,44 @@ func BeginBlocker(ctx sdk.Context, k keeper.Keeper) {
 		k.Logger(ctx).Error("Failed to check distribution status", "error", err)
 	}
 
-	// Check if it's time for monthly distribution
-	if shouldDistributeMonthly(ctx) {
+	// Sample total supply for the supply floor forecast
+	if k.SupplySnapshotDue(ctx) {
+		k.RecordSupplySnapshot(ctx, k.GetCurrentTotalSupply(ctx))
+	}
+
+	// Drop heartbeat bitmaps that fell out of the retention window
+	k.PruneHeartbeatBitmaps(ctx)
+
+	// Fix who is eligible for the next distribution month's validator rewards
+	if k.EligibilitySnapshotDue(ctx) {
+		k.TakeEligibilitySnapshot(ctx)
+	}
+
+	// Check if it's time for monthly distribution. Missed months are caught up
+	// one per block without waiting for the trigger.
+	if shouldDistributeMonthly(ctx, k) || k.CatchUpPending(ctx) {
 		if err := k.DistributeHalvingRewards(ctx); err != nil {
 			k.Logger(ctx).Error("Failed to distribute monthly rewards", "error", err)
 		}
 	}
 }
 
-// shouldDistributeMonthly checks if it's time for monthly distribution
-func shouldDistributeMonthly(ctx sdk.Context) bool {
-	// Get the last distribution time from state
-	// For simplicity, we'll check if it's a new month (approximately every 30 days)
-	
-	// This is a simplified check - in production, you might want to store
-	// the last distribution time in the state and check against it
-	currentTime := ctx.BlockTime()
-	
-	// Check if it's the first day of a new month (simplified logic)
-	// In production, you'd store the last distribution timestamp
-	dayOfMonth := currentTime.Day()
-	
-	// Distribute on the 1st of each month
-	return dayOfMonth == 1
-}
\ No newline at end of file
+// EndBlocker rolls distribution records past the retention window up into
+// their cycle summary, a bounded number per block
+func EndBlocker(ctx sdk.Context, k keeper.Keeper) {
+	k.PruneDistributionRecords(ctx)
+}
+
+// shouldDistributeMonthly reports whether MonthlyDistributionTrigger has
+// passed since the last distribution (see NextMonthlyDistributionTime). Only
+// DistributeHalvingRewards moves the last distribution time, so the blocks
+// after a distribution do not distribute again, and a chain that was down
+// when the trigger passed distributes in its first block back.
+func shouldDistributeMonthly(ctx sdk.Context, k keeper.Keeper) bool {
+	info, found := k.GetHalvingInfo(ctx)
+	if !found || !info.DistributionActive {
+		return false
+	}
+
+	return !ctx.BlockTime().Before(k.NextMonthlyDistributionTime(ctx, info))
+}
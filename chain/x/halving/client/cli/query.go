@@ -2,11 +2,13 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
 )
@@ -26,6 +28,20 @@ func GetQueryCmd(queryRoute string) *cobra.Command {
 		CmdQueryParams(),
 		CmdQueryHalvingInfo(),
 		CmdQueryDistributionHistory(),
+		CmdQueryBotProtocolVersion(),
+		CmdQuerySupplyFloorForecast(),
+		CmdQueryPendingDexAllocation(),
+		CmdQueryDexDistributionBalance(),
+		CmdQueryValidatorUptime(),
+		CmdQueryHeartbeatCompliance(),
+		CmdQueryDexReserveWithdrawals(),
+		CmdQueryDexWithdrawalLimit(),
+		CmdQueryRewardEstimate(),
+		CmdQuerySimulateDistribution(),
+		CmdQueryDistributionRecord(),
+		CmdQueryClawbacks(),
+		CmdQueryBotDirectory(),
+		CmdQueryParamChangeHistory(),
 	)
 
 	return cmd
@@ -121,4 +137,473 @@ func CmdQueryDistributionHistory() *cobra.Command {
 	flags.AddPaginationFlagsToCmd(cmd, "distribution records")
 
 	return cmd
-}
\ No newline at end of file
+}
+
+// CmdQueryBotProtocolVersion implements the bot protocol version query command.
+func CmdQueryBotProtocolVersion() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bot-protocol-version",
+		Args:  cobra.NoArgs,
+		Short: "Query the bot protocol version expected by the chain",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.BotProtocolVersion(cmd.Context(), &types.QueryBotProtocolVersionRequest{})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQuerySupplyFloorForecast implements the supply floor forecast query command.
+func CmdQuerySupplyFloorForecast() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "supply-floor-forecast",
+		Args:  cobra.NoArgs,
+		Short: "Estimate when total supply reaches the halving minimum threshold",
+		Long: `Extrapolates the recent total supply trend to estimate when supply falls below
+the minimum threshold. The halving burns and re-mints the same amount each month,
+so it is supply-neutral: only burns outside the halving move supply toward the floor.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.SupplyFloorForecast(cmd.Context(), &types.QuerySupplyFloorForecastRequest{})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryPendingDexAllocation implements the pending DEX allocation query command.
+func CmdQueryPendingDexAllocation() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pending-dex-allocation [cycle]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Query the DEX share awaiting bot distribution",
+		Long: `Shows the DEX share the halving module kept for the bot to distribute in a
+cycle. Without an argument the current cycle is queried.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := &types.QueryPendingDexAllocationRequest{}
+			if len(args) == 1 {
+				req.Cycle, err = strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid cycle %q: %w", args[0], err)
+				}
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.PendingDexAllocation(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryDexDistributionBalance implements the dex_distribution balance query command.
+func CmdQueryDexDistributionBalance() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dex-distribution-balance",
+		Args:  cobra.NoArgs,
+		Short: "Query the dex_distribution account holding the pending DEX share",
+		Long: `Shows the address and balance of the dex_distribution module account, which
+holds the DEX share of the DEX window of a distribution period until the DEX
+reserve operator withdraws it, and the pending DEX share the balance covers.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.DexDistributionBalance(cmd.Context(), &types.QueryDexDistributionBalanceRequest{})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryValidatorUptime implements the validator uptime query command.
+func CmdQueryValidatorUptime() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validator-uptime [validator-address]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Query a validator's inactive days and declared downtime for the current month",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.ValidatorUptime(cmd.Context(), &types.QueryValidatorUptimeRequest{ValidatorAddress: args[0]})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryHeartbeatCompliance implements the heartbeat compliance query command.
+func CmdQueryHeartbeatCompliance() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "heartbeat-compliance [validator-address] [month]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "Query a validator's latest bot heartbeat and heartbeat coverage for an uptime month (default: current)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := &types.QueryHeartbeatComplianceRequest{ValidatorAddress: args[0]}
+			if len(args) == 2 {
+				req.Month, err = strconv.ParseUint(args[1], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid month %q: %w", args[1], err)
+				}
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.HeartbeatCompliance(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryDexReserveWithdrawals implements the DEX reserve withdrawals query command.
+func CmdQueryDexReserveWithdrawals() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dex-reserve-withdrawals",
+		Args:  cobra.NoArgs,
+		Short: "Query the DEX reserve withdrawals to LP pools",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.DexReserveWithdrawals(cmd.Context(), &types.QueryDexReserveWithdrawalsRequest{Pagination: pageReq})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "dex reserve withdrawals")
+
+	return cmd
+}
+
+// CmdQueryDexWithdrawalLimit implements the DEX withdrawal limit query command.
+func CmdQueryDexWithdrawalLimit() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dex-withdrawal-limit",
+		Args:  cobra.NoArgs,
+		Short: "Query how much the DEX reserve operator may still withdraw this epoch",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.DexWithdrawalLimit(cmd.Context(), &types.QueryDexWithdrawalLimitRequest{})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryRewardEstimate implements the reward estimate query command.
+func CmdQueryRewardEstimate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reward-estimate [validator-address] [additional-delegation]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "Estimate a validator's reward and the reward of delegating more to it in the next distribution month",
+		Long: `Estimates what the validator and an additional delegation of the given ugen
+amount to it receive from the next halving distribution month. The result is
+an estimate based on the current fund, stake and activity; the assumptions it
+relies on are listed with it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := &types.QueryRewardEstimateRequest{ValidatorAddress: args[0], AdditionalDelegation: sdk.ZeroInt()}
+			if len(args) == 2 {
+				amount, ok := sdk.NewIntFromString(args[1])
+				if !ok || amount.IsNegative() {
+					return fmt.Errorf("invalid additional delegation %q", args[1])
+				}
+				req.AdditionalDelegation = amount
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.RewardEstimate(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQuerySimulateDistribution implements the distribution simulation query command.
+func CmdQuerySimulateDistribution() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate-distribution",
+		Args:  cobra.NoArgs,
+		Short: "Simulate what the next halving distribution month would pay",
+		Long: `Runs the next unpaid distribution month with the chain's own distribution math
+on a discarded copy of the current state and prints the bucket totals, the
+payout of every eligible validator and the validators excluded. Nothing is
+written. Use --height to simulate on an earlier block.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.SimulateDistribution(cmd.Context(), &types.QuerySimulateDistributionRequest{})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryDistributionRecord implements the distribution record query command.
+func CmdQueryDistributionRecord() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "distribution-record [cycle] [distribution-month]",
+		Args:  cobra.ExactArgs(2),
+		Short: "Query the distribution record of a month of the distribution period",
+		Long: `Shows the distribution record of a month (1-24) of a cycle's distribution
+period. Records older than distribution_record_retention_cycles are rolled up
+into their cycle summary; for those the summary is shown with pruned: true.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := &types.QueryDistributionRecordRequest{}
+			req.Cycle, err = strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid cycle %q: %w", args[0], err)
+			}
+			req.DistributionMonth, err = strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid distribution month %q: %w", args[1], err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.DistributionRecord(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryClawbacks implements the equivocation clawbacks query command.
+func CmdQueryClawbacks() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clawbacks [validator-address]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Query a validator's reward ledger, or all outstanding equivocation clawbacks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := &types.QueryClawbacksRequest{}
+			if len(args) == 1 {
+				req.ValidatorAddress = args[0]
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.Clawbacks(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdQueryBotDirectory implements the bot directory query command.
+func CmdQueryBotDirectory() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bot-directory",
+		Args:  cobra.NoArgs,
+		Short: "Query the bot version, contact and endpoint registered by each validator",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.BotDirectory(cmd.Context(), &types.QueryBotDirectoryRequest{Pagination: pageReq})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "bot directory entries")
+
+	return cmd
+}
+
+// CmdQueryParamChangeHistory implements the param change history query command.
+func CmdQueryParamChangeHistory() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "param-changes",
+		Args:  cobra.NoArgs,
+		Short: "Query the governance changes of the distribution shares",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+
+			res, err := queryClient.ParamChangeHistory(cmd.Context(), &types.QueryParamChangeHistoryRequest{Pagination: pageReq})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "param changes")
+
+	return cmd
+}
@@ -2,14 +2,30 @@ package cli
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
 
 	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
 )
 
+// Flags of the halving param change proposal
+const (
+	FlagTitle     = "title"
+	FlagSummary   = "summary"
+	FlagDeposit   = "deposit"
+	FlagAuthority = "authority"
+)
+
 // GetTxCmd returns the transaction commands for this module
 func GetTxCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -20,8 +36,234 @@ func GetTxCmd() *cobra.Command {
 		RunE:                       client.ValidateCmd,
 	}
 
-	// Add transaction commands here if needed
-	// For now, halving module only has automatic functions
+	cmd.AddCommand(
+		CmdDeclareDowntime(),
+		CmdWithdrawDexReserve(),
+		CmdRegisterBotHeartbeat(),
+		CmdSetBotMetadata(),
+		CmdProposeHalvingParamChange(),
+	)
+
+	return cmd
+}
+
+// CmdDeclareDowntime implements the declare downtime transaction command.
+func CmdDeclareDowntime() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "declare-downtime [start] [end] [reason]",
+		Args:  cobra.MinimumNArgs(3),
+		Short: "Declare a planned downtime window for your validator",
+		Long: `Declares a planned maintenance window, signed with the validator operator key.
+Inactive days inside the window count at declared_downtime_weight toward the
+monthly inactivity threshold. Times are RFC3339; the window cannot start in
+the past and must lie within one uptime month.
+
+Example:
+$ gxrchaind tx halving declare-downtime 2025-03-10T02:00:00Z 2025-03-11T02:00:00Z "disk replacement" --from validator`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			start, err := time.Parse(time.RFC3339, args[0])
+			if err != nil {
+				return fmt.Errorf("invalid start %q: %w", args[0], err)
+			}
+			end, err := time.Parse(time.RFC3339, args[1])
+			if err != nil {
+				return fmt.Errorf("invalid end %q: %w", args[1], err)
+			}
+			reason := strings.Join(args[2:], " ")
+
+			msg := types.NewMsgDeclareDowntime(sdk.ValAddress(clientCtx.GetFromAddress()), start, end, reason)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdWithdrawDexReserve implements the DEX reserve withdrawal transaction command.
+func CmdWithdrawDexReserve() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "withdraw-dex-reserve [amount] [destination-pool]",
+		Args:  cobra.ExactArgs(2),
+		Short: "Withdraw pending DEX share to a registered LP pool",
+		Long: `Sends part of the DEX share held by the halving module to an active LP pool
+registered in the feerouter module. Must be signed by the dex_reserve_operator
+set in the halving params, and at most dex_withdrawal_cap can be withdrawn per
+dex_withdrawal_epoch. Query dex-withdrawal-limit for what is left.
+
+Example:
+$ gxrchaind tx halving withdraw-dex-reserve 100000000000ugen gxr1... --from dex-operator`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			amount, err := sdk.ParseCoinNormalized(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid amount %q: %w", args[0], err)
+			}
+			pool, err := sdk.AccAddressFromBech32(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid destination pool %q: %w", args[1], err)
+			}
+
+			msg := types.NewMsgWithdrawDexReserve(clientCtx.GetFromAddress(), amount, pool)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdRegisterBotHeartbeat implements the bot heartbeat transaction command.
+func CmdRegisterBotHeartbeat() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-bot-heartbeat",
+		Args:  cobra.NoArgs,
+		Short: "Record that your validator's bot is running",
+		Long: `Records a bot heartbeat, signed with the validator operator key. The bot counts
+as running until bot_heartbeat_timeout_blocks after the heartbeat's block; the
+response returns that height. The GXR bot sends one every heartbeat interval,
+so this is mainly for checking a validator setup by hand.
+
+Example:
+$ gxrchaind tx halving register-bot-heartbeat --from validator`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgRegisterBotHeartbeat(sdk.ValAddress(clientCtx.GetFromAddress()))
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdSetBotMetadata implements the bot metadata transaction command.
+func CmdSetBotMetadata() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-bot-metadata [version] [contact] [endpoint-url]",
+		Args:  cobra.RangeArgs(1, 3),
+		Short: "Register the version, contact and endpoint of your validator's bot",
+		Long: fmt.Sprintf(`Registers the bot your validator runs in the bot directory, signed with the
+validator operator key. The version is required, the contact and the http(s)
+endpoint are optional. A validator may update its entry once every %d blocks.
+The GXR bot registers itself on startup when bot_metadata is enabled.
+
+Example:
+$ gxrchaind tx halving set-bot-metadata v1.4.0 ops@validator.example https://bot.validator.example/status --from validator`,
+			types.BotMetadataUpdateIntervalBlocks),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			var contact, endpointURL string
+			if len(args) > 1 {
+				contact = args[1]
+			}
+			if len(args) > 2 {
+				endpointURL = args[2]
+			}
+
+			msg := types.NewMsgSetBotMetadata(sdk.ValAddress(clientCtx.GetFromAddress()), args[0], contact, endpointURL)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdProposeHalvingParamChange implements the halving param change proposal command.
+func CmdProposeHalvingParamChange() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "propose-halving-param-change [validator-share] [delegator-share] [dex-share]",
+		Args:  cobra.ExactArgs(3),
+		Short: "Submit a governance proposal to change the halving distribution shares",
+		Long: `Submits a governance proposal that executes MsgUpdateHalvingParams once it
+passes. The shares are decimals that must add up to 1.0; distributions after
+the proposal passed use the new shares. Every executed change is recorded in
+the param change history (gxrchaind query halving param-changes).
+
+Example:
+$ gxrchaind tx halving propose-halving-param-change 0.65 0.25 0.10 --title "Raise delegator share" --summary "..." --deposit 10000000ugen --from proposer`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			shares := make([]sdk.Dec, len(args))
+			for i, arg := range args {
+				shares[i], err = sdk.NewDecFromStr(arg)
+				if err != nil {
+					return fmt.Errorf("invalid share %q: %w", arg, err)
+				}
+			}
+
+			authority, _ := cmd.Flags().GetString(FlagAuthority)
+			msg := types.NewMsgUpdateHalvingParams(authority, shares[0], shares[1], shares[2])
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			title, _ := cmd.Flags().GetString(FlagTitle)
+			summary, _ := cmd.Flags().GetString(FlagSummary)
+			depositStr, _ := cmd.Flags().GetString(FlagDeposit)
+			deposit, err := sdk.ParseCoinsNormalized(depositStr)
+			if err != nil {
+				return fmt.Errorf("invalid deposit %q: %w", depositStr, err)
+			}
+
+			proposal, err := govv1.NewMsgSubmitProposal([]sdk.Msg{msg}, deposit, clientCtx.GetFromAddress().String(), "", title, summary)
+			if err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), proposal)
+		},
+	}
+
+	cmd.Flags().String(FlagTitle, "", "Title of the proposal")
+	cmd.Flags().String(FlagSummary, "", "Summary of the proposal")
+	cmd.Flags().String(FlagDeposit, "", "Initial deposit of the proposal, e.g. 10000000ugen")
+	cmd.Flags().String(FlagAuthority, authtypes.NewModuleAddress(govtypes.ModuleName).String(), "Authority executing the change, the gov module account by default")
+	_ = cmd.MarkFlagRequired(FlagTitle)
+	_ = cmd.MarkFlagRequired(FlagSummary)
+	flags.AddTxFlagsToCmd(cmd)
 
 	return cmd
-}
\ No newline at end of file
+}
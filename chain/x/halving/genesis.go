@@ -12,6 +12,9 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState types.GenesisState)
 	// Set module parameters
 	k.SetParams(ctx, genState.Params)
 
+	// Bot liveness is tracked from the first block
+	k.SetBotLivenessStart(ctx, ctx.BlockHeight())
+
 	// Set halving info
 	k.SetHalvingInfo(ctx, genState.HalvingInfo)
 
@@ -19,6 +22,84 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState types.GenesisState)
 	for _, record := range genState.DistributionRecords {
 		k.SetDistributionRecord(ctx, record)
 	}
+	for _, summary := range genState.DistributionCycleSummaries {
+		k.SetDistributionCycleSummary(ctx, summary)
+	}
+
+	// Set supply snapshots
+	for _, snapshot := range genState.SupplySnapshots {
+		k.SetSupplySnapshot(ctx, snapshot)
+	}
+
+	// Set the uptime records, so inactive days carry over an export
+	for _, uptime := range genState.ValidatorUptimes {
+		valAddr, err := sdk.ValAddressFromBech32(uptime.ValidatorAddress)
+		if err != nil {
+			panic(err)
+		}
+		k.SetValidatorUptime(ctx, valAddr, uptime)
+	}
+
+	// Set declared downtime windows
+	for _, declaration := range genState.DowntimeDeclarations {
+		valAddr, err := sdk.ValAddressFromBech32(declaration.ValidatorAddress)
+		if err != nil {
+			panic(err)
+		}
+		k.SetDowntimeDeclaration(ctx, valAddr, declaration)
+	}
+
+	// Set the latest heartbeats and the retained heartbeat bitmaps
+	for _, heartbeat := range genState.ValidatorHeartbeats {
+		valAddr, err := sdk.ValAddressFromBech32(heartbeat.ValidatorAddress)
+		if err != nil {
+			panic(err)
+		}
+		k.SetLastHeartbeat(ctx, valAddr, heartbeat)
+	}
+	for _, bitmap := range genState.HeartbeatBitmaps {
+		valAddr, err := sdk.ValAddressFromBech32(bitmap.ValidatorAddress)
+		if err != nil {
+			panic(err)
+		}
+		k.SetHeartbeatBitmap(ctx, valAddr, bitmap)
+	}
+
+	// Set the eligibility snapshot of the next distribution month
+	for _, snapshot := range genState.EligibilitySnapshots {
+		valAddr, err := sdk.ValAddressFromBech32(snapshot.ValidatorAddress)
+		if err != nil {
+			panic(err)
+		}
+		k.SetEligibilitySnapshot(ctx, valAddr, snapshot)
+	}
+
+	// Set the DEX reserve withdrawals and the current cap window
+	for _, withdrawal := range genState.DexReserveWithdrawals {
+		k.SetDexReserveWithdrawal(ctx, withdrawal)
+	}
+	if genState.DexWithdrawalEpoch.End != 0 {
+		k.SetDexWithdrawalEpoch(ctx, genState.DexWithdrawalEpoch)
+	}
+
+	// Set the reward ledgers with their outstanding clawbacks
+	for _, ledger := range genState.ValidatorRewardLedgers {
+		k.SetValidatorRewardLedger(ctx, ledger)
+	}
+
+	// Set the bot directory
+	for _, metadata := range genState.BotMetadata {
+		valAddr, err := sdk.ValAddressFromBech32(metadata.ValidatorAddress)
+		if err != nil {
+			panic(err)
+		}
+		k.SetBotMetadata(ctx, valAddr, metadata)
+	}
+
+	// Set the history of governance changes to the distribution shares
+	for _, record := range genState.ParamChangeRecords {
+		k.SetParamChangeRecord(ctx, record)
+	}
 }
 
 // ExportGenesis returns the halving module's exported genesis.
@@ -32,5 +113,53 @@ func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
 
 	genesis.DistributionRecords = k.GetAllDistributionRecords(ctx)
 
+	if summaries := k.GetAllDistributionCycleSummaries(ctx); summaries != nil {
+		genesis.DistributionCycleSummaries = summaries
+	}
+
+	if snapshots := k.GetSupplySnapshots(ctx); snapshots != nil {
+		genesis.SupplySnapshots = snapshots
+	}
+
+	if uptimes := k.GetAllValidatorUptimes(ctx); uptimes != nil {
+		genesis.ValidatorUptimes = uptimes
+	}
+
+	if declarations := k.GetAllDowntimeDeclarations(ctx); declarations != nil {
+		genesis.DowntimeDeclarations = declarations
+	}
+
+	if heartbeats := k.GetAllLastHeartbeats(ctx); heartbeats != nil {
+		genesis.ValidatorHeartbeats = heartbeats
+	}
+
+	if bitmaps := k.GetAllHeartbeatBitmaps(ctx); bitmaps != nil {
+		genesis.HeartbeatBitmaps = bitmaps
+	}
+
+	if snapshots := k.GetAllEligibilitySnapshots(ctx); snapshots != nil {
+		genesis.EligibilitySnapshots = snapshots
+	}
+
+	if withdrawals := k.GetAllDexReserveWithdrawals(ctx); withdrawals != nil {
+		genesis.DexReserveWithdrawals = withdrawals
+	}
+
+	if epoch, found := k.GetDexWithdrawalEpoch(ctx); found {
+		genesis.DexWithdrawalEpoch = epoch
+	}
+
+	if ledgers := k.GetAllValidatorRewardLedgers(ctx); ledgers != nil {
+		genesis.ValidatorRewardLedgers = ledgers
+	}
+
+	if entries := k.GetAllBotMetadata(ctx); entries != nil {
+		genesis.BotMetadata = entries
+	}
+
+	if records := k.GetAllParamChangeRecords(ctx); records != nil {
+		genesis.ParamChangeRecords = records
+	}
+
 	return genesis
 }
\ No newline at end of file
@@ -0,0 +1,112 @@
+package keeper
+
+import (
+	"context"
+
+	errorsmod "cosmossdk.io/errors"
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// UpdateBotMetadata registers or replaces a validator's bot metadata at the
+// current block. A validator may update it once per
+// BotMetadataUpdateIntervalBlocks, also when nothing changed, so that the
+// directory cannot be used to spam events.
+func (k Keeper) UpdateBotMetadata(ctx sdk.Context, valAddr sdk.ValAddress, version, contact, endpointURL string) (types.BotMetadata, error) {
+	if err := types.ValidateBotMetadataFields(version, contact, endpointURL); err != nil {
+		return types.BotMetadata{}, err
+	}
+
+	if previous, found := k.GetBotMetadata(ctx, valAddr); found {
+		if next := nextBotMetadataUpdateHeight(previous); ctx.BlockHeight() < next {
+			return types.BotMetadata{}, errorsmod.Wrapf(types.ErrBotMetadataRateLimited,
+				"last update at height %d, next allowed at height %d", previous.UpdatedHeight, next)
+		}
+	}
+
+	metadata := types.BotMetadata{
+		ValidatorAddress: valAddr.String(),
+		Version:          version,
+		Contact:          contact,
+		EndpointUrl:      endpointURL,
+		UpdatedHeight:    ctx.BlockHeight(),
+		UpdatedTime:      ctx.BlockTime().Unix(),
+	}
+	k.SetBotMetadata(ctx, valAddr, metadata)
+	return metadata, nil
+}
+
+// SetBotMetadata stores a validator's bot metadata
+func (k Keeper) SetBotMetadata(ctx sdk.Context, valAddr sdk.ValAddress, metadata types.BotMetadata) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&metadata)
+	store.Set(types.GetBotMetadataKey(valAddr), bz)
+}
+
+// GetBotMetadata returns a validator's bot metadata
+func (k Keeper) GetBotMetadata(ctx sdk.Context, valAddr sdk.ValAddress) (types.BotMetadata, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetBotMetadataKey(valAddr))
+	if bz == nil {
+		return types.BotMetadata{}, false
+	}
+
+	var metadata types.BotMetadata
+	k.cdc.MustUnmarshal(bz, &metadata)
+	return metadata, true
+}
+
+// GetAllBotMetadata returns the bot metadata of every validator that registered it
+func (k Keeper) GetAllBotMetadata(ctx sdk.Context) []types.BotMetadata {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.BotMetadataKey)
+	defer iterator.Close()
+
+	var entries []types.BotMetadata
+	for ; iterator.Valid(); iterator.Next() {
+		var metadata types.BotMetadata
+		k.cdc.MustUnmarshal(iterator.Value(), &metadata)
+		entries = append(entries, metadata)
+	}
+
+	return entries
+}
+
+// BotDirectory returns the registered bot metadata with pagination.
+func (k Keeper) BotDirectory(goCtx context.Context, req *types.QueryBotDirectoryRequest) (*types.QueryBotDirectoryResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	metadataStore := prefix.NewStore(ctx.KVStore(k.storeKey), types.BotMetadataKey)
+
+	entries := []types.BotMetadata{}
+	pageRes, err := query.Paginate(metadataStore, req.Pagination, func(key []byte, value []byte) error {
+		var metadata types.BotMetadata
+		if err := k.cdc.Unmarshal(value, &metadata); err != nil {
+			return err
+		}
+		entries = append(entries, metadata)
+		return nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryBotDirectoryResponse{
+		BotMetadata: entries,
+		Pagination:  pageRes,
+	}, nil
+}
+
+// nextBotMetadataUpdateHeight returns the first height a validator may
+// update its metadata at after the given update
+func nextBotMetadataUpdateHeight(metadata types.BotMetadata) int64 {
+	return metadata.UpdatedHeight + types.BotMetadataUpdateIntervalBlocks
+}
@@ -0,0 +1,288 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// HandleEquivocation claws back the halving rewards of the validator that
+// signed with consAddr for an equivocation reported by consensus. Unknown
+// validators and validators already tombstoned for an earlier infraction
+// are ignored.
+func (k Keeper) HandleEquivocation(ctx sdk.Context, consAddr sdk.ConsAddress, height int64, infractionTime time.Time) {
+	validator, found := k.stakingKeeper.GetValidatorByConsAddr(ctx, consAddr)
+	if !found {
+		k.Logger(ctx).Info("Ignoring equivocation of unknown validator", "consensus_address", consAddr.String(), "height", height)
+		return
+	}
+	if k.slashingKeeper != nil && k.slashingKeeper.IsTombstoned(ctx, consAddr) {
+		return
+	}
+
+	k.ClawBack(ctx, validator.GetOperator(), height, infractionTime)
+}
+
+// ClawBack records an equivocation of a validator and debits the halving
+// rewards it was paid in the uptime month of the infraction from its reward
+// ledger. The debit is withheld from the validator's future halving payouts.
+// An infraction at a height already recorded is ignored; the second return
+// value reports whether the infraction was recorded.
+func (k Keeper) ClawBack(ctx sdk.Context, valAddr sdk.ValAddress, height int64, infractionTime time.Time) (types.EquivocationInfraction, bool) {
+	ledger := k.GetValidatorRewardLedger(ctx, valAddr)
+	for _, infraction := range ledger.Infractions {
+		if infraction.Height == height {
+			return infraction, false
+		}
+	}
+
+	month := monthOf(infractionTime.Unix())
+	clawback := sdk.ZeroInt()
+	for i, payout := range ledger.Payouts {
+		if payout.Month != month || payout.ClawedBack {
+			continue
+		}
+		clawback = clawback.Add(payout.Amount.Amount)
+		ledger.Payouts[i].ClawedBack = true
+	}
+
+	infraction := types.EquivocationInfraction{
+		Height:         height,
+		Time:           infractionTime.Unix(),
+		Month:          month,
+		RecordedHeight: ctx.BlockHeight(),
+		Clawback:       sdk.NewCoin(MainDenom, clawback),
+	}
+	ledger.Infractions = append(ledger.Infractions, infraction)
+	ledger.Balance = ledger.Balance.Sub(clawback)
+	ledger.TotalClawedBack = ledger.TotalClawedBack.AddAmount(clawback)
+	k.SetValidatorRewardLedger(ctx, ledger)
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeEquivocationClawback,
+		sdk.NewAttribute(types.AttributeKeyValidator, valAddr.String()),
+		sdk.NewAttribute(types.AttributeKeyMonth, fmt.Sprintf("%d", month)),
+		sdk.NewAttribute(types.AttributeKeyHeight, fmt.Sprintf("%d", height)),
+		sdk.NewAttribute(types.AttributeKeyClawback, infraction.Clawback.String()),
+		sdk.NewAttribute(types.AttributeKeyOutstanding, sdk.NewCoin(MainDenom, ledger.Balance.Neg()).String()),
+	))
+
+	k.Logger(ctx).Info("Halving rewards clawed back for equivocation",
+		"validator", valAddr.String(),
+		"infraction_height", height,
+		"month", month,
+		"clawback", infraction.Clawback.String(),
+		"outstanding", ledger.Balance.Neg().String(),
+	)
+
+	return infraction, true
+}
+
+// equivocated reports whether a validator is excluded from halving rewards
+// for double signing: it is tombstoned, or it equivocated in the current or
+// previous uptime month, which is recorded before the evidence module
+// tombstones it.
+func (k Keeper) equivocated(ctx sdk.Context, valAddr sdk.ValAddress) bool {
+	if k.tombstoned(ctx, valAddr) {
+		return true
+	}
+
+	month := k.getCurrentMonth(ctx)
+	for _, infraction := range k.GetValidatorRewardLedger(ctx, valAddr).Infractions {
+		if infraction.Month+1 >= month {
+			return true
+		}
+	}
+	return false
+}
+
+// tombstoned reports whether a validator is tombstoned for double signing
+func (k Keeper) tombstoned(ctx sdk.Context, valAddr sdk.ValAddress) bool {
+	if k.slashingKeeper == nil || k.validators == nil {
+		return false
+	}
+	validator, found := k.validators.GetValidator(ctx, valAddr)
+	if !found {
+		return false
+	}
+	consAddr, err := validator.GetConsAddr()
+	return err == nil && k.slashingKeeper.IsTombstoned(ctx, consAddr)
+}
+
+// writeOffTombstonedClawbacks writes off the outstanding clawbacks of
+// tombstoned validators. They are never paid again, so nothing would ever be
+// withheld to settle the debt; the ledger balance returns to zero and the
+// written-off part is TotalClawedBack minus TotalRecovered.
+func (k Keeper) writeOffTombstonedClawbacks(ctx sdk.Context) {
+	for _, ledger := range k.GetAllValidatorRewardLedgers(ctx) {
+		if !ledger.Balance.IsNegative() {
+			continue
+		}
+		valAddr, err := sdk.ValAddressFromBech32(ledger.ValidatorAddress)
+		if err != nil || !k.tombstoned(ctx, valAddr) {
+			continue
+		}
+
+		writtenOff := sdk.NewCoin(MainDenom, ledger.Balance.Neg())
+		ledger.Balance = sdk.ZeroInt()
+		k.SetValidatorRewardLedger(ctx, ledger)
+
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeClawbackWrittenOff,
+			sdk.NewAttribute(types.AttributeKeyValidator, ledger.ValidatorAddress),
+			sdk.NewAttribute(types.AttributeKeyWrittenOff, writtenOff.String()),
+		))
+
+		k.Logger(ctx).Info("Clawback of tombstoned validator written off",
+			"validator", ledger.ValidatorAddress,
+			"written_off", writtenOff.String(),
+		)
+	}
+}
+
+// clawbackWithheld returns how much of a reward is withheld to settle the
+// validator's outstanding clawback
+func (k Keeper) clawbackWithheld(ctx sdk.Context, valAddr sdk.ValAddress, reward sdk.Int) sdk.Int {
+	owed := k.GetValidatorRewardLedger(ctx, valAddr).Balance.Neg()
+	if !owed.IsPositive() {
+		return sdk.ZeroInt()
+	}
+	return sdk.MinInt(reward, owed)
+}
+
+// recordValidatorPayout books a halving reward paid to a validator in its
+// ledger, along with the part withheld for an outstanding clawback. Payouts
+// before the previous uptime month can no longer be clawed back and are
+// dropped.
+func (k Keeper) recordValidatorPayout(ctx sdk.Context, valAddr sdk.ValAddress, info types.HalvingInfo, month uint64, paid, withheld sdk.Int) {
+	ledger := k.GetValidatorRewardLedger(ctx, valAddr)
+	currentMonth := k.getCurrentMonth(ctx)
+
+	payouts := ledger.Payouts[:0]
+	for _, payout := range ledger.Payouts {
+		if payout.Month+1 >= currentMonth {
+			payouts = append(payouts, payout)
+		}
+	}
+	ledger.Payouts = payouts
+	if paid.IsPositive() {
+		ledger.Payouts = append(ledger.Payouts, types.LedgerPayout{
+			Month:             currentMonth,
+			Cycle:             info.CurrentCycle,
+			DistributionMonth: month,
+			PaidAt:            ctx.BlockTime().Unix(),
+			Amount:            sdk.NewCoin(MainDenom, paid),
+		})
+	}
+
+	if withheld.IsPositive() {
+		ledger.Balance = ledger.Balance.Add(withheld)
+		ledger.TotalRecovered = ledger.TotalRecovered.AddAmount(withheld)
+
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeClawbackSettled,
+			sdk.NewAttribute(types.AttributeKeyValidator, valAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyMonth, fmt.Sprintf("%d", month)),
+			sdk.NewAttribute(types.AttributeKeyWithheld, sdk.NewCoin(MainDenom, withheld).String()),
+			sdk.NewAttribute(types.AttributeKeyOutstanding, sdk.NewCoin(MainDenom, ledger.Balance.Neg()).String()),
+		))
+	}
+
+	if len(ledger.Payouts) == 0 && len(ledger.Infractions) == 0 && ledger.Balance.IsZero() {
+		ctx.KVStore(k.storeKey).Delete(types.GetValidatorRewardLedgerKey(valAddr))
+		return
+	}
+	k.SetValidatorRewardLedger(ctx, ledger)
+}
+
+// newValidatorRewardLedger returns the empty reward ledger of a validator
+func newValidatorRewardLedger(valAddr sdk.ValAddress) types.ValidatorRewardLedger {
+	zero := sdk.NewCoin(MainDenom, sdk.ZeroInt())
+	return types.ValidatorRewardLedger{
+		ValidatorAddress: valAddr.String(),
+		Balance:          sdk.ZeroInt(),
+		TotalClawedBack:  zero,
+		TotalRecovered:   zero,
+	}
+}
+
+// GetValidatorRewardLedger returns a validator's reward ledger, empty if none is stored
+func (k Keeper) GetValidatorRewardLedger(ctx sdk.Context, valAddr sdk.ValAddress) types.ValidatorRewardLedger {
+	bz := ctx.KVStore(k.storeKey).Get(types.GetValidatorRewardLedgerKey(valAddr))
+	if bz == nil {
+		return newValidatorRewardLedger(valAddr)
+	}
+
+	var ledger types.ValidatorRewardLedger
+	k.cdc.MustUnmarshal(bz, &ledger)
+	return ledger
+}
+
+// SetValidatorRewardLedger stores a validator's reward ledger
+func (k Keeper) SetValidatorRewardLedger(ctx sdk.Context, ledger types.ValidatorRewardLedger) {
+	valAddr, err := sdk.ValAddressFromBech32(ledger.ValidatorAddress)
+	if err != nil {
+		panic(err)
+	}
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&ledger)
+	store.Set(types.GetValidatorRewardLedgerKey(valAddr), bz)
+}
+
+// GetAllValidatorRewardLedgers returns the stored reward ledgers
+func (k Keeper) GetAllValidatorRewardLedgers(ctx sdk.Context) []types.ValidatorRewardLedger {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.ValidatorRewardLedgerKey)
+	defer iterator.Close()
+
+	var ledgers []types.ValidatorRewardLedger
+	for ; iterator.Valid(); iterator.Next() {
+		var ledger types.ValidatorRewardLedger
+		k.cdc.MustUnmarshal(iterator.Value(), &ledger)
+		ledgers = append(ledgers, ledger)
+	}
+
+	return ledgers
+}
+
+// Clawbacks returns the reward ledger of a validator, or the ledgers with an
+// outstanding clawback when no validator is given, and the total outstanding.
+func (k Keeper) Clawbacks(goCtx context.Context, req *types.QueryClawbacksRequest) (*types.QueryClawbacksResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	ledgers := []types.ValidatorRewardLedger{}
+	if req.ValidatorAddress != "" {
+		valAddr, err := sdk.ValAddressFromBech32(req.ValidatorAddress)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid validator address: %s", err)
+		}
+		ledgers = append(ledgers, k.GetValidatorRewardLedger(ctx, valAddr))
+	} else {
+		for _, ledger := range k.GetAllValidatorRewardLedgers(ctx) {
+			if ledger.Balance.IsNegative() {
+				ledgers = append(ledgers, ledger)
+			}
+		}
+	}
+
+	outstanding := sdk.ZeroInt()
+	for _, ledger := range ledgers {
+		if ledger.Balance.IsNegative() {
+			outstanding = outstanding.Add(ledger.Balance.Neg())
+		}
+	}
+
+	return &types.QueryClawbacksResponse{
+		Ledgers:          ledgers,
+		TotalOutstanding: sdk.NewCoin(MainDenom, outstanding),
+	}, nil
+}
@@ -0,0 +1,237 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+var (
+	testHonestValidator = sdk.ValAddress([]byte("honest-validator"))
+	testDoubleSigner    = sdk.ValAddress([]byte("double-signer"))
+)
+
+// mockValidators looks up validators by operator address
+type mockValidators map[string]stakingtypes.Validator
+
+func (m mockValidators) GetValidator(_ sdk.Context, addr sdk.ValAddress) (stakingtypes.Validator, bool) {
+	validator, found := m[addr.String()]
+	return validator, found
+}
+
+// mockSlashingKeeper reports the consensus addresses it holds as tombstoned
+type mockSlashingKeeper map[string]bool
+
+func (m mockSlashingKeeper) IsTombstoned(_ sdk.Context, consAddr sdk.ConsAddress) bool {
+	return m[consAddr.String()]
+}
+
+// clawbackMonth returns the start of the nth uptime month after the one of genesisTime
+func clawbackMonth(n int) time.Time {
+	month := genesisTime.Unix() / int64(keeper.MonthDuration.Seconds())
+	return time.Unix(0, 0).UTC().Add(time.Duration(month+int64(n)) * keeper.MonthDuration)
+}
+
+// setupClawback returns a keeper whose module holds enough to pay the
+// validator rewards of testHonestValidator and testDoubleSigner, bonded with
+// equal tokens and both eligible in the snapshots of distribution months 1
+// to 3 of cycle 1
+func setupClawback(t *testing.T) (keeper.Keeper, sdk.Context, *mockBankKeeper) {
+	bank := &mockBankKeeper{
+		moduleBalance: sdk.NewCoins(sdk.NewInt64Coin(keeper.MainDenom, 1_000_000)),
+		sent:          make(map[string]sdk.Coins),
+	}
+	k, ctx := setupKeeperWithBank(t, bank)
+	k.SetBondedValidators(mockBondedValidators{
+		bondedValidator(testHonestValidator, 1000),
+		bondedValidator(testDoubleSigner, 1000),
+	})
+
+	for month := uint64(1); month <= 3; month++ {
+		for _, valAddr := range []sdk.ValAddress{testHonestValidator, testDoubleSigner} {
+			k.SetEligibilitySnapshot(ctx, valAddr, types.EligibilitySnapshot{
+				ValidatorAddress: valAddr.String(),
+				Cycle:            1,
+				Month:            month,
+				Eligible:         true,
+				InactiveDays:     sdk.ZeroDec(),
+			})
+		}
+	}
+
+	return k, ctx, bank
+}
+
+// distributeAt pays the validator share of a distribution month in a block at blockTime
+func distributeAt(t *testing.T, k keeper.Keeper, ctx sdk.Context, blockTime time.Time, month uint64, amount int64) sdk.Context {
+	ctx = ctx.WithBlockTime(blockTime).WithBlockHeight(ctx.BlockHeight() + 1).WithEventManager(sdk.NewEventManager())
+	info := types.HalvingInfo{CurrentCycle: 1}
+	require.NoError(t, k.DistributeToActiveValidators(ctx, sdk.NewInt64Coin(keeper.MainDenom, amount), info, month))
+	return ctx
+}
+
+// received returns what a validator's operator account was sent
+func received(bank *mockBankKeeper, valAddr sdk.ValAddress) sdk.Int {
+	return bank.sent[sdk.AccAddress(valAddr).String()].AmountOf(keeper.MainDenom)
+}
+
+// hasEvent reports whether an event of eventType was emitted on ctx
+func hasEvent(ctx sdk.Context, eventType string) bool {
+	for _, event := range ctx.EventManager().Events() {
+		if event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInfractionBeforeDistribution(t *testing.T) {
+	k, ctx, bank := setupClawback(t)
+
+	// Nothing was paid in the month of the infraction yet: nothing to claw back
+	ctx = ctx.WithBlockTime(clawbackMonth(1).Add(time.Hour)).WithBlockHeight(100)
+	infraction, recorded := k.ClawBack(ctx, testDoubleSigner, 99, ctx.BlockTime().Add(-5*time.Second))
+	require.True(t, recorded)
+	require.True(t, infraction.Clawback.IsZero())
+
+	// The month's distribution excludes the validator; the honest one gets
+	// the whole validator share
+	ctx = distributeAt(t, k, ctx, clawbackMonth(1).Add(24*time.Hour), 1, 1000)
+	require.True(t, received(bank, testDoubleSigner).IsZero())
+	require.Equal(t, sdk.NewInt(1000), received(bank, testHonestValidator))
+
+	// So does the distribution in the next uptime month
+	ctx = distributeAt(t, k, ctx, clawbackMonth(2).Add(24*time.Hour), 2, 1000)
+	require.True(t, received(bank, testDoubleSigner).IsZero())
+
+	ledger := k.GetValidatorRewardLedger(ctx, testDoubleSigner)
+	require.True(t, ledger.Balance.IsZero())
+	require.Len(t, ledger.Infractions, 1)
+
+	// Reporting the same infraction again changes nothing
+	_, recorded = k.ClawBack(ctx, testDoubleSigner, 99, clawbackMonth(1))
+	require.False(t, recorded)
+	require.Len(t, k.GetValidatorRewardLedger(ctx, testDoubleSigner).Infractions, 1)
+}
+
+func TestInfractionDuringDistribution(t *testing.T) {
+	k, ctx, bank := setupClawback(t)
+
+	// Month 1 is paid, month 2 is caught up later in the same uptime month
+	ctx = distributeAt(t, k, ctx, clawbackMonth(1).Add(time.Hour), 1, 1000)
+	require.Equal(t, sdk.NewInt(500), received(bank, testDoubleSigner))
+
+	// The infraction of the previous block is handled at the start of the
+	// catch-up block: the month 1 payout is clawed back and month 2 is not paid
+	blockTime := clawbackMonth(1).Add(2 * time.Hour)
+	ctx = ctx.WithBlockTime(blockTime).WithBlockHeight(200).WithEventManager(sdk.NewEventManager())
+	infraction, recorded := k.ClawBack(ctx, testDoubleSigner, 199, blockTime.Add(-5*time.Second))
+	require.True(t, recorded)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 500), infraction.Clawback)
+	require.Equal(t, int64(200), infraction.RecordedHeight)
+	require.True(t, hasEvent(ctx, types.EventTypeEquivocationClawback))
+
+	require.NoError(t, k.DistributeToActiveValidators(ctx, sdk.NewInt64Coin(keeper.MainDenom, 1000), types.HalvingInfo{CurrentCycle: 1}, 2))
+	require.Equal(t, sdk.NewInt(500), received(bank, testDoubleSigner))
+	require.Equal(t, sdk.NewInt(1500), received(bank, testHonestValidator))
+
+	ledger := k.GetValidatorRewardLedger(ctx, testDoubleSigner)
+	require.Equal(t, sdk.NewInt(-500), ledger.Balance)
+	require.Len(t, ledger.Payouts, 1)
+	require.True(t, ledger.Payouts[0].ClawedBack)
+}
+
+func TestInfractionAfterDistribution(t *testing.T) {
+	k, ctx, bank := setupClawback(t)
+
+	ctx = distributeAt(t, k, ctx, clawbackMonth(1).Add(time.Hour), 1, 1000)
+	require.Equal(t, sdk.NewInt(500), received(bank, testDoubleSigner))
+
+	// An infraction in the previous uptime month, reported late, does not
+	// claw back this month's payout
+	ctx = ctx.WithBlockTime(clawbackMonth(1).Add(48 * time.Hour)).WithBlockHeight(300)
+	infraction, recorded := k.ClawBack(ctx, testDoubleSigner, 5, clawbackMonth(0).Add(time.Hour))
+	require.True(t, recorded)
+	require.True(t, infraction.Clawback.IsZero())
+
+	// A double sign later in the month of the payout claws it back
+	infraction, recorded = k.ClawBack(ctx, testDoubleSigner, 299, ctx.BlockTime().Add(-5*time.Second))
+	require.True(t, recorded)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 500), infraction.Clawback)
+
+	res, err := k.Clawbacks(sdk.WrapSDKContext(ctx), &types.QueryClawbacksRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Ledgers, 1)
+	require.Equal(t, testDoubleSigner.String(), res.Ledgers[0].ValidatorAddress)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 500), res.TotalOutstanding)
+
+	// Excluded the next uptime month
+	ctx = distributeAt(t, k, ctx, clawbackMonth(2).Add(time.Hour), 2, 1000)
+	require.Equal(t, sdk.NewInt(500), received(bank, testDoubleSigner))
+
+	// Paid again afterwards, the outstanding clawback withheld first and
+	// returned for the halving fund
+	ctx = ctx.WithBlockTime(clawbackMonth(3).Add(time.Hour)).WithEventManager(sdk.NewEventManager())
+	withheld, err := k.DistributeToActiveValidatorsWithheld(ctx, sdk.NewInt64Coin(keeper.MainDenom, 1600), types.HalvingInfo{CurrentCycle: 1}, 3)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt(500), withheld)
+	require.Equal(t, sdk.NewInt(800), received(bank, testDoubleSigner))
+	require.True(t, hasEvent(ctx, types.EventTypeClawbackSettled))
+
+	ledger := k.GetValidatorRewardLedger(ctx, testDoubleSigner)
+	require.True(t, ledger.Balance.IsZero())
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 500), ledger.TotalClawedBack)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 500), ledger.TotalRecovered)
+	// Only the payouts that can still be clawed back are kept
+	require.Len(t, ledger.Payouts, 1)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 300), ledger.Payouts[0].Amount)
+
+	res, err = k.Clawbacks(sdk.WrapSDKContext(ctx), &types.QueryClawbacksRequest{})
+	require.NoError(t, err)
+	require.Empty(t, res.Ledgers)
+	require.True(t, res.TotalOutstanding.IsZero())
+}
+
+func TestTombstonedClawbackWrittenOff(t *testing.T) {
+	k, ctx, bank := setupClawback(t)
+
+	ctx = distributeAt(t, k, ctx, clawbackMonth(1).Add(time.Hour), 1, 1000)
+	ctx = ctx.WithBlockTime(clawbackMonth(1).Add(2 * time.Hour)).WithBlockHeight(200)
+	infraction, recorded := k.ClawBack(ctx, testDoubleSigner, 199, ctx.BlockTime().Add(-5*time.Second))
+	require.True(t, recorded)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 500), infraction.Clawback)
+
+	// The double signer is tombstoned and never paid again
+	validator, err := stakingtypes.NewValidator(testDoubleSigner, ed25519.GenPrivKey().PubKey(), stakingtypes.Description{})
+	require.NoError(t, err)
+	consAddr, err := validator.GetConsAddr()
+	require.NoError(t, err)
+	k.SetValidators(mockValidators{testDoubleSigner.String(): validator})
+	k.SetSlashingKeeper(mockSlashingKeeper{consAddr.String(): true})
+
+	// Its outstanding clawback is written off rather than left outstanding
+	// for good; nothing is withheld from the honest validator's payout
+	ctx = ctx.WithBlockTime(clawbackMonth(3).Add(time.Hour)).WithEventManager(sdk.NewEventManager())
+	withheld, err := k.DistributeToActiveValidatorsWithheld(ctx, sdk.NewInt64Coin(keeper.MainDenom, 1000), types.HalvingInfo{CurrentCycle: 1}, 3)
+	require.NoError(t, err)
+	require.True(t, withheld.IsZero())
+	require.Equal(t, sdk.NewInt(500), received(bank, testDoubleSigner))
+	require.Equal(t, sdk.NewInt(1500), received(bank, testHonestValidator))
+	require.True(t, hasEvent(ctx, types.EventTypeClawbackWrittenOff))
+
+	ledger := k.GetValidatorRewardLedger(ctx, testDoubleSigner)
+	require.True(t, ledger.Balance.IsZero())
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 500), ledger.TotalClawedBack)
+	require.True(t, ledger.TotalRecovered.IsZero())
+
+	res, err := k.Clawbacks(sdk.WrapSDKContext(ctx), &types.QueryClawbacksRequest{})
+	require.NoError(t, err)
+	require.Empty(t, res.Ledgers)
+}
@@ -0,0 +1,234 @@
+package keeper
+
+import (
+	"fmt"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// WithdrawDexReserve sends amount of the pending DEX share to a registered LP
+// pool on behalf of the DEX reserve operator. Only the operator set in params
+// may withdraw, only to an active feerouter LP pool, and at most
+// DexWithdrawalCap per DexWithdrawalEpoch. The pending allocations of the
+// oldest cycles are released first. Returns the withdrawal and the cap window
+// it counted against.
+func (k Keeper) WithdrawDexReserve(ctx sdk.Context, operator, destination sdk.AccAddress, amount sdk.Coin) (types.DexReserveWithdrawal, types.DexWithdrawalEpoch, error) {
+	params := k.GetParams(ctx)
+
+	if params.DexReserveOperator == "" {
+		return types.DexReserveWithdrawal{}, types.DexWithdrawalEpoch{}, errorsmod.Wrap(types.ErrUnauthorizedDexOperator,
+			"dex reserve withdrawals are disabled, no operator is set")
+	}
+	if operator.String() != params.DexReserveOperator {
+		return types.DexReserveWithdrawal{}, types.DexWithdrawalEpoch{}, errorsmod.Wrapf(types.ErrUnauthorizedDexOperator,
+			"%s is not the dex reserve operator", operator)
+	}
+	if amount.Denom != MainDenom || !amount.IsPositive() {
+		return types.DexReserveWithdrawal{}, types.DexWithdrawalEpoch{}, errorsmod.Wrapf(types.ErrInvalidDexWithdrawal,
+			"amount must be positive %s: %s", MainDenom, amount)
+	}
+	if k.lpPoolRegistry == nil || !k.lpPoolRegistry.IsActiveLPPool(ctx, destination.String()) {
+		return types.DexReserveWithdrawal{}, types.DexWithdrawalEpoch{}, errorsmod.Wrap(types.ErrDexPoolNotWhitelisted, destination.String())
+	}
+
+	epoch := k.GetCurrentDexWithdrawalEpoch(ctx)
+	remaining := dexWithdrawalRemaining(params, epoch)
+	if amount.Amount.GT(remaining.Amount) {
+		return types.DexReserveWithdrawal{}, types.DexWithdrawalEpoch{}, errorsmod.Wrapf(types.ErrDexWithdrawalCapExceeded,
+			"withdrawing %s exceeds the %s left of the %s%s epoch cap, the cap resets at %s",
+			amount, remaining, params.DexWithdrawalCap, MainDenom, time.Unix(epoch.End, 0).UTC().Format(time.RFC3339))
+	}
+
+	if available := k.GetTotalPendingDexAllocation(ctx); amount.Amount.GT(available.Amount) {
+		return types.DexReserveWithdrawal{}, types.DexWithdrawalEpoch{}, errorsmod.Wrapf(types.ErrInsufficientDexReserve,
+			"withdrawing %s, only %s pending", amount, available)
+	}
+	k.releaseOldestPendingDexAllocations(ctx, amount)
+
+	if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.DexDistributionAccountName, destination, sdk.NewCoins(amount)); err != nil {
+		return types.DexReserveWithdrawal{}, types.DexWithdrawalEpoch{}, err
+	}
+
+	epoch.Withdrawn = epoch.Withdrawn.Add(amount)
+	k.SetDexWithdrawalEpoch(ctx, epoch)
+
+	withdrawal := types.DexReserveWithdrawal{
+		Id:              k.lastDexReserveWithdrawalID(ctx) + 1,
+		Operator:        operator.String(),
+		DestinationPool: destination.String(),
+		Amount:          amount,
+		EpochStart:      epoch.Start,
+		Height:          ctx.BlockHeight(),
+		Timestamp:       ctx.BlockTime().Unix(),
+	}
+	k.SetDexReserveWithdrawal(ctx, withdrawal)
+
+	remaining = dexWithdrawalRemaining(params, epoch)
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeDexReserveWithdrawn,
+			sdk.NewAttribute(types.AttributeKeyWithdrawalID, fmt.Sprintf("%d", withdrawal.Id)),
+			sdk.NewAttribute(types.AttributeKeyOperator, withdrawal.Operator),
+			sdk.NewAttribute(types.AttributeKeyDestination, withdrawal.DestinationPool),
+			sdk.NewAttribute(types.AttributeKeyAmount, amount.String()),
+			sdk.NewAttribute(types.AttributeKeyRemaining, remaining.String()),
+			sdk.NewAttribute(types.AttributeKeyEpochEnd, fmt.Sprintf("%d", epoch.End)),
+		),
+	)
+
+	k.Logger(ctx).Info("DEX reserve withdrawn to LP pool",
+		"id", withdrawal.Id,
+		"pool", withdrawal.DestinationPool,
+		"amount", amount.String(),
+		"remaining", remaining.String(),
+	)
+
+	return withdrawal, epoch, nil
+}
+
+// GetDEXDistributionBalance returns the balance of the dex_distribution
+// account, which holds the pending DEX shares of all cycles
+func (k Keeper) GetDEXDistributionBalance(ctx sdk.Context) sdk.Coin {
+	return k.bankKeeper.GetBalance(ctx, authtypes.NewModuleAddress(types.DexDistributionAccountName), MainDenom)
+}
+
+// DexWithdrawalRemaining returns what the operator may still withdraw in the
+// current cap window
+func (k Keeper) DexWithdrawalRemaining(ctx sdk.Context) sdk.Coin {
+	return dexWithdrawalRemaining(k.GetParams(ctx), k.GetCurrentDexWithdrawalEpoch(ctx))
+}
+
+// dexWithdrawalRemaining returns the cap left in epoch. Lowering the cap
+// within a window can leave nothing to withdraw, never a negative amount.
+func dexWithdrawalRemaining(params types.Params, epoch types.DexWithdrawalEpoch) sdk.Coin {
+	remaining := params.DexWithdrawalCap.Sub(epoch.Withdrawn.Amount)
+	if remaining.IsNegative() {
+		remaining = sdk.ZeroInt()
+	}
+	return sdk.NewCoin(MainDenom, remaining)
+}
+
+// GetCurrentDexWithdrawalEpoch returns the cap window containing the block
+// time. Windows are DexWithdrawalEpoch long counted from the unix epoch, so a
+// new window starts with nothing withdrawn; changing the epoch length starts
+// a new window as well.
+func (k Keeper) GetCurrentDexWithdrawalEpoch(ctx sdk.Context) types.DexWithdrawalEpoch {
+	length := int64(k.GetParams(ctx).DexWithdrawalEpoch / time.Second)
+	now := ctx.BlockTime().Unix()
+	start := now - now%length
+
+	epoch, found := k.GetDexWithdrawalEpoch(ctx)
+	if !found || epoch.Start != start || epoch.End != start+length {
+		return types.DexWithdrawalEpoch{
+			Start:     start,
+			End:       start + length,
+			Withdrawn: sdk.NewCoin(MainDenom, sdk.ZeroInt()),
+		}
+	}
+	return epoch
+}
+
+// GetDexWithdrawalEpoch returns the stored cap window, which may have ended
+func (k Keeper) GetDexWithdrawalEpoch(ctx sdk.Context) (types.DexWithdrawalEpoch, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.DexWithdrawalEpochKey)
+	if bz == nil {
+		return types.DexWithdrawalEpoch{}, false
+	}
+
+	var epoch types.DexWithdrawalEpoch
+	k.cdc.MustUnmarshal(bz, &epoch)
+	return epoch, true
+}
+
+// SetDexWithdrawalEpoch stores the current cap window
+func (k Keeper) SetDexWithdrawalEpoch(ctx sdk.Context, epoch types.DexWithdrawalEpoch) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&epoch)
+	store.Set(types.DexWithdrawalEpochKey, bz)
+}
+
+// SetDexReserveWithdrawal stores a DEX reserve withdrawal
+func (k Keeper) SetDexReserveWithdrawal(ctx sdk.Context, withdrawal types.DexReserveWithdrawal) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&withdrawal)
+	store.Set(types.GetDexReserveWithdrawalKey(withdrawal.Id), bz)
+}
+
+// GetAllDexReserveWithdrawals returns all DEX reserve withdrawals ordered by id
+func (k Keeper) GetAllDexReserveWithdrawals(ctx sdk.Context) []types.DexReserveWithdrawal {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.DexReserveWithdrawalKey)
+	defer iterator.Close()
+
+	var withdrawals []types.DexReserveWithdrawal
+	for ; iterator.Valid(); iterator.Next() {
+		var withdrawal types.DexReserveWithdrawal
+		k.cdc.MustUnmarshal(iterator.Value(), &withdrawal)
+		withdrawals = append(withdrawals, withdrawal)
+	}
+
+	return withdrawals
+}
+
+// lastDexReserveWithdrawalID returns the highest withdrawal id, 0 if there is none
+func (k Keeper) lastDexReserveWithdrawalID(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStoreReversePrefixIterator(store, types.DexReserveWithdrawalKey)
+	defer iterator.Close()
+
+	if !iterator.Valid() {
+		return 0
+	}
+	return sdk.BigEndianToUint64(iterator.Key()[len(types.DexReserveWithdrawalKey):])
+}
+
+// GetTotalPendingDexAllocation returns the pending DEX share of all cycles
+func (k Keeper) GetTotalPendingDexAllocation(ctx sdk.Context) sdk.Coin {
+	total := sdk.NewCoin(MainDenom, sdk.ZeroInt())
+	for _, pending := range k.getAllPendingDexAllocations(ctx) {
+		total = total.Add(pending.Amount)
+	}
+	return total
+}
+
+// releaseOldestPendingDexAllocations releases amount from the pending DEX
+// allocations, oldest cycle first. The caller checks that enough is pending.
+func (k Keeper) releaseOldestPendingDexAllocations(ctx sdk.Context, amount sdk.Coin) {
+	left := amount.Amount
+	for _, pending := range k.getAllPendingDexAllocations(ctx) {
+		if left.IsZero() {
+			return
+		}
+
+		release := sdk.MinInt(left, pending.Amount.Amount)
+		if release.IsZero() {
+			continue
+		}
+		if err := k.ReleasePendingDexAllocation(ctx, pending.Cycle, sdk.NewCoin(MainDenom, release)); err != nil {
+			panic(err)
+		}
+		left = left.Sub(release)
+	}
+}
+
+// getAllPendingDexAllocations returns the pending DEX allocations ordered by cycle
+func (k Keeper) getAllPendingDexAllocations(ctx sdk.Context) []types.PendingDexAllocation {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.PendingDexAllocationKey)
+	defer iterator.Close()
+
+	var allocations []types.PendingDexAllocation
+	for ; iterator.Valid(); iterator.Next() {
+		var pending types.PendingDexAllocation
+		k.cdc.MustUnmarshal(iterator.Value(), &pending)
+		allocations = append(allocations, pending)
+	}
+
+	return allocations
+}
@@ -0,0 +1,149 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// GetDistributionCycleSummary returns the totals of a cycle's pruned
+// distribution records
+func (k Keeper) GetDistributionCycleSummary(ctx sdk.Context, cycle uint64) (types.DistributionCycleSummary, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.GetDistributionCycleSummaryKey(cycle))
+	if bz == nil {
+		return types.DistributionCycleSummary{}, false
+	}
+
+	var summary types.DistributionCycleSummary
+	k.cdc.MustUnmarshal(bz, &summary)
+	return summary, true
+}
+
+// SetDistributionCycleSummary stores the totals of a cycle's pruned distribution records
+func (k Keeper) SetDistributionCycleSummary(ctx sdk.Context, summary types.DistributionCycleSummary) {
+	bz := k.cdc.MustMarshal(&summary)
+	ctx.KVStore(k.storeKey).Set(types.GetDistributionCycleSummaryKey(summary.Cycle), bz)
+}
+
+// GetAllDistributionCycleSummaries returns the cycle summaries, ordered by cycle
+func (k Keeper) GetAllDistributionCycleSummaries(ctx sdk.Context) []types.DistributionCycleSummary {
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.DistributionCycleSummaryKey)
+	defer iterator.Close()
+
+	var summaries []types.DistributionCycleSummary
+	for ; iterator.Valid(); iterator.Next() {
+		var summary types.DistributionCycleSummary
+		k.cdc.MustUnmarshal(iterator.Value(), &summary)
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// GetDistributionRecordByMonth returns the record of a distribution month of a cycle
+func (k Keeper) GetDistributionRecordByMonth(ctx sdk.Context, cycle, month uint64) (types.DistributionRecord, bool) {
+	for _, record := range k.GetAllDistributionRecords(ctx) {
+		if record.Cycle == cycle && record.DistributionMonth == month {
+			return record, true
+		}
+	}
+	return types.DistributionRecord{}, false
+}
+
+// PruneDistributionRecords rolls the distribution records of cycles older
+// than distribution_record_retention_cycles into their cycle summary and
+// deletes them. Records are pruned oldest first and at most
+// max_records_pruned_per_block per call, so a backlog is spread over blocks.
+// It returns the number of records pruned.
+func (k Keeper) PruneDistributionRecords(ctx sdk.Context) int {
+	params := k.GetParams(ctx)
+	info, found := k.GetHalvingInfo(ctx)
+	if !found || params.DistributionRecordRetentionCycles == 0 || info.CurrentCycle <= params.DistributionRecordRetentionCycles {
+		return 0
+	}
+	cutoff := info.CurrentCycle - params.DistributionRecordRetentionCycles
+
+	// Records are keyed by timestamp, so their cycles only grow. The iteration
+	// starts after the bare last distribution time key.
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(types.GetDistributionRecordKey(0), sdk.PrefixEndBytes(types.DistributionRecordKey))
+	var keys [][]byte
+	var records []types.DistributionRecord
+	for ; iterator.Valid() && uint64(len(keys)) < params.MaxRecordsPrunedPerBlock; iterator.Next() {
+		var record types.DistributionRecord
+		k.cdc.MustUnmarshal(iterator.Value(), &record)
+		if record.Cycle >= cutoff {
+			break
+		}
+		keys = append(keys, iterator.Key())
+		records = append(records, record)
+	}
+	iterator.Close()
+
+	for i, record := range records {
+		summary, found := k.GetDistributionCycleSummary(ctx, record.Cycle)
+		if !found {
+			summary = types.DistributionCycleSummary{
+				Cycle:               record.Cycle,
+				TotalAmount:         sdk.NewCoin(MainDenom, sdk.ZeroInt()),
+				RedirectedDexAmount: sdk.NewCoin(MainDenom, sdk.ZeroInt()),
+			}
+		}
+		k.SetDistributionCycleSummary(ctx, rollUpDistributionRecord(summary, record))
+		store.Delete(keys[i])
+	}
+
+	if len(records) > 0 {
+		k.Logger(ctx).Info("Pruned distribution records", "records", len(records), "before_cycle", cutoff)
+	}
+	return len(records)
+}
+
+// rollUpDistributionRecord adds a record to its cycle summary
+func rollUpDistributionRecord(summary types.DistributionCycleSummary, record types.DistributionRecord) types.DistributionCycleSummary {
+	summary.Records++
+	if !record.Amount.IsNil() {
+		summary.TotalAmount = summary.TotalAmount.Add(record.Amount)
+	}
+	if !record.RedirectedDexAmount.IsNil() {
+		summary.RedirectedDexAmount = summary.RedirectedDexAmount.Add(record.RedirectedDexAmount)
+	}
+	if record.CatchUp {
+		summary.CatchUpRecords++
+	}
+	if summary.FirstTimestamp == 0 || record.Timestamp < summary.FirstTimestamp {
+		summary.FirstTimestamp = record.Timestamp
+	}
+	if record.Timestamp > summary.LastTimestamp {
+		summary.LastTimestamp = record.Timestamp
+	}
+	if record.DistributionMonth > summary.LastDistributionMonth {
+		summary.LastDistributionMonth = record.DistributionMonth
+	}
+	return summary
+}
+
+// DistributionRecord returns the record of a distribution month of a cycle.
+// Once the record was pruned the cycle summary is returned with pruned set.
+func (k Keeper) DistributionRecord(goCtx context.Context, req *types.QueryDistributionRecordRequest) (*types.QueryDistributionRecordResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+	if req.Cycle == 0 || req.DistributionMonth == 0 || req.DistributionMonth > types.DistributionMonths {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cycle %d or distribution month %d, month must be between 1 and %d",
+			req.Cycle, req.DistributionMonth, types.DistributionMonths)
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	if record, found := k.GetDistributionRecordByMonth(ctx, req.Cycle, req.DistributionMonth); found {
+		return &types.QueryDistributionRecordResponse{Record: &record}, nil
+	}
+	if summary, found := k.GetDistributionCycleSummary(ctx, req.Cycle); found && req.DistributionMonth <= summary.LastDistributionMonth {
+		return &types.QueryDistributionRecordResponse{Pruned: true, Summary: &summary}, nil
+	}
+
+	return nil, status.Errorf(codes.NotFound, "no distribution record for month %d of cycle %d", req.DistributionMonth, req.Cycle)
+}
@@ -0,0 +1,157 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// seedDistributionRecords stores a full distribution period for each cycle
+// and returns the records by cycle
+func seedDistributionRecords(ctx sdk.Context, k keeper.Keeper, cycles uint64) map[uint64][]types.DistributionRecord {
+	records := make(map[uint64][]types.DistributionRecord)
+	for cycle := uint64(1); cycle <= cycles; cycle++ {
+		start := genesisTime.Add(time.Duration(cycle-1) * types.DefaultHalvingCycleDuration)
+		for month := uint64(1); month <= types.DistributionMonths; month++ {
+			record := types.DistributionRecord{
+				Timestamp:           start.Add(time.Duration(month-1) * keeper.MonthDuration).Unix(),
+				Amount:              sdk.NewInt64Coin(keeper.MainDenom, int64(1_000_000*cycle+month)),
+				Cycle:               cycle,
+				Month:               month,
+				RedirectedDexAmount: sdk.NewInt64Coin(keeper.MainDenom, 0),
+				CatchUp:             month%7 == 0,
+				DistributionMonth:   month,
+			}
+			if month > 20 {
+				record.RedirectedDexAmount = sdk.NewInt64Coin(keeper.MainDenom, int64(month))
+			}
+			k.SetDistributionRecord(ctx, record)
+			records[cycle] = append(records[cycle], record)
+		}
+	}
+	return records
+}
+
+// requireSummaryEqual compares summaries by value; amounts read back from the
+// store need not share the big.Int representation
+func requireSummaryEqual(t *testing.T, want, got types.DistributionCycleSummary) {
+	t.Helper()
+	require.True(t, want.TotalAmount.IsEqual(got.TotalAmount), "total amount %s, want %s", got.TotalAmount, want.TotalAmount)
+	require.True(t, want.RedirectedDexAmount.IsEqual(got.RedirectedDexAmount), "redirected %s, want %s", got.RedirectedDexAmount, want.RedirectedDexAmount)
+	want.TotalAmount, got.TotalAmount = sdk.Coin{}, sdk.Coin{}
+	want.RedirectedDexAmount, got.RedirectedDexAmount = sdk.Coin{}, sdk.Coin{}
+	require.Equal(t, want, got)
+}
+
+func queryDistributionRecord(ctx sdk.Context, k keeper.Keeper, cycle, month uint64) (*types.QueryDistributionRecordResponse, error) {
+	return k.DistributionRecord(sdk.WrapSDKContext(ctx), &types.QueryDistributionRecordRequest{Cycle: cycle, DistributionMonth: month})
+}
+
+func TestPruneDistributionRecordsRollsUpPastRetention(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	params := k.GetParams(ctx)
+	params.DistributionRecordRetentionCycles = 1
+	params.MaxRecordsPrunedPerBlock = 5
+	k.SetParams(ctx, params)
+	seeded := seedDistributionRecords(ctx, k, 3)
+
+	// Within the retention window nothing is pruned
+	info := types.DefaultHalvingInfo()
+	info.CurrentCycle = 2
+	k.SetHalvingInfo(ctx, info)
+	require.Zero(t, k.PruneDistributionRecords(ctx))
+	require.Len(t, k.GetAllDistributionRecords(ctx), 3*types.DistributionMonths)
+
+	// Past the boundary the first cycle is rolled up, bounded per block
+	info.CurrentCycle = 3
+	k.SetHalvingInfo(ctx, info)
+	require.Equal(t, 5, k.PruneDistributionRecords(ctx))
+
+	res, err := queryDistributionRecord(ctx, k, 1, 5)
+	require.NoError(t, err)
+	require.True(t, res.Pruned)
+	require.Nil(t, res.Record)
+	require.Equal(t, uint64(5), res.Summary.Records)
+	res, err = queryDistributionRecord(ctx, k, 1, 6)
+	require.NoError(t, err)
+	require.False(t, res.Pruned)
+	require.Equal(t, seeded[1][5].Timestamp, res.Record.Timestamp)
+
+	blocks := 1
+	for {
+		pruned := k.PruneDistributionRecords(ctx)
+		require.LessOrEqual(t, pruned, int(params.MaxRecordsPrunedPerBlock))
+		if pruned == 0 {
+			break
+		}
+		blocks++
+	}
+	require.Equal(t, 5, blocks)
+
+	// The summary holds the totals of the deleted records
+	want := types.DistributionCycleSummary{
+		Cycle:               1,
+		TotalAmount:         sdk.NewInt64Coin(keeper.MainDenom, 0),
+		RedirectedDexAmount: sdk.NewInt64Coin(keeper.MainDenom, 0),
+	}
+	for _, record := range seeded[1] {
+		want.Records++
+		want.TotalAmount = want.TotalAmount.Add(record.Amount)
+		want.RedirectedDexAmount = want.RedirectedDexAmount.Add(record.RedirectedDexAmount)
+		if record.CatchUp {
+			want.CatchUpRecords++
+		}
+	}
+	want.FirstTimestamp = seeded[1][0].Timestamp
+	want.LastTimestamp = seeded[1][types.DistributionMonths-1].Timestamp
+	want.LastDistributionMonth = types.DistributionMonths
+
+	summary, found := k.GetDistributionCycleSummary(ctx, 1)
+	require.True(t, found)
+	requireSummaryEqual(t, want, summary)
+	require.Equal(t, uint64(3), summary.CatchUpRecords)
+	require.Len(t, k.GetAllDistributionCycleSummaries(ctx), 1)
+
+	// Only the first cycle's records were deleted
+	records := k.GetAllDistributionRecords(ctx)
+	require.Len(t, records, 2*types.DistributionMonths)
+	for _, record := range records {
+		require.NotEqual(t, uint64(1), record.Cycle)
+	}
+
+	res, err = queryDistributionRecord(ctx, k, 1, types.DistributionMonths)
+	require.NoError(t, err)
+	require.True(t, res.Pruned)
+	requireSummaryEqual(t, want, *res.Summary)
+	res, err = queryDistributionRecord(ctx, k, 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, seeded[2][2].Timestamp, res.Record.Timestamp)
+	require.True(t, seeded[2][2].Amount.IsEqual(res.Record.Amount))
+
+	_, err = queryDistributionRecord(ctx, k, 4, 1)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = queryDistributionRecord(ctx, k, 1, types.DistributionMonths+1)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPruneDistributionRecordsDisabled(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	params := k.GetParams(ctx)
+	params.DistributionRecordRetentionCycles = 0
+	k.SetParams(ctx, params)
+	seedDistributionRecords(ctx, k, 2)
+
+	info := types.DefaultHalvingInfo()
+	info.CurrentCycle = 5
+	k.SetHalvingInfo(ctx, info)
+	require.Zero(t, k.PruneDistributionRecords(ctx))
+	require.Len(t, k.GetAllDistributionRecords(ctx), 2*types.DistributionMonths)
+	require.Empty(t, k.GetAllDistributionCycleSummaries(ctx))
+}
@@ -0,0 +1,176 @@
+package keeper
+
+import (
+	"fmt"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// DeclareDowntime stores a planned downtime window for a validator. Windows
+// may not start before the current block, must lie within one uptime month
+// (the current or the next one), may not overlap earlier declarations, and
+// the declared days of a month are capped by MaxDeclaredDowntimeDays.
+func (k Keeper) DeclareDowntime(ctx sdk.Context, valAddr sdk.ValAddress, start, end int64, reason string) (types.DowntimeDeclaration, error) {
+	params := k.GetParams(ctx)
+	now := ctx.BlockTime().Unix()
+
+	if start < now {
+		return types.DowntimeDeclaration{}, errorsmod.Wrapf(types.ErrRetroactiveDowntime,
+			"window starts at %d, block time is %d", start, now)
+	}
+	if end <= start {
+		return types.DowntimeDeclaration{}, errorsmod.Wrapf(types.ErrInvalidDowntimeWindow,
+			"end %d must be after start %d", end, start)
+	}
+
+	month := monthOf(start)
+	if monthOf(end-1) != month {
+		return types.DowntimeDeclaration{}, errorsmod.Wrap(types.ErrInvalidDowntimeWindow,
+			"window must lie within a single uptime month, split it into one declaration per month")
+	}
+	if currentMonth := k.getCurrentMonth(ctx); month > currentMonth+1 {
+		return types.DowntimeDeclaration{}, errorsmod.Wrapf(types.ErrInvalidDowntimeWindow,
+			"downtime can only be declared for the current or next month (month %d, current %d)", month, currentMonth)
+	}
+
+	days := uint64((end - start + secondsPerDay - 1) / secondsPerDay)
+
+	declarations, declaredDays := k.GetDowntimeDeclarationsForMonth(ctx, valAddr, month)
+	for _, existing := range declarations {
+		if start < existing.End && existing.Start < end {
+			return types.DowntimeDeclaration{}, errorsmod.Wrapf(types.ErrOverlappingDowntime,
+				"window [%d, %d) overlaps [%d, %d)", start, end, existing.Start, existing.End)
+		}
+	}
+	if declaredDays+days > params.MaxDeclaredDowntimeDays {
+		return types.DowntimeDeclaration{}, errorsmod.Wrapf(types.ErrDowntimeCapExceeded,
+			"%d days already declared this month, %d more would exceed the cap of %d",
+			declaredDays, days, params.MaxDeclaredDowntimeDays)
+	}
+
+	declaration := types.DowntimeDeclaration{
+		ValidatorAddress: valAddr.String(),
+		Start:            start,
+		End:              end,
+		Reason:           reason,
+		Month:            month,
+		Days:             days,
+		DeclaredAt:       now,
+	}
+	k.SetDowntimeDeclaration(ctx, valAddr, declaration)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeDowntimeDeclared,
+			sdk.NewAttribute(types.AttributeKeyValidator, declaration.ValidatorAddress),
+			sdk.NewAttribute(types.AttributeKeyStart, fmt.Sprintf("%d", start)),
+			sdk.NewAttribute(types.AttributeKeyEnd, fmt.Sprintf("%d", end)),
+			sdk.NewAttribute(types.AttributeKeyMonth, fmt.Sprintf("%d", month)),
+			sdk.NewAttribute(types.AttributeKeyDays, fmt.Sprintf("%d", days)),
+			sdk.NewAttribute(types.AttributeKeyReason, reason),
+		),
+	)
+
+	k.Logger(ctx).Info("Validator declared planned downtime",
+		"validator", declaration.ValidatorAddress,
+		"start", time.Unix(start, 0).UTC(),
+		"end", time.Unix(end, 0).UTC(),
+		"days", days,
+	)
+
+	return declaration, nil
+}
+
+// SetDowntimeDeclaration stores a downtime declaration
+func (k Keeper) SetDowntimeDeclaration(ctx sdk.Context, valAddr sdk.ValAddress, declaration types.DowntimeDeclaration) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&declaration)
+	store.Set(types.GetDowntimeDeclarationKey(valAddr, declaration.Start), bz)
+}
+
+// GetDowntimeDeclarations returns a validator's downtime declarations ordered by start
+func (k Keeper) GetDowntimeDeclarations(ctx sdk.Context, valAddr sdk.ValAddress) []types.DowntimeDeclaration {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.GetDowntimeDeclarationsPrefix(valAddr))
+	defer iterator.Close()
+
+	var declarations []types.DowntimeDeclaration
+	for ; iterator.Valid(); iterator.Next() {
+		var declaration types.DowntimeDeclaration
+		k.cdc.MustUnmarshal(iterator.Value(), &declaration)
+		declarations = append(declarations, declaration)
+	}
+
+	return declarations
+}
+
+// GetAllDowntimeDeclarations returns the downtime declarations of all validators
+func (k Keeper) GetAllDowntimeDeclarations(ctx sdk.Context) []types.DowntimeDeclaration {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.DowntimeDeclarationKey)
+	defer iterator.Close()
+
+	var declarations []types.DowntimeDeclaration
+	for ; iterator.Valid(); iterator.Next() {
+		var declaration types.DowntimeDeclaration
+		k.cdc.MustUnmarshal(iterator.Value(), &declaration)
+		declarations = append(declarations, declaration)
+	}
+
+	return declarations
+}
+
+// GetDowntimeDeclarationsForMonth returns a validator's declarations for an
+// uptime month and the days they count against the monthly cap
+func (k Keeper) GetDowntimeDeclarationsForMonth(ctx sdk.Context, valAddr sdk.ValAddress, month uint64) ([]types.DowntimeDeclaration, uint64) {
+	var declarations []types.DowntimeDeclaration
+	var days uint64
+	for _, declaration := range k.GetDowntimeDeclarations(ctx, valAddr) {
+		if declaration.Month == month {
+			declarations = append(declarations, declaration)
+			days += declaration.Days
+		}
+	}
+	return declarations, days
+}
+
+// EffectiveInactiveDays returns the inactive days compared against the
+// monthly threshold: undeclared days count fully, days inside a declared
+// downtime window count at DeclaredDowntimeWeight
+func (k Keeper) EffectiveInactiveDays(ctx sdk.Context, uptime types.ValidatorUptime) sdk.Dec {
+	weight := k.GetParams(ctx).DeclaredDowntimeWeight
+	declared := sdk.NewDec(int64(uptime.DeclaredInactiveDays)).Mul(weight)
+	return sdk.NewDec(int64(uptime.InactiveDays)).Add(declared)
+}
+
+// isInDeclaredDowntime reports whether t falls inside one of the validator's declared windows
+func (k Keeper) isInDeclaredDowntime(ctx sdk.Context, valAddr sdk.ValAddress, t int64) bool {
+	for _, declaration := range k.GetDowntimeDeclarations(ctx, valAddr) {
+		if declaration.Start <= t && t < declaration.End {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneDowntimeDeclarations removes a validator's declarations for months before month
+func (k Keeper) pruneDowntimeDeclarations(ctx sdk.Context, valAddr sdk.ValAddress, month uint64) {
+	store := ctx.KVStore(k.storeKey)
+	for _, declaration := range k.GetDowntimeDeclarations(ctx, valAddr) {
+		if declaration.Month < month {
+			store.Delete(types.GetDowntimeDeclarationKey(valAddr, declaration.Start))
+		}
+	}
+}
+
+// secondsPerDay is the length of a declared downtime day
+const secondsPerDay = int64(24 * time.Hour / time.Second)
+
+// monthOf returns the uptime month of a unix timestamp, matching getCurrentMonth
+func monthOf(unix int64) uint64 {
+	return uint64(unix / int64(MonthDuration.Seconds()))
+}
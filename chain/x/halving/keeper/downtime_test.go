@@ -0,0 +1,175 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving"
+	"github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+var (
+	declaringValidator = sdk.ValAddress([]byte("declaring-validator-"))
+	silentValidator    = sdk.ValAddress([]byte("silent-validator----"))
+)
+
+// monthStart returns the start of the uptime month containing t
+func monthStart(t time.Time) time.Time {
+	month := int64(keeper.MonthDuration / time.Second)
+	return time.Unix(t.Unix()/month*month, 0).UTC()
+}
+
+func TestDeclareDowntimeRejectsInvalidWindows(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	now := ctx.BlockTime()
+	nextMonth := monthStart(now).Add(keeper.MonthDuration)
+
+	testCases := []struct {
+		name   string
+		start  time.Time
+		end    time.Time
+		expErr error
+	}{
+		{"retroactive", now.Add(-time.Hour), now.Add(time.Hour), types.ErrRetroactiveDowntime},
+		{"empty window", now.Add(time.Hour), now.Add(time.Hour), types.ErrInvalidDowntimeWindow},
+		{"spans two months", nextMonth.Add(-time.Hour), nextMonth.Add(time.Hour), types.ErrInvalidDowntimeWindow},
+		{"beyond next month", nextMonth.Add(keeper.MonthDuration), nextMonth.Add(keeper.MonthDuration + time.Hour), types.ErrInvalidDowntimeWindow},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := k.DeclareDowntime(ctx, declaringValidator, tc.start.Unix(), tc.end.Unix(), "maintenance")
+			require.ErrorIs(t, err, tc.expErr)
+		})
+	}
+	require.Empty(t, k.GetDowntimeDeclarations(ctx, declaringValidator))
+
+	// A window starting at the current block is not retroactive
+	_, err := k.DeclareDowntime(ctx, declaringValidator, now.Unix(), now.Add(time.Hour).Unix(), "maintenance")
+	require.NoError(t, err)
+}
+
+func TestDeclareDowntimeMonthlyCap(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	ctx = ctx.WithBlockTime(monthStart(genesisTime).Add(keeper.MonthDuration))
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	start := ctx.BlockTime().Add(time.Hour)
+	day := 24 * time.Hour
+
+	// Partial days round up: 1.5 days counts as 2
+	declaration, err := k.DeclareDowntime(ctx, declaringValidator, start.Unix(), start.Add(36*time.Hour).Unix(), "kernel upgrade")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), declaration.Days)
+	require.Equal(t, types.EventTypeDowntimeDeclared, ctx.EventManager().Events()[0].Type)
+
+	_, err = k.DeclareDowntime(ctx, declaringValidator, start.Add(day).Unix(), start.Add(3*day).Unix(), "overlap")
+	require.ErrorIs(t, err, types.ErrOverlappingDowntime)
+
+	// The third day reaches the default cap of 3, a fourth is rejected
+	_, err = k.DeclareDowntime(ctx, declaringValidator, start.Add(5*day).Unix(), start.Add(6*day).Unix(), "disk swap")
+	require.NoError(t, err)
+	_, err = k.DeclareDowntime(ctx, declaringValidator, start.Add(10*day).Unix(), start.Add(10*day+time.Hour).Unix(), "one more")
+	require.ErrorIs(t, err, types.ErrDowntimeCapExceeded)
+
+	_, declared := k.GetDowntimeDeclarationsForMonth(ctx, declaringValidator, uint64(ctx.BlockTime().Unix()/int64(keeper.MonthDuration/time.Second)))
+	require.Equal(t, types.DefaultMaxDeclaredDowntimeDays, declared)
+
+	// The cap is per validator and per month
+	_, err = k.DeclareDowntime(ctx, silentValidator, start.Unix(), start.Add(day).Unix(), "maintenance")
+	require.NoError(t, err)
+	next := monthStart(ctx.BlockTime()).Add(keeper.MonthDuration)
+	_, err = k.DeclareDowntime(ctx, declaringValidator, next.Unix(), next.Add(3*day).Unix(), "datacenter move")
+	require.NoError(t, err)
+
+	// A zero cap disables declarations
+	params := k.GetParams(ctx)
+	params.MaxDeclaredDowntimeDays = 0
+	k.SetParams(ctx, params)
+	_, err = k.DeclareDowntime(ctx, silentValidator, start.Add(5*day).Unix(), start.Add(6*day).Unix(), "maintenance")
+	require.ErrorIs(t, err, types.ErrDowntimeCapExceeded)
+}
+
+func TestDeclaredDowntimeWeighting(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	begin := monthStart(genesisTime).Add(keeper.MonthDuration)
+	ctx = ctx.WithBlockTime(begin)
+	month := uint64(begin.Unix() / int64(keeper.MonthDuration/time.Second))
+
+	for _, valAddr := range []sdk.ValAddress{declaringValidator, silentValidator} {
+		k.SetValidatorUptime(ctx, valAddr, types.ValidatorUptime{
+			ValidatorAddress: valAddr.String(),
+			CurrentMonth:     month,
+			LastCheck:        begin.Unix(),
+		})
+	}
+
+	// Three declared days at the default half weight
+	_, err := k.DeclareDowntime(ctx, declaringValidator, begin.Add(12*time.Hour).Unix(), begin.Add(84*time.Hour).Unix(), "planned maintenance")
+	require.NoError(t, err)
+
+	// Both validators are unbonded for 11 days
+	var declaringActive, silentActive bool
+	for day := 1; day <= 11; day++ {
+		dayCtx := ctx.WithBlockTime(begin.Add(time.Duration(day) * 24 * time.Hour))
+		declaringActive = k.AccrueInactivity(dayCtx, declaringValidator, false)
+		silentActive = k.AccrueInactivity(dayCtx, silentValidator, false)
+	}
+
+	declaring, _ := k.GetValidatorUptime(ctx, declaringValidator)
+	require.Equal(t, uint64(8), declaring.InactiveDays)
+	require.Equal(t, uint64(3), declaring.DeclaredInactiveDays)
+	require.True(t, sdk.MustNewDecFromStr("9.5").Equal(k.EffectiveInactiveDays(ctx, declaring)))
+	require.True(t, declaringActive, "8 + 3*0.5 days is within the 10-day threshold")
+
+	silent, _ := k.GetValidatorUptime(ctx, silentValidator)
+	require.Equal(t, uint64(11), silent.InactiveDays)
+	require.False(t, silentActive, "11 undeclared days exceed the threshold")
+
+	// At full weight declared days count like any other inactive day
+	params := k.GetParams(ctx)
+	params.DeclaredDowntimeWeight = sdk.OneDec()
+	k.SetParams(ctx, params)
+	require.True(t, sdk.NewDec(11).Equal(k.EffectiveInactiveDays(ctx, declaring)))
+
+	// The uptime query reports the declaration and the effective days
+	res, err := k.ValidatorUptime(sdk.WrapSDKContext(ctx), &types.QueryValidatorUptimeRequest{ValidatorAddress: declaringValidator.String()})
+	require.NoError(t, err)
+	require.Len(t, res.Declarations, 1)
+	require.Equal(t, uint64(3), res.DeclaredDays)
+	require.True(t, sdk.NewDec(11).Equal(res.EffectiveInactiveDays))
+}
+
+func TestValidatorUptimesGenesisRoundTrip(t *testing.T) {
+	k, ctx := setupKeeper(t)
+
+	uptimes := []types.ValidatorUptime{
+		{ValidatorAddress: declaringValidator.String(), CurrentMonth: 661, InactiveDays: 4, LastCheck: genesisTime.Unix(), DeclaredInactiveDays: 2},
+		{ValidatorAddress: silentValidator.String(), CurrentMonth: 661, InactiveDays: 9, LastCheck: genesisTime.Unix()},
+	}
+	for _, uptime := range uptimes {
+		valAddr, err := sdk.ValAddressFromBech32(uptime.ValidatorAddress)
+		require.NoError(t, err)
+		k.SetValidatorUptime(ctx, valAddr, uptime)
+	}
+
+	exported := halving.ExportGenesis(ctx, k)
+	require.NoError(t, exported.Validate())
+	require.ElementsMatch(t, uptimes, exported.ValidatorUptimes)
+
+	// The inactive days survive the import
+	k2, ctx2 := setupKeeper(t)
+	halving.InitGenesis(ctx2, k2, *exported)
+
+	silent, found := k2.GetValidatorUptime(ctx2, silentValidator)
+	require.True(t, found)
+	require.Equal(t, uint64(9), silent.InactiveDays)
+	require.ElementsMatch(t, uptimes, halving.ExportGenesis(ctx2, k2).ValidatorUptimes)
+
+	// Duplicate records are rejected
+	exported.ValidatorUptimes = append(exported.ValidatorUptimes, uptimes[0])
+	require.Error(t, exported.Validate())
+}
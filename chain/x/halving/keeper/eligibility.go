@@ -0,0 +1,165 @@
+package keeper
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// EligibilitySnapshotDue reports whether the reward eligibility of the next
+// unpaid distribution month should be snapshotted: the month is due,
+// eligibility_snapshot_delay has passed since it became due and no snapshot
+// was taken yet.
+func (k Keeper) EligibilitySnapshotDue(ctx sdk.Context) bool {
+	info, found := k.GetHalvingInfo(ctx)
+	if !found || !info.DistributionActive || !k.ShouldDistribute(ctx) {
+		return false
+	}
+
+	month := k.monthsDistributed(ctx, info) + 1
+	if k.hasEligibilitySnapshot(ctx, info.CurrentCycle, month) {
+		return false
+	}
+
+	takeAt := monthDueTime(info, month).Add(k.GetParams(ctx).EligibilitySnapshotDelay)
+	return !ctx.BlockTime().Before(takeAt)
+}
+
+// TakeEligibilitySnapshot records the reward eligibility of every bonded
+// validator for the next unpaid distribution month. The month's validator
+// rewards are paid to the validators eligible in the snapshot, whatever
+// their state in the distribution block, so a validator flapping around the
+// distribution block is neither paid nor forfeited by chance.
+func (k Keeper) TakeEligibilitySnapshot(ctx sdk.Context) []types.EligibilitySnapshot {
+	info, found := k.GetHalvingInfo(ctx)
+	if !found || !info.DistributionActive {
+		return nil
+	}
+	return k.takeEligibilitySnapshot(ctx, info, k.monthsDistributed(ctx, info)+1)
+}
+
+// takeEligibilitySnapshot evaluates and stores the eligibility of the bonded
+// validators for a distribution month. Snapshots of earlier months were used
+// by their distribution and are pruned.
+func (k Keeper) takeEligibilitySnapshot(ctx sdk.Context, info types.HalvingInfo, month uint64) []types.EligibilitySnapshot {
+	k.pruneEligibilitySnapshots(ctx, info.CurrentCycle, month)
+
+	var snapshots []types.EligibilitySnapshot
+	eligible := 0
+	for _, validator := range k.stakingKeeper.GetBondedValidatorsByPower(ctx) {
+		valAddr, err := sdk.ValAddressFromBech32(validator.OperatorAddress)
+		if err != nil {
+			k.Logger(ctx).Error("Invalid validator address", "validator", validator.OperatorAddress, "error", err)
+			continue
+		}
+
+		active := k.isValidatorActive(ctx, valAddr) && !k.equivocated(ctx, valAddr)
+		uptime, _ := k.GetValidatorUptime(ctx, valAddr)
+		snapshot := types.EligibilitySnapshot{
+			ValidatorAddress: validator.OperatorAddress,
+			Cycle:            info.CurrentCycle,
+			Month:            month,
+			Eligible:         active,
+			InactiveDays:     k.EffectiveInactiveDays(ctx, uptime),
+			TakenAt:          ctx.BlockTime().Unix(),
+			Height:           ctx.BlockHeight(),
+		}
+		k.SetEligibilitySnapshot(ctx, valAddr, snapshot)
+		snapshots = append(snapshots, snapshot)
+
+		if active {
+			eligible++
+		}
+	}
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeEligibilitySnapshot,
+		sdk.NewAttribute(types.AttributeKeyCycle, fmt.Sprintf("%d", info.CurrentCycle)),
+		sdk.NewAttribute(types.AttributeKeyMonth, fmt.Sprintf("%d", month)),
+		sdk.NewAttribute(types.AttributeKeyEligible, fmt.Sprintf("%d", eligible)),
+		sdk.NewAttribute(types.AttributeKeyValidators, fmt.Sprintf("%d", len(snapshots))),
+	))
+
+	k.Logger(ctx).Info("Reward eligibility snapshot taken",
+		"cycle", info.CurrentCycle,
+		"month", month,
+		"eligible", eligible,
+		"validators", len(snapshots),
+	)
+
+	return snapshots
+}
+
+// eligibilitySnapshot returns the snapshot a distribution month is paid
+// from. A month distributed before its snapshot was due, e.g. with a long
+// eligibility_snapshot_delay, takes it in the distribution block.
+func (k Keeper) eligibilitySnapshot(ctx sdk.Context, info types.HalvingInfo, month uint64) []types.EligibilitySnapshot {
+	if snapshots := k.GetEligibilitySnapshots(ctx, info.CurrentCycle, month); len(snapshots) > 0 {
+		return snapshots
+	}
+	return k.takeEligibilitySnapshot(ctx, info, month)
+}
+
+// SetEligibilitySnapshot stores a validator's eligibility for the snapshot's distribution month
+func (k Keeper) SetEligibilitySnapshot(ctx sdk.Context, valAddr sdk.ValAddress, snapshot types.EligibilitySnapshot) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&snapshot)
+	store.Set(types.GetEligibilitySnapshotKey(snapshot.Cycle, snapshot.Month, valAddr), bz)
+}
+
+// GetEligibilitySnapshots returns the eligibility snapshot of a distribution month
+func (k Keeper) GetEligibilitySnapshots(ctx sdk.Context, cycle, month uint64) []types.EligibilitySnapshot {
+	return k.eligibilitySnapshots(ctx, types.GetEligibilitySnapshotMonthPrefix(cycle, month))
+}
+
+// GetAllEligibilitySnapshots returns the stored eligibility snapshots, oldest month first
+func (k Keeper) GetAllEligibilitySnapshots(ctx sdk.Context) []types.EligibilitySnapshot {
+	return k.eligibilitySnapshots(ctx, types.EligibilitySnapshotKey)
+}
+
+// eligibilitySnapshots returns the eligibility snapshots under a store prefix
+func (k Keeper) eligibilitySnapshots(ctx sdk.Context, prefix []byte) []types.EligibilitySnapshot {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+
+	var snapshots []types.EligibilitySnapshot
+	for ; iterator.Valid(); iterator.Next() {
+		var snapshot types.EligibilitySnapshot
+		k.cdc.MustUnmarshal(iterator.Value(), &snapshot)
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots
+}
+
+// hasEligibilitySnapshot reports whether a distribution month has a snapshot
+func (k Keeper) hasEligibilitySnapshot(ctx sdk.Context, cycle, month uint64) bool {
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.GetEligibilitySnapshotMonthPrefix(cycle, month))
+	defer iterator.Close()
+	return iterator.Valid()
+}
+
+// pruneEligibilitySnapshots deletes the snapshots of distribution months
+// before month of cycle, including those of earlier cycles
+func (k Keeper) pruneEligibilitySnapshots(ctx sdk.Context, cycle, month uint64) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(types.EligibilitySnapshotKey, types.GetEligibilitySnapshotMonthPrefix(cycle, month))
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// monthDueTime returns when a distribution month of the current period becomes due
+func monthDueTime(info types.HalvingInfo, month uint64) time.Time {
+	return time.Unix(info.DistributionStart, 0).Add(time.Duration(month-1) * MonthDuration)
+}
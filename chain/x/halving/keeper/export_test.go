@@ -0,0 +1,76 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// DistributeToDEX exposes distributeToDEX to keeper_test
+func (k Keeper) DistributeToDEX(ctx sdk.Context, amount sdk.Coin, info types.HalvingInfo) error {
+	return k.distributeToDEX(ctx, amount, info)
+}
+
+// SplitRewards exposes splitRewards to keeper_test
+func (k Keeper) SplitRewards(ctx sdk.Context, totalAmount sdk.Coin, info types.HalvingInfo) RewardSplit {
+	return k.splitRewards(ctx, totalAmount, info)
+}
+
+// DistributeToActiveValidators exposes distributeToActiveValidators to keeper_test
+func (k Keeper) DistributeToActiveValidators(ctx sdk.Context, amount sdk.Coin, info types.HalvingInfo, month uint64) error {
+	_, err := k.distributeToActiveValidators(ctx, amount, info, month)
+	return err
+}
+
+// DistributeToActiveValidatorsWithheld exposes distributeToActiveValidators
+// with the part withheld for clawbacks to keeper_test
+func (k Keeper) DistributeToActiveValidatorsWithheld(ctx sdk.Context, amount sdk.Coin, info types.HalvingInfo, month uint64) (sdk.Int, error) {
+	return k.distributeToActiveValidators(ctx, amount, info, month)
+}
+
+// SetValidators replaces the staking keeper looking up validators for a
+// tombstone in keeper_test
+func (k *Keeper) SetValidators(source types.ValidatorSource) {
+	k.validators = source
+}
+
+// SetBondedValidators replaces the staking keeper weighting the validator
+// rewards in keeper_test
+func (k *Keeper) SetBondedValidators(source types.BondedValidatorSource) {
+	k.bondedValidators = source
+}
+
+// NextMonthlyDistribution exposes nextMonthlyDistribution to keeper_test
+func (k Keeper) NextMonthlyDistribution(ctx sdk.Context, info types.HalvingInfo) (sdk.Coin, sdk.Int) {
+	return k.nextMonthlyDistribution(ctx, info)
+}
+
+// AccrueInactivity exposes accrueInactivity to keeper_test for the stored uptime record
+func (k Keeper) AccrueInactivity(ctx sdk.Context, valAddr sdk.ValAddress, bonded bool) bool {
+	uptime, _ := k.GetValidatorUptime(ctx, valAddr)
+	return k.accrueInactivity(ctx, valAddr, uptime, bonded)
+}
+
+// StoreBytes exposes the size of the keys and values under a store prefix to keeper_test
+func (k Keeper) StoreBytes(ctx sdk.Context, prefix []byte) int {
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), prefix)
+	defer iterator.Close()
+
+	size := 0
+	for ; iterator.Valid(); iterator.Next() {
+		size += len(iterator.Key()) + len(iterator.Value())
+	}
+	return size
+}
+
+// SetVersion6LastDistributionTime stores the last distribution time under
+// the distribution record prefix, as consensus version 6 did
+func (k Keeper) SetVersion6LastDistributionTime(ctx sdk.Context, timestamp int64) {
+	ctx.KVStore(k.storeKey).Set(types.DistributionRecordKey, sdk.Uint64ToBigEndian(uint64(timestamp)))
+}
+
+// RegisterMigrationsUpTo exposes registerMigrations to keeper_test for another consensus version
+func RegisterMigrationsUpTo(cfg module.Configurator, migrations map[uint64]module.MigrationHandler, consensusVersion uint64) error {
+	return registerMigrations(cfg, migrations, consensusVersion)
+}
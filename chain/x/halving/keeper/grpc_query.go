@@ -7,8 +7,10 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/cosmos/cosmos-sdk/store/prefix"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/query"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 
 	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
 )
@@ -50,7 +52,7 @@ func (k Keeper) DistributionHistory(goCtx context.Context, req *types.QueryDistr
 
 	ctx := sdk.UnwrapSDKContext(goCtx)
 	store := ctx.KVStore(k.storeKey)
-	distributionStore := prefix.NewStore(store, types.LastDistributionKey)
+	distributionStore := prefix.NewStore(store, types.DistributionRecordKey)
 
 	var records []types.DistributionRecord
 	pageRes, err := query.Paginate(distributionStore, req.Pagination, func(key []byte, value []byte) error {
@@ -70,4 +72,262 @@ func (k Keeper) DistributionHistory(goCtx context.Context, req *types.QueryDistr
 		DistributionRecords: records,
 		Pagination:         pageRes,
 	}, nil
-}
\ No newline at end of file
+}
+
+// BotProtocolVersion returns the bot protocol version expected by the chain
+// together with the oldest version that is still accepted.
+func (k Keeper) BotProtocolVersion(goCtx context.Context, req *types.QueryBotProtocolVersionRequest) (*types.QueryBotProtocolVersionResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	params := k.GetParams(ctx)
+
+	return &types.QueryBotProtocolVersionResponse{
+		BotProtocolVersion:  params.BotProtocolVersion,
+		MinSupportedVersion: params.MinBotProtocolVersion,
+	}, nil
+}
+
+// SupplyFloorForecast estimates when total supply will reach the minimum threshold.
+func (k Keeper) SupplyFloorForecast(goCtx context.Context, req *types.QuerySupplyFloorForecastRequest) (*types.QuerySupplyFloorForecastResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	return &types.QuerySupplyFloorForecastResponse{Forecast: k.ForecastSupplyFloor(ctx)}, nil
+}
+
+// PendingDexAllocation returns the DEX share awaiting bot distribution for the
+// requested cycle, or the current cycle when none is given.
+func (k Keeper) PendingDexAllocation(goCtx context.Context, req *types.QueryPendingDexAllocationRequest) (*types.QueryPendingDexAllocationResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	cycle := req.Cycle
+	if cycle == 0 {
+		info, found := k.GetHalvingInfo(ctx)
+		if !found {
+			return nil, status.Error(codes.NotFound, "halving info not found")
+		}
+		cycle = info.CurrentCycle
+	}
+
+	return &types.QueryPendingDexAllocationResponse{PendingDexAllocation: k.GetPendingDexAllocation(ctx, cycle)}, nil
+}
+
+// ValidatorUptime returns a validator's uptime record for the current month
+// together with its declared downtime and effective inactive days
+func (k Keeper) ValidatorUptime(goCtx context.Context, req *types.QueryValidatorUptimeRequest) (*types.QueryValidatorUptimeResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	valAddr, err := sdk.ValAddressFromBech32(req.ValidatorAddress)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid validator address: %s", err)
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	currentMonth := k.getCurrentMonth(ctx)
+
+	uptime, found := k.GetValidatorUptime(ctx, valAddr)
+	if !found || uptime.CurrentMonth != currentMonth {
+		// No inactivity recorded for this month yet
+		uptime = types.ValidatorUptime{
+			ValidatorAddress: valAddr.String(),
+			CurrentMonth:     currentMonth,
+			LastCheck:        uptime.LastCheck,
+		}
+	}
+
+	declarations, declaredDays := k.GetDowntimeDeclarationsForMonth(ctx, valAddr, currentMonth)
+	if declarations == nil {
+		declarations = []types.DowntimeDeclaration{}
+	}
+
+	return &types.QueryValidatorUptimeResponse{
+		Uptime:                uptime,
+		EffectiveInactiveDays: k.EffectiveInactiveDays(ctx, uptime),
+		Declarations:          declarations,
+		DeclaredDays:          declaredDays,
+	}, nil
+}
+
+// HeartbeatCompliance returns a validator's latest heartbeat and registered
+// bot metadata, and the share of heartbeat intervals covered in the requested
+// uptime month, or the current month when none is given.
+func (k Keeper) HeartbeatCompliance(goCtx context.Context, req *types.QueryHeartbeatComplianceRequest) (*types.QueryHeartbeatComplianceResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	valAddr, err := sdk.ValAddressFromBech32(req.ValidatorAddress)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid validator address: %s", err)
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	currentMonth := k.getCurrentMonth(ctx)
+	month := req.Month
+	if month == 0 {
+		month = currentMonth
+	}
+	if month > currentMonth {
+		return nil, status.Errorf(codes.InvalidArgument, "month %d has not started, current month is %d", month, currentMonth)
+	}
+	if oldest := k.oldestRetainedHeartbeatMonth(ctx); month < oldest {
+		return nil, status.Errorf(codes.NotFound, "heartbeats of month %d were pruned, the oldest retained month is %d", month, oldest)
+	}
+
+	bitmap, elapsed := k.heartbeatCompliance(ctx, valAddr, month)
+	lastHeartbeat, _ := k.GetLastHeartbeat(ctx, valAddr)
+
+	var botMetadata *types.BotMetadata
+	if metadata, found := k.GetBotMetadata(ctx, valAddr); found {
+		botMetadata = &metadata
+	}
+
+	return &types.QueryHeartbeatComplianceResponse{
+		LastHeartbeat:    lastHeartbeat,
+		Month:            month,
+		IntervalSeconds:  bitmap.IntervalSeconds,
+		Intervals:        bitmap.Intervals,
+		ElapsedIntervals: elapsed,
+		PresentIntervals: bitmap.Present(elapsed),
+		CoveragePercent:  bitmap.CoveragePercent(elapsed),
+		BotMetadata:      botMetadata,
+	}, nil
+}
+
+// DexReserveWithdrawals returns the DEX reserve withdrawal history with pagination.
+func (k Keeper) DexReserveWithdrawals(goCtx context.Context, req *types.QueryDexReserveWithdrawalsRequest) (*types.QueryDexReserveWithdrawalsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	withdrawalStore := prefix.NewStore(ctx.KVStore(k.storeKey), types.DexReserveWithdrawalKey)
+
+	withdrawals := []types.DexReserveWithdrawal{}
+	pageRes, err := query.Paginate(withdrawalStore, req.Pagination, func(key []byte, value []byte) error {
+		var withdrawal types.DexReserveWithdrawal
+		if err := k.cdc.Unmarshal(value, &withdrawal); err != nil {
+			return err
+		}
+		withdrawals = append(withdrawals, withdrawal)
+		return nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryDexReserveWithdrawalsResponse{
+		Withdrawals: withdrawals,
+		Pagination:  pageRes,
+	}, nil
+}
+
+// DexDistributionBalance returns the balance of the dex_distribution account
+// and the pending DEX share it holds
+func (k Keeper) DexDistributionBalance(goCtx context.Context, req *types.QueryDexDistributionBalanceRequest) (*types.QueryDexDistributionBalanceResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	return &types.QueryDexDistributionBalanceResponse{
+		Address: authtypes.NewModuleAddress(types.DexDistributionAccountName).String(),
+		Balance: k.GetDEXDistributionBalance(ctx),
+		Pending: k.GetTotalPendingDexAllocation(ctx),
+	}, nil
+}
+
+// DexWithdrawalLimit returns the DEX reserve operator, the epoch cap and what
+// is left of it, and the pending DEX share that can be withdrawn.
+func (k Keeper) DexWithdrawalLimit(goCtx context.Context, req *types.QueryDexWithdrawalLimitRequest) (*types.QueryDexWithdrawalLimitResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	params := k.GetParams(ctx)
+	epoch := k.GetCurrentDexWithdrawalEpoch(ctx)
+
+	return &types.QueryDexWithdrawalLimitResponse{
+		Operator:   params.DexReserveOperator,
+		Cap:        sdk.NewCoin(MainDenom, params.DexWithdrawalCap),
+		Withdrawn:  epoch.Withdrawn,
+		Remaining:  dexWithdrawalRemaining(params, epoch),
+		EpochStart: epoch.Start,
+		EpochEnd:   epoch.End,
+		Available:  k.GetTotalPendingDexAllocation(ctx),
+	}, nil
+}
+
+// RewardEstimate estimates what a validator and an additional delegation to
+// it receive from the next distribution month
+func (k Keeper) RewardEstimate(goCtx context.Context, req *types.QueryRewardEstimateRequest) (*types.QueryRewardEstimateResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	valAddr, err := sdk.ValAddressFromBech32(req.ValidatorAddress)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid validator address: %s", err)
+	}
+
+	additional := req.AdditionalDelegation
+	if additional.IsNil() {
+		additional = sdk.ZeroInt()
+	}
+	if additional.IsNegative() {
+		return nil, status.Errorf(codes.InvalidArgument, "additional delegation must not be negative: %s", additional)
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	estimate, err := k.EstimateReward(ctx, valAddr, additional)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &types.QueryRewardEstimateResponse{RewardEstimate: estimate}, nil
+}
+
+// SimulateDistribution returns what the next distribution month would pay
+// on the queried state. The simulation runs under its own gas meter of at
+// most SimulateDistributionGasLimit and fails with ResourceExhausted when
+// it is used up.
+func (k Keeper) SimulateDistribution(goCtx context.Context, req *types.QuerySimulateDistributionRequest) (res *types.QuerySimulateDistributionResponse, err error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	limit := storetypes.Gas(SimulateDistributionGasLimit)
+	if remaining := ctx.GasMeter().GasRemaining(); remaining < limit {
+		limit = remaining
+	}
+	simCtx := ctx.WithGasMeter(storetypes.NewGasMeter(limit))
+
+	defer func() {
+		if r := recover(); r != nil {
+			outOfGas, ok := r.(storetypes.ErrorOutOfGas)
+			if !ok {
+				panic(r)
+			}
+			res, err = nil, status.Errorf(codes.ResourceExhausted, "distribution simulation out of gas in %s, limit %d", outOfGas.Descriptor, limit)
+		}
+	}()
+
+	simulation := k.SimulateNextDistribution(simCtx)
+	ctx.GasMeter().ConsumeGas(simCtx.GasMeter().GasConsumed(), "simulate distribution")
+
+	return &types.QuerySimulateDistributionResponse{Simulation: simulation}, nil
+}
@@ -0,0 +1,72 @@
+package keeper_test
+
+import (
+	"testing"
+
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+func TestQueryBotProtocolVersion(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	wctx := sdk.WrapSDKContext(ctx)
+
+	_, err := k.BotProtocolVersion(wctx, nil)
+	require.Error(t, err)
+
+	res, err := k.BotProtocolVersion(wctx, &types.QueryBotProtocolVersionRequest{})
+	require.NoError(t, err)
+	require.Equal(t, types.DefaultBotProtocolVersion, res.BotProtocolVersion)
+	require.Equal(t, types.DefaultMinBotProtocolVersion, res.MinSupportedVersion)
+
+	// Governance bumps the expected version while keeping v2 bots accepted
+	params := k.GetParams(ctx)
+	params.BotProtocolVersion = 3
+	params.MinBotProtocolVersion = 2
+	k.SetParams(ctx, params)
+
+	res, err = k.BotProtocolVersion(wctx, &types.QueryBotProtocolVersionRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), res.BotProtocolVersion)
+	require.Equal(t, uint64(2), res.MinSupportedVersion)
+}
+
+func TestQueryRewardEstimateValidatesRequest(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	wctx := sdk.WrapSDKContext(ctx)
+
+	_, err := k.RewardEstimate(wctx, nil)
+	require.Error(t, err)
+
+	_, err = k.RewardEstimate(wctx, &types.QueryRewardEstimateRequest{ValidatorAddress: "not-a-validator"})
+	require.ErrorContains(t, err, "invalid validator address")
+
+	_, err = k.RewardEstimate(wctx, &types.QueryRewardEstimateRequest{
+		ValidatorAddress:     sdk.ValAddress([]byte("validator")).String(),
+		AdditionalDelegation: sdk.NewInt(-1),
+	})
+	require.ErrorContains(t, err, "must not be negative")
+}
+
+func TestQuerySimulateDistributionBoundedInGas(t *testing.T) {
+	k, ctx := setupKeeper(t)
+
+	_, err := k.SimulateDistribution(sdk.WrapSDKContext(ctx), nil)
+	require.Error(t, err)
+
+	// Without an active distribution period nothing would be paid
+	res, err := k.SimulateDistribution(sdk.WrapSDKContext(ctx), &types.QuerySimulateDistributionRequest{})
+	require.NoError(t, err)
+	require.False(t, res.Simulation.Due)
+	require.True(t, res.Simulation.MonthlyAmount.IsZero())
+
+	// Running out of the query's gas is an error, not a panic
+	limited := ctx.WithGasMeter(storetypes.NewGasMeter(10))
+	_, err = k.SimulateDistribution(sdk.WrapSDKContext(limited), &types.QuerySimulateDistributionRequest{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
@@ -0,0 +1,203 @@
+package keeper
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// RecordHeartbeat stores a validator's bot heartbeat at the current block.
+// Only the latest heartbeat is kept; its interval is marked in the
+// validator's bitmap for the current uptime month, which is all that
+// compliance evaluation needs. The bitmap keeps the interval it was created
+// with, so a changed heartbeat_interval applies from the next month.
+func (k Keeper) RecordHeartbeat(ctx sdk.Context, valAddr sdk.ValAddress) types.ValidatorHeartbeat {
+	now := ctx.BlockTime().Unix()
+	heartbeat := types.ValidatorHeartbeat{
+		ValidatorAddress: valAddr.String(),
+		Timestamp:        now,
+		Height:           ctx.BlockHeight(),
+	}
+	k.SetLastHeartbeat(ctx, valAddr, heartbeat)
+
+	month := monthOf(now)
+	bitmap, found := k.GetHeartbeatBitmap(ctx, valAddr, month)
+	if !found {
+		bitmap = k.newHeartbeatBitmap(ctx, valAddr, month)
+	}
+	if bitmap.Set(bitmap.IntervalAt(now - monthStartUnix(month))) {
+		k.SetHeartbeatBitmap(ctx, valAddr, bitmap)
+	}
+
+	return heartbeat
+}
+
+// IsValidatorBotRunning reports whether a validator's bot sent a heartbeat
+// within the last bot_heartbeat_timeout_blocks. A validator that never sent
+// one gets the same grace from the height liveness tracking started, so the
+// upgrade introducing it does not find every validator's bot stopped. Before
+// tracking starts every bot counts as running.
+func (k Keeper) IsValidatorBotRunning(ctx sdk.Context, valAddr sdk.ValAddress) bool {
+	lastSeen, tracking := k.GetBotLivenessStart(ctx)
+	if !tracking {
+		return true
+	}
+	if heartbeat, found := k.GetLastHeartbeat(ctx, valAddr); found && heartbeat.Height > lastSeen {
+		lastSeen = heartbeat.Height
+	}
+
+	return ctx.BlockHeight()-lastSeen <= int64(k.GetParams(ctx).BotHeartbeatTimeoutBlocks)
+}
+
+// SetBotLivenessStart stores the height bot liveness tracking starts at
+func (k Keeper) SetBotLivenessStart(ctx sdk.Context, height int64) {
+	ctx.KVStore(k.storeKey).Set(types.BotLivenessStartKey, sdk.Uint64ToBigEndian(uint64(height)))
+}
+
+// GetBotLivenessStart returns the height bot liveness tracking started at
+func (k Keeper) GetBotLivenessStart(ctx sdk.Context) (int64, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.BotLivenessStartKey)
+	if bz == nil {
+		return 0, false
+	}
+	return int64(sdk.BigEndianToUint64(bz)), true
+}
+
+// SetLastHeartbeat stores a validator's latest heartbeat
+func (k Keeper) SetLastHeartbeat(ctx sdk.Context, valAddr sdk.ValAddress, heartbeat types.ValidatorHeartbeat) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&heartbeat)
+	store.Set(types.GetLastHeartbeatKey(valAddr), bz)
+}
+
+// GetLastHeartbeat returns a validator's latest heartbeat
+func (k Keeper) GetLastHeartbeat(ctx sdk.Context, valAddr sdk.ValAddress) (types.ValidatorHeartbeat, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetLastHeartbeatKey(valAddr))
+	if bz == nil {
+		return types.ValidatorHeartbeat{}, false
+	}
+
+	var heartbeat types.ValidatorHeartbeat
+	k.cdc.MustUnmarshal(bz, &heartbeat)
+	return heartbeat, true
+}
+
+// GetAllLastHeartbeats returns the latest heartbeat of every validator
+func (k Keeper) GetAllLastHeartbeats(ctx sdk.Context) []types.ValidatorHeartbeat {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.LastHeartbeatKey)
+	defer iterator.Close()
+
+	var heartbeats []types.ValidatorHeartbeat
+	for ; iterator.Valid(); iterator.Next() {
+		var heartbeat types.ValidatorHeartbeat
+		k.cdc.MustUnmarshal(iterator.Value(), &heartbeat)
+		heartbeats = append(heartbeats, heartbeat)
+	}
+
+	return heartbeats
+}
+
+// SetHeartbeatBitmap stores a validator's heartbeat bitmap for the bitmap's month
+func (k Keeper) SetHeartbeatBitmap(ctx sdk.Context, valAddr sdk.ValAddress, bitmap types.HeartbeatBitmap) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&bitmap)
+	store.Set(types.GetHeartbeatBitmapKey(bitmap.Month, valAddr), bz)
+}
+
+// GetHeartbeatBitmap returns a validator's heartbeat bitmap for an uptime month
+func (k Keeper) GetHeartbeatBitmap(ctx sdk.Context, valAddr sdk.ValAddress, month uint64) (types.HeartbeatBitmap, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetHeartbeatBitmapKey(month, valAddr))
+	if bz == nil {
+		return types.HeartbeatBitmap{}, false
+	}
+
+	var bitmap types.HeartbeatBitmap
+	k.cdc.MustUnmarshal(bz, &bitmap)
+	return bitmap, true
+}
+
+// GetAllHeartbeatBitmaps returns the retained heartbeat bitmaps, oldest month first
+func (k Keeper) GetAllHeartbeatBitmaps(ctx sdk.Context) []types.HeartbeatBitmap {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.HeartbeatBitmapKey)
+	defer iterator.Close()
+
+	var bitmaps []types.HeartbeatBitmap
+	for ; iterator.Valid(); iterator.Next() {
+		var bitmap types.HeartbeatBitmap
+		k.cdc.MustUnmarshal(iterator.Value(), &bitmap)
+		bitmaps = append(bitmaps, bitmap)
+	}
+
+	return bitmaps
+}
+
+// PruneHeartbeatBitmaps deletes the bitmaps of months older than
+// heartbeat_retention_months before the current month and returns how many
+// were deleted. Bitmaps are keyed by month first, so when nothing is due the
+// range iteration is empty and the call is cheap enough for every block.
+func (k Keeper) PruneHeartbeatBitmaps(ctx sdk.Context) int {
+	cutoff := k.oldestRetainedHeartbeatMonth(ctx)
+	if cutoff == 0 {
+		return 0
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(types.HeartbeatBitmapKey, types.GetHeartbeatBitmapMonthPrefix(cutoff))
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+
+	if len(keys) > 0 {
+		k.Logger(ctx).Info("Pruned heartbeat bitmaps", "bitmaps", len(keys), "before_month", cutoff)
+	}
+	return len(keys)
+}
+
+// heartbeatCompliance decodes a validator's heartbeat bitmap for an uptime
+// month and returns it with the number of intervals to evaluate: all of
+// them for a past month, only those that have started for the current one.
+func (k Keeper) heartbeatCompliance(ctx sdk.Context, valAddr sdk.ValAddress, month uint64) (types.HeartbeatBitmap, uint64) {
+	bitmap, found := k.GetHeartbeatBitmap(ctx, valAddr, month)
+	if !found {
+		bitmap = k.newHeartbeatBitmap(ctx, valAddr, month)
+	}
+
+	elapsed := bitmap.Intervals
+	if month == k.getCurrentMonth(ctx) {
+		elapsed = bitmap.IntervalAt(ctx.BlockTime().Unix()-monthStartUnix(month)) + 1
+	}
+	return bitmap, elapsed
+}
+
+// oldestRetainedHeartbeatMonth returns the first month whose bitmaps are kept
+func (k Keeper) oldestRetainedHeartbeatMonth(ctx sdk.Context) uint64 {
+	retention := k.GetParams(ctx).HeartbeatRetentionMonths
+	currentMonth := k.getCurrentMonth(ctx)
+	if currentMonth <= retention {
+		return 0
+	}
+	return currentMonth - retention
+}
+
+// newHeartbeatBitmap returns an empty bitmap sized by the heartbeat_interval param
+func (k Keeper) newHeartbeatBitmap(ctx sdk.Context, valAddr sdk.ValAddress, month uint64) types.HeartbeatBitmap {
+	interval := int64(k.GetParams(ctx).HeartbeatInterval / time.Second)
+	return types.NewHeartbeatBitmap(valAddr.String(), month, interval, int64(MonthDuration/time.Second))
+}
+
+// monthStartUnix returns the unix time an uptime month starts at
+func monthStartUnix(month uint64) int64 {
+	return int64(month) * int64(MonthDuration/time.Second)
+}
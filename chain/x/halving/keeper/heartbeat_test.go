@@ -0,0 +1,178 @@
+package keeper_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+var heartbeatValidator = sdk.ValAddress([]byte("heartbeat-validator-"))
+
+// uptimeMonth returns the uptime month containing t
+func uptimeMonth(t time.Time) uint64 {
+	return uint64(t.Unix() / int64(keeper.MonthDuration/time.Second))
+}
+
+func TestRecordHeartbeatKeepsLatestAndBitmap(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	begin := monthStart(genesisTime).Add(keeper.MonthDuration)
+	month := uptimeMonth(begin)
+
+	// Two heartbeats in the first interval, then the third and seventh interval
+	for i, offset := range []time.Duration{time.Minute, 5 * time.Minute, 25 * time.Minute, time.Hour} {
+		ctx = ctx.WithBlockTime(begin.Add(offset)).WithBlockHeight(int64(i + 1))
+		k.RecordHeartbeat(ctx, heartbeatValidator)
+	}
+
+	last, found := k.GetLastHeartbeat(ctx, heartbeatValidator)
+	require.True(t, found)
+	require.Equal(t, begin.Add(time.Hour).Unix(), last.Timestamp)
+	require.Equal(t, int64(4), last.Height)
+	require.Len(t, k.GetAllLastHeartbeats(ctx), 1)
+
+	bitmap, found := k.GetHeartbeatBitmap(ctx, heartbeatValidator, month)
+	require.True(t, found)
+	require.Equal(t, uint64(3), bitmap.Present(bitmap.Intervals))
+	require.True(t, bitmap.IsSet(0))
+	require.True(t, bitmap.IsSet(2))
+	require.True(t, bitmap.IsSet(6))
+
+	// Mid-month only the intervals that have started are evaluated
+	ctx = ctx.WithBlockTime(begin.Add(2*time.Hour + time.Minute))
+	res, err := k.HeartbeatCompliance(sdk.WrapSDKContext(ctx), &types.QueryHeartbeatComplianceRequest{ValidatorAddress: heartbeatValidator.String()})
+	require.NoError(t, err)
+	require.Equal(t, month, res.Month)
+	require.Equal(t, uint64(13), res.ElapsedIntervals)
+	require.Equal(t, uint64(3), res.PresentIntervals)
+	require.Equal(t, last, res.LastHeartbeat)
+	require.True(t, sdk.NewDec(300).QuoInt64(13).Equal(res.CoveragePercent))
+
+	// Once the month is over all of its intervals count
+	ctx = ctx.WithBlockTime(begin.Add(keeper.MonthDuration))
+	res, err = k.HeartbeatCompliance(sdk.WrapSDKContext(ctx), &types.QueryHeartbeatComplianceRequest{ValidatorAddress: heartbeatValidator.String(), Month: month})
+	require.NoError(t, err)
+	require.Equal(t, uint64(4320), res.ElapsedIntervals)
+	require.Equal(t, uint64(3), res.PresentIntervals)
+
+	// A validator without heartbeats has zero coverage
+	res, err = k.HeartbeatCompliance(sdk.WrapSDKContext(ctx), &types.QueryHeartbeatComplianceRequest{ValidatorAddress: silentValidator.String()})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), res.PresentIntervals)
+	require.True(t, res.CoveragePercent.IsZero())
+
+	_, err = k.HeartbeatCompliance(sdk.WrapSDKContext(ctx), &types.QueryHeartbeatComplianceRequest{ValidatorAddress: heartbeatValidator.String(), Month: month + 2})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = k.HeartbeatCompliance(sdk.WrapSDKContext(ctx), &types.QueryHeartbeatComplianceRequest{ValidatorAddress: "gxrvaloper1invalid"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPruneHeartbeatBitmaps(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	begin := monthStart(genesisTime).Add(keeper.MonthDuration)
+	first := uptimeMonth(begin)
+
+	// One heartbeat in each of five months
+	for i := 0; i < 5; i++ {
+		ctx = ctx.WithBlockTime(begin.Add(time.Duration(i) * keeper.MonthDuration))
+		k.RecordHeartbeat(ctx, heartbeatValidator)
+		k.RecordHeartbeat(ctx, silentValidator)
+	}
+	require.Len(t, k.GetAllHeartbeatBitmaps(ctx), 10)
+
+	// The current month and the three months before it are retained
+	require.Equal(t, 2, k.PruneHeartbeatBitmaps(ctx))
+	require.Equal(t, 0, k.PruneHeartbeatBitmaps(ctx))
+	_, found := k.GetHeartbeatBitmap(ctx, heartbeatValidator, first)
+	require.False(t, found)
+	_, found = k.GetHeartbeatBitmap(ctx, heartbeatValidator, first+1)
+	require.True(t, found)
+
+	_, err := k.HeartbeatCompliance(sdk.WrapSDKContext(ctx), &types.QueryHeartbeatComplianceRequest{ValidatorAddress: heartbeatValidator.String(), Month: first})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// Shortening the retention prunes on the next block
+	params := k.GetParams(ctx)
+	params.HeartbeatRetentionMonths = 1
+	k.SetParams(ctx, params)
+	require.Equal(t, 4, k.PruneHeartbeatBitmaps(ctx))
+
+	bitmaps := k.GetAllHeartbeatBitmaps(ctx)
+	require.Len(t, bitmaps, 4)
+	require.Equal(t, first+3, bitmaps[0].Month)
+
+	// The latest heartbeat is never pruned
+	last, found := k.GetLastHeartbeat(ctx, heartbeatValidator)
+	require.True(t, found)
+	require.Equal(t, ctx.BlockTime().Unix(), last.Timestamp)
+}
+
+func TestIsValidatorBotRunning(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	params := k.GetParams(ctx)
+	params.BotHeartbeatTimeoutBlocks = 10
+	k.SetParams(ctx, params)
+
+	// Tracking started at height 100, and one validator's bot reported at 105
+	k.SetBotLivenessStart(ctx, 100)
+	k.RecordHeartbeat(ctx.WithBlockHeight(105), heartbeatValidator)
+
+	for _, tc := range []struct {
+		height    int64
+		validator sdk.ValAddress
+		running   bool
+	}{
+		{110, silentValidator, true},
+		{111, silentValidator, false},
+		{115, heartbeatValidator, true},
+		{116, heartbeatValidator, false},
+	} {
+		require.Equal(t, tc.running, k.IsValidatorBotRunning(ctx.WithBlockHeight(tc.height), tc.validator), "%s at height %d", tc.validator, tc.height)
+	}
+
+	// A later heartbeat extends the window
+	k.RecordHeartbeat(ctx.WithBlockHeight(116), heartbeatValidator)
+	require.True(t, k.IsValidatorBotRunning(ctx.WithBlockHeight(126), heartbeatValidator))
+}
+
+// BenchmarkHeartbeatStateSize records a month of heartbeats for 85 validators,
+// one per default ten-minute interval, and compares the state kept (latest
+// heartbeat plus bitmap) with storing every heartbeat as its own record.
+func BenchmarkHeartbeatStateSize(b *testing.B) {
+	const validators = 85
+	valAddrs := make([]sdk.ValAddress, validators)
+	for i := range valAddrs {
+		valAddrs[i] = sdk.ValAddress([]byte(fmt.Sprintf("heartbeat-val-%06d", i)))
+	}
+
+	for n := 0; n < b.N; n++ {
+		k, ctx := setupKeeper(b)
+		begin := monthStart(genesisTime).Add(keeper.MonthDuration)
+		interval := k.GetParams(ctx).HeartbeatInterval
+
+		historyBytes := 0
+		for at := begin; at.Before(begin.Add(keeper.MonthDuration)); at = at.Add(interval) {
+			blockCtx := ctx.WithBlockTime(at)
+			for _, valAddr := range valAddrs {
+				heartbeat := k.RecordHeartbeat(blockCtx, valAddr)
+				// A history record would be keyed by validator and timestamp
+				historyBytes += len(types.GetLastHeartbeatKey(valAddr)) + 8 + heartbeat.Size()
+			}
+		}
+
+		stateBytes := k.StoreBytes(ctx, types.LastHeartbeatKey) + k.StoreBytes(ctx, types.HeartbeatBitmapKey)
+		if stateBytes*100 > historyBytes {
+			b.Fatalf("bitmap state of %d bytes is not two orders of magnitude below the %d bytes of full history", stateBytes, historyBytes)
+		}
+
+		b.ReportMetric(float64(stateBytes), "bitmap-bytes/month")
+		b.ReportMetric(float64(historyBytes), "history-bytes/month")
+	}
+}
@@ -0,0 +1,45 @@
+package keeper
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// RegisterInvariants registers the halving invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "params-consistency", ParamsConsistencyInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "dex-distribution-balance", DexDistributionBalanceInvariant(k))
+}
+
+// ParamsConsistencyInvariant checks that the params in the module store and
+// in the legacy x/params subspace agree while both exist
+func ParamsConsistencyInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		stored := ctx.KVStore(k.storeKey).Get(types.ParamsKey)
+		if stored == nil || !k.hasLegacyParams(ctx) {
+			return sdk.FormatInvariant(types.ModuleName, "params-consistency", "single params source"), false
+		}
+
+		legacy := k.legacyParams(ctx)
+		broken := !bytes.Equal(stored, k.cdc.MustMarshal(&legacy))
+		return sdk.FormatInvariant(types.ModuleName, "params-consistency",
+			fmt.Sprintf("module store and legacy subspace params differ: %t", broken)), broken
+	}
+}
+
+// DexDistributionBalanceInvariant checks that the dex_distribution account
+// holds at least the pending DEX share of all cycles
+func DexDistributionBalanceInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		balance := k.GetDEXDistributionBalance(ctx)
+		pending := k.GetTotalPendingDexAllocation(ctx)
+
+		broken := balance.IsLT(pending)
+		return sdk.FormatInvariant(types.ModuleName, "dex-distribution-balance",
+			fmt.Sprintf("%s account holds %s of %s pending", types.DexDistributionAccountName, balance, pending)), broken
+	}
+}
@@ -15,13 +15,14 @@ import (
 	stakingkeeper "github.com/cosmos/cosmos-sdk/x/staking/keeper"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
+	"github.com/Crocodile-ark/gxrchaind/internal/rounding"
 	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
 )
 
 const (
 	// MinimumSupplyThreshold is the minimum supply threshold (1,000 GXR)
 	MinimumSupplyThreshold = 1000 * 1e8 // 1,000 GXR in ugen
-	// HalvingReductionRate is the reduction rate per halving cycle (15%)
+	// HalvingReductionRate is the reduction of the halving fund per cycle (15%)
 	HalvingReductionRate = "0.15"
 	// MainDenom is the main denomination
 	MainDenom = "ugen"
@@ -35,21 +36,61 @@ const (
 	ValidatorInactiveThreshold = 10
 	// MonthDuration is 30 days
 	MonthDuration = 30 * 24 * time.Hour
-	// DEXDistributionPeriod is 2 years (only years 1-2)
-	DEXDistributionPeriod = 2 * 365 * 24 * time.Hour
 	// MonthlyDistributionTrigger is 30 days
 	MonthlyDistributionTrigger = 30 * 24 * time.Hour
+	// SupplySnapshotInterval is how often the total supply is sampled for trend analysis
+	SupplySnapshotInterval = 24 * time.Hour
+	// MaxSupplySnapshots bounds the supply trend window (90 daily samples)
+	MaxSupplySnapshots = 90
 )
 
 type (
 	Keeper struct {
 		cdc        codec.BinaryCodec
 		storeKey   storetypes.StoreKey
+		// paramstore is the legacy x/params subspace, read until the params
+		// are migrated to the module store and kept in sync until it is deleted
 		paramstore paramtypes.Subspace
 
 		accountKeeper authkeeper.AccountKeeper
 		bankKeeper    bankkeeper.Keeper
 		stakingKeeper *stakingkeeper.Keeper
+		// bondedValidators weights the validator rewards by bonded tokens,
+		// the staking keeper unless replaced in tests
+		bondedValidators types.BondedValidatorSource
+		// validators looks up the validators checked for a tombstone, the
+		// staking keeper unless replaced in tests
+		validators types.ValidatorSource
+
+		// lpPoolDistributor is optional; set when the DEX share may go to feerouter LP pools
+		lpPoolDistributor types.LPPoolDistributor
+		// communityPoolFunder receives the DEX share redirected to the community pool
+		communityPoolFunder types.CommunityPoolFunder
+		// lpPoolRegistry whitelists the destinations of DEX reserve withdrawals;
+		// without it no withdrawal is possible
+		lpPoolRegistry types.LPPoolRegistry
+		// communityTaxSource is read by reward estimates; without it the
+		// community tax is taken as zero
+		communityTaxSource types.CommunityTaxSource
+		// slashingKeeper excludes tombstoned validators from the validator
+		// rewards; without it only the equivocations the module recorded exclude them
+		slashingKeeper types.SlashingKeeper
+
+		// authority may update the distribution shares, normally the gov module account
+		authority string
+	}
+
+	// RewardSplit is how one monthly distribution is divided. After the DEX
+	// window the DEX share is zero and Redirected holds the amount sent to
+	// RedirectDestination instead. Withheld is the part of the validator share
+	// kept to settle clawbacks, which returns to the halving fund.
+	RewardSplit struct {
+		Validators          sdk.Int
+		Delegators          sdk.Int
+		Dex                 sdk.Int
+		Redirected          sdk.Int
+		RedirectDestination string
+		Withheld            sdk.Int
 	}
 )
 
@@ -60,35 +101,66 @@ func NewKeeper(
 	accountKeeper authkeeper.AccountKeeper,
 	bankKeeper bankkeeper.Keeper,
 	stakingKeeper *stakingkeeper.Keeper,
+	authority string,
 ) Keeper {
 	// set KeyTable if it has not already been set
 	if !ps.HasKeyTable() {
 		ps = ps.WithKeyTable(types.ParamKeyTable())
 	}
 
-	return Keeper{
+	k := Keeper{
 		cdc:           cdc,
 		storeKey:      storeKey,
 		paramstore:    ps,
 		accountKeeper: accountKeeper,
 		bankKeeper:    bankKeeper,
 		stakingKeeper: stakingKeeper,
+		authority:     authority,
+	}
+	if stakingKeeper != nil {
+		k.bondedValidators = stakingKeeper
+		k.validators = stakingKeeper
 	}
+	return k
 }
 
-func (k Keeper) Logger(ctx sdk.Context) log.Logger {
-	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
+// GetAuthority returns the address allowed to update the distribution shares
+func (k Keeper) GetAuthority() string {
+	return k.authority
+}
+
+// SetLPPoolDistributor wires the feerouter LP-pool distribution used when the
+// DexShareToLPPools param is enabled
+func (k *Keeper) SetLPPoolDistributor(distributor types.LPPoolDistributor) {
+	k.lpPoolDistributor = distributor
+}
+
+// SetSlashingKeeper wires the slashing keeper whose tombstoned validators
+// receive no validator rewards
+func (k *Keeper) SetSlashingKeeper(slashingKeeper types.SlashingKeeper) {
+	k.slashingKeeper = slashingKeeper
+}
+
+// SetCommunityPoolFunder wires the distribution keeper used when the
+// PostDexShareDestination param is community_pool
+func (k *Keeper) SetCommunityPoolFunder(funder types.CommunityPoolFunder) {
+	k.communityPoolFunder = funder
+}
+
+// SetLPPoolRegistry wires the feerouter LP pools that DEX reserve
+// withdrawals may be sent to
+func (k *Keeper) SetLPPoolRegistry(registry types.LPPoolRegistry) {
+	k.lpPoolRegistry = registry
 }
 
-// GetParams get all parameters as types.Params
-func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
-	k.paramstore.GetParamSet(ctx, &params)
-	return
+// SetCommunityTaxSource wires the distribution keeper whose community tax
+// reward estimates deduct from the delegator share
+func (k *Keeper) SetCommunityTaxSource(source types.CommunityTaxSource) {
+	k.communityTaxSource = source
 }
 
-// SetParams set the params
-func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
-	k.paramstore.SetParamSet(ctx, &params)
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
 }
 
 // GetHalvingInfo gets the current halving information
@@ -142,7 +214,7 @@ func (k Keeper) SetValidatorUptime(ctx sdk.Context, valAddr sdk.ValAddress, upti
 // GetLastDistributionTime gets the last distribution timestamp
 func (k Keeper) GetLastDistributionTime(ctx sdk.Context) (int64, bool) {
 	store := ctx.KVStore(k.storeKey)
-	bz := store.Get(types.LastDistributionKey)
+	bz := store.Get(types.LastDistributionTimeKey)
 	if bz == nil {
 		return 0, false
 	}
@@ -153,7 +225,85 @@ func (k Keeper) GetLastDistributionTime(ctx sdk.Context) (int64, bool) {
 // SetLastDistributionTime sets the last distribution timestamp
 func (k Keeper) SetLastDistributionTime(ctx sdk.Context, timestamp int64) {
 	store := ctx.KVStore(k.storeKey)
-	store.Set(types.LastDistributionKey, sdk.Uint64ToBigEndian(uint64(timestamp)))
+	store.Set(types.LastDistributionTimeKey, sdk.Uint64ToBigEndian(uint64(timestamp)))
+}
+
+// GetSupplySnapshots returns the recorded supply snapshots, oldest first
+func (k Keeper) GetSupplySnapshots(ctx sdk.Context) []types.SupplySnapshot {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.SupplySnapshotKey)
+	defer iterator.Close()
+
+	var snapshots []types.SupplySnapshot
+	for ; iterator.Valid(); iterator.Next() {
+		var snapshot types.SupplySnapshot
+		k.cdc.MustUnmarshal(iterator.Value(), &snapshot)
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots
+}
+
+// SetSupplySnapshot stores a supply snapshot keyed by its timestamp
+func (k Keeper) SetSupplySnapshot(ctx sdk.Context, snapshot types.SupplySnapshot) {
+	store := ctx.KVStore(k.storeKey)
+	key := append(append([]byte{}, types.SupplySnapshotKey...), sdk.Uint64ToBigEndian(uint64(snapshot.Timestamp))...)
+	bz := k.cdc.MustMarshal(&snapshot)
+	store.Set(key, bz)
+}
+
+// SupplySnapshotDue reports whether a new supply snapshot should be taken
+func (k Keeper) SupplySnapshotDue(ctx sdk.Context) bool {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStoreReversePrefixIterator(store, types.SupplySnapshotKey)
+	defer iterator.Close()
+
+	if !iterator.Valid() {
+		return true
+	}
+
+	var latest types.SupplySnapshot
+	k.cdc.MustUnmarshal(iterator.Value(), &latest)
+	return ctx.BlockTime().Sub(time.Unix(latest.Timestamp, 0)) >= SupplySnapshotInterval
+}
+
+// RecordSupplySnapshot stores the given supply at the current block and prunes
+// snapshots beyond the trend window
+func (k Keeper) RecordSupplySnapshot(ctx sdk.Context, supply sdk.Coin) {
+	k.SetSupplySnapshot(ctx, types.SupplySnapshot{
+		Timestamp: ctx.BlockTime().Unix(),
+		Height:    ctx.BlockHeight(),
+		Supply:    supply,
+	})
+
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.SupplySnapshotKey)
+	var stale [][]byte
+	count := 0
+	for ; iterator.Valid(); iterator.Next() {
+		count++
+		stale = append(stale, iterator.Key())
+	}
+	iterator.Close()
+
+	if count > MaxSupplySnapshots {
+		for _, key := range stale[:count-MaxSupplySnapshots] {
+			store.Delete(key)
+		}
+	}
+}
+
+// ForecastSupplyFloor estimates when total supply will fall below the minimum
+// threshold, based on the supply trend across the recorded snapshots.
+func (k Keeper) ForecastSupplyFloor(ctx sdk.Context) types.SupplyFloorForecast {
+	current := k.GetCurrentTotalSupply(ctx)
+
+	oldest := types.SupplySnapshot{Timestamp: ctx.BlockTime().Unix(), Supply: current}
+	if snapshots := k.GetSupplySnapshots(ctx); len(snapshots) > 0 {
+		oldest = snapshots[0]
+	}
+
+	return types.NewSupplyFloorForecast(oldest, current, sdk.NewInt(MinimumSupplyThreshold), ctx.BlockTime(), HalvingCycleDuration)
 }
 
 // CheckAndAdvanceHalvingCycle checks if we should advance to the next halving cycle
@@ -166,7 +316,7 @@ func (k Keeper) CheckAndAdvanceHalvingCycle(ctx sdk.Context) error {
 			CurrentCycle:       1,
 			CycleStartTime:     ctx.BlockTime().Unix(),
 			TotalSupply:        currentSupply,
-			HalvingFund:        sdk.NewCoin(MainDenom, sdk.ZeroInt()),
+			HalvingFund:        k.fundFromReserve(ctx, sdk.NewInt(types.FirstCycleFund)),
 			DistributionActive: false,
 			DistributionStart:  0,
 			DistributedAmount:  sdk.NewCoin(MainDenom, sdk.ZeroInt()),
@@ -178,7 +328,9 @@ func (k Keeper) CheckAndAdvanceHalvingCycle(ctx sdk.Context) error {
 		return nil
 	}
 
-	// Check if total supply is below threshold - stop permanently
+	// Check if total supply is below threshold - stop permanently.
+	// The halving burns and re-mints the same amount, so only burns elsewhere
+	// can bring supply down to this floor (see ForecastSupplyFloor).
 	currentSupply := k.GetCurrentTotalSupply(ctx)
 	if currentSupply.Amount.LT(sdk.NewInt(MinimumSupplyThreshold)) {
 		k.Logger(ctx).Info("Halving stopped permanently: total supply below minimum threshold",
@@ -198,17 +350,38 @@ func (k Keeper) CheckAndAdvanceHalvingCycle(ctx sdk.Context) error {
 	return nil
 }
 
-// advanceToNextCycle advances to the next halving cycle
+// NextCycleFund returns the fund of the cycle after one funded with
+// cycleFund: 15% less than the previous cycle
+func NextCycleFund(cycleFund sdk.Int) sdk.Int {
+	reductionRate := sdk.MustNewDecFromStr(HalvingReductionRate)
+	return sdk.OneDec().Sub(reductionRate).MulInt(cycleFund).TruncateInt()
+}
+
+// fundFromReserve caps a cycle fund at the halving reserve, the module
+// account balance. The pending DEX shares are held apart in the
+// dex_distribution account.
+func (k Keeper) fundFromReserve(ctx sdk.Context, fund sdk.Int) sdk.Coin {
+	reserve := k.bankKeeper.GetBalance(ctx, k.accountKeeper.GetModuleAddress(types.ModuleName), MainDenom).Amount
+	if fund.GT(reserve) {
+		k.Logger(ctx).Error("Halving reserve cannot cover the cycle fund",
+			"fund", fund.String(),
+			"reserve", reserve.String(),
+		)
+		fund = reserve
+	}
+
+	return sdk.NewCoin(MainDenom, fund)
+}
+
+// advanceToNextCycle advances to the next halving cycle. Cycle funds are
+// allocated from the halving reserve pre-funded at genesis: each cycle gets
+// 15% less than the previous one, and what a cycle left undistributed stays in
+// the reserve.
 func (k Keeper) advanceToNextCycle(ctx sdk.Context, info types.HalvingInfo) error {
 	currentSupply := k.GetCurrentTotalSupply(ctx)
-	
-	// Calculate 15% for halving fund
-	reductionRate := sdk.MustNewDecFromStr(HalvingReductionRate)
-	halvingAmount := currentSupply.Amount.ToDec().Mul(reductionRate).TruncateInt()
-	
-	// Create halving fund entry (virtual allocation)
-	halvingFund := sdk.NewCoin(MainDenom, halvingAmount)
-	
+
+	halvingFund := k.fundFromReserve(ctx, NextCycleFund(info.CycleFund().Amount))
+
 	// Update halving info for next cycle
 	newInfo := types.HalvingInfo{
 		CurrentCycle:       info.CurrentCycle + 1,
@@ -261,20 +434,81 @@ func (k Keeper) CheckAndUpdateDistributionStatus(ctx sdk.Context) error {
 	return nil
 }
 
-// ShouldDistribute checks if monthly distribution should occur
+// ShouldDistribute checks if monthly distribution should occur: a month of
+// the distribution period is due and has not been paid yet
 func (k Keeper) ShouldDistribute(ctx sdk.Context) bool {
 	info, found := k.GetHalvingInfo(ctx)
 	if !found || !info.DistributionActive {
 		return false
 	}
 
-	// Check if 30 days have passed since last distribution
-	if info.LastMonthlyDistrib == 0 {
-		return true // First distribution
+	return k.monthsDistributed(ctx, info) < k.monthsDue(ctx, info)
+}
+
+// NextMonthlyDistributionTime returns when the next monthly distribution is
+// due: MonthlyDistributionTrigger after the last one. State from before the
+// last distribution time was stored falls back to LastMonthlyDistrib. The
+// first distribution of a distribution period is due on the first day of a
+// month at or after the period's start.
+func (k Keeper) NextMonthlyDistributionTime(ctx sdk.Context, info types.HalvingInfo) time.Time {
+	last, found := k.GetLastDistributionTime(ctx)
+	if !found {
+		last = info.LastMonthlyDistrib
+	}
+	if last > 0 && last >= info.DistributionStart {
+		return time.Unix(last, 0).Add(MonthlyDistributionTrigger)
 	}
 
-	lastDistrib := time.Unix(info.LastMonthlyDistrib, 0)
-	return ctx.BlockTime().Sub(lastDistrib) >= MonthlyDistributionTrigger
+	start := time.Unix(info.DistributionStart, 0).UTC()
+	if start.Day() == 1 {
+		return start
+	}
+	return time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// CatchUpPending reports whether months that are already over have not been
+// paid, e.g. after importing state whose last distribution is months old.
+// Catch-up distributions run one month per block until the current month is
+// reached, without waiting for the regular distribution day.
+func (k Keeper) CatchUpPending(ctx sdk.Context) bool {
+	info, found := k.GetHalvingInfo(ctx)
+	if !found || !info.DistributionActive {
+		return false
+	}
+
+	return k.monthsDistributed(ctx, info)+1 < k.monthsDue(ctx, info)
+}
+
+// monthsDue returns the number of months of the distribution period that are
+// due by the block time. Month n is due n-1 months after the period started.
+func (k Keeper) monthsDue(ctx sdk.Context, info types.HalvingInfo) uint64 {
+	elapsed := ctx.BlockTime().Sub(time.Unix(info.DistributionStart, 0))
+	if elapsed < 0 {
+		return 0
+	}
+
+	due := uint64(elapsed/MonthDuration) + 1
+	if due > types.DistributionMonths {
+		due = types.DistributionMonths
+	}
+	return due
+}
+
+// monthsDistributed returns the months paid in the current distribution
+// period. State written before months_distributed was tracked falls back to
+// counting the cycle's distribution records.
+func (k Keeper) monthsDistributed(ctx sdk.Context, info types.HalvingInfo) uint64 {
+	if info.MonthsDistributed > 0 {
+		return info.MonthsDistributed
+	}
+
+	var months uint64
+	for _, record := range k.GetAllDistributionRecords(ctx) {
+		if record.Cycle == info.CurrentCycle && !record.Amount.IsNil() && record.Amount.IsPositive() {
+			months++
+		}
+	}
+	return months
 }
 
 // DistributeHalvingRewards distributes monthly rewards from halving fund
@@ -288,17 +522,18 @@ func (k Keeper) DistributeHalvingRewards(ctx sdk.Context) error {
 		return nil
 	}
 
-	// Calculate monthly distribution amount (over 24 months)
-	monthlyAmount := k.calculateMonthlyDistribution(ctx, info)
+	paid := k.monthsDistributed(ctx, info)
+	month := paid + 1
+	monthsBehind := k.monthsDue(ctx, info) - month
+	catchUp := monthsBehind > 0
+
+	// Calculate monthly distribution amount (over 24 months), capped at the
+	// fund and the distribution ceiling
+	monthlyAmount, clamped := k.nextMonthlyDistribution(ctx, info)
 	if monthlyAmount.IsZero() {
 		return nil
 	}
 
-	// Check if we have enough in halving fund
-	if info.HalvingFund.Amount.LT(monthlyAmount.Amount) {
-		monthlyAmount = info.HalvingFund
-	}
-
 	// Burn the monthly amount from total supply
 	if err := k.bankKeeper.BurnCoins(ctx, types.ModuleName, sdk.NewCoins(monthlyAmount)); err != nil {
 		return fmt.Errorf("failed to burn monthly distribution: %w", err)
@@ -310,15 +545,64 @@ func (k Keeper) DistributeHalvingRewards(ctx sdk.Context) error {
 	}
 
 	// Distribute rewards
-	if err := k.distributeRewards(ctx, monthlyAmount, info); err != nil {
+	split, err := k.distributeRewards(ctx, monthlyAmount, info, month)
+	if err != nil {
 		return fmt.Errorf("failed to distribute rewards: %w", err)
 	}
 
-	// Update halving info
-	info.DistributedAmount = info.DistributedAmount.Add(monthlyAmount)
-	info.HalvingFund = info.HalvingFund.Sub(monthlyAmount)
+	k.SetDistributionRecord(ctx, types.DistributionRecord{
+		Timestamp:              ctx.BlockTime().Unix(),
+		Amount:                 monthlyAmount,
+		Cycle:                  info.CurrentCycle,
+		Month:                  k.getCycleMonth(ctx, info),
+		RedirectedDexAmount:    sdk.NewCoin(MainDenom, split.Redirected),
+		DexRedirectDestination: split.RedirectDestination,
+		CatchUp:                catchUp,
+		DistributionMonth:      month,
+	})
+
+	// Update halving info; what was withheld for clawbacks is paid again from
+	// the fund in later months
+	info.DistributedAmount = info.DistributedAmount.Add(monthlyAmount).SubAmount(split.Withheld)
+	info.HalvingFund = info.HalvingFund.Sub(monthlyAmount).AddAmount(split.Withheld)
 	info.LastMonthlyDistrib = ctx.BlockTime().Unix()
+	info.MonthsDistributed = month
+	info.ClampedAmount = sdk.NewCoin(MainDenom, clamped)
 	k.SetHalvingInfo(ctx, info)
+	k.SetLastDistributionTime(ctx, info.LastMonthlyDistrib)
+
+	if clamped.IsPositive() {
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeDistributionClamped,
+			sdk.NewAttribute(types.AttributeKeyAmount, monthlyAmount.String()),
+			sdk.NewAttribute(types.AttributeKeyDueAmount, monthlyAmount.AddAmount(clamped).String()),
+			sdk.NewAttribute(types.AttributeKeyClampedAmount, sdk.NewCoin(MainDenom, clamped).String()),
+			sdk.NewAttribute(types.AttributeKeyCycle, fmt.Sprintf("%d", info.CurrentCycle)),
+			sdk.NewAttribute(types.AttributeKeyMonth, fmt.Sprintf("%d", month)),
+		))
+
+		k.Logger(ctx).Error("Monthly halving distribution clamped at the distribution ceiling",
+			"month", month,
+			"paid", monthlyAmount.String(),
+			"clamped", clamped.String(),
+		)
+	}
+
+	if catchUp {
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeCatchUpDistribution,
+			sdk.NewAttribute(types.AttributeKeyAmount, monthlyAmount.String()),
+			sdk.NewAttribute(types.AttributeKeyCycle, fmt.Sprintf("%d", info.CurrentCycle)),
+			sdk.NewAttribute(types.AttributeKeyMonth, fmt.Sprintf("%d", month)),
+			sdk.NewAttribute(types.AttributeKeyMonthsBehind, fmt.Sprintf("%d", monthsBehind)),
+		))
+
+		k.Logger(ctx).Info("Catch-up halving distribution for a missed month",
+			"month", month,
+			"months_behind", monthsBehind,
+			"amount", monthlyAmount.String(),
+		)
+	}
 
 	k.Logger(ctx).Info("Monthly halving rewards distributed",
 		"amount", monthlyAmount.String(),
@@ -332,9 +616,8 @@ func (k Keeper) DistributeHalvingRewards(ctx sdk.Context) error {
 
 // calculateMonthlyDistribution calculates monthly distribution amount
 func (k Keeper) calculateMonthlyDistribution(ctx sdk.Context, info types.HalvingInfo) sdk.Coin {
-	// Distribute over 24 months (2 years)
-	totalMonths := int64(24)
-	monthlyAmount := info.HalvingFund.Amount.QuoRaw(totalMonths)
+	// Distribute the cycle fund over 24 months (2 years)
+	monthlyAmount := info.CycleFund().Amount.QuoRaw(types.DistributionMonths)
 	
 	// Ensure we don't exceed available funds
 	if monthlyAmount.GT(info.HalvingFund.Amount) {
@@ -344,94 +627,236 @@ func (k Keeper) calculateMonthlyDistribution(ctx sdk.Context, info types.Halving
 	return sdk.NewCoin(MainDenom, monthlyAmount)
 }
 
-// distributeRewards distributes rewards according to the enhanced specifications
-func (k Keeper) distributeRewards(ctx sdk.Context, totalAmount sdk.Coin, info types.HalvingInfo) error {
-	// Distribution percentages:
-	// - 70% to active validators
-	// - 20% to delegators (PoS staking pool)
-	// - 10% to DEX pools (only years 1-2)
-	
-	validatorAmount := totalAmount.Amount.ToDec().Mul(sdk.MustNewDecFromStr("0.70")).TruncateInt()
-	delegatorAmount := totalAmount.Amount.ToDec().Mul(sdk.MustNewDecFromStr("0.20")).TruncateInt()
-	dexAmount := totalAmount.Amount.ToDec().Mul(sdk.MustNewDecFromStr("0.10")).TruncateInt()
+// MonthlyDistributionCeiling returns the most one monthly distribution may
+// pay: the lower of max_monthly_distribution and
+// max_monthly_distribution_supply_ratio of the current supply. A zero param
+// removes its limit; false means there is no ceiling at all.
+func (k Keeper) MonthlyDistributionCeiling(ctx sdk.Context) (sdk.Int, bool) {
+	params := k.GetParams(ctx)
 
-	// Distribute to active validators (70%)
-	if err := k.distributeToActiveValidators(ctx, sdk.NewCoin(MainDenom, validatorAmount)); err != nil {
-		return fmt.Errorf("failed to distribute to validators: %w", err)
+	ceiling, capped := sdk.ZeroInt(), false
+	if !params.MaxMonthlyDistribution.IsNil() && params.MaxMonthlyDistribution.IsPositive() {
+		ceiling, capped = params.MaxMonthlyDistribution, true
 	}
 
-	// Distribute to delegators (20%)
-	if err := k.distributeToDelegators(ctx, sdk.NewCoin(MainDenom, delegatorAmount)); err != nil {
-		return fmt.Errorf("failed to distribute to delegators: %w", err)
+	ratio := params.MaxMonthlyDistributionSupplyRatio
+	if !ratio.IsNil() && ratio.IsPositive() {
+		supplyCeiling := ratio.MulInt(k.GetCurrentTotalSupply(ctx).Amount).TruncateInt()
+		if !capped || supplyCeiling.LT(ceiling) {
+			ceiling, capped = supplyCeiling, true
+		}
 	}
 
-	// Distribute to DEX (10%, only in years 1-2)
-	if err := k.distributeToDEX(ctx, sdk.NewCoin(MainDenom, dexAmount), info); err != nil {
-		return fmt.Errorf("failed to distribute to DEX: %w", err)
+	return ceiling, capped
+}
+
+// nextMonthlyDistribution returns what the next distribution month pays: the
+// monthly amount plus what the ceiling held back in earlier months, at most
+// the halving fund and at most the distribution ceiling. The part over the
+// ceiling is returned as clamped; it stays in the fund for later months.
+func (k Keeper) nextMonthlyDistribution(ctx sdk.Context, info types.HalvingInfo) (sdk.Coin, sdk.Int) {
+	due := k.calculateMonthlyDistribution(ctx, info).AddAmount(info.Clamped())
+	if info.HalvingFund.Amount.LT(due.Amount) {
+		due = info.HalvingFund
 	}
 
-	return nil
+	ceiling, capped := k.MonthlyDistributionCeiling(ctx)
+	if !capped || due.Amount.LTE(ceiling) {
+		return due, sdk.ZeroInt()
+	}
+	return sdk.NewCoin(MainDenom, ceiling), due.Amount.Sub(ceiling)
 }
 
-// distributeToActiveValidators distributes rewards to active validators only
-func (k Keeper) distributeToActiveValidators(ctx sdk.Context, amount sdk.Coin) error {
-	validators := k.stakingKeeper.GetBondedValidatorsByPower(ctx)
-	if len(validators) == 0 {
-		k.Logger(ctx).Info("No bonded validators found, forfeiting validator rewards")
-		return nil
+// splitRewards divides the monthly amount between validators, delegators and
+// the DEX. Once the DEX window has closed the DEX share goes to the
+// PostDexShareDestination param instead of being dropped.
+func (k Keeper) splitRewards(ctx sdk.Context, totalAmount sdk.Coin, info types.HalvingInfo) RewardSplit {
+	// The ValidatorShare, DexShare and DelegatorShare params, by default 70%
+	// to active validators, 10% to DEX pools (only during the DEX window)
+	// and 20% to delegators (PoS staking pool). Delegators come last so they
+	// can take the rounding remainder.
+	params := k.GetParams(ctx)
+	parts := rounding.Split(totalAmount.Amount, []sdk.Dec{
+		params.ValidatorShare,
+		params.DexShare,
+		params.DelegatorShare,
+	}, params.RoundingStrategy, params.RemainderToPos)
+
+	split := RewardSplit{
+		Validators: parts[0],
+		Dex:        parts[1],
+		Delegators: parts[2],
+		Redirected: sdk.ZeroInt(),
+		Withheld:   sdk.ZeroInt(),
 	}
 
-	// Filter active validators (uptime > 20 days in current month)
-	activeValidators := make([]stakingtypes.Validator, 0)
-	for _, validator := range validators {
-		valAddr, err := sdk.ValAddressFromBech32(validator.OperatorAddress)
-		if err != nil {
-			k.Logger(ctx).Error("Invalid validator address", "validator", validator.OperatorAddress, "error", err)
-			continue
+	if k.isDEXWindowOpen(ctx, info) {
+		return split
+	}
+
+	split.Redirected, split.Dex = split.Dex, sdk.ZeroInt()
+	split.RedirectDestination = params.PostDexShareDestination
+
+	switch split.RedirectDestination {
+	case types.PostDexShareToValidators:
+		split.Validators = split.Validators.Add(split.Redirected)
+	case types.PostDexShareToDelegators:
+		split.Delegators = split.Delegators.Add(split.Redirected)
+	}
+
+	return split
+}
+
+// distributeRewards distributes the rewards of a distribution month according
+// to the enhanced specifications
+func (k Keeper) distributeRewards(ctx sdk.Context, totalAmount sdk.Coin, info types.HalvingInfo, month uint64) (RewardSplit, error) {
+	split := k.splitRewards(ctx, totalAmount, info)
+
+	// Distribute to active validators (ValidatorShare)
+	withheld, err := k.distributeToActiveValidators(ctx, sdk.NewCoin(MainDenom, split.Validators), info, month)
+	if err != nil {
+		return split, fmt.Errorf("failed to distribute to validators: %w", err)
+	}
+	split.Withheld = withheld
+
+	// Distribute to delegators (DelegatorShare)
+	if err := k.distributeToDelegators(ctx, sdk.NewCoin(MainDenom, split.Delegators)); err != nil {
+		return split, fmt.Errorf("failed to distribute to delegators: %w", err)
+	}
+
+	// Distribute to DEX (DexShare, only inside the DEX window)
+	if split.Dex.IsPositive() {
+		if err := k.distributeToDEX(ctx, sdk.NewCoin(MainDenom, split.Dex), info); err != nil {
+			return split, fmt.Errorf("failed to distribute to DEX: %w", err)
+		}
+	}
+
+	// After the DEX window the DEX share goes to the post_dex_share_destination
+	if split.Redirected.IsPositive() {
+		if err := k.redirectDexShare(ctx, split, info); err != nil {
+			return split, fmt.Errorf("failed to redirect DEX share: %w", err)
+		}
+	}
+
+	return split, nil
+}
+
+// redirectDexShare sends the DEX share to its post-window destination. The
+// validators and delegators destinations were already folded into their
+// shares by splitRewards; only the event is emitted for them.
+func (k Keeper) redirectDexShare(ctx sdk.Context, split RewardSplit, info types.HalvingInfo) error {
+	amount := sdk.NewCoins(sdk.NewCoin(MainDenom, split.Redirected))
+
+	switch split.RedirectDestination {
+	case types.PostDexShareToCommunityPool:
+		if k.communityPoolFunder == nil {
+			return fmt.Errorf("community pool funder not set")
+		}
+		if err := k.communityPoolFunder.FundCommunityPool(ctx, amount, authtypes.NewModuleAddress(types.ModuleName)); err != nil {
+			return fmt.Errorf("failed to fund community pool: %w", err)
+		}
+	case types.PostDexShareBurn:
+		if err := k.bankKeeper.BurnCoins(ctx, types.ModuleName, amount); err != nil {
+			return fmt.Errorf("failed to burn DEX share: %w", err)
 		}
+	}
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeDexShareRedirected,
+		sdk.NewAttribute(types.AttributeKeyAmount, amount.String()),
+		sdk.NewAttribute(types.AttributeKeyDestination, split.RedirectDestination),
+		sdk.NewAttribute(types.AttributeKeyCycle, fmt.Sprintf("%d", info.CurrentCycle)),
+		sdk.NewAttribute(types.AttributeKeyMonth, fmt.Sprintf("%d", k.getCycleMonth(ctx, info))),
+	))
+
+	k.Logger(ctx).Info("DEX share redirected after DEX distribution period",
+		"amount", amount.String(),
+		"destination", split.RedirectDestination,
+		"cycle", info.CurrentCycle,
+	)
+
+	return nil
+}
+
+// distributeToActiveValidators distributes rewards to the validators eligible
+// in the month's eligibility snapshot only, weighted by their bonded tokens,
+// and returns the part withheld to settle clawbacks
+func (k Keeper) distributeToActiveValidators(ctx sdk.Context, amount sdk.Coin, info types.HalvingInfo, month uint64) (sdk.Int, error) {
+	// Tombstoned validators are never paid again, nothing could be withheld
+	// from them
+	k.writeOffTombstonedClawbacks(ctx)
+
+	totalWithheld := sdk.ZeroInt()
+	snapshots := k.eligibilitySnapshot(ctx, info, month)
+	if len(snapshots) == 0 {
+		k.Logger(ctx).Info("No bonded validators found, forfeiting validator rewards")
+		return totalWithheld, nil
+	}
 
-		if k.isValidatorActive(ctx, valAddr) {
-			activeValidators = append(activeValidators, validator)
+	// Filter validators that were active (uptime > 20 days in the month) at the
+	// snapshot and did not double sign since
+	activeValidators := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		valAddr, err := sdk.ValAddressFromBech32(snapshot.ValidatorAddress)
+		if snapshot.Eligible && err == nil && k.equivocated(ctx, valAddr) {
+			k.Logger(ctx).Info("Validator forfeit rewards due to equivocation",
+				"validator", snapshot.ValidatorAddress,
+				"month", month,
+			)
+		} else if snapshot.Eligible {
+			activeValidators[snapshot.ValidatorAddress] = true
 		} else {
 			k.Logger(ctx).Info("Validator forfeit rewards due to inactivity",
-				"validator", validator.OperatorAddress,
-				"month", k.getCurrentMonth(ctx),
+				"validator", snapshot.ValidatorAddress,
+				"month", month,
+				"inactive_days", snapshot.InactiveDays.String(),
 			)
 		}
 	}
 
 	if len(activeValidators) == 0 {
 		k.Logger(ctx).Info("No active validators found, forfeiting all validator rewards")
-		return nil
+		return totalWithheld, nil
 	}
 
-	// Distribute equally among active validators
-	perValidatorAmount := amount.Amount.QuoRaw(int64(len(activeValidators)))
-	if perValidatorAmount.IsZero() {
-		return nil
+	// Distribute among the active validators by their bonded tokens
+	shares := k.WeightedValidatorReward(ctx, activeValidators, amount.Amount)
+	if len(shares) < len(activeValidators) {
+		k.Logger(ctx).Info("Eligible validators no longer bonded forfeit rewards",
+			"eligible", len(activeValidators),
+			"bonded", len(shares),
+			"month", month,
+		)
 	}
 
-	for _, validator := range activeValidators {
-		valAddr, err := sdk.ValAddressFromBech32(validator.OperatorAddress)
-		if err != nil {
+	for _, share := range shares {
+		if !share.Amount.IsPositive() {
 			continue
 		}
 
-		accAddr := sdk.AccAddress(valAddr)
-		reward := sdk.NewCoin(MainDenom, perValidatorAmount)
+		accAddr := sdk.AccAddress(share.Validator)
+		// An outstanding clawback is settled first; the withheld part stays in
+		// the module account and is credited back to the halving fund
+		withheld := k.clawbackWithheld(ctx, share.Validator, share.Amount)
+		reward := sdk.NewCoin(MainDenom, share.Amount.Sub(withheld))
 		
-		if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, accAddr, sdk.NewCoins(reward)); err != nil {
-			k.Logger(ctx).Error("Failed to send reward to validator", "validator", validator.OperatorAddress, "error", err)
-			continue
+		if reward.IsPositive() {
+			if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, accAddr, sdk.NewCoins(reward)); err != nil {
+				k.Logger(ctx).Error("Failed to send reward to validator", "validator", share.Validator.String(), "error", err)
+				continue
+			}
 		}
+		k.recordValidatorPayout(ctx, share.Validator, info, month, reward.Amount, withheld)
+		totalWithheld = totalWithheld.Add(withheld)
 
 		k.Logger(ctx).Info("Distributed reward to active validator",
-			"validator", validator.OperatorAddress,
+			"validator", share.Validator.String(),
+			"tokens", share.Tokens.String(),
 			"amount", reward.String(),
 		)
 	}
 
-	return nil
+	return totalWithheld, nil
 }
 
 // isValidatorActive checks if validator is active (not inactive >10 days in current month)
@@ -454,8 +879,10 @@ func (k Keeper) isValidatorActive(ctx sdk.Context, valAddr sdk.ValAddress) bool
 		// New month, reset counters
 		uptime.CurrentMonth = currentMonth
 		uptime.InactiveDays = 0
+		uptime.DeclaredInactiveDays = 0
 		uptime.LastCheck = ctx.BlockTime().Unix()
 		k.SetValidatorUptime(ctx, valAddr, uptime)
+		k.pruneDowntimeDeclarations(ctx, valAddr, currentMonth)
 		return true
 	}
 
@@ -466,18 +893,29 @@ func (k Keeper) isValidatorActive(ctx sdk.Context, valAddr sdk.ValAddress) bool
 		return false
 	}
 
-	// Update inactive days if validator is not bonded
-	if validator.Status != stakingtypes.Bonded {
+	return k.accrueInactivity(ctx, valAddr, uptime, validator.Status == stakingtypes.Bonded)
+}
+
+// accrueInactivity adds an inactive day if the validator is not bonded and a
+// day has passed since the last check, and reports whether the validator is
+// still within the monthly threshold. Days inside a declared downtime window
+// are counted separately and weighted by DeclaredDowntimeWeight.
+func (k Keeper) accrueInactivity(ctx sdk.Context, valAddr sdk.ValAddress, uptime types.ValidatorUptime, bonded bool) bool {
+	if !bonded {
 		lastCheck := time.Unix(uptime.LastCheck, 0)
 		if ctx.BlockTime().Sub(lastCheck) >= 24*time.Hour {
-			uptime.InactiveDays++
+			if k.isInDeclaredDowntime(ctx, valAddr, ctx.BlockTime().Unix()) {
+				uptime.DeclaredInactiveDays++
+			} else {
+				uptime.InactiveDays++
+			}
 			uptime.LastCheck = ctx.BlockTime().Unix()
 			k.SetValidatorUptime(ctx, valAddr, uptime)
 		}
 	}
 
-	// Validator is active if inactive days <= 10
-	return uptime.InactiveDays <= ValidatorInactiveThreshold
+	// Validator is active if effective inactive days <= 10
+	return k.EffectiveInactiveDays(ctx, uptime).LTE(sdk.NewDec(ValidatorInactiveThreshold))
 }
 
 // getCurrentMonth returns current month identifier
@@ -485,6 +923,22 @@ func (k Keeper) getCurrentMonth(ctx sdk.Context) uint64 {
 	return uint64(ctx.BlockTime().Unix() / int64(MonthDuration.Seconds()))
 }
 
+// getCycleMonth returns the month number within the current cycle, starting at 1
+func (k Keeper) getCycleMonth(ctx sdk.Context, info types.HalvingInfo) uint64 {
+	elapsed := ctx.BlockTime().Sub(time.Unix(info.CycleStartTime, 0))
+	if elapsed < 0 {
+		return 1
+	}
+	return uint64(elapsed/MonthDuration) + 1
+}
+
+// isDEXWindowOpen reports whether the DEX share is still paid, that is less
+// than the DexDistributionPeriod param has passed since the distribution start
+func (k Keeper) isDEXWindowOpen(ctx sdk.Context, info types.HalvingInfo) bool {
+	distributionStart := time.Unix(info.DistributionStart, 0)
+	return ctx.BlockTime().Sub(distributionStart) < k.GetParams(ctx).DexDistributionPeriod
+}
+
 // distributeToDelegators distributes rewards to delegators via fee pool
 func (k Keeper) distributeToDelegators(ctx sdk.Context, amount sdk.Coin) error {
 	// Send to fee collector for distribution to delegators
@@ -501,21 +955,39 @@ func (k Keeper) distributeToDelegators(ctx sdk.Context, amount sdk.Coin) error {
 	return nil
 }
 
-// distributeToDEX distributes rewards to DEX pools (only years 1-2)
+// distributeToDEX distributes rewards to DEX pools (only inside the DEX window)
 func (k Keeper) distributeToDEX(ctx sdk.Context, amount sdk.Coin, info types.HalvingInfo) error {
-	// Check if we're in year 1 or 2 of distribution
-	distributionStart := time.Unix(info.DistributionStart, 0)
-	elapsed := ctx.BlockTime().Sub(distributionStart)
-	
-	// Only distribute to DEX in first 2 years
-	if elapsed >= DEXDistributionPeriod {
-		k.Logger(ctx).Info("DEX distribution period ended (after 2 years)", "cycle", info.CurrentCycle)
+	// Only distribute to DEX while the DEX window is open
+	if !k.isDEXWindowOpen(ctx, info) {
+		k.Logger(ctx).Info("DEX distribution period ended", "cycle", info.CurrentCycle)
 		return nil
 	}
 
-	// Keep DEX allocation in module account for bot to handle
+	// Optionally distribute on-chain to the feerouter LP pools
+	if k.GetParams(ctx).DexShareToLPPools && k.lpPoolDistributor != nil {
+		distributed := k.lpPoolDistributor.DistributeToLPPools(ctx, types.ModuleName, sdk.NewCoins(amount))
+		k.Logger(ctx).Info("DEX rewards distributed to LP pools",
+			"amount", distributed.String(),
+			"cycle", info.CurrentCycle,
+		)
+
+		// Whatever could not be distributed is held for the bot
+		remaining, _ := sdk.NewCoins(amount).SafeSub(distributed...)
+		if remaining.IsZero() {
+			return nil
+		}
+		amount = sdk.NewCoin(MainDenom, remaining.AmountOf(MainDenom))
+	}
+
+	// Hold the DEX allocation in its own account until it is withdrawn
+	if err := k.bankKeeper.SendCoinsFromModuleToModule(ctx, types.ModuleName, types.DexDistributionAccountName, sdk.NewCoins(amount)); err != nil {
+		return fmt.Errorf("failed to fund %s account: %w", types.DexDistributionAccountName, err)
+	}
+	pending := k.addPendingDexAllocation(ctx, info.CurrentCycle, amount)
 	k.Logger(ctx).Info("DEX rewards allocated for bot distribution", 
 		"amount", amount.String(),
+		"pending", pending.Amount.String(),
+		"account_balance", k.GetDEXDistributionBalance(ctx).String(),
 		"cycle", info.CurrentCycle,
 		"elapsed_days", int(elapsed.Hours()/24),
 	)
@@ -542,8 +1014,7 @@ func (k Keeper) GetAllValidatorUptimes(ctx sdk.Context) []types.ValidatorUptime
 // GetDistributionRecord gets a specific distribution record
 func (k Keeper) GetDistributionRecord(ctx sdk.Context, timestamp int64) (types.DistributionRecord, bool) {
 	store := ctx.KVStore(k.storeKey)
-	key := append(types.LastDistributionKey, sdk.Uint64ToBigEndian(uint64(timestamp))...)
-	bz := store.Get(key)
+	bz := store.Get(types.GetDistributionRecordKey(timestamp))
 	if bz == nil {
 		return types.DistributionRecord{}, false
 	}
@@ -556,15 +1027,64 @@ func (k Keeper) GetDistributionRecord(ctx sdk.Context, timestamp int64) (types.D
 // SetDistributionRecord sets a distribution record
 func (k Keeper) SetDistributionRecord(ctx sdk.Context, record types.DistributionRecord) {
 	store := ctx.KVStore(k.storeKey)
-	key := append(types.LastDistributionKey, sdk.Uint64ToBigEndian(uint64(record.Timestamp))...)
 	bz := k.cdc.MustMarshal(&record)
-	store.Set(key, bz)
+	store.Set(types.GetDistributionRecordKey(record.Timestamp), bz)
+}
+
+// GetPendingDexAllocation returns the DEX share of a cycle awaiting bot
+// distribution. A cycle without allocations returns zero amounts.
+func (k Keeper) GetPendingDexAllocation(ctx sdk.Context, cycle uint64) types.PendingDexAllocation {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetPendingDexAllocationKey(cycle))
+	if bz == nil {
+		return types.PendingDexAllocation{
+			Cycle:          cycle,
+			Amount:         sdk.NewCoin(MainDenom, sdk.ZeroInt()),
+			TotalAllocated: sdk.NewCoin(MainDenom, sdk.ZeroInt()),
+		}
+	}
+
+	var pending types.PendingDexAllocation
+	k.cdc.MustUnmarshal(bz, &pending)
+	return pending
+}
+
+// SetPendingDexAllocation stores the pending DEX allocation of a cycle
+func (k Keeper) SetPendingDexAllocation(ctx sdk.Context, pending types.PendingDexAllocation) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&pending)
+	store.Set(types.GetPendingDexAllocationKey(pending.Cycle), bz)
+}
+
+// addPendingDexAllocation adds one monthly DEX share to the cycle accumulator
+func (k Keeper) addPendingDexAllocation(ctx sdk.Context, cycle uint64, amount sdk.Coin) types.PendingDexAllocation {
+	pending := k.GetPendingDexAllocation(ctx, cycle)
+	pending.Amount = pending.Amount.Add(amount)
+	pending.TotalAllocated = pending.TotalAllocated.Add(amount)
+	pending.Months++
+	pending.LastUpdated = ctx.BlockTime().Unix()
+	k.SetPendingDexAllocation(ctx, pending)
+	return pending
+}
+
+// ReleasePendingDexAllocation marks amount of a cycle's pending DEX share as
+// distributed by the bot
+func (k Keeper) ReleasePendingDexAllocation(ctx sdk.Context, cycle uint64, amount sdk.Coin) error {
+	pending := k.GetPendingDexAllocation(ctx, cycle)
+	if amount.Denom != pending.Amount.Denom || amount.Amount.GT(pending.Amount.Amount) {
+		return fmt.Errorf("cannot release %s: only %s pending for cycle %d", amount, pending.Amount, cycle)
+	}
+
+	pending.Amount = pending.Amount.Sub(amount)
+	pending.LastUpdated = ctx.BlockTime().Unix()
+	k.SetPendingDexAllocation(ctx, pending)
+	return nil
 }
 
 // GetAllDistributionRecords gets all distribution records
 func (k Keeper) GetAllDistributionRecords(ctx sdk.Context) []types.DistributionRecord {
 	store := ctx.KVStore(k.storeKey)
-	iterator := sdk.KVStorePrefixIterator(store, types.LastDistributionKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.DistributionRecordKey)
 	defer iterator.Close()
 
 	var records []types.DistributionRecord
@@ -577,13 +1097,6 @@ func (k Keeper) GetAllDistributionRecords(ctx sdk.Context) []types.DistributionR
 	return records
 }
 
-// Helper function to check if validator bot is running (for slashing)
-func (k Keeper) IsValidatorBotRunning(ctx sdk.Context, valAddr sdk.ValAddress) bool {
-	// This would be implemented with actual bot monitoring logic
-	// For now, return true to avoid slashing during development
-	return true
-}
-
 // SlashInactiveValidators slashes validators without running bots
 func (k Keeper) SlashInactiveValidators(ctx sdk.Context) error {
 	validators := k.stakingKeeper.GetBondedValidatorsByPower(ctx)
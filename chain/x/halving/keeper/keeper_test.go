@@ -0,0 +1,361 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/store"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	bankkeeper "github.com/cosmos/cosmos-sdk/x/bank/keeper"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// genesisTime is the block time used by keeper tests
+var genesisTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testAuthority may update the distribution shares in keeper tests
+var testAuthority = sdk.AccAddress([]byte("halving-authority")).String()
+
+// setupKeeper creates a halving keeper backed by an in-memory store. Only the
+// module store and params are available; bank and staking are not wired.
+func setupKeeper(t testing.TB) (keeper.Keeper, sdk.Context) {
+	return setupKeeperWithBank(t, nil)
+}
+
+// setupKeeperWithBank is setupKeeper with the given bank keeper wired
+func setupKeeperWithBank(t testing.TB, bankKeeper bankkeeper.Keeper) (keeper.Keeper, sdk.Context) {
+	storeKey := sdk.NewKVStoreKey(types.StoreKey)
+	paramsKey := sdk.NewKVStoreKey(paramstypes.StoreKey)
+	paramsTKey := sdk.NewTransientStoreKey(paramstypes.TStoreKey)
+
+	db := dbm.NewMemDB()
+	stateStore := store.NewCommitMultiStore(db)
+	stateStore.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(paramsKey, storetypes.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(paramsTKey, storetypes.StoreTypeTransient, nil)
+	require.NoError(t, stateStore.LoadLatestVersion())
+
+	registry := codectypes.NewInterfaceRegistry()
+	cdc := codec.NewProtoCodec(registry)
+	amino := codec.NewLegacyAmino()
+
+	subspace := paramstypes.NewSubspace(cdc, amino, paramsKey, paramsTKey, types.ModuleName)
+	k := keeper.NewKeeper(cdc, storeKey, subspace, authkeeper.AccountKeeper{}, bankKeeper, nil, testAuthority)
+
+	ctx := sdk.NewContext(stateStore, tmproto.Header{Time: genesisTime}, false, log.NewNopLogger())
+	k.SetParams(ctx, types.DefaultParams())
+
+	return k, ctx
+}
+
+// supplyBankKeeper reports a fixed total supply
+type supplyBankKeeper struct {
+	bankkeeper.Keeper
+	supply sdk.Int
+}
+
+func (b supplyBankKeeper) GetSupply(_ sdk.Context, denom string) sdk.Coin {
+	return sdk.NewCoin(denom, b.supply)
+}
+
+// moduleBankKeeper holds the balances of module accounts by name
+type moduleBankKeeper struct {
+	bankkeeper.Keeper
+	balances map[string]sdk.Coins
+}
+
+func newModuleBankKeeper(halvingBalance int64) *moduleBankKeeper {
+	return &moduleBankKeeper{balances: map[string]sdk.Coins{
+		types.ModuleName: sdk.NewCoins(sdk.NewInt64Coin(keeper.MainDenom, halvingBalance)),
+	}}
+}
+
+func (b *moduleBankKeeper) SendCoinsFromModuleToModule(_ sdk.Context, sender, recipient string, amount sdk.Coins) error {
+	balance, negative := b.balances[sender].SafeSub(amount...)
+	if negative {
+		return sdkerrors.ErrInsufficientFunds
+	}
+	b.balances[sender] = balance
+	b.balances[recipient] = b.balances[recipient].Add(amount...)
+	return nil
+}
+
+func (b *moduleBankKeeper) GetBalance(_ sdk.Context, address sdk.AccAddress, denom string) sdk.Coin {
+	for name, balance := range b.balances {
+		if authtypes.NewModuleAddress(name).Equals(address) {
+			return sdk.NewCoin(denom, balance.AmountOf(denom))
+		}
+	}
+	return sdk.NewCoin(denom, sdk.ZeroInt())
+}
+
+func TestSupplySnapshotsRecordedDailyAndPruned(t *testing.T) {
+	k, ctx := setupKeeper(t)
+
+	require.True(t, k.SupplySnapshotDue(ctx))
+
+	supply := int64(1_000_000_000)
+	for day := 0; day < keeper.MaxSupplySnapshots+10; day++ {
+		blockTime := genesisTime.Add(time.Duration(day) * keeper.SupplySnapshotInterval)
+		ctx = ctx.WithBlockTime(blockTime).WithBlockHeight(int64(day + 1))
+		require.True(t, k.SupplySnapshotDue(ctx))
+
+		k.RecordSupplySnapshot(ctx, sdk.NewInt64Coin(keeper.MainDenom, supply))
+		supply -= 1000
+
+		// A later block on the same day does not need another sample
+		require.False(t, k.SupplySnapshotDue(ctx.WithBlockTime(blockTime.Add(time.Hour))))
+	}
+
+	snapshots := k.GetSupplySnapshots(ctx)
+	require.Len(t, snapshots, keeper.MaxSupplySnapshots)
+
+	// The oldest samples were pruned and order is chronological
+	require.Equal(t, genesisTime.Add(10*keeper.SupplySnapshotInterval).Unix(), snapshots[0].Timestamp)
+	for i := 1; i < len(snapshots); i++ {
+		require.Greater(t, snapshots[i].Timestamp, snapshots[i-1].Timestamp)
+	}
+}
+
+// mockLPPoolDistributor records LP-pool distributions and sends up to limit
+type mockLPPoolDistributor struct {
+	calls      int
+	fromModule string
+	requested  sdk.Coins
+	limit      sdk.Int
+}
+
+func (m *mockLPPoolDistributor) DistributeToLPPools(_ sdk.Context, fromModule string, amount sdk.Coins) sdk.Coins {
+	m.calls++
+	m.fromModule = fromModule
+	m.requested = amount
+
+	sent := sdk.NewCoins()
+	for _, coin := range amount {
+		sent = sent.Add(sdk.NewCoin(coin.Denom, sdk.MinInt(coin.Amount, m.limit)))
+	}
+	return sent
+}
+
+func TestDexShareRoutedToLPPools(t *testing.T) {
+	bank := newModuleBankKeeper(1_000_000)
+	k, ctx := setupKeeperWithBank(t, bank)
+	distributor := &mockLPPoolDistributor{limit: sdk.NewInt(1_000_000)}
+	k.SetLPPoolDistributor(distributor)
+
+	info := types.HalvingInfo{CurrentCycle: 1, DistributionStart: genesisTime.Unix()}
+	ctx = ctx.WithBlockTime(genesisTime.Add(keeper.MonthDuration))
+	amount := sdk.NewInt64Coin(keeper.MainDenom, 500_000)
+
+	// Disabled by default: the DEX share is held for the bot
+	require.NoError(t, k.DistributeToDEX(ctx, amount, info))
+	require.Zero(t, distributor.calls)
+	require.Equal(t, amount, k.GetDEXDistributionBalance(ctx))
+
+	params := k.GetParams(ctx)
+	params.DexShareToLPPools = true
+	k.SetParams(ctx, params)
+
+	require.NoError(t, k.DistributeToDEX(ctx, amount, info))
+	require.Equal(t, 1, distributor.calls)
+	require.Equal(t, types.ModuleName, distributor.fromModule)
+	require.Equal(t, sdk.NewCoins(amount), distributor.requested)
+
+	// A partial distribution is not an error; the remainder is held for the bot
+	distributor.limit = sdk.NewInt(100_000)
+	require.NoError(t, k.DistributeToDEX(ctx, amount, info))
+	require.Equal(t, 2, distributor.calls)
+	require.Equal(t, int64(900_000), k.GetDEXDistributionBalance(ctx).Amount.Int64())
+
+	// No DEX share after the two-year DEX distribution period
+	ctx = ctx.WithBlockTime(genesisTime.Add(types.DefaultDexDistributionPeriod))
+	require.NoError(t, k.DistributeToDEX(ctx, amount, info))
+	require.Equal(t, 2, distributor.calls)
+}
+
+func TestPostDexShareDestination(t *testing.T) {
+	total := sdk.NewInt64Coin(keeper.MainDenom, 1_000_000)
+	info := types.HalvingInfo{CurrentCycle: 1, CycleStartTime: genesisTime.Unix(), DistributionStart: genesisTime.Unix()}
+
+	insideWindow := genesisTime.Add(keeper.MonthDuration)
+	outsideWindow := genesisTime.Add(types.DefaultDexDistributionPeriod)
+
+	testCases := []struct {
+		destination   string
+		expValidators int64
+		expDelegators int64
+	}{
+		{types.PostDexShareToValidators, 800_000, 200_000},
+		{types.PostDexShareToDelegators, 700_000, 300_000},
+		{types.PostDexShareToCommunityPool, 700_000, 200_000},
+		{types.PostDexShareBurn, 700_000, 200_000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.destination, func(t *testing.T) {
+			k, ctx := setupKeeper(t)
+			params := k.GetParams(ctx)
+			params.PostDexShareDestination = tc.destination
+			k.SetParams(ctx, params)
+
+			// Inside the DEX window the destination is not consulted
+			split := k.SplitRewards(ctx.WithBlockTime(insideWindow), total, info)
+			require.Equal(t, sdk.NewInt(700_000), split.Validators)
+			require.Equal(t, sdk.NewInt(200_000), split.Delegators)
+			require.Equal(t, sdk.NewInt(100_000), split.Dex)
+			require.True(t, split.Redirected.IsZero())
+			require.Empty(t, split.RedirectDestination)
+
+			// Afterwards the DEX share is redirected instead of dropped
+			split = k.SplitRewards(ctx.WithBlockTime(outsideWindow), total, info)
+			require.True(t, split.Dex.IsZero())
+			require.Equal(t, sdk.NewInt(100_000), split.Redirected)
+			require.Equal(t, tc.destination, split.RedirectDestination)
+			require.Equal(t, sdk.NewInt(tc.expValidators), split.Validators)
+			require.Equal(t, sdk.NewInt(tc.expDelegators), split.Delegators)
+
+			// Nothing is lost: folded shares plus any separately routed amount add up
+			routed := split.Validators.Add(split.Delegators)
+			if tc.destination == types.PostDexShareToCommunityPool || tc.destination == types.PostDexShareBurn {
+				routed = routed.Add(split.Redirected)
+			}
+			require.Equal(t, total.Amount, routed)
+		})
+	}
+}
+
+func TestSplitRewardsRounding(t *testing.T) {
+	total := sdk.NewInt64Coin(keeper.MainDenom, 1_000_003)
+	info := types.HalvingInfo{CurrentCycle: 1, CycleStartTime: genesisTime.Unix(), DistributionStart: genesisTime.Unix()}
+
+	testCases := []struct {
+		name           string
+		strategy       string
+		remainderToPos bool
+		exp            [3]int64 // validators, dex, delegators
+	}{
+		{"truncate leaves dust", "truncate", false, [3]int64{700_002, 100_000, 200_000}},
+		{"truncate remainder to pos", "truncate", true, [3]int64{700_002, 100_000, 200_001}},
+		{"round", "round", false, [3]int64{700_002, 100_000, 200_001}},
+		{"bankers", "bankers", false, [3]int64{700_002, 100_000, 200_001}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			k, ctx := setupKeeper(t)
+			params := k.GetParams(ctx)
+			params.RoundingStrategy = tc.strategy
+			params.RemainderToPos = tc.remainderToPos
+			k.SetParams(ctx, params)
+
+			split := k.SplitRewards(ctx.WithBlockTime(genesisTime.Add(keeper.MonthDuration)), total, info)
+			require.Equal(t, tc.exp[0], split.Validators.Int64())
+			require.Equal(t, tc.exp[1], split.Dex.Int64())
+			require.Equal(t, tc.exp[2], split.Delegators.Int64())
+		})
+	}
+}
+
+func TestPendingDexAllocationAccumulatesPerMonth(t *testing.T) {
+	bank := newModuleBankKeeper(10_000_000)
+	k, ctx := setupKeeperWithBank(t, bank)
+	info := types.HalvingInfo{CurrentCycle: 1, CycleStartTime: genesisTime.Unix(), DistributionStart: genesisTime.Unix()}
+	k.SetHalvingInfo(ctx, info)
+	amount := sdk.NewInt64Coin(keeper.MainDenom, 500_000)
+
+	pending := k.GetPendingDexAllocation(ctx, 1)
+	require.True(t, pending.Amount.IsZero())
+	require.Zero(t, pending.Months)
+
+	for month := 1; month <= 3; month++ {
+		ctx = ctx.WithBlockTime(genesisTime.Add(time.Duration(month) * keeper.MonthDuration))
+		require.NoError(t, k.DistributeToDEX(ctx, amount, info))
+
+		pending = k.GetPendingDexAllocation(ctx, 1)
+		require.Equal(t, uint64(month), pending.Months)
+		require.Equal(t, amount.Amount.MulRaw(int64(month)), pending.Amount.Amount)
+		require.Equal(t, pending.Amount, pending.TotalAllocated)
+		require.Equal(t, ctx.BlockTime().Unix(), pending.LastUpdated)
+
+		// The share moved out of the halving module account
+		require.Equal(t, pending.Amount, k.GetDEXDistributionBalance(ctx))
+		require.Equal(t, int64(10_000_000)-pending.Amount.Amount.Int64(), bank.balances[types.ModuleName].AmountOf(keeper.MainDenom).Int64())
+	}
+
+	// Other cycles are tracked separately
+	require.True(t, k.GetPendingDexAllocation(ctx, 2).Amount.IsZero())
+
+	// Nothing accrues once the DEX window has closed
+	ctx = ctx.WithBlockTime(genesisTime.Add(types.DefaultDexDistributionPeriod))
+	require.NoError(t, k.DistributeToDEX(ctx, amount, info))
+	require.Equal(t, uint64(3), k.GetPendingDexAllocation(ctx, 1).Months)
+
+	// Releasing reduces the pending amount but not the cycle total
+	require.NoError(t, k.ReleasePendingDexAllocation(ctx, 1, sdk.NewInt64Coin(keeper.MainDenom, 200_000)))
+	pending = k.GetPendingDexAllocation(ctx, 1)
+	require.Equal(t, sdk.NewInt(1_300_000), pending.Amount.Amount)
+	require.Equal(t, sdk.NewInt(1_500_000), pending.TotalAllocated.Amount)
+	require.Error(t, k.ReleasePendingDexAllocation(ctx, 1, sdk.NewInt64Coin(keeper.MainDenom, 2_000_000)))
+
+	// The query defaults to the current cycle
+	res, err := k.PendingDexAllocation(sdk.WrapSDKContext(ctx), &types.QueryPendingDexAllocationRequest{})
+	require.NoError(t, err)
+	require.Equal(t, pending, res.PendingDexAllocation)
+}
+
+func TestMonthlyDistributionCeiling(t *testing.T) {
+	k, ctx := setupKeeperWithBank(t, supplyBankKeeper{supply: sdk.NewInt(100_000)})
+
+	// 2,000 ugen a month; 1% of the supply is 1,000 ugen
+	info := types.HalvingInfo{
+		CurrentCycle:      1,
+		HalvingFund:       sdk.NewInt64Coin(keeper.MainDenom, 48_000),
+		DistributedAmount: sdk.NewInt64Coin(keeper.MainDenom, 0),
+	}
+	params := types.DefaultParams()
+	params.MaxMonthlyDistribution = sdk.NewInt(1_500)
+	params.MaxMonthlyDistributionSupplyRatio = sdk.MustNewDecFromStr("0.01")
+	k.SetParams(ctx, params)
+
+	// The lower limit applies
+	ceiling, capped := k.MonthlyDistributionCeiling(ctx)
+	require.True(t, capped)
+	require.Equal(t, sdk.NewInt(1_000), ceiling)
+
+	amount, clamped := k.NextMonthlyDistribution(ctx, info)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 1_000), amount)
+	require.Equal(t, sdk.NewInt(1_000), clamped)
+
+	// The clamped part stays in the fund and is due on top of the next month
+	info.HalvingFund = info.HalvingFund.Sub(amount)
+	info.DistributedAmount = info.DistributedAmount.Add(amount)
+	info.ClampedAmount = sdk.NewCoin(keeper.MainDenom, clamped)
+
+	params.MaxMonthlyDistributionSupplyRatio = sdk.ZeroDec()
+	k.SetParams(ctx, params)
+	amount, clamped = k.NextMonthlyDistribution(ctx, info)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 1_500), amount)
+	require.Equal(t, sdk.NewInt(1_500), clamped)
+
+	// Governance removes the ceiling: the whole amount due is paid
+	params.MaxMonthlyDistribution = sdk.ZeroInt()
+	k.SetParams(ctx, params)
+	_, capped = k.MonthlyDistributionCeiling(ctx)
+	require.False(t, capped)
+	amount, clamped = k.NextMonthlyDistribution(ctx, info)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 3_000), amount)
+	require.True(t, clamped.IsZero())
+}
@@ -0,0 +1,205 @@
+package keeper
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// Migrator migrates the halving store between consensus versions
+type Migrator struct {
+	keeper Keeper
+}
+
+// NewMigrator returns a Migrator for the keeper
+func NewMigrator(keeper Keeper) Migrator {
+	return Migrator{keeper: keeper}
+}
+
+// Migrations returns the in-place store migrations by the consensus version
+// they migrate from. A ConsensusVersion bump adds the migration from the
+// previous version here, a no-op one if the store shape did not change.
+func (m Migrator) Migrations() map[uint64]module.MigrationHandler {
+	return map[uint64]module.MigrationHandler{
+		1: m.Migrate1to2,
+		2: m.Migrate2to3,
+		3: m.Migrate3to4,
+		4: m.Migrate4to5,
+		5: m.Migrate5to6,
+		6: m.Migrate6to7,
+		7: m.Migrate7to8,
+	}
+}
+
+// RegisterMigrations registers the store migrations with the configurator.
+// It fails when a consensus version below types.ConsensusVersion has no
+// migration, which would otherwise only surface as a halt at the upgrade.
+func RegisterMigrations(cfg module.Configurator, k Keeper) error {
+	return registerMigrations(cfg, NewMigrator(k).Migrations(), types.ConsensusVersion)
+}
+
+// registerMigrations registers migrations, which must take the store from
+// version 1 to consensusVersion
+func registerMigrations(cfg module.Configurator, migrations map[uint64]module.MigrationHandler, consensusVersion uint64) error {
+	for from := uint64(1); from < consensusVersion; from++ {
+		if migrations[from] == nil {
+			return fmt.Errorf("x/%s consensus version %d has no migration from version %d", types.ModuleName, consensusVersion, from)
+		}
+	}
+
+	versions := make([]uint64, 0, len(migrations))
+	for from := range migrations {
+		if from == 0 || from >= consensusVersion {
+			return fmt.Errorf("x/%s migration from version %d is beyond consensus version %d", types.ModuleName, from, consensusVersion)
+		}
+		versions = append(versions, from)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	for _, from := range versions {
+		if err := cfg.RegisterMigration(types.ModuleName, from, migrations[from]); err != nil {
+			return fmt.Errorf("failed to register x/%s migration %d to %d: %w", types.ModuleName, from, from+1, err)
+		}
+	}
+	return nil
+}
+
+// Migrate1to2 copies the params from the legacy x/params subspace to the
+// module store, the defaults if the subspace was never initialized. The
+// legacy copy is kept and kept in sync until a later upgrade deletes it.
+// Params that do not validate fail the upgrade rather than the first block
+// after it.
+func (m Migrator) Migrate1to2(ctx sdk.Context) error {
+	params := m.keeper.legacyParams(ctx)
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("invalid legacy %s params: %w", types.ModuleName, err)
+	}
+
+	m.keeper.setStoreParams(ctx, params)
+	m.keeper.Logger(ctx).Info("Migrated params to the module store", "legacy_copy", m.keeper.hasLegacyParams(ctx))
+	return nil
+}
+
+// Migrate2to3 sets the new bot_heartbeat_timeout_blocks param to its default
+// and starts bot liveness tracking at the upgrade height. Validators that
+// have not sent a heartbeat since get the timeout from there to send one
+// before their bot counts as stopped.
+func (m Migrator) Migrate2to3(ctx sdk.Context) error {
+	params := m.keeper.GetParams(ctx)
+	if params.BotHeartbeatTimeoutBlocks == 0 {
+		params.BotHeartbeatTimeoutBlocks = types.DefaultBotHeartbeatTimeoutBlocks
+	}
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("invalid %s params: %w", types.ModuleName, err)
+	}
+	m.keeper.SetParams(ctx, params)
+
+	m.keeper.SetBotLivenessStart(ctx, ctx.BlockHeight())
+	m.keeper.Logger(ctx).Info("Started bot liveness tracking", "height", ctx.BlockHeight(), "timeout_blocks", params.BotHeartbeatTimeoutBlocks)
+	return nil
+}
+
+// Migrate3to4 sets the new monthly distribution ceiling params to their
+// defaults. Version 2 stores rewritten by Migrate2to3 hold them as zero
+// rather than unset, which no one can have chosen yet, so both are replaced.
+// The clamped amount of the halving info starts at zero, as no distribution
+// was clamped before.
+func (m Migrator) Migrate3to4(ctx sdk.Context) error {
+	params := m.keeper.GetParams(ctx)
+	if params.MaxMonthlyDistribution.IsNil() || params.MaxMonthlyDistribution.IsZero() {
+		params.MaxMonthlyDistribution = sdk.NewInt(types.DefaultMaxMonthlyDistribution)
+	}
+	if params.MaxMonthlyDistributionSupplyRatio.IsNil() || params.MaxMonthlyDistributionSupplyRatio.IsZero() {
+		params.MaxMonthlyDistributionSupplyRatio = sdk.MustNewDecFromStr(types.DefaultMaxMonthlyDistributionSupplyRatio)
+	}
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("invalid %s params: %w", types.ModuleName, err)
+	}
+	m.keeper.SetParams(ctx, params)
+
+	ceiling, capped := m.keeper.MonthlyDistributionCeiling(ctx)
+	m.keeper.Logger(ctx).Info("Set the monthly distribution ceiling", "ceiling", ceiling.String(), "capped", capped)
+	return nil
+}
+
+// Migrate4to5 moves the pending DEX share of all cycles from the halving
+// module account to the dex_distribution account, which holds it from now on.
+// Only what the module holds is moved; a shortfall is logged, as the balance
+// could not cover the pending share before either.
+func (m Migrator) Migrate4to5(ctx sdk.Context) error {
+	pending := m.keeper.GetTotalPendingDexAllocation(ctx)
+	if pending.IsZero() {
+		return nil
+	}
+	held := m.keeper.bankKeeper.GetBalance(ctx, authtypes.NewModuleAddress(types.ModuleName), MainDenom)
+
+	moved := sdk.NewCoin(MainDenom, sdk.MinInt(pending.Amount, held.Amount))
+	if moved.IsPositive() {
+		if err := m.keeper.bankKeeper.SendCoinsFromModuleToModule(ctx, types.ModuleName, types.DexDistributionAccountName, sdk.NewCoins(moved)); err != nil {
+			return fmt.Errorf("failed to move the pending DEX share to the %s account: %w", types.DexDistributionAccountName, err)
+		}
+	}
+	if moved.IsLT(pending) {
+		m.keeper.Logger(ctx).Error("Halving module account held less than the pending DEX share",
+			"pending", pending.String(),
+			"moved", moved.String(),
+		)
+	}
+
+	m.keeper.Logger(ctx).Info("Moved the pending DEX share to its own account",
+		"account", types.DexDistributionAccountName,
+		"amount", moved.String(),
+	)
+	return nil
+}
+
+// Migrate5to6 changes no state: from version 6 on the validator share is
+// divided among the eligible validators by their bonded tokens rather than
+// equally. It only logs the switch, at the upgrade block.
+func (m Migrator) Migrate5to6(ctx sdk.Context) error {
+	m.keeper.Logger(ctx).Info("Validator rewards are weighted by bonded tokens from now on", "height", ctx.BlockHeight())
+	return nil
+}
+
+// Migrate6to7 moves the last distribution time off the distribution record
+// prefix. Version 6 stored it under the prefix itself, where every iteration
+// over the records failed to decode it as a record.
+func (m Migrator) Migrate6to7(ctx sdk.Context) error {
+	store := ctx.KVStore(m.keeper.storeKey)
+	bz := store.Get(types.DistributionRecordKey)
+	if bz == nil {
+		return nil
+	}
+	if len(bz) != 8 {
+		return fmt.Errorf("invalid last distribution time of %d bytes", len(bz))
+	}
+
+	store.Set(types.LastDistributionTimeKey, bz)
+	store.Delete(types.DistributionRecordKey)
+	m.keeper.Logger(ctx).Info("Moved the last distribution time off the distribution records",
+		"last_distribution", sdk.BigEndianToUint64(bz),
+	)
+	return nil
+}
+
+// Migrate7to8 sets the new dex_distribution_period param to its default, the
+// two-year window version 7 hard-coded, so the DEX share is paid as before
+// until governance shortens the window.
+func (m Migrator) Migrate7to8(ctx sdk.Context) error {
+	params := m.keeper.GetParams(ctx)
+	if params.DexDistributionPeriod == 0 {
+		params.DexDistributionPeriod = types.DefaultDexDistributionPeriod
+	}
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("invalid %s params: %w", types.ModuleName, err)
+	}
+	m.keeper.SetParams(ctx, params)
+
+	m.keeper.Logger(ctx).Info("Set the DEX distribution period", "period", params.DexDistributionPeriod.String())
+	return nil
+}
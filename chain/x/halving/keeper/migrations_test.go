@@ -0,0 +1,316 @@
+package keeper_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/store"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// setupParamsMigration returns a keeper whose params are in neither store
+// yet, and its legacy subspace
+func setupParamsMigration(t *testing.T) (keeper.Keeper, sdk.Context, paramstypes.Subspace) {
+	storeKey := sdk.NewKVStoreKey(types.StoreKey)
+	paramsKey := sdk.NewKVStoreKey(paramstypes.StoreKey)
+	paramsTKey := sdk.NewTransientStoreKey(paramstypes.TStoreKey)
+
+	db := dbm.NewMemDB()
+	stateStore := store.NewCommitMultiStore(db)
+	stateStore.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(paramsKey, storetypes.StoreTypeIAVL, db)
+	stateStore.MountStoreWithDB(paramsTKey, storetypes.StoreTypeTransient, nil)
+	require.NoError(t, stateStore.LoadLatestVersion())
+
+	cdc := codec.NewProtoCodec(codectypes.NewInterfaceRegistry())
+	subspace := paramstypes.NewSubspace(cdc, codec.NewLegacyAmino(), paramsKey, paramsTKey, types.ModuleName).
+		WithKeyTable(types.ParamKeyTable())
+	k := keeper.NewKeeper(cdc, storeKey, subspace, authkeeper.AccountKeeper{}, nil, nil, testAuthority)
+
+	ctx := sdk.NewContext(stateStore, tmproto.Header{Time: genesisTime}, false, log.NewNopLogger())
+	return k, ctx, subspace
+}
+
+// recordingConfigurator records the migrations registered with it
+type recordingConfigurator struct {
+	module.Configurator
+	migrations map[uint64]module.MigrationHandler
+}
+
+func newRecordingConfigurator() *recordingConfigurator {
+	return &recordingConfigurator{migrations: make(map[uint64]module.MigrationHandler)}
+}
+
+func (c *recordingConfigurator) RegisterMigration(moduleName string, fromVersion uint64, handler module.MigrationHandler) error {
+	if moduleName != types.ModuleName {
+		return fmt.Errorf("migration registered for %s", moduleName)
+	}
+	c.migrations[fromVersion] = handler
+	return nil
+}
+
+// requireParamsConsistent asserts the params consistency invariant holds
+func requireParamsConsistent(t *testing.T, k keeper.Keeper, ctx sdk.Context) {
+	t.Helper()
+	msg, broken := keeper.ParamsConsistencyInvariant(k)(ctx)
+	require.False(t, broken, msg)
+}
+
+func TestParamsMigrationFreshChain(t *testing.T) {
+	k, ctx, subspace := setupParamsMigration(t)
+
+	// A chain started at consensus version 2 never writes the subspace
+	params := types.DefaultParams()
+	params.BotProtocolVersion = 3
+	k.SetParams(ctx, params)
+
+	require.Equal(t, params, k.GetParams(ctx))
+	require.False(t, subspace.Has(ctx, types.KeyBotProtocolVersion))
+	requireParamsConsistent(t, k, ctx)
+}
+
+func TestParamsMigrationMigratedChain(t *testing.T) {
+	k, ctx, subspace := setupParamsMigration(t)
+
+	legacy := types.DefaultParams()
+	legacy.BotProtocolVersion = 4
+	legacy.MinBotProtocolVersion = 2
+	subspace.SetParamSet(ctx, &legacy)
+
+	// A block before the migration ran reads the subspace and writes through
+	require.Equal(t, legacy, k.GetParams(ctx))
+	requireParamsConsistent(t, k, ctx)
+
+	// The migration copies the same params again
+	require.NoError(t, keeper.NewMigrator(k).Migrate1to2(ctx))
+	require.Equal(t, legacy, k.GetParams(ctx))
+	requireParamsConsistent(t, k, ctx)
+
+	// Governance updates keep both copies in sync
+	updated := legacy
+	updated.BotProtocolVersion = 5
+	k.SetParams(ctx, updated)
+	require.Equal(t, updated, k.GetParams(ctx))
+	var version uint64
+	subspace.Get(ctx, types.KeyBotProtocolVersion, &version)
+	require.Equal(t, uint64(5), version)
+	requireParamsConsistent(t, k, ctx)
+
+	// A write that bypasses the keeper breaks the invariant
+	subspace.Set(ctx, types.KeyBotProtocolVersion, uint64(6))
+	_, broken := keeper.ParamsConsistencyInvariant(k)(ctx)
+	require.True(t, broken)
+}
+
+func TestParamsMigrationPartialSubspace(t *testing.T) {
+	k, ctx, subspace := setupParamsMigration(t)
+
+	// A chain that predates a param only holds the older ones
+	subspace.Set(ctx, types.KeyBotProtocolVersion, uint64(4))
+
+	require.NoError(t, keeper.NewMigrator(k).Migrate1to2(ctx))
+	params := k.GetParams(ctx)
+	require.Equal(t, uint64(4), params.BotProtocolVersion)
+	require.Equal(t, types.DefaultParams().MaxRecordsPrunedPerBlock, params.MaxRecordsPrunedPerBlock)
+}
+
+func TestParamsMigrationUninitializedSubspace(t *testing.T) {
+	k, ctx, subspace := setupParamsMigration(t)
+
+	// Nothing to copy: the defaults are stored and the subspace stays empty
+	require.NoError(t, keeper.NewMigrator(k).Migrate1to2(ctx))
+	require.Equal(t, types.DefaultParams(), k.GetParams(ctx))
+	require.False(t, subspace.Has(ctx, types.KeyBotProtocolVersion))
+	requireParamsConsistent(t, k, ctx)
+
+	// Reads without a migration do not panic either
+	k2, ctx2, _ := setupParamsMigration(t)
+	require.Equal(t, types.DefaultParams(), k2.GetParams(ctx2))
+}
+
+func TestParamsMigrationRejectsInvalidLegacyParams(t *testing.T) {
+	k, ctx, subspace := setupParamsMigration(t)
+
+	// The subspace validates each param, but not the params together
+	legacy := types.DefaultParams()
+	subspace.SetParamSet(ctx, &legacy)
+	subspace.Set(ctx, types.KeyValidatorShare, sdk.MustNewDecFromStr("0.9"))
+
+	require.Error(t, keeper.NewMigrator(k).Migrate1to2(ctx))
+}
+
+func TestEveryConsensusVersionHasMigration(t *testing.T) {
+	k, _, _ := setupParamsMigration(t)
+
+	// Every version a chain can upgrade from is registered, nothing beyond
+	cfg := newRecordingConfigurator()
+	require.NoError(t, keeper.RegisterMigrations(cfg, k))
+	require.Len(t, cfg.migrations, int(types.ConsensusVersion-1))
+	for from := uint64(1); from < types.ConsensusVersion; from++ {
+		require.NotNil(t, cfg.migrations[from], "no migration from version %d", from)
+	}
+
+	noop := func(sdk.Context) error { return nil }
+
+	// A version bump without its migration is rejected
+	err := keeper.RegisterMigrationsUpTo(newRecordingConfigurator(), map[uint64]module.MigrationHandler{1: noop}, 3)
+	require.ErrorContains(t, err, "no migration from version 2")
+
+	// So is a migration to a version the module does not declare
+	err = keeper.RegisterMigrationsUpTo(newRecordingConfigurator(), map[uint64]module.MigrationHandler{1: noop, 2: noop}, 2)
+	require.ErrorContains(t, err, "beyond consensus version 2")
+}
+
+func TestMigrationsFromVersion1(t *testing.T) {
+	k, ctx, subspace := setupParamsMigration(t)
+
+	// A version 1 store, params in the subspace only
+	legacy := types.DefaultParams()
+	legacy.BotProtocolVersion = 4
+	subspace.SetParamSet(ctx, &legacy)
+
+	// Running every migration in order reaches the current store shape
+	migrations := keeper.NewMigrator(k).Migrations()
+	for from := uint64(1); from < types.ConsensusVersion; from++ {
+		require.NoError(t, migrations[from](ctx), "migration from version %d", from)
+	}
+	require.Equal(t, legacy, k.GetParams(ctx))
+	requireParamsConsistent(t, k, ctx)
+}
+
+func TestBotLivenessMigration(t *testing.T) {
+	k, ctx, _ := setupParamsMigration(t)
+	ctx = ctx.WithBlockHeight(1000)
+
+	// A version 2 store predates the timeout param
+	params := types.DefaultParams()
+	params.BotHeartbeatTimeoutBlocks = 0
+	k.SetParams(ctx, params)
+	validator := sdk.ValAddress([]byte("upgraded-validator--"))
+	require.True(t, k.IsValidatorBotRunning(ctx, validator), "running before tracking starts")
+
+	require.NoError(t, keeper.NewMigrator(k).Migrate2to3(ctx))
+	require.Equal(t, types.DefaultBotHeartbeatTimeoutBlocks, k.GetParams(ctx).BotHeartbeatTimeoutBlocks)
+	start, found := k.GetBotLivenessStart(ctx)
+	require.True(t, found)
+	require.Equal(t, int64(1000), start)
+	requireParamsConsistent(t, k, ctx)
+
+	// Validators get the timeout from the upgrade height to send a heartbeat
+	timeout := int64(types.DefaultBotHeartbeatTimeoutBlocks)
+	require.True(t, k.IsValidatorBotRunning(ctx.WithBlockHeight(1000+timeout), validator))
+	require.False(t, k.IsValidatorBotRunning(ctx.WithBlockHeight(1000+timeout+1), validator))
+}
+
+func TestDistributionCeilingMigration(t *testing.T) {
+	k, ctx, _ := setupParamsMigration(t)
+
+	// A version 3 store predates the ceiling params
+	params := types.DefaultParams()
+	params.MaxMonthlyDistribution = sdk.Int{}
+	params.MaxMonthlyDistributionSupplyRatio = sdk.Dec{}
+	k.SetParams(ctx, params)
+	require.NoError(t, k.GetParams(ctx).Validate())
+
+	require.NoError(t, keeper.NewMigrator(k).Migrate3to4(ctx))
+	require.Equal(t, types.DefaultParams(), k.GetParams(ctx))
+	requireParamsConsistent(t, k, ctx)
+}
+
+func TestDexDistributionPeriodMigration(t *testing.T) {
+	k, ctx, _ := setupParamsMigration(t)
+
+	// A version 7 store predates dex_distribution_period
+	params := types.DefaultParams()
+	params.DexDistributionPeriod = 0
+	k.SetParams(ctx, params)
+
+	require.NoError(t, keeper.NewMigrator(k).Migrate7to8(ctx))
+	require.Equal(t, types.DefaultParams(), k.GetParams(ctx))
+	requireParamsConsistent(t, k, ctx)
+}
+
+func TestDexDistributionAccountMigration(t *testing.T) {
+	bank := newModuleBankKeeper(1_000_000)
+	k, ctx := setupKeeperWithBank(t, bank)
+
+	// A version 4 store holds the pending DEX share of two cycles in the
+	// halving module account
+	for cycle, amount := range map[uint64]int64{1: 30_000, 2: 20_000} {
+		k.SetPendingDexAllocation(ctx, types.PendingDexAllocation{
+			Cycle:          cycle,
+			Amount:         sdk.NewInt64Coin(keeper.MainDenom, amount),
+			TotalAllocated: sdk.NewInt64Coin(keeper.MainDenom, amount),
+		})
+	}
+	_, broken := keeper.DexDistributionBalanceInvariant(k)(ctx)
+	require.True(t, broken)
+
+	require.NoError(t, keeper.NewMigrator(k).Migrate4to5(ctx))
+	require.Equal(t, int64(50_000), k.GetDEXDistributionBalance(ctx).Amount.Int64())
+	require.Equal(t, int64(950_000), bank.balances[types.ModuleName].AmountOf(keeper.MainDenom).Int64())
+	msg, broken := keeper.DexDistributionBalanceInvariant(k)(ctx)
+	require.False(t, broken, msg)
+
+	// The pending allocations themselves are unchanged
+	require.Equal(t, int64(30_000), k.GetPendingDexAllocation(ctx, 1).Amount.Amount.Int64())
+	require.Equal(t, int64(20_000), k.GetPendingDexAllocation(ctx, 2).Amount.Amount.Int64())
+}
+
+func TestDexDistributionAccountMigrationShortfall(t *testing.T) {
+	bank := newModuleBankKeeper(10_000)
+	k, ctx := setupKeeperWithBank(t, bank)
+	k.SetPendingDexAllocation(ctx, types.PendingDexAllocation{
+		Cycle:          1,
+		Amount:         sdk.NewInt64Coin(keeper.MainDenom, 30_000),
+		TotalAllocated: sdk.NewInt64Coin(keeper.MainDenom, 30_000),
+	})
+
+	// Only what the module holds is moved, the invariant reports the rest
+	require.NoError(t, keeper.NewMigrator(k).Migrate4to5(ctx))
+	require.Equal(t, int64(10_000), k.GetDEXDistributionBalance(ctx).Amount.Int64())
+	require.True(t, bank.balances[types.ModuleName].IsZero())
+	msg, broken := keeper.DexDistributionBalanceInvariant(k)(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "holds 10000ugen of 30000ugen pending")
+}
+
+func TestLastDistributionTimeMigration(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	distributed := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// A version 6 store holds the time under the distribution record prefix
+	k.SetDistributionRecord(ctx, types.DistributionRecord{
+		Timestamp:           distributed.Unix(),
+		Amount:              sdk.NewInt64Coin(keeper.MainDenom, 1_000),
+		Cycle:               1,
+		Month:               1,
+		RedirectedDexAmount: sdk.NewInt64Coin(keeper.MainDenom, 0),
+		DistributionMonth:   1,
+	})
+	k.SetVersion6LastDistributionTime(ctx, distributed.Unix())
+
+	require.NoError(t, keeper.NewMigrator(k).Migrate6to7(ctx))
+	last, found := k.GetLastDistributionTime(ctx)
+	require.True(t, found)
+	require.Equal(t, distributed.Unix(), last)
+	require.Len(t, k.GetAllDistributionRecords(ctx), 1)
+
+	// Stores without the time, or already migrated, are unchanged
+	require.NoError(t, keeper.NewMigrator(k).Migrate6to7(ctx))
+	require.Len(t, k.GetAllDistributionRecords(ctx), 1)
+}
@@ -0,0 +1,145 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+type msgServer struct {
+	Keeper
+}
+
+var _ types.MsgServer = msgServer{}
+
+// NewMsgServerImpl returns an implementation of the halving MsgServer interface
+func NewMsgServerImpl(keeper Keeper) types.MsgServer {
+	return &msgServer{Keeper: keeper}
+}
+
+// DeclareDowntime records a planned downtime window for the signing validator
+func (m msgServer) DeclareDowntime(goCtx context.Context, msg *types.MsgDeclareDowntime) (*types.MsgDeclareDowntimeResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	valAddr, err := sdk.ValAddressFromBech32(msg.ValidatorAddress)
+	if err != nil {
+		return nil, errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid validator address: %s", err)
+	}
+
+	if _, found := m.stakingKeeper.GetValidator(ctx, valAddr); !found {
+		return nil, errorsmod.Wrap(types.ErrValidatorNotFound, msg.ValidatorAddress)
+	}
+
+	declaration, err := m.Keeper.DeclareDowntime(ctx, valAddr, msg.Start, msg.End, msg.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgDeclareDowntimeResponse{Days: declaration.Days}, nil
+}
+
+// WithdrawDexReserve sends part of the pending DEX share to a registered LP
+// pool for the DEX reserve operator
+func (m msgServer) WithdrawDexReserve(goCtx context.Context, msg *types.MsgWithdrawDexReserve) (*types.MsgWithdrawDexReserveResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	operator, err := sdk.AccAddressFromBech32(msg.Operator)
+	if err != nil {
+		return nil, errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid operator address: %s", err)
+	}
+	destination, err := sdk.AccAddressFromBech32(msg.DestinationPool)
+	if err != nil {
+		return nil, errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid destination pool address: %s", err)
+	}
+
+	withdrawal, epoch, err := m.Keeper.WithdrawDexReserve(ctx, operator, destination, msg.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgWithdrawDexReserveResponse{
+		WithdrawalId: withdrawal.Id,
+		Remaining:    dexWithdrawalRemaining(m.GetParams(ctx), epoch),
+		EpochEnd:     epoch.End,
+	}, nil
+}
+
+// RegisterBotHeartbeat records a heartbeat of the signing validator's bot
+func (m msgServer) RegisterBotHeartbeat(goCtx context.Context, msg *types.MsgRegisterBotHeartbeat) (*types.MsgRegisterBotHeartbeatResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	valAddr, err := sdk.ValAddressFromBech32(msg.ValidatorAddress)
+	if err != nil {
+		return nil, errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid validator address: %s", err)
+	}
+
+	if _, found := m.stakingKeeper.GetValidator(ctx, valAddr); !found {
+		return nil, errorsmod.Wrap(types.ErrValidatorNotFound, msg.ValidatorAddress)
+	}
+
+	heartbeat := m.RecordHeartbeat(ctx, valAddr)
+	expiresHeight := heartbeat.Height + int64(m.GetParams(ctx).BotHeartbeatTimeoutBlocks)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeBotHeartbeat,
+			sdk.NewAttribute(types.AttributeKeyValidator, msg.ValidatorAddress),
+			sdk.NewAttribute(types.AttributeKeyExpiresAt, fmt.Sprintf("%d", expiresHeight)),
+		),
+	)
+
+	return &types.MsgRegisterBotHeartbeatResponse{ExpiresHeight: expiresHeight}, nil
+}
+
+// SetBotMetadata registers the version, contact and endpoint of the signing
+// validator's bot in the bot directory
+func (m msgServer) SetBotMetadata(goCtx context.Context, msg *types.MsgSetBotMetadata) (*types.MsgSetBotMetadataResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	valAddr, err := sdk.ValAddressFromBech32(msg.ValidatorAddress)
+	if err != nil {
+		return nil, errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid validator address: %s", err)
+	}
+
+	if _, found := m.stakingKeeper.GetValidator(ctx, valAddr); !found {
+		return nil, errorsmod.Wrap(types.ErrValidatorNotFound, msg.ValidatorAddress)
+	}
+
+	metadata, err := m.UpdateBotMetadata(ctx, valAddr, msg.Version, msg.Contact, msg.EndpointUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeBotMetadataSet,
+			sdk.NewAttribute(types.AttributeKeyValidator, msg.ValidatorAddress),
+			sdk.NewAttribute(types.AttributeKeyVersion, metadata.Version),
+			sdk.NewAttribute(types.AttributeKeyContact, metadata.Contact),
+			sdk.NewAttribute(types.AttributeKeyEndpointURL, metadata.EndpointUrl),
+		),
+	)
+
+	return &types.MsgSetBotMetadataResponse{NextUpdateHeight: nextBotMetadataUpdateHeight(metadata)}, nil
+}
+
+// UpdateHalvingParams sets the distribution shares; only the authority may
+// update them
+func (m msgServer) UpdateHalvingParams(goCtx context.Context, msg *types.MsgUpdateHalvingParams) (*types.MsgUpdateHalvingParamsResponse, error) {
+	if msg.Authority != m.authority {
+		return nil, errorsmod.Wrapf(types.ErrInvalidAuthority, "expected %s, got %s", m.authority, msg.Authority)
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	record, err := m.Keeper.UpdateHalvingParams(ctx, msg.Authority, msg.ValidatorShare, msg.DelegatorShare, msg.DexShare)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgUpdateHalvingParamsResponse{RecordId: record.Id}, nil
+}
@@ -0,0 +1,272 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	bankkeeper "github.com/cosmos/cosmos-sdk/x/bank/keeper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// mockBankKeeper holds the halving module balance and records what it sends
+type mockBankKeeper struct {
+	bankkeeper.Keeper
+	moduleBalance sdk.Coins
+	sent          map[string]sdk.Coins
+}
+
+func (m *mockBankKeeper) SendCoinsFromModuleToAccount(_ sdk.Context, _ string, recipient sdk.AccAddress, amount sdk.Coins) error {
+	balance, negative := m.moduleBalance.SafeSub(amount...)
+	if negative {
+		return sdkerrors.ErrInsufficientFunds
+	}
+	m.moduleBalance = balance
+	m.sent[recipient.String()] = m.sent[recipient.String()].Add(amount...)
+	return nil
+}
+
+// mockLPPoolRegistry whitelists the pools mapped to true
+type mockLPPoolRegistry map[string]bool
+
+func (m mockLPPoolRegistry) IsActiveLPPool(_ sdk.Context, address string) bool {
+	return m[address]
+}
+
+var (
+	testDexOperator = sdk.AccAddress([]byte("dex-reserve-operator"))
+	testActivePool  = sdk.AccAddress([]byte("lp-pool-gxr-ton"))
+	testPausedPool  = sdk.AccAddress([]byte("lp-pool-gxr-usdt"))
+)
+
+// setupDexReserve returns a keeper whose module holds reserve ugen, all of
+// it pending for the bot in cycle 1, with the operator, a 1,000ugen cap per
+// one-day epoch, and testActivePool registered
+func setupDexReserve(t *testing.T, reserve int64) (keeper.Keeper, sdk.Context, *mockBankKeeper) {
+	bank := &mockBankKeeper{
+		moduleBalance: sdk.NewCoins(sdk.NewInt64Coin(keeper.MainDenom, reserve)),
+		sent:          make(map[string]sdk.Coins),
+	}
+	k, ctx := setupKeeperWithBank(t, bank)
+	k.SetLPPoolRegistry(mockLPPoolRegistry{testActivePool.String(): true, testPausedPool.String(): false})
+
+	params := k.GetParams(ctx)
+	params.DexReserveOperator = testDexOperator.String()
+	params.DexWithdrawalCap = sdk.NewInt(1000)
+	params.DexWithdrawalEpoch = 24 * time.Hour
+	k.SetParams(ctx, params)
+
+	k.SetPendingDexAllocation(ctx, types.PendingDexAllocation{
+		Cycle:          1,
+		Amount:         sdk.NewInt64Coin(keeper.MainDenom, reserve),
+		TotalAllocated: sdk.NewInt64Coin(keeper.MainDenom, reserve),
+		Months:         1,
+	})
+
+	return k, ctx, bank
+}
+
+func withdraw(ctx sdk.Context, k keeper.Keeper, operator sdk.AccAddress, amount int64, pool sdk.AccAddress) (*types.MsgWithdrawDexReserveResponse, error) {
+	msg := types.NewMsgWithdrawDexReserve(operator, sdk.NewInt64Coin(keeper.MainDenom, amount), pool)
+	return keeper.NewMsgServerImpl(k).WithdrawDexReserve(sdk.WrapSDKContext(ctx), msg)
+}
+
+func TestMsgServerWithdrawDexReserveAuthorization(t *testing.T) {
+	k, ctx, bank := setupDexReserve(t, 10_000)
+
+	// Only the operator set by governance may withdraw
+	_, err := withdraw(ctx, k, sdk.AccAddress([]byte("bot-hot-key")), 100, testActivePool)
+	require.ErrorIs(t, err, types.ErrUnauthorizedDexOperator)
+
+	res, err := withdraw(ctx, k, testDexOperator, 100, testActivePool)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.WithdrawalId)
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 900), res.Remaining)
+	require.Equal(t, "100ugen", bank.sent[testActivePool.String()].String())
+	require.Equal(t, sdk.NewInt64Coin(keeper.MainDenom, 9_900), k.GetPendingDexAllocation(ctx, 1).Amount)
+
+	withdrawals := k.GetAllDexReserveWithdrawals(ctx)
+	require.Len(t, withdrawals, 1)
+	require.Equal(t, testDexOperator.String(), withdrawals[0].Operator)
+	require.Equal(t, testActivePool.String(), withdrawals[0].DestinationPool)
+
+	var emitted bool
+	for _, event := range ctx.EventManager().Events() {
+		emitted = emitted || event.Type == types.EventTypeDexReserveWithdrawn
+	}
+	require.True(t, emitted)
+
+	// Clearing the operator disables withdrawals
+	params := k.GetParams(ctx)
+	params.DexReserveOperator = ""
+	k.SetParams(ctx, params)
+	_, err = withdraw(ctx, k, testDexOperator, 100, testActivePool)
+	require.ErrorIs(t, err, types.ErrUnauthorizedDexOperator)
+
+	// Only the main denom is held for the bot
+	params.DexReserveOperator = testDexOperator.String()
+	k.SetParams(ctx, params)
+	msg := types.NewMsgWithdrawDexReserve(testDexOperator, sdk.NewInt64Coin("uatom", 100), testActivePool)
+	_, err = keeper.NewMsgServerImpl(k).WithdrawDexReserve(sdk.WrapSDKContext(ctx), msg)
+	require.ErrorIs(t, err, types.ErrInvalidDexWithdrawal)
+}
+
+func TestMsgServerWithdrawDexReserveWhitelist(t *testing.T) {
+	k, ctx, bank := setupDexReserve(t, 10_000)
+
+	// Unregistered and inactive pools are rejected
+	_, err := withdraw(ctx, k, testDexOperator, 100, sdk.AccAddress([]byte("operator-own-wallet")))
+	require.ErrorIs(t, err, types.ErrDexPoolNotWhitelisted)
+	_, err = withdraw(ctx, k, testDexOperator, 100, testPausedPool)
+	require.ErrorIs(t, err, types.ErrDexPoolNotWhitelisted)
+	require.Empty(t, bank.sent)
+	require.Empty(t, k.GetAllDexReserveWithdrawals(ctx))
+
+	// Without the feerouter registry no destination is whitelisted
+	k.SetLPPoolRegistry(nil)
+	_, err = withdraw(ctx, k, testDexOperator, 100, testActivePool)
+	require.ErrorIs(t, err, types.ErrDexPoolNotWhitelisted)
+}
+
+func TestMsgServerWithdrawDexReserveCap(t *testing.T) {
+	k, ctx, bank := setupDexReserve(t, 10_000)
+
+	res, err := withdraw(ctx, k, testDexOperator, 600, testActivePool)
+	require.NoError(t, err)
+	require.Equal(t, int64(400), res.Remaining.Amount.Int64())
+
+	// A withdrawal over what is left of the cap fails as a whole
+	_, err = withdraw(ctx, k, testDexOperator, 401, testActivePool)
+	require.ErrorIs(t, err, types.ErrDexWithdrawalCapExceeded)
+	require.Contains(t, err.Error(), "400ugen left of the 1000ugen epoch cap")
+
+	res, err = withdraw(ctx, k, testDexOperator, 400, testActivePool)
+	require.NoError(t, err)
+	require.True(t, res.Remaining.IsZero())
+	require.Equal(t, uint64(2), res.WithdrawalId)
+
+	_, err = withdraw(ctx, k, testDexOperator, 1, testActivePool)
+	require.ErrorIs(t, err, types.ErrDexWithdrawalCapExceeded)
+	require.Equal(t, "1000ugen", bank.sent[testActivePool.String()].String())
+
+	// Lowering the cap below what was withdrawn leaves nothing, not a negative amount
+	params := k.GetParams(ctx)
+	params.DexWithdrawalCap = sdk.NewInt(500)
+	k.SetParams(ctx, params)
+	require.True(t, k.DexWithdrawalRemaining(ctx).IsZero())
+}
+
+func TestMsgServerWithdrawDexReserveLimitedToPendingShare(t *testing.T) {
+	k, ctx, bank := setupDexReserve(t, 300)
+	k.SetPendingDexAllocation(ctx, types.PendingDexAllocation{
+		Cycle:          2,
+		Amount:         sdk.NewInt64Coin(keeper.MainDenom, 200),
+		TotalAllocated: sdk.NewInt64Coin(keeper.MainDenom, 200),
+	})
+	// The module holds more than is pending; the rest is the halving reserve
+	bank.moduleBalance = sdk.NewCoins(sdk.NewInt64Coin(keeper.MainDenom, 1_000_000))
+
+	_, err := withdraw(ctx, k, testDexOperator, 501, testActivePool)
+	require.ErrorIs(t, err, types.ErrInsufficientDexReserve)
+
+	// The oldest cycle is released first
+	_, err = withdraw(ctx, k, testDexOperator, 400, testActivePool)
+	require.NoError(t, err)
+	require.True(t, k.GetPendingDexAllocation(ctx, 1).Amount.IsZero())
+	require.Equal(t, int64(100), k.GetPendingDexAllocation(ctx, 2).Amount.Amount.Int64())
+}
+
+func TestMsgServerWithdrawDexReserveCapResetsAtEpochBoundary(t *testing.T) {
+	k, ctx, _ := setupDexReserve(t, 10_000)
+
+	// genesisTime is midnight UTC, so one-day epochs start at midnight
+	ctx = ctx.WithBlockTime(genesisTime.Add(time.Hour))
+	res, err := withdraw(ctx, k, testDexOperator, 1000, testActivePool)
+	require.NoError(t, err)
+	require.Equal(t, genesisTime.Add(24*time.Hour).Unix(), res.EpochEnd)
+
+	ctx = ctx.WithBlockTime(genesisTime.Add(24*time.Hour - time.Second))
+	_, err = withdraw(ctx, k, testDexOperator, 1, testActivePool)
+	require.ErrorIs(t, err, types.ErrDexWithdrawalCapExceeded)
+
+	// The next epoch starts with the full cap
+	ctx = ctx.WithBlockTime(genesisTime.Add(24 * time.Hour))
+	limit, err := k.DexWithdrawalLimit(sdk.WrapSDKContext(ctx), &types.QueryDexWithdrawalLimitRequest{})
+	require.NoError(t, err)
+	require.True(t, limit.Withdrawn.IsZero())
+	require.Equal(t, int64(1000), limit.Remaining.Amount.Int64())
+	require.Equal(t, genesisTime.Add(24*time.Hour).Unix(), limit.EpochStart)
+	require.Equal(t, int64(9_000), limit.Available.Amount.Int64())
+
+	res, err = withdraw(ctx, k, testDexOperator, 1000, testActivePool)
+	require.NoError(t, err)
+	require.Equal(t, genesisTime.Add(48*time.Hour).Unix(), res.EpochEnd)
+
+	// Both withdrawals are queryable with the epoch they counted against
+	history, err := k.DexReserveWithdrawals(sdk.WrapSDKContext(ctx), &types.QueryDexReserveWithdrawalsRequest{
+		Pagination: &query.PageRequest{Limit: 1, CountTotal: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, history.Withdrawals, 1)
+	require.Equal(t, uint64(2), history.Pagination.Total)
+	require.Equal(t, genesisTime.Unix(), history.Withdrawals[0].EpochStart)
+}
+
+func TestMsgServerUpdateHalvingParams(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	msgServer := keeper.NewMsgServerImpl(k)
+	update := func(authority, validatorShare, delegatorShare, dexShare string) (*types.MsgUpdateHalvingParamsResponse, error) {
+		msg := types.NewMsgUpdateHalvingParams(authority, sdk.MustNewDecFromStr(validatorShare),
+			sdk.MustNewDecFromStr(delegatorShare), sdk.MustNewDecFromStr(dexShare))
+		return msgServer.UpdateHalvingParams(sdk.WrapSDKContext(ctx), msg)
+	}
+
+	// Only the authority may change the shares
+	other := sdk.AccAddress([]byte("not-the-authority")).String()
+	_, err := update(other, "0.60", "0.30", "0.10")
+	require.ErrorIs(t, err, types.ErrInvalidAuthority)
+
+	// The shares must add up to 1.0
+	_, err = update(testAuthority, "0.60", "0.30", "0.20")
+	require.ErrorIs(t, err, types.ErrInvalidShares)
+	require.True(t, types.DefaultParams().ValidatorShare.Equal(k.GetParams(ctx).ValidatorShare))
+
+	ctx = ctx.WithBlockHeight(10)
+	res, err := update(testAuthority, "0.60", "0.30", "0.10")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.RecordId)
+	params := k.GetParams(ctx)
+	require.True(t, sdk.MustNewDecFromStr("0.60").Equal(params.ValidatorShare))
+	require.True(t, sdk.MustNewDecFromStr("0.30").Equal(params.DelegatorShare))
+	require.True(t, sdk.MustNewDecFromStr("0.10").Equal(params.DexShare))
+
+	var updated bool
+	for _, event := range ctx.EventManager().Events() {
+		updated = updated || event.Type == types.EventTypeHalvingParamsUpdated
+	}
+	require.True(t, updated)
+
+	ctx = ctx.WithBlockHeight(20)
+	res, err = update(testAuthority, "0.65", "0.25", "0.10")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), res.RecordId)
+
+	// Auditors see every change with the shares it replaced
+	history, err := k.ParamChangeHistory(sdk.WrapSDKContext(ctx), &types.QueryParamChangeHistoryRequest{
+		Pagination: &query.PageRequest{CountTotal: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, history.Records, 2)
+	require.Equal(t, uint64(2), history.Pagination.Total)
+	first, second := history.Records[0], history.Records[1]
+	require.Equal(t, int64(10), first.Height)
+	require.Equal(t, testAuthority, first.Authority)
+	require.True(t, types.DefaultParams().ValidatorShare.Equal(first.PreviousValidatorShare))
+	require.True(t, sdk.MustNewDecFromStr("0.60").Equal(second.PreviousValidatorShare))
+	require.True(t, sdk.MustNewDecFromStr("0.65").Equal(second.ValidatorShare))
+}
@@ -0,0 +1,132 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+
+	errorsmod "cosmossdk.io/errors"
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// UpdateHalvingParams sets the validator, delegator and DEX shares on behalf
+// of the governance authority and records the change in the param change
+// history. The shares must add up to 1.0; distributions from the next one
+// on use the new shares.
+func (k Keeper) UpdateHalvingParams(ctx sdk.Context, authority string, validatorShare, delegatorShare, dexShare sdk.Dec) (types.ParamChangeRecord, error) {
+	if err := types.ValidateShares(validatorShare, delegatorShare, dexShare); err != nil {
+		return types.ParamChangeRecord{}, errorsmod.Wrap(types.ErrInvalidShares, err.Error())
+	}
+
+	params := k.GetParams(ctx)
+	record := types.ParamChangeRecord{
+		Id:                     k.lastParamChangeRecordID(ctx) + 1,
+		Height:                 ctx.BlockHeight(),
+		Timestamp:              ctx.BlockTime().Unix(),
+		Authority:              authority,
+		PreviousValidatorShare: params.ValidatorShare,
+		PreviousDelegatorShare: params.DelegatorShare,
+		PreviousDexShare:       params.DexShare,
+		ValidatorShare:         validatorShare,
+		DelegatorShare:         delegatorShare,
+		DexShare:               dexShare,
+	}
+
+	params.ValidatorShare = validatorShare
+	params.DelegatorShare = delegatorShare
+	params.DexShare = dexShare
+	if err := params.Validate(); err != nil {
+		return types.ParamChangeRecord{}, errorsmod.Wrap(types.ErrInvalidShares, err.Error())
+	}
+	k.SetParams(ctx, params)
+	k.SetParamChangeRecord(ctx, record)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeHalvingParamsUpdated,
+			sdk.NewAttribute(types.AttributeKeyRecordID, fmt.Sprintf("%d", record.Id)),
+			sdk.NewAttribute(types.AttributeKeyAuthority, authority),
+			sdk.NewAttribute(types.AttributeKeyValidatorShare, validatorShare.String()),
+			sdk.NewAttribute(types.AttributeKeyDelegatorShare, delegatorShare.String()),
+			sdk.NewAttribute(types.AttributeKeyDexShare, dexShare.String()),
+		),
+	)
+
+	k.Logger(ctx).Info("Halving distribution shares updated",
+		"id", record.Id,
+		"validator_share", validatorShare.String(),
+		"delegator_share", delegatorShare.String(),
+		"dex_share", dexShare.String(),
+	)
+
+	return record, nil
+}
+
+// SetParamChangeRecord stores a param change record
+func (k Keeper) SetParamChangeRecord(ctx sdk.Context, record types.ParamChangeRecord) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshal(&record)
+	store.Set(types.GetParamChangeRecordKey(record.Id), bz)
+}
+
+// GetAllParamChangeRecords returns all param change records ordered by id
+func (k Keeper) GetAllParamChangeRecords(ctx sdk.Context) []types.ParamChangeRecord {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.ParamChangeRecordKey)
+	defer iterator.Close()
+
+	var records []types.ParamChangeRecord
+	for ; iterator.Valid(); iterator.Next() {
+		var record types.ParamChangeRecord
+		k.cdc.MustUnmarshal(iterator.Value(), &record)
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// lastParamChangeRecordID returns the highest param change record id, 0 if there is none
+func (k Keeper) lastParamChangeRecordID(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStoreReversePrefixIterator(store, types.ParamChangeRecordKey)
+	defer iterator.Close()
+
+	if !iterator.Valid() {
+		return 0
+	}
+	return sdk.BigEndianToUint64(iterator.Key()[len(types.ParamChangeRecordKey):])
+}
+
+// ParamChangeHistory returns the governance changes of the distribution
+// shares with pagination.
+func (k Keeper) ParamChangeHistory(goCtx context.Context, req *types.QueryParamChangeHistoryRequest) (*types.QueryParamChangeHistoryResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	recordStore := prefix.NewStore(ctx.KVStore(k.storeKey), types.ParamChangeRecordKey)
+
+	records := []types.ParamChangeRecord{}
+	pageRes, err := query.Paginate(recordStore, req.Pagination, func(key []byte, value []byte) error {
+		var record types.ParamChangeRecord
+		if err := k.cdc.Unmarshal(value, &record); err != nil {
+			return err
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &types.QueryParamChangeHistoryResponse{
+		Records:    records,
+		Pagination: pageRes,
+	}, nil
+}
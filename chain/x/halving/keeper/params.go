@@ -0,0 +1,61 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// GetParams returns the module params. Until the migration to the module
+// store has run, they are read from the legacy x/params subspace and written
+// through, so a block processed before the migration still sees the chain's
+// params rather than failing.
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	if bz := ctx.KVStore(k.storeKey).Get(types.ParamsKey); bz != nil {
+		k.cdc.MustUnmarshal(bz, &params)
+		return params
+	}
+
+	params = k.legacyParams(ctx)
+	k.setStoreParams(ctx, params)
+	return params
+}
+
+// SetParams stores the params. While the legacy subspace still holds a copy,
+// it is updated too, so both sources agree until the copy is deleted.
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.setStoreParams(ctx, params)
+	if k.hasLegacyParams(ctx) {
+		k.paramstore.SetParamSet(ctx, &params)
+	}
+}
+
+// setStoreParams writes the params to the module store
+func (k Keeper) setStoreParams(ctx sdk.Context, params types.Params) {
+	ctx.KVStore(k.storeKey).Set(types.ParamsKey, k.cdc.MustMarshal(&params))
+}
+
+// hasLegacyParams reports whether the legacy subspace holds any param
+func (k Keeper) hasLegacyParams(ctx sdk.Context) bool {
+	if !k.paramstore.HasKeyTable() {
+		return false
+	}
+	var params types.Params
+	for _, pair := range params.ParamSetPairs() {
+		if k.paramstore.Has(ctx, pair.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyParams reads the params from the legacy subspace. Params the
+// subspace does not hold, all of them if it was never initialized, keep
+// their defaults.
+func (k Keeper) legacyParams(ctx sdk.Context) types.Params {
+	params := types.DefaultParams()
+	if k.hasLegacyParams(ctx) {
+		k.paramstore.GetParamSetIfExists(ctx, &params)
+	}
+	return params
+}
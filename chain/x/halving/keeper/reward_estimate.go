@@ -0,0 +1,203 @@
+package keeper
+
+import (
+	"fmt"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// EstimateReward estimates what a validator and an additional delegation of
+// additional ugen to it receive from the next unpaid distribution month. It
+// replays the distribution math on the current state: the monthly amount of
+// the current fund, the reward split at the expected distribution time, the
+// validator's eligibility, and the x/distribution allocation of the
+// delegator share by voting power with the additional delegation bonded.
+// Nothing is written to state.
+func (k Keeper) EstimateReward(ctx sdk.Context, valAddr sdk.ValAddress, additional sdk.Int) (types.RewardEstimate, error) {
+	validator, found := k.stakingKeeper.GetValidator(ctx, valAddr)
+	if !found {
+		return types.RewardEstimate{}, errorsmod.Wrap(types.ErrValidatorNotFound, valAddr.String())
+	}
+
+	zero := sdk.NewCoin(MainDenom, sdk.ZeroInt())
+	estimate := types.RewardEstimate{
+		Estimate:             true,
+		ValidatorAddress:     valAddr.String(),
+		MonthlyAmount:        zero,
+		ValidatorReward:      zero,
+		DelegatorPool:        zero,
+		VotingPowerFraction:  sdk.ZeroDec(),
+		CommunityTax:         k.communityTax(ctx),
+		CommissionRate:       validator.GetCommission(),
+		ValidatorCommission:  zero,
+		DelegatorsReward:     zero,
+		AdditionalDelegation: sdk.NewCoin(MainDenom, additional),
+		DelegationReward:     zero,
+	}
+
+	info, found := k.GetHalvingInfo(ctx)
+	if !found || !info.DistributionActive {
+		estimate.Assumptions = []string{"the distribution period is paused, no rewards are distributed until the next cycle"}
+		return estimate, nil
+	}
+	paid := k.monthsDistributed(ctx, info)
+	if paid >= types.DistributionMonths {
+		estimate.Assumptions = []string{"all months of the distribution period were paid, no rewards are distributed until the next cycle"}
+		return estimate, nil
+	}
+
+	month := paid + 1
+	distributionTime := k.expectedDistributionTime(ctx, info, month)
+	estimate.DistributionMonth = month
+	estimate.DistributionTime = distributionTime.Unix()
+	estimate.MonthsRemaining = types.DistributionMonths - paid
+
+	monthly, _ := k.nextMonthlyDistribution(ctx, info)
+	split := k.splitRewards(ctx.WithBlockTime(distributionTime), monthly, info)
+	estimate.MonthlyAmount = monthly
+	estimate.DexWindowOpen = split.RedirectDestination == ""
+	estimate.DexRedirectDestination = split.RedirectDestination
+	estimate.DelegatorPool = sdk.NewCoin(MainDenom, split.Delegators)
+
+	eligible, snapshotted := k.estimateEligibility(ctx, info, month)
+	estimate.ValidatorEligible = eligible[valAddr.String()]
+	estimate.EligibleValidators = uint64(len(eligible))
+	withheld := sdk.ZeroInt()
+	for _, share := range k.WeightedValidatorReward(ctx, eligible, split.Validators) {
+		if share.Validator.Equals(valAddr) {
+			withheld = k.clawbackWithheld(ctx, valAddr, share.Amount)
+			estimate.ValidatorReward = sdk.NewCoin(MainDenom, share.Amount.Sub(withheld))
+		}
+	}
+
+	k.estimateDelegatorShare(ctx, &estimate, validator, split.Delegators, additional)
+
+	estimate.Assumptions = []string{
+		fmt.Sprintf("month %d of %d is paid at %s from the current halving fund of %s",
+			month, types.DistributionMonths, distributionTime.UTC().Format(time.RFC3339), info.HalvingFund),
+		"the bonded validators, their stake and commission stay as they are apart from the additional delegation",
+		"the validator share is divided by the current bonded tokens, without the additional delegation",
+		"all bonded validators sign the block before the distribution block",
+		"no other fees are collected in the distribution block",
+	}
+	if snapshotted {
+		estimate.Assumptions = append(estimate.Assumptions, "eligibility is taken from the month's eligibility snapshot")
+	} else {
+		estimate.Assumptions = append(estimate.Assumptions, "eligibility is evaluated on the current uptime until the month's eligibility snapshot is taken")
+	}
+	if !validator.IsBonded() {
+		estimate.Assumptions = append(estimate.Assumptions, "the validator is not bonded and receives nothing unless it joins the bonded set")
+	}
+	if withheld.IsPositive() {
+		estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("%s%s of the validator reward is withheld to settle an equivocation clawback", withheld, MainDenom))
+	}
+	if k.communityTaxSource == nil {
+		estimate.Assumptions = append(estimate.Assumptions, "the community tax is unknown and taken as zero")
+	}
+
+	return estimate, nil
+}
+
+// expectedDistributionTime returns when a distribution month is expected to
+// be paid: in the next block if earlier months are being caught up,
+// otherwise once the month is due and the monthly distribution trigger
+// passed
+func (k Keeper) expectedDistributionTime(ctx sdk.Context, info types.HalvingInfo, month uint64) time.Time {
+	if k.CatchUpPending(ctx) {
+		return ctx.BlockTime()
+	}
+
+	due := monthDueTime(info, month)
+	if next := k.NextMonthlyDistributionTime(ctx, info); next.After(due) {
+		due = next
+	}
+	if due.Before(ctx.BlockTime()) {
+		due = ctx.BlockTime()
+	}
+	return due.UTC()
+}
+
+// estimateEligibility returns the validators eligible for the month's
+// validator rewards by operator address, and whether they come from the
+// month's snapshot. Until the snapshot is taken the bonded validators are
+// evaluated on their current uptime without recording it.
+func (k Keeper) estimateEligibility(ctx sdk.Context, info types.HalvingInfo, month uint64) (map[string]bool, bool) {
+	eligible := make(map[string]bool)
+
+	if snapshots := k.GetEligibilitySnapshots(ctx, info.CurrentCycle, month); len(snapshots) > 0 {
+		for _, snapshot := range snapshots {
+			snapshotAddr, err := sdk.ValAddressFromBech32(snapshot.ValidatorAddress)
+			if snapshot.Eligible && !(err == nil && k.equivocated(ctx, snapshotAddr)) {
+				eligible[snapshot.ValidatorAddress] = true
+			}
+		}
+		return eligible, true
+	}
+
+	currentMonth := k.getCurrentMonth(ctx)
+	threshold := sdk.NewDec(ValidatorInactiveThreshold)
+	for _, validator := range k.stakingKeeper.GetBondedValidatorsByPower(ctx) {
+		uptime, found := k.GetValidatorUptime(ctx, validator.GetOperator())
+		if found && uptime.CurrentMonth == currentMonth && k.EffectiveInactiveDays(ctx, uptime).GT(threshold) {
+			continue
+		}
+		if k.equivocated(ctx, validator.GetOperator()) {
+			continue
+		}
+		eligible[validator.OperatorAddress] = true
+	}
+	return eligible, false
+}
+
+// estimateDelegatorShare fills in the validator's part of the delegator pool
+// as x/distribution allocates it: the pool less the community tax, by the
+// validator's share of the bonded voting power, less its commission, shared
+// among its delegators per token. The same truncation as x/distribution is
+// applied, so the estimate matches the allocation when nothing changes.
+func (k Keeper) estimateDelegatorShare(ctx sdk.Context, estimate *types.RewardEstimate, validator stakingtypes.Validator, pool, additional sdk.Int) {
+	if !validator.IsBonded() || !pool.IsPositive() {
+		return
+	}
+
+	powerReduction := k.stakingKeeper.PowerReduction(ctx)
+	tokens := validator.GetTokens().Add(additional)
+	power := sdk.TokensToConsensusPower(tokens, powerReduction)
+	totalPower := power
+	for _, bonded := range k.stakingKeeper.GetBondedValidatorsByPower(ctx) {
+		if bonded.OperatorAddress != validator.OperatorAddress {
+			totalPower += bonded.GetConsensusPower(powerReduction)
+		}
+	}
+	if power <= 0 || totalPower <= 0 {
+		return
+	}
+
+	fraction := sdk.NewDec(power).QuoTruncate(sdk.NewDec(totalPower))
+	afterTax := sdk.NewDecFromInt(pool).MulTruncate(sdk.OneDec().Sub(estimate.CommunityTax))
+	reward := afterTax.MulTruncate(fraction)
+	commission := reward.Mul(estimate.CommissionRate)
+	shared := reward.Sub(commission)
+
+	estimate.VotingPowerFraction = fraction
+	estimate.ValidatorCommission = sdk.NewCoin(MainDenom, commission.TruncateInt())
+	estimate.DelegatorsReward = sdk.NewCoin(MainDenom, shared.TruncateInt())
+
+	if additional.IsPositive() {
+		perToken := shared.QuoTruncate(sdk.NewDecFromInt(tokens))
+		estimate.DelegationReward = sdk.NewCoin(MainDenom, perToken.MulTruncate(sdk.NewDecFromInt(additional)).TruncateInt())
+	}
+}
+
+// communityTax returns the distribution module's community tax, zero when it
+// is not wired
+func (k Keeper) communityTax(ctx sdk.Context) sdk.Dec {
+	if k.communityTaxSource == nil {
+		return sdk.ZeroDec()
+	}
+	return k.communityTaxSource.GetCommunityTax(ctx)
+}
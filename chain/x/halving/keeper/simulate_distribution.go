@@ -0,0 +1,131 @@
+package keeper
+
+import (
+	"github.com/cometbft/cometbft/libs/log"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+const (
+	// SimulateDistributionGasLimit bounds the gas of a distribution
+	// simulation query; its cost grows with the bonded validator set
+	SimulateDistributionGasLimit = 10_000_000
+
+	// ExclusionReasonInactive excludes a validator over the monthly
+	// inactivity threshold at the eligibility snapshot
+	ExclusionReasonInactive = "inactive"
+
+	// ExclusionReasonEquivocation excludes a validator tombstoned or caught
+	// double signing in the current or previous uptime month
+	ExclusionReasonEquivocation = "equivocation"
+)
+
+// SimulateNextDistribution returns what DistributeHalvingRewards pays for the
+// next unpaid distribution month if it runs on ctx: the same monthly amount,
+// split and eligibility snapshot, and the same per-validator division. It
+// runs on a branch of the store that is discarded, with its events and logs,
+// so the eligibility snapshot and uptime bookkeeping a distribution would
+// write leave no trace. Amounts a distribution cannot know up front, such as
+// a failed transfer to a validator, are not simulated.
+func (k Keeper) SimulateNextDistribution(ctx sdk.Context) types.DistributionSimulation {
+	ctx, _ = ctx.CacheContext()
+	ctx = ctx.WithLogger(log.NewNopLogger())
+
+	zero := sdk.NewCoin(MainDenom, sdk.ZeroInt())
+	simulation := types.DistributionSimulation{
+		SimulatedAt:         ctx.BlockTime().Unix(),
+		Height:              ctx.BlockHeight(),
+		MonthlyAmount:       zero,
+		RemainingFund:       zero,
+		Validators:          zero,
+		Delegators:          zero,
+		Dex:                 zero,
+		Redirected:          zero,
+		ValidatorsForfeited: zero,
+	}
+
+	info, found := k.GetHalvingInfo(ctx)
+	if !found || !info.DistributionActive {
+		return simulation
+	}
+	simulation.Cycle = info.CurrentCycle
+	simulation.RemainingFund = info.HalvingFund
+
+	paid := k.monthsDistributed(ctx, info)
+	if paid >= types.DistributionMonths {
+		return simulation
+	}
+	month := paid + 1
+	simulation.DistributionMonth = month
+	simulation.Due = k.ShouldDistribute(ctx)
+	simulation.CatchUp = k.CatchUpPending(ctx)
+
+	monthlyAmount, _ := k.nextMonthlyDistribution(ctx, info)
+	if monthlyAmount.IsZero() {
+		return simulation
+	}
+	simulation.MonthlyAmount = monthlyAmount
+	simulation.RemainingFund = info.HalvingFund.Sub(monthlyAmount)
+
+	split := k.splitRewards(ctx, monthlyAmount, info)
+	simulation.Validators = sdk.NewCoin(MainDenom, split.Validators)
+	simulation.Delegators = sdk.NewCoin(MainDenom, split.Delegators)
+	simulation.Dex = sdk.NewCoin(MainDenom, split.Dex)
+	simulation.Redirected = sdk.NewCoin(MainDenom, split.Redirected)
+	simulation.DexRedirectDestination = split.RedirectDestination
+
+	withheld := k.simulateValidatorPayouts(ctx, &simulation, info, month, split.Validators)
+	simulation.RemainingFund = simulation.RemainingFund.AddAmount(withheld)
+
+	return simulation
+}
+
+// simulateValidatorPayouts divides the validator share among the validators
+// eligible in the month's snapshot by their bonded tokens as
+// distributeToActiveValidators does, taking the snapshot on the branch when
+// it is not stored yet. It returns the part withheld for clawbacks, which
+// stays in the halving fund.
+func (k Keeper) simulateValidatorPayouts(ctx sdk.Context, simulation *types.DistributionSimulation, info types.HalvingInfo, month uint64, amount sdk.Int) sdk.Int {
+	k.writeOffTombstonedClawbacks(ctx)
+	simulation.EligibilitySnapshotted = k.hasEligibilitySnapshot(ctx, info.CurrentCycle, month)
+
+	eligible := make(map[string]bool)
+	for _, snapshot := range k.eligibilitySnapshot(ctx, info, month) {
+		valAddr, err := sdk.ValAddressFromBech32(snapshot.ValidatorAddress)
+		switch {
+		case !snapshot.Eligible:
+			simulation.Exclusions = append(simulation.Exclusions, types.DistributionExclusion{
+				ValidatorAddress: snapshot.ValidatorAddress,
+				Reason:           ExclusionReasonInactive,
+				InactiveDays:     snapshot.InactiveDays,
+			})
+		case err == nil && k.equivocated(ctx, valAddr):
+			simulation.Exclusions = append(simulation.Exclusions, types.DistributionExclusion{
+				ValidatorAddress: snapshot.ValidatorAddress,
+				Reason:           ExclusionReasonEquivocation,
+				InactiveDays:     snapshot.InactiveDays,
+			})
+		default:
+			eligible[snapshot.ValidatorAddress] = true
+		}
+	}
+
+	paidOut, totalWithheld := sdk.ZeroInt(), sdk.ZeroInt()
+	for _, share := range k.WeightedValidatorReward(ctx, eligible, amount) {
+		if !share.Amount.IsPositive() {
+			continue
+		}
+		withheld := k.clawbackWithheld(ctx, share.Validator, share.Amount)
+		simulation.ValidatorPayouts = append(simulation.ValidatorPayouts, types.ValidatorPayout{
+			ValidatorAddress: share.Validator.String(),
+			AccountAddress:   sdk.AccAddress(share.Validator).String(),
+			Amount:           sdk.NewCoin(MainDenom, share.Amount.Sub(withheld)),
+			Withheld:         sdk.NewCoin(MainDenom, withheld),
+		})
+		paidOut = paidOut.Add(share.Amount)
+		totalWithheld = totalWithheld.Add(withheld)
+	}
+	simulation.ValidatorsForfeited = sdk.NewCoin(MainDenom, amount.Sub(paidOut))
+	return totalWithheld
+}
@@ -0,0 +1,55 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ValidatorRewardShare is a validator's part of a month's validator share
+type ValidatorRewardShare struct {
+	Validator sdk.ValAddress
+	Tokens    sdk.Int
+	Amount    sdk.Int
+}
+
+// WeightedValidatorReward divides totalReward among the bonded validators in
+// eligible, keyed by operator address, in proportion to their bonded tokens:
+// validatorTokens / totalTokens * totalReward, truncated. The rounding
+// remainder goes to the validator with the most tokens, so the shares add up
+// to totalReward. Eligible validators that are no longer bonded receive
+// nothing. Shares are ordered by voting power.
+func (k Keeper) WeightedValidatorReward(ctx sdk.Context, eligible map[string]bool, totalReward sdk.Int) []ValidatorRewardShare {
+	if k.bondedValidators == nil || !totalReward.IsPositive() {
+		return nil
+	}
+
+	var shares []ValidatorRewardShare
+	totalTokens := sdk.ZeroInt()
+	for _, validator := range k.bondedValidators.GetBondedValidatorsByPower(ctx) {
+		if !eligible[validator.OperatorAddress] || !validator.GetTokens().IsPositive() {
+			continue
+		}
+		valAddr, err := sdk.ValAddressFromBech32(validator.OperatorAddress)
+		if err != nil {
+			k.Logger(ctx).Error("Invalid validator address", "validator", validator.OperatorAddress, "error", err)
+			continue
+		}
+		shares = append(shares, ValidatorRewardShare{Validator: valAddr, Tokens: validator.GetTokens(), Amount: sdk.ZeroInt()})
+		totalTokens = totalTokens.Add(validator.GetTokens())
+	}
+	if len(shares) == 0 {
+		return nil
+	}
+
+	allocated := sdk.ZeroInt()
+	largest := 0
+	for i := range shares {
+		shares[i].Amount = totalReward.Mul(shares[i].Tokens).Quo(totalTokens)
+		allocated = allocated.Add(shares[i].Amount)
+		if shares[i].Tokens.GT(shares[largest].Tokens) {
+			largest = i
+		}
+	}
+	shares[largest].Amount = shares[largest].Amount.Add(totalReward.Sub(allocated))
+
+	return shares
+}
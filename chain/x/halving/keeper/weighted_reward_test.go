@@ -0,0 +1,144 @@
+package keeper_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/keeper"
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// mockBondedValidators lists the bonded validators in the given order
+type mockBondedValidators []stakingtypes.Validator
+
+func (m mockBondedValidators) GetBondedValidatorsByPower(sdk.Context) []stakingtypes.Validator {
+	return m
+}
+
+func bondedValidator(valAddr sdk.ValAddress, tokens int64) stakingtypes.Validator {
+	return stakingtypes.Validator{
+		OperatorAddress: valAddr.String(),
+		Status:          stakingtypes.Bonded,
+		Tokens:          sdk.NewInt(tokens),
+	}
+}
+
+var (
+	testLargeValidator  = sdk.ValAddress([]byte("large-validator"))
+	testMediumValidator = sdk.ValAddress([]byte("medium-validator"))
+	testSmallValidator  = sdk.ValAddress([]byte("small-validator"))
+)
+
+func TestWeightedValidatorReward(t *testing.T) {
+	k, ctx := setupKeeper(t)
+	k.SetBondedValidators(mockBondedValidators{
+		bondedValidator(testLargeValidator, 500),
+		bondedValidator(testMediumValidator, 300),
+		bondedValidator(testSmallValidator, 200),
+	})
+	all := map[string]bool{
+		testLargeValidator.String():  true,
+		testMediumValidator.String(): true,
+		testSmallValidator.String():  true,
+	}
+
+	testCases := []struct {
+		name     string
+		eligible map[string]bool
+		total    int64
+		want     map[string]int64
+	}{
+		{
+			name:     "proportional to tokens",
+			eligible: all,
+			total:    1000,
+			want:     map[string]int64{testLargeValidator.String(): 500, testMediumValidator.String(): 300, testSmallValidator.String(): 200},
+		},
+		{
+			// 500.5, 300.3 and 200.2 truncate to 1000; the remainder goes to the largest stake
+			name:     "remainder to the highest stake",
+			eligible: all,
+			total:    1001,
+			want:     map[string]int64{testLargeValidator.String(): 501, testMediumValidator.String(): 300, testSmallValidator.String(): 200},
+		},
+		{
+			name:     "single validator",
+			eligible: map[string]bool{testSmallValidator.String(): true},
+			total:    999,
+			want:     map[string]int64{testSmallValidator.String(): 999},
+		},
+		{
+			// 3 * 300/500 = 1.8 and 3 * 200/500 = 1.2 truncate to 1 and 1
+			name:     "remainder without the largest validator eligible",
+			eligible: map[string]bool{testMediumValidator.String(): true, testSmallValidator.String(): true},
+			total:    3,
+			want:     map[string]int64{testMediumValidator.String(): 2, testSmallValidator.String(): 1},
+		},
+		{
+			name:     "no validator eligible",
+			eligible: map[string]bool{},
+			total:    1000,
+			want:     map[string]int64{},
+		},
+		{
+			name:     "eligible but no longer bonded",
+			eligible: map[string]bool{sdk.ValAddress([]byte("unbonded-validator")).String(): true},
+			total:    1000,
+			want:     map[string]int64{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			shares := k.WeightedValidatorReward(ctx, tc.eligible, sdk.NewInt(tc.total))
+			got := make(map[string]int64, len(shares))
+			for _, share := range shares {
+				got[share.Validator.String()] = share.Amount.Int64()
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDistributeToActiveValidatorsWeighted(t *testing.T) {
+	bank := &mockBankKeeper{
+		moduleBalance: sdk.NewCoins(sdk.NewInt64Coin(keeper.MainDenom, 1_000_000)),
+		sent:          make(map[string]sdk.Coins),
+	}
+	k, ctx := setupKeeperWithBank(t, bank)
+	k.SetBondedValidators(mockBondedValidators{
+		bondedValidator(testLargeValidator, 600),
+		bondedValidator(testMediumValidator, 300),
+		bondedValidator(testSmallValidator, 100),
+	})
+	info := types.HalvingInfo{CurrentCycle: 1}
+
+	snapshot := func(month uint64, valAddr sdk.ValAddress, eligible bool) {
+		k.SetEligibilitySnapshot(ctx, valAddr, types.EligibilitySnapshot{
+			ValidatorAddress: valAddr.String(),
+			Cycle:            1,
+			Month:            month,
+			Eligible:         eligible,
+			InactiveDays:     sdk.ZeroDec(),
+		})
+	}
+
+	// The inactive validator's tokens do not dilute the others' shares
+	snapshot(1, testLargeValidator, true)
+	snapshot(1, testMediumValidator, false)
+	snapshot(1, testSmallValidator, true)
+	require.NoError(t, k.DistributeToActiveValidators(ctx, sdk.NewInt64Coin(keeper.MainDenom, 7001), info, 1))
+	require.Equal(t, sdk.NewInt(6001), received(bank, testLargeValidator))
+	require.True(t, received(bank, testMediumValidator).IsZero())
+	require.Equal(t, sdk.NewInt(1000), received(bank, testSmallValidator))
+
+	// All validators inactive: the whole share is forfeited
+	for _, valAddr := range []sdk.ValAddress{testLargeValidator, testMediumValidator, testSmallValidator} {
+		snapshot(2, valAddr, false)
+	}
+	require.NoError(t, k.DistributeToActiveValidators(ctx, sdk.NewInt64Coin(keeper.MainDenom, 7000), info, 2))
+	require.Equal(t, int64(1_000_000-7001), bank.moduleBalance.AmountOf(keeper.MainDenom).Int64())
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"unicode/utf8"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// MaxBotVersionLength bounds the bot version a validator registers
+	MaxBotVersionLength = 64
+	// MaxBotContactLength bounds the operator contact a validator registers
+	MaxBotContactLength = 128
+	// MaxBotEndpointURLLength bounds the bot endpoint a validator registers
+	MaxBotEndpointURLLength = 256
+
+	// BotMetadataUpdateIntervalBlocks is how many blocks a validator waits
+	// between two bot metadata updates, about an hour of 6s blocks. The
+	// directory is read by people, so this only keeps it from being spammed.
+	BotMetadataUpdateIntervalBlocks = 600
+)
+
+// ValidateBotMetadataFields checks the fields a validator registers about its
+// bot: a version, an optional contact and an optional http(s) endpoint, each
+// valid UTF-8 within its length limit
+func ValidateBotMetadataFields(version, contact, endpointURL string) error {
+	if version == "" {
+		return errorsmod.Wrap(ErrInvalidBotMetadata, "version cannot be empty")
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"version", version, MaxBotVersionLength},
+		{"contact", contact, MaxBotContactLength},
+		{"endpoint_url", endpointURL, MaxBotEndpointURLLength},
+	} {
+		if len(field.value) > field.max {
+			return errorsmod.Wrapf(ErrInvalidBotMetadata, "%s is %d bytes, max %d", field.name, len(field.value), field.max)
+		}
+		if !utf8.ValidString(field.value) {
+			return errorsmod.Wrapf(ErrInvalidBotMetadata, "%s is not valid UTF-8", field.name)
+		}
+	}
+
+	if endpointURL != "" {
+		u, err := url.Parse(endpointURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return errorsmod.Wrapf(ErrInvalidBotMetadata, "endpoint_url %q is not an http(s) URL", endpointURL)
+		}
+	}
+	return nil
+}
+
+// Validate checks a stored bot metadata entry
+func (m BotMetadata) Validate() error {
+	if _, err := sdk.ValAddressFromBech32(m.ValidatorAddress); err != nil {
+		return fmt.Errorf("invalid bot metadata validator address %s: %w", m.ValidatorAddress, err)
+	}
+	if err := ValidateBotMetadataFields(m.Version, m.Contact, m.EndpointUrl); err != nil {
+		return fmt.Errorf("invalid bot metadata for %s: %w", m.ValidatorAddress, err)
+	}
+	return nil
+}
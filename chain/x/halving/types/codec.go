@@ -0,0 +1,47 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	cdctypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/msgservice"
+	authzcodec "github.com/cosmos/cosmos-sdk/x/authz/codec"
+)
+
+// RegisterLegacyAminoCodec registers the halving messages for amino JSON signing
+func RegisterLegacyAminoCodec(cdc *codec.LegacyAmino) {
+	cdc.RegisterConcrete(&MsgDeclareDowntime{}, "halving/DeclareDowntime", nil)
+	cdc.RegisterConcrete(&MsgWithdrawDexReserve{}, "halving/WithdrawDexReserve", nil)
+	cdc.RegisterConcrete(&MsgRegisterBotHeartbeat{}, "halving/RegisterBotHeartbeat", nil)
+	cdc.RegisterConcrete(&MsgSetBotMetadata{}, "halving/SetBotMetadata", nil)
+	cdc.RegisterConcrete(&MsgUpdateHalvingParams{}, "halving/UpdateHalvingParams", nil)
+}
+
+// RegisterInterfaces registers the halving messages and Msg service
+func RegisterInterfaces(registry cdctypes.InterfaceRegistry) {
+	registry.RegisterImplementations((*sdk.Msg)(nil),
+		&MsgDeclareDowntime{},
+		&MsgWithdrawDexReserve{},
+		&MsgRegisterBotHeartbeat{},
+		&MsgSetBotMetadata{},
+		&MsgUpdateHalvingParams{},
+	)
+
+	msgservice.RegisterMsgServiceDesc(registry, &Msg_ServiceDesc)
+}
+
+var (
+	amino = codec.NewLegacyAmino()
+
+	// ModuleCdc is the amino codec used for legacy sign bytes
+	ModuleCdc = codec.NewAminoCodec(amino)
+)
+
+func init() {
+	RegisterLegacyAminoCodec(amino)
+	amino.Seal()
+
+	// A MsgExec carrying these messages, e.g. from a bot key acting for the
+	// operator, is signed with amino JSON by hardware wallets
+	RegisterLegacyAminoCodec(authzcodec.Amino)
+}
@@ -0,0 +1,142 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+func TestMsgDeclareDowntimeCodecRoundTrip(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	msg := types.NewMsgDeclareDowntime(sdk.ValAddress([]byte("validator-address-01")), start, start.Add(48*time.Hour), "hardware upgrade")
+
+	registry := codectypes.NewInterfaceRegistry()
+	types.RegisterInterfaces(registry)
+	cdc := codec.NewProtoCodec(registry)
+
+	// Proto binary
+	bz, err := cdc.Marshal(msg)
+	require.NoError(t, err)
+	var decoded types.MsgDeclareDowntime
+	require.NoError(t, cdc.Unmarshal(bz, &decoded))
+	require.Equal(t, *msg, decoded)
+
+	// Packed in an Any, as in a tx body
+	any, err := codectypes.NewAnyWithValue(msg)
+	require.NoError(t, err)
+	require.Equal(t, "/gxr.halving.v1beta1.MsgDeclareDowntime", any.TypeUrl)
+	var unpacked sdk.Msg
+	require.NoError(t, registry.UnpackAny(any, &unpacked))
+	require.Equal(t, msg, unpacked)
+
+	// Amino JSON, used for ledger signing
+	amino := codec.NewLegacyAmino()
+	types.RegisterLegacyAminoCodec(amino)
+	jsonBz, err := amino.MarshalJSON(msg)
+	require.NoError(t, err)
+	var fromJSON types.MsgDeclareDowntime
+	require.NoError(t, amino.UnmarshalJSON(jsonBz, &fromJSON))
+	require.Equal(t, *msg, fromJSON)
+	require.Contains(t, string(msg.GetSignBytes()), `"type":"halving/DeclareDowntime"`)
+}
+
+func TestMsgWithdrawDexReserveCodecRoundTrip(t *testing.T) {
+	operator := sdk.AccAddress([]byte("dex-reserve-operator"))
+	pool := sdk.AccAddress([]byte("lp-pool-gxr-ton"))
+	msg := types.NewMsgWithdrawDexReserve(operator, sdk.NewInt64Coin("ugen", 1000), pool)
+	require.NoError(t, msg.ValidateBasic())
+	require.Equal(t, []sdk.AccAddress{operator}, msg.GetSigners())
+
+	registry := codectypes.NewInterfaceRegistry()
+	types.RegisterInterfaces(registry)
+	any, err := codectypes.NewAnyWithValue(msg)
+	require.NoError(t, err)
+	require.Equal(t, "/gxr.halving.v1beta1.MsgWithdrawDexReserve", any.TypeUrl)
+	var unpacked sdk.Msg
+	require.NoError(t, registry.UnpackAny(any, &unpacked))
+	require.Equal(t, msg, unpacked)
+
+	require.Contains(t, string(msg.GetSignBytes()), `"type":"halving/WithdrawDexReserve"`)
+
+	// Zero amounts and bad addresses are rejected before they reach the keeper
+	zero := types.NewMsgWithdrawDexReserve(operator, sdk.NewInt64Coin("ugen", 0), pool)
+	require.ErrorIs(t, zero.ValidateBasic(), types.ErrInvalidDexWithdrawal)
+	badPool := *msg
+	badPool.DestinationPool = "not-an-address"
+	require.Error(t, badPool.ValidateBasic())
+}
+
+func TestMsgRegisterBotHeartbeatCodecRoundTrip(t *testing.T) {
+	valAddr := sdk.ValAddress([]byte("validator-address-01"))
+	msg := types.NewMsgRegisterBotHeartbeat(valAddr)
+	require.NoError(t, msg.ValidateBasic())
+	require.Equal(t, []sdk.AccAddress{sdk.AccAddress(valAddr)}, msg.GetSigners())
+
+	registry := codectypes.NewInterfaceRegistry()
+	types.RegisterInterfaces(registry)
+	any, err := codectypes.NewAnyWithValue(msg)
+	require.NoError(t, err)
+	require.Equal(t, "/gxr.halving.v1beta1.MsgRegisterBotHeartbeat", any.TypeUrl)
+	var unpacked sdk.Msg
+	require.NoError(t, registry.UnpackAny(any, &unpacked))
+	require.Equal(t, msg, unpacked)
+
+	require.Contains(t, string(msg.GetSignBytes()), `"type":"halving/RegisterBotHeartbeat"`)
+
+	bad := *msg
+	bad.ValidatorAddress = "not-an-address"
+	require.Error(t, bad.ValidateBasic())
+}
+
+func TestMsgSetBotMetadataCodecRoundTrip(t *testing.T) {
+	valAddr := sdk.ValAddress([]byte("validator-address-01"))
+	msg := types.NewMsgSetBotMetadata(valAddr, "v1.4.0", "ops@validator.example", "https://bot.validator.example/status")
+	require.NoError(t, msg.ValidateBasic())
+	require.Equal(t, []sdk.AccAddress{sdk.AccAddress(valAddr)}, msg.GetSigners())
+
+	registry := codectypes.NewInterfaceRegistry()
+	types.RegisterInterfaces(registry)
+	any, err := codectypes.NewAnyWithValue(msg)
+	require.NoError(t, err)
+	require.Equal(t, "/gxr.halving.v1beta1.MsgSetBotMetadata", any.TypeUrl)
+	var unpacked sdk.Msg
+	require.NoError(t, registry.UnpackAny(any, &unpacked))
+	require.Equal(t, msg, unpacked)
+
+	require.Contains(t, string(msg.GetSignBytes()), `"type":"halving/SetBotMetadata"`)
+
+	// Contact and endpoint are optional, the version is not
+	require.NoError(t, types.NewMsgSetBotMetadata(valAddr, "v1.4.0", "", "").ValidateBasic())
+	require.ErrorIs(t, types.NewMsgSetBotMetadata(valAddr, "", "", "").ValidateBasic(), types.ErrInvalidBotMetadata)
+	require.ErrorIs(t, types.NewMsgSetBotMetadata(valAddr, "v1.4.0", "", "bot.validator.example").ValidateBasic(), types.ErrInvalidBotMetadata)
+}
+
+func TestMsgUpdateHalvingParamsCodecRoundTrip(t *testing.T) {
+	authority := sdk.AccAddress([]byte("halving-authority"))
+	msg := types.NewMsgUpdateHalvingParams(authority.String(), sdk.MustNewDecFromStr("0.60"), sdk.MustNewDecFromStr("0.30"), sdk.MustNewDecFromStr("0.10"))
+	require.NoError(t, msg.ValidateBasic())
+	require.Equal(t, []sdk.AccAddress{authority}, msg.GetSigners())
+
+	registry := codectypes.NewInterfaceRegistry()
+	types.RegisterInterfaces(registry)
+	any, err := codectypes.NewAnyWithValue(msg)
+	require.NoError(t, err)
+	require.Equal(t, "/gxr.halving.v1beta1.MsgUpdateHalvingParams", any.TypeUrl)
+	var unpacked sdk.Msg
+	require.NoError(t, registry.UnpackAny(any, &unpacked))
+	require.Equal(t, msg, unpacked)
+
+	require.Contains(t, string(msg.GetSignBytes()), `"type":"halving/UpdateHalvingParams"`)
+
+	// Shares that do not add up to 1.0 never reach a proposal
+	over := types.NewMsgUpdateHalvingParams(authority.String(), sdk.MustNewDecFromStr("0.70"), sdk.MustNewDecFromStr("0.30"), sdk.MustNewDecFromStr("0.10"))
+	require.ErrorIs(t, over.ValidateBasic(), types.ErrInvalidShares)
+	negative := types.NewMsgUpdateHalvingParams(authority.String(), sdk.MustNewDecFromStr("1.10"), sdk.MustNewDecFromStr("0.00"), sdk.MustNewDecFromStr("-0.10"))
+	require.ErrorIs(t, negative.ValidateBasic(), types.ErrInvalidShares)
+}
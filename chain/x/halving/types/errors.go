@@ -0,0 +1,26 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// Halving module errors
+var (
+	ErrInvalidDowntimeWindow = errorsmod.Register(ModuleName, 2, "invalid downtime window")
+	ErrRetroactiveDowntime   = errorsmod.Register(ModuleName, 3, "downtime cannot be declared retroactively")
+	ErrDowntimeCapExceeded   = errorsmod.Register(ModuleName, 4, "monthly declared downtime cap exceeded")
+	ErrOverlappingDowntime   = errorsmod.Register(ModuleName, 5, "downtime window overlaps an existing declaration")
+	ErrValidatorNotFound     = errorsmod.Register(ModuleName, 6, "validator not found")
+
+	ErrUnauthorizedDexOperator  = errorsmod.Register(ModuleName, 7, "signer is not the dex reserve operator")
+	ErrDexWithdrawalCapExceeded = errorsmod.Register(ModuleName, 8, "dex reserve withdrawal cap exceeded")
+	ErrDexPoolNotWhitelisted    = errorsmod.Register(ModuleName, 9, "destination is not an active registered LP pool")
+	ErrInsufficientDexReserve   = errorsmod.Register(ModuleName, 10, "insufficient pending dex allocation")
+	ErrInvalidDexWithdrawal     = errorsmod.Register(ModuleName, 11, "invalid dex reserve withdrawal")
+
+	ErrInvalidBotMetadata     = errorsmod.Register(ModuleName, 12, "invalid bot metadata")
+	ErrBotMetadataRateLimited = errorsmod.Register(ModuleName, 13, "bot metadata updated too recently")
+
+	ErrInvalidAuthority = errorsmod.Register(ModuleName, 14, "invalid authority")
+	ErrInvalidShares    = errorsmod.Register(ModuleName, 15, "invalid distribution shares")
+)
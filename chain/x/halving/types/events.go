@@ -0,0 +1,52 @@
+package types
+
+// Halving module event types and attributes
+const (
+	EventTypeDexShareRedirected   = "dex_share_redirected"
+	EventTypeDowntimeDeclared     = "downtime_declared"
+	EventTypeCatchUpDistribution  = "catch_up_distribution"
+	EventTypeEligibilitySnapshot  = "eligibility_snapshot"
+	EventTypeDexReserveWithdrawn  = "dex_reserve_withdrawn"
+	EventTypeEquivocationClawback = "equivocation_clawback"
+	EventTypeClawbackSettled      = "clawback_settled"
+	EventTypeClawbackWrittenOff   = "clawback_written_off"
+	EventTypeBotHeartbeat         = "bot_heartbeat"
+	EventTypeBotMetadataSet       = "bot_metadata_set"
+	EventTypeHalvingParamsUpdated = "halving_params_updated"
+	EventTypeDistributionClamped  = "distribution_clamped"
+
+	AttributeKeyAmount       = "amount"
+	AttributeKeyDestination  = "destination"
+	AttributeKeyCycle        = "cycle"
+	AttributeKeyMonth        = "month"
+	AttributeKeyValidator    = "validator"
+	AttributeKeyStart        = "start"
+	AttributeKeyEnd          = "end"
+	AttributeKeyDays         = "days"
+	AttributeKeyReason       = "reason"
+	AttributeKeyMonthsBehind = "months_behind"
+	AttributeKeyEligible     = "eligible"
+	AttributeKeyValidators   = "validators"
+	AttributeKeyOperator     = "operator"
+	AttributeKeyWithdrawalID = "withdrawal_id"
+	AttributeKeyRemaining    = "remaining"
+	AttributeKeyEpochEnd     = "epoch_end"
+	AttributeKeyHeight       = "infraction_height"
+	AttributeKeyClawback     = "clawback"
+	AttributeKeyWithheld     = "withheld"
+	AttributeKeyWrittenOff   = "written_off"
+	AttributeKeyOutstanding  = "outstanding"
+	AttributeKeyExpiresAt    = "expires_height"
+	AttributeKeyVersion      = "version"
+	AttributeKeyContact      = "contact"
+	AttributeKeyEndpointURL  = "endpoint_url"
+
+	AttributeKeyRecordID       = "record_id"
+	AttributeKeyAuthority      = "authority"
+	AttributeKeyValidatorShare = "validator_share"
+	AttributeKeyDelegatorShare = "delegator_share"
+	AttributeKeyDexShare       = "dex_share"
+
+	AttributeKeyDueAmount     = "due_amount"
+	AttributeKeyClampedAmount = "clamped_amount"
+)
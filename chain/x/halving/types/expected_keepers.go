@@ -0,0 +1,48 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// BondedValidatorSource lists the bonded validators the validator rewards are
+// weighted by, normally the staking keeper
+type BondedValidatorSource interface {
+	GetBondedValidatorsByPower(ctx sdk.Context) []stakingtypes.Validator
+}
+
+// ValidatorSource looks up validators by operator address, normally the
+// staking keeper
+type ValidatorSource interface {
+	GetValidator(ctx sdk.Context, addr sdk.ValAddress) (stakingtypes.Validator, bool)
+}
+
+// LPPoolDistributor distributes coins equally among the active LP pools
+// registered in the feerouter module
+type LPPoolDistributor interface {
+	// DistributeToLPPools sends amount from the given module account to the
+	// active LP pools and returns the coins actually distributed
+	DistributeToLPPools(ctx sdk.Context, fromModule string, amount sdk.Coins) sdk.Coins
+}
+
+// CommunityPoolFunder funds the distribution module's community pool
+type CommunityPoolFunder interface {
+	FundCommunityPool(ctx sdk.Context, amount sdk.Coins, sender sdk.AccAddress) error
+}
+
+// LPPoolRegistry looks up the LP pools registered in the feerouter module
+type LPPoolRegistry interface {
+	// IsActiveLPPool reports whether address is a registered LP pool that is active
+	IsActiveLPPool(ctx sdk.Context, address string) bool
+}
+
+// SlashingKeeper reports the validators tombstoned for double signing
+type SlashingKeeper interface {
+	IsTombstoned(ctx sdk.Context, consAddr sdk.ConsAddress) bool
+}
+
+// CommunityTaxSource reads the share of fees the distribution module keeps
+// for the community pool
+type CommunityTaxSource interface {
+	GetCommunityTax(ctx sdk.Context) sdk.Dec
+}
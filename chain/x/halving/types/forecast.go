@@ -0,0 +1,61 @@
+package types
+
+import (
+	"math"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewSupplyFloorForecast extrapolates the supply trend between the oldest
+// snapshot and the current supply to estimate when the floor is reached.
+//
+// The halving itself burns and re-mints the same amount every month, so it
+// never moves total supply; only burns outside the halving (fees, slashing,
+// explicit burns) show up in the trend.
+func NewSupplyFloorForecast(oldest SupplySnapshot, current sdk.Coin, floor sdk.Int, now time.Time, cycleDuration time.Duration) SupplyFloorForecast {
+	forecast := SupplyFloorForecast{
+		CurrentSupply:        current,
+		FloorSupply:          sdk.NewCoin(current.Denom, floor),
+		BurnedInWindow:       sdk.NewCoin(current.Denom, sdk.ZeroInt()),
+		HalvingSupplyNeutral: true,
+	}
+
+	if current.Amount.LT(floor) {
+		forecast.FloorReachable = true
+		forecast.FloorReached = true
+		forecast.EstimatedFloorAt = now.Unix()
+		return forecast
+	}
+
+	window := now.Unix() - oldest.Timestamp
+	if window <= 0 || oldest.Supply.Denom != current.Denom {
+		return forecast
+	}
+	forecast.WindowSeconds = window
+
+	burned := oldest.Supply.Amount.Sub(current.Amount)
+	if !burned.IsPositive() {
+		// Flat or growing supply: the floor is never reached at this trend
+		return forecast
+	}
+	forecast.BurnedInWindow = sdk.NewCoin(current.Denom, burned)
+
+	// remaining / (burned / window), rounded up to whole seconds
+	remaining := current.Amount.Sub(floor)
+	seconds := remaining.MulRaw(window).Add(burned).SubRaw(1).Quo(burned)
+	if !seconds.IsInt64() || seconds.Int64() > math.MaxInt64-now.Unix() {
+		return forecast
+	}
+
+	forecast.FloorReachable = true
+	forecast.SecondsToFloor = seconds.Int64()
+	forecast.EstimatedFloorAt = now.Unix() + forecast.SecondsToFloor
+
+	cycleSeconds := int64(cycleDuration / time.Second)
+	if cycleSeconds > 0 {
+		forecast.CyclesToFloor = uint64((forecast.SecondsToFloor + cycleSeconds - 1) / cycleSeconds)
+	}
+
+	return forecast
+}
@@ -0,0 +1,59 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+func TestNewSupplyFloorForecast(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := int64(24 * 60 * 60)
+	cycle := 5 * 365 * 24 * time.Hour
+	floor := sdk.NewInt(1000)
+
+	snapshot := func(daysAgo int64, supply int64) types.SupplySnapshot {
+		return types.SupplySnapshot{
+			Timestamp: now.Unix() - daysAgo*day,
+			Supply:    sdk.NewInt64Coin("ugen", supply),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		oldest        types.SupplySnapshot
+		current       int64
+		reachable     bool
+		reached       bool
+		secondsToFlr  int64
+		cyclesToFloor uint64
+	}{
+		{"flat supply never reaches floor", snapshot(30, 1_000_000), 1_000_000, false, false, 0, 0},
+		{"growing supply never reaches floor", snapshot(30, 1_000_000), 1_100_000, false, false, 0, 0},
+		{"no observation window", snapshot(0, 1_000_000), 900_000, false, false, 0, 0},
+		{"already below floor", snapshot(30, 2_000), 999, true, true, 0, 0},
+		// 100 burned per day, 9,000 above the floor -> 90 days
+		{"linear burn", snapshot(10, 11_000), 10_000, true, false, 90 * day, 1},
+		// 1 burned over 10 days, ~1e9 above the floor -> beyond one cycle
+		{"slow burn spans cycles", snapshot(10, 1_000_001_001), 1_000_001_000, true, false, 1_000_000_000 * 10 * day, 5479453},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			forecast := types.NewSupplyFloorForecast(tc.oldest, sdk.NewInt64Coin("ugen", tc.current), floor, now, cycle)
+
+			require.True(t, forecast.HalvingSupplyNeutral)
+			require.Equal(t, tc.reachable, forecast.FloorReachable)
+			require.Equal(t, tc.reached, forecast.FloorReached)
+			require.Equal(t, tc.secondsToFlr, forecast.SecondsToFloor)
+			require.Equal(t, tc.cyclesToFloor, forecast.CyclesToFloor)
+			if tc.reachable && !tc.reached {
+				require.Equal(t, now.Unix()+tc.secondsToFlr, forecast.EstimatedFloorAt)
+			}
+		})
+	}
+}
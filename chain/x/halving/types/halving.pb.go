@@ -21,10 +21,33 @@ import (
 
 // Params defines the parameters for the halving module.
 type Params struct {
-	HalvingCycleDuration time.Duration `protobuf:"bytes,1,opt,name=halving_cycle_duration,json=halvingCycleDuration,proto3,stdduration" json:"halving_cycle_duration"`
-	ValidatorShare       types.Dec     `protobuf:"bytes,2,opt,name=validator_share,json=validatorShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"validator_share"`
-	DelegatorShare       types.Dec     `protobuf:"bytes,3,opt,name=delegator_share,json=delegatorShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"delegator_share"`
-	DexShare             types.Dec     `protobuf:"bytes,4,opt,name=dex_share,json=dexShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"dex_share"`
+	HalvingCycleDuration     time.Duration `protobuf:"bytes,1,opt,name=halving_cycle_duration,json=halvingCycleDuration,proto3,stdduration" json:"halving_cycle_duration"`
+	ValidatorShare           types.Dec     `protobuf:"bytes,2,opt,name=validator_share,json=validatorShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"validator_share"`
+	DelegatorShare           types.Dec     `protobuf:"bytes,3,opt,name=delegator_share,json=delegatorShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"delegator_share"`
+	DexShare                 types.Dec     `protobuf:"bytes,4,opt,name=dex_share,json=dexShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"dex_share"`
+	BotProtocolVersion       uint64        `protobuf:"varint,5,opt,name=bot_protocol_version,json=botProtocolVersion,proto3" json:"bot_protocol_version,omitempty"`
+	MinBotProtocolVersion    uint64        `protobuf:"varint,6,opt,name=min_bot_protocol_version,json=minBotProtocolVersion,proto3" json:"min_bot_protocol_version,omitempty"`
+	DexShareToLPPools        bool          `protobuf:"varint,7,opt,name=dex_share_to_lp_pools,json=dexShareToLpPools,proto3" json:"dex_share_to_lp_pools,omitempty"`
+	PostDexShareDestination  string        `protobuf:"bytes,8,opt,name=post_dex_share_destination,json=postDexShareDestination,proto3" json:"post_dex_share_destination,omitempty"`
+	RoundingStrategy         string        `protobuf:"bytes,9,opt,name=rounding_strategy,json=roundingStrategy,proto3" json:"rounding_strategy,omitempty"`
+	RemainderToPos           bool          `protobuf:"varint,10,opt,name=remainder_to_pos,json=remainderToPos,proto3" json:"remainder_to_pos,omitempty"`
+	MaxDeclaredDowntimeDays  uint64        `protobuf:"varint,11,opt,name=max_declared_downtime_days,json=maxDeclaredDowntimeDays,proto3" json:"max_declared_downtime_days,omitempty"`
+	DeclaredDowntimeWeight   types.Dec     `protobuf:"bytes,12,opt,name=declared_downtime_weight,json=declaredDowntimeWeight,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"declared_downtime_weight"`
+	HeartbeatInterval        time.Duration `protobuf:"bytes,13,opt,name=heartbeat_interval,json=heartbeatInterval,proto3,stdduration" json:"heartbeat_interval"`
+	HeartbeatRetentionMonths uint64        `protobuf:"varint,14,opt,name=heartbeat_retention_months,json=heartbeatRetentionMonths,proto3" json:"heartbeat_retention_months,omitempty"`
+	EligibilitySnapshotDelay time.Duration `protobuf:"bytes,15,opt,name=eligibility_snapshot_delay,json=eligibilitySnapshotDelay,proto3,stdduration" json:"eligibility_snapshot_delay"`
+	DexReserveOperator       string        `protobuf:"bytes,16,opt,name=dex_reserve_operator,json=dexReserveOperator,proto3" json:"dex_reserve_operator,omitempty"`
+	DexWithdrawalCap         types.Int     `protobuf:"bytes,17,opt,name=dex_withdrawal_cap,json=dexWithdrawalCap,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Int" json:"dex_withdrawal_cap"`
+	DexWithdrawalEpoch       time.Duration `protobuf:"bytes,18,opt,name=dex_withdrawal_epoch,json=dexWithdrawalEpoch,proto3,stdduration" json:"dex_withdrawal_epoch"`
+
+	DistributionRecordRetentionCycles uint64 `protobuf:"varint,19,opt,name=distribution_record_retention_cycles,json=distributionRecordRetentionCycles,proto3" json:"distribution_record_retention_cycles,omitempty"`
+	MaxRecordsPrunedPerBlock          uint64 `protobuf:"varint,20,opt,name=max_records_pruned_per_block,json=maxRecordsPrunedPerBlock,proto3" json:"max_records_pruned_per_block,omitempty"`
+	BotHeartbeatTimeoutBlocks         uint64 `protobuf:"varint,21,opt,name=bot_heartbeat_timeout_blocks,json=botHeartbeatTimeoutBlocks,proto3" json:"bot_heartbeat_timeout_blocks,omitempty"`
+
+	MaxMonthlyDistribution            types.Int `protobuf:"bytes,22,opt,name=max_monthly_distribution,json=maxMonthlyDistribution,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Int" json:"max_monthly_distribution"`
+	MaxMonthlyDistributionSupplyRatio types.Dec `protobuf:"bytes,23,opt,name=max_monthly_distribution_supply_ratio,json=maxMonthlyDistributionSupplyRatio,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"max_monthly_distribution_supply_ratio"`
+
+	DexDistributionPeriod time.Duration `protobuf:"bytes,24,opt,name=dex_distribution_period,json=dexDistributionPeriod,proto3,stdduration" json:"dex_distribution_period"`
 }
 
 // HalvingInfo stores information about the current halving cycle
@@ -38,30 +61,267 @@ type HalvingInfo struct {
 	DistributedAmount  types.Coin `protobuf:"bytes,7,opt,name=distributed_amount,json=distributedAmount,proto3" json:"distributed_amount"`
 	PauseStart         int64      `protobuf:"varint,8,opt,name=pause_start,json=pauseStart,proto3" json:"pause_start,omitempty"`
 	LastMonthlyDistrib int64      `protobuf:"varint,9,opt,name=last_monthly_distrib,json=lastMonthlyDistrib,proto3" json:"last_monthly_distrib,omitempty"`
+	MonthsDistributed  uint64     `protobuf:"varint,10,opt,name=months_distributed,json=monthsDistributed,proto3" json:"months_distributed,omitempty"`
+	// Part of the amounts due that the monthly distribution ceiling held
+	// back; it stays in the halving fund and is paid by later months
+	ClampedAmount types.Coin `protobuf:"bytes,11,opt,name=clamped_amount,json=clampedAmount,proto3" json:"clamped_amount"`
 }
 
 // ValidatorUptime tracks validator uptime for reward eligibility
 type ValidatorUptime struct {
-	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
-	CurrentMonth     uint64 `protobuf:"varint,2,opt,name=current_month,json=currentMonth,proto3" json:"current_month,omitempty"`
-	InactiveDays     uint64 `protobuf:"varint,3,opt,name=inactive_days,json=inactiveDays,proto3" json:"inactive_days,omitempty"`
-	LastCheck        int64  `protobuf:"varint,4,opt,name=last_check,json=lastCheck,proto3" json:"last_check,omitempty"`
+	ValidatorAddress     string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	CurrentMonth         uint64 `protobuf:"varint,2,opt,name=current_month,json=currentMonth,proto3" json:"current_month,omitempty"`
+	InactiveDays         uint64 `protobuf:"varint,3,opt,name=inactive_days,json=inactiveDays,proto3" json:"inactive_days,omitempty"`
+	LastCheck            int64  `protobuf:"varint,4,opt,name=last_check,json=lastCheck,proto3" json:"last_check,omitempty"`
+	DeclaredInactiveDays uint64 `protobuf:"varint,5,opt,name=declared_inactive_days,json=declaredInactiveDays,proto3" json:"declared_inactive_days,omitempty"`
 }
 
 // DistributionRecord tracks monthly distributions
 type DistributionRecord struct {
+	Timestamp              int64      `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Amount                 types.Coin `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount"`
+	Cycle                  uint64     `protobuf:"varint,3,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	Month                  uint64     `protobuf:"varint,4,opt,name=month,proto3" json:"month,omitempty"`
+	RedirectedDexAmount    types.Coin `protobuf:"bytes,5,opt,name=redirected_dex_amount,json=redirectedDexAmount,proto3" json:"redirected_dex_amount"`
+	DexRedirectDestination string     `protobuf:"bytes,6,opt,name=dex_redirect_destination,json=dexRedirectDestination,proto3" json:"dex_redirect_destination,omitempty"`
+	CatchUp                bool       `protobuf:"varint,7,opt,name=catch_up,json=catchUp,proto3" json:"catch_up,omitempty"`
+	DistributionMonth      uint64     `protobuf:"varint,8,opt,name=distribution_month,json=distributionMonth,proto3" json:"distribution_month,omitempty"`
+}
+
+// ValidatorRewardLedger tracks the halving rewards of a validator that can
+// still be clawed back, and what the validator owes after an equivocation
+type ValidatorRewardLedger struct {
+	ValidatorAddress string                   `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Payouts          []LedgerPayout           `protobuf:"bytes,2,rep,name=payouts,proto3" json:"payouts"`
+	Balance          types.Int                `protobuf:"bytes,3,opt,name=balance,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Int" json:"balance"`
+	TotalClawedBack  types.Coin               `protobuf:"bytes,4,opt,name=total_clawed_back,json=totalClawedBack,proto3" json:"total_clawed_back"`
+	TotalRecovered   types.Coin               `protobuf:"bytes,5,opt,name=total_recovered,json=totalRecovered,proto3" json:"total_recovered"`
+	Infractions      []EquivocationInfraction `protobuf:"bytes,6,rep,name=infractions,proto3" json:"infractions"`
+}
+
+// LedgerPayout is a validator reward paid in an uptime month
+type LedgerPayout struct {
+	Month             uint64     `protobuf:"varint,1,opt,name=month,proto3" json:"month,omitempty"`
+	Cycle             uint64     `protobuf:"varint,2,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	DistributionMonth uint64     `protobuf:"varint,3,opt,name=distribution_month,json=distributionMonth,proto3" json:"distribution_month,omitempty"`
+	PaidAt            int64      `protobuf:"varint,4,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	Amount            types.Coin `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount"`
+	ClawedBack        bool       `protobuf:"varint,6,opt,name=clawed_back,json=clawedBack,proto3" json:"clawed_back,omitempty"`
+}
+
+// EquivocationInfraction is a double sign of a validator reported by consensus
+type EquivocationInfraction struct {
+	Height         int64      `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Time           int64      `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	Month          uint64     `protobuf:"varint,3,opt,name=month,proto3" json:"month,omitempty"`
+	RecordedHeight int64      `protobuf:"varint,4,opt,name=recorded_height,json=recordedHeight,proto3" json:"recorded_height,omitempty"`
+	Clawback       types.Coin `protobuf:"bytes,5,opt,name=clawback,proto3" json:"clawback"`
+}
+
+// DistributionCycleSummary keeps the totals of the distribution records of a
+// halving cycle that were pruned
+type DistributionCycleSummary struct {
+	Cycle                 uint64     `protobuf:"varint,1,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	Records               uint64     `protobuf:"varint,2,opt,name=records,proto3" json:"records,omitempty"`
+	TotalAmount           types.Coin `protobuf:"bytes,3,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount"`
+	RedirectedDexAmount   types.Coin `protobuf:"bytes,4,opt,name=redirected_dex_amount,json=redirectedDexAmount,proto3" json:"redirected_dex_amount"`
+	CatchUpRecords        uint64     `protobuf:"varint,5,opt,name=catch_up_records,json=catchUpRecords,proto3" json:"catch_up_records,omitempty"`
+	FirstTimestamp        int64      `protobuf:"varint,6,opt,name=first_timestamp,json=firstTimestamp,proto3" json:"first_timestamp,omitempty"`
+	LastTimestamp         int64      `protobuf:"varint,7,opt,name=last_timestamp,json=lastTimestamp,proto3" json:"last_timestamp,omitempty"`
+	LastDistributionMonth uint64     `protobuf:"varint,8,opt,name=last_distribution_month,json=lastDistributionMonth,proto3" json:"last_distribution_month,omitempty"`
+}
+
+// SupplySnapshot records the total supply observed at a point in time
+type SupplySnapshot struct {
 	Timestamp int64      `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Amount    types.Coin `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount"`
-	Cycle     uint64     `protobuf:"varint,3,opt,name=cycle,proto3" json:"cycle,omitempty"`
-	Month     uint64     `protobuf:"varint,4,opt,name=month,proto3" json:"month,omitempty"`
+	Height    int64      `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Supply    types.Coin `protobuf:"bytes,3,opt,name=supply,proto3" json:"supply"`
+}
+
+// SupplyFloorForecast estimates when the total supply reaches the minimum threshold
+type SupplyFloorForecast struct {
+	CurrentSupply        types.Coin `protobuf:"bytes,1,opt,name=current_supply,json=currentSupply,proto3" json:"current_supply"`
+	FloorSupply          types.Coin `protobuf:"bytes,2,opt,name=floor_supply,json=floorSupply,proto3" json:"floor_supply"`
+	BurnedInWindow       types.Coin `protobuf:"bytes,3,opt,name=burned_in_window,json=burnedInWindow,proto3" json:"burned_in_window"`
+	WindowSeconds        int64      `protobuf:"varint,4,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	FloorReachable       bool       `protobuf:"varint,5,opt,name=floor_reachable,json=floorReachable,proto3" json:"floor_reachable,omitempty"`
+	FloorReached         bool       `protobuf:"varint,6,opt,name=floor_reached,json=floorReached,proto3" json:"floor_reached,omitempty"`
+	SecondsToFloor       int64      `protobuf:"varint,7,opt,name=seconds_to_floor,json=secondsToFloor,proto3" json:"seconds_to_floor,omitempty"`
+	EstimatedFloorAt     int64      `protobuf:"varint,8,opt,name=estimated_floor_at,json=estimatedFloorAt,proto3" json:"estimated_floor_at,omitempty"`
+	CyclesToFloor        uint64     `protobuf:"varint,9,opt,name=cycles_to_floor,json=cyclesToFloor,proto3" json:"cycles_to_floor,omitempty"`
+	HalvingSupplyNeutral bool       `protobuf:"varint,10,opt,name=halving_supply_neutral,json=halvingSupplyNeutral,proto3" json:"halving_supply_neutral,omitempty"`
+}
+
+// PendingDexAllocation accumulates the DEX share of a cycle left for the bot to distribute
+type PendingDexAllocation struct {
+	Cycle          uint64     `protobuf:"varint,1,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	Amount         types.Coin `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount"`
+	TotalAllocated types.Coin `protobuf:"bytes,3,opt,name=total_allocated,json=totalAllocated,proto3" json:"total_allocated"`
+	Months         uint64     `protobuf:"varint,4,opt,name=months,proto3" json:"months,omitempty"`
+	LastUpdated    int64      `protobuf:"varint,5,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+}
+
+// DowntimeDeclaration is a planned downtime window declared by a validator operator
+type DowntimeDeclaration struct {
+	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Start            int64  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End              int64  `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	Reason           string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Month            uint64 `protobuf:"varint,5,opt,name=month,proto3" json:"month,omitempty"`
+	Days             uint64 `protobuf:"varint,6,opt,name=days,proto3" json:"days,omitempty"`
+	DeclaredAt       int64  `protobuf:"varint,7,opt,name=declared_at,json=declaredAt,proto3" json:"declared_at,omitempty"`
+}
+
+// ValidatorHeartbeat is the latest bot heartbeat of a validator
+type ValidatorHeartbeat struct {
+	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Timestamp        int64  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Height           int64  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+// BotMetadata is the registered version, contact and endpoint of a validator's bot
+type BotMetadata struct {
+	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Version          string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Contact          string `protobuf:"bytes,3,opt,name=contact,proto3" json:"contact,omitempty"`
+	EndpointUrl      string `protobuf:"bytes,4,opt,name=endpoint_url,json=endpointUrl,proto3" json:"endpoint_url,omitempty"`
+	UpdatedHeight    int64  `protobuf:"varint,5,opt,name=updated_height,json=updatedHeight,proto3" json:"updated_height,omitempty"`
+	UpdatedTime      int64  `protobuf:"varint,6,opt,name=updated_time,json=updatedTime,proto3" json:"updated_time,omitempty"`
+}
+
+// HeartbeatBitmap records in which heartbeat intervals of an uptime month a validator sent a heartbeat
+type HeartbeatBitmap struct {
+	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Month            uint64 `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	IntervalSeconds  int64  `protobuf:"varint,3,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	Intervals        uint64 `protobuf:"varint,4,opt,name=intervals,proto3" json:"intervals,omitempty"`
+	Bitmap           []byte `protobuf:"bytes,5,opt,name=bitmap,proto3" json:"bitmap,omitempty"`
+}
+
+// EligibilitySnapshot is the reward eligibility of a bonded validator for a distribution month
+type EligibilitySnapshot struct {
+	ValidatorAddress string    `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Cycle            uint64    `protobuf:"varint,2,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	Month            uint64    `protobuf:"varint,3,opt,name=month,proto3" json:"month,omitempty"`
+	Eligible         bool      `protobuf:"varint,4,opt,name=eligible,proto3" json:"eligible,omitempty"`
+	InactiveDays     types.Dec `protobuf:"bytes,5,opt,name=inactive_days,json=inactiveDays,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"inactive_days"`
+	TakenAt          int64     `protobuf:"varint,6,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`
+	Height           int64     `protobuf:"varint,7,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+// DexReserveWithdrawal is a withdrawal of the pending DEX share to a registered LP pool
+type DexReserveWithdrawal struct {
+	Id              uint64     `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Operator        string     `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	DestinationPool string     `protobuf:"bytes,3,opt,name=destination_pool,json=destinationPool,proto3" json:"destination_pool,omitempty"`
+	Amount          types.Coin `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount"`
+	EpochStart      int64      `protobuf:"varint,5,opt,name=epoch_start,json=epochStart,proto3" json:"epoch_start,omitempty"`
+	Height          int64      `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	Timestamp       int64      `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+// ParamChangeRecord records a governance update of the distribution shares
+type ParamChangeRecord struct {
+	Id                     uint64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Height                 int64     `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Timestamp              int64     `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Authority              string    `protobuf:"bytes,4,opt,name=authority,proto3" json:"authority,omitempty"`
+	PreviousValidatorShare types.Dec `protobuf:"bytes,5,opt,name=previous_validator_share,json=previousValidatorShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"previous_validator_share"`
+	PreviousDelegatorShare types.Dec `protobuf:"bytes,6,opt,name=previous_delegator_share,json=previousDelegatorShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"previous_delegator_share"`
+	PreviousDexShare       types.Dec `protobuf:"bytes,7,opt,name=previous_dex_share,json=previousDexShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"previous_dex_share"`
+	ValidatorShare         types.Dec `protobuf:"bytes,8,opt,name=validator_share,json=validatorShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"validator_share"`
+	DelegatorShare         types.Dec `protobuf:"bytes,9,opt,name=delegator_share,json=delegatorShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"delegator_share"`
+	DexShare               types.Dec `protobuf:"bytes,10,opt,name=dex_share,json=dexShare,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"dex_share"`
+}
+
+// DexWithdrawalEpoch is what the operator withdrew in the current cap window
+type DexWithdrawalEpoch struct {
+	Start     int64      `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End       int64      `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	Withdrawn types.Coin `protobuf:"bytes,3,opt,name=withdrawn,proto3" json:"withdrawn"`
+}
+
+// RewardEstimate estimates what a validator and an additional delegation to it
+// receive from the next distribution month
+type RewardEstimate struct {
+	Estimate               bool       `protobuf:"varint,1,opt,name=estimate,proto3" json:"estimate,omitempty"`
+	ValidatorAddress       string     `protobuf:"bytes,2,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	DistributionMonth      uint64     `protobuf:"varint,3,opt,name=distribution_month,json=distributionMonth,proto3" json:"distribution_month,omitempty"`
+	DistributionTime       int64      `protobuf:"varint,4,opt,name=distribution_time,json=distributionTime,proto3" json:"distribution_time,omitempty"`
+	MonthsRemaining        uint64     `protobuf:"varint,5,opt,name=months_remaining,json=monthsRemaining,proto3" json:"months_remaining,omitempty"`
+	MonthlyAmount          types.Coin `protobuf:"bytes,6,opt,name=monthly_amount,json=monthlyAmount,proto3" json:"monthly_amount"`
+	DexWindowOpen          bool       `protobuf:"varint,7,opt,name=dex_window_open,json=dexWindowOpen,proto3" json:"dex_window_open,omitempty"`
+	DexRedirectDestination string     `protobuf:"bytes,8,opt,name=dex_redirect_destination,json=dexRedirectDestination,proto3" json:"dex_redirect_destination,omitempty"`
+	ValidatorEligible      bool       `protobuf:"varint,9,opt,name=validator_eligible,json=validatorEligible,proto3" json:"validator_eligible,omitempty"`
+	EligibleValidators     uint64     `protobuf:"varint,10,opt,name=eligible_validators,json=eligibleValidators,proto3" json:"eligible_validators,omitempty"`
+	ValidatorReward        types.Coin `protobuf:"bytes,11,opt,name=validator_reward,json=validatorReward,proto3" json:"validator_reward"`
+	DelegatorPool          types.Coin `protobuf:"bytes,12,opt,name=delegator_pool,json=delegatorPool,proto3" json:"delegator_pool"`
+	VotingPowerFraction    types.Dec  `protobuf:"bytes,13,opt,name=voting_power_fraction,json=votingPowerFraction,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"voting_power_fraction"`
+	CommunityTax           types.Dec  `protobuf:"bytes,14,opt,name=community_tax,json=communityTax,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"community_tax"`
+	CommissionRate         types.Dec  `protobuf:"bytes,15,opt,name=commission_rate,json=commissionRate,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"commission_rate"`
+	ValidatorCommission    types.Coin `protobuf:"bytes,16,opt,name=validator_commission,json=validatorCommission,proto3" json:"validator_commission"`
+	DelegatorsReward       types.Coin `protobuf:"bytes,17,opt,name=delegators_reward,json=delegatorsReward,proto3" json:"delegators_reward"`
+	AdditionalDelegation   types.Coin `protobuf:"bytes,18,opt,name=additional_delegation,json=additionalDelegation,proto3" json:"additional_delegation"`
+	DelegationReward       types.Coin `protobuf:"bytes,19,opt,name=delegation_reward,json=delegationReward,proto3" json:"delegation_reward"`
+	Assumptions            []string   `protobuf:"bytes,20,rep,name=assumptions,proto3" json:"assumptions,omitempty"`
+}
+
+// DistributionSimulation is what the next unpaid distribution month pays
+// when it is distributed on the state and at the block time it was simulated on
+type DistributionSimulation struct {
+	Due                    bool                    `protobuf:"varint,1,opt,name=due,proto3" json:"due,omitempty"`
+	CatchUp                bool                    `protobuf:"varint,2,opt,name=catch_up,json=catchUp,proto3" json:"catch_up,omitempty"`
+	Cycle                  uint64                  `protobuf:"varint,3,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	DistributionMonth      uint64                  `protobuf:"varint,4,opt,name=distribution_month,json=distributionMonth,proto3" json:"distribution_month,omitempty"`
+	SimulatedAt            int64                   `protobuf:"varint,5,opt,name=simulated_at,json=simulatedAt,proto3" json:"simulated_at,omitempty"`
+	Height                 int64                   `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	MonthlyAmount          types.Coin              `protobuf:"bytes,7,opt,name=monthly_amount,json=monthlyAmount,proto3" json:"monthly_amount"`
+	RemainingFund          types.Coin              `protobuf:"bytes,8,opt,name=remaining_fund,json=remainingFund,proto3" json:"remaining_fund"`
+	Validators             types.Coin              `protobuf:"bytes,9,opt,name=validators,proto3" json:"validators"`
+	Delegators             types.Coin              `protobuf:"bytes,10,opt,name=delegators,proto3" json:"delegators"`
+	Dex                    types.Coin              `protobuf:"bytes,11,opt,name=dex,proto3" json:"dex"`
+	Redirected             types.Coin              `protobuf:"bytes,12,opt,name=redirected,proto3" json:"redirected"`
+	DexRedirectDestination string                  `protobuf:"bytes,13,opt,name=dex_redirect_destination,json=dexRedirectDestination,proto3" json:"dex_redirect_destination,omitempty"`
+	EligibilitySnapshotted bool                    `protobuf:"varint,14,opt,name=eligibility_snapshotted,json=eligibilitySnapshotted,proto3" json:"eligibility_snapshotted,omitempty"`
+	ValidatorPayouts       []ValidatorPayout       `protobuf:"bytes,15,rep,name=validator_payouts,json=validatorPayouts,proto3" json:"validator_payouts"`
+	Exclusions             []DistributionExclusion `protobuf:"bytes,16,rep,name=exclusions,proto3" json:"exclusions"`
+	ValidatorsForfeited    types.Coin              `protobuf:"bytes,17,opt,name=validators_forfeited,json=validatorsForfeited,proto3" json:"validators_forfeited"`
+}
+
+// ValidatorPayout is a validator's part of the validator share by its bonded tokens
+type ValidatorPayout struct {
+	ValidatorAddress string     `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	AccountAddress   string     `protobuf:"bytes,2,opt,name=account_address,json=accountAddress,proto3" json:"account_address,omitempty"`
+	Amount           types.Coin `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount"`
+	Withheld         types.Coin `protobuf:"bytes,4,opt,name=withheld,proto3" json:"withheld"`
+}
+
+// DistributionExclusion is a bonded validator not paid the validator reward
+// of a distribution month
+type DistributionExclusion struct {
+	ValidatorAddress string    `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Reason           string    `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	InactiveDays     types.Dec `protobuf:"bytes,3,opt,name=inactive_days,json=inactiveDays,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"inactive_days"`
 }
 
 // GenesisState defines the halving module's genesis state.
 type GenesisState struct {
-	Params              Params               `protobuf:"bytes,1,opt,name=params,proto3" json:"params"`
-	HalvingInfo         HalvingInfo          `protobuf:"bytes,2,opt,name=halving_info,json=halvingInfo,proto3" json:"halving_info"`
-	DistributionRecords []DistributionRecord `protobuf:"bytes,3,rep,name=distribution_records,json=distributionRecords,proto3" json:"distribution_records"`
-	ValidatorUptimes    []ValidatorUptime    `protobuf:"bytes,4,rep,name=validator_uptimes,json=validatorUptimes,proto3" json:"validator_uptimes"`
+	Params                Params                 `protobuf:"bytes,1,opt,name=params,proto3" json:"params"`
+	HalvingInfo           HalvingInfo            `protobuf:"bytes,2,opt,name=halving_info,json=halvingInfo,proto3" json:"halving_info"`
+	DistributionRecords   []DistributionRecord   `protobuf:"bytes,3,rep,name=distribution_records,json=distributionRecords,proto3" json:"distribution_records"`
+	ValidatorUptimes      []ValidatorUptime      `protobuf:"bytes,4,rep,name=validator_uptimes,json=validatorUptimes,proto3" json:"validator_uptimes"`
+	SupplySnapshots       []SupplySnapshot       `protobuf:"bytes,5,rep,name=supply_snapshots,json=supplySnapshots,proto3" json:"supply_snapshots"`
+	DowntimeDeclarations  []DowntimeDeclaration  `protobuf:"bytes,6,rep,name=downtime_declarations,json=downtimeDeclarations,proto3" json:"downtime_declarations"`
+	ValidatorHeartbeats   []ValidatorHeartbeat   `protobuf:"bytes,7,rep,name=validator_heartbeats,json=validatorHeartbeats,proto3" json:"validator_heartbeats"`
+	HeartbeatBitmaps      []HeartbeatBitmap      `protobuf:"bytes,8,rep,name=heartbeat_bitmaps,json=heartbeatBitmaps,proto3" json:"heartbeat_bitmaps"`
+	EligibilitySnapshots  []EligibilitySnapshot  `protobuf:"bytes,9,rep,name=eligibility_snapshots,json=eligibilitySnapshots,proto3" json:"eligibility_snapshots"`
+	DexReserveWithdrawals []DexReserveWithdrawal `protobuf:"bytes,10,rep,name=dex_reserve_withdrawals,json=dexReserveWithdrawals,proto3" json:"dex_reserve_withdrawals"`
+	DexWithdrawalEpoch    DexWithdrawalEpoch     `protobuf:"bytes,11,opt,name=dex_withdrawal_epoch,json=dexWithdrawalEpoch,proto3" json:"dex_withdrawal_epoch"`
+
+	DistributionCycleSummaries []DistributionCycleSummary `protobuf:"bytes,12,rep,name=distribution_cycle_summaries,json=distributionCycleSummaries,proto3" json:"distribution_cycle_summaries"`
+	ValidatorRewardLedgers     []ValidatorRewardLedger    `protobuf:"bytes,13,rep,name=validator_reward_ledgers,json=validatorRewardLedgers,proto3" json:"validator_reward_ledgers"`
+	BotMetadata                []BotMetadata              `protobuf:"bytes,14,rep,name=bot_metadata,json=botMetadata,proto3" json:"bot_metadata"`
+	ParamChangeRecords         []ParamChangeRecord        `protobuf:"bytes,15,rep,name=param_change_records,json=paramChangeRecords,proto3" json:"param_change_records"`
 }
 
 func (m *Params) Reset()         { *m = Params{} }
@@ -99,12 +359,164 @@ func (*GenesisState) Descriptor() ([]byte, []int) {
 	return fileDescriptor_halving, []int{4}
 }
 
+func (m *SupplySnapshot) Reset()         { *m = SupplySnapshot{} }
+func (m *SupplySnapshot) String() string { return proto.CompactTextString(m) }
+func (*SupplySnapshot) ProtoMessage()    {}
+func (*SupplySnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{5}
+}
+
+func (m *SupplyFloorForecast) Reset()         { *m = SupplyFloorForecast{} }
+func (m *SupplyFloorForecast) String() string { return proto.CompactTextString(m) }
+func (*SupplyFloorForecast) ProtoMessage()    {}
+func (*SupplyFloorForecast) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{6}
+}
+
+func (m *PendingDexAllocation) Reset()         { *m = PendingDexAllocation{} }
+func (m *PendingDexAllocation) String() string { return proto.CompactTextString(m) }
+func (*PendingDexAllocation) ProtoMessage()    {}
+func (*PendingDexAllocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{7}
+}
+
+func (m *DowntimeDeclaration) Reset()         { *m = DowntimeDeclaration{} }
+func (m *DowntimeDeclaration) String() string { return proto.CompactTextString(m) }
+func (*DowntimeDeclaration) ProtoMessage()    {}
+func (*DowntimeDeclaration) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{8}
+}
+
+func (m *ValidatorHeartbeat) Reset()         { *m = ValidatorHeartbeat{} }
+func (m *ValidatorHeartbeat) String() string { return proto.CompactTextString(m) }
+func (*ValidatorHeartbeat) ProtoMessage()    {}
+func (*ValidatorHeartbeat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{9}
+}
+
+func (m *HeartbeatBitmap) Reset()         { *m = HeartbeatBitmap{} }
+func (m *HeartbeatBitmap) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatBitmap) ProtoMessage()    {}
+func (*HeartbeatBitmap) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{10}
+}
+
+func (m *EligibilitySnapshot) Reset()         { *m = EligibilitySnapshot{} }
+func (m *EligibilitySnapshot) String() string { return proto.CompactTextString(m) }
+func (*EligibilitySnapshot) ProtoMessage()    {}
+func (*EligibilitySnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{11}
+}
+
+func (m *DexReserveWithdrawal) Reset()         { *m = DexReserveWithdrawal{} }
+func (m *DexReserveWithdrawal) String() string { return proto.CompactTextString(m) }
+func (*DexReserveWithdrawal) ProtoMessage()    {}
+func (*DexReserveWithdrawal) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{12}
+}
+
+func (m *DexWithdrawalEpoch) Reset()         { *m = DexWithdrawalEpoch{} }
+func (m *DexWithdrawalEpoch) String() string { return proto.CompactTextString(m) }
+func (*DexWithdrawalEpoch) ProtoMessage()    {}
+func (*DexWithdrawalEpoch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{13}
+}
+
+func (m *RewardEstimate) Reset()         { *m = RewardEstimate{} }
+func (m *RewardEstimate) String() string { return proto.CompactTextString(m) }
+func (*RewardEstimate) ProtoMessage()    {}
+func (*RewardEstimate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{14}
+}
+
+func (m *DistributionSimulation) Reset()         { *m = DistributionSimulation{} }
+func (m *DistributionSimulation) String() string { return proto.CompactTextString(m) }
+func (*DistributionSimulation) ProtoMessage()    {}
+func (*DistributionSimulation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{15}
+}
+
+func (m *ValidatorPayout) Reset()         { *m = ValidatorPayout{} }
+func (m *ValidatorPayout) String() string { return proto.CompactTextString(m) }
+func (*ValidatorPayout) ProtoMessage()    {}
+func (*ValidatorPayout) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{16}
+}
+
+func (m *DistributionExclusion) Reset()         { *m = DistributionExclusion{} }
+func (m *DistributionExclusion) String() string { return proto.CompactTextString(m) }
+func (*DistributionExclusion) ProtoMessage()    {}
+func (*DistributionExclusion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{17}
+}
+
+func (m *DistributionCycleSummary) Reset()         { *m = DistributionCycleSummary{} }
+func (m *DistributionCycleSummary) String() string { return proto.CompactTextString(m) }
+func (*DistributionCycleSummary) ProtoMessage()    {}
+func (*DistributionCycleSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{18}
+}
+
+func (m *ValidatorRewardLedger) Reset()         { *m = ValidatorRewardLedger{} }
+func (m *ValidatorRewardLedger) String() string { return proto.CompactTextString(m) }
+func (*ValidatorRewardLedger) ProtoMessage()    {}
+func (*ValidatorRewardLedger) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{19}
+}
+
+func (m *LedgerPayout) Reset()         { *m = LedgerPayout{} }
+func (m *LedgerPayout) String() string { return proto.CompactTextString(m) }
+func (*LedgerPayout) ProtoMessage()    {}
+func (*LedgerPayout) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{20}
+}
+
+func (m *EquivocationInfraction) Reset()         { *m = EquivocationInfraction{} }
+func (m *EquivocationInfraction) String() string { return proto.CompactTextString(m) }
+func (*EquivocationInfraction) ProtoMessage()    {}
+func (*EquivocationInfraction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{21}
+}
+
+func (m *BotMetadata) Reset()         { *m = BotMetadata{} }
+func (m *BotMetadata) String() string { return proto.CompactTextString(m) }
+func (*BotMetadata) ProtoMessage()    {}
+func (*BotMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{22}
+}
+
+func (m *ParamChangeRecord) Reset()         { *m = ParamChangeRecord{} }
+func (m *ParamChangeRecord) String() string { return proto.CompactTextString(m) }
+func (*ParamChangeRecord) ProtoMessage()    {}
+func (*ParamChangeRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_halving, []int{23}
+}
+
 func init() {
 	proto.RegisterType((*Params)(nil), "gxr.halving.Params")
 	proto.RegisterType((*HalvingInfo)(nil), "gxr.halving.HalvingInfo")
 	proto.RegisterType((*ValidatorUptime)(nil), "gxr.halving.ValidatorUptime")
 	proto.RegisterType((*DistributionRecord)(nil), "gxr.halving.DistributionRecord")
 	proto.RegisterType((*GenesisState)(nil), "gxr.halving.GenesisState")
+	proto.RegisterType((*SupplySnapshot)(nil), "gxr.halving.SupplySnapshot")
+	proto.RegisterType((*SupplyFloorForecast)(nil), "gxr.halving.SupplyFloorForecast")
+	proto.RegisterType((*PendingDexAllocation)(nil), "gxr.halving.PendingDexAllocation")
+	proto.RegisterType((*DowntimeDeclaration)(nil), "gxr.halving.DowntimeDeclaration")
+	proto.RegisterType((*ValidatorHeartbeat)(nil), "gxr.halving.ValidatorHeartbeat")
+	proto.RegisterType((*HeartbeatBitmap)(nil), "gxr.halving.HeartbeatBitmap")
+	proto.RegisterType((*EligibilitySnapshot)(nil), "gxr.halving.EligibilitySnapshot")
+	proto.RegisterType((*DexReserveWithdrawal)(nil), "gxr.halving.DexReserveWithdrawal")
+	proto.RegisterType((*DexWithdrawalEpoch)(nil), "gxr.halving.DexWithdrawalEpoch")
+	proto.RegisterType((*RewardEstimate)(nil), "gxr.halving.RewardEstimate")
+	proto.RegisterType((*DistributionSimulation)(nil), "gxr.halving.DistributionSimulation")
+	proto.RegisterType((*ValidatorPayout)(nil), "gxr.halving.ValidatorPayout")
+	proto.RegisterType((*DistributionExclusion)(nil), "gxr.halving.DistributionExclusion")
+	proto.RegisterType((*DistributionCycleSummary)(nil), "gxr.halving.DistributionCycleSummary")
+	proto.RegisterType((*ValidatorRewardLedger)(nil), "gxr.halving.ValidatorRewardLedger")
+	proto.RegisterType((*LedgerPayout)(nil), "gxr.halving.LedgerPayout")
+	proto.RegisterType((*EquivocationInfraction)(nil), "gxr.halving.EquivocationInfraction")
+	proto.RegisterType((*BotMetadata)(nil), "gxr.halving.BotMetadata")
+	proto.RegisterType((*ParamChangeRecord)(nil), "gxr.halving.ParamChangeRecord")
 }
 
 var fileDescriptor_halving = []byte{
@@ -114,11 +526,46 @@ var fileDescriptor_halving = []byte{
 // DefaultGenesisState returns a default genesis state
 func DefaultGenesisState() *GenesisState {
 	return &GenesisState{
-		Params:              DefaultParams(),
-		HalvingInfo:         HalvingInfo{},
-		DistributionRecords: []DistributionRecord{},
-		ValidatorUptimes:    []ValidatorUptime{},
+		Params:                DefaultParams(),
+		HalvingInfo:           HalvingInfo{},
+		DistributionRecords:   []DistributionRecord{},
+		ValidatorUptimes:      []ValidatorUptime{},
+		SupplySnapshots:       []SupplySnapshot{},
+		DowntimeDeclarations:  []DowntimeDeclaration{},
+		ValidatorHeartbeats:   []ValidatorHeartbeat{},
+		HeartbeatBitmaps:      []HeartbeatBitmap{},
+		EligibilitySnapshots:  []EligibilitySnapshot{},
+		DexReserveWithdrawals: []DexReserveWithdrawal{},
+
+		DistributionCycleSummaries: []DistributionCycleSummary{},
+		ValidatorRewardLedgers:     []ValidatorRewardLedger{},
+		BotMetadata:                []BotMetadata{},
+		ParamChangeRecords:         []ParamChangeRecord{},
+	}
+}
+
+// FirstCycleFund is the fund of the first halving cycle: 4,250,000 GXR in
+// ugen, 20% of the 21,250,000 GXR halving reserve held by the module account.
+// Every later cycle is funded from the same reserve with 15% less than the
+// previous cycle.
+const FirstCycleFund = 425000000000000
+
+// CycleFund returns the fund the current cycle started with: what has been
+// distributed plus what is left
+func (h HalvingInfo) CycleFund() types.Coin {
+	if h.DistributedAmount.IsNil() {
+		return h.HalvingFund
 	}
+	return h.HalvingFund.Add(h.DistributedAmount)
+}
+
+// Clamped returns the amount held back by the distribution ceiling, zero
+// for state written before it was tracked
+func (h HalvingInfo) Clamped() types.Int {
+	if h.ClampedAmount.IsNil() {
+		return types.ZeroInt()
+	}
+	return h.ClampedAmount.Amount
 }
 
 // DefaultHalvingInfo returns default halving info for genesis
@@ -126,7 +573,7 @@ func DefaultHalvingInfo() HalvingInfo {
 	// GXR Total Supply: 85,000,000 GXR
 	// Halving Fund: 21,250,000 GXR (25% of total supply)
 	// First cycle allocation: 4,250,000 GXR (20% of halving fund)
-	totalFunds := types.NewCoin("ugen", types.NewInt(425000000000000)) // 4,250,000 GXR in ugen
+	totalFunds := types.NewCoin("ugen", types.NewInt(FirstCycleFund))
 	
 	return HalvingInfo{
 		CurrentCycle:       1,
@@ -154,5 +601,144 @@ func (gs GenesisState) Validate() error {
 		return fmt.Errorf("invalid cycle start time: %d", gs.HalvingInfo.CycleStartTime)
 	}
 	
+	if gs.HalvingInfo.MonthsDistributed > DistributionMonths {
+		return fmt.Errorf("invalid months distributed: %d, at most %d", gs.HalvingInfo.MonthsDistributed, DistributionMonths)
+	}
+	
+	if clamped := gs.HalvingInfo.Clamped(); clamped.IsNegative() {
+		return fmt.Errorf("invalid clamped amount: %s", clamped)
+	}
+	
+	seenUptimes := make(map[string]bool, len(gs.ValidatorUptimes))
+	for _, uptime := range gs.ValidatorUptimes {
+		if _, err := types.ValAddressFromBech32(uptime.ValidatorAddress); err != nil {
+			return fmt.Errorf("invalid uptime validator address %s: %w", uptime.ValidatorAddress, err)
+		}
+		if seenUptimes[uptime.ValidatorAddress] {
+			return fmt.Errorf("duplicate uptime record for %s", uptime.ValidatorAddress)
+		}
+		seenUptimes[uptime.ValidatorAddress] = true
+	}
+	
+	for _, declaration := range gs.DowntimeDeclarations {
+		if _, err := types.ValAddressFromBech32(declaration.ValidatorAddress); err != nil {
+			return fmt.Errorf("invalid downtime declaration validator address %s: %w", declaration.ValidatorAddress, err)
+		}
+		if declaration.End <= declaration.Start {
+			return fmt.Errorf("invalid downtime declaration for %s: end %d must be after start %d",
+				declaration.ValidatorAddress, declaration.End, declaration.Start)
+		}
+	}
+	
+	for _, heartbeat := range gs.ValidatorHeartbeats {
+		if _, err := types.ValAddressFromBech32(heartbeat.ValidatorAddress); err != nil {
+			return fmt.Errorf("invalid heartbeat validator address %s: %w", heartbeat.ValidatorAddress, err)
+		}
+	}
+	
+	for _, bitmap := range gs.HeartbeatBitmaps {
+		if _, err := types.ValAddressFromBech32(bitmap.ValidatorAddress); err != nil {
+			return fmt.Errorf("invalid heartbeat bitmap validator address %s: %w", bitmap.ValidatorAddress, err)
+		}
+		if err := bitmap.Validate(); err != nil {
+			return err
+		}
+	}
+	
+	for _, snapshot := range gs.EligibilitySnapshots {
+		if _, err := types.ValAddressFromBech32(snapshot.ValidatorAddress); err != nil {
+			return fmt.Errorf("invalid eligibility snapshot validator address %s: %w", snapshot.ValidatorAddress, err)
+		}
+		if snapshot.Month == 0 || snapshot.Month > DistributionMonths {
+			return fmt.Errorf("invalid eligibility snapshot month %d for %s, must be between 1 and %d",
+				snapshot.Month, snapshot.ValidatorAddress, DistributionMonths)
+		}
+	}
+	
+	seenWithdrawals := make(map[uint64]bool, len(gs.DexReserveWithdrawals))
+	for _, withdrawal := range gs.DexReserveWithdrawals {
+		if withdrawal.Id == 0 || seenWithdrawals[withdrawal.Id] {
+			return fmt.Errorf("invalid or duplicate dex reserve withdrawal id %d", withdrawal.Id)
+		}
+		seenWithdrawals[withdrawal.Id] = true
+		if _, err := types.AccAddressFromBech32(withdrawal.Operator); err != nil {
+			return fmt.Errorf("invalid dex reserve withdrawal %d operator %s: %w", withdrawal.Id, withdrawal.Operator, err)
+		}
+		if _, err := types.AccAddressFromBech32(withdrawal.DestinationPool); err != nil {
+			return fmt.Errorf("invalid dex reserve withdrawal %d destination pool %s: %w", withdrawal.Id, withdrawal.DestinationPool, err)
+		}
+		if !withdrawal.Amount.IsValid() || !withdrawal.Amount.IsPositive() {
+			return fmt.Errorf("invalid dex reserve withdrawal %d amount %s", withdrawal.Id, withdrawal.Amount)
+		}
+	}
+	
+	if epoch := gs.DexWithdrawalEpoch; epoch.Start != 0 || epoch.End != 0 {
+		if epoch.End <= epoch.Start {
+			return fmt.Errorf("invalid dex withdrawal epoch: end %d must be after start %d", epoch.End, epoch.Start)
+		}
+		if !epoch.Withdrawn.IsValid() {
+			return fmt.Errorf("invalid dex withdrawal epoch withdrawn amount %s", epoch.Withdrawn)
+		}
+	}
+	
+	seenSummaries := make(map[uint64]bool, len(gs.DistributionCycleSummaries))
+	for _, summary := range gs.DistributionCycleSummaries {
+		if summary.Cycle == 0 || seenSummaries[summary.Cycle] {
+			return fmt.Errorf("invalid or duplicate distribution cycle summary cycle %d", summary.Cycle)
+		}
+		seenSummaries[summary.Cycle] = true
+		if !summary.TotalAmount.IsValid() || !summary.RedirectedDexAmount.IsValid() {
+			return fmt.Errorf("invalid distribution cycle summary amounts for cycle %d", summary.Cycle)
+		}
+		if summary.LastDistributionMonth > DistributionMonths {
+			return fmt.Errorf("invalid distribution cycle summary month %d for cycle %d, at most %d",
+				summary.LastDistributionMonth, summary.Cycle, DistributionMonths)
+		}
+	}
+	
+	seenLedgers := make(map[string]bool, len(gs.ValidatorRewardLedgers))
+	for _, ledger := range gs.ValidatorRewardLedgers {
+		if _, err := types.ValAddressFromBech32(ledger.ValidatorAddress); err != nil {
+			return fmt.Errorf("invalid reward ledger validator address %s: %w", ledger.ValidatorAddress, err)
+		}
+		if seenLedgers[ledger.ValidatorAddress] {
+			return fmt.Errorf("duplicate reward ledger for %s", ledger.ValidatorAddress)
+		}
+		seenLedgers[ledger.ValidatorAddress] = true
+		if ledger.Balance.IsNil() || ledger.Balance.IsPositive() {
+			return fmt.Errorf("invalid reward ledger balance for %s, must be zero or negative", ledger.ValidatorAddress)
+		}
+		if !ledger.TotalClawedBack.IsValid() || !ledger.TotalRecovered.IsValid() {
+			return fmt.Errorf("invalid reward ledger totals for %s", ledger.ValidatorAddress)
+		}
+		for _, payout := range ledger.Payouts {
+			if !payout.Amount.IsValid() {
+				return fmt.Errorf("invalid reward ledger payout %s for %s", payout.Amount, ledger.ValidatorAddress)
+			}
+		}
+	}
+	
+	seenMetadata := make(map[string]bool, len(gs.BotMetadata))
+	for _, metadata := range gs.BotMetadata {
+		if err := metadata.Validate(); err != nil {
+			return err
+		}
+		if seenMetadata[metadata.ValidatorAddress] {
+			return fmt.Errorf("duplicate bot metadata for %s", metadata.ValidatorAddress)
+		}
+		seenMetadata[metadata.ValidatorAddress] = true
+	}
+	
+	seenParamChanges := make(map[uint64]bool, len(gs.ParamChangeRecords))
+	for _, record := range gs.ParamChangeRecords {
+		if record.Id == 0 || seenParamChanges[record.Id] {
+			return fmt.Errorf("invalid or duplicate param change record id %d", record.Id)
+		}
+		seenParamChanges[record.Id] = true
+		if err := ValidateShares(record.ValidatorShare, record.DelegatorShare, record.DexShare); err != nil {
+			return fmt.Errorf("invalid param change record %d: %w", record.Id, err)
+		}
+	}
+	
 	return nil
 }
\ No newline at end of file
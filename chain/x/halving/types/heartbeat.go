@@ -0,0 +1,92 @@
+package types
+
+import (
+	"fmt"
+	"math/bits"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewHeartbeatBitmap returns an empty bitmap for a validator's uptime month,
+// with one bit per heartbeat interval of intervalSeconds in a month of
+// monthSeconds. A trailing partial interval gets its own bit.
+func NewHeartbeatBitmap(validatorAddress string, month uint64, intervalSeconds, monthSeconds int64) HeartbeatBitmap {
+	intervals := uint64((monthSeconds + intervalSeconds - 1) / intervalSeconds)
+	return HeartbeatBitmap{
+		ValidatorAddress: validatorAddress,
+		Month:            month,
+		IntervalSeconds:  intervalSeconds,
+		Intervals:        intervals,
+		Bitmap:           make([]byte, (intervals+7)/8),
+	}
+}
+
+// IntervalAt returns the interval containing a point offset seconds into the month
+func (b HeartbeatBitmap) IntervalAt(offset int64) uint64 {
+	if offset < 0 || b.IntervalSeconds <= 0 {
+		return 0
+	}
+	return uint64(offset / b.IntervalSeconds)
+}
+
+// Set marks an interval as covered and reports whether the bit changed.
+// Intervals outside the month are ignored.
+func (b *HeartbeatBitmap) Set(interval uint64) bool {
+	if interval >= b.Intervals || b.IsSet(interval) {
+		return false
+	}
+	b.Bitmap[interval/8] |= 1 << (interval % 8)
+	return true
+}
+
+// IsSet reports whether a heartbeat was recorded in the interval
+func (b HeartbeatBitmap) IsSet(interval uint64) bool {
+	if interval >= b.Intervals || interval/8 >= uint64(len(b.Bitmap)) {
+		return false
+	}
+	return b.Bitmap[interval/8]&(1<<(interval%8)) != 0
+}
+
+// Present returns the number of covered intervals among the first n
+func (b HeartbeatBitmap) Present(n uint64) uint64 {
+	if n > b.Intervals {
+		n = b.Intervals
+	}
+
+	var present uint64
+	full := n / 8
+	for i := uint64(0); i < full && i < uint64(len(b.Bitmap)); i++ {
+		present += uint64(bits.OnesCount8(b.Bitmap[i]))
+	}
+	for interval := full * 8; interval < n; interval++ {
+		if b.IsSet(interval) {
+			present++
+		}
+	}
+	return present
+}
+
+// CoveragePercent returns the covered share of the first n intervals in
+// percent, e.g. 99.5 for 4298 of 4320 intervals
+func (b HeartbeatBitmap) CoveragePercent(n uint64) sdk.Dec {
+	if n > b.Intervals {
+		n = b.Intervals
+	}
+	if n == 0 {
+		return sdk.ZeroDec()
+	}
+	return sdk.NewDec(int64(b.Present(n))).MulInt64(100).QuoInt64(int64(n))
+}
+
+// Validate checks that the bitmap is sized for its interval count
+func (b HeartbeatBitmap) Validate() error {
+	if b.IntervalSeconds <= 0 {
+		return fmt.Errorf("heartbeat bitmap of %s for month %d has non-positive interval %d",
+			b.ValidatorAddress, b.Month, b.IntervalSeconds)
+	}
+	if uint64(len(b.Bitmap)) != (b.Intervals+7)/8 {
+		return fmt.Errorf("heartbeat bitmap of %s for month %d has %d bytes, %d intervals need %d",
+			b.ValidatorAddress, b.Month, len(b.Bitmap), b.Intervals, (b.Intervals+7)/8)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+// monthSeconds is the 30-day uptime month used by the keeper
+const monthSeconds = int64(30 * 24 * time.Hour / time.Second)
+
+func TestHeartbeatBitmapSetAndDecode(t *testing.T) {
+	interval := int64(types.DefaultHeartbeatInterval / time.Second)
+	bitmap := types.NewHeartbeatBitmap("gxrvaloper1test", 7, interval, monthSeconds)
+
+	// Ten-minute intervals over 30 days fit in 540 bytes
+	require.Equal(t, uint64(4320), bitmap.Intervals)
+	require.Len(t, bitmap.Bitmap, 540)
+	require.NoError(t, bitmap.Validate())
+
+	require.Equal(t, uint64(0), bitmap.IntervalAt(interval-1))
+	require.Equal(t, uint64(1), bitmap.IntervalAt(interval))
+	require.Equal(t, uint64(4319), bitmap.IntervalAt(monthSeconds-1))
+
+	for _, interval := range []uint64{0, 7, 8, 100, 4319} {
+		require.True(t, bitmap.Set(interval), interval)
+	}
+	// Setting twice or outside the month changes nothing
+	require.False(t, bitmap.Set(7))
+	require.False(t, bitmap.Set(4320))
+
+	require.True(t, bitmap.IsSet(0))
+	require.True(t, bitmap.IsSet(8))
+	require.False(t, bitmap.IsSet(9))
+	require.False(t, bitmap.IsSet(4320))
+	require.Equal(t, byte(0x81), bitmap.Bitmap[0])
+
+	require.Equal(t, uint64(5), bitmap.Present(bitmap.Intervals))
+	require.Equal(t, uint64(3), bitmap.Present(9))
+	require.Equal(t, uint64(2), bitmap.Present(8))
+	require.Equal(t, uint64(5), bitmap.Present(10000))
+
+	require.True(t, sdk.MustNewDecFromStr("30").Equal(bitmap.CoveragePercent(10)))
+	require.True(t, sdk.ZeroDec().Equal(bitmap.CoveragePercent(0)))
+}
+
+func TestHeartbeatBitmapCoverage(t *testing.T) {
+	bitmap := types.NewHeartbeatBitmap("gxrvaloper1test", 7, 600, monthSeconds)
+	for i := uint64(0); i < bitmap.Intervals; i++ {
+		if i%200 != 0 {
+			bitmap.Set(i)
+		}
+	}
+
+	// 22 of 4320 intervals missed
+	require.Equal(t, uint64(4298), bitmap.Present(bitmap.Intervals))
+	require.Equal(t, "99.490740740740740741", bitmap.CoveragePercent(bitmap.Intervals).String())
+}
+
+func TestHeartbeatBitmapPartialInterval(t *testing.T) {
+	// Seven-minute intervals leave a partial interval at the end of the month
+	bitmap := types.NewHeartbeatBitmap("gxrvaloper1test", 7, 420, monthSeconds)
+	require.Equal(t, uint64(6172), bitmap.Intervals)
+	require.Len(t, bitmap.Bitmap, 772)
+	require.Equal(t, uint64(6171), bitmap.IntervalAt(monthSeconds-1))
+	require.True(t, bitmap.Set(6171))
+	require.Equal(t, uint64(1), bitmap.Present(bitmap.Intervals))
+
+	bitmap.Bitmap = bitmap.Bitmap[:771]
+	require.Error(t, bitmap.Validate())
+}
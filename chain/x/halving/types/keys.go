@@ -1,16 +1,79 @@
 package types
 
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/address"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
 var (
 	// Keys for store
 	CurrentHalvingKey     = []byte("current_halving")
-	LastDistributionKey   = []byte("last_distribution")
 	ValidatorUptimeKey    = []byte("validator_uptime")
+	SupplySnapshotKey     = []byte("supply_snapshot")
+
+	// DistributionRecordKey prefixes the distribution records, ordered by
+	// timestamp. Its bytes predate the records and are kept for existing stores.
+	DistributionRecordKey = []byte("last_distribution")
+
+	// LastDistributionTimeKey stores the time of the last monthly
+	// distribution. It must not start with DistributionRecordKey, whose
+	// iterations would decode it as a record; before consensus version 7 it
+	// was stored under DistributionRecordKey itself.
+	LastDistributionTimeKey = []byte("last_distributed_at")
+
+	// PendingDexAllocationKey prefixes the per-cycle DEX share awaiting the bot
+	PendingDexAllocationKey = []byte("pending_dex_allocation")
+
+	// DowntimeDeclarationKey prefixes declared downtime windows per validator
+	DowntimeDeclarationKey = []byte("downtime_declaration")
+
+	// LastHeartbeatKey prefixes the latest bot heartbeat per validator
+	LastHeartbeatKey = []byte("last_heartbeat")
+
+	// HeartbeatBitmapKey prefixes the monthly heartbeat bitmaps, ordered by month
+	HeartbeatBitmapKey = []byte("heartbeat_bitmap")
+
+	// EligibilitySnapshotKey prefixes the reward eligibility snapshots,
+	// ordered by cycle and distribution month
+	EligibilitySnapshotKey = []byte("eligibility_snapshot")
+
+	// DexReserveWithdrawalKey prefixes the DEX reserve withdrawals, ordered by id
+	DexReserveWithdrawalKey = []byte("dex_reserve_withdrawal")
+
+	// DexWithdrawalEpochKey stores what was withdrawn in the current cap window
+	DexWithdrawalEpochKey = []byte("dex_withdrawal_epoch")
+
+	// DistributionCycleSummaryKey prefixes the totals of the pruned
+	// distribution records per cycle
+	DistributionCycleSummaryKey = []byte("distribution_cycle_summary")
+
+	// ValidatorRewardLedgerKey prefixes the reward ledger per validator
+	ValidatorRewardLedgerKey = []byte("validator_reward_ledger")
+
+	// ParamsKey stores the module params, kept in the x/params subspace
+	// before consensus version 2
+	ParamsKey = []byte("params")
+
+	// BotLivenessStartKey stores the height bot liveness tracking started at
+	BotLivenessStartKey = []byte("bot_liveness_start")
+
+	// BotMetadataKey prefixes the registered bot metadata per validator
+	BotMetadataKey = []byte("bot_metadata")
+
+	// ParamChangeRecordKey prefixes the governance changes of the
+	// distribution shares, ordered by id
+	ParamChangeRecordKey = []byte("param_change_record")
 )
 
 const (
 	// ModuleName is the name of the halving module
 	ModuleName = "halving"
 	
+	// DexDistributionAccountName is the module account holding the DEX share
+	// of the DEX window of a distribution period until it is withdrawn
+	DexDistributionAccountName = "dex_distribution"
+	
 	// StoreKey is the store key string for the halving module
 	StoreKey = ModuleName
 	
@@ -19,4 +82,96 @@ const (
 	
 	// QuerierRoute is the querier route for the halving module
 	QuerierRoute = ModuleName
-)
\ No newline at end of file
+	
+	// DistributionMonths is the number of monthly distributions in a distribution period
+	DistributionMonths = 24
+	
+	// ConsensusVersion is the consensus version of the halving store. Every
+	// bump needs a migration from the previous version in keeper.Migrator.
+	ConsensusVersion = 8
+)
+
+// GetPendingDexAllocationKey returns the store key for a cycle's pending DEX allocation
+func GetPendingDexAllocationKey(cycle uint64) []byte {
+	return append(append([]byte{}, PendingDexAllocationKey...), sdk.Uint64ToBigEndian(cycle)...)
+}
+
+// GetDowntimeDeclarationsPrefix returns the store prefix of a validator's downtime declarations
+func GetDowntimeDeclarationsPrefix(valAddr sdk.ValAddress) []byte {
+	return append(append([]byte{}, DowntimeDeclarationKey...), address.MustLengthPrefix(valAddr)...)
+}
+
+// GetDowntimeDeclarationKey returns the store key of a downtime declaration,
+// ordered by window start
+func GetDowntimeDeclarationKey(valAddr sdk.ValAddress, start int64) []byte {
+	return append(GetDowntimeDeclarationsPrefix(valAddr), sdk.Uint64ToBigEndian(uint64(start))...)
+}
+
+// GetLastHeartbeatKey returns the store key of a validator's latest heartbeat
+func GetLastHeartbeatKey(valAddr sdk.ValAddress) []byte {
+	return append(append([]byte{}, LastHeartbeatKey...), address.MustLengthPrefix(valAddr)...)
+}
+
+// GetBotMetadataKey returns the store key of a validator's bot metadata
+func GetBotMetadataKey(valAddr sdk.ValAddress) []byte {
+	return append(append([]byte{}, BotMetadataKey...), address.MustLengthPrefix(valAddr)...)
+}
+
+// GetParamChangeRecordKey returns the store key of a param change record
+func GetParamChangeRecordKey(id uint64) []byte {
+	return append(append([]byte{}, ParamChangeRecordKey...), sdk.Uint64ToBigEndian(id)...)
+}
+
+// GetHeartbeatBitmapMonthPrefix returns the store prefix of the heartbeat
+// bitmaps of an uptime month. Months are big-endian so that all bitmaps
+// before a month can be pruned with one range iteration.
+func GetHeartbeatBitmapMonthPrefix(month uint64) []byte {
+	return append(append([]byte{}, HeartbeatBitmapKey...), sdk.Uint64ToBigEndian(month)...)
+}
+
+// GetHeartbeatBitmapKey returns the store key of a validator's heartbeat bitmap for an uptime month
+func GetHeartbeatBitmapKey(month uint64, valAddr sdk.ValAddress) []byte {
+	return append(GetHeartbeatBitmapMonthPrefix(month), address.MustLengthPrefix(valAddr)...)
+}
+
+// GetEligibilitySnapshotMonthPrefix returns the store prefix of the
+// eligibility snapshot of a distribution month. Cycle and month are
+// big-endian so that the snapshots before a month form one range.
+func GetEligibilitySnapshotMonthPrefix(cycle, month uint64) []byte {
+	key := append(append([]byte{}, EligibilitySnapshotKey...), sdk.Uint64ToBigEndian(cycle)...)
+	return append(key, sdk.Uint64ToBigEndian(month)...)
+}
+
+// GetEligibilitySnapshotKey returns the store key of a validator's eligibility for a distribution month
+func GetEligibilitySnapshotKey(cycle, month uint64, valAddr sdk.ValAddress) []byte {
+	return append(GetEligibilitySnapshotMonthPrefix(cycle, month), address.MustLengthPrefix(valAddr)...)
+}
+
+// GetDexReserveWithdrawalKey returns the store key of a DEX reserve withdrawal
+func GetDexReserveWithdrawalKey(id uint64) []byte {
+	return append(append([]byte{}, DexReserveWithdrawalKey...), sdk.Uint64ToBigEndian(id)...)
+}
+
+// GetDistributionRecordKey returns the store key of a distribution record,
+// ordered by timestamp so that the oldest records are pruned first
+func GetDistributionRecordKey(timestamp int64) []byte {
+	return append(append([]byte{}, DistributionRecordKey...), sdk.Uint64ToBigEndian(uint64(timestamp))...)
+}
+
+// GetDistributionCycleSummaryKey returns the store key of a cycle's pruned distribution totals
+func GetDistributionCycleSummaryKey(cycle uint64) []byte {
+	return append(append([]byte{}, DistributionCycleSummaryKey...), sdk.Uint64ToBigEndian(cycle)...)
+}
+
+// GetValidatorRewardLedgerKey returns the store key of a validator's reward ledger
+func GetValidatorRewardLedgerKey(valAddr sdk.ValAddress) []byte {
+	return append(append([]byte{}, ValidatorRewardLedgerKey...), address.MustLengthPrefix(valAddr)...)
+}
+
+// ModuleAccountPermissions lists the module accounts the halving keeper moves
+// coins out of, with the permissions each one needs. The app asserts at
+// startup that all of them are registered.
+var ModuleAccountPermissions = map[string][]string{
+	ModuleName:                 {authtypes.Minter, authtypes.Burner},
+	DexDistributionAccountName: nil,
+}
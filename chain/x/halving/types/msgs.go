@@ -0,0 +1,246 @@
+package types
+
+import (
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	// TypeMsgDeclareDowntime is the type of MsgDeclareDowntime
+	TypeMsgDeclareDowntime = "declare_downtime"
+
+	// TypeMsgWithdrawDexReserve is the type of MsgWithdrawDexReserve
+	TypeMsgWithdrawDexReserve = "withdraw_dex_reserve"
+
+	// TypeMsgRegisterBotHeartbeat is the type of MsgRegisterBotHeartbeat
+	TypeMsgRegisterBotHeartbeat = "register_bot_heartbeat"
+
+	// TypeMsgSetBotMetadata is the type of MsgSetBotMetadata
+	TypeMsgSetBotMetadata = "set_bot_metadata"
+
+	// TypeMsgUpdateHalvingParams is the type of MsgUpdateHalvingParams
+	TypeMsgUpdateHalvingParams = "update_halving_params"
+
+	// MaxDowntimeReasonLength bounds the reason stored with a downtime declaration
+	MaxDowntimeReasonLength = 256
+)
+
+var (
+	_ sdk.Msg = &MsgDeclareDowntime{}
+	_ sdk.Msg = &MsgWithdrawDexReserve{}
+	_ sdk.Msg = &MsgRegisterBotHeartbeat{}
+	_ sdk.Msg = &MsgSetBotMetadata{}
+	_ sdk.Msg = &MsgUpdateHalvingParams{}
+)
+
+// NewMsgDeclareDowntime creates a new MsgDeclareDowntime
+func NewMsgDeclareDowntime(valAddr sdk.ValAddress, start, end time.Time, reason string) *MsgDeclareDowntime {
+	return &MsgDeclareDowntime{
+		ValidatorAddress: valAddr.String(),
+		Start:            start.Unix(),
+		End:              end.Unix(),
+		Reason:           reason,
+	}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgDeclareDowntime) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgDeclareDowntime) Type() string { return TypeMsgDeclareDowntime }
+
+// GetSigners returns the validator operator account
+func (msg MsgDeclareDowntime) GetSigners() []sdk.AccAddress {
+	valAddr, err := sdk.ValAddressFromBech32(msg.ValidatorAddress)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sdk.AccAddress(valAddr)}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgDeclareDowntime) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic performs stateless checks. Whether the window is in the past
+// or exceeds the monthly cap depends on block time and params and is checked
+// by the keeper.
+func (msg MsgDeclareDowntime) ValidateBasic() error {
+	if _, err := sdk.ValAddressFromBech32(msg.ValidatorAddress); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid validator address: %s", err)
+	}
+	if msg.Start <= 0 || msg.End <= msg.Start {
+		return errorsmod.Wrapf(ErrInvalidDowntimeWindow, "end %d must be after start %d", msg.End, msg.Start)
+	}
+	if msg.Reason == "" {
+		return errorsmod.Wrap(ErrInvalidDowntimeWindow, "reason cannot be empty")
+	}
+	if len(msg.Reason) > MaxDowntimeReasonLength {
+		return errorsmod.Wrapf(ErrInvalidDowntimeWindow, "reason is %d bytes, max %d", len(msg.Reason), MaxDowntimeReasonLength)
+	}
+	return nil
+}
+
+// NewMsgWithdrawDexReserve creates a new MsgWithdrawDexReserve
+func NewMsgWithdrawDexReserve(operator sdk.AccAddress, amount sdk.Coin, destinationPool sdk.AccAddress) *MsgWithdrawDexReserve {
+	return &MsgWithdrawDexReserve{
+		Operator:        operator.String(),
+		Amount:          amount,
+		DestinationPool: destinationPool.String(),
+	}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgWithdrawDexReserve) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgWithdrawDexReserve) Type() string { return TypeMsgWithdrawDexReserve }
+
+// GetSigners returns the DEX reserve operator account
+func (msg MsgWithdrawDexReserve) GetSigners() []sdk.AccAddress {
+	operator, err := sdk.AccAddressFromBech32(msg.Operator)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{operator}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgWithdrawDexReserve) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic performs stateless checks. Whether the signer is the
+// operator, the pool is registered and the cap allows the amount depends on
+// state and is checked by the keeper.
+func (msg MsgWithdrawDexReserve) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Operator); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid operator address: %s", err)
+	}
+	if _, err := sdk.AccAddressFromBech32(msg.DestinationPool); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid destination pool address: %s", err)
+	}
+	if !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+		return errorsmod.Wrapf(ErrInvalidDexWithdrawal, "amount must be positive: %s", msg.Amount)
+	}
+	return nil
+}
+
+// NewMsgRegisterBotHeartbeat creates a new MsgRegisterBotHeartbeat
+func NewMsgRegisterBotHeartbeat(valAddr sdk.ValAddress) *MsgRegisterBotHeartbeat {
+	return &MsgRegisterBotHeartbeat{ValidatorAddress: valAddr.String()}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgRegisterBotHeartbeat) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgRegisterBotHeartbeat) Type() string { return TypeMsgRegisterBotHeartbeat }
+
+// GetSigners returns the validator operator account
+func (msg MsgRegisterBotHeartbeat) GetSigners() []sdk.AccAddress {
+	valAddr, err := sdk.ValAddressFromBech32(msg.ValidatorAddress)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sdk.AccAddress(valAddr)}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgRegisterBotHeartbeat) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic performs stateless checks. Whether the validator exists is
+// checked by the keeper.
+func (msg MsgRegisterBotHeartbeat) ValidateBasic() error {
+	if _, err := sdk.ValAddressFromBech32(msg.ValidatorAddress); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid validator address: %s", err)
+	}
+	return nil
+}
+
+// NewMsgSetBotMetadata creates a new MsgSetBotMetadata
+func NewMsgSetBotMetadata(valAddr sdk.ValAddress, version, contact, endpointURL string) *MsgSetBotMetadata {
+	return &MsgSetBotMetadata{
+		ValidatorAddress: valAddr.String(),
+		Version:          version,
+		Contact:          contact,
+		EndpointUrl:      endpointURL,
+	}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgSetBotMetadata) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgSetBotMetadata) Type() string { return TypeMsgSetBotMetadata }
+
+// GetSigners returns the validator operator account
+func (msg MsgSetBotMetadata) GetSigners() []sdk.AccAddress {
+	valAddr, err := sdk.ValAddressFromBech32(msg.ValidatorAddress)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sdk.AccAddress(valAddr)}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgSetBotMetadata) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic performs stateless checks. Whether the validator exists and
+// may update its metadata yet is checked by the keeper.
+func (msg MsgSetBotMetadata) ValidateBasic() error {
+	if _, err := sdk.ValAddressFromBech32(msg.ValidatorAddress); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid validator address: %s", err)
+	}
+	return ValidateBotMetadataFields(msg.Version, msg.Contact, msg.EndpointUrl)
+}
+
+// NewMsgUpdateHalvingParams creates a new MsgUpdateHalvingParams
+func NewMsgUpdateHalvingParams(authority string, validatorShare, delegatorShare, dexShare sdk.Dec) *MsgUpdateHalvingParams {
+	return &MsgUpdateHalvingParams{
+		Authority:      authority,
+		ValidatorShare: validatorShare,
+		DelegatorShare: delegatorShare,
+		DexShare:       dexShare,
+	}
+}
+
+// Route implements the legacy Msg interface
+func (msg MsgUpdateHalvingParams) Route() string { return RouterKey }
+
+// Type implements the legacy Msg interface
+func (msg MsgUpdateHalvingParams) Type() string { return TypeMsgUpdateHalvingParams }
+
+// GetSigners returns the governance authority
+func (msg MsgUpdateHalvingParams) GetSigners() []sdk.AccAddress {
+	authority, err := sdk.AccAddressFromBech32(msg.Authority)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{authority}
+}
+
+// GetSignBytes returns the amino JSON sign bytes
+func (msg MsgUpdateHalvingParams) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+// ValidateBasic performs stateless checks, including that the shares add up
+// to 1.0. Whether the signer is the authority is checked by the msg server.
+func (msg MsgUpdateHalvingParams) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errorsmod.Wrapf(sdkerrors.ErrInvalidAddress, "invalid authority address: %s", err)
+	}
+	if err := ValidateShares(msg.ValidatorShare, msg.DelegatorShare, msg.DexShare); err != nil {
+		return errorsmod.Wrap(ErrInvalidShares, err.Error())
+	}
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
 	"gopkg.in/yaml.v2"
+
+	"github.com/Crocodile-ark/gxrchaind/internal/rounding"
 )
 
 // Parameter store keys
@@ -15,6 +17,37 @@ var (
 	KeyValidatorShare       = []byte("ValidatorShare")
 	KeyDelegatorShare       = []byte("DelegatorShare")
 	KeyDexShare            = []byte("DexShare")
+	KeyBotProtocolVersion      = []byte("BotProtocolVersion")
+	KeyMinBotProtocolVersion   = []byte("MinBotProtocolVersion")
+	KeyDexShareToLPPools       = []byte("DexShareToLPPools")
+	KeyPostDexShareDestination = []byte("PostDexShareDestination")
+	KeyRoundingStrategy        = []byte("RoundingStrategy")
+	KeyRemainderToPos          = []byte("RemainderToPos")
+	KeyMaxDeclaredDowntimeDays = []byte("MaxDeclaredDowntimeDays")
+	KeyDeclaredDowntimeWeight  = []byte("DeclaredDowntimeWeight")
+	KeyHeartbeatInterval       = []byte("HeartbeatInterval")
+	KeyHeartbeatRetentionMonths = []byte("HeartbeatRetentionMonths")
+	KeyEligibilitySnapshotDelay = []byte("EligibilitySnapshotDelay")
+	KeyDexReserveOperator       = []byte("DexReserveOperator")
+	KeyDexWithdrawalCap         = []byte("DexWithdrawalCap")
+	KeyDexWithdrawalEpoch       = []byte("DexWithdrawalEpoch")
+
+	KeyDistributionRecordRetentionCycles = []byte("DistributionRecordRetentionCycles")
+	KeyMaxRecordsPrunedPerBlock          = []byte("MaxRecordsPrunedPerBlock")
+	KeyBotHeartbeatTimeoutBlocks         = []byte("BotHeartbeatTimeoutBlocks")
+
+	KeyMaxMonthlyDistribution            = []byte("MaxMonthlyDistribution")
+	KeyMaxMonthlyDistributionSupplyRatio = []byte("MaxMonthlyDistributionSupplyRatio")
+
+	KeyDexDistributionPeriod = []byte("DexDistributionPeriod")
+)
+
+// Destinations for the DEX share once the DEX distribution window has closed
+const (
+	PostDexShareToValidators    = "validators"
+	PostDexShareToDelegators    = "delegators"
+	PostDexShareToCommunityPool = "community_pool"
+	PostDexShareBurn            = "burn"
 )
 
 // Default parameter values
@@ -23,19 +56,103 @@ const (
 	DefaultValidatorShare       = "0.70"                   // 70%
 	DefaultDelegatorShare       = "0.20"                   // 20%
 	DefaultDexShare            = "0.10"                   // 10%
+	DefaultBotProtocolVersion      = uint64(1)
+	DefaultMinBotProtocolVersion   = uint64(1)
+	DefaultDexShareToLPPools       = false
+	DefaultPostDexShareDestination = PostDexShareToValidators
+	DefaultRoundingStrategy        = rounding.DefaultStrategy
+	DefaultRemainderToPos          = true
+	DefaultMaxDeclaredDowntimeDays = uint64(3)
+	DefaultDeclaredDowntimeWeight  = "0.5"
+	DefaultHeartbeatInterval       = 10 * time.Minute
+	DefaultHeartbeatRetentionMonths = uint64(3)
+	DefaultEligibilitySnapshotDelay = time.Duration(0)
+	DefaultDexReserveOperator       = ""
+	DefaultDexWithdrawalCap         = 500_000_000_000 // 5,000 GXR in ugen
+	DefaultDexWithdrawalEpoch       = 7 * 24 * time.Hour
+
+	DefaultDistributionRecordRetentionCycles = uint64(1)
+	DefaultMaxRecordsPrunedPerBlock          = uint64(10)
+	DefaultBotHeartbeatTimeoutBlocks         = uint64(50) // about 5 minutes of 6s blocks
+
+	// The first cycle pays 177,083 GXR a month, about 0.21% of the supply
+	DefaultMaxMonthlyDistribution            = 25_000_000_000_000 // 250,000 GXR in ugen
+	DefaultMaxMonthlyDistributionSupplyRatio = "0.005"            // 0.5% of the current supply
+
+	// The DEX share is paid in years 1 and 2, the whole distribution period
+	DefaultDexDistributionPeriod = MaxDexDistributionPeriod
+)
+
+// MaxDeclarableDowntimeDays is the upper bound for max_declared_downtime_days (one month)
+const MaxDeclarableDowntimeDays = 30
+
+// Bounds of the heartbeat params. The interval is one bit of the monthly
+// heartbeat bitmap, so a one-minute interval costs 5,400 bytes per validator
+// and month.
+const (
+	MinHeartbeatInterval        = time.Minute
+	MaxHeartbeatInterval        = 24 * time.Hour
+	MaxHeartbeatRetentionMonths = 24
 )
 
+// MaxEligibilitySnapshotDelay bounds eligibility_snapshot_delay so that the
+// snapshot is always taken within the 30-day distribution month
+const MaxEligibilitySnapshotDelay = 29 * 24 * time.Hour
+
+// Bounds of dex_withdrawal_epoch
+const (
+	MinDexWithdrawalEpoch = time.Hour
+	MaxDexWithdrawalEpoch = 365 * 24 * time.Hour
+)
+
+// MaxDexDistributionPeriod bounds dex_distribution_period to the two-year
+// distribution period, after which nothing is distributed at all
+const MaxDexDistributionPeriod = 730 * 24 * time.Hour
+
+// MaxPrunedRecordsPerBlockLimit bounds max_records_pruned_per_block, so that
+// pruning never dominates a block
+const MaxPrunedRecordsPerBlockLimit = 100
+
+// MaxBotHeartbeatTimeoutBlocks bounds bot_heartbeat_timeout_blocks to about
+// a day of 6s blocks
+const MaxBotHeartbeatTimeoutBlocks = 14_400
+
 // DefaultParams returns a default set of parameters
 func DefaultParams() Params {
 	validatorShare, _ := sdk.NewDecFromStr(DefaultValidatorShare)
 	delegatorShare, _ := sdk.NewDecFromStr(DefaultDelegatorShare)
 	dexShare, _ := sdk.NewDecFromStr(DefaultDexShare)
+	downtimeWeight, _ := sdk.NewDecFromStr(DefaultDeclaredDowntimeWeight)
+	maxSupplyRatio, _ := sdk.NewDecFromStr(DefaultMaxMonthlyDistributionSupplyRatio)
 
 	return Params{
 		HalvingCycleDuration: DefaultHalvingCycleDuration,
 		ValidatorShare:       validatorShare,
 		DelegatorShare:       delegatorShare,
 		DexShare:            dexShare,
+		BotProtocolVersion:      DefaultBotProtocolVersion,
+		MinBotProtocolVersion:   DefaultMinBotProtocolVersion,
+		DexShareToLPPools:       DefaultDexShareToLPPools,
+		PostDexShareDestination: DefaultPostDexShareDestination,
+		RoundingStrategy:        DefaultRoundingStrategy,
+		RemainderToPos:          DefaultRemainderToPos,
+		MaxDeclaredDowntimeDays: DefaultMaxDeclaredDowntimeDays,
+		DeclaredDowntimeWeight:  downtimeWeight,
+		HeartbeatInterval:       DefaultHeartbeatInterval,
+		HeartbeatRetentionMonths: DefaultHeartbeatRetentionMonths,
+		EligibilitySnapshotDelay: DefaultEligibilitySnapshotDelay,
+		DexReserveOperator:       DefaultDexReserveOperator,
+		DexWithdrawalCap:         sdk.NewInt(DefaultDexWithdrawalCap),
+		DexWithdrawalEpoch:       DefaultDexWithdrawalEpoch,
+
+		DistributionRecordRetentionCycles: DefaultDistributionRecordRetentionCycles,
+		MaxRecordsPrunedPerBlock:          DefaultMaxRecordsPrunedPerBlock,
+		BotHeartbeatTimeoutBlocks:         DefaultBotHeartbeatTimeoutBlocks,
+
+		MaxMonthlyDistribution:            sdk.NewInt(DefaultMaxMonthlyDistribution),
+		MaxMonthlyDistributionSupplyRatio: maxSupplyRatio,
+
+		DexDistributionPeriod: DefaultDexDistributionPeriod,
 	}
 }
 
@@ -58,9 +175,94 @@ func (p Params) Validate() error {
 	if err := validateDexShare(p.DexShare); err != nil {
 		return err
 	}
+	if err := validateBotProtocolVersion(p.BotProtocolVersion); err != nil {
+		return err
+	}
+	if err := validateBotProtocolVersion(p.MinBotProtocolVersion); err != nil {
+		return err
+	}
+	if err := validateDexShareToLPPools(p.DexShareToLPPools); err != nil {
+		return err
+	}
+	if err := validatePostDexShareDestination(p.PostDexShareDestination); err != nil {
+		return err
+	}
+	if err := validateRoundingStrategy(p.RoundingStrategy); err != nil {
+		return err
+	}
+	if err := validateRemainderToPos(p.RemainderToPos); err != nil {
+		return err
+	}
+	if err := validateMaxDeclaredDowntimeDays(p.MaxDeclaredDowntimeDays); err != nil {
+		return err
+	}
+	if err := validateDeclaredDowntimeWeight(p.DeclaredDowntimeWeight); err != nil {
+		return err
+	}
+	if err := validateHeartbeatInterval(p.HeartbeatInterval); err != nil {
+		return err
+	}
+	if err := validateHeartbeatRetentionMonths(p.HeartbeatRetentionMonths); err != nil {
+		return err
+	}
+	if err := validateEligibilitySnapshotDelay(p.EligibilitySnapshotDelay); err != nil {
+		return err
+	}
+	if err := validateDexReserveOperator(p.DexReserveOperator); err != nil {
+		return err
+	}
+	if err := validateDexWithdrawalCap(p.DexWithdrawalCap); err != nil {
+		return err
+	}
+	if err := validateDexWithdrawalEpoch(p.DexWithdrawalEpoch); err != nil {
+		return err
+	}
+	if err := validateDistributionRecordRetentionCycles(p.DistributionRecordRetentionCycles); err != nil {
+		return err
+	}
+	if err := validateMaxRecordsPrunedPerBlock(p.MaxRecordsPrunedPerBlock); err != nil {
+		return err
+	}
+	if err := validateBotHeartbeatTimeoutBlocks(p.BotHeartbeatTimeoutBlocks); err != nil {
+		return err
+	}
+	if err := validateMaxMonthlyDistribution(p.MaxMonthlyDistribution); err != nil {
+		return err
+	}
+	if err := validateMaxMonthlyDistributionSupplyRatio(p.MaxMonthlyDistributionSupplyRatio); err != nil {
+		return err
+	}
+	if err := validateDexDistributionPeriod(p.DexDistributionPeriod); err != nil {
+		return err
+	}
+
+	// The minimum supported bot protocol can never be ahead of the expected one
+	if p.MinBotProtocolVersion > p.BotProtocolVersion {
+		return fmt.Errorf("min bot protocol version %d cannot exceed bot protocol version %d",
+			p.MinBotProtocolVersion, p.BotProtocolVersion)
+	}
 
 	// Ensure shares add up to 1.0
-	total := p.ValidatorShare.Add(p.DelegatorShare).Add(p.DexShare)
+	return ValidateShares(p.ValidatorShare, p.DelegatorShare, p.DexShare)
+}
+
+// ValidateShares checks that the validator, delegator and dex shares each
+// lie within [0, 1] and add up to 1.0
+func ValidateShares(validatorShare, delegatorShare, dexShare sdk.Dec) error {
+	if validatorShare.IsNil() || delegatorShare.IsNil() || dexShare.IsNil() {
+		return fmt.Errorf("validator, delegator, and dex shares must be set")
+	}
+	if err := validateValidatorShare(validatorShare); err != nil {
+		return err
+	}
+	if err := validateDelegatorShare(delegatorShare); err != nil {
+		return err
+	}
+	if err := validateDexShare(dexShare); err != nil {
+		return err
+	}
+
+	total := validatorShare.Add(delegatorShare).Add(dexShare)
 	if !total.Equal(sdk.OneDec()) {
 		return fmt.Errorf("validator, delegator, and dex shares must add up to 1.0, got %s", total.String())
 	}
@@ -82,6 +284,26 @@ func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
 		paramtypes.NewParamSetPair(KeyValidatorShare, &p.ValidatorShare, validateValidatorShare),
 		paramtypes.NewParamSetPair(KeyDelegatorShare, &p.DelegatorShare, validateDelegatorShare),
 		paramtypes.NewParamSetPair(KeyDexShare, &p.DexShare, validateDexShare),
+		paramtypes.NewParamSetPair(KeyBotProtocolVersion, &p.BotProtocolVersion, validateBotProtocolVersion),
+		paramtypes.NewParamSetPair(KeyMinBotProtocolVersion, &p.MinBotProtocolVersion, validateBotProtocolVersion),
+		paramtypes.NewParamSetPair(KeyDexShareToLPPools, &p.DexShareToLPPools, validateDexShareToLPPools),
+		paramtypes.NewParamSetPair(KeyPostDexShareDestination, &p.PostDexShareDestination, validatePostDexShareDestination),
+		paramtypes.NewParamSetPair(KeyRoundingStrategy, &p.RoundingStrategy, validateRoundingStrategy),
+		paramtypes.NewParamSetPair(KeyRemainderToPos, &p.RemainderToPos, validateRemainderToPos),
+		paramtypes.NewParamSetPair(KeyMaxDeclaredDowntimeDays, &p.MaxDeclaredDowntimeDays, validateMaxDeclaredDowntimeDays),
+		paramtypes.NewParamSetPair(KeyDeclaredDowntimeWeight, &p.DeclaredDowntimeWeight, validateDeclaredDowntimeWeight),
+		paramtypes.NewParamSetPair(KeyHeartbeatInterval, &p.HeartbeatInterval, validateHeartbeatInterval),
+		paramtypes.NewParamSetPair(KeyHeartbeatRetentionMonths, &p.HeartbeatRetentionMonths, validateHeartbeatRetentionMonths),
+		paramtypes.NewParamSetPair(KeyEligibilitySnapshotDelay, &p.EligibilitySnapshotDelay, validateEligibilitySnapshotDelay),
+		paramtypes.NewParamSetPair(KeyDexReserveOperator, &p.DexReserveOperator, validateDexReserveOperator),
+		paramtypes.NewParamSetPair(KeyDexWithdrawalCap, &p.DexWithdrawalCap, validateDexWithdrawalCap),
+		paramtypes.NewParamSetPair(KeyDexWithdrawalEpoch, &p.DexWithdrawalEpoch, validateDexWithdrawalEpoch),
+		paramtypes.NewParamSetPair(KeyDistributionRecordRetentionCycles, &p.DistributionRecordRetentionCycles, validateDistributionRecordRetentionCycles),
+		paramtypes.NewParamSetPair(KeyMaxRecordsPrunedPerBlock, &p.MaxRecordsPrunedPerBlock, validateMaxRecordsPrunedPerBlock),
+		paramtypes.NewParamSetPair(KeyBotHeartbeatTimeoutBlocks, &p.BotHeartbeatTimeoutBlocks, validateBotHeartbeatTimeoutBlocks),
+		paramtypes.NewParamSetPair(KeyMaxMonthlyDistribution, &p.MaxMonthlyDistribution, validateMaxMonthlyDistribution),
+		paramtypes.NewParamSetPair(KeyMaxMonthlyDistributionSupplyRatio, &p.MaxMonthlyDistributionSupplyRatio, validateMaxMonthlyDistributionSupplyRatio),
+		paramtypes.NewParamSetPair(KeyDexDistributionPeriod, &p.DexDistributionPeriod, validateDexDistributionPeriod),
 	}
 }
 
@@ -147,4 +369,270 @@ func validateDexShare(i interface{}) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func validateBotProtocolVersion(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v == 0 {
+		return fmt.Errorf("bot protocol version must be positive")
+	}
+
+	return nil
+}
+
+func validateDexShareToLPPools(i interface{}) error {
+	if _, ok := i.(bool); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return nil
+}
+
+func validatePostDexShareDestination(i interface{}) error {
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	switch v {
+	case PostDexShareToValidators, PostDexShareToDelegators, PostDexShareToCommunityPool, PostDexShareBurn:
+		return nil
+	default:
+		return fmt.Errorf("invalid post dex share destination %q: must be one of %s, %s, %s or %s",
+			v, PostDexShareToValidators, PostDexShareToDelegators, PostDexShareToCommunityPool, PostDexShareBurn)
+	}
+}
+
+func validateRoundingStrategy(i interface{}) error {
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return rounding.ValidateStrategy(v)
+}
+
+func validateRemainderToPos(i interface{}) error {
+	if _, ok := i.(bool); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return nil
+}
+
+func validateMaxDeclaredDowntimeDays(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v > MaxDeclarableDowntimeDays {
+		return fmt.Errorf("max declared downtime days cannot exceed %d: %d", MaxDeclarableDowntimeDays, v)
+	}
+
+	return nil
+}
+
+func validateDeclaredDowntimeWeight(i interface{}) error {
+	v, ok := i.(sdk.Dec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("declared downtime weight cannot be negative: %s", v)
+	}
+
+	if v.GT(sdk.OneDec()) {
+		return fmt.Errorf("declared downtime weight cannot be greater than 1: %s", v)
+	}
+
+	return nil
+}
+
+func validateHeartbeatInterval(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v < MinHeartbeatInterval || v > MaxHeartbeatInterval {
+		return fmt.Errorf("heartbeat interval must be between %s and %s: %s", MinHeartbeatInterval, MaxHeartbeatInterval, v)
+	}
+
+	if v%time.Second != 0 {
+		return fmt.Errorf("heartbeat interval must be a whole number of seconds: %s", v)
+	}
+
+	return nil
+}
+
+func validateHeartbeatRetentionMonths(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v == 0 || v > MaxHeartbeatRetentionMonths {
+		return fmt.Errorf("heartbeat retention must be between 1 and %d months: %d", MaxHeartbeatRetentionMonths, v)
+	}
+
+	return nil
+}
+
+func validateEligibilitySnapshotDelay(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v < 0 || v > MaxEligibilitySnapshotDelay {
+		return fmt.Errorf("eligibility snapshot delay must be between 0 and %s: %s", MaxEligibilitySnapshotDelay, v)
+	}
+
+	return nil
+}
+
+func validateDexDistributionPeriod(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v <= 0 || v > MaxDexDistributionPeriod {
+		return fmt.Errorf("dex distribution period must be positive and at most %s: %s", MaxDexDistributionPeriod, v)
+	}
+
+	return nil
+}
+
+func validateDexReserveOperator(i interface{}) error {
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	// Empty disables DEX reserve withdrawals
+	if v == "" {
+		return nil
+	}
+
+	if _, err := sdk.AccAddressFromBech32(v); err != nil {
+		return fmt.Errorf("invalid dex reserve operator %q: %w", v, err)
+	}
+
+	return nil
+}
+
+func validateDexWithdrawalCap(i interface{}) error {
+	v, ok := i.(sdk.Int)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("dex withdrawal cap cannot be negative: %s", v)
+	}
+
+	return nil
+}
+
+func validateDexWithdrawalEpoch(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v < MinDexWithdrawalEpoch || v > MaxDexWithdrawalEpoch {
+		return fmt.Errorf("dex withdrawal epoch must be between %s and %s: %s", MinDexWithdrawalEpoch, MaxDexWithdrawalEpoch, v)
+	}
+
+	if v%time.Second != 0 {
+		return fmt.Errorf("dex withdrawal epoch must be a whole number of seconds: %s", v)
+	}
+
+	return nil
+}
+
+func validateDistributionRecordRetentionCycles(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	// The halving schedule has five cycles; 0 keeps every record
+	if v > 5 {
+		return fmt.Errorf("distribution record retention cannot exceed 5 cycles: %d", v)
+	}
+
+	return nil
+}
+
+func validateMaxRecordsPrunedPerBlock(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v == 0 || v > MaxPrunedRecordsPerBlockLimit {
+		return fmt.Errorf("max records pruned per block must be between 1 and %d: %d", MaxPrunedRecordsPerBlockLimit, v)
+	}
+
+	return nil
+}
+
+func validateBotHeartbeatTimeoutBlocks(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v == 0 || v > MaxBotHeartbeatTimeoutBlocks {
+		return fmt.Errorf("bot heartbeat timeout must be between 1 and %d blocks: %d", MaxBotHeartbeatTimeoutBlocks, v)
+	}
+
+	return nil
+}
+
+// validateMaxMonthlyDistribution checks the absolute distribution ceiling in
+// ugen; zero removes it. Params stored before the ceiling existed leave it
+// unset until the migration sets the default, which is no ceiling either.
+func validateMaxMonthlyDistribution(i interface{}) error {
+	v, ok := i.(sdk.Int)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if !v.IsNil() && v.IsNegative() {
+		return fmt.Errorf("max monthly distribution cannot be negative: %s", v)
+	}
+
+	return nil
+}
+
+// validateMaxMonthlyDistributionSupplyRatio checks the distribution ceiling
+// relative to the current supply; zero or unset removes it
+func validateMaxMonthlyDistributionSupplyRatio(i interface{}) error {
+	v, ok := i.(sdk.Dec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v.IsNil() {
+		return nil
+	}
+
+	if v.IsNegative() {
+		return fmt.Errorf("max monthly distribution supply ratio cannot be negative: %s", v)
+	}
+
+	if v.GT(sdk.OneDec()) {
+		return fmt.Errorf("max monthly distribution supply ratio cannot be greater than 1: %s", v)
+	}
+
+	return nil
+}
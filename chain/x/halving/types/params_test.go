@@ -0,0 +1,188 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Crocodile-ark/gxrchaind/x/halving/types"
+)
+
+func TestParamsValidateBotProtocolVersion(t *testing.T) {
+	testCases := []struct {
+		name       string
+		version    uint64
+		minVersion uint64
+		expErr     bool
+	}{
+		{"defaults", types.DefaultBotProtocolVersion, types.DefaultMinBotProtocolVersion, false},
+		{"min below current", 3, 2, false},
+		{"zero version", 0, 0, true},
+		{"zero min version", 2, 0, true},
+		{"min above current", 2, 3, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := types.DefaultParams()
+			params.BotProtocolVersion = tc.version
+			params.MinBotProtocolVersion = tc.minVersion
+
+			err := params.Validate()
+			if tc.expErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParamsValidatePostDexShareDestination(t *testing.T) {
+	for _, destination := range []string{
+		types.PostDexShareToValidators,
+		types.PostDexShareToDelegators,
+		types.PostDexShareToCommunityPool,
+		types.PostDexShareBurn,
+	} {
+		params := types.DefaultParams()
+		params.PostDexShareDestination = destination
+		require.NoError(t, params.Validate(), destination)
+	}
+
+	for _, destination := range []string{"", "dex", "Validators"} {
+		params := types.DefaultParams()
+		params.PostDexShareDestination = destination
+		require.Error(t, params.Validate(), destination)
+	}
+}
+
+func TestParamsValidateDeclaredDowntime(t *testing.T) {
+	params := types.DefaultParams()
+	params.MaxDeclaredDowntimeDays = types.MaxDeclarableDowntimeDays
+	params.DeclaredDowntimeWeight = sdk.ZeroDec()
+	require.NoError(t, params.Validate())
+
+	params.MaxDeclaredDowntimeDays = types.MaxDeclarableDowntimeDays + 1
+	require.Error(t, params.Validate())
+
+	for _, weight := range []string{"-0.1", "1.01"} {
+		params := types.DefaultParams()
+		params.DeclaredDowntimeWeight = sdk.MustNewDecFromStr(weight)
+		require.Error(t, params.Validate(), weight)
+	}
+}
+
+func TestParamsValidateHeartbeat(t *testing.T) {
+	params := types.DefaultParams()
+	params.HeartbeatInterval = types.MinHeartbeatInterval
+	params.HeartbeatRetentionMonths = types.MaxHeartbeatRetentionMonths
+	require.NoError(t, params.Validate())
+
+	for _, interval := range []time.Duration{0, 30 * time.Second, 25 * time.Hour, 10*time.Minute + 500*time.Millisecond} {
+		params := types.DefaultParams()
+		params.HeartbeatInterval = interval
+		require.Error(t, params.Validate(), interval)
+	}
+
+	for _, months := range []uint64{0, types.MaxHeartbeatRetentionMonths + 1} {
+		params := types.DefaultParams()
+		params.HeartbeatRetentionMonths = months
+		require.Error(t, params.Validate(), months)
+	}
+}
+
+func TestParamsValidateEligibilitySnapshotDelay(t *testing.T) {
+	params := types.DefaultParams()
+	require.Zero(t, params.EligibilitySnapshotDelay)
+	params.EligibilitySnapshotDelay = types.MaxEligibilitySnapshotDelay
+	require.NoError(t, params.Validate())
+
+	for _, delay := range []time.Duration{-time.Second, types.MaxEligibilitySnapshotDelay + time.Second} {
+		params := types.DefaultParams()
+		params.EligibilitySnapshotDelay = delay
+		require.Error(t, params.Validate(), delay)
+	}
+}
+
+func TestParamsValidateDexDistributionPeriod(t *testing.T) {
+	params := types.DefaultParams()
+	require.Equal(t, types.MaxDexDistributionPeriod, params.DexDistributionPeriod)
+	params.DexDistributionPeriod = 365 * 24 * time.Hour
+	require.NoError(t, params.Validate())
+
+	for _, period := range []time.Duration{0, -time.Hour, types.MaxDexDistributionPeriod + time.Second} {
+		params := types.DefaultParams()
+		params.DexDistributionPeriod = period
+		require.Error(t, params.Validate(), period)
+	}
+}
+
+func TestParamsValidateDexWithdrawals(t *testing.T) {
+	params := types.DefaultParams()
+	require.Empty(t, params.DexReserveOperator, "withdrawals are disabled by default")
+	params.DexReserveOperator = sdk.AccAddress([]byte("dex-reserve-operator")).String()
+	params.DexWithdrawalCap = sdk.ZeroInt()
+	params.DexWithdrawalEpoch = types.MinDexWithdrawalEpoch
+	require.NoError(t, params.Validate())
+
+	params.DexReserveOperator = "not-an-address"
+	require.Error(t, params.Validate())
+
+	params = types.DefaultParams()
+	params.DexWithdrawalCap = sdk.NewInt(-1)
+	require.Error(t, params.Validate())
+
+	for _, epoch := range []time.Duration{0, 30 * time.Minute, types.MaxDexWithdrawalEpoch + time.Hour, time.Hour + 500*time.Millisecond} {
+		params := types.DefaultParams()
+		params.DexWithdrawalEpoch = epoch
+		require.Error(t, params.Validate(), epoch)
+	}
+}
+
+func TestParamsValidateDistributionRecordPruning(t *testing.T) {
+	params := types.DefaultParams()
+	params.DistributionRecordRetentionCycles = 0
+	require.NoError(t, params.Validate())
+
+	params.DistributionRecordRetentionCycles = 6
+	require.Error(t, params.Validate())
+
+	for _, limit := range []uint64{0, types.MaxPrunedRecordsPerBlockLimit + 1} {
+		params := types.DefaultParams()
+		params.MaxRecordsPrunedPerBlock = limit
+		require.Error(t, params.Validate(), limit)
+	}
+}
+
+func TestParamsValidateBotHeartbeatTimeout(t *testing.T) {
+	params := types.DefaultParams()
+	params.BotHeartbeatTimeoutBlocks = types.MaxBotHeartbeatTimeoutBlocks
+	require.NoError(t, params.Validate())
+
+	for _, timeout := range []uint64{0, types.MaxBotHeartbeatTimeoutBlocks + 1} {
+		params := types.DefaultParams()
+		params.BotHeartbeatTimeoutBlocks = timeout
+		require.Error(t, params.Validate(), timeout)
+	}
+}
+
+func TestParamsValidateMonthlyDistributionCeiling(t *testing.T) {
+	// Zero removes a limit
+	params := types.DefaultParams()
+	params.MaxMonthlyDistribution = sdk.ZeroInt()
+	params.MaxMonthlyDistributionSupplyRatio = sdk.ZeroDec()
+	require.NoError(t, params.Validate())
+
+	params = types.DefaultParams()
+	params.MaxMonthlyDistribution = sdk.NewInt(-1)
+	require.Error(t, params.Validate())
+
+	for _, ratio := range []string{"-0.01", "1.01"} {
+		params := types.DefaultParams()
+		params.MaxMonthlyDistributionSupplyRatio = sdk.MustNewDecFromStr(ratio)
+		require.Error(t, params.Validate(), ratio)
+	}
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/query"
 )
 
@@ -20,6 +21,33 @@ type QueryHalvingInfoResponse struct {
 	HalvingInfo HalvingInfo `protobuf:"bytes,1,opt,name=halving_info,json=halvingInfo,proto3" json:"halving_info"`
 }
 
+// QueryBotProtocolVersionRequest is the request type for the Query/BotProtocolVersion RPC method.
+type QueryBotProtocolVersionRequest struct{}
+
+// QueryBotProtocolVersionResponse is the response type for the Query/BotProtocolVersion RPC method.
+type QueryBotProtocolVersionResponse struct {
+	BotProtocolVersion    uint64 `protobuf:"varint,1,opt,name=bot_protocol_version,json=botProtocolVersion,proto3" json:"bot_protocol_version,omitempty"`
+	MinSupportedVersion   uint64 `protobuf:"varint,2,opt,name=min_supported_version,json=minSupportedVersion,proto3" json:"min_supported_version,omitempty"`
+}
+
+// QuerySupplyFloorForecastRequest is the request type for the Query/SupplyFloorForecast RPC method.
+type QuerySupplyFloorForecastRequest struct{}
+
+// QuerySupplyFloorForecastResponse is the response type for the Query/SupplyFloorForecast RPC method.
+type QuerySupplyFloorForecastResponse struct {
+	Forecast SupplyFloorForecast `protobuf:"bytes,1,opt,name=forecast,proto3" json:"forecast"`
+}
+
+// QueryPendingDexAllocationRequest is the request type for the Query/PendingDexAllocation RPC method.
+type QueryPendingDexAllocationRequest struct {
+	Cycle uint64 `protobuf:"varint,1,opt,name=cycle,proto3" json:"cycle,omitempty"`
+}
+
+// QueryPendingDexAllocationResponse is the response type for the Query/PendingDexAllocation RPC method.
+type QueryPendingDexAllocationResponse struct {
+	PendingDexAllocation PendingDexAllocation `protobuf:"bytes,1,opt,name=pending_dex_allocation,json=pendingDexAllocation,proto3" json:"pending_dex_allocation"`
+}
+
 // QueryDistributionHistoryRequest is the request type for the Query/DistributionHistory RPC method.
 type QueryDistributionHistoryRequest struct {
 	Pagination *query.PageRequest `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
@@ -29,4 +57,135 @@ type QueryDistributionHistoryRequest struct {
 type QueryDistributionHistoryResponse struct {
 	DistributionRecords []DistributionRecord `protobuf:"bytes,1,rep,name=distribution_records,json=distributionRecords,proto3" json:"distribution_records"`
 	Pagination          *query.PageResponse  `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
-}
\ No newline at end of file
+}
+
+// QueryDexDistributionBalanceRequest is the request type for the Query/DexDistributionBalance RPC method.
+type QueryDexDistributionBalanceRequest struct{}
+
+// QueryDexDistributionBalanceResponse is the response type for the Query/DexDistributionBalance RPC method.
+type QueryDexDistributionBalanceResponse struct {
+	Address string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Balance sdk.Coin `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance"`
+	Pending sdk.Coin `protobuf:"bytes,3,opt,name=pending,proto3" json:"pending"`
+}
+
+// QueryValidatorUptimeRequest is the request type for the Query/ValidatorUptime RPC method.
+type QueryValidatorUptimeRequest struct {
+	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+}
+
+// QueryValidatorUptimeResponse is the response type for the Query/ValidatorUptime RPC method.
+type QueryValidatorUptimeResponse struct {
+	Uptime                ValidatorUptime       `protobuf:"bytes,1,opt,name=uptime,proto3" json:"uptime"`
+	EffectiveInactiveDays sdk.Dec               `protobuf:"bytes,2,opt,name=effective_inactive_days,json=effectiveInactiveDays,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"effective_inactive_days"`
+	Declarations          []DowntimeDeclaration `protobuf:"bytes,3,rep,name=declarations,proto3" json:"declarations"`
+	DeclaredDays          uint64                `protobuf:"varint,4,opt,name=declared_days,json=declaredDays,proto3" json:"declared_days,omitempty"`
+}
+
+// QueryHeartbeatComplianceRequest is the request type for the Query/HeartbeatCompliance RPC method.
+type QueryHeartbeatComplianceRequest struct {
+	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Month            uint64 `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+}
+
+// QueryHeartbeatComplianceResponse is the response type for the Query/HeartbeatCompliance RPC method.
+type QueryHeartbeatComplianceResponse struct {
+	LastHeartbeat    ValidatorHeartbeat `protobuf:"bytes,1,opt,name=last_heartbeat,json=lastHeartbeat,proto3" json:"last_heartbeat"`
+	Month            uint64             `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	IntervalSeconds  int64              `protobuf:"varint,3,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	Intervals        uint64             `protobuf:"varint,4,opt,name=intervals,proto3" json:"intervals,omitempty"`
+	ElapsedIntervals uint64             `protobuf:"varint,5,opt,name=elapsed_intervals,json=elapsedIntervals,proto3" json:"elapsed_intervals,omitempty"`
+	PresentIntervals uint64             `protobuf:"varint,6,opt,name=present_intervals,json=presentIntervals,proto3" json:"present_intervals,omitempty"`
+	CoveragePercent  sdk.Dec            `protobuf:"bytes,7,opt,name=coverage_percent,json=coveragePercent,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Dec" json:"coverage_percent"`
+	BotMetadata      *BotMetadata       `protobuf:"bytes,8,opt,name=bot_metadata,json=botMetadata,proto3" json:"bot_metadata,omitempty"`
+}
+
+// QueryDexReserveWithdrawalsRequest is the request type for the Query/DexReserveWithdrawals RPC method.
+type QueryDexReserveWithdrawalsRequest struct {
+	Pagination *query.PageRequest `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+// QueryDexReserveWithdrawalsResponse is the response type for the Query/DexReserveWithdrawals RPC method.
+type QueryDexReserveWithdrawalsResponse struct {
+	Withdrawals []DexReserveWithdrawal `protobuf:"bytes,1,rep,name=withdrawals,proto3" json:"withdrawals"`
+	Pagination  *query.PageResponse    `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+// QueryDexWithdrawalLimitRequest is the request type for the Query/DexWithdrawalLimit RPC method.
+type QueryDexWithdrawalLimitRequest struct{}
+
+// QueryDexWithdrawalLimitResponse is the response type for the Query/DexWithdrawalLimit RPC method.
+type QueryDexWithdrawalLimitResponse struct {
+	Operator   string   `protobuf:"bytes,1,opt,name=operator,proto3" json:"operator,omitempty"`
+	Cap        sdk.Coin `protobuf:"bytes,2,opt,name=cap,proto3" json:"cap"`
+	Withdrawn  sdk.Coin `protobuf:"bytes,3,opt,name=withdrawn,proto3" json:"withdrawn"`
+	Remaining  sdk.Coin `protobuf:"bytes,4,opt,name=remaining,proto3" json:"remaining"`
+	EpochStart int64    `protobuf:"varint,5,opt,name=epoch_start,json=epochStart,proto3" json:"epoch_start,omitempty"`
+	EpochEnd   int64    `protobuf:"varint,6,opt,name=epoch_end,json=epochEnd,proto3" json:"epoch_end,omitempty"`
+	Available  sdk.Coin `protobuf:"bytes,7,opt,name=available,proto3" json:"available"`
+}
+
+// QueryRewardEstimateRequest is the request type for the Query/RewardEstimate RPC method.
+type QueryRewardEstimateRequest struct {
+	ValidatorAddress     string  `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	AdditionalDelegation sdk.Int `protobuf:"bytes,2,opt,name=additional_delegation,json=additionalDelegation,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Int" json:"additional_delegation"`
+}
+
+// QueryRewardEstimateResponse is the response type for the Query/RewardEstimate RPC method.
+type QueryRewardEstimateResponse struct {
+	RewardEstimate RewardEstimate `protobuf:"bytes,1,opt,name=reward_estimate,json=rewardEstimate,proto3" json:"reward_estimate"`
+}
+
+// QuerySimulateDistributionRequest is the request type for the Query/SimulateDistribution RPC method.
+type QuerySimulateDistributionRequest struct{}
+
+// QuerySimulateDistributionResponse is the response type for the Query/SimulateDistribution RPC method.
+type QuerySimulateDistributionResponse struct {
+	Simulation DistributionSimulation `protobuf:"bytes,1,opt,name=simulation,proto3" json:"simulation"`
+}
+
+// QueryDistributionRecordRequest is the request type for the Query/DistributionRecord RPC method.
+type QueryDistributionRecordRequest struct {
+	Cycle             uint64 `protobuf:"varint,1,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	DistributionMonth uint64 `protobuf:"varint,2,opt,name=distribution_month,json=distributionMonth,proto3" json:"distribution_month,omitempty"`
+}
+
+// QueryDistributionRecordResponse is the response type for the Query/DistributionRecord RPC method.
+type QueryDistributionRecordResponse struct {
+	Record  *DistributionRecord       `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	Pruned  bool                      `protobuf:"varint,2,opt,name=pruned,proto3" json:"pruned,omitempty"`
+	Summary *DistributionCycleSummary `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+}
+
+// QueryClawbacksRequest is the request type for the Query/Clawbacks RPC method.
+type QueryClawbacksRequest struct {
+	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+}
+
+// QueryClawbacksResponse is the response type for the Query/Clawbacks RPC method.
+type QueryClawbacksResponse struct {
+	Ledgers          []ValidatorRewardLedger `protobuf:"bytes,1,rep,name=ledgers,proto3" json:"ledgers"`
+	TotalOutstanding sdk.Coin                `protobuf:"bytes,2,opt,name=total_outstanding,json=totalOutstanding,proto3" json:"total_outstanding"`
+}
+
+// QueryBotDirectoryRequest is the request type for the Query/BotDirectory RPC method.
+type QueryBotDirectoryRequest struct {
+	Pagination *query.PageRequest `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+// QueryBotDirectoryResponse is the response type for the Query/BotDirectory RPC method.
+type QueryBotDirectoryResponse struct {
+	BotMetadata []BotMetadata       `protobuf:"bytes,1,rep,name=bot_metadata,json=botMetadata,proto3" json:"bot_metadata"`
+	Pagination  *query.PageResponse `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+// QueryParamChangeHistoryRequest is the request type for the Query/ParamChangeHistory RPC method.
+type QueryParamChangeHistoryRequest struct {
+	Pagination *query.PageRequest `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
+
+// QueryParamChangeHistoryResponse is the response type for the Query/ParamChangeHistory RPC method.
+type QueryParamChangeHistoryResponse struct {
+	Records    []ParamChangeRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records"`
+	Pagination *query.PageResponse `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+}
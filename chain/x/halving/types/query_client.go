@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"google.golang.org/grpc"
 )
 
@@ -13,6 +12,20 @@ type QueryServer interface {
 	Params(context.Context, *QueryParamsRequest) (*QueryParamsResponse, error)
 	HalvingInfo(context.Context, *QueryHalvingInfoRequest) (*QueryHalvingInfoResponse, error)
 	DistributionHistory(context.Context, *QueryDistributionHistoryRequest) (*QueryDistributionHistoryResponse, error)
+	BotProtocolVersion(context.Context, *QueryBotProtocolVersionRequest) (*QueryBotProtocolVersionResponse, error)
+	SupplyFloorForecast(context.Context, *QuerySupplyFloorForecastRequest) (*QuerySupplyFloorForecastResponse, error)
+	PendingDexAllocation(context.Context, *QueryPendingDexAllocationRequest) (*QueryPendingDexAllocationResponse, error)
+	DexDistributionBalance(context.Context, *QueryDexDistributionBalanceRequest) (*QueryDexDistributionBalanceResponse, error)
+	ValidatorUptime(context.Context, *QueryValidatorUptimeRequest) (*QueryValidatorUptimeResponse, error)
+	HeartbeatCompliance(context.Context, *QueryHeartbeatComplianceRequest) (*QueryHeartbeatComplianceResponse, error)
+	DexReserveWithdrawals(context.Context, *QueryDexReserveWithdrawalsRequest) (*QueryDexReserveWithdrawalsResponse, error)
+	DexWithdrawalLimit(context.Context, *QueryDexWithdrawalLimitRequest) (*QueryDexWithdrawalLimitResponse, error)
+	RewardEstimate(context.Context, *QueryRewardEstimateRequest) (*QueryRewardEstimateResponse, error)
+	SimulateDistribution(context.Context, *QuerySimulateDistributionRequest) (*QuerySimulateDistributionResponse, error)
+	DistributionRecord(context.Context, *QueryDistributionRecordRequest) (*QueryDistributionRecordResponse, error)
+	Clawbacks(context.Context, *QueryClawbacksRequest) (*QueryClawbacksResponse, error)
+	BotDirectory(context.Context, *QueryBotDirectoryRequest) (*QueryBotDirectoryResponse, error)
+	ParamChangeHistory(context.Context, *QueryParamChangeHistoryRequest) (*QueryParamChangeHistoryResponse, error)
 }
 
 // QueryClient defines the gRPC querier client for the halving module.
@@ -20,6 +33,20 @@ type QueryClient interface {
 	Params(ctx context.Context, in *QueryParamsRequest, opts ...grpc.CallOption) (*QueryParamsResponse, error)
 	HalvingInfo(ctx context.Context, in *QueryHalvingInfoRequest, opts ...grpc.CallOption) (*QueryHalvingInfoResponse, error)
 	DistributionHistory(ctx context.Context, in *QueryDistributionHistoryRequest, opts ...grpc.CallOption) (*QueryDistributionHistoryResponse, error)
+	BotProtocolVersion(ctx context.Context, in *QueryBotProtocolVersionRequest, opts ...grpc.CallOption) (*QueryBotProtocolVersionResponse, error)
+	SupplyFloorForecast(ctx context.Context, in *QuerySupplyFloorForecastRequest, opts ...grpc.CallOption) (*QuerySupplyFloorForecastResponse, error)
+	PendingDexAllocation(ctx context.Context, in *QueryPendingDexAllocationRequest, opts ...grpc.CallOption) (*QueryPendingDexAllocationResponse, error)
+	DexDistributionBalance(ctx context.Context, in *QueryDexDistributionBalanceRequest, opts ...grpc.CallOption) (*QueryDexDistributionBalanceResponse, error)
+	ValidatorUptime(ctx context.Context, in *QueryValidatorUptimeRequest, opts ...grpc.CallOption) (*QueryValidatorUptimeResponse, error)
+	HeartbeatCompliance(ctx context.Context, in *QueryHeartbeatComplianceRequest, opts ...grpc.CallOption) (*QueryHeartbeatComplianceResponse, error)
+	DexReserveWithdrawals(ctx context.Context, in *QueryDexReserveWithdrawalsRequest, opts ...grpc.CallOption) (*QueryDexReserveWithdrawalsResponse, error)
+	DexWithdrawalLimit(ctx context.Context, in *QueryDexWithdrawalLimitRequest, opts ...grpc.CallOption) (*QueryDexWithdrawalLimitResponse, error)
+	RewardEstimate(ctx context.Context, in *QueryRewardEstimateRequest, opts ...grpc.CallOption) (*QueryRewardEstimateResponse, error)
+	SimulateDistribution(ctx context.Context, in *QuerySimulateDistributionRequest, opts ...grpc.CallOption) (*QuerySimulateDistributionResponse, error)
+	DistributionRecord(ctx context.Context, in *QueryDistributionRecordRequest, opts ...grpc.CallOption) (*QueryDistributionRecordResponse, error)
+	Clawbacks(ctx context.Context, in *QueryClawbacksRequest, opts ...grpc.CallOption) (*QueryClawbacksResponse, error)
+	BotDirectory(ctx context.Context, in *QueryBotDirectoryRequest, opts ...grpc.CallOption) (*QueryBotDirectoryResponse, error)
+	ParamChangeHistory(ctx context.Context, in *QueryParamChangeHistoryRequest, opts ...grpc.CallOption) (*QueryParamChangeHistoryResponse, error)
 }
 
 type queryClient struct {
@@ -58,21 +85,135 @@ func (c *queryClient) DistributionHistory(ctx context.Context, in *QueryDistribu
 	return out, nil
 }
 
-// RegisterQueryServer registers the halving query server
-func RegisterQueryServer(s grpc.ServiceRegistrar, srv QueryServer) {
-	s.RegisterService(&Query_ServiceDesc, srv)
+func (c *queryClient) BotProtocolVersion(ctx context.Context, in *QueryBotProtocolVersionRequest, opts ...grpc.CallOption) (*QueryBotProtocolVersionResponse, error) {
+	out := new(QueryBotProtocolVersionResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/BotProtocolVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) SupplyFloorForecast(ctx context.Context, in *QuerySupplyFloorForecastRequest, opts ...grpc.CallOption) (*QuerySupplyFloorForecastResponse, error) {
+	out := new(QuerySupplyFloorForecastResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/SupplyFloorForecast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) PendingDexAllocation(ctx context.Context, in *QueryPendingDexAllocationRequest, opts ...grpc.CallOption) (*QueryPendingDexAllocationResponse, error) {
+	out := new(QueryPendingDexAllocationResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/PendingDexAllocation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// RegisterQueryHandlerClient registers the halving query handler client
-func RegisterQueryHandlerClient(ctx context.Context, mux *runtime.ServeMux, client QueryClient) error {
-	return RegisterQueryHandlerFromEndpoint(ctx, mux, "", client)
+func (c *queryClient) DexDistributionBalance(ctx context.Context, in *QueryDexDistributionBalanceRequest, opts ...grpc.CallOption) (*QueryDexDistributionBalanceResponse, error) {
+	out := new(QueryDexDistributionBalanceResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/DexDistributionBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) ValidatorUptime(ctx context.Context, in *QueryValidatorUptimeRequest, opts ...grpc.CallOption) (*QueryValidatorUptimeResponse, error) {
+	out := new(QueryValidatorUptimeResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/ValidatorUptime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// RegisterQueryHandlerFromEndpoint is a placeholder for gateway registration
-func RegisterQueryHandlerFromEndpoint(ctx context.Context, mux *runtime.ServeMux, endpoint string, client interface{}) error {
-	// This would normally be generated by protoc
-	// For now, we'll provide a minimal implementation
-	return nil
+func (c *queryClient) HeartbeatCompliance(ctx context.Context, in *QueryHeartbeatComplianceRequest, opts ...grpc.CallOption) (*QueryHeartbeatComplianceResponse, error) {
+	out := new(QueryHeartbeatComplianceResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/HeartbeatCompliance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) DexReserveWithdrawals(ctx context.Context, in *QueryDexReserveWithdrawalsRequest, opts ...grpc.CallOption) (*QueryDexReserveWithdrawalsResponse, error) {
+	out := new(QueryDexReserveWithdrawalsResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/DexReserveWithdrawals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) DexWithdrawalLimit(ctx context.Context, in *QueryDexWithdrawalLimitRequest, opts ...grpc.CallOption) (*QueryDexWithdrawalLimitResponse, error) {
+	out := new(QueryDexWithdrawalLimitResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/DexWithdrawalLimit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) RewardEstimate(ctx context.Context, in *QueryRewardEstimateRequest, opts ...grpc.CallOption) (*QueryRewardEstimateResponse, error) {
+	out := new(QueryRewardEstimateResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/RewardEstimate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) SimulateDistribution(ctx context.Context, in *QuerySimulateDistributionRequest, opts ...grpc.CallOption) (*QuerySimulateDistributionResponse, error) {
+	out := new(QuerySimulateDistributionResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/SimulateDistribution", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) DistributionRecord(ctx context.Context, in *QueryDistributionRecordRequest, opts ...grpc.CallOption) (*QueryDistributionRecordResponse, error) {
+	out := new(QueryDistributionRecordResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/DistributionRecord", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) Clawbacks(ctx context.Context, in *QueryClawbacksRequest, opts ...grpc.CallOption) (*QueryClawbacksResponse, error) {
+	out := new(QueryClawbacksResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/Clawbacks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) BotDirectory(ctx context.Context, in *QueryBotDirectoryRequest, opts ...grpc.CallOption) (*QueryBotDirectoryResponse, error) {
+	out := new(QueryBotDirectoryResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/BotDirectory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) ParamChangeHistory(ctx context.Context, in *QueryParamChangeHistoryRequest, opts ...grpc.CallOption) (*QueryParamChangeHistoryResponse, error) {
+	out := new(QueryParamChangeHistoryResponse)
+	err := c.cc.Invoke(ctx, "/gxr.halving.v1beta1.Query/ParamChangeHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegisterQueryServer registers the halving query server
+func RegisterQueryServer(s grpc.ServiceRegistrar, srv QueryServer) {
+	s.RegisterService(&Query_ServiceDesc, srv)
 }
 
 // Query_ServiceDesc is the grpc service descriptor for Query service.
@@ -92,6 +233,62 @@ var Query_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DistributionHistory",
 			Handler:    _Query_DistributionHistory_Handler,
 		},
+		{
+			MethodName: "BotProtocolVersion",
+			Handler:    _Query_BotProtocolVersion_Handler,
+		},
+		{
+			MethodName: "SupplyFloorForecast",
+			Handler:    _Query_SupplyFloorForecast_Handler,
+		},
+		{
+			MethodName: "PendingDexAllocation",
+			Handler:    _Query_PendingDexAllocation_Handler,
+		},
+		{
+			MethodName: "DexDistributionBalance",
+			Handler:    _Query_DexDistributionBalance_Handler,
+		},
+		{
+			MethodName: "ValidatorUptime",
+			Handler:    _Query_ValidatorUptime_Handler,
+		},
+		{
+			MethodName: "HeartbeatCompliance",
+			Handler:    _Query_HeartbeatCompliance_Handler,
+		},
+		{
+			MethodName: "DexReserveWithdrawals",
+			Handler:    _Query_DexReserveWithdrawals_Handler,
+		},
+		{
+			MethodName: "DexWithdrawalLimit",
+			Handler:    _Query_DexWithdrawalLimit_Handler,
+		},
+		{
+			MethodName: "RewardEstimate",
+			Handler:    _Query_RewardEstimate_Handler,
+		},
+		{
+			MethodName: "SimulateDistribution",
+			Handler:    _Query_SimulateDistribution_Handler,
+		},
+		{
+			MethodName: "DistributionRecord",
+			Handler:    _Query_DistributionRecord_Handler,
+		},
+		{
+			MethodName: "Clawbacks",
+			Handler:    _Query_Clawbacks_Handler,
+		},
+		{
+			MethodName: "BotDirectory",
+			Handler:    _Query_BotDirectory_Handler,
+		},
+		{
+			MethodName: "ParamChangeHistory",
+			Handler:    _Query_ParamChangeHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "gxr/halving/v1beta1/query.proto",
@@ -150,4 +347,256 @@ func _Query_DistributionHistory_Handler(srv interface{}, ctx context.Context, de
 		return srv.(QueryServer).DistributionHistory(ctx, req.(*QueryDistributionHistoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
-}
\ No newline at end of file
+}
+
+func _Query_BotProtocolVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryBotProtocolVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).BotProtocolVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/BotProtocolVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).BotProtocolVersion(ctx, req.(*QueryBotProtocolVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_SupplyFloorForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuerySupplyFloorForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).SupplyFloorForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/SupplyFloorForecast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).SupplyFloorForecast(ctx, req.(*QuerySupplyFloorForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_PendingDexAllocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryPendingDexAllocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).PendingDexAllocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/PendingDexAllocation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).PendingDexAllocation(ctx, req.(*QueryPendingDexAllocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_DexDistributionBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryDexDistributionBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).DexDistributionBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/DexDistributionBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).DexDistributionBalance(ctx, req.(*QueryDexDistributionBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_ValidatorUptime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryValidatorUptimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).ValidatorUptime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/ValidatorUptime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).ValidatorUptime(ctx, req.(*QueryValidatorUptimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_HeartbeatCompliance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryHeartbeatComplianceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).HeartbeatCompliance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/HeartbeatCompliance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).HeartbeatCompliance(ctx, req.(*QueryHeartbeatComplianceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_DexReserveWithdrawals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryDexReserveWithdrawalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).DexReserveWithdrawals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/DexReserveWithdrawals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).DexReserveWithdrawals(ctx, req.(*QueryDexReserveWithdrawalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_DexWithdrawalLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryDexWithdrawalLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).DexWithdrawalLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/DexWithdrawalLimit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).DexWithdrawalLimit(ctx, req.(*QueryDexWithdrawalLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_RewardEstimate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRewardEstimateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).RewardEstimate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/RewardEstimate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).RewardEstimate(ctx, req.(*QueryRewardEstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_SimulateDistribution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuerySimulateDistributionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).SimulateDistribution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/SimulateDistribution",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).SimulateDistribution(ctx, req.(*QuerySimulateDistributionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_DistributionRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryDistributionRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).DistributionRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/DistributionRecord",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).DistributionRecord(ctx, req.(*QueryDistributionRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_Clawbacks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryClawbacksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).Clawbacks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/Clawbacks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).Clawbacks(ctx, req.(*QueryClawbacksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_BotDirectory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryBotDirectoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).BotDirectory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/BotDirectory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).BotDirectory(ctx, req.(*QueryBotDirectoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_ParamChangeHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryParamChangeHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).ParamChangeHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gxr.halving.v1beta1.Query/ParamChangeHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).ParamChangeHistory(ctx, req.(*QueryParamChangeHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// BotHealthFileName is the bot health snapshot in the run directory
+	BotHealthFileName = "bot_health.json"
+	// BotHealthEnv tells the bot where to write its health snapshot
+	BotHealthEnv = "GXR_BOT_HEALTH_SNAPSHOT"
+	// BotHealthCheckInterval is how often the launcher reads the snapshot
+	BotHealthCheckInterval = 30 * time.Second
+	// DefaultBotUnhealthyRestart is how long the bot may report failed health
+	// before the launcher restarts it
+	DefaultBotUnhealthyRestart = 10 * time.Minute
+	// defaultBotHealthInterval is assumed when a snapshot carries no interval
+	defaultBotHealthInterval = 30 * time.Second
+)
+
+// Bot health states reported by the launcher
+const (
+	BotHealthUnknown   = "unknown"
+	BotHealthHealthy   = "healthy"
+	BotHealthUnhealthy = "unhealthy"
+	BotHealthStale     = "stale"
+)
+
+// BotHealthSnapshot is the part of the bot's health snapshot the launcher
+// uses. The bot rewrites it atomically after every health check.
+type BotHealthSnapshot struct {
+	Version         string               `json:"version"`
+	PID             int                  `json:"pid"`
+	WrittenAt       time.Time            `json:"written_at"`
+	IntervalSeconds int64                `json:"interval_seconds"`
+	Healthy         bool                 `json:"healthy"`
+	UnhealthySince  *time.Time           `json:"unhealthy_since,omitempty"`
+	Health          map[string]bool      `json:"health"`
+	LastHeartbeats  map[string]time.Time `json:"last_heartbeats,omitempty"`
+	RebalancerState string               `json:"rebalancer_state,omitempty"`
+}
+
+// BotHealthStatus is the launcher's reading of the bot health snapshot
+type BotHealthStatus struct {
+	State           string     `json:"state"`
+	Detail          string     `json:"detail,omitempty"`
+	SnapshotAt      *time.Time `json:"snapshot_at,omitempty"`
+	UnhealthySince  *time.Time `json:"unhealthy_since,omitempty"`
+	Unhealthy       []string   `json:"unhealthy_components,omitempty"`
+	LastHeartbeat   *time.Time `json:"last_heartbeat,omitempty"`
+	RebalancerState string     `json:"rebalancer_state,omitempty"`
+	RestartEligible bool       `json:"restart_eligible"`
+}
+
+// botHealthPath returns the bot health snapshot path for a run directory
+func botHealthPath(runDir string) string {
+	return filepath.Join(runDir, BotHealthFileName)
+}
+
+// readBotHealth reads the snapshot written by the bot
+func readBotHealth(path string) (BotHealthSnapshot, error) {
+	var snapshot BotHealthSnapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, err
+	}
+	err = json.Unmarshal(data, &snapshot)
+	return snapshot, err
+}
+
+// evaluateBotHealth interprets a snapshot of the bot process botPID at now.
+// A snapshot older than two of the bot's health check intervals, or written
+// by another process, is stale. A bot reporting failed health for longer
+// than restartAfter is restart-eligible; zero disables that.
+func evaluateBotHealth(snapshot BotHealthSnapshot, botPID int, now time.Time, restartAfter time.Duration) BotHealthStatus {
+	writtenAt := snapshot.WrittenAt
+	status := BotHealthStatus{
+		SnapshotAt:      &writtenAt,
+		RebalancerState: snapshot.RebalancerState,
+	}
+	for component, healthy := range snapshot.Health {
+		if !healthy {
+			status.Unhealthy = append(status.Unhealthy, component)
+		}
+	}
+	sort.Strings(status.Unhealthy)
+	for _, heartbeat := range snapshot.LastHeartbeats {
+		if status.LastHeartbeat == nil || heartbeat.After(*status.LastHeartbeat) {
+			heartbeat := heartbeat
+			status.LastHeartbeat = &heartbeat
+		}
+	}
+
+	interval := time.Duration(snapshot.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = defaultBotHealthInterval
+	}
+	if botPID != 0 && snapshot.PID != botPID {
+		status.State = BotHealthStale
+		status.Detail = fmt.Sprintf("snapshot written by PID %d, not the running bot", snapshot.PID)
+		return status
+	}
+	if age := now.Sub(snapshot.WrittenAt); age > 2*interval {
+		status.State = BotHealthStale
+		status.Detail = fmt.Sprintf("no snapshot for %s", age.Truncate(time.Second))
+		return status
+	}
+
+	if snapshot.Healthy {
+		status.State = BotHealthHealthy
+		return status
+	}
+
+	status.State = BotHealthUnhealthy
+	if snapshot.UnhealthySince != nil {
+		since := *snapshot.UnhealthySince
+		status.UnhealthySince = &since
+		status.RestartEligible = restartAfter > 0 && now.Sub(since) >= restartAfter
+	}
+	return status
+}
+
+// botHealthLocked returns the bot health for the status output, nil when the
+// bot is not running or no run directory is configured. l.mu must be held.
+func (l *GXRLauncher) botHealthLocked(now time.Time) *BotHealthStatus {
+	if l.config.RunDir == "" || !l.botRunning || l.botCmd == nil {
+		return nil
+	}
+
+	snapshot, err := readBotHealth(botHealthPath(l.config.RunDir))
+	if err != nil {
+		return &BotHealthStatus{State: BotHealthUnknown, Detail: "no health snapshot yet"}
+	}
+	status := evaluateBotHealth(snapshot, l.botCmd.Process.Pid, now, l.config.BotUnhealthyRestart)
+	return &status
+}
+
+// botHealthWatchdog refreshes the status file with the bot health and
+// restarts a bot that has reported failed health for too long
+func (l *GXRLauncher) botHealthWatchdog() {
+	defer l.wg.Done()
+	ticker := time.NewTicker(BotHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case now := <-ticker.C:
+			l.mu.Lock()
+			l.lastHealthCycle = now
+			l.mu.Unlock()
+			l.updateStatusFile()
+			l.checkBotHealth()
+		}
+	}
+}
+
+// checkBotHealth restarts the bot when its health snapshot makes it
+// restart-eligible. Only the bot is stopped; the auto-restart brings it back.
+func (l *GXRLauncher) checkBotHealth() {
+	l.mu.Lock()
+	health := l.botHealthLocked(time.Now())
+	botCmd := l.botCmd
+	skip := !l.config.AutoRestart || l.maintenance
+	l.mu.Unlock()
+
+	if skip || health == nil || !health.RestartEligible {
+		return
+	}
+
+	log.Printf("🩺 Bot unhealthy since %s (%s), restarting it",
+		health.UnhealthySince.Format(time.RFC3339), strings.Join(health.Unhealthy, ", "))
+	if err := stopProcess(botCmd.Process); err != nil {
+		log.Printf("❌ Failed to stop unhealthy bot: %v", err)
+	}
+}
+
+// formatBotHealth renders the bot health line of the status output
+func formatBotHealth(health *BotHealthStatus) string {
+	if health == nil {
+		return ""
+	}
+
+	var line string
+	switch health.State {
+	case BotHealthHealthy:
+		line = "✅ Healthy"
+	case BotHealthUnhealthy:
+		line = "❌ Unhealthy"
+		if len(health.Unhealthy) > 0 {
+			line += " (" + strings.Join(health.Unhealthy, ", ") + ")"
+		}
+		if health.UnhealthySince != nil {
+			line += " since " + health.UnhealthySince.Format(time.RFC3339)
+		}
+		if health.RestartEligible {
+			line += ", restart pending"
+		}
+	case BotHealthStale:
+		line = "⚠️  Stale snapshot: " + health.Detail
+	default:
+		line = "❔ Unknown"
+		if health.Detail != "" {
+			line += " (" + health.Detail + ")"
+		}
+	}
+
+	if health.LastHeartbeat != nil {
+		line += "\nLast heartbeat: " + health.LastHeartbeat.Format(time.RFC3339)
+	}
+	if health.RebalancerState != "" {
+		line += "\nRebalancer: " + health.RebalancerState
+	}
+	return "Bot health: " + line
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func writeBotHealth(t *testing.T, path string, snapshot BotHealthSnapshot) {
+	t.Helper()
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testProcess starts a process standing in for the bot; it is killed when the test ends
+func testProcess(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return cmd
+}
+
+func TestEvaluateBotHealth(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	heartbeat := now.Add(-40 * time.Second)
+	snapshot := BotHealthSnapshot{
+		PID:             100,
+		WrittenAt:       now.Add(-20 * time.Second),
+		IntervalSeconds: 30,
+		Healthy:         true,
+		Health:          map[string]bool{"rebalancer": true, "ibc_relayer": true},
+		LastHeartbeats:  map[string]time.Time{"gxrvaloper1a": heartbeat, "gxrvaloper1b": heartbeat.Add(-time.Hour)},
+		RebalancerState: "active",
+	}
+
+	status := evaluateBotHealth(snapshot, 100, now, 10*time.Minute)
+	if status.State != BotHealthHealthy || status.RestartEligible {
+		t.Fatalf("healthy status = %+v", status)
+	}
+	if !status.LastHeartbeat.Equal(heartbeat) || status.RebalancerState != "active" {
+		t.Fatalf("status = %+v", status)
+	}
+
+	// Failed health below the threshold is reported but not restart-eligible
+	since := now.Add(-9 * time.Minute)
+	snapshot.Healthy, snapshot.UnhealthySince = false, &since
+	snapshot.Health["ibc_relayer"] = false
+	status = evaluateBotHealth(snapshot, 100, now, 10*time.Minute)
+	if status.State != BotHealthUnhealthy || status.RestartEligible || len(status.Unhealthy) != 1 || status.Unhealthy[0] != "ibc_relayer" {
+		t.Fatalf("unhealthy status = %+v", status)
+	}
+
+	// Past the threshold the bot is restart-eligible, unless that is disabled
+	since = now.Add(-10 * time.Minute)
+	if status = evaluateBotHealth(snapshot, 100, now, 10*time.Minute); !status.RestartEligible {
+		t.Fatalf("bot unhealthy for the threshold not restart-eligible: %+v", status)
+	}
+	if status = evaluateBotHealth(snapshot, 100, now, 0); status.RestartEligible {
+		t.Fatal("restart-eligible with the restart policy disabled")
+	}
+}
+
+func TestEvaluateBotHealthStale(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour)
+	snapshot := BotHealthSnapshot{
+		PID:             100,
+		WrittenAt:       now.Add(-61 * time.Second),
+		IntervalSeconds: 30,
+		UnhealthySince:  &since,
+	}
+
+	// Older than two intervals: the bot stopped writing, never a restart on stale data
+	status := evaluateBotHealth(snapshot, 100, now, time.Minute)
+	if status.State != BotHealthStale || status.RestartEligible {
+		t.Fatalf("stale status = %+v", status)
+	}
+
+	snapshot.WrittenAt = now.Add(-60 * time.Second)
+	if status = evaluateBotHealth(snapshot, 100, now, time.Minute); status.State != BotHealthUnhealthy {
+		t.Fatalf("snapshot within two intervals = %+v", status)
+	}
+
+	// A snapshot left by a previous bot process is stale as well
+	if status = evaluateBotHealth(snapshot, 200, now, time.Minute); status.State != BotHealthStale || !strings.Contains(status.Detail, "PID 100") {
+		t.Fatalf("snapshot of another process = %+v", status)
+	}
+}
+
+func TestStatusReadsBotHealthSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	launcher := NewGXRLauncher(&LauncherConfig{RunDir: dir, AutoRestart: true, BotUnhealthyRestart: time.Minute})
+	defer launcher.cancel()
+
+	// A stand-in bot process to report on
+	launcher.botCmd = testProcess(t)
+	launcher.botRunning = true
+	pid := launcher.botCmd.Process.Pid
+
+	status := launcher.Status()
+	if status.BotHealth == nil || status.BotHealth.State != BotHealthUnknown {
+		t.Fatalf("bot health without snapshot = %+v", status.BotHealth)
+	}
+
+	since := time.Now().Add(-2 * time.Minute)
+	writeBotHealth(t, botHealthPath(dir), BotHealthSnapshot{
+		PID:             pid,
+		WrittenAt:       time.Now(),
+		IntervalSeconds: 30,
+		UnhealthySince:  &since,
+		Health:          map[string]bool{"dex_manager": false},
+	})
+	status = launcher.Status()
+	if status.BotHealth == nil || status.BotHealth.State != BotHealthUnhealthy || !status.BotHealth.RestartEligible {
+		t.Fatalf("bot health = %+v", status.BotHealth)
+	}
+	if out := formatStatus(status); !strings.Contains(out, "Bot health: ❌ Unhealthy (dex_manager)") || !strings.Contains(out, "restart pending") {
+		t.Fatalf("status output:\n%s", out)
+	}
+
+	// The status file carries the bot health for the status command fallback
+	if err := writeStatusFile(statusPath(dir), status); err != nil {
+		t.Fatal(err)
+	}
+	read, err := readStatusFile(statusPath(dir))
+	if err != nil || read.BotHealth == nil || read.BotHealth.State != BotHealthUnhealthy {
+		t.Fatalf("status file bot health = %+v, %v", read.BotHealth, err)
+	}
+
+	// The watchdog stops the restart-eligible bot
+	launcher.checkBotHealth()
+	if err := launcher.botCmd.Wait(); err == nil {
+		t.Fatal("unhealthy bot was not stopped")
+	}
+
+	// In maintenance the bot is left alone
+	launcher.botCmd = testProcess(t)
+	launcher.maintenance = true
+	writeBotHealth(t, botHealthPath(dir), BotHealthSnapshot{
+		PID:             launcher.botCmd.Process.Pid,
+		WrittenAt:       time.Now(),
+		IntervalSeconds: 30,
+		UnhealthySince:  &since,
+	})
+	launcher.checkBotHealth()
+	if err := launcher.botCmd.Process.Signal(syscall.Signal(0)); err != nil {
+		t.Fatalf("bot stopped in maintenance: %v", err)
+	}
+}
+
+func TestStatusWithoutRunDirHasNoBotHealth(t *testing.T) {
+	launcher := NewGXRLauncher(&LauncherConfig{})
+	defer launcher.cancel()
+	launcher.botRunning = true
+
+	if health := launcher.Status().BotHealth; health != nil {
+		t.Fatalf("bot health without run dir = %+v", health)
+	}
+	if out := formatStatus(launcher.Status()); strings.Contains(out, "Bot health") {
+		t.Fatalf("status output:\n%s", out)
+	}
+	if got := botHealthPath("/run/gxr"); got != filepath.Join("/run/gxr", BotHealthFileName) {
+		t.Fatalf("bot health path = %s", got)
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// ControlSocketName is the unix socket the running launcher listens on
+	ControlSocketName = "launcher.sock"
+	// StatusFileName is the status file written by the running launcher
+	StatusFileName = "status.json"
+	// ControlTimeout bounds a single control request
+	ControlTimeout = 60 * time.Second
+)
+
+// LauncherStatus is the state written to the status file and returned over
+// the control socket
+type LauncherStatus struct {
+	LauncherPID      int              `json:"launcher_pid"`
+	ChainRunning     bool             `json:"chain_running"`
+	ChainPID         int              `json:"chain_pid,omitempty"`
+	BotRunning       bool             `json:"bot_running"`
+	BotPID           int              `json:"bot_pid,omitempty"`
+	AutoRestart      bool             `json:"auto_restart"`
+	Maintenance      bool             `json:"maintenance"`
+	MaintenanceSince *time.Time       `json:"maintenance_since,omitempty"`
+	BotHealth        *BotHealthStatus `json:"bot_health,omitempty"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// controlResponse is the reply to a control request
+type controlResponse struct {
+	OK     bool            `json:"ok"`
+	Error  string          `json:"error,omitempty"`
+	Status *LauncherStatus `json:"status,omitempty"`
+}
+
+// socketPath returns the control socket path for a run directory
+func socketPath(runDir string) string {
+	return filepath.Join(runDir, ControlSocketName)
+}
+
+// statusPath returns the status file path for a run directory
+func statusPath(runDir string) string {
+	return filepath.Join(runDir, StatusFileName)
+}
+
+// writeStatusFile atomically replaces the status file
+func writeStatusFile(path string, status LauncherStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readStatusFile reads the status file written by a running launcher
+func readStatusFile(path string) (LauncherStatus, error) {
+	var status LauncherStatus
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return status, err
+	}
+	err = json.Unmarshal(data, &status)
+	return status, err
+}
+
+// listenControl opens the control socket. A socket left behind by a launcher
+// that is no longer running is removed; a live one is an error.
+func listenControl(runDir string) (net.Listener, error) {
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create run directory: %w", err)
+	}
+
+	path := socketPath(runDir)
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another launcher is already running (%s)", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale control socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open control socket: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict control socket: %w", err)
+	}
+
+	return listener, nil
+}
+
+// serveControl answers control requests until the listener is closed
+func (l *GXRLauncher) serveControl(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Printf("Control socket error: %v", err)
+			}
+			return
+		}
+		go l.handleControl(conn)
+	}
+}
+
+// handleControl processes one request line: "maintenance on", "maintenance off" or "status"
+func (l *GXRLauncher) handleControl(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ControlTimeout))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+
+	var resp controlResponse
+	switch fields := strings.Fields(line); {
+	case len(fields) == 1 && fields[0] == "status":
+		resp.OK = true
+	case len(fields) == 2 && fields[0] == "maintenance" && (fields[1] == "on" || fields[1] == "off"):
+		if err := l.SetMaintenance(fields[1] == "on"); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.OK = true
+		}
+	default:
+		resp.Error = fmt.Sprintf("unknown command %q", strings.TrimSpace(line))
+	}
+
+	status := l.Status()
+	resp.Status = &status
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// sendControl sends one command to the running launcher and returns its status
+func sendControl(runDir, command string) (LauncherStatus, error) {
+	conn, err := net.DialTimeout("unix", socketPath(runDir), 5*time.Second)
+	if err != nil {
+		return LauncherStatus{}, fmt.Errorf("launcher is not running (%s): %w", socketPath(runDir), err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ControlTimeout))
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return LauncherStatus{}, fmt.Errorf("failed to send command: %w", err)
+	}
+
+	var resp controlResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return LauncherStatus{}, fmt.Errorf("failed to read launcher response: %w", err)
+	}
+
+	var status LauncherStatus
+	if resp.Status != nil {
+		status = *resp.Status
+	}
+	if !resp.OK {
+		return status, errors.New(resp.Error)
+	}
+	return status, nil
+}
+
+// formatStatus renders a status for the status and maintenance commands
+func formatStatus(status LauncherStatus) string {
+	running := func(ok bool, pid int) string {
+		if !ok {
+			return "❌ Stopped"
+		}
+		return fmt.Sprintf("✅ Running (PID: %d)", pid)
+	}
+	enabled := map[bool]string{true: "✅ Enabled", false: "❌ Disabled"}
+
+	lines := []string{
+		"Chain: " + running(status.ChainRunning, status.ChainPID),
+		"Bot:   " + running(status.BotRunning, status.BotPID),
+	}
+	if health := formatBotHealth(status.BotHealth); health != "" {
+		lines = append(lines, health)
+	}
+	lines = append(lines, "Auto-restart: "+enabled[status.AutoRestart])
+	if status.Maintenance && status.MaintenanceSince != nil {
+		lines = append(lines, fmt.Sprintf("Maintenance: 🔧 On since %s (bot auto-restart suppressed)",
+			status.MaintenanceSince.Format(time.RFC3339)))
+	} else if status.Maintenance {
+		lines = append(lines, "Maintenance: 🔧 On (bot auto-restart suppressed)")
+	} else {
+		lines = append(lines, "Maintenance: Off")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -4,11 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -16,6 +17,8 @@ import (
 
 const (
 	LauncherVersion = "1.0.0"
+	// BotStopTimeout is how long the bot gets to exit on SIGTERM before it is killed
+	BotStopTimeout = 30 * time.Second
 )
 
 // LauncherConfig holds the launcher configuration
@@ -28,6 +31,10 @@ type LauncherConfig struct {
 	LogLevel       string
 	AutoRestart    bool
 	RestartDelay   time.Duration
+	RunDir         string // holds the control socket, status file and bot health snapshot
+	
+	// BotUnhealthyRestart restarts a bot reporting failed health for this long, 0 disables
+	BotUnhealthyRestart time.Duration
 }
 
 // GXRLauncher manages both chain and bot processes
@@ -40,8 +47,22 @@ type GXRLauncher struct {
 	chainCmd   *exec.Cmd
 	botCmd     *exec.Cmd
 	
+	// mu guards the process handles, running flags and maintenance state
+	mu           sync.Mutex
 	chainRunning bool
 	botRunning   bool
+	botDone      chan struct{}
+
+	// maintenance stops the bot and suppresses its auto-restart while the chain keeps running
+	maintenance      bool
+	maintenanceSince time.Time
+
+	control net.Listener
+
+	// notifier reports to systemd, nil unless run under it; lastHealthCycle
+	// is the last bot health watchdog tick
+	notifier        *SystemdNotifier
+	lastHealthCycle time.Time
 }
 
 // NewGXRLauncher creates a new launcher instance
@@ -49,16 +70,28 @@ func NewGXRLauncher(config *LauncherConfig) *GXRLauncher {
 	ctx, cancel := context.WithCancel(context.Background())
 	
 	return &GXRLauncher{
-		config: config,
-		ctx:    ctx,
-		cancel: cancel,
-		wg:     &sync.WaitGroup{},
+		config:   config,
+		ctx:      ctx,
+		cancel:   cancel,
+		wg:       &sync.WaitGroup{},
+		notifier: NewSystemdNotifier(),
 	}
 }
 
 // Start starts both chain and bot processes
 func (l *GXRLauncher) Start() error {
 	log.Printf("🚀 Starting GXR Launcher v%s", LauncherVersion)
+	warnBinaries(l.config)
+
+	// Open the control socket first so a second launcher on the same run dir is refused
+	if l.config.RunDir != "" {
+		listener, err := listenControl(l.config.RunDir)
+		if err != nil {
+			return err
+		}
+		l.control = listener
+		go l.serveControl(listener)
+	}
 	
 	// Start chain first
 	if err := l.startChain(); err != nil {
@@ -77,12 +110,29 @@ func (l *GXRLauncher) Start() error {
 	
 	log.Println("✅ GXR Launcher started successfully")
 	log.Println("   📦 Chain: Running")
-	if l.botRunning {
+	if l.Status().BotRunning {
 		log.Println("   🤖 Bot: Running")
 	} else {
 		log.Println("   🤖 Bot: Failed to start")
 	}
 	
+	// Follow the bot's health snapshot for the status output and restart policy
+	if l.config.RunDir != "" {
+		l.mu.Lock()
+		l.lastHealthCycle = time.Now()
+		l.mu.Unlock()
+		l.wg.Add(1)
+		go l.botHealthWatchdog()
+	}
+	
+	// Tell systemd the launcher is up and keep its watchdog fed
+	l.notifySystemd(l.Status(), true)
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		l.notifier.RunWatchdog(l.ctx, l.watchdogAlive)
+	}()
+	
 	return nil
 }
 
@@ -91,31 +141,41 @@ func (l *GXRLauncher) startChain() error {
 	log.Println("🔗 Starting GXR Chain...")
 	
 	// Build chain command
-	l.chainCmd = exec.CommandContext(l.ctx, l.config.ChainBinary, "start")
+	chainCmd := exec.CommandContext(l.ctx, l.config.ChainBinary, "start")
+	configureProcess(chainCmd)
 	
 	// Set environment variables
 	if l.config.ChainHome != "" {
-		l.chainCmd.Env = append(os.Environ(), fmt.Sprintf("HOME=%s", l.config.ChainHome))
+		chainCmd.Env = append(os.Environ(), fmt.Sprintf("HOME=%s", l.config.ChainHome))
 	}
 	
 	// Set up logging
-	l.chainCmd.Stdout = &PrefixedWriter{prefix: "[CHAIN]", writer: os.Stdout}
-	l.chainCmd.Stderr = &PrefixedWriter{prefix: "[CHAIN]", writer: os.Stderr}
+	chainCmd.Stdout = &PrefixedWriter{prefix: "[CHAIN]", writer: os.Stdout}
+	chainCmd.Stderr = &PrefixedWriter{prefix: "[CHAIN]", writer: os.Stderr}
 	
 	// Start chain process
-	if err := l.chainCmd.Start(); err != nil {
+	if err := chainCmd.Start(); err != nil {
 		return fmt.Errorf("failed to start chain process: %w", err)
 	}
 	
+	l.mu.Lock()
+	l.chainCmd = chainCmd
 	l.chainRunning = true
+	l.mu.Unlock()
+	l.updateStatusFile()
 	
 	// Monitor chain process
 	l.wg.Add(1)
 	go func() {
 		defer l.wg.Done()
-		defer func() { l.chainRunning = false }()
 		
-		if err := l.chainCmd.Wait(); err != nil {
+		err := chainCmd.Wait()
+		l.mu.Lock()
+		l.chainRunning = false
+		l.mu.Unlock()
+		l.updateStatusFile()
+		
+		if err != nil {
 			log.Printf("❌ Chain process exited with error: %v", err)
 		} else {
 			log.Println("🔗 Chain process exited normally")
@@ -144,35 +204,64 @@ func (l *GXRLauncher) startBot() error {
 		args = append(args, "--config", l.config.BotConfig)
 	}
 	
-	l.botCmd = exec.CommandContext(l.ctx, l.config.BotBinary, args...)
+	botCmd := exec.CommandContext(l.ctx, l.config.BotBinary, args...)
+	configureProcess(botCmd)
+	
+	botCmd.Env = botEnv(l.config.RunDir)
 	
 	// Set up logging
-	l.botCmd.Stdout = &PrefixedWriter{prefix: "[BOT] ", writer: os.Stdout}
-	l.botCmd.Stderr = &PrefixedWriter{prefix: "[BOT] ", writer: os.Stderr}
+	botCmd.Stdout = &PrefixedWriter{prefix: "[BOT] ", writer: os.Stdout}
+	botCmd.Stderr = &PrefixedWriter{prefix: "[BOT] ", writer: os.Stderr}
+	
+	l.mu.Lock()
+	if l.botRunning {
+		// A pending auto-restart and "maintenance off" can race; one bot is enough
+		l.mu.Unlock()
+		return nil
+	}
 	
 	// Start bot process
-	if err := l.botCmd.Start(); err != nil {
+	if err := botCmd.Start(); err != nil {
+		l.mu.Unlock()
 		return fmt.Errorf("failed to start bot process: %w", err)
 	}
 	
+	done := make(chan struct{})
+	l.botCmd = botCmd
+	l.botDone = done
 	l.botRunning = true
+	l.mu.Unlock()
+	l.updateStatusFile()
 	
 	// Monitor bot process
 	l.wg.Add(1)
 	go func() {
 		defer l.wg.Done()
-		defer func() { l.botRunning = false }()
 		
-		if err := l.botCmd.Wait(); err != nil {
+		err := botCmd.Wait()
+		l.mu.Lock()
+		l.botRunning = false
+		l.mu.Unlock()
+		close(done)
+		l.updateStatusFile()
+		
+		if l.inMaintenance() {
+			log.Println("🔧 Bot stopped for maintenance")
+			return
+		}
+		if err != nil {
 			log.Printf("❌ Bot process exited with error: %v", err)
 		} else {
 			log.Println("🤖 Bot process exited normally")
 		}
 		
-		// Auto-restart if enabled
+		// Auto-restart if enabled and not in maintenance
 		if l.config.AutoRestart && l.ctx.Err() == nil {
 			log.Printf("🔄 Restarting bot in %v...", l.config.RestartDelay)
 			time.Sleep(l.config.RestartDelay)
+			if l.inMaintenance() || l.ctx.Err() != nil {
+				return
+			}
 			if err := l.startBot(); err != nil {
 				log.Printf("❌ Failed to restart bot: %v", err)
 			}
@@ -182,41 +271,150 @@ func (l *GXRLauncher) startBot() error {
 	return nil
 }
 
+// SetMaintenance turns maintenance mode on or off. Turning it on stops the bot
+// and suppresses its auto-restart while the chain keeps running; turning it
+// off starts the bot again.
+func (l *GXRLauncher) SetMaintenance(on bool) error {
+	l.mu.Lock()
+	if l.maintenance == on {
+		l.mu.Unlock()
+		return nil
+	}
+	l.maintenance = on
+	if on {
+		l.maintenanceSince = time.Now()
+	} else {
+		l.maintenanceSince = time.Time{}
+	}
+	botCmd, botDone, botRunning := l.botCmd, l.botDone, l.botRunning
+	l.mu.Unlock()
+	l.updateStatusFile()
+	
+	if !on {
+		log.Println("🔧 Maintenance mode off, starting bot")
+		return l.startBot()
+	}
+	
+	log.Println("🔧 Maintenance mode on, stopping bot (chain keeps running)")
+	if !botRunning {
+		return nil
+	}
+	if err := stopProcess(botCmd.Process); err != nil {
+		return fmt.Errorf("failed to stop bot: %w", err)
+	}
+	
+	select {
+	case <-botDone:
+	case <-time.After(BotStopTimeout):
+		log.Printf("⚠️  Bot did not exit within %v, killing it", BotStopTimeout)
+		if err := botCmd.Process.Kill(); err != nil {
+			return fmt.Errorf("failed to kill bot: %w", err)
+		}
+		<-botDone
+	}
+	
+	return nil
+}
+
+// inMaintenance reports whether maintenance mode is on
+func (l *GXRLauncher) inMaintenance() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.maintenance
+}
+
 // Stop gracefully stops both processes
 func (l *GXRLauncher) Stop() {
 	log.Println("🛑 Stopping GXR Launcher...")
+	if err := l.notifier.Notify("STOPPING=1"); err != nil {
+		log.Printf("⚠️  Failed to notify systemd: %v", err)
+	}
 	
 	// Cancel context to signal all processes to stop
 	l.cancel()
+	if l.control != nil {
+		l.control.Close()
+	}
+	
+	l.mu.Lock()
+	botCmd, botRunning := l.botCmd, l.botRunning
+	chainCmd, chainRunning := l.chainCmd, l.chainRunning
+	l.mu.Unlock()
 	
 	// Stop bot first
-	if l.botCmd != nil && l.botRunning {
+	if botCmd != nil && botRunning {
 		log.Println("🤖 Stopping bot...")
-		if err := l.botCmd.Process.Signal(syscall.SIGTERM); err != nil {
+		if err := stopProcess(botCmd.Process); err != nil {
 			log.Printf("Error stopping bot: %v", err)
 		}
 	}
 	
 	// Stop chain
-	if l.chainCmd != nil && l.chainRunning {
+	if chainCmd != nil && chainRunning {
 		log.Println("🔗 Stopping chain...")
-		if err := l.chainCmd.Process.Signal(syscall.SIGTERM); err != nil {
+		if err := stopProcess(chainCmd.Process); err != nil {
 			log.Printf("Error stopping chain: %v", err)
 		}
 	}
 	
 	// Wait for all processes to finish
 	l.wg.Wait()
+	l.updateStatusFile()
 	
 	log.Println("✅ GXR Launcher stopped gracefully")
 }
 
 // GetStatus returns the current status of both processes
 func (l *GXRLauncher) GetStatus() map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
 	return map[string]interface{}{
 		"chain_running": l.chainRunning,
 		"bot_running":   l.botRunning,
 		"auto_restart":  l.config.AutoRestart,
+		"maintenance":   l.maintenance,
+	}
+}
+
+// Status returns a snapshot of the launcher state for the status file and control socket
+func (l *GXRLauncher) Status() LauncherStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	status := LauncherStatus{
+		LauncherPID:  os.Getpid(),
+		ChainRunning: l.chainRunning,
+		BotRunning:   l.botRunning,
+		AutoRestart:  l.config.AutoRestart,
+		Maintenance:  l.maintenance,
+		UpdatedAt:    time.Now().UTC(),
+	}
+	if l.chainRunning && l.chainCmd != nil {
+		status.ChainPID = l.chainCmd.Process.Pid
+	}
+	if l.botRunning && l.botCmd != nil {
+		status.BotPID = l.botCmd.Process.Pid
+	}
+	if l.maintenance {
+		since := l.maintenanceSince.UTC()
+		status.MaintenanceSince = &since
+	}
+	status.BotHealth = l.botHealthLocked(status.UpdatedAt)
+	
+	return status
+}
+
+// updateStatusFile writes the current state to the status file in RunDir
+// and reports it to systemd
+func (l *GXRLauncher) updateStatusFile() {
+	status := l.Status()
+	l.notifySystemd(status, false)
+	if l.config.RunDir == "" {
+		return
+	}
+	if err := writeStatusFile(statusPath(l.config.RunDir), status); err != nil {
+		log.Printf("⚠️  Failed to write status file: %v", err)
 	}
 }
 
@@ -233,25 +431,30 @@ func (pw *PrefixedWriter) Write(p []byte) (n int, err error) {
 
 // DefaultConfig returns the default launcher configuration
 func DefaultConfig() *LauncherConfig {
+	home := homeDir()
 	return &LauncherConfig{
-		ChainBinary:  "./build/gxrchaind",
-		BotBinary:    "./bot/gxr-bot",
-		ChainHome:    os.ExpandEnv("$HOME/.gxrchaind"),
-		LogLevel:     "info",
-		AutoRestart:  true,
-		RestartDelay: 5 * time.Second,
+		ChainBinary:         defaultBinary("build", "gxrchaind"),
+		BotBinary:           defaultBinary("bot", "gxr-bot"),
+		ChainHome:           filepath.Join(home, ".gxrchaind"),
+		LogLevel:            "info",
+		AutoRestart:         true,
+		RestartDelay:        5 * time.Second,
+		RunDir:              filepath.Join(home, ".gxr-launcher"),
+		BotUnhealthyRestart: DefaultBotUnhealthyRestart,
 	}
 }
 
 // Main CLI command
 func main() {
 	var (
-		chainBinary string
-		botBinary   string
-		chainHome   string
-		chainConfig string
-		botConfig   string
-		autoRestart bool
+		chainBinary         string
+		botBinary           string
+		chainHome           string
+		chainConfig         string
+		botConfig           string
+		autoRestart         bool
+		runDir              string
+		botUnhealthyRestart time.Duration
 	)
 	
 	rootCmd := &cobra.Command{
@@ -266,17 +469,21 @@ The launcher ensures both services start together and can be managed as a single
 			// Create configuration
 			config := DefaultConfig()
 			if chainBinary != "" {
-				config.ChainBinary = chainBinary
+				config.ChainBinary = expandHome(chainBinary)
 			}
 			if botBinary != "" {
-				config.BotBinary = botBinary
+				config.BotBinary = expandHome(botBinary)
 			}
 			if chainHome != "" {
-				config.ChainHome = chainHome
+				config.ChainHome = expandHome(chainHome)
 			}
-			config.ChainConfig = chainConfig
-			config.BotConfig = botConfig
+			config.ChainConfig = expandHome(chainConfig)
+			config.BotConfig = expandHome(botConfig)
 			config.AutoRestart = autoRestart
+			if runDir != "" {
+				config.RunDir = expandHome(runDir)
+			}
+			config.BotUnhealthyRestart = botUnhealthyRestart
 			
 			// Create and start launcher
 			launcher := NewGXRLauncher(config)
@@ -286,7 +493,7 @@ The launcher ensures both services start together and can be managed as a single
 			
 			// Wait for interrupt signal
 			sigChan := make(chan os.Signal, 1)
-			signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+			signal.Notify(sigChan, shutdownSignals...)
 			
 			log.Println("🏃 GXR Launcher is running. Press Ctrl+C to stop.")
 			<-sigChan
@@ -304,19 +511,61 @@ The launcher ensures both services start together and can be managed as a single
 	rootCmd.Flags().StringVar(&chainConfig, "chain-config", "", "Chain configuration file")
 	rootCmd.Flags().StringVar(&botConfig, "bot-config", "", "Bot configuration file")
 	rootCmd.Flags().BoolVar(&autoRestart, "auto-restart", true, "Automatically restart failed processes")
+	rootCmd.Flags().DurationVar(&botUnhealthyRestart, "bot-unhealthy-restart", DefaultBotUnhealthyRestart, "Restart the bot after it reports failed health for this long (0 disables)")
+	rootCmd.PersistentFlags().StringVar(&runDir, "run-dir", "", "Directory for the launcher control socket and status file (default ~/.gxr-launcher)")
 	
 	// Add status command
 	statusCmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show status of chain and bot processes",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO: Implement status checking for running processes
-			fmt.Println("Status checking not implemented yet")
+			dir := expandHome(runDir)
+			if dir == "" {
+				dir = DefaultConfig().RunDir
+			}
+			
+			// Ask the running launcher first; fall back to the last status file it wrote
+			status, err := sendControl(dir, "status")
+			if err != nil {
+				status, err = readStatusFile(statusPath(dir))
+				if err != nil {
+					return fmt.Errorf("launcher is not running and no status file found in %s", dir)
+				}
+				fmt.Printf("⚠️  Launcher is not running, last status from %s\n", status.UpdatedAt.Format(time.RFC3339))
+			}
+			
+			fmt.Println(formatStatus(status))
 			return nil
 		},
 	}
 	rootCmd.AddCommand(statusCmd)
 	
+	// Add maintenance command
+	maintenanceCmd := &cobra.Command{
+		Use:   "maintenance [on|off]",
+		Short: "Stop or restart the bot while the chain keeps running",
+		Long: `Maintenance mode stops the bot managed by the running launcher and suppresses
+its auto-restart, leaving the chain untouched. Use it to upgrade the bot binary
+without restarting the validator node, then turn it off to start the bot again.`,
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"on", "off"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := expandHome(runDir)
+			if dir == "" {
+				dir = DefaultConfig().RunDir
+			}
+			
+			status, err := sendControl(dir, "maintenance "+args[0])
+			if err != nil {
+				return fmt.Errorf("failed to set maintenance %s: %w", args[0], err)
+			}
+			
+			fmt.Println(formatStatus(status))
+			return nil
+		},
+	}
+	rootCmd.AddCommand(maintenanceCmd)
+	
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
 	}
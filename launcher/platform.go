@@ -0,0 +1,141 @@
+package main
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// homeDir returns the user's home directory, or the working directory when
+// it cannot be determined
+func homeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Printf("⚠️  Cannot determine home directory, using the working directory: %v", err)
+		return "."
+	}
+	return home
+}
+
+// expandHome resolves a leading "~" in path to the user's home directory.
+// Both separators are accepted after it on Windows.
+func expandHome(path string) string {
+	if path == "~" {
+		return homeDir()
+	}
+	if len(path) > 1 && path[0] == '~' && os.IsPathSeparator(path[1]) {
+		return filepath.Join(homeDir(), path[2:])
+	}
+	return path
+}
+
+// defaultBinary returns the path of a binary built in dir, with the
+// platform's executable suffix
+func defaultBinary(dir, name string) string {
+	return filepath.Join(dir, name+exeSuffix)
+}
+
+// checkBinary reports why path cannot be run on this platform: it is
+// missing, not an executable, or built for another OS or architecture. A
+// path without a directory is looked up in PATH, as exec.Command does.
+func checkBinary(path string) error {
+	if path == "" {
+		return fmt.Errorf("no binary configured")
+	}
+	if !strings.ContainsAny(path, `/\`) {
+		resolved, err := exec.LookPath(path)
+		if err != nil {
+			return fmt.Errorf("not found in PATH")
+		}
+		path = resolved
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("does not exist")
+		}
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("is a directory")
+	}
+	if !isExecutable(path, info) {
+		return fmt.Errorf("is not executable")
+	}
+
+	goos, goarch, ok := binaryPlatform(path)
+	if !ok {
+		// Scripts and unknown formats are left to the OS
+		return nil
+	}
+	if !platformMatches(goos, runtime.GOOS) || goarch != runtime.GOARCH {
+		return fmt.Errorf("is built for %s/%s, this is %s/%s", goos, goarch, runtime.GOOS, runtime.GOARCH)
+	}
+	return nil
+}
+
+// platformMatches reports whether a binary for goos runs on host. ELF
+// binaries are reported as linux, and run on the other ELF platforms too.
+func platformMatches(goos, host string) bool {
+	if goos == "linux" {
+		return host != "windows" && host != "darwin"
+	}
+	return goos == host
+}
+
+// binaryPlatform reads the executable format of path and returns the
+// GOOS/GOARCH it was built for
+func binaryPlatform(path string) (goos, goarch string, ok bool) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		goarch, ok = map[elf.Machine]string{
+			elf.EM_X86_64:  "amd64",
+			elf.EM_AARCH64: "arm64",
+			elf.EM_ARM:     "arm",
+			elf.EM_386:     "386",
+			elf.EM_RISCV:   "riscv64",
+		}[f.Machine]
+		return "linux", goarch, ok
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		goarch, ok = map[uint16]string{
+			pe.IMAGE_FILE_MACHINE_AMD64: "amd64",
+			pe.IMAGE_FILE_MACHINE_ARM64: "arm64",
+			pe.IMAGE_FILE_MACHINE_ARMNT: "arm",
+			pe.IMAGE_FILE_MACHINE_I386:  "386",
+		}[f.Machine]
+		return "windows", goarch, ok
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		goarch, ok = map[macho.Cpu]string{
+			macho.CpuAmd64: "amd64",
+			macho.CpuArm64: "arm64",
+		}[f.Cpu]
+		return "darwin", goarch, ok
+	}
+	return "", "", false
+}
+
+// warnBinaries logs a warning for each configured binary that cannot be run
+// on this platform. Starting still goes ahead, so the process error is
+// reported as well.
+func warnBinaries(config *LauncherConfig) {
+	for _, binary := range []struct{ name, path string }{
+		{"Chain", config.ChainBinary},
+		{"Bot", config.BotBinary},
+	} {
+		if err := checkBinary(binary.path); err != nil {
+			log.Printf("⚠️  %s binary %q: %v", binary.name, binary.path, err)
+		}
+	}
+}
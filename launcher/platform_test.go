@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeForeignELF writes an ELF header for an architecture other than this one
+func writeForeignELF(t *testing.T, path string) {
+	t.Helper()
+
+	machine := elf.EM_AARCH64
+	if runtime.GOARCH == "arm64" {
+		machine = elf.EM_X86_64
+	}
+	header := elf.Header64{
+		Type:    uint16(elf.ET_EXEC),
+		Machine: uint16(machine),
+		Version: uint32(elf.EV_CURRENT),
+		Ehsize:  uint16(binary.Size(elf.Header64{})),
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckBinary(t *testing.T) {
+	dir := t.TempDir()
+
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkBinary(self); err != nil {
+		t.Fatalf("test binary reported unusable: %v", err)
+	}
+
+	foreign := filepath.Join(dir, "gxrchaind"+exeSuffix)
+	writeForeignELF(t, foreign)
+
+	for path, want := range map[string]string{
+		"":                            "no binary configured",
+		filepath.Join(dir, "missing"): "does not exist",
+		dir:                           "is a directory",
+		foreign:                       "is built for linux/",
+		"gxrchaind-not-in-path":       "not found in PATH",
+	} {
+		err := checkBinary(path)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("checkBinary(%q) = %v, want %q", path, err, want)
+		}
+	}
+}
+
+func TestDefaultConfigUsesHomeDir(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory:", err)
+	}
+
+	config := DefaultConfig()
+	if config.ChainHome != filepath.Join(home, ".gxrchaind") || config.RunDir != filepath.Join(home, ".gxr-launcher") {
+		t.Fatalf("default dirs = %s, %s, want under %s", config.ChainHome, config.RunDir, home)
+	}
+	if config.ChainBinary != defaultBinary("build", "gxrchaind") || config.BotBinary != defaultBinary("bot", "gxr-bot") {
+		t.Fatalf("default binaries = %s, %s", config.ChainBinary, config.BotBinary)
+	}
+}
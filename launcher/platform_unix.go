@@ -0,0 +1,28 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// exeSuffix is appended to default binary names
+const exeSuffix = ""
+
+// shutdownSignals stop the launcher
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// configureProcess prepares a child process command; nothing is needed here
+func configureProcess(cmd *exec.Cmd) {}
+
+// stopProcess asks a child process to shut down gracefully
+func stopProcess(process *os.Process) error {
+	return process.Signal(syscall.SIGTERM)
+}
+
+// isExecutable reports whether any execute bit is set
+func isExecutable(path string, info os.FileInfo) bool {
+	return info.Mode()&0o111 != 0
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandHomeUnix(t *testing.T) {
+	t.Setenv("HOME", "/home/validator")
+
+	for path, want := range map[string]string{
+		"~":                 "/home/validator",
+		"~/":                "/home/validator",
+		"~/.gxrchaind":      "/home/validator/.gxrchaind",
+		"~/bin/../gxr-bot":  "/home/validator/gxr-bot",
+		"~other/.gxr":       "~other/.gxr",
+		"./build/gxrchaind": "./build/gxrchaind",
+		"/opt/gxr/~/bin":    "/opt/gxr/~/bin",
+		"":                  "",
+	} {
+		if got := expandHome(path); got != want {
+			t.Errorf("expandHome(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestDefaultBinaryUnix(t *testing.T) {
+	if got := defaultBinary("build", "gxrchaind"); got != "build/gxrchaind" {
+		t.Fatalf("defaultBinary = %q", got)
+	}
+}
+
+func TestCheckBinaryNotExecutableUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gxr-bot")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkBinary(path); err == nil || !strings.Contains(err.Error(), "is not executable") {
+		t.Fatalf("checkBinary on a 0644 file = %v", err)
+	}
+
+	// Scripts are left to the OS once executable
+	if err := os.Chmod(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkBinary(path); err != nil {
+		t.Fatalf("checkBinary on an executable script = %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// exeSuffix is appended to default binary names
+const exeSuffix = ".exe"
+
+// shutdownSignals stop the launcher. Windows has no SIGTERM; Ctrl+C and
+// Ctrl+Break both arrive as os.Interrupt.
+var shutdownSignals = []os.Signal{os.Interrupt}
+
+var procGenerateConsoleCtrlEvent = syscall.NewLazyDLL("kernel32.dll").NewProc("GenerateConsoleCtrlEvent")
+
+// configureProcess starts a child process in its own process group, so
+// that stopProcess can send it Ctrl+Break without reaching the launcher
+func configureProcess(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// stopProcess asks a child process to shut down gracefully with Ctrl+Break,
+// which Go programs receive as os.Interrupt. A process that cannot be sent
+// the event is killed.
+func stopProcess(process *os.Process) error {
+	if r, _, _ := procGenerateConsoleCtrlEvent.Call(syscall.CTRL_BREAK_EVENT, uintptr(process.Pid)); r != 0 {
+		return nil
+	}
+	return process.Kill()
+}
+
+// isExecutable reports whether the file extension is listed in PATHEXT
+func isExecutable(path string, info os.FileInfo) bool {
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".com;.exe;.bat;.cmd"
+	}
+	ext := filepath.Ext(path)
+	for _, e := range strings.Split(pathext, ";") {
+		if e != "" && strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandHomeWindows(t *testing.T) {
+	t.Setenv("USERPROFILE", `C:\Users\validator`)
+
+	for path, want := range map[string]string{
+		"~":                    `C:\Users\validator`,
+		`~\.gxrchaind`:         `C:\Users\validator\.gxrchaind`,
+		"~/.gxrchaind":         `C:\Users\validator\.gxrchaind`,
+		`~\bin\..\gxr-bot.exe`: `C:\Users\validator\gxr-bot.exe`,
+		`~other\.gxr`:          `~other\.gxr`,
+		`build\gxrchaind.exe`:  `build\gxrchaind.exe`,
+		"":                     "",
+	} {
+		if got := expandHome(path); got != want {
+			t.Errorf("expandHome(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestDefaultBinaryWindows(t *testing.T) {
+	if got := defaultBinary("build", "gxrchaind"); got != `build\gxrchaind.exe` {
+		t.Fatalf("defaultBinary = %q", got)
+	}
+}
+
+func TestCheckBinaryNotExecutableWindows(t *testing.T) {
+	t.Setenv("PATHEXT", ".COM;.EXE;.BAT;.CMD")
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "gxr-bot")
+	if err := os.WriteFile(path, []byte("MZ"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkBinary(path); err == nil || !strings.Contains(err.Error(), "is not executable") {
+		t.Fatalf("checkBinary without an executable extension = %v", err)
+	}
+
+	// Batch files are left to the OS; the extension is matched case-insensitively
+	script := filepath.Join(dir, "start-bot.bat")
+	if err := os.WriteFile(script, []byte("@echo off\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkBinary(script); err != nil {
+		t.Fatalf("checkBinary on a batch file = %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment set by systemd for Type=notify services
+const (
+	NotifySocketEnv = "NOTIFY_SOCKET"
+	WatchdogUsecEnv = "WATCHDOG_USEC"
+	WatchdogPIDEnv  = "WATCHDOG_PID"
+)
+
+// SystemdNotifier sends sd_notify datagrams to the service manager. It is nil
+// when the launcher does not run under systemd; all methods are no-ops then.
+type SystemdNotifier struct {
+	socket   string
+	watchdog time.Duration
+}
+
+// NewSystemdNotifier returns a notifier for NOTIFY_SOCKET, nil when it is not
+// set. The watchdog is enabled by WATCHDOG_USEC for this process.
+func NewSystemdNotifier() *SystemdNotifier {
+	socket := os.Getenv(NotifySocketEnv)
+	if socket == "" {
+		return nil
+	}
+
+	n := &SystemdNotifier{socket: socket}
+	usec, err := strconv.ParseInt(os.Getenv(WatchdogUsecEnv), 10, 64)
+	pid := os.Getenv(WatchdogPIDEnv)
+	if err == nil && usec > 0 && (pid == "" || pid == strconv.Itoa(os.Getpid())) {
+		n.watchdog = time.Duration(usec) * time.Microsecond
+	}
+	return n
+}
+
+// Notify sends the given state assignments, e.g. "READY=1", in one datagram
+func (n *SystemdNotifier) Notify(state ...string) error {
+	if n == nil {
+		return nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", n.socket, err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(strings.Join(state, "\n")))
+	return err
+}
+
+// WatchdogInterval returns the watchdog timeout systemd expects pings within,
+// 0 when the watchdog is disabled
+func (n *SystemdNotifier) WatchdogInterval() time.Duration {
+	if n == nil {
+		return 0
+	}
+	return n.watchdog
+}
+
+// RunWatchdog sends WATCHDOG=1 every half watchdog interval until ctx is
+// done, but only while alive reports the launcher as working
+func (n *SystemdNotifier) RunWatchdog(ctx context.Context, alive func(now time.Time) bool) {
+	if n.WatchdogInterval() <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(n.watchdog / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !alive(now) {
+				continue
+			}
+			if err := n.Notify("WATCHDOG=1"); err != nil {
+				log.Printf("⚠️  Failed to send systemd watchdog ping: %v", err)
+			}
+		}
+	}
+}
+
+// systemdStatusLine returns the one-line summary shown by systemctl status
+func systemdStatusLine(status LauncherStatus) string {
+	line := "chain stopped"
+	if status.ChainRunning {
+		line = "chain running"
+	}
+
+	switch {
+	case status.Maintenance:
+		return line + ", bot in maintenance"
+	case !status.BotRunning:
+		return line + ", bot stopped"
+	}
+
+	line += ", bot running"
+	health := status.BotHealth
+	if health == nil {
+		return line
+	}
+	switch health.State {
+	case BotHealthHealthy:
+		line += ", healthy"
+	case BotHealthUnhealthy:
+		line += ", unhealthy"
+		if len(health.Unhealthy) > 0 {
+			line += " (" + strings.Join(health.Unhealthy, ", ") + ")"
+		}
+		if health.UnhealthySince != nil {
+			line += " since " + health.UnhealthySince.Format(time.RFC3339)
+		}
+		if health.RestartEligible {
+			line += ", restart pending"
+		}
+	case BotHealthStale:
+		line += ", health snapshot stale"
+	default:
+		line += ", health unknown"
+	}
+	return line
+}
+
+// notifySystemd sends the status line to systemd, with READY=1 once the
+// launcher has started
+func (l *GXRLauncher) notifySystemd(status LauncherStatus, ready bool) {
+	state := []string{"STATUS=" + systemdStatusLine(status)}
+	if ready {
+		state = append([]string{"READY=1"}, state...)
+	}
+	if err := l.notifier.Notify(state...); err != nil {
+		log.Printf("⚠️  Failed to notify systemd: %v", err)
+	}
+}
+
+// watchdogAlive reports whether the chain runs and the bot health loop
+// ticked within two intervals, which is what the watchdog pings vouch for
+func (l *GXRLauncher) watchdogAlive(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.chainRunning {
+		return false
+	}
+	return l.config.RunDir == "" || now.Sub(l.lastHealthCycle) <= 2*BotHealthCheckInterval
+}
+
+// botEnv returns the bot's environment. The notify socket is the launcher's:
+// a bot reporting READY or watchdog pings in its place would mislead systemd.
+func botEnv(runDir string) []string {
+	env := make([]string, 0, len(os.Environ())+1)
+	for _, kv := range os.Environ() {
+		switch strings.SplitN(kv, "=", 2)[0] {
+		case NotifySocketEnv, WatchdogUsecEnv, WatchdogPIDEnv:
+			continue
+		}
+		env = append(env, kv)
+	}
+
+	// Have the bot write its health snapshot where the launcher reads it
+	if runDir != "" {
+		env = append(env, fmt.Sprintf("%s=%s", BotHealthEnv, botHealthPath(runDir)))
+	}
+	return env
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenNotifySocket serves a fake systemd notify socket and points
+// NOTIFY_SOCKET at it. The directory is kept short for the sun_path limit.
+func listenNotifySocket(t *testing.T) *net.UnixConn {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "sdn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv(NotifySocketEnv, path)
+	return conn
+}
+
+// readDatagram returns the next datagram, "" when none arrives within timeout
+func readDatagram(t *testing.T, conn *net.UnixConn, timeout time.Duration) string {
+	t.Helper()
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	n, err := conn.Read(buf)
+	if err != nil {
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			return ""
+		}
+		t.Fatal(err)
+	}
+	return string(buf[:n])
+}
+
+func TestSystemdNotifyLauncherStatus(t *testing.T) {
+	conn := listenNotifySocket(t)
+	dir := t.TempDir()
+	launcher := NewGXRLauncher(&LauncherConfig{RunDir: dir, AutoRestart: true, BotUnhealthyRestart: time.Hour})
+	defer launcher.cancel()
+
+	launcher.chainRunning = true
+	launcher.notifySystemd(launcher.Status(), true)
+	if got := readDatagram(t, conn, time.Second); got != "READY=1\nSTATUS=chain running, bot stopped" {
+		t.Fatalf("ready datagram = %q", got)
+	}
+
+	// Every status file update, e.g. each health watchdog tick, reports the bot health
+	launcher.botCmd = testProcess(t)
+	launcher.botRunning = true
+	since := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	writeBotHealth(t, botHealthPath(dir), BotHealthSnapshot{
+		PID:             launcher.botCmd.Process.Pid,
+		WrittenAt:       time.Now(),
+		IntervalSeconds: 30,
+		UnhealthySince:  &since,
+		Health:          map[string]bool{"dex_manager": false, "rebalancer": true},
+	})
+	launcher.updateStatusFile()
+	if got := readDatagram(t, conn, time.Second); got != "STATUS=chain running, bot running, unhealthy (dex_manager) since 2026-10-17T08:00:00Z" {
+		t.Fatalf("status datagram = %q", got)
+	}
+
+	launcher.maintenance = true
+	launcher.updateStatusFile()
+	if got := readDatagram(t, conn, time.Second); got != "STATUS=chain running, bot in maintenance" {
+		t.Fatalf("status datagram = %q", got)
+	}
+}
+
+func TestSystemdWatchdogFollowsLauncher(t *testing.T) {
+	conn := listenNotifySocket(t)
+	t.Setenv(WatchdogUsecEnv, "100000")
+	t.Setenv(WatchdogPIDEnv, "")
+	launcher := NewGXRLauncher(&LauncherConfig{RunDir: t.TempDir()})
+	defer launcher.cancel()
+	launcher.chainRunning = true
+	launcher.lastHealthCycle = time.Now()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		launcher.notifier.RunWatchdog(ctx, launcher.watchdogAlive)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if got := readDatagram(t, conn, time.Second); got != "WATCHDOG=1" {
+		t.Fatalf("watchdog datagram = %q", got)
+	}
+
+	// A stalled health loop starves the watchdog
+	launcher.mu.Lock()
+	launcher.lastHealthCycle = time.Now().Add(-3 * BotHealthCheckInterval)
+	launcher.mu.Unlock()
+	for readDatagram(t, conn, 100*time.Millisecond) != "" {
+	}
+	if got := readDatagram(t, conn, 300*time.Millisecond); got != "" {
+		t.Fatalf("watchdog pinged for a stalled health loop: %q", got)
+	}
+
+	// So does a stopped chain
+	launcher.mu.Lock()
+	launcher.lastHealthCycle = time.Now()
+	launcher.chainRunning = false
+	launcher.mu.Unlock()
+	if got := readDatagram(t, conn, 300*time.Millisecond); got != "" {
+		t.Fatalf("watchdog pinged without the chain: %q", got)
+	}
+
+	launcher.mu.Lock()
+	launcher.chainRunning = true
+	launcher.mu.Unlock()
+	if got := readDatagram(t, conn, time.Second); got != "WATCHDOG=1" {
+		t.Fatalf("watchdog datagram after recovery = %q", got)
+	}
+}
+
+func TestBotEnvDropsNotifySocket(t *testing.T) {
+	t.Setenv(NotifySocketEnv, "/run/systemd/notify")
+	t.Setenv(WatchdogUsecEnv, "120000000")
+	t.Setenv(WatchdogPIDEnv, "1")
+
+	env := strings.Join(botEnv("/run/gxr"), "\n")
+	for _, name := range []string{NotifySocketEnv, WatchdogUsecEnv, WatchdogPIDEnv} {
+		if strings.Contains(env, name+"=") {
+			t.Fatalf("bot environment passes %s", name)
+		}
+	}
+	if !strings.Contains(env, BotHealthEnv+"="+botHealthPath("/run/gxr")) {
+		t.Fatal("bot environment misses the health snapshot path")
+	}
+	if strings.Contains(strings.Join(botEnv(""), "\n"), BotHealthEnv+"=") {
+		t.Fatal("health snapshot path set without a run dir")
+	}
+}